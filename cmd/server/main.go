@@ -1,18 +1,30 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"log"
+	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/backgroundtaskservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/featureflagservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/filetransferservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/macroservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/pcservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/reconciliationservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/storagequotaservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/events"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
 	"github.com/unikyri/escritorio-remoto-backend/internal/infrastructure/database"
 	"github.com/unikyri/escritorio-remoto-backend/internal/infrastructure/persistence/mysql"
 	"github.com/unikyri/escritorio-remoto-backend/internal/infrastructure/storage"
@@ -21,6 +33,10 @@ import (
 	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/middleware"
 )
 
+// effectiveConfig valores de configuración ya resueltos (entorno o valor por defecto) que consultan los helpers
+// getEnv*; se exponen sin secretos en GET /api/v1/admin/config
+var effectiveConfig = make(map[string]interface{})
+
 func main() {
 	log.Println("Escritorio Remoto - Backend Server")
 	log.Println("FASE 8 - PASO 1: Transferencia de Archivos (Servidor a Cliente)")
@@ -43,21 +59,56 @@ func main() {
 
 	log.Println("Conexion a MySQL exitosa")
 
+	// Réplica de lectura opcional para listados, informes y auditoría (DB_REPLICA_DSN). El DSN incluye la
+	// contraseña, así que no pasa por getEnv ni aparece en la configuración expuesta.
+	var replicaDB *sql.DB
+	if replicaDSN := os.Getenv("DB_REPLICA_DSN"); replicaDSN != "" {
+		replica, err := database.NewReplicaConnection(replicaDSN, dbConfig)
+		if err != nil {
+			log.Printf("⚠️ Warning: No se pudo conectar con la réplica de lectura, las lecturas irán al primario: %v", err)
+		} else {
+			replicaDB = replica
+			defer replicaDB.Close()
+			log.Println("Conexion a la réplica de lectura exitosa")
+		}
+	}
+	dbRouter := database.NewDBRouter(db, replicaDB)
+	effectiveConfig["DB_READ_REPLICA_ENABLED"] = dbRouter.HasReplica()
+
 	userRepository := database.NewMySQLUserRepository(db)
 	clientPCRepository := database.NewMySQLClientPCRepository(db)
 	clientPCFactory := clientpc.NewClientPCFactory()
 
 	// Crear repositorio y servicio de ActionLog
-	actionLogRepository := mysql.NewActionLogRepository(db)
+	actionLogRepository := mysql.NewActionLogRepositoryWithRouter(dbRouter)
 	actionLogService := actionlogservice.NewActionLogService(actionLogRepository)
+	// Tipos de acción a guardar en el audit log (AUDIT_LOG_ALLOW_ACTIONS / AUDIT_LOG_DENY_ACTIONS); las acciones
+	// de autenticación y de inicio/fin de sesión se guardan siempre. Modificable en caliente por API.
+	actionLogFilter := actionLogService.(actionlogservice.IActionTypeFilterService)
+	actionLogFilter.SetActionTypeFilter(actionlogservice.ActionTypeFilter{
+		Allow: actionlogservice.ParseActionTypeList(getEnv("AUDIT_LOG_ALLOW_ACTIONS", "")),
+		Deny:  actionlogservice.ParseActionTypeList(getEnv("AUDIT_LOG_DENY_ACTIONS", "")),
+	})
 
-	jwtSecret := getEnv("JWT_SECRET", "escritorio_remoto_jwt_secret_development_2025")
+	// Fuera de SERVER_ENV=development el servidor no arranca sin un JWT_SECRET propio y suficientemente largo
+	serverEnv := getEnv("SERVER_ENV", "production")
+	jwtSecret, jwtSecretWarning, err := userservice.ResolveJWTSecret(getEnv("JWT_SECRET", ""), serverEnv == "development")
+	if err != nil {
+		log.Fatalf("❌ Configuración de seguridad no válida (SERVER_ENV=%s): %v", serverEnv, err)
+	}
+	if jwtSecretWarning != "" {
+		log.Printf("⚠️ %s", jwtSecretWarning)
+	}
 	authService := userservice.NewAuthService(userRepository, jwtSecret)
 	pcService := pcservice.NewPCService(clientPCRepository, clientPCFactory)
 
+	// Historial de conexiones de PCs cliente para auditoría y diagnóstico
+	connectionSessionRepository := mysql.NewConnectionSessionRepository(db)
+	connectionHistoryService := pcservice.NewConnectionHistoryService(connectionSessionRepository)
+
 	// Inicializar dependencias para sesiones remotas
 	eventBus := events.NewSimpleEventBus()
-	remoteSessionRepository := mysql.NewRemoteSessionRepository(db)
+	remoteSessionRepository := mysql.NewRemoteSessionRepositoryWithRouter(dbRouter)
 	remoteSessionService := remotesessionservice.NewRemoteSessionService(
 		remoteSessionRepository,
 		userRepository,
@@ -66,33 +117,176 @@ func main() {
 		eventBus,
 	)
 
+	// Política para las grabaciones parciales de sesiones rechazadas o fallidas
+	partialRecordingPolicy := videoservice.DefaultPartialRecordingPolicy
+	if value := os.Getenv("VIDEO_PARTIAL_RECORDING_POLICY"); value != "" {
+		if parsed, err := videoservice.ParsePartialRecordingPolicy(value); err == nil {
+			partialRecordingPolicy = parsed
+		} else {
+			log.Printf("Valor inválido para VIDEO_PARTIAL_RECORDING_POLICY: %v, usando %s", err, partialRecordingPolicy)
+		}
+	}
+	effectiveConfig["VIDEO_PARTIAL_RECORDING_POLICY"] = map[string]interface{}{
+		"mode":         string(partialRecordingPolicy.Mode),
+		"min_duration": partialRecordingPolicy.MinDuration.String(),
+	}
+
+	// Resolución máxima de frames de streaming y grabación (FRAME_MAX_WIDTH/FRAME_MAX_HEIGHT, 0 = sin límite)
+	frameResolutionLimit := videoservice.FrameResolutionLimit{
+		MaxWidth:  int(getEnvFloat("FRAME_MAX_WIDTH", 0)),
+		MaxHeight: int(getEnvFloat("FRAME_MAX_HEIGHT", 0)),
+		Mode:      videoservice.OversizeFrameDownscale,
+	}
+	if value := os.Getenv("FRAME_OVERSIZE_POLICY"); value != "" {
+		if parsed, err := videoservice.ParseOversizeFrameMode(value); err == nil {
+			frameResolutionLimit.Mode = parsed
+		} else {
+			log.Printf("Valor inválido para FRAME_OVERSIZE_POLICY: %v, usando %s", err, frameResolutionLimit.Mode)
+		}
+	}
+	effectiveConfig["FRAME_OVERSIZE_POLICY"] = string(frameResolutionLimit.Mode)
+
+	// Buffer de salida por conexión WebSocket (WS_OUTBOUND_BUFFER_SIZE mensajes, 0 = sin buffer)
+	outboundBufferConfig := handlers.DefaultOutboundBufferConfig()
+	outboundBufferConfig.Size = int(getEnvFloat("WS_OUTBOUND_BUFFER_SIZE", float64(outboundBufferConfig.Size)))
+	outboundBufferConfig.BlockTimeout = getEnvDuration("WS_OUTBOUND_BLOCK_TIMEOUT", outboundBufferConfig.BlockTimeout)
+	if value := os.Getenv("WS_OUTBOUND_OVERFLOW_POLICY"); value != "" {
+		if parsed, err := handlers.ParseOverflowPolicy(value); err == nil {
+			outboundBufferConfig.Policy = parsed
+		} else {
+			log.Printf("Valor inválido para WS_OUTBOUND_OVERFLOW_POLICY: %v, usando %s", err, outboundBufferConfig.Policy)
+		}
+	}
+	effectiveConfig["WS_OUTBOUND_OVERFLOW_POLICY"] = string(outboundBufferConfig.Policy)
+
 	// Inicializar dependencias para video service
-	sessionVideoRepository := mysql.NewSessionVideoRepository(db)
-	fileStorage := storage.NewLocalFileSystemStorage("./storage")
+	sessionVideoRepository := mysql.NewSessionVideoRepositoryWithRouter(dbRouter)
+	storageRoot := getEnv("STORAGE_ROOT", "./storage")
+	fileStorage := storage.NewLocalFileSystemStorage(storageRoot)
+	// Sin permisos de escritura las grabaciones y transferencias fallarían en tiempo de ejecución: fallar al arrancar
+	if err := storagequotaservice.VerifyStorageWritable(context.Background(), fileStorage); err != nil {
+		log.Fatalf("❌ Almacenamiento %s no utilizable: %v", storageRoot, err)
+	}
 	videoService := videoservice.NewVideoService(
 		sessionVideoRepository,
+		remoteSessionRepository,
 		fileStorage,
 		actionLogService,
+		videoservice.ParseFrameStorageFormat(getEnv("VIDEO_FRAME_STORAGE_FORMAT", string(videoservice.FrameStorageIndividual))),
+		int(getEnvFloat("VIDEO_MAX_FRAMES_PER_RECORDING", videoservice.DefaultMaxFramesPerRecording)),
+		partialRecordingPolicy,
 	)
+	// Grabaciones simultáneas (VIDEO_MAX_CONCURRENT_RECORDINGS, 0 = sin límite): las nuevas por encima del máximo
+	// se rechazan con recording_capacity_reached y la sesión sigue sin grabar
+	videoService.(videoservice.IRecordingCapacityService).SetMaxConcurrentRecordings(
+		int(getEnvFloat("VIDEO_MAX_CONCURRENT_RECORDINGS", videoservice.DefaultMaxConcurrentRecordings)))
+	// Una grabación sin frames durante VIDEO_RECORDING_STALE_TIMEOUT que el cliente nunca finalizó deja de ocupar hueco
+	videoService.(videoservice.IRecordingCapacityService).SetRecordingStaleTimeout(
+		getEnvDuration("VIDEO_RECORDING_STALE_TIMEOUT", videoservice.DefaultRecordingStaleTimeout))
 
 	// Inicializar dependencias para file transfer service
 	fileTransferRepository := mysql.NewFileTransferRepository(db)
+
+	// Cuota de almacenamiento por cliente (grabaciones + transferencias)
+	storageQuotaService := storagequotaservice.NewStorageQuotaService(
+		sessionVideoRepository,
+		fileTransferRepository,
+		getEnvFloat("STORAGE_QUOTA_MB_PER_CLIENT", storagequotaservice.DefaultClientQuotaMB),
+	)
+
 	fileTransferService := filetransferservice.NewFileTransferService(
 		fileTransferRepository,
 		actionLogRepository,
 		fileStorage,
+		storageQuotaService,
 	)
+	// Directorios desde los que un admin puede enviar archivos por server_file_path (vacío = solo archivos subidos)
+	if err := fileTransferService.SetAllowedSourceDirs(filetransferservice.ParseSourceDirs(getEnv("FILE_TRANSFER_SOURCE_DIRS", ""))); err != nil {
+		log.Fatalf("FILE_TRANSFER_SOURCE_DIRS inválido: %v", err)
+	}
+	// Segunda transferencia al mismo archivo de un PC con otra pendiente o en curso: queue (espera) o reject (409)
+	destinationBusyPolicy, err := filetransferservice.ParseDestinationBusyPolicy(getEnv("FILE_TRANSFER_DESTINATION_BUSY", string(filetransferservice.DefaultDestinationBusyPolicy)))
+	if err != nil {
+		log.Fatalf("FILE_TRANSFER_DESTINATION_BUSY inválido: %v", err)
+	}
+	fileTransferService.SetDestinationBusyPolicy(destinationBusyPolicy)
+	// Plantilla de destino en el cliente con {session}, {date}, {pc} y {file} (vacía = Descargas/RemoteDesk)
+	if err := fileTransferService.SetClientDestinationTemplate(getEnv("FILE_TRANSFER_DESTINATION_TEMPLATE", "")); err != nil {
+		log.Fatalf("FILE_TRANSFER_DESTINATION_TEMPLATE inválido: %v", err)
+	}
+
+	// Feature flags (FEATURE_*) para activar/desactivar comportamientos sin redesplegar código
+	featureFlags := featureflagservice.NewFeatureFlagService(os.LookupEnv)
 
 	// Crear handlers con las dependencias correctas
 	authHandler := handlers.NewAuthHandler(authService)
 	adminWSHandler := handlers.NewAdminWebSocketHandler(authService, remoteSessionService)
 	webSocketHandler := handlers.NewWebSocketHandler(authService, pcService, remoteSessionService, videoService, fileTransferService, adminWSHandler)
 
+	// Registro de las goroutines que envían transferencias (GET/DELETE /api/v1/admin/tasks)
+	taskRegistry := backgroundtaskservice.NewTaskRegistry()
+	webSocketHandler.SetTaskRegistry(taskRegistry)
+
+	// Trabajos periódicos (GET /api/v1/admin/jobs): como mucho JOBS_MAX_CONCURRENT a la vez para no saturar la BD
+	jobScheduler := backgroundtaskservice.NewJobScheduler(int(getEnvFloat("JOBS_MAX_CONCURRENT", backgroundtaskservice.DefaultMaxConcurrentJobs)))
+
 	// Establecer referencia circular entre handlers
 	adminWSHandler.SetClientWSHandler(webSocketHandler)
+	adminWSHandler.SetFeatureFlags(featureFlags)
+	webSocketHandler.SetFeatureFlags(featureFlags)
+	webSocketHandler.SetConnectionHistoryService(connectionHistoryService)
+	webSocketHandler.SetStorageQuotaService(storageQuotaService)
+	webSocketHandler.SetRequireChunkEncryption(getEnvBool("FILE_TRANSFER_REQUIRE_ENCRYPTION", false))
+	// Reintentos de cada chunk ante errores de escritura, acotados también por transferencia
+	webSocketHandler.SetChunkRetryConfig(handlers.ChunkRetryConfig{
+		PerChunk:     int(getEnvFloat("FILE_TRANSFER_CHUNK_RETRIES", handlers.DefaultChunkRetriesPerChunk)),
+		Backoff:      getEnvDuration("FILE_TRANSFER_CHUNK_RETRY_BACKOFF", handlers.DefaultChunkRetryBackoff),
+		MaxBackoff:   handlers.DefaultChunkRetryMaxBackoff,
+		TotalRetries: int(getEnvFloat("FILE_TRANSFER_CHUNK_RETRIES_TOTAL", handlers.DefaultChunkRetriesTotal)),
+	})
+	webSocketHandler.SetMaxPendingTransfersPerClient(int(getEnvFloat("FILE_TRANSFER_MAX_PENDING_PER_CLIENT", handlers.DefaultMaxPendingTransfersPerClient)))
+	webSocketHandler.SetFrameResolutionLimit(frameResolutionLimit)
+	webSocketHandler.SetValidateJPEGFrames(getEnvBool("FRAME_VALIDATE_JPEG", true))
+	webSocketHandler.SetOutboundBufferConfig(outboundBufferConfig)
+	adminWSHandler.SetOutboundBufferConfig(outboundBufferConfig)
+
+	// Límite de input_command por sesión para proteger al PC cliente de inundaciones (0 = sin límite)
+	adminWSHandler.SetInputRateLimit(handlers.InputRateLimitConfig{
+		CommandsPerSecond: getEnvFloat("WS_INPUT_COMMANDS_PER_SECOND", handlers.DefaultInputCommandsPerSecond),
+		Burst:             int(getEnvFloat("WS_INPUT_COMMAND_BURST", handlers.DefaultInputCommandBurst)),
+	})
+
+	// Límite de conexiones WebSocket con aviso al acercarse al máximo (0 = sin límite)
+	connectionCapacity := handlers.NewConnectionCapacity(handlers.ConnectionCapacityConfig{
+		MaxClientConnections: int(getEnvFloat("WS_MAX_CLIENT_CONNECTIONS", 0)),
+		MaxAdminConnections:  int(getEnvFloat("WS_MAX_ADMIN_CONNECTIONS", 0)),
+		WarningRatio:         getEnvFloat("WS_CAPACITY_WARNING_RATIO", handlers.DefaultCapacityWarningRatio),
+	})
+	connectionCapacity.SetWarningNotifier(adminWSHandler.BroadcastCapacityWarning)
+	webSocketHandler.SetConnectionCapacity(connectionCapacity)
+	adminWSHandler.SetConnectionCapacity(connectionCapacity)
+
+	// Agrupa las conexiones/desconexiones masivas de PCs en un pc_list_delta por ventana (0 = un mensaje por evento)
+	adminWSHandler.SetPCBroadcastBatchWindow(getEnvDuration("WS_ADMIN_BROADCAST_BATCH_WINDOW", handlers.DefaultPCBroadcastBatchWindow))
+
+	// Pings del servidor a los administradores: un dashboard inactivo sigue conectado mientras responda con pong
+	adminWSHandler.SetAdminKeepaliveConfig(handlers.AdminKeepaliveConfig{
+		PingInterval:    getEnvDuration("WS_ADMIN_PING_INTERVAL", handlers.DefaultAdminPingInterval),
+		PresenceTimeout: getEnvDuration("WS_ADMIN_PRESENCE_TIMEOUT", handlers.DefaultAdminPresenceTimeout),
+	})
+
+	// Macros de input: grabación de comandos durante una sesión y reproducción en otras sesiones
+	macroRepository := mysql.NewMacroRepository(db)
+	macroService := macroservice.NewMacroService(macroRepository, remoteSessionService, adminWSHandler)
+	adminWSHandler.SetInputCommandRecorder(macroService)
+	macroHandler := httpHandlers.NewMacroHandler(macroService)
 
 	// Configurar callback para notificar sesiones terminadas
 	remoteSessionService.SetSessionEndedNotifier(func(sessionID, clientPCID, adminUserID string) {
+		// Las grabaciones que el cliente no llegó a finalizar dejan de contar para VIDEO_MAX_CONCURRENT_RECORDINGS
+		videoService.ReleaseSessionRecordings(sessionID)
+		// Una grabación de macro sin guardar pertenece a la sesión y no sirve una vez terminada
+		macroService.DiscardRecording(sessionID)
 		err := adminWSHandler.NotifySessionEnded(sessionID, clientPCID, adminUserID)
 		if err != nil {
 			log.Printf("Error notifying session ended: %v", err)
@@ -107,18 +301,159 @@ func main() {
 		}
 	})
 
-	pcHandler := handlers.NewPCHandler(pcService, authService)
+	// Traspaso de sesiones entre administradores: el destino debe tener el panel conectado
+	remoteSessionService.SetAdminConnectedChecker(adminWSHandler.IsAdminConnected)
+	remoteSessionService.SetSessionTransferredNotifier(adminWSHandler.NotifySessionOwnershipTransferred)
+	remoteSessionService.SetClientSessionTransferredNotifier(func(sessionID, clientPCID, toAdminID string) {
+		if err := webSocketHandler.SendSessionOwnershipTransferredToClient(sessionID, clientPCID, toAdminID); err != nil {
+			log.Printf("Error notifying client session transferred: %v", err)
+		}
+	})
+
+	// Aplicar la política de grabaciones parciales a las sesiones rechazadas o fallidas
+	remoteSessionService.SetUnsuccessfulSessionEndNotifier(func(sessionID, adminUserID string, status remotesession.SessionStatus) {
+		if _, err := videoService.ApplyPartialRecordingPolicy(context.Background(), sessionID, adminUserID, status); err != nil {
+			log.Printf("Error applying partial recording policy to session %s: %v", sessionID, err)
+		}
+	})
+
+	// Solicitudes de control en cola para PCs offline: caducan tras SESSION_QUEUE_TIMEOUT y se avisa al administrador
+	remoteSessionService.SetQueueTimeout(getEnvDuration("SESSION_QUEUE_TIMEOUT", remotesessionservice.DefaultSessionQueueTimeout))
+	// El cliente recibe SESSION_APPROVAL_TIMEOUT en remote_control_request; sin respuesta en ese plazo la solicitud se rechaza
+	remoteSessionService.SetApprovalTimeout(getEnvDuration("SESSION_APPROVAL_TIMEOUT", remotesessionservice.DefaultSessionApprovalTimeout))
+	registerJob(jobScheduler, "session_approval_expiry", getEnvDuration("SESSION_APPROVAL_EXPIRY_INTERVAL", 15*time.Second), func(ctx context.Context) error {
+		_, err := remoteSessionService.ExpirePendingApprovals(ctx)
+		return err
+	})
+	// Con SESSION_REQUIRE_JUSTIFICATION=true cada solicitud de control debe indicar un motivo o un número de ticket
+	remoteSessionService.SetRequireJustification(getEnvBool("SESSION_REQUIRE_JUSTIFICATION", false))
+	// Los frames de una grabación solo se aceptan de su PC, mientras la sesión está activa o durante RECORDING_GRACE_PERIOD
+	remoteSessionService.SetRecordingGracePeriod(getEnvDuration("RECORDING_GRACE_PERIOD", remotesessionservice.DefaultRecordingGracePeriod))
+	remoteSessionService.SetQueueExpiredNotifier(adminWSHandler.NotifySessionQueueExpired)
+	registerJob(jobScheduler, "session_queue_expiry", getEnvDuration("SESSION_QUEUE_EXPIRY_INTERVAL", time.Minute), func(ctx context.Context) error {
+		_, err := remoteSessionService.ExpireQueuedSessions(ctx)
+		return err
+	})
+
+	// Heartbeat anunciado a los clientes: sin mensajes durante HEARTBEAT_MISSED_LIMIT × HEARTBEAT_INTERVAL el WebSocket
+	// se cierra y los clientes REST pasan a OFFLINE
+	webSocketHandler.SetHeartbeatConfig(handlers.HeartbeatConfig{
+		Interval:    getEnvDuration("HEARTBEAT_INTERVAL", handlers.DefaultHeartbeatInterval),
+		MissedLimit: int(getEnvFloat("HEARTBEAT_MISSED_LIMIT", handlers.DefaultHeartbeatMissedLimit)),
+	})
+	registerJob(jobScheduler, "rest_client_expiry", getEnvDuration("REST_CLIENT_EXPIRY_INTERVAL", 15*time.Second), func(ctx context.Context) error {
+		webSocketHandler.ExpireRESTClients(ctx, time.Now())
+		return nil
+	})
+
+	// Aviso stream_not_starting si el cliente activa la sesión y no envía frames en STREAM_FIRST_FRAME_TIMEOUT (0 = desactivado)
+	webSocketHandler.SetStreamWatchdogConfig(handlers.StreamWatchdogConfig{
+		FirstFrameTimeout:   getEnvDuration("STREAM_FIRST_FRAME_TIMEOUT", handlers.DefaultFirstFrameTimeout),
+		EndSessionOnTimeout: getEnvBool("STREAM_END_ON_NO_FRAMES", false),
+	})
+
+	// Tras control_session_ended el cliente debe confirmar con session_end_ack; si no, se le desconecta (0 = no esperar)
+	webSocketHandler.SetSessionEndAckTimeout(getEnvDuration("SESSION_END_ACK_TIMEOUT", handlers.DefaultSessionEndAckTimeout))
+
+	// PCs fijados (favoritos) por administrador; el estado online se toma de las conexiones vivas
+	pinnedPCRepository := mysql.NewPinnedPCRepository(db)
+	adminWSHandler.SetPinnedPCRepository(pinnedPCRepository)
+	adminWSHandler.SetNotificationPreferenceRepository(mysql.NewNotificationPreferenceRepository(db))
+	pinnedPCService := pcservice.NewPinnedPCService(pinnedPCRepository, clientPCRepository)
+
+	pcHandler := handlers.NewPCHandler(pcService, connectionHistoryService, pinnedPCService, webSocketHandler, authService)
+	// Purga de los datos de un PC dado de baja (grabaciones, transferencias, sesiones y el propio PC)
+	pcPurgeService := pcservice.NewPCPurgeService(
+		clientPCRepository, remoteSessionRepository, sessionVideoRepository, fileTransferRepository, fileStorage, actionLogService)
+	pcPurgeService.(*pcservice.PCPurgeService).SetRecordingArtifactCleaner(videoService.(videoservice.IRecordingArtifactService))
+	pcHandler.SetPurgeService(pcPurgeService)
 
 	// Crear handler de control remoto con WebSocket handler (no el hub separado)
 	remoteControlHandler := httpHandlers.NewRemoteControlHandler(remoteSessionService, webSocketHandler)
+	remoteControlHandler.SetRecordingProvider(videoService)
+	remoteControlHandler.SetInputCapabilityProvider(webSocketHandler)
 
 	// Crear handler de video para frames individuales
 	videoHandler := httpHandlers.NewVideoHandler(remoteSessionService, videoService, authService)
+	videoHandler.SetThumbnailService(videoService.(videoservice.IRecordingThumbnailService))
+	orphanedRecordingService := videoService.(videoservice.IOrphanedRecordingService)
+	videoHandler.SetOrphanedRecordingService(orphanedRecordingService)
 
 	// Crear handler de transferencia de archivos
 	fileTransferHandler := httpHandlers.NewFileTransferHandler(fileTransferService, authService, fileStorage, webSocketHandler)
 
-	router := gin.Default()
+	// Auditoría de lecturas de grabaciones y transferencias: una entrada por visualización (ACCESS_AUDIT_WINDOW de inactividad)
+	accessAuditor := actionlogservice.NewAccessAuditor(actionLogService, getEnvDuration("ACCESS_AUDIT_WINDOW", actionlogservice.DefaultAccessAuditWindow))
+	videoHandler.SetAccessAuditor(accessAuditor)
+	fileTransferHandler.SetAccessAuditor(accessAuditor)
+	fileTransferHandler.SetTaskRegistry(taskRegistry)
+
+	// Detalle de sesión: estado, transferencias, grabación y audit log en una sola respuesta
+	sessionDetailHandler := httpHandlers.NewSessionDetailHandler(remoteSessionService, fileTransferService, videoService, actionLogService)
+	sessionDetailHandler.SetAccessAuditor(accessAuditor)
+
+	// Reconciliación manual de estados PC/sesión contra las conexiones WebSocket vivas
+	reconciliationService := reconciliationservice.NewReconciliationService(pcService, remoteSessionRepository, remoteSessionService, connectionHistoryService)
+	reconciliationHandler := httpHandlers.NewReconciliationHandler(reconciliationService, webSocketHandler)
+	// Con RECONCILIATION_INTERVAL > 0 la reconciliación también se ejecuta periódicamente (0 = solo manual)
+	if reconciliationInterval := getEnvDuration("RECONCILIATION_INTERVAL", 0); reconciliationInterval > 0 {
+		registerJob(jobScheduler, "status_reconciliation", reconciliationInterval, func(ctx context.Context) error {
+			_, err := reconciliationService.Reconcile(ctx, webSocketHandler.ConnectedPCIDs())
+			return err
+		})
+	}
+
+	// Con ORPHANED_RECORDINGS_CHECK_INTERVAL > 0 se avisa periódicamente de las grabaciones cuya sesión ya no existe;
+	// solo se informa: la limpieza la pide un super-administrador con DELETE /recordings/orphaned
+	if orphanCheckInterval := getEnvDuration("ORPHANED_RECORDINGS_CHECK_INTERVAL", 0); orphanCheckInterval > 0 {
+		registerJob(jobScheduler, "orphaned_recordings_check", orphanCheckInterval, func(ctx context.Context) error {
+			report, err := orphanedRecordingService.FindOrphanedRecordings(ctx)
+			if err == nil && report.Count > 0 {
+				log.Printf("⚠️ ORPHANED RECORDINGS: %d recordings without session use %d bytes on disk", report.Count, report.TotalBytes)
+			}
+			return err
+		})
+	}
+
+	// En modo sprites las grabaciones finalizadas se compactan en hojas en segundo plano, no al finalizarlas
+	recordingCompactionService := videoService.(videoservice.IRecordingCompactionService)
+	registerJob(jobScheduler, "recording_compaction", getEnvDuration("VIDEO_SPRITE_COMPACTION_INTERVAL", videoservice.DefaultRecordingCompactionInterval), func(ctx context.Context) error {
+		_, err := recordingCompactionService.CompactPendingRecordings(ctx)
+		return err
+	})
+
+	// Las subidas de grabaciones (por chunks o por partes) sin actividad durante VIDEO_UPLOAD_TTL se eliminan del disco
+	videoUploadSweepService := videoService.(videoservice.IVideoUploadSweepService)
+	videoUploadTTL := getEnvDuration("VIDEO_UPLOAD_TTL", videoservice.DefaultVideoUploadTTL)
+	registerJob(jobScheduler, "video_upload_sweep", getEnvDuration("VIDEO_UPLOAD_SWEEP_INTERVAL", videoservice.DefaultVideoUploadSweepInterval), func(ctx context.Context) error {
+		_, err := videoUploadSweepService.SweepAbandonedUploads(ctx, videoUploadTTL)
+		return err
+	})
+
+	// Consulta de solo lectura del estado de los feature flags
+	featureFlagHandler := httpHandlers.NewFeatureFlagHandler(featureFlags)
+
+	// Filtro de tipos de acción del audit log
+	auditFilterHandler := httpHandlers.NewAuditFilterHandler(actionLogFilter)
+	auditLogExportHandler := httpHandlers.NewAuditLogExportHandler(actionLogService.(actionlogservice.IAuditLogExportService))
+
+	// Tareas en segundo plano: listado y cancelación de transferencias en curso
+	taskHandler := httpHandlers.NewTaskHandler(taskRegistry)
+	jobHandler := httpHandlers.NewJobHandler(jobScheduler)
+	userTokenHandler := httpHandlers.NewUserTokenHandler(authService, adminWSHandler, actionLogService)
+
+	// Configuración efectiva para diagnóstico de despliegues (sin JWT_SECRET, DB_PASSWORD ni otros secretos)
+	configHandler := httpHandlers.NewConfigHandler(effectiveConfig)
+	// CORS no es configurable: se admite cualquier origen
+	effectiveConfig["CORS_ALLOWED_ORIGINS"] = "*"
+
+	// Logger de gin y Recovery propio: un panic en un handler responde con el sobre de error estándar
+	router := gin.New()
+	router.Use(gin.Logger(), middleware.Recovery())
+	// X-Forwarded-For y X-Real-IP solo se aceptan de estos proxies (IPs o CIDR); vacío = IP del socket
+	if err := router.SetTrustedProxies(middleware.ParseTrustedProxies(getEnv("TRUSTED_PROXIES", ""))); err != nil {
+		log.Fatalf("TRUSTED_PROXIES inválido: %v", err)
+	}
 
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
@@ -131,21 +466,45 @@ func main() {
 		c.Next()
 	})
 
-	api := router.Group("/api")
+	// Límite de tamaño del body; la subida de archivos tiene su propio límite (REQUEST_MAX_BODY_MB / UPLOAD_MAX_BODY_MB)
+	router.Use(middleware.MaxBodySize(
+		megabytesToBytes(getEnvFloat("REQUEST_MAX_BODY_MB", 1)),
+		map[string]int64{
+			"/api/v1/admin/sessions/:sessionId/files/send": megabytesToBytes(getEnvFloat("UPLOAD_MAX_BODY_MB", 512)),
+			"/api/client/recordings/:videoId/upload":       megabytesToBytes(getEnvFloat("RECORDING_UPLOAD_PART_MAX_MB", 64)),
+		},
+	))
+
+	// API v1: todas las respuestas usan el envelope {success, data, error{code,message}}
+	api := router.Group("/api/v1")
 	authHandler.RegisterRoutes(api)
 
+	// Todo el grupo admite cualquier rol de administración; las acciones de control exigen operador y las
+	// destructivas o de configuración, super-administrador
+	requireOperator := middleware.RequireRole(user.SessionControlRoles()...)
+	requireSuperAdmin := middleware.RequireRole(user.SuperAdminRoles()...)
+
 	admin := api.Group("/admin")
-	admin.Use(middleware.AuthMiddleware(authService))
+	admin.Use(middleware.AuthMiddleware(authService), middleware.RequireRole(user.AdminRoles()...))
 	{
 		admin.GET("/pcs", pcHandler.GetAllClientPCs)
 		admin.GET("/pcs/online", pcHandler.GetOnlineClientPCs)
+		admin.GET("/pcs/pinned", pcHandler.GetPinnedPCs)
+		admin.POST("/pcs/:pcId/pin", pcHandler.PinPC)
+		admin.DELETE("/pcs/:pcId/pin", pcHandler.UnpinPC)
+		admin.GET("/pcs/:pcId/connection-history", pcHandler.GetConnectionHistory)
+		admin.PUT("/pcs/:pcId/auto-accept", requireOperator, pcHandler.UpdateAutoAcceptPolicy)
+		admin.DELETE("/pcs/:pcId/purge", requireSuperAdmin, pcHandler.PurgePC)
 
 		// Rutas para sesiones de control remoto
-		admin.POST("/sessions/initiate", remoteControlHandler.InitiateSession)
+		admin.POST("/sessions/initiate", requireOperator, remoteControlHandler.InitiateSession)
 		admin.GET("/sessions/:sessionId/status", remoteControlHandler.GetSessionStatus)
-		admin.POST("/sessions/:sessionId/end", remoteControlHandler.EndSession)
+		admin.POST("/sessions/:sessionId/end", requireOperator, remoteControlHandler.EndSession)
+		admin.POST("/sessions/:sessionId/transfer", requireOperator, remoteControlHandler.TransferSession)
 		admin.GET("/sessions/active", remoteControlHandler.GetActiveSessions)
 		admin.GET("/sessions/my", remoteControlHandler.GetUserSessions)
+		admin.GET("/sessions/:sessionId/detail", sessionDetailHandler.GetSessionDetail)
+		admin.GET("/access-report", requireSuperAdmin, remoteControlHandler.GetAccessReport)
 
 		// Nuevas rutas para video frames individuales
 		admin.GET("/sessions/:sessionId/recording/metadata", videoHandler.GetRecordingMetadata)
@@ -154,13 +513,60 @@ func main() {
 		// Rutas para grabaciones por cliente
 		admin.GET("/recordings", videoHandler.GetAllRecordings)
 		admin.GET("/clients/:clientId/recordings", videoHandler.GetClientRecordings)
+		admin.GET("/recordings/:videoId/storage", videoHandler.GetRecordingStorage)
+		admin.GET("/recordings/:videoId/thumbnails", videoHandler.GetRecordingThumbnails)
+		admin.GET("/recordings/active", videoHandler.GetActiveRecordings)
+		admin.GET("/recordings/orphaned", videoHandler.GetOrphanedRecordings)
+		admin.DELETE("/recordings/orphaned", requireSuperAdmin, videoHandler.CleanOrphanedRecordings)
 
 		// Rutas para transferencia de archivos
-		admin.POST("/sessions/:sessionId/files/send", fileTransferHandler.SendFile)
+		admin.POST("/sessions/:sessionId/files/send", requireOperator, fileTransferHandler.SendFile)
 		admin.GET("/sessions/:sessionId/files", fileTransferHandler.GetTransfersBySession)
 		admin.GET("/transfers/:transferId/status", fileTransferHandler.GetTransferStatus)
+		admin.GET("/transfers/:transferId/download", fileTransferHandler.DownloadTransferFile)
 		admin.GET("/transfers/pending", fileTransferHandler.GetPendingTransfers)
 		admin.GET("/clients/:clientId/transfers", fileTransferHandler.GetTransfersByClient)
+
+		// Reconciliación de estados tras caídas
+		admin.POST("/reconcile", requireSuperAdmin, reconciliationHandler.Reconcile)
+		admin.GET("/diagnostics/status-drift", reconciliationHandler.GetStatusDrift)
+
+		// Macros de input
+		admin.GET("/macros", macroHandler.ListMacros)
+		admin.POST("/sessions/:sessionId/macros/recording", requireOperator, macroHandler.StartRecording)
+		admin.POST("/sessions/:sessionId/macros", requireOperator, macroHandler.SaveMacro)
+		admin.POST("/sessions/:sessionId/macros/:macroId/replay", requireOperator, macroHandler.ReplayMacro)
+
+		// Feature flags (solo lectura)
+		admin.GET("/flags", featureFlagHandler.GetFlags)
+
+		// Configuración efectiva (solo lectura, sin secretos)
+		admin.GET("/config", requireSuperAdmin, configHandler.GetConfig)
+
+		// Filtro de tipos de acción del audit log
+		admin.GET("/audit/action-filter", auditFilterHandler.GetActionFilter)
+		admin.PUT("/audit/action-filter", requireSuperAdmin, auditFilterHandler.UpdateActionFilter)
+		admin.GET("/audit-logs/export.csv", requireSuperAdmin, auditLogExportHandler.ExportCSV)
+
+		// Tareas en segundo plano
+		admin.GET("/tasks", taskHandler.ListTasks)
+		admin.DELETE("/tasks/:taskId", requireSuperAdmin, taskHandler.CancelTask)
+		admin.GET("/jobs", jobHandler.ListJobs)
+
+		// Revocación de tokens comprometidos
+		admin.POST("/users/:userId/revoke-tokens", requireSuperAdmin, userTokenHandler.RevokeTokens)
+	}
+
+	// Alternativa REST para clientes que no pueden mantener un WebSocket abierto
+	router.POST("/api/client/auth", authHandler.ClientLogin)
+	clientAPI := router.Group("/api/client")
+	clientAPI.Use(middleware.AuthMiddleware(authService))
+	{
+		clientAPI.POST("/register", webSocketHandler.RegisterPCViaREST)
+		clientAPI.POST("/heartbeat", webSocketHandler.HeartbeatViaREST)
+		// Subida reanudable de grabaciones terminadas, fuera del WebSocket del streaming
+		clientAPI.POST("/recordings/:videoId/upload", webSocketHandler.UploadRecordingViaREST)
+		clientAPI.GET("/recordings/:videoId/upload", webSocketHandler.GetRecordingUploadProgress)
 	}
 
 	ws := router.Group("/ws")
@@ -174,6 +580,8 @@ func main() {
 			"status":  "ok",
 			"message": "Escritorio Remoto Backend - FASE 4 PASO 1: Sesiones de Control Remoto",
 			"version": "0.4.1-fase4-paso1-remote-sessions",
+			// Métricas de conexiones WebSocket abiertas, picos, avisos y rechazos por capacidad
+			"connections": connectionCapacity.Stats(),
 		})
 	})
 
@@ -212,30 +620,114 @@ func main() {
 	log.Printf("Servidor iniciando en puerto %s", port)
 	log.Printf("WebSocket Cliente: ws://localhost:%s/ws/client", port)
 	log.Printf("WebSocket Admin: ws://localhost:%s/ws/admin", port)
-	log.Printf("API Admin PCs: http://localhost:%s/api/admin/pcs", port)
-	log.Printf("API Admin PCs Online: http://localhost:%s/api/admin/pcs/online", port)
-	log.Printf("API Iniciar Sesión: http://localhost:%s/api/admin/sessions/initiate", port)
-	log.Printf("API Estado Sesión: http://localhost:%s/api/admin/sessions/:sessionId/status", port)
-	log.Printf("API Sesiones Activas: http://localhost:%s/api/admin/sessions/active", port)
-	log.Printf("API Mis Sesiones: http://localhost:%s/api/admin/sessions/my", port)
-	log.Printf("API Video Metadata: http://localhost:%s/api/admin/sessions/:sessionId/recording/metadata", port)
-	log.Printf("API Video Frames: http://localhost:%s/api/admin/sessions/:sessionId/frames/:frameNumber", port)
-	log.Printf("API Todas las Grabaciones: http://localhost:%s/api/admin/recordings", port)
-	log.Printf("API Grabaciones por Cliente: http://localhost:%s/api/admin/clients/:clientId/recordings", port)
-	log.Printf("API Enviar Archivo: http://localhost:%s/api/admin/sessions/:sessionId/files/send", port)
-	log.Printf("API Transferencias por Sesión: http://localhost:%s/api/admin/sessions/:sessionId/files", port)
-	log.Printf("API Estado de Transferencia: http://localhost:%s/api/admin/transfers/:transferId/status", port)
-	log.Printf("API Transferencias Pendientes: http://localhost:%s/api/admin/transfers/pending", port)
-	log.Printf("API Transferencias por Cliente: http://localhost:%s/api/admin/clients/:clientId/transfers", port)
-
-	if err := router.Run(":" + port); err != nil {
+	log.Printf("API Cliente REST (registro/heartbeat): http://localhost:%s/api/client/register", port)
+	log.Printf("API Admin PCs: http://localhost:%s/api/v1/admin/pcs", port)
+	log.Printf("API Admin PCs Online: http://localhost:%s/api/v1/admin/pcs/online", port)
+	log.Printf("API PCs Fijados: http://localhost:%s/api/v1/admin/pcs/pinned", port)
+	log.Printf("API Fijar PC: http://localhost:%s/api/v1/admin/pcs/:pcId/pin", port)
+	log.Printf("API Historial de Conexiones: http://localhost:%s/api/v1/admin/pcs/:pcId/connection-history", port)
+	log.Printf("API Auto-Aceptación PC: http://localhost:%s/api/v1/admin/pcs/:pcId/auto-accept", port)
+	log.Printf("API Iniciar Sesión: http://localhost:%s/api/v1/admin/sessions/initiate", port)
+	log.Printf("API Estado Sesión: http://localhost:%s/api/v1/admin/sessions/:sessionId/status", port)
+	log.Printf("API Sesiones Activas: http://localhost:%s/api/v1/admin/sessions/active", port)
+	log.Printf("API Mis Sesiones: http://localhost:%s/api/v1/admin/sessions/my", port)
+	log.Printf("API Video Metadata: http://localhost:%s/api/v1/admin/sessions/:sessionId/recording/metadata", port)
+	log.Printf("API Video Frames: http://localhost:%s/api/v1/admin/sessions/:sessionId/frames/:frameNumber", port)
+	log.Printf("API Todas las Grabaciones: http://localhost:%s/api/v1/admin/recordings", port)
+	log.Printf("API Grabaciones en Curso: http://localhost:%s/api/v1/admin/recordings/active", port)
+	log.Printf("API Miniaturas de Grabación: http://localhost:%s/api/v1/admin/recordings/:videoId/thumbnails", port)
+	log.Printf("API Grabaciones por Cliente: http://localhost:%s/api/v1/admin/clients/:clientId/recordings", port)
+	log.Printf("API Enviar Archivo: http://localhost:%s/api/v1/admin/sessions/:sessionId/files/send", port)
+	log.Printf("API Transferencias por Sesión: http://localhost:%s/api/v1/admin/sessions/:sessionId/files", port)
+	log.Printf("API Estado de Transferencia: http://localhost:%s/api/v1/admin/transfers/:transferId/status", port)
+	log.Printf("API Descargar Archivo de Transferencia: http://localhost:%s/api/v1/admin/transfers/:transferId/download", port)
+	log.Printf("API Transferencias Pendientes: http://localhost:%s/api/v1/admin/transfers/pending", port)
+	log.Printf("API Transferencias por Cliente: http://localhost:%s/api/v1/admin/clients/:clientId/transfers", port)
+	log.Printf("API Reconciliación de Estados: http://localhost:%s/api/v1/admin/reconcile", port)
+	log.Printf("API Diagnóstico de Estados: http://localhost:%s/api/v1/admin/diagnostics/status-drift", port)
+	log.Printf("API Macros: http://localhost:%s/api/v1/admin/macros", port)
+	log.Printf("API Grabar Macro: http://localhost:%s/api/v1/admin/sessions/:sessionId/macros/recording", port)
+	log.Printf("API Reproducir Macro: http://localhost:%s/api/v1/admin/sessions/:sessionId/macros/:macroId/replay", port)
+	log.Printf("API Feature Flags: http://localhost:%s/api/v1/admin/flags", port)
+	log.Printf("API Configuración Efectiva: http://localhost:%s/api/v1/admin/config", port)
+	log.Printf("API Filtro del Audit Log: http://localhost:%s/api/v1/admin/audit/action-filter", port)
+	log.Printf("API Exportar Audit Log (CSV): http://localhost:%s/api/v1/admin/audit-logs/export.csv", port)
+	log.Printf("API Tareas en Segundo Plano: http://localhost:%s/api/v1/admin/tasks", port)
+	log.Printf("API Trabajos Periódicos: http://localhost:%s/api/v1/admin/jobs", port)
+
+	jobScheduler.Start(context.Background())
+
+	// Timeout por petición (REQUEST_TIMEOUT); WebSockets, subida y descarga de archivos, descarga de frames y export del audit log quedan exentos
+	server := &http.Server{
+		Addr: ":" + port,
+		Handler: middleware.RequestTimeout(router, getEnvDuration("REQUEST_TIMEOUT", middleware.DefaultRequestTimeout),
+			"/ws/*",
+			"/api/v1/admin/sessions/*/files/send",
+			"/api/client/recordings/*/upload",
+			"/api/v1/admin/transfers/*/download",
+			"/api/v1/admin/sessions/*/frames/*",
+			"/api/v1/admin/audit-logs/export.csv",
+		),
+	}
+
+	if err := server.ListenAndServe(); err != nil {
 		log.Fatalf("Error al iniciar el servidor: %v", err)
 	}
 }
 
+// registerJob registra un trabajo periódico; un intervalo no válido es un error de configuración
+func registerJob(scheduler *backgroundtaskservice.JobScheduler, name string, interval time.Duration, run backgroundtaskservice.JobFunc) {
+	if err := scheduler.Register(name, interval, run); err != nil {
+		log.Fatalf("Error registrando el trabajo periódico %s: %v", name, err)
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
+		effectiveConfig[key] = value
 		return value
 	}
+	effectiveConfig[key] = defaultValue
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			effectiveConfig[key] = parsed
+			return parsed
+		}
+		log.Printf("Valor inválido para %s: %q, usando %v", key, value, defaultValue)
+	}
+	effectiveConfig[key] = defaultValue
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			effectiveConfig[key] = parsed.String()
+			return parsed
+		}
+		log.Printf("Valor inválido para %s: %q, usando %v", key, value, defaultValue)
+	}
+	effectiveConfig[key] = defaultValue.String()
+	return defaultValue
+}
+
+// megabytesToBytes convierte un tamaño en MB (configurable) a bytes
+func megabytesToBytes(megabytes float64) int64 {
+	return int64(megabytes * 1024 * 1024)
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			effectiveConfig[key] = parsed
+			return parsed
+		}
+		log.Printf("Valor inválido para %s: %q, usando %v", key, value, defaultValue)
+	}
+	effectiveConfig[key] = defaultValue
 	return defaultValue
 }
@@ -0,0 +1,130 @@
+package actionlogservice
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+)
+
+// DefaultAccessAuditWindow inactividad tras la que el siguiente acceso de un administrador al mismo recurso
+// se considera una nueva visualización y vuelve a auditarse
+const DefaultAccessAuditWindow = 30 * time.Minute
+
+// AccessAuditor audita las lecturas de grabaciones y transferencias por parte de los administradores.
+// Los accesos repetidos al mismo recurso (p. ej. cada frame de una grabación) se agrupan en una sola
+// entrada por visualización: solo se registra el primero y los siguientes dentro de la ventana la extienden.
+type AccessAuditor struct {
+	actionLogService IActionLogService
+	window           time.Duration
+	now              func() time.Time
+
+	// Accesos recientes del más antiguo al más reciente, con su elemento por clave
+	// "adminUserID|actionType|entityType|entityID": cada acceso mueve su clave al final, así las ventanas
+	// expiradas quedan siempre al principio y se descartan sin recorrer las demás
+	accessOrder *list.List
+	lastAccess  map[string]*list.Element
+	mutex       sync.Mutex
+}
+
+// recentAccess último acceso de un administrador a un recurso
+type recentAccess struct {
+	key string
+	at  time.Time
+}
+
+// NewAccessAuditor crea el auditor de accesos; una ventana <= 0 usa DefaultAccessAuditWindow
+func NewAccessAuditor(actionLogService IActionLogService, window time.Duration) *AccessAuditor {
+	if window <= 0 {
+		window = DefaultAccessAuditWindow
+	}
+	return &AccessAuditor{
+		actionLogService: actionLogService,
+		window:           window,
+		now:              time.Now,
+		accessOrder:      list.New(),
+		lastAccess:       make(map[string]*list.Element),
+	}
+}
+
+// LogRecordingViewed registra que el administrador está viendo la grabación de una sesión (metadatos o frames)
+func (a *AccessAuditor) LogRecordingViewed(ctx context.Context, adminUserID, sessionID, videoID, accessedVia string) {
+	a.logAccess(ctx, actionlog.ActionRecordingViewed, "SESSION_VIDEO", videoID, adminUserID,
+		fmt.Sprintf("Recording of session %s viewed", sessionID),
+		map[string]interface{}{
+			"video_id":     videoID,
+			"session_id":   sessionID,
+			"accessed_via": accessedVia,
+		})
+}
+
+// LogFileTransferViewed registra que el administrador consultó una transferencia de archivo
+func (a *AccessAuditor) LogFileTransferViewed(ctx context.Context, adminUserID, transferID, fileName string) {
+	a.logAccess(ctx, actionlog.ActionFileTransferViewed, "FILE_TRANSFER", transferID, adminUserID,
+		fmt.Sprintf("File transfer %s viewed", fileName),
+		map[string]interface{}{
+			"transfer_id": transferID,
+			"file_name":   fileName,
+		})
+}
+
+// LogFileTransferDownloaded registra que el administrador descargó la copia del servidor de una transferencia;
+// las peticiones Range de una misma descarga cuentan como una sola
+func (a *AccessAuditor) LogFileTransferDownloaded(ctx context.Context, adminUserID, transferID, fileName string, sizeBytes int64) {
+	a.logAccess(ctx, actionlog.ActionFileTransferDownloaded, "FILE_TRANSFER", transferID, adminUserID,
+		fmt.Sprintf("File transfer %s downloaded", fileName),
+		map[string]interface{}{
+			"transfer_id": transferID,
+			"file_name":   fileName,
+			"size_bytes":  sizeBytes,
+		})
+}
+
+// logAccess registra la entrada salvo que el mismo administrador haya accedido al recurso dentro de la ventana
+func (a *AccessAuditor) logAccess(ctx context.Context, actionType actionlog.ActionType, entityType, entityID, adminUserID, description string, details map[string]interface{}) {
+	if a == nil || a.actionLogService == nil || adminUserID == "" {
+		return
+	}
+
+	now := a.now()
+	// Consultar una transferencia no agrupa su descarga: cada tipo de acción lleva su propia ventana
+	key := adminUserID + "|" + string(actionType) + "|" + entityType + "|" + entityID
+
+	a.mutex.Lock()
+	a.pruneLocked(now)
+	element, seen := a.lastAccess[key]
+	if seen {
+		element.Value.(*recentAccess).at = now
+		a.accessOrder.MoveToBack(element)
+	} else {
+		a.lastAccess[key] = a.accessOrder.PushBack(&recentAccess{key: key, at: now})
+	}
+	a.mutex.Unlock()
+
+	// Tras la poda solo quedan accesos dentro de la ventana: si la clave estaba, es la misma visualización
+	if seen {
+		return
+	}
+
+	details["accessed_at"] = now.UTC().Format(time.RFC3339)
+	if err := a.actionLogService.LogAction(ctx, actionType, description, adminUserID, &entityID, &entityType, details); err != nil {
+		log.Printf("⚠️ Warning: Failed to log %s audit entry for %s: %v", actionType, entityID, err)
+	}
+}
+
+// pruneLocked descarta desde el principio los accesos cuya ventana ya expiró y se detiene en el primero que
+// sigue vigente. Requiere mutex.
+func (a *AccessAuditor) pruneLocked(now time.Time) {
+	for front := a.accessOrder.Front(); front != nil; front = a.accessOrder.Front() {
+		access := front.Value.(*recentAccess)
+		if now.Sub(access.at) <= a.window {
+			return
+		}
+		a.accessOrder.Remove(front)
+		delete(a.lastAccess, access.key)
+	}
+}
@@ -0,0 +1,76 @@
+package actionlogservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+)
+
+// recordingActionLogService guarda los tipos de acción registrados
+type recordingActionLogService struct {
+	IActionLogService
+	logged []actionlog.ActionType
+}
+
+func (s *recordingActionLogService) LogAction(ctx context.Context, actionType actionlog.ActionType, description string,
+	performedByUserID string, subjectEntityID *string, subjectEntityType *string, details map[string]interface{}) error {
+	s.logged = append(s.logged, actionType)
+	return nil
+}
+
+func TestAccessAuditor_NewViewAfterInactivityWindowIsLoggedAgain(t *testing.T) {
+	// Arrange
+	actionLogs := &recordingActionLogService{}
+	auditor := NewAccessAuditor(actionLogs, 10*time.Minute)
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	auditor.now = func() time.Time { return now }
+
+	// Act
+	auditor.LogRecordingViewed(context.Background(), "admin-1", "session-1", "video-1", "metadata")
+	now = now.Add(8 * time.Minute)
+	auditor.LogRecordingViewed(context.Background(), "admin-1", "session-1", "video-1", "frame") // misma visualización
+	auditor.LogRecordingViewed(context.Background(), "admin-2", "session-1", "video-1", "frame") // otro administrador
+	now = now.Add(11 * time.Minute)
+	auditor.LogRecordingViewed(context.Background(), "admin-1", "session-1", "video-1", "frame") // nueva visualización
+
+	// Assert
+	assert.Len(t, actionLogs.logged, 3)
+}
+
+func TestAccessAuditor_DownloadIsLoggedSeparatelyFromView(t *testing.T) {
+	// Arrange
+	actionLogs := &recordingActionLogService{}
+	auditor := NewAccessAuditor(actionLogs, 10*time.Minute)
+
+	// Act - consulta el estado y después descarga con varias peticiones Range
+	auditor.LogFileTransferViewed(context.Background(), "admin-1", "transfer-1", "report.pdf")
+	auditor.LogFileTransferDownloaded(context.Background(), "admin-1", "transfer-1", "report.pdf", 2048)
+	auditor.LogFileTransferDownloaded(context.Background(), "admin-1", "transfer-1", "report.pdf", 2048)
+
+	// Assert
+	assert.Equal(t, []actionlog.ActionType{actionlog.ActionFileTransferViewed, actionlog.ActionFileTransferDownloaded}, actionLogs.logged)
+}
+
+func TestAccessAuditor_ExpiredAccessesArePrunedFromTheFront(t *testing.T) {
+	// Arrange - video-1 se vio hace más de una ventana; video-2 se sigue viendo
+	actionLogs := &recordingActionLogService{}
+	auditor := NewAccessAuditor(actionLogs, 10*time.Minute)
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	auditor.now = func() time.Time { return now }
+	auditor.LogRecordingViewed(context.Background(), "admin-1", "session-1", "video-1", "metadata")
+	auditor.LogRecordingViewed(context.Background(), "admin-1", "session-2", "video-2", "metadata")
+	now = now.Add(6 * time.Minute)
+	auditor.LogRecordingViewed(context.Background(), "admin-1", "session-2", "video-2", "frame")
+
+	// Act
+	now = now.Add(6 * time.Minute)
+	auditor.LogRecordingViewed(context.Background(), "admin-1", "session-2", "video-2", "frame")
+
+	// Assert - solo queda la clave vigente y video-2 sigue siendo la misma visualización
+	assert.Len(t, actionLogs.logged, 2)
+	assert.Equal(t, 1, auditor.accessOrder.Len())
+	assert.Len(t, auditor.lastAccess, 1)
+}
@@ -3,6 +3,7 @@ package actionlogservice
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
@@ -37,6 +38,10 @@ type IActionLogService interface {
 // ActionLogService implementa la lógica de negocio para ActionLog
 type ActionLogService struct {
 	actionLogRepo interfaces.IActionLogRepository
+
+	// Filtro de tipos de acción a guardar, modificable en caliente
+	filter      ActionTypeFilter
+	filterMutex sync.RWMutex
 }
 
 // NewActionLogService crea una nueva instancia del servicio
@@ -51,6 +56,11 @@ func (als *ActionLogService) LogAction(ctx context.Context, actionType actionlog
 	description string, performedByUserID string, subjectEntityID *string, 
 	subjectEntityType *string, details map[string]interface{}) error {
 
+	// Tipos excluidos por el filtro: no se guardan (los obligatorios nunca se excluyen)
+	if !als.ActionTypeFilter().Allows(actionType) {
+		return nil
+	}
+
 	// Crear nueva entrada de log
 	log := actionlog.NewActionLog(
 		actionType,
@@ -0,0 +1,96 @@
+package actionlogservice
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+)
+
+// mandatoryActionTypes acciones de seguridad (autenticación y ciclo de vida de sesiones remotas) que se
+// registran siempre, aunque el filtro las excluya
+var mandatoryActionTypes = map[actionlog.ActionType]bool{
+	actionlog.ActionUserLogin:                 true,
+	actionlog.ActionUserLogout:                true,
+	actionlog.ActionUserCreated:               true,
+	actionlog.ActionUserTokensRevoked:         true,
+	actionlog.ActionRemoteSessionRequested:    true,
+	actionlog.ActionRemoteSessionStarted:      true,
+	actionlog.ActionRemoteSessionEnded:        true,
+	actionlog.ActionRemoteSessionAutoAccepted: true,
+	actionlog.ActionRemoteSessionTransferred:  true,
+}
+
+// ActionTypeFilter decide qué tipos de acción se guardan en el audit log. Con Allow vacío se guardan todos
+// salvo los de Deny; con Allow solo los indicados. Los tipos obligatorios se guardan en cualquier caso.
+type ActionTypeFilter struct {
+	Allow []actionlog.ActionType `json:"allow"`
+	Deny  []actionlog.ActionType `json:"deny"`
+}
+
+// IActionTypeFilterService consulta y cambia en caliente el filtro de tipos de acción del audit log
+type IActionTypeFilterService interface {
+	ActionTypeFilter() ActionTypeFilter
+	SetActionTypeFilter(filter ActionTypeFilter)
+}
+
+// Allows indica si una acción de ese tipo debe guardarse
+func (f ActionTypeFilter) Allows(actionType actionlog.ActionType) bool {
+	if IsMandatoryActionType(actionType) {
+		return true
+	}
+	for _, denied := range f.Deny {
+		if denied == actionType {
+			return false
+		}
+	}
+	if len(f.Allow) == 0 {
+		return true
+	}
+	for _, allowed := range f.Allow {
+		if allowed == actionType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMandatoryActionType indica si el tipo de acción no puede excluirse del audit log
+func IsMandatoryActionType(actionType actionlog.ActionType) bool {
+	return mandatoryActionTypes[actionType]
+}
+
+// MandatoryActionTypes lista ordenada de los tipos de acción que se registran siempre
+func MandatoryActionTypes() []actionlog.ActionType {
+	types := make([]actionlog.ActionType, 0, len(mandatoryActionTypes))
+	for actionType := range mandatoryActionTypes {
+		types = append(types, actionType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// ParseActionTypeList separa una lista de tipos de acción por comas (AUDIT_LOG_ALLOW_ACTIONS / AUDIT_LOG_DENY_ACTIONS)
+func ParseActionTypeList(value string) []actionlog.ActionType {
+	var types []actionlog.ActionType
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.ToUpper(strings.TrimSpace(item)); item != "" {
+			types = append(types, actionlog.ActionType(item))
+		}
+	}
+	return types
+}
+
+// ActionTypeFilter retorna el filtro vigente
+func (als *ActionLogService) ActionTypeFilter() ActionTypeFilter {
+	als.filterMutex.RLock()
+	defer als.filterMutex.RUnlock()
+	return als.filter
+}
+
+// SetActionTypeFilter reemplaza el filtro de tipos de acción; aplica a las acciones registradas desde ese momento
+func (als *ActionLogService) SetActionTypeFilter(filter ActionTypeFilter) {
+	als.filterMutex.Lock()
+	defer als.filterMutex.Unlock()
+	als.filter = filter
+}
@@ -0,0 +1,67 @@
+package actionlogservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+)
+
+// savingActionLogRepository guarda en memoria los logs persistidos; el resto del repositorio no se usa
+type savingActionLogRepository struct {
+	interfaces.IActionLogRepository
+	saved []actionlog.ActionType
+}
+
+func (r *savingActionLogRepository) Save(ctx context.Context, log *actionlog.ActionLog) error {
+	r.saved = append(r.saved, log.ActionType())
+	return nil
+}
+
+func TestActionLogService_LogAction_DeniedTypeIsNotPersisted(t *testing.T) {
+	// Arrange
+	repo := &savingActionLogRepository{}
+	service := NewActionLogService(repo).(*ActionLogService)
+	service.SetActionTypeFilter(ActionTypeFilter{Deny: []actionlog.ActionType{actionlog.ActionPCStatusChanged}})
+
+	// Act
+	require.NoError(t, service.LogAction(context.Background(), actionlog.ActionPCStatusChanged, "PC online", "user-1", nil, nil, nil))
+	require.NoError(t, service.LogAction(context.Background(), actionlog.ActionPCRegistered, "PC registrado", "user-1", nil, nil, nil))
+
+	// Assert
+	assert.Equal(t, []actionlog.ActionType{actionlog.ActionPCRegistered}, repo.saved)
+}
+
+func TestActionLogService_LogAction_MandatoryTypesCannotBeSuppressed(t *testing.T) {
+	// Arrange - ni la lista de denegados ni una lista de permitidos excluyen los tipos obligatorios
+	repo := &savingActionLogRepository{}
+	service := NewActionLogService(repo).(*ActionLogService)
+	service.SetActionTypeFilter(ActionTypeFilter{
+		Allow: []actionlog.ActionType{actionlog.ActionFileTransferFailed},
+		Deny:  []actionlog.ActionType{actionlog.ActionUserLogin, actionlog.ActionRemoteSessionStarted},
+	})
+
+	// Act
+	require.NoError(t, service.LogAction(context.Background(), actionlog.ActionUserLogin, "login", "admin-1", nil, nil, nil))
+	require.NoError(t, service.LogSessionEnded(context.Background(), "session-1", "admin-1", "done"))
+	require.NoError(t, service.LogAction(context.Background(), actionlog.ActionRemoteSessionStarted, "inicio", "admin-1", nil, nil, nil))
+	require.NoError(t, service.LogAction(context.Background(), actionlog.ActionVideoUploaded, "video", "admin-1", nil, nil, nil))
+
+	// Assert
+	assert.Equal(t, []actionlog.ActionType{
+		actionlog.ActionUserLogin,
+		actionlog.ActionRemoteSessionEnded,
+		actionlog.ActionRemoteSessionStarted,
+	}, repo.saved)
+}
+
+func TestParseActionTypeList_NormalizesAndSkipsEmptyItems(t *testing.T) {
+	// Act
+	types := ParseActionTypeList(" pc_status_changed, ,FILE_TRANSFER_VIEWED,")
+
+	// Assert
+	assert.Equal(t, []actionlog.ActionType{actionlog.ActionPCStatusChanged, actionlog.ActionFileTransferViewed}, types)
+}
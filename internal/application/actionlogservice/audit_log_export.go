@@ -0,0 +1,22 @@
+package actionlogservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+)
+
+// IAuditLogExportService recorre el audit log filtrado para exportarlo sin cargarlo entero en memoria
+type IAuditLogExportService interface {
+	ExportLogs(ctx context.Context, filter interfaces.ActionLogFilter, fn func(*actionlog.ActionLog) error) error
+}
+
+// ExportLogs llama a fn con cada log que cumple el filtro, del más antiguo al más reciente
+func (als *ActionLogService) ExportLogs(ctx context.Context, filter interfaces.ActionLogFilter, fn func(*actionlog.ActionLog) error) error {
+	if err := als.actionLogRepo.StreamByFilter(ctx, filter, fn); err != nil {
+		return fmt.Errorf("error exporting logs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package backgroundtaskservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrJobAlreadyRegistered ya hay un trabajo periódico con ese nombre
+	ErrJobAlreadyRegistered = errors.New("background job already registered")
+	// ErrInvalidJobInterval el intervalo de un trabajo periódico debe ser mayor que cero
+	ErrInvalidJobInterval = errors.New("background job interval must be positive")
+)
+
+// DefaultMaxConcurrentJobs trabajos periódicos que se ejecutan a la vez como máximo
+const DefaultMaxConcurrentJobs = 2
+
+// JobFunc ejecución de un trabajo periódico; el contexto se cancela al detener el planificador
+type JobFunc func(ctx context.Context) error
+
+// JobInfo instantánea de un trabajo periódico. LastRunAt es cero si aún no se ejecutó.
+type JobInfo struct {
+	Name         string
+	Interval     time.Duration
+	Running      bool
+	Runs         int
+	LastRunAt    time.Time
+	LastDuration time.Duration
+	LastError    string
+	NextRunAt    time.Time
+}
+
+type scheduledJob struct {
+	info JobInfo
+	run  JobFunc
+}
+
+// JobScheduler ejecuta los trabajos periódicos del servidor (caducidad de colas, barrido de clientes REST,
+// reconciliación...) cada uno con su intervalo, sin que haya más de maxConcurrent en marcha a la vez: un trabajo
+// que vence con el cupo lleno espera su turno. Un mismo trabajo nunca se solapa consigo mismo.
+type JobScheduler struct {
+	jobs  map[string]*scheduledJob
+	slots chan struct{}
+	// ctx contexto de Start; los trabajos registrados después arrancan con él
+	ctx   context.Context
+	mutex sync.RWMutex
+}
+
+// NewJobScheduler crea un planificador; maxConcurrent <= 0 usa DefaultMaxConcurrentJobs
+func NewJobScheduler(maxConcurrent int) *JobScheduler {
+	if maxConcurrent <= 0 {
+		maxConcurrent = DefaultMaxConcurrentJobs
+	}
+	return &JobScheduler{
+		jobs:  make(map[string]*scheduledJob),
+		slots: make(chan struct{}, maxConcurrent),
+	}
+}
+
+// Register añade un trabajo que se ejecuta cada interval, la primera vez un intervalo después de arrancar
+func (s *JobScheduler) Register(name string, interval time.Duration, run JobFunc) error {
+	if interval <= 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidJobInterval, name)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("%w: %s", ErrJobAlreadyRegistered, name)
+	}
+	job := &scheduledJob{info: JobInfo{Name: name, Interval: interval}, run: run}
+	s.jobs[name] = job
+
+	if s.ctx != nil {
+		s.startJob(s.ctx, job)
+	}
+	return nil
+}
+
+// Start arranca todos los trabajos registrados hasta que se cancele ctx
+func (s *JobScheduler) Start(ctx context.Context) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.ctx != nil {
+		return
+	}
+	s.ctx = ctx
+	for _, job := range s.jobs {
+		s.startJob(ctx, job)
+	}
+	log.Printf("⏰ JOBS: Started %d background jobs (max %d at a time)", len(s.jobs), cap(s.slots))
+}
+
+// startJob lanza el bucle del trabajo; llamar con el mutex tomado
+func (s *JobScheduler) startJob(ctx context.Context, job *scheduledJob) {
+	ticker := time.NewTicker(job.info.Interval)
+	job.info.NextRunAt = time.Now().Add(job.info.Interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case tick := <-ticker.C:
+				s.runJob(ctx, job, tick)
+			}
+		}
+	}()
+}
+
+// runJob espera un hueco libre, ejecuta el trabajo y guarda el resultado
+func (s *JobScheduler) runJob(ctx context.Context, job *scheduledJob, tick time.Time) {
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return
+	}
+	defer func() { <-s.slots }()
+
+	s.mutex.Lock()
+	job.info.Running = true
+	s.mutex.Unlock()
+
+	startedAt := time.Now()
+	err := runJobRecovered(ctx, job.info.Name, job.run)
+	finishedAt := time.Now()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job.info.Running = false
+	job.info.Runs++
+	job.info.LastRunAt = startedAt
+	job.info.LastDuration = finishedAt.Sub(startedAt)
+	job.info.LastError = ""
+	if err != nil {
+		job.info.LastError = err.Error()
+	}
+	// Si la ejecución se alargó más de un intervalo, el ticker dispara en cuanto puede
+	job.info.NextRunAt = tick.Add(job.info.Interval)
+	if job.info.NextRunAt.Before(finishedAt) {
+		job.info.NextRunAt = finishedAt
+	}
+}
+
+// runJobRecovered ejecuta el trabajo convirtiendo un panic en error para que el bucle siga
+func runJobRecovered(ctx context.Context, name string, run JobFunc) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("💥 JOBS: Job %s panicked: %v\n%s", name, recovered, debug.Stack())
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	if err := run(ctx); err != nil {
+		log.Printf("❌ JOBS: Job %s failed: %v", name, err)
+		return err
+	}
+	return nil
+}
+
+// Jobs trabajos registrados ordenados por nombre. Un planificador nil no tiene trabajos.
+func (s *JobScheduler) Jobs() []JobInfo {
+	if s == nil {
+		return []JobInfo{}
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	jobs := make([]JobInfo, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job.info)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Name < jobs[j].Name
+	})
+	return jobs
+}
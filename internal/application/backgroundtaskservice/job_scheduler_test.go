@@ -0,0 +1,127 @@
+package backgroundtaskservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobScheduler_RunsJobsOnTheirInterval(t *testing.T) {
+	// Arrange
+	scheduler := NewJobScheduler(2)
+	var fastRuns, slowRuns atomic.Int32
+	require.NoError(t, scheduler.Register("fast", 10*time.Millisecond, func(ctx context.Context) error {
+		fastRuns.Add(1)
+		return nil
+	}))
+	require.NoError(t, scheduler.Register("slow", time.Hour, func(ctx context.Context) error {
+		slowRuns.Add(1)
+		return nil
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Act
+	scheduler.Start(ctx)
+
+	// Assert - el rápido se repite y el lento aún no ha vencido
+	assert.Eventually(t, func() bool { return fastRuns.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	assert.Zero(t, slowRuns.Load())
+
+	jobs := scheduler.Jobs()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "fast", jobs[0].Name)
+	assert.GreaterOrEqual(t, jobs[0].Runs, 3)
+	assert.False(t, jobs[0].LastRunAt.IsZero())
+	assert.Equal(t, "slow", jobs[1].Name)
+	assert.Zero(t, jobs[1].Runs)
+	assert.True(t, jobs[1].LastRunAt.IsZero())
+	assert.WithinDuration(t, time.Now().Add(time.Hour), jobs[1].NextRunAt, time.Minute)
+}
+
+func TestJobScheduler_NeverExceedsConcurrencyBound(t *testing.T) {
+	// Arrange - cinco trabajos que vencen a la vez y tardan más que su intervalo, con cupo de dos
+	scheduler := NewJobScheduler(2)
+	var running, maxRunning, runs atomic.Int32
+	for i := 0; i < 5; i++ {
+		require.NoError(t, scheduler.Register(fmt.Sprintf("job-%d", i), 5*time.Millisecond, func(ctx context.Context) error {
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				observed := maxRunning.Load()
+				if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+			runs.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		}))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Act
+	scheduler.Start(ctx)
+
+	// Assert
+	assert.Eventually(t, func() bool { return runs.Load() >= 10 }, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(2), maxRunning.Load())
+}
+
+func TestJobScheduler_RecordsErrorsAndPanicsAndKeepsRunning(t *testing.T) {
+	// Arrange
+	scheduler := NewJobScheduler(1)
+	var runs atomic.Int32
+	require.NoError(t, scheduler.Register("flaky", 5*time.Millisecond, func(ctx context.Context) error {
+		switch runs.Add(1) {
+		case 1:
+			return errors.New("database unavailable")
+		case 2:
+			panic("unexpected state")
+		}
+		return nil
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Act
+	scheduler.Start(ctx)
+
+	// Assert - tras el error y el panic el trabajo sigue ejecutándose y el último resultado queda limpio
+	assert.Eventually(t, func() bool {
+		jobs := scheduler.Jobs()
+		return jobs[0].Runs >= 3 && !jobs[0].Running
+	}, time.Second, 5*time.Millisecond)
+	assert.Empty(t, scheduler.Jobs()[0].LastError)
+}
+
+func TestJobScheduler_RegisterRejectsDuplicatesAndInvalidIntervals(t *testing.T) {
+	// Arrange
+	scheduler := NewJobScheduler(0)
+	noop := func(ctx context.Context) error { return nil }
+	require.NoError(t, scheduler.Register("sweeper", time.Minute, noop))
+
+	// Act
+	duplicateErr := scheduler.Register("sweeper", time.Minute, noop)
+	intervalErr := scheduler.Register("disabled", 0, noop)
+
+	// Assert
+	assert.ErrorIs(t, duplicateErr, ErrJobAlreadyRegistered)
+	assert.ErrorIs(t, intervalErr, ErrInvalidJobInterval)
+	assert.Len(t, scheduler.Jobs(), 1)
+}
+
+func TestJobScheduler_NilSchedulerHasNoJobs(t *testing.T) {
+	// Arrange
+	var scheduler *JobScheduler
+
+	// Act & Assert
+	assert.Empty(t, scheduler.Jobs())
+}
@@ -0,0 +1,199 @@
+package backgroundtaskservice
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrTaskNotFound se retorna al cancelar una tarea que no existe o que ya terminó
+var ErrTaskNotFound = errors.New("background task not found")
+
+// TaskStatus estado de una tarea en segundo plano
+type TaskStatus string
+
+const (
+	TaskStatusQueued     TaskStatus = "QUEUED"
+	TaskStatusRunning    TaskStatus = "RUNNING"
+	TaskStatusCancelling TaskStatus = "CANCELLING"
+)
+
+// Tipos de tarea registrados por el servidor
+const (
+	TaskKindFileTransfer    = "file_transfer"
+	TaskKindPendingTransfer = "pending_transfer"
+)
+
+// TaskInfo instantánea de una tarea en curso
+type TaskInfo struct {
+	ID         string
+	Kind       string
+	TransferID string
+	StartedAt  time.Time
+	Status     TaskStatus
+}
+
+// Task tarea registrada; quien la lanza la marca como iniciada y terminada y consulta su contexto para
+// detenerse si un administrador la cancela. Una tarea nil (registro nil) no hace nada.
+type Task struct {
+	info     TaskInfo
+	ctx      context.Context
+	cancel   context.CancelFunc
+	registry *TaskRegistry
+}
+
+// ID identificador de la tarea en el registro
+func (t *Task) ID() string {
+	if t == nil {
+		return ""
+	}
+	return t.info.ID
+}
+
+// Context se cancela cuando un administrador cancela la tarea
+func (t *Task) Context() context.Context {
+	if t == nil {
+		return context.Background()
+	}
+	return t.ctx
+}
+
+// MarkRunning indica que la tarea dejó la cola y se está ejecutando
+func (t *Task) MarkRunning() {
+	if t == nil {
+		return
+	}
+	t.registry.mutex.Lock()
+	defer t.registry.mutex.Unlock()
+
+	if t.info.Status == TaskStatusQueued {
+		t.info.Status = TaskStatusRunning
+	}
+}
+
+// Finish saca la tarea del registro
+func (t *Task) Finish() {
+	if t == nil {
+		return
+	}
+	t.registry.mutex.Lock()
+	delete(t.registry.tasks, t.info.ID)
+	t.registry.mutex.Unlock()
+	t.cancel()
+}
+
+// TaskRegistry lleva la cuenta de las goroutines de transferencias lanzadas por el servidor para poder
+// listarlas y cancelarlas. Un registro nil es válido: las tareas se ejecutan sin seguimiento.
+type TaskRegistry struct {
+	tasks map[string]*Task
+	mutex sync.RWMutex
+}
+
+// NewTaskRegistry crea un registro de tareas vacío
+func NewTaskRegistry() *TaskRegistry {
+	return &TaskRegistry{tasks: make(map[string]*Task)}
+}
+
+// Register añade una tarea en cola asociada a una transferencia
+func (r *TaskRegistry) Register(kind, transferID string) *Task {
+	if r == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	task := &Task{
+		info: TaskInfo{
+			ID:         uuid.New().String(),
+			Kind:       kind,
+			TransferID: transferID,
+			StartedAt:  time.Now(),
+			Status:     TaskStatusQueued,
+		},
+		ctx:      ctx,
+		cancel:   cancel,
+		registry: r,
+	}
+
+	r.mutex.Lock()
+	r.tasks[task.info.ID] = task
+	r.mutex.Unlock()
+	return task
+}
+
+// Go ejecuta fn en una goroutine registrada que sale del registro al terminar. Un panic de fn se registra
+// como fallo de la tarea en lugar de tumbar el proceso.
+func (r *TaskRegistry) Go(kind, transferID string, fn func(ctx context.Context) error) {
+	task := r.Register(kind, transferID)
+	task.MarkRunning()
+	go func() {
+		defer task.Finish()
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log.Printf("💥 BACKGROUND TASK: %s for transfer %s panicked: %v\n%s", kind, transferID, recovered, debug.Stack())
+			}
+		}()
+		if err := fn(task.Context()); err != nil {
+			log.Printf("❌ BACKGROUND TASK: %s for transfer %s failed: %v", kind, transferID, err)
+		}
+	}()
+}
+
+// List tareas en curso, de la más antigua a la más reciente
+func (r *TaskRegistry) List() []TaskInfo {
+	if r == nil {
+		return []TaskInfo{}
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	tasks := make([]TaskInfo, 0, len(r.tasks))
+	for _, task := range r.tasks {
+		tasks = append(tasks, task.info)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].StartedAt.Before(tasks[j].StartedAt)
+	})
+	return tasks
+}
+
+// Cancel cancela el contexto de la tarea; la tarea sigue listada como CANCELLING hasta que se detiene
+func (r *TaskRegistry) Cancel(taskID string) (TaskInfo, error) {
+	if r == nil {
+		return TaskInfo{}, ErrTaskNotFound
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	task, exists := r.tasks[taskID]
+	if !exists {
+		return TaskInfo{}, ErrTaskNotFound
+	}
+	task.info.Status = TaskStatusCancelling
+	task.cancel()
+	return task.info, nil
+}
+
+// TransferCancelled indica si alguna tarea de la transferencia fue cancelada
+func (r *TaskRegistry) TransferCancelled(transferID string) bool {
+	if r == nil {
+		return false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, task := range r.tasks {
+		if task.info.TransferID == transferID && task.ctx.Err() != nil {
+			return true
+		}
+	}
+	return false
+}
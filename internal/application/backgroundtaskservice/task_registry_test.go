@@ -0,0 +1,79 @@
+package backgroundtaskservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTransferID = "transfer-task-test"
+
+func TestTaskRegistry_Go_ListsTaskUntilItCompletes(t *testing.T) {
+	// Arrange
+	registry := NewTaskRegistry()
+	release := make(chan struct{})
+	done := make(chan struct{})
+
+	// Act
+	registry.Go(TaskKindFileTransfer, testTransferID, func(ctx context.Context) error {
+		defer close(done)
+		<-release
+		return nil
+	})
+
+	// Assert - la transferencia aparece mientras se ejecuta
+	tasks := registry.List()
+	require.Len(t, tasks, 1)
+	assert.Equal(t, TaskKindFileTransfer, tasks[0].Kind)
+	assert.Equal(t, testTransferID, tasks[0].TransferID)
+	assert.Equal(t, TaskStatusRunning, tasks[0].Status)
+	assert.NotEmpty(t, tasks[0].ID)
+
+	// ...y sale del registro al terminar
+	close(release)
+	<-done
+	assert.Eventually(t, func() bool { return len(registry.List()) == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestTaskRegistry_Cancel_CancelsTaskContext(t *testing.T) {
+	// Arrange
+	registry := NewTaskRegistry()
+	stopped := make(chan error, 1)
+	registry.Go(TaskKindFileTransfer, testTransferID, func(ctx context.Context) error {
+		<-ctx.Done()
+		stopped <- ctx.Err()
+		return ctx.Err()
+	})
+	taskID := registry.List()[0].ID
+
+	// Act
+	info, err := registry.Cancel(taskID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, TaskStatusCancelling, info.Status)
+	assert.ErrorIs(t, <-stopped, context.Canceled)
+	assert.Eventually(t, func() bool { return len(registry.List()) == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestTaskRegistry_TransferCancelled_OnlyForCancelledTransfer(t *testing.T) {
+	// Arrange
+	registry := NewTaskRegistry()
+	cancelled := registry.Register(TaskKindPendingTransfer, testTransferID)
+	other := registry.Register(TaskKindPendingTransfer, "transfer-other")
+	defer other.Finish()
+
+	// Act
+	_, err := registry.Cancel(cancelled.ID())
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, registry.TransferCancelled(testTransferID))
+	assert.False(t, registry.TransferCancelled("transfer-other"))
+
+	_, err = registry.Cancel("missing")
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+}
@@ -0,0 +1,125 @@
+package featureflagservice
+
+import (
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Flag identifica un comportamiento experimental que puede activarse o desactivarse sin redesplegar
+type Flag string
+
+const (
+	// FlagServerSideRecording guarda en el servidor los frames y videos de grabación enviados por los clientes
+	FlagServerSideRecording Flag = "server_side_recording"
+	// FlagAdaptiveFrameRate reduce los frames reenviados al administrador cuando sus confirmaciones se retrasan
+	FlagAdaptiveFrameRate Flag = "adaptive_frame_rate"
+)
+
+// EnvPrefix prefijo de las variables de entorno de los flags (FEATURE_SERVER_SIDE_RECORDING=false)
+const EnvPrefix = "FEATURE_"
+
+// Orígenes posibles del valor de un flag
+const (
+	SourceDefault = "default"
+	SourceEnv     = "env"
+)
+
+// flagDefinition describe un flag conocido y su valor por defecto
+type flagDefinition struct {
+	flag         Flag
+	description  string
+	defaultValue bool
+}
+
+// definitions flags conocidos; el valor por defecto conserva el comportamiento actual
+var definitions = []flagDefinition{
+	{flag: FlagServerSideRecording, description: "Store recording frames uploaded by clients on the server", defaultValue: true},
+	{flag: FlagAdaptiveFrameRate, description: "Throttle frames forwarded to admins based on frame_ack latency", defaultValue: true},
+}
+
+// FlagState estado resuelto de un flag, expuesto para visibilidad operativa
+type FlagState struct {
+	Name        Flag   `json:"name"`
+	Enabled     bool   `json:"enabled"`
+	Default     bool   `json:"default"`
+	Source      string `json:"source"`
+	EnvVar      string `json:"env_var"`
+	Description string `json:"description"`
+}
+
+// IFeatureFlagService define la interfaz de consulta de feature flags
+type IFeatureFlagService interface {
+	IsEnabled(flag Flag) bool
+	ServerSideRecording() bool
+	AdaptiveFrameRate() bool
+	All() []FlagState
+}
+
+// featureFlagService resuelve los flags desde variables de entorno una sola vez y responde desde memoria
+type featureFlagService struct {
+	states []FlagState
+	byName map[Flag]FlagState
+}
+
+// NewFeatureFlagService crea el servicio leyendo cada flag con lookupEnv (normalmente os.LookupEnv).
+// Los valores no booleanos se ignoran con un aviso y se usa el valor por defecto.
+func NewFeatureFlagService(lookupEnv func(key string) (string, bool)) IFeatureFlagService {
+	service := &featureFlagService{
+		states: make([]FlagState, 0, len(definitions)),
+		byName: make(map[Flag]FlagState, len(definitions)),
+	}
+
+	for _, definition := range definitions {
+		state := FlagState{
+			Name:        definition.flag,
+			Enabled:     definition.defaultValue,
+			Default:     definition.defaultValue,
+			Source:      SourceDefault,
+			EnvVar:      EnvVarName(definition.flag),
+			Description: definition.description,
+		}
+
+		if raw, ok := lookupEnv(state.EnvVar); ok && strings.TrimSpace(raw) != "" {
+			enabled, err := strconv.ParseBool(strings.TrimSpace(raw))
+			if err != nil {
+				log.Printf("⚠️ FEATURE FLAGS: Invalid value %q for %s, using default %t", raw, state.EnvVar, state.Default)
+			} else {
+				state.Enabled = enabled
+				state.Source = SourceEnv
+			}
+		}
+
+		service.states = append(service.states, state)
+		service.byName[state.Name] = state
+	}
+
+	return service
+}
+
+// EnvVarName retorna la variable de entorno asociada al flag
+func EnvVarName(flag Flag) string {
+	return EnvPrefix + strings.ToUpper(string(flag))
+}
+
+// IsEnabled indica si el flag está activo; los flags desconocidos se consideran desactivados
+func (s *featureFlagService) IsEnabled(flag Flag) bool {
+	return s.byName[flag].Enabled
+}
+
+// ServerSideRecording indica si el servidor debe guardar las grabaciones de los clientes
+func (s *featureFlagService) ServerSideRecording() bool {
+	return s.IsEnabled(FlagServerSideRecording)
+}
+
+// AdaptiveFrameRate indica si se adapta la tasa de frames reenviados al administrador
+func (s *featureFlagService) AdaptiveFrameRate() bool {
+	return s.IsEnabled(FlagAdaptiveFrameRate)
+}
+
+// All retorna el estado de todos los flags conocidos en orden de definición
+func (s *featureFlagService) All() []FlagState {
+	states := make([]FlagState, len(s.states))
+	copy(states, s.states)
+	return states
+}
@@ -0,0 +1,66 @@
+package featureflagservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// envLookup simula os.LookupEnv con un mapa fijo
+func envLookup(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func TestFeatureFlagService_DefaultsKeepCurrentBehavior(t *testing.T) {
+	// Arrange & Act
+	flags := NewFeatureFlagService(envLookup(nil))
+
+	// Assert
+	assert.True(t, flags.ServerSideRecording())
+	assert.True(t, flags.AdaptiveFrameRate())
+	for _, state := range flags.All() {
+		assert.Equal(t, SourceDefault, state.Source)
+	}
+}
+
+func TestFeatureFlagService_EnvOverridesDefault(t *testing.T) {
+	// Arrange & Act
+	flags := NewFeatureFlagService(envLookup(map[string]string{
+		"FEATURE_SERVER_SIDE_RECORDING": "false",
+		"FEATURE_ADAPTIVE_FRAME_RATE":   " 0 ",
+	}))
+
+	// Assert
+	assert.False(t, flags.ServerSideRecording())
+	assert.False(t, flags.AdaptiveFrameRate())
+
+	states := flags.All()
+	require.Len(t, states, 2)
+	assert.Equal(t, FlagServerSideRecording, states[0].Name)
+	assert.Equal(t, SourceEnv, states[0].Source)
+	assert.True(t, states[0].Default)
+	assert.Equal(t, "FEATURE_SERVER_SIDE_RECORDING", states[0].EnvVar)
+}
+
+func TestFeatureFlagService_InvalidValueFallsBackToDefault(t *testing.T) {
+	// Arrange & Act
+	flags := NewFeatureFlagService(envLookup(map[string]string{
+		"FEATURE_SERVER_SIDE_RECORDING": "maybe",
+	}))
+
+	// Assert
+	assert.True(t, flags.ServerSideRecording())
+	assert.Equal(t, SourceDefault, flags.All()[0].Source)
+}
+
+func TestFeatureFlagService_UnknownFlagIsDisabled(t *testing.T) {
+	// Arrange
+	flags := NewFeatureFlagService(envLookup(nil))
+
+	// Act & Assert
+	assert.False(t, flags.IsEnabled(Flag("frame_compression")))
+}
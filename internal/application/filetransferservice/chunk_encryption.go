@@ -0,0 +1,118 @@
+package filetransferservice
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ChunkEncryptionAlgorithm identifica el esquema negociado con el cliente:
+// acuerdo de clave X25519, derivación HKDF-SHA256 por transferencia y cifrado AES-256-GCM por chunk
+const ChunkEncryptionAlgorithm = "X25519-HKDF-SHA256-AES-256-GCM"
+
+// ErrChunkDecryptionFailed indica que un chunk no pudo descifrarse (clave incorrecta, datos alterados o chunk reordenado)
+var ErrChunkDecryptionFailed = errors.New("chunk decryption failed")
+
+// TransferKeyExchange par de claves efímero usado para negociar la clave de una transferencia
+type TransferKeyExchange struct {
+	privateKey *ecdh.PrivateKey
+}
+
+// NewTransferKeyExchange genera un nuevo par de claves X25519 para una transferencia
+func NewTransferKeyExchange() (*TransferKeyExchange, error) {
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error generando clave de transferencia: %w", err)
+	}
+	return &TransferKeyExchange{privateKey: privateKey}, nil
+}
+
+// PublicKey devuelve la clave pública codificada en base64 para enviarla al otro extremo
+func (kx *TransferKeyExchange) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(kx.privateKey.PublicKey().Bytes())
+}
+
+// DeriveCipher combina la clave pública del otro extremo con la propia y deriva el cifrador de la transferencia.
+// Ambos extremos obtienen la misma clave simétrica; el transferID la liga a esta transferencia concreta.
+func (kx *TransferKeyExchange) DeriveCipher(peerPublicKey, transferID string) (*ChunkCipher, error) {
+	peerKeyBytes, err := base64.StdEncoding.DecodeString(peerPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("clave pública del cliente inválida: %w", err)
+	}
+
+	peerKey, err := ecdh.X25519().NewPublicKey(peerKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("clave pública del cliente inválida: %w", err)
+	}
+
+	sharedSecret, err := kx.privateKey.ECDH(peerKey)
+	if err != nil {
+		return nil, fmt.Errorf("error en acuerdo de clave: %w", err)
+	}
+
+	key, err := hkdf.Key(sha256.New, sharedSecret, []byte(transferID), ChunkEncryptionAlgorithm, 32)
+	if err != nil {
+		return nil, fmt.Errorf("error derivando clave de transferencia: %w", err)
+	}
+
+	return newChunkCipher(key, transferID)
+}
+
+// ChunkCipher cifra y descifra los chunks de una transferencia con AES-256-GCM
+type ChunkCipher struct {
+	aead       cipher.AEAD
+	transferID string
+}
+
+// newChunkCipher crea el cifrador a partir de una clave simétrica de 32 bytes
+func newChunkCipher(key []byte, transferID string) (*ChunkCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creando cifrador: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creando cifrador: %w", err)
+	}
+
+	return &ChunkCipher{aead: aead, transferID: transferID}, nil
+}
+
+// EncryptChunk cifra un chunk y devuelve nonce || ciphertext.
+// El índice del chunk se autentica para que no pueda reordenarse ni moverse a otra transferencia.
+func (c *ChunkCipher) EncryptChunk(chunkIndex int, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("error generando nonce: %w", err)
+	}
+
+	return c.aead.Seal(nonce, nonce, plaintext, c.additionalData(chunkIndex)), nil
+}
+
+// DecryptChunk descifra un chunk producido por EncryptChunk
+func (c *ChunkCipher) DecryptChunk(chunkIndex int, data []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, ErrChunkDecryptionFailed
+	}
+
+	plaintext, err := c.aead.Open(nil, data[:nonceSize], data[nonceSize:], c.additionalData(chunkIndex))
+	if err != nil {
+		return nil, ErrChunkDecryptionFailed
+	}
+
+	return plaintext, nil
+}
+
+// additionalData datos autenticados asociados a cada chunk
+func (c *ChunkCipher) additionalData(chunkIndex int) []byte {
+	return []byte(c.transferID + ":" + strconv.Itoa(chunkIndex))
+}
@@ -0,0 +1,83 @@
+package filetransferservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTransferID = "transfer-123"
+
+// negotiateTestCiphers simula el handshake servidor/cliente y devuelve el cifrador de cada extremo
+func negotiateTestCiphers(t *testing.T) (*ChunkCipher, *ChunkCipher) {
+	server, err := NewTransferKeyExchange()
+	require.NoError(t, err)
+	client, err := NewTransferKeyExchange()
+	require.NoError(t, err)
+
+	serverCipher, err := server.DeriveCipher(client.PublicKey(), testTransferID)
+	require.NoError(t, err)
+	clientCipher, err := client.DeriveCipher(server.PublicKey(), testTransferID)
+	require.NoError(t, err)
+
+	return serverCipher, clientCipher
+}
+
+func TestChunkCipher_EncryptedChunkRoundTrips(t *testing.T) {
+	// Arrange
+	serverCipher, clientCipher := negotiateTestCiphers(t)
+	plaintext := []byte("contenido confidencial del archivo")
+
+	// Act
+	encrypted, err := serverCipher.EncryptChunk(0, plaintext)
+	require.NoError(t, err)
+	decrypted, err := clientCipher.DecryptChunk(0, encrypted)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+	assert.NotContains(t, string(encrypted), string(plaintext))
+}
+
+func TestChunkCipher_WrongKeyFailsDecryption(t *testing.T) {
+	// Arrange
+	serverCipher, _ := negotiateTestCiphers(t)
+	_, otherClientCipher := negotiateTestCiphers(t)
+
+	encrypted, err := serverCipher.EncryptChunk(0, []byte("contenido confidencial"))
+	require.NoError(t, err)
+
+	// Act
+	decrypted, err := otherClientCipher.DecryptChunk(0, encrypted)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrChunkDecryptionFailed)
+	assert.Nil(t, decrypted)
+}
+
+func TestChunkCipher_ReorderedChunkFailsDecryption(t *testing.T) {
+	// Arrange
+	serverCipher, clientCipher := negotiateTestCiphers(t)
+
+	encrypted, err := serverCipher.EncryptChunk(3, []byte("chunk tres"))
+	require.NoError(t, err)
+
+	// Act
+	_, err = clientCipher.DecryptChunk(4, encrypted)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrChunkDecryptionFailed)
+}
+
+func TestTransferKeyExchange_InvalidPeerKeyIsRejected(t *testing.T) {
+	// Arrange
+	server, err := NewTransferKeyExchange()
+	require.NoError(t, err)
+
+	// Act
+	_, err = server.DeriveCipher("no-es-base64!", testTransferID)
+
+	// Assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package filetransferservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+)
+
+// DestinationBusyPolicy qué hacer con una transferencia cuyo destino en el cliente ya está recibiendo otra
+type DestinationBusyPolicy string
+
+const (
+	// DestinationBusyQueue la transferencia queda PENDING y se envía cuando termine la anterior
+	DestinationBusyQueue DestinationBusyPolicy = "queue"
+	// DestinationBusyReject la transferencia se rechaza con ErrDestinationBusy
+	DestinationBusyReject DestinationBusyPolicy = "reject"
+)
+
+// DefaultDestinationBusyPolicy política cuando no se configura otra
+const DefaultDestinationBusyPolicy = DestinationBusyQueue
+
+var (
+	// ErrDestinationBusy otra transferencia al mismo archivo del mismo PC sigue pendiente o en curso
+	ErrDestinationBusy = errors.New("another transfer to the same client destination is in progress")
+	// ErrInvalidDestinationBusyPolicy el valor de FILE_TRANSFER_DESTINATION_BUSY no es queue ni reject
+	ErrInvalidDestinationBusyPolicy = errors.New("invalid destination busy policy")
+)
+
+// ParseDestinationBusyPolicy interpreta la política (queue | reject); vacía usa DefaultDestinationBusyPolicy
+func ParseDestinationBusyPolicy(value string) (DestinationBusyPolicy, error) {
+	switch policy := DestinationBusyPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return DefaultDestinationBusyPolicy, nil
+	case DestinationBusyQueue, DestinationBusyReject:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidDestinationBusyPolicy, value)
+	}
+}
+
+// SetDestinationBusyPolicy configura si una transferencia a un destino ocupado se encola o se rechaza
+func (s *FileTransferService) SetDestinationBusyPolicy(policy DestinationBusyPolicy) {
+	s.destinationBusyPolicy = policy
+}
+
+// DestinationBusyPolicy retorna la política vigente
+func (s *FileTransferService) DestinationBusyPolicy() DestinationBusyPolicy {
+	if s.destinationBusyPolicy == "" {
+		return DefaultDestinationBusyPolicy
+	}
+	return s.destinationBusyPolicy
+}
+
+// FindConflictingTransfer retorna la transferencia anterior, pendiente o en curso, al mismo destino del mismo PC,
+// o nil si transfer puede enviarse ya. Con la política queue así se envían en orden de creación.
+func (s *FileTransferService) FindConflictingTransfer(ctx context.Context, transfer *filetransfer.FileTransfer) (*filetransfer.FileTransfer, error) {
+	transfers, err := s.fileTransferRepository.FindByTargetPCID(ctx, transfer.TargetPCID())
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo transferencias del PC: %w", err)
+	}
+	return FindDestinationConflict(transfer, transfers), nil
+}
+
+// FindDestinationConflict busca entre transfers (todas del mismo PC) la más antigua que sigue pendiente o en curso
+// hacia el mismo destino y se creó antes que transfer. Las rutas se comparan sin distinguir mayúsculas, como en
+// los clientes Windows.
+func FindDestinationConflict(transfer *filetransfer.FileTransfer, transfers []*filetransfer.FileTransfer) *filetransfer.FileTransfer {
+	var conflict *filetransfer.FileTransfer
+	for _, other := range transfers {
+		if other.TransferID() == transfer.TransferID() || other.TargetPCID() != transfer.TargetPCID() {
+			continue
+		}
+		if other.Status().IsFinal() || !strings.EqualFold(other.DestinationPathClient(), transfer.DestinationPathClient()) {
+			continue
+		}
+		if !createdBefore(other, transfer) {
+			continue
+		}
+		if conflict == nil || createdBefore(other, conflict) {
+			conflict = other
+		}
+	}
+	return conflict
+}
+
+// createdBefore ordena por creación y, a igual instante, por ID para que el orden sea estable
+func createdBefore(a, b *filetransfer.FileTransfer) bool {
+	if a.CreatedAt().Equal(b.CreatedAt()) {
+		return a.TransferID() < b.TransferID()
+	}
+	return a.CreatedAt().Before(b.CreatedAt())
+}
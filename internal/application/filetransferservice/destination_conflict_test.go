@@ -0,0 +1,87 @@
+package filetransferservice
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+)
+
+// existingTransfer transferencia ya guardada hacia destinationPath del pc-1, creada hace un minuto
+func existingTransfer(transferID, destinationPath string, status filetransfer.TransferStatus) *filetransfer.FileTransfer {
+	createdAt := time.Now().Add(-time.Minute)
+	return filetransfer.NewFileTransferFromDB(transferID, "report.pdf", "/srv/report.pdf", destinationPath,
+		nil, status, "session-1", "admin-1", "pc-1", 1, "", filetransfer.DefaultConflictPolicy, createdAt, createdAt)
+}
+
+func TestInitiateServerToClientTransfer_RejectsSameDestinationWhileInProgress(t *testing.T) {
+	// Arrange
+	repo := new(MockFileTransferRepository)
+	inProgress := existingTransfer("transfer-1", filepath.Join("Descargas", "RemoteDesk", "REPORT.pdf"), filetransfer.TransferStatusInProgress)
+	repo.On("FindByTargetPCID", mock.Anything, "pc-1").Return([]*filetransfer.FileTransfer{inProgress}, nil)
+	service := NewFileTransferService(repo, nil, nil, nil)
+	service.SetDestinationBusyPolicy(DestinationBusyReject)
+
+	// Act
+	transfer, err := service.InitiateServerToClientTransfer(context.Background(), newTransferRequest(t, ""))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrDestinationBusy)
+	assert.Contains(t, err.Error(), "transfer-1")
+	assert.Nil(t, transfer)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestInitiateServerToClientTransfer_QueuesSameDestinationBehindEarlierTransfer(t *testing.T) {
+	// Arrange
+	repo := new(MockFileTransferRepository)
+	inProgress := existingTransfer("transfer-1", filepath.Join("Descargas", "RemoteDesk", "report.pdf"), filetransfer.TransferStatusInProgress)
+	otherFile := existingTransfer("transfer-2", filepath.Join("Descargas", "RemoteDesk", "otro.pdf"), filetransfer.TransferStatusInProgress)
+	repo.On("Save", mock.Anything, mock.AnythingOfType("*filetransfer.FileTransfer")).Return(nil)
+	service := NewFileTransferService(repo, nil, nil, nil)
+
+	// Act
+	transfer, err := service.InitiateServerToClientTransfer(context.Background(), newTransferRequest(t, ""))
+	require.NoError(t, err)
+	repo.On("FindByTargetPCID", mock.Anything, "pc-1").Return([]*filetransfer.FileTransfer{inProgress, otherFile, transfer}, nil)
+	conflict, conflictErr := service.FindConflictingTransfer(context.Background(), transfer)
+
+	// Assert - la nueva queda PENDING detrás de la que escribe el mismo archivo; la primera no espera a la nueva
+	require.NoError(t, conflictErr)
+	assert.Equal(t, filetransfer.TransferStatusPending, transfer.Status())
+	require.NotNil(t, conflict)
+	assert.Equal(t, "transfer-1", conflict.TransferID())
+	assert.Nil(t, FindDestinationConflict(inProgress, []*filetransfer.FileTransfer{inProgress, transfer}))
+}
+
+func TestFindDestinationConflict_FinishedTransferDoesNotBlock(t *testing.T) {
+	// Arrange
+	destination := filepath.Join("Descargas", "RemoteDesk", "report.pdf")
+	failed := existingTransfer("transfer-1", destination, filetransfer.TransferStatusFailed)
+	queued := filetransfer.NewFileTransfer("report.pdf", "/srv/report.pdf", destination, "session-1", "admin-1", "pc-1", 1)
+
+	// Act
+	conflict := FindDestinationConflict(queued, []*filetransfer.FileTransfer{failed, queued})
+
+	// Assert
+	assert.Nil(t, conflict)
+}
+
+func TestParseDestinationBusyPolicy(t *testing.T) {
+	// Act
+	defaultPolicy, defaultErr := ParseDestinationBusyPolicy("")
+	rejectPolicy, rejectErr := ParseDestinationBusyPolicy(" Reject ")
+	_, invalidErr := ParseDestinationBusyPolicy("overwrite")
+
+	// Assert
+	require.NoError(t, defaultErr)
+	assert.Equal(t, DestinationBusyQueue, defaultPolicy)
+	require.NoError(t, rejectErr)
+	assert.Equal(t, DestinationBusyReject, rejectPolicy)
+	assert.ErrorIs(t, invalidErr, ErrInvalidDestinationBusyPolicy)
+}
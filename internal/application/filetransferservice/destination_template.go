@@ -0,0 +1,114 @@
+package filetransferservice
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Variables que se expanden en una plantilla de destino del cliente
+const (
+	DestinationTemplateSession = "{session}"
+	DestinationTemplateDate    = "{date}"
+	DestinationTemplatePC      = "{pc}"
+	DestinationTemplateFile    = "{file}"
+)
+
+// destinationTemplateDateLayout formato de {date}
+const destinationTemplateDateLayout = "2006-01-02"
+
+// ErrInvalidDestinationTemplate la plantilla usa variables desconocidas o no genera una ruta relativa segura
+var ErrInvalidDestinationTemplate = errors.New("invalid client destination template")
+
+// destinationTemplateVariable cualquier texto entre llaves, para detectar variables desconocidas
+var destinationTemplateVariable = regexp.MustCompile(`\{[^{}]*\}`)
+
+// ParseClientDestinationTemplate valida una plantilla de destino como "Descargas/RemoteDesk/{session}/{date}/{file}".
+// Sin {file} el nombre del archivo se añade como último segmento; {file} solo puede aparecer en ese segmento.
+// Vacía retorna "" (sin plantilla).
+func ParseClientDestinationTemplate(template string) (string, error) {
+	template = strings.TrimRight(strings.TrimSpace(strings.ReplaceAll(template, "\\", "/")), "/")
+	if template == "" {
+		return "", nil
+	}
+
+	for _, variable := range destinationTemplateVariable.FindAllString(template, -1) {
+		switch variable {
+		case DestinationTemplateSession, DestinationTemplateDate, DestinationTemplatePC, DestinationTemplateFile:
+		default:
+			return "", fmt.Errorf("%w: variable desconocida %s en %q", ErrInvalidDestinationTemplate, variable, template)
+		}
+	}
+	if strings.ContainsAny(destinationTemplateVariable.ReplaceAllString(template, ""), "{}") {
+		return "", fmt.Errorf("%w: llaves sin cerrar en %q", ErrInvalidDestinationTemplate, template)
+	}
+	if !strings.Contains(template, DestinationTemplateFile) {
+		template += "/" + DestinationTemplateFile
+	}
+	if strings.Contains(path.Dir(template), DestinationTemplateFile) {
+		return "", fmt.Errorf("%w: %s solo puede usarse en el último segmento de %q", ErrInvalidDestinationTemplate, DestinationTemplateFile, template)
+	}
+
+	// La parte fija de la plantilla debe cumplir las mismas reglas que client_destination_dir
+	sample := destinationTemplateVariable.ReplaceAllString(template, "x")
+	if _, err := sanitizeClientDestinationDir(path.Dir(sample)); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidDestinationTemplate, err)
+	}
+	if _, err := sanitizeClientDestinationDir(sample); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidDestinationTemplate, err)
+	}
+	return template, nil
+}
+
+// SetClientDestinationTemplate configura la plantilla de destino que se usa cuando la solicitud no indica
+// carpeta ni plantilla propia (FILE_TRANSFER_DESTINATION_TEMPLATE). Vacía mantiene DefaultClientDestinationDir.
+func (s *FileTransferService) SetClientDestinationTemplate(template string) error {
+	parsed, err := ParseClientDestinationTemplate(template)
+	if err != nil {
+		return err
+	}
+	s.destinationTemplate = parsed
+	return nil
+}
+
+// expandClientDestinationTemplate sustituye las variables de una plantilla ya validada. Cada valor se reduce a un
+// único segmento seguro, así un "../" o una ruta absoluta en el nombre del archivo no cambian de carpeta.
+func (s *FileTransferService) expandClientDestinationTemplate(template string, req InitiateServerToClientTransferRequest) (string, error) {
+	now := s.now
+	if now == nil {
+		now = time.Now
+	}
+
+	expanded := strings.NewReplacer(
+		DestinationTemplateSession, sanitizeDestinationTemplateValue(req.SessionID),
+		DestinationTemplateDate, now().Format(destinationTemplateDateLayout),
+		DestinationTemplatePC, sanitizeDestinationTemplateValue(req.TargetPCID),
+		DestinationTemplateFile, sanitizeDestinationTemplateValue(req.ClientFileName),
+	).Replace(template)
+
+	dir, err := sanitizeClientDestinationDir(path.Dir(expanded))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.FromSlash(dir), path.Base(expanded)), nil
+}
+
+// sanitizeDestinationTemplateValue reemplaza separadores y caracteres no válidos en Windows por "_" y quita los
+// puntos y espacios de los extremos; un valor que queda vacío (p. ej. "..") se convierte en "_"
+func sanitizeDestinationTemplateValue(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`/\<>:"|?*`, r) {
+			return '_'
+		}
+		return r
+	}, value)
+	value = strings.Trim(value, ". ")
+	if value == "" {
+		return "_"
+	}
+	return value
+}
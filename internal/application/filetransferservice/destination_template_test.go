@@ -0,0 +1,112 @@
+package filetransferservice
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTemplateTransferService servicio con la fecha fija 2026-03-15 para {date}
+func newTemplateTransferService(t *testing.T, template string) (*FileTransferService, *MockFileTransferRepository) {
+	t.Helper()
+
+	repo := new(MockFileTransferRepository)
+	repo.On("Save", mock.Anything, mock.AnythingOfType("*filetransfer.FileTransfer")).Return(nil).Maybe()
+	service := NewFileTransferService(repo, nil, nil, nil)
+	service.now = func() time.Time { return time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC) }
+	require.NoError(t, service.SetClientDestinationTemplate(template))
+	return service, repo
+}
+
+func TestInitiateServerToClientTransfer_ExpandsConfiguredDestinationTemplate(t *testing.T) {
+	templates := map[string]string{
+		"Descargas/RemoteDesk/{session}/{date}/{file}": filepath.Join("Descargas", "RemoteDesk", "session-1", "2026-03-15", "report.pdf"),
+		`Equipos\{pc}\{date}`:                          filepath.Join("Equipos", "pc-1", "2026-03-15", "report.pdf"),
+		"Recibidos/{date}-{file}":                      filepath.Join("Recibidos", "2026-03-15-report.pdf"),
+	}
+
+	for template, expected := range templates {
+		t.Run(template, func(t *testing.T) {
+			// Arrange
+			service, _ := newTemplateTransferService(t, template)
+
+			// Act
+			transfer, err := service.InitiateServerToClientTransfer(context.Background(), newTransferRequest(t, ""))
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, expected, transfer.DestinationPathClient())
+		})
+	}
+}
+
+func TestInitiateServerToClientTransfer_DestinationTemplatePrecedence(t *testing.T) {
+	// Arrange
+	service, _ := newTemplateTransferService(t, "Configurada/{session}")
+
+	// Act - la carpeta explícita gana a la plantilla configurada y la plantilla del envío gana a ambas
+	withDir, err := service.InitiateServerToClientTransfer(context.Background(), newTransferRequest(t, "Documentos"))
+	require.NoError(t, err)
+	perTransfer := newTransferRequest(t, "Documentos")
+	perTransfer.ClientDestinationTemplate = "Envio/{pc}/{file}"
+	withTemplate, err := service.InitiateServerToClientTransfer(context.Background(), perTransfer)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, filepath.Join("Documentos", "report.pdf"), withDir.DestinationPathClient())
+	assert.Equal(t, filepath.Join("Envio", "pc-1", "report.pdf"), withTemplate.DestinationPathClient())
+}
+
+func TestInitiateServerToClientTransfer_NeutralizesTraversalInTemplateVariables(t *testing.T) {
+	// Arrange - los valores de las variables vienen del administrador y del cliente
+	service, _ := newTemplateTransferService(t, "Descargas/RemoteDesk/{session}/{pc}/{file}")
+	req := newTransferRequest(t, "")
+	req.SessionID = "../../.."
+	req.TargetPCID = `C:\Windows`
+	req.ClientFileName = "../../startup/run.bat"
+
+	// Act
+	transfer, err := service.InitiateServerToClientTransfer(context.Background(), req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("Descargas", "RemoteDesk", "_.._", "C__Windows", "_.._startup_run.bat"), transfer.DestinationPathClient())
+}
+
+func TestSetClientDestinationTemplate_RejectsUnsafeTemplates(t *testing.T) {
+	unsafeTemplates := []string{"/etc/{file}", `C:\{session}`, "../{session}", "Descargas/{user}", "Descargas/{session", "{file}/Descargas", "{file}"}
+
+	for _, template := range unsafeTemplates {
+		t.Run(template, func(t *testing.T) {
+			// Arrange
+			service := NewFileTransferService(new(MockFileTransferRepository), nil, nil, nil)
+
+			// Act
+			err := service.SetClientDestinationTemplate(template)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidDestinationTemplate)
+			assert.Empty(t, service.destinationTemplate)
+		})
+	}
+}
+
+func TestInitiateServerToClientTransfer_RejectsInvalidPerTransferTemplate(t *testing.T) {
+	// Arrange
+	service, repo := newTemplateTransferService(t, "")
+	req := newTransferRequest(t, "")
+	req.ClientDestinationTemplate = "Descargas/{user}/{file}"
+
+	// Act
+	transfer, err := service.InitiateServerToClientTransfer(context.Background(), req)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidDestinationTemplate)
+	assert.Nil(t, transfer)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
@@ -2,12 +2,19 @@ package filetransferservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/storagequotaservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
 )
 
@@ -16,6 +23,16 @@ type FileTransferService struct {
 	fileTransferRepository interfaces.IFileTransferRepository
 	actionLogRepository    interfaces.IActionLogRepository
 	fileStorage            interfaces.IFileStorage
+	storageQuotaService    storagequotaservice.IStorageQuotaService
+	// allowedSourceDirs directorios desde los que se puede enviar un server_file_path (ver SetAllowedSourceDirs)
+	allowedSourceDirs []string
+	// destinationBusyPolicy qué hacer si el destino en el cliente ya está recibiendo otra transferencia
+	destinationBusyPolicy DestinationBusyPolicy
+	// destinationMutex serializa la comprobación de destino ocupado y el guardado con la política reject
+	destinationMutex sync.Mutex
+	// destinationTemplate plantilla de destino configurada (ver SetClientDestinationTemplate); vacía = sin plantilla
+	destinationTemplate string
+	now                 func() time.Time
 }
 
 // NewFileTransferService crea una nueva instancia del servicio
@@ -23,14 +40,27 @@ func NewFileTransferService(
 	fileTransferRepository interfaces.IFileTransferRepository,
 	actionLogRepository interfaces.IActionLogRepository,
 	fileStorage interfaces.IFileStorage,
+	storageQuotaService storagequotaservice.IStorageQuotaService,
 ) *FileTransferService {
 	return &FileTransferService{
 		fileTransferRepository: fileTransferRepository,
 		actionLogRepository:    actionLogRepository,
 		fileStorage:            fileStorage,
+		storageQuotaService:    storageQuotaService,
+		now:                    time.Now,
 	}
 }
 
+// DefaultClientDestinationDir carpeta de destino en el cliente cuando la solicitud no indica otra
+const DefaultClientDestinationDir = "Descargas/RemoteDesk"
+
+// ErrInvalidDestinationDir indica que la carpeta de destino solicitada no es una ruta relativa segura
+var ErrInvalidDestinationDir = errors.New("invalid client destination directory")
+
+// ErrFailedAfterCompletion el cliente reportó un fallo de una transferencia ya completada; queda COMPLETED y el
+// fallo se audita
+var ErrFailedAfterCompletion = errors.New("file transfer reported failed after completion")
+
 // InitiateServerToClientTransferRequest representa la solicitud de transferencia
 type InitiateServerToClientTransferRequest struct {
 	AdminUserID    string
@@ -38,6 +68,13 @@ type InitiateServerToClientTransferRequest struct {
 	TargetPCID     string
 	ServerFilePath string
 	ClientFileName string
+	// ClientDestinationDir carpeta relativa de destino en el cliente; vacía usa DefaultClientDestinationDir
+	ClientDestinationDir string
+	// ClientDestinationTemplate plantilla de destino solo para esta transferencia, con {session}, {date}, {pc} y
+	// {file}; tiene prioridad sobre ClientDestinationDir y sobre la plantilla configurada
+	ClientDestinationTemplate string
+	// ConflictPolicy qué hace el cliente si el archivo ya existe; vacía usa filetransfer.DefaultConflictPolicy
+	ConflictPolicy filetransfer.ConflictPolicy
 }
 
 // InitiateServerToClientTransfer inicia una transferencia de archivo del servidor al cliente
@@ -54,8 +91,25 @@ func (s *FileTransferService) InitiateServerToClientTransfer(
 	// 2. Calcular tamaño del archivo en MB
 	fileSizeMB := float64(fileInfo.Size()) / (1024 * 1024)
 
-	// 3. Definir ruta de destino en el cliente (predefinida para MVP)
-	destinationPath := s.getClientDestinationPath(req.ClientFileName)
+	// 2.1 Reservar la cuota de almacenamiento del cliente; la reserva se libera al salir, cuando el registro ya
+	// está guardado y la cuota lo cuenta desde la base de datos (o la transferencia no llegó a crearse)
+	if s.storageQuotaService != nil {
+		release, _, err := s.storageQuotaService.ReserveQuota(ctx, req.TargetPCID, fileSizeMB)
+		if err != nil {
+			return nil, fmt.Errorf("transferencia rechazada: %w", err)
+		}
+		defer release()
+	}
+
+	// 3. Definir ruta de destino en el cliente (la solicitada, la plantilla o la predefinida)
+	destinationPath, err := s.getClientDestinationPath(req)
+	if err != nil {
+		return nil, err
+	}
+	conflictPolicy, err := filetransfer.ParseConflictPolicy(string(req.ConflictPolicy))
+	if err != nil {
+		return nil, err
+	}
 
 	// 4. Crear registro FileTransfer en BD con estado PENDING
 	transfer := filetransfer.NewFileTransfer(
@@ -67,6 +121,25 @@ func (s *FileTransferService) InitiateServerToClientTransfer(
 		req.TargetPCID,
 		fileSizeMB,
 	)
+	if err := transfer.SetConflictPolicy(conflictPolicy); err != nil {
+		return nil, err
+	}
+
+	// 4.1 Con la política reject no se admite una segunda transferencia al mismo archivo del PC mientras la
+	// anterior siga pendiente o en curso; con queue se guarda PENDING y se envía al terminar (FindConflictingTransfer)
+	if s.DestinationBusyPolicy() == DestinationBusyReject {
+		s.destinationMutex.Lock()
+		defer s.destinationMutex.Unlock()
+
+		conflict, err := s.FindConflictingTransfer(ctx, transfer)
+		if err != nil {
+			return nil, err
+		}
+		if conflict != nil {
+			return nil, fmt.Errorf("%w: transferencia %s (%s) hacia %s", ErrDestinationBusy,
+				conflict.TransferID(), conflict.Status(), conflict.DestinationPathClient())
+		}
+	}
 
 	err = s.fileTransferRepository.Save(ctx, transfer)
 	if err != nil {
@@ -91,39 +164,58 @@ func (s *FileTransferService) UpdateTransferStatus(
 	status filetransfer.TransferStatus,
 	errorMessage string,
 ) error {
-	err := s.fileTransferRepository.UpdateStatus(ctx, transferID, status, errorMessage)
+	transfer, err := s.fileTransferRepository.FindByID(ctx, transferID)
+	if err != nil {
+		return fmt.Errorf("error obteniendo transferencia: %w", err)
+	}
+	if transfer == nil {
+		return fmt.Errorf("transferencia no encontrada: %s", transferID)
+	}
+
+	// Repetir el estado actual no hace nada (p. ej. COMPLETED del servidor y después COMPLETED_CLIENT)
+	if transfer.Status() == status {
+		return nil
+	}
+	if transfer.Status() == filetransfer.TransferStatusCompleted &&
+		(status == filetransfer.TransferStatusFailed || status == filetransfer.TransferStatusInsufficientClientSpace) {
+		return s.recordFailureAfterCompletion(ctx, transfer, status, errorMessage)
+	}
+	if err := transfer.UpdateStatus(status, errorMessage); err != nil {
+		return fmt.Errorf("transferencia %s: %w", transferID, err)
+	}
+
+	err = s.fileTransferRepository.UpdateStatus(ctx, transferID, status, errorMessage)
 	if err != nil {
 		return fmt.Errorf("error actualizando estado de transferencia: %w", err)
 	}
 
-	// Log the status change
+	// Log the status change (IN_PROGRESS no tiene action_type propio en action_logs)
 	var actionType string
 	switch status {
-	case filetransfer.TransferStatusInProgress:
-		actionType = "FILE_TRANSFER_STARTED"
 	case filetransfer.TransferStatusCompleted:
-		actionType = "FILE_TRANSFER_COMPLETED"
-	case filetransfer.TransferStatusFailed:
-		actionType = "FILE_TRANSFER_FAILED"
+		actionType = string(actionlog.ActionFileTransferCompleted)
+	case filetransfer.TransferStatusFailed, filetransfer.TransferStatusInsufficientClientSpace:
+		actionType = string(actionlog.ActionFileTransferFailed)
 	}
 
 	if actionType != "" {
-		// Get transfer to log details
-		transfer, err := s.fileTransferRepository.FindByID(ctx, transferID)
-		if err == nil {
-			description := fmt.Sprintf("Transferencia %s - Estado: %s", transfer.FileName(), status)
-			if errorMessage != "" {
-				description += fmt.Sprintf(" - Error: %s", errorMessage)
-			}
-
-			s.logTransferAction(ctx, transfer.InitiatingUserID(), transferID, actionType, description)
+		description := fmt.Sprintf("Transferencia %s - Estado: %s", transfer.FileName(), status)
+		if errorMessage != "" {
+			description += fmt.Sprintf(" - Error: %s", errorMessage)
 		}
+
+		s.logTransferAction(ctx, transfer.InitiatingUserID(), transferID, actionType, description)
 	}
 
 	return nil
 }
 
-// ReadFileInChunks lee un archivo en chunks para transferencia
+// ErrSourceFileChanged indica que el archivo de origen cambió (truncado, creció o eliminado) durante la transferencia
+var ErrSourceFileChanged = errors.New("source file changed during transfer")
+
+// ReadFileInChunks lee un archivo en chunks para transferencia.
+// El tamaño obtenido al abrir el archivo se toma como referencia: si los bytes leídos no coinciden
+// con él, la lectura falla con ErrSourceFileChanged y nunca se marca un chunk como último.
 func (s *FileTransferService) ReadFileInChunks(filePath string, chunkSize int, callback func([]byte, bool) error) error {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -131,28 +223,64 @@ func (s *FileTransferService) ReadFileInChunks(filePath string, chunkSize int, c
 	}
 	defer file.Close()
 
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("error obteniendo información del archivo: %w", err)
+	}
+	expectedSize := fileInfo.Size()
+
 	buffer := make([]byte, chunkSize)
+	var totalRead int64
 
-	for {
-		n, err := file.Read(buffer)
-		if err != nil && err != io.EOF {
+	for totalRead < expectedSize {
+		n, err := io.ReadFull(file, buffer)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
 			return fmt.Errorf("error leyendo archivo: %w", err)
 		}
 
 		if n == 0 {
-			break
+			// El archivo se truncó antes de alcanzar el tamaño esperado
+			return fmt.Errorf("%w: esperados %d bytes, leídos %d", ErrSourceFileChanged, expectedSize, totalRead)
+		}
+
+		totalRead += int64(n)
+		if totalRead > expectedSize {
+			return fmt.Errorf("%w: el archivo creció por encima de %d bytes", ErrSourceFileChanged, expectedSize)
+		}
+
+		isLastChunk := totalRead == expectedSize
+		if isLastChunk {
+			// Verificar antes de confirmar el último chunk que el archivo no cambió
+			if err := s.verifySourceUnchanged(file, filePath, expectedSize); err != nil {
+				return err
+			}
+		} else if err == io.ErrUnexpectedEOF || err == io.EOF {
+			return fmt.Errorf("%w: esperados %d bytes, leídos %d", ErrSourceFileChanged, expectedSize, totalRead)
 		}
 
-		isLastChunk := err == io.EOF
 		chunk := buffer[:n]
 
 		if err := callback(chunk, isLastChunk); err != nil {
 			return fmt.Errorf("error procesando chunk: %w", err)
 		}
+	}
 
-		if isLastChunk {
-			break
-		}
+	return nil
+}
+
+// verifySourceUnchanged comprueba que el archivo sigue existiendo con el tamaño esperado y sin datos adicionales
+func (s *FileTransferService) verifySourceUnchanged(file *os.File, filePath string, expectedSize int64) error {
+	pathInfo, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSourceFileChanged, err)
+	}
+	if pathInfo.Size() != expectedSize {
+		return fmt.Errorf("%w: tamaño esperado %d bytes, actual %d", ErrSourceFileChanged, expectedSize, pathInfo.Size())
+	}
+
+	extra := make([]byte, 1)
+	if n, _ := file.Read(extra); n > 0 {
+		return fmt.Errorf("%w: el archivo creció por encima de %d bytes", ErrSourceFileChanged, expectedSize)
 	}
 
 	return nil
@@ -204,17 +332,89 @@ func (s *FileTransferService) validateServerFile(filePath string) (os.FileInfo,
 	return fileInfo, nil
 }
 
-// getClientDestinationPath genera la ruta de destino en el cliente (predefinida para MVP)
-func (s *FileTransferService) getClientDestinationPath(fileName string) string {
-	// Para MVP, usar una ruta consistente con la configuración del cliente
-	// El cliente detecta automáticamente Descargas/Downloads y usa RemoteDesk como subcarpeta
-	return filepath.Join("Descargas", "RemoteDesk", fileName)
+// getClientDestinationPath genera la ruta de destino en el cliente: la plantilla de la solicitud, si no la carpeta
+// solicitada, si no la plantilla configurada y, sin ninguna, la carpeta predefinida (el cliente detecta
+// automáticamente Descargas/Downloads y usa RemoteDesk como subcarpeta)
+func (s *FileTransferService) getClientDestinationPath(req InitiateServerToClientTransferRequest) (string, error) {
+	if strings.TrimSpace(req.ClientDestinationTemplate) != "" {
+		template, err := ParseClientDestinationTemplate(req.ClientDestinationTemplate)
+		if err != nil {
+			return "", err
+		}
+		return s.expandClientDestinationTemplate(template, req)
+	}
+	if strings.TrimSpace(req.ClientDestinationDir) == "" && s.destinationTemplate != "" {
+		return s.expandClientDestinationTemplate(s.destinationTemplate, req)
+	}
+
+	dir, err := sanitizeClientDestinationDir(req.ClientDestinationDir)
+	if err != nil {
+		return "", err
+	}
+	// El nombre es un único segmento, igual que {file} en las plantillas: no puede sacar el archivo de la carpeta
+	return filepath.Join(filepath.FromSlash(dir), sanitizeDestinationTemplateValue(req.ClientFileName)), nil
+}
+
+// sanitizeClientDestinationDir normaliza la carpeta de destino y rechaza rutas absolutas,
+// segmentos ".." y caracteres no válidos en Windows para que el archivo no salga de la carpeta del usuario
+func sanitizeClientDestinationDir(dir string) (string, error) {
+	dir = strings.TrimSpace(strings.ReplaceAll(dir, "\\", "/"))
+	if dir == "" {
+		return DefaultClientDestinationDir, nil
+	}
+
+	if strings.HasPrefix(dir, "/") {
+		return "", fmt.Errorf("%w: %q debe ser una ruta relativa", ErrInvalidDestinationDir, dir)
+	}
+	if strings.ContainsAny(dir, `<>:"|?*`) || strings.ContainsFunc(dir, func(r rune) bool { return r < 0x20 }) {
+		return "", fmt.Errorf("%w: %q contiene caracteres no permitidos", ErrInvalidDestinationDir, dir)
+	}
+	for _, segment := range strings.Split(dir, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("%w: %q no puede contener '..'", ErrInvalidDestinationDir, dir)
+		}
+	}
+
+	cleaned := path.Clean(dir)
+	if cleaned == "." {
+		return "", fmt.Errorf("%w: %q no indica ninguna carpeta", ErrInvalidDestinationDir, dir)
+	}
+	return cleaned, nil
+}
+
+// recordFailureAfterCompletion registra el fallo que el cliente reportó de una transferencia ya completada. El
+// estado no cambia (COMPLETED es final), pero queda en el log y en la auditoría: el archivo puede no haber llegado
+// íntegro aunque el servidor lo diera por entregado.
+func (s *FileTransferService) recordFailureAfterCompletion(
+	ctx context.Context,
+	transfer *filetransfer.FileTransfer,
+	status filetransfer.TransferStatus,
+	errorMessage string,
+) error {
+	fmt.Printf("⚠️ FILE TRANSFER: %s reported %s after COMPLETED: %s\n", transfer.TransferID(), status, errorMessage)
+
+	description := fmt.Sprintf("Transferencia %s - Fallo reportado tras completarse (%s)", transfer.FileName(), status)
+	if errorMessage != "" {
+		description += fmt.Sprintf(" - Error: %s", errorMessage)
+	}
+	if err := s.logTransferAction(ctx, transfer.InitiatingUserID(), transfer.TransferID(),
+		string(actionlog.ActionFileTransferFailed), description); err != nil {
+		fmt.Printf("Error logging transfer action: %v\n", err)
+	}
+
+	return fmt.Errorf("%w: transferencia %s", ErrFailedAfterCompletion, transfer.TransferID())
 }
 
-// logTransferAction registra una acción de transferencia en el log de auditoría
+// logTransferAction registra una acción de transferencia en el log de auditoría; sin repositorio no hace nada
 func (s *FileTransferService) logTransferAction(ctx context.Context, userID, entityID, actionType, description string) error {
-	// Import and use the action log service if available
-	// For now, just return nil to avoid circular dependencies
-	// This should be properly implemented when action log is needed
+	if s.actionLogRepository == nil {
+		return nil
+	}
+
+	entityType := "FILE_TRANSFER"
+	entry := actionlog.NewActionLog(actionlog.ActionType(actionType), description, userID, &entityID, &entityType, nil)
+	if err := s.actionLogRepository.Save(ctx, entry); err != nil {
+		return fmt.Errorf("error guardando acción de transferencia: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,405 @@
+package filetransferservice
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+)
+
+const testChunkSize = 1024
+
+// writeTestFile crea un archivo temporal de size bytes
+func writeTestFile(t *testing.T, size int) string {
+	path := filepath.Join(t.TempDir(), "source.bin")
+	require.NoError(t, os.WriteFile(path, bytes.Repeat([]byte("a"), size), 0644))
+	return path
+}
+
+func TestReadFileInChunks_MarksOnlyFinalChunkAsLast(t *testing.T) {
+	// Arrange
+	service := NewFileTransferService(nil, nil, nil, nil)
+	path := writeTestFile(t, testChunkSize*3+100)
+
+	var chunkSizes []int
+	var lastFlags []bool
+
+	// Act
+	err := service.ReadFileInChunks(path, testChunkSize, func(chunk []byte, isLastChunk bool) error {
+		chunkSizes = append(chunkSizes, len(chunk))
+		lastFlags = append(lastFlags, isLastChunk)
+		return nil
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []int{testChunkSize, testChunkSize, testChunkSize, 100}, chunkSizes)
+	assert.Equal(t, []bool{false, false, false, true}, lastFlags)
+}
+
+func TestReadFileInChunks_ExactMultipleOfChunkSize(t *testing.T) {
+	// Arrange
+	service := NewFileTransferService(nil, nil, nil, nil)
+	path := writeTestFile(t, testChunkSize*2)
+
+	var lastFlags []bool
+
+	// Act
+	err := service.ReadFileInChunks(path, testChunkSize, func(chunk []byte, isLastChunk bool) error {
+		lastFlags = append(lastFlags, isLastChunk)
+		return nil
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, true}, lastFlags)
+}
+
+func TestReadFileInChunks_FailsWhenFileTruncatedMidRead(t *testing.T) {
+	// Arrange
+	service := NewFileTransferService(nil, nil, nil, nil)
+	path := writeTestFile(t, testChunkSize*4)
+
+	chunksSent := 0
+	lastChunkSent := false
+
+	// Act - otro proceso trunca el archivo después del primer chunk
+	err := service.ReadFileInChunks(path, testChunkSize, func(chunk []byte, isLastChunk bool) error {
+		chunksSent++
+		if isLastChunk {
+			lastChunkSent = true
+		}
+		if chunksSent == 1 {
+			require.NoError(t, os.Truncate(path, testChunkSize+10))
+		}
+		return nil
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrSourceFileChanged)
+	assert.Contains(t, err.Error(), "source file changed during transfer")
+	assert.False(t, lastChunkSent, "no se debe enviar IsLastChunk de un archivo truncado")
+}
+
+func TestReadFileInChunks_FailsWhenFileDeletedMidRead(t *testing.T) {
+	// Arrange
+	service := NewFileTransferService(nil, nil, nil, nil)
+	path := writeTestFile(t, testChunkSize*3)
+
+	lastChunkSent := false
+
+	// Act - el archivo se elimina mientras se lee
+	err := service.ReadFileInChunks(path, testChunkSize, func(chunk []byte, isLastChunk bool) error {
+		if isLastChunk {
+			lastChunkSent = true
+		}
+		_ = os.Remove(path)
+		return nil
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrSourceFileChanged)
+	assert.False(t, lastChunkSent)
+}
+
+func TestReadFileInChunks_FailsWhenFileGrowsMidRead(t *testing.T) {
+	// Arrange
+	service := NewFileTransferService(nil, nil, nil, nil)
+	path := writeTestFile(t, testChunkSize*2)
+
+	lastChunkSent := false
+	appended := false
+
+	// Act - se agregan datos al archivo durante la lectura
+	err := service.ReadFileInChunks(path, testChunkSize, func(chunk []byte, isLastChunk bool) error {
+		if isLastChunk {
+			lastChunkSent = true
+		}
+		if !appended {
+			appended = true
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+			require.NoError(t, err)
+			_, err = f.Write([]byte("extra"))
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+		}
+		return nil
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrSourceFileChanged)
+	assert.False(t, lastChunkSent)
+}
+
+// MockFileTransferRepository es un mock del repositorio de transferencias
+type MockFileTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockFileTransferRepository) Save(ctx context.Context, transfer *filetransfer.FileTransfer) error {
+	return m.Called(ctx, transfer).Error(0)
+}
+
+func (m *MockFileTransferRepository) UpdateStatus(ctx context.Context, transferID string, status filetransfer.TransferStatus, errorMessage string) error {
+	return m.Called(ctx, transferID, status, errorMessage).Error(0)
+}
+
+func (m *MockFileTransferRepository) FindByID(ctx context.Context, transferID string) (*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindByTargetPCID(ctx context.Context, targetPCID string) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, targetPCID)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindByInitiatingUserID(ctx context.Context, userID string) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindPendingTransfers(ctx context.Context) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) SumFileSizeByTargetPCID(ctx context.Context, targetPCID string) (float64, error) {
+	args := m.Called(ctx, targetPCID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindInProgressTransfers(ctx context.Context) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) Delete(ctx context.Context, transferID string) error {
+	return m.Called(ctx, transferID).Error(0)
+}
+
+// newTransferRequest crea una solicitud de transferencia sobre un archivo temporal
+func newTransferRequest(t *testing.T, destinationDir string) InitiateServerToClientTransferRequest {
+	return InitiateServerToClientTransferRequest{
+		AdminUserID:          "admin-1",
+		SessionID:            "session-1",
+		TargetPCID:           "pc-1",
+		ServerFilePath:       writeTestFile(t, 10),
+		ClientFileName:       "report.pdf",
+		ClientDestinationDir: destinationDir,
+	}
+}
+
+func TestInitiateServerToClientTransfer_UsesCustomDestinationDir(t *testing.T) {
+	// Arrange
+	repo := new(MockFileTransferRepository)
+	repo.On("Save", mock.Anything, mock.AnythingOfType("*filetransfer.FileTransfer")).Return(nil)
+	service := NewFileTransferService(repo, nil, nil, nil)
+
+	// Act
+	transfer, err := service.InitiateServerToClientTransfer(context.Background(), newTransferRequest(t, `Documentos\Reportes\2026`))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("Documentos", "Reportes", "2026", "report.pdf"), transfer.DestinationPathClient())
+	repo.AssertExpectations(t)
+}
+
+func TestInitiateServerToClientTransfer_DefaultsDestinationDirWhenOmitted(t *testing.T) {
+	// Arrange
+	repo := new(MockFileTransferRepository)
+	repo.On("Save", mock.Anything, mock.AnythingOfType("*filetransfer.FileTransfer")).Return(nil)
+	service := NewFileTransferService(repo, nil, nil, nil)
+
+	// Act
+	transfer, err := service.InitiateServerToClientTransfer(context.Background(), newTransferRequest(t, "  "))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("Descargas", "RemoteDesk", "report.pdf"), transfer.DestinationPathClient())
+}
+
+func TestInitiateServerToClientTransfer_RejectsUnsafeDestinationDir(t *testing.T) {
+	unsafeDirs := []string{"/etc", `C:\Windows\System32`, "../../startup", "Descargas/../../..", ".", "Docs/<script>"}
+
+	for _, dir := range unsafeDirs {
+		t.Run(dir, func(t *testing.T) {
+			// Arrange
+			repo := new(MockFileTransferRepository)
+			service := NewFileTransferService(repo, nil, nil, nil)
+
+			// Act
+			transfer, err := service.InitiateServerToClientTransfer(context.Background(), newTransferRequest(t, dir))
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidDestinationDir)
+			assert.Nil(t, transfer)
+			repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestInitiateServerToClientTransfer_FileNameCannotEscapeDestinationDir(t *testing.T) {
+	fileNames := map[string]string{
+		"../../../Windows/x.dll":       "_.._.._Windows_x.dll",
+		`..\..\Startup\run.bat`:        `_.._Startup_run.bat`,
+		"C:/Windows/System32/evil.dll": "C__Windows_System32_evil.dll",
+		"..":                           "_",
+	}
+
+	for fileName, expected := range fileNames {
+		t.Run(fileName, func(t *testing.T) {
+			// Arrange
+			repo := new(MockFileTransferRepository)
+			repo.On("Save", mock.Anything, mock.AnythingOfType("*filetransfer.FileTransfer")).Return(nil)
+			service := NewFileTransferService(repo, nil, nil, nil)
+			req := newTransferRequest(t, "")
+			req.ClientFileName = fileName
+
+			// Act
+			transfer, err := service.InitiateServerToClientTransfer(context.Background(), req)
+
+			// Assert - el archivo queda directamente dentro de la carpeta de destino
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join("Descargas", "RemoteDesk", expected), transfer.DestinationPathClient())
+		})
+	}
+}
+
+func TestUpdateTransferStatus_RejectsInvalidTransitionWithoutPersisting(t *testing.T) {
+	// Arrange
+	repo := new(MockFileTransferRepository)
+	completedAt := time.Now()
+	transfer := filetransfer.NewFileTransferFromDB("transfer-1", "report.pdf", "/srv/report.pdf", "Descargas/report.pdf",
+		&completedAt, filetransfer.TransferStatusCompleted, "session-1", "admin-1", "pc-1", 1, "", filetransfer.DefaultConflictPolicy, time.Now(), time.Now())
+	repo.On("FindByID", mock.Anything, "transfer-1").Return(transfer, nil)
+	service := NewFileTransferService(repo, nil, nil, nil)
+
+	// Act
+	err := service.UpdateTransferStatus(context.Background(), "transfer-1", filetransfer.TransferStatusInProgress, "")
+
+	// Assert
+	assert.ErrorIs(t, err, filetransfer.ErrInvalidStatusTransition)
+	repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// Repetir el estado actual no es un error ni vuelve a persistirse
+	assert.NoError(t, service.UpdateTransferStatus(context.Background(), "transfer-1", filetransfer.TransferStatusCompleted, ""))
+	repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateTransferStatus_StampsTransferTimeOnCompletion(t *testing.T) {
+	// Arrange - la transferencia se creó días antes de completarse
+	repo := new(MockFileTransferRepository)
+	createdAt := time.Now().Add(-72 * time.Hour)
+	transfer := filetransfer.NewFileTransferFromDB("transfer-1", "report.pdf", "/srv/report.pdf", "Descargas/report.pdf",
+		nil, filetransfer.TransferStatusInProgress, "session-1", "admin-1", "pc-1", 1, "", filetransfer.DefaultConflictPolicy, createdAt, createdAt)
+	repo.On("FindByID", mock.Anything, "transfer-1").Return(transfer, nil)
+	repo.On("UpdateStatus", mock.Anything, "transfer-1", filetransfer.TransferStatusCompleted, "").Return(nil)
+	service := NewFileTransferService(repo, nil, nil, nil)
+
+	// Act
+	err := service.UpdateTransferStatus(context.Background(), "transfer-1", filetransfer.TransferStatusCompleted, "")
+
+	// Assert
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	require.NotNil(t, transfer.TransferTime())
+	assert.WithinDuration(t, time.Now(), *transfer.TransferTime(), time.Second)
+	assert.True(t, transfer.TransferTime().After(transfer.CreatedAt()))
+}
+
+func TestInitiateServerToClientTransfer_RecordsRequestedConflictPolicy(t *testing.T) {
+	policies := map[filetransfer.ConflictPolicy]filetransfer.ConflictPolicy{
+		"":                                   filetransfer.ConflictPolicyRename,
+		filetransfer.ConflictPolicyOverwrite: filetransfer.ConflictPolicyOverwrite,
+		filetransfer.ConflictPolicyRename:    filetransfer.ConflictPolicyRename,
+		filetransfer.ConflictPolicySkip:      filetransfer.ConflictPolicySkip,
+	}
+
+	for requested, expected := range policies {
+		t.Run(string(expected)+"/"+string(requested), func(t *testing.T) {
+			// Arrange
+			repo := new(MockFileTransferRepository)
+			repo.On("Save", mock.Anything, mock.AnythingOfType("*filetransfer.FileTransfer")).Return(nil)
+			service := NewFileTransferService(repo, nil, nil, nil)
+			req := newTransferRequest(t, "")
+			req.ConflictPolicy = requested
+
+			// Act
+			transfer, err := service.InitiateServerToClientTransfer(context.Background(), req)
+
+			// Assert - la política se guarda con la transferencia
+			require.NoError(t, err)
+			assert.Equal(t, expected, transfer.ConflictPolicy())
+			saved := repo.Calls[0].Arguments.Get(1).(*filetransfer.FileTransfer)
+			assert.Equal(t, expected, saved.ConflictPolicy())
+		})
+	}
+}
+
+func TestInitiateServerToClientTransfer_RejectsUnknownConflictPolicy(t *testing.T) {
+	// Arrange
+	repo := new(MockFileTransferRepository)
+	service := NewFileTransferService(repo, nil, nil, nil)
+	req := newTransferRequest(t, "")
+	req.ConflictPolicy = "append"
+
+	// Act
+	transfer, err := service.InitiateServerToClientTransfer(context.Background(), req)
+
+	// Assert
+	assert.ErrorIs(t, err, filetransfer.ErrInvalidConflictPolicy)
+	assert.Nil(t, transfer)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+// savingActionLogRepository guarda en memoria los logs persistidos; el resto del repositorio no se usa
+type savingActionLogRepository struct {
+	interfaces.IActionLogRepository
+	saved []*actionlog.ActionLog
+}
+
+func (r *savingActionLogRepository) Save(ctx context.Context, log *actionlog.ActionLog) error {
+	r.saved = append(r.saved, log)
+	return nil
+}
+
+func TestUpdateTransferStatus_FailureAfterCompletionIsAuditedWithoutChangingStatus(t *testing.T) {
+	// Arrange
+	repo := new(MockFileTransferRepository)
+	completedAt := time.Now()
+	transfer := filetransfer.NewFileTransferFromDB("transfer-1", "report.pdf", "/srv/report.pdf", "Descargas/report.pdf",
+		&completedAt, filetransfer.TransferStatusCompleted, "session-1", "admin-1", "pc-1", 1, "", filetransfer.DefaultConflictPolicy, time.Now(), time.Now())
+	repo.On("FindByID", mock.Anything, "transfer-1").Return(transfer, nil)
+	actionLogs := &savingActionLogRepository{}
+	service := NewFileTransferService(repo, actionLogs, nil, nil)
+
+	// Act
+	err := service.UpdateTransferStatus(context.Background(), "transfer-1", filetransfer.TransferStatusFailed, "checksum mismatch")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrFailedAfterCompletion)
+	repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.Len(t, actionLogs.saved, 1)
+	assert.Equal(t, actionlog.ActionFileTransferFailed, actionLogs.saved[0].ActionType())
+	assert.Equal(t, "admin-1", actionLogs.saved[0].PerformedByUserID())
+	assert.Equal(t, "transfer-1", *actionLogs.saved[0].SubjectEntityID())
+	assert.Contains(t, actionLogs.saved[0].Description(), "checksum mismatch")
+}
@@ -0,0 +1,95 @@
+package filetransferservice
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrServerPathNotAllowed la ruta indicada en server_file_path está fuera de los directorios permitidos
+	ErrServerPathNotAllowed = errors.New("server file path is outside the allowed source directories")
+	// ErrServerFileNotFound la ruta está dentro de un directorio permitido pero no existe
+	ErrServerFileNotFound = errors.New("server file not found")
+)
+
+// ParseSourceDirs separa una lista de directorios por comas (FILE_TRANSFER_SOURCE_DIRS), ignorando vacíos
+func ParseSourceDirs(value string) []string {
+	var dirs []string
+	for _, dir := range strings.Split(value, ",") {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// SetAllowedSourceDirs establece los directorios base desde los que un administrador puede enviar archivos
+// indicando server_file_path. Sin directorios no se acepta ninguna ruta; los archivos subidos no se ven afectados.
+func (s *FileTransferService) SetAllowedSourceDirs(dirs []string) error {
+	allowed := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("directorio de origen inválido %q: %w", dir, err)
+		}
+		allowed = append(allowed, absDir)
+	}
+	s.allowedSourceDirs = allowed
+	return nil
+}
+
+// ResolveServerSourcePath valida una ruta de servidor indicada por el administrador y retorna la ruta real
+// (sin symlinks ni ".."). Debe estar dentro de alguno de los directorios permitidos tanto antes como después
+// de resolver los symlinks, para que ni "../" ni un enlace dentro del directorio permitan salir de él.
+func (s *FileTransferService) ResolveServerSourcePath(filePath string) (string, error) {
+	if len(s.allowedSourceDirs) == 0 {
+		return "", fmt.Errorf("%w: no source directories configured", ErrServerPathNotAllowed)
+	}
+
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrServerPathNotAllowed, err)
+	}
+	if !s.withinAllowedDir(absPath, false) {
+		return "", fmt.Errorf("%w: %s", ErrServerPathNotAllowed, filePath)
+	}
+
+	resolvedPath, err := filepath.EvalSymlinks(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: %s", ErrServerFileNotFound, filePath)
+		}
+		return "", fmt.Errorf("error resolviendo la ruta del archivo: %w", err)
+	}
+	if !s.withinAllowedDir(resolvedPath, true) {
+		return "", fmt.Errorf("%w: %s resolves outside the allowed directories", ErrServerPathNotAllowed, filePath)
+	}
+
+	return resolvedPath, nil
+}
+
+// withinAllowedDir indica si path está dentro de algún directorio permitido; con resolveDirs se comparan
+// los directorios ya resueltos, ya que path también lo está
+func (s *FileTransferService) withinAllowedDir(path string, resolveDirs bool) bool {
+	for _, dir := range s.allowedSourceDirs {
+		if resolveDirs {
+			resolvedDir, err := filepath.EvalSymlinks(dir)
+			if err != nil {
+				continue
+			}
+			dir = resolvedDir
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			continue
+		}
+		if rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package filetransferservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSourceDirsFixture crea un directorio permitido con shared/report.pdf y un secreto fuera de él
+func newSourceDirsFixture(t *testing.T) (*FileTransferService, string, string) {
+	root := t.TempDir()
+	allowedDir := filepath.Join(root, "shared")
+	require.NoError(t, os.Mkdir(allowedDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(allowedDir, "report.pdf"), []byte("report"), 0644))
+
+	secret := filepath.Join(root, "shadow")
+	require.NoError(t, os.WriteFile(secret, []byte("secret"), 0600))
+
+	service := NewFileTransferService(nil, nil, nil, nil)
+	require.NoError(t, service.SetAllowedSourceDirs([]string{allowedDir}))
+	return service, allowedDir, secret
+}
+
+func TestResolveServerSourcePath_AllowsFileInsideAllowedDir(t *testing.T) {
+	// Arrange
+	service, allowedDir, _ := newSourceDirsFixture(t)
+
+	// Act
+	resolved, err := service.ResolveServerSourcePath(filepath.Join(allowedDir, "report.pdf"))
+
+	// Assert
+	require.NoError(t, err)
+	expected, _ := filepath.EvalSymlinks(filepath.Join(allowedDir, "report.pdf"))
+	assert.Equal(t, expected, resolved)
+}
+
+func TestResolveServerSourcePath_RejectsTraversal(t *testing.T) {
+	// Arrange
+	service, allowedDir, _ := newSourceDirsFixture(t)
+
+	// Act
+	_, err := service.ResolveServerSourcePath(allowedDir + "/../shadow")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrServerPathNotAllowed)
+
+	_, err = service.ResolveServerSourcePath("/etc/shadow")
+	assert.ErrorIs(t, err, ErrServerPathNotAllowed)
+}
+
+func TestResolveServerSourcePath_RejectsSymlinkEscape(t *testing.T) {
+	// Arrange
+	service, allowedDir, secret := newSourceDirsFixture(t)
+	link := filepath.Join(allowedDir, "innocent.pdf")
+	require.NoError(t, os.Symlink(secret, link))
+
+	// Act
+	_, err := service.ResolveServerSourcePath(link)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrServerPathNotAllowed)
+}
+
+func TestResolveServerSourcePath_RejectsEverythingWithoutConfiguredDirs(t *testing.T) {
+	// Arrange
+	service := NewFileTransferService(nil, nil, nil, nil)
+	path := writeTestFile(t, 10)
+
+	// Act
+	_, err := service.ResolveServerSourcePath(path)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrServerPathNotAllowed)
+}
+
+func TestResolveServerSourcePath_MissingFileInsideAllowedDir(t *testing.T) {
+	// Arrange
+	service, allowedDir, _ := newSourceDirsFixture(t)
+
+	// Act
+	_, err := service.ResolveServerSourcePath(filepath.Join(allowedDir, "missing.pdf"))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrServerFileNotFound)
+}
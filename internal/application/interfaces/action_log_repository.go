@@ -6,6 +6,14 @@ import (
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
 )
 
+// ActionLogFilter criterios combinables (AND) para recorrer el audit log; un campo vacío no filtra
+type ActionLogFilter struct {
+	PerformedByUserID string
+	ActionType        actionlog.ActionType
+	SubjectEntityID   string
+	SubjectEntityType string
+}
+
 // IActionLogRepository define la interfaz para operaciones de persistencia de ActionLog
 type IActionLogRepository interface {
 	// Save guarda una nueva entrada de log de auditoría
@@ -31,4 +39,8 @@ type IActionLogRepository interface {
 
 	// CountByUser retorna el número de logs de un usuario específico
 	CountByUser(ctx context.Context, userID string) (int, error)
+
+	// StreamByFilter recorre los logs que cumplen el filtro, del más antiguo al más reciente, llamando a fn con
+	// cada uno sin cargarlos todos en memoria. Si fn retorna error el recorrido se detiene con ese error.
+	StreamByFilter(ctx context.Context, filter ActionLogFilter, fn func(*actionlog.ActionLog) error) error
 }
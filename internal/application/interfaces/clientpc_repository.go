@@ -14,6 +14,9 @@ type IClientPCRepository interface {
 	// FindByID retrieves a ClientPC by its ID
 	FindByID(ctx context.Context, pcID string) (*clientpc.ClientPC, error)
 
+	// FindByIDs retrieves several ClientPCs in a single query, keyed by PC ID; unknown IDs are omitted
+	FindByIDs(ctx context.Context, pcIDs []string) (map[string]*clientpc.ClientPC, error)
+
 	// FindByIdentifierAndOwner retrieves a ClientPC by identifier and owner user ID
 	FindByIdentifierAndOwner(ctx context.Context, identifier string, ownerID string) (*clientpc.ClientPC, error)
 
@@ -29,6 +32,9 @@ type IClientPCRepository interface {
 	// UpdateLastSeen updates the last seen timestamp of a ClientPC
 	UpdateLastSeen(ctx context.Context, pcID string) error
 
+	// UpdateAutoAcceptControl updates whether remote control requests are accepted automatically
+	UpdateAutoAcceptControl(ctx context.Context, pcID string, enabled bool) error
+
 	// Delete removes a ClientPC from the repository
 	Delete(ctx context.Context, pcID string) error
 
@@ -37,4 +43,7 @@ type IClientPCRepository interface {
 
 	// CountByOwner returns the count of ClientPCs for a specific owner
 	CountByOwner(ctx context.Context, ownerID string) (int, error)
+
+	// Count returns the total number of ClientPCs
+	Count(ctx context.Context) (int, error)
 }
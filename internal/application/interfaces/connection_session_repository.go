@@ -0,0 +1,28 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/connectionsession"
+)
+
+// IConnectionSessionRepository define la interfaz para la persistencia del historial de conexiones
+type IConnectionSessionRepository interface {
+	// Save guarda una nueva sesión de conexión
+	Save(ctx context.Context, session *connectionsession.ConnectionSession) error
+
+	// Update actualiza una sesión de conexión existente (desconexión)
+	Update(ctx context.Context, session *connectionsession.ConnectionSession) error
+
+	// FindByID busca una sesión de conexión por su ID
+	FindByID(ctx context.Context, connectionID string) (*connectionsession.ConnectionSession, error)
+
+	// FindByPCID obtiene el historial de conexiones de un PC, más recientes primero
+	FindByPCID(ctx context.Context, pcID string, limit, offset int) ([]*connectionsession.ConnectionSession, error)
+
+	// CountByPCID cuenta las sesiones de conexión registradas para un PC
+	CountByPCID(ctx context.Context, pcID string) (int, error)
+
+	// FindOpenByPCID obtiene las sesiones de conexión de un PC que siguen sin desconexión registrada
+	FindOpenByPCID(ctx context.Context, pcID string) ([]*connectionsession.ConnectionSession, error)
+}
@@ -7,6 +7,11 @@ type IFileStorage interface {
 	// SaveFile guarda un archivo en el almacenamiento y retorna la ruta final
 	SaveFile(ctx context.Context, destinationPath string, content []byte) (string, error)
 
+	// MoveFile lleva al almacenamiento un archivo local ya escrito (p. ej. ensamblado en un temporal) y retorna la
+	// ruta final. Renombra si origen y destino comparten backend y, si no, copia y borra el origen; en ningún caso
+	// queda visible un destino a medio escribir.
+	MoveFile(ctx context.Context, sourcePath, destinationPath string) (string, error)
+
 	// ReadFile lee un archivo del almacenamiento
 	ReadFile(ctx context.Context, filePath string) ([]byte, error)
 
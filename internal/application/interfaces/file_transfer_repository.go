@@ -23,6 +23,9 @@ type IFileTransferRepository interface {
 	// FindByTargetPCID busca todas las transferencias enviadas a un PC específico
 	FindByTargetPCID(ctx context.Context, targetPCID string) ([]*filetransfer.FileTransfer, error)
 
+	// SumFileSizeByTargetPCID suma el tamaño en MB de las transferencias enviadas a un PC, sin contar las fallidas
+	SumFileSizeByTargetPCID(ctx context.Context, targetPCID string) (float64, error)
+
 	// FindByInitiatingUserID busca todas las transferencias iniciadas por un usuario
 	FindByInitiatingUserID(ctx context.Context, userID string) ([]*filetransfer.FileTransfer, error)
 
@@ -31,4 +34,7 @@ type IFileTransferRepository interface {
 
 	// FindInProgressTransfers busca todas las transferencias en estado IN_PROGRESS
 	FindInProgressTransfers(ctx context.Context) ([]*filetransfer.FileTransfer, error)
+
+	// Delete elimina el registro de una transferencia
+	Delete(ctx context.Context, transferID string) error
 } 
\ No newline at end of file
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/macro"
+)
+
+// IMacroRepository define la interfaz para la persistencia de macros de input
+type IMacroRepository interface {
+	// Save guarda una nueva macro
+	Save(ctx context.Context, m *macro.Macro) error
+
+	// FindByID busca una macro por su ID (nil si no existe)
+	FindByID(ctx context.Context, macroID string) (*macro.Macro, error)
+
+	// FindByAdminUserID obtiene las macros de un administrador, más recientes primero
+	FindByAdminUserID(ctx context.Context, adminUserID string) ([]*macro.Macro, error)
+}
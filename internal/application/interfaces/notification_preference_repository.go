@@ -0,0 +1,20 @@
+package interfaces
+
+import "context"
+
+// NotificationPreferenceRecord preferencias de notificación persistidas de un administrador
+type NotificationPreferenceRecord struct {
+	AdminUserID     string
+	Scope           string
+	MutedCategories []string
+}
+
+// INotificationPreferenceRepository define la interfaz para la persistencia de las preferencias de notificación
+// de los administradores
+type INotificationPreferenceRepository interface {
+	// FindByAdmin obtiene las preferencias del administrador (nil si nunca las cambió)
+	FindByAdmin(ctx context.Context, adminUserID string) (*NotificationPreferenceRecord, error)
+
+	// Save crea o reemplaza las preferencias del administrador
+	Save(ctx context.Context, record *NotificationPreferenceRecord) error
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import "context"
+
+// IPinnedPCRepository define la interfaz para la persistencia de los PCs fijados (favoritos) por administrador
+type IPinnedPCRepository interface {
+	// Pin fija un PC para el administrador; fijar un PC ya fijado no hace nada
+	Pin(ctx context.Context, adminUserID, pcID string) error
+
+	// Unpin quita el PC de los fijados del administrador; quitar un PC no fijado no hace nada
+	Unpin(ctx context.Context, adminUserID, pcID string) error
+
+	// FindPCIDsByAdmin obtiene los IDs de los PCs fijados por el administrador, en orden de fijado
+	FindPCIDsByAdmin(ctx context.Context, adminUserID string) ([]string, error)
+}
@@ -1,44 +1,55 @@
 package interfaces
 
 import (
+	"context"
+	"time"
+
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
 )
 
-// IRemoteSessionRepository define la interface del repositorio de sesiones remotas
+// IRemoteSessionRepository define la interface del repositorio de sesiones remotas.
+// Todas las operaciones reciben el contexto de la petición o conexión que las origina.
 type IRemoteSessionRepository interface {
 	// Save guarda una nueva sesión remota
-	Save(session *remotesession.RemoteSession) error
+	Save(ctx context.Context, session *remotesession.RemoteSession) error
 	
 	// FindById busca una sesión por su ID
-	FindById(id string) (*remotesession.RemoteSession, error)
+	FindById(ctx context.Context, id string) (*remotesession.RemoteSession, error)
 	
 	// UpdateStatus actualiza el estado de una sesión
-	UpdateStatus(id string, status remotesession.SessionStatus) error
+	UpdateStatus(ctx context.Context, id string, status remotesession.SessionStatus) error
 	
 	// FindByAdminUserID busca sesiones por ID de usuario administrador
-	FindByAdminUserID(adminUserID string) ([]*remotesession.RemoteSession, error)
+	FindByAdminUserID(ctx context.Context, adminUserID string) ([]*remotesession.RemoteSession, error)
 	
 	// FindByClientPCID busca sesiones por ID de PC cliente
-	FindByClientPCID(clientPCID string) ([]*remotesession.RemoteSession, error)
+	FindByClientPCID(ctx context.Context, clientPCID string) ([]*remotesession.RemoteSession, error)
 	
 	// FindActiveSessions busca sesiones activas
-	FindActiveSessions() ([]*remotesession.RemoteSession, error)
+	FindActiveSessions(ctx context.Context) ([]*remotesession.RemoteSession, error)
 	
 	// FindPendingSessions busca sesiones pendientes de aprobación
-	FindPendingSessions() ([]*remotesession.RemoteSession, error)
+	FindPendingSessions(ctx context.Context) ([]*remotesession.RemoteSession, error)
 	
 	// Update actualiza una sesión completa
-	Update(session *remotesession.RemoteSession) error
+	Update(ctx context.Context, session *remotesession.RemoteSession) error
 	
-	// Delete elimina una sesión (soft delete)
-	Delete(id string) error
+	// Delete elimina una sesión terminada; si está en curso no la borra y retorna remotesession.ErrSessionInProgress
+	Delete(ctx context.Context, id string) error
 	
 	// FindByStatus busca sesiones por estado
-	FindByStatus(status remotesession.SessionStatus) ([]*remotesession.RemoteSession, error)
+	FindByStatus(ctx context.Context, status remotesession.SessionStatus) ([]*remotesession.RemoteSession, error)
 	
 	// FindSessionsByDateRange busca sesiones en un rango de fechas
-	FindSessionsByDateRange(adminUserID string, startDate, endDate string) ([]*remotesession.RemoteSession, error)
+	FindSessionsByDateRange(ctx context.Context, adminUserID string, startDate, endDate string) ([]*remotesession.RemoteSession, error)
+	
+	// FindAccessReport busca una página de las sesiones de un administrador sobre un PC iniciadas entre from y to,
+	// de la más reciente a la más antigua. Un ID vacío o una fecha cero no filtran por ese campo.
+	FindAccessReport(ctx context.Context, adminUserID, clientPCID string, from, to time.Time, limit, offset int) ([]*remotesession.RemoteSession, error)
+
+	// CountAccessReport cuenta las sesiones que cumplen los mismos filtros que FindAccessReport
+	CountAccessReport(ctx context.Context, adminUserID, clientPCID string, from, to time.Time) (int, error)
 	
 	// CountSessionsByUser cuenta sesiones por usuario
-	CountSessionsByUser(adminUserID string) (int64, error)
+	CountSessionsByUser(ctx context.Context, adminUserID string) (int64, error)
 } 
\ No newline at end of file
@@ -1,24 +1,27 @@
 package interfaces
 
 import (
+	"context"
+
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
 )
 
 // IRemoteSessionService define la interfaz para el servicio de sesiones remotas
 type IRemoteSessionService interface {
-	InitiateSession(adminUserID, clientPCID string) (*remotesession.RemoteSession, error)
-	AcceptSession(sessionID string) error
-	RejectSession(sessionID, reason string) error
-	GetSessionById(sessionID string) (*remotesession.RemoteSession, error)
-	GetActiveSessions() ([]*remotesession.RemoteSession, error)
-	GetSessionsByUser(userID string) ([]*remotesession.RemoteSession, error)
-	GetSessionsByPC(clientPCID string) ([]*remotesession.RemoteSession, error)
-	CleanupStuckSessions(clientPCID string) error
-	GetActiveSessionForPC(clientPCID string) (*remotesession.RemoteSession, error)
-	IsSessionActiveForStreaming(sessionID string) (bool, error)
-	GetAdminUserIDForActiveSession(sessionID string) (string, error)
-	GetClientPCIDForActiveSession(sessionID string) (string, error)
-	ValidateStreamingPermission(sessionID, clientPCID string) error
-	ValidateInputCommandPermission(sessionID, adminUserID string) error
-	HandleClientPCDisconnect(clientPCID string) error
+	InitiateSession(ctx context.Context, adminUserID, clientPCID string, justification remotesession.Justification) (*remotesession.RemoteSession, error)
+	AcceptSession(ctx context.Context, sessionID string) error
+	RejectSession(ctx context.Context, sessionID, reason string) error
+	GetSessionById(ctx context.Context, sessionID string) (*remotesession.RemoteSession, error)
+	GetActiveSessions(ctx context.Context) ([]*remotesession.RemoteSession, error)
+	GetSessionsByUser(ctx context.Context, userID string) ([]*remotesession.RemoteSession, error)
+	GetSessionsByPC(ctx context.Context, clientPCID string) ([]*remotesession.RemoteSession, error)
+	CleanupStuckSessions(ctx context.Context, clientPCID string) error
+	GetActiveSessionForPC(ctx context.Context, clientPCID string) (*remotesession.RemoteSession, error)
+	IsSessionActiveForStreaming(ctx context.Context, sessionID string) (bool, error)
+	GetAdminUserIDForActiveSession(ctx context.Context, sessionID string) (string, error)
+	GetClientPCIDForActiveSession(ctx context.Context, sessionID string) (string, error)
+	ValidateStreamingPermission(ctx context.Context, sessionID, clientPCID string) error
+	ValidateRecordingPermission(ctx context.Context, sessionID, clientPCID string) error
+	ValidateInputCommandPermission(ctx context.Context, sessionID, adminUserID string) error
+	HandleClientPCDisconnect(ctx context.Context, clientPCID string, reason remotesession.DisconnectReason) error
 } 
\ No newline at end of file
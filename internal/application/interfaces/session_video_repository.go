@@ -29,6 +29,15 @@ type ISessionVideoRepository interface {
 	// Count obtiene el total de videos
 	Count(ctx context.Context) (int64, error)
 
+	// SumFileSizeByClientPCID suma el tamaño en MB de las grabaciones de todas las sesiones de un PC cliente
+	SumFileSizeByClientPCID(ctx context.Context, clientPCID string) (float64, error)
+
 	// FindByDateRange busca videos en un rango de fechas
 	FindByDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*sessionvideo.SessionVideo, error)
+
+	// SaveFrameIndex guarda en una sola transacción el índice de frames de una grabación empaquetada
+	SaveFrameIndex(ctx context.Context, videoID string, entries []sessionvideo.FrameIndexEntry) error
+
+	// FindOrphaned busca hasta limit grabaciones cuya sesión ya no existe en remote_sessions, de la más antigua a la más reciente
+	FindOrphaned(ctx context.Context, limit int) ([]*sessionvideo.SessionVideo, error)
 }
@@ -1,6 +1,8 @@
 package interfaces
 
 import (
+	"context"
+
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
 )
 
@@ -12,9 +14,15 @@ type IUserRepository interface {
 	// FindByID busca un usuario por su ID
 	FindByID(userID string) (*user.User, error)
 
+	// FindByIDs busca varios usuarios en una sola consulta; los IDs inexistentes no aparecen en el mapa
+	FindByIDs(ctx context.Context, userIDs []string) (map[string]*user.User, error)
+
 	// Save guarda o actualiza un usuario
 	Save(user *user.User) error
 
 	// Create crea un nuevo usuario
 	Create(user *user.User) error
+
+	// IncrementTokenVersion incrementa de forma atómica la versión de los tokens del usuario y retorna la nueva
+	IncrementTokenVersion(userID string) (int, error)
 }
@@ -0,0 +1,261 @@
+package macroservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/macro"
+)
+
+var (
+	// ErrMacroNotFound indica que la macro no existe o pertenece a otro administrador
+	ErrMacroNotFound = errors.New("macro not found")
+	// ErrNoActiveRecording indica que no hay una grabación de macro en curso para la sesión
+	ErrNoActiveRecording = errors.New("no macro recording in progress for this session")
+	// ErrInputNotPermitted indica que el administrador no puede enviar input a la sesión
+	ErrInputNotPermitted = errors.New("input commands not permitted for this session")
+)
+
+// ISessionInputValidator valida que un administrador puede enviar comandos de input a una sesión
+type ISessionInputValidator interface {
+	ValidateInputCommandPermission(ctx context.Context, sessionID, adminUserID string) error
+}
+
+// ICommandSender envía un comando de input al cliente controlado en la sesión, validando el permiso en cada envío
+type ICommandSender interface {
+	SendMacroCommand(ctx context.Context, adminUserID, sessionID string, command macro.Command) error
+}
+
+// IMacroService define la interfaz de grabación y reproducción de macros de input
+type IMacroService interface {
+	StartRecording(ctx context.Context, sessionID, adminUserID string) error
+	CaptureCommand(sessionID string, command macro.Command)
+	SaveRecording(ctx context.Context, sessionID, adminUserID, name string) (*macro.Macro, error)
+	ListMacros(ctx context.Context, adminUserID string) ([]*macro.Macro, error)
+	ReplayMacro(ctx context.Context, macroID, sessionID, adminUserID string) (*macro.Macro, error)
+}
+
+// recording comandos capturados de una sesión mientras se graba una macro
+type recording struct {
+	adminUserID    string
+	commands       []macro.Command
+	lastCapturedAt time.Time
+	discarded      int
+}
+
+// MacroService graba en memoria los comandos de input enviados durante una sesión, los guarda como
+// macro con nombre y los reproduce en otra sesión respetando las esperas originales entre comandos
+type MacroService struct {
+	macroRepository interfaces.IMacroRepository
+	inputValidator  ISessionInputValidator
+	commandSender   ICommandSender
+
+	recordings map[string]*recording // sessionID -> grabación en curso
+	mutex      sync.Mutex
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewMacroService crea una nueva instancia de MacroService
+func NewMacroService(
+	macroRepository interfaces.IMacroRepository,
+	inputValidator ISessionInputValidator,
+	commandSender ICommandSender,
+) *MacroService {
+	return &MacroService{
+		macroRepository: macroRepository,
+		inputValidator:  inputValidator,
+		commandSender:   commandSender,
+		recordings:      make(map[string]*recording),
+		now:             time.Now,
+		sleep:           sleepContext,
+	}
+}
+
+// StartRecording empieza a grabar los comandos de input de la sesión; reinicia una grabación previa
+func (s *MacroService) StartRecording(ctx context.Context, sessionID, adminUserID string) error {
+	if err := s.inputValidator.ValidateInputCommandPermission(ctx, sessionID, adminUserID); err != nil {
+		return fmt.Errorf("%w: %v", ErrInputNotPermitted, err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.recordings[sessionID] = &recording{adminUserID: adminUserID}
+	log.Printf("⏺️ MACRO: Recording started for session %s by admin %s", sessionID, adminUserID)
+	return nil
+}
+
+// CaptureCommand añade un comando ya reenviado al cliente a la grabación de la sesión, si la hay.
+// La espera se mide con el reloj del servidor desde el comando anterior.
+func (s *MacroService) CaptureCommand(sessionID string, command macro.Command) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rec, exists := s.recordings[sessionID]
+	if !exists {
+		return
+	}
+
+	if len(rec.commands) >= macro.MaxCommands {
+		rec.discarded++
+		return
+	}
+
+	now := s.now()
+	if len(rec.commands) > 0 {
+		command.Delay = now.Sub(rec.lastCapturedAt)
+	} else {
+		command.Delay = 0
+	}
+	rec.lastCapturedAt = now
+	rec.commands = append(rec.commands, command)
+}
+
+// SaveRecording termina la grabación de la sesión y la guarda como macro con el nombre indicado
+func (s *MacroService) SaveRecording(ctx context.Context, sessionID, adminUserID, name string) (*macro.Macro, error) {
+	s.mutex.Lock()
+	rec, exists := s.recordings[sessionID]
+	if !exists || rec.adminUserID != adminUserID {
+		s.mutex.Unlock()
+		return nil, ErrNoActiveRecording
+	}
+	commands := rec.commands
+	discarded := rec.discarded
+	s.mutex.Unlock()
+
+	m, err := macro.NewMacro(adminUserID, name, sessionID, commands)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.macroRepository.Save(ctx, m); err != nil {
+		return nil, fmt.Errorf("error saving macro: %w", err)
+	}
+
+	// La grabación solo se descarta cuando la macro quedó guardada, así un nombre inválido no la pierde
+	s.mutex.Lock()
+	if s.recordings[sessionID] == rec {
+		delete(s.recordings, sessionID)
+	}
+	s.mutex.Unlock()
+
+	if discarded > 0 {
+		log.Printf("⚠️ MACRO: %d commands beyond the %d limit were not recorded for macro %s", discarded, macro.MaxCommands, m.MacroID())
+	}
+	log.Printf("💾 MACRO: Saved macro %s (%q) with %d commands from session %s", m.MacroID(), m.Name(), m.CommandCount(), sessionID)
+	return m, nil
+}
+
+// DiscardRecording descarta sin guardar la grabación en curso de la sesión; se llama cuando la sesión termina
+func (s *MacroService) DiscardRecording(sessionID string) {
+	s.mutex.Lock()
+	rec, exists := s.recordings[sessionID]
+	delete(s.recordings, sessionID)
+	s.mutex.Unlock()
+
+	if exists {
+		log.Printf("🗑️ MACRO: Discarded unsaved recording of session %s (%d commands)", sessionID, len(rec.commands))
+	}
+}
+
+// DiscardAdminRecordings descarta sin guardar las grabaciones en curso del administrador; se llama cuando
+// cierra su última conexión. Retorna cuántas se descartaron.
+func (s *MacroService) DiscardAdminRecordings(adminUserID string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	discarded := 0
+	for sessionID, rec := range s.recordings {
+		if rec.adminUserID == adminUserID {
+			delete(s.recordings, sessionID)
+			discarded++
+		}
+	}
+	if discarded > 0 {
+		log.Printf("🗑️ MACRO: Discarded %d unsaved recording(s) of disconnected admin %s", discarded, adminUserID)
+	}
+	return discarded
+}
+
+// ListMacros obtiene las macros guardadas por el administrador
+func (s *MacroService) ListMacros(ctx context.Context, adminUserID string) ([]*macro.Macro, error) {
+	macros, err := s.macroRepository.FindByAdminUserID(ctx, adminUserID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing macros: %w", err)
+	}
+	return macros, nil
+}
+
+// ReplayMacro valida el permiso de input y reproduce la macro en segundo plano en la sesión indicada.
+// Cada comando se envía tras su espera original; la reproducción se detiene si un envío falla
+// (p. ej. porque la sesión terminó y el permiso ya no es válido).
+func (s *MacroService) ReplayMacro(ctx context.Context, macroID, sessionID, adminUserID string) (*macro.Macro, error) {
+	m, err := s.macroRepository.FindByID(ctx, macroID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding macro: %w", err)
+	}
+	if m == nil || m.AdminUserID() != adminUserID {
+		return nil, ErrMacroNotFound
+	}
+
+	if err := s.inputValidator.ValidateInputCommandPermission(ctx, sessionID, adminUserID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInputNotPermitted, err)
+	}
+
+	// La reproducción sobrevive a la petición HTTP que la inició
+	go s.replay(context.WithoutCancel(ctx), m, sessionID, adminUserID)
+
+	return m, nil
+}
+
+// replay envía los comandos de la macro en orden respetando las esperas
+func (s *MacroService) replay(ctx context.Context, m *macro.Macro, sessionID, adminUserID string) {
+	// Se ejecuta en su propia goroutine: un panic detiene la reproducción, no el servidor
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("💥 MACRO: Replay of macro %s on session %s panicked: %v\n%s", m.MacroID(), sessionID, recovered, debug.Stack())
+		}
+	}()
+
+	log.Printf("▶️ MACRO: Replaying macro %s (%d commands) on session %s", m.MacroID(), m.CommandCount(), sessionID)
+
+	for i, command := range m.Commands() {
+		if err := s.sleep(ctx, command.Delay); err != nil {
+			log.Printf("⚠️ MACRO: Replay of macro %s on session %s cancelled: %v", m.MacroID(), sessionID, err)
+			return
+		}
+
+		if err := s.commandSender.SendMacroCommand(ctx, adminUserID, sessionID, command); err != nil {
+			log.Printf("❌ MACRO: Replay of macro %s on session %s stopped at command %d/%d: %v",
+				m.MacroID(), sessionID, i+1, m.CommandCount(), err)
+			return
+		}
+	}
+
+	log.Printf("✅ MACRO: Replay of macro %s on session %s completed", m.MacroID(), sessionID)
+}
+
+// sleepContext espera d o hasta que se cancele el contexto
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,263 @@
+package macroservice
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/macro"
+)
+
+const (
+	testAdminID   = "admin-1"
+	testSessionID = "session-1"
+)
+
+// inMemoryMacroRepository guarda las macros en memoria
+type inMemoryMacroRepository struct {
+	macros map[string]*macro.Macro
+}
+
+func (r *inMemoryMacroRepository) Save(ctx context.Context, m *macro.Macro) error {
+	r.macros[m.MacroID()] = m
+	return nil
+}
+
+func (r *inMemoryMacroRepository) FindByID(ctx context.Context, macroID string) (*macro.Macro, error) {
+	return r.macros[macroID], nil
+}
+
+func (r *inMemoryMacroRepository) FindByAdminUserID(ctx context.Context, adminUserID string) ([]*macro.Macro, error) {
+	macros := make([]*macro.Macro, 0)
+	for _, m := range r.macros {
+		if m.AdminUserID() == adminUserID {
+			macros = append(macros, m)
+		}
+	}
+	return macros, nil
+}
+
+// stubInputValidator permite el input solo en las sesiones indicadas
+type stubInputValidator map[string]bool
+
+func (v stubInputValidator) ValidateInputCommandPermission(ctx context.Context, sessionID, adminUserID string) error {
+	if !v[sessionID] {
+		return errors.New("session is not active")
+	}
+	return nil
+}
+
+// spyCommandSender publica cada comando enviado; falla a partir de failAfter envíos si es > 0
+type spyCommandSender struct {
+	sent      chan macro.Command
+	failAfter int
+	count     int
+}
+
+func (s *spyCommandSender) SendMacroCommand(ctx context.Context, adminUserID, sessionID string, command macro.Command) error {
+	s.count++
+	if s.failAfter > 0 && s.count > s.failAfter {
+		close(s.sent)
+		return errors.New("session is not active")
+	}
+	s.sent <- command
+	return nil
+}
+
+// fakeClock reloj manual para medir las esperas entre comandos capturados
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// newTestMacroService crea el servicio con reloj manual y esperas registradas sin dormir
+func newTestMacroService(sender *spyCommandSender, activeSessions ...string) (*MacroService, *fakeClock, *[]time.Duration) {
+	validator := stubInputValidator{}
+	for _, sessionID := range activeSessions {
+		validator[sessionID] = true
+	}
+
+	service := NewMacroService(&inMemoryMacroRepository{macros: make(map[string]*macro.Macro)}, validator, sender)
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	service.now = clock.Now
+
+	var sleepsMutex sync.Mutex
+	sleeps := make([]time.Duration, 0)
+	service.sleep = func(ctx context.Context, d time.Duration) error {
+		sleepsMutex.Lock()
+		defer sleepsMutex.Unlock()
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	return service, clock, &sleeps
+}
+
+// recordTestMacro graba y guarda tres comandos separados por 100ms y 250ms
+func recordTestMacro(t *testing.T, service *MacroService, clock *fakeClock) (*macro.Macro, []macro.Command) {
+	t.Helper()
+
+	commands := []macro.Command{
+		{EventType: "mouse", Action: "click", Payload: map[string]interface{}{"x": 10.0, "y": 20.0, "button": "left"}},
+		{EventType: "keyboard", Action: "type", Payload: map[string]interface{}{"text": "ipconfig /flushdns"}},
+		{EventType: "keyboard", Action: "keydown", Payload: map[string]interface{}{"key": "Enter"}},
+	}
+
+	require.NoError(t, service.StartRecording(context.Background(), testSessionID, testAdminID))
+	service.CaptureCommand(testSessionID, commands[0])
+	clock.Advance(100 * time.Millisecond)
+	service.CaptureCommand(testSessionID, commands[1])
+	clock.Advance(250 * time.Millisecond)
+	service.CaptureCommand(testSessionID, commands[2])
+
+	saved, err := service.SaveRecording(context.Background(), testSessionID, testAdminID, "Flush DNS")
+	require.NoError(t, err)
+	return saved, commands
+}
+
+// receiveCommands espera n comandos enviados por la reproducción
+func receiveCommands(t *testing.T, sent <-chan macro.Command, n int) []macro.Command {
+	t.Helper()
+
+	received := make([]macro.Command, 0, n)
+	for len(received) < n {
+		select {
+		case command, ok := <-sent:
+			if !ok {
+				return received
+			}
+			received = append(received, command)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("replay sent %d of %d commands", len(received), n)
+		}
+	}
+	return received
+}
+
+func TestMacroService_RecordedMacroReplaysSameCommandsInOrder(t *testing.T) {
+	// Arrange
+	sender := &spyCommandSender{sent: make(chan macro.Command, 10)}
+	service, clock, sleeps := newTestMacroService(sender, testSessionID, "session-2")
+	saved, commands := recordTestMacro(t, service, clock)
+
+	// Act
+	replayed, err := service.ReplayMacro(context.Background(), saved.MacroID(), "session-2", testAdminID)
+	require.NoError(t, err)
+	received := receiveCommands(t, sender.sent, len(commands))
+
+	// Assert
+	assert.Equal(t, saved.MacroID(), replayed.MacroID())
+	require.Len(t, received, len(commands))
+	for i, command := range commands {
+		assert.Equal(t, command.EventType, received[i].EventType)
+		assert.Equal(t, command.Action, received[i].Action)
+		assert.Equal(t, command.Payload, received[i].Payload)
+	}
+	assert.Equal(t, []time.Duration{0, 100 * time.Millisecond, 250 * time.Millisecond}, *sleeps)
+	assert.Equal(t, 350*time.Millisecond, saved.Duration())
+}
+
+func TestMacroService_ReplayRequiresInputPermission(t *testing.T) {
+	// Arrange
+	sender := &spyCommandSender{sent: make(chan macro.Command, 10)}
+	service, clock, _ := newTestMacroService(sender, testSessionID)
+	saved, _ := recordTestMacro(t, service, clock)
+
+	// Act
+	_, err := service.ReplayMacro(context.Background(), saved.MacroID(), "session-ended", testAdminID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInputNotPermitted)
+	assert.Empty(t, sender.sent)
+}
+
+func TestMacroService_ReplayStopsWhenSendFails(t *testing.T) {
+	// Arrange - la sesión termina tras el primer comando
+	sender := &spyCommandSender{sent: make(chan macro.Command, 10), failAfter: 1}
+	service, clock, _ := newTestMacroService(sender, testSessionID, "session-2")
+	saved, _ := recordTestMacro(t, service, clock)
+
+	// Act
+	_, err := service.ReplayMacro(context.Background(), saved.MacroID(), "session-2", testAdminID)
+	require.NoError(t, err)
+	received := receiveCommands(t, sender.sent, saved.CommandCount())
+
+	// Assert
+	assert.Len(t, received, 1)
+}
+
+func TestMacroService_OtherAdminsMacrosAreNotFound(t *testing.T) {
+	// Arrange
+	sender := &spyCommandSender{sent: make(chan macro.Command, 10)}
+	service, clock, _ := newTestMacroService(sender, testSessionID)
+	saved, _ := recordTestMacro(t, service, clock)
+
+	// Act
+	_, err := service.ReplayMacro(context.Background(), saved.MacroID(), testSessionID, "admin-2")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrMacroNotFound)
+}
+
+func TestMacroService_SaveWithoutRecordingFails(t *testing.T) {
+	// Arrange
+	service, _, _ := newTestMacroService(&spyCommandSender{}, testSessionID)
+
+	// Act
+	_, err := service.SaveRecording(context.Background(), testSessionID, testAdminID, "Nothing")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNoActiveRecording)
+}
+
+func TestMacroService_InvalidNameKeepsRecording(t *testing.T) {
+	// Arrange
+	service, _, _ := newTestMacroService(&spyCommandSender{}, testSessionID)
+	require.NoError(t, service.StartRecording(context.Background(), testSessionID, testAdminID))
+	service.CaptureCommand(testSessionID, macro.Command{EventType: "mouse", Action: "click"})
+
+	// Act
+	_, invalidErr := service.SaveRecording(context.Background(), testSessionID, testAdminID, "  ")
+	saved, err := service.SaveRecording(context.Background(), testSessionID, testAdminID, "Click")
+
+	// Assert
+	assert.ErrorIs(t, invalidErr, macro.ErrEmptyName)
+	require.NoError(t, err)
+	assert.Equal(t, 1, saved.CommandCount())
+}
+
+func TestMacroService_DiscardRecordingOnSessionEnd(t *testing.T) {
+	// Arrange
+	service, _, _ := newTestMacroService(&spyCommandSender{}, testSessionID)
+	require.NoError(t, service.StartRecording(context.Background(), testSessionID, testAdminID))
+	service.CaptureCommand(testSessionID, macro.Command{EventType: "mouse", Action: "click"})
+
+	// Act
+	service.DiscardRecording(testSessionID)
+	service.CaptureCommand(testSessionID, macro.Command{EventType: "mouse", Action: "click"})
+	_, err := service.SaveRecording(context.Background(), testSessionID, testAdminID, "Click")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNoActiveRecording)
+	assert.Empty(t, service.recordings)
+}
+
+func TestMacroService_DiscardAdminRecordingsKeepsOtherAdmins(t *testing.T) {
+	// Arrange
+	service, _, _ := newTestMacroService(&spyCommandSender{}, testSessionID, "other-session")
+	require.NoError(t, service.StartRecording(context.Background(), testSessionID, testAdminID))
+	require.NoError(t, service.StartRecording(context.Background(), "other-session", "other-admin"))
+
+	// Act
+	discarded := service.DiscardAdminRecordings(testAdminID)
+
+	// Assert
+	assert.Equal(t, 1, discarded)
+	assert.NotContains(t, service.recordings, testSessionID)
+	assert.Contains(t, service.recordings, "other-session")
+}
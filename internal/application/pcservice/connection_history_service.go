@@ -0,0 +1,118 @@
+package pcservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/connectionsession"
+)
+
+// DefaultConnectionHistoryLimit es el número de registros devueltos si no se especifica límite
+const DefaultConnectionHistoryLimit = 50
+
+// IConnectionHistoryService defines the interface for recording PC connection sessions
+type IConnectionHistoryService interface {
+	RecordConnect(ctx context.Context, pcID, ipAddress string) (*connectionsession.ConnectionSession, error)
+	RecordDisconnect(ctx context.Context, session *connectionsession.ConnectionSession, reason string) error
+	GetConnectionHistory(ctx context.Context, pcID string, limit, offset int) ([]*connectionsession.ConnectionSession, error)
+	CountConnectionHistory(ctx context.Context, pcID string) (int, error)
+	CloseOpenConnections(ctx context.Context, pcID, reason string) (int, error)
+}
+
+// ConnectionHistoryService persists connect/disconnect cycles of client PCs for audit and troubleshooting
+type ConnectionHistoryService struct {
+	connectionRepository interfaces.IConnectionSessionRepository
+}
+
+// NewConnectionHistoryService creates a new instance of ConnectionHistoryService
+func NewConnectionHistoryService(connectionRepository interfaces.IConnectionSessionRepository) IConnectionHistoryService {
+	return &ConnectionHistoryService{
+		connectionRepository: connectionRepository,
+	}
+}
+
+// RecordConnect persists a new open connection session for the given PC
+func (s *ConnectionHistoryService) RecordConnect(ctx context.Context, pcID, ipAddress string) (*connectionsession.ConnectionSession, error) {
+	session, err := connectionsession.NewConnectionSession(pcID, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.connectionRepository.Save(ctx, session); err != nil {
+		return nil, fmt.Errorf("error saving connection session: %w", err)
+	}
+
+	return session, nil
+}
+
+// RecordDisconnect closes the connection session and persists the disconnect time and reason
+func (s *ConnectionHistoryService) RecordDisconnect(ctx context.Context, session *connectionsession.ConnectionSession, reason string) error {
+	if session == nil {
+		return errors.New("connection session cannot be nil")
+	}
+
+	if err := session.Close(reason); err != nil {
+		return err
+	}
+
+	if err := s.connectionRepository.Update(ctx, session); err != nil {
+		return fmt.Errorf("error updating connection session: %w", err)
+	}
+
+	return nil
+}
+
+// CloseOpenConnections closes every connection session of the PC still without a disconnect (e.g. the server lost
+// the socket without running its disconnect path) and returns how many were closed
+func (s *ConnectionHistoryService) CloseOpenConnections(ctx context.Context, pcID, reason string) (int, error) {
+	sessions, err := s.connectionRepository.FindOpenByPCID(ctx, pcID)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving open connection sessions: %w", err)
+	}
+
+	closed := 0
+	for _, session := range sessions {
+		if err := s.RecordDisconnect(ctx, session, reason); err != nil {
+			return closed, err
+		}
+		closed++
+	}
+	return closed, nil
+}
+
+// GetConnectionHistory retrieves the connection history of a PC, most recent first
+func (s *ConnectionHistoryService) GetConnectionHistory(ctx context.Context, pcID string, limit, offset int) ([]*connectionsession.ConnectionSession, error) {
+	if pcID == "" {
+		return nil, errors.New("PC ID cannot be empty")
+	}
+
+	if limit <= 0 {
+		limit = DefaultConnectionHistoryLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	sessions, err := s.connectionRepository.FindByPCID(ctx, pcID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving connection history: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// CountConnectionHistory returns the total number of recorded connections of a PC
+func (s *ConnectionHistoryService) CountConnectionHistory(ctx context.Context, pcID string) (int, error) {
+	if pcID == "" {
+		return 0, errors.New("PC ID cannot be empty")
+	}
+
+	count, err := s.connectionRepository.CountByPCID(ctx, pcID)
+	if err != nil {
+		return 0, fmt.Errorf("error counting connection history: %w", err)
+	}
+
+	return count, nil
+}
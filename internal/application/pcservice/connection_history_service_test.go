@@ -0,0 +1,211 @@
+package pcservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/connectionsession"
+)
+
+// MockConnectionSessionRepository es un mock del repositorio de historial de conexiones
+type MockConnectionSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockConnectionSessionRepository) Save(ctx context.Context, session *connectionsession.ConnectionSession) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockConnectionSessionRepository) Update(ctx context.Context, session *connectionsession.ConnectionSession) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockConnectionSessionRepository) FindByID(ctx context.Context, connectionID string) (*connectionsession.ConnectionSession, error) {
+	args := m.Called(ctx, connectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*connectionsession.ConnectionSession), args.Error(1)
+}
+
+func (m *MockConnectionSessionRepository) FindByPCID(ctx context.Context, pcID string, limit, offset int) ([]*connectionsession.ConnectionSession, error) {
+	args := m.Called(ctx, pcID, limit, offset)
+	return args.Get(0).([]*connectionsession.ConnectionSession), args.Error(1)
+}
+
+func (m *MockConnectionSessionRepository) CountByPCID(ctx context.Context, pcID string) (int, error) {
+	args := m.Called(ctx, pcID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockConnectionSessionRepository) FindOpenByPCID(ctx context.Context, pcID string) ([]*connectionsession.ConnectionSession, error) {
+	args := m.Called(ctx, pcID)
+	return args.Get(0).([]*connectionsession.ConnectionSession), args.Error(1)
+}
+
+func TestConnectionHistoryService_ConnectDisconnectCycle(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockConnectionSessionRepository)
+	service := NewConnectionHistoryService(mockRepo)
+
+	ctx := context.Background()
+	pcID := "550e8400-e29b-41d4-a716-446655440001"
+	ip := "192.168.1.100"
+
+	var saved, updated *connectionsession.ConnectionSession
+	mockRepo.On("Save", ctx, mock.AnythingOfType("*connectionsession.ConnectionSession")).
+		Run(func(args mock.Arguments) {
+			s := args.Get(1).(*connectionsession.ConnectionSession)
+			// Copiar el estado en el momento del INSERT
+			saved = connectionsession.NewConnectionSessionFromDB(
+				s.ConnectionID(), s.PCID(), s.IPAddress(), s.ConnectedAt(), s.DisconnectedAt(), s.DisconnectReason())
+		}).Return(nil)
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*connectionsession.ConnectionSession")).
+		Run(func(args mock.Arguments) {
+			updated = args.Get(1).(*connectionsession.ConnectionSession)
+		}).Return(nil)
+
+	// Act
+	session, err := service.RecordConnect(ctx, pcID, ip)
+	assert.NoError(t, err)
+	err = service.RecordDisconnect(ctx, session, "connection closed")
+
+	// Assert
+	assert.NoError(t, err)
+
+	assert.NotNil(t, saved)
+	assert.Equal(t, pcID, saved.PCID())
+	assert.Equal(t, ip, saved.IPAddress())
+	assert.False(t, saved.ConnectedAt().IsZero())
+	assert.Nil(t, saved.DisconnectedAt())
+	assert.Empty(t, saved.DisconnectReason())
+
+	assert.NotNil(t, updated)
+	assert.Equal(t, saved.ConnectionID(), updated.ConnectionID())
+	assert.NotNil(t, updated.DisconnectedAt())
+	assert.False(t, updated.DisconnectedAt().Before(updated.ConnectedAt()))
+	assert.Equal(t, "connection closed", updated.DisconnectReason())
+	assert.False(t, updated.IsOpen())
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestConnectionHistoryService_RecordConnect_EmptyPCID(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockConnectionSessionRepository)
+	service := NewConnectionHistoryService(mockRepo)
+
+	// Act
+	session, err := service.RecordConnect(context.Background(), "", "192.168.1.100")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, session)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestConnectionHistoryService_RecordDisconnect_AlreadyClosed(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockConnectionSessionRepository)
+	service := NewConnectionHistoryService(mockRepo)
+
+	ctx := context.Background()
+	session, _ := connectionsession.NewConnectionSession("550e8400-e29b-41d4-a716-446655440001", "192.168.1.100")
+	mockRepo.On("Update", ctx, session).Return(nil).Once()
+
+	// Act
+	firstErr := service.RecordDisconnect(ctx, session, "connection closed")
+	secondErr := service.RecordDisconnect(ctx, session, "connection closed")
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.Error(t, secondErr)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestConnectionHistoryService_RecordDisconnect_TruncatesLongReason(t *testing.T) {
+	// Arrange - el motivo puede traer el texto completo de un error de red
+	mockRepo := new(MockConnectionSessionRepository)
+	service := NewConnectionHistoryService(mockRepo)
+
+	ctx := context.Background()
+	session, _ := connectionsession.NewConnectionSession("550e8400-e29b-41d4-a716-446655440001", "192.168.1.100")
+	mockRepo.On("Update", ctx, session).Return(nil).Once()
+
+	// Act
+	err := service.RecordDisconnect(ctx, session, strings.Repeat("é", 300))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("é", connectionsession.MaxDisconnectReasonLength), session.DisconnectReason())
+}
+
+func TestConnectionHistoryService_GetConnectionHistory_DefaultLimit(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockConnectionSessionRepository)
+	service := NewConnectionHistoryService(mockRepo)
+
+	ctx := context.Background()
+	pcID := "550e8400-e29b-41d4-a716-446655440001"
+	mockRepo.On("FindByPCID", ctx, pcID, DefaultConnectionHistoryLimit, 0).
+		Return([]*connectionsession.ConnectionSession{}, nil)
+
+	// Act
+	sessions, err := service.GetConnectionHistory(ctx, pcID, 0, -1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, sessions)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestConnectionHistoryService_GetConnectionHistory_RepositoryError(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockConnectionSessionRepository)
+	service := NewConnectionHistoryService(mockRepo)
+
+	ctx := context.Background()
+	pcID := "550e8400-e29b-41d4-a716-446655440001"
+	mockRepo.On("FindByPCID", ctx, pcID, 10, 0).
+		Return([]*connectionsession.ConnectionSession(nil), errors.New("database error"))
+
+	// Act
+	sessions, err := service.GetConnectionHistory(ctx, pcID, 10, 0)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, sessions)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestConnectionHistoryService_CloseOpenConnections_ClosesEveryOpenSession(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockConnectionSessionRepository)
+	service := NewConnectionHistoryService(mockRepo)
+
+	ctx := context.Background()
+	pcID := "550e8400-e29b-41d4-a716-446655440001"
+	first, err := connectionsession.NewConnectionSession(pcID, "192.168.1.10")
+	require.NoError(t, err)
+	second, err := connectionsession.NewConnectionSession(pcID, "192.168.1.11")
+	require.NoError(t, err)
+	mockRepo.On("FindOpenByPCID", ctx, pcID).Return([]*connectionsession.ConnectionSession{first, second}, nil)
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*connectionsession.ConnectionSession")).Return(nil).Twice()
+
+	// Act
+	closed, err := service.CloseOpenConnections(ctx, pcID, "reconciled")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, closed)
+	assert.False(t, first.IsOpen())
+	assert.Equal(t, "reconciled", second.DisconnectReason())
+	mockRepo.AssertExpectations(t)
+}
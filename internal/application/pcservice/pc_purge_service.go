@@ -0,0 +1,280 @@
+package pcservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+// ErrPCHasActiveSession indica que el PC tiene una sesión de control en curso y no se puede purgar
+var ErrPCHasActiveSession = errors.New("PC has an active remote session")
+
+// transferUploadDir carpeta, en el almacenamiento y en temp/, donde FileTransferHandler deja los archivos subidos
+// de cada sesión para enviarlos al PC
+const transferUploadDir = "file_transfers"
+
+// PCPurgeSummary resultado de purgar los datos de un PC dado de baja
+type PCPurgeSummary struct {
+	PCID               string   `json:"pc_id"`
+	Identifier         string   `json:"identifier"`
+	RecordingsDeleted  int      `json:"recordings_deleted"`
+	TransfersDeleted   int      `json:"transfers_deleted"`
+	SessionsArchived   int      `json:"sessions_archived"`
+	DirectoriesDeleted int      `json:"directories_deleted"`
+	Warnings           []string `json:"warnings,omitempty"`
+}
+
+// RecordingArtifactCleaner elimina los directorios de trabajo de las grabaciones (frames y subidas en curso)
+type RecordingArtifactCleaner interface {
+	RemoveRecordingArtifacts(sessionIDs, videoIDs []string) (int, error)
+}
+
+// IPCPurgeService elimina todos los datos asociados a un PC que se retira
+type IPCPurgeService interface {
+	PurgePC(ctx context.Context, pcID, adminUserID string) (*PCPurgeSummary, error)
+}
+
+// PCPurgeService borra grabaciones, transferencias, sesiones y el registro de un PC en una sola operación
+type PCPurgeService struct {
+	pcRepository           interfaces.IClientPCRepository
+	sessionRepository      interfaces.IRemoteSessionRepository
+	videoRepository        interfaces.ISessionVideoRepository
+	fileTransferRepository interfaces.IFileTransferRepository
+	fileStorage            interfaces.IFileStorage
+	actionLogService       actionlogservice.IActionLogService
+	// Opcional: frames y subidas en curso de las grabaciones (nil = solo se borran los archivos de session_videos)
+	recordingArtifacts RecordingArtifactCleaner
+	// transferTempDir respaldo local donde se suben los archivos a transferir si no hay almacenamiento
+	transferTempDir string
+}
+
+// NewPCPurgeService creates a new instance of PCPurgeService
+func NewPCPurgeService(
+	pcRepository interfaces.IClientPCRepository,
+	sessionRepository interfaces.IRemoteSessionRepository,
+	videoRepository interfaces.ISessionVideoRepository,
+	fileTransferRepository interfaces.IFileTransferRepository,
+	fileStorage interfaces.IFileStorage,
+	actionLogService actionlogservice.IActionLogService,
+) IPCPurgeService {
+	return &PCPurgeService{
+		pcRepository:           pcRepository,
+		sessionRepository:      sessionRepository,
+		videoRepository:        videoRepository,
+		fileTransferRepository: fileTransferRepository,
+		fileStorage:            fileStorage,
+		actionLogService:       actionLogService,
+		transferTempDir:        filepath.Join("temp", transferUploadDir),
+	}
+}
+
+// SetRecordingArtifactCleaner configura quién elimina los frames y las subidas en curso de las grabaciones
+func (s *PCPurgeService) SetRecordingArtifactCleaner(cleaner RecordingArtifactCleaner) {
+	s.recordingArtifacts = cleaner
+}
+
+// PurgePC elimina las grabaciones (archivos y filas), las transferencias y las sesiones del PC y, por último,
+// el propio PC. Los repositorios no comparten transacción, así que se borra en orden de dependencias y el PC
+// va al final: si un paso falla, el PC sigue existiendo y la purga puede repetirse sin efectos duplicados.
+// Todo lo que se va a borrar se lee y las sesiones se archivan antes del primer borrado; la entrada PC_PURGED del
+// audit log se registra también si la purga se interrumpe a medias, con lo que se llegó a borrar y el error.
+// El borrado de cada sesión vuelve a comprobar que no esté en curso, por si el PC recibió una solicitud durante
+// la purga.
+func (s *PCPurgeService) PurgePC(ctx context.Context, pcID, adminUserID string) (*PCPurgeSummary, error) {
+	if pcID == "" {
+		return nil, errors.New("PC ID cannot be empty")
+	}
+
+	pc, err := s.pcRepository.FindByID(ctx, pcID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding PC: %w", err)
+	}
+	if pc == nil {
+		return nil, ErrPCNotFound
+	}
+
+	sessions, err := s.sessionRepository.FindByClientPCID(ctx, pcID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding sessions of PC: %w", err)
+	}
+	for _, session := range sessions {
+		switch session.Status() {
+		case remotesession.StatusActive, remotesession.StatusPendingApproval, remotesession.StatusQueued:
+			return nil, fmt.Errorf("%w: %s", ErrPCHasActiveSession, session.SessionID())
+		}
+	}
+
+	summary := &PCPurgeSummary{PCID: pcID, Identifier: pc.Identifier}
+
+	var videos []*sessionvideo.SessionVideo
+	sessionIDs := make([]string, 0, len(sessions))
+	archivedSessions := make([]map[string]interface{}, 0, len(sessions))
+	for _, session := range sessions {
+		sessionVideos, err := s.videoRepository.FindBySessionID(ctx, session.SessionID())
+		if err != nil {
+			return summary, fmt.Errorf("error finding recordings of session %s: %w", session.SessionID(), err)
+		}
+		videos = append(videos, sessionVideos...)
+		sessionIDs = append(sessionIDs, session.SessionID())
+		archivedSessions = append(archivedSessions, archiveSession(session))
+	}
+
+	// Transferencias: referencian tanto al PC como a sus sesiones
+	transfers, err := s.fileTransferRepository.FindByTargetPCID(ctx, pcID)
+	if err != nil {
+		return summary, fmt.Errorf("error finding transfers of PC: %w", err)
+	}
+
+	if err := s.deletePCData(ctx, summary, sessionIDs, videos, transfers); err != nil {
+		s.logPurge(ctx, summary, adminUserID, archivedSessions, err)
+		log.Printf("⚠️ PC PURGE: Purge of PC %s (%s) by admin %s interrupted after %d recordings, %d transfers, %d sessions: %v",
+			pcID, pc.Identifier, adminUserID, summary.RecordingsDeleted, summary.TransfersDeleted, summary.SessionsArchived, err)
+		return summary, err
+	}
+
+	s.logPurge(ctx, summary, adminUserID, archivedSessions, nil)
+	log.Printf("🗑️ PC PURGE: PC %s (%s) purged by admin %s: %d recordings, %d transfers, %d sessions",
+		pcID, pc.Identifier, adminUserID, summary.RecordingsDeleted, summary.TransfersDeleted, summary.SessionsArchived)
+	return summary, nil
+}
+
+// deletePCData borra en orden de dependencias las grabaciones, las transferencias, las sesiones y el PC,
+// contando en summary lo que se borra; se detiene en el primer borrado de fila que falla
+func (s *PCPurgeService) deletePCData(ctx context.Context, summary *PCPurgeSummary, sessionIDs []string,
+	videos []*sessionvideo.SessionVideo, transfers []*filetransfer.FileTransfer) error {
+	// Grabaciones: primero los directorios de trabajo y el archivo, luego la fila; un archivo que ya no existe no
+	// impide la purga
+	s.removeRecordingArtifacts(summary, sessionIDs, videos)
+	for _, video := range videos {
+		if video.FilePath() != "" {
+			if err := s.fileStorage.DeleteFile(ctx, video.FilePath()); err != nil {
+				summary.Warnings = append(summary.Warnings, fmt.Sprintf("recording file %s: %v", video.FilePath(), err))
+			}
+		}
+		if err := s.videoRepository.Delete(ctx, video.VideoID()); err != nil {
+			return fmt.Errorf("error deleting recording %s: %w", video.VideoID(), err)
+		}
+		summary.RecordingsDeleted++
+	}
+
+	for _, transfer := range transfers {
+		if err := s.fileTransferRepository.Delete(ctx, transfer.TransferID()); err != nil {
+			return fmt.Errorf("error deleting transfer %s: %w", transfer.TransferID(), err)
+		}
+		summary.TransfersDeleted++
+	}
+	s.removeTransferUploads(summary, sessionIDs)
+
+	for _, sessionID := range sessionIDs {
+		if err := s.sessionRepository.Delete(ctx, sessionID); err != nil {
+			if errors.Is(err, remotesession.ErrSessionInProgress) {
+				return fmt.Errorf("%w: %v", ErrPCHasActiveSession, err)
+			}
+			return fmt.Errorf("error deleting session %s: %w", sessionID, err)
+		}
+		summary.SessionsArchived++
+	}
+
+	// El historial de conexiones y los PCs fijados se eliminan en cascada con el PC
+	if err := s.pcRepository.Delete(ctx, summary.PCID); err != nil {
+		return fmt.Errorf("error deleting PC: %w", err)
+	}
+	return nil
+}
+
+// removeRecordingArtifacts elimina los frames y las subidas en curso de las grabaciones de las sesiones del PC,
+// también las que aún no tienen fila en session_videos
+func (s *PCPurgeService) removeRecordingArtifacts(summary *PCPurgeSummary, sessionIDs []string, videos []*sessionvideo.SessionVideo) {
+	if s.recordingArtifacts == nil {
+		return
+	}
+	videoIDs := make([]string, 0, len(videos))
+	for _, video := range videos {
+		videoIDs = append(videoIDs, video.VideoID())
+	}
+	removed, err := s.recordingArtifacts.RemoveRecordingArtifacts(sessionIDs, videoIDs)
+	summary.DirectoriesDeleted += removed
+	if err != nil {
+		summary.Warnings = append(summary.Warnings, fmt.Sprintf("recording directories: %v", err))
+	}
+}
+
+// removeTransferUploads elimina los archivos subidos para las transferencias de cada sesión, tanto en el
+// almacenamiento como en el respaldo local de temp/
+func (s *PCPurgeService) removeTransferUploads(summary *PCPurgeSummary, sessionIDs []string) {
+	for _, sessionID := range sessionIDs {
+		dirs := []string{filepath.Join(s.transferTempDir, sessionID)}
+		if s.fileStorage != nil {
+			dirs = append(dirs, s.fileStorage.GetFilePath(filepath.Join(transferUploadDir, sessionID)))
+		}
+		for _, dir := range dirs {
+			if _, err := os.Stat(dir); err != nil {
+				continue
+			}
+			if err := os.RemoveAll(dir); err != nil {
+				summary.Warnings = append(summary.Warnings, fmt.Sprintf("transfer uploads %s: %v", dir, err))
+				continue
+			}
+			summary.DirectoriesDeleted++
+		}
+	}
+}
+
+// logPurge registra la purga con el resumen y las sesiones archivadas; si purgeErr no es nil la purga quedó a
+// medias y se registra como incompleta con el error. Un fallo del audit log no revierte la purga.
+func (s *PCPurgeService) logPurge(ctx context.Context, summary *PCPurgeSummary, adminUserID string, archivedSessions []map[string]interface{}, purgeErr error) {
+	if s.actionLogService == nil {
+		return
+	}
+
+	entityType := "CLIENT_PC"
+	details := map[string]interface{}{
+		"identifier":          summary.Identifier,
+		"recordings_deleted":  summary.RecordingsDeleted,
+		"transfers_deleted":   summary.TransfersDeleted,
+		"directories_deleted": summary.DirectoriesDeleted,
+		"sessions":            archivedSessions,
+		"sessions_archived":   summary.SessionsArchived,
+		"completed":           purgeErr == nil,
+	}
+	if len(summary.Warnings) > 0 {
+		details["warnings"] = summary.Warnings
+	}
+
+	description := fmt.Sprintf("PC %s purged on decommission", summary.Identifier)
+	if purgeErr != nil {
+		details["error"] = purgeErr.Error()
+		description = fmt.Sprintf("PC %s purge on decommission interrupted", summary.Identifier)
+	}
+	if err := s.actionLogService.LogAction(ctx, actionlog.ActionPCPurged, description, adminUserID, &summary.PCID, &entityType, details); err != nil {
+		log.Printf("⚠️ Warning: Failed to log PC purge audit entry: %v", err)
+	}
+}
+
+// archiveSession datos de la sesión que se conservan en el audit log una vez borrada
+func archiveSession(session *remotesession.RemoteSession) map[string]interface{} {
+	archived := map[string]interface{}{
+		"session_id":    session.SessionID(),
+		"admin_user_id": session.AdminUserID(),
+		"status":        string(session.Status()),
+		"created_at":    session.CreatedAt().Format(time.RFC3339),
+	}
+	if session.StartTime() != nil {
+		archived["start_time"] = session.StartTime().Format(time.RFC3339)
+	}
+	if session.EndTime() != nil {
+		archived["end_time"] = session.EndTime().Format(time.RFC3339)
+	}
+	return archived
+}
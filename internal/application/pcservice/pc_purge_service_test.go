@@ -0,0 +1,317 @@
+package pcservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+const (
+	testPurgePCID    = "550e8400-e29b-41d4-a716-446655440020"
+	testPurgeOwnerID = "550e8400-e29b-41d4-a716-446655440021"
+	testPurgeAdminID = "550e8400-e29b-41d4-a716-446655440022"
+)
+
+// inMemorySessionRepository sesiones del PC en memoria; el resto del repositorio no se usa
+type inMemorySessionRepository struct {
+	interfaces.IRemoteSessionRepository
+	sessions map[string]*remotesession.RemoteSession
+	// startedDuringPurge sesiones que pasan a estar en curso antes de borrarlas
+	startedDuringPurge map[string]bool
+	// deleteErr error con el que falla el borrado de cualquier sesión
+	deleteErr error
+}
+
+func (r *inMemorySessionRepository) FindByClientPCID(ctx context.Context, clientPCID string) ([]*remotesession.RemoteSession, error) {
+	var sessions []*remotesession.RemoteSession
+	for _, session := range r.sessions {
+		if session.ClientPCID() == clientPCID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *inMemorySessionRepository) Delete(ctx context.Context, id string) error {
+	if r.startedDuringPurge[id] {
+		return fmt.Errorf("%w: session %s", remotesession.ErrSessionInProgress, id)
+	}
+	if r.deleteErr != nil {
+		return r.deleteErr
+	}
+	delete(r.sessions, id)
+	return nil
+}
+
+// inMemoryVideoRepository grabaciones en memoria indexadas por videoID
+type inMemoryVideoRepository struct {
+	interfaces.ISessionVideoRepository
+	videos map[string]*sessionvideo.SessionVideo
+}
+
+func (r *inMemoryVideoRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*sessionvideo.SessionVideo, error) {
+	var videos []*sessionvideo.SessionVideo
+	for _, video := range r.videos {
+		if video.AssociatedSessionID() == sessionID {
+			videos = append(videos, video)
+		}
+	}
+	return videos, nil
+}
+
+func (r *inMemoryVideoRepository) Delete(ctx context.Context, videoID string) error {
+	delete(r.videos, videoID)
+	return nil
+}
+
+// inMemoryTransferRepository transferencias en memoria indexadas por transferID
+type inMemoryTransferRepository struct {
+	interfaces.IFileTransferRepository
+	transfers map[string]*filetransfer.FileTransfer
+}
+
+func (r *inMemoryTransferRepository) FindByTargetPCID(ctx context.Context, targetPCID string) ([]*filetransfer.FileTransfer, error) {
+	var transfers []*filetransfer.FileTransfer
+	for _, transfer := range r.transfers {
+		if transfer.TargetPCID() == targetPCID {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers, nil
+}
+
+func (r *inMemoryTransferRepository) Delete(ctx context.Context, transferID string) error {
+	delete(r.transfers, transferID)
+	return nil
+}
+
+// diskFileStorage borra archivos reales del disco bajo root
+type diskFileStorage struct {
+	interfaces.IFileStorage
+	root string
+}
+
+func (s diskFileStorage) DeleteFile(ctx context.Context, filePath string) error {
+	return os.Remove(filePath)
+}
+
+func (s diskFileStorage) GetFilePath(relativePath string) string {
+	return filepath.Join(s.root, relativePath)
+}
+
+// spyArtifactCleaner registra las sesiones y grabaciones cuyos directorios se piden eliminar
+type spyArtifactCleaner struct {
+	sessionIDs []string
+	videoIDs   []string
+}
+
+func (c *spyArtifactCleaner) RemoveRecordingArtifacts(sessionIDs, videoIDs []string) (int, error) {
+	c.sessionIDs, c.videoIDs = sessionIDs, videoIDs
+	return len(videoIDs), nil
+}
+
+// spyPurgeAuditLog registra las entradas del audit log; el resto del servicio no se usa
+type spyPurgeAuditLog struct {
+	actionlogservice.IActionLogService
+	actionTypes []actionlog.ActionType
+	details     []map[string]interface{}
+}
+
+func (l *spyPurgeAuditLog) LogAction(ctx context.Context, actionType actionlog.ActionType, description string,
+	performedByUserID string, subjectEntityID *string, subjectEntityType *string, details map[string]interface{}) error {
+	l.actionTypes = append(l.actionTypes, actionType)
+	l.details = append(l.details, details)
+	return nil
+}
+
+// purgeFixture PC con una sesión terminada, su grabación en disco y una transferencia
+type purgeFixture struct {
+	service      IPCPurgeService
+	pcRepo       *MockClientPCRepository
+	sessionRepo  *inMemorySessionRepository
+	videoRepo    *inMemoryVideoRepository
+	transferRepo *inMemoryTransferRepository
+	videoPath    string
+	session      *remotesession.RemoteSession
+	video        *sessionvideo.SessionVideo
+	storageRoot  string
+}
+
+func newPurgeFixture(t *testing.T) *purgeFixture {
+	t.Helper()
+
+	pc, err := clientpc.NewClientPC(testPurgePCID, "retired-pc", "192.168.1.120", testPurgeOwnerID)
+	require.NoError(t, err)
+	pcRepo := new(MockClientPCRepository)
+	pcRepo.On("FindByID", mock.Anything, testPurgePCID).Return(pc, nil)
+
+	session, err := remotesession.NewRemoteSession(testPurgeAdminID, testPurgePCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+	require.NoError(t, session.End(remotesession.StatusEndedByAdmin))
+
+	videoPath := filepath.Join(t.TempDir(), "session.mp4")
+	require.NoError(t, os.WriteFile(videoPath, []byte("mp4"), 0644))
+	video := sessionvideo.NewSessionVideo(videoPath, 60, session.SessionID(), 1)
+	transfer := filetransfer.NewFileTransfer("report.pdf", "/srv/files/report.pdf", "C:/Downloads/report.pdf",
+		session.SessionID(), testPurgeAdminID, testPurgePCID, 2)
+
+	fixture := &purgeFixture{
+		pcRepo:       pcRepo,
+		sessionRepo:  &inMemorySessionRepository{sessions: map[string]*remotesession.RemoteSession{session.SessionID(): session}},
+		videoRepo:    &inMemoryVideoRepository{videos: map[string]*sessionvideo.SessionVideo{video.VideoID(): video}},
+		transferRepo: &inMemoryTransferRepository{transfers: map[string]*filetransfer.FileTransfer{transfer.TransferID(): transfer}},
+		videoPath:    videoPath,
+		session:      session,
+		video:        video,
+		storageRoot:  t.TempDir(),
+	}
+	service := NewPCPurgeService(pcRepo, fixture.sessionRepo, fixture.videoRepo, fixture.transferRepo,
+		diskFileStorage{root: fixture.storageRoot}, nil).(*PCPurgeService)
+	service.transferTempDir = filepath.Join(t.TempDir(), "temp", transferUploadDir)
+	fixture.service = service
+	return fixture
+}
+
+func TestPCPurgeService_PurgePC_RemovesAllAssociatedArtifacts(t *testing.T) {
+	// Arrange
+	fixture := newPurgeFixture(t)
+	fixture.pcRepo.On("Delete", mock.Anything, testPurgePCID).Return(nil)
+
+	// Act
+	summary, err := fixture.service.PurgePC(context.Background(), testPurgePCID, testPurgeAdminID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, &PCPurgeSummary{
+		PCID:              testPurgePCID,
+		Identifier:        "retired-pc",
+		RecordingsDeleted: 1,
+		TransfersDeleted:  1,
+		SessionsArchived:  1,
+	}, summary)
+	assert.Empty(t, fixture.sessionRepo.sessions)
+	assert.Empty(t, fixture.videoRepo.videos)
+	assert.Empty(t, fixture.transferRepo.transfers)
+	_, statErr := os.Stat(fixture.videoPath)
+	assert.True(t, os.IsNotExist(statErr))
+	fixture.pcRepo.AssertCalled(t, "Delete", mock.Anything, testPurgePCID)
+}
+
+func TestPCPurgeService_PurgePC_RemovesRecordingAndTransferDirectories(t *testing.T) {
+	// Arrange - archivos subidos para la sesión en el almacenamiento y en el respaldo de temp/
+	fixture := newPurgeFixture(t)
+	fixture.pcRepo.On("Delete", mock.Anything, testPurgePCID).Return(nil)
+	service := fixture.service.(*PCPurgeService)
+	cleaner := &spyArtifactCleaner{}
+	service.SetRecordingArtifactCleaner(cleaner)
+
+	storedUploads := filepath.Join(fixture.storageRoot, transferUploadDir, fixture.session.SessionID())
+	tempUploads := filepath.Join(service.transferTempDir, fixture.session.SessionID())
+	for _, dir := range []string{storedUploads, tempUploads} {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "report.pdf"), []byte("pdf"), 0644))
+	}
+
+	// Act
+	summary, err := fixture.service.PurgePC(context.Background(), testPurgePCID, testPurgeAdminID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{fixture.session.SessionID()}, cleaner.sessionIDs)
+	assert.Equal(t, []string{fixture.video.VideoID()}, cleaner.videoIDs)
+	assert.NoDirExists(t, storedUploads)
+	assert.NoDirExists(t, tempUploads)
+	assert.Equal(t, 3, summary.DirectoriesDeleted)
+	assert.Empty(t, summary.Warnings)
+}
+
+func TestPCPurgeService_PurgePC_SessionStartedDuringPurgeKeepsPC(t *testing.T) {
+	// Arrange - el DELETE vuelve a comprobar el estado y encuentra la sesión en curso
+	fixture := newPurgeFixture(t)
+	fixture.sessionRepo.startedDuringPurge = map[string]bool{fixture.session.SessionID(): true}
+
+	// Act
+	_, err := fixture.service.PurgePC(context.Background(), testPurgePCID, testPurgeAdminID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrPCHasActiveSession)
+	assert.Len(t, fixture.sessionRepo.sessions, 1)
+	fixture.pcRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestPCPurgeService_PurgePC_AuditsPartialPurgeWhenSessionDeleteFails(t *testing.T) {
+	// Arrange - la grabación y la transferencia ya se borraron cuando falla el borrado de la sesión
+	fixture := newPurgeFixture(t)
+	fixture.sessionRepo.deleteErr = errors.New("connection reset")
+	auditLog := &spyPurgeAuditLog{}
+	fixture.service.(*PCPurgeService).actionLogService = auditLog
+
+	// Act
+	summary, err := fixture.service.PurgePC(context.Background(), testPurgePCID, testPurgeAdminID)
+
+	// Assert - el PC se conserva y la purga incompleta queda en el audit log con la sesión archivada
+	assert.ErrorContains(t, err, "connection reset")
+	assert.Equal(t, 1, summary.RecordingsDeleted)
+	assert.Equal(t, 1, summary.TransfersDeleted)
+	assert.Zero(t, summary.SessionsArchived)
+	fixture.pcRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+
+	require.Equal(t, []actionlog.ActionType{actionlog.ActionPCPurged}, auditLog.actionTypes)
+	details := auditLog.details[0]
+	assert.Equal(t, false, details["completed"])
+	assert.Contains(t, details["error"], "connection reset")
+	assert.Equal(t, 1, details["recordings_deleted"])
+	archived := details["sessions"].([]map[string]interface{})
+	require.Len(t, archived, 1)
+	assert.Equal(t, fixture.session.SessionID(), archived[0]["session_id"])
+}
+
+func TestPCPurgeService_PurgePC_RejectsPCWithActiveSession(t *testing.T) {
+	// Arrange
+	fixture := newPurgeFixture(t)
+	active, err := remotesession.NewRemoteSession(testPurgeAdminID, testPurgePCID)
+	require.NoError(t, err)
+	require.NoError(t, active.Accept())
+	fixture.sessionRepo.sessions[active.SessionID()] = active
+
+	// Act
+	_, err = fixture.service.PurgePC(context.Background(), testPurgePCID, testPurgeAdminID)
+
+	// Assert - no se borra nada
+	assert.ErrorIs(t, err, ErrPCHasActiveSession)
+	assert.Len(t, fixture.sessionRepo.sessions, 2)
+	assert.Len(t, fixture.videoRepo.videos, 1)
+	assert.FileExists(t, fixture.videoPath)
+	fixture.pcRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestPCPurgeService_PurgePC_RejectsPCWithQueuedSession(t *testing.T) {
+	// Arrange - la solicitud en cola se entregaría al PC purgado cuando vuelva a registrarse
+	fixture := newPurgeFixture(t)
+	queued, err := remotesession.NewQueuedRemoteSession(testPurgeAdminID, testPurgePCID)
+	require.NoError(t, err)
+	fixture.sessionRepo.sessions[queued.SessionID()] = queued
+
+	// Act
+	_, err = fixture.service.PurgePC(context.Background(), testPurgePCID, testPurgeAdminID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrPCHasActiveSession)
+	assert.Len(t, fixture.sessionRepo.sessions, 2)
+	fixture.pcRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
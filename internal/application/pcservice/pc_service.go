@@ -11,7 +11,8 @@ import (
 
 // IPCService defines the interface for PC-related business operations
 type IPCService interface {
-	RegisterPC(ctx context.Context, ownerUserID, pcIdentifier, ip string) (*clientpc.ClientPC, error)
+	RegisterPC(ctx context.Context, ownerUserID, pcIdentifier, ip string, metadata clientpc.PCMetadata) (*clientpc.ClientPC, error)
+	MarkPCConnecting(ctx context.Context, ownerUserID, pcIdentifier string) (*clientpc.ClientPC, error)
 	GetPCByID(ctx context.Context, pcID string) (*clientpc.ClientPC, error)
 	GetPCsByOwner(ctx context.Context, ownerUserID string) ([]*clientpc.ClientPC, error)
 	GetOnlinePCsByOwner(ctx context.Context, ownerUserID string) ([]*clientpc.ClientPC, error)
@@ -19,6 +20,8 @@ type IPCService interface {
 	UpdatePCLastSeen(ctx context.Context, pcID string) error
 	GetAllClientPCs(ctx context.Context) ([]*clientpc.ClientPC, error)
 	GetOnlineClientPCs(ctx context.Context) ([]*clientpc.ClientPC, error)
+	GetClientPCsPage(ctx context.Context, limit, offset int) ([]*clientpc.ClientPC, int, error)
+	SetAutoAcceptControl(ctx context.Context, pcID string, enabled bool) (*clientpc.ClientPC, error)
 }
 
 // PCService implements the business logic for PC operations
@@ -35,8 +38,9 @@ func NewPCService(pcRepository interfaces.IClientPCRepository, pcFactory clientp
 	}
 }
 
-// RegisterPC registers a new PC or updates an existing one for a specific user
-func (s *PCService) RegisterPC(ctx context.Context, ownerUserID, pcIdentifier, ip string) (*clientpc.ClientPC, error) {
+// RegisterPC registers a new PC or updates an existing one for a specific user.
+// Metadata fields the agent did not send keep their stored values.
+func (s *PCService) RegisterPC(ctx context.Context, ownerUserID, pcIdentifier, ip string, metadata clientpc.PCMetadata) (*clientpc.ClientPC, error) {
 	fmt.Printf("DEBUG RegisterPC: Starting registration for user=%s, identifier=%s, ip=%s\n", ownerUserID, pcIdentifier, ip)
 	
 	// Validate input parameters
@@ -65,6 +69,7 @@ func (s *PCService) RegisterPC(ctx context.Context, ownerUserID, pcIdentifier, i
 	if existingPC != nil {
 		fmt.Printf("DEBUG RegisterPC: Updating existing PC: %s\n", existingPC.PCID)
 		existingPC.SetOnline()
+		existingPC.UpdateMetadata(metadata)
 		// Update IP if it has changed
 		if existingPC.IP != ip {
 			// Note: We might need to add an UpdateIP method to the domain entity
@@ -94,6 +99,7 @@ func (s *PCService) RegisterPC(ctx context.Context, ownerUserID, pcIdentifier, i
 
 	// Mark PC as online since it's being registered
 	newPC.SetOnline()
+	newPC.UpdateMetadata(metadata)
 	fmt.Printf("DEBUG RegisterPC: PC marked as online\n")
 
 	// Save the new PC
@@ -108,6 +114,32 @@ func (s *PCService) RegisterPC(ctx context.Context, ownerUserID, pcIdentifier, i
 	return newPC, nil
 }
 
+// MarkPCConnecting marks an already registered PC as CONNECTING while its registration is in progress.
+// Returns nil when the PC is not registered yet (it is created directly as ONLINE by RegisterPC).
+func (s *PCService) MarkPCConnecting(ctx context.Context, ownerUserID, pcIdentifier string) (*clientpc.ClientPC, error) {
+	if ownerUserID == "" {
+		return nil, errors.New("owner user ID cannot be empty")
+	}
+	if pcIdentifier == "" {
+		return nil, errors.New("PC identifier cannot be empty")
+	}
+
+	pc, err := s.pcRepository.FindByIdentifierAndOwner(ctx, pcIdentifier, ownerUserID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking existing PC: %w", err)
+	}
+	if pc == nil {
+		return nil, nil
+	}
+
+	if err := s.pcRepository.UpdateConnectionStatus(ctx, pc.PCID, clientpc.PCConnectionStatusConnecting); err != nil {
+		return nil, fmt.Errorf("error marking PC as connecting: %w", err)
+	}
+
+	pc.SetConnecting()
+	return pc, nil
+}
+
 // GetPCByID retrieves a PC by its ID
 func (s *PCService) GetPCByID(ctx context.Context, pcID string) (*clientpc.ClientPC, error) {
 	if pcID == "" {
@@ -186,6 +218,22 @@ func (s *PCService) UpdatePCLastSeen(ctx context.Context, pcID string) error {
 	return nil
 }
 
+// SetAutoAcceptControl enables or disables automatic acceptance of remote control requests for a PC
+func (s *PCService) SetAutoAcceptControl(ctx context.Context, pcID string, enabled bool) (*clientpc.ClientPC, error) {
+	pc, err := s.GetPCByID(ctx, pcID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.pcRepository.UpdateAutoAcceptControl(ctx, pcID, enabled)
+	if err != nil {
+		return nil, fmt.Errorf("error updating PC auto-accept policy: %w", err)
+	}
+
+	pc.SetAutoAcceptControl(enabled)
+	return pc, nil
+}
+
 // GetAllClientPCs retrieves all client PCs in the system (for admin dashboard)
 func (s *PCService) GetAllClientPCs(ctx context.Context) ([]*clientpc.ClientPC, error) {
 	pcs, err := s.pcRepository.FindAll(ctx, 0, 0) // 0 means no limit
@@ -214,3 +262,18 @@ func (s *PCService) GetOnlineClientPCs(ctx context.Context) ([]*clientpc.ClientP
 
 	return onlinePCs, nil
 }
+
+// GetClientPCsPage retrieves one page of client PCs together with the total number of registered PCs
+func (s *PCService) GetClientPCsPage(ctx context.Context, limit, offset int) ([]*clientpc.ClientPC, int, error) {
+	pcs, err := s.pcRepository.FindAll(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error retrieving client PCs page: %w", err)
+	}
+
+	total, err := s.pcRepository.Count(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting client PCs: %w", err)
+	}
+
+	return pcs, total, nil
+}
@@ -3,6 +3,7 @@ package pcservice
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,6 +29,11 @@ func (m *MockClientPCRepository) FindByID(ctx context.Context, pcID string) (*cl
 	return args.Get(0).(*clientpc.ClientPC), args.Error(1)
 }
 
+func (m *MockClientPCRepository) FindByIDs(ctx context.Context, pcIDs []string) (map[string]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, pcIDs)
+	return args.Get(0).(map[string]*clientpc.ClientPC), args.Error(1)
+}
+
 func (m *MockClientPCRepository) FindByIdentifierAndOwner(ctx context.Context, identifier string, ownerID string) (*clientpc.ClientPC, error) {
 	args := m.Called(ctx, identifier, ownerID)
 	if args.Get(0) == nil {
@@ -56,6 +62,11 @@ func (m *MockClientPCRepository) UpdateLastSeen(ctx context.Context, pcID string
 	return args.Error(0)
 }
 
+func (m *MockClientPCRepository) UpdateAutoAcceptControl(ctx context.Context, pcID string, enabled bool) error {
+	args := m.Called(ctx, pcID, enabled)
+	return args.Error(0)
+}
+
 func (m *MockClientPCRepository) Delete(ctx context.Context, pcID string) error {
 	args := m.Called(ctx, pcID)
 	return args.Error(0)
@@ -71,6 +82,11 @@ func (m *MockClientPCRepository) CountByOwner(ctx context.Context, ownerID strin
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockClientPCRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
 type MockClientPCFactory struct {
 	mock.Mock
 }
@@ -103,7 +119,7 @@ func TestPCService_RegisterPC_NewPC(t *testing.T) {
 	mockRepo.On("Save", ctx, mock.AnythingOfType("*clientpc.ClientPC")).Return(nil)
 
 	// Act
-	result, err := service.RegisterPC(ctx, ownerUserID, pcIdentifier, ip)
+	result, err := service.RegisterPC(ctx, ownerUserID, pcIdentifier, ip, clientpc.PCMetadata{})
 
 	// Assert
 	assert.NoError(t, err)
@@ -137,7 +153,7 @@ func TestPCService_RegisterPC_ExistingPC(t *testing.T) {
 	mockRepo.On("Save", ctx, mock.AnythingOfType("*clientpc.ClientPC")).Return(nil)
 
 	// Act
-	result, err := service.RegisterPC(ctx, ownerUserID, pcIdentifier, ip)
+	result, err := service.RegisterPC(ctx, ownerUserID, pcIdentifier, ip, clientpc.PCMetadata{})
 
 	// Assert
 	assert.NoError(t, err)
@@ -151,6 +167,82 @@ func TestPCService_RegisterPC_ExistingPC(t *testing.T) {
 	mockFactory.AssertNotCalled(t, "CreateClientPC")
 }
 
+func TestPCService_RegisterPC_StoresReportedMetadata(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockClientPCRepository)
+	mockFactory := new(MockClientPCFactory)
+	service := NewPCService(mockRepo, mockFactory)
+
+	ctx := context.Background()
+	ownerUserID := "550e8400-e29b-41d4-a716-446655440000"
+	newPC, _ := clientpc.NewClientPC("550e8400-e29b-41d4-a716-446655440001", "test-pc", "192.168.1.100", ownerUserID)
+	mockRepo.On("FindByIdentifierAndOwner", ctx, "test-pc", ownerUserID).Return(nil, nil)
+	mockFactory.On("CreateClientPC", "test-pc", "192.168.1.100", ownerUserID).Return(newPC, nil)
+	mockRepo.On("Save", ctx, mock.AnythingOfType("*clientpc.ClientPC")).Return(nil)
+
+	// Act
+	result, err := service.RegisterPC(ctx, ownerUserID, "test-pc", "192.168.1.100", clientpc.PCMetadata{
+		OS:           " Windows 11 Pro ",
+		Hostname:     "LAB-PC-01",
+		AgentVersion: "1.4.2",
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Windows 11 Pro", result.OS)
+	assert.Equal(t, "LAB-PC-01", result.Hostname)
+	assert.Equal(t, "1.4.2", result.AgentVersion)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPCService_RegisterPC_OlderClientKeepsStoredMetadata(t *testing.T) {
+	// Arrange - el PC ya informó sus metadatos con un agente reciente
+	mockRepo := new(MockClientPCRepository)
+	mockFactory := new(MockClientPCFactory)
+	service := NewPCService(mockRepo, mockFactory)
+
+	ctx := context.Background()
+	ownerUserID := "550e8400-e29b-41d4-a716-446655440000"
+	existingPC, _ := clientpc.NewClientPC("550e8400-e29b-41d4-a716-446655440001", "test-pc", "192.168.1.50", ownerUserID)
+	existingPC.UpdateMetadata(clientpc.PCMetadata{OS: "Ubuntu 24.04", Hostname: "lab-linux", AgentVersion: "1.4.2"})
+	existingPC.SetOffline()
+	mockRepo.On("FindByIdentifierAndOwner", ctx, "test-pc", ownerUserID).Return(existingPC, nil)
+	mockRepo.On("Save", ctx, mock.AnythingOfType("*clientpc.ClientPC")).Return(nil)
+
+	// Act - un cliente antiguo no envía metadatos
+	result, err := service.RegisterPC(ctx, ownerUserID, "test-pc", "192.168.1.100", clientpc.PCMetadata{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Ubuntu 24.04", result.OS)
+	assert.Equal(t, "lab-linux", result.Hostname)
+	assert.Equal(t, "1.4.2", result.AgentVersion)
+	assert.True(t, result.IsOnline())
+}
+
+func TestPCService_RegisterPC_TruncatesOversizedMetadata(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockClientPCRepository)
+	mockFactory := new(MockClientPCFactory)
+	service := NewPCService(mockRepo, mockFactory)
+
+	ctx := context.Background()
+	ownerUserID := "550e8400-e29b-41d4-a716-446655440000"
+	existingPC, _ := clientpc.NewClientPC("550e8400-e29b-41d4-a716-446655440001", "test-pc", "192.168.1.50", ownerUserID)
+	mockRepo.On("FindByIdentifierAndOwner", ctx, "test-pc", ownerUserID).Return(existingPC, nil)
+	mockRepo.On("Save", ctx, mock.AnythingOfType("*clientpc.ClientPC")).Return(nil)
+
+	// Act
+	result, err := service.RegisterPC(ctx, ownerUserID, "test-pc", "192.168.1.100", clientpc.PCMetadata{
+		AgentVersion: strings.Repeat("9", clientpc.MaxAgentVersionLength+10),
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result.AgentVersion, clientpc.MaxAgentVersionLength)
+	assert.Empty(t, result.OS)
+}
+
 func TestPCService_RegisterPC_InvalidInput(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockClientPCRepository)
@@ -175,7 +267,7 @@ func TestPCService_RegisterPC_InvalidInput(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Act
-			result, err := service.RegisterPC(ctx, tc.ownerUserID, tc.pcIdentifier, tc.ip)
+			result, err := service.RegisterPC(ctx, tc.ownerUserID, tc.pcIdentifier, tc.ip, clientpc.PCMetadata{})
 
 			// Assert
 			assert.Error(t, err)
@@ -200,7 +292,7 @@ func TestPCService_RegisterPC_RepositoryError(t *testing.T) {
 	mockRepo.On("FindByIdentifierAndOwner", ctx, pcIdentifier, ownerUserID).Return(nil, errors.New("database error"))
 
 	// Act
-	result, err := service.RegisterPC(ctx, ownerUserID, pcIdentifier, ip)
+	result, err := service.RegisterPC(ctx, ownerUserID, pcIdentifier, ip, clientpc.PCMetadata{})
 
 	// Assert
 	assert.Error(t, err)
@@ -210,6 +302,51 @@ func TestPCService_RegisterPC_RepositoryError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestPCService_MarkPCConnecting_ExistingPC(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockClientPCRepository)
+	mockFactory := new(MockClientPCFactory)
+	service := NewPCService(mockRepo, mockFactory)
+
+	ctx := context.Background()
+	ownerUserID := "550e8400-e29b-41d4-a716-446655440000"
+	pcIdentifier := "test-pc"
+
+	existingPC, _ := clientpc.NewClientPC("550e8400-e29b-41d4-a716-446655440001", pcIdentifier, "192.168.1.50", ownerUserID)
+	existingPC.SetOffline()
+	mockRepo.On("FindByIdentifierAndOwner", ctx, pcIdentifier, ownerUserID).Return(existingPC, nil)
+	mockRepo.On("UpdateConnectionStatus", ctx, existingPC.PCID, clientpc.PCConnectionStatusConnecting).Return(nil)
+
+	// Act
+	result, err := service.MarkPCConnecting(ctx, ownerUserID, pcIdentifier)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.True(t, result.IsConnecting())
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPCService_MarkPCConnecting_NewPC(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockClientPCRepository)
+	mockFactory := new(MockClientPCFactory)
+	service := NewPCService(mockRepo, mockFactory)
+
+	ctx := context.Background()
+	ownerUserID := "550e8400-e29b-41d4-a716-446655440000"
+	mockRepo.On("FindByIdentifierAndOwner", ctx, "new-pc", ownerUserID).Return(nil, nil)
+
+	// Act
+	result, err := service.MarkPCConnecting(ctx, ownerUserID, "new-pc")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "UpdateConnectionStatus")
+}
+
 func TestPCService_GetPCByID_Success(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockClientPCRepository)
@@ -449,3 +586,48 @@ func TestPCService_GetOnlineClientPCs(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestPCService_SetAutoAcceptControl_Success(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockClientPCRepository)
+	mockFactory := new(MockClientPCFactory)
+	service := NewPCService(mockRepo, mockFactory)
+
+	ctx := context.Background()
+	pcID := "550e8400-e29b-41d4-a716-446655440001" // Valid UUID
+
+	existingPC, _ := clientpc.NewClientPC(pcID, "lab-pc", "192.168.1.100", "550e8400-e29b-41d4-a716-446655440000")
+	mockRepo.On("FindByID", ctx, pcID).Return(existingPC, nil)
+	mockRepo.On("UpdateAutoAcceptControl", ctx, pcID, true).Return(nil)
+
+	// Act
+	result, err := service.SetAutoAcceptControl(ctx, pcID, true)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.True(t, result.AutoAcceptControl)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPCService_SetAutoAcceptControl_PCNotFound(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockClientPCRepository)
+	mockFactory := new(MockClientPCFactory)
+	service := NewPCService(mockRepo, mockFactory)
+
+	ctx := context.Background()
+	pcID := "550e8400-e29b-41d4-a716-446655440001" // Valid UUID
+
+	mockRepo.On("FindByID", ctx, pcID).Return((*clientpc.ClientPC)(nil), nil)
+
+	// Act
+	result, err := service.SetAutoAcceptControl(ctx, pcID, true)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "PC not found")
+	mockRepo.AssertNotCalled(t, "UpdateAutoAcceptControl")
+}
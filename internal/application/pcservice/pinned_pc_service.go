@@ -0,0 +1,129 @@
+package pcservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+)
+
+// ErrPCNotFound indica que el PC solicitado no existe o el administrador no tiene acceso a él
+var ErrPCNotFound = errors.New("PC not found")
+
+// IPinnedPCService defines the interface for managing the PCs pinned by each administrator
+type IPinnedPCService interface {
+	PinPC(ctx context.Context, adminUserID, pcID string) error
+	UnpinPC(ctx context.Context, adminUserID, pcID string) error
+	GetPinnedPCs(ctx context.Context, adminUserID string, connectedPCIDs []string) ([]*clientpc.ClientPC, error)
+}
+
+// PinnedPCService mantiene la lista de PCs favoritos de cada administrador
+type PinnedPCService struct {
+	pinnedPCRepository interfaces.IPinnedPCRepository
+	pcRepository       interfaces.IClientPCRepository
+}
+
+// NewPinnedPCService creates a new instance of PinnedPCService
+func NewPinnedPCService(pinnedPCRepository interfaces.IPinnedPCRepository, pcRepository interfaces.IClientPCRepository) IPinnedPCService {
+	return &PinnedPCService{
+		pinnedPCRepository: pinnedPCRepository,
+		pcRepository:       pcRepository,
+	}
+}
+
+// PinPC fija un PC para el administrador. Solo se pueden fijar PCs registrados a los que el
+// administrador tiene acceso; fijar de nuevo un PC ya fijado no es un error.
+func (s *PinnedPCService) PinPC(ctx context.Context, adminUserID, pcID string) error {
+	if adminUserID == "" || pcID == "" {
+		return errors.New("admin user ID and PC ID cannot be empty")
+	}
+
+	if err := s.ensureAccessible(ctx, pcID); err != nil {
+		return err
+	}
+
+	if err := s.pinnedPCRepository.Pin(ctx, adminUserID, pcID); err != nil {
+		return fmt.Errorf("error pinning PC: %w", err)
+	}
+
+	return nil
+}
+
+// UnpinPC quita el PC de los fijados del administrador
+func (s *PinnedPCService) UnpinPC(ctx context.Context, adminUserID, pcID string) error {
+	if adminUserID == "" || pcID == "" {
+		return errors.New("admin user ID and PC ID cannot be empty")
+	}
+
+	if err := s.ensureAccessible(ctx, pcID); err != nil {
+		return err
+	}
+
+	if err := s.pinnedPCRepository.Unpin(ctx, adminUserID, pcID); err != nil {
+		return fmt.Errorf("error unpinning PC: %w", err)
+	}
+
+	return nil
+}
+
+// GetPinnedPCs retorna los PCs fijados por el administrador en orden de fijado. El estado de
+// conexión se toma de las conexiones WebSocket vivas y no del valor persistido, que puede estar
+// desactualizado tras una caída del servidor.
+func (s *PinnedPCService) GetPinnedPCs(ctx context.Context, adminUserID string, connectedPCIDs []string) ([]*clientpc.ClientPC, error) {
+	if adminUserID == "" {
+		return nil, errors.New("admin user ID cannot be empty")
+	}
+
+	pcIDs, err := s.pinnedPCRepository.FindPCIDsByAdmin(ctx, adminUserID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving pinned PCs: %w", err)
+	}
+
+	if len(pcIDs) == 0 {
+		return []*clientpc.ClientPC{}, nil
+	}
+
+	pcsByID, err := s.pcRepository.FindByIDs(ctx, pcIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving pinned PCs details: %w", err)
+	}
+
+	connected := make(map[string]bool, len(connectedPCIDs))
+	for _, pcID := range connectedPCIDs {
+		connected[pcID] = true
+	}
+
+	pcs := make([]*clientpc.ClientPC, 0, len(pcIDs))
+	for _, pcID := range pcIDs {
+		pc, exists := pcsByID[pcID]
+		if !exists {
+			continue
+		}
+
+		if connected[pcID] {
+			pc.ConnectionStatus = clientpc.PCConnectionStatusOnline
+		} else {
+			pc.ConnectionStatus = clientpc.PCConnectionStatusOffline
+		}
+		pcs = append(pcs, pc)
+	}
+
+	return pcs, nil
+}
+
+// ensureAccessible verifica que el PC existe. Todos los administradores tienen acceso a todos
+// los PCs registrados, por lo que un PC inexistente es el único caso no autorizado.
+func (s *PinnedPCService) ensureAccessible(ctx context.Context, pcID string) error {
+	pc, err := s.pcRepository.FindByID(ctx, pcID)
+	if err != nil {
+		return fmt.Errorf("error retrieving PC by ID: %w", err)
+	}
+
+	if pc == nil {
+		return fmt.Errorf("%w: %s", ErrPCNotFound, pcID)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package pcservice
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+)
+
+// inMemoryPinnedPCRepository guarda los PCs fijados en memoria conservando el orden de fijado
+type inMemoryPinnedPCRepository struct {
+	pinned map[string][]string
+}
+
+func newInMemoryPinnedPCRepository() *inMemoryPinnedPCRepository {
+	return &inMemoryPinnedPCRepository{pinned: make(map[string][]string)}
+}
+
+func (r *inMemoryPinnedPCRepository) Pin(ctx context.Context, adminUserID, pcID string) error {
+	if !slices.Contains(r.pinned[adminUserID], pcID) {
+		r.pinned[adminUserID] = append(r.pinned[adminUserID], pcID)
+	}
+	return nil
+}
+
+func (r *inMemoryPinnedPCRepository) Unpin(ctx context.Context, adminUserID, pcID string) error {
+	r.pinned[adminUserID] = slices.DeleteFunc(r.pinned[adminUserID], func(id string) bool { return id == pcID })
+	return nil
+}
+
+func (r *inMemoryPinnedPCRepository) FindPCIDsByAdmin(ctx context.Context, adminUserID string) ([]string, error) {
+	return slices.Clone(r.pinned[adminUserID]), nil
+}
+
+const (
+	testPinAdminID = "550e8400-e29b-41d4-a716-446655440010"
+	testPinOwnerID = "550e8400-e29b-41d4-a716-446655440000"
+	testPinnedPCA  = "550e8400-e29b-41d4-a716-446655440001"
+	testPinnedPCB  = "550e8400-e29b-41d4-a716-446655440002"
+)
+
+func TestPinnedPCService_PinAndListWithLiveStatus(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockClientPCRepository)
+	pinnedRepo := newInMemoryPinnedPCRepository()
+	service := NewPinnedPCService(pinnedRepo, mockRepo)
+	ctx := context.Background()
+
+	pcA, _ := clientpc.NewClientPC(testPinnedPCA, "lab-pc-a", "192.168.1.101", testPinOwnerID)
+	pcA.SetOnline() // estado persistido obsoleto: el PC ya no tiene conexión viva
+	pcB, _ := clientpc.NewClientPC(testPinnedPCB, "lab-pc-b", "192.168.1.102", testPinOwnerID)
+
+	mockRepo.On("FindByID", ctx, testPinnedPCB).Return(pcB, nil)
+	mockRepo.On("FindByID", ctx, testPinnedPCA).Return(pcA, nil)
+	mockRepo.On("FindByIDs", ctx, []string{testPinnedPCB, testPinnedPCA}).
+		Return(map[string]*clientpc.ClientPC{testPinnedPCA: pcA, testPinnedPCB: pcB}, nil)
+
+	// Act
+	require.NoError(t, service.PinPC(ctx, testPinAdminID, testPinnedPCB))
+	require.NoError(t, service.PinPC(ctx, testPinAdminID, testPinnedPCA))
+	require.NoError(t, service.PinPC(ctx, testPinAdminID, testPinnedPCA)) // fijar dos veces es idempotente
+	pcs, err := service.GetPinnedPCs(ctx, testPinAdminID, []string{testPinnedPCB})
+
+	// Assert
+	assert.NoError(t, err)
+	require.Len(t, pcs, 2)
+	assert.Equal(t, testPinnedPCB, pcs[0].PCID, "se respeta el orden de fijado")
+	assert.Equal(t, clientpc.PCConnectionStatusOnline, pcs[0].ConnectionStatus)
+	assert.Equal(t, testPinnedPCA, pcs[1].PCID)
+	assert.Equal(t, clientpc.PCConnectionStatusOffline, pcs[1].ConnectionStatus)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPinnedPCService_UnpinRemovesFromList(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockClientPCRepository)
+	pinnedRepo := newInMemoryPinnedPCRepository()
+	service := NewPinnedPCService(pinnedRepo, mockRepo)
+	ctx := context.Background()
+
+	pcA, _ := clientpc.NewClientPC(testPinnedPCA, "lab-pc-a", "192.168.1.101", testPinOwnerID)
+	mockRepo.On("FindByID", ctx, testPinnedPCA).Return(pcA, nil)
+	require.NoError(t, service.PinPC(ctx, testPinAdminID, testPinnedPCA))
+
+	// Act
+	err := service.UnpinPC(ctx, testPinAdminID, testPinnedPCA)
+	pcs, listErr := service.GetPinnedPCs(ctx, testPinAdminID, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, listErr)
+	assert.NotNil(t, pcs)
+	assert.Empty(t, pcs)
+	mockRepo.AssertNotCalled(t, "FindByIDs", mock.Anything, mock.Anything)
+}
+
+func TestPinnedPCService_PinUnknownPC(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockClientPCRepository)
+	pinnedRepo := newInMemoryPinnedPCRepository()
+	service := NewPinnedPCService(pinnedRepo, mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("FindByID", ctx, testPinnedPCA).Return((*clientpc.ClientPC)(nil), nil)
+
+	// Act
+	err := service.PinPC(ctx, testPinAdminID, testPinnedPCA)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrPCNotFound)
+	assert.Empty(t, pinnedRepo.pinned[testPinAdminID])
+	mockRepo.AssertNotCalled(t, "FindByIDs", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,319 @@
+package reconciliationservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/pcservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+// PCStatusChange describe un PC cuyo estado en base de datos fue corregido
+type PCStatusChange struct {
+	PCID           string `json:"pc_id"`
+	Identifier     string `json:"identifier"`
+	PreviousStatus string `json:"previous_status"`
+	NewStatus      string `json:"new_status"`
+}
+
+// SessionStatusChange describe una sesión huérfana que fue finalizada
+type SessionStatusChange struct {
+	SessionID      string `json:"session_id"`
+	ClientPCID     string `json:"client_pc_id"`
+	PreviousStatus string `json:"previous_status"`
+	NewStatus      string `json:"new_status"`
+}
+
+// ReconciliationReport resume los cambios aplicados al reconciliar la base de datos con las conexiones vivas
+type ReconciliationReport struct {
+	PCsMarkedOffline []PCStatusChange      `json:"pcs_marked_offline"`
+	SessionsEnded    []SessionStatusChange `json:"sessions_ended"`
+	// ConnectionsClosed filas de pc_connection_sessions que seguían abiertas para PCs sin conexión
+	ConnectionsClosed int       `json:"connections_closed"`
+	ConnectedPCs      int       `json:"connected_pcs"`
+	ReconciledAt      time.Time `json:"reconciled_at"`
+}
+
+// ReconcileDisconnectReason motivo con el que se cierran las conexiones que la reconciliación encuentra abiertas
+const ReconcileDisconnectReason = "reconciled: no live connection"
+
+// IOrphanedSessionEnder cierra una sesión huérfana con la secuencia normal de fin de sesión (estado, auditoría,
+// avisos al administrador y cierre de la actividad del cliente); lo implementa RemoteSessionService
+type IOrphanedSessionEnder interface {
+	EndOrphanedSession(ctx context.Context, sessionID string, expectedStatus remotesession.SessionStatus) (*remotesession.RemoteSession, error)
+}
+
+// DriftKind tipo de discrepancia entre el estado persistido de un PC y su conexión WebSocket
+type DriftKind string
+
+const (
+	// DriftPersistedOnlineWithoutSocket el PC figura ONLINE/CONNECTING en base de datos pero no tiene conexión viva
+	DriftPersistedOnlineWithoutSocket DriftKind = "DB_ONLINE_WITHOUT_SOCKET"
+	// DriftSocketWithPersistedOffline el PC tiene conexión viva pero figura OFFLINE en base de datos
+	DriftSocketWithPersistedOffline DriftKind = "SOCKET_WITH_DB_OFFLINE"
+	// DriftSocketWithoutPersistedPC hay una conexión viva para un PC que no existe en base de datos
+	DriftSocketWithoutPersistedPC DriftKind = "SOCKET_WITHOUT_DB_PC"
+)
+
+// PCStatusDrift describe un PC cuyo estado persistido no coincide con sus conexiones vivas
+type PCStatusDrift struct {
+	PCID            string    `json:"pc_id"`
+	Identifier      string    `json:"identifier,omitempty"`
+	Kind            DriftKind `json:"kind"`
+	PersistedStatus string    `json:"persisted_status,omitempty"`
+	LiveConnection  bool      `json:"live_connection"`
+}
+
+// StatusDriftReport lista las discrepancias detectadas sin modificar ningún estado
+type StatusDriftReport struct {
+	Drifts       []PCStatusDrift `json:"drifts"`
+	PersistedPCs int             `json:"persisted_pcs"`
+	ConnectedPCs int             `json:"connected_pcs"`
+	CheckedAt    time.Time       `json:"checked_at"`
+}
+
+// IReconciliationService define la interfaz del servicio de reconciliación de estados
+type IReconciliationService interface {
+	Reconcile(ctx context.Context, connectedPCIDs []string) (*ReconciliationReport, error)
+	DetectStatusDrift(ctx context.Context, connectedPCIDs []string) (*StatusDriftReport, error)
+}
+
+// reconciliationService implementa IReconciliationService
+type reconciliationService struct {
+	pcService         pcservice.IPCService
+	sessionRepository interfaces.IRemoteSessionRepository
+	// Opcional: sin él las sesiones huérfanas solo cambian de estado, sin auditoría ni avisos
+	sessionEnder IOrphanedSessionEnder
+	// Opcional: sin él no se cierran las filas de pc_connection_sessions que quedaron abiertas
+	connectionHistory pcservice.IConnectionHistoryService
+}
+
+// NewReconciliationService crea una nueva instancia del servicio de reconciliación
+func NewReconciliationService(
+	pcService pcservice.IPCService,
+	sessionRepository interfaces.IRemoteSessionRepository,
+	sessionEnder IOrphanedSessionEnder,
+	connectionHistory pcservice.IConnectionHistoryService,
+) IReconciliationService {
+	return &reconciliationService{
+		pcService:         pcService,
+		sessionRepository: sessionRepository,
+		sessionEnder:      sessionEnder,
+		connectionHistory: connectionHistory,
+	}
+}
+
+// Reconcile compara el estado persistido con los PCs que tienen conexión WebSocket viva:
+// los PCs que figuran conectados sin conexión pasan a OFFLINE y sus sesiones activas o pendientes se cierran;
+// las solicitudes en cola de PCs que ya están conectados también se cierran. De los PCs sin conexión afectados
+// se cierran además las filas de pc_connection_sessions que quedaron abiertas.
+func (s *reconciliationService) Reconcile(ctx context.Context, connectedPCIDs []string) (*ReconciliationReport, error) {
+	connected := make(map[string]bool, len(connectedPCIDs))
+	for _, pcID := range connectedPCIDs {
+		connected[pcID] = true
+	}
+
+	report := &ReconciliationReport{
+		PCsMarkedOffline: make([]PCStatusChange, 0),
+		SessionsEnded:    make([]SessionStatusChange, 0),
+		ConnectedPCs:     len(connected),
+		ReconciledAt:     time.Now(),
+	}
+
+	pcs, err := s.pcService.GetAllClientPCs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client PCs: %w", err)
+	}
+
+	// PCs sin conexión cuyo historial de conexiones puede haber quedado abierto
+	disconnectedPCs := make(map[string]bool)
+	for _, pc := range pcs {
+		if pc.ConnectionStatus == clientpc.PCConnectionStatusOffline || connected[pc.PCID] {
+			continue
+		}
+
+		if err := s.pcService.UpdatePCConnectionStatus(ctx, pc.PCID, clientpc.PCConnectionStatusOffline); err != nil {
+			return nil, fmt.Errorf("failed to mark PC %s offline: %w", pc.PCID, err)
+		}
+
+		log.Printf("🔧 RECONCILE: PC %s (%s) marked OFFLINE (was %s, no live connection)", pc.PCID, pc.Identifier, pc.ConnectionStatus)
+		disconnectedPCs[pc.PCID] = true
+		report.PCsMarkedOffline = append(report.PCsMarkedOffline, PCStatusChange{
+			PCID:           pc.PCID,
+			Identifier:     pc.Identifier,
+			PreviousStatus: string(pc.ConnectionStatus),
+			NewStatus:      string(clientpc.PCConnectionStatusOffline),
+		})
+	}
+
+	// Sesiones abiertas de PCs sin conexión, independientemente del estado que tenga el PC en base de datos.
+	// Una solicitud QUEUED es lo contrario: espera a un PC offline y queda huérfana si el PC ya está conectado
+	// (se registró sin que se le entregara).
+	for _, status := range []remotesession.SessionStatus{remotesession.StatusActive, remotesession.StatusPendingApproval, remotesession.StatusQueued} {
+		sessions, err := s.sessionRepository.FindByStatus(ctx, status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s sessions: %w", status, err)
+		}
+
+		for _, session := range sessions {
+			if connected[session.ClientPCID()] != session.IsQueued() {
+				continue
+			}
+
+			change, err := s.endOrphanedSession(ctx, session)
+			if err != nil {
+				return nil, err
+			}
+			if change == nil {
+				continue
+			}
+			report.SessionsEnded = append(report.SessionsEnded, *change)
+			if !session.IsQueued() {
+				disconnectedPCs[session.ClientPCID()] = true
+			}
+		}
+	}
+
+	report.ConnectionsClosed = s.closeOpenConnections(ctx, disconnectedPCs)
+
+	log.Printf("✅ RECONCILE: %d PCs marked offline, %d orphaned sessions ended, %d connections closed (%d live connections)",
+		len(report.PCsMarkedOffline), len(report.SessionsEnded), report.ConnectionsClosed, report.ConnectedPCs)
+
+	return report, nil
+}
+
+// DetectStatusDrift compara el estado persistido de cada PC con las conexiones vivas sin corregir nada:
+// sirve para vigilar las inconsistencias que Reconcile y el barrido de heartbeats resuelven.
+func (s *reconciliationService) DetectStatusDrift(ctx context.Context, connectedPCIDs []string) (*StatusDriftReport, error) {
+	connected := make(map[string]bool, len(connectedPCIDs))
+	for _, pcID := range connectedPCIDs {
+		connected[pcID] = true
+	}
+
+	pcs, err := s.pcService.GetAllClientPCs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client PCs: %w", err)
+	}
+
+	report := &StatusDriftReport{
+		Drifts:       make([]PCStatusDrift, 0),
+		PersistedPCs: len(pcs),
+		ConnectedPCs: len(connected),
+		CheckedAt:    time.Now(),
+	}
+
+	persisted := make(map[string]bool, len(pcs))
+	for _, pc := range pcs {
+		persisted[pc.PCID] = true
+		isOffline := pc.ConnectionStatus == clientpc.PCConnectionStatusOffline
+
+		var kind DriftKind
+		switch {
+		case connected[pc.PCID] && isOffline:
+			kind = DriftSocketWithPersistedOffline
+		case !connected[pc.PCID] && !isOffline:
+			kind = DriftPersistedOnlineWithoutSocket
+		default:
+			continue
+		}
+
+		report.Drifts = append(report.Drifts, PCStatusDrift{
+			PCID:            pc.PCID,
+			Identifier:      pc.Identifier,
+			Kind:            kind,
+			PersistedStatus: string(pc.ConnectionStatus),
+			LiveConnection:  connected[pc.PCID],
+		})
+	}
+
+	for _, pcID := range connectedPCIDs {
+		if persisted[pcID] {
+			continue
+		}
+		report.Drifts = append(report.Drifts, PCStatusDrift{
+			PCID:           pcID,
+			Kind:           DriftSocketWithoutPersistedPC,
+			LiveConnection: true,
+		})
+	}
+
+	if len(report.Drifts) > 0 {
+		log.Printf("⚠️ STATUS DRIFT: %d PCs with persisted status not matching live connections", len(report.Drifts))
+	}
+
+	return report, nil
+}
+
+// closeOpenConnections cierra el historial de conexiones que quedó abierto de los PCs sin conexión; un fallo solo
+// se registra, la reconciliación de estados ya está hecha. Retorna cuántas filas cerró.
+func (s *reconciliationService) closeOpenConnections(ctx context.Context, pcIDs map[string]bool) int {
+	if s.connectionHistory == nil {
+		return 0
+	}
+
+	closed := 0
+	for pcID := range pcIDs {
+		count, err := s.connectionHistory.CloseOpenConnections(ctx, pcID, ReconcileDisconnectReason)
+		if err != nil {
+			log.Printf("⚠️ RECONCILE: Failed to close open connections of PC %s: %v", pcID, err)
+		}
+		closed += count
+	}
+	return closed
+}
+
+// endOrphanedSession cierra una sesión huérfana: las activas terminan como FAILED (desconexión no limpia), las
+// pendientes se rechazan y las solicitudes en cola caducan. Con sessionEnder se usa la secuencia normal de fin de
+// sesión; retorna nil si la sesión cambió de estado desde que se leyó y ya no hay nada que cerrar.
+func (s *reconciliationService) endOrphanedSession(ctx context.Context, session *remotesession.RemoteSession) (*SessionStatusChange, error) {
+	previousStatus := session.Status()
+
+	if s.sessionEnder != nil {
+		ended, err := s.sessionEnder.EndOrphanedSession(ctx, session.SessionID(), previousStatus)
+		if errors.Is(err, remotesessionservice.ErrSessionAlreadyDecided) || errors.Is(err, remotesessionservice.ErrSessionNotFound) {
+			log.Printf("ℹ️ RECONCILE: Session %s changed while reconciling, skipped: %v", session.SessionID(), err)
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to end orphaned session %s: %w", session.SessionID(), err)
+		}
+		return newSessionStatusChange(ended, previousStatus), nil
+	}
+
+	var err error
+	switch {
+	case session.IsActive():
+		err = session.End(remotesession.StatusFailed)
+	case session.IsQueued():
+		err = session.ExpireQueue()
+	default:
+		err = session.Reject()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to end orphaned session %s: %w", session.SessionID(), err)
+	}
+
+	if err := s.sessionRepository.UpdateStatus(ctx, session.SessionID(), session.Status()); err != nil {
+		return nil, fmt.Errorf("failed to update orphaned session %s: %w", session.SessionID(), err)
+	}
+
+	log.Printf("🔧 RECONCILE: Orphaned session %s for PC %s set to %s (was %s)",
+		session.SessionID(), session.ClientPCID(), session.Status(), previousStatus)
+
+	return newSessionStatusChange(session, previousStatus), nil
+}
+
+func newSessionStatusChange(session *remotesession.RemoteSession, previousStatus remotesession.SessionStatus) *SessionStatusChange {
+	return &SessionStatusChange{
+		SessionID:      session.SessionID(),
+		ClientPCID:     session.ClientPCID(),
+		PreviousStatus: string(previousStatus),
+		NewStatus:      string(session.Status()),
+	}
+}
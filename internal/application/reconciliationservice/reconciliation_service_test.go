@@ -0,0 +1,368 @@
+package reconciliationservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/pcservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+// MockPCService es un mock del servicio de PCs
+type MockPCService struct {
+	mock.Mock
+}
+
+func (m *MockPCService) RegisterPC(ctx context.Context, ownerUserID, pcIdentifier, ip string, metadata clientpc.PCMetadata) (*clientpc.ClientPC, error) {
+	args := m.Called(ctx, ownerUserID, pcIdentifier, ip, metadata)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) GetPCByID(ctx context.Context, pcID string) (*clientpc.ClientPC, error) {
+	args := m.Called(ctx, pcID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) GetPCsByOwner(ctx context.Context, ownerUserID string) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, ownerUserID)
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) GetOnlinePCsByOwner(ctx context.Context, ownerUserID string) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, ownerUserID)
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) UpdatePCConnectionStatus(ctx context.Context, pcID string, status clientpc.PCConnectionStatus) error {
+	return m.Called(ctx, pcID, status).Error(0)
+}
+
+func (m *MockPCService) UpdatePCLastSeen(ctx context.Context, pcID string) error {
+	return m.Called(ctx, pcID).Error(0)
+}
+
+func (m *MockPCService) GetAllClientPCs(ctx context.Context) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) GetOnlineClientPCs(ctx context.Context) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) GetClientPCsPage(ctx context.Context, limit, offset int) ([]*clientpc.ClientPC, int, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*clientpc.ClientPC), args.Int(1), args.Error(2)
+}
+
+func (m *MockPCService) SetAutoAcceptControl(ctx context.Context, pcID string, enabled bool) (*clientpc.ClientPC, error) {
+	args := m.Called(ctx, pcID, enabled)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) MarkPCConnecting(ctx context.Context, ownerUserID, pcIdentifier string) (*clientpc.ClientPC, error) {
+	args := m.Called(ctx, ownerUserID, pcIdentifier)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*clientpc.ClientPC), args.Error(1)
+}
+
+// MockRemoteSessionRepository es un mock del repositorio de sesiones remotas
+type MockRemoteSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRemoteSessionRepository) Save(ctx context.Context, session *remotesession.RemoteSession) error {
+	return m.Called(ctx, session).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) FindById(ctx context.Context, id string) (*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) UpdateStatus(ctx context.Context, id string, status remotesession.SessionStatus) error {
+	return m.Called(ctx, id, status).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) FindByAdminUserID(ctx context.Context, adminUserID string) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, adminUserID)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindByClientPCID(ctx context.Context, clientPCID string) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, clientPCID)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindActiveSessions(ctx context.Context) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindPendingSessions(ctx context.Context) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) Update(ctx context.Context, session *remotesession.RemoteSession) error {
+	return m.Called(ctx, session).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) Delete(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) FindByStatus(ctx context.Context, status remotesession.SessionStatus) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindSessionsByDateRange(ctx context.Context, adminUserID string, startDate, endDate string) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, adminUserID, startDate, endDate)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindAccessReport(ctx context.Context, adminUserID, clientPCID string, from, to time.Time, limit, offset int) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, adminUserID, clientPCID, from, to, limit, offset)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) CountAccessReport(ctx context.Context, adminUserID, clientPCID string, from, to time.Time) (int, error) {
+	args := m.Called(ctx, adminUserID, clientPCID, from, to)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) CountSessionsByUser(ctx context.Context, adminUserID string) (int64, error) {
+	args := m.Called(ctx, adminUserID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newTestPC(pcID string, status clientpc.PCConnectionStatus) *clientpc.ClientPC {
+	return &clientpc.ClientPC{PCID: pcID, Identifier: "PC-" + pcID, ConnectionStatus: status}
+}
+
+func newTestActiveSession(t *testing.T, clientPCID string) *remotesession.RemoteSession {
+	session, err := remotesession.NewRemoteSession("admin-1", clientPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+	return session
+}
+
+func newTestPendingSession(t *testing.T, clientPCID string) *remotesession.RemoteSession {
+	session, err := remotesession.NewRemoteSession("admin-1", clientPCID)
+	require.NoError(t, err)
+	return session
+}
+
+func newTestQueuedSession(t *testing.T, clientPCID string) *remotesession.RemoteSession {
+	session, err := remotesession.NewQueuedRemoteSession("admin-1", clientPCID)
+	require.NoError(t, err)
+	return session
+}
+
+func TestReconcile_FixesSeededInconsistentState(t *testing.T) {
+	// Arrange - pc-live está conectado; pc-ghost figura ONLINE sin conexión;
+	// pc-offline ya está OFFLINE pero quedó con una sesión ACTIVE huérfana
+	pcService := new(MockPCService)
+	sessionRepo := new(MockRemoteSessionRepository)
+	service := NewReconciliationService(pcService, sessionRepo, nil, nil)
+
+	liveSession := newTestActiveSession(t, "pc-live")
+	ghostSession := newTestActiveSession(t, "pc-ghost")
+	orphanedSession := newTestActiveSession(t, "pc-offline")
+	ghostPending := newTestPendingSession(t, "pc-ghost")
+	waitingQueued := newTestQueuedSession(t, "pc-offline")
+	undeliveredQueued := newTestQueuedSession(t, "pc-live")
+
+	pcService.On("GetAllClientPCs", mock.Anything).Return([]*clientpc.ClientPC{
+		newTestPC("pc-live", clientpc.PCConnectionStatusOnline),
+		newTestPC("pc-ghost", clientpc.PCConnectionStatusOnline),
+		newTestPC("pc-offline", clientpc.PCConnectionStatusOffline),
+	}, nil)
+	pcService.On("UpdatePCConnectionStatus", mock.Anything, "pc-ghost", clientpc.PCConnectionStatusOffline).Return(nil)
+
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusActive).
+		Return([]*remotesession.RemoteSession{liveSession, ghostSession, orphanedSession}, nil)
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusPendingApproval).
+		Return([]*remotesession.RemoteSession{ghostPending}, nil)
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusQueued).
+		Return([]*remotesession.RemoteSession{waitingQueued, undeliveredQueued}, nil)
+	sessionRepo.On("UpdateStatus", mock.Anything, ghostSession.SessionID(), remotesession.StatusFailed).Return(nil)
+	sessionRepo.On("UpdateStatus", mock.Anything, undeliveredQueued.SessionID(), remotesession.StatusFailed).Return(nil)
+	sessionRepo.On("UpdateStatus", mock.Anything, orphanedSession.SessionID(), remotesession.StatusFailed).Return(nil)
+	sessionRepo.On("UpdateStatus", mock.Anything, ghostPending.SessionID(), remotesession.StatusRejected).Return(nil)
+
+	// Act
+	report, err := service.Reconcile(context.Background(), []string{"pc-live"})
+
+	// Assert
+	require.NoError(t, err)
+	pcService.AssertExpectations(t)
+	sessionRepo.AssertExpectations(t)
+	pcService.AssertNotCalled(t, "UpdatePCConnectionStatus", mock.Anything, "pc-live", mock.Anything)
+	sessionRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, liveSession.SessionID(), mock.Anything)
+	sessionRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, waitingQueued.SessionID(), mock.Anything)
+
+	assert.Equal(t, 1, report.ConnectedPCs)
+	assert.Equal(t, []PCStatusChange{{
+		PCID:           "pc-ghost",
+		Identifier:     "PC-pc-ghost",
+		PreviousStatus: "ONLINE",
+		NewStatus:      "OFFLINE",
+	}}, report.PCsMarkedOffline)
+
+	require.Len(t, report.SessionsEnded, 4)
+	assert.Equal(t, SessionStatusChange{
+		SessionID:      ghostSession.SessionID(),
+		ClientPCID:     "pc-ghost",
+		PreviousStatus: "ACTIVE",
+		NewStatus:      "FAILED",
+	}, report.SessionsEnded[0])
+	assert.Equal(t, orphanedSession.SessionID(), report.SessionsEnded[1].SessionID)
+	assert.Equal(t, "REJECTED", report.SessionsEnded[2].NewStatus)
+	assert.Equal(t, SessionStatusChange{
+		SessionID:      undeliveredQueued.SessionID(),
+		ClientPCID:     "pc-live",
+		PreviousStatus: "QUEUED",
+		NewStatus:      "FAILED",
+	}, report.SessionsEnded[3])
+}
+
+func TestReconcile_ConsistentStateChangesNothing(t *testing.T) {
+	// Arrange
+	pcService := new(MockPCService)
+	sessionRepo := new(MockRemoteSessionRepository)
+	service := NewReconciliationService(pcService, sessionRepo, nil, nil)
+
+	pcService.On("GetAllClientPCs", mock.Anything).Return([]*clientpc.ClientPC{
+		newTestPC("pc-live", clientpc.PCConnectionStatusOnline),
+		newTestPC("pc-offline", clientpc.PCConnectionStatusOffline),
+	}, nil)
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusActive).
+		Return([]*remotesession.RemoteSession{newTestActiveSession(t, "pc-live")}, nil)
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusPendingApproval).
+		Return([]*remotesession.RemoteSession{}, nil)
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusQueued).
+		Return([]*remotesession.RemoteSession{newTestQueuedSession(t, "pc-offline")}, nil)
+
+	// Act
+	report, err := service.Reconcile(context.Background(), []string{"pc-live"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, report.PCsMarkedOffline)
+	assert.Empty(t, report.SessionsEnded)
+	pcService.AssertNotCalled(t, "UpdatePCConnectionStatus", mock.Anything, mock.Anything, mock.Anything)
+	sessionRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+}
+
+func TestDetectStatusDrift_ReportsMismatchesWithoutChangingState(t *testing.T) {
+	// Arrange - pc-ghost figura ONLINE sin conexión, pc-woken tiene conexión pero figura OFFLINE
+	// y pc-unknown está conectado sin existir en base de datos
+	pcService := new(MockPCService)
+	sessionRepo := new(MockRemoteSessionRepository)
+	service := NewReconciliationService(pcService, sessionRepo, nil, nil)
+
+	pcService.On("GetAllClientPCs", mock.Anything).Return([]*clientpc.ClientPC{
+		newTestPC("pc-live", clientpc.PCConnectionStatusOnline),
+		newTestPC("pc-ghost", clientpc.PCConnectionStatusOnline),
+		newTestPC("pc-woken", clientpc.PCConnectionStatusOffline),
+		newTestPC("pc-offline", clientpc.PCConnectionStatusOffline),
+	}, nil)
+
+	// Act
+	report, err := service.DetectStatusDrift(context.Background(), []string{"pc-live", "pc-woken", "pc-unknown"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.PersistedPCs)
+	assert.Equal(t, 3, report.ConnectedPCs)
+	assert.Equal(t, []PCStatusDrift{
+		{PCID: "pc-ghost", Identifier: "PC-pc-ghost", Kind: DriftPersistedOnlineWithoutSocket, PersistedStatus: "ONLINE"},
+		{PCID: "pc-woken", Identifier: "PC-pc-woken", Kind: DriftSocketWithPersistedOffline, PersistedStatus: "OFFLINE", LiveConnection: true},
+		{PCID: "pc-unknown", Kind: DriftSocketWithoutPersistedPC, LiveConnection: true},
+	}, report.Drifts)
+	pcService.AssertNotCalled(t, "UpdatePCConnectionStatus", mock.Anything, mock.Anything, mock.Anything)
+	sessionRepo.AssertNotCalled(t, "FindByStatus", mock.Anything, mock.Anything)
+}
+
+// fakeSessionEnder registra las sesiones que la reconciliación delega en la secuencia normal de fin de sesión
+type fakeSessionEnder struct {
+	ended []string
+}
+
+func (f *fakeSessionEnder) EndOrphanedSession(ctx context.Context, sessionID string, expectedStatus remotesession.SessionStatus) (*remotesession.RemoteSession, error) {
+	f.ended = append(f.ended, sessionID)
+	session, err := remotesession.NewRemoteSession("admin-1", "pc-ghost")
+	if err != nil {
+		return nil, err
+	}
+	if err := session.Accept(); err != nil {
+		return nil, err
+	}
+	return session, session.End(remotesession.StatusFailed)
+}
+
+// fakeConnectionHistory cierra una conexión abierta por PC
+type fakeConnectionHistory struct {
+	pcservice.IConnectionHistoryService
+	closed []string
+}
+
+func (f *fakeConnectionHistory) CloseOpenConnections(ctx context.Context, pcID, reason string) (int, error) {
+	f.closed = append(f.closed, pcID+":"+reason)
+	return 1, nil
+}
+
+func TestReconcile_DelegatesOrphanedSessionsAndClosesOpenConnections(t *testing.T) {
+	// Arrange
+	pcService := new(MockPCService)
+	sessionRepo := new(MockRemoteSessionRepository)
+	ender := &fakeSessionEnder{}
+	history := &fakeConnectionHistory{}
+	service := NewReconciliationService(pcService, sessionRepo, ender, history)
+
+	ghostSession := newTestActiveSession(t, "pc-ghost")
+	pcService.On("GetAllClientPCs", mock.Anything).Return([]*clientpc.ClientPC{
+		newTestPC("pc-ghost", clientpc.PCConnectionStatusOnline),
+	}, nil)
+	pcService.On("UpdatePCConnectionStatus", mock.Anything, "pc-ghost", clientpc.PCConnectionStatusOffline).Return(nil)
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusActive).
+		Return([]*remotesession.RemoteSession{ghostSession}, nil)
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusPendingApproval).
+		Return([]*remotesession.RemoteSession{}, nil)
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusQueued).
+		Return([]*remotesession.RemoteSession{}, nil)
+
+	// Act
+	report, err := service.Reconcile(context.Background(), nil)
+
+	// Assert - el repositorio no se toca directamente: todo pasa por la secuencia de fin de sesión
+	require.NoError(t, err)
+	assert.Equal(t, []string{ghostSession.SessionID()}, ender.ended)
+	sessionRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	require.Len(t, report.SessionsEnded, 1)
+	assert.Equal(t, "FAILED", report.SessionsEnded[0].NewStatus)
+	assert.Equal(t, []string{"pc-ghost:" + ReconcileDisconnectReason}, history.closed)
+	assert.Equal(t, 1, report.ConnectionsClosed)
+}
@@ -0,0 +1,32 @@
+package remotesessionservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+// ErrInvalidAccessReportRange el inicio del rango del informe de accesos es posterior a su fin
+var ErrInvalidAccessReportRange = errors.New("access report range start is after its end")
+
+// GetAccessReport obtiene una página de qué administrador controló qué PC y cuándo, para las revisiones de acceso,
+// junto con el total de sesiones que cumplen los filtros. Un ID vacío o una fecha cero no filtran por ese campo.
+func (rss *RemoteSessionService) GetAccessReport(ctx context.Context, adminUserID, clientPCID string, from, to time.Time, limit, offset int) ([]*remotesession.RemoteSession, int, error) {
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return nil, 0, ErrInvalidAccessReportRange
+	}
+
+	sessions, err := rss.sessionRepo.FindAccessReport(ctx, adminUserID, clientPCID, from, to, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error obteniendo informe de accesos: %w", err)
+	}
+
+	total, err := rss.sessionRepo.CountAccessReport(ctx, adminUserID, clientPCID, from, to)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error contando informe de accesos: %w", err)
+	}
+	return sessions, total, nil
+}
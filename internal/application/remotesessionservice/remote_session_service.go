@@ -2,16 +2,35 @@ package remotesessionservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
 	events "github.com/unikyri/escritorio-remoto-backend/internal/domain/events"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
 )
 
+// ErrSessionAlreadyDecided indica que la sesión ya fue aceptada o rechazada y no admite otra decisión
+var ErrSessionAlreadyDecided = errors.New("session already decided")
+
+// Errores de InitiateSession que el handler traduce a códigos estables para la UI
+var (
+	// ErrSessionAlreadyActive indica que el PC ya tiene una sesión activa
+	ErrSessionAlreadyActive = errors.New("session already active")
+	// ErrAdminUserNotFound indica que el usuario que inicia la sesión no existe
+	ErrAdminUserNotFound = errors.New("admin user not found")
+	// ErrClientPCNotFound indica que el PC cliente no está registrado
+	ErrClientPCNotFound = errors.New("client PC not found")
+	// ErrClientPCOffline indica que el PC existe pero no está conectado
+	ErrClientPCOffline = errors.New("client PC is not online")
+	// ErrInvalidSessionRequest indica que los datos de la solicitud no permiten crear la sesión
+	ErrInvalidSessionRequest = errors.New("invalid session request")
+)
+
 // RemoteSessionService servicio de aplicación para sesiones remotas
 type RemoteSessionService struct {
 	sessionRepo      interfaces.IRemoteSessionRepository
@@ -24,6 +43,30 @@ type RemoteSessionService struct {
 	notifySessionEndedCallback func(sessionID, clientPCID, adminUserID string)
 	// Callback para notificar al cliente cuando termina la sesión
 	notifyClientSessionEndedCallback func(sessionID, clientPCID string)
+	// Callback para las sesiones que terminan rechazadas o fallidas (p. ej. retención de grabaciones parciales)
+	notifyUnsuccessfulEndCallback func(sessionID, adminUserID string, status remotesession.SessionStatus)
+
+	// Callback para avisar al administrador de que su solicitud en cola caducó
+	notifyQueueExpiredCallback func(sessionID, clientPCID, adminUserID string)
+	// Tiempo máximo que una solicitud espera en cola a que el PC se conecte
+	queueTimeout time.Duration
+	// Tiempo que una solicitud espera la respuesta del cliente antes de rechazarse
+	approvalTimeout time.Duration
+	// Exige motivo o ticket en cada solicitud de control
+	requireJustification bool
+	// Tiempo tras el fin de la sesión durante el que se aceptan los últimos frames de su grabación
+	recordingGracePeriod time.Duration
+
+	// Traspaso de sesiones entre administradores: presencia del destino y avisos a administradores y cliente
+	adminConnectedChecker                  func(adminUserID string) bool
+	notifySessionTransferredCallback       func(sessionID, clientPCID, fromAdminID, toAdminID string)
+	notifyClientSessionTransferredCallback func(sessionID, clientPCID, toAdminID string)
+
+	// Serializa aceptar/rechazar por sesión para que gane la primera decisión
+	decisionLocks *sessionLocks
+
+	// Presencia del usuario en el PC informada por el cliente durante cada sesión
+	activities *sessionActivities
 }
 
 // NewRemoteSessionService crea una nueva instancia del servicio
@@ -35,11 +78,16 @@ func NewRemoteSessionService(
 	eventBus events.IEventBus,
 ) *RemoteSessionService {
 	return &RemoteSessionService{
-		sessionRepo:      sessionRepo,
-		userRepo:         userRepo,
-		pcRepo:           pcRepo,
-		actionLogService: actionLogService,
-		eventBus:         eventBus,
+		sessionRepo:          sessionRepo,
+		userRepo:             userRepo,
+		pcRepo:               pcRepo,
+		actionLogService:     actionLogService,
+		eventBus:             eventBus,
+		queueTimeout:         DefaultSessionQueueTimeout,
+		approvalTimeout:      DefaultSessionApprovalTimeout,
+		recordingGracePeriod: DefaultRecordingGracePeriod,
+		decisionLocks:        newSessionLocks(),
+		activities:           newSessionActivities(),
 	}
 }
 
@@ -53,9 +101,25 @@ func (rss *RemoteSessionService) SetClientSessionEndedNotifier(callback func(ses
 	rss.notifyClientSessionEndedCallback = callback
 }
 
+// SetUnsuccessfulSessionEndNotifier establece el callback para las sesiones que terminan en REJECTED o FAILED
+func (rss *RemoteSessionService) SetUnsuccessfulSessionEndNotifier(callback func(sessionID, adminUserID string, status remotesession.SessionStatus)) {
+	rss.notifyUnsuccessfulEndCallback = callback
+}
+
+// notifyIfUnsuccessfulEnd invoca el callback de sesiones no exitosas si el estado final es REJECTED o FAILED
+func (rss *RemoteSessionService) notifyIfUnsuccessfulEnd(session *remotesession.RemoteSession) {
+	status := session.Status()
+	if rss.notifyUnsuccessfulEndCallback == nil || (status != remotesession.StatusRejected && status != remotesession.StatusFailed) {
+		return
+	}
+	rss.notifyUnsuccessfulEndCallback(session.SessionID(), session.AdminUserID(), status)
+}
+
 // CleanupStuckSessions limpia sesiones que se quedaron en estado activo o pendiente sin resolución.
-func (rss *RemoteSessionService) CleanupStuckSessions(clientPCID string) error {
-	sessions, err := rss.sessionRepo.FindByClientPCID(clientPCID)
+// Las que la lista muestra atascadas se vuelven a leer con su lock de decisión tomado antes de cerrarlas: la lista
+// puede estar desactualizada y el cliente pudo aceptarlas o rechazarlas entretanto.
+func (rss *RemoteSessionService) CleanupStuckSessions(ctx context.Context, clientPCID string) error {
+	sessions, err := rss.sessionRepo.FindByClientPCID(ctx, clientPCID)
 	if err != nil {
 		return fmt.Errorf("failed to get sessions for cleanup: %w", err)
 	}
@@ -64,48 +128,8 @@ func (rss *RemoteSessionService) CleanupStuckSessions(clientPCID string) error {
 
 	for _, session := range sessions {
 		originalStatus := session.Status()
-		var actionTaken bool = false
-		var newRepoStatus remotesession.SessionStatus // Para registrar el estado que se intentará guardar en el repo
-		var internalError error
-
-		if originalStatus == remotesession.StatusActive {
-			stuckTimeoutActive := 15 * time.Minute
-			shouldClean := false
-			reason := ""
-			if session.StartTime() == nil && now.Sub(session.CreatedAt()) > stuckTimeoutActive {
-				shouldClean = true
-				reason = fmt.Sprintf("active session %s with nil StartTime, created %v ago", session.SessionID(), now.Sub(session.CreatedAt()))
-			} else if session.StartTime() != nil && now.Sub(*session.StartTime()) > stuckTimeoutActive {
-				shouldClean = true
-				reason = fmt.Sprintf("active session %s started %v ago", session.SessionID(), now.Sub(*session.StartTime()))
-			}
 
-			if shouldClean {
-				log.Printf("🧹 Cleaning up stuck ACTIVE session: %s (%s)", session.SessionID(), reason)
-				internalError = session.End(remotesession.StatusFailed)
-				if internalError != nil {
-					log.Printf("⚠️ Warning: Call to End() on stuck ACTIVE session %s returned error: %v. Entity status before End(): %s, after End(): %s",
-						session.SessionID(), internalError, originalStatus, session.Status())
-				}
-				newRepoStatus = session.Status() // El estado que la entidad tenga ahora
-				actionTaken = true
-			}
-		} else if originalStatus == remotesession.StatusPendingApproval {
-			stuckTimeoutPending := 2 * time.Minute
-			if now.Sub(session.CreatedAt()) > stuckTimeoutPending {
-				reason := fmt.Sprintf("pending approval session %s created %v ago", session.SessionID(), now.Sub(session.CreatedAt()))
-				log.Printf("🧹 Cleaning up stuck PENDING_APPROVAL session: %s (%s)", session.SessionID(), reason)
-
-				internalError = session.Reject() // Usar Reject para PENDING_APPROVAL
-				if internalError != nil {
-					log.Printf("⚠️ Warning: Call to Reject() on stuck PENDING_APPROVAL session %s returned error: %v. Entity status before Reject(): %s, after Reject(): %s",
-						session.SessionID(), internalError, originalStatus, session.Status())
-					// Si Reject() falla, podría ser que el estado ya cambió. El estado actual de la entidad se usará para el repo.
-				}
-				newRepoStatus = session.Status() // El estado que la entidad tenga ahora (debería ser REJECTED o el estado original si Reject falló)
-				actionTaken = true
-			}
-		} else if originalStatus == remotesession.StatusRejected {
+		if originalStatus == remotesession.StatusRejected {
 			// Limpiar sesiones rechazadas antiguas para evitar acumulación
 			stuckTimeoutRejected := 30 * time.Minute
 			if now.Sub(session.CreatedAt()) > stuckTimeoutRejected {
@@ -117,53 +141,104 @@ func (rss *RemoteSessionService) CleanupStuckSessions(clientPCID string) error {
 				log.Printf("ℹ️ REJECTED session %s marked for cleanup (no state change needed)", session.SessionID())
 
 				// Actualizar timestamp para evitar que se procese repetidamente
-				errUpdate := rss.sessionRepo.UpdateStatus(session.SessionID(), remotesession.StatusRejected)
+				errUpdate := rss.sessionRepo.UpdateStatus(ctx, session.SessionID(), remotesession.StatusRejected)
 				if errUpdate != nil {
 					log.Printf("❌ Failed to update timestamp for REJECTED session %s: %v", session.SessionID(), errUpdate)
 				} else {
 					log.Printf("✅ REJECTED session %s cleanup timestamp updated", session.SessionID())
 				}
-
-				// No marcar como actionTaken porque no cambiamos el estado de la entidad
-				continue
 			}
+			continue
 		}
 
-		if actionTaken {
-			// Solo actualizar si el estado de la entidad realmente cambió o si hubo un intento de cambiarlo
-			if newRepoStatus != originalStatus || internalError == nil { // internalError == nil significa que la operación (End/Reject) tuvo éxito en cambiar el estado o no era necesaria
-				errUpdate := rss.sessionRepo.UpdateStatus(session.SessionID(), newRepoStatus)
-				if errUpdate != nil {
-					log.Printf("❌ CRITICAL: Failed to update session %s status in repo (original: %s, attempted new: %s): %v",
-						session.SessionID(), originalStatus, newRepoStatus, errUpdate)
-				} else {
-					log.Printf("✅ Session %s processed. Original status: %s, New status in repo: %s",
-						session.SessionID(), originalStatus, newRepoStatus)
-				}
+		if rss.stuckReason(session, now) == "" {
+			continue
+		}
+
+		cleaned, err := rss.transitionIfUnchanged(ctx, session.SessionID(), originalStatus, func(current *remotesession.RemoteSession) (bool, error) {
+			// Con el estado vigente puede haber dejado de estar atascada (p. ej. se volvió a entregar)
+			reason := rss.stuckReason(current, now)
+			if reason == "" {
+				return false, nil
+			}
+			log.Printf("🧹 Cleaning up stuck %s session: %s (%s)", originalStatus, current.SessionID(), reason)
+
+			var errTransition error
+			if originalStatus == remotesession.StatusActive {
+				errTransition = current.End(remotesession.StatusFailed)
 			} else {
-				log.Printf("ℹ️ Session %s (status: %s) was processed by cleanup, but no state change was made or repo update needed due to internal error during state change.",
-					session.SessionID(), originalStatus)
+				// Una sesión pendiente nunca empezó: se cierra con Reject, no con End
+				errTransition = current.Reject()
+			}
+			if errTransition != nil {
+				// Una transición inválida no modifica la entidad: no hay nada que persistir
+				log.Printf("⚠️ Warning: Could not close stuck %s session %s: %v", originalStatus, current.SessionID(), errTransition)
+				return false, nil
 			}
+			return true, nil
+		})
+		if err != nil {
+			log.Printf("❌ CRITICAL: Failed to clean up session %s (original: %s): %v", session.SessionID(), originalStatus, err)
+			continue
 		}
+		if cleaned == nil {
+			continue
+		}
+		log.Printf("✅ Session %s processed. Original status: %s, New status in repo: %s",
+			session.SessionID(), originalStatus, cleaned.Status())
+		rss.sessionEnded(ctx, cleaned)
 	}
 	return nil
 }
 
+// stuckReason motivo por el que CleanupStuckSessions debe cerrar la sesión activa o pendiente, o "" si no está
+// atascada
+func (rss *RemoteSessionService) stuckReason(session *remotesession.RemoteSession, now time.Time) string {
+	switch session.Status() {
+	case remotesession.StatusActive:
+		stuckTimeoutActive := 15 * time.Minute
+		if session.StartTime() == nil && now.Sub(session.CreatedAt()) > stuckTimeoutActive {
+			return fmt.Sprintf("active session %s with nil StartTime, created %v ago", session.SessionID(), now.Sub(session.CreatedAt()))
+		}
+		if session.StartTime() != nil && now.Sub(*session.StartTime()) > stuckTimeoutActive {
+			return fmt.Sprintf("active session %s started %v ago", session.SessionID(), now.Sub(*session.StartTime()))
+		}
+	case remotesession.StatusPendingApproval:
+		if rss.approvalExpired(session, now) {
+			return fmt.Sprintf("pending approval session %s waiting for %v", session.SessionID(), now.Sub(session.UpdatedAt()))
+		}
+	}
+	return ""
+}
+
 // InitiateSession inicia una nueva sesión de control remoto (método actualizado)
-func (rss *RemoteSessionService) InitiateSession(adminUserID, clientPCID string) (*remotesession.RemoteSession, error) {
+func (rss *RemoteSessionService) InitiateSession(ctx context.Context, adminUserID, clientPCID string, justification remotesession.Justification) (*remotesession.RemoteSession, error) {
+	if err := rss.checkJustification(justification); err != nil {
+		return nil, err
+	}
+
 	// Limpiar sesiones anteriores que puedan estar stuck
-	err := rss.CleanupStuckSessions(clientPCID)
+	err := rss.CleanupStuckSessions(ctx, clientPCID)
 	if err != nil {
 		log.Printf("⚠️ Warning during cleanup: %v", err)
 	}
 
 	// Verificar que no hay una sesión activa para este PC
-	activeSession, err := rss.GetActiveSessionForPC(clientPCID)
+	activeSession, err := rss.GetActiveSessionForPC(ctx, clientPCID)
 	if err != nil {
 		return nil, fmt.Errorf("error checking active sessions: %w", err)
 	}
 	if activeSession != nil {
-		return nil, fmt.Errorf("session already active: %s", activeSession.SessionID())
+		return nil, fmt.Errorf("%w: %s", ErrSessionAlreadyActive, activeSession.SessionID())
+	}
+
+	// Una solicitud en cola todavía no entregada (el PC acaba de reconectarse) ocupa el PC igual que una activa
+	queuedSession, err := rss.queuedSessionForPC(ctx, clientPCID)
+	if err != nil {
+		return nil, err
+	}
+	if queuedSession != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSessionAlreadyQueued, queuedSession.SessionID())
 	}
 
 	// Validar que el usuario administrador existe
@@ -172,57 +247,117 @@ func (rss *RemoteSessionService) InitiateSession(adminUserID, clientPCID string)
 		return nil, fmt.Errorf("error finding admin user: %w", err)
 	}
 	if user == nil {
-		return nil, fmt.Errorf("admin user not found")
+		return nil, ErrAdminUserNotFound
 	}
 
 	// Validar que el PC cliente existe y está online
-	pc, err := rss.pcRepo.FindByID(context.Background(), clientPCID)
+	pc, err := rss.pcRepo.FindByID(ctx, clientPCID)
 	if err != nil {
 		return nil, fmt.Errorf("error finding client PC: %w", err)
 	}
 	if pc == nil {
-		return nil, fmt.Errorf("client PC not found")
+		return nil, ErrClientPCNotFound
 	}
 
 	// Verificar que el PC está online
 	if string(pc.ConnectionStatus) != "ONLINE" {
-		return nil, fmt.Errorf("client PC is not online")
+		return nil, ErrClientPCOffline
 	}
 
 	// Crear nueva sesión
 	session, err := remotesession.NewRemoteSession(adminUserID, clientPCID)
 	if err != nil {
-		return nil, fmt.Errorf("error creating session: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSessionRequest, err)
+	}
+	session.SetJustification(justification)
+
+	// PCs de confianza (laboratorio/kiosco): la sesión se activa sin esperar al usuario
+	if pc.AutoAcceptControl {
+		err = session.Accept()
+		if err != nil {
+			return nil, fmt.Errorf("error auto-accepting session: %w", err)
+		}
 	}
 
 	// Guardar en repositorio
-	err = rss.sessionRepo.Save(session)
+	err = rss.sessionRepo.Save(ctx, session)
 	if err != nil {
 		return nil, fmt.Errorf("error saving session: %w", err)
 	}
 
+	rss.logSessionRequested(ctx, session, pc.Identifier)
+	rss.publishSessionInitiated(session, user.Username(), pc.Identifier)
+
+	return session, nil
+}
+
+// publishSessionInitiated publica los eventos de una sesión recién enviada al cliente
+// y, si el PC la aceptó automáticamente, registra la auditoría correspondiente
+func (rss *RemoteSessionService) publishSessionInitiated(session *remotesession.RemoteSession, adminUsername, pcIdentifier string) {
 	// Publicar evento de dominio
 	event := events.NewRemoteSessionInitiatedEvent(
 		session.SessionID(),
 		session.AdminUserID(),
 		session.ClientPCID(),
-		"", // adminUsername - podríamos obtenerlo del usuario si es necesario
-		"", // clientPCName - podríamos obtenerlo del PC si es necesario
+		adminUsername,
+		pcIdentifier,
 	)
 	rss.eventBus.Publish(event)
 
-	return session, nil
+	if session.Status() == remotesession.StatusActive {
+		rss.eventBus.Publish(events.NewRemoteSessionAcceptedEvent(
+			session.SessionID(),
+			session.AdminUserID(),
+			session.ClientPCID(),
+			*session.StartTime(),
+		))
+		rss.logAutoAcceptedSession(session, pcIdentifier)
+		rss.logSessionStarted(context.Background(), session, "auto_accept_policy")
+	}
 }
 
-// AcceptSession acepta una sesión de control remoto
-func (rss *RemoteSessionService) AcceptSession(sessionID string) error {
-	// Obtener sesión
-	session, err := rss.sessionRepo.FindById(sessionID)
+// logAutoAcceptedSession registra en auditoría una sesión aceptada automáticamente por política del PC
+func (rss *RemoteSessionService) logAutoAcceptedSession(session *remotesession.RemoteSession, pcIdentifier string) {
+	description := fmt.Sprintf("Sesión de control remoto aceptada automáticamente por política del PC %s", pcIdentifier)
+
+	details := map[string]interface{}{
+		"session_id":    session.SessionID(),
+		"client_pc_id":  session.ClientPCID(),
+		"pc_identifier": pcIdentifier,
+		"accepted_by":   "auto_accept_policy",
+	}
+
+	subjectEntityID := session.SessionID()
+	subjectEntityType := "REMOTE_SESSION"
+
+	err := rss.actionLogService.LogAction(
+		context.Background(),
+		actionlog.ActionRemoteSessionAutoAccepted,
+		description,
+		session.AdminUserID(),
+		&subjectEntityID,
+		&subjectEntityType,
+		details,
+	)
 	if err != nil {
-		return fmt.Errorf("error finding session: %w", err)
+		// Log error pero no falle la operación principal
+		log.Printf("⚠️ Warning: Failed to log auto-accepted session audit entry: %v", err)
+	} else {
+		log.Printf("📝 Audit log registered: Session %s auto-accepted for PC %s", session.SessionID(), session.ClientPCID())
 	}
-	if session == nil {
-		return fmt.Errorf("session not found")
+}
+
+// AcceptSession acepta una sesión de control remoto.
+// Si la sesión ya fue aceptada o rechazada retorna ErrSessionAlreadyDecided, y si venció su plazo de aprobación
+// ErrSessionApprovalExpired, sin modificarla.
+func (rss *RemoteSessionService) AcceptSession(ctx context.Context, sessionID string) error {
+	unlock := rss.decisionLocks.lock(sessionID)
+	defer unlock()
+
+	// Obtener sesión
+	session, err := rss.findPendingDecision(ctx, sessionID)
+	if err != nil {
+		return err
 	}
 
 	// Aceptar sesión
@@ -232,11 +367,13 @@ func (rss *RemoteSessionService) AcceptSession(sessionID string) error {
 	}
 
 	// Actualizar en repositorio
-	err = rss.sessionRepo.UpdateStatus(sessionID, session.Status())
+	err = rss.sessionRepo.UpdateStatus(ctx, sessionID, session.Status())
 	if err != nil {
 		return fmt.Errorf("error updating session status: %w", err)
 	}
 
+	rss.logSessionStarted(ctx, session, "client")
+
 	// Publicar evento de dominio
 	event := events.NewRemoteSessionAcceptedEvent(
 		session.SessionID(),
@@ -249,15 +386,17 @@ func (rss *RemoteSessionService) AcceptSession(sessionID string) error {
 	return nil
 }
 
-// RejectSession rechaza una sesión de control remoto
-func (rss *RemoteSessionService) RejectSession(sessionID, reason string) error {
+// RejectSession rechaza una sesión de control remoto.
+// Si la sesión ya fue aceptada o rechazada retorna ErrSessionAlreadyDecided, y si venció su plazo de aprobación
+// ErrSessionApprovalExpired, sin modificarla.
+func (rss *RemoteSessionService) RejectSession(ctx context.Context, sessionID, reason string) error {
+	unlock := rss.decisionLocks.lock(sessionID)
+	defer unlock()
+
 	// Obtener sesión
-	session, err := rss.sessionRepo.FindById(sessionID)
+	session, err := rss.findPendingDecision(ctx, sessionID)
 	if err != nil {
-		return fmt.Errorf("error finding session: %w", err)
-	}
-	if session == nil {
-		return fmt.Errorf("session not found")
+		return err
 	}
 
 	// Rechazar sesión
@@ -267,7 +406,7 @@ func (rss *RemoteSessionService) RejectSession(sessionID, reason string) error {
 	}
 
 	// Actualizar en repositorio
-	err = rss.sessionRepo.UpdateStatus(sessionID, session.Status())
+	err = rss.sessionRepo.UpdateStatus(ctx, sessionID, session.Status())
 	if err != nil {
 		return fmt.Errorf("error updating session status: %w", err)
 	}
@@ -280,37 +419,106 @@ func (rss *RemoteSessionService) RejectSession(sessionID, reason string) error {
 	)
 	rss.eventBus.Publish(event)
 
+	rss.notifyIfUnsuccessfulEnd(session)
+
 	return nil
 }
 
+// findPendingDecision obtiene la sesión y verifica que siga esperando la decisión del cliente y que no haya vencido
+// su plazo de aprobación (ErrSessionApprovalExpired).
+// Debe llamarse con el lock de la sesión tomado para leer el estado vigente.
+func (rss *RemoteSessionService) findPendingDecision(ctx context.Context, sessionID string) (*remotesession.RemoteSession, error) {
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding session: %w", err)
+	}
+	if session == nil {
+		return nil, fmt.Errorf("session not found")
+	}
+
+	if session.Status() != remotesession.StatusPendingApproval {
+		return nil, fmt.Errorf("%w: session %s is %s", ErrSessionAlreadyDecided, sessionID, session.Status())
+	}
+	// Pasado el plazo la solicitud ya no espera respuesta aunque la limpieza todavía no la haya rechazado
+	if rss.approvalExpired(session, time.Now().UTC()) {
+		return nil, fmt.Errorf("%w: session %s was not answered within %v", ErrSessionApprovalExpired, sessionID, rss.approvalTimeout)
+	}
+
+	return session, nil
+}
+
+// ResolveSessionLabels obtiene los identificadores de PC y nombres de administrador de una lista
+// de sesiones con una consulta en bloque por repositorio (evita N+1). Los IDs que ya no existen
+// simplemente no aparecen en los mapas resultantes.
+func (rss *RemoteSessionService) ResolveSessionLabels(ctx context.Context, sessions []*remotesession.RemoteSession) (map[string]string, map[string]string, error) {
+	pcNames := make(map[string]string)
+	adminUsernames := make(map[string]string)
+	if len(sessions) == 0 {
+		return pcNames, adminUsernames, nil
+	}
+
+	pcIDs := make([]string, 0, len(sessions))
+	adminIDs := make([]string, 0, len(sessions))
+	seenPCs := make(map[string]bool)
+	seenAdmins := make(map[string]bool)
+	for _, session := range sessions {
+		if !seenPCs[session.ClientPCID()] {
+			seenPCs[session.ClientPCID()] = true
+			pcIDs = append(pcIDs, session.ClientPCID())
+		}
+		if !seenAdmins[session.AdminUserID()] {
+			seenAdmins[session.AdminUserID()] = true
+			adminIDs = append(adminIDs, session.AdminUserID())
+		}
+	}
+
+	pcs, err := rss.pcRepo.FindByIDs(ctx, pcIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error finding client PCs: %w", err)
+	}
+	for id, pc := range pcs {
+		pcNames[id] = pc.Identifier
+	}
+
+	admins, err := rss.userRepo.FindByIDs(ctx, adminIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error finding admin users: %w", err)
+	}
+	for id, admin := range admins {
+		adminUsernames[id] = admin.Username()
+	}
+
+	return pcNames, adminUsernames, nil
+}
+
 // GetSessionById obtiene una sesión por ID
-func (rss *RemoteSessionService) GetSessionById(sessionID string) (*remotesession.RemoteSession, error) {
-	return rss.sessionRepo.FindById(sessionID)
+func (rss *RemoteSessionService) GetSessionById(ctx context.Context, sessionID string) (*remotesession.RemoteSession, error) {
+	return rss.sessionRepo.FindById(ctx, sessionID)
 }
 
 // GetActiveSessions obtiene todas las sesiones activas
-func (rss *RemoteSessionService) GetActiveSessions() ([]*remotesession.RemoteSession, error) {
-	return rss.sessionRepo.FindByStatus(remotesession.StatusActive)
+func (rss *RemoteSessionService) GetActiveSessions(ctx context.Context) ([]*remotesession.RemoteSession, error) {
+	return rss.sessionRepo.FindByStatus(ctx, remotesession.StatusActive)
 }
 
 // GetSessionsByUser obtiene las sesiones de un usuario específico
-func (rss *RemoteSessionService) GetSessionsByUser(userID string) ([]*remotesession.RemoteSession, error) {
-	return rss.sessionRepo.FindByAdminUserID(userID)
+func (rss *RemoteSessionService) GetSessionsByUser(ctx context.Context, userID string) ([]*remotesession.RemoteSession, error) {
+	return rss.sessionRepo.FindByAdminUserID(ctx, userID)
 }
 
 // GetSessionsByPC obtiene sesiones por PC cliente
-func (rss *RemoteSessionService) GetSessionsByPC(clientPCID string) ([]*remotesession.RemoteSession, error) {
-	return rss.sessionRepo.FindByClientPCID(clientPCID)
+func (rss *RemoteSessionService) GetSessionsByPC(ctx context.Context, clientPCID string) ([]*remotesession.RemoteSession, error) {
+	return rss.sessionRepo.FindByClientPCID(ctx, clientPCID)
 }
 
 // GetSessionsByClientPCID obtiene todas las sesiones para un PC cliente específico
 func (rss *RemoteSessionService) GetSessionsByClientPCID(ctx context.Context, clientPCID string) ([]*remotesession.RemoteSession, error) {
-	return rss.sessionRepo.FindByClientPCID(clientPCID)
+	return rss.sessionRepo.FindByClientPCID(ctx, clientPCID)
 }
 
 // IsSessionActiveForStreaming verifica si una sesión está activa para streaming de pantalla
-func (rss *RemoteSessionService) IsSessionActiveForStreaming(sessionID string) (bool, error) {
-	session, err := rss.sessionRepo.FindById(sessionID)
+func (rss *RemoteSessionService) IsSessionActiveForStreaming(ctx context.Context, sessionID string) (bool, error) {
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
 	if err != nil {
 		return false, fmt.Errorf("error finding session: %w", err)
 	}
@@ -323,8 +531,8 @@ func (rss *RemoteSessionService) IsSessionActiveForStreaming(sessionID string) (
 }
 
 // GetActiveSessionForPC obtiene la sesión activa para un PC específico (si existe)
-func (rss *RemoteSessionService) GetActiveSessionForPC(clientPCID string) (*remotesession.RemoteSession, error) {
-	sessions, err := rss.sessionRepo.FindByClientPCID(clientPCID)
+func (rss *RemoteSessionService) GetActiveSessionForPC(ctx context.Context, clientPCID string) (*remotesession.RemoteSession, error) {
+	sessions, err := rss.sessionRepo.FindByClientPCID(ctx, clientPCID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sessions for PC: %w", err)
 	}
@@ -340,8 +548,8 @@ func (rss *RemoteSessionService) GetActiveSessionForPC(clientPCID string) (*remo
 }
 
 // GetAdminUserIDForActiveSession obtiene el ID del administrador para una sesión activa
-func (rss *RemoteSessionService) GetAdminUserIDForActiveSession(sessionID string) (string, error) {
-	session, err := rss.sessionRepo.FindById(sessionID)
+func (rss *RemoteSessionService) GetAdminUserIDForActiveSession(ctx context.Context, sessionID string) (string, error) {
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
 	if err != nil {
 		return "", fmt.Errorf("error finding session: %w", err)
 	}
@@ -358,8 +566,8 @@ func (rss *RemoteSessionService) GetAdminUserIDForActiveSession(sessionID string
 }
 
 // GetClientPCIDForActiveSession obtiene el ID del PC cliente para una sesión activa
-func (rss *RemoteSessionService) GetClientPCIDForActiveSession(sessionID string) (string, error) {
-	session, err := rss.sessionRepo.FindById(sessionID)
+func (rss *RemoteSessionService) GetClientPCIDForActiveSession(ctx context.Context, sessionID string) (string, error) {
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
 	if err != nil {
 		return "", fmt.Errorf("error finding session: %w", err)
 	}
@@ -376,8 +584,8 @@ func (rss *RemoteSessionService) GetClientPCIDForActiveSession(sessionID string)
 }
 
 // ValidateStreamingPermission valida que se puede hacer streaming para una sesión
-func (rss *RemoteSessionService) ValidateStreamingPermission(sessionID, clientPCID string) error {
-	session, err := rss.sessionRepo.FindById(sessionID)
+func (rss *RemoteSessionService) ValidateStreamingPermission(ctx context.Context, sessionID, clientPCID string) error {
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
 	if err != nil {
 		return fmt.Errorf("error finding session: %w", err)
 	}
@@ -399,8 +607,8 @@ func (rss *RemoteSessionService) ValidateStreamingPermission(sessionID, clientPC
 }
 
 // ValidateInputCommandPermission valida que se puede enviar un comando de input
-func (rss *RemoteSessionService) ValidateInputCommandPermission(sessionID, adminUserID string) error {
-	session, err := rss.sessionRepo.FindById(sessionID)
+func (rss *RemoteSessionService) ValidateInputCommandPermission(ctx context.Context, sessionID, adminUserID string) error {
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
 	if err != nil {
 		return fmt.Errorf("error finding session: %w", err)
 	}
@@ -422,10 +630,11 @@ func (rss *RemoteSessionService) ValidateInputCommandPermission(sessionID, admin
 }
 
 // HandleClientPCDisconnect se encarga de limpiar/finalizar sesiones
-// cuando un PC cliente se desconecta.
-func (rss *RemoteSessionService) HandleClientPCDisconnect(clientPCID string) error {
-	log.Printf("⚡ Handling disconnect for PCID: %s. Checking for active/pending sessions.", clientPCID)
-	sessions, err := rss.sessionRepo.FindByClientPCID(clientPCID)
+// cuando un PC cliente se desconecta. El motivo de cierre determina el estado final
+// de las sesiones activas: cierre limpio → ENDED_BY_CLIENT, cierre anormal → FAILED.
+func (rss *RemoteSessionService) HandleClientPCDisconnect(ctx context.Context, clientPCID string, reason remotesession.DisconnectReason) error {
+	log.Printf("⚡ Handling disconnect for PCID: %s (%s). Checking for active/pending sessions.", clientPCID, reason)
+	sessions, err := rss.sessionRepo.FindByClientPCID(ctx, clientPCID)
 	if err != nil {
 		// Si no se encuentran sesiones o hay un error que no sea 'not found',
 		// podríamos querer loguearlo pero no necesariamente detener todo.
@@ -446,67 +655,64 @@ func (rss *RemoteSessionService) HandleClientPCDisconnect(clientPCID string) err
 	var sessionsCleanedCount int = 0
 	for _, session := range sessions {
 		originalStatus := session.Status()
-		actionTaken := false
-		var newStatusForRepo remotesession.SessionStatus
-		var internalErr error
+		var transition func(session *remotesession.RemoteSession) (bool, error)
 
 		log.Printf("🔎 Checking session %s for disconnected PCID %s (status: %s)", session.SessionID(), clientPCID, originalStatus)
 
 		if originalStatus == remotesession.StatusActive {
-			log.Printf("Ending ACTIVE session %s for disconnected PC %s.", session.SessionID(), clientPCID)
-			internalErr = session.End(remotesession.StatusEndedByClient) // O StatusFailed
-			if internalErr != nil {
-				log.Printf("⚠️ Error calling End(StatusEndedByClient) on session %s: %v. Entity status: %s", session.SessionID(), internalErr, session.Status())
+			transition = func(session *remotesession.RemoteSession) (bool, error) {
+				endStatus := reason.EndStatus()
+				log.Printf("Ending ACTIVE session %s for disconnected PC %s with status %s.", session.SessionID(), clientPCID, endStatus)
+				if err := session.End(endStatus); err != nil {
+					// Una transición inválida no modifica la entidad: no hay nada que persistir
+					log.Printf("⚠️ Error calling End(%s) on session %s: %v", endStatus, session.SessionID(), err)
+					return false, nil
+				}
+				return true, nil
 			}
-			newStatusForRepo = session.Status()
-			actionTaken = true
 		} else if originalStatus == remotesession.StatusPendingApproval {
-			log.Printf("Rejecting PENDING_APPROVAL session %s for disconnected PC %s.", session.SessionID(), clientPCID)
-			internalErr = session.Reject()
-			if internalErr != nil {
-				log.Printf("⚠️ Error calling Reject() on PENDING_APPROVAL session %s: %v. Entity status: %s", session.SessionID(), internalErr, session.Status())
+			transition = func(session *remotesession.RemoteSession) (bool, error) {
+				log.Printf("Rejecting PENDING_APPROVAL session %s for disconnected PC %s.", session.SessionID(), clientPCID)
+				if err := session.Reject(); err != nil {
+					log.Printf("⚠️ Error calling Reject() on PENDING_APPROVAL session %s: %v", session.SessionID(), err)
+					return false, nil
+				}
+				return true, nil
 			}
-			newStatusForRepo = session.Status()
-			actionTaken = true
 		}
 
-		if actionTaken {
-			// Solo actualizar si el estado de la entidad realmente cambió o si la operación tuvo éxito
-			// (internalErr == nil indica que la operación de cambio de estado en la entidad tuvo éxito)
-			if newStatusForRepo != originalStatus || internalErr == nil {
-				errUpdate := rss.sessionRepo.UpdateStatus(session.SessionID(), newStatusForRepo)
-				if errUpdate != nil {
-					log.Printf("❌ CRITICAL: Failed to update session %s status in repo to %s (was %s) during PC disconnect: %v",
-						session.SessionID(), newStatusForRepo, originalStatus, errUpdate)
-				} else {
-					log.Printf("✅ Session %s for disconnected PC %s updated to %s (was %s).",
-						session.SessionID(), clientPCID, newStatusForRepo, originalStatus)
-					sessionsCleanedCount++
-
-					// Notificar al AdminWeb que la sesión terminó
-					if rss.notifySessionEndedCallback != nil {
-						log.Printf("📡 Notifying AdminWeb that session %s ended", session.SessionID())
-						rss.notifySessionEndedCallback(session.SessionID(), session.ClientPCID(), session.AdminUserID())
-					}
-
-					// Aquí podrías emitir eventos de dominio si es necesario
-					// event := events.NewRemoteSessionEndedEvent(session.SessionID(), session.AdminUserID(), session.ClientPCID(), string(newStatusForRepo), "Client PC disconnected")
-					// rss.eventBus.Publish(event)
-				}
-			} else {
-				log.Printf("ℹ️ Session %s (original status: %s) processed for disconnect, but no state change to repo was made or repo update needed (likely due to internal error during state change: %v). Entity current status: %s",
-					session.SessionID(), originalStatus, internalErr, session.Status())
-			}
+		if transition == nil {
+			continue
+		}
+
+		// Se vuelve a leer con el lock de decisión: el cliente pudo aceptar o rechazar justo antes de desconectarse
+		ended, errUpdate := rss.transitionIfUnchanged(ctx, session.SessionID(), originalStatus, transition)
+		if errUpdate != nil {
+			log.Printf("❌ CRITICAL: Failed to update session %s status in repo (was %s) during PC disconnect: %v",
+				session.SessionID(), originalStatus, errUpdate)
+			continue
 		}
+		if ended == nil {
+			continue
+		}
+		log.Printf("✅ Session %s for disconnected PC %s updated to %s (was %s).",
+			session.SessionID(), clientPCID, ended.Status(), originalStatus)
+		sessionsCleanedCount++
+
+		// Notificar al AdminWeb que la sesión terminó
+		rss.sessionEnded(ctx, ended)
 	}
 	log.Printf("ℹ️ Finished disconnect handling for PCID %s. Processed %d sessions, cleaned %d sessions that changed state.", clientPCID, len(sessions), sessionsCleanedCount)
 	return nil
 }
 
 // EndSessionByAdmin finaliza una sesión por parte del administrador
-func (rss *RemoteSessionService) EndSessionByAdmin(sessionID string) error {
+func (rss *RemoteSessionService) EndSessionByAdmin(ctx context.Context, sessionID string) error {
+	unlock := rss.decisionLocks.lock(sessionID)
+	defer unlock()
+
 	// Obtener sesión
-	session, err := rss.sessionRepo.FindById(sessionID)
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
 	if err != nil {
 		return fmt.Errorf("error finding session: %w", err)
 	}
@@ -519,45 +725,13 @@ func (rss *RemoteSessionService) EndSessionByAdmin(sessionID string) error {
 		return fmt.Errorf("session is not active")
 	}
 
-	// Guardar datos antes de finalizar para el log
-	adminUserID := session.AdminUserID()
-	clientPCID := session.ClientPCID()
-
-	// Finalizar sesión usando el método de dominio
-	err = session.End(remotesession.StatusEndedByAdmin)
-	if err != nil {
-		return fmt.Errorf("error ending session: %w", err)
-	}
-
-	// Actualizar en repositorio
-	err = rss.sessionRepo.UpdateStatus(sessionID, session.Status())
-	if err != nil {
-		return fmt.Errorf("error updating session status: %w", err)
-	}
-
-	// 📝 REGISTRAR LOG DE AUDITORÍA
-	ctx := context.Background()
-	err = rss.actionLogService.LogSessionEnded(ctx, sessionID, adminUserID, "ended_by_admin")
-	if err != nil {
-		// Log error pero no falle la operación principal
-		log.Printf("⚠️ Warning: Failed to log session ended audit entry: %v", err)
-	} else {
-		log.Printf("📝 Audit log registered: Session %s ended by admin %s", sessionID, adminUserID)
-	}
-
-	// Notificar al AdminWeb que la sesión terminó
-	if rss.notifySessionEndedCallback != nil {
-		log.Printf("📡 Notifying AdminWeb that session %s ended by admin", sessionID)
-		rss.notifySessionEndedCallback(sessionID, clientPCID, adminUserID)
-	}
-
-	// Notificar al cliente que la sesión terminó
-	if rss.notifyClientSessionEndedCallback != nil {
-		log.Printf("📱 Notifying client %s that session %s ended by admin", clientPCID, sessionID)
-		rss.notifyClientSessionEndedCallback(sessionID, clientPCID)
+	// Finalizar con la secuencia común: estado, auditoría y avisos al AdminWeb y al cliente
+	endByAdmin := func() error { return session.End(remotesession.StatusEndedByAdmin) }
+	if err := rss.endSession(ctx, session, endByAdmin, "ended_by_admin"); err != nil {
+		return err
 	}
 
-	log.Printf("✅ Session %s ended by admin %s", sessionID, adminUserID)
+	log.Printf("✅ Session %s ended by admin %s", sessionID, session.AdminUserID())
 	return nil
 }
 
@@ -566,21 +740,21 @@ func (rss *RemoteSessionService) GetAllSessionsWithVideos(ctx context.Context) (
 	var allSessions []*remotesession.RemoteSession
 
 	// Obtener sesiones terminadas exitosamente
-	sessions1, err := rss.sessionRepo.FindByStatus(remotesession.StatusEnded)
+	sessions1, err := rss.sessionRepo.FindByStatus(ctx, remotesession.StatusEnded)
 	if err != nil {
 		return nil, fmt.Errorf("error finding sessions ended successfully: %w", err)
 	}
 	allSessions = append(allSessions, sessions1...)
 
 	// Obtener sesiones terminadas por admin
-	sessions2, err := rss.sessionRepo.FindByStatus(remotesession.StatusEndedByAdmin)
+	sessions2, err := rss.sessionRepo.FindByStatus(ctx, remotesession.StatusEndedByAdmin)
 	if err != nil {
 		return nil, fmt.Errorf("error finding sessions ended by admin: %w", err)
 	}
 	allSessions = append(allSessions, sessions2...)
 
 	// Obtener sesiones terminadas por cliente
-	sessions3, err := rss.sessionRepo.FindByStatus(remotesession.StatusEndedByClient)
+	sessions3, err := rss.sessionRepo.FindByStatus(ctx, remotesession.StatusEndedByClient)
 	if err != nil {
 		return nil, fmt.Errorf("error finding sessions ended by client: %w", err)
 	}
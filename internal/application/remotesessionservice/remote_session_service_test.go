@@ -0,0 +1,593 @@
+package remotesessionservice
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/events"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	sharedevents "github.com/unikyri/escritorio-remoto-backend/internal/domain/shared/events"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+)
+
+// MockRemoteSessionRepository es un mock del repositorio de sesiones remotas
+type MockRemoteSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRemoteSessionRepository) Save(ctx context.Context, session *remotesession.RemoteSession) error {
+	return m.Called(ctx, session).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) FindById(ctx context.Context, id string) (*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) UpdateStatus(ctx context.Context, id string, status remotesession.SessionStatus) error {
+	return m.Called(ctx, id, status).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) FindByAdminUserID(ctx context.Context, adminUserID string) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, adminUserID)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindByClientPCID(ctx context.Context, clientPCID string) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, clientPCID)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindActiveSessions(ctx context.Context) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindPendingSessions(ctx context.Context) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) Update(ctx context.Context, session *remotesession.RemoteSession) error {
+	return m.Called(ctx, session).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) Delete(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) FindByStatus(ctx context.Context, status remotesession.SessionStatus) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindSessionsByDateRange(ctx context.Context, adminUserID string, startDate, endDate string) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, adminUserID, startDate, endDate)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindAccessReport(ctx context.Context, adminUserID, clientPCID string, from, to time.Time, limit, offset int) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, adminUserID, clientPCID, from, to, limit, offset)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) CountAccessReport(ctx context.Context, adminUserID, clientPCID string, from, to time.Time) (int, error) {
+	args := m.Called(ctx, adminUserID, clientPCID, from, to)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) CountSessionsByUser(ctx context.Context, adminUserID string) (int64, error) {
+	args := m.Called(ctx, adminUserID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockUserRepository es un mock del repositorio de usuarios
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) FindByUsername(username string) (*user.User, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByID(userID string) (*user.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByIDs(ctx context.Context, userIDs []string) (map[string]*user.User, error) {
+	args := m.Called(ctx, userIDs)
+	return args.Get(0).(map[string]*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Save(u *user.User) error {
+	return m.Called(u).Error(0)
+}
+
+func (m *MockUserRepository) Create(u *user.User) error {
+	return m.Called(u).Error(0)
+}
+
+func (m *MockUserRepository) IncrementTokenVersion(userID string) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+// MockClientPCRepository es un mock del repositorio de PCs cliente
+type MockClientPCRepository struct {
+	mock.Mock
+}
+
+func (m *MockClientPCRepository) Save(ctx context.Context, pc *clientpc.ClientPC) error {
+	return m.Called(ctx, pc).Error(0)
+}
+
+func (m *MockClientPCRepository) FindByID(ctx context.Context, pcID string) (*clientpc.ClientPC, error) {
+	args := m.Called(ctx, pcID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockClientPCRepository) FindByIDs(ctx context.Context, pcIDs []string) (map[string]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, pcIDs)
+	return args.Get(0).(map[string]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockClientPCRepository) FindByIdentifierAndOwner(ctx context.Context, identifier string, ownerID string) (*clientpc.ClientPC, error) {
+	args := m.Called(ctx, identifier, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockClientPCRepository) FindByOwner(ctx context.Context, ownerID string) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, ownerID)
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockClientPCRepository) FindOnlineByOwner(ctx context.Context, ownerID string) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, ownerID)
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockClientPCRepository) UpdateConnectionStatus(ctx context.Context, pcID string, status clientpc.PCConnectionStatus) error {
+	return m.Called(ctx, pcID, status).Error(0)
+}
+
+func (m *MockClientPCRepository) UpdateLastSeen(ctx context.Context, pcID string) error {
+	return m.Called(ctx, pcID).Error(0)
+}
+
+func (m *MockClientPCRepository) UpdateAutoAcceptControl(ctx context.Context, pcID string, enabled bool) error {
+	return m.Called(ctx, pcID, enabled).Error(0)
+}
+
+func (m *MockClientPCRepository) Delete(ctx context.Context, pcID string) error {
+	return m.Called(ctx, pcID).Error(0)
+}
+
+func (m *MockClientPCRepository) FindAll(ctx context.Context, limit, offset int) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockClientPCRepository) CountByOwner(ctx context.Context, ownerID string) (int, error) {
+	args := m.Called(ctx, ownerID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockClientPCRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+// MockActionLogService es un mock del servicio de auditoría
+type MockActionLogService struct {
+	mock.Mock
+}
+
+func (m *MockActionLogService) LogAction(ctx context.Context, actionType actionlog.ActionType, description string,
+	performedByUserID string, subjectEntityID *string, subjectEntityType *string,
+	details map[string]interface{}) error {
+	return m.Called(ctx, actionType, description, performedByUserID, subjectEntityID, subjectEntityType, details).Error(0)
+}
+
+func (m *MockActionLogService) LogSessionEnded(ctx context.Context, sessionID, adminUserID, reason string) error {
+	return m.Called(ctx, sessionID, adminUserID, reason).Error(0)
+}
+
+func (m *MockActionLogService) GetRecentLogs(ctx context.Context, limit int) ([]*actionlog.ActionLog, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*actionlog.ActionLog), args.Error(1)
+}
+
+func (m *MockActionLogService) GetLogsByUser(ctx context.Context, userID string, limit, offset int) ([]*actionlog.ActionLog, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	return args.Get(0).([]*actionlog.ActionLog), args.Error(1)
+}
+
+func (m *MockActionLogService) GetLogsByActionType(ctx context.Context, actionType actionlog.ActionType, limit, offset int) ([]*actionlog.ActionLog, error) {
+	args := m.Called(ctx, actionType, limit, offset)
+	return args.Get(0).([]*actionlog.ActionLog), args.Error(1)
+}
+
+func (m *MockActionLogService) GetLogsByEntity(ctx context.Context, entityID, entityType string, limit, offset int) ([]*actionlog.ActionLog, error) {
+	args := m.Called(ctx, entityID, entityType, limit, offset)
+	return args.Get(0).([]*actionlog.ActionLog), args.Error(1)
+}
+
+func (m *MockActionLogService) GetLogsCount(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+// MockEventBus es un mock del bus de eventos de dominio
+type MockEventBus struct {
+	mock.Mock
+}
+
+func (m *MockEventBus) Publish(event sharedevents.DomainEvent) {
+	m.Called(event)
+}
+
+func (m *MockEventBus) Subscribe(eventType string, handler events.EventHandler) {
+	m.Called(eventType, handler)
+}
+
+const (
+	testAdminUserID = "550e8400-e29b-41d4-a716-446655440000"
+	testClientPCID  = "550e8400-e29b-41d4-a716-446655440001"
+)
+
+// newInitiateSessionFixture prepara los mocks comunes para InitiateSession sobre un PC online
+func newInitiateSessionFixture(autoAccept bool) (*RemoteSessionService, *MockRemoteSessionRepository, *MockActionLogService, *MockEventBus) {
+	sessionRepo := new(MockRemoteSessionRepository)
+	userRepo := new(MockUserRepository)
+	pcRepo := new(MockClientPCRepository)
+	actionLogService := new(MockActionLogService)
+	eventBus := new(MockEventBus)
+
+	admin := user.NewUser(testAdminUserID, "admin", "", "hashed", user.RoleAdministrator)
+	pc, _ := clientpc.NewClientPC(testClientPCID, "lab-pc-01", "192.168.1.50", testAdminUserID)
+	pc.SetOnline()
+	pc.SetAutoAcceptControl(autoAccept)
+
+	sessionRepo.On("FindByClientPCID", mock.Anything, testClientPCID).Return([]*remotesession.RemoteSession{}, nil)
+	userRepo.On("FindByID", testAdminUserID).Return(admin, nil)
+	pcRepo.On("FindByID", mock.Anything, testClientPCID).Return(pc, nil)
+	sessionRepo.On("Save", mock.Anything, mock.AnythingOfType("*remotesession.RemoteSession")).Return(nil)
+	eventBus.On("Publish", mock.Anything).Return()
+	actionLogService.On("LogAction", mock.Anything, actionlog.ActionRemoteSessionRequested, mock.Anything,
+		testAdminUserID, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	actionLogService.On("LogAction", mock.Anything, actionlog.ActionRemoteSessionStarted, mock.Anything,
+		testAdminUserID, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	service := NewRemoteSessionService(sessionRepo, userRepo, pcRepo, actionLogService, eventBus)
+	return service, sessionRepo, actionLogService, eventBus
+}
+
+func TestRemoteSessionService_InitiateSession_AutoAcceptEnabled(t *testing.T) {
+	// Arrange
+	service, sessionRepo, actionLogService, eventBus := newInitiateSessionFixture(true)
+	actionLogService.On("LogAction", mock.Anything, actionlog.ActionRemoteSessionAutoAccepted, mock.Anything,
+		testAdminUserID, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// Act
+	session, err := service.InitiateSession(context.Background(), testAdminUserID, testClientPCID, remotesession.Justification{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, session)
+	assert.Equal(t, remotesession.StatusActive, session.Status())
+	assert.NotNil(t, session.StartTime())
+
+	sessionRepo.AssertExpectations(t)
+	// Se registran la solicitud, la auto-aceptación y el inicio de la sesión
+	actionLogService.AssertNumberOfCalls(t, "LogAction", 3)
+	actionLogService.AssertCalled(t, "LogAction", mock.Anything, actionlog.ActionRemoteSessionStarted, mock.Anything,
+		testAdminUserID, mock.Anything, mock.Anything, mock.Anything)
+	// Se publican los eventos de sesión iniciada y aceptada
+	eventBus.AssertNumberOfCalls(t, "Publish", 2)
+}
+
+func TestRemoteSessionService_InitiateSession_PromptsClientByDefault(t *testing.T) {
+	// Arrange
+	service, sessionRepo, actionLogService, eventBus := newInitiateSessionFixture(false)
+
+	// Act
+	session, err := service.InitiateSession(context.Background(), testAdminUserID, testClientPCID, remotesession.Justification{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, session)
+	assert.Equal(t, remotesession.StatusPendingApproval, session.Status())
+	assert.Nil(t, session.StartTime())
+
+	sessionRepo.AssertExpectations(t)
+	// Solo se registra la solicitud: no hubo auto-aceptación
+	actionLogService.AssertNumberOfCalls(t, "LogAction", 1)
+	actionLogService.AssertNotCalled(t, "LogAction", mock.Anything, actionlog.ActionRemoteSessionAutoAccepted,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	eventBus.AssertNumberOfCalls(t, "Publish", 1)
+}
+
+func TestRemoteSessionService_ResolveSessionLabels_BatchLookupWithMissingIDs(t *testing.T) {
+	// Arrange
+	sessionRepo := new(MockRemoteSessionRepository)
+	userRepo := new(MockUserRepository)
+	pcRepo := new(MockClientPCRepository)
+	service := NewRemoteSessionService(sessionRepo, userRepo, pcRepo, new(MockActionLogService), new(MockEventBus))
+
+	const deletedPCID = "550e8400-e29b-41d4-a716-446655440099"
+	first, _ := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	second, _ := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	orphan, _ := remotesession.NewRemoteSession(testAdminUserID, deletedPCID)
+
+	admin := user.NewUser(testAdminUserID, "admin", "", "hashed", user.RoleAdministrator)
+	pc, _ := clientpc.NewClientPC(testClientPCID, "lab-pc-01", "192.168.1.50", testAdminUserID)
+
+	// Cada ID se consulta una sola vez, en una única llamada por repositorio
+	pcRepo.On("FindByIDs", mock.Anything, []string{testClientPCID, deletedPCID}).
+		Return(map[string]*clientpc.ClientPC{testClientPCID: pc}, nil).Once()
+	userRepo.On("FindByIDs", mock.Anything, []string{testAdminUserID}).
+		Return(map[string]*user.User{testAdminUserID: admin}, nil).Once()
+
+	// Act
+	pcNames, adminUsernames, err := service.ResolveSessionLabels(context.Background(), []*remotesession.RemoteSession{first, second, orphan})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{testClientPCID: "lab-pc-01"}, pcNames)
+	assert.Equal(t, map[string]string{testAdminUserID: "admin"}, adminUsernames)
+	assert.NotContains(t, pcNames, deletedPCID)
+
+	pcRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func TestRemoteSessionService_ResolveSessionLabels_EmptyList(t *testing.T) {
+	// Arrange
+	pcRepo := new(MockClientPCRepository)
+	userRepo := new(MockUserRepository)
+	service := NewRemoteSessionService(new(MockRemoteSessionRepository), userRepo, pcRepo, new(MockActionLogService), new(MockEventBus))
+
+	// Act
+	pcNames, adminUsernames, err := service.ResolveSessionLabels(context.Background(), nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, pcNames)
+	assert.Empty(t, adminUsernames)
+	pcRepo.AssertNotCalled(t, "FindByIDs")
+	userRepo.AssertNotCalled(t, "FindByIDs")
+}
+
+// statefulSessionRepository simula la BD para una sesión: cada lectura crea una entidad nueva con el estado persistido
+type statefulSessionRepository struct {
+	MockRemoteSessionRepository
+	mu      sync.Mutex
+	session *remotesession.RemoteSession
+	updates int
+}
+
+func newStatefulSessionRepository() *statefulSessionRepository {
+	session, _ := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	return &statefulSessionRepository{session: session}
+}
+
+func (r *statefulSessionRepository) FindById(ctx context.Context, id string) (*remotesession.RemoteSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.session
+	return remotesession.NewRemoteSessionFromDB(s.SessionID(), s.AdminUserID(), s.ClientPCID(), s.StartTime(),
+		s.EndTime(), s.Status(), s.SessionVideoID(), s.CreatedAt(), s.UpdatedAt()), nil
+}
+
+func (r *statefulSessionRepository) UpdateStatus(ctx context.Context, id string, status remotesession.SessionStatus) error {
+	// Ensanchar la ventana entre lectura y escritura para que un acceso sin serializar se note
+	time.Sleep(time.Millisecond)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.session
+	now := time.Now().UTC()
+	r.session = remotesession.NewRemoteSessionFromDB(s.SessionID(), s.AdminUserID(), s.ClientPCID(), &now,
+		s.EndTime(), status, s.SessionVideoID(), s.CreatedAt(), now)
+	r.updates++
+	return nil
+}
+
+func (r *statefulSessionRepository) FindByClientPCID(ctx context.Context, clientPCID string) ([]*remotesession.RemoteSession, error) {
+	session, err := r.FindById(ctx, "")
+	return []*remotesession.RemoteSession{session}, err
+}
+
+func (r *statefulSessionRepository) status() remotesession.SessionStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.session.Status()
+}
+
+func TestRemoteSessionService_AcceptAndRejectConcurrently_FirstDecisionWins(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		// Arrange
+		sessionRepo := newStatefulSessionRepository()
+		eventBus := new(MockEventBus)
+		eventBus.On("Publish", mock.Anything).Return()
+		service := NewRemoteSessionService(sessionRepo, new(MockUserRepository), new(MockClientPCRepository),
+			newSessionStartedActionLog(), eventBus)
+		sessionID := sessionRepo.session.SessionID()
+
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		var acceptErr, rejectErr error
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			<-start
+			acceptErr = service.AcceptSession(context.Background(), sessionID)
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			rejectErr = service.RejectSession(context.Background(), sessionID, "user declined")
+		}()
+
+		// Act
+		close(start)
+		wg.Wait()
+
+		// Assert
+		if acceptErr == nil {
+			assert.ErrorIs(t, rejectErr, ErrSessionAlreadyDecided)
+			assert.Equal(t, remotesession.StatusActive, sessionRepo.status())
+		} else {
+			assert.NoError(t, rejectErr)
+			assert.ErrorIs(t, acceptErr, ErrSessionAlreadyDecided)
+			assert.Equal(t, remotesession.StatusRejected, sessionRepo.status())
+		}
+		assert.Equal(t, 1, sessionRepo.updates)
+		eventBus.AssertNumberOfCalls(t, "Publish", 1)
+	}
+}
+
+func TestRemoteSessionService_AcceptAndTimeoutCleanupConcurrently_FirstDecisionWins(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		// Arrange - con un timeout mínimo la limpieza considera vencida la solicitud que el cliente está aceptando
+		sessionRepo := newStatefulSessionRepository()
+		eventBus := new(MockEventBus)
+		eventBus.On("Publish", mock.Anything).Return()
+		service := NewRemoteSessionService(sessionRepo, new(MockUserRepository), new(MockClientPCRepository),
+			newSessionStartedActionLog(), eventBus)
+		service.SetApprovalTimeout(time.Nanosecond)
+		sessionID := sessionRepo.session.SessionID()
+
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		var acceptErr, cleanupErr error
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			<-start
+			acceptErr = service.AcceptSession(context.Background(), sessionID)
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			cleanupErr = service.CleanupStuckSessions(context.Background(), testClientPCID)
+		}()
+
+		// Act
+		close(start)
+		wg.Wait()
+
+		// Assert - la limpieza no sobrescribe una aceptación ni la aceptación revive una sesión rechazada; una
+		// aceptación que llega antes que la limpieza también ve la solicitud vencida
+		assert.NoError(t, cleanupErr)
+		if acceptErr == nil {
+			assert.Equal(t, remotesession.StatusActive, sessionRepo.status())
+		} else {
+			assert.True(t, errors.Is(acceptErr, ErrSessionAlreadyDecided) || errors.Is(acceptErr, ErrSessionApprovalExpired), acceptErr)
+			assert.Equal(t, remotesession.StatusRejected, sessionRepo.status())
+		}
+		assert.Equal(t, 1, sessionRepo.updates)
+	}
+}
+
+// newSessionStartedActionLog auditoría que acepta el REMOTE_SESSION_STARTED de una sesión aceptada
+func newSessionStartedActionLog() *MockActionLogService {
+	actionLogService := new(MockActionLogService)
+	actionLogService.On("LogAction", mock.Anything, actionlog.ActionRemoteSessionStarted, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return actionLogService
+}
+
+func TestRemoteSessionService_AcceptSession_AuditsSessionStarted(t *testing.T) {
+	// Arrange
+	sessionRepo := newStatefulSessionRepository()
+	eventBus := new(MockEventBus)
+	eventBus.On("Publish", mock.Anything).Return()
+	actionLogService := newSessionStartedActionLog()
+	service := NewRemoteSessionService(sessionRepo, new(MockUserRepository), new(MockClientPCRepository),
+		actionLogService, eventBus)
+	sessionID := sessionRepo.session.SessionID()
+
+	// Act
+	err := service.AcceptSession(context.Background(), sessionID)
+	lateErr := service.AcceptSession(context.Background(), sessionID)
+
+	// Assert - el inicio se registra una vez, al aceptarse
+	assert.NoError(t, err)
+	assert.ErrorIs(t, lateErr, ErrSessionAlreadyDecided)
+	actionLogService.AssertNumberOfCalls(t, "LogAction", 1)
+	details := actionLogService.Calls[0].Arguments.Get(6).(map[string]interface{})
+	assert.Equal(t, sessionID, details["session_id"])
+	assert.Equal(t, "client", details["accepted_by"])
+}
+
+func TestRemoteSessionService_RejectAfterAccept_ReturnsAlreadyDecided(t *testing.T) {
+	// Arrange
+	sessionRepo := newStatefulSessionRepository()
+	eventBus := new(MockEventBus)
+	eventBus.On("Publish", mock.Anything).Return()
+	service := NewRemoteSessionService(sessionRepo, new(MockUserRepository), new(MockClientPCRepository),
+		newSessionStartedActionLog(), eventBus)
+	sessionID := sessionRepo.session.SessionID()
+	assert.NoError(t, service.AcceptSession(context.Background(), sessionID))
+
+	// Act
+	rejectErr := service.RejectSession(context.Background(), sessionID, "late retry")
+	acceptErr := service.AcceptSession(context.Background(), sessionID)
+
+	// Assert
+	assert.True(t, errors.Is(rejectErr, ErrSessionAlreadyDecided))
+	assert.True(t, errors.Is(acceptErr, ErrSessionAlreadyDecided))
+	assert.Contains(t, rejectErr.Error(), string(remotesession.StatusActive))
+	assert.Equal(t, remotesession.StatusActive, sessionRepo.status())
+	assert.Equal(t, 1, sessionRepo.updates)
+}
+
+func TestRemoteSessionService_RejectSession_NotifiesUnsuccessfulEnd(t *testing.T) {
+	// Arrange
+	sessionRepo := newStatefulSessionRepository()
+	eventBus := new(MockEventBus)
+	eventBus.On("Publish", mock.Anything).Return()
+	service := NewRemoteSessionService(sessionRepo, new(MockUserRepository), new(MockClientPCRepository),
+		new(MockActionLogService), eventBus)
+	sessionID := sessionRepo.session.SessionID()
+
+	var notifiedSessionID, notifiedAdminID string
+	var notifiedStatus remotesession.SessionStatus
+	service.SetUnsuccessfulSessionEndNotifier(func(sessionID, adminUserID string, status remotesession.SessionStatus) {
+		notifiedSessionID, notifiedAdminID, notifiedStatus = sessionID, adminUserID, status
+	})
+
+	// Act
+	err := service.RejectSession(context.Background(), sessionID, "user declined")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, sessionID, notifiedSessionID)
+	assert.Equal(t, testAdminUserID, notifiedAdminID)
+	assert.Equal(t, remotesession.StatusRejected, notifiedStatus)
+}
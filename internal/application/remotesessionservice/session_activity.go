@@ -0,0 +1,153 @@
+package remotesessionservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+// ClientActivityState presencia del usuario en el PC cliente según su última entrada de teclado o ratón
+type ClientActivityState string
+
+const (
+	ClientActivityActive ClientActivityState = "ACTIVE"
+	ClientActivityIdle   ClientActivityState = "IDLE"
+)
+
+var (
+	// ErrInvalidActivityState el cliente informó un estado que no es ACTIVE ni IDLE
+	ErrInvalidActivityState = errors.New("invalid client activity state")
+	// ErrActivityReportNotPermitted el PC no puede informar actividad de esa sesión
+	ErrActivityReportNotPermitted = errors.New("activity report not permitted for this session")
+)
+
+// SessionActivity presencia del usuario en el PC durante una sesión
+type SessionActivity struct {
+	SessionID    string
+	AdminUserID  string
+	ClientPCID   string
+	State        ClientActivityState
+	LastInputAge time.Duration // antigüedad de la última entrada en el momento del informe
+	ReportedAt   time.Time
+	ChangedAt    time.Time     // inicio del estado actual; para IDLE, el momento de la última entrada
+	IdleCount    int           // veces que el usuario pasó a IDLE
+	IdleDuration time.Duration // tiempo total en IDLE, incluido el periodo en curso
+}
+
+// sessionActivities presencia informada por sesión; se descarta al terminar la sesión
+type sessionActivities struct {
+	sessions map[string]*SessionActivity
+	mutex    sync.Mutex
+}
+
+func newSessionActivities() *sessionActivities {
+	return &sessionActivities{sessions: make(map[string]*SessionActivity)}
+}
+
+// RecordClientActivity registra el estado de actividad que informa el PC de una sesión activa y retorna la
+// instantánea resultante; changed es true si el estado cambió respecto al último informe (o es el primero)
+func (rss *RemoteSessionService) RecordClientActivity(ctx context.Context, sessionID, clientPCID string, state ClientActivityState, lastInputAge time.Duration) (SessionActivity, bool, error) {
+	if state != ClientActivityActive && state != ClientActivityIdle {
+		return SessionActivity{}, false, fmt.Errorf("%w: %q", ErrInvalidActivityState, state)
+	}
+	if lastInputAge < 0 {
+		lastInputAge = 0
+	}
+
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
+	if err != nil {
+		return SessionActivity{}, false, fmt.Errorf("error finding session: %w", err)
+	}
+	if session == nil {
+		return SessionActivity{}, false, fmt.Errorf("%w: session not found", ErrActivityReportNotPermitted)
+	}
+	if clientPCID == "" || session.ClientPCID() != clientPCID {
+		return SessionActivity{}, false, fmt.Errorf("%w: client PC ID mismatch", ErrActivityReportNotPermitted)
+	}
+	if session.Status() != remotesession.StatusActive {
+		return SessionActivity{}, false, ErrSessionNotActive
+	}
+
+	now := time.Now()
+	rss.activities.mutex.Lock()
+	defer rss.activities.mutex.Unlock()
+
+	activity, exists := rss.activities.sessions[sessionID]
+	if !exists {
+		activity = &SessionActivity{SessionID: sessionID, ClientPCID: clientPCID}
+		rss.activities.sessions[sessionID] = activity
+	}
+	// El administrador puede cambiar por un traspaso de la sesión
+	activity.AdminUserID = session.AdminUserID()
+
+	changed := !exists || activity.State != state
+	if changed {
+		if activity.State == ClientActivityIdle {
+			activity.IdleDuration += now.Sub(activity.ChangedAt)
+		}
+		activity.State = state
+		activity.ChangedAt = now
+		if state == ClientActivityIdle {
+			// El usuario se ausentó con la última entrada, no cuando el cliente lo detectó
+			activity.ChangedAt = now.Add(-lastInputAge)
+			activity.IdleCount++
+		}
+	}
+	activity.LastInputAge = lastInputAge
+	activity.ReportedAt = now
+
+	return activity.snapshot(now), changed, nil
+}
+
+// GetSessionActivity retorna la presencia informada de una sesión en curso; false si el cliente no informó nada
+func (rss *RemoteSessionService) GetSessionActivity(sessionID string) (SessionActivity, bool) {
+	rss.activities.mutex.Lock()
+	defer rss.activities.mutex.Unlock()
+
+	activity, exists := rss.activities.sessions[sessionID]
+	if !exists {
+		return SessionActivity{}, false
+	}
+	return activity.snapshot(time.Now()), true
+}
+
+// FinishSessionActivity descarta la presencia de una sesión terminada y deja el resumen en el audit log
+func (rss *RemoteSessionService) FinishSessionActivity(ctx context.Context, sessionID string) {
+	rss.activities.mutex.Lock()
+	activity, exists := rss.activities.sessions[sessionID]
+	delete(rss.activities.sessions, sessionID)
+	rss.activities.mutex.Unlock()
+
+	if !exists || rss.actionLogService == nil {
+		return
+	}
+
+	summary := activity.snapshot(time.Now())
+	entityType := "REMOTE_SESSION"
+	details := map[string]interface{}{
+		"client_pc_id":  summary.ClientPCID,
+		"final_state":   string(summary.State),
+		"idle_count":    summary.IdleCount,
+		"idle_seconds":  int(summary.IdleDuration.Seconds()),
+		"last_reported": summary.ReportedAt.Format(time.RFC3339),
+	}
+	description := fmt.Sprintf("Client activity during session %s", sessionID)
+	if err := rss.actionLogService.LogAction(ctx, actionlog.ActionRemoteSessionActivity, description, summary.AdminUserID, &sessionID, &entityType, details); err != nil {
+		log.Printf("⚠️ Warning: Failed to log session activity audit entry: %v", err)
+	}
+}
+
+// snapshot copia del estado con el periodo IDLE en curso sumado al total. Requiere mutex.
+func (a *SessionActivity) snapshot(now time.Time) SessionActivity {
+	snapshot := *a
+	if a.State == ClientActivityIdle {
+		snapshot.IdleDuration += now.Sub(a.ChangedAt)
+	}
+	return snapshot
+}
@@ -0,0 +1,82 @@
+package remotesessionservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+func TestRemoteSessionService_RecordClientActivity_RejectsInvalidState(t *testing.T) {
+	// Arrange
+	service, session := newRecordingPermissionFixture(t)
+
+	// Act
+	_, _, err := service.RecordClientActivity(context.Background(), session.SessionID(), testClientPCID, "AWAY", 0)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidActivityState)
+}
+
+func TestRemoteSessionService_FinishSessionActivity_LogsSummaryAndForgetsSession(t *testing.T) {
+	// Arrange
+	session, err := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+	actionLog := new(MockActionLogService)
+	actionLog.On("LogAction", mock.Anything, actionlog.ActionRemoteSessionActivity, mock.Anything, testAdminUserID,
+		mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	service := NewRemoteSessionService(sessionRepo, nil, nil, actionLog, nil)
+
+	ctx := context.Background()
+	_, _, err = service.RecordClientActivity(ctx, session.SessionID(), testClientPCID, ClientActivityIdle, 2*time.Minute)
+	require.NoError(t, err)
+	_, changed, err := service.RecordClientActivity(ctx, session.SessionID(), testClientPCID, ClientActivityActive, 0)
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	// Act
+	service.FinishSessionActivity(ctx, session.SessionID())
+
+	// Assert
+	_, reported := service.GetSessionActivity(session.SessionID())
+	assert.False(t, reported)
+	details := actionLog.Calls[0].Arguments.Get(6).(map[string]interface{})
+	assert.Equal(t, "ACTIVE", details["final_state"])
+	assert.Equal(t, 1, details["idle_count"])
+	assert.GreaterOrEqual(t, details["idle_seconds"], 120)
+}
+
+func TestRemoteSessionService_HandleClientPCDisconnect_FinishesSessionActivity(t *testing.T) {
+	// Arrange - la sesión termina porque el PC se desconecta, no por el administrador
+	session, err := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+	sessionRepo.On("FindByClientPCID", mock.Anything, testClientPCID).Return([]*remotesession.RemoteSession{session}, nil)
+	sessionRepo.On("UpdateStatus", mock.Anything, session.SessionID(), remotesession.StatusFailed).Return(nil)
+	actionLog := new(MockActionLogService)
+	actionLog.On("LogAction", mock.Anything, actionlog.ActionRemoteSessionActivity, mock.Anything, testAdminUserID,
+		mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	service := NewRemoteSessionService(sessionRepo, nil, nil, actionLog, nil)
+
+	ctx := context.Background()
+	_, _, err = service.RecordClientActivity(ctx, session.SessionID(), testClientPCID, ClientActivityIdle, time.Minute)
+	require.NoError(t, err)
+
+	// Act
+	require.NoError(t, service.HandleClientPCDisconnect(ctx, testClientPCID, remotesession.NewDisconnectReason(remotesession.CloseCodeAbnormalClosure, "")))
+
+	// Assert
+	_, reported := service.GetSessionActivity(session.SessionID())
+	assert.False(t, reported)
+	actionLog.AssertExpectations(t)
+}
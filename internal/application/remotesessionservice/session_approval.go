@@ -0,0 +1,77 @@
+package remotesessionservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+// DefaultSessionApprovalTimeout tiempo por defecto que el usuario del PC tiene para aceptar o rechazar una
+// solicitud de control antes de que la limpieza la rechace
+const DefaultSessionApprovalTimeout = 2 * time.Minute
+
+// ErrSessionApprovalExpired indica que la decisión del cliente llegó después del plazo de aprobación
+var ErrSessionApprovalExpired = errors.New("session approval expired")
+
+// SetApprovalTimeout configura cuánto espera una sesión PENDING_APPROVAL la respuesta del cliente
+// (<= 0 mantiene el valor por defecto)
+func (rss *RemoteSessionService) SetApprovalTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		rss.approvalTimeout = timeout
+	}
+}
+
+// ApprovalTimeout tiempo que se anuncia al cliente en remote_control_request; es el mismo umbral con el que
+// ExpirePendingApprovals y CleanupStuckSessions rechazan las solicitudes sin respuesta
+func (rss *RemoteSessionService) ApprovalTimeout() time.Duration {
+	return rss.approvalTimeout
+}
+
+// approvalExpired indica si la sesión pendiente superó el plazo de aprobación. Se mide desde la última
+// actualización: una sesión que estuvo en cola pasa a pendiente al entregarse.
+func (rss *RemoteSessionService) approvalExpired(session *remotesession.RemoteSession, now time.Time) bool {
+	return now.Sub(session.UpdatedAt()) > rss.approvalTimeout
+}
+
+// ExpirePendingApprovals rechaza las sesiones PENDING_APPROVAL que superaron el plazo de aprobación sin respuesta
+// del cliente y avisa a su administrador. Retorna cuántas caducaron.
+func (rss *RemoteSessionService) ExpirePendingApprovals(ctx context.Context) (int, error) {
+	sessions, err := rss.sessionRepo.FindByStatus(ctx, remotesession.StatusPendingApproval)
+	if err != nil {
+		return 0, fmt.Errorf("error finding pending approval sessions: %w", err)
+	}
+
+	now := time.Now().UTC()
+	expired := 0
+	for _, session := range sessions {
+		if !rss.approvalExpired(session, now) {
+			continue
+		}
+
+		rejected, err := rss.transitionIfUnchanged(ctx, session.SessionID(), remotesession.StatusPendingApproval, func(current *remotesession.RemoteSession) (bool, error) {
+			if !rss.approvalExpired(current, now) {
+				return false, nil
+			}
+			if err := current.Reject(); err != nil {
+				return false, fmt.Errorf("error rejecting session: %w", err)
+			}
+			return true, nil
+		})
+		if err != nil {
+			log.Printf("❌ SESSION APPROVAL: Failed to expire session %s: %v", session.SessionID(), err)
+			continue
+		}
+		if rejected == nil {
+			continue
+		}
+
+		log.Printf("⌛ SESSION APPROVAL: Session %s for PC %s was not answered within %v", rejected.SessionID(), rejected.ClientPCID(), rss.approvalTimeout)
+		rss.sessionEnded(ctx, rejected)
+		expired++
+	}
+	return expired, nil
+}
@@ -0,0 +1,81 @@
+package remotesessionservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+func TestRemoteSessionService_SetApprovalTimeout_IgnoresNonPositive(t *testing.T) {
+	// Arrange
+	service := NewRemoteSessionService(nil, nil, nil, nil, nil)
+
+	// Act
+	service.SetApprovalTimeout(0)
+	service.SetApprovalTimeout(-time.Second)
+
+	// Assert
+	assert.Equal(t, DefaultSessionApprovalTimeout, service.ApprovalTimeout())
+
+	service.SetApprovalTimeout(45 * time.Second)
+	assert.Equal(t, 45*time.Second, service.ApprovalTimeout())
+}
+
+// newExpiredApprovalRepository repositorio con una sesión PENDING_APPROVAL que lleva esperando más que timeout
+func newExpiredApprovalRepository(timeout time.Duration) *statefulSessionRepository {
+	sessionRepo := newStatefulSessionRepository()
+	waitingSince := time.Now().UTC().Add(-timeout - time.Second)
+	s := sessionRepo.session
+	sessionRepo.session = remotesession.NewRemoteSessionFromDB(s.SessionID(), s.AdminUserID(), s.ClientPCID(), nil, nil,
+		remotesession.StatusPendingApproval, nil, waitingSince, waitingSince)
+	return sessionRepo
+}
+
+func TestRemoteSessionService_AcceptSession_AfterApprovalTimeoutFails(t *testing.T) {
+	// Arrange - la limpieza todavía no rechazó la solicitud vencida
+	sessionRepo := newExpiredApprovalRepository(30 * time.Second)
+	service := NewRemoteSessionService(sessionRepo, nil, nil, newSessionStartedActionLog(), nil)
+	service.SetApprovalTimeout(30 * time.Second)
+
+	// Act
+	err := service.AcceptSession(context.Background(), sessionRepo.session.SessionID())
+
+	// Assert
+	assert.ErrorIs(t, err, ErrSessionApprovalExpired)
+	assert.Equal(t, remotesession.StatusPendingApproval, sessionRepo.status())
+	assert.Zero(t, sessionRepo.updates)
+}
+
+func TestRemoteSessionService_ExpirePendingApprovals_RejectsOnlyExpiredSessions(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	expired := remotesession.NewRemoteSessionFromDB("unanswered", testAdminUserID, testClientPCID, nil, nil,
+		remotesession.StatusPendingApproval, nil, now.Add(-time.Minute), now.Add(-time.Minute))
+	inTime := remotesession.NewRemoteSessionFromDB("waiting", testAdminUserID, testClientPCID, nil, nil,
+		remotesession.StatusPendingApproval, nil, now, now)
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusPendingApproval).
+		Return([]*remotesession.RemoteSession{expired, inTime}, nil)
+	sessionRepo.On("FindById", mock.Anything, "unanswered").Return(expired, nil)
+	sessionRepo.On("UpdateStatus", mock.Anything, "unanswered", remotesession.StatusRejected).Return(nil)
+	service := NewRemoteSessionService(sessionRepo, nil, nil, nil, nil)
+	service.SetApprovalTimeout(30 * time.Second)
+	var notified []string
+	service.SetSessionEndedNotifier(func(sessionID, clientPCID, adminUserID string) {
+		notified = append(notified, sessionID)
+	})
+
+	// Act
+	count, err := service.ExpirePendingApprovals(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{"unanswered"}, notified)
+	sessionRepo.AssertExpectations(t)
+	sessionRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, "waiting", mock.Anything)
+}
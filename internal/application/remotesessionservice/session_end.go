@@ -0,0 +1,101 @@
+package remotesessionservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+// sessionEnded efectos comunes a cualquier sesión que acaba de terminar y ya está persistida: cierra la presencia
+// informada por el cliente (con su resumen en el audit log), avisa al administrador y, si terminó en REJECTED o
+// FAILED, al notificador de fines no exitosos
+func (rss *RemoteSessionService) sessionEnded(ctx context.Context, session *remotesession.RemoteSession) {
+	rss.FinishSessionActivity(ctx, session.SessionID())
+
+	if rss.notifySessionEndedCallback != nil {
+		log.Printf("📡 Notifying AdminWeb that session %s ended (%s)", session.SessionID(), session.Status())
+		rss.notifySessionEndedCallback(session.SessionID(), session.ClientPCID(), session.AdminUserID())
+	}
+	rss.notifyIfUnsuccessfulEnd(session)
+}
+
+// endSession termina una sesión ya leída con la secuencia común a los fines de sesión: aplica la transición de
+// dominio, persiste el estado, registra REMOTE_SESSION_ENDED con auditReason, ejecuta sessionEnded y avisa al
+// cliente con control_session_ended. Debe llamarse con el lock de decisión de la sesión tomado.
+func (rss *RemoteSessionService) endSession(ctx context.Context, session *remotesession.RemoteSession, transition func() error, auditReason string) error {
+	if err := rss.persistSessionEnd(ctx, session, transition, auditReason); err != nil {
+		return err
+	}
+
+	rss.sessionEnded(ctx, session)
+	if rss.notifyClientSessionEndedCallback != nil {
+		log.Printf("📱 Notifying client %s that session %s ended (%s)", session.ClientPCID(), session.SessionID(), auditReason)
+		rss.notifyClientSessionEndedCallback(session.SessionID(), session.ClientPCID())
+	}
+	return nil
+}
+
+// persistSessionEnd aplica la transición de fin, guarda el nuevo estado y lo registra en auditoría; los avisos
+// quedan a cargo de quien llama
+func (rss *RemoteSessionService) persistSessionEnd(ctx context.Context, session *remotesession.RemoteSession, transition func() error, auditReason string) error {
+	if err := transition(); err != nil {
+		return fmt.Errorf("error ending session: %w", err)
+	}
+	if err := rss.sessionRepo.UpdateStatus(ctx, session.SessionID(), session.Status()); err != nil {
+		return fmt.Errorf("error updating session status: %w", err)
+	}
+
+	if rss.actionLogService != nil {
+		if err := rss.actionLogService.LogSessionEnded(ctx, session.SessionID(), session.AdminUserID(), auditReason); err != nil {
+			// Log error pero no falle la operación principal
+			log.Printf("⚠️ Warning: Failed to log session ended audit entry: %v", err)
+		}
+	}
+	return nil
+}
+
+// EndOrphanedSession cierra una sesión que la reconciliación encontró huérfana con la misma secuencia que los
+// demás fines de sesión: las activas terminan como FAILED, las pendientes se rechazan y las solicitudes en cola
+// caducan; después se registra en auditoría y se avisa al administrador. expectedStatus es el estado con el que la
+// reconciliación la leyó: si cambió entretanto (p. ej. el administrador la terminó) retorna ErrSessionAlreadyDecided
+// sin tocarla.
+func (rss *RemoteSessionService) EndOrphanedSession(ctx context.Context, sessionID string, expectedStatus remotesession.SessionStatus) (*remotesession.RemoteSession, error) {
+	unlock := rss.decisionLocks.lock(sessionID)
+	defer unlock()
+
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding session: %w", err)
+	}
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.Status() != expectedStatus {
+		return nil, fmt.Errorf("%w: session %s is %s", ErrSessionAlreadyDecided, sessionID, session.Status())
+	}
+
+	transition := session.Reject
+	switch {
+	case session.IsActive():
+		transition = func() error { return session.End(remotesession.StatusFailed) }
+	case session.IsQueued():
+		transition = session.ExpireQueue
+	}
+	if err := rss.persistSessionEnd(ctx, session, transition, "reconciled"); err != nil {
+		return nil, err
+	}
+
+	// Una solicitud en cola nunca llegó a empezar: el administrador recibe el mismo aviso que cuando caduca
+	if expectedStatus == remotesession.StatusQueued {
+		if rss.notifyQueueExpiredCallback != nil {
+			rss.notifyQueueExpiredCallback(sessionID, session.ClientPCID(), session.AdminUserID())
+		}
+	} else {
+		rss.sessionEnded(ctx, session)
+	}
+
+	log.Printf("🔧 Orphaned session %s for PC %s set to %s (was %s)", sessionID, session.ClientPCID(), session.Status(), expectedStatus)
+	return session, nil
+}
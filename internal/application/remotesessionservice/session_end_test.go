@@ -0,0 +1,130 @@
+package remotesessionservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+func TestRemoteSessionService_EndOrphanedSession_RunsNormalEndSequence(t *testing.T) {
+	// Arrange
+	session, err := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+	sessionRepo.On("UpdateStatus", mock.Anything, session.SessionID(), remotesession.StatusFailed).Return(nil).Once()
+	actionLog := new(MockActionLogService)
+	actionLog.On("LogSessionEnded", mock.Anything, session.SessionID(), testAdminUserID, "reconciled").Return(nil).Once()
+	actionLog.On("LogAction", mock.Anything, actionlog.ActionRemoteSessionActivity, mock.Anything, testAdminUserID,
+		mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	service := NewRemoteSessionService(sessionRepo, nil, nil, actionLog, nil)
+	var notified string
+	service.SetSessionEndedNotifier(func(sessionID, clientPCID, adminUserID string) { notified = sessionID })
+
+	ctx := context.Background()
+	_, _, err = service.RecordClientActivity(ctx, session.SessionID(), testClientPCID, ClientActivityIdle, time.Minute)
+	require.NoError(t, err)
+
+	// Act
+	ended, err := service.EndOrphanedSession(ctx, session.SessionID(), remotesession.StatusActive)
+
+	// Assert - estado, auditoría, aviso al administrador y cierre de la actividad del cliente
+	require.NoError(t, err)
+	assert.Equal(t, remotesession.StatusFailed, ended.Status())
+	assert.Equal(t, session.SessionID(), notified)
+	_, reported := service.GetSessionActivity(session.SessionID())
+	assert.False(t, reported)
+	sessionRepo.AssertExpectations(t)
+	actionLog.AssertExpectations(t)
+}
+
+func TestRemoteSessionService_EndOrphanedSession_QueuedSessionNotifiesQueueExpiry(t *testing.T) {
+	// Arrange
+	session, err := remotesession.NewQueuedRemoteSession(testAdminUserID, testClientPCID)
+	require.NoError(t, err)
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+	sessionRepo.On("UpdateStatus", mock.Anything, session.SessionID(), remotesession.StatusFailed).Return(nil).Once()
+	service := NewRemoteSessionService(sessionRepo, nil, nil, nil, nil)
+	var queueExpired, ended bool
+	service.SetQueueExpiredNotifier(func(sessionID, clientPCID, adminUserID string) { queueExpired = true })
+	service.SetSessionEndedNotifier(func(sessionID, clientPCID, adminUserID string) { ended = true })
+
+	// Act
+	_, err = service.EndOrphanedSession(context.Background(), session.SessionID(), remotesession.StatusQueued)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, queueExpired)
+	assert.False(t, ended)
+}
+
+func TestRemoteSessionService_EndOrphanedSession_SkipsSessionThatChangedState(t *testing.T) {
+	// Arrange - el administrador la terminó después de que la reconciliación la leyera como ACTIVE
+	session, err := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+	require.NoError(t, session.End(remotesession.StatusEndedByAdmin))
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+	service := NewRemoteSessionService(sessionRepo, nil, nil, nil, nil)
+
+	// Act
+	_, err = service.EndOrphanedSession(context.Background(), session.SessionID(), remotesession.StatusActive)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrSessionAlreadyDecided)
+	sessionRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRemoteSessionService_AdminAndStreamEndsShareEndSequence(t *testing.T) {
+	tests := []struct {
+		name        string
+		end         func(*RemoteSessionService, string) error
+		endStatus   remotesession.SessionStatus
+		auditReason string
+	}{
+		{name: "ended by admin", end: func(s *RemoteSessionService, id string) error {
+			return s.EndSessionByAdmin(context.Background(), id)
+		}, endStatus: remotesession.StatusEndedByAdmin, auditReason: "ended_by_admin"},
+		{name: "stream not started", end: func(s *RemoteSessionService, id string) error {
+			return s.FailSessionWithoutStream(context.Background(), id)
+		}, endStatus: remotesession.StatusFailed, auditReason: "stream_not_started"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			session, err := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+			require.NoError(t, err)
+			require.NoError(t, session.Accept())
+			sessionRepo := new(MockRemoteSessionRepository)
+			sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+			sessionRepo.On("UpdateStatus", mock.Anything, session.SessionID(), tt.endStatus).Return(nil).Once()
+			actionLog := new(MockActionLogService)
+			actionLog.On("LogSessionEnded", mock.Anything, session.SessionID(), testAdminUserID, tt.auditReason).Return(nil).Once()
+			service := NewRemoteSessionService(sessionRepo, nil, nil, actionLog, nil)
+			var adminNotified, clientNotified string
+			service.SetSessionEndedNotifier(func(sessionID, clientPCID, adminUserID string) { adminNotified = sessionID })
+			service.SetClientSessionEndedNotifier(func(sessionID, clientPCID string) { clientNotified = clientPCID })
+
+			// Act
+			err = tt.end(service, session.SessionID())
+
+			// Assert - estado, auditoría y avisos al administrador y al cliente
+			require.NoError(t, err)
+			assert.Equal(t, tt.endStatus, session.Status())
+			assert.Equal(t, session.SessionID(), adminNotified)
+			assert.Equal(t, testClientPCID, clientNotified)
+			sessionRepo.AssertExpectations(t)
+			actionLog.AssertExpectations(t)
+		})
+	}
+}
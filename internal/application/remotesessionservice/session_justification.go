@@ -0,0 +1,103 @@
+package remotesessionservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+// ErrJustificationRequired la configuración exige un motivo o un ticket para iniciar sesiones
+var ErrJustificationRequired = errors.New("session justification required")
+
+// SetRequireJustification exige que cada solicitud de control indique un motivo o un número de ticket
+func (rss *RemoteSessionService) SetRequireJustification(required bool) {
+	rss.requireJustification = required
+}
+
+// checkJustification valida la justificación de una solicitud según la configuración
+func (rss *RemoteSessionService) checkJustification(justification remotesession.Justification) error {
+	if rss.requireJustification && justification.IsEmpty() {
+		return ErrJustificationRequired
+	}
+	return nil
+}
+
+// logSessionRequested registra en auditoría la solicitud de control con su motivo y su ticket. La sesión aún no ha
+// empezado: REMOTE_SESSION_STARTED se registra cuando el cliente (o su política de auto-aceptación) la acepta.
+func (rss *RemoteSessionService) logSessionRequested(ctx context.Context, session *remotesession.RemoteSession, pcIdentifier string) {
+	if rss.actionLogService == nil {
+		return
+	}
+
+	justification := session.Justification()
+	description := fmt.Sprintf("Sesión de control remoto solicitada sobre el PC %s", pcIdentifier)
+	if justification.Reason != "" {
+		description += fmt.Sprintf(" - Motivo: %s", justification.Reason)
+	}
+
+	details := map[string]interface{}{
+		"session_id":    session.SessionID(),
+		"client_pc_id":  session.ClientPCID(),
+		"pc_identifier": pcIdentifier,
+		"status":        string(session.Status()),
+		"reason":        justification.Reason,
+		"ticket_id":     justification.TicketID,
+	}
+
+	subjectEntityID := session.SessionID()
+	subjectEntityType := "REMOTE_SESSION"
+
+	err := rss.actionLogService.LogAction(
+		ctx,
+		actionlog.ActionRemoteSessionRequested,
+		description,
+		session.AdminUserID(),
+		&subjectEntityID,
+		&subjectEntityType,
+		details,
+	)
+	if err != nil {
+		// Log error pero no falle la operación principal
+		log.Printf("⚠️ Warning: Failed to log session requested audit entry: %v", err)
+	}
+}
+
+// logSessionStarted registra en auditoría el inicio de una sesión aceptada, con la justificación de su solicitud.
+// acceptedBy indica quién la aceptó: "client" o "auto_accept_policy".
+func (rss *RemoteSessionService) logSessionStarted(ctx context.Context, session *remotesession.RemoteSession, acceptedBy string) {
+	if rss.actionLogService == nil {
+		return
+	}
+
+	justification := session.Justification()
+	description := fmt.Sprintf("Sesión de control remoto iniciada sobre el PC %s", session.ClientPCID())
+
+	details := map[string]interface{}{
+		"session_id":   session.SessionID(),
+		"client_pc_id": session.ClientPCID(),
+		"accepted_by":  acceptedBy,
+		"reason":       justification.Reason,
+		"ticket_id":    justification.TicketID,
+	}
+
+	subjectEntityID := session.SessionID()
+	subjectEntityType := "REMOTE_SESSION"
+
+	err := rss.actionLogService.LogAction(
+		ctx,
+		actionlog.ActionRemoteSessionStarted,
+		description,
+		session.AdminUserID(),
+		&subjectEntityID,
+		&subjectEntityType,
+		details,
+	)
+	if err != nil {
+		// Log error pero no falle la operación principal
+		log.Printf("⚠️ Warning: Failed to log session started audit entry: %v", err)
+	}
+}
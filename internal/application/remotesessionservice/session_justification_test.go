@@ -0,0 +1,71 @@
+package remotesessionservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+func TestRemoteSessionService_InitiateSession_PersistsAndAuditsJustification(t *testing.T) {
+	// Arrange
+	service, sessionRepo, actionLogService, _ := newInitiateSessionFixture(false)
+	justification := remotesession.Justification{Reason: "Printer driver crash", TicketID: "HD-4821"}
+
+	// Act
+	session, err := service.InitiateSession(context.Background(), testAdminUserID, testClientPCID, justification)
+
+	// Assert - la sesión guardada lleva la justificación
+	require.NoError(t, err)
+	assert.Equal(t, justification, session.Justification())
+	saved := sessionRepo.Calls[len(sessionRepo.Calls)-1].Arguments.Get(1).(*remotesession.RemoteSession)
+	assert.Equal(t, justification, saved.Justification())
+
+	// y la entrada de auditoría de la solicitud también
+	actionLogService.AssertNumberOfCalls(t, "LogAction", 1)
+	call := actionLogService.Calls[0]
+	assert.Equal(t, actionlog.ActionRemoteSessionRequested, call.Arguments.Get(1))
+	assert.Contains(t, call.Arguments.String(2), "Printer driver crash")
+	details := call.Arguments.Get(6).(map[string]interface{})
+	assert.Equal(t, "Printer driver crash", details["reason"])
+	assert.Equal(t, "HD-4821", details["ticket_id"])
+	assert.Equal(t, session.SessionID(), details["session_id"])
+}
+
+func TestRemoteSessionService_RequireJustification_RejectsMissingReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		initiate func(*RemoteSessionService, remotesession.Justification) (*remotesession.RemoteSession, error)
+	}{
+		{name: "initiate", initiate: func(s *RemoteSessionService, j remotesession.Justification) (*remotesession.RemoteSession, error) {
+			return s.InitiateSession(context.Background(), testAdminUserID, testClientPCID, j)
+		}},
+		{name: "queue", initiate: func(s *RemoteSessionService, j remotesession.Justification) (*remotesession.RemoteSession, error) {
+			return s.QueueSession(context.Background(), testAdminUserID, testClientPCID, j)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service, sessionRepo, actionLogService, _ := newInitiateSessionFixture(false)
+			service.SetRequireJustification(true)
+
+			// Act
+			session, err := tt.initiate(service, remotesession.Justification{})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrJustificationRequired)
+			assert.Nil(t, session)
+			sessionRepo.AssertNotCalled(t, "Save")
+			actionLogService.AssertNotCalled(t, "LogAction")
+
+			// Un ticket sin motivo basta
+			_, err = tt.initiate(service, remotesession.Justification{TicketID: "HD-4821"})
+			require.NoError(t, err)
+		})
+	}
+}
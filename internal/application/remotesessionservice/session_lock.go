@@ -0,0 +1,79 @@
+package remotesessionservice
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+// sessionLock mutex de una sesión con el número de goroutines que lo usan o esperan
+type sessionLock struct {
+	mu      sync.Mutex
+	holders int
+}
+
+// sessionLocks serializa las transiciones de estado por sesión sin bloquear sesiones distintas
+type sessionLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sessionLock
+}
+
+func newSessionLocks() *sessionLocks {
+	return &sessionLocks{locks: make(map[string]*sessionLock)}
+}
+
+// lock bloquea la sesión y retorna la función que la libera.
+// El mutex se elimina del mapa cuando ya nadie lo usa para no acumular sesiones terminadas.
+func (sl *sessionLocks) lock(sessionID string) func() {
+	sl.mu.Lock()
+	lock, exists := sl.locks[sessionID]
+	if !exists {
+		lock = &sessionLock{}
+		sl.locks[sessionID] = lock
+	}
+	lock.holders++
+	sl.mu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		sl.mu.Lock()
+		lock.holders--
+		if lock.holders == 0 {
+			delete(sl.locks, sessionID)
+		}
+		sl.mu.Unlock()
+	}
+}
+
+// transitionIfUnchanged vuelve a leer la sesión con su lock de decisión tomado y, si sigue en expectedStatus,
+// aplica transition y persiste el nuevo estado. Los barridos (sesiones atascadas, desconexión del PC) trabajan con
+// listas que pueden estar desactualizadas: así no sobrescriben una aceptación o un rechazo del cliente.
+// transition retorna false si con el estado vigente ya no corresponde tocarla. Retorna la sesión persistida, o
+// nil si no se modificó.
+func (rss *RemoteSessionService) transitionIfUnchanged(ctx context.Context, sessionID string, expectedStatus remotesession.SessionStatus,
+	transition func(session *remotesession.RemoteSession) (bool, error)) (*remotesession.RemoteSession, error) {
+	unlock := rss.decisionLocks.lock(sessionID)
+	defer unlock()
+
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding session: %w", err)
+	}
+	if session == nil || session.Status() != expectedStatus {
+		return nil, nil
+	}
+
+	applied, err := transition(session)
+	if err != nil || !applied {
+		return nil, err
+	}
+	if err := rss.sessionRepo.UpdateStatus(ctx, sessionID, session.Status()); err != nil {
+		return nil, fmt.Errorf("error updating session status: %w", err)
+	}
+	return session, nil
+}
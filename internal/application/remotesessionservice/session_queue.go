@@ -0,0 +1,217 @@
+package remotesessionservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+// DefaultSessionQueueTimeout tiempo por defecto que una solicitud espera en cola a que el PC se conecte
+const DefaultSessionQueueTimeout = 10 * time.Minute
+
+// ErrSessionAlreadyQueued indica que el PC ya tiene una solicitud de control en cola
+var ErrSessionAlreadyQueued = errors.New("session already queued")
+
+// SetQueueTimeout configura cuánto espera en cola una solicitud antes de caducar (<= 0 mantiene el valor por defecto)
+func (rss *RemoteSessionService) SetQueueTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		rss.queueTimeout = timeout
+	}
+}
+
+// SetQueueExpiredNotifier establece el callback para avisar al administrador de que su solicitud en cola caducó
+func (rss *RemoteSessionService) SetQueueExpiredNotifier(callback func(sessionID, clientPCID, adminUserID string)) {
+	rss.notifyQueueExpiredCallback = callback
+}
+
+// QueueSession crea una sesión QUEUED para un PC desconectado; la solicitud se entrega
+// cuando el PC vuelve a registrarse (DeliverQueuedSession) o caduca tras el timeout de cola.
+func (rss *RemoteSessionService) QueueSession(ctx context.Context, adminUserID, clientPCID string, justification remotesession.Justification) (*remotesession.RemoteSession, error) {
+	if err := rss.checkJustification(justification); err != nil {
+		return nil, err
+	}
+
+	sessions, err := rss.sessionRepo.FindByClientPCID(ctx, clientPCID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking sessions for PC: %w", err)
+	}
+	for _, session := range sessions {
+		switch session.Status() {
+		case remotesession.StatusActive:
+			return nil, fmt.Errorf("%w: %s", ErrSessionAlreadyActive, session.SessionID())
+		case remotesession.StatusQueued:
+			if !rss.queueExpired(session, time.Now().UTC()) {
+				return nil, fmt.Errorf("%w: %s", ErrSessionAlreadyQueued, session.SessionID())
+			}
+		}
+	}
+
+	user, err := rss.userRepo.FindByID(adminUserID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding admin user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrAdminUserNotFound
+	}
+
+	pc, err := rss.pcRepo.FindByID(ctx, clientPCID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding client PC: %w", err)
+	}
+	if pc == nil {
+		return nil, ErrClientPCNotFound
+	}
+
+	session, err := remotesession.NewQueuedRemoteSession(adminUserID, clientPCID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating session: %w", err)
+	}
+	session.SetJustification(justification)
+
+	if err := rss.sessionRepo.Save(ctx, session); err != nil {
+		return nil, fmt.Errorf("error saving session: %w", err)
+	}
+	rss.logSessionRequested(ctx, session, pc.Identifier)
+
+	log.Printf("⏳ SESSION QUEUE: Session %s queued for offline PC %s by admin %s (expires in %v)",
+		session.SessionID(), clientPCID, adminUserID, rss.queueTimeout)
+	return session, nil
+}
+
+// DeliverQueuedSession pasa a PENDING_APPROVAL (o ACTIVE si el PC auto-acepta) la solicitud en cola
+// del PC que se acaba de registrar. Retorna nil si no había ninguna vigente; las caducadas se expiran.
+func (rss *RemoteSessionService) DeliverQueuedSession(ctx context.Context, clientPCID string) (*remotesession.RemoteSession, error) {
+	sessions, err := rss.sessionRepo.FindByClientPCID(ctx, clientPCID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding queued sessions for PC: %w", err)
+	}
+
+	now := time.Now().UTC()
+	var queued *remotesession.RemoteSession
+	for _, session := range sessions {
+		if !session.IsQueued() {
+			continue
+		}
+		if rss.queueExpired(session, now) {
+			rss.expireQueuedSession(ctx, session.SessionID())
+			continue
+		}
+		if queued == nil || session.CreatedAt().Before(queued.CreatedAt()) {
+			queued = session
+		}
+	}
+	if queued == nil {
+		return nil, nil
+	}
+
+	unlock := rss.decisionLocks.lock(queued.SessionID())
+	defer unlock()
+
+	// Releer bajo el lock: la expiración periódica puede haberla resuelto entre tanto
+	session, err := rss.sessionRepo.FindById(ctx, queued.SessionID())
+	if err != nil {
+		return nil, fmt.Errorf("error finding session: %w", err)
+	}
+	if session == nil || !session.IsQueued() {
+		return nil, nil
+	}
+
+	pc, err := rss.pcRepo.FindByID(ctx, clientPCID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding client PC: %w", err)
+	}
+	if pc == nil {
+		return nil, ErrClientPCNotFound
+	}
+
+	if err := session.Dequeue(); err != nil {
+		return nil, fmt.Errorf("error dequeuing session: %w", err)
+	}
+	if pc.AutoAcceptControl {
+		if err := session.Accept(); err != nil {
+			return nil, fmt.Errorf("error auto-accepting session: %w", err)
+		}
+	}
+
+	if err := rss.sessionRepo.Update(ctx, session); err != nil {
+		return nil, fmt.Errorf("error updating session: %w", err)
+	}
+
+	adminUsername := ""
+	if user, err := rss.userRepo.FindByID(session.AdminUserID()); err == nil && user != nil {
+		adminUsername = user.Username()
+	}
+	rss.publishSessionInitiated(session, adminUsername, pc.Identifier)
+
+	log.Printf("📬 SESSION QUEUE: Delivered queued session %s to PC %s (status %s)", session.SessionID(), clientPCID, session.Status())
+	return session, nil
+}
+
+// ExpireQueuedSessions marca como FAILED las solicitudes en cola que superaron el timeout
+// y avisa a su administrador. Retorna cuántas caducaron.
+func (rss *RemoteSessionService) ExpireQueuedSessions(ctx context.Context) (int, error) {
+	sessions, err := rss.sessionRepo.FindByStatus(ctx, remotesession.StatusQueued)
+	if err != nil {
+		return 0, fmt.Errorf("error finding queued sessions: %w", err)
+	}
+
+	now := time.Now().UTC()
+	expired := 0
+	for _, session := range sessions {
+		if rss.queueExpired(session, now) && rss.expireQueuedSession(ctx, session.SessionID()) {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// queuedSessionForPC solicitud en cola vigente del PC, o nil si no tiene ninguna
+func (rss *RemoteSessionService) queuedSessionForPC(ctx context.Context, clientPCID string) (*remotesession.RemoteSession, error) {
+	sessions, err := rss.sessionRepo.FindByClientPCID(ctx, clientPCID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking queued sessions for PC: %w", err)
+	}
+
+	now := time.Now().UTC()
+	for _, session := range sessions {
+		if session.IsQueued() && !rss.queueExpired(session, now) {
+			return session, nil
+		}
+	}
+	return nil, nil
+}
+
+// queueExpired indica si la solicitud lleva en cola más que el timeout configurado
+func (rss *RemoteSessionService) queueExpired(session *remotesession.RemoteSession, now time.Time) bool {
+	return now.Sub(session.CreatedAt()) > rss.queueTimeout
+}
+
+// expireQueuedSession marca la sesión como FAILED si sigue en cola y notifica al administrador
+func (rss *RemoteSessionService) expireQueuedSession(ctx context.Context, sessionID string) bool {
+	unlock := rss.decisionLocks.lock(sessionID)
+	defer unlock()
+
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
+	if err != nil || session == nil || !session.IsQueued() {
+		return false
+	}
+
+	if err := session.ExpireQueue(); err != nil {
+		log.Printf("⚠️ SESSION QUEUE: Cannot expire session %s: %v", sessionID, err)
+		return false
+	}
+	if err := rss.sessionRepo.Update(ctx, session); err != nil {
+		log.Printf("❌ SESSION QUEUE: Failed to persist expiration of session %s: %v", sessionID, err)
+		return false
+	}
+
+	log.Printf("⌛ SESSION QUEUE: Queued session %s for PC %s expired after %v", sessionID, session.ClientPCID(), rss.queueTimeout)
+	if rss.notifyQueueExpiredCallback != nil {
+		rss.notifyQueueExpiredCallback(session.SessionID(), session.ClientPCID(), session.AdminUserID())
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+package remotesessionservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+)
+
+// newQueueFixture prepara el servicio con un PC offline del administrador de prueba
+func newQueueFixture() (*RemoteSessionService, *MockRemoteSessionRepository, *clientpc.ClientPC) {
+	sessionRepo := new(MockRemoteSessionRepository)
+	userRepo := new(MockUserRepository)
+	pcRepo := new(MockClientPCRepository)
+	eventBus := new(MockEventBus)
+
+	admin := user.NewUser(testAdminUserID, "admin", "", "hashed", user.RoleAdministrator)
+	pc, _ := clientpc.NewClientPC(testClientPCID, "lab-pc-01", "192.168.1.50", testAdminUserID)
+
+	userRepo.On("FindByID", testAdminUserID).Return(admin, nil)
+	pcRepo.On("FindByID", mock.Anything, testClientPCID).Return(pc, nil)
+	eventBus.On("Publish", mock.Anything).Return()
+	actionLogService := new(MockActionLogService)
+	actionLogService.On("LogAction", mock.Anything, actionlog.ActionRemoteSessionRequested, mock.Anything,
+		testAdminUserID, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	actionLogService.On("LogAction", mock.Anything, actionlog.ActionRemoteSessionStarted, mock.Anything,
+		testAdminUserID, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	service := NewRemoteSessionService(sessionRepo, userRepo, pcRepo, actionLogService, eventBus)
+	return service, sessionRepo, pc
+}
+
+func TestRemoteSessionService_QueueSession_DeliveredWhenPCRegisters(t *testing.T) {
+	// Arrange
+	service, sessionRepo, pc := newQueueFixture()
+	sessionRepo.On("FindByClientPCID", mock.Anything, testClientPCID).Return([]*remotesession.RemoteSession{}, nil).Once()
+	sessionRepo.On("Save", mock.Anything, mock.AnythingOfType("*remotesession.RemoteSession")).Return(nil)
+
+	queued, err := service.QueueSession(context.Background(), testAdminUserID, testClientPCID, remotesession.Justification{})
+	require.NoError(t, err)
+	assert.Equal(t, remotesession.StatusQueued, queued.Status())
+
+	pc.SetOnline()
+	sessionRepo.On("FindByClientPCID", mock.Anything, testClientPCID).Return([]*remotesession.RemoteSession{queued}, nil)
+	sessionRepo.On("FindById", mock.Anything, queued.SessionID()).Return(queued, nil)
+	sessionRepo.On("Update", mock.Anything, queued).Return(nil)
+
+	// Act
+	delivered, err := service.DeliverQueuedSession(context.Background(), testClientPCID)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, delivered)
+	assert.Equal(t, queued.SessionID(), delivered.SessionID())
+	assert.Equal(t, remotesession.StatusPendingApproval, delivered.Status())
+	sessionRepo.AssertCalled(t, "Update", mock.Anything, queued)
+
+	// Una segunda entrega no reenvía la solicitud
+	again, err := service.DeliverQueuedSession(context.Background(), testClientPCID)
+	assert.NoError(t, err)
+	assert.Nil(t, again)
+}
+
+func TestRemoteSessionService_QueueSession_RejectsSecondQueuedRequest(t *testing.T) {
+	// Arrange
+	service, sessionRepo, _ := newQueueFixture()
+	existing, _ := remotesession.NewQueuedRemoteSession(testAdminUserID, testClientPCID)
+	sessionRepo.On("FindByClientPCID", mock.Anything, testClientPCID).Return([]*remotesession.RemoteSession{existing}, nil)
+
+	// Act
+	session, err := service.QueueSession(context.Background(), testAdminUserID, testClientPCID, remotesession.Justification{})
+
+	// Assert
+	assert.Nil(t, session)
+	assert.True(t, errors.Is(err, ErrSessionAlreadyQueued))
+	sessionRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestRemoteSessionService_InitiateSession_RejectsWhileQueuedRequestIsPending(t *testing.T) {
+	// Arrange - el PC acaba de reconectarse y la solicitud en cola aún no se ha entregado
+	service, sessionRepo, pc := newQueueFixture()
+	pc.ConnectionStatus = clientpc.PCConnectionStatusOnline
+	existing, _ := remotesession.NewQueuedRemoteSession(testAdminUserID, testClientPCID)
+	sessionRepo.On("FindByClientPCID", mock.Anything, testClientPCID).Return([]*remotesession.RemoteSession{existing}, nil)
+
+	// Act
+	session, err := service.InitiateSession(context.Background(), testAdminUserID, testClientPCID, remotesession.Justification{})
+
+	// Assert
+	assert.Nil(t, session)
+	assert.True(t, errors.Is(err, ErrSessionAlreadyQueued))
+	sessionRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestRemoteSessionService_ExpireQueuedSessions_FailsAndNotifiesAdmin(t *testing.T) {
+	// Arrange
+	service, sessionRepo, _ := newQueueFixture()
+	service.SetQueueTimeout(5 * time.Minute)
+
+	queuedAt := time.Now().UTC().Add(-6 * time.Minute)
+	stale := remotesession.NewRemoteSessionFromDB("stale-session", testAdminUserID, testClientPCID,
+		nil, nil, remotesession.StatusQueued, nil, queuedAt, queuedAt)
+	fresh, _ := remotesession.NewQueuedRemoteSession(testAdminUserID, "other-pc")
+
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusQueued).Return([]*remotesession.RemoteSession{stale, fresh}, nil)
+	sessionRepo.On("FindById", mock.Anything, "stale-session").Return(stale, nil)
+	sessionRepo.On("Update", mock.Anything, stale).Return(nil)
+
+	var notifiedSessionID, notifiedPCID, notifiedAdminID string
+	service.SetQueueExpiredNotifier(func(sessionID, clientPCID, adminUserID string) {
+		notifiedSessionID, notifiedPCID, notifiedAdminID = sessionID, clientPCID, adminUserID
+	})
+
+	// Act
+	expired, err := service.ExpireQueuedSessions(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	assert.Equal(t, remotesession.StatusFailed, stale.Status())
+	assert.NotNil(t, stale.EndTime())
+	assert.Equal(t, remotesession.StatusQueued, fresh.Status())
+	assert.Equal(t, "stale-session", notifiedSessionID)
+	assert.Equal(t, testClientPCID, notifiedPCID)
+	assert.Equal(t, testAdminUserID, notifiedAdminID)
+}
+
+func TestRemoteSessionService_DeliverQueuedSession_ExpiredRequestIsNotDelivered(t *testing.T) {
+	// Arrange
+	service, sessionRepo, _ := newQueueFixture()
+	queuedAt := time.Now().UTC().Add(-DefaultSessionQueueTimeout - time.Minute)
+	stale := remotesession.NewRemoteSessionFromDB("stale-session", testAdminUserID, testClientPCID,
+		nil, nil, remotesession.StatusQueued, nil, queuedAt, queuedAt)
+
+	sessionRepo.On("FindByClientPCID", mock.Anything, testClientPCID).Return([]*remotesession.RemoteSession{stale}, nil)
+	sessionRepo.On("FindById", mock.Anything, "stale-session").Return(stale, nil)
+	sessionRepo.On("Update", mock.Anything, stale).Return(nil)
+
+	notified := false
+	service.SetQueueExpiredNotifier(func(sessionID, clientPCID, adminUserID string) { notified = true })
+
+	// Act
+	delivered, err := service.DeliverQueuedSession(context.Background(), testClientPCID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, delivered)
+	assert.Equal(t, remotesession.StatusFailed, stale.Status())
+	assert.True(t, notified)
+}
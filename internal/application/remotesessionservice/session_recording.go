@@ -0,0 +1,59 @@
+package remotesessionservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+// DefaultRecordingGracePeriod tiempo tras el fin de una sesión durante el que su PC aún puede enviar los
+// últimos frames y la finalización de la grabación
+const DefaultRecordingGracePeriod = 2 * time.Minute
+
+// ErrRecordingNotPermitted el PC no puede escribir en la grabación de esa sesión
+var ErrRecordingNotPermitted = errors.New("recording not permitted for this session")
+
+// SetRecordingGracePeriod configura cuánto tiempo tras terminar la sesión se aceptan frames de su grabación
+// (< 0 mantiene el valor por defecto, 0 solo admite sesiones activas)
+func (rss *RemoteSessionService) SetRecordingGracePeriod(grace time.Duration) {
+	if grace >= 0 {
+		rss.recordingGracePeriod = grace
+	}
+}
+
+// RecordingGracePeriod tiempo tras el fin de una sesión durante el que ValidateRecordingPermission aún la admite
+func (rss *RemoteSessionService) RecordingGracePeriod() time.Duration {
+	return rss.recordingGracePeriod
+}
+
+// ValidateRecordingPermission valida que el PC puede subir frames o finalizar la grabación de una sesión:
+// la sesión debe ser suya y estar activa o haber terminado (sin ser rechazada) dentro del periodo de gracia
+func (rss *RemoteSessionService) ValidateRecordingPermission(ctx context.Context, sessionID, clientPCID string) error {
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("error finding session: %w", err)
+	}
+	if session == nil {
+		return fmt.Errorf("%w: session not found", ErrRecordingNotPermitted)
+	}
+
+	// Verificar que el PC cliente coincide
+	if clientPCID == "" || session.ClientPCID() != clientPCID {
+		return fmt.Errorf("%w: client PC ID mismatch", ErrRecordingNotPermitted)
+	}
+
+	if session.IsActive() {
+		return nil
+	}
+
+	recentlyEnded := session.IsCompleted() && session.Status() != remotesession.StatusRejected &&
+		session.EndTime() != nil && time.Since(*session.EndTime()) <= rss.recordingGracePeriod
+	if !recentlyEnded {
+		return fmt.Errorf("%w: session is %s", ErrRecordingNotPermitted, session.Status())
+	}
+
+	return nil
+}
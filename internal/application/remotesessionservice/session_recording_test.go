@@ -0,0 +1,62 @@
+package remotesessionservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+// newRecordingPermissionFixture prepara el servicio con una sesión activa del PC de prueba
+func newRecordingPermissionFixture(t *testing.T) (*RemoteSessionService, *remotesession.RemoteSession) {
+	t.Helper()
+
+	session, err := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+
+	return NewRemoteSessionService(sessionRepo, nil, nil, nil, nil), session
+}
+
+func TestRemoteSessionService_ValidateRecordingPermission_AllowsOwnActiveSession(t *testing.T) {
+	// Arrange
+	service, session := newRecordingPermissionFixture(t)
+
+	// Act
+	err := service.ValidateRecordingPermission(context.Background(), session.SessionID(), testClientPCID)
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestRemoteSessionService_ValidateRecordingPermission_RejectsAnotherPC(t *testing.T) {
+	// Arrange
+	service, session := newRecordingPermissionFixture(t)
+
+	// Act
+	err := service.ValidateRecordingPermission(context.Background(), session.SessionID(), "550e8400-e29b-41d4-a716-446655440099")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrRecordingNotPermitted)
+}
+
+func TestRemoteSessionService_ValidateRecordingPermission_RecentlyEndedSessionWithinGracePeriod(t *testing.T) {
+	// Arrange
+	service, session := newRecordingPermissionFixture(t)
+	require.NoError(t, session.End(remotesession.StatusEndedByAdmin))
+
+	// Act
+	withinGrace := service.ValidateRecordingPermission(context.Background(), session.SessionID(), testClientPCID)
+	service.SetRecordingGracePeriod(0)
+	afterGrace := service.ValidateRecordingPermission(context.Background(), session.SessionID(), testClientPCID)
+
+	// Assert
+	assert.NoError(t, withinGrace)
+	assert.ErrorIs(t, afterGrace, ErrRecordingNotPermitted)
+}
@@ -0,0 +1,35 @@
+package remotesessionservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+// FailSessionWithoutStream finaliza como FAILED una sesión ACTIVE cuyo cliente nunca empezó a enviar frames,
+// y avisa al administrador y al cliente igual que cualquier otro fin de sesión
+func (rss *RemoteSessionService) FailSessionWithoutStream(ctx context.Context, sessionID string) error {
+	unlock := rss.decisionLocks.lock(sessionID)
+	defer unlock()
+
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("error finding session: %w", err)
+	}
+	if session == nil {
+		return ErrSessionNotFound
+	}
+	if session.Status() != remotesession.StatusActive {
+		return ErrSessionNotActive
+	}
+
+	endFailed := func() error { return session.End(remotesession.StatusFailed) }
+	if err := rss.endSession(ctx, session, endFailed, "stream_not_started"); err != nil {
+		return err
+	}
+
+	log.Printf("🚫 Session %s failed: client %s never started streaming", sessionID, session.ClientPCID())
+	return nil
+}
@@ -0,0 +1,132 @@
+package remotesessionservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+var (
+	// ErrSessionNotFound la sesión no existe
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrSessionNotActive la sesión no está activa
+	ErrSessionNotActive = errors.New("session is not active")
+	// ErrNotSessionOwner el administrador que solicita la operación no controla la sesión
+	ErrNotSessionOwner = errors.New("admin does not own the session")
+	// ErrInvalidTransferTarget el destino del traspaso no es otro administrador válido
+	ErrInvalidTransferTarget = errors.New("invalid transfer target")
+	// ErrTargetAdminNotConnected el administrador destino no tiene el panel conectado
+	ErrTargetAdminNotConnected = errors.New("target admin is not connected")
+)
+
+// SetAdminConnectedChecker establece cómo se comprueba si un administrador está conectado al panel
+func (rss *RemoteSessionService) SetAdminConnectedChecker(checker func(adminUserID string) bool) {
+	rss.adminConnectedChecker = checker
+}
+
+// SetSessionTransferredNotifier establece el callback para avisar a ambos administradores del traspaso
+func (rss *RemoteSessionService) SetSessionTransferredNotifier(callback func(sessionID, clientPCID, fromAdminID, toAdminID string)) {
+	rss.notifySessionTransferredCallback = callback
+}
+
+// SetClientSessionTransferredNotifier establece el callback para avisar al cliente de su nuevo administrador
+func (rss *RemoteSessionService) SetClientSessionTransferredNotifier(callback func(sessionID, clientPCID, toAdminID string)) {
+	rss.notifyClientSessionTransferredCallback = callback
+}
+
+// TransferSessionOwnership entrega una sesión activa de fromAdminID a toAdminID sin cortarla.
+// El nuevo propietario debe ser otro administrador conectado; los frames pasan a reenviarse a él
+// porque el reenvío resuelve el administrador de la sesión en cada frame.
+func (rss *RemoteSessionService) TransferSessionOwnership(ctx context.Context, sessionID, fromAdminID, toAdminID string) (*remotesession.RemoteSession, error) {
+	unlock := rss.decisionLocks.lock(sessionID)
+	defer unlock()
+
+	session, err := rss.sessionRepo.FindById(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding session: %w", err)
+	}
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.Status() != remotesession.StatusActive {
+		return nil, ErrSessionNotActive
+	}
+	if session.AdminUserID() != fromAdminID {
+		return nil, ErrNotSessionOwner
+	}
+	if toAdminID == "" || toAdminID == fromAdminID {
+		return nil, ErrInvalidTransferTarget
+	}
+
+	target, err := rss.userRepo.FindByID(toAdminID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding target admin: %w", err)
+	}
+	if target == nil {
+		return nil, ErrAdminUserNotFound
+	}
+	if !target.Role().CanControlSessions() {
+		return nil, fmt.Errorf("%w: user %s cannot control sessions", ErrInvalidTransferTarget, toAdminID)
+	}
+	if rss.adminConnectedChecker == nil || !rss.adminConnectedChecker(toAdminID) {
+		return nil, ErrTargetAdminNotConnected
+	}
+
+	if err := session.TransferOwnership(toAdminID); err != nil {
+		return nil, fmt.Errorf("error transferring session: %w", err)
+	}
+	if err := rss.sessionRepo.Update(ctx, session); err != nil {
+		return nil, fmt.Errorf("error updating session: %w", err)
+	}
+
+	rss.logSessionTransferred(ctx, session, fromAdminID, target.Username())
+
+	if rss.notifySessionTransferredCallback != nil {
+		rss.notifySessionTransferredCallback(sessionID, session.ClientPCID(), fromAdminID, toAdminID)
+	}
+	if rss.notifyClientSessionTransferredCallback != nil {
+		rss.notifyClientSessionTransferredCallback(sessionID, session.ClientPCID(), toAdminID)
+	}
+
+	log.Printf("🔀 SESSION TRANSFER: Session %s handed off from admin %s to admin %s", sessionID, fromAdminID, toAdminID)
+	return session, nil
+}
+
+// logSessionTransferred registra el traspaso en la auditoría
+func (rss *RemoteSessionService) logSessionTransferred(ctx context.Context, session *remotesession.RemoteSession, fromAdminID, toAdminUsername string) {
+	if rss.actionLogService == nil {
+		return
+	}
+
+	description := fmt.Sprintf("Remote session transferred to admin %s", toAdminUsername)
+	details := map[string]interface{}{
+		"session_id":    session.SessionID(),
+		"client_pc_id":  session.ClientPCID(),
+		"from_admin_id": fromAdminID,
+		"to_admin_id":   session.AdminUserID(),
+		"to_admin_name": toAdminUsername,
+	}
+
+	subjectEntityID := session.SessionID()
+	subjectEntityType := "REMOTE_SESSION"
+
+	err := rss.actionLogService.LogAction(
+		ctx,
+		actionlog.ActionRemoteSessionTransferred,
+		description,
+		fromAdminID,
+		&subjectEntityID,
+		&subjectEntityType,
+		details,
+	)
+	if err != nil {
+		// Log error pero no falle la operación principal
+		log.Printf("⚠️ Warning: Failed to log session transfer audit entry: %v", err)
+	} else {
+		log.Printf("📝 Audit log registered: Session %s transferred to admin %s", session.SessionID(), session.AdminUserID())
+	}
+}
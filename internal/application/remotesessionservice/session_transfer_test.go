@@ -0,0 +1,89 @@
+package remotesessionservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+)
+
+const testTargetAdminUserID = "550e8400-e29b-41d4-a716-446655440002"
+
+// newTransferFixture prepara el servicio con una sesión activa del administrador de prueba
+func newTransferFixture(t *testing.T) (*RemoteSessionService, *MockRemoteSessionRepository, *MockActionLogService, *remotesession.RemoteSession) {
+	t.Helper()
+
+	sessionRepo := new(MockRemoteSessionRepository)
+	userRepo := new(MockUserRepository)
+	actionLogService := new(MockActionLogService)
+
+	session, err := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+
+	target := user.NewUser(testTargetAdminUserID, "second-admin", "", "hashed", user.RoleAdministrator)
+	userRepo.On("FindByID", testTargetAdminUserID).Return(target, nil)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+
+	service := NewRemoteSessionService(sessionRepo, userRepo, new(MockClientPCRepository), actionLogService, new(MockEventBus))
+	return service, sessionRepo, actionLogService, session
+}
+
+func TestRemoteSessionService_TransferSessionOwnership_HandsOffToConnectedAdmin(t *testing.T) {
+	// Arrange
+	service, sessionRepo, actionLogService, session := newTransferFixture(t)
+	service.SetAdminConnectedChecker(func(adminUserID string) bool { return adminUserID == testTargetAdminUserID })
+	sessionRepo.On("Update", mock.Anything, session).Return(nil)
+	actionLogService.On("LogAction", mock.Anything, actionlog.ActionRemoteSessionTransferred, mock.Anything, testAdminUserID,
+		mock.Anything, mock.Anything, mock.MatchedBy(func(details map[string]interface{}) bool {
+			return details["from_admin_id"] == testAdminUserID && details["to_admin_id"] == testTargetAdminUserID
+		})).Return(nil)
+
+	var adminNotified, clientNotified bool
+	service.SetSessionTransferredNotifier(func(sessionID, clientPCID, fromAdminID, toAdminID string) {
+		adminNotified = sessionID == session.SessionID() && fromAdminID == testAdminUserID && toAdminID == testTargetAdminUserID
+	})
+	service.SetClientSessionTransferredNotifier(func(sessionID, clientPCID, toAdminID string) {
+		clientNotified = clientPCID == testClientPCID && toAdminID == testTargetAdminUserID
+	})
+
+	// Act
+	transferred, err := service.TransferSessionOwnership(context.Background(), session.SessionID(), testAdminUserID, testTargetAdminUserID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, testTargetAdminUserID, transferred.AdminUserID())
+	assert.Equal(t, remotesession.StatusActive, transferred.Status())
+	sessionRepo.AssertCalled(t, "Update", mock.Anything, session)
+	actionLogService.AssertExpectations(t)
+	assert.True(t, adminNotified)
+	assert.True(t, clientNotified)
+
+	// El administrador anterior ya no puede volver a traspasarla
+	_, err = service.TransferSessionOwnership(context.Background(), session.SessionID(), testAdminUserID, testTargetAdminUserID)
+	assert.ErrorIs(t, err, ErrNotSessionOwner)
+}
+
+func TestRemoteSessionService_TransferSessionOwnership_TargetAdminNotConnected(t *testing.T) {
+	// Arrange
+	service, sessionRepo, actionLogService, session := newTransferFixture(t)
+	service.SetAdminConnectedChecker(func(adminUserID string) bool { return false })
+
+	notified := false
+	service.SetSessionTransferredNotifier(func(sessionID, clientPCID, fromAdminID, toAdminID string) { notified = true })
+
+	// Act
+	_, err := service.TransferSessionOwnership(context.Background(), session.SessionID(), testAdminUserID, testTargetAdminUserID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTargetAdminNotConnected)
+	assert.Equal(t, testAdminUserID, session.AdminUserID())
+	sessionRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	actionLogService.AssertNotCalled(t, "LogAction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.False(t, notified)
+}
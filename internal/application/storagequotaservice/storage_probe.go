@@ -0,0 +1,36 @@
+package storagequotaservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+)
+
+// ErrStorageNotWritable el almacenamiento de archivos no permite crear o borrar archivos
+var ErrStorageNotWritable = errors.New("file storage is not writable")
+
+// storageProbeFile nombre base del archivo que se escribe y borra para comprobar el almacenamiento
+const storageProbeFile = ".storage-write-probe"
+
+// VerifyStorageWritable comprueba al arrancar que el almacenamiento acepta escrituras escribiendo y
+// borrando un archivo de prueba, para que un directorio sin permisos falle al inicio y no en mitad
+// de una grabación o transferencia.
+func VerifyStorageWritable(ctx context.Context, fileStorage interfaces.IFileStorage) error {
+	if fileStorage == nil {
+		return fmt.Errorf("%w: no file storage configured", ErrStorageNotWritable)
+	}
+
+	probePath := fmt.Sprintf("%s-%d", storageProbeFile, time.Now().UnixNano())
+	savedPath, err := fileStorage.SaveFile(ctx, probePath, []byte("probe"))
+	if err != nil {
+		return fmt.Errorf("%w: cannot write %s: %v", ErrStorageNotWritable, fileStorage.GetFilePath(probePath), err)
+	}
+
+	if err := fileStorage.DeleteFile(ctx, savedPath); err != nil {
+		return fmt.Errorf("%w: cannot delete %s: %v", ErrStorageNotWritable, savedPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package storagequotaservice
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// diskFileStorage almacenamiento en disco mínimo sobre un directorio raíz
+type diskFileStorage struct {
+	root string
+}
+
+func (s *diskFileStorage) SaveFile(ctx context.Context, destinationPath string, content []byte) (string, error) {
+	fullPath := s.GetFilePath(destinationPath)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(fullPath, content, 0644); err != nil {
+		return "", err
+	}
+	return fullPath, nil
+}
+
+func (s *diskFileStorage) MoveFile(ctx context.Context, sourcePath, destinationPath string) (string, error) {
+	fullPath := s.GetFilePath(destinationPath)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return "", err
+	}
+	if err := os.Rename(sourcePath, fullPath); err != nil {
+		return "", err
+	}
+	return fullPath, nil
+}
+
+func (s *diskFileStorage) ReadFile(ctx context.Context, filePath string) ([]byte, error) {
+	return os.ReadFile(filePath)
+}
+
+func (s *diskFileStorage) DeleteFile(ctx context.Context, filePath string) error {
+	return os.Remove(filePath)
+}
+
+func (s *diskFileStorage) FileExists(ctx context.Context, filePath string) bool {
+	_, err := os.Stat(filePath)
+	return err == nil
+}
+
+func (s *diskFileStorage) GetFileSize(ctx context.Context, filePath string) (int64, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func (s *diskFileStorage) CreateDirectory(ctx context.Context, dirPath string) error {
+	return os.MkdirAll(s.GetFilePath(dirPath), 0755)
+}
+
+func (s *diskFileStorage) GetFilePath(relativePath string) string {
+	return filepath.Join(s.root, relativePath)
+}
+
+// failingDeleteStorage permite escribir pero no borrar
+type failingDeleteStorage struct {
+	diskFileStorage
+}
+
+func (s *failingDeleteStorage) DeleteFile(ctx context.Context, filePath string) error {
+	return errors.New("permission denied")
+}
+
+func TestVerifyStorageWritable_WritableDirectoryLeavesNoProbe(t *testing.T) {
+	// Arrange
+	root := t.TempDir()
+
+	// Act
+	err := VerifyStorageWritable(context.Background(), &diskFileStorage{root: root})
+
+	// Assert
+	require.NoError(t, err)
+	entries, err := os.ReadDir(root)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestVerifyStorageWritable_ReadOnlyDirectoryFails(t *testing.T) {
+	// Arrange
+	if os.Geteuid() == 0 {
+		t.Skip("root ignores directory permissions")
+	}
+	root := t.TempDir()
+	require.NoError(t, os.Chmod(root, 0555))
+	t.Cleanup(func() { os.Chmod(root, 0755) })
+
+	// Act
+	err := VerifyStorageWritable(context.Background(), &diskFileStorage{root: root})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrStorageNotWritable)
+}
+
+func TestVerifyStorageWritable_FailsWhenProbeCannotBeWrittenOrDeleted(t *testing.T) {
+	// Arrange
+	root := t.TempDir()
+	notADirectory := filepath.Join(root, "storage")
+	require.NoError(t, os.WriteFile(notADirectory, []byte("x"), 0644))
+
+	// Act
+	writeErr := VerifyStorageWritable(context.Background(), &diskFileStorage{root: notADirectory})
+	deleteErr := VerifyStorageWritable(context.Background(), &failingDeleteStorage{diskFileStorage{root: root}})
+	nilErr := VerifyStorageWritable(context.Background(), nil)
+
+	// Assert
+	assert.ErrorIs(t, writeErr, ErrStorageNotWritable)
+	assert.ErrorIs(t, deleteErr, ErrStorageNotWritable)
+	assert.ErrorIs(t, nilErr, ErrStorageNotWritable)
+}
@@ -0,0 +1,182 @@
+package storagequotaservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+)
+
+// DefaultClientQuotaMB es la cuota de almacenamiento por cliente si no se configura otra (5 GB)
+const DefaultClientQuotaMB = 5120.0
+
+// ErrStorageQuotaExceeded se devuelve cuando un cliente alcanzó su cuota de almacenamiento
+var ErrStorageQuotaExceeded = errors.New("storage quota exceeded")
+
+// ClientStorageUsage resume el almacenamiento ocupado por un PC cliente
+type ClientStorageUsage struct {
+	ClientPCID   string  `json:"client_pc_id"`
+	RecordingsMB float64 `json:"recordings_mb"`
+	TransfersMB  float64 `json:"transfers_mb"`
+	TotalMB      float64 `json:"total_mb"`
+	QuotaMB      float64 `json:"quota_mb"`
+}
+
+// RemainingMB retorna el espacio disponible antes de alcanzar la cuota
+func (u *ClientStorageUsage) RemainingMB() float64 {
+	remaining := u.QuotaMB - u.TotalMB
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// IStorageQuotaService define la interfaz del servicio de cuotas de almacenamiento
+type IStorageQuotaService interface {
+	GetClientStorageUsage(ctx context.Context, clientPCID string) (*ClientStorageUsage, error)
+	CheckQuota(ctx context.Context, clientPCID string, additionalMB float64) (*ClientStorageUsage, error)
+	ReserveQuota(ctx context.Context, clientPCID string, additionalMB float64) (func(), *ClientStorageUsage, error)
+}
+
+// clientReservations espacio reservado para un cliente por operaciones que aún no guardaron su registro. mu
+// serializa la comprobación y la reserva de ese cliente; refs cuenta quién espera o mantiene una reserva, para
+// quitar la entrada cuando ya nadie la usa.
+type clientReservations struct {
+	mu         sync.Mutex
+	refs       int
+	reservedMB float64
+}
+
+// storageQuotaService implementa IStorageQuotaService
+type storageQuotaService struct {
+	videoRepository        interfaces.ISessionVideoRepository
+	fileTransferRepository interfaces.IFileTransferRepository
+	quotaMB                float64
+
+	reservationsMutex sync.Mutex
+	reservations      map[string]*clientReservations
+}
+
+// NewStorageQuotaService crea una nueva instancia del servicio de cuotas.
+// Si quotaMB <= 0 se usa DefaultClientQuotaMB.
+func NewStorageQuotaService(
+	videoRepository interfaces.ISessionVideoRepository,
+	fileTransferRepository interfaces.IFileTransferRepository,
+	quotaMB float64,
+) IStorageQuotaService {
+	if quotaMB <= 0 {
+		quotaMB = DefaultClientQuotaMB
+	}
+
+	return &storageQuotaService{
+		videoRepository:        videoRepository,
+		fileTransferRepository: fileTransferRepository,
+		quotaMB:                quotaMB,
+		reservations:           make(map[string]*clientReservations),
+	}
+}
+
+// GetClientStorageUsage suma el tamaño de las grabaciones y transferencias asociadas al cliente
+func (s *storageQuotaService) GetClientStorageUsage(ctx context.Context, clientPCID string) (*ClientStorageUsage, error) {
+	if clientPCID == "" {
+		return nil, errors.New("client PC ID cannot be empty")
+	}
+
+	usage := &ClientStorageUsage{
+		ClientPCID: clientPCID,
+		QuotaMB:    s.quotaMB,
+	}
+
+	// Grabaciones: videos asociados a las sesiones del cliente, sumados en la base de datos
+	recordingsMB, err := s.videoRepository.SumFileSizeByClientPCID(ctx, clientPCID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo grabaciones del cliente: %w", err)
+	}
+	usage.RecordingsMB = recordingsMB
+
+	// Transferencias: las fallidas no ocupan espacio en el cliente
+	transfersMB, err := s.fileTransferRepository.SumFileSizeByTargetPCID(ctx, clientPCID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo transferencias del cliente: %w", err)
+	}
+	usage.TransfersMB = transfersMB
+
+	usage.TotalMB = usage.RecordingsMB + usage.TransfersMB
+	return usage, nil
+}
+
+// CheckQuota verifica que el cliente tenga espacio para additionalMB adicionales, contando lo ya reservado.
+// Un cliente que ya alcanzó su cuota no puede aceptar nuevos datos aunque additionalMB sea 0.
+func (s *storageQuotaService) CheckQuota(ctx context.Context, clientPCID string, additionalMB float64) (*ClientStorageUsage, error) {
+	release, usage, err := s.ReserveQuota(ctx, clientPCID, additionalMB)
+	if err != nil {
+		return usage, err
+	}
+	release()
+	return usage, nil
+}
+
+// ReserveQuota comprueba la cuota y reserva additionalMB en un solo paso: dos subidas simultáneas al mismo cliente
+// no pueden pasar ambas la comprobación con el mismo espacio libre. La reserva cuenta para las siguientes
+// comprobaciones hasta llamar a release, que el llamador invoca cuando ya guardó el registro que ocupa ese espacio
+// (desde entonces lo cuenta la base de datos) o cuando la operación falla. Si se rechaza no queda nada reservado.
+func (s *storageQuotaService) ReserveQuota(ctx context.Context, clientPCID string, additionalMB float64) (func(), *ClientStorageUsage, error) {
+	entry := s.acquireReservations(clientPCID)
+	entry.mu.Lock()
+
+	usage, err := s.GetClientStorageUsage(ctx, clientPCID)
+	if err != nil {
+		entry.mu.Unlock()
+		s.releaseReservations(clientPCID, entry)
+		return nil, nil, err
+	}
+	usage.TotalMB += entry.reservedMB
+
+	if usage.TotalMB >= usage.QuotaMB || usage.TotalMB+additionalMB > usage.QuotaMB {
+		entry.mu.Unlock()
+		s.releaseReservations(clientPCID, entry)
+		return nil, usage, fmt.Errorf("%w: client %s uses %.2f MB of %.2f MB (requested %.2f MB)",
+			ErrStorageQuotaExceeded, clientPCID, usage.TotalMB, usage.QuotaMB, additionalMB)
+	}
+
+	entry.reservedMB += additionalMB
+	entry.mu.Unlock()
+
+	var releaseOnce sync.Once
+	release := func() {
+		releaseOnce.Do(func() {
+			entry.mu.Lock()
+			entry.reservedMB -= additionalMB
+			entry.mu.Unlock()
+			s.releaseReservations(clientPCID, entry)
+		})
+	}
+	return release, usage, nil
+}
+
+// acquireReservations retorna la entrada de reservas del cliente, creándola si no existe, y la marca en uso
+func (s *storageQuotaService) acquireReservations(clientPCID string) *clientReservations {
+	s.reservationsMutex.Lock()
+	defer s.reservationsMutex.Unlock()
+
+	entry, exists := s.reservations[clientPCID]
+	if !exists {
+		entry = &clientReservations{}
+		s.reservations[clientPCID] = entry
+	}
+	entry.refs++
+	return entry
+}
+
+// releaseReservations deja de usar la entrada y la elimina cuando nadie espera ni mantiene una reserva
+func (s *storageQuotaService) releaseReservations(clientPCID string, entry *clientReservations) {
+	s.reservationsMutex.Lock()
+	defer s.reservationsMutex.Unlock()
+
+	entry.refs--
+	if entry.refs == 0 {
+		delete(s.reservations, clientPCID)
+	}
+}
@@ -0,0 +1,298 @@
+package storagequotaservice
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+// MockSessionVideoRepository es un mock del repositorio de videos de sesión
+type MockSessionVideoRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionVideoRepository) Save(ctx context.Context, video *sessionvideo.SessionVideo) error {
+	return m.Called(ctx, video).Error(0)
+}
+
+func (m *MockSessionVideoRepository) FindByID(ctx context.Context, videoID string) (*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, videoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sessionvideo.SessionVideo), args.Error(1)
+}
+
+func (m *MockSessionVideoRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*sessionvideo.SessionVideo), args.Error(1)
+}
+
+func (m *MockSessionVideoRepository) Update(ctx context.Context, video *sessionvideo.SessionVideo) error {
+	return m.Called(ctx, video).Error(0)
+}
+
+func (m *MockSessionVideoRepository) Delete(ctx context.Context, videoID string) error {
+	return m.Called(ctx, videoID).Error(0)
+}
+
+func (m *MockSessionVideoRepository) FindAll(ctx context.Context, limit, offset int) ([]*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*sessionvideo.SessionVideo), args.Error(1)
+}
+
+func (m *MockSessionVideoRepository) Count(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSessionVideoRepository) FindByDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, startDate, endDate, limit, offset)
+	return args.Get(0).([]*sessionvideo.SessionVideo), args.Error(1)
+}
+
+func (m *MockSessionVideoRepository) SaveFrameIndex(ctx context.Context, videoID string, entries []sessionvideo.FrameIndexEntry) error {
+	return m.Called(ctx, videoID, entries).Error(0)
+}
+
+func (m *MockSessionVideoRepository) SumFileSizeByClientPCID(ctx context.Context, clientPCID string) (float64, error) {
+	args := m.Called(ctx, clientPCID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockSessionVideoRepository) FindOrphaned(ctx context.Context, limit int) ([]*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*sessionvideo.SessionVideo), args.Error(1)
+}
+
+// MockFileTransferRepository es un mock del repositorio de transferencias
+type MockFileTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockFileTransferRepository) Save(ctx context.Context, transfer *filetransfer.FileTransfer) error {
+	return m.Called(ctx, transfer).Error(0)
+}
+
+func (m *MockFileTransferRepository) UpdateStatus(ctx context.Context, transferID string, status filetransfer.TransferStatus, errorMessage string) error {
+	return m.Called(ctx, transferID, status, errorMessage).Error(0)
+}
+
+func (m *MockFileTransferRepository) FindByID(ctx context.Context, transferID string) (*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindByTargetPCID(ctx context.Context, targetPCID string) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, targetPCID)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindByInitiatingUserID(ctx context.Context, userID string) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindPendingTransfers(ctx context.Context) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) SumFileSizeByTargetPCID(ctx context.Context, targetPCID string) (float64, error) {
+	args := m.Called(ctx, targetPCID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindInProgressTransfers(ctx context.Context) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) Delete(ctx context.Context, transferID string) error {
+	return m.Called(ctx, transferID).Error(0)
+}
+
+const testClientPCID = "550e8400-e29b-41d4-a716-446655440001"
+
+// setupUsage prepara un cliente con 60 MB de grabaciones y 30 MB de transferencias (las fallidas ya excluidas en SQL)
+func setupUsage(ctx context.Context) (*MockSessionVideoRepository, *MockFileTransferRepository) {
+	videoRepo := new(MockSessionVideoRepository)
+	transferRepo := new(MockFileTransferRepository)
+
+	videoRepo.On("SumFileSizeByClientPCID", ctx, testClientPCID).Return(60.0, nil)
+	transferRepo.On("SumFileSizeByTargetPCID", ctx, testClientPCID).Return(30.0, nil)
+
+	return videoRepo, transferRepo
+}
+
+func TestStorageQuotaService_GetClientStorageUsage(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	videoRepo, transferRepo := setupUsage(ctx)
+	service := NewStorageQuotaService(videoRepo, transferRepo, 100)
+
+	// Act
+	usage, err := service.GetClientStorageUsage(ctx, testClientPCID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 60.0, usage.RecordingsMB)
+	assert.Equal(t, 30.0, usage.TransfersMB)
+	assert.Equal(t, 90.0, usage.TotalMB)
+	assert.Equal(t, 100.0, usage.QuotaMB)
+	assert.Equal(t, 10.0, usage.RemainingMB())
+}
+
+func TestStorageQuotaService_CheckQuota_UnderQuota(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	videoRepo, transferRepo := setupUsage(ctx)
+	service := NewStorageQuotaService(videoRepo, transferRepo, 100)
+
+	// Act
+	usage, err := service.CheckQuota(ctx, testClientPCID, 5)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 90.0, usage.TotalMB)
+}
+
+func TestStorageQuotaService_CheckQuota_AtQuota(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	videoRepo, transferRepo := setupUsage(ctx)
+	service := NewStorageQuotaService(videoRepo, transferRepo, 90)
+
+	// Act
+	_, err := service.CheckQuota(ctx, testClientPCID, 0)
+
+	// Assert
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrStorageQuotaExceeded))
+}
+
+func TestStorageQuotaService_CheckQuota_RequestFitsExactly(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	videoRepo, transferRepo := setupUsage(ctx)
+	service := NewStorageQuotaService(videoRepo, transferRepo, 100)
+
+	// Act
+	_, err := service.CheckQuota(ctx, testClientPCID, 10)
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestStorageQuotaService_CheckQuota_OverQuota(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	videoRepo, transferRepo := setupUsage(ctx)
+	service := NewStorageQuotaService(videoRepo, transferRepo, 100)
+
+	// Act
+	usage, err := service.CheckQuota(ctx, testClientPCID, 10.5)
+
+	// Assert
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrStorageQuotaExceeded))
+	assert.NotNil(t, usage)
+	assert.Equal(t, 90.0, usage.TotalMB)
+}
+
+func TestStorageQuotaService_DefaultQuota(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	videoRepo, transferRepo := setupUsage(ctx)
+	service := NewStorageQuotaService(videoRepo, transferRepo, 0)
+
+	// Act
+	usage, err := service.GetClientStorageUsage(ctx, testClientPCID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultClientQuotaMB, usage.QuotaMB)
+}
+
+func TestStorageQuotaService_GetClientStorageUsage_RepositoryError(t *testing.T) {
+	// Arrange
+	videoRepo := new(MockSessionVideoRepository)
+	service := NewStorageQuotaService(videoRepo, new(MockFileTransferRepository), 100)
+	videoRepo.On("SumFileSizeByClientPCID", mock.Anything, testClientPCID).Return(0.0, errors.New("database error"))
+
+	// Act
+	usage, err := service.GetClientStorageUsage(context.Background(), testClientPCID)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, usage)
+	assert.False(t, errors.Is(err, ErrStorageQuotaExceeded))
+}
+
+func TestStorageQuotaService_ReserveQuota_CountsPendingReservations(t *testing.T) {
+	// Arrange - 90 MB usados de 100: caben 8 MB, pero no dos veces
+	ctx := context.Background()
+	videoRepo, transferRepo := setupUsage(ctx)
+	service := NewStorageQuotaService(videoRepo, transferRepo, 100)
+
+	release, _, err := service.ReserveQuota(ctx, testClientPCID, 8)
+	assert.NoError(t, err)
+
+	// Act
+	_, usage, secondErr := service.ReserveQuota(ctx, testClientPCID, 8)
+	release()
+	releaseAfter, _, afterReleaseErr := service.ReserveQuota(ctx, testClientPCID, 8)
+
+	// Assert
+	assert.True(t, errors.Is(secondErr, ErrStorageQuotaExceeded))
+	assert.Equal(t, 98.0, usage.TotalMB)
+	assert.NoError(t, afterReleaseErr)
+	releaseAfter()
+	assert.Empty(t, service.(*storageQuotaService).reservations)
+}
+
+func TestStorageQuotaService_ReserveQuota_ConcurrentRequestsDoNotOvershoot(t *testing.T) {
+	// Arrange - 10 MB libres y 10 subidas simultáneas de 4 MB: solo caben dos
+	ctx := context.Background()
+	videoRepo, transferRepo := setupUsage(ctx)
+	service := NewStorageQuotaService(videoRepo, transferRepo, 100)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var releases []func()
+
+	// Act
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, _, err := service.ReserveQuota(ctx, testClientPCID, 4)
+			if err == nil {
+				mu.Lock()
+				releases = append(releases, release)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Len(t, releases, 2)
+	for _, release := range releases {
+		release()
+	}
+}
@@ -2,18 +2,46 @@ package userservice
 
 import (
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// Errores de autenticación. La capa HTTP/WebSocket los presenta todos como "credenciales inválidas"
+// para no revelar si un usuario existe, tiene otro rol o está desactivado.
+var (
+	// ErrInvalidCredentials el usuario no existe o la contraseña no es correcta
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrNotAdministrator el usuario no tiene rol de administrador
+	ErrNotAdministrator = errors.New("user is not an administrator")
+	// ErrNotClientUser el usuario no tiene rol de usuario cliente
+	ErrNotClientUser = errors.New("user is not a client user")
+	// ErrUserInactive la cuenta del usuario está desactivada
+	ErrUserInactive = errors.New("user account is not active")
+	// ErrTokenGeneration no se pudo firmar el token JWT
+	ErrTokenGeneration = errors.New("failed to generate authentication token")
+	// ErrInvalidToken el token JWT no es válido o expiró
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// dummyPasswordHash hash bcrypt, con el coste por defecto de las contraseñas guardadas, con el que se compara la
+// contraseña de un usuario que no existe
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("escritorio-remoto-dummy-password"), bcrypt.DefaultCost)
+
 // AuthService maneja la autenticación de usuarios
 type AuthService struct {
 	userRepository interfaces.IUserRepository
 	jwtSecret      []byte
 	jwtExpiration  time.Duration
+
+	// tokenVersions caché de users.token_version; revocar la incrementa
+	tokenVersions map[string]int
+	tokenMutex    sync.RWMutex
 }
 
 // NewAuthService crea una nueva instancia del servicio de autenticación
@@ -22,41 +50,32 @@ func NewAuthService(userRepository interfaces.IUserRepository, jwtSecret string)
 		userRepository: userRepository,
 		jwtSecret:      []byte(jwtSecret),
 		jwtExpiration:  24 * time.Hour, // 24 horas por defecto
+		tokenVersions:  make(map[string]int),
 	}
 }
 
 // AuthenticateAdmin autentica un administrador y retorna un token JWT
 func (s *AuthService) AuthenticateAdmin(username, password string) (string, *user.User, error) {
-	// Buscar usuario por nombre de usuario
-	foundUser, err := s.userRepository.FindByUsername(username)
+	// Buscar el usuario y validar la contraseña antes de mirar rol o estado
+	foundUser, err := s.verifyCredentials(username, password)
 	if err != nil {
-		return "", nil, errors.New("invalid credentials")
-	}
-
-	// Verificar que el usuario existe
-	if foundUser == nil {
-		return "", nil, errors.New("invalid credentials")
+		return "", nil, err
 	}
 
 	// Verificar que es un administrador
 	if !foundUser.IsAdministrator() {
-		return "", nil, errors.New("user is not an administrator")
+		return "", nil, ErrNotAdministrator
 	}
 
 	// Verificar que está activo
 	if !foundUser.IsActive() {
-		return "", nil, errors.New("user account is not active")
-	}
-
-	// Validar contraseña
-	if err := foundUser.ValidatePassword(password); err != nil {
-		return "", nil, errors.New("invalid credentials")
+		return "", nil, ErrUserInactive
 	}
 
 	// Generar token JWT
 	token, err := s.generateJWT(foundUser)
 	if err != nil {
-		return "", nil, errors.New("failed to generate authentication token")
+		return "", nil, fmt.Errorf("%w: %v", ErrTokenGeneration, err)
 	}
 
 	return token, foundUser, nil
@@ -64,41 +83,47 @@ func (s *AuthService) AuthenticateAdmin(username, password string) (string, *use
 
 // AuthenticateClient autentica un usuario cliente y retorna un token JWT
 func (s *AuthService) AuthenticateClient(username, password string) (string, *user.User, error) {
-	// Buscar usuario por nombre de usuario
-	foundUser, err := s.userRepository.FindByUsername(username)
+	// Buscar el usuario y validar la contraseña antes de mirar rol o estado
+	foundUser, err := s.verifyCredentials(username, password)
 	if err != nil {
-		return "", nil, errors.New("invalid credentials")
-	}
-
-	// Verificar que el usuario existe
-	if foundUser == nil {
-		return "", nil, errors.New("invalid credentials")
+		return "", nil, err
 	}
 
 	// Verificar que es un usuario cliente
 	if !foundUser.IsClientUser() {
-		return "", nil, errors.New("user is not a client user")
+		return "", nil, ErrNotClientUser
 	}
 
 	// Verificar que está activo
 	if !foundUser.IsActive() {
-		return "", nil, errors.New("user account is not active")
-	}
-
-	// Validar contraseña
-	if err := foundUser.ValidatePassword(password); err != nil {
-		return "", nil, errors.New("invalid credentials")
+		return "", nil, ErrUserInactive
 	}
 
 	// Generar token JWT
 	token, err := s.generateJWT(foundUser)
 	if err != nil {
-		return "", nil, errors.New("failed to generate authentication token")
+		return "", nil, fmt.Errorf("%w: %v", ErrTokenGeneration, err)
 	}
 
 	return token, foundUser, nil
 }
 
+// verifyCredentials busca el usuario y compara la contraseña con bcrypt. La comparación se hace siempre, contra
+// dummyPasswordHash si el usuario no existe o la consulta falla, así el tiempo de respuesta no revela qué usuarios
+// existen ni su rol o estado, que se comprueban después.
+func (s *AuthService) verifyCredentials(username, password string) (*user.User, error) {
+	foundUser, err := s.userRepository.FindByUsername(username)
+
+	hashedPassword := dummyPasswordHash
+	if err == nil && foundUser != nil {
+		hashedPassword = []byte(foundUser.HashedPassword())
+	}
+	if compareErr := bcrypt.CompareHashAndPassword(hashedPassword, []byte(password)); compareErr != nil || err != nil || foundUser == nil {
+		return nil, ErrInvalidCredentials
+	}
+	return foundUser, nil
+}
+
 // ValidateToken valida un token JWT y retorna los claims
 func (s *AuthService) ValidateToken(tokenString string) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
@@ -106,14 +131,21 @@ func (s *AuthService) ValidateToken(tokenString string) (*JWTClaims, error) {
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
 	}
 
 	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+		version, err := s.tokenVersion(claims.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+		}
+		if claims.TokenVersion < version {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidToken, ErrTokenRevoked)
+		}
 		return claims, nil
 	}
 
-	return nil, errors.New("invalid token")
+	return nil, ErrInvalidToken
 }
 
 // generateJWT genera un token JWT para el usuario
@@ -122,6 +154,8 @@ func (s *AuthService) generateJWT(u *user.User) (string, error) {
 		UserID:   u.UserID(),
 		Username: u.Username(),
 		Role:     string(u.Role()),
+		// Los tokens anteriores a la última revocación del usuario dejan de ser válidos
+		TokenVersion: s.rememberTokenVersion(u.UserID(), u.TokenVersion()),
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.jwtExpiration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -140,5 +174,7 @@ type JWTClaims struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// TokenVersion versión de los tokens del usuario al emitirlo; ver RevokeUserTokens
+	TokenVersion int `json:"token_version,omitempty"`
 	jwt.RegisteredClaims
 }
@@ -1,6 +1,7 @@
 package userservice
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -32,6 +33,11 @@ func (m *MockUserRepository) FindByID(userID string) (*user.User, error) {
 	return args.Get(0).(*user.User), args.Error(1)
 }
 
+func (m *MockUserRepository) FindByIDs(ctx context.Context, userIDs []string) (map[string]*user.User, error) {
+	args := m.Called(ctx, userIDs)
+	return args.Get(0).(map[string]*user.User), args.Error(1)
+}
+
 func (m *MockUserRepository) Save(user *user.User) error {
 	args := m.Called(user)
 	return args.Error(0)
@@ -42,6 +48,11 @@ func (m *MockUserRepository) Create(user *user.User) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) IncrementTokenVersion(userID string) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
 func TestAuthService_AuthenticateAdmin_Success(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockUserRepository)
@@ -85,7 +96,7 @@ func TestAuthService_AuthenticateAdmin_UserNotFound(t *testing.T) {
 	assert.Error(t, err)
 	assert.Empty(t, token)
 	assert.Nil(t, returnedUser)
-	assert.Contains(t, err.Error(), "invalid credentials")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -112,7 +123,7 @@ func TestAuthService_AuthenticateAdmin_WrongPassword(t *testing.T) {
 	assert.Error(t, err)
 	assert.Empty(t, token)
 	assert.Nil(t, returnedUser)
-	assert.Contains(t, err.Error(), "invalid credentials")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -139,10 +150,33 @@ func TestAuthService_AuthenticateAdmin_NotAdministrator(t *testing.T) {
 	assert.Error(t, err)
 	assert.Empty(t, token)
 	assert.Nil(t, returnedUser)
-	assert.Contains(t, err.Error(), "not an administrator")
+	assert.ErrorIs(t, err, ErrNotAdministrator)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestAuthService_AuthenticateAdmin_AcceptsOperatorAndViewerRoles(t *testing.T) {
+	for _, role := range []user.Role{user.RoleOperator, user.RoleViewer} {
+		t.Run(string(role), func(t *testing.T) {
+			// Arrange
+			mockRepo := new(MockUserRepository)
+			authService := NewAuthService(mockRepo, "test-secret")
+
+			hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+			adminUser := user.NewUser("staff-id", "staff", "127.0.0.1", string(hashedPassword), role)
+			mockRepo.On("FindByUsername", "staff").Return(adminUser, nil)
+
+			// Act
+			token, _, err := authService.AuthenticateAdmin("staff", "password")
+
+			// Assert - el token lleva el rol para que RequireRole autorice cada endpoint
+			assert.NoError(t, err)
+			claims, err := authService.ValidateToken(token)
+			assert.NoError(t, err)
+			assert.Equal(t, string(role), claims.Role)
+		})
+	}
+}
+
 func TestAuthService_AuthenticateAdmin_InactiveUser(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockUserRepository)
@@ -167,10 +201,64 @@ func TestAuthService_AuthenticateAdmin_InactiveUser(t *testing.T) {
 	assert.Error(t, err)
 	assert.Empty(t, token)
 	assert.Nil(t, returnedUser)
-	assert.Contains(t, err.Error(), "not active")
+	assert.ErrorIs(t, err, ErrUserInactive)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestAuthService_Authenticate_WrongPasswordHidesRoleAndStatus(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	inactiveAdmin := user.NewUser("admin-id", "admin", "127.0.0.1", string(hashedPassword), user.RoleAdministrator)
+	inactiveAdmin.Deactivate()
+	clientUser := user.NewUser("client-id", "client", "127.0.0.1", string(hashedPassword), user.RoleClientUser)
+
+	tests := []struct {
+		name         string
+		username     string
+		foundUser    *user.User
+		authenticate func(*AuthService, string, string) (string, *user.User, error)
+	}{
+		{name: "admin login of inactive admin", username: "admin", foundUser: inactiveAdmin, authenticate: (*AuthService).AuthenticateAdmin},
+		{name: "admin login of client user", username: "client", foundUser: clientUser, authenticate: (*AuthService).AuthenticateAdmin},
+		{name: "client login of inactive admin", username: "admin", foundUser: inactiveAdmin, authenticate: (*AuthService).AuthenticateClient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(MockUserRepository)
+			authService := NewAuthService(mockRepo, "test-secret")
+			mockRepo.On("FindByUsername", tt.username).Return(tt.foundUser, nil)
+
+			// Act
+			token, returnedUser, err := tt.authenticate(authService, tt.username, "wrong-password")
+
+			// Assert - la contraseña se valida antes que el rol y el estado
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+			assert.Empty(t, token)
+			assert.Nil(t, returnedUser)
+		})
+	}
+}
+
+func TestAuthService_AuthenticateClient_RejectsAdministrator(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockUserRepository)
+	authService := NewAuthService(mockRepo, "test-secret")
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	adminUser := user.NewUser("admin-id", "admin", "127.0.0.1", string(hashedPassword), user.RoleAdministrator)
+
+	mockRepo.On("FindByUsername", "admin").Return(adminUser, nil)
+
+	// Act
+	token, returnedUser, err := authService.AuthenticateClient("admin", "password")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNotClientUser)
+	assert.Empty(t, token)
+	assert.Nil(t, returnedUser)
+}
+
 func TestAuthService_AuthenticateAdmin_RepositoryError(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockUserRepository)
@@ -185,7 +273,7 @@ func TestAuthService_AuthenticateAdmin_RepositoryError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Empty(t, token)
 	assert.Nil(t, returnedUser)
-	assert.Contains(t, err.Error(), "invalid credentials")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -229,7 +317,7 @@ func TestAuthService_ValidateToken_InvalidToken(t *testing.T) {
 	claims, err := authService.ValidateToken("invalid-token")
 
 	// Assert
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidToken)
 	assert.Nil(t, claims)
 }
 
@@ -266,3 +354,34 @@ func TestAuthService_ValidateToken_ExpiredToken(t *testing.T) {
 	assert.Nil(t, claims)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestAuthService_RevokeUserTokens_SurvivesRestart(t *testing.T) {
+	// Arrange - el token se emite antes de revocar
+	mockRepo := new(MockUserRepository)
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	adminUser := user.NewUser("admin-id", "admin", "127.0.0.1", string(hashedPassword), user.RoleAdministrator)
+	mockRepo.On("FindByUsername", "admin").Return(adminUser, nil)
+	mockRepo.On("FindByID", "admin-id").Return(adminUser, nil)
+	mockRepo.On("IncrementTokenVersion", "admin-id").Return(1, nil).Run(func(args mock.Arguments) {
+		adminUser.RestoreTokenVersion(1)
+	})
+
+	authService := NewAuthService(mockRepo, "test-secret")
+	token, _, err := authService.AuthenticateAdmin("admin", "password")
+	assert.NoError(t, err)
+	_, err = authService.RevokeUserTokens("admin-id")
+	assert.NoError(t, err)
+
+	// Act - un servidor recién arrancado no tiene la revocación en memoria
+	restarted := NewAuthService(mockRepo, "test-secret")
+	claims, err := restarted.ValidateToken(token)
+
+	// Assert - lee users.token_version y sigue rechazando el token
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+	assert.Nil(t, claims)
+	newToken, _, err := restarted.AuthenticateAdmin("admin", "password")
+	assert.NoError(t, err)
+	_, err = restarted.ValidateToken(newToken)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,59 @@
+package userservice
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DevelopmentJWTSecret secreto que se usa en modo desarrollo cuando JWT_SECRET no está definido
+const DevelopmentJWTSecret = "escritorio_remoto_jwt_secret_development_2025"
+
+// MinJWTSecretLength longitud mínima del secreto con el que se firman los tokens
+const MinJWTSecretLength = 32
+
+var (
+	// ErrJWTSecretMissing JWT_SECRET no está definido
+	ErrJWTSecretMissing = errors.New("jwt secret is not set")
+	// ErrJWTSecretDefault JWT_SECRET es uno de los valores publicados en el repositorio
+	ErrJWTSecretDefault = errors.New("jwt secret is a known default")
+	// ErrJWTSecretTooShort JWT_SECRET tiene menos de MinJWTSecretLength caracteres
+	ErrJWTSecretTooShort = errors.New("jwt secret is too short")
+)
+
+// knownJWTSecrets secretos que aparecen en el código y en los ficheros de configuración de ejemplo: cualquiera
+// que tenga acceso al repositorio puede firmar tokens con ellos
+var knownJWTSecrets = map[string]bool{
+	DevelopmentJWTSecret: true,
+	"tu_jwt_secret_muy_seguro_aqui_cambiar_en_produccion": true,
+}
+
+// validateJWTSecret comprueba que el secreto no falta, no es un valor conocido y tiene la longitud mínima
+func validateJWTSecret(secret string) error {
+	switch {
+	case secret == "":
+		return ErrJWTSecretMissing
+	case knownJWTSecrets[secret]:
+		return ErrJWTSecretDefault
+	case len(secret) < MinJWTSecretLength:
+		return fmt.Errorf("%w: %d characters, minimum %d", ErrJWTSecretTooShort, len(secret), MinJWTSecretLength)
+	}
+	return nil
+}
+
+// ResolveJWTSecret devuelve el secreto con el que firmar los tokens. Fuera de desarrollo un secreto que no pasa la
+// validación es un error y el servidor no debe arrancar. En desarrollo se acepta con un aviso y, si falta, se usa
+// DevelopmentJWTSecret.
+func ResolveJWTSecret(secret string, developmentMode bool) (string, string, error) {
+	err := validateJWTSecret(secret)
+	if err == nil {
+		return secret, "", nil
+	}
+	if !developmentMode {
+		return "", "", fmt.Errorf("invalid JWT_SECRET: %w", err)
+	}
+
+	if secret == "" {
+		secret = DevelopmentJWTSecret
+	}
+	return secret, fmt.Sprintf("JWT_SECRET no válido (%v); aceptado solo porque el servidor está en modo desarrollo", err), nil
+}
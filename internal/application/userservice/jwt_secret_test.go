@@ -0,0 +1,63 @@
+package userservice
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveJWTSecret_ProductionRejectsMissingDefaultAndShortSecrets(t *testing.T) {
+	cases := map[string]struct {
+		secret      string
+		expectedErr error
+	}{
+		"missing":     {secret: "", expectedErr: ErrJWTSecretMissing},
+		"development": {secret: DevelopmentJWTSecret, expectedErr: ErrJWTSecretDefault},
+		"example":     {secret: "tu_jwt_secret_muy_seguro_aqui_cambiar_en_produccion", expectedErr: ErrJWTSecretDefault},
+		"too short":   {secret: "short-secret", expectedErr: ErrJWTSecretTooShort},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			secret, warning, err := ResolveJWTSecret(tc.secret, false)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Empty(t, secret)
+			assert.Empty(t, warning)
+		})
+	}
+}
+
+func TestResolveJWTSecret_DevelopmentWarnsButProceeds(t *testing.T) {
+	// Act
+	secret, warning, err := ResolveJWTSecret("", true)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, DevelopmentJWTSecret, secret)
+	assert.Contains(t, warning, ErrJWTSecretMissing.Error())
+
+	// Un secreto corto también se acepta en desarrollo, sin sustituirlo
+	secret, warning, err = ResolveJWTSecret("short-secret", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "short-secret", secret)
+	assert.NotEmpty(t, warning)
+}
+
+func TestResolveJWTSecret_AcceptsStrongSecretWithoutWarning(t *testing.T) {
+	// Arrange
+	strongSecret := strings.Repeat("k", MinJWTSecretLength)
+
+	for _, developmentMode := range []bool{false, true} {
+		// Act
+		secret, warning, err := ResolveJWTSecret(strongSecret, developmentMode)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, strongSecret, secret)
+		assert.Empty(t, warning)
+	}
+}
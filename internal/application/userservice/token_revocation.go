@@ -0,0 +1,68 @@
+package userservice
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+)
+
+var (
+	// ErrTokenRevoked el token se emitió antes de revocar los tokens del usuario
+	ErrTokenRevoked = errors.New("token has been revoked")
+	// ErrUserNotFound no existe un usuario con ese ID
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// tokenVersion versión vigente de los tokens del usuario. La columna users.token_version es la fuente de verdad;
+// tokenVersions la guarda en memoria para no consultar la BD en cada petición y se rellena la primera vez que se
+// valida un token del usuario tras arrancar el servidor.
+func (s *AuthService) tokenVersion(userID string) (int, error) {
+	s.tokenMutex.RLock()
+	version, cached := s.tokenVersions[userID]
+	s.tokenMutex.RUnlock()
+	if cached {
+		return version, nil
+	}
+
+	foundUser, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		return 0, fmt.Errorf("error finding user %s: %w", userID, err)
+	}
+	if foundUser == nil {
+		return 0, fmt.Errorf("%w: %s", ErrUserNotFound, userID)
+	}
+	return s.rememberTokenVersion(userID, foundUser.TokenVersion()), nil
+}
+
+// rememberTokenVersion guarda la versión leída de la BD sin retroceder nunca la ya conocida, y retorna la vigente
+func (s *AuthService) rememberTokenVersion(userID string, version int) int {
+	s.tokenMutex.Lock()
+	defer s.tokenMutex.Unlock()
+
+	if cached, exists := s.tokenVersions[userID]; exists && cached > version {
+		return cached
+	}
+	s.tokenVersions[userID] = version
+	return version
+}
+
+// RevokeUserTokens invalida todos los tokens emitidos hasta ahora al usuario: ValidateToken los rechaza con
+// ErrTokenRevoked y el usuario tiene que volver a autenticarse. La nueva versión se guarda en users.token_version,
+// así la revocación sobrevive a un reinicio del servidor.
+func (s *AuthService) RevokeUserTokens(userID string) (*user.User, error) {
+	foundUser, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding user %s: %w", userID, err)
+	}
+	if foundUser == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, userID)
+	}
+
+	version, err := s.userRepository.IncrementTokenVersion(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error revoking tokens of user %s: %w", userID, err)
+	}
+	foundUser.RestoreTokenVersion(s.rememberTokenVersion(userID, version))
+	return foundUser, nil
+}
@@ -0,0 +1,57 @@
+package videoservice
+
+import (
+	"sort"
+	"time"
+)
+
+// ActiveRecording grabación en curso de una sesión
+type ActiveRecording struct {
+	VideoID   string    `json:"video_id"`
+	SessionID string    `json:"session_id"`
+	StartedAt time.Time `json:"started_at"`
+	Frames    int       `json:"frames"`
+}
+
+// IsSessionRecording indica si la sesión tiene alguna grabación en curso. Una grabación cerrada
+// automáticamente por el límite de frames, liberada al terminar la sesión o sin frames recientes ya no cuenta
+// aunque el cliente no la haya finalizado.
+func (vs *videoService) IsSessionRecording(sessionID string) bool {
+	vs.recordingsMutex.Lock()
+	defer vs.recordingsMutex.Unlock()
+
+	now := time.Now()
+	for _, progress := range vs.recordings {
+		if progress.sessionID == sessionID && vs.inProgress(progress, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListActiveRecordings lista las grabaciones en curso, de la más antigua a la más reciente
+func (vs *videoService) ListActiveRecordings() []ActiveRecording {
+	vs.recordingsMutex.Lock()
+	now := time.Now()
+	active := make([]ActiveRecording, 0, len(vs.recordings))
+	for videoID, progress := range vs.recordings {
+		if !vs.inProgress(progress, now) {
+			continue
+		}
+		active = append(active, ActiveRecording{
+			VideoID:   videoID,
+			SessionID: progress.sessionID,
+			StartedAt: progress.startedAt,
+			Frames:    progress.frames,
+		})
+	}
+	vs.recordingsMutex.Unlock()
+
+	sort.Slice(active, func(i, j int) bool {
+		if active[i].StartedAt.Equal(active[j].StartedAt) {
+			return active[i].VideoID < active[j].VideoID
+		}
+		return active[i].StartedAt.Before(active[j].StartedAt)
+	})
+	return active
+}
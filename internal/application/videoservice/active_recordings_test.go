@@ -0,0 +1,62 @@
+package videoservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActiveRecordings_StartAndFinalizeToggleRecordingState(t *testing.T) {
+	// Arrange
+	service, videoRepo, actionLog := newLimitedVideoService(t, 10)
+	videoRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	actionLog.On("LogAction", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	require.False(t, service.IsSessionRecording(testSessionID))
+
+	// Act - el primer frame inicia la grabación
+	before := time.Now()
+	require.NoError(t, service.SaveVideoFrame(testFrameInfo(1)))
+	require.NoError(t, service.SaveVideoFrame(testFrameInfo(2)))
+
+	// Assert
+	assert.True(t, service.IsSessionRecording(testSessionID))
+	assert.False(t, service.IsSessionRecording("other-session"))
+	active := service.ListActiveRecordings()
+	require.Len(t, active, 1)
+	assert.Equal(t, testVideoID, active[0].VideoID)
+	assert.Equal(t, testSessionID, active[0].SessionID)
+	assert.Equal(t, 2, active[0].Frames)
+	assert.False(t, active[0].StartedAt.Before(before))
+
+	// Act - el cliente finaliza la grabación
+	require.NoError(t, service.FinalizeVideoRecording(VideoRecordingMetadata{
+		VideoID:     testVideoID,
+		SessionID:   testSessionID,
+		TotalFrames: 2,
+		CompletedAt: time.Now(),
+	}))
+
+	// Assert
+	assert.False(t, service.IsSessionRecording(testSessionID))
+	assert.Empty(t, service.ListActiveRecordings())
+}
+
+func TestActiveRecordings_RecordingClosedByFrameLimitIsNotActive(t *testing.T) {
+	// Arrange
+	service, videoRepo, actionLog := newLimitedVideoService(t, 1)
+	videoRepo.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+	actionLog.On("LogAction", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+	// Act
+	require.NoError(t, service.SaveVideoFrame(testFrameInfo(1)))
+	require.ErrorIs(t, service.SaveVideoFrame(testFrameInfo(2)), ErrFrameLimitReached)
+
+	// Assert
+	assert.False(t, service.IsSessionRecording(testSessionID))
+	assert.Empty(t, service.ListActiveRecordings())
+}
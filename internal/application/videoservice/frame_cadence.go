@@ -0,0 +1,166 @@
+package videoservice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// frameTimestampsFileName archivo con el timestamp de cada frame, junto a los frames de la grabación
+const frameTimestampsFileName = "frame_timestamps.json"
+
+const (
+	// variableRateTolerance un intervalo está fuera de cadencia si se aparta de la mediana más de este factor
+	variableRateTolerance = 1.5
+	// variableRateMaxIrregular fracción de intervalos fuera de cadencia a partir de la cual la tasa es variable
+	variableRateMaxIrregular = 0.1
+)
+
+// ErrFrameTimestampsNotFound la grabación no tiene timestamps por frame (grabaciones anteriores a guardarlos)
+var ErrFrameTimestampsNotFound = errors.New("frame timestamps not found")
+
+// FrameCadence cadencia medida con los timestamps de los frames de una grabación
+type FrameCadence struct {
+	// FPS tasa representativa: la inversa de la mediana de los intervalos entre frames, que no se ve afectada por
+	// pausas ni frames perdidos como la división frames/duración
+	FPS float64
+	// MedianInterval intervalo típico entre dos frames consecutivos
+	MedianInterval time.Duration
+	// VariableRate más del 10% de los intervalos se aparta de la mediana más de un 50%
+	VariableRate bool
+	// Intervals intervalos medidos (pares de frames consecutivos con timestamps crecientes)
+	Intervals int
+}
+
+// frameTimestampsFile contenido de frame_timestamps.json
+type frameTimestampsFile struct {
+	Frames []frameTimestampEntry `json:"frames"`
+}
+
+type frameTimestampEntry struct {
+	FrameIndex  int   `json:"frame_index"`
+	TimestampMs int64 `json:"timestamp_ms"`
+}
+
+// frameTimestamp instante de captura de un frame: el timestamp del cliente o, si no lo envía, la llegada al
+// servidor. Un desfase constante del reloj del cliente no altera los intervalos.
+func frameTimestamp(clientTimestamp int64, receivedAt time.Time) time.Time {
+	if clientTimestamp <= 0 {
+		return receivedAt
+	}
+	return clientTimestampToTime(clientTimestamp)
+}
+
+// measureFrameCadence calcula la cadencia con los timestamps ordenados por índice de frame. Los intervalos no
+// positivos (timestamps repetidos o desordenados) se ignoran; con menos de un intervalo no hay medida.
+func measureFrameCadence(timestamps map[int]time.Time) FrameCadence {
+	indices := make([]int, 0, len(timestamps))
+	for frameIndex := range timestamps {
+		indices = append(indices, frameIndex)
+	}
+	sort.Ints(indices)
+
+	intervals := make([]time.Duration, 0, len(indices))
+	for i := 1; i < len(indices); i++ {
+		interval := timestamps[indices[i]].Sub(timestamps[indices[i-1]])
+		if interval > 0 {
+			intervals = append(intervals, interval)
+		}
+	}
+	if len(intervals) == 0 {
+		return FrameCadence{}
+	}
+
+	sorted := append([]time.Duration(nil), intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+
+	irregular := 0
+	for _, interval := range intervals {
+		ratio := float64(interval) / float64(median)
+		if ratio > variableRateTolerance || ratio < 1/variableRateTolerance {
+			irregular++
+		}
+	}
+
+	return FrameCadence{
+		FPS:            float64(time.Second) / float64(median),
+		MedianInterval: median,
+		VariableRate:   float64(irregular)/float64(len(intervals)) > variableRateMaxIrregular,
+		Intervals:      len(intervals),
+	}
+}
+
+// writeFrameTimestamps guarda los timestamps de los frames junto a la grabación
+func writeFrameTimestamps(framesDir string, timestamps map[int]time.Time) error {
+	content := frameTimestampsFile{Frames: make([]frameTimestampEntry, 0, len(timestamps))}
+	for frameIndex, timestamp := range timestamps {
+		content.Frames = append(content.Frames, frameTimestampEntry{FrameIndex: frameIndex, TimestampMs: timestamp.UnixMilli()})
+	}
+	sort.Slice(content.Frames, func(i, j int) bool { return content.Frames[i].FrameIndex < content.Frames[j].FrameIndex })
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("error serializando timestamps de frames: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(framesDir, frameTimestampsFileName), data, 0644); err != nil {
+		return fmt.Errorf("error guardando timestamps de frames: %w", err)
+	}
+	return nil
+}
+
+// readFrameTimestamps lee los timestamps guardados con writeFrameTimestamps
+func readFrameTimestamps(framesDir string) (map[int]time.Time, error) {
+	data, err := os.ReadFile(filepath.Join(framesDir, frameTimestampsFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrFrameTimestampsNotFound, framesDir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo timestamps de frames: %w", err)
+	}
+
+	var content frameTimestampsFile
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("error interpretando timestamps de frames: %w", err)
+	}
+	timestamps := make(map[int]time.Time, len(content.Frames))
+	for _, entry := range content.Frames {
+		timestamps[entry.FrameIndex] = time.UnixMilli(entry.TimestampMs)
+	}
+	return timestamps, nil
+}
+
+// GetFrameCadence mide la cadencia de una grabación con los timestamps de sus frames; ErrFrameTimestampsNotFound
+// si la grabación se guardó sin ellos
+func (vs *videoService) GetFrameCadence(framesDir string) (*FrameCadence, error) {
+	timestamps, err := readFrameTimestamps(framesDir)
+	if err != nil {
+		return nil, err
+	}
+	cadence := measureFrameCadence(timestamps)
+	return &cadence, nil
+}
+
+// saveFrameTimestamps guarda con la grabación los instantes de captura de sus frames, con los que la API calcula los
+// FPS; sin timestamps (p. ej. tras un reinicio del servidor) la API sigue dividiendo frames entre duración
+func (vs *videoService) saveFrameTimestamps(recordingInfo VideoRecordingMetadata, framesDir string, timestamps map[int]time.Time) {
+	if len(timestamps) == 0 {
+		return
+	}
+	if err := writeFrameTimestamps(framesDir, timestamps); err != nil {
+		fmt.Printf("Warning: no se pudieron guardar los timestamps de la grabación %s: %v\n", recordingInfo.VideoID, err)
+		return
+	}
+
+	if cadence := measureFrameCadence(timestamps); cadence.VariableRate {
+		fmt.Printf("⚠️ Warning: grabación %s con tasa de frames variable (%.2f FPS medidos, %.2f FPS por duración)\n",
+			recordingInfo.VideoID, cadence.FPS, recordingInfo.FPS)
+	}
+}
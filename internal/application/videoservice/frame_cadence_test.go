@@ -0,0 +1,110 @@
+package videoservice
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// irregularCadence frames a 25 FPS con una pausa de 5 s a mitad y dos frames perdidos
+func irregularCadence(start time.Time) map[int]time.Time {
+	timestamps := make(map[int]time.Time)
+	capturedAt := start
+	for frameIndex := 1; frameIndex <= 100; frameIndex++ {
+		switch frameIndex {
+		case 51:
+			capturedAt = capturedAt.Add(5 * time.Second)
+		case 70, 71:
+			capturedAt = capturedAt.Add(40 * time.Millisecond)
+			continue
+		}
+		timestamps[frameIndex] = capturedAt
+		capturedAt = capturedAt.Add(40 * time.Millisecond)
+	}
+	return timestamps
+}
+
+func TestMeasureFrameCadence_IrregularCadenceUsesTypicalInterval(t *testing.T) {
+	// Arrange - 98 frames en ~9 s: la división daría ~11 FPS
+	timestamps := irregularCadence(time.Now())
+
+	// Act
+	cadence := measureFrameCadence(timestamps)
+
+	// Assert
+	assert.InDelta(t, 25.0, cadence.FPS, 0.01)
+	assert.Equal(t, 40*time.Millisecond, cadence.MedianInterval)
+	assert.Equal(t, 97, cadence.Intervals)
+	assert.False(t, cadence.VariableRate, "una pausa y dos frames perdidos no hacen variable la tasa")
+}
+
+func TestMeasureFrameCadence_FlagsVariableRate(t *testing.T) {
+	// Arrange - el cliente alterna entre 30 FPS y 10 FPS
+	timestamps := make(map[int]time.Time)
+	capturedAt := time.Now()
+	for frameIndex := 1; frameIndex <= 40; frameIndex++ {
+		timestamps[frameIndex] = capturedAt
+		if frameIndex%2 == 0 {
+			capturedAt = capturedAt.Add(100 * time.Millisecond)
+		} else {
+			capturedAt = capturedAt.Add(33 * time.Millisecond)
+		}
+	}
+
+	// Act
+	cadence := measureFrameCadence(timestamps)
+
+	// Assert
+	assert.True(t, cadence.VariableRate)
+	assert.Greater(t, cadence.FPS, 0.0)
+}
+
+func TestMeasureFrameCadence_WithoutIntervalsHasNoMeasurement(t *testing.T) {
+	// Arrange - un solo frame y frames con el mismo timestamp
+	now := time.Now()
+
+	// Act & Assert
+	assert.Equal(t, FrameCadence{}, measureFrameCadence(map[int]time.Time{1: now}))
+	assert.Equal(t, FrameCadence{}, measureFrameCadence(map[int]time.Time{1: now, 2: now, 3: now}))
+}
+
+func TestFinalizeVideoRecording_StoresFrameTimestampsForCadence(t *testing.T) {
+	// Arrange - el cliente envía sus timestamps con una cadencia irregular
+	service, videoRepo, actionLog := newLimitedVideoService(t, 1000)
+	timestamps := irregularCadence(time.Now().Add(-10 * time.Second))
+	for frameIndex, capturedAt := range timestamps {
+		frame := testFrameInfo(frameIndex)
+		frame.Timestamp = capturedAt.UnixMilli()
+		require.NoError(t, service.SaveVideoFrame(frame))
+	}
+
+	// Act
+	finalizeCapturingMetadata(t, service, videoRepo, actionLog, VideoRecordingMetadata{
+		VideoID:         testVideoID,
+		SessionID:       testSessionID,
+		TotalFrames:     len(timestamps),
+		FPS:             11,
+		DurationSeconds: 9,
+		CompletedAt:     time.Now(),
+	})
+	cadence, err := service.GetFrameCadence(filepath.Join(service.framesBaseDir, testVideoID, "frames"))
+
+	// Assert
+	require.NoError(t, err)
+	assert.InDelta(t, 25.0, cadence.FPS, 0.01)
+	assert.False(t, cadence.VariableRate)
+}
+
+func TestGetFrameCadence_RecordingWithoutTimestamps(t *testing.T) {
+	// Arrange
+	service, _, _ := newLimitedVideoService(t, 100)
+
+	// Act
+	_, err := service.GetFrameCadence(t.TempDir())
+
+	// Assert
+	assert.ErrorIs(t, err, ErrFrameTimestampsNotFound)
+}
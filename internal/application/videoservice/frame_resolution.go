@@ -0,0 +1,158 @@
+package videoservice
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"strings"
+)
+
+// OversizeFrameMode indica qué se hace con un frame que supera la resolución máxima
+type OversizeFrameMode string
+
+const (
+	// OversizeFrameDownscale reduce el frame manteniendo la proporción hasta que quepa en el límite
+	OversizeFrameDownscale OversizeFrameMode = "downscale"
+	// OversizeFrameReject descarta el frame
+	OversizeFrameReject OversizeFrameMode = "reject"
+)
+
+// downscaleJPEGQuality calidad con la que se recodifican los frames JPEG reducidos
+const downscaleJPEGQuality = 85
+
+// MaxDecodableFramePixels píxeles (8K) por encima de los cuales un frame se rechaza sin decodificarlo, aunque la
+// política sea downscale: la cabecera la controla el cliente y decodificar una resolución arbitraria agota la memoria
+const MaxDecodableFramePixels = 7680 * 4320
+
+// ErrFrameTooLarge indica que el frame supera la resolución máxima y la política es rechazarlo
+var ErrFrameTooLarge = errors.New("frame exceeds maximum resolution")
+
+// FrameResolutionLimit resolución máxima de los frames de streaming y grabación.
+// Un ancho o alto de 0 deja esa dimensión sin límite.
+type FrameResolutionLimit struct {
+	MaxWidth  int
+	MaxHeight int
+	Mode      OversizeFrameMode
+}
+
+// ParseOversizeFrameMode interpreta "downscale" o "reject"
+func ParseOversizeFrameMode(value string) (OversizeFrameMode, error) {
+	switch mode := OversizeFrameMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case OversizeFrameDownscale, OversizeFrameReject:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("política de frames sobredimensionados desconocida: %q", value)
+	}
+}
+
+// Enabled indica si hay alguna dimensión limitada
+func (l FrameResolutionLimit) Enabled() bool {
+	return l.MaxWidth > 0 || l.MaxHeight > 0
+}
+
+// Exceeds indica si una resolución supera el límite
+func (l FrameResolutionLimit) Exceeds(width, height int) bool {
+	return (l.MaxWidth > 0 && width > l.MaxWidth) || (l.MaxHeight > 0 && height > l.MaxHeight)
+}
+
+// Enforce aplica el límite a un frame JPEG o PNG y retorna los bytes resultantes con su resolución real.
+// Los frames dentro del límite se devuelven sin cambios; los que lo superan se reducen o se rechazan
+// con ErrFrameTooLarge según Mode. La resolución se lee de la cabecera antes de decodificar nada, y los frames de
+// más de MaxDecodableFramePixels se rechazan siempre.
+func (l FrameResolutionLimit) Enforce(frameData []byte) ([]byte, int, int, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(frameData))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("error reading frame resolution: %w", err)
+	}
+
+	if !l.Exceeds(config.Width, config.Height) {
+		return frameData, config.Width, config.Height, nil
+	}
+	if l.Mode == OversizeFrameReject {
+		return nil, config.Width, config.Height, fmt.Errorf("%w: %dx%d (max %dx%d)",
+			ErrFrameTooLarge, config.Width, config.Height, l.MaxWidth, l.MaxHeight)
+	}
+	if int64(config.Width)*int64(config.Height) > MaxDecodableFramePixels {
+		return nil, config.Width, config.Height, fmt.Errorf("%w: %dx%d cannot be decoded for downscaling (max %d pixels)",
+			ErrFrameTooLarge, config.Width, config.Height, MaxDecodableFramePixels)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(frameData))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("error decoding frame: %w", err)
+	}
+
+	width, height := l.fit(config.Width, config.Height)
+	scaled := downscaleBox(src, width, height)
+
+	var out bytes.Buffer
+	if format == "png" {
+		err = png.Encode(&out, scaled)
+	} else {
+		err = jpeg.Encode(&out, scaled, &jpeg.Options{Quality: downscaleJPEGQuality})
+	}
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("error encoding downscaled frame: %w", err)
+	}
+
+	return out.Bytes(), width, height, nil
+}
+
+// fit calcula la mayor resolución con la misma proporción que cabe en el límite
+func (l FrameResolutionLimit) fit(width, height int) (int, int) {
+	scale := 1.0
+	if l.MaxWidth > 0 && width > l.MaxWidth {
+		scale = float64(l.MaxWidth) / float64(width)
+	}
+	if l.MaxHeight > 0 && float64(height)*scale > float64(l.MaxHeight) {
+		scale = float64(l.MaxHeight) / float64(height)
+	}
+
+	scaledWidth := max(1, int(float64(width)*scale))
+	scaledHeight := max(1, int(float64(height)*scale))
+	return scaledWidth, scaledHeight
+}
+
+// downscaleBox reduce la imagen promediando los píxeles de origen que cubre cada píxel de destino
+func downscaleBox(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			var r, g, b, a, count int
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += int(p[0])
+					g += int(p[1])
+					b += int(p[2])
+					a += int(p[3])
+					count++
+				}
+			}
+
+			d := dst.Pix[y*dst.Stride+x*4:]
+			d[0] = uint8(r / count)
+			d[1] = uint8(g / count)
+			d[2] = uint8(b / count)
+			d[3] = uint8(a / count)
+		}
+	}
+
+	return dst
+}
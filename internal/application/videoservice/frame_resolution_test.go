@@ -0,0 +1,115 @@
+package videoservice
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testJPEG genera un JPEG de la resolución indicada con un color uniforme
+func testJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 40, B: 40, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	return buf.Bytes()
+}
+
+func TestFrameResolutionLimit_DownscalesOversizeFrameKeepingAspectRatio(t *testing.T) {
+	// Arrange
+	limit := FrameResolutionLimit{MaxWidth: 640, MaxHeight: 480, Mode: OversizeFrameDownscale}
+	frame := testJPEG(t, 1280, 720)
+
+	// Act
+	data, width, height, err := limit.Enforce(frame)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 640, width)
+	assert.Equal(t, 360, height)
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, 640, config.Width)
+	assert.Equal(t, 360, config.Height)
+}
+
+func TestFrameResolutionLimit_FrameWithinLimitPassesThroughUnchanged(t *testing.T) {
+	// Arrange
+	limit := FrameResolutionLimit{MaxWidth: 1920, MaxHeight: 1080, Mode: OversizeFrameDownscale}
+	frame := testJPEG(t, 800, 600)
+
+	// Act
+	data, width, height, err := limit.Enforce(frame)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, frame, data)
+	assert.Equal(t, 800, width)
+	assert.Equal(t, 600, height)
+}
+
+func TestFrameResolutionLimit_RejectModeRejectsOversizeFrame(t *testing.T) {
+	// Arrange - solo se limita el alto
+	limit := FrameResolutionLimit{MaxHeight: 480, Mode: OversizeFrameReject}
+
+	// Act
+	data, _, _, err := limit.Enforce(testJPEG(t, 320, 640))
+
+	// Assert
+	assert.Nil(t, data)
+	assert.True(t, errors.Is(err, ErrFrameTooLarge))
+}
+
+func TestFrameResolutionLimit_HugeHeaderIsRejectedWithoutDecoding(t *testing.T) {
+	// Arrange - JPEG pequeño cuya cabecera SOF0 declara 60000x60000
+	limit := FrameResolutionLimit{MaxWidth: 640, Mode: OversizeFrameDownscale}
+	frame := testJPEG(t, 16, 16)
+	sof := bytes.Index(frame, []byte{0xFF, 0xC0})
+	require.Greater(t, sof, 0)
+	frame[sof+5], frame[sof+6] = 0xEA, 0x60
+	frame[sof+7], frame[sof+8] = 0xEA, 0x60
+
+	// Act
+	data, width, height, err := limit.Enforce(frame)
+
+	// Assert
+	assert.Nil(t, data)
+	assert.True(t, errors.Is(err, ErrFrameTooLarge))
+	assert.Equal(t, 60000, width)
+	assert.Equal(t, 60000, height)
+}
+
+func TestFrameResolutionLimit_UndecodableFrameFails(t *testing.T) {
+	// Arrange
+	limit := FrameResolutionLimit{MaxWidth: 640, Mode: OversizeFrameDownscale}
+
+	// Act
+	_, _, _, err := limit.Enforce([]byte("not an image"))
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestParseOversizeFrameMode(t *testing.T) {
+	mode, err := ParseOversizeFrameMode(" Reject ")
+	assert.NoError(t, err)
+	assert.Equal(t, OversizeFrameReject, mode)
+
+	_, err = ParseOversizeFrameMode("crop")
+	assert.Error(t, err)
+}
@@ -0,0 +1,325 @@
+package videoservice
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	// SpriteIndexFileName índice con la hoja y el rectángulo de cada frame dentro de ella
+	SpriteIndexFileName = "sprites.idx"
+	// SpriteSheetsDirName subdirectorio de las hojas; fuera de él solo se cuentan frames individuales
+	SpriteSheetsDirName = "sprites"
+
+	// DefaultFramesPerSprite frames por hoja cuando la grabación no informa sus FPS
+	DefaultFramesPerSprite = 30
+
+	// Cada entrada del índice: frameIndex, hoja, x, y, ancho y alto como int64 little-endian
+	spriteIndexEntrySize = 48
+	// spriteJPEGQuality calidad de las hojas y de los frames extraídos de ellas
+	spriteJPEGQuality = 90
+	// spriteTileAlign los frames empiezan en múltiplos de 16 px para que los bloques JPEG de un frame
+	// no mezclen píxeles del vecino
+	spriteTileAlign = 16
+
+	// DefaultMaxSpriteSheetPixels tamaño máximo de una hoja (4096×4096 px, 64 MiB en RGBA mientras se compone);
+	// si los frames de un segundo no caben, el segundo se reparte en varias hojas
+	DefaultMaxSpriteSheetPixels = 4096 * 4096
+	// spriteSheetCacheSize hojas decodificadas que se conservan para servir los frames vecinos sin volver a decodificar
+	spriteSheetCacheSize = 2
+)
+
+// ErrSpriteFrameTooLarge un frame no cabe en una hoja de DefaultMaxSpriteSheetPixels; la grabación no se compacta
+var ErrSpriteFrameTooLarge = errors.New("frame demasiado grande para una hoja de sprites")
+
+// spriteIndexEntry posición de un frame en su hoja
+type spriteIndexEntry struct {
+	sheet int
+	rect  image.Rectangle
+}
+
+// CompactRecording agrupa los frames de una grabación finalizada en hojas (sprite sheets) de un segundo,
+// framesPerSecond frames por hoja, con un índice de rectángulos. Solo actúa en modo FrameStorageSprites.
+// Una hoja nunca supera maxSheetPixels: con frames grandes un segundo ocupa varias hojas.
+// Las hojas y el índice se escriben antes de borrar los frames originales, así que un fallo a mitad deja la
+// grabación intacta en su formato original.
+func (fs *frameStore) CompactRecording(framesDir string, framesPerSecond float64) error {
+	if fs.format != FrameStorageSprites || isSpriteRecording(framesDir) {
+		return nil
+	}
+
+	frameIndexes, err := fs.frameIndexes(framesDir)
+	if err != nil {
+		return err
+	}
+	if len(frameIndexes) == 0 {
+		return nil
+	}
+
+	perSheet := int(math.Round(framesPerSecond))
+	if perSheet < 1 {
+		perSheet = DefaultFramesPerSprite
+	}
+
+	if err := os.MkdirAll(filepath.Join(framesDir, SpriteSheetsDirName), 0755); err != nil {
+		return fmt.Errorf("error creando directorio de hojas: %w", err)
+	}
+
+	var index []byte
+	for sheet, start := 0, 0; start < len(frameIndexes); sheet++ {
+		end := start + perSheet
+		if end > len(frameIndexes) {
+			end = len(frameIndexes)
+		}
+		written, entries, err := fs.writeSpriteSheet(framesDir, sheet, frameIndexes[start:end])
+		if err != nil {
+			return err
+		}
+		index = append(index, entries...)
+		start += written
+	}
+
+	// El índice se publica de una vez: a partir de aquí las lecturas usan las hojas
+	tmpIndex := filepath.Join(framesDir, SpriteIndexFileName+".tmp")
+	if err := os.WriteFile(tmpIndex, index, 0644); err != nil {
+		return fmt.Errorf("error escribiendo índice de hojas: %w", err)
+	}
+	if err := os.Rename(tmpIndex, filepath.Join(framesDir, SpriteIndexFileName)); err != nil {
+		return fmt.Errorf("error publicando índice de hojas: %w", err)
+	}
+
+	return removeOriginalFrames(framesDir, frameIndexes)
+}
+
+// writeSpriteSheet compone en una cuadrícula los primeros frames que caben en la hoja, la guarda y retorna
+// cuántos frames usó y sus entradas del índice. Las dimensiones se leen de las cabeceras JPEG antes de decodificar,
+// así la hoja se acota sin tener todos los frames en memoria.
+func (fs *frameStore) writeSpriteSheet(framesDir string, sheet int, frameIndexes []int) (int, []byte, error) {
+	maxPixels := fs.maxSheetPixels
+	if maxPixels <= 0 {
+		maxPixels = DefaultMaxSpriteSheetPixels
+	}
+
+	var frameData [][]byte
+	tileWidth, tileHeight := 0, 0
+	for _, frameIndex := range frameIndexes {
+		data, err := fs.ReadFrame(framesDir, frameIndex)
+		if err != nil {
+			return 0, nil, err
+		}
+		config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return 0, nil, fmt.Errorf("error decodificando frame %d: %w", frameIndex, err)
+		}
+		width, height := max(tileWidth, alignUp(config.Width, spriteTileAlign)), max(tileHeight, alignUp(config.Height, spriteTileAlign))
+		if spriteSheetPixels(len(frameData)+1, width, height) > maxPixels {
+			if len(frameData) == 0 {
+				return 0, nil, fmt.Errorf("%w: frame %d de %dx%d", ErrSpriteFrameTooLarge, frameIndex, config.Width, config.Height)
+			}
+			break
+		}
+		frameData = append(frameData, data)
+		tileWidth, tileHeight = width, height
+	}
+
+	columns, rows := spriteSheetGrid(len(frameData))
+	canvas := image.NewRGBA(image.Rect(0, 0, columns*tileWidth, rows*tileHeight))
+
+	entries := make([]byte, 0, len(frameData)*spriteIndexEntrySize)
+	for i, data := range frameData {
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return 0, nil, fmt.Errorf("error decodificando frame %d: %w", frameIndexes[i], err)
+		}
+		origin := image.Pt((i%columns)*tileWidth, (i/columns)*tileHeight)
+		rect := image.Rectangle{Min: origin, Max: origin.Add(img.Bounds().Size())}
+		draw.Draw(canvas, rect, img, img.Bounds().Min, draw.Src)
+		entries = append(entries, encodeSpriteIndexEntry(frameIndexes[i], sheet, rect)...)
+	}
+
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, canvas, &jpeg.Options{Quality: spriteJPEGQuality}); err != nil {
+		return 0, nil, fmt.Errorf("error codificando hoja %d: %w", sheet, err)
+	}
+	if err := os.WriteFile(spriteSheetPath(framesDir, sheet), encoded.Bytes(), 0644); err != nil {
+		return 0, nil, fmt.Errorf("error escribiendo hoja %d: %w", sheet, err)
+	}
+	return len(frameData), entries, nil
+}
+
+// spriteSheetGrid columnas y filas de la cuadrícula casi cuadrada de una hoja con frames frames
+func spriteSheetGrid(frames int) (int, int) {
+	columns := int(math.Ceil(math.Sqrt(float64(frames))))
+	return columns, (frames + columns - 1) / columns
+}
+
+// spriteSheetPixels píxeles de una hoja con frames frames de tileWidth×tileHeight
+func spriteSheetPixels(frames, tileWidth, tileHeight int) int {
+	columns, rows := spriteSheetGrid(frames)
+	return columns * tileWidth * rows * tileHeight
+}
+
+// readSpriteFrame recorta un frame de su hoja y lo retorna como JPEG. La reproducción lee frames consecutivos
+// de la misma hoja, así que las últimas hojas decodificadas se reutilizan.
+func (fs *frameStore) readSpriteFrame(framesDir string, frameIndex int) ([]byte, error) {
+	entries, err := readSpriteIndex(framesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, exists := entries[frameIndex]
+	if !exists {
+		return nil, ErrFrameNotFound
+	}
+
+	sheet, err := fs.decodedSpriteSheet(spriteSheetPath(framesDir, entry.sheet), entry.sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	frame := image.NewRGBA(image.Rect(0, 0, entry.rect.Dx(), entry.rect.Dy()))
+	draw.Draw(frame, frame.Bounds(), sheet, entry.rect.Min, draw.Src)
+
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, frame, &jpeg.Options{Quality: spriteJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("error codificando frame %d: %w", frameIndex, err)
+	}
+	return encoded.Bytes(), nil
+}
+
+// decodedSpriteSheet retorna la hoja decodificada, de la caché o leyéndola del disco. Las hojas no cambian una vez
+// escritas, así que la ruta basta como clave.
+func (fs *frameStore) decodedSpriteSheet(sheetPath string, sheet int) (image.Image, error) {
+	fs.sheetMutex.Lock()
+	cached, exists := fs.sheetCache[sheetPath]
+	fs.sheetMutex.Unlock()
+	if exists {
+		return cached, nil
+	}
+
+	sheetData, err := os.ReadFile(sheetPath)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo hoja %d: %w", sheet, err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(sheetData))
+	if err != nil {
+		return nil, fmt.Errorf("error decodificando hoja %d: %w", sheet, err)
+	}
+
+	fs.sheetMutex.Lock()
+	defer fs.sheetMutex.Unlock()
+	if fs.sheetCache == nil {
+		fs.sheetCache = make(map[string]image.Image)
+	}
+	if _, exists := fs.sheetCache[sheetPath]; !exists {
+		fs.sheetOrder = append(fs.sheetOrder, sheetPath)
+	}
+	fs.sheetCache[sheetPath] = img
+	for len(fs.sheetOrder) > spriteSheetCacheSize {
+		delete(fs.sheetCache, fs.sheetOrder[0])
+		fs.sheetOrder = fs.sheetOrder[1:]
+	}
+	return img, nil
+}
+
+// readSpriteIndex carga el índice de hojas
+func readSpriteIndex(framesDir string) (map[int]spriteIndexEntry, error) {
+	raw, err := os.ReadFile(filepath.Join(framesDir, SpriteIndexFileName))
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo índice de hojas: %w", err)
+	}
+
+	entries := make(map[int]spriteIndexEntry, len(raw)/spriteIndexEntrySize)
+	for pos := 0; pos+spriteIndexEntrySize <= len(raw); pos += spriteIndexEntrySize {
+		field := func(i int) int {
+			return int(binary.LittleEndian.Uint64(raw[pos+i*8 : pos+(i+1)*8]))
+		}
+		x, y := field(2), field(3)
+		entries[field(0)] = spriteIndexEntry{
+			sheet: field(1),
+			rect:  image.Rect(x, y, x+field(4), y+field(5)),
+		}
+	}
+	return entries, nil
+}
+
+func encodeSpriteIndexEntry(frameIndex, sheet int, rect image.Rectangle) []byte {
+	entry := make([]byte, spriteIndexEntrySize)
+	for i, value := range []int{frameIndex, sheet, rect.Min.X, rect.Min.Y, rect.Dx(), rect.Dy()} {
+		binary.LittleEndian.PutUint64(entry[i*8:(i+1)*8], uint64(value))
+	}
+	return entry
+}
+
+// frameIndexes índices de los frames de la grabación en orden, en formato individual o empaquetado
+func (fs *frameStore) frameIndexes(framesDir string) ([]int, error) {
+	var indexes []int
+	if isPackedRecording(framesDir) {
+		entries, err := fs.readPackedIndex(framesDir)
+		if err != nil {
+			return nil, err
+		}
+		for frameIndex := range entries {
+			indexes = append(indexes, frameIndex)
+		}
+	} else {
+		files, err := os.ReadDir(framesDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			var frameIndex int
+			if !file.IsDir() && filepath.Ext(file.Name()) == ".jpg" {
+				if _, err := fmt.Sscanf(file.Name(), "frame_%06d.jpg", &frameIndex); err == nil {
+					indexes = append(indexes, frameIndex)
+				}
+			}
+		}
+	}
+
+	sort.Ints(indexes)
+	return indexes, nil
+}
+
+// removeOriginalFrames borra los frames ya copiados a las hojas
+func removeOriginalFrames(framesDir string, frameIndexes []int) error {
+	if isPackedRecording(framesDir) {
+		for _, name := range []string{PackedFramesFileName, PackedIndexFileName} {
+			if err := os.Remove(filepath.Join(framesDir, name)); err != nil {
+				return fmt.Errorf("error borrando %s: %w", name, err)
+			}
+		}
+		return nil
+	}
+
+	for _, frameIndex := range frameIndexes {
+		if err := os.Remove(filepath.Join(framesDir, individualFrameFileName(frameIndex))); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error borrando frame %d: %w", frameIndex, err)
+		}
+	}
+	return nil
+}
+
+// isSpriteRecording indica si el directorio contiene una grabación compactada en hojas
+func isSpriteRecording(framesDir string) bool {
+	_, err := os.Stat(filepath.Join(framesDir, SpriteIndexFileName))
+	return err == nil
+}
+
+// spriteSheetPath ruta de la hoja de un segundo: sprites/sheet_000000.jpg, ...
+func spriteSheetPath(framesDir string, sheet int) string {
+	return filepath.Join(framesDir, SpriteSheetsDirName, fmt.Sprintf("sheet_%06d.jpg", sheet))
+}
+
+func alignUp(value, align int) int {
+	return (value + align - 1) / align * align
+}
@@ -0,0 +1,193 @@
+package videoservice
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGradientJPEG genera un JPEG con un degradado distinto por frame para detectar recortes desplazados
+func testGradientJPEG(t *testing.T, width, height, seed int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 255 / width), G: uint8(y * 255 / height), B: uint8(seed * 40), A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}))
+	return buf.Bytes()
+}
+
+// meanPixelDifference diferencia media por canal (0-255) entre dos JPEG de la misma resolución
+func meanPixelDifference(t *testing.T, a, b []byte) float64 {
+	t.Helper()
+
+	imgA, err := jpeg.Decode(bytes.NewReader(a))
+	require.NoError(t, err)
+	imgB, err := jpeg.Decode(bytes.NewReader(b))
+	require.NoError(t, err)
+	require.Equal(t, imgA.Bounds().Size(), imgB.Bounds().Size())
+
+	var total, samples float64
+	bounds := imgA.Bounds()
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			r1, g1, b1, _ := imgA.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			r2, g2, b2, _ := imgB.At(imgB.Bounds().Min.X+x, imgB.Bounds().Min.Y+y).RGBA()
+			for _, diff := range []int{int(r1>>8) - int(r2>>8), int(g1>>8) - int(g2>>8), int(b1>>8) - int(b2>>8)} {
+				if diff < 0 {
+					diff = -diff
+				}
+				total += float64(diff)
+				samples++
+			}
+		}
+	}
+	return total / samples
+}
+
+func TestFrameStore_SpriteFrameMatchesOriginalFrame(t *testing.T) {
+	// Arrange - 5 frames a 2 FPS: tres hojas (2 + 2 + 1), con un frame de otra resolución
+	framesDir := t.TempDir()
+	store := NewFrameStore(FrameStorageSprites)
+	frames := make([][]byte, 5)
+	for i := range frames {
+		width, height := 64, 48
+		if i == 3 {
+			width, height = 40, 30
+		}
+		frames[i] = testGradientJPEG(t, width, height, i)
+		require.NoError(t, store.WriteFrame(framesDir, i+1, frames[i]))
+	}
+
+	// Act
+	require.NoError(t, store.CompactRecording(framesDir, 2))
+
+	// Assert - cada frame extraído coincide con el original salvo la pérdida de recodificar el JPEG
+	for i, original := range frames {
+		extracted, err := store.ReadFrame(framesDir, i+1)
+		require.NoError(t, err)
+		assert.Less(t, meanPixelDifference(t, original, extracted), 3.0, "frame %d", i+1)
+	}
+
+	count, err := store.CountFrames(framesDir)
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	_, err = os.Stat(filepath.Join(framesDir, "frame_000001.jpg"))
+	assert.True(t, os.IsNotExist(err))
+	sheets, err := os.ReadDir(filepath.Join(framesDir, SpriteSheetsDirName))
+	require.NoError(t, err)
+	assert.Len(t, sheets, 3)
+
+	_, err = store.ReadFrame(framesDir, 99)
+	assert.ErrorIs(t, err, ErrFrameNotFound)
+}
+
+func TestFrameStore_CompactRecordingKeepsOriginalsWhenFramesCannotBeDecoded(t *testing.T) {
+	// Arrange
+	framesDir := t.TempDir()
+	store := NewFrameStore(FrameStorageSprites)
+	frames := testFrames(3)
+	for i, data := range frames {
+		require.NoError(t, store.WriteFrame(framesDir, i+1, data))
+	}
+
+	// Act
+	err := store.CompactRecording(framesDir, 30)
+
+	// Assert
+	assert.Error(t, err)
+	data, readErr := store.ReadFrame(framesDir, 2)
+	require.NoError(t, readErr)
+	assert.Equal(t, frames[1], data)
+}
+
+func TestFrameStore_CompactRecordingOnlyInSpritesMode(t *testing.T) {
+	// Arrange
+	framesDir := t.TempDir()
+	store := NewFrameStore(FrameStorageIndividual)
+	original := testGradientJPEG(t, 32, 32, 1)
+	require.NoError(t, store.WriteFrame(framesDir, 1, original))
+
+	// Act
+	require.NoError(t, store.CompactRecording(framesDir, 30))
+
+	// Assert
+	data, err := store.ReadFrame(framesDir, 1)
+	require.NoError(t, err)
+	assert.Equal(t, original, data)
+	assert.False(t, isSpriteRecording(framesDir))
+}
+
+func TestFrameStore_CompactRecordingSplitsSecondsThatDoNotFitInOneSheet(t *testing.T) {
+	// Arrange - 4 frames a 4 FPS con hojas de como mucho dos frames de 64x48
+	framesDir := t.TempDir()
+	store := &frameStore{format: FrameStorageSprites, maxSheetPixels: 2 * 64 * 48}
+	frames := make([][]byte, 4)
+	for i := range frames {
+		frames[i] = testGradientJPEG(t, 64, 48, i)
+		require.NoError(t, store.WriteFrame(framesDir, i+1, frames[i]))
+	}
+
+	// Act
+	require.NoError(t, store.CompactRecording(framesDir, 4))
+
+	// Assert
+	sheets, err := os.ReadDir(filepath.Join(framesDir, SpriteSheetsDirName))
+	require.NoError(t, err)
+	assert.Len(t, sheets, 2)
+	for i, original := range frames {
+		extracted, err := store.ReadFrame(framesDir, i+1)
+		require.NoError(t, err)
+		assert.Less(t, meanPixelDifference(t, original, extracted), 3.0, "frame %d", i+1)
+	}
+}
+
+func TestFrameStore_CompactRecordingKeepsOriginalsWhenFrameExceedsSheetLimit(t *testing.T) {
+	// Arrange
+	framesDir := t.TempDir()
+	store := &frameStore{format: FrameStorageSprites, maxSheetPixels: 32 * 32}
+	original := testGradientJPEG(t, 64, 48, 1)
+	require.NoError(t, store.WriteFrame(framesDir, 1, original))
+
+	// Act
+	err := store.CompactRecording(framesDir, 30)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrSpriteFrameTooLarge)
+	assert.False(t, isSpriteRecording(framesDir))
+	data, err := store.ReadFrame(framesDir, 1)
+	require.NoError(t, err)
+	assert.Equal(t, original, data)
+}
+
+func TestFrameStore_SpriteFramesReuseDecodedSheet(t *testing.T) {
+	// Arrange
+	framesDir := t.TempDir()
+	store := NewFrameStore(FrameStorageSprites)
+	for i := 1; i <= 2; i++ {
+		require.NoError(t, store.WriteFrame(framesDir, i, testGradientJPEG(t, 32, 32, i)))
+	}
+	require.NoError(t, store.CompactRecording(framesDir, 2))
+	_, err := store.ReadFrame(framesDir, 1)
+	require.NoError(t, err)
+
+	// Act - la hoja ya decodificada sirve el frame vecino sin volver a leer el disco
+	require.NoError(t, os.Remove(spriteSheetPath(framesDir, 0)))
+	_, err = store.ReadFrame(framesDir, 2)
+
+	// Assert
+	assert.NoError(t, err)
+}
@@ -0,0 +1,338 @@
+package videoservice
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+// FrameStorageFormat define cómo se guardan en disco los frames de una grabación
+type FrameStorageFormat string
+
+const (
+	// FrameStorageIndividual guarda cada frame como un archivo frame_%06d.jpg
+	FrameStorageIndividual FrameStorageFormat = "individual"
+	// FrameStoragePacked agrega los frames a un único contenedor por grabación con un índice offset/longitud
+	FrameStoragePacked FrameStorageFormat = "packed"
+	// FrameStorageSprites guarda frames individuales durante la grabación y al finalizarla los agrupa en
+	// una hoja (sprite sheet) por segundo con un índice de rectángulos
+	FrameStorageSprites FrameStorageFormat = "sprites"
+)
+
+const (
+	// PackedFramesFileName contenedor con los bytes JPEG concatenados
+	PackedFramesFileName = "frames.pack"
+	// PackedIndexFileName índice con una entrada de tamaño fijo por frame
+	PackedIndexFileName = "frames.idx"
+
+	// Cada entrada del índice: frameIndex, offset y longitud como int64 little-endian
+	packedIndexEntrySize = 24
+)
+
+// ErrFrameNotFound se retorna cuando el frame solicitado no existe en la grabación
+var ErrFrameNotFound = errors.New("frame no encontrado")
+
+// ParseFrameStorageFormat interpreta el valor de configuración; valores vacíos o desconocidos usan archivos individuales
+func ParseFrameStorageFormat(value string) FrameStorageFormat {
+	switch format := FrameStorageFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case FrameStoragePacked, FrameStorageSprites:
+		return format
+	}
+	return FrameStorageIndividual
+}
+
+// IFrameStore abstrae el almacenamiento de frames de una grabación
+type IFrameStore interface {
+	// WriteFrame guarda un frame en el directorio de la grabación
+	WriteFrame(framesDir string, frameIndex int, data []byte) error
+	// ReadFrame lee un frame, independientemente del formato con el que se guardó la grabación
+	ReadFrame(framesDir string, frameIndex int) ([]byte, error)
+	// CountFrames cuenta los frames de la grabación, independientemente de su formato
+	CountFrames(framesDir string) (int, error)
+	// FrameIndexes índices de los frames de la grabación en orden, independientemente de su formato
+	FrameIndexes(framesDir string) ([]int, error)
+	// HasValidFrame indica si la grabación tiene al menos un frame con datos, independientemente de su formato
+	HasValidFrame(framesDir string) (bool, error)
+	// CompactRecording post-procesa una grabación finalizada según el formato (hojas por segundo en modo sprites)
+	CompactRecording(framesDir string, framesPerSecond float64) error
+	// PackedFrameIndex índice offset/longitud de una grabación empaquetada; nil en los demás formatos
+	PackedFrameIndex(framesDir string) ([]sessionvideo.FrameIndexEntry, error)
+}
+
+// frameStore escribe en el formato configurado y lee detectando el formato de cada grabación,
+// de modo que las grabaciones existentes sigan siendo accesibles si se cambia la configuración
+type frameStore struct {
+	format FrameStorageFormat
+	// packLocks serializa los appends al contenedor de cada grabación; grabaciones distintas escriben en paralelo
+	packLocks recordingLocks
+
+	// maxSheetPixels tamaño máximo de una hoja de sprites (cero = DefaultMaxSpriteSheetPixels)
+	maxSheetPixels int
+	// Últimas hojas de sprites decodificadas por ruta; la más antigua se descarta primero
+	sheetCache map[string]image.Image
+	sheetOrder []string
+	sheetMutex sync.Mutex
+}
+
+// NewFrameStore crea un almacén de frames que escribe en el formato indicado
+func NewFrameStore(format FrameStorageFormat) IFrameStore {
+	return &frameStore{format: format}
+}
+
+// WriteFrame guarda un frame en el formato configurado
+func (fs *frameStore) WriteFrame(framesDir string, frameIndex int, data []byte) error {
+	if fs.format == FrameStoragePacked {
+		return fs.appendPackedFrame(framesDir, frameIndex, data)
+	}
+
+	framePath := filepath.Join(framesDir, individualFrameFileName(frameIndex))
+	return os.WriteFile(framePath, data, 0644)
+}
+
+// ReadFrame lee un frame individual, empaquetado o recortado de su hoja
+func (fs *frameStore) ReadFrame(framesDir string, frameIndex int) ([]byte, error) {
+	if isSpriteRecording(framesDir) {
+		return fs.readSpriteFrame(framesDir, frameIndex)
+	}
+	if isPackedRecording(framesDir) {
+		return fs.readPackedFrame(framesDir, frameIndex)
+	}
+
+	data, err := os.ReadFile(filepath.Join(framesDir, individualFrameFileName(frameIndex)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			// La compactación en hojas pudo borrar el frame después de comprobar el formato
+			if isSpriteRecording(framesDir) {
+				return fs.readSpriteFrame(framesDir, frameIndex)
+			}
+			return nil, ErrFrameNotFound
+		}
+		return nil, fmt.Errorf("error leyendo frame %d: %w", frameIndex, err)
+	}
+	return data, nil
+}
+
+// CountFrames cuenta los frames de una grabación individual, empaquetada o en hojas
+func (fs *frameStore) CountFrames(framesDir string) (int, error) {
+	if isSpriteRecording(framesDir) {
+		entries, err := readSpriteIndex(framesDir)
+		if err != nil {
+			return 0, err
+		}
+		return len(entries), nil
+	}
+	if isPackedRecording(framesDir) {
+		entries, err := fs.readPackedIndex(framesDir)
+		if err != nil {
+			return 0, err
+		}
+		return len(entries), nil
+	}
+
+	entries, err := os.ReadDir(framesDir)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".jpg" {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// FrameIndexes índices de los frames de una grabación individual, empaquetada o en hojas, en orden
+func (fs *frameStore) FrameIndexes(framesDir string) ([]int, error) {
+	if !isSpriteRecording(framesDir) {
+		return fs.frameIndexes(framesDir)
+	}
+
+	entries, err := readSpriteIndex(framesDir)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]int, 0, len(entries))
+	for frameIndex := range entries {
+		indexes = append(indexes, frameIndex)
+	}
+	sort.Ints(indexes)
+	return indexes, nil
+}
+
+// HasValidFrame indica si la grabación tiene algún frame con datos: una entrada en el índice de hojas, una
+// entrada no vacía en el contenedor o un frame_*.jpg no vacío. Un cliente que se cae antes de enviar frames
+// puede dejar el directorio vacío o con archivos a medio escribir.
+func (fs *frameStore) HasValidFrame(framesDir string) (bool, error) {
+	if isSpriteRecording(framesDir) {
+		entries, err := readSpriteIndex(framesDir)
+		if err != nil {
+			return false, err
+		}
+		return len(entries) > 0, nil
+	}
+	if isPackedRecording(framesDir) {
+		entries, err := fs.readPackedIndex(framesDir)
+		if err != nil {
+			return false, err
+		}
+		for _, entry := range entries {
+			if entry.length > 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	entries, err := os.ReadDir(framesDir)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".jpg" {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && info.Size() > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PackedFrameIndex entradas del índice de una grabación empaquetada ordenadas por frame; nil si la grabación
+// no está empaquetada
+func (fs *frameStore) PackedFrameIndex(framesDir string) ([]sessionvideo.FrameIndexEntry, error) {
+	if !isPackedRecording(framesDir) {
+		return nil, nil
+	}
+
+	entries, err := fs.readPackedIndex(framesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make([]sessionvideo.FrameIndexEntry, 0, len(entries))
+	for frameIndex, entry := range entries {
+		index = append(index, sessionvideo.FrameIndexEntry{FrameIndex: frameIndex, Offset: entry.offset, Length: entry.length})
+	}
+	sort.Slice(index, func(i, j int) bool {
+		return index[i].FrameIndex < index[j].FrameIndex
+	})
+	return index, nil
+}
+
+// appendPackedFrame agrega el frame al contenedor y registra su posición en el índice
+func (fs *frameStore) appendPackedFrame(framesDir string, frameIndex int, data []byte) error {
+	unlock := fs.packLocks.lock(framesDir)
+	defer unlock()
+
+	packFile, err := os.OpenFile(filepath.Join(framesDir, PackedFramesFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("error abriendo contenedor de frames: %w", err)
+	}
+	defer packFile.Close()
+
+	info, err := packFile.Stat()
+	if err != nil {
+		return fmt.Errorf("error obteniendo tamaño del contenedor: %w", err)
+	}
+	offset := info.Size()
+
+	if _, err := packFile.Write(data); err != nil {
+		return fmt.Errorf("error escribiendo frame en contenedor: %w", err)
+	}
+
+	indexFile, err := os.OpenFile(filepath.Join(framesDir, PackedIndexFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("error abriendo índice de frames: %w", err)
+	}
+	defer indexFile.Close()
+
+	entry := make([]byte, packedIndexEntrySize)
+	binary.LittleEndian.PutUint64(entry[0:8], uint64(frameIndex))
+	binary.LittleEndian.PutUint64(entry[8:16], uint64(offset))
+	binary.LittleEndian.PutUint64(entry[16:24], uint64(len(data)))
+
+	if _, err := indexFile.Write(entry); err != nil {
+		return fmt.Errorf("error escribiendo índice de frames: %w", err)
+	}
+
+	return nil
+}
+
+// packedIndexEntry posición de un frame dentro del contenedor
+type packedIndexEntry struct {
+	offset int64
+	length int64
+}
+
+// readPackedIndex carga el índice; si un frame se escribió más de una vez prevalece la última entrada
+func (fs *frameStore) readPackedIndex(framesDir string) (map[int]packedIndexEntry, error) {
+	raw, err := os.ReadFile(filepath.Join(framesDir, PackedIndexFileName))
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo índice de frames: %w", err)
+	}
+
+	entries := make(map[int]packedIndexEntry, len(raw)/packedIndexEntrySize)
+	// Una entrada incompleta al final (escritura interrumpida) se ignora
+	for pos := 0; pos+packedIndexEntrySize <= len(raw); pos += packedIndexEntrySize {
+		frameIndex := int(binary.LittleEndian.Uint64(raw[pos : pos+8]))
+		entries[frameIndex] = packedIndexEntry{
+			offset: int64(binary.LittleEndian.Uint64(raw[pos+8 : pos+16])),
+			length: int64(binary.LittleEndian.Uint64(raw[pos+16 : pos+24])),
+		}
+	}
+
+	return entries, nil
+}
+
+// readPackedFrame lee un frame del contenedor usando el índice
+func (fs *frameStore) readPackedFrame(framesDir string, frameIndex int) ([]byte, error) {
+	entries, err := fs.readPackedIndex(framesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, exists := entries[frameIndex]
+	if !exists {
+		return nil, ErrFrameNotFound
+	}
+
+	packFile, err := os.Open(filepath.Join(framesDir, PackedFramesFileName))
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo contenedor de frames: %w", err)
+	}
+	defer packFile.Close()
+
+	data := make([]byte, entry.length)
+	if _, err := packFile.ReadAt(data, entry.offset); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("contenedor de frames truncado en frame %d", frameIndex)
+		}
+		return nil, fmt.Errorf("error leyendo frame %d del contenedor: %w", frameIndex, err)
+	}
+
+	return data, nil
+}
+
+// isPackedRecording indica si el directorio contiene una grabación empaquetada
+func isPackedRecording(framesDir string) bool {
+	_, err := os.Stat(filepath.Join(framesDir, PackedIndexFileName))
+	return err == nil
+}
+
+// individualFrameFileName genera el nombre con padding para ordenamiento correcto: frame_000001.jpg, ...
+func individualFrameFileName(frameIndex int) string {
+	return fmt.Sprintf("frame_%06d.jpg", frameIndex)
+}
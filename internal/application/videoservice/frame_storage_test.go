@@ -0,0 +1,201 @@
+package videoservice
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFrames genera frames con contenido distinto y tamaños variables
+func testFrames(count int) [][]byte {
+	frames := make([][]byte, count)
+	for i := range frames {
+		payload := bytes.Repeat([]byte(fmt.Sprintf("frame-%d;", i)), i*7+1)
+		frames[i] = append(append([]byte{0xFF, 0xD8}, payload...), 0xFF, 0xD9)
+	}
+	return frames
+}
+
+func TestFrameStore_PackedModeRoundTrip(t *testing.T) {
+	// Arrange
+	framesDir := t.TempDir()
+	store := NewFrameStore(FrameStoragePacked)
+	frames := testFrames(5)
+
+	// Act
+	for i, data := range frames {
+		require.NoError(t, store.WriteFrame(framesDir, i+1, data))
+	}
+
+	// Assert - todos los frames se leen de vuelta intactos
+	for i, expected := range frames {
+		data, err := store.ReadFrame(framesDir, i+1)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, data)
+	}
+
+	count, err := store.CountFrames(framesDir)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	// Solo dos archivos en disco independientemente del número de frames
+	entries, err := os.ReadDir(framesDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestFrameStore_PackedFrameMatchesIndividualEquivalent(t *testing.T) {
+	// Arrange
+	packedDir := t.TempDir()
+	individualDir := t.TempDir()
+	packed := NewFrameStore(FrameStoragePacked)
+	individual := NewFrameStore(FrameStorageIndividual)
+	frames := testFrames(4)
+
+	for i, data := range frames {
+		require.NoError(t, packed.WriteFrame(packedDir, i+1, data))
+		require.NoError(t, individual.WriteFrame(individualDir, i+1, data))
+	}
+
+	// Act
+	packedFrame, err := packed.ReadFrame(packedDir, 3)
+	require.NoError(t, err)
+	individualFile, err := os.ReadFile(filepath.Join(individualDir, "frame_000003.jpg"))
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, individualFile, packedFrame)
+
+	individualCount, err := individual.CountFrames(individualDir)
+	assert.NoError(t, err)
+	packedCount, err := packed.CountFrames(packedDir)
+	assert.NoError(t, err)
+	assert.Equal(t, individualCount, packedCount)
+}
+
+func TestFrameStore_PackedWritesToOtherRecordingDoNotWaitForBusyRecording(t *testing.T) {
+	// Arrange - la grabación ocupada mantiene su lock mientras otra escribe
+	busyDir, otherDir := t.TempDir(), t.TempDir()
+	store := NewFrameStore(FrameStoragePacked).(*frameStore)
+	unlock := store.packLocks.lock(busyDir)
+	defer unlock()
+
+	// Act
+	written := make(chan error, 1)
+	go func() { written <- store.WriteFrame(otherDir, 1, testFrames(1)[0]) }()
+
+	// Assert
+	select {
+	case err := <-written:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("la escritura de otra grabación esperó al lock de la grabación ocupada")
+	}
+}
+
+func TestFrameStore_PackedConcurrentWritesKeepIndexConsistent(t *testing.T) {
+	// Arrange
+	framesDir := t.TempDir()
+	store := NewFrameStore(FrameStoragePacked)
+	frames := testFrames(20)
+
+	// Act
+	var wg sync.WaitGroup
+	for i, data := range frames {
+		wg.Add(1)
+		go func(frameIndex int, data []byte) {
+			defer wg.Done()
+			assert.NoError(t, store.WriteFrame(framesDir, frameIndex, data))
+		}(i+1, data)
+	}
+	wg.Wait()
+
+	// Assert
+	for i, expected := range frames {
+		data, err := store.ReadFrame(framesDir, i+1)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, data)
+	}
+	assert.Empty(t, store.(*frameStore).packLocks.locks)
+}
+
+func TestFrameStore_ReadsRecordingRegardlessOfConfiguredFormat(t *testing.T) {
+	// Arrange - grabación antigua en archivos individuales, servidor configurado en modo packed
+	framesDir := t.TempDir()
+	require.NoError(t, NewFrameStore(FrameStorageIndividual).WriteFrame(framesDir, 1, []byte("legacy")))
+	store := NewFrameStore(FrameStoragePacked)
+
+	// Act
+	data, err := store.ReadFrame(framesDir, 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("legacy"), data)
+}
+
+func TestFrameStore_MissingFrame(t *testing.T) {
+	for _, format := range []FrameStorageFormat{FrameStorageIndividual, FrameStoragePacked} {
+		t.Run(string(format), func(t *testing.T) {
+			// Arrange
+			framesDir := t.TempDir()
+			store := NewFrameStore(format)
+			require.NoError(t, store.WriteFrame(framesDir, 1, []byte("frame")))
+
+			// Act
+			data, err := store.ReadFrame(framesDir, 99)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrFrameNotFound)
+			assert.Nil(t, data)
+		})
+	}
+}
+
+func TestFrameStore_HasValidFrame(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   FrameStorageFormat
+		frames   [][]byte
+		expected bool
+	}{
+		{name: "individual without frames", format: FrameStorageIndividual, expected: false},
+		{name: "individual with only empty frames", format: FrameStorageIndividual, frames: [][]byte{{}, {}}, expected: false},
+		{name: "individual with one frame", format: FrameStorageIndividual, frames: [][]byte{{}, testFrames(1)[0]}, expected: true},
+		{name: "packed with only empty frames", format: FrameStoragePacked, frames: [][]byte{{}}, expected: false},
+		{name: "packed with frames", format: FrameStoragePacked, frames: testFrames(2), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			framesDir := t.TempDir()
+			store := NewFrameStore(tt.format)
+			for i, data := range tt.frames {
+				require.NoError(t, store.WriteFrame(framesDir, i+1, data))
+			}
+
+			// Act
+			hasFrames, err := store.HasValidFrame(framesDir)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, hasFrames)
+		})
+	}
+}
+
+func TestParseFrameStorageFormat(t *testing.T) {
+	assert.Equal(t, FrameStoragePacked, ParseFrameStorageFormat("packed"))
+	assert.Equal(t, FrameStoragePacked, ParseFrameStorageFormat(" PACKED "))
+	assert.Equal(t, FrameStorageSprites, ParseFrameStorageFormat("sprites"))
+	assert.Equal(t, FrameStorageIndividual, ParseFrameStorageFormat("individual"))
+	assert.Equal(t, FrameStorageIndividual, ParseFrameStorageFormat(""))
+	assert.Equal(t, FrameStorageIndividual, ParseFrameStorageFormat("zip"))
+}
@@ -0,0 +1,27 @@
+package videoservice
+
+import (
+	"bytes"
+	"errors"
+)
+
+// Marcadores de inicio (SOI) y fin (EOI) de imagen de un JPEG
+var (
+	jpegStartOfImage = []byte{0xFF, 0xD8, 0xFF}
+	jpegEndOfImage   = []byte{0xFF, 0xD9}
+)
+
+// ErrInvalidJPEGFrame indica que los datos de un frame no son un JPEG completo
+var ErrInvalidJPEGFrame = errors.New("frame data is not a valid JPEG")
+
+// ValidateJPEGFrame comprueba los marcadores SOI/EOI del frame decodificado. Es una comprobación barata
+// que no decodifica la imagen: descarta bytes arbitrarios y JPEGs truncados antes de reenviarlos o guardarlos.
+func ValidateJPEGFrame(frameData []byte) error {
+	if len(frameData) < len(jpegStartOfImage)+len(jpegEndOfImage) {
+		return ErrInvalidJPEGFrame
+	}
+	if !bytes.HasPrefix(frameData, jpegStartOfImage) || !bytes.HasSuffix(frameData, jpegEndOfImage) {
+		return ErrInvalidJPEGFrame
+	}
+	return nil
+}
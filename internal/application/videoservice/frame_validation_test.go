@@ -0,0 +1,39 @@
+package videoservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateJPEGFrame_AcceptsValidJPEG(t *testing.T) {
+	// Arrange
+	frame := testJPEG(t, 32, 16)
+
+	// Act
+	err := ValidateJPEGFrame(frame)
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestValidateJPEGFrame_RejectsTruncatedJPEG(t *testing.T) {
+	// Arrange - el cliente cortó el frame antes del marcador EOI
+	frame := testJPEG(t, 32, 16)
+	truncated := frame[:len(frame)/2]
+
+	// Act
+	err := ValidateJPEGFrame(truncated)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidJPEGFrame)
+}
+
+func TestValidateJPEGFrame_RejectsRandomBytes(t *testing.T) {
+	// Act
+	err := ValidateJPEGFrame([]byte("definitely not an image payload"))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidJPEGFrame)
+	assert.ErrorIs(t, ValidateJPEGFrame(nil), ErrInvalidJPEGFrame)
+}
@@ -0,0 +1,149 @@
+package videoservice
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+// OrphanedRecordingsBatchLimit grabaciones huérfanas que se revisan o limpian en cada ejecución
+const OrphanedRecordingsBatchLimit = 1000
+
+// OrphanedRecording grabación cuya sesión ya no existe; GetAllRecordings la omite pero sigue ocupando disco
+type OrphanedRecording struct {
+	VideoID    string    `json:"video_id"`
+	SessionID  string    `json:"session_id"`
+	FilePath   string    `json:"file_path"`
+	RecordedAt time.Time `json:"recorded_at"`
+	FileSizeMB float64   `json:"file_size_mb"`
+	DiskBytes  int64     `json:"disk_bytes"`
+}
+
+// OrphanedRecordingsReport grabaciones huérfanas encontradas y, tras una limpieza, cuántas se eliminaron
+type OrphanedRecordingsReport struct {
+	Recordings []OrphanedRecording `json:"recordings"`
+	Count      int                 `json:"count"`
+	TotalBytes int64               `json:"total_bytes"`
+	Cleaned    bool                `json:"cleaned"`
+	Deleted    int                 `json:"deleted"`
+	Warnings   []string            `json:"warnings,omitempty"`
+}
+
+// IOrphanedRecordingService detecta y limpia las grabaciones de sesiones que ya no existen
+type IOrphanedRecordingService interface {
+	FindOrphanedRecordings(ctx context.Context) (*OrphanedRecordingsReport, error)
+	CleanOrphanedRecordings(ctx context.Context, adminUserID string) (*OrphanedRecordingsReport, error)
+}
+
+// FindOrphanedRecordings lista, sin modificar nada, las grabaciones cuya sesión no existe y lo que ocupan en disco
+func (vs *videoService) FindOrphanedRecordings(ctx context.Context) (*OrphanedRecordingsReport, error) {
+	videos, err := vs.videoRepository.FindOrphaned(ctx, OrphanedRecordingsBatchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error buscando grabaciones huérfanas: %w", err)
+	}
+
+	report := &OrphanedRecordingsReport{Recordings: make([]OrphanedRecording, 0, len(videos))}
+	for _, video := range videos {
+		orphan := toOrphanedRecording(video)
+		report.Recordings = append(report.Recordings, orphan)
+		report.TotalBytes += orphan.DiskBytes
+	}
+	report.Count = len(report.Recordings)
+	return report, nil
+}
+
+// CleanOrphanedRecordings elimina los archivos y la fila de cada grabación huérfana. Como en DeleteVideo, un
+// archivo que no se puede borrar solo se anota en Warnings; la fila se borra igualmente para no volver a
+// detectarla. Cada grabación eliminada se registra como VIDEO_RECORDING_DISPOSED (event: orphaned_recording).
+func (vs *videoService) CleanOrphanedRecordings(ctx context.Context, adminUserID string) (*OrphanedRecordingsReport, error) {
+	report, err := vs.FindOrphanedRecordings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Cleaned = true
+
+	for _, orphan := range report.Recordings {
+		if err := vs.deleteOrphanedRecordingFiles(ctx, orphan); err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("recording files %s: %v", orphan.FilePath, err))
+		}
+		if err := vs.videoRepository.Delete(ctx, orphan.VideoID); err != nil {
+			return report, fmt.Errorf("error eliminando grabación huérfana %s: %w", orphan.VideoID, err)
+		}
+		report.Deleted++
+		vs.logOrphanedRecordingDeleted(ctx, orphan, adminUserID)
+	}
+
+	if report.Deleted > 0 {
+		fmt.Printf("🧹 Grabaciones huérfanas eliminadas: %d (%d bytes)\n", report.Deleted, report.TotalBytes)
+	}
+	return report, nil
+}
+
+// toOrphanedRecording datos de la grabación y bytes de su directorio de frames (cero si ya no está en disco)
+func toOrphanedRecording(video *sessionvideo.SessionVideo) OrphanedRecording {
+	orphan := OrphanedRecording{
+		VideoID:    video.VideoID(),
+		SessionID:  video.AssociatedSessionID(),
+		FilePath:   video.FilePath(),
+		RecordedAt: video.RecordedAt(),
+		FileSizeMB: video.FileSizeMB(),
+	}
+	if info, err := os.Stat(video.FilePath()); err == nil && info.IsDir() {
+		orphan.DiskBytes, _ = framesDirBytes(video.FilePath())
+	} else if err == nil {
+		orphan.DiskBytes = info.Size()
+	}
+	return orphan
+}
+
+// deleteOrphanedRecordingFiles borra el directorio de frames o el archivo de la grabación y su MP4 procesado
+func (vs *videoService) deleteOrphanedRecordingFiles(ctx context.Context, orphan OrphanedRecording) error {
+	if orphan.FilePath != "" {
+		if info, err := os.Stat(orphan.FilePath); err == nil && info.IsDir() {
+			if err := os.RemoveAll(orphan.FilePath); err != nil {
+				return err
+			}
+		} else if vs.fileStorage != nil && vs.fileStorage.FileExists(ctx, orphan.FilePath) {
+			if err := vs.fileStorage.DeleteFile(ctx, orphan.FilePath); err != nil {
+				return err
+			}
+		}
+	}
+
+	if vs.fileStorage != nil {
+		mp4Path := processedVideoPath(orphan.SessionID, orphan.VideoID)
+		if vs.fileStorage.FileExists(ctx, mp4Path) {
+			return vs.fileStorage.DeleteFile(ctx, mp4Path)
+		}
+	}
+	return nil
+}
+
+// logOrphanedRecordingDeleted registra en auditoría la grabación huérfana eliminada
+func (vs *videoService) logOrphanedRecordingDeleted(ctx context.Context, orphan OrphanedRecording, adminUserID string) {
+	if vs.actionLogService == nil {
+		return
+	}
+
+	entityType := "SESSION_VIDEO"
+	err := vs.actionLogService.LogAction(ctx, actionlog.ActionVideoRecordingDisposed,
+		fmt.Sprintf("Grabación huérfana eliminada - VideoID: %s, Sesión inexistente: %s", orphan.VideoID, orphan.SessionID),
+		adminUserID,
+		&orphan.VideoID,
+		&entityType,
+		map[string]interface{}{
+			"event":      "orphaned_recording",
+			"video_id":   orphan.VideoID,
+			"session_id": orphan.SessionID,
+			"file_path":  orphan.FilePath,
+			"disk_bytes": orphan.DiskBytes,
+		})
+	if err != nil {
+		// Log pero no fallar
+		fmt.Printf("Warning: error registrando audit log para la grabación huérfana %s: %v\n", orphan.VideoID, err)
+	}
+}
@@ -0,0 +1,73 @@
+package videoservice
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+// newOrphanedRecordingService crea un servicio cuyo repositorio reporta una grabación huérfana de 3 frames en disco
+func newOrphanedRecordingService(t *testing.T) (*videoService, *MockSessionVideoRepository, *MockActionLogService, *sessionvideo.SessionVideo) {
+	t.Helper()
+
+	framesDir := filepath.Join(t.TempDir(), "video-orphan")
+	require.NoError(t, os.MkdirAll(framesDir, 0755))
+	for i, size := range []int{100, 200, 300} {
+		require.NoError(t, os.WriteFile(filepath.Join(framesDir, individualFrameFileName(i)), make([]byte, size), 0644))
+	}
+	orphan := sessionvideo.NewSessionVideo(framesDir, 3, "deleted-session", 0.5)
+
+	videoRepo := new(MockSessionVideoRepository)
+	videoRepo.On("FindOrphaned", mock.Anything, OrphanedRecordingsBatchLimit).Return([]*sessionvideo.SessionVideo{orphan}, nil)
+	actionLog := new(MockActionLogService)
+	service := NewVideoService(videoRepo, nil, nil, actionLog, FrameStorageIndividual, 0, DefaultPartialRecordingPolicy).(*videoService)
+	return service, videoRepo, actionLog, orphan
+}
+
+func TestFindOrphanedRecordings_ReportsRecordingOfMissingSessionWithoutDeleting(t *testing.T) {
+	// Arrange
+	service, videoRepo, _, orphan := newOrphanedRecordingService(t)
+
+	// Act
+	report, err := service.FindOrphanedRecordings(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Count)
+	assert.Equal(t, orphan.VideoID(), report.Recordings[0].VideoID)
+	assert.Equal(t, "deleted-session", report.Recordings[0].SessionID)
+	assert.Equal(t, int64(600), report.Recordings[0].DiskBytes)
+	assert.Equal(t, int64(600), report.TotalBytes)
+	assert.False(t, report.Cleaned)
+	assert.DirExists(t, orphan.FilePath())
+	videoRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestCleanOrphanedRecordings_DeletesFramesAndRowAndAudits(t *testing.T) {
+	// Arrange
+	service, videoRepo, actionLog, orphan := newOrphanedRecordingService(t)
+	videoRepo.On("Delete", mock.Anything, orphan.VideoID()).Return(nil)
+	actionLog.On("LogAction", mock.Anything, actionlog.ActionVideoRecordingDisposed, mock.Anything, "admin-1",
+		mock.Anything, mock.Anything, mock.MatchedBy(func(details map[string]interface{}) bool {
+			return details["event"] == "orphaned_recording" && details["session_id"] == "deleted-session"
+		})).Return(nil)
+
+	// Act
+	report, err := service.CleanOrphanedRecordings(context.Background(), "admin-1")
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, report.Cleaned)
+	assert.Equal(t, 1, report.Deleted)
+	assert.Empty(t, report.Warnings)
+	assert.NoDirExists(t, orphan.FilePath())
+	videoRepo.AssertExpectations(t)
+	actionLog.AssertExpectations(t)
+}
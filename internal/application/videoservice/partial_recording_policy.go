@@ -0,0 +1,101 @@
+package videoservice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PartialRecordingPolicyMode indica qué se hace con los frames ya capturados de una sesión que termina sin éxito
+type PartialRecordingPolicyMode string
+
+const (
+	// PartialRecordingDiscard elimina los frames capturados
+	PartialRecordingDiscard PartialRecordingPolicyMode = "discard"
+	// PartialRecordingKeep finaliza la grabación con los frames capturados
+	PartialRecordingKeep PartialRecordingPolicyMode = "keep"
+	// PartialRecordingKeepIfLongerThan conserva la grabación solo si supera una duración mínima
+	PartialRecordingKeepIfLongerThan PartialRecordingPolicyMode = "keep-if-longer-than"
+)
+
+// DefaultPartialRecordingPolicy conserva las grabaciones parciales, como ocurría antes de existir la política
+var DefaultPartialRecordingPolicy = PartialRecordingPolicy{Mode: PartialRecordingKeep}
+
+// PartialRecordingPolicy política de retención de grabaciones de sesiones rechazadas o fallidas
+type PartialRecordingPolicy struct {
+	Mode        PartialRecordingPolicyMode
+	MinDuration time.Duration
+}
+
+// ParsePartialRecordingPolicy interpreta "discard", "keep" o "keep-if-longer-than-N-seconds"
+// (también "keep-if-longer-than-N" o con una duración de Go, p. ej. "keep-if-longer-than-1m30s").
+func ParsePartialRecordingPolicy(value string) (PartialRecordingPolicy, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+
+	switch PartialRecordingPolicyMode(normalized) {
+	case PartialRecordingDiscard, PartialRecordingKeep:
+		return PartialRecordingPolicy{Mode: PartialRecordingPolicyMode(normalized)}, nil
+	}
+
+	prefix := string(PartialRecordingKeepIfLongerThan) + "-"
+	if !strings.HasPrefix(normalized, prefix) {
+		return PartialRecordingPolicy{}, fmt.Errorf("política de grabación parcial desconocida: %q", value)
+	}
+
+	minDuration, err := parsePolicyDuration(strings.TrimPrefix(normalized, prefix))
+	if err != nil {
+		return PartialRecordingPolicy{}, fmt.Errorf("duración inválida en la política %q: %w", value, err)
+	}
+
+	return PartialRecordingPolicy{Mode: PartialRecordingKeepIfLongerThan, MinDuration: minDuration}, nil
+}
+
+// parsePolicyDuration acepta "N-seconds", "N" (segundos) o una duración de Go
+func parsePolicyDuration(value string) (time.Duration, error) {
+	seconds := strings.TrimSuffix(value, "-seconds")
+	if parsed, err := strconv.ParseFloat(seconds, 64); err == nil {
+		if parsed < 0 {
+			return 0, fmt.Errorf("la duración no puede ser negativa")
+		}
+		return time.Duration(parsed * float64(time.Second)), nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if parsed < 0 {
+		return 0, fmt.Errorf("la duración no puede ser negativa")
+	}
+	return parsed, nil
+}
+
+// Keeps indica si una grabación parcial con la duración dada debe conservarse
+func (p PartialRecordingPolicy) Keeps(duration time.Duration) bool {
+	switch p.Mode {
+	case PartialRecordingDiscard:
+		return false
+	case PartialRecordingKeepIfLongerThan:
+		return duration > p.MinDuration
+	default:
+		return true
+	}
+}
+
+// String representa la política con el mismo formato que acepta ParsePartialRecordingPolicy
+func (p PartialRecordingPolicy) String() string {
+	if p.Mode == PartialRecordingKeepIfLongerThan {
+		return fmt.Sprintf("%s-%g-seconds", p.Mode, p.MinDuration.Seconds())
+	}
+	return string(p.Mode)
+}
+
+// PartialRecordingDisposition resultado de aplicar la política a una grabación en curso
+type PartialRecordingDisposition struct {
+	VideoID         string  `json:"video_id"`
+	SessionID       string  `json:"session_id"`
+	Kept            bool    `json:"kept"`
+	TotalFrames     int     `json:"total_frames"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
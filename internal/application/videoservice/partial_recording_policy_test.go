@@ -0,0 +1,133 @@
+package videoservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+func TestParsePartialRecordingPolicy(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected PartialRecordingPolicy
+	}{
+		{value: "discard", expected: PartialRecordingPolicy{Mode: PartialRecordingDiscard}},
+		{value: " KEEP ", expected: PartialRecordingPolicy{Mode: PartialRecordingKeep}},
+		{value: "keep-if-longer-than-30-seconds", expected: PartialRecordingPolicy{Mode: PartialRecordingKeepIfLongerThan, MinDuration: 30 * time.Second}},
+		{value: "keep-if-longer-than-5", expected: PartialRecordingPolicy{Mode: PartialRecordingKeepIfLongerThan, MinDuration: 5 * time.Second}},
+		{value: "keep-if-longer-than-1m30s", expected: PartialRecordingPolicy{Mode: PartialRecordingKeepIfLongerThan, MinDuration: 90 * time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// Act
+			policy, err := ParsePartialRecordingPolicy(tt.value)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, policy)
+		})
+	}
+}
+
+func TestParsePartialRecordingPolicy_RejectsInvalidValues(t *testing.T) {
+	for _, value := range []string{"", "archive", "keep-if-longer-than-", "keep-if-longer-than--5-seconds", "keep-if-longer-than-soon"} {
+		// Act
+		_, err := ParsePartialRecordingPolicy(value)
+
+		// Assert
+		assert.Error(t, err, value)
+	}
+}
+
+// seedPartialRecording guarda frames de una grabación en curso y fija su duración medida por el servidor
+func seedPartialRecording(t *testing.T, service *videoService, duration time.Duration) string {
+	t.Helper()
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, service.SaveVideoFrame(testFrameInfo(i)))
+	}
+	progress := service.recordings[testVideoID]
+	progress.firstFrameAt = progress.lastFrameAt.Add(-duration)
+	return filepath.Join(service.framesBaseDir, testVideoID)
+}
+
+func TestApplyPartialRecordingPolicy(t *testing.T) {
+	shortRecording := 2 * time.Second
+	longRecording := 2 * time.Minute
+	keepIfLongerThanMinute := PartialRecordingPolicy{Mode: PartialRecordingKeepIfLongerThan, MinDuration: time.Minute}
+
+	tests := []struct {
+		name         string
+		policy       PartialRecordingPolicy
+		duration     time.Duration
+		expectedKept bool
+	}{
+		{name: "discard short", policy: PartialRecordingPolicy{Mode: PartialRecordingDiscard}, duration: shortRecording, expectedKept: false},
+		{name: "discard long", policy: PartialRecordingPolicy{Mode: PartialRecordingDiscard}, duration: longRecording, expectedKept: false},
+		{name: "keep short", policy: PartialRecordingPolicy{Mode: PartialRecordingKeep}, duration: shortRecording, expectedKept: true},
+		{name: "keep long", policy: PartialRecordingPolicy{Mode: PartialRecordingKeep}, duration: longRecording, expectedKept: true},
+		{name: "keep-if-longer-than short", policy: keepIfLongerThanMinute, duration: shortRecording, expectedKept: false},
+		{name: "keep-if-longer-than long", policy: keepIfLongerThanMinute, duration: longRecording, expectedKept: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service, videoRepo, actionLog := newLimitedVideoService(t, 100)
+			service.partialPolicy = tt.policy
+			recordingDir := seedPartialRecording(t, service, tt.duration)
+
+			expectedDisposition := "DISCARDED"
+			if tt.expectedKept {
+				expectedDisposition = "KEPT"
+				videoRepo.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+				actionLog.On("LogAction", mock.Anything, actionlog.ActionVideoRecordingEnded, mock.Anything,
+					mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			}
+			actionLog.On("LogAction", mock.Anything, actionlog.ActionVideoRecordingDisposed, mock.Anything,
+				"admin-1", mock.Anything, mock.Anything, mock.MatchedBy(func(details map[string]interface{}) bool {
+					return details["disposition"] == expectedDisposition && details["session_end_status"] == "FAILED"
+				})).Return(nil).Once()
+
+			// Act
+			dispositions, err := service.ApplyPartialRecordingPolicy(t.Context(), testSessionID, "admin-1", remotesession.StatusFailed)
+
+			// Assert
+			require.NoError(t, err)
+			require.Len(t, dispositions, 1)
+			assert.Equal(t, tt.expectedKept, dispositions[0].Kept)
+			assert.Equal(t, 3, dispositions[0].TotalFrames)
+			assert.InDelta(t, tt.duration.Seconds(), dispositions[0].DurationSeconds, 0.001)
+
+			_, statErr := os.Stat(recordingDir)
+			assert.Equal(t, tt.expectedKept, statErr == nil, "los frames solo se conservan si la política lo indica")
+			assert.NotContains(t, service.recordings, testVideoID)
+			videoRepo.AssertExpectations(t)
+			actionLog.AssertExpectations(t)
+		})
+	}
+}
+
+func TestApplyPartialRecordingPolicy_IgnoresRecordingsOfOtherSessions(t *testing.T) {
+	// Arrange
+	service, _, actionLog := newLimitedVideoService(t, 100)
+	service.partialPolicy = PartialRecordingPolicy{Mode: PartialRecordingDiscard}
+	recordingDir := seedPartialRecording(t, service, time.Second)
+
+	// Act
+	dispositions, err := service.ApplyPartialRecordingPolicy(t.Context(), "other-session", "admin-1", remotesession.StatusRejected)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, dispositions)
+	assert.DirExists(t, recordingDir)
+	assert.Contains(t, service.recordings, testVideoID)
+	actionLog.AssertNotCalled(t, "LogAction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,109 @@
+package videoservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// IRecordingArtifactService elimina los directorios de trabajo de las grabaciones al purgar un PC
+type IRecordingArtifactService interface {
+	RemoveRecordingArtifacts(sessionIDs, videoIDs []string) (int, error)
+}
+
+// RemoveRecordingArtifacts elimina del disco los directorios de trabajo de las grabaciones indicadas y de las de
+// las sesiones indicadas: frames (con su índice y la sesión ligada) y subidas en curso, por chunks o por partes,
+// aunque la grabación aún no tenga fila en session_videos. Se usa al purgar un PC. Retorna cuántos directorios
+// eliminó; los que no puede eliminar se acumulan en el error sin detener el resto.
+func (vs *videoService) RemoveRecordingArtifacts(sessionIDs, videoIDs []string) (int, error) {
+	sessions := make(map[string]bool, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		sessions[sessionID] = true
+	}
+	videos := make(map[string]bool, len(videoIDs))
+	for _, videoID := range videoIDs {
+		videos[videoID] = true
+	}
+	if len(sessions) == 0 && len(videos) == 0 {
+		return 0, nil
+	}
+
+	removed := 0
+	var failures []error
+	remove := func(dir string) {
+		if err := os.RemoveAll(dir); err != nil {
+			failures = append(failures, err)
+			return
+		}
+		removed++
+	}
+
+	// Frames: la sesión está en memoria o, tras un reinicio, en el archivo session_id del directorio
+	vs.recordingsMutex.Lock()
+	for _, videoID := range listDirNames(vs.framesBaseDir) {
+		sessionID := ""
+		if progress, exists := vs.recordings[videoID]; exists {
+			sessionID = progress.sessionID
+		} else if bound, err := vs.boundRecordingSession(videoID); err == nil {
+			sessionID = bound
+		}
+		if videos[videoID] || sessions[sessionID] {
+			remove(filepath.Join(vs.framesBaseDir, videoID))
+			delete(vs.recordings, videoID)
+		}
+	}
+	vs.recordingsMutex.Unlock()
+
+	// Subidas en curso: la sesión está en la subida en memoria o en su manifiesto
+	vs.uploadMutex.Lock()
+	for _, videoID := range listDirNames(vs.uploadsBaseDir) {
+		uploadDir := filepath.Join(vs.uploadsBaseDir, videoID)
+		sessionID := uploadDirSessionID(uploadDir)
+		if uploadSession, exists := vs.uploadSessions[videoID]; exists {
+			sessionID = uploadSession.SessionID
+		}
+		if videos[videoID] || sessions[sessionID] {
+			remove(uploadDir)
+			delete(vs.uploadSessions, videoID)
+		}
+	}
+	vs.uploadMutex.Unlock()
+
+	if len(failures) > 0 {
+		return removed, fmt.Errorf("error eliminando %d directorios de grabación: %v", len(failures), failures)
+	}
+	return removed, nil
+}
+
+// listDirNames nombres de los subdirectorios de dir; vacío si dir no existe o no se puede leer
+func listDirNames(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+// uploadDirSessionID sesión del manifiesto de una subida, por chunks o por partes; "" si no tiene manifiesto
+func uploadDirSessionID(uploadDir string) string {
+	for _, manifestFile := range []string{uploadManifestFileName, partUploadManifestFileName} {
+		raw, err := os.ReadFile(filepath.Join(uploadDir, manifestFile))
+		if err != nil {
+			continue
+		}
+		var manifest struct {
+			SessionID string `json:"session_id"`
+		}
+		if json.Unmarshal(raw, &manifest) == nil && manifest.SessionID != "" {
+			return manifest.SessionID
+		}
+	}
+	return ""
+}
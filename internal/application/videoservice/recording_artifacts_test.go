@@ -0,0 +1,40 @@
+package videoservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveRecordingArtifacts_RemovesFramesAndUploadsOfPurgedSessions(t *testing.T) {
+	// Arrange - frames de la sesión purgada (tras un reinicio), su subida por chunks, una grabación antigua sin
+	// sesión ligada y los frames de otra sesión, que se conservan
+	service, _, _ := newUploadVideoService(t)
+	service.framesBaseDir = t.TempDir()
+	require.NoError(t, service.SaveVideoFrame(testFrameInfo(1)))
+	_, err := service.HandleUploadedVideoChunk(testResumableChunk(0))
+	require.NoError(t, err)
+
+	legacyDir := filepath.Join(service.framesBaseDir, "video-legacy", "frames")
+	require.NoError(t, os.MkdirAll(legacyDir, 0755))
+	otherFrame := testFrameInfo(1)
+	otherFrame.VideoID, otherFrame.SessionID = "video-other", "session-other"
+	require.NoError(t, service.SaveVideoFrame(otherFrame))
+
+	restarted, _, _ := newUploadVideoService(t)
+	restarted.framesBaseDir, restarted.uploadsBaseDir = service.framesBaseDir, service.uploadsBaseDir
+
+	// Act
+	removed, err := restarted.RemoveRecordingArtifacts([]string{testSessionID}, []string{"video-legacy"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, removed)
+	assert.NoDirExists(t, filepath.Join(service.framesBaseDir, testVideoID))
+	assert.NoDirExists(t, filepath.Join(service.uploadsBaseDir, testVideoID))
+	assert.NoDirExists(t, filepath.Join(service.framesBaseDir, "video-legacy"))
+	assert.DirExists(t, filepath.Join(service.framesBaseDir, "video-other"))
+}
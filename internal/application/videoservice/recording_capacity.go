@@ -0,0 +1,133 @@
+package videoservice
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultMaxConcurrentRecordings máximo de grabaciones en curso a la vez; cada una escribe frames a disco
+const DefaultMaxConcurrentRecordings = 20
+
+// DefaultRecordingStaleTimeout tiempo sin frames tras el que una grabación que el cliente nunca finalizó se da
+// por abandonada y se olvida su progreso
+const DefaultRecordingStaleTimeout = 2 * time.Minute
+
+// ErrRecordingCapacityReached se retorna con el primer frame de una grabación nueva cuando ya hay el máximo de
+// grabaciones en curso. Las grabaciones ya iniciadas siguen aceptando frames.
+var ErrRecordingCapacityReached = errors.New("máximo de grabaciones simultáneas alcanzado")
+
+// IRecordingCapacityService consulta y cambia el máximo de grabaciones simultáneas
+type IRecordingCapacityService interface {
+	MaxConcurrentRecordings() int
+	SetMaxConcurrentRecordings(max int)
+	SetRecordingStaleTimeout(timeout time.Duration)
+}
+
+// MaxConcurrentRecordings máximo de grabaciones en curso a la vez; cero = sin límite
+func (vs *videoService) MaxConcurrentRecordings() int {
+	vs.recordingsMutex.Lock()
+	defer vs.recordingsMutex.Unlock()
+
+	return vs.maxConcurrentRecordings
+}
+
+// SetMaxConcurrentRecordings cambia el máximo de grabaciones simultáneas (cero o negativo = sin límite). Bajarlo
+// no corta las grabaciones en curso: solo se rechazan las nuevas hasta que haya hueco.
+func (vs *videoService) SetMaxConcurrentRecordings(max int) {
+	if max < 0 {
+		max = 0
+	}
+
+	vs.recordingsMutex.Lock()
+	defer vs.recordingsMutex.Unlock()
+
+	vs.maxConcurrentRecordings = max
+}
+
+// SetRecordingStaleTimeout cambia el tiempo sin frames tras el que una grabación se da por abandonada (cero o
+// negativo = DefaultRecordingStaleTimeout)
+func (vs *videoService) SetRecordingStaleTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultRecordingStaleTimeout
+	}
+
+	vs.recordingsMutex.Lock()
+	defer vs.recordingsMutex.Unlock()
+
+	vs.recordingStaleTimeout = timeout
+}
+
+// ReleaseSessionRecordings la sesión terminó o su PC se desconectó: sus grabaciones dejan de contar como en curso
+// y de ocupar hueco en el máximo de grabaciones simultáneas. El progreso se conserva hasta que pasa
+// recordingStaleTimeout sin frames, así un video_recording_complete que llegue después sigue usando los tiempos del
+// servidor. Retorna cuántas grabaciones se liberaron.
+func (vs *videoService) ReleaseSessionRecordings(sessionID string) int {
+	vs.recordingsMutex.Lock()
+	defer vs.recordingsMutex.Unlock()
+
+	released := 0
+	now := time.Now()
+	for videoID, progress := range vs.recordings {
+		if progress.sessionID != sessionID || !progress.releasedAt.IsZero() {
+			continue
+		}
+		progress.releasedAt = now
+		released++
+		if !progress.limitReached {
+			fmt.Printf("🎞️ Grabación %s de la sesión %s liberada: la sesión terminó sin finalizarla\n", videoID, sessionID)
+		}
+	}
+	return released
+}
+
+// inProgress indica si la grabación sigue en curso: sin cerrar por el límite de frames, con la sesión abierta y
+// con frames recientes. Requiere recordingsMutex.
+func (vs *videoService) inProgress(progress *recordingProgress, now time.Time) bool {
+	return !progress.limitReached && progress.releasedAt.IsZero() && !vs.isStale(progress, now)
+}
+
+// isStale indica si la grabación lleva recordingStaleTimeout sin recibir frames; requiere recordingsMutex
+func (vs *videoService) isStale(progress *recordingProgress, now time.Time) bool {
+	lastActivity := progress.lastFrameAt
+	if lastActivity.IsZero() {
+		lastActivity = progress.startedAt
+	}
+	return now.Sub(lastActivity) >= vs.recordingStaleTimeout
+}
+
+// pruneStaleRecordings olvida el progreso de las grabaciones abandonadas; requiere recordingsMutex
+func (vs *videoService) pruneStaleRecordings(now time.Time) {
+	for videoID, progress := range vs.recordings {
+		if vs.isStale(progress, now) {
+			fmt.Printf("🧹 Grabación %s de la sesión %s olvidada: %d frames y sin frames desde hace %v\n",
+				videoID, progress.sessionID, progress.frames, vs.recordingStaleTimeout)
+			delete(vs.recordings, videoID)
+		}
+	}
+}
+
+// checkRecordingCapacity rechaza iniciar la grabación videoID si ya hay el máximo en curso; requiere recordingsMutex
+func (vs *videoService) checkRecordingCapacity(videoID string) error {
+	now := time.Now()
+	vs.pruneStaleRecordings(now)
+
+	if vs.maxConcurrentRecordings <= 0 {
+		return nil
+	}
+	if _, exists := vs.recordings[videoID]; exists {
+		return nil
+	}
+
+	active := 0
+	for _, progress := range vs.recordings {
+		if vs.inProgress(progress, now) {
+			active++
+		}
+	}
+	if active >= vs.maxConcurrentRecordings {
+		return fmt.Errorf("%w: %d/%d grabaciones en curso, video %s rechazado",
+			ErrRecordingCapacityReached, active, vs.maxConcurrentRecordings, videoID)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package videoservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testRecordingFrame frame de la grabación videoID de la sesión de prueba
+func testRecordingFrame(videoID string, frameIndex int) VideoFrameInfo {
+	frame := testFrameInfo(frameIndex)
+	frame.VideoID = videoID
+	return frame
+}
+
+func TestSaveVideoFrame_RefusesRecordingsBeyondConcurrentLimit(t *testing.T) {
+	// Arrange - dos grabaciones en curso con un máximo de dos
+	service, videoRepo, actionLog := newLimitedVideoService(t, 0)
+	videoRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	actionLog.On("LogAction", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	service.SetMaxConcurrentRecordings(2)
+	require.NoError(t, service.SaveVideoFrame(testRecordingFrame("video-1", 1)))
+	require.NoError(t, service.SaveVideoFrame(testRecordingFrame("video-2", 1)))
+
+	// Act
+	refusedErr := service.SaveVideoFrame(testRecordingFrame("video-3", 1))
+	ongoingErr := service.SaveVideoFrame(testRecordingFrame("video-1", 2))
+
+	// Assert - la tercera no se inicia ni deja frames en disco; las que estaban en curso siguen grabando
+	assert.ErrorIs(t, refusedErr, ErrRecordingCapacityReached)
+	assert.NoError(t, ongoingErr)
+	assert.Len(t, service.ListActiveRecordings(), 2)
+	_, err := os.Stat(filepath.Join(service.framesBaseDir, "video-3"))
+	assert.True(t, os.IsNotExist(err))
+
+	// Act - al finalizar una grabación queda hueco para otra nueva
+	require.NoError(t, service.FinalizeVideoRecording(VideoRecordingMetadata{
+		VideoID:     "video-2",
+		SessionID:   testSessionID,
+		TotalFrames: 1,
+		CompletedAt: time.Now(),
+	}))
+
+	// Assert
+	assert.NoError(t, service.SaveVideoFrame(testRecordingFrame("video-4", 1)))
+	assert.ErrorIs(t, service.SaveVideoFrame(testRecordingFrame("video-5", 1)), ErrRecordingCapacityReached)
+}
+
+func TestSaveVideoFrame_NoConcurrentLimitByDefault(t *testing.T) {
+	// Arrange
+	service, _, _ := newLimitedVideoService(t, 0)
+
+	// Act & Assert
+	for _, videoID := range []string{"video-1", "video-2", "video-3"} {
+		require.NoError(t, service.SaveVideoFrame(testRecordingFrame(videoID, 1)))
+	}
+	assert.Equal(t, 0, service.MaxConcurrentRecordings())
+	assert.Len(t, service.ListActiveRecordings(), 3)
+}
+
+func TestSaveVideoFrame_EndedSessionReleasesItsRecordings(t *testing.T) {
+	// Arrange - la sesión terminó sin que el cliente enviara video_recording_complete
+	service, _, _ := newLimitedVideoService(t, 0)
+	service.SetMaxConcurrentRecordings(1)
+	require.NoError(t, service.SaveVideoFrame(testRecordingFrame("video-1", 1)))
+
+	// Act
+	released := service.ReleaseSessionRecordings(testSessionID)
+
+	// Assert - deja de estar en curso y una grabación nueva cabe; el progreso se conserva para una finalización tardía
+	assert.Equal(t, 1, released)
+	assert.False(t, service.IsSessionRecording(testSessionID))
+	assert.Empty(t, service.ListActiveRecordings())
+	assert.NoError(t, service.SaveVideoFrame(testRecordingFrame("video-2", 1)))
+	assert.Contains(t, service.recordings, "video-1")
+}
+
+func TestSaveVideoFrame_StaleRecordingsStopOccupyingSlots(t *testing.T) {
+	// Arrange - una grabación cuyo cliente dejó de enviar frames
+	service, _, _ := newLimitedVideoService(t, 0)
+	service.SetMaxConcurrentRecordings(1)
+	service.SetRecordingStaleTimeout(time.Minute)
+	require.NoError(t, service.SaveVideoFrame(testRecordingFrame("video-1", 1)))
+	service.recordings["video-1"].lastFrameAt = time.Now().Add(-2 * time.Minute)
+
+	// Act
+	err := service.SaveVideoFrame(testRecordingFrame("video-2", 1))
+
+	// Assert - se olvida su progreso y la nueva ocupa el hueco
+	assert.NoError(t, err)
+	assert.NotContains(t, service.recordings, "video-1")
+	require.Len(t, service.ListActiveRecordings(), 1)
+	assert.Equal(t, "video-2", service.ListActiveRecordings()[0].VideoID)
+}
@@ -0,0 +1,88 @@
+package videoservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultRecordingCompactionInterval cada cuánto se compactan en hojas las grabaciones finalizadas
+const DefaultRecordingCompactionInterval = 30 * time.Second
+
+// pendingCompaction grabación finalizada que aún guarda sus frames en el formato de captura
+type pendingCompaction struct {
+	framesDir       string
+	framesPerSecond float64
+}
+
+// IRecordingCompactionService compacta en segundo plano las grabaciones finalizadas (modo FrameStorageSprites)
+type IRecordingCompactionService interface {
+	CompactPendingRecordings(ctx context.Context) (int, error)
+}
+
+// queueCompaction deja la grabación pendiente de compactar; en los formatos que no se compactan no hace nada
+func (vs *videoService) queueCompaction(videoID, framesDir string, framesPerSecond float64) {
+	if store, ok := vs.frameStore.(*frameStore); ok && store.format != FrameStorageSprites {
+		return
+	}
+
+	vs.compactionMutex.Lock()
+	defer vs.compactionMutex.Unlock()
+
+	if vs.pendingCompactions == nil {
+		vs.pendingCompactions = make(map[string]pendingCompaction)
+	}
+	vs.pendingCompactions[videoID] = pendingCompaction{framesDir: framesDir, framesPerSecond: framesPerSecond}
+}
+
+// CompactPendingRecordings agrupa en hojas las grabaciones finalizadas desde la última ejecución y actualiza su
+// tamaño en BD; retorna cuántas compactó. Una grabación que falla conserva sus frames originales y no se reintenta.
+// Las pendientes se pierden con un reinicio: siguen siendo legibles en su formato de captura.
+func (vs *videoService) CompactPendingRecordings(ctx context.Context) (int, error) {
+	vs.compactionMutex.Lock()
+	pending := vs.pendingCompactions
+	vs.pendingCompactions = nil
+	vs.compactionMutex.Unlock()
+
+	compacted := 0
+	for videoID, recording := range pending {
+		if err := ctx.Err(); err != nil {
+			// Lo que no dio tiempo a compactar queda para la siguiente ejecución
+			vs.requeueCompactions(pending)
+			return compacted, err
+		}
+		delete(pending, videoID)
+
+		if err := vs.frameStore.CompactRecording(recording.framesDir, recording.framesPerSecond); err != nil {
+			fmt.Printf("Warning: no se pudo compactar la grabación %s en hojas: %v\n", videoID, err)
+			continue
+		}
+		compacted++
+
+		video, err := vs.videoRepository.FindByID(ctx, videoID)
+		if err != nil || video == nil {
+			// La grabación pudo borrarse mientras esperaba; las hojas se van con su directorio
+			continue
+		}
+		video.SetFileSizeMB(vs.calculateFramesDirSize(recording.framesDir))
+		if err := vs.videoRepository.Update(ctx, video); err != nil {
+			fmt.Printf("Warning: no se pudo actualizar el tamaño de la grabación compactada %s: %v\n", videoID, err)
+		}
+	}
+	return compacted, nil
+}
+
+// requeueCompactions devuelve a la cola las grabaciones que quedaron sin compactar
+func (vs *videoService) requeueCompactions(pending map[string]pendingCompaction) {
+	vs.compactionMutex.Lock()
+	defer vs.compactionMutex.Unlock()
+
+	if vs.pendingCompactions == nil {
+		vs.pendingCompactions = make(map[string]pendingCompaction)
+	}
+	for videoID, recording := range pending {
+		if _, exists := vs.pendingCompactions[videoID]; !exists {
+			vs.pendingCompactions[videoID] = recording
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package videoservice
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+func TestCompactPendingRecordings_CompactsFinalizedRecordingInBackground(t *testing.T) {
+	// Arrange
+	videoRepo := new(MockSessionVideoRepository)
+	actionLog := new(MockActionLogService)
+	service := NewVideoService(videoRepo, nil, nil, actionLog, FrameStorageSprites, 100, DefaultPartialRecordingPolicy).(*videoService)
+	service.framesBaseDir = t.TempDir()
+	framesDir := filepath.Join(service.framesBaseDir, testVideoID, "frames")
+
+	videoRepo.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+	actionLog.On("LogAction", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	for i := 1; i <= 3; i++ {
+		frame := testFrameInfo(i)
+		frame.FrameData = testGradientJPEG(t, 32, 32, i)
+		require.NoError(t, service.SaveVideoFrame(frame))
+	}
+	require.NoError(t, service.FinalizeVideoRecording(VideoRecordingMetadata{
+		VideoID:     testVideoID,
+		SessionID:   testSessionID,
+		TotalFrames: 3,
+		CompletedAt: time.Now(),
+	}))
+	require.False(t, isSpriteRecording(framesDir), "la finalización no debe esperar a la compactación")
+
+	video := sessionvideo.NewSessionVideoFromDB(testVideoID, framesDir, 1, time.Now(), testSessionID, 1, time.Now(), time.Now())
+	videoRepo.On("FindByID", mock.Anything, testVideoID).Return(video, nil).Once()
+	videoRepo.On("Update", mock.Anything, video).Return(nil).Once()
+
+	// Act
+	compacted, err := service.CompactPendingRecordings(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, compacted)
+	assert.True(t, isSpriteRecording(framesDir))
+	count, err := service.CountVideoFrames(framesDir)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	videoRepo.AssertExpectations(t)
+
+	compacted, err = service.CompactPendingRecordings(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, compacted)
+}
@@ -0,0 +1,70 @@
+package videoservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+)
+
+// ErrRecordingEmpty el directorio de frames existe pero no contiene ningún frame válido; la grabación no se guarda
+var ErrRecordingEmpty = errors.New("la grabación no contiene frames válidos")
+
+// recordingAuditFallbackUserID usuario de auditoría cuando no se puede resolver el administrador de la sesión
+const recordingAuditFallbackUserID = "admin-000-000-000-000000000001"
+
+// sessionAdminUserID administrador de la sesión grabada; sin repositorio o sin sesión se usa el de respaldo
+func (vs *videoService) sessionAdminUserID(ctx context.Context, sessionID string) string {
+	if vs.sessionRepository == nil || sessionID == "" {
+		return recordingAuditFallbackUserID
+	}
+	session, err := vs.sessionRepository.FindById(ctx, sessionID)
+	if err != nil || session == nil {
+		fmt.Printf("Warning: no se pudo resolver el administrador de la sesión %s para auditoría: %v\n", sessionID, err)
+		return recordingAuditFallbackUserID
+	}
+	return session.AdminUserID()
+}
+
+// discardEmptyRecording comprueba que la grabación tiene algún frame válido. Si no lo tiene (el cliente se cayó antes
+// de enviar frames), elimina el directorio, registra recording_empty en auditoría y retorna ErrRecordingEmpty para
+// que no se cree un SessionVideo sin frames.
+func (vs *videoService) discardEmptyRecording(recordingInfo VideoRecordingMetadata, framesDir string) error {
+	hasFrames, err := vs.frameStore.HasValidFrame(framesDir)
+	if err != nil {
+		return fmt.Errorf("error comprobando los frames de la grabación %s: %w", recordingInfo.VideoID, err)
+	}
+	if hasFrames {
+		return nil
+	}
+
+	fmt.Printf("🎞️ recording_empty: la grabación %s de la sesión %s no tiene frames válidos, no se guarda\n",
+		recordingInfo.VideoID, recordingInfo.SessionID)
+	if err := os.RemoveAll(filepath.Join(vs.framesBaseDir, recordingInfo.VideoID)); err != nil {
+		fmt.Printf("Warning: no se pudo eliminar el directorio de la grabación vacía %s: %v\n", recordingInfo.VideoID, err)
+	}
+
+	ctx := context.Background()
+	entityType := "SESSION_VIDEO"
+	err = vs.actionLogService.LogAction(ctx, actionlog.ActionVideoRecordingEmpty,
+		fmt.Sprintf("Grabación sin frames descartada - VideoID: %s, Sesión: %s", recordingInfo.VideoID, recordingInfo.SessionID),
+		vs.sessionAdminUserID(ctx, recordingInfo.SessionID),
+		&recordingInfo.VideoID,
+		&entityType,
+		map[string]interface{}{
+			"event":                 "recording_empty",
+			"video_id":              recordingInfo.VideoID,
+			"session_id":            recordingInfo.SessionID,
+			"reported_total_frames": recordingInfo.TotalFrames,
+			"frames_dir":            framesDir,
+		})
+	if err != nil {
+		// Log pero no fallar
+		fmt.Printf("Warning: error registrando audit log para la grabación vacía %s: %v\n", recordingInfo.VideoID, err)
+	}
+
+	return fmt.Errorf("%w: %s", ErrRecordingEmpty, recordingInfo.VideoID)
+}
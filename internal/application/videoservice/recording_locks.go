@@ -0,0 +1,45 @@
+package videoservice
+
+import "sync"
+
+// recordingLock mutex de una grabación con el número de goroutines que lo usan o esperan
+type recordingLock struct {
+	mu      sync.Mutex
+	holders int
+}
+
+// recordingLocks serializa las escrituras por grabación sin bloquear grabaciones distintas. El valor cero está
+// listo para usarse.
+type recordingLocks struct {
+	mu    sync.Mutex
+	locks map[string]*recordingLock
+}
+
+// lock bloquea la grabación de framesDir y retorna la función que la libera.
+// El mutex se elimina del mapa cuando ya nadie lo usa para no acumular grabaciones terminadas.
+func (rl *recordingLocks) lock(framesDir string) func() {
+	rl.mu.Lock()
+	if rl.locks == nil {
+		rl.locks = make(map[string]*recordingLock)
+	}
+	lock, exists := rl.locks[framesDir]
+	if !exists {
+		lock = &recordingLock{}
+		rl.locks[framesDir] = lock
+	}
+	lock.holders++
+	rl.mu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		rl.mu.Lock()
+		lock.holders--
+		if lock.holders == 0 {
+			delete(rl.locks, framesDir)
+		}
+		rl.mu.Unlock()
+	}
+}
@@ -0,0 +1,49 @@
+package videoservice
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// recordingSessionFile archivo, junto al directorio de frames, con la sesión a la que quedó ligada la grabación.
+// Se guarda en disco para que la validación siga en pie tras un reinicio del servidor a mitad de grabación.
+const recordingSessionFile = "session_id"
+
+// recordingSessionPath ruta del archivo con la sesión ligada a la grabación
+func (vs *videoService) recordingSessionPath(videoID string) string {
+	return filepath.Join(vs.framesBaseDir, videoID, recordingSessionFile)
+}
+
+// boundRecordingSession sesión guardada en disco para la grabación; "" si todavía no está ligada
+func (vs *videoService) boundRecordingSession(videoID string) (string, error) {
+	data, err := os.ReadFile(vs.recordingSessionPath(videoID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("error leyendo la sesión de la grabación: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// bindRecordingSession liga la grabación a sessionID si aún no lo estaba y retorna la sesión ligada. Se llama
+// con recordingsMutex tomado, así que no compite con otro frame de la misma grabación.
+func (vs *videoService) bindRecordingSession(videoID, sessionID string) (string, error) {
+	bound, err := vs.boundRecordingSession(videoID)
+	if err != nil || bound != "" {
+		return bound, err
+	}
+
+	path := vs.recordingSessionPath(videoID)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(sessionID), 0644); err != nil {
+		return "", fmt.Errorf("error guardando la sesión de la grabación: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("error guardando la sesión de la grabación: %w", err)
+	}
+	return sessionID, nil
+}
@@ -0,0 +1,53 @@
+package videoservice
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+// RecordingStorageUsage espacio que ocupa una grabación de frames en disco
+type RecordingStorageUsage struct {
+	TotalBytes  int64
+	Format      FrameStorageFormat
+	MP4Exported bool
+	MP4Path     string
+}
+
+// GetRecordingStorageUsage calcula los bytes del directorio de frames de la grabación, su formato de
+// almacenamiento y si existe el MP4 procesado (videos/processed/<sessionID>_<videoID>.mp4)
+func (vs *videoService) GetRecordingStorageUsage(ctx context.Context, video *sessionvideo.SessionVideo) (*RecordingStorageUsage, error) {
+	framesDir := video.FilePath()
+
+	totalBytes, err := framesDirBytes(framesDir)
+	if err != nil {
+		return nil, fmt.Errorf("error calculando tamaño de la grabación %s: %w", video.VideoID(), err)
+	}
+
+	usage := &RecordingStorageUsage{
+		TotalBytes: totalBytes,
+		Format:     FrameStorageIndividual,
+	}
+	if isSpriteRecording(framesDir) {
+		usage.Format = FrameStorageSprites
+	} else if isPackedRecording(framesDir) {
+		usage.Format = FrameStoragePacked
+	}
+
+	if vs.fileStorage != nil {
+		mp4Path := processedVideoPath(video.AssociatedSessionID(), video.VideoID())
+		if vs.fileStorage.FileExists(ctx, mp4Path) {
+			usage.MP4Exported = true
+			usage.MP4Path = mp4Path
+		}
+	}
+
+	return usage, nil
+}
+
+// processedVideoPath ruta relativa del MP4 ensamblado a partir de los chunks subidos
+func processedVideoPath(sessionID, videoID string) string {
+	return filepath.Join("videos", "processed", fmt.Sprintf("%s_%s.mp4", sessionID, videoID))
+}
@@ -0,0 +1,193 @@
+package videoservice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"math"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+const (
+	// DefaultThumbnailCount miniaturas por grabación cuando no se indica cuántas
+	DefaultThumbnailCount = 10
+	// MaxThumbnailCount máximo de miniaturas por petición, para acotar la decodificación de frames
+	MaxThumbnailCount = 100
+
+	// thumbnailMaxWidth ancho máximo de cada miniatura; el alto mantiene la proporción del frame
+	thumbnailMaxWidth = 160
+	// thumbnailJPEGQuality calidad de las miniaturas y del montaje
+	thumbnailJPEGQuality = 75
+	// thumbnailCacheSize tiras de miniaturas en memoria; al superarse se descarta la más antigua
+	thumbnailCacheSize = 64
+)
+
+// ErrRecordingHasNoFrames la grabación no tiene frames de los que extraer miniaturas
+var ErrRecordingHasNoFrames = errors.New("recording has no frames")
+
+// RecordingThumbnail miniatura JPEG de un frame de la grabación
+type RecordingThumbnail struct {
+	FrameIndex int
+	Width      int
+	Height     int
+	Data       []byte
+}
+
+// RecordingThumbnails tira de miniaturas equiespaciadas de una grabación y su montaje (contact sheet) en
+// una sola imagen JPEG de Columns columnas, en el mismo orden
+type RecordingThumbnails struct {
+	VideoID     string
+	TotalFrames int
+	Thumbnails  []RecordingThumbnail
+	Montage     []byte
+	Columns     int
+}
+
+// IRecordingThumbnailService genera miniaturas de una grabación para revisarla sin cargar la reproducción
+type IRecordingThumbnailService interface {
+	GetRecordingThumbnails(ctx context.Context, video *sessionvideo.SessionVideo, count int) (*RecordingThumbnails, error)
+}
+
+// GetRecordingThumbnails extrae count frames equiespaciados de la grabación (el primero y el último incluidos),
+// los reduce a miniaturas y compone el montaje. El resultado se cachea por grabación, count y número de frames,
+// así que una grabación en curso se regenera cuando recibe frames nuevos.
+func (vs *videoService) GetRecordingThumbnails(ctx context.Context, video *sessionvideo.SessionVideo, count int) (*RecordingThumbnails, error) {
+	if count <= 0 || count > MaxThumbnailCount {
+		return nil, fmt.Errorf("número de miniaturas fuera de rango (1-%d): %d", MaxThumbnailCount, count)
+	}
+
+	framesDir := video.FilePath()
+	frameIndexes, err := vs.frameStore.FrameIndexes(framesDir)
+	if err != nil {
+		return nil, fmt.Errorf("error listando frames de la grabación %s: %w", video.VideoID(), err)
+	}
+	if len(frameIndexes) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrRecordingHasNoFrames, video.VideoID())
+	}
+
+	cacheKey := fmt.Sprintf("%s/%d/%d", video.VideoID(), count, len(frameIndexes))
+	if cached := vs.cachedThumbnails(cacheKey); cached != nil {
+		return cached, nil
+	}
+
+	sampled := sampleFrameIndexes(frameIndexes, count)
+	thumbnails := make([]RecordingThumbnail, 0, len(sampled))
+	images := make([]image.Image, 0, len(sampled))
+	for _, frameIndex := range sampled {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		data, err := vs.frameStore.ReadFrame(framesDir, frameIndex)
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo frame %d: %w", frameIndex, err)
+		}
+		thumbnail, img, err := encodeThumbnail(frameIndex, data)
+		if err != nil {
+			return nil, err
+		}
+		thumbnails = append(thumbnails, thumbnail)
+		images = append(images, img)
+	}
+
+	montage, columns, err := encodeThumbnailMontage(images)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RecordingThumbnails{
+		VideoID:     video.VideoID(),
+		TotalFrames: len(frameIndexes),
+		Thumbnails:  thumbnails,
+		Montage:     montage,
+		Columns:     columns,
+	}
+	vs.cacheThumbnails(cacheKey, result)
+	return result, nil
+}
+
+// sampleFrameIndexes elige count índices equiespaciados; con más miniaturas que frames se usan todos
+func sampleFrameIndexes(frameIndexes []int, count int) []int {
+	if count >= len(frameIndexes) {
+		return frameIndexes
+	}
+	if count == 1 {
+		return []int{frameIndexes[len(frameIndexes)/2]}
+	}
+
+	sampled := make([]int, count)
+	for i := range sampled {
+		sampled[i] = frameIndexes[i*(len(frameIndexes)-1)/(count-1)]
+	}
+	return sampled
+}
+
+// encodeThumbnail decodifica el frame y lo reduce a thumbnailMaxWidth de ancho como máximo
+func encodeThumbnail(frameIndex int, data []byte) (RecordingThumbnail, image.Image, error) {
+	src, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return RecordingThumbnail{}, nil, fmt.Errorf("error decodificando frame %d: %w", frameIndex, err)
+	}
+
+	width, height := FrameResolutionLimit{MaxWidth: thumbnailMaxWidth}.fit(src.Bounds().Dx(), src.Bounds().Dy())
+	img := downscaleBox(src, width, height)
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, img, &jpeg.Options{Quality: thumbnailJPEGQuality}); err != nil {
+		return RecordingThumbnail{}, nil, fmt.Errorf("error codificando miniatura del frame %d: %w", frameIndex, err)
+	}
+	return RecordingThumbnail{FrameIndex: frameIndex, Width: width, Height: height, Data: out.Bytes()}, img, nil
+}
+
+// encodeThumbnailMontage compone las miniaturas en una cuadrícula casi cuadrada, de izquierda a derecha y de
+// arriba abajo, y retorna el JPEG y el número de columnas
+func encodeThumbnailMontage(images []image.Image) ([]byte, int, error) {
+	tileWidth, tileHeight := 0, 0
+	for _, img := range images {
+		tileWidth = max(tileWidth, img.Bounds().Dx())
+		tileHeight = max(tileHeight, img.Bounds().Dy())
+	}
+
+	columns := int(math.Ceil(math.Sqrt(float64(len(images)))))
+	rows := (len(images) + columns - 1) / columns
+	canvas := image.NewRGBA(image.Rect(0, 0, columns*tileWidth, rows*tileHeight))
+	for i, img := range images {
+		origin := image.Pt((i%columns)*tileWidth, (i/columns)*tileHeight)
+		draw.Draw(canvas, image.Rectangle{Min: origin, Max: origin.Add(img.Bounds().Size())}, img, img.Bounds().Min, draw.Src)
+	}
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, canvas, &jpeg.Options{Quality: thumbnailJPEGQuality}); err != nil {
+		return nil, 0, fmt.Errorf("error codificando montaje de miniaturas: %w", err)
+	}
+	return out.Bytes(), columns, nil
+}
+
+// cachedThumbnails retorna la tira cacheada o nil
+func (vs *videoService) cachedThumbnails(key string) *RecordingThumbnails {
+	vs.thumbnailMutex.Lock()
+	defer vs.thumbnailMutex.Unlock()
+	return vs.thumbnailCache[key]
+}
+
+// cacheThumbnails guarda la tira y descarta la más antigua si la caché está llena
+func (vs *videoService) cacheThumbnails(key string, thumbnails *RecordingThumbnails) {
+	vs.thumbnailMutex.Lock()
+	defer vs.thumbnailMutex.Unlock()
+
+	if vs.thumbnailCache == nil {
+		vs.thumbnailCache = make(map[string]*RecordingThumbnails)
+	}
+	if _, exists := vs.thumbnailCache[key]; !exists {
+		vs.thumbnailOrder = append(vs.thumbnailOrder, key)
+	}
+	vs.thumbnailCache[key] = thumbnails
+	for len(vs.thumbnailOrder) > thumbnailCacheSize {
+		delete(vs.thumbnailCache, vs.thumbnailOrder[0])
+		vs.thumbnailOrder = vs.thumbnailOrder[1:]
+	}
+}
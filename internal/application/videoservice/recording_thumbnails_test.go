@@ -0,0 +1,84 @@
+package videoservice
+
+import (
+	"bytes"
+	"context"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+// newThumbnailRecording graba frames 640x360 en un directorio y retorna el servicio y la grabación
+func newThumbnailRecording(t *testing.T, format FrameStorageFormat, frames int) (*videoService, *sessionvideo.SessionVideo) {
+	t.Helper()
+
+	service, _, _ := newUploadVideoService(t)
+	service.frameStore = NewFrameStore(format)
+	framesDir := t.TempDir()
+	for i := 0; i < frames; i++ {
+		require.NoError(t, service.frameStore.WriteFrame(framesDir, i, testGradientJPEG(t, 640, 360, i)))
+	}
+	return service, sessionvideo.NewSessionVideo(framesDir, frames, testSessionID, 1)
+}
+
+func TestGetRecordingThumbnails_SamplesRequestedNumberOfEvenlySpacedFrames(t *testing.T) {
+	// Arrange
+	service, video := newThumbnailRecording(t, FrameStorageIndividual, 25)
+
+	// Act
+	result, err := service.GetRecordingThumbnails(context.Background(), video, 5)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 25, result.TotalFrames)
+	require.Len(t, result.Thumbnails, 5)
+	frameIndexes := make([]int, 0, len(result.Thumbnails))
+	for _, thumbnail := range result.Thumbnails {
+		frameIndexes = append(frameIndexes, thumbnail.FrameIndex)
+		img, err := jpeg.Decode(bytes.NewReader(thumbnail.Data))
+		require.NoError(t, err)
+		assert.Equal(t, 160, img.Bounds().Dx())
+		assert.Equal(t, 90, img.Bounds().Dy())
+	}
+	assert.Equal(t, []int{0, 6, 12, 18, 24}, frameIndexes)
+
+	// Montaje de 3 columnas × 2 filas
+	montage, err := jpeg.Decode(bytes.NewReader(result.Montage))
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Columns)
+	assert.Equal(t, 480, montage.Bounds().Dx())
+	assert.Equal(t, 180, montage.Bounds().Dy())
+}
+
+func TestGetRecordingThumbnails_CachesUntilRecordingGetsNewFrames(t *testing.T) {
+	// Arrange
+	service, video := newThumbnailRecording(t, FrameStoragePacked, 4)
+	first, err := service.GetRecordingThumbnails(context.Background(), video, 10)
+	require.NoError(t, err)
+
+	// Act
+	cached, err := service.GetRecordingThumbnails(context.Background(), video, 10)
+	require.NoError(t, err)
+	require.NoError(t, service.frameStore.WriteFrame(video.FilePath(), 4, testGradientJPEG(t, 640, 360, 4)))
+	regenerated, err := service.GetRecordingThumbnails(context.Background(), video, 10)
+	require.NoError(t, err)
+
+	// Assert - con menos frames que miniaturas se usan todos
+	assert.Len(t, first.Thumbnails, 4)
+	assert.Same(t, first, cached)
+	assert.Len(t, regenerated.Thumbnails, 5)
+}
+
+func TestGetRecordingThumbnails_RecordingWithoutFrames(t *testing.T) {
+	// Arrange
+	service, video := newThumbnailRecording(t, FrameStorageIndividual, 0)
+
+	// Act
+	_, err := service.GetRecordingThumbnails(context.Background(), video, 5)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrRecordingHasNoFrames)
+}
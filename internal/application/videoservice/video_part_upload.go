@@ -0,0 +1,302 @@
+package videoservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// partUploadManifestFileName datos de una subida HTTP por partes, junto a los bytes recibidos
+	partUploadManifestFileName = "part_upload.json"
+	// partUploadDataFileName bytes contiguos recibidos hasta ahora; su tamaño es el offset desde el que se reanuda
+	partUploadDataFileName = "part_upload.bin"
+)
+
+var (
+	// ErrUploadOffsetMismatch la parte no empieza donde terminan los bytes ya recibidos
+	ErrUploadOffsetMismatch = errors.New("upload offset mismatch")
+	// ErrUploadSizeExceeded la parte supera el tamaño total declarado de la grabación
+	ErrUploadSizeExceeded = errors.New("upload exceeds declared file size")
+	// ErrUploadParametersMismatch la subida en curso se inició con otra sesión, PC o tamaño
+	ErrUploadParametersMismatch = errors.New("upload parameters do not match upload in progress")
+)
+
+// UploadPart parte de una grabación subida por HTTP. Offset es la posición en bytes de Data dentro del archivo.
+type UploadPart struct {
+	VideoID   string
+	SessionID string
+	PCID      string
+	// UserID usuario dueño del PC, autenticado con el token del cliente
+	UserID   string
+	FileSize int64
+	Duration int
+	Offset   int64
+	Data     io.Reader
+}
+
+// PartUploadProgress estado de una subida por partes: el cliente reanuda enviando desde ReceivedBytes
+type PartUploadProgress struct {
+	VideoID       string `json:"video_id"`
+	SessionID     string `json:"session_id"`
+	PCID          string `json:"pc_id"`
+	FileSize      int64  `json:"file_size"`
+	ReceivedBytes int64  `json:"received_bytes"`
+	IsComplete    bool   `json:"is_complete"`
+	FilePath      string `json:"file_path,omitempty"`
+}
+
+// partUploadManifest datos persistidos de la subida por partes; los bytes recibidos se deducen del archivo de datos
+type partUploadManifest struct {
+	SessionID string    `json:"session_id"`
+	PCID      string    `json:"pc_id"`
+	FileSize  int64     `json:"file_size"`
+	Duration  int       `json:"duration"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetPartUploadProgress informa de cuántos bytes tiene el servidor de una subida por partes, también tras un
+// reinicio. ErrVideoUploadNotFound si no hay subida en curso ni completada recientemente.
+func (vs *videoService) GetPartUploadProgress(videoID string) (*PartUploadProgress, error) {
+	vs.uploadMutex.Lock()
+	defer vs.uploadMutex.Unlock()
+
+	return vs.partUploadProgress(videoID)
+}
+
+// partUploadProgress estado de la subida; requiere uploadMutex
+func (vs *videoService) partUploadProgress(videoID string) (*PartUploadProgress, error) {
+	if completed, exists := vs.completedUploads[videoID]; exists {
+		if completed.partUpload != nil {
+			progress := *completed.partUpload
+			return &progress, nil
+		}
+		// Grabación ya subida por chunks: no admite más partes, pero el cliente sabe que está completa
+		return &PartUploadProgress{
+			VideoID:       videoID,
+			FileSize:      completed.result.FileSize,
+			ReceivedBytes: completed.result.FileSize,
+			IsComplete:    true,
+			FilePath:      completed.result.FilePath,
+		}, nil
+	}
+
+	uploadDir, err := vs.uploadDir(videoID)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(filepath.Join(uploadDir, partUploadManifestFileName))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrVideoUploadNotFound, videoID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo manifiesto de subida: %w", err)
+	}
+	var manifest partUploadManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("manifiesto de subida inválido: %w", err)
+	}
+
+	var received int64
+	if info, err := os.Stat(filepath.Join(uploadDir, partUploadDataFileName)); err == nil {
+		received = info.Size()
+	}
+	return &PartUploadProgress{
+		VideoID:       videoID,
+		SessionID:     manifest.SessionID,
+		PCID:          manifest.PCID,
+		FileSize:      manifest.FileSize,
+		ReceivedBytes: received,
+	}, nil
+}
+
+// WriteUploadPart añade una parte a la subida HTTP de una grabación y, con la última, la persiste con
+// FinalizeVideoUpload. Una parte que no empieza justo donde terminan los bytes recibidos se rechaza antes de
+// leerla, con ErrUploadOffsetMismatch junto al estado actual para que el cliente reanude desde ReceivedBytes.
+// Si encaja se recibe en un temporal, sin bloquear otras subidas, y el offset se comprueba de nuevo antes de
+// añadirla por si entretanto llegó otra parte. Reenviar una parte ya añadida no la duplica.
+func (vs *videoService) WriteUploadPart(ctx context.Context, part UploadPart) (*PartUploadProgress, error) {
+	if part.FileSize <= 0 {
+		return nil, fmt.Errorf("%w: file size must be positive", ErrUploadSizeExceeded)
+	}
+	uploadDir, err := vs.uploadDir(part.VideoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+		return nil, fmt.Errorf("error creando directorio de subida: %w", err)
+	}
+
+	vs.uploadMutex.Lock()
+	progress, err := vs.uploadPartPosition(uploadDir, part)
+	vs.uploadMutex.Unlock()
+	if err != nil || progress.IsComplete {
+		return progress, err
+	}
+
+	partPath, partSize, err := receiveUploadPart(uploadDir, part)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(partPath)
+
+	vs.uploadMutex.Lock()
+	defer vs.uploadMutex.Unlock()
+
+	progress, err = vs.uploadPartPosition(uploadDir, part)
+	if err != nil || progress.IsComplete {
+		return progress, err
+	}
+	if part.Offset+partSize > part.FileSize {
+		return progress, fmt.Errorf("%w: %d bytes at offset %d, file size %d", ErrUploadSizeExceeded, partSize, part.Offset, part.FileSize)
+	}
+
+	if err := appendUploadPart(filepath.Join(uploadDir, partUploadDataFileName), partPath, progress.ReceivedBytes); err != nil {
+		return nil, err
+	}
+	progress.ReceivedBytes += partSize
+
+	if progress.ReceivedBytes < progress.FileSize {
+		return progress, nil
+	}
+	return vs.completePartUpload(ctx, uploadDir, part, progress)
+}
+
+// uploadPartPosition estado de la subida a la que va la parte, iniciándola si es la primera, y comprueba que la
+// parte encaja: misma sesión, PC y tamaño, y offset igual a los bytes recibidos. Si la grabación ya se completó
+// retorna su estado con IsComplete. Requiere uploadMutex.
+func (vs *videoService) uploadPartPosition(uploadDir string, part UploadPart) (*PartUploadProgress, error) {
+	progress, err := vs.partUploadProgress(part.VideoID)
+	switch {
+	case errors.Is(err, ErrVideoUploadNotFound):
+		progress, err = vs.startPartUpload(uploadDir, part)
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case progress.SessionID != part.SessionID || progress.PCID != part.PCID || progress.FileSize != part.FileSize:
+		return progress, fmt.Errorf("%w: %s", ErrUploadParametersMismatch, part.VideoID)
+	case progress.IsComplete:
+		// Reenvío de la última parte: la grabación ya está persistida
+		vs.removeUploadDir(part.VideoID)
+		return progress, nil
+	}
+
+	if part.Offset != progress.ReceivedBytes {
+		return progress, fmt.Errorf("%w: expected offset %d, got %d", ErrUploadOffsetMismatch, progress.ReceivedBytes, part.Offset)
+	}
+	return progress, nil
+}
+
+// receiveUploadPart copia los bytes de la parte a un temporal y retorna su ruta y tamaño
+func receiveUploadPart(uploadDir string, part UploadPart) (string, int64, error) {
+	partFile, err := os.CreateTemp(uploadDir, "part-*.tmp")
+	if err != nil {
+		return "", 0, fmt.Errorf("error creando temporal de la parte: %w", err)
+	}
+	// Como mucho un byte más de lo que cabe, para detectar una parte demasiado grande sin copiarla entera
+	limit := part.FileSize - part.Offset + 1
+	if limit < 1 {
+		limit = 1
+	}
+	size, err := io.Copy(partFile, io.LimitReader(part.Data, limit))
+	closeErr := partFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(partFile.Name())
+		return "", 0, fmt.Errorf("error recibiendo la parte en el offset %d: %w", part.Offset, err)
+	}
+	return partFile.Name(), size, nil
+}
+
+// startPartUpload guarda el manifiesto de una subida nueva; requiere uploadMutex
+func (vs *videoService) startPartUpload(uploadDir string, part UploadPart) (*PartUploadProgress, error) {
+	raw, err := json.Marshal(partUploadManifest{
+		SessionID: part.SessionID,
+		PCID:      part.PCID,
+		FileSize:  part.FileSize,
+		Duration:  part.Duration,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error serializando manifiesto de subida: %w", err)
+	}
+	if err := writeFileAtomically(filepath.Join(uploadDir, partUploadManifestFileName), raw); err != nil {
+		return nil, err
+	}
+	return &PartUploadProgress{VideoID: part.VideoID, SessionID: part.SessionID, PCID: part.PCID, FileSize: part.FileSize}, nil
+}
+
+// appendUploadPart añade el temporal al final de los datos; si falla, recorta los datos al tamaño previo para
+// que el offset de reanudación siga siendo válido
+func appendUploadPart(dataPath, partPath string, previousSize int64) error {
+	source, err := os.Open(partPath)
+	if err != nil {
+		return fmt.Errorf("error abriendo la parte recibida: %w", err)
+	}
+	defer source.Close()
+
+	data, err := os.OpenFile(dataPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("error abriendo datos de la subida: %w", err)
+	}
+	_, err = io.Copy(data, source)
+	closeErr := data.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Truncate(dataPath, previousSize)
+		return fmt.Errorf("error añadiendo la parte a la subida: %w", err)
+	}
+	return nil
+}
+
+// completePartUpload mueve la grabación al almacenamiento y la registra como SessionVideo; requiere uploadMutex.
+// Si falla, los datos se conservan y una parte vacía en el offset final reintenta la finalización.
+func (vs *videoService) completePartUpload(ctx context.Context, uploadDir string, part UploadPart, progress *PartUploadProgress) (*PartUploadProgress, error) {
+	raw, err := os.ReadFile(filepath.Join(uploadDir, partUploadManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo manifiesto de subida: %w", err)
+	}
+	var manifest partUploadManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("manifiesto de subida inválido: %w", err)
+	}
+
+	finalPath, err := vs.fileStorage.MoveFile(ctx, filepath.Join(uploadDir, partUploadDataFileName), processedVideoPath(part.SessionID, part.VideoID))
+	if err != nil {
+		return nil, fmt.Errorf("error guardando video completo: %w", err)
+	}
+	fileSizeMB := float64(progress.FileSize) / (1024 * 1024)
+	if _, err := vs.FinalizeVideoUpload(ctx, part.SessionID, part.VideoID, finalPath, fileSizeMB, manifest.Duration); err != nil {
+		return nil, fmt.Errorf("error finalizando upload: %w", err)
+	}
+
+	progress.IsComplete = true
+	progress.FilePath = finalPath
+	vs.removeUploadDir(part.VideoID)
+
+	completed := *progress
+	vs.rememberCompletedUpload(part.VideoID, &completedUpload{
+		result: &VideoUploadResult{
+			IsComplete:      true,
+			ProgressPercent: 100.0,
+			FilePath:        finalPath,
+			Duration:        manifest.Duration,
+			FileSize:        progress.FileSize,
+		},
+		partUpload: &completed,
+		pcID:       part.PCID,
+		userID:     part.UserID,
+	})
+	return progress, nil
+}
@@ -0,0 +1,173 @@
+package videoservice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPartPCID = "pc-001"
+
+// testUploadPart parte de un video de 9 bytes ("012345678") que empieza en offset
+func testUploadPart(offset int64, data string) UploadPart {
+	return UploadPart{
+		VideoID:   testVideoID,
+		SessionID: testSessionID,
+		PCID:      testPartPCID,
+		FileSize:  9,
+		Duration:  30,
+		Offset:    offset,
+		Data:      bytes.NewReader([]byte(data)),
+	}
+}
+
+func TestWriteUploadPart_MultiPartUploadFinalizesVideo(t *testing.T) {
+	// Arrange
+	service, storage, videoRepo := newUploadVideoService(t)
+	ctx := context.Background()
+
+	// Act
+	first, err := service.WriteUploadPart(ctx, testUploadPart(0, "012"))
+	require.NoError(t, err)
+	second, err := service.WriteUploadPart(ctx, testUploadPart(3, "345"))
+	require.NoError(t, err)
+	last, err := service.WriteUploadPart(ctx, testUploadPart(6, "678"))
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, int64(3), first.ReceivedBytes)
+	assert.False(t, first.IsComplete)
+	assert.Equal(t, int64(6), second.ReceivedBytes)
+	assert.True(t, last.IsComplete)
+	assert.Equal(t, int64(9), last.ReceivedBytes)
+	assert.Equal(t, []byte("012345678"), storage.saved[last.FilePath])
+	videoRepo.AssertNumberOfCalls(t, "Save", 1)
+	_, err = os.Stat(filepath.Join(service.uploadsBaseDir, testVideoID))
+	assert.True(t, os.IsNotExist(err))
+
+	// Reenviar la última parte no vuelve a persistir la grabación
+	again, err := service.WriteUploadPart(ctx, testUploadPart(6, "678"))
+	require.NoError(t, err)
+	assert.True(t, again.IsComplete)
+	videoRepo.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestWriteUploadPart_ResumesAfterFailedPartAndRestart(t *testing.T) {
+	// Arrange - el primer servidor recibe la primera parte; la segunda se corta y el servidor se reinicia
+	service, _, _ := newUploadVideoService(t)
+	ctx := context.Background()
+	_, err := service.WriteUploadPart(ctx, testUploadPart(0, "0123"))
+	require.NoError(t, err)
+
+	failed := testUploadPart(4, "")
+	failed.Data = &failingReader{data: []byte("45"), err: errors.New("connection reset")}
+	_, err = service.WriteUploadPart(ctx, failed)
+	require.Error(t, err)
+
+	restarted, storage, videoRepo := newUploadVideoService(t)
+	restarted.uploadsBaseDir = service.uploadsBaseDir
+
+	// Act - el cliente consulta desde dónde reanudar y envía el resto
+	progress, err := restarted.GetPartUploadProgress(testVideoID)
+	require.NoError(t, err)
+	_, mismatchErr := restarted.WriteUploadPart(ctx, testUploadPart(6, "678"))
+	result, err := restarted.WriteUploadPart(ctx, testUploadPart(progress.ReceivedBytes, "45678"))
+
+	// Assert - la parte cortada no dejó bytes a medias y una parte fuera de orden se rechaza
+	assert.Equal(t, int64(4), progress.ReceivedBytes)
+	assert.Equal(t, testSessionID, progress.SessionID)
+	assert.ErrorIs(t, mismatchErr, ErrUploadOffsetMismatch)
+	require.NoError(t, err)
+	assert.True(t, result.IsComplete)
+	assert.Equal(t, []byte("012345678"), storage.saved[result.FilePath])
+	videoRepo.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestWriteUploadPart_RejectsPartBeyondDeclaredSize(t *testing.T) {
+	// Arrange
+	service, _, videoRepo := newUploadVideoService(t)
+
+	// Act
+	progress, err := service.WriteUploadPart(context.Background(), testUploadPart(0, "0123456789"))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrUploadSizeExceeded)
+	assert.Equal(t, int64(0), progress.ReceivedBytes)
+	videoRepo.AssertNotCalled(t, "Save")
+}
+
+func TestWriteUploadPart_RejectsDifferentSession(t *testing.T) {
+	// Arrange
+	service, _, _ := newUploadVideoService(t)
+	_, err := service.WriteUploadPart(context.Background(), testUploadPart(0, "012"))
+	require.NoError(t, err)
+
+	other := testUploadPart(3, "345")
+	other.SessionID = "other-session"
+
+	// Act
+	_, err = service.WriteUploadPart(context.Background(), other)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrUploadParametersMismatch)
+}
+
+func TestWriteUploadPart_RejectsMisplacedPartWithoutReadingIt(t *testing.T) {
+	// Arrange
+	service, _, _ := newUploadVideoService(t)
+	_, err := service.WriteUploadPart(context.Background(), testUploadPart(0, "012"))
+	require.NoError(t, err)
+
+	misplaced := testUploadPart(6, "")
+	reader := &countingReader{data: []byte("678")}
+	misplaced.Data = reader
+
+	// Act
+	progress, err := service.WriteUploadPart(context.Background(), misplaced)
+
+	// Assert - ni se leyó el cuerpo ni quedó ningún temporal de la parte
+	assert.ErrorIs(t, err, ErrUploadOffsetMismatch)
+	assert.Equal(t, int64(3), progress.ReceivedBytes)
+	assert.Zero(t, reader.reads)
+	temps, err := filepath.Glob(filepath.Join(service.uploadsBaseDir, testVideoID, "part-*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, temps)
+}
+
+// countingReader cuenta las lecturas, para comprobar que una parte rechazada no se llega a leer
+type countingReader struct {
+	data  []byte
+	reads int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.reads++
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// failingReader entrega data y después falla, como una conexión que se corta a mitad de la parte
+type failingReader struct {
+	data []byte
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
@@ -2,17 +2,34 @@ package videoservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
 )
 
+// DefaultMaxFramesPerRecording límite de frames por grabación (1 hora a 30 FPS)
+const DefaultMaxFramesPerRecording = 108000
+
+// DefaultMaxClockSkew diferencia máxima tolerada entre el reloj del cliente y el del servidor
+const DefaultMaxClockSkew = 30 * time.Second
+
+// ErrFrameLimitReached se retorna cuando una grabación alcanzó el máximo de frames y ya no acepta más
+var ErrFrameLimitReached = errors.New("límite de frames por grabación alcanzado")
+
+// ErrVideoSessionMismatch se retorna cuando un frame o la finalización de una grabación indican una sesión
+// distinta de aquella a la que quedó ligado el VideoID con su primer frame
+var ErrVideoSessionMismatch = errors.New("la grabación pertenece a otra sesión")
+
 // VideoChunk representa un chunk de video recibido
 type VideoChunk struct {
 	SessionID   string `json:"session_id"`
@@ -23,6 +40,9 @@ type VideoChunk struct {
 	Duration    int    `json:"duration"`
 	FileName    string `json:"file_name"`
 	ChunkIndex  int    `json:"chunk_index"`
+	// PCID y UserID PC que envía el chunk y su usuario; la subida pertenece a los que enviaron el primero
+	PCID   string `json:"pc_id"`
+	UserID string `json:"user_id"`
 }
 
 // VideoUploadResult representa el resultado del procesamiento de chunks
@@ -34,12 +54,16 @@ type VideoUploadResult struct {
 	FilePath        string  `json:"file_path,omitempty"`
 	Duration        int     `json:"duration,omitempty"`
 	FileSize        int64   `json:"file_size,omitempty"`
+	// AlreadyCompleted indica que el video ya se había persistido antes (p. ej. por el último chunk)
+	AlreadyCompleted bool `json:"already_completed,omitempty"`
 }
 
 // VideoUploadSession representa una sesión de subida en progreso
 type VideoUploadSession struct {
 	VideoID        string
 	SessionID      string
+	PCID           string
+	UserID         string
 	FileName       string
 	FileSize       int64
 	Duration       int
@@ -68,11 +92,46 @@ type VideoRecordingMetadata struct {
 	FPS             float64   `json:"fps"`
 	DurationSeconds float64   `json:"duration_seconds"`
 	CompletedAt     time.Time `json:"completed_at"`
+	// ClientTimestamp momento de finalización según el reloj del cliente (Unix en segundos o milisegundos)
+	ClientTimestamp int64 `json:"client_timestamp,omitempty"`
+}
+
+// recordingProgress lleva la cuenta de frames aceptados de una grabación en curso
+type recordingProgress struct {
+	// sessionID sesión a la que quedó ligada la grabación con su primer frame
+	sessionID    string
+	startedAt    time.Time
+	frames       int
+	firstFrameAt time.Time
+	lastFrameAt  time.Time
+	limitReached bool
+	// releasedAt momento en que terminó la sesión o se desconectó el PC sin finalizar la grabación
+	releasedAt time.Time
+	// clockSkewed indica que algún frame llegó con un timestamp del cliente fuera de la tolerancia
+	clockSkewed bool
+	// frameTimes instante de captura de cada frame aceptado, para medir los FPS reales
+	frameTimes map[int]time.Time
+}
+
+// frameTimesSnapshot copia de los instantes de captura; requiere recordingsMutex
+func (p *recordingProgress) frameTimesSnapshot() map[int]time.Time {
+	if p == nil {
+		return nil
+	}
+	snapshot := make(map[int]time.Time, len(p.frameTimes))
+	for frameIndex, capturedAt := range p.frameTimes {
+		snapshot[frameIndex] = capturedAt
+	}
+	return snapshot
 }
 
 // IVideoService define la interfaz del servicio de video
 type IVideoService interface {
 	HandleUploadedVideoChunk(chunk VideoChunk) (*VideoUploadResult, error)
+	CompleteVideoUpload(videoID, pcID, userID string) (*VideoUploadResult, error)
+	GetVideoUploadStatus(videoID, pcID string) (*VideoUploadStatus, error)
+	WriteUploadPart(ctx context.Context, part UploadPart) (*PartUploadProgress, error)
+	GetPartUploadProgress(videoID string) (*PartUploadProgress, error)
 	FinalizeVideoUpload(ctx context.Context, sessionID, videoID, tempFilePath string, fileSizeMB float64, duration int) (*sessionvideo.SessionVideo, error)
 	GetVideosBySessionID(ctx context.Context, sessionID string) ([]*sessionvideo.SessionVideo, error)
 	GetVideoByID(ctx context.Context, videoID string) (*sessionvideo.SessionVideo, error)
@@ -82,6 +141,19 @@ type IVideoService interface {
 	// Nuevos métodos para el sistema de frames individuales
 	SaveVideoFrame(frameInfo VideoFrameInfo) error
 	FinalizeVideoRecording(recordingInfo VideoRecordingMetadata) error
+	GetVideoFrame(framesDir string, frameNumber int) ([]byte, error)
+	CountVideoFrames(framesDir string) (int, error)
+	GetFrameCadence(framesDir string) (*FrameCadence, error)
+	GetRecordingStorageUsage(ctx context.Context, video *sessionvideo.SessionVideo) (*RecordingStorageUsage, error)
+
+	// ApplyPartialRecordingPolicy conserva o descarta las grabaciones en curso de una sesión rechazada o fallida
+	ApplyPartialRecordingPolicy(ctx context.Context, sessionID, adminUserID string, endStatus remotesession.SessionStatus) ([]PartialRecordingDisposition, error)
+
+	// Grabaciones en curso (con frames recibidos y sin finalizar)
+	IsSessionRecording(sessionID string) bool
+	ListActiveRecordings() []ActiveRecording
+	// ReleaseSessionRecordings deja de contar como en curso las grabaciones de una sesión terminada o desconectada
+	ReleaseSessionRecordings(sessionID string) int
 }
 
 // videoService implementa IVideoService
@@ -89,23 +161,69 @@ type videoService struct {
 	videoRepository  interfaces.ISessionVideoRepository
 	fileStorage      interfaces.IFileStorage
 	actionLogService actionlogservice.IActionLogService
-
-	// Mapa para tracking de uploads en progreso
-	uploadSessions map[string]*VideoUploadSession
-	uploadMutex    sync.RWMutex
+	frameStore       IFrameStore
+	// Opcional: al finalizar una grabación se enlaza en remote_sessions.session_video_id (nil = sin enlace)
+	sessionRepository interfaces.IRemoteSessionRepository
+
+	// Límite de frames por grabación y progreso de las grabaciones en curso
+	maxFramesPerRecording int
+	// maxConcurrentRecordings máximo de grabaciones en curso a la vez (cero = sin límite)
+	maxConcurrentRecordings int
+	// recordingStaleTimeout tiempo sin frames tras el que se olvida una grabación no finalizada
+	recordingStaleTimeout time.Duration
+	maxClockSkew            time.Duration
+	framesBaseDir           string
+	// Qué hacer con los frames ya capturados cuando la sesión termina sin éxito
+	partialPolicy   PartialRecordingPolicy
+	recordings      map[string]*recordingProgress
+	recordingsMutex sync.Mutex
+
+	// Mapa para tracking de uploads en progreso y de los ya completados (para video_upload_complete).
+	// Los chunks de las subidas en curso también se guardan en uploadsBaseDir para reanudarlas tras un reinicio.
+	uploadsBaseDir   string
+	uploadSessions   map[string]*VideoUploadSession
+	completedUploads map[string]*completedUpload
+	uploadMutex      sync.RWMutex
+
+	// Miniaturas generadas por grabación, count y número de frames; la más antigua se descarta primero
+	thumbnailCache map[string]*RecordingThumbnails
+	thumbnailOrder []string
+	thumbnailMutex sync.Mutex
+
+	// Grabaciones finalizadas pendientes de compactar en hojas, con sus FPS; las vacía CompactPendingRecordings
+	pendingCompactions map[string]pendingCompaction
+	compactionMutex    sync.Mutex
 }
 
 // NewVideoService crea una nueva instancia del servicio de video
 func NewVideoService(
 	videoRepository interfaces.ISessionVideoRepository,
+	sessionRepository interfaces.IRemoteSessionRepository,
 	fileStorage interfaces.IFileStorage,
 	actionLogService actionlogservice.IActionLogService,
+	frameStorageFormat FrameStorageFormat,
+	maxFramesPerRecording int,
+	partialRecordingPolicy PartialRecordingPolicy,
 ) IVideoService {
+	if maxFramesPerRecording <= 0 {
+		maxFramesPerRecording = DefaultMaxFramesPerRecording
+	}
+
 	return &videoService{
-		videoRepository:  videoRepository,
-		fileStorage:      fileStorage,
-		actionLogService: actionLogService,
-		uploadSessions:   make(map[string]*VideoUploadSession),
+		videoRepository:       videoRepository,
+		sessionRepository:     sessionRepository,
+		fileStorage:           fileStorage,
+		actionLogService:      actionLogService,
+		frameStore:            NewFrameStore(frameStorageFormat),
+		maxFramesPerRecording: maxFramesPerRecording,
+		maxClockSkew:          DefaultMaxClockSkew,
+		recordingStaleTimeout: DefaultRecordingStaleTimeout,
+		framesBaseDir:         filepath.Join("storage", "session_videos"),
+		partialPolicy:         partialRecordingPolicy,
+		uploadsBaseDir:        filepath.Join("storage", "video_uploads"),
+		recordings:            make(map[string]*recordingProgress),
+		uploadSessions:        make(map[string]*VideoUploadSession),
+		completedUploads:      make(map[string]*completedUpload),
 	}
 }
 
@@ -124,16 +242,24 @@ func (vs *videoService) HandleUploadedVideoChunk(chunk VideoChunk) (*VideoUpload
 		return nil, fmt.Errorf("chunk data vacío para video %s", chunk.VideoID)
 	}
 
-	// Buscar o crear sesión de upload
-	uploadSession, exists := vs.uploadSessions[chunk.VideoID]
+	// Buscar (en memoria o en disco, si el servidor se reinició) o crear sesión de upload
+	uploadSession, exists := vs.uploadSessionFor(chunk.VideoID)
+	if exists && uploadSession.PCID != chunk.PCID {
+		return nil, fmt.Errorf("%w: %s", ErrVideoUploadNotOwned, chunk.VideoID)
+	}
 	if !exists {
 		// Calcular total de chunks basado en el tamaño del archivo
 		chunkSize := 64 * 1024 // 64KB
 		totalChunks := int((chunk.FileSize + int64(chunkSize) - 1) / int64(chunkSize))
+		if chunk.ChunkIndex < 0 || chunk.ChunkIndex >= totalChunks {
+			return nil, fmt.Errorf("%w: chunk %d de %d para video %s", ErrVideoChunkOutOfRange, chunk.ChunkIndex, totalChunks, chunk.VideoID)
+		}
 
 		uploadSession = &VideoUploadSession{
 			VideoID:     chunk.VideoID,
 			SessionID:   chunk.SessionID,
+			PCID:        chunk.PCID,
+			UserID:      chunk.UserID,
 			FileName:    chunk.FileName,
 			FileSize:    chunk.FileSize,
 			Duration:    chunk.Duration,
@@ -143,44 +269,34 @@ func (vs *videoService) HandleUploadedVideoChunk(chunk VideoChunk) (*VideoUpload
 			LastChunkAt: time.Now(),
 		}
 		vs.uploadSessions[chunk.VideoID] = uploadSession
+
+		if err := vs.persistUploadManifest(uploadSession); err != nil {
+			fmt.Printf("⚠️ Warning: la subida del video %s no podrá reanudarse: %v\n", chunk.VideoID, err)
+		}
 	}
 
 	uploadSession.mutex.Lock()
 	defer uploadSession.mutex.Unlock()
 
-	// Guardar chunk
+	// Un índice fuera de 0..TotalChunks-1 nunca se ensamblaría y solo ocuparía memoria y disco
+	if chunk.ChunkIndex < 0 || chunk.ChunkIndex >= uploadSession.TotalChunks {
+		return nil, fmt.Errorf("%w: chunk %d de %d para video %s", ErrVideoChunkOutOfRange, chunk.ChunkIndex, uploadSession.TotalChunks, chunk.VideoID)
+	}
+
+	// Guardar chunk; un chunk reenviado tras una reconexión reemplaza al anterior sin contarse dos veces
 	uploadSession.Chunks[chunk.ChunkIndex] = chunkData
-	uploadSession.ReceivedChunks++
+	uploadSession.ReceivedChunks = len(uploadSession.Chunks)
 	uploadSession.LastChunkAt = time.Now()
+	if err := vs.persistUploadChunk(chunk.VideoID, chunk.ChunkIndex, chunkData); err != nil {
+		fmt.Printf("⚠️ Warning: chunk %d del video %s no persistido: %v\n", chunk.ChunkIndex, chunk.VideoID, err)
+	}
 
 	// Calcular progreso
 	progressPercent := float64(uploadSession.ReceivedChunks) / float64(uploadSession.TotalChunks) * 100
 
 	// Si es el último chunk o tenemos todos los chunks, procesar
 	if chunk.IsLastChunk || uploadSession.ReceivedChunks >= uploadSession.TotalChunks {
-		err := vs.processCompleteVideo(uploadSession)
-		if err != nil {
-			return nil, fmt.Errorf("error procesando video completo: %w", err)
-		}
-
-		// Generar ruta del archivo final
-		fileName := fmt.Sprintf("%s_%s.mp4", uploadSession.SessionID, uploadSession.VideoID)
-		finalPath := filepath.Join("videos", "processed", fileName)
-
-		result := &VideoUploadResult{
-			IsComplete:      true,
-			ChunksReceived:  uploadSession.ReceivedChunks,
-			TotalChunks:     uploadSession.TotalChunks,
-			ProgressPercent: 100.0,
-			FilePath:        finalPath,
-			Duration:        uploadSession.Duration,
-			FileSize:        uploadSession.FileSize,
-		}
-
-		// Limpiar sesión de upload
-		delete(vs.uploadSessions, chunk.VideoID)
-
-		return result, nil
+		return vs.completeUploadSession(uploadSession)
 	}
 
 	// Retornar progreso parcial
@@ -192,31 +308,28 @@ func (vs *videoService) HandleUploadedVideoChunk(chunk VideoChunk) (*VideoUpload
 	}, nil
 }
 
-// processCompleteVideo ensambla todos los chunks y finaliza la subida
+// processCompleteVideo ensambla todos los chunks en un temporal del directorio de subida y lo mueve a su
+// ruta final, de modo que el video procesado nunca queda a medio escribir
 func (vs *videoService) processCompleteVideo(uploadSession *VideoUploadSession) error {
-	// Ensamblar chunks en orden
-	var completeVideo []byte
-	for i := 0; i < uploadSession.TotalChunks; i++ {
-		chunkData, exists := uploadSession.Chunks[i]
-		if !exists {
-			return fmt.Errorf("chunk %d faltante para video %s", i, uploadSession.VideoID)
-		}
-		completeVideo = append(completeVideo, chunkData...)
+	ctx := context.Background()
+
+	assembledPath, sizeBytes, err := vs.assembleUploadChunks(uploadSession)
+	if err != nil {
+		return err
 	}
+	defer os.Remove(assembledPath) // no-op si ya se movió
 
 	// Generar ruta de destino
-	fileName := fmt.Sprintf("%s_%s.mp4", uploadSession.SessionID, uploadSession.VideoID)
-	destinationPath := filepath.Join("videos", "processed", fileName)
+	destinationPath := processedVideoPath(uploadSession.SessionID, uploadSession.VideoID)
 
-	// Guardar archivo completo
-	ctx := context.Background()
-	finalPath, err := vs.fileStorage.SaveFile(ctx, destinationPath, completeVideo)
+	// Mover el archivo completo (rename si está en el mismo almacenamiento)
+	finalPath, err := vs.fileStorage.MoveFile(ctx, assembledPath, destinationPath)
 	if err != nil {
 		return fmt.Errorf("error guardando video completo: %w", err)
 	}
 
 	// Calcular tamaño en MB
-	fileSizeMB := float64(len(completeVideo)) / (1024 * 1024)
+	fileSizeMB := float64(sizeBytes) / (1024 * 1024)
 
 	// Finalizar upload
 	_, err = vs.FinalizeVideoUpload(ctx, uploadSession.SessionID, uploadSession.VideoID, finalPath, fileSizeMB, uploadSession.Duration)
@@ -227,6 +340,45 @@ func (vs *videoService) processCompleteVideo(uploadSession *VideoUploadSession)
 	return nil
 }
 
+// assembleUploadChunks escribe los chunks en orden a un temporal y retorna su ruta y tamaño
+func (vs *videoService) assembleUploadChunks(uploadSession *VideoUploadSession) (string, int64, error) {
+	for i := 0; i < uploadSession.TotalChunks; i++ {
+		if _, exists := uploadSession.Chunks[i]; !exists {
+			return "", 0, fmt.Errorf("chunk %d faltante para video %s", i, uploadSession.VideoID)
+		}
+	}
+
+	uploadDir, err := vs.uploadDir(uploadSession.VideoID)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+		return "", 0, fmt.Errorf("error creando directorio de subida: %w", err)
+	}
+
+	assembledPath := filepath.Join(uploadDir, assembledVideoFileName)
+	assembled, err := os.Create(assembledPath)
+	if err != nil {
+		return "", 0, fmt.Errorf("error creando video ensamblado: %w", err)
+	}
+
+	var sizeBytes int64
+	for i := 0; i < uploadSession.TotalChunks; i++ {
+		written, err := assembled.Write(uploadSession.Chunks[i])
+		sizeBytes += int64(written)
+		if err != nil {
+			assembled.Close()
+			os.Remove(assembledPath)
+			return "", 0, fmt.Errorf("error escribiendo chunk %d del video %s: %w", i, uploadSession.VideoID, err)
+		}
+	}
+	if err := assembled.Close(); err != nil {
+		os.Remove(assembledPath)
+		return "", 0, fmt.Errorf("error cerrando video ensamblado: %w", err)
+	}
+	return assembledPath, sizeBytes, nil
+}
+
 // FinalizeVideoUpload mueve el video al almacenamiento final y actualiza la BD
 func (vs *videoService) FinalizeVideoUpload(ctx context.Context, sessionID, videoID, tempFilePath string, fileSizeMB float64, duration int) (*sessionvideo.SessionVideo, error) {
 	// Crear entidad SessionVideo
@@ -308,39 +460,334 @@ func (vs *videoService) GetAllVideos(ctx context.Context, limit, offset int) ([]
 	return vs.videoRepository.FindAll(ctx, limit, offset)
 }
 
-// SaveVideoFrame guarda un frame individual de video
+// SaveVideoFrame guarda un frame individual de video.
+// Al superar el máximo de frames la grabación se finaliza automáticamente y se retorna ErrFrameLimitReached.
+// El primer frame de una grabación nueva se rechaza con ErrRecordingCapacityReached si no hay hueco.
 func (vs *videoService) SaveVideoFrame(frameInfo VideoFrameInfo) error {
+	vs.recordingsMutex.Lock()
+	err := vs.checkRecordingCapacity(frameInfo.VideoID)
+	vs.recordingsMutex.Unlock()
+	if err != nil {
+		return err
+	}
+
 	// Crear directorio para los frames de este video si no existe
-	framesDir := filepath.Join("storage", "session_videos", frameInfo.VideoID, "frames")
-	err := os.MkdirAll(framesDir, 0755)
+	framesDir := filepath.Join(vs.framesBaseDir, frameInfo.VideoID, "frames")
+	err = os.MkdirAll(framesDir, 0755)
 	if err != nil {
 		return fmt.Errorf("error creando directorio de frames: %w", err)
 	}
 
-	// Generar nombre del archivo con padding para ordenamiento correcto
-	// frame_000001.jpg, frame_000002.jpg, etc.
-	frameFileName := fmt.Sprintf("frame_%06d.jpg", frameInfo.FrameIndex)
-	frameFilePath := filepath.Join(framesDir, frameFileName)
+	vs.recordingsMutex.Lock()
+	// Otra grabación pudo ocupar el último hueco mientras se creaba el directorio
+	if err := vs.checkRecordingCapacity(frameInfo.VideoID); err != nil {
+		vs.recordingsMutex.Unlock()
+		return err
+	}
+	progress, err := vs.recordingProgressFor(frameInfo.VideoID, frameInfo.SessionID, framesDir)
+	if err != nil {
+		vs.recordingsMutex.Unlock()
+		return err
+	}
 
-	// Guardar frame como archivo JPEG
-	err = os.WriteFile(frameFilePath, frameInfo.FrameData, 0644)
+	// Un VideoID solo admite frames de una sesión: mezclar sesiones contaminaría la grabación
+	if progress.sessionID != frameInfo.SessionID {
+		boundSessionID := progress.sessionID
+		vs.recordingsMutex.Unlock()
+		return fmt.Errorf("%w: video %s ligado a la sesión %s, frame de la sesión %s",
+			ErrVideoSessionMismatch, frameInfo.VideoID, boundSessionID, frameInfo.SessionID)
+	}
+
+	if progress.limitReached {
+		vs.recordingsMutex.Unlock()
+		return ErrFrameLimitReached
+	}
+
+	if progress.frames >= vs.maxFramesPerRecording {
+		progress.limitReached = true
+		metadata := VideoRecordingMetadata{
+			VideoID:         frameInfo.VideoID,
+			SessionID:       frameInfo.SessionID,
+			TotalFrames:     progress.frames,
+			DurationSeconds: progress.lastFrameAt.Sub(progress.firstFrameAt).Seconds(),
+			CompletedAt:     time.Now(),
+		}
+		if metadata.DurationSeconds > 0 {
+			metadata.FPS = float64(metadata.TotalFrames) / metadata.DurationSeconds
+		}
+		frameTimes := progress.frameTimesSnapshot()
+		vs.recordingsMutex.Unlock()
+
+		// Finalizar automáticamente la grabación con los frames aceptados
+		if err := vs.persistRecording(metadata, frameTimes); err != nil {
+			return fmt.Errorf("%w: error finalizando grabación: %v", ErrFrameLimitReached, err)
+		}
+		return ErrFrameLimitReached
+	}
+	vs.recordingsMutex.Unlock()
+
+	// Guardar frame según el formato configurado (archivo individual o contenedor empaquetado)
+	err = vs.frameStore.WriteFrame(framesDir, frameInfo.FrameIndex, frameInfo.FrameData)
 	if err != nil {
 		return fmt.Errorf("error guardando frame %d: %w", frameInfo.FrameIndex, err)
 	}
 
+	vs.recordingsMutex.Lock()
+	now := time.Now()
+	if progress.frames == 0 {
+		progress.firstFrameAt = now
+	}
+	progress.frames++
+	progress.lastFrameAt = now
+	if progress.frameTimes == nil {
+		progress.frameTimes = make(map[int]time.Time)
+	}
+	progress.frameTimes[frameInfo.FrameIndex] = frameTimestamp(frameInfo.Timestamp, now)
+
+	skew, skewed := vs.clockSkew(frameInfo.Timestamp, now)
+	firstSkewedFrame := skewed && !progress.clockSkewed
+	if skewed {
+		progress.clockSkewed = true
+	}
+	vs.recordingsMutex.Unlock()
+
+	if firstSkewedFrame {
+		fmt.Printf("⚠️ Warning: reloj del cliente desfasado %v en la grabación %s (frame %d); se usará el tiempo del servidor\n",
+			skew, frameInfo.VideoID, frameInfo.FrameIndex)
+	}
+
 	return nil
 }
 
+// clockSkew calcula el desfase entre un timestamp del cliente y el momento de recepción en el servidor.
+// Un timestamp en cero se considera ausente y nunca se reporta como desfasado.
+func (vs *videoService) clockSkew(clientTimestamp int64, receivedAt time.Time) (time.Duration, bool) {
+	if clientTimestamp <= 0 {
+		return 0, false
+	}
+
+	skew := clientTimestampToTime(clientTimestamp).Sub(receivedAt)
+	if skew < 0 {
+		skew = -skew
+	}
+	return skew, skew > vs.maxClockSkew
+}
+
+// clientTimestampToTime interpreta un timestamp Unix del cliente en segundos o milisegundos
+func clientTimestampToTime(timestamp int64) time.Time {
+	// Por debajo de 1e12 el valor solo tiene sentido en segundos (1e12 ms es septiembre de 2001)
+	if timestamp < 1e12 {
+		return time.Unix(timestamp, 0)
+	}
+	return time.UnixMilli(timestamp)
+}
+
+// recordingProgressFor obtiene el progreso de una grabación; si el servidor se reinició a mitad
+// de la grabación se reconstruye contando los frames ya guardados y leyendo la sesión ligada en disco.
+// Requiere recordingsMutex.
+func (vs *videoService) recordingProgressFor(videoID, sessionID, framesDir string) (*recordingProgress, error) {
+	progress, exists := vs.recordings[videoID]
+	if !exists {
+		boundSessionID, err := vs.bindRecordingSession(videoID, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		progress = &recordingProgress{sessionID: boundSessionID, startedAt: time.Now()}
+		if existing, err := vs.frameStore.CountFrames(framesDir); err == nil {
+			progress.frames = existing
+		}
+		vs.recordings[videoID] = progress
+	}
+	return progress, nil
+}
+
+// GetVideoFrame obtiene los bytes JPEG de un frame de una grabación
+func (vs *videoService) GetVideoFrame(framesDir string, frameNumber int) ([]byte, error) {
+	return vs.frameStore.ReadFrame(framesDir, frameNumber)
+}
+
+// CountVideoFrames cuenta los frames de una grabación
+func (vs *videoService) CountVideoFrames(framesDir string) (int, error) {
+	return vs.frameStore.CountFrames(framesDir)
+}
+
 // FinalizeVideoRecording finaliza una grabación de frames
 func (vs *videoService) FinalizeVideoRecording(recordingInfo VideoRecordingMetadata) error {
+	vs.recordingsMutex.Lock()
+	progress, exists := vs.recordings[recordingInfo.VideoID]
+	boundSessionID := ""
+	if exists {
+		boundSessionID = progress.sessionID
+	} else if bound, err := vs.boundRecordingSession(recordingInfo.VideoID); err == nil {
+		// Tras un reinicio la grabación no está en memoria, pero su sesión sigue en disco
+		boundSessionID = bound
+	}
+	if boundSessionID != "" && boundSessionID != recordingInfo.SessionID {
+		vs.recordingsMutex.Unlock()
+		return fmt.Errorf("%w: video %s ligado a la sesión %s, finalización de la sesión %s",
+			ErrVideoSessionMismatch, recordingInfo.VideoID, boundSessionID, recordingInfo.SessionID)
+	}
+	delete(vs.recordings, recordingInfo.VideoID)
+	frameTimes := progress.frameTimesSnapshot()
+	vs.recordingsMutex.Unlock()
+
+	// La grabación ya se finalizó automáticamente al alcanzar el límite de frames
+	if exists && progress.limitReached {
+		return nil
+	}
+
+	_, completionSkewed := vs.clockSkew(recordingInfo.ClientTimestamp, recordingInfo.CompletedAt)
+	if completionSkewed || (exists && progress.clockSkewed) {
+		recordingInfo = vs.applyServerTiming(recordingInfo, progress)
+	}
+
+	return vs.persistRecording(recordingInfo, frameTimes)
+}
+
+// ApplyPartialRecordingPolicy aplica la política de grabaciones parciales a las grabaciones en curso de una
+// sesión que terminó rechazada o fallida: se finalizan con los frames capturados o se eliminan del disco.
+// Las grabaciones ya cerradas por el límite de frames no se tocan. Cada decisión queda en auditoría.
+func (vs *videoService) ApplyPartialRecordingPolicy(ctx context.Context, sessionID, adminUserID string, endStatus remotesession.SessionStatus) ([]PartialRecordingDisposition, error) {
+	vs.recordingsMutex.Lock()
+	partial := make(map[string]*recordingProgress)
+	for videoID, progress := range vs.recordings {
+		if progress.sessionID != sessionID {
+			continue
+		}
+		delete(vs.recordings, videoID)
+		if !progress.limitReached {
+			partial[videoID] = progress
+		}
+	}
+	vs.recordingsMutex.Unlock()
+
+	videoIDs := make([]string, 0, len(partial))
+	for videoID := range partial {
+		videoIDs = append(videoIDs, videoID)
+	}
+	sort.Strings(videoIDs)
+
+	dispositions := make([]PartialRecordingDisposition, 0, len(videoIDs))
+	var errs []error
+	for _, videoID := range videoIDs {
+		progress := partial[videoID]
+		duration := progress.lastFrameAt.Sub(progress.firstFrameAt)
+		disposition := PartialRecordingDisposition{
+			VideoID:         videoID,
+			SessionID:       sessionID,
+			Kept:            vs.partialPolicy.Keeps(duration),
+			TotalFrames:     progress.frames,
+			DurationSeconds: duration.Seconds(),
+		}
+
+		var err error
+		if disposition.Kept {
+			metadata := VideoRecordingMetadata{
+				VideoID:         videoID,
+				SessionID:       sessionID,
+				TotalFrames:     progress.frames,
+				DurationSeconds: disposition.DurationSeconds,
+				CompletedAt:     time.Now(),
+			}
+			if metadata.DurationSeconds > 0 {
+				metadata.FPS = float64(metadata.TotalFrames) / metadata.DurationSeconds
+			}
+			vs.recordingsMutex.Lock()
+			frameTimes := progress.frameTimesSnapshot()
+			vs.recordingsMutex.Unlock()
+			err = vs.persistRecording(metadata, frameTimes)
+			if errors.Is(err, ErrRecordingEmpty) {
+				// Ya se eliminó y se registró como recording_empty
+				continue
+			}
+		} else {
+			err = os.RemoveAll(filepath.Join(vs.framesBaseDir, videoID))
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error aplicando política a la grabación %s: %w", videoID, err))
+			continue
+		}
+
+		vs.logPartialRecordingDisposition(ctx, disposition, adminUserID, endStatus)
+		dispositions = append(dispositions, disposition)
+	}
+
+	return dispositions, errors.Join(errs...)
+}
+
+// logPartialRecordingDisposition registra en auditoría si una grabación parcial se conservó o se descartó
+func (vs *videoService) logPartialRecordingDisposition(ctx context.Context, disposition PartialRecordingDisposition,
+	adminUserID string, endStatus remotesession.SessionStatus) {
+	outcome := "DISCARDED"
+	if disposition.Kept {
+		outcome = "KEPT"
+	}
+	fmt.Printf("🎞️ Grabación parcial %s de la sesión %s (%s): %s por la política %s (%d frames, %.2fs)\n",
+		disposition.VideoID, disposition.SessionID, endStatus, outcome, vs.partialPolicy,
+		disposition.TotalFrames, disposition.DurationSeconds)
+
+	entityType := "SESSION_VIDEO"
+	err := vs.actionLogService.LogAction(ctx, actionlog.ActionVideoRecordingDisposed,
+		fmt.Sprintf("Grabación parcial %s - VideoID: %s, Sesión: %s (%s)", outcome, disposition.VideoID, disposition.SessionID, endStatus),
+		adminUserID,
+		&disposition.VideoID,
+		&entityType,
+		map[string]interface{}{
+			"video_id":           disposition.VideoID,
+			"session_id":         disposition.SessionID,
+			"session_end_status": string(endStatus),
+			"disposition":        outcome,
+			"policy":             vs.partialPolicy.String(),
+			"total_frames":       disposition.TotalFrames,
+			"duration_seconds":   disposition.DurationSeconds,
+		})
+	if err != nil {
+		// Log pero no fallar
+		fmt.Printf("Warning: error registrando audit log para la grabación parcial %s: %v\n", disposition.VideoID, err)
+	}
+}
+
+// applyServerTiming reemplaza la duración y FPS calculados con el reloj del cliente por los medidos
+// con la llegada de los frames al servidor. Sin progreso en memoria (p. ej. tras un reinicio) no hay
+// tiempos del servidor y se conservan los valores del cliente.
+func (vs *videoService) applyServerTiming(recordingInfo VideoRecordingMetadata, progress *recordingProgress) VideoRecordingMetadata {
+	if progress == nil || progress.frames == 0 {
+		fmt.Printf("⚠️ Warning: reloj del cliente desfasado en la grabación %s y sin tiempos del servidor; se conservan duración y FPS del cliente\n",
+			recordingInfo.VideoID)
+		return recordingInfo
+	}
+
+	recordingInfo.TotalFrames = progress.frames
+	recordingInfo.DurationSeconds = progress.lastFrameAt.Sub(progress.firstFrameAt).Seconds()
+	recordingInfo.FPS = 0
+	if recordingInfo.DurationSeconds > 0 {
+		recordingInfo.FPS = float64(recordingInfo.TotalFrames) / recordingInfo.DurationSeconds
+	}
+
+	fmt.Printf("⚠️ Warning: grabación %s finalizada con tiempo del servidor por desfase de reloj del cliente (%.2fs, %.2f FPS)\n",
+		recordingInfo.VideoID, recordingInfo.DurationSeconds, recordingInfo.FPS)
+	return recordingInfo
+}
+
+// persistRecording guarda los metadatos de una grabación de frames y la registra en auditoría. frameTimes son los
+// instantes de captura de los frames recibidos desde que arrancó el servidor; puede ser nil.
+func (vs *videoService) persistRecording(recordingInfo VideoRecordingMetadata, frameTimes map[int]time.Time) error {
 	// Construir la ruta base donde están guardados los frames
-	framesBasePath := filepath.Join("storage", "session_videos", recordingInfo.VideoID, "frames")
+	framesBasePath := filepath.Join(vs.framesBaseDir, recordingInfo.VideoID, "frames")
 
 	// Verificar que el directorio de frames existe
 	if _, err := os.Stat(framesBasePath); os.IsNotExist(err) {
 		return fmt.Errorf("directorio de frames no encontrado: %s", framesBasePath)
 	}
 
+	// Un directorio vacío o sin frames válidos no es una grabación
+	if err := vs.discardEmptyRecording(recordingInfo, framesBasePath); err != nil {
+		return err
+	}
+
+	// Los FPS se miden con los timestamps de los frames: frames/duración falla si hubo pausas o frames perdidos
+	vs.saveFrameTimestamps(recordingInfo, framesBasePath, frameTimes)
+
 	// Calcular tamaño total aproximado de todos los frames
 	totalSizeMB := vs.calculateFramesDirSize(framesBasePath)
 
@@ -363,6 +810,20 @@ func (vs *videoService) FinalizeVideoRecording(recordingInfo VideoRecordingMetad
 		return fmt.Errorf("error guardando metadatos de video en BD: %w", err)
 	}
 
+	// En modo packed el índice de frames también se guarda en BD; el frames.idx del disco sigue siendo
+	// la fuente de lectura, así que un fallo aquí no invalida la grabación
+	if err := vs.saveFrameIndex(ctx, recordingInfo.VideoID, framesBasePath); err != nil {
+		fmt.Printf("Warning: no se pudo guardar el índice de frames de la grabación %s: %v\n", recordingInfo.VideoID, err)
+	}
+
+	// En modo sprites los frames se agrupan en hojas por segundo en segundo plano, sin retrasar la finalización
+	vs.queueCompaction(recordingInfo.VideoID, framesBasePath, recordingInfo.FPS)
+
+	if err := vs.linkSessionVideo(ctx, recordingInfo.SessionID, recordingInfo.VideoID); err != nil {
+		// El video ya está guardado y sigue siendo localizable por su sesión
+		fmt.Printf("Warning: no se pudo enlazar la grabación %s a la sesión %s: %v\n", recordingInfo.VideoID, recordingInfo.SessionID, err)
+	}
+
 	// Registrar en audit log
 	entityType := "SESSION_VIDEO"
 	err = vs.actionLogService.LogAction(ctx, "VIDEO_RECORDING_ENDED",
@@ -388,8 +849,60 @@ func (vs *videoService) FinalizeVideoRecording(recordingInfo VideoRecordingMetad
 	return nil
 }
 
+// saveFrameIndex guarda en una sola transacción el índice de una grabación empaquetada
+func (vs *videoService) saveFrameIndex(ctx context.Context, videoID, framesDir string) error {
+	entries, err := vs.frameStore.PackedFrameIndex(framesDir)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return vs.videoRepository.SaveFrameIndex(ctx, videoID, entries)
+}
+
+// linkSessionVideo guarda en la sesión el ID de su grabación para que el enlace sea bidireccional;
+// con varias grabaciones en la misma sesión queda la última finalizada
+func (vs *videoService) linkSessionVideo(ctx context.Context, sessionID, videoID string) error {
+	if vs.sessionRepository == nil || sessionID == "" {
+		return nil
+	}
+
+	session, err := vs.sessionRepository.FindById(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("error buscando la sesión: %w", err)
+	}
+	if session == nil {
+		return fmt.Errorf("sesión %s no encontrada", sessionID)
+	}
+
+	if current := session.SessionVideoID(); current != nil && *current == videoID {
+		return nil
+	}
+	if err := session.SetSessionVideoID(videoID); err != nil {
+		return err
+	}
+	if err := vs.sessionRepository.Update(ctx, session); err != nil {
+		return fmt.Errorf("error actualizando session_video_id: %w", err)
+	}
+	return nil
+}
+
 // calculateFramesDirSize calcula el tamaño total de un directorio de frames en MB
 func (vs *videoService) calculateFramesDirSize(dirPath string) float64 {
+	totalSize, err := framesDirBytes(dirPath)
+	if err != nil {
+		// Si hay error, retornar 0
+		return 0.0
+	}
+
+	// Convertir a MB
+	return float64(totalSize) / (1024 * 1024)
+}
+
+// framesDirBytes suma el tamaño en bytes de los archivos de un directorio de frames
+// (frames individuales o contenedor e índice empaquetados)
+func framesDirBytes(dirPath string) (int64, error) {
 	var totalSize int64
 
 	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
@@ -402,11 +915,5 @@ func (vs *videoService) calculateFramesDirSize(dirPath string) float64 {
 		return nil
 	})
 
-	if err != nil {
-		// Si hay error, retornar 0
-		return 0.0
-	}
-
-	// Convertir a MB
-	return float64(totalSize) / (1024 * 1024)
+	return totalSize, err
 }
@@ -0,0 +1,517 @@
+package videoservice
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+// MockSessionVideoRepository es un mock del repositorio de videos
+type MockSessionVideoRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionVideoRepository) Save(ctx context.Context, video *sessionvideo.SessionVideo) error {
+	return m.Called(ctx, video).Error(0)
+}
+
+func (m *MockSessionVideoRepository) FindByID(ctx context.Context, videoID string) (*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, videoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sessionvideo.SessionVideo), args.Error(1)
+}
+
+func (m *MockSessionVideoRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*sessionvideo.SessionVideo), args.Error(1)
+}
+
+func (m *MockSessionVideoRepository) Update(ctx context.Context, video *sessionvideo.SessionVideo) error {
+	return m.Called(ctx, video).Error(0)
+}
+
+func (m *MockSessionVideoRepository) Delete(ctx context.Context, videoID string) error {
+	return m.Called(ctx, videoID).Error(0)
+}
+
+func (m *MockSessionVideoRepository) FindAll(ctx context.Context, limit, offset int) ([]*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*sessionvideo.SessionVideo), args.Error(1)
+}
+
+func (m *MockSessionVideoRepository) Count(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSessionVideoRepository) FindByDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, startDate, endDate, limit, offset)
+	return args.Get(0).([]*sessionvideo.SessionVideo), args.Error(1)
+}
+
+func (m *MockSessionVideoRepository) SaveFrameIndex(ctx context.Context, videoID string, entries []sessionvideo.FrameIndexEntry) error {
+	return m.Called(ctx, videoID, entries).Error(0)
+}
+
+func (m *MockSessionVideoRepository) SumFileSizeByClientPCID(ctx context.Context, clientPCID string) (float64, error) {
+	args := m.Called(ctx, clientPCID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockSessionVideoRepository) FindOrphaned(ctx context.Context, limit int) ([]*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*sessionvideo.SessionVideo), args.Error(1)
+}
+
+// MockActionLogService es un mock del servicio de auditoría
+type MockActionLogService struct {
+	mock.Mock
+}
+
+func (m *MockActionLogService) LogAction(ctx context.Context, actionType actionlog.ActionType, description string,
+	performedByUserID string, subjectEntityID *string, subjectEntityType *string,
+	details map[string]interface{}) error {
+	return m.Called(ctx, actionType, description, performedByUserID, subjectEntityID, subjectEntityType, details).Error(0)
+}
+
+func (m *MockActionLogService) LogSessionEnded(ctx context.Context, sessionID, adminUserID, reason string) error {
+	return m.Called(ctx, sessionID, adminUserID, reason).Error(0)
+}
+
+func (m *MockActionLogService) GetRecentLogs(ctx context.Context, limit int) ([]*actionlog.ActionLog, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*actionlog.ActionLog), args.Error(1)
+}
+
+func (m *MockActionLogService) GetLogsByUser(ctx context.Context, userID string, limit, offset int) ([]*actionlog.ActionLog, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	return args.Get(0).([]*actionlog.ActionLog), args.Error(1)
+}
+
+func (m *MockActionLogService) GetLogsByActionType(ctx context.Context, actionType actionlog.ActionType, limit, offset int) ([]*actionlog.ActionLog, error) {
+	args := m.Called(ctx, actionType, limit, offset)
+	return args.Get(0).([]*actionlog.ActionLog), args.Error(1)
+}
+
+func (m *MockActionLogService) GetLogsByEntity(ctx context.Context, entityID, entityType string, limit, offset int) ([]*actionlog.ActionLog, error) {
+	args := m.Called(ctx, entityID, entityType, limit, offset)
+	return args.Get(0).([]*actionlog.ActionLog), args.Error(1)
+}
+
+func (m *MockActionLogService) GetLogsCount(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+const (
+	testVideoID   = "video-limit-test"
+	testSessionID = "session-limit-test"
+)
+
+// newLimitedVideoService crea un servicio con un máximo de frames y frames guardados en un directorio temporal
+func newLimitedVideoService(t *testing.T, maxFrames int) (*videoService, *MockSessionVideoRepository, *MockActionLogService) {
+	videoRepo := new(MockSessionVideoRepository)
+	actionLog := new(MockActionLogService)
+
+	service := NewVideoService(videoRepo, nil, nil, actionLog, FrameStorageIndividual, maxFrames, DefaultPartialRecordingPolicy).(*videoService)
+	service.framesBaseDir = t.TempDir()
+
+	return service, videoRepo, actionLog
+}
+
+func testFrameInfo(frameIndex int) VideoFrameInfo {
+	return VideoFrameInfo{
+		VideoID:    testVideoID,
+		SessionID:  testSessionID,
+		FrameIndex: frameIndex,
+		Timestamp:  time.Now().UnixMilli(),
+		FrameData:  []byte{0xFF, 0xD8, byte(frameIndex), 0xFF, 0xD9},
+	}
+}
+
+func TestSaveVideoFrame_RejectsFramesBeyondLimitAndFinalizesRecording(t *testing.T) {
+	// Arrange
+	service, videoRepo, actionLog := newLimitedVideoService(t, 3)
+
+	videoRepo.On("Save", mock.Anything, mock.MatchedBy(func(video *sessionvideo.SessionVideo) bool {
+		return video.VideoID() == testVideoID && video.AssociatedSessionID() == testSessionID
+	})).Return(nil).Once()
+	actionLog.On("LogAction", mock.Anything, actionlog.ActionType("VIDEO_RECORDING_ENDED"), mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.MatchedBy(func(details map[string]interface{}) bool {
+			return details["total_frames"] == 3
+		})).Return(nil).Once()
+
+	// Act
+	var errs []error
+	for i := 1; i <= 5; i++ {
+		errs = append(errs, service.SaveVideoFrame(testFrameInfo(i)))
+	}
+
+	// Assert - los primeros frames se aceptan y los que superan el límite se rechazan
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.NoError(t, errs[2])
+	assert.ErrorIs(t, errs[3], ErrFrameLimitReached)
+	assert.ErrorIs(t, errs[4], ErrFrameLimitReached)
+
+	count, err := service.CountVideoFrames(filepath.Join(service.framesBaseDir, testVideoID, "frames"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, count, "no se deben guardar frames por encima del límite")
+
+	// La grabación se finalizó automáticamente una sola vez
+	videoRepo.AssertExpectations(t)
+	actionLog.AssertExpectations(t)
+}
+
+func TestSaveVideoFrame_RejectsFrameFromAnotherSession(t *testing.T) {
+	// Arrange - el primer frame liga la grabación a testSessionID
+	service, _, _ := newLimitedVideoService(t, 10)
+	require.NoError(t, service.SaveVideoFrame(testFrameInfo(1)))
+
+	foreignFrame := testFrameInfo(2)
+	foreignFrame.SessionID = "session-other"
+
+	// Act
+	err := service.SaveVideoFrame(foreignFrame)
+
+	// Assert - el frame ajeno no se guarda y la grabación sigue aceptando frames de su sesión
+	assert.ErrorIs(t, err, ErrVideoSessionMismatch)
+	count, err := service.CountVideoFrames(filepath.Join(service.framesBaseDir, testVideoID, "frames"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, service.SaveVideoFrame(testFrameInfo(2)))
+}
+
+func TestSaveVideoFrame_SessionBindingSurvivesServerRestart(t *testing.T) {
+	// Arrange - un servicio nuevo sobre el mismo directorio simula el reinicio a mitad de grabación
+	service, _, _ := newLimitedVideoService(t, 10)
+	require.NoError(t, service.SaveVideoFrame(testFrameInfo(1)))
+
+	restarted, _, _ := newLimitedVideoService(t, 10)
+	restarted.framesBaseDir = service.framesBaseDir
+
+	foreignFrame := testFrameInfo(2)
+	foreignFrame.SessionID = "session-other"
+
+	// Act
+	frameErr := restarted.SaveVideoFrame(foreignFrame)
+	finalizeErr := restarted.FinalizeVideoRecording(VideoRecordingMetadata{VideoID: testVideoID, SessionID: "session-other"})
+
+	// Assert
+	assert.ErrorIs(t, frameErr, ErrVideoSessionMismatch)
+	assert.ErrorIs(t, finalizeErr, ErrVideoSessionMismatch)
+	assert.NoError(t, restarted.SaveVideoFrame(testFrameInfo(2)))
+}
+
+func TestFinalizeVideoRecording_SkipsRecordingAlreadyFinalizedByLimit(t *testing.T) {
+	// Arrange
+	service, videoRepo, actionLog := newLimitedVideoService(t, 1)
+
+	videoRepo.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+	actionLog.On("LogAction", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+	require.NoError(t, service.SaveVideoFrame(testFrameInfo(1)))
+	require.ErrorIs(t, service.SaveVideoFrame(testFrameInfo(2)), ErrFrameLimitReached)
+
+	// Act - el cliente envía video_recording_complete después de recibir recording_limit_reached
+	err := service.FinalizeVideoRecording(VideoRecordingMetadata{
+		VideoID:     testVideoID,
+		SessionID:   testSessionID,
+		TotalFrames: 2,
+		CompletedAt: time.Now(),
+	})
+
+	// Assert - no se duplica el registro de la grabación
+	assert.NoError(t, err)
+	videoRepo.AssertNumberOfCalls(t, "Save", 1)
+	actionLog.AssertNumberOfCalls(t, "LogAction", 1)
+}
+
+func TestNewVideoService_UsesDefaultFrameLimitWhenNotConfigured(t *testing.T) {
+	// Act
+	service := NewVideoService(nil, nil, nil, nil, FrameStorageIndividual, 0, DefaultPartialRecordingPolicy).(*videoService)
+
+	// Assert
+	assert.Equal(t, DefaultMaxFramesPerRecording, service.maxFramesPerRecording)
+}
+
+// finalizeCapturingMetadata finaliza la grabación y retorna la duración guardada y los detalles del audit log
+func finalizeCapturingMetadata(t *testing.T, service *videoService, videoRepo *MockSessionVideoRepository,
+	actionLog *MockActionLogService, metadata VideoRecordingMetadata) (*sessionvideo.SessionVideo, map[string]interface{}) {
+	t.Helper()
+
+	var saved *sessionvideo.SessionVideo
+	var details map[string]interface{}
+	videoRepo.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*sessionvideo.SessionVideo)
+	}).Return(nil).Once()
+	actionLog.On("LogAction", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		details = args.Get(6).(map[string]interface{})
+	}).Return(nil).Once()
+
+	require.NoError(t, service.FinalizeVideoRecording(metadata))
+	require.NotNil(t, saved)
+	return saved, details
+}
+
+func TestFinalizeVideoRecording_UsesServerTimingWhenFrameClockSkewed(t *testing.T) {
+	// Arrange - el reloj del cliente va 2 horas atrasado
+	service, videoRepo, actionLog := newLimitedVideoService(t, 100)
+	skewedClientTime := time.Now().Add(-2 * time.Hour)
+
+	for i := 1; i <= 3; i++ {
+		frame := testFrameInfo(i)
+		frame.Timestamp = skewedClientTime.UnixMilli()
+		require.NoError(t, service.SaveVideoFrame(frame))
+	}
+
+	// Fijar los tiempos de llegada para que la duración del servidor sea determinista
+	progress := service.recordings[testVideoID]
+	require.True(t, progress.clockSkewed)
+	progress.firstFrameAt = progress.lastFrameAt.Add(-2 * time.Second)
+
+	// Act - el cliente reporta valores calculados con su reloj
+	saved, details := finalizeCapturingMetadata(t, service, videoRepo, actionLog, VideoRecordingMetadata{
+		VideoID:         testVideoID,
+		SessionID:       testSessionID,
+		TotalFrames:     3,
+		FPS:             0.0004,
+		DurationSeconds: 7200,
+		CompletedAt:     time.Now(),
+		ClientTimestamp: skewedClientTime.UnixMilli(),
+	})
+
+	// Assert
+	assert.Equal(t, 2, saved.DurationSeconds())
+	assert.Equal(t, 3, details["total_frames"])
+	assert.InDelta(t, 2.0, details["duration_seconds"], 0.001)
+	assert.InDelta(t, 1.5, details["fps"], 0.001)
+}
+
+func TestFinalizeVideoRecording_UsesServerTimingWhenCompletionClockSkewed(t *testing.T) {
+	// Arrange - los frames no traen timestamp, solo el mensaje de finalización (en segundos) está adelantado
+	service, videoRepo, actionLog := newLimitedVideoService(t, 100)
+	for i := 1; i <= 4; i++ {
+		frame := testFrameInfo(i)
+		frame.Timestamp = 0
+		require.NoError(t, service.SaveVideoFrame(frame))
+	}
+	progress := service.recordings[testVideoID]
+	require.False(t, progress.clockSkewed)
+	progress.firstFrameAt = progress.lastFrameAt.Add(-4 * time.Second)
+
+	// Act
+	saved, details := finalizeCapturingMetadata(t, service, videoRepo, actionLog, VideoRecordingMetadata{
+		VideoID:         testVideoID,
+		SessionID:       testSessionID,
+		TotalFrames:     4,
+		FPS:             0.001,
+		DurationSeconds: 3600,
+		CompletedAt:     time.Now(),
+		ClientTimestamp: time.Now().Add(24 * time.Hour).Unix(),
+	})
+
+	// Assert
+	assert.Equal(t, 4, saved.DurationSeconds())
+	assert.InDelta(t, 1.0, details["fps"], 0.001)
+}
+
+func TestFinalizeVideoRecording_KeepsClientTimingWithinTolerance(t *testing.T) {
+	// Arrange
+	service, videoRepo, actionLog := newLimitedVideoService(t, 100)
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, service.SaveVideoFrame(testFrameInfo(i)))
+	}
+	require.False(t, service.recordings[testVideoID].clockSkewed)
+
+	// Act
+	saved, details := finalizeCapturingMetadata(t, service, videoRepo, actionLog, VideoRecordingMetadata{
+		VideoID:         testVideoID,
+		SessionID:       testSessionID,
+		TotalFrames:     300,
+		FPS:             30,
+		DurationSeconds: 10,
+		CompletedAt:     time.Now(),
+		ClientTimestamp: time.Now().Add(5 * time.Second).UnixMilli(),
+	})
+
+	// Assert
+	assert.Equal(t, 10, saved.DurationSeconds())
+	assert.Equal(t, 300, details["total_frames"])
+	assert.Equal(t, 30.0, details["fps"])
+}
+
+// inMemorySessionRepository sesiones en memoria; el resto del repositorio no se usa
+type inMemorySessionRepository struct {
+	interfaces.IRemoteSessionRepository
+	sessions map[string]*remotesession.RemoteSession
+	updates  int
+}
+
+func (r *inMemorySessionRepository) FindById(ctx context.Context, id string) (*remotesession.RemoteSession, error) {
+	return r.sessions[id], nil
+}
+
+func (r *inMemorySessionRepository) Update(ctx context.Context, session *remotesession.RemoteSession) error {
+	r.sessions[session.SessionID()] = session
+	r.updates++
+	return nil
+}
+
+func TestFinalizeVideoRecording_LinksVideoToSession(t *testing.T) {
+	// Arrange
+	service, videoRepo, actionLog := newLimitedVideoService(t, 100)
+	session, err := remotesession.NewRemoteSession("admin-id", "pc-id")
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+	sessionRepo := &inMemorySessionRepository{sessions: map[string]*remotesession.RemoteSession{session.SessionID(): session}}
+	service.sessionRepository = sessionRepo
+
+	frame := testFrameInfo(1)
+	frame.SessionID = session.SessionID()
+	require.NoError(t, service.SaveVideoFrame(frame))
+	videoRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	actionLog.On("LogAction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	metadata := VideoRecordingMetadata{
+		VideoID:         testVideoID,
+		SessionID:       session.SessionID(),
+		TotalFrames:     1,
+		DurationSeconds: 1,
+		CompletedAt:     time.Now(),
+	}
+
+	// Act
+	require.NoError(t, service.FinalizeVideoRecording(metadata))
+	require.NoError(t, service.linkSessionVideo(context.Background(), session.SessionID(), testVideoID))
+
+	// Assert - el segundo enlace con el mismo video no vuelve a escribir
+	linked := sessionRepo.sessions[session.SessionID()].SessionVideoID()
+	require.NotNil(t, linked)
+	assert.Equal(t, testVideoID, *linked)
+	assert.Equal(t, 1, sessionRepo.updates)
+}
+
+func TestFinalizeVideoRecording_SavesPackedFrameIndexInOneBatch(t *testing.T) {
+	// Arrange
+	videoRepo := new(MockSessionVideoRepository)
+	actionLog := new(MockActionLogService)
+	service := NewVideoService(videoRepo, nil, nil, actionLog, FrameStoragePacked, 100, DefaultPartialRecordingPolicy).(*videoService)
+	service.framesBaseDir = t.TempDir()
+
+	for frameIndex := 0; frameIndex < 3; frameIndex++ {
+		require.NoError(t, service.SaveVideoFrame(testFrameInfo(frameIndex)))
+	}
+	var saved []sessionvideo.FrameIndexEntry
+	videoRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	videoRepo.On("SaveFrameIndex", mock.Anything, testVideoID, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		saved = args.Get(2).([]sessionvideo.FrameIndexEntry)
+	})
+	actionLog.On("LogAction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// Act
+	err := service.FinalizeVideoRecording(VideoRecordingMetadata{
+		VideoID:         testVideoID,
+		SessionID:       testSessionID,
+		TotalFrames:     3,
+		DurationSeconds: 1,
+		CompletedAt:     time.Now(),
+	})
+
+	// Assert - cada frame de prueba ocupa 5 bytes en el contenedor
+	require.NoError(t, err)
+	videoRepo.AssertNumberOfCalls(t, "SaveFrameIndex", 1)
+	assert.Equal(t, []sessionvideo.FrameIndexEntry{
+		{FrameIndex: 0, Offset: 0, Length: 5},
+		{FrameIndex: 1, Offset: 5, Length: 5},
+		{FrameIndex: 2, Offset: 10, Length: 5},
+	}, saved)
+}
+
+func TestFinalizeVideoRecording_EmptyFramesDirectoryIsNotPersisted(t *testing.T) {
+	// Arrange - el cliente se cayó antes de enviar frames: el directorio existe con un frame a medio escribir
+	service, videoRepo, actionLog := newLimitedVideoService(t, 100)
+	framesDir := filepath.Join(service.framesBaseDir, testVideoID, "frames")
+	require.NoError(t, os.MkdirAll(framesDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(framesDir, individualFrameFileName(1)), nil, 0644))
+
+	var details map[string]interface{}
+	actionLog.On("LogAction", mock.Anything, actionlog.ActionVideoRecordingEmpty, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		details = args.Get(6).(map[string]interface{})
+	}).Return(nil).Once()
+
+	// Act
+	err := service.FinalizeVideoRecording(VideoRecordingMetadata{
+		VideoID:         testVideoID,
+		SessionID:       testSessionID,
+		TotalFrames:     120,
+		FPS:             2,
+		DurationSeconds: 60,
+		CompletedAt:     time.Now(),
+	})
+
+	// Assert - sin SessionVideo, con recording_empty en auditoría y sin el directorio vacío
+	assert.ErrorIs(t, err, ErrRecordingEmpty)
+	videoRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	actionLog.AssertExpectations(t)
+	assert.Equal(t, "recording_empty", details["event"])
+	assert.Equal(t, testSessionID, details["session_id"])
+	assert.NoDirExists(t, filepath.Join(service.framesBaseDir, testVideoID))
+}
+
+func TestFinalizeVideoRecording_EmptyRecordingIsAuditedUnderSessionAdmin(t *testing.T) {
+	// Arrange
+	service, _, actionLog := newLimitedVideoService(t, 100)
+	session, err := remotesession.NewRemoteSession("admin-id", "pc-id")
+	require.NoError(t, err)
+	service.sessionRepository = &inMemorySessionRepository{sessions: map[string]*remotesession.RemoteSession{session.SessionID(): session}}
+	require.NoError(t, os.MkdirAll(filepath.Join(service.framesBaseDir, testVideoID, "frames"), 0755))
+	actionLog.On("LogAction", mock.Anything, actionlog.ActionVideoRecordingEmpty, mock.Anything, "admin-id",
+		mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+	// Act
+	err = service.FinalizeVideoRecording(VideoRecordingMetadata{
+		VideoID:     testVideoID,
+		SessionID:   session.SessionID(),
+		TotalFrames: 10,
+		CompletedAt: time.Now(),
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrRecordingEmpty)
+	actionLog.AssertExpectations(t)
+}
+
+func TestFinalizeVideoRecording_MissingFramesDirectoryFails(t *testing.T) {
+	// Arrange - no llegó ningún frame, así que ni siquiera existe el directorio
+	service, videoRepo, actionLog := newLimitedVideoService(t, 100)
+
+	// Act
+	err := service.FinalizeVideoRecording(VideoRecordingMetadata{
+		VideoID:     testVideoID,
+		SessionID:   testSessionID,
+		TotalFrames: 10,
+		CompletedAt: time.Now(),
+	})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "directorio de frames no encontrado")
+	assert.NotErrorIs(t, err, ErrRecordingEmpty)
+	videoRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	actionLog.AssertNotCalled(t, "LogAction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,107 @@
+package videoservice
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// completedUploadRetention tiempo durante el que se recuerda una subida completada para confirmar un
+// video_upload_complete que llegue después del último chunk
+const completedUploadRetention = time.Hour
+
+var (
+	// ErrVideoUploadNotFound no hay una subida en curso ni completada recientemente con ese video_id
+	ErrVideoUploadNotFound = errors.New("subida de video no encontrada")
+	// ErrVideoUploadIncomplete se pidió completar una subida a la que le faltan chunks
+	ErrVideoUploadIncomplete = errors.New("subida de video incompleta")
+	// ErrVideoUploadNotOwned la subida la inició otro PC
+	ErrVideoUploadNotOwned = errors.New("subida de video de otro PC")
+	// ErrVideoChunkOutOfRange el índice del chunk no está entre 0 y el total de chunks de la subida
+	ErrVideoChunkOutOfRange = errors.New("índice de chunk fuera de rango")
+)
+
+// completedUpload resultado de una subida ya persistida
+type completedUpload struct {
+	result *VideoUploadResult
+	// pcID y userID PC que hizo la subida y su usuario
+	pcID   string
+	userID string
+	// partUpload estado final de una subida HTTP por partes (nil en las subidas por chunks)
+	partUpload  *PartUploadProgress
+	completedAt time.Time
+}
+
+// CompleteVideoUpload finaliza explícitamente una subida por chunks (mensaje video_upload_complete).
+// La finalización es la misma que dispara el último chunk, así que el video se persiste una sola vez:
+// si ya se completó, retorna el resultado guardado con AlreadyCompleted. Solo el PC y el usuario que hicieron
+// la subida pueden completarla o consultar su resultado (ErrVideoUploadNotOwned).
+func (vs *videoService) CompleteVideoUpload(videoID, pcID, userID string) (*VideoUploadResult, error) {
+	vs.uploadMutex.Lock()
+	defer vs.uploadMutex.Unlock()
+
+	if completed, exists := vs.completedUploads[videoID]; exists {
+		if completed.pcID != pcID || completed.userID != userID {
+			return nil, fmt.Errorf("%w: %s", ErrVideoUploadNotOwned, videoID)
+		}
+		result := *completed.result
+		result.AlreadyCompleted = true
+		return &result, nil
+	}
+
+	uploadSession, exists := vs.uploadSessionFor(videoID)
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrVideoUploadNotFound, videoID)
+	}
+	if uploadSession.PCID != pcID || uploadSession.UserID != userID {
+		return nil, fmt.Errorf("%w: %s", ErrVideoUploadNotOwned, videoID)
+	}
+
+	uploadSession.mutex.Lock()
+	defer uploadSession.mutex.Unlock()
+
+	if missing := uploadSession.TotalChunks - len(uploadSession.Chunks); missing > 0 {
+		return nil, fmt.Errorf("%w: faltan %d de %d chunks para video %s",
+			ErrVideoUploadIncomplete, missing, uploadSession.TotalChunks, videoID)
+	}
+
+	return vs.completeUploadSession(uploadSession)
+}
+
+// completeUploadSession ensambla y persiste la subida y la mueve a completedUploads.
+// Requiere uploadMutex y el mutex de la sesión de subida.
+func (vs *videoService) completeUploadSession(uploadSession *VideoUploadSession) (*VideoUploadResult, error) {
+	if err := vs.processCompleteVideo(uploadSession); err != nil {
+		return nil, fmt.Errorf("error procesando video completo: %w", err)
+	}
+
+	result := &VideoUploadResult{
+		IsComplete:      true,
+		ChunksReceived:  uploadSession.ReceivedChunks,
+		TotalChunks:     uploadSession.TotalChunks,
+		ProgressPercent: 100.0,
+		FilePath:        processedVideoPath(uploadSession.SessionID, uploadSession.VideoID),
+		Duration:        uploadSession.Duration,
+		FileSize:        uploadSession.FileSize,
+	}
+
+	// Limpiar sesión de upload (también sus chunks en disco) y recordar el resultado
+	delete(vs.uploadSessions, uploadSession.VideoID)
+	vs.removeUploadDir(uploadSession.VideoID)
+	vs.rememberCompletedUpload(uploadSession.VideoID, &completedUpload{result: result, pcID: uploadSession.PCID, userID: uploadSession.UserID})
+
+	return result, nil
+}
+
+// rememberCompletedUpload guarda el resultado de una subida persistida y olvida las que superaron
+// completedUploadRetention. Requiere uploadMutex.
+func (vs *videoService) rememberCompletedUpload(videoID string, completed *completedUpload) {
+	now := time.Now()
+	for completedID, previous := range vs.completedUploads {
+		if now.Sub(previous.completedAt) > completedUploadRetention {
+			delete(vs.completedUploads, completedID)
+		}
+	}
+	completed.completedAt = now
+	vs.completedUploads[videoID] = completed
+}
@@ -0,0 +1,191 @@
+package videoservice
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+)
+
+// memoryFileStorage guarda en memoria los videos ensamblados; el resto de IFileStorage no se usa
+type memoryFileStorage struct {
+	interfaces.IFileStorage
+	saved   map[string][]byte
+	failErr error
+}
+
+func (s *memoryFileStorage) SaveFile(ctx context.Context, destinationPath string, content []byte) (string, error) {
+	if s.failErr != nil {
+		return "", s.failErr
+	}
+	s.saved[destinationPath] = content
+	return destinationPath, nil
+}
+
+func (s *memoryFileStorage) MoveFile(ctx context.Context, sourcePath, destinationPath string) (string, error) {
+	if s.failErr != nil {
+		return "", s.failErr
+	}
+	content, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return "", err
+	}
+	s.saved[destinationPath] = content
+	return destinationPath, os.Remove(sourcePath)
+}
+
+// newUploadVideoService crea un servicio cuyo almacenamiento y repositorio registran cada persistencia
+func newUploadVideoService(t *testing.T) (*videoService, *memoryFileStorage, *MockSessionVideoRepository) {
+	t.Helper()
+
+	videoRepo := new(MockSessionVideoRepository)
+	actionLog := new(MockActionLogService)
+	storage := &memoryFileStorage{saved: make(map[string][]byte)}
+
+	videoRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	actionLog.On("LogAction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	service := NewVideoService(videoRepo, nil, storage, actionLog, FrameStorageIndividual, 0, DefaultPartialRecordingPolicy).(*videoService)
+	service.uploadsBaseDir = t.TempDir()
+	return service, storage, videoRepo
+}
+
+// PC y usuario que envían los chunks de las subidas de prueba
+const (
+	testUploadPCID   = "pc-upload-test"
+	testUploadUserID = "user-upload-test"
+)
+
+// testUploadChunk chunk i de un video de dos chunks de 64KB
+func testUploadChunk(chunkIndex int, isLast bool) VideoChunk {
+	return VideoChunk{
+		VideoID:     testVideoID,
+		SessionID:   testSessionID,
+		PCID:        testUploadPCID,
+		UserID:      testUploadUserID,
+		ChunkIndex:  chunkIndex,
+		ChunkData:   []byte{byte(chunkIndex)},
+		IsLastChunk: isLast,
+		FileSize:    2 * 64 * 1024,
+	}
+}
+
+func TestCompleteVideoUpload_ConfirmsUploadAlreadyPersistedByLastChunk(t *testing.T) {
+	// Arrange
+	service, storage, videoRepo := newUploadVideoService(t)
+	_, err := service.HandleUploadedVideoChunk(testUploadChunk(0, false))
+	require.NoError(t, err)
+	last, err := service.HandleUploadedVideoChunk(testUploadChunk(1, true))
+	require.NoError(t, err)
+	require.True(t, last.IsComplete)
+
+	// Act
+	result, err := service.CompleteVideoUpload(testVideoID, testUploadPCID, testUploadUserID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, result.AlreadyCompleted)
+	assert.Equal(t, last.FilePath, result.FilePath)
+	// El video se persistió una sola vez, por el último chunk
+	videoRepo.AssertNumberOfCalls(t, "Save", 1)
+	assert.Len(t, storage.saved, 1)
+}
+
+func TestCompleteVideoUpload_PersistsUploadWhoseLastChunkFailedToSave(t *testing.T) {
+	// Arrange
+	service, storage, videoRepo := newUploadVideoService(t)
+	_, err := service.HandleUploadedVideoChunk(testUploadChunk(0, false))
+	require.NoError(t, err)
+
+	storage.failErr = errors.New("disk full")
+	_, err = service.HandleUploadedVideoChunk(testUploadChunk(1, true))
+	require.Error(t, err)
+	storage.failErr = nil
+	// El temporal ensamblado no sobrevive al fallo
+	assert.NoFileExists(t, filepath.Join(service.uploadsBaseDir, testVideoID, assembledVideoFileName))
+
+	// Act
+	result, err := service.CompleteVideoUpload(testVideoID, testUploadPCID, testUploadUserID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, result.IsComplete)
+	assert.False(t, result.AlreadyCompleted)
+	assert.Equal(t, []byte{0, 1}, storage.saved[result.FilePath])
+	videoRepo.AssertNumberOfCalls(t, "Save", 1)
+
+	// Una segunda confirmación no vuelve a persistirlo
+	again, err := service.CompleteVideoUpload(testVideoID, testUploadPCID, testUploadUserID)
+	require.NoError(t, err)
+	assert.True(t, again.AlreadyCompleted)
+	videoRepo.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestCompleteVideoUpload_RejectsUploadWithMissingChunks(t *testing.T) {
+	// Arrange
+	service, _, videoRepo := newUploadVideoService(t)
+	_, err := service.HandleUploadedVideoChunk(testUploadChunk(0, false))
+	require.NoError(t, err)
+
+	// Act
+	_, err = service.CompleteVideoUpload(testVideoID, testUploadPCID, testUploadUserID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrVideoUploadIncomplete)
+	videoRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+
+	// La subida sigue abierta y el último chunk la completa
+	last, err := service.HandleUploadedVideoChunk(testUploadChunk(1, true))
+	require.NoError(t, err)
+	assert.True(t, last.IsComplete)
+}
+
+func TestCompleteVideoUpload_UnknownVideo(t *testing.T) {
+	// Arrange
+	service, _, videoRepo := newUploadVideoService(t)
+
+	// Act
+	_, err := service.CompleteVideoUpload("missing-video", testUploadPCID, testUploadUserID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrVideoUploadNotFound)
+	videoRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestCompleteVideoUpload_RejectsAnotherPCOrUser(t *testing.T) {
+	owners := map[string][2]string{
+		"other pc":   {"other-pc", testUploadUserID},
+		"other user": {testUploadPCID, "other-user"},
+	}
+
+	for name, owner := range owners {
+		t.Run(name, func(t *testing.T) {
+			// Arrange - una subida con todos sus chunks cuyo último chunk no se pudo persistir
+			service, storage, videoRepo := newUploadVideoService(t)
+			_, err := service.HandleUploadedVideoChunk(testUploadChunk(0, false))
+			require.NoError(t, err)
+			storage.failErr = errors.New("disk full")
+			_, err = service.HandleUploadedVideoChunk(testUploadChunk(1, true))
+			require.Error(t, err)
+			storage.failErr = nil
+
+			// Act
+			_, pendingErr := service.CompleteVideoUpload(testVideoID, owner[0], owner[1])
+
+			// Assert - la subida ajena no se finaliza ni se confirma
+			assert.ErrorIs(t, pendingErr, ErrVideoUploadNotOwned)
+			videoRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+
+			_, err = service.CompleteVideoUpload(testVideoID, testUploadPCID, testUploadUserID)
+			require.NoError(t, err)
+			_, completedErr := service.CompleteVideoUpload(testVideoID, owner[0], owner[1])
+			assert.ErrorIs(t, completedErr, ErrVideoUploadNotOwned)
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package videoservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// uploadManifestFileName archivo con los datos de una subida por chunks en curso, junto a sus chunks
+const uploadManifestFileName = "upload.json"
+
+// assembledVideoFileName temporal donde se ensamblan los chunks antes de moverlo al almacenamiento
+const assembledVideoFileName = "assembled.mp4.tmp"
+
+// VideoUploadStatus chunks que el servidor ya tiene de una subida, para que el cliente reenvíe solo los que faltan
+type VideoUploadStatus struct {
+	VideoID        string `json:"video_id"`
+	SessionID      string `json:"session_id"`
+	TotalChunks    int    `json:"total_chunks"`
+	ReceivedChunks []int  `json:"received_chunks"`
+	MissingChunks  []int  `json:"missing_chunks"`
+	IsComplete     bool   `json:"is_complete"`
+}
+
+// uploadManifest datos persistidos de una subida para reconstruirla tras un reinicio del servidor
+type uploadManifest struct {
+	SessionID   string    `json:"session_id"`
+	PCID        string    `json:"pc_id"`
+	UserID      string    `json:"user_id"`
+	FileName    string    `json:"file_name"`
+	FileSize    int64     `json:"file_size"`
+	Duration    int       `json:"duration"`
+	TotalChunks int       `json:"total_chunks"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// GetVideoUploadStatus informa qué chunks de la subida ya se recibieron. Si el servidor se reinició a mitad
+// de la subida, se reconstruye a partir de los chunks guardados en disco. Solo el PC que inició la subida puede
+// consultarla (ErrVideoUploadNotOwned).
+func (vs *videoService) GetVideoUploadStatus(videoID, pcID string) (*VideoUploadStatus, error) {
+	vs.uploadMutex.Lock()
+	defer vs.uploadMutex.Unlock()
+
+	if completed, exists := vs.completedUploads[videoID]; exists {
+		if completed.pcID != pcID {
+			return nil, fmt.Errorf("%w: %s", ErrVideoUploadNotOwned, videoID)
+		}
+		received := make([]int, completed.result.TotalChunks)
+		for i := range received {
+			received[i] = i
+		}
+		return &VideoUploadStatus{
+			VideoID:        videoID,
+			TotalChunks:    completed.result.TotalChunks,
+			ReceivedChunks: received,
+			MissingChunks:  []int{},
+			IsComplete:     true,
+		}, nil
+	}
+
+	uploadSession, exists := vs.uploadSessionFor(videoID)
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrVideoUploadNotFound, videoID)
+	}
+	if uploadSession.PCID != pcID {
+		return nil, fmt.Errorf("%w: %s", ErrVideoUploadNotOwned, videoID)
+	}
+
+	uploadSession.mutex.RLock()
+	defer uploadSession.mutex.RUnlock()
+
+	status := &VideoUploadStatus{
+		VideoID:        videoID,
+		SessionID:      uploadSession.SessionID,
+		TotalChunks:    uploadSession.TotalChunks,
+		ReceivedChunks: make([]int, 0, len(uploadSession.Chunks)),
+		MissingChunks:  []int{},
+	}
+	for chunkIndex := range uploadSession.Chunks {
+		status.ReceivedChunks = append(status.ReceivedChunks, chunkIndex)
+	}
+	sort.Ints(status.ReceivedChunks)
+	for i := 0; i < uploadSession.TotalChunks; i++ {
+		if _, received := uploadSession.Chunks[i]; !received {
+			status.MissingChunks = append(status.MissingChunks, i)
+		}
+	}
+	return status, nil
+}
+
+// uploadSessionFor obtiene la subida en curso, restaurándola desde disco si no está en memoria. Requiere uploadMutex.
+func (vs *videoService) uploadSessionFor(videoID string) (*VideoUploadSession, bool) {
+	if uploadSession, exists := vs.uploadSessions[videoID]; exists {
+		return uploadSession, true
+	}
+
+	uploadSession, err := vs.restoreUploadSession(videoID)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Printf("⚠️ Warning: no se pudo restaurar la subida del video %s: %v\n", videoID, err)
+		}
+		return nil, false
+	}
+
+	vs.uploadSessions[videoID] = uploadSession
+	return uploadSession, true
+}
+
+// restoreUploadSession reconstruye una subida a partir de su manifiesto y los chunks guardados
+func (vs *videoService) restoreUploadSession(videoID string) (*VideoUploadSession, error) {
+	uploadDir, err := vs.uploadDir(videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := os.ReadFile(filepath.Join(uploadDir, uploadManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	var manifest uploadManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("manifiesto de subida inválido: %w", err)
+	}
+
+	entries, err := os.ReadDir(uploadDir)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo chunks de la subida: %w", err)
+	}
+
+	chunks := make(map[int][]byte)
+	for _, entry := range entries {
+		var chunkIndex int
+		if _, err := fmt.Sscanf(entry.Name(), "chunk_%06d.bin", &chunkIndex); err != nil || entry.Name() != uploadChunkFileName(chunkIndex) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(uploadDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo chunk %d: %w", chunkIndex, err)
+		}
+		chunks[chunkIndex] = data
+	}
+
+	return &VideoUploadSession{
+		VideoID:        videoID,
+		SessionID:      manifest.SessionID,
+		PCID:           manifest.PCID,
+		UserID:         manifest.UserID,
+		FileName:       manifest.FileName,
+		FileSize:       manifest.FileSize,
+		Duration:       manifest.Duration,
+		Chunks:         chunks,
+		TotalChunks:    manifest.TotalChunks,
+		ReceivedChunks: len(chunks),
+		CreatedAt:      manifest.CreatedAt,
+		LastChunkAt:    time.Now(),
+	}, nil
+}
+
+// persistUploadManifest guarda los datos de una subida nueva para poder reanudarla tras un reinicio
+func (vs *videoService) persistUploadManifest(uploadSession *VideoUploadSession) error {
+	uploadDir, err := vs.uploadDir(uploadSession.VideoID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+		return fmt.Errorf("error creando directorio de subida: %w", err)
+	}
+
+	raw, err := json.Marshal(uploadManifest{
+		SessionID:   uploadSession.SessionID,
+		PCID:        uploadSession.PCID,
+		UserID:      uploadSession.UserID,
+		FileName:    uploadSession.FileName,
+		FileSize:    uploadSession.FileSize,
+		Duration:    uploadSession.Duration,
+		TotalChunks: uploadSession.TotalChunks,
+		CreatedAt:   uploadSession.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("error serializando manifiesto de subida: %w", err)
+	}
+	return writeFileAtomically(filepath.Join(uploadDir, uploadManifestFileName), raw)
+}
+
+// persistUploadChunk guarda un chunk recibido junto al manifiesto de su subida
+func (vs *videoService) persistUploadChunk(videoID string, chunkIndex int, data []byte) error {
+	uploadDir, err := vs.uploadDir(videoID)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(filepath.Join(uploadDir, uploadChunkFileName(chunkIndex)), data)
+}
+
+// removeUploadDir elimina los chunks persistidos de una subida ya completada
+func (vs *videoService) removeUploadDir(videoID string) {
+	uploadDir, err := vs.uploadDir(videoID)
+	if err != nil {
+		return
+	}
+	if err := os.RemoveAll(uploadDir); err != nil {
+		fmt.Printf("⚠️ Warning: no se pudieron eliminar los chunks de la subida %s: %v\n", videoID, err)
+	}
+}
+
+// uploadDir directorio con el manifiesto y los chunks de una subida; rechaza IDs que saldrían de uploadsBaseDir
+func (vs *videoService) uploadDir(videoID string) (string, error) {
+	if videoID == "" || videoID == "." || videoID == ".." || strings.ContainsAny(videoID, `/\`) {
+		return "", fmt.Errorf("video_id inválido para la subida: %q", videoID)
+	}
+	return filepath.Join(vs.uploadsBaseDir, videoID), nil
+}
+
+// uploadChunkFileName nombre del archivo de un chunk de subida
+func uploadChunkFileName(chunkIndex int) string {
+	return fmt.Sprintf("chunk_%06d.bin", chunkIndex)
+}
+
+// writeFileAtomically escribe en un archivo temporal y lo renombra, para que un corte no deje un chunk a medias
+func writeFileAtomically(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("error escribiendo %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("error guardando %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package videoservice
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testResumableChunk chunk i de un video de tres chunks de 64KB
+func testResumableChunk(chunkIndex int) VideoChunk {
+	chunk := testUploadChunk(chunkIndex, chunkIndex == 2)
+	chunk.FileSize = 3 * 64 * 1024
+	return chunk
+}
+
+func TestGetVideoUploadStatus_ReportsChunksReceivedBeforeRestart(t *testing.T) {
+	// Arrange - el primer servidor recibe los chunks 0 y 1 de 3 y se reinicia
+	service, _, _ := newUploadVideoService(t)
+	for _, chunkIndex := range []int{0, 1} {
+		_, err := service.HandleUploadedVideoChunk(testResumableChunk(chunkIndex))
+		require.NoError(t, err)
+	}
+
+	restarted, storage, videoRepo := newUploadVideoService(t)
+	restarted.uploadsBaseDir = service.uploadsBaseDir
+
+	// Act
+	status, err := restarted.GetVideoUploadStatus(testVideoID, testUploadPCID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, testSessionID, status.SessionID)
+	assert.Equal(t, 3, status.TotalChunks)
+	assert.Equal(t, []int{0, 1}, status.ReceivedChunks)
+	assert.Equal(t, []int{2}, status.MissingChunks)
+	assert.False(t, status.IsComplete)
+
+	// Reenviar solo el chunk que falta completa el video con los chunks previos al reinicio
+	result, err := restarted.HandleUploadedVideoChunk(testResumableChunk(2))
+	require.NoError(t, err)
+	assert.True(t, result.IsComplete)
+	assert.Equal(t, []byte{0, 1, 2}, storage.saved[result.FilePath])
+	videoRepo.AssertNumberOfCalls(t, "Save", 1)
+
+	// Completada la subida, sus chunks persistidos se eliminan
+	_, err = os.Stat(filepath.Join(service.uploadsBaseDir, testVideoID))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGetVideoUploadStatus_UnknownVideo(t *testing.T) {
+	// Arrange
+	service, _, _ := newUploadVideoService(t)
+
+	// Act
+	_, err := service.GetVideoUploadStatus("missing-video", testUploadPCID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrVideoUploadNotFound)
+}
+
+func TestVideoUpload_RejectsStatusAndChunksFromAnotherPC(t *testing.T) {
+	// Arrange - la subida la inicia testUploadPCID y el servidor se reinicia
+	service, _, _ := newUploadVideoService(t)
+	_, err := service.HandleUploadedVideoChunk(testResumableChunk(0))
+	require.NoError(t, err)
+
+	restarted, _, _ := newUploadVideoService(t)
+	restarted.uploadsBaseDir = service.uploadsBaseDir
+	foreignChunk := testResumableChunk(1)
+	foreignChunk.PCID = "other-pc"
+
+	// Act
+	_, statusErr := restarted.GetVideoUploadStatus(testVideoID, "other-pc")
+	_, chunkErr := restarted.HandleUploadedVideoChunk(foreignChunk)
+
+	// Assert - la propiedad se conserva en el manifiesto y el chunk ajeno no se guarda
+	assert.ErrorIs(t, statusErr, ErrVideoUploadNotOwned)
+	assert.ErrorIs(t, chunkErr, ErrVideoUploadNotOwned)
+	status, err := restarted.GetVideoUploadStatus(testVideoID, testUploadPCID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0}, status.ReceivedChunks)
+}
+
+func TestHandleUploadedVideoChunk_RejectsChunkIndexOutOfRange(t *testing.T) {
+	for _, chunkIndex := range []int{-1, 3, 1000} {
+		t.Run(fmt.Sprint(chunkIndex), func(t *testing.T) {
+			// Arrange - subida de 3 chunks ya iniciada con el chunk 0
+			service, _, _ := newUploadVideoService(t)
+			_, err := service.HandleUploadedVideoChunk(testResumableChunk(0))
+			require.NoError(t, err)
+			chunk := testResumableChunk(0)
+			chunk.ChunkIndex = chunkIndex
+
+			// Act
+			_, err = service.HandleUploadedVideoChunk(chunk)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrVideoChunkOutOfRange)
+			_, statErr := os.Stat(filepath.Join(service.uploadsBaseDir, testVideoID, uploadChunkFileName(chunkIndex)))
+			assert.True(t, os.IsNotExist(statErr))
+		})
+	}
+}
+
+func TestHandleUploadedVideoChunk_OutOfRangeFirstChunkDoesNotStartUpload(t *testing.T) {
+	// Arrange
+	service, _, _ := newUploadVideoService(t)
+	chunk := testResumableChunk(0)
+	chunk.ChunkIndex = 5
+
+	// Act
+	_, err := service.HandleUploadedVideoChunk(chunk)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrVideoChunkOutOfRange)
+	_, statusErr := service.GetVideoUploadStatus(testVideoID, testUploadPCID)
+	assert.ErrorIs(t, statusErr, ErrVideoUploadNotFound)
+}
@@ -0,0 +1,111 @@
+package videoservice
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// DefaultVideoUploadTTL tiempo sin actividad tras el que una subida (por chunks o por partes) se da por abandonada
+	DefaultVideoUploadTTL = 24 * time.Hour
+	// DefaultVideoUploadSweepInterval cada cuánto se buscan subidas abandonadas
+	DefaultVideoUploadSweepInterval = time.Hour
+)
+
+// IVideoUploadSweepService elimina en segundo plano las subidas de grabaciones abandonadas
+type IVideoUploadSweepService interface {
+	SweepAbandonedUploads(ctx context.Context, ttl time.Duration) (int, error)
+}
+
+// SweepAbandonedUploads elimina los directorios de uploadsBaseDir sin actividad desde hace más de ttl, con su
+// manifiesto, sus chunks o sus datos por partes, y olvida las subidas en memoria igual de antiguas. La actividad
+// es el archivo modificado más recientemente del directorio, así una parte HTTP que se está recibiendo lo mantiene.
+// Retorna cuántas subidas eliminó; ttl <= 0 no elimina nada.
+func (vs *videoService) SweepAbandonedUploads(ctx context.Context, ttl time.Duration) (int, error) {
+	if ttl <= 0 {
+		return 0, nil
+	}
+
+	vs.uploadMutex.Lock()
+	defer vs.uploadMutex.Unlock()
+
+	cutoff := time.Now().Add(-ttl)
+	swept := 0
+
+	entries, err := os.ReadDir(vs.uploadsBaseDir)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("error leyendo subidas en curso: %w", err)
+	}
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return swept, err
+		}
+		if !entry.IsDir() {
+			continue
+		}
+		videoID := entry.Name()
+		if uploadSession, exists := vs.uploadSessions[videoID]; exists && uploadSession.LastChunkAt.After(cutoff) {
+			continue
+		}
+
+		uploadDir := filepath.Join(vs.uploadsBaseDir, videoID)
+		lastActivity, err := uploadDirLastActivity(uploadDir)
+		if err != nil {
+			fmt.Printf("⚠️ Warning: no se pudo revisar la subida %s: %v\n", videoID, err)
+			continue
+		}
+		if lastActivity.After(cutoff) {
+			continue
+		}
+
+		if err := os.RemoveAll(uploadDir); err != nil {
+			fmt.Printf("⚠️ Warning: no se pudo eliminar la subida abandonada %s: %v\n", videoID, err)
+			continue
+		}
+		delete(vs.uploadSessions, videoID)
+		swept++
+	}
+
+	// Subidas solo en memoria (su directorio no llegó a crearse o ya no existe)
+	for videoID, uploadSession := range vs.uploadSessions {
+		if _, err := os.Stat(filepath.Join(vs.uploadsBaseDir, videoID)); err == nil {
+			continue
+		}
+		if uploadSession.LastChunkAt.Before(cutoff) {
+			delete(vs.uploadSessions, videoID)
+			swept++
+		}
+	}
+
+	if swept > 0 {
+		fmt.Printf("🧹 VIDEO UPLOADS: %d subidas abandonadas eliminadas (sin actividad desde hace más de %s)\n", swept, ttl)
+	}
+	return swept, nil
+}
+
+// uploadDirLastActivity fecha de modificación más reciente entre el directorio de una subida y sus archivos
+func uploadDirLastActivity(uploadDir string) (time.Time, error) {
+	info, err := os.Stat(uploadDir)
+	if err != nil {
+		return time.Time{}, err
+	}
+	lastActivity := info.ModTime()
+
+	entries, err := os.ReadDir(uploadDir)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, entry := range entries {
+		fileInfo, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if fileInfo.ModTime().After(lastActivity) {
+			lastActivity = fileInfo.ModTime()
+		}
+	}
+	return lastActivity, nil
+}
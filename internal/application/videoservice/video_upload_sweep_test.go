@@ -0,0 +1,113 @@
+package videoservice
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ageUploadDir retrasa la fecha de modificación del directorio de una subida y de sus archivos
+func ageUploadDir(t *testing.T, uploadDir string, age time.Duration) {
+	t.Helper()
+
+	old := time.Now().Add(-age)
+	entries, err := os.ReadDir(uploadDir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		require.NoError(t, os.Chtimes(filepath.Join(uploadDir, entry.Name()), old, old))
+	}
+	require.NoError(t, os.Chtimes(uploadDir, old, old))
+}
+
+func TestSweepAbandonedUploads_RemovesOnlyUploadsIdleLongerThanTTL(t *testing.T) {
+	// Arrange - una subida por chunks que lleva dos días parada tras un reinicio y otra reciente
+	service, _, _ := newUploadVideoService(t)
+	_, err := service.HandleUploadedVideoChunk(testResumableChunk(0))
+	require.NoError(t, err)
+	recent := testResumableChunk(0)
+	recent.VideoID = "video-recent"
+	_, err = service.HandleUploadedVideoChunk(recent)
+	require.NoError(t, err)
+
+	restarted, _, _ := newUploadVideoService(t)
+	restarted.uploadsBaseDir = service.uploadsBaseDir
+	ageUploadDir(t, filepath.Join(restarted.uploadsBaseDir, testVideoID), 48*time.Hour)
+
+	// Act
+	swept, err := restarted.SweepAbandonedUploads(context.Background(), DefaultVideoUploadTTL)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, swept)
+	_, statErr := os.Stat(filepath.Join(restarted.uploadsBaseDir, testVideoID))
+	assert.True(t, os.IsNotExist(statErr))
+	_, err = restarted.GetVideoUploadStatus(testVideoID, testUploadPCID)
+	assert.ErrorIs(t, err, ErrVideoUploadNotFound)
+	status, err := restarted.GetVideoUploadStatus("video-recent", testUploadPCID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0}, status.ReceivedChunks)
+}
+
+func TestSweepAbandonedUploads_RemovesAbandonedPartUploads(t *testing.T) {
+	// Arrange - una subida HTTP por partes parada hace dos días (manifiesto y datos) y otra que sigue recibiendo
+	service, _, _ := newUploadVideoService(t)
+	ctx := context.Background()
+	_, err := service.WriteUploadPart(ctx, testUploadPart(0, "012"))
+	require.NoError(t, err)
+	recent := testUploadPart(0, "012")
+	recent.VideoID = "video-recent-part"
+	_, err = service.WriteUploadPart(ctx, recent)
+	require.NoError(t, err)
+	ageUploadDir(t, filepath.Join(service.uploadsBaseDir, testVideoID), 48*time.Hour)
+
+	// Act
+	swept, err := service.SweepAbandonedUploads(ctx, DefaultVideoUploadTTL)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, swept)
+	assert.NoDirExists(t, filepath.Join(service.uploadsBaseDir, testVideoID))
+	_, err = service.GetPartUploadProgress(testVideoID)
+	assert.ErrorIs(t, err, ErrVideoUploadNotFound)
+	progress, err := service.GetPartUploadProgress("video-recent-part")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), progress.ReceivedBytes)
+}
+
+func TestSweepAbandonedUploads_ForgetsIdleInMemoryUploads(t *testing.T) {
+	// Arrange - subida en memoria cuyo directorio no llegó a persistirse
+	service, _, _ := newUploadVideoService(t)
+	_, err := service.HandleUploadedVideoChunk(testResumableChunk(0))
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(filepath.Join(service.uploadsBaseDir, testVideoID)))
+	service.uploadSessions[testVideoID].LastChunkAt = time.Now().Add(-48 * time.Hour)
+
+	// Act
+	swept, err := service.SweepAbandonedUploads(context.Background(), DefaultVideoUploadTTL)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, swept)
+	assert.NotContains(t, service.uploadSessions, testVideoID)
+}
+
+func TestSweepAbandonedUploads_DisabledWithZeroTTL(t *testing.T) {
+	// Arrange
+	service, _, _ := newUploadVideoService(t)
+	_, err := service.HandleUploadedVideoChunk(testResumableChunk(0))
+	require.NoError(t, err)
+	ageUploadDir(t, filepath.Join(service.uploadsBaseDir, testVideoID), 48*time.Hour)
+
+	// Act
+	swept, err := service.SweepAbandonedUploads(context.Background(), 0)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Zero(t, swept)
+	assert.DirExists(t, filepath.Join(service.uploadsBaseDir, testVideoID))
+}
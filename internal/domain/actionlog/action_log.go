@@ -8,19 +8,30 @@ import (
 type ActionType string
 
 const (
-	ActionUserLogin             ActionType = "USER_LOGIN"
-	ActionUserLogout            ActionType = "USER_LOGOUT"
-	ActionUserCreated           ActionType = "USER_CREATED"
-	ActionPCRegistered          ActionType = "PC_REGISTERED"
-	ActionPCStatusChanged       ActionType = "PC_STATUS_CHANGED"
-	ActionRemoteSessionStarted  ActionType = "REMOTE_SESSION_STARTED"
-	ActionRemoteSessionEnded    ActionType = "REMOTE_SESSION_ENDED"
-	ActionFileTransferInitiated ActionType = "FILE_TRANSFER_INITIATED"
-	ActionFileTransferCompleted ActionType = "FILE_TRANSFER_COMPLETED"
-	ActionFileTransferFailed    ActionType = "FILE_TRANSFER_FAILED"
-	ActionVideoRecordingStarted ActionType = "VIDEO_RECORDING_STARTED"
-	ActionVideoRecordingEnded   ActionType = "VIDEO_RECORDING_ENDED"
-	ActionVideoUploaded         ActionType = "VIDEO_UPLOADED"
+	ActionUserLogin                 ActionType = "USER_LOGIN"
+	ActionUserLogout                ActionType = "USER_LOGOUT"
+	ActionUserCreated               ActionType = "USER_CREATED"
+	ActionPCRegistered              ActionType = "PC_REGISTERED"
+	ActionPCStatusChanged           ActionType = "PC_STATUS_CHANGED"
+	ActionRemoteSessionRequested    ActionType = "REMOTE_SESSION_REQUESTED"
+	ActionRemoteSessionStarted      ActionType = "REMOTE_SESSION_STARTED"
+	ActionRemoteSessionEnded        ActionType = "REMOTE_SESSION_ENDED"
+	ActionRemoteSessionAutoAccepted ActionType = "REMOTE_SESSION_AUTO_ACCEPTED"
+	ActionFileTransferInitiated     ActionType = "FILE_TRANSFER_INITIATED"
+	ActionFileTransferCompleted     ActionType = "FILE_TRANSFER_COMPLETED"
+	ActionFileTransferFailed        ActionType = "FILE_TRANSFER_FAILED"
+	ActionVideoRecordingStarted     ActionType = "VIDEO_RECORDING_STARTED"
+	ActionVideoRecordingEnded       ActionType = "VIDEO_RECORDING_ENDED"
+	ActionVideoUploaded             ActionType = "VIDEO_UPLOADED"
+	ActionVideoRecordingDisposed    ActionType = "VIDEO_RECORDING_DISPOSED"
+	ActionVideoRecordingEmpty       ActionType = "VIDEO_RECORDING_EMPTY"
+	ActionRemoteSessionTransferred  ActionType = "REMOTE_SESSION_TRANSFERRED"
+	ActionRecordingViewed           ActionType = "RECORDING_VIEWED"
+	ActionFileTransferViewed        ActionType = "FILE_TRANSFER_VIEWED"
+	ActionFileTransferDownloaded    ActionType = "FILE_TRANSFER_DOWNLOADED"
+	ActionPCPurged                  ActionType = "PC_PURGED"
+	ActionRemoteSessionActivity     ActionType = "REMOTE_SESSION_ACTIVITY"
+	ActionUserTokensRevoked         ActionType = "USER_TOKENS_REVOKED"
 )
 
 // ActionLog representa una entrada en el log de auditoría
@@ -2,6 +2,7 @@ package clientpc
 
 import (
 	"errors"
+	"net/netip"
 	"regexp"
 	"strings"
 	"time"
@@ -33,15 +34,34 @@ func (status PCConnectionStatus) String() string {
 
 // ClientPC represents a client PC registered in the system
 type ClientPC struct {
-	PCID             string             `json:"pcId" db:"pc_id"`
-	Identifier       string             `json:"identifier" db:"identifier"`
-	IP               string             `json:"ip" db:"ip"`
-	ConnectionStatus PCConnectionStatus `json:"connectionStatus" db:"connection_status"`
-	RegisteredAt     time.Time          `json:"registeredAt" db:"registered_at"`
-	OwnerUserID      string             `json:"ownerUserId" db:"owner_user_id"`
-	LastSeenAt       *time.Time         `json:"lastSeenAt" db:"last_seen_at"`
-	CreatedAt        time.Time          `json:"createdAt" db:"created_at"`
-	UpdatedAt        time.Time          `json:"updatedAt" db:"updated_at"`
+	PCID              string             `json:"pcId" db:"pc_id"`
+	Identifier        string             `json:"identifier" db:"identifier"`
+	IP                string             `json:"ip" db:"ip"`
+	ConnectionStatus  PCConnectionStatus `json:"connectionStatus" db:"connection_status"`
+	RegisteredAt      time.Time          `json:"registeredAt" db:"registered_at"`
+	OwnerUserID       string             `json:"ownerUserId" db:"owner_user_id"`
+	LastSeenAt        *time.Time         `json:"lastSeenAt" db:"last_seen_at"`
+	AutoAcceptControl bool               `json:"autoAcceptControl" db:"auto_accept_control"`
+	OS                string             `json:"os" db:"os"`
+	Hostname          string             `json:"hostname" db:"hostname"`
+	AgentVersion      string             `json:"agentVersion" db:"agent_version"`
+	CreatedAt         time.Time          `json:"createdAt" db:"created_at"`
+	UpdatedAt         time.Time          `json:"updatedAt" db:"updated_at"`
+}
+
+// Longitud máxima de los metadatos del agente (tamaño de las columnas en client_pcs)
+const (
+	MaxOSLength           = 64
+	MaxHostnameLength     = 255
+	MaxAgentVersionLength = 32
+)
+
+// PCMetadata datos opcionales que el agente informa al registrarse: sistema operativo, nombre de host y
+// versión del agente. Los clientes antiguos no los envían.
+type PCMetadata struct {
+	OS           string
+	Hostname     string
+	AgentVersion string
 }
 
 // NewClientPC creates a new ClientPC instance with validation
@@ -104,6 +124,38 @@ func (pc *ClientPC) UpdateLastSeen() {
 	pc.UpdatedAt = now
 }
 
+// SetAutoAcceptControl enables or disables automatic acceptance of remote control requests
+func (pc *ClientPC) SetAutoAcceptControl(enabled bool) {
+	pc.AutoAcceptControl = enabled
+	pc.UpdatedAt = time.Now()
+}
+
+// UpdateMetadata guarda los metadatos informados por el agente. Un campo vacío conserva el valor anterior
+// para que un cliente antiguo no borre lo que informó una versión más reciente; los valores demasiado
+// largos se recortan. Retorna true si algo cambió.
+func (pc *ClientPC) UpdateMetadata(metadata PCMetadata) bool {
+	changed := false
+	apply := func(field *string, value string, maxLength int) {
+		value = strings.TrimSpace(value)
+		if len(value) > maxLength {
+			value = strings.ToValidUTF8(value[:maxLength], "")
+		}
+		if value != "" && value != *field {
+			*field = value
+			changed = true
+		}
+	}
+
+	apply(&pc.OS, metadata.OS, MaxOSLength)
+	apply(&pc.Hostname, metadata.Hostname, MaxHostnameLength)
+	apply(&pc.AgentVersion, metadata.AgentVersion, MaxAgentVersionLength)
+
+	if changed {
+		pc.UpdatedAt = time.Now()
+	}
+	return changed
+}
+
 // IsOnline returns true if the PC is currently online
 func (pc *ClientPC) IsOnline() bool {
 	return pc.ConnectionStatus == PCConnectionStatusOnline
@@ -188,15 +240,24 @@ func validateIP(ip string) error {
 		return errors.New("IP address cannot be empty")
 	}
 
-	// Basic IP validation (IPv4)
-	ipRegex := regexp.MustCompile(`^(?:(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)\.){3}(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)$`)
-	if !ipRegex.MatchString(ip) {
-		return errors.New("invalid IP address format")
+	if _, err := NormalizeIP(ip); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// NormalizeIP valida una dirección IPv4/IPv6 y la devuelve en forma canónica: sin espacios ni zona,
+// IPv6 comprimida en minúsculas y las IPv4 mapeadas en IPv6 (::ffff:a.b.c.d) como IPv4
+func NormalizeIP(ip string) (string, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return "", errors.New("invalid IP address format")
+	}
+
+	return addr.WithZone("").Unmap().String(), nil
+}
+
 func validateOwnerUserID(ownerUserID string) error {
 	if strings.TrimSpace(ownerUserID) == "" {
 		return errors.New("owner user ID cannot be empty")
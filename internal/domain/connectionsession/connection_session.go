@@ -0,0 +1,93 @@
+package connectionsession
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxDisconnectReasonLength caracteres de disconnect_reason (VARCHAR(255)); los motivos más largos se recortan
+const MaxDisconnectReasonLength = 255
+
+// ConnectionSession representa un periodo de conexión WebSocket de un PC cliente,
+// persistido para auditoría y diagnóstico una vez que la conexión se cierra
+type ConnectionSession struct {
+	connectionID     string
+	pcID             string
+	ipAddress        string
+	connectedAt      time.Time
+	disconnectedAt   *time.Time
+	disconnectReason string
+}
+
+// NewConnectionSession crea una nueva sesión de conexión abierta
+func NewConnectionSession(pcID, ipAddress string) (*ConnectionSession, error) {
+	if pcID == "" {
+		return nil, errors.New("PC ID cannot be empty")
+	}
+
+	return &ConnectionSession{
+		connectionID: uuid.New().String(),
+		pcID:         pcID,
+		ipAddress:    ipAddress,
+		connectedAt:  time.Now(),
+	}, nil
+}
+
+// NewConnectionSessionFromDB reconstruye una ConnectionSession desde base de datos
+func NewConnectionSessionFromDB(
+	connectionID string,
+	pcID string,
+	ipAddress string,
+	connectedAt time.Time,
+	disconnectedAt *time.Time,
+	disconnectReason string,
+) *ConnectionSession {
+	return &ConnectionSession{
+		connectionID:     connectionID,
+		pcID:             pcID,
+		ipAddress:        ipAddress,
+		connectedAt:      connectedAt,
+		disconnectedAt:   disconnectedAt,
+		disconnectReason: disconnectReason,
+	}
+}
+
+// Getters
+func (cs *ConnectionSession) ConnectionID() string       { return cs.connectionID }
+func (cs *ConnectionSession) PCID() string               { return cs.pcID }
+func (cs *ConnectionSession) IPAddress() string          { return cs.ipAddress }
+func (cs *ConnectionSession) ConnectedAt() time.Time     { return cs.connectedAt }
+func (cs *ConnectionSession) DisconnectedAt() *time.Time { return cs.disconnectedAt }
+func (cs *ConnectionSession) DisconnectReason() string   { return cs.disconnectReason }
+
+// IsOpen indica si la conexión sigue abierta
+func (cs *ConnectionSession) IsOpen() bool {
+	return cs.disconnectedAt == nil
+}
+
+// Close marca la sesión de conexión como cerrada con el motivo indicado, recortado a MaxDisconnectReasonLength
+func (cs *ConnectionSession) Close(reason string) error {
+	if !cs.IsOpen() {
+		return errors.New("connection session already closed")
+	}
+
+	if utf8.RuneCountInString(reason) > MaxDisconnectReasonLength {
+		reason = string([]rune(reason)[:MaxDisconnectReasonLength])
+	}
+
+	now := time.Now()
+	cs.disconnectedAt = &now
+	cs.disconnectReason = reason
+	return nil
+}
+
+// GetDuration calcula la duración de la conexión (hasta ahora si sigue abierta)
+func (cs *ConnectionSession) GetDuration() time.Duration {
+	if cs.disconnectedAt == nil {
+		return time.Since(cs.connectedAt)
+	}
+	return cs.disconnectedAt.Sub(cs.connectedAt)
+}
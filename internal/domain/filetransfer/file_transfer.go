@@ -1,6 +1,9 @@
 package filetransfer
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,21 +17,81 @@ const (
 	TransferStatusInProgress TransferStatus = "IN_PROGRESS"
 	TransferStatusCompleted  TransferStatus = "COMPLETED"
 	TransferStatusFailed     TransferStatus = "FAILED"
+	// TransferStatusInsufficientClientSpace el cliente reportó espacio libre insuficiente antes de enviar el archivo
+	TransferStatusInsufficientClientSpace TransferStatus = "INSUFFICIENT_CLIENT_SPACE"
 )
 
+// ErrInvalidStatusTransition se intentó pasar la transferencia a un estado no permitido desde el actual
+var ErrInvalidStatusTransition = errors.New("invalid file transfer status transition")
+
+// allowedTransitions estados a los que puede pasar una transferencia desde cada estado.
+// COMPLETED, FAILED e INSUFFICIENT_CLIENT_SPACE son finales.
+var allowedTransitions = map[TransferStatus][]TransferStatus{
+	TransferStatusPending:    {TransferStatusInProgress, TransferStatusFailed, TransferStatusInsufficientClientSpace},
+	TransferStatusInProgress: {TransferStatusCompleted, TransferStatusFailed},
+}
+
+// CanTransition indica si una transferencia puede pasar del estado from al estado to
+func CanTransition(from, to TransferStatus) bool {
+	for _, allowed := range allowedTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal indica si el estado es final (la transferencia ya no cambia)
+func (s TransferStatus) IsFinal() bool {
+	return len(allowedTransitions[s]) == 0
+}
+
+// ConflictPolicy qué hace el cliente si en la carpeta de destino ya existe un archivo con el mismo nombre
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyOverwrite reemplaza el archivo existente
+	ConflictPolicyOverwrite ConflictPolicy = "overwrite"
+	// ConflictPolicyRename guarda el nuevo con un sufijo numérico: "informe (1).pdf", "informe (2).pdf", ...
+	ConflictPolicyRename ConflictPolicy = "rename"
+	// ConflictPolicySkip conserva el archivo existente y descarta el recibido
+	ConflictPolicySkip ConflictPolicy = "skip"
+
+	// DefaultConflictPolicy política cuando la solicitud no indica ninguna: envíos repetidos no se pisan
+	DefaultConflictPolicy = ConflictPolicyRename
+)
+
+// ErrInvalidConflictPolicy la política de conflicto de nombres no es overwrite, rename ni skip
+var ErrInvalidConflictPolicy = errors.New("invalid file name conflict policy")
+
+// ParseConflictPolicy convierte el valor recibido en una política; vacío equivale a DefaultConflictPolicy
+func ParseConflictPolicy(value string) (ConflictPolicy, error) {
+	policy := ConflictPolicy(strings.ToLower(strings.TrimSpace(value)))
+	switch policy {
+	case "":
+		return DefaultConflictPolicy, nil
+	case ConflictPolicyOverwrite, ConflictPolicyRename, ConflictPolicySkip:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidConflictPolicy, value)
+	}
+}
+
 // FileTransfer representa una transferencia de archivo del servidor al cliente
 type FileTransfer struct {
 	transferID           string
 	fileName             string
 	sourcePathServer     string
 	destinationPathClient string
-	transferTime         time.Time
+	// transferTime momento en que la transferencia llegó a COMPLETED; nil mientras no se haya completado
+	transferTime         *time.Time
 	status               TransferStatus
 	associatedSessionID  string
 	initiatingUserID     string
 	targetPCID          string
 	fileSizeMB          float64
 	errorMessage        string
+	conflictPolicy      ConflictPolicy
 	createdAt           time.Time
 	updatedAt           time.Time
 }
@@ -48,12 +111,12 @@ func NewFileTransfer(
 		fileName:             fileName,
 		sourcePathServer:     sourcePathServer,
 		destinationPathClient: destinationPathClient,
-		transferTime:         time.Now(),
 		status:               TransferStatusPending,
 		associatedSessionID:  associatedSessionID,
 		initiatingUserID:     initiatingUserID,
 		targetPCID:          targetPCID,
 		fileSizeMB:          fileSizeMB,
+		conflictPolicy:      DefaultConflictPolicy,
 		createdAt:           time.Now(),
 		updatedAt:           time.Now(),
 	}
@@ -65,13 +128,14 @@ func NewFileTransferFromDB(
 	fileName string,
 	sourcePathServer string,
 	destinationPathClient string,
-	transferTime time.Time,
+	transferTime *time.Time,
 	status TransferStatus,
 	associatedSessionID string,
 	initiatingUserID string,
 	targetPCID string,
 	fileSizeMB float64,
 	errorMessage string,
+	conflictPolicy ConflictPolicy,
 	createdAt time.Time,
 	updatedAt time.Time,
 ) *FileTransfer {
@@ -87,6 +151,7 @@ func NewFileTransferFromDB(
 		targetPCID:          targetPCID,
 		fileSizeMB:          fileSizeMB,
 		errorMessage:        errorMessage,
+		conflictPolicy:      conflictPolicy,
 		createdAt:           createdAt,
 		updatedAt:           updatedAt,
 	}
@@ -97,36 +162,60 @@ func (ft *FileTransfer) TransferID() string           { return ft.transferID }
 func (ft *FileTransfer) FileName() string            { return ft.fileName }
 func (ft *FileTransfer) SourcePathServer() string    { return ft.sourcePathServer }
 func (ft *FileTransfer) DestinationPathClient() string { return ft.destinationPathClient }
-func (ft *FileTransfer) TransferTime() time.Time     { return ft.transferTime }
+func (ft *FileTransfer) TransferTime() *time.Time    { return ft.transferTime }
 func (ft *FileTransfer) Status() TransferStatus      { return ft.status }
 func (ft *FileTransfer) AssociatedSessionID() string { return ft.associatedSessionID }
 func (ft *FileTransfer) InitiatingUserID() string    { return ft.initiatingUserID }
 func (ft *FileTransfer) TargetPCID() string         { return ft.targetPCID }
 func (ft *FileTransfer) FileSizeMB() float64         { return ft.fileSizeMB }
 func (ft *FileTransfer) ErrorMessage() string        { return ft.errorMessage }
+func (ft *FileTransfer) ConflictPolicy() ConflictPolicy { return ft.conflictPolicy }
 func (ft *FileTransfer) CreatedAt() time.Time        { return ft.createdAt }
 func (ft *FileTransfer) UpdatedAt() time.Time        { return ft.updatedAt }
 
-// UpdateStatus actualiza el estado de la transferencia
-func (ft *FileTransfer) UpdateStatus(status TransferStatus, errorMessage string) {
+// UpdateStatus actualiza el estado de la transferencia si la transición está permitida
+// (PENDING→IN_PROGRESS→COMPLETED/FAILED; PENDING también puede fallar o quedarse sin espacio en el cliente).
+// Al llegar a COMPLETED fija TransferTime, distinto de CreatedAt para las transferencias que esperaron en cola.
+func (ft *FileTransfer) UpdateStatus(status TransferStatus, errorMessage string) error {
+	if !CanTransition(ft.status, status) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, ft.status, status)
+	}
+
+	now := time.Now()
 	ft.status = status
 	ft.errorMessage = errorMessage
+	ft.updatedAt = now
+	if status == TransferStatusCompleted {
+		ft.transferTime = &now
+	}
+	return nil
+}
+
+// SetConflictPolicy fija la política de conflicto de nombres; vacía equivale a DefaultConflictPolicy
+func (ft *FileTransfer) SetConflictPolicy(policy ConflictPolicy) error {
+	parsed, err := ParseConflictPolicy(string(policy))
+	if err != nil {
+		return err
+	}
+
+	ft.conflictPolicy = parsed
 	ft.updatedAt = time.Now()
+	return nil
 }
 
 // SetInProgress marca la transferencia como en progreso
-func (ft *FileTransfer) SetInProgress() {
-	ft.UpdateStatus(TransferStatusInProgress, "")
+func (ft *FileTransfer) SetInProgress() error {
+	return ft.UpdateStatus(TransferStatusInProgress, "")
 }
 
 // SetCompleted marca la transferencia como completada
-func (ft *FileTransfer) SetCompleted() {
-	ft.UpdateStatus(TransferStatusCompleted, "")
+func (ft *FileTransfer) SetCompleted() error {
+	return ft.UpdateStatus(TransferStatusCompleted, "")
 }
 
 // SetFailed marca la transferencia como fallida
-func (ft *FileTransfer) SetFailed(errorMessage string) {
-	ft.UpdateStatus(TransferStatusFailed, errorMessage)
+func (ft *FileTransfer) SetFailed(errorMessage string) error {
+	return ft.UpdateStatus(TransferStatusFailed, errorMessage)
 }
 
 // IsCompleted verifica si la transferencia está completada
@@ -0,0 +1,171 @@
+package filetransfer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transferInStatus crea una transferencia en el estado indicado, como si viniera de la BD
+func transferInStatus(status TransferStatus) *FileTransfer {
+	transfer := NewFileTransfer("report.pdf", "/srv/report.pdf", "Descargas/report.pdf", "session-1", "admin-1", "pc-1", 1)
+	transfer.status = status
+	return transfer
+}
+
+func TestFileTransfer_UpdateStatus_ValidTransitions(t *testing.T) {
+	validTransitions := []struct {
+		from TransferStatus
+		to   TransferStatus
+	}{
+		{TransferStatusPending, TransferStatusInProgress},
+		{TransferStatusPending, TransferStatusFailed},
+		{TransferStatusPending, TransferStatusInsufficientClientSpace},
+		{TransferStatusInProgress, TransferStatusCompleted},
+		{TransferStatusInProgress, TransferStatusFailed},
+	}
+
+	for _, tc := range validTransitions {
+		t.Run(string(tc.from)+"->"+string(tc.to), func(t *testing.T) {
+			// Arrange
+			transfer := transferInStatus(tc.from)
+
+			// Act
+			err := transfer.UpdateStatus(tc.to, "detalle")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tc.to, transfer.Status())
+			assert.Equal(t, "detalle", transfer.ErrorMessage())
+		})
+	}
+}
+
+func TestFileTransfer_UpdateStatus_RejectsInvalidTransitions(t *testing.T) {
+	invalidTransitions := []struct {
+		from TransferStatus
+		to   TransferStatus
+	}{
+		{TransferStatusCompleted, TransferStatusInProgress},
+		{TransferStatusCompleted, TransferStatusFailed},
+		{TransferStatusFailed, TransferStatusInProgress},
+		{TransferStatusFailed, TransferStatusCompleted},
+		{TransferStatusInsufficientClientSpace, TransferStatusInProgress},
+		{TransferStatusPending, TransferStatusCompleted},
+		{TransferStatusInProgress, TransferStatusPending},
+		{TransferStatusInProgress, TransferStatusInsufficientClientSpace},
+		{TransferStatusPending, TransferStatus("UNKNOWN")},
+	}
+
+	for _, tc := range invalidTransitions {
+		t.Run(string(tc.from)+"->"+string(tc.to), func(t *testing.T) {
+			// Arrange
+			transfer := transferInStatus(tc.from)
+			transfer.errorMessage = "original"
+
+			// Act
+			err := transfer.UpdateStatus(tc.to, "")
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+			assert.Equal(t, tc.from, transfer.Status())
+			assert.Equal(t, "original", transfer.ErrorMessage())
+		})
+	}
+}
+
+func TestTransferStatus_IsFinal(t *testing.T) {
+	// Act & Assert
+	assert.False(t, TransferStatusPending.IsFinal())
+	assert.False(t, TransferStatusInProgress.IsFinal())
+	assert.True(t, TransferStatusCompleted.IsFinal())
+	assert.True(t, TransferStatusFailed.IsFinal())
+	assert.True(t, TransferStatusInsufficientClientSpace.IsFinal())
+}
+
+func TestFileTransfer_ConflictPolicy_DefaultsToRename(t *testing.T) {
+	// Arrange & Act
+	transfer := transferInStatus(TransferStatusPending)
+
+	// Assert
+	assert.Equal(t, ConflictPolicyRename, transfer.ConflictPolicy())
+}
+
+func TestFileTransfer_SetConflictPolicy_RecordsChosenPolicy(t *testing.T) {
+	for _, policy := range []ConflictPolicy{ConflictPolicyOverwrite, ConflictPolicyRename, ConflictPolicySkip} {
+		t.Run(string(policy), func(t *testing.T) {
+			// Arrange
+			transfer := transferInStatus(TransferStatusPending)
+
+			// Act
+			err := transfer.SetConflictPolicy(policy)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, policy, transfer.ConflictPolicy())
+		})
+	}
+}
+
+func TestFileTransfer_SetConflictPolicy_RejectsUnknownPolicy(t *testing.T) {
+	// Arrange
+	transfer := transferInStatus(TransferStatusPending)
+
+	// Act
+	err := transfer.SetConflictPolicy("merge")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidConflictPolicy)
+	assert.Equal(t, DefaultConflictPolicy, transfer.ConflictPolicy())
+}
+
+func TestParseConflictPolicy(t *testing.T) {
+	cases := map[string]ConflictPolicy{
+		"":          ConflictPolicyRename,
+		"  ":        ConflictPolicyRename,
+		"overwrite": ConflictPolicyOverwrite,
+		" SKIP ":    ConflictPolicySkip,
+		"Rename":    ConflictPolicyRename,
+	}
+
+	for value, expected := range cases {
+		// Act
+		policy, err := ParseConflictPolicy(value)
+
+		// Assert
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, policy, value)
+	}
+}
+
+func TestFileTransfer_TransferTimeIsSetAtCompletionNotCreation(t *testing.T) {
+	// Arrange - una transferencia creada hace dos días que esperó en cola a que el PC volviera
+	createdAt := time.Now().Add(-48 * time.Hour)
+	transfer := NewFileTransferFromDB("transfer-1", "report.pdf", "/srv/report.pdf", "Descargas/report.pdf",
+		nil, TransferStatusPending, "session-1", "admin-1", "pc-1", 1, "", DefaultConflictPolicy, createdAt, createdAt)
+	assert.Nil(t, NewFileTransfer("report.pdf", "/srv/report.pdf", "Descargas/report.pdf", "session-1", "admin-1", "pc-1", 1).TransferTime())
+
+	// Act
+	require.NoError(t, transfer.SetInProgress())
+	inProgressTime := transfer.TransferTime()
+	require.NoError(t, transfer.SetCompleted())
+
+	// Assert
+	assert.Nil(t, inProgressTime)
+	require.NotNil(t, transfer.TransferTime())
+	assert.WithinDuration(t, time.Now(), *transfer.TransferTime(), time.Second)
+	assert.Equal(t, createdAt, transfer.CreatedAt())
+}
+
+func TestFileTransfer_FailedTransferHasNoTransferTime(t *testing.T) {
+	// Arrange
+	transfer := transferInStatus(TransferStatusInProgress)
+
+	// Act
+	require.NoError(t, transfer.SetFailed("disk error"))
+
+	// Assert
+	assert.Nil(t, transfer.TransferTime())
+}
@@ -0,0 +1,133 @@
+package macro
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Límites de una macro
+const (
+	// MaxNameLength longitud máxima del nombre de una macro
+	MaxNameLength = 100
+	// MaxCommands número máximo de comandos que puede contener una macro
+	MaxCommands = 5000
+	// MaxCommandDelay espera máxima entre dos comandos al reproducir; pausas mayores se recortan
+	MaxCommandDelay = 10 * time.Second
+)
+
+var (
+	// ErrEmptyName indica que la macro no tiene nombre
+	ErrEmptyName = errors.New("macro name cannot be empty")
+	// ErrNameTooLong indica que el nombre supera MaxNameLength
+	ErrNameTooLong = errors.New("macro name is too long")
+	// ErrNoCommands indica que la macro no contiene comandos
+	ErrNoCommands = errors.New("macro must contain at least one command")
+	// ErrTooManyCommands indica que la macro supera MaxCommands
+	ErrTooManyCommands = errors.New("macro contains too many commands")
+)
+
+// Command comando de input grabado; Delay es la espera desde el comando anterior (0 en el primero)
+type Command struct {
+	EventType string
+	Action    string
+	Payload   map[string]interface{}
+	Delay     time.Duration
+}
+
+// Macro secuencia con nombre de comandos de input grabados durante una sesión de control remoto,
+// reproducible después en otra sesión del mismo administrador
+type Macro struct {
+	macroID         string
+	adminUserID     string
+	name            string
+	sourceSessionID string
+	commands        []Command
+	createdAt       time.Time
+}
+
+// NewMacro crea una macro validando nombre y comandos; las esperas se acotan a [0, MaxCommandDelay]
+func NewMacro(adminUserID, name, sourceSessionID string, commands []Command) (*Macro, error) {
+	if adminUserID == "" {
+		return nil, errors.New("admin user ID cannot be empty")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+	if len(name) > MaxNameLength {
+		return nil, ErrNameTooLong
+	}
+	if len(commands) == 0 {
+		return nil, ErrNoCommands
+	}
+	if len(commands) > MaxCommands {
+		return nil, ErrTooManyCommands
+	}
+
+	normalized := make([]Command, len(commands))
+	for i, command := range commands {
+		command.Delay = min(max(command.Delay, 0), MaxCommandDelay)
+		if i == 0 {
+			command.Delay = 0
+		}
+		normalized[i] = command
+	}
+
+	return &Macro{
+		macroID:         uuid.New().String(),
+		adminUserID:     adminUserID,
+		name:            name,
+		sourceSessionID: sourceSessionID,
+		commands:        normalized,
+		createdAt:       time.Now(),
+	}, nil
+}
+
+// NewMacroFromDB reconstruye una Macro desde base de datos
+func NewMacroFromDB(
+	macroID string,
+	adminUserID string,
+	name string,
+	sourceSessionID string,
+	commands []Command,
+	createdAt time.Time,
+) *Macro {
+	return &Macro{
+		macroID:         macroID,
+		adminUserID:     adminUserID,
+		name:            name,
+		sourceSessionID: sourceSessionID,
+		commands:        commands,
+		createdAt:       createdAt,
+	}
+}
+
+// Getters
+func (m *Macro) MacroID() string         { return m.macroID }
+func (m *Macro) AdminUserID() string     { return m.adminUserID }
+func (m *Macro) Name() string            { return m.name }
+func (m *Macro) SourceSessionID() string { return m.sourceSessionID }
+func (m *Macro) CreatedAt() time.Time    { return m.createdAt }
+
+// Commands retorna una copia de la secuencia de comandos
+func (m *Macro) Commands() []Command {
+	return append([]Command(nil), m.commands...)
+}
+
+// CommandCount número de comandos de la macro
+func (m *Macro) CommandCount() int {
+	return len(m.commands)
+}
+
+// Duration suma de las esperas entre comandos (duración aproximada de la reproducción)
+func (m *Macro) Duration() time.Duration {
+	var total time.Duration
+	for _, command := range m.commands {
+		total += command.Delay
+	}
+	return total
+}
@@ -0,0 +1,54 @@
+package macro
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMacro_NormalizesDelays(t *testing.T) {
+	// Arrange
+	commands := []Command{
+		{EventType: "mouse", Action: "click", Delay: 3 * time.Second},
+		{EventType: "keyboard", Action: "type", Delay: -time.Second},
+		{EventType: "keyboard", Action: "keydown", Delay: time.Minute},
+	}
+
+	// Act
+	m, err := NewMacro("admin-1", "  Reset spooler  ", "session-1", commands)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Reset spooler", m.Name())
+	assert.Equal(t, []time.Duration{0, 0, MaxCommandDelay}, []time.Duration{m.Commands()[0].Delay, m.Commands()[1].Delay, m.Commands()[2].Delay})
+	assert.Equal(t, MaxCommandDelay, m.Duration())
+}
+
+func TestNewMacro_Validation(t *testing.T) {
+	oneCommand := []Command{{EventType: "mouse", Action: "click"}}
+
+	testCases := []struct {
+		name        string
+		macroName   string
+		commands    []Command
+		expectedErr error
+	}{
+		{"empty name", " ", oneCommand, ErrEmptyName},
+		{"name too long", strings.Repeat("a", MaxNameLength+1), oneCommand, ErrNameTooLong},
+		{"no commands", "Macro", nil, ErrNoCommands},
+		{"too many commands", "Macro", make([]Command, MaxCommands+1), ErrTooManyCommands},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			_, err := NewMacro("admin-1", tc.macroName, "session-1", tc.commands)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package remotesession
+
+import "fmt"
+
+// Códigos de cierre WebSocket relevantes (RFC 6455, sección 7.4.1)
+const (
+	CloseCodeNone            = 0    // La conexión terminó sin frame de cierre ni código conocido
+	CloseCodeNormal          = 1000 // Cierre limpio solicitado por el cliente (logout)
+	CloseCodeGoingAway       = 1001 // El cliente se está cerrando (aplicación terminada)
+	CloseCodeProtocolError   = 1002
+	CloseCodeAbnormalClosure = 1006 // Caída de red sin frame de cierre
+	CloseCodeInternalError   = 1011
+)
+
+// DisconnectReason describe por qué se cerró la conexión de un PC cliente
+type DisconnectReason struct {
+	CloseCode int
+	CloseText string
+}
+
+// NewDisconnectReason crea un DisconnectReason a partir del código y texto de cierre
+func NewDisconnectReason(closeCode int, closeText string) DisconnectReason {
+	return DisconnectReason{
+		CloseCode: closeCode,
+		CloseText: closeText,
+	}
+}
+
+// NewClientShutdownReason crea el motivo de un cierre anunciado por el cliente con client_shutdown;
+// se trata como cierre limpio aunque el socket termine después sin frame de cierre
+func NewClientShutdownReason(reason string) DisconnectReason {
+	closeText := "client_shutdown"
+	if reason != "" {
+		closeText = closeText + ": " + reason
+	}
+	return NewDisconnectReason(CloseCodeGoingAway, closeText)
+}
+
+// IsClean indica si el cliente cerró la conexión de forma ordenada
+func (r DisconnectReason) IsClean() bool {
+	return r.CloseCode == CloseCodeNormal || r.CloseCode == CloseCodeGoingAway
+}
+
+// EndStatus devuelve el estado final de una sesión activa interrumpida por esta desconexión:
+// un cierre limpio finaliza la sesión por el cliente, cualquier otro cierre la marca como fallida
+func (r DisconnectReason) EndStatus() SessionStatus {
+	if r.IsClean() {
+		return StatusEndedByClient
+	}
+	return StatusFailed
+}
+
+// String retorna una descripción legible para logs y auditoría
+func (r DisconnectReason) String() string {
+	label := "abnormal"
+	if r.IsClean() {
+		label = "clean"
+	}
+	if r.CloseText == "" {
+		return fmt.Sprintf("%s close (code %d)", label, r.CloseCode)
+	}
+	return fmt.Sprintf("%s close (code %d): %s", label, r.CloseCode, r.CloseText)
+}
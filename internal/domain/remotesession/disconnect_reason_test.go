@@ -0,0 +1,64 @@
+package remotesession
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisconnectReason_EndStatusByCloseCode(t *testing.T) {
+	testCases := []struct {
+		name           string
+		closeCode      int
+		expectedClean  bool
+		expectedStatus SessionStatus
+	}{
+		{"normal closure", CloseCodeNormal, true, StatusEndedByClient},
+		{"going away", CloseCodeGoingAway, true, StatusEndedByClient},
+		{"protocol error", CloseCodeProtocolError, false, StatusFailed},
+		{"abnormal closure", CloseCodeAbnormalClosure, false, StatusFailed},
+		{"internal error", CloseCodeInternalError, false, StatusFailed},
+		{"no close frame", CloseCodeNone, false, StatusFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			reason := NewDisconnectReason(tc.closeCode, "")
+
+			// Act & Assert
+			assert.Equal(t, tc.expectedClean, reason.IsClean())
+			assert.Equal(t, tc.expectedStatus, reason.EndStatus())
+		})
+	}
+}
+
+func TestDisconnectReason_EndStatusIsValidEndStatus(t *testing.T) {
+	for _, code := range []int{CloseCodeNormal, CloseCodeAbnormalClosure} {
+		// Arrange
+		session, _ := NewRemoteSession("admin-id", "pc-id")
+		_ = session.Accept()
+
+		// Act
+		err := session.End(NewDisconnectReason(code, "").EndStatus())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, session.IsCompleted())
+	}
+}
+
+func TestDisconnectReason_String(t *testing.T) {
+	assert.Equal(t, "clean close (code 1000): logout", NewDisconnectReason(CloseCodeNormal, "logout").String())
+	assert.Equal(t, "abnormal close (code 1006)", NewDisconnectReason(CloseCodeAbnormalClosure, "").String())
+}
+
+func TestNewClientShutdownReason_IsCleanEnd(t *testing.T) {
+	// Arrange & Act
+	reason := NewClientShutdownReason("user quit")
+
+	// Assert
+	assert.True(t, reason.IsClean())
+	assert.Equal(t, StatusEndedByClient, reason.EndStatus())
+	assert.Equal(t, "clean close (code 1001): client_shutdown: user quit", reason.String())
+}
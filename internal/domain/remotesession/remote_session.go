@@ -2,6 +2,7 @@ package remotesession
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,6 +19,7 @@ const (
 	StatusEndedByClient   SessionStatus = "ENDED_BY_CLIENT"
 	StatusRejected        SessionStatus = "REJECTED"
 	StatusFailed          SessionStatus = "FAILED"
+	StatusQueued          SessionStatus = "QUEUED" // esperando a que el PC se conecte para enviar la solicitud
 )
 
 // RemoteSession representa la entidad de sesión de control remoto
@@ -29,6 +31,7 @@ type RemoteSession struct {
 	endTime      *time.Time
 	status       SessionStatus
 	sessionVideoID *string
+	justification Justification
 	createdAt    time.Time
 	updatedAt    time.Time
 }
@@ -58,6 +61,17 @@ func NewRemoteSession(adminUserID, clientPCID string) (*RemoteSession, error) {
 	}, nil
 }
 
+// NewQueuedRemoteSession crea una sesión en cola para un PC desconectado.
+// La solicitud se entrega al cliente cuando el PC vuelve a registrarse (ver Dequeue).
+func NewQueuedRemoteSession(adminUserID, clientPCID string) (*RemoteSession, error) {
+	session, err := NewRemoteSession(adminUserID, clientPCID)
+	if err != nil {
+		return nil, err
+	}
+	session.status = StatusQueued
+	return session, nil
+}
+
 // NewRemoteSessionFromDB crea una sesión remota desde datos de base de datos
 func NewRemoteSessionFromDB(
 	sessionID, adminUserID, clientPCID string,
@@ -118,8 +132,8 @@ func (rs *RemoteSession) UpdatedAt() time.Time {
 
 // Accept acepta la sesión y la marca como activa
 func (rs *RemoteSession) Accept() error {
-	if !rs.CanAccept() {
-		return errors.New("session cannot be accepted in current state")
+	if err := rs.checkTransition(OperationAccept, StatusActive); err != nil {
+		return err
 	}
 
 	now := time.Now().UTC()
@@ -132,8 +146,8 @@ func (rs *RemoteSession) Accept() error {
 
 // Reject rechaza la sesión
 func (rs *RemoteSession) Reject() error {
-	if !rs.CanReject() {
-		return errors.New("session cannot be rejected in current state")
+	if err := rs.checkTransition(OperationReject, StatusRejected); err != nil {
+		return err
 	}
 
 	rs.status = StatusRejected
@@ -142,14 +156,59 @@ func (rs *RemoteSession) Reject() error {
 	return nil
 }
 
-// End finaliza la sesión con el estado especificado
-func (rs *RemoteSession) End(endStatus SessionStatus) error {
-	if !rs.CanEnd() {
-		return errors.New("session cannot be ended in current state")
+// Dequeue pasa una sesión en cola a pendiente de aprobación al entregarse la solicitud al cliente
+func (rs *RemoteSession) Dequeue() error {
+	if err := rs.checkTransition(OperationDequeue, StatusPendingApproval); err != nil {
+		return err
 	}
 
+	rs.status = StatusPendingApproval
+	rs.updatedAt = time.Now().UTC()
+
+	return nil
+}
+
+// ExpireQueue marca como fallida una sesión en cola cuyo PC no se conectó a tiempo
+func (rs *RemoteSession) ExpireQueue() error {
+	if err := rs.checkTransition(OperationExpireQueue, StatusFailed); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	rs.status = StatusFailed
+	rs.endTime = &now
+	rs.updatedAt = now
+
+	return nil
+}
+
+// TransferOwnership entrega una sesión activa a otro administrador sin interrumpirla
+func (rs *RemoteSession) TransferOwnership(toAdminUserID string) error {
+	if err := rs.checkTransition(OperationTransfer, StatusActive); err != nil {
+		return err
+	}
+	if toAdminUserID == "" {
+		return errors.New("target admin user ID cannot be empty")
+	}
+	if toAdminUserID == rs.adminUserID {
+		return errors.New("session already belongs to target admin")
+	}
+
+	rs.adminUserID = toAdminUserID
+	rs.updatedAt = time.Now().UTC()
+
+	return nil
+}
+
+// End finaliza una sesión activa con el estado especificado. Desde cualquier otro estado (incluido
+// PENDING_APPROVAL, que se cierra con Reject) retorna ErrInvalidStatusTransition sin modificar la sesión.
+func (rs *RemoteSession) End(endStatus SessionStatus) error {
 	if !isValidEndStatus(endStatus) {
-		return errors.New("invalid end status")
+		return fmt.Errorf("%w: %s", ErrInvalidEndStatus, endStatus)
+	}
+
+	if err := rs.checkTransition(OperationEnd, endStatus); err != nil {
+		return err
 	}
 
 	now := time.Now().UTC()
@@ -172,17 +231,18 @@ func (rs *RemoteSession) SetSessionVideoID(videoID string) error {
 	return nil
 }
 
-// Métodos de validación de estado
+// Métodos de validación de estado (según operationTransitions)
 func (rs *RemoteSession) CanAccept() bool {
-	return rs.status == StatusPendingApproval
+	return CanApply(OperationAccept, rs.status, StatusActive)
 }
 
 func (rs *RemoteSession) CanReject() bool {
-	return rs.status == StatusPendingApproval
+	return CanApply(OperationReject, rs.status, StatusRejected)
 }
 
+// CanEnd indica si End se puede aplicar con algún estado de finalización (solo sesiones activas)
 func (rs *RemoteSession) CanEnd() bool {
-	return rs.status == StatusActive
+	return len(operationTransitions[OperationEnd][rs.status]) > 0
 }
 
 func (rs *RemoteSession) IsActive() bool {
@@ -193,6 +253,10 @@ func (rs *RemoteSession) IsPending() bool {
 	return rs.status == StatusPendingApproval
 }
 
+func (rs *RemoteSession) IsQueued() bool {
+	return rs.status == StatusQueued
+}
+
 func (rs *RemoteSession) IsCompleted() bool {
 	return rs.status == StatusEnded || 
 		   rs.status == StatusEndedByAdmin || 
@@ -201,12 +265,29 @@ func (rs *RemoteSession) IsCompleted() bool {
 		   rs.status == StatusFailed
 }
 
-// GetDuration retorna la duración de la sesión si está disponible
+// GetDuration retorna la duración de la sesión: hasta su fin si terminó o hasta ahora si sigue activa
 func (rs *RemoteSession) GetDuration() time.Duration {
-	if rs.startTime == nil || rs.endTime == nil {
+	return rs.DurationAt(time.Now().UTC())
+}
+
+// DurationAt calcula la duración respecto a now. Una sesión sin inicio registrado (en cola, pendiente o
+// activada sin startTime) y una terminada sin endTime retornan 0; un reloj desfasado nunca da una duración negativa.
+func (rs *RemoteSession) DurationAt(now time.Time) time.Duration {
+	if rs.startTime == nil {
 		return 0
 	}
-	return rs.endTime.Sub(*rs.startTime)
+
+	var duration time.Duration
+	switch {
+	case rs.endTime != nil:
+		duration = rs.endTime.Sub(*rs.startTime)
+	case rs.IsActive():
+		duration = now.Sub(*rs.startTime)
+	}
+	if duration < 0 {
+		return 0
+	}
+	return duration
 }
 
 // UpdateStatus actualiza solo el estado (para casos especiales)
@@ -215,8 +296,8 @@ func (rs *RemoteSession) UpdateStatus(newStatus SessionStatus) error {
 		return errors.New("invalid session status")
 	}
 
-	if !rs.canTransitionTo(newStatus) {
-		return errors.New("invalid status transition")
+	if !CanTransition(rs.status, newStatus) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, rs.status, newStatus)
 	}
 
 	rs.status = newStatus
@@ -229,7 +310,7 @@ func (rs *RemoteSession) UpdateStatus(newStatus SessionStatus) error {
 func isValidStatus(status SessionStatus) bool {
 	switch status {
 	case StatusPendingApproval, StatusActive, StatusEnded, 
-		 StatusEndedByAdmin, StatusEndedByClient, StatusRejected, StatusFailed:
+		 StatusEndedByAdmin, StatusEndedByClient, StatusRejected, StatusFailed, StatusQueued:
 		return true
 	default:
 		return false
@@ -244,29 +325,3 @@ func isValidEndStatus(status SessionStatus) bool {
 		return false
 	}
 }
-
-func (rs *RemoteSession) canTransitionTo(newStatus SessionStatus) bool {
-	// Definir transiciones válidas según reglas de negocio
-	validTransitions := map[SessionStatus][]SessionStatus{
-		StatusPendingApproval: {StatusActive, StatusRejected, StatusFailed},
-		StatusActive:          {StatusEnded, StatusEndedByAdmin, StatusEndedByClient, StatusFailed},
-		StatusEnded:           {}, // No puede cambiar
-		StatusEndedByAdmin:    {}, // No puede cambiar
-		StatusEndedByClient:   {}, // No puede cambiar
-		StatusRejected:        {}, // No puede cambiar
-		StatusFailed:          {}, // No puede cambiar
-	}
-
-	allowedStates, exists := validTransitions[rs.status]
-	if !exists {
-		return false
-	}
-
-	for _, allowed := range allowedStates {
-		if newStatus == allowed {
-			return true
-		}
-	}
-
-	return false
-} 
\ No newline at end of file
@@ -0,0 +1,63 @@
+package remotesession
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDurationAt(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	startTime := now.Add(-90 * time.Minute)
+	endTime := now.Add(-30 * time.Minute)
+	afterNow := now.Add(time.Minute)
+
+	cases := map[string]struct {
+		status    SessionStatus
+		startTime *time.Time
+		endTime   *time.Time
+		expected  time.Duration
+	}{
+		"pending without start":    {StatusPendingApproval, nil, nil, 0},
+		"queued without start":     {StatusQueued, nil, nil, 0},
+		"active without start":     {StatusActive, nil, nil, 0},
+		"active until now":         {StatusActive, &startTime, nil, 90 * time.Minute},
+		"active started in future": {StatusActive, &afterNow, nil, 0},
+		"ended until end time":     {StatusEndedByAdmin, &startTime, &endTime, time.Hour},
+		"ended without start":      {StatusEnded, nil, &endTime, 0},
+		"ended without end time":   {StatusFailed, &startTime, nil, 0},
+		"ended before it started":  {StatusEndedByClient, &endTime, &startTime, 0},
+		"rejected without start":   {StatusRejected, nil, &endTime, 0},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			session := NewRemoteSessionFromDB("session-id", "admin-id", "pc-id", tc.startTime, tc.endTime, tc.status, nil, startTime, startTime)
+
+			// Act
+			duration := session.DurationAt(now)
+
+			// Assert
+			assert.Equal(t, tc.expected, duration)
+		})
+	}
+}
+
+func TestGetDuration_ActiveSessionGrowsUntilEnded(t *testing.T) {
+	// Arrange
+	startTime := time.Now().UTC().Add(-time.Minute)
+	session := NewRemoteSessionFromDB("session-id", "admin-id", "pc-id", &startTime, nil, StatusActive, nil, startTime, startTime)
+
+	// Act
+	active := session.GetDuration()
+	require.NoError(t, session.End(StatusEndedByAdmin))
+	ended := session.GetDuration()
+
+	// Assert
+	assert.GreaterOrEqual(t, active, time.Minute)
+	assert.Equal(t, session.EndTime().Sub(startTime), ended)
+	assert.Equal(t, ended, session.GetDuration())
+}
@@ -0,0 +1,54 @@
+package remotesession
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Longitudes máximas del motivo y del ticket, iguales a las columnas de remote_sessions
+const (
+	MaxJustificationReasonLength   = 500
+	MaxJustificationTicketIDLength = 100
+)
+
+// ErrJustificationTooLong el motivo o el ticket superan su longitud máxima
+var ErrJustificationTooLong = errors.New("session justification too long")
+
+// Justification motivo por el que el administrador se conecta al PC (texto libre y/o número de ticket),
+// registrado al iniciar la sesión para la rendición de cuentas
+type Justification struct {
+	Reason   string
+	TicketID string
+}
+
+// NewJustification crea una justificación sin espacios sobrantes; ambos campos son opcionales
+func NewJustification(reason, ticketID string) (Justification, error) {
+	justification := Justification{
+		Reason:   strings.TrimSpace(reason),
+		TicketID: strings.TrimSpace(ticketID),
+	}
+	if utf8.RuneCountInString(justification.Reason) > MaxJustificationReasonLength {
+		return Justification{}, fmt.Errorf("%w: reason exceeds %d characters", ErrJustificationTooLong, MaxJustificationReasonLength)
+	}
+	if utf8.RuneCountInString(justification.TicketID) > MaxJustificationTicketIDLength {
+		return Justification{}, fmt.Errorf("%w: ticket_id exceeds %d characters", ErrJustificationTooLong, MaxJustificationTicketIDLength)
+	}
+	return justification, nil
+}
+
+// IsEmpty indica que no se informó ni motivo ni ticket
+func (j Justification) IsEmpty() bool {
+	return j.Reason == "" && j.TicketID == ""
+}
+
+// Justification retorna el motivo registrado al iniciar la sesión
+func (rs *RemoteSession) Justification() Justification {
+	return rs.justification
+}
+
+// SetJustification asigna el motivo de la sesión; se usa al crearla y al reconstruirla desde la base de datos
+func (rs *RemoteSession) SetJustification(justification Justification) {
+	rs.justification = justification
+}
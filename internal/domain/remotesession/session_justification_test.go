@@ -0,0 +1,41 @@
+package remotesession
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJustification_TrimsAndValidatesLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		reason   string
+		ticketID string
+		expected Justification
+		wantErr  bool
+	}{
+		{name: "empty", expected: Justification{}},
+		{name: "trimmed", reason: "  VPN broken \n", ticketID: " HD-1 ", expected: Justification{Reason: "VPN broken", TicketID: "HD-1"}},
+		{name: "reason at limit", reason: strings.Repeat("ñ", MaxJustificationReasonLength), expected: Justification{Reason: strings.Repeat("ñ", MaxJustificationReasonLength)}},
+		{name: "reason too long", reason: strings.Repeat("a", MaxJustificationReasonLength+1), wantErr: true},
+		{name: "ticket too long", ticketID: strings.Repeat("1", MaxJustificationTicketIDLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			justification, err := NewJustification(tt.reason, tt.ticketID)
+
+			// Assert
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrJustificationTooLong)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, justification)
+			assert.Equal(t, tt.expected == Justification{}, justification.IsEmpty())
+		})
+	}
+}
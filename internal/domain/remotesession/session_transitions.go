@@ -0,0 +1,82 @@
+package remotesession
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidStatusTransition la operación no está permitida desde el estado actual de la sesión; la entidad no cambia
+	ErrInvalidStatusTransition = errors.New("invalid remote session status transition")
+	// ErrInvalidEndStatus el estado indicado a End no es un estado de finalización
+	ErrInvalidEndStatus = errors.New("invalid end status")
+	// ErrSessionInProgress la sesión sigue en curso (ACTIVE, PENDING_APPROVAL o QUEUED) y no se puede borrar
+	ErrSessionInProgress = errors.New("remote session is still in progress")
+)
+
+// SessionOperation operación del dominio que cambia el estado de una sesión
+type SessionOperation string
+
+const (
+	OperationAccept      SessionOperation = "accept"
+	OperationReject      SessionOperation = "reject"
+	OperationDequeue     SessionOperation = "dequeue"
+	OperationExpireQueue SessionOperation = "expire_queue"
+	OperationEnd         SessionOperation = "end"
+	OperationTransfer    SessionOperation = "transfer"
+)
+
+// operationTransitions estados de origen y destino permitidos para cada operación. Es la única fuente de las
+// transiciones de una sesión: las operaciones, los Can* y UpdateStatus la consultan.
+//
+// End solo se aplica a sesiones ACTIVE: una sesión PENDING_APPROVAL nunca empezó y se cierra con Reject, y una
+// QUEUED con ExpireQueue. REJECTED, FAILED y ENDED_* son finales.
+var operationTransitions = map[SessionOperation]map[SessionStatus][]SessionStatus{
+	OperationAccept:      {StatusPendingApproval: {StatusActive}},
+	OperationReject:      {StatusPendingApproval: {StatusRejected}},
+	OperationDequeue:     {StatusQueued: {StatusPendingApproval}},
+	OperationExpireQueue: {StatusQueued: {StatusFailed}},
+	OperationEnd:         {StatusActive: {StatusEnded, StatusEndedByAdmin, StatusEndedByClient, StatusFailed}},
+	OperationTransfer:    {StatusActive: {StatusActive}},
+}
+
+// CanApply indica si la operación puede llevar una sesión del estado from al estado to
+func CanApply(operation SessionOperation, from, to SessionStatus) bool {
+	for _, allowed := range operationTransitions[operation][from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransition indica si alguna operación lleva una sesión del estado from a otro estado to
+func CanTransition(from, to SessionStatus) bool {
+	if from == to {
+		return false
+	}
+	for operation := range operationTransitions {
+		if CanApply(operation, from, to) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal indica si el estado es final (ninguna operación lo cambia)
+func (s SessionStatus) IsFinal() bool {
+	for _, transitions := range operationTransitions {
+		if len(transitions[s]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// checkTransition valida la operación desde el estado actual de la sesión
+func (rs *RemoteSession) checkTransition(operation SessionOperation, to SessionStatus) error {
+	if !CanApply(operation, rs.status, to) {
+		return fmt.Errorf("%w: cannot %s session in status %s (-> %s)", ErrInvalidStatusTransition, operation, rs.status, to)
+	}
+	return nil
+}
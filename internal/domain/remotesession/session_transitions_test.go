@@ -0,0 +1,160 @@
+package remotesession
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var allStatuses = []SessionStatus{
+	StatusQueued, StatusPendingApproval, StatusActive, StatusEnded,
+	StatusEndedByAdmin, StatusEndedByClient, StatusRejected, StatusFailed,
+}
+
+// sessionInStatus sesión persistida en el estado indicado, con updatedAt en el pasado para detectar cambios
+func sessionInStatus(status SessionStatus) *RemoteSession {
+	createdAt := time.Now().UTC().Add(-time.Hour)
+	var startTime *time.Time
+	if status != StatusQueued && status != StatusPendingApproval && status != StatusRejected {
+		startTime = &createdAt
+	}
+	return NewRemoteSessionFromDB("session-id", "admin-id", "pc-id", startTime, nil, status, nil, createdAt, createdAt)
+}
+
+var sessionOperations = map[string]func(*RemoteSession) error{
+	"accept":            (*RemoteSession).Accept,
+	"reject":            (*RemoteSession).Reject,
+	"dequeue":           (*RemoteSession).Dequeue,
+	"expire_queue":      (*RemoteSession).ExpireQueue,
+	"end_successfully":  func(rs *RemoteSession) error { return rs.End(StatusEnded) },
+	"end_by_admin":      func(rs *RemoteSession) error { return rs.End(StatusEndedByAdmin) },
+	"end_by_client":     func(rs *RemoteSession) error { return rs.End(StatusEndedByClient) },
+	"end_failed":        func(rs *RemoteSession) error { return rs.End(StatusFailed) },
+	"transfer_to_other": func(rs *RemoteSession) error { return rs.TransferOwnership("other-admin-id") },
+}
+
+// allowedOperations estado resultante de cada operación permitida; las que no aparecen deben fallar
+var allowedOperations = map[SessionStatus]map[string]SessionStatus{
+	StatusQueued: {
+		"dequeue":      StatusPendingApproval,
+		"expire_queue": StatusFailed,
+	},
+	StatusPendingApproval: {
+		"accept": StatusActive,
+		"reject": StatusRejected,
+	},
+	StatusActive: {
+		"end_successfully":  StatusEnded,
+		"end_by_admin":      StatusEndedByAdmin,
+		"end_by_client":     StatusEndedByClient,
+		"end_failed":        StatusFailed,
+		"transfer_to_other": StatusActive,
+	},
+}
+
+func TestRemoteSession_EveryOperationFromEveryStatus(t *testing.T) {
+	for _, from := range allStatuses {
+		for name, operation := range sessionOperations {
+			t.Run(string(from)+"/"+name, func(t *testing.T) {
+				// Arrange
+				session := sessionInStatus(from)
+				before := *session
+				expected, allowed := allowedOperations[from][name]
+
+				// Act
+				err := operation(session)
+
+				// Assert
+				if !allowed {
+					assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+					assert.Equal(t, before, *session, "an illegal transition must not modify the session")
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, expected, session.Status())
+				assert.True(t, session.UpdatedAt().After(before.UpdatedAt()))
+			})
+		}
+	}
+}
+
+func TestRemoteSession_CanGuardsMatchOperations(t *testing.T) {
+	for _, from := range allStatuses {
+		t.Run(string(from), func(t *testing.T) {
+			// Arrange
+			session := sessionInStatus(from)
+			_, canEnd := allowedOperations[from]["end_failed"]
+			_, canAccept := allowedOperations[from]["accept"]
+			_, canReject := allowedOperations[from]["reject"]
+
+			// Act & Assert
+			assert.Equal(t, canAccept, session.CanAccept())
+			assert.Equal(t, canReject, session.CanReject())
+			assert.Equal(t, canEnd, session.CanEnd())
+			assert.Equal(t, len(allowedOperations[from]) == 0, from.IsFinal())
+		})
+	}
+}
+
+func TestRemoteSession_EndFromPendingApprovalLeavesSessionPending(t *testing.T) {
+	// Arrange - la solicitud nunca se aceptó, así que no hay nada que finalizar
+	session, err := NewRemoteSession("admin-id", "pc-id")
+	require.NoError(t, err)
+
+	// Act
+	err = session.End(StatusFailed)
+
+	// Assert - error tipado, la sesión sigue pendiente y se puede rechazar
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+	assert.Equal(t, StatusPendingApproval, session.Status())
+	assert.Nil(t, session.EndTime())
+	require.NoError(t, session.Reject())
+	assert.Equal(t, StatusRejected, session.Status())
+}
+
+func TestRemoteSession_EndWithNonEndStatusIsRejected(t *testing.T) {
+	for _, endStatus := range []SessionStatus{StatusActive, StatusRejected, StatusPendingApproval, StatusQueued, "UNKNOWN"} {
+		t.Run(string(endStatus), func(t *testing.T) {
+			// Arrange
+			session := sessionInStatus(StatusActive)
+
+			// Act
+			err := session.End(endStatus)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidEndStatus)
+			assert.Equal(t, StatusActive, session.Status())
+		})
+	}
+}
+
+func TestRemoteSession_UpdateStatusFollowsOperationTable(t *testing.T) {
+	for _, from := range allStatuses {
+		for _, to := range allStatuses {
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				// Arrange
+				session := sessionInStatus(from)
+				allowed := false
+				for _, result := range allowedOperations[from] {
+					if result == to && to != from {
+						allowed = true
+					}
+				}
+
+				// Act
+				err := session.UpdateStatus(to)
+
+				// Assert
+				if allowed {
+					require.NoError(t, err)
+					assert.Equal(t, to, session.Status())
+					return
+				}
+				assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+				assert.Equal(t, from, session.Status())
+			})
+		}
+	}
+}
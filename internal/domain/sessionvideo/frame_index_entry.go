@@ -0,0 +1,8 @@
+package sessionvideo
+
+// FrameIndexEntry posición de un frame dentro del contenedor de una grabación empaquetada
+type FrameIndexEntry struct {
+	FrameIndex int
+	Offset     int64
+	Length     int64
+}
@@ -9,11 +9,63 @@ import (
 
 type Role string
 
+// Roles del panel de administración, de más a menos privilegios, y el rol de los usuarios cliente.
+// ADMINISTRATOR es el super-administrador: conserva el acceso completo de las cuentas existentes.
 const (
 	RoleAdministrator Role = "ADMINISTRATOR"
+	RoleOperator      Role = "OPERATOR" // controla sesiones, transfiere archivos y graba macros
+	RoleViewer        Role = "VIEWER"   // solo consulta: PCs, sesiones, grabaciones y transferencias
 	RoleClientUser    Role = "CLIENT_USER"
 )
 
+// ErrInvalidRole el rol almacenado o solicitado no es ninguno de los conocidos
+var ErrInvalidRole = errors.New("invalid user role")
+
+// ParseRole convierte el rol almacenado en base de datos
+func ParseRole(value string) (Role, error) {
+	switch role := Role(value); role {
+	case RoleAdministrator, RoleOperator, RoleViewer, RoleClientUser:
+		return role, nil
+	default:
+		return "", ErrInvalidRole
+	}
+}
+
+// AdminRoles roles que pueden iniciar sesión en el panel de administración
+func AdminRoles() []Role {
+	return []Role{RoleAdministrator, RoleOperator, RoleViewer}
+}
+
+// SessionControlRoles roles que pueden iniciar, controlar y recibir sesiones remotas
+func SessionControlRoles() []Role {
+	return []Role{RoleAdministrator, RoleOperator}
+}
+
+// SuperAdminRoles roles que pueden borrar datos y cambiar la configuración del servidor
+func SuperAdminRoles() []Role {
+	return []Role{RoleAdministrator}
+}
+
+// IsAnyOf indica si el rol está en la lista
+func (r Role) IsAnyOf(roles ...Role) bool {
+	for _, role := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin indica si el rol es de administración (cualquier nivel)
+func (r Role) IsAdmin() bool {
+	return r.IsAnyOf(AdminRoles()...)
+}
+
+// CanControlSessions indica si el rol puede tomar el control de un PC
+func (r Role) CanControlSessions() bool {
+	return r.IsAnyOf(SessionControlRoles()...)
+}
+
 type User struct {
 	userID         string
 	username       string
@@ -21,6 +73,7 @@ type User struct {
 	hashedPassword string
 	role           Role
 	isActive       bool
+	tokenVersion   int
 	createdAt      time.Time
 	updatedAt      time.Time
 }
@@ -63,8 +116,19 @@ func (u *User) IsActive() bool {
 	return u.isActive
 }
 
+// TokenVersion versión vigente de los tokens del usuario; los emitidos con una versión menor están revocados
+func (u *User) TokenVersion() int {
+	return u.tokenVersion
+}
+
+// RestoreTokenVersion reconstruye la versión de los tokens guardada en base de datos
+func (u *User) RestoreTokenVersion(version int) {
+	u.tokenVersion = version
+}
+
+// IsAdministrator indica si el usuario tiene cualquier rol del panel de administración
 func (u *User) IsAdministrator() bool {
-	return u.role == RoleAdministrator
+	return u.role.IsAdmin()
 }
 
 func (u *User) IsClientUser() bool {
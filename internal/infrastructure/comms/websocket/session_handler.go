@@ -46,7 +46,7 @@ func (sh *SessionHandler) handleSessionAccepted(clientID string, data []byte) er
 	}
 
 	// Aceptar la sesión usando el servicio
-	err := sh.sessionService.AcceptSession(message.SessionID)
+	err := sh.sessionService.AcceptSession(context.Background(), message.SessionID)
 	if err != nil {
 		log.Printf("Error accepting session %s: %v", message.SessionID, err)
 
@@ -57,7 +57,7 @@ func (sh *SessionHandler) handleSessionAccepted(clientID string, data []byte) er
 	}
 
 	// Obtener la sesión actualizada
-	session, err := sh.sessionService.GetSessionById(message.SessionID)
+	session, err := sh.sessionService.GetSessionById(context.Background(), message.SessionID)
 	if err != nil {
 		log.Printf("Error getting session %s: %v", message.SessionID, err)
 		return err
@@ -88,14 +88,14 @@ func (sh *SessionHandler) handleSessionRejected(clientID string, data []byte) er
 	}
 
 	// Rechazar la sesión usando el servicio
-	err := sh.sessionService.RejectSession(message.SessionID, message.Reason)
+	err := sh.sessionService.RejectSession(context.Background(), message.SessionID, message.Reason)
 	if err != nil {
 		log.Printf("Error rejecting session %s: %v", message.SessionID, err)
 		return err
 	}
 
 	// Obtener la sesión actualizada
-	session, err := sh.sessionService.GetSessionById(message.SessionID)
+	session, err := sh.sessionService.GetSessionById(context.Background(), message.SessionID)
 	if err != nil {
 		log.Printf("Error getting session %s: %v", message.SessionID, err)
 		return err
@@ -29,6 +29,20 @@ func NewConnection(config Config) (*sql.DB, error) {
 		config.Database,
 	)
 
+	return openPool(dsn, config)
+}
+
+// NewReplicaConnection abre la réplica de lectura indicada por dsn con el mismo tamaño de pool que el primario
+func NewReplicaConnection(dsn string, config Config) (*sql.DB, error) {
+	db, err := openPool(dsn, config)
+	if err != nil {
+		return nil, fmt.Errorf("read replica: %w", err)
+	}
+	return db, nil
+}
+
+// openPool abre el pool de conexiones y verifica que la base de datos responde
+func openPool(dsn string, config Config) (*sql.DB, error) {
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
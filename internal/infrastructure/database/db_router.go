@@ -0,0 +1,34 @@
+package database
+
+import "database/sql"
+
+// DBRouter reparte las sentencias entre el primario y una réplica de lectura opcional. Las escrituras
+// van siempre al primario; las consultas de solo lectura que toleran el retraso de replicación
+// (listados, informes, auditoría) usan Reader. Sin réplica, Reader devuelve el primario.
+type DBRouter struct {
+	primary *sql.DB
+	replica *sql.DB
+}
+
+// NewDBRouter crea el router; replica puede ser nil
+func NewDBRouter(primary, replica *sql.DB) *DBRouter {
+	return &DBRouter{primary: primary, replica: replica}
+}
+
+// Writer conexión para escrituras y lecturas que deben ver el último estado
+func (r *DBRouter) Writer() *sql.DB {
+	return r.primary
+}
+
+// Reader conexión para consultas de solo lectura: la réplica si está configurada, si no el primario
+func (r *DBRouter) Reader() *sql.DB {
+	if r.replica != nil {
+		return r.replica
+	}
+	return r.primary
+}
+
+// HasReplica indica si hay una réplica de lectura configurada
+func (r *DBRouter) HasReplica() bool {
+	return r.replica != nil
+}
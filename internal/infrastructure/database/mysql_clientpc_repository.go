@@ -28,13 +28,16 @@ func (r *MySQLClientPCRepository) Save(ctx context.Context, pc *clientpc.ClientP
 	// First, try to update existing record
 	updateQuery := `
 		UPDATE client_pcs 
-		SET ip = ?, connection_status = ?, last_seen_at = ?, updated_at = ?
+		SET ip = ?, connection_status = ?, last_seen_at = ?, os = ?, hostname = ?, agent_version = ?, updated_at = ?
 		WHERE pc_id = ?`
 
 	result, err := r.db.ExecContext(ctx, updateQuery,
 		pc.IP,
 		pc.ConnectionStatus.String(),
 		pc.LastSeenAt,
+		nullString(pc.OS),
+		nullString(pc.Hostname),
+		nullString(pc.AgentVersion),
 		pc.UpdatedAt,
 		pc.PCID,
 	)
@@ -51,8 +54,8 @@ func (r *MySQLClientPCRepository) Save(ctx context.Context, pc *clientpc.ClientP
 	// If no rows were updated, insert new record
 	if rowsAffected == 0 {
 		insertQuery := `
-			INSERT INTO client_pcs (pc_id, identifier, ip, connection_status, registered_at, owner_user_id, last_seen_at, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			INSERT INTO client_pcs (pc_id, identifier, ip, connection_status, registered_at, owner_user_id, last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 		_, err = r.db.ExecContext(ctx, insertQuery,
 			pc.PCID,
@@ -62,6 +65,10 @@ func (r *MySQLClientPCRepository) Save(ctx context.Context, pc *clientpc.ClientP
 			pc.RegisteredAt,
 			pc.OwnerUserID,
 			pc.LastSeenAt,
+			pc.AutoAcceptControl,
+			nullString(pc.OS),
+			nullString(pc.Hostname),
+			nullString(pc.AgentVersion),
 			pc.CreatedAt,
 			pc.UpdatedAt,
 		)
@@ -77,7 +84,7 @@ func (r *MySQLClientPCRepository) Save(ctx context.Context, pc *clientpc.ClientP
 // FindByID retrieves a ClientPC by its ID
 func (r *MySQLClientPCRepository) FindByID(ctx context.Context, pcID string) (*clientpc.ClientPC, error) {
 	query := `
-		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id, last_seen_at, created_at, updated_at
+		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id, last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at
 		FROM client_pcs 
 		WHERE pc_id = ?`
 
@@ -94,10 +101,45 @@ func (r *MySQLClientPCRepository) FindByID(ctx context.Context, pcID string) (*c
 	return pc, nil
 }
 
+// FindByIDs retrieves several ClientPCs in a single query, keyed by PC ID
+func (r *MySQLClientPCRepository) FindByIDs(ctx context.Context, pcIDs []string) (map[string]*clientpc.ClientPC, error) {
+	pcs := make(map[string]*clientpc.ClientPC, len(pcIDs))
+	if len(pcIDs) == 0 {
+		return pcs, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id, last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at
+		FROM client_pcs 
+		WHERE pc_id IN (%s)`, inPlaceholders(len(pcIDs)))
+
+	args := make([]interface{}, len(pcIDs))
+	for i, id := range pcIDs {
+		args[i] = id
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error finding ClientPCs by IDs: %w", err)
+	}
+	defer rows.Close()
+
+	found, err := r.scanClientPCs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pc := range found {
+		pcs[pc.PCID] = pc
+	}
+
+	return pcs, nil
+}
+
 // FindByIdentifierAndOwner retrieves a ClientPC by identifier and owner user ID
 func (r *MySQLClientPCRepository) FindByIdentifierAndOwner(ctx context.Context, identifier string, ownerID string) (*clientpc.ClientPC, error) {
 	query := `
-		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id, last_seen_at, created_at, updated_at
+		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id, last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at
 		FROM client_pcs 
 		WHERE identifier = ? AND owner_user_id = ?`
 
@@ -117,7 +159,7 @@ func (r *MySQLClientPCRepository) FindByIdentifierAndOwner(ctx context.Context,
 // FindByOwner retrieves all ClientPCs belonging to a specific owner
 func (r *MySQLClientPCRepository) FindByOwner(ctx context.Context, ownerID string) ([]*clientpc.ClientPC, error) {
 	query := `
-		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id, last_seen_at, created_at, updated_at
+		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id, last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at
 		FROM client_pcs 
 		WHERE owner_user_id = ?
 		ORDER BY created_at DESC`
@@ -134,7 +176,7 @@ func (r *MySQLClientPCRepository) FindByOwner(ctx context.Context, ownerID strin
 // FindOnlineByOwner retrieves all online ClientPCs belonging to a specific owner
 func (r *MySQLClientPCRepository) FindOnlineByOwner(ctx context.Context, ownerID string) ([]*clientpc.ClientPC, error) {
 	query := `
-		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id, last_seen_at, created_at, updated_at
+		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id, last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at
 		FROM client_pcs 
 		WHERE owner_user_id = ? AND connection_status = 'ONLINE'
 		ORDER BY last_seen_at DESC`
@@ -197,6 +239,30 @@ func (r *MySQLClientPCRepository) UpdateLastSeen(ctx context.Context, pcID strin
 	return nil
 }
 
+// UpdateAutoAcceptControl updates the auto-accept control policy of a ClientPC
+func (r *MySQLClientPCRepository) UpdateAutoAcceptControl(ctx context.Context, pcID string, enabled bool) error {
+	query := `
+		UPDATE client_pcs 
+		SET auto_accept_control = ?, updated_at = ?
+		WHERE pc_id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, enabled, time.Now(), pcID)
+	if err != nil {
+		return fmt.Errorf("error updating auto-accept control: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("no ClientPC found with ID: %s", pcID)
+	}
+
+	return nil
+}
+
 // Delete removes a ClientPC from the repository
 func (r *MySQLClientPCRepository) Delete(ctx context.Context, pcID string) error {
 	query := `DELETE FROM client_pcs WHERE pc_id = ?`
@@ -223,7 +289,7 @@ func (r *MySQLClientPCRepository) FindAll(ctx context.Context, limit, offset int
 	log.Printf("DEBUG FindAll: Starting query with limit=%d, offset=%d", limit, offset)
 
 	query := `
-		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id, last_seen_at, created_at, updated_at
+		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id, last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at
 		FROM client_pcs 
 		ORDER BY created_at DESC`
 
@@ -278,6 +344,19 @@ func (r *MySQLClientPCRepository) CountByOwner(ctx context.Context, ownerID stri
 	return count, nil
 }
 
+// Count returns the total number of ClientPCs
+func (r *MySQLClientPCRepository) Count(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM client_pcs`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting ClientPCs: %w", err)
+	}
+
+	return count, nil
+}
+
 // Helper methods for scanning results
 
 // scanClientPC scans a single row into a ClientPC struct
@@ -285,6 +364,7 @@ func (r *MySQLClientPCRepository) scanClientPC(row *sql.Row) (*clientpc.ClientPC
 	var pc clientpc.ClientPC
 	var connectionStatusStr string
 	var lastSeenAt sql.NullTime
+	var osName, hostname, agentVersion sql.NullString
 
 	err := row.Scan(
 		&pc.PCID,
@@ -294,6 +374,10 @@ func (r *MySQLClientPCRepository) scanClientPC(row *sql.Row) (*clientpc.ClientPC
 		&pc.RegisteredAt,
 		&pc.OwnerUserID,
 		&lastSeenAt,
+		&pc.AutoAcceptControl,
+		&osName,
+		&hostname,
+		&agentVersion,
 		&pc.CreatedAt,
 		&pc.UpdatedAt,
 	)
@@ -312,6 +396,9 @@ func (r *MySQLClientPCRepository) scanClientPC(row *sql.Row) (*clientpc.ClientPC
 		pc.LastSeenAt = nil
 	}
 
+	// Agent metadata is NULL for PCs registered by older clients
+	pc.OS, pc.Hostname, pc.AgentVersion = osName.String, hostname.String, agentVersion.String
+
 	return &pc, nil
 }
 
@@ -323,6 +410,7 @@ func (r *MySQLClientPCRepository) scanClientPCs(rows *sql.Rows) ([]*clientpc.Cli
 		var pc clientpc.ClientPC
 		var connectionStatusStr string
 		var lastSeenAt sql.NullTime
+		var osName, hostname, agentVersion sql.NullString
 
 		err := rows.Scan(
 			&pc.PCID,
@@ -332,6 +420,10 @@ func (r *MySQLClientPCRepository) scanClientPCs(rows *sql.Rows) ([]*clientpc.Cli
 			&pc.RegisteredAt,
 			&pc.OwnerUserID,
 			&lastSeenAt,
+			&pc.AutoAcceptControl,
+			&osName,
+			&hostname,
+			&agentVersion,
 			&pc.CreatedAt,
 			&pc.UpdatedAt,
 		)
@@ -350,6 +442,9 @@ func (r *MySQLClientPCRepository) scanClientPCs(rows *sql.Rows) ([]*clientpc.Cli
 			pc.LastSeenAt = nil
 		}
 
+		// Agent metadata is NULL for PCs registered by older clients
+		pc.OS, pc.Hostname, pc.AgentVersion = osName.String, hostname.String, agentVersion.String
+
 		pcs = append(pcs, &pc)
 	}
 
@@ -359,3 +454,8 @@ func (r *MySQLClientPCRepository) scanClientPCs(rows *sql.Rows) ([]*clientpc.Cli
 
 	return pcs, nil
 }
+
+// nullString stores empty optional values as NULL
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
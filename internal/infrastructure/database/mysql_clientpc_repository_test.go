@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+)
+
+// newTestClientPC construye un PC de prueba perteneciente al usuario admin sembrado en la BD
+func newTestClientPC(pcID, identifier string) *clientpc.ClientPC {
+	now := time.Now()
+	return &clientpc.ClientPC{
+		PCID:             pcID,
+		Identifier:       identifier,
+		IP:               "192.168.1.100",
+		ConnectionStatus: clientpc.PCConnectionStatusOffline,
+		RegisteredAt:     now,
+		OwnerUserID:      "admin-000-000-000-000000000001",
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+func TestMySQLClientPCRepository_FindByIDs_Success(t *testing.T) {
+	// Arrange
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMySQLClientPCRepository(db)
+	ctx := context.Background()
+
+	// Crear dos PCs de prueba del usuario admin existente
+	firstPC := newTestClientPC("11111111-1111-4111-8111-111111111111", "bulk-pc-1")
+	secondPC := newTestClientPC("22222222-2222-4222-8222-222222222222", "bulk-pc-2")
+
+	require.NoError(t, repo.Save(ctx, firstPC))
+	require.NoError(t, repo.Save(ctx, secondPC))
+
+	// Act - incluir un ID inexistente
+	pcs, err := repo.FindByIDs(ctx, []string{firstPC.PCID, secondPC.PCID, "nonexistent-pc-id"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, pcs, 2)
+	assert.Equal(t, "bulk-pc-1", pcs[firstPC.PCID].Identifier)
+	assert.Equal(t, "bulk-pc-2", pcs[secondPC.PCID].Identifier)
+	assert.NotContains(t, pcs, "nonexistent-pc-id")
+
+	// Cleanup
+	_, err = db.Exec("DELETE FROM client_pcs WHERE pc_id IN (?, ?)", firstPC.PCID, secondPC.PCID)
+	assert.NoError(t, err)
+}
+
+func TestMySQLClientPCRepository_FindByIDs_OnlyMissingIDs(t *testing.T) {
+	// Arrange
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMySQLClientPCRepository(db)
+
+	// Act
+	pcs, err := repo.FindByIDs(context.Background(), []string{"nonexistent-pc-id-1", "nonexistent-pc-id-2"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, pcs)
+	assert.Empty(t, pcs)
+}
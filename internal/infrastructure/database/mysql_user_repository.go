@@ -1,8 +1,10 @@
 package database
 
 import (
+	"context"
 	"database/sql"
-	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
@@ -24,17 +26,18 @@ func NewMySQLUserRepository(db *sql.DB) interfaces.IUserRepository {
 // FindByUsername busca un usuario por su nombre de usuario
 func (r *MySQLUserRepository) FindByUsername(username string) (*user.User, error) {
 	query := `
-		SELECT user_id, username, ip, hashed_password, role, is_active, created_at, updated_at
+		SELECT user_id, username, ip, hashed_password, role, is_active, token_version, created_at, updated_at
 		FROM users 
 		WHERE username = ? AND is_active = TRUE
 	`
 
 	var userID, dbUsername, ip, hashedPassword, roleStr string
 	var isActive bool
+	var tokenVersion int
 	var createdAt, updatedAt time.Time
 
 	err := r.db.QueryRow(query, username).Scan(
-		&userID, &dbUsername, &ip, &hashedPassword, &roleStr, &isActive, &createdAt, &updatedAt,
+		&userID, &dbUsername, &ip, &hashedPassword, &roleStr, &isActive, &tokenVersion, &createdAt, &updatedAt,
 	)
 
 	if err != nil {
@@ -45,14 +48,9 @@ func (r *MySQLUserRepository) FindByUsername(username string) (*user.User, error
 	}
 
 	// Convertir string a Role
-	var role user.Role
-	switch roleStr {
-	case "ADMINISTRATOR":
-		role = user.RoleAdministrator
-	case "CLIENT_USER":
-		role = user.RoleClientUser
-	default:
-		return nil, errors.New("invalid user role")
+	role, err := user.ParseRole(roleStr)
+	if err != nil {
+		return nil, err
 	}
 
 	// Crear usuario desde datos de BD
@@ -62,6 +60,7 @@ func (r *MySQLUserRepository) FindByUsername(username string) (*user.User, error
 	if !isActive {
 		foundUser.Deactivate()
 	}
+	foundUser.RestoreTokenVersion(tokenVersion)
 
 	return foundUser, nil
 }
@@ -69,17 +68,18 @@ func (r *MySQLUserRepository) FindByUsername(username string) (*user.User, error
 // FindByID busca un usuario por su ID
 func (r *MySQLUserRepository) FindByID(userID string) (*user.User, error) {
 	query := `
-		SELECT user_id, username, ip, hashed_password, role, is_active, created_at, updated_at
+		SELECT user_id, username, ip, hashed_password, role, is_active, token_version, created_at, updated_at
 		FROM users 
 		WHERE user_id = ?
 	`
 
 	var dbUserID, username, ip, hashedPassword, roleStr string
 	var isActive bool
+	var tokenVersion int
 	var createdAt, updatedAt time.Time
 
 	err := r.db.QueryRow(query, userID).Scan(
-		&dbUserID, &username, &ip, &hashedPassword, &roleStr, &isActive, &createdAt, &updatedAt,
+		&dbUserID, &username, &ip, &hashedPassword, &roleStr, &isActive, &tokenVersion, &createdAt, &updatedAt,
 	)
 
 	if err != nil {
@@ -90,14 +90,9 @@ func (r *MySQLUserRepository) FindByID(userID string) (*user.User, error) {
 	}
 
 	// Convertir string a Role
-	var role user.Role
-	switch roleStr {
-	case "ADMINISTRATOR":
-		role = user.RoleAdministrator
-	case "CLIENT_USER":
-		role = user.RoleClientUser
-	default:
-		return nil, errors.New("invalid user role")
+	role, err := user.ParseRole(roleStr)
+	if err != nil {
+		return nil, err
 	}
 
 	// Crear usuario desde datos de BD
@@ -107,10 +102,75 @@ func (r *MySQLUserRepository) FindByID(userID string) (*user.User, error) {
 	if !isActive {
 		foundUser.Deactivate()
 	}
+	foundUser.RestoreTokenVersion(tokenVersion)
 
 	return foundUser, nil
 }
 
+// FindByIDs busca varios usuarios por ID en una sola consulta
+func (r *MySQLUserRepository) FindByIDs(ctx context.Context, userIDs []string) (map[string]*user.User, error) {
+	users := make(map[string]*user.User, len(userIDs))
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT user_id, username, ip, hashed_password, role, is_active, token_version, created_at, updated_at
+		FROM users 
+		WHERE user_id IN (%s)
+	`, inPlaceholders(len(userIDs)))
+
+	args := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		args[i] = id
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error finding users by IDs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dbUserID, username, ip, hashedPassword, roleStr string
+		var isActive bool
+		var tokenVersion int
+		var createdAt, updatedAt time.Time
+
+		err := rows.Scan(
+			&dbUserID, &username, &ip, &hashedPassword, &roleStr, &isActive, &tokenVersion, &createdAt, &updatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// Convertir string a Role
+		role, err := user.ParseRole(roleStr)
+		if err != nil {
+			return nil, err
+		}
+
+		foundUser := user.NewUser(dbUserID, username, ip, hashedPassword, role)
+		if !isActive {
+			foundUser.Deactivate()
+		}
+		foundUser.RestoreTokenVersion(tokenVersion)
+
+		users[dbUserID] = foundUser
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// inPlaceholders genera la lista "?, ?, ?" para una cláusula IN con n parámetros
+func inPlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
 // Save guarda o actualiza un usuario existente
 func (r *MySQLUserRepository) Save(u *user.User) error {
 	query := `
@@ -153,3 +213,33 @@ func (r *MySQLUserRepository) Create(u *user.User) error {
 
 	return err
 }
+
+// IncrementTokenVersion incrementa token_version en una sola sentencia; LAST_INSERT_ID(expr) retorna el valor
+// escrito sin una segunda consulta que pudiera leer el de otra revocación concurrente
+func (r *MySQLUserRepository) IncrementTokenVersion(userID string) (int, error) {
+	query := `
+		UPDATE users 
+		SET token_version = LAST_INSERT_ID(token_version + 1), updated_at = ?
+		WHERE user_id = ?
+	`
+
+	result, err := r.db.Exec(query, time.Now(), userID)
+	if err != nil {
+		return 0, fmt.Errorf("error incrementing token version: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error incrementing token version: %w", err)
+	}
+	if affected == 0 {
+		return 0, fmt.Errorf("error incrementing token version: user %s not found", userID)
+	}
+
+	version, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error reading token version: %w", err)
+	}
+
+	return int(version), nil
+}
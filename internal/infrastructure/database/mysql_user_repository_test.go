@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 
@@ -79,6 +80,39 @@ func TestMySQLUserRepository_FindByID_Success(t *testing.T) {
 	assert.True(t, foundUser.IsAdministrator())
 }
 
+func TestMySQLUserRepository_FindByIDs_Success(t *testing.T) {
+	// Arrange
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMySQLUserRepository(db)
+
+	// Act - buscar el admin existente y un ID que no existe
+	users, err := repo.FindByIDs(context.Background(), []string{"admin-000-000-000-000000000001", "nonexistent-user-id"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "admin", users["admin-000-000-000-000000000001"].Username())
+	assert.NotContains(t, users, "nonexistent-user-id")
+}
+
+func TestMySQLUserRepository_FindByIDs_EmptyInput(t *testing.T) {
+	// Arrange
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMySQLUserRepository(db)
+
+	// Act
+	users, err := repo.FindByIDs(context.Background(), []string{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, users)
+	assert.Empty(t, users)
+}
+
 func TestMySQLUserRepository_Create_Success(t *testing.T) {
 	// Arrange
 	db := setupTestDB(t)
@@ -9,17 +9,28 @@ import (
 
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/infrastructure/database"
 )
 
 // ActionLogRepositoryImpl implementa el repositorio de ActionLog usando MySQL
 type ActionLogRepositoryImpl struct {
-	db *sql.DB
+	db     *sql.DB // primario: escrituras y lecturas que deben ver el último estado
+	readDB *sql.DB // réplica de lectura para las consultas de auditoría (el primario si no hay réplica)
 }
 
 // NewActionLogRepository crea una nueva instancia del repositorio
 func NewActionLogRepository(db *sql.DB) interfaces.IActionLogRepository {
 	return &ActionLogRepositoryImpl{
-		db: db,
+		db:     db,
+		readDB: db,
+	}
+}
+
+// NewActionLogRepositoryWithRouter crea el repositorio enviando las consultas de auditoría a la réplica de lectura del router
+func NewActionLogRepositoryWithRouter(router *database.DBRouter) interfaces.IActionLogRepository {
+	return &ActionLogRepositoryImpl{
+		db:     router.Writer(),
+		readDB: router.Reader(),
 	}
 }
 
@@ -77,7 +88,7 @@ func (r *ActionLogRepositoryImpl) FindByID(ctx context.Context, logID int64) (*a
 		WHERE log_id = ?
 	`
 
-	row := r.db.QueryRowContext(ctx, query, logID)
+	row := r.readDB.QueryRowContext(ctx, query, logID)
 	return r.scanActionLog(row)
 }
 
@@ -92,7 +103,7 @@ func (r *ActionLogRepositoryImpl) FindByUserID(ctx context.Context, userID strin
 		LIMIT ? OFFSET ?
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := r.readDB.QueryContext(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("error finding action logs by user ID: %w", err)
 	}
@@ -112,7 +123,7 @@ func (r *ActionLogRepositoryImpl) FindByActionType(ctx context.Context, actionTy
 		LIMIT ? OFFSET ?
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, string(actionType), limit, offset)
+	rows, err := r.readDB.QueryContext(ctx, query, string(actionType), limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("error finding action logs by type: %w", err)
 	}
@@ -132,7 +143,7 @@ func (r *ActionLogRepositoryImpl) FindBySubjectEntity(ctx context.Context, entit
 		LIMIT ? OFFSET ?
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, entityID, entityType, limit, offset)
+	rows, err := r.readDB.QueryContext(ctx, query, entityID, entityType, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("error finding action logs by subject entity: %w", err)
 	}
@@ -151,7 +162,7 @@ func (r *ActionLogRepositoryImpl) FindRecent(ctx context.Context, limit int) ([]
 		LIMIT ?
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit)
+	rows, err := r.readDB.QueryContext(ctx, query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("error finding recent action logs: %w", err)
 	}
@@ -165,7 +176,7 @@ func (r *ActionLogRepositoryImpl) Count(ctx context.Context) (int, error) {
 	query := `SELECT COUNT(*) FROM action_logs`
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.readDB.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("error counting action logs: %w", err)
 	}
@@ -178,7 +189,7 @@ func (r *ActionLogRepositoryImpl) CountByUser(ctx context.Context, userID string
 	query := `SELECT COUNT(*) FROM action_logs WHERE performed_by_user_id = ?`
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(&count)
+	err := r.readDB.QueryRowContext(ctx, query, userID).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("error counting action logs by user: %w", err)
 	}
@@ -186,6 +197,54 @@ func (r *ActionLogRepositoryImpl) CountByUser(ctx context.Context, userID string
 	return count, nil
 }
 
+// StreamByFilter recorre los logs que cumplen el filtro, del más antiguo al más reciente, fila a fila
+func (r *ActionLogRepositoryImpl) StreamByFilter(ctx context.Context, filter interfaces.ActionLogFilter, fn func(*actionlog.ActionLog) error) error {
+	query := `
+		SELECT log_id, timestamp, action_type, description, performed_by_user_id, 
+		       subject_entity_id, subject_entity_type, details, created_at
+		FROM action_logs 
+		WHERE 1 = 1`
+	var args []interface{}
+	if filter.PerformedByUserID != "" {
+		query += " AND performed_by_user_id = ?"
+		args = append(args, filter.PerformedByUserID)
+	}
+	if filter.ActionType != "" {
+		query += " AND action_type = ?"
+		args = append(args, string(filter.ActionType))
+	}
+	if filter.SubjectEntityID != "" {
+		query += " AND subject_entity_id = ?"
+		args = append(args, filter.SubjectEntityID)
+	}
+	if filter.SubjectEntityType != "" {
+		query += " AND subject_entity_type = ?"
+		args = append(args, filter.SubjectEntityType)
+	}
+	query += " ORDER BY timestamp ASC, log_id ASC"
+
+	rows, err := r.readDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("error streaming action logs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		actionLog, err := r.scanActionLogRow(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(actionLog); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating action log rows: %w", err)
+	}
+	return nil
+}
+
 // Helper methods
 
 // scanActionLog escanea una fila en un ActionLog
@@ -255,60 +314,10 @@ func (r *ActionLogRepositoryImpl) scanActionLogs(rows *sql.Rows) ([]*actionlog.A
 	var logs []*actionlog.ActionLog
 
 	for rows.Next() {
-		var actionTypeStr string
-		var subjectEntityID, subjectEntityType sql.NullString
-		var detailsJSON sql.NullString
-		var logID int64
-		var timestamp, createdAt time.Time
-		var description, performedByUserID string
-
-		err := rows.Scan(
-			&logID,
-			&timestamp,
-			&actionTypeStr,
-			&description,
-			&performedByUserID,
-			&subjectEntityID,
-			&subjectEntityType,
-			&detailsJSON,
-			&createdAt,
-		)
-
+		actionLog, err := r.scanActionLogRow(rows)
 		if err != nil {
-			return nil, fmt.Errorf("error scanning action log row: %w", err)
+			return nil, err
 		}
-
-		// Convertir campos nullable
-		var entityID, entityType *string
-		if subjectEntityID.Valid {
-			entityID = &subjectEntityID.String
-		}
-		if subjectEntityType.Valid {
-			entityType = &subjectEntityType.String
-		}
-
-		// Convertir JSON details
-		var details map[string]interface{}
-		if detailsJSON.Valid && detailsJSON.String != "" {
-			err = json.Unmarshal([]byte(detailsJSON.String), &details)
-			if err != nil {
-				return nil, fmt.Errorf("error unmarshalling details JSON: %w", err)
-			}
-		}
-
-		// Crear ActionLog desde DB
-		actionLog := actionlog.NewActionLogFromDB(
-			logID,
-			timestamp,
-			actionlog.ActionType(actionTypeStr),
-			description,
-			performedByUserID,
-			entityID,
-			entityType,
-			details,
-			createdAt,
-		)
-
 		logs = append(logs, actionLog)
 	}
 
@@ -318,3 +327,62 @@ func (r *ActionLogRepositoryImpl) scanActionLogs(rows *sql.Rows) ([]*actionlog.A
 
 	return logs, nil
 }
+
+// scanActionLogRow escanea la fila actual de rows en un ActionLog
+func (r *ActionLogRepositoryImpl) scanActionLogRow(rows *sql.Rows) (*actionlog.ActionLog, error) {
+	var actionTypeStr string
+	var subjectEntityID, subjectEntityType sql.NullString
+	var detailsJSON sql.NullString
+	var logID int64
+	var timestamp, createdAt time.Time
+	var description, performedByUserID string
+
+	err := rows.Scan(
+		&logID,
+		&timestamp,
+		&actionTypeStr,
+		&description,
+		&performedByUserID,
+		&subjectEntityID,
+		&subjectEntityType,
+		&detailsJSON,
+		&createdAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("error scanning action log row: %w", err)
+	}
+
+	// Convertir campos nullable
+	var entityID, entityType *string
+	if subjectEntityID.Valid {
+		entityID = &subjectEntityID.String
+	}
+	if subjectEntityType.Valid {
+		entityType = &subjectEntityType.String
+	}
+
+	// Convertir JSON details
+	var details map[string]interface{}
+	if detailsJSON.Valid && detailsJSON.String != "" {
+		err = json.Unmarshal([]byte(detailsJSON.String), &details)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling details JSON: %w", err)
+		}
+	}
+
+	// Crear ActionLog desde DB
+	actionLog := actionlog.NewActionLogFromDB(
+		logID,
+		timestamp,
+		actionlog.ActionType(actionTypeStr),
+		description,
+		performedByUserID,
+		entityID,
+		entityType,
+		details,
+		createdAt,
+	)
+
+	return actionLog, nil
+}
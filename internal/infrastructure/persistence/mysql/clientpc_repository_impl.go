@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
@@ -27,13 +28,16 @@ func (r *ClientPCRepositoryImpl) Save(ctx context.Context, pc *clientpc.ClientPC
 	query := `
 		INSERT INTO client_pcs (
 			pc_id, identifier, ip, connection_status, registered_at, owner_user_id, 
-			last_seen_at, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON DUPLICATE KEY UPDATE
 			identifier = VALUES(identifier),
 			ip = VALUES(ip),
 			connection_status = VALUES(connection_status),
 			last_seen_at = VALUES(last_seen_at),
+			os = VALUES(os),
+			hostname = VALUES(hostname),
+			agent_version = VALUES(agent_version),
 			updated_at = VALUES(updated_at)
 	`
 
@@ -45,6 +49,10 @@ func (r *ClientPCRepositoryImpl) Save(ctx context.Context, pc *clientpc.ClientPC
 		pc.RegisteredAt,
 		pc.OwnerUserID,
 		pc.LastSeenAt,
+		pc.AutoAcceptControl,
+		nullString(pc.OS),
+		nullString(pc.Hostname),
+		nullString(pc.AgentVersion),
 		pc.CreatedAt,
 		pc.UpdatedAt,
 	)
@@ -56,7 +64,7 @@ func (r *ClientPCRepositoryImpl) Save(ctx context.Context, pc *clientpc.ClientPC
 func (r *ClientPCRepositoryImpl) FindByID(ctx context.Context, pcID string) (*clientpc.ClientPC, error) {
 	query := `
 		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id,
-			   last_seen_at, created_at, updated_at
+			   last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at
 		FROM client_pcs 
 		WHERE pc_id = ?
 	`
@@ -66,10 +74,12 @@ func (r *ClientPCRepositoryImpl) FindByID(ctx context.Context, pcID string) (*cl
 	var pcIDStr, identifier, ip, connectionStatusStr, ownerUserID string
 	var registeredAt, createdAt, updatedAt time.Time
 	var lastSeenAt *time.Time
+	var autoAcceptControl bool
+	var osName, hostname, agentVersion sql.NullString
 
 	err := row.Scan(
 		&pcIDStr, &identifier, &ip, &connectionStatusStr, &registeredAt,
-		&ownerUserID, &lastSeenAt, &createdAt, &updatedAt,
+		&ownerUserID, &lastSeenAt, &autoAcceptControl, &osName, &hostname, &agentVersion, &createdAt, &updatedAt,
 	)
 
 	if err != nil {
@@ -81,25 +91,66 @@ func (r *ClientPCRepositoryImpl) FindByID(ctx context.Context, pcID string) (*cl
 
 	// Construir la entidad simple
 	pc := &clientpc.ClientPC{
-		PCID:             pcIDStr,
-		Identifier:       identifier,
-		IP:               ip,
-		ConnectionStatus: clientpc.PCConnectionStatus(connectionStatusStr),
-		RegisteredAt:     registeredAt,
-		OwnerUserID:      ownerUserID,
-		LastSeenAt:       lastSeenAt,
-		CreatedAt:        createdAt,
-		UpdatedAt:        updatedAt,
+		PCID:              pcIDStr,
+		Identifier:        identifier,
+		IP:                ip,
+		ConnectionStatus:  clientpc.PCConnectionStatus(connectionStatusStr),
+		RegisteredAt:      registeredAt,
+		OwnerUserID:       ownerUserID,
+		LastSeenAt:        lastSeenAt,
+		AutoAcceptControl: autoAcceptControl,
+		OS:                osName.String,
+		Hostname:          hostname.String,
+		AgentVersion:      agentVersion.String,
+		CreatedAt:         createdAt,
+		UpdatedAt:         updatedAt,
 	}
 
 	return pc, nil
 }
 
+// FindByIDs busca varios ClientPCs en una sola consulta, indexados por ID
+func (r *ClientPCRepositoryImpl) FindByIDs(ctx context.Context, pcIDs []string) (map[string]*clientpc.ClientPC, error) {
+	pcs := make(map[string]*clientpc.ClientPC, len(pcIDs))
+	if len(pcIDs) == 0 {
+		return pcs, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id,
+			   last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at
+		FROM client_pcs 
+		WHERE pc_id IN (%s)
+	`, strings.TrimSuffix(strings.Repeat("?, ", len(pcIDs)), ", "))
+
+	args := make([]interface{}, len(pcIDs))
+	for i, id := range pcIDs {
+		args[i] = id
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found, err := r.scanClientPCs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pc := range found {
+		pcs[pc.PCID] = pc
+	}
+
+	return pcs, nil
+}
+
 // FindByIdentifierAndOwner busca un PC por identificador y propietario
 func (r *ClientPCRepositoryImpl) FindByIdentifierAndOwner(ctx context.Context, identifier, ownerUserID string) (*clientpc.ClientPC, error) {
 	query := `
 		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id,
-			   last_seen_at, created_at, updated_at
+			   last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at
 		FROM client_pcs 
 		WHERE identifier = ? AND owner_user_id = ?
 		LIMIT 1
@@ -110,10 +161,12 @@ func (r *ClientPCRepositoryImpl) FindByIdentifierAndOwner(ctx context.Context, i
 	var pcIDStr, identifierCol, ip, connectionStatusStr, ownerID string
 	var registeredAt, createdAt, updatedAt time.Time
 	var lastSeenAt *time.Time
+	var autoAcceptControl bool
+	var osName, hostname, agentVersion sql.NullString
 
 	err := row.Scan(
 		&pcIDStr, &identifierCol, &ip, &connectionStatusStr, &registeredAt,
-		&ownerID, &lastSeenAt, &createdAt, &updatedAt,
+		&ownerID, &lastSeenAt, &autoAcceptControl, &osName, &hostname, &agentVersion, &createdAt, &updatedAt,
 	)
 
 	if err != nil {
@@ -125,15 +178,19 @@ func (r *ClientPCRepositoryImpl) FindByIdentifierAndOwner(ctx context.Context, i
 
 	// Construir la entidad simple
 	pc := &clientpc.ClientPC{
-		PCID:             pcIDStr,
-		Identifier:       identifierCol,
-		IP:               ip,
-		ConnectionStatus: clientpc.PCConnectionStatus(connectionStatusStr),
-		RegisteredAt:     registeredAt,
-		OwnerUserID:      ownerID,
-		LastSeenAt:       lastSeenAt,
-		CreatedAt:        createdAt,
-		UpdatedAt:        updatedAt,
+		PCID:              pcIDStr,
+		Identifier:        identifierCol,
+		IP:                ip,
+		ConnectionStatus:  clientpc.PCConnectionStatus(connectionStatusStr),
+		RegisteredAt:      registeredAt,
+		OwnerUserID:       ownerID,
+		LastSeenAt:        lastSeenAt,
+		AutoAcceptControl: autoAcceptControl,
+		OS:                osName.String,
+		Hostname:          hostname.String,
+		AgentVersion:      agentVersion.String,
+		CreatedAt:         createdAt,
+		UpdatedAt:         updatedAt,
 	}
 
 	return pc, nil
@@ -143,7 +200,7 @@ func (r *ClientPCRepositoryImpl) FindByIdentifierAndOwner(ctx context.Context, i
 func (r *ClientPCRepositoryImpl) FindByOwner(ctx context.Context, ownerUserID string) ([]*clientpc.ClientPC, error) {
 	query := `
 		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id,
-			   last_seen_at, created_at, updated_at
+			   last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at
 		FROM client_pcs 
 		WHERE owner_user_id = ?
 		ORDER BY created_at DESC
@@ -162,7 +219,7 @@ func (r *ClientPCRepositoryImpl) FindByOwner(ctx context.Context, ownerUserID st
 func (r *ClientPCRepositoryImpl) FindOnlineByOwner(ctx context.Context, ownerUserID string) ([]*clientpc.ClientPC, error) {
 	query := `
 		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id,
-			   last_seen_at, created_at, updated_at
+			   last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at
 		FROM client_pcs 
 		WHERE owner_user_id = ? AND connection_status = 'ONLINE'
 		ORDER BY last_seen_at DESC
@@ -181,7 +238,7 @@ func (r *ClientPCRepositoryImpl) FindOnlineByOwner(ctx context.Context, ownerUse
 func (r *ClientPCRepositoryImpl) FindAll(ctx context.Context, limit, offset int) ([]*clientpc.ClientPC, error) {
 	query := `
 		SELECT pc_id, identifier, ip, connection_status, registered_at, owner_user_id,
-			   last_seen_at, created_at, updated_at
+			   last_seen_at, auto_accept_control, os, hostname, agent_version, created_at, updated_at
 		FROM client_pcs 
 		ORDER BY created_at DESC
 	`
@@ -227,6 +284,18 @@ func (r *ClientPCRepositoryImpl) UpdateLastSeen(ctx context.Context, pcID string
 	return err
 }
 
+// UpdateAutoAcceptControl actualiza la política de auto-aceptación de control remoto
+func (r *ClientPCRepositoryImpl) UpdateAutoAcceptControl(ctx context.Context, pcID string, enabled bool) error {
+	query := `
+		UPDATE client_pcs 
+		SET auto_accept_control = ?, updated_at = ?
+		WHERE pc_id = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, enabled, time.Now().UTC(), pcID)
+	return err
+}
+
 // Delete elimina un ClientPC
 func (r *ClientPCRepositoryImpl) Delete(ctx context.Context, pcID string) error {
 	query := `DELETE FROM client_pcs WHERE pc_id = ?`
@@ -243,6 +312,15 @@ func (r *ClientPCRepositoryImpl) CountByOwner(ctx context.Context, ownerID strin
 	return count, err
 }
 
+// Count retorna el número total de PCs registrados
+func (r *ClientPCRepositoryImpl) Count(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM client_pcs`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	return count, err
+}
+
 // scanClientPCs es un helper para escanear múltiples filas
 func (r *ClientPCRepositoryImpl) scanClientPCs(rows *sql.Rows) ([]*clientpc.ClientPC, error) {
 	var pcs []*clientpc.ClientPC
@@ -251,10 +329,12 @@ func (r *ClientPCRepositoryImpl) scanClientPCs(rows *sql.Rows) ([]*clientpc.Clie
 		var pcIDStr, identifier, ip, connectionStatusStr, ownerUserID string
 		var registeredAt, createdAt, updatedAt time.Time
 		var lastSeenAt *time.Time
+		var autoAcceptControl bool
+		var osName, hostname, agentVersion sql.NullString
 
 		err := rows.Scan(
 			&pcIDStr, &identifier, &ip, &connectionStatusStr, &registeredAt,
-			&ownerUserID, &lastSeenAt, &createdAt, &updatedAt,
+			&ownerUserID, &lastSeenAt, &autoAcceptControl, &osName, &hostname, &agentVersion, &createdAt, &updatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -262,15 +342,19 @@ func (r *ClientPCRepositoryImpl) scanClientPCs(rows *sql.Rows) ([]*clientpc.Clie
 
 		// Construir la entidad simple
 		pc := &clientpc.ClientPC{
-			PCID:             pcIDStr,
-			Identifier:       identifier,
-			IP:               ip,
-			ConnectionStatus: clientpc.PCConnectionStatus(connectionStatusStr),
-			RegisteredAt:     registeredAt,
-			OwnerUserID:      ownerUserID,
-			LastSeenAt:       lastSeenAt,
-			CreatedAt:        createdAt,
-			UpdatedAt:        updatedAt,
+			PCID:              pcIDStr,
+			Identifier:        identifier,
+			IP:                ip,
+			ConnectionStatus:  clientpc.PCConnectionStatus(connectionStatusStr),
+			RegisteredAt:      registeredAt,
+			OwnerUserID:       ownerUserID,
+			LastSeenAt:        lastSeenAt,
+			AutoAcceptControl: autoAcceptControl,
+			OS:                osName.String,
+			Hostname:          hostname.String,
+			AgentVersion:      agentVersion.String,
+			CreatedAt:         createdAt,
+			UpdatedAt:         updatedAt,
 		}
 
 		pcs = append(pcs, pc)
@@ -0,0 +1,195 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/connectionsession"
+)
+
+// ConnectionSessionRepositoryImpl implementa IConnectionSessionRepository usando MySQL
+type ConnectionSessionRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewConnectionSessionRepository crea una nueva instancia del repositorio
+func NewConnectionSessionRepository(db *sql.DB) interfaces.IConnectionSessionRepository {
+	return &ConnectionSessionRepositoryImpl{
+		db: db,
+	}
+}
+
+// Save guarda una nueva sesión de conexión
+func (r *ConnectionSessionRepositoryImpl) Save(ctx context.Context, session *connectionsession.ConnectionSession) error {
+	query := `
+		INSERT INTO pc_connection_sessions (
+			connection_id, pc_id, ip_address, connected_at, disconnected_at, disconnect_reason
+		) VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		session.ConnectionID(),
+		session.PCID(),
+		session.IPAddress(),
+		session.ConnectedAt(),
+		session.DisconnectedAt(),
+		nullString(session.DisconnectReason()),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save connection session: %w", err)
+	}
+
+	return nil
+}
+
+// Update actualiza los datos de desconexión de una sesión de conexión
+func (r *ConnectionSessionRepositoryImpl) Update(ctx context.Context, session *connectionsession.ConnectionSession) error {
+	query := `
+		UPDATE pc_connection_sessions
+		SET disconnected_at = ?, disconnect_reason = ?
+		WHERE connection_id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		session.DisconnectedAt(),
+		nullString(session.DisconnectReason()),
+		session.ConnectionID(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update connection session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("connection session with ID %s not found", session.ConnectionID())
+	}
+
+	return nil
+}
+
+// FindByID busca una sesión de conexión por su ID
+func (r *ConnectionSessionRepositoryImpl) FindByID(ctx context.Context, connectionID string) (*connectionsession.ConnectionSession, error) {
+	query := `
+		SELECT connection_id, pc_id, ip_address, connected_at, disconnected_at, disconnect_reason
+		FROM pc_connection_sessions
+		WHERE connection_id = ?
+	`
+
+	session, err := r.scanConnectionSession(r.db.QueryRowContext(ctx, query, connectionID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find connection session: %w", err)
+	}
+
+	return session, nil
+}
+
+// FindByPCID obtiene el historial de conexiones de un PC, más recientes primero
+func (r *ConnectionSessionRepositoryImpl) FindByPCID(ctx context.Context, pcID string, limit, offset int) ([]*connectionsession.ConnectionSession, error) {
+	query := `
+		SELECT connection_id, pc_id, ip_address, connected_at, disconnected_at, disconnect_reason
+		FROM pc_connection_sessions
+		WHERE pc_id = ?
+		ORDER BY connected_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pcID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find connection sessions by PC: %w", err)
+	}
+	return r.scanConnectionSessions(rows)
+}
+
+// FindOpenByPCID obtiene las sesiones de conexión de un PC que siguen sin desconexión registrada
+func (r *ConnectionSessionRepositoryImpl) FindOpenByPCID(ctx context.Context, pcID string) ([]*connectionsession.ConnectionSession, error) {
+	query := `
+		SELECT connection_id, pc_id, ip_address, connected_at, disconnected_at, disconnect_reason
+		FROM pc_connection_sessions
+		WHERE pc_id = ? AND disconnected_at IS NULL
+		ORDER BY connected_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pcID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find open connection sessions by PC: %w", err)
+	}
+	return r.scanConnectionSessions(rows)
+}
+
+// scanConnectionSessions reconstruye todas las filas de la consulta y cierra rows
+func (r *ConnectionSessionRepositoryImpl) scanConnectionSessions(rows *sql.Rows) ([]*connectionsession.ConnectionSession, error) {
+	defer rows.Close()
+
+	sessions := make([]*connectionsession.ConnectionSession, 0)
+	for rows.Next() {
+		session, err := r.scanConnectionSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan connection session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating connection sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// CountByPCID cuenta las sesiones de conexión registradas para un PC
+func (r *ConnectionSessionRepositoryImpl) CountByPCID(ctx context.Context, pcID string) (int, error) {
+	query := `SELECT COUNT(*) FROM pc_connection_sessions WHERE pc_id = ?`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, pcID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count connection sessions by PC: %w", err)
+	}
+
+	return count, nil
+}
+
+// connectionSessionScanner abstrae *sql.Row y *sql.Rows para reutilizar el escaneo
+type connectionSessionScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanConnectionSession reconstruye una ConnectionSession a partir de una fila
+func (r *ConnectionSessionRepositoryImpl) scanConnectionSession(row connectionSessionScanner) (*connectionsession.ConnectionSession, error) {
+	var connectionID, pcID, ipAddress string
+	var connectedAt time.Time
+	var disconnectedAt sql.NullTime
+	var disconnectReason sql.NullString
+
+	if err := row.Scan(&connectionID, &pcID, &ipAddress, &connectedAt, &disconnectedAt, &disconnectReason); err != nil {
+		return nil, err
+	}
+
+	var disconnectedAtPtr *time.Time
+	if disconnectedAt.Valid {
+		disconnectedAtPtr = &disconnectedAt.Time
+	}
+
+	return connectionsession.NewConnectionSessionFromDB(
+		connectionID,
+		pcID,
+		ipAddress,
+		connectedAt,
+		disconnectedAtPtr,
+		disconnectReason.String,
+	), nil
+}
+
+// nullString convierte un string vacío en NULL para la base de datos
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -27,8 +27,8 @@ func (r *FileTransferRepositoryImpl) Save(ctx context.Context, transfer *filetra
 		INSERT INTO file_transfers (
 			transfer_id, file_name, source_path_server, destination_path_client,
 			transfer_time, status, associated_session_id, initiating_user_id,
-			target_pc_id, file_size_mb, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			target_pc_id, file_size_mb, conflict_policy, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -42,6 +42,7 @@ func (r *FileTransferRepositoryImpl) Save(ctx context.Context, transfer *filetra
 		transfer.InitiatingUserID(),
 		transfer.TargetPCID(),
 		transfer.FileSizeMB(),
+		string(transfer.ConflictPolicy()),
 		transfer.CreatedAt(),
 		transfer.UpdatedAt(),
 	)
@@ -53,7 +54,8 @@ func (r *FileTransferRepositoryImpl) Save(ctx context.Context, transfer *filetra
 	return nil
 }
 
-// UpdateStatus actualiza el estado de una transferencia existente
+// UpdateStatus actualiza el estado de una transferencia existente; al pasar a COMPLETED guarda también el
+// momento de la transferencia en transfer_time
 func (r *FileTransferRepositoryImpl) UpdateStatus(
 	ctx context.Context,
 	transferID string,
@@ -62,11 +64,14 @@ func (r *FileTransferRepositoryImpl) UpdateStatus(
 ) error {
 	query := `
 		UPDATE file_transfers 
-		SET status = ?, updated_at = ?
+		SET status = ?, updated_at = ?,
+		    transfer_time = CASE WHEN ? THEN ? ELSE transfer_time END
 		WHERE transfer_id = ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query, string(status), time.Now(), transferID)
+	now := time.Now()
+	completed := status == filetransfer.TransferStatusCompleted
+	result, err := r.db.ExecContext(ctx, query, string(status), now, completed, now, transferID)
 	if err != nil {
 		return fmt.Errorf("error actualizando estado de transferencia: %w", err)
 	}
@@ -88,7 +93,7 @@ func (r *FileTransferRepositoryImpl) FindByID(ctx context.Context, transferID st
 	query := `
 		SELECT transfer_id, file_name, source_path_server, destination_path_client,
 			   transfer_time, status, associated_session_id, initiating_user_id,
-			   target_pc_id, file_size_mb, created_at, updated_at
+			   target_pc_id, file_size_mb, conflict_policy, created_at, updated_at
 		FROM file_transfers
 		WHERE transfer_id = ?
 	`
@@ -102,7 +107,7 @@ func (r *FileTransferRepositoryImpl) FindBySessionID(ctx context.Context, sessio
 	query := `
 		SELECT transfer_id, file_name, source_path_server, destination_path_client,
 			   transfer_time, status, associated_session_id, initiating_user_id,
-			   target_pc_id, file_size_mb, created_at, updated_at
+			   target_pc_id, file_size_mb, conflict_policy, created_at, updated_at
 		FROM file_transfers
 		WHERE associated_session_id = ?
 		ORDER BY created_at DESC
@@ -122,7 +127,7 @@ func (r *FileTransferRepositoryImpl) FindByTargetPCID(ctx context.Context, targe
 	query := `
 		SELECT transfer_id, file_name, source_path_server, destination_path_client,
 			   transfer_time, status, associated_session_id, initiating_user_id,
-			   target_pc_id, file_size_mb, created_at, updated_at
+			   target_pc_id, file_size_mb, conflict_policy, created_at, updated_at
 		FROM file_transfers
 		WHERE target_pc_id = ?
 		ORDER BY created_at DESC
@@ -137,12 +142,31 @@ func (r *FileTransferRepositoryImpl) FindByTargetPCID(ctx context.Context, targe
 	return r.scanFileTransfers(rows)
 }
 
+// SumFileSizeByTargetPCID suma en una sola consulta el tamaño de las transferencias enviadas a un PC; las fallidas
+// (también por falta de espacio en el cliente) no ocupan espacio en él
+func (r *FileTransferRepositoryImpl) SumFileSizeByTargetPCID(ctx context.Context, targetPCID string) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(file_size_mb), 0)
+		FROM file_transfers
+		WHERE target_pc_id = ? AND status NOT IN (?, ?)
+	`
+
+	var totalMB float64
+	err := r.db.QueryRowContext(ctx, query, targetPCID,
+		string(filetransfer.TransferStatusFailed), string(filetransfer.TransferStatusInsufficientClientSpace)).Scan(&totalMB)
+	if err != nil {
+		return 0, fmt.Errorf("error sumando transferencias por PC: %w", err)
+	}
+
+	return totalMB, nil
+}
+
 // FindByInitiatingUserID busca todas las transferencias iniciadas por un usuario
 func (r *FileTransferRepositoryImpl) FindByInitiatingUserID(ctx context.Context, userID string) ([]*filetransfer.FileTransfer, error) {
 	query := `
 		SELECT transfer_id, file_name, source_path_server, destination_path_client,
 			   transfer_time, status, associated_session_id, initiating_user_id,
-			   target_pc_id, file_size_mb, created_at, updated_at
+			   target_pc_id, file_size_mb, conflict_policy, created_at, updated_at
 		FROM file_transfers
 		WHERE initiating_user_id = ?
 		ORDER BY created_at DESC
@@ -167,12 +191,22 @@ func (r *FileTransferRepositoryImpl) FindInProgressTransfers(ctx context.Context
 	return r.findByStatus(ctx, filetransfer.TransferStatusInProgress)
 }
 
+// Delete elimina el registro de una transferencia
+func (r *FileTransferRepositoryImpl) Delete(ctx context.Context, transferID string) error {
+	query := `DELETE FROM file_transfers WHERE transfer_id = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, transferID); err != nil {
+		return fmt.Errorf("error eliminando transferencia: %w", err)
+	}
+	return nil
+}
+
 // findByStatus busca transferencias por estado
 func (r *FileTransferRepositoryImpl) findByStatus(ctx context.Context, status filetransfer.TransferStatus) ([]*filetransfer.FileTransfer, error) {
 	query := `
 		SELECT transfer_id, file_name, source_path_server, destination_path_client,
 			   transfer_time, status, associated_session_id, initiating_user_id,
-			   target_pc_id, file_size_mb, created_at, updated_at
+			   target_pc_id, file_size_mb, conflict_policy, created_at, updated_at
 		FROM file_transfers
 		WHERE status = ?
 		ORDER BY created_at ASC
@@ -190,15 +224,15 @@ func (r *FileTransferRepositoryImpl) findByStatus(ctx context.Context, status fi
 // scanFileTransfer convierte una fila de BD en una entidad FileTransfer
 func (r *FileTransferRepositoryImpl) scanFileTransfer(row *sql.Row) (*filetransfer.FileTransfer, error) {
 	var transferID, fileName, sourcePathServer, destinationPathClient string
-	var transferTime time.Time
-	var statusStr, associatedSessionID, initiatingUserID, targetPCID string
+	var transferTime sql.NullTime
+	var statusStr, associatedSessionID, initiatingUserID, targetPCID, conflictPolicy string
 	var fileSizeMB float64
 	var createdAt, updatedAt time.Time
 
 	err := row.Scan(
 		&transferID, &fileName, &sourcePathServer, &destinationPathClient,
 		&transferTime, &statusStr, &associatedSessionID, &initiatingUserID,
-		&targetPCID, &fileSizeMB, &createdAt, &updatedAt,
+		&targetPCID, &fileSizeMB, &conflictPolicy, &createdAt, &updatedAt,
 	)
 
 	if err != nil {
@@ -214,13 +248,14 @@ func (r *FileTransferRepositoryImpl) scanFileTransfer(row *sql.Row) (*filetransf
 		fileName,
 		sourcePathServer,
 		destinationPathClient,
-		transferTime,
+		transferTimePtr(transferTime),
 		filetransfer.TransferStatus(statusStr),
 		associatedSessionID,
 		initiatingUserID,
 		targetPCID,
 		fileSizeMB,
 		"", // errorMessage (no está en esta consulta, se agregará si es necesario)
+		filetransfer.ConflictPolicy(conflictPolicy),
 		createdAt,
 		updatedAt,
 	)
@@ -234,15 +269,15 @@ func (r *FileTransferRepositoryImpl) scanFileTransfers(rows *sql.Rows) ([]*filet
 
 	for rows.Next() {
 		var transferID, fileName, sourcePathServer, destinationPathClient string
-		var transferTime time.Time
-		var statusStr, associatedSessionID, initiatingUserID, targetPCID string
+		var transferTime sql.NullTime
+		var statusStr, associatedSessionID, initiatingUserID, targetPCID, conflictPolicy string
 		var fileSizeMB float64
 		var createdAt, updatedAt time.Time
 
 		err := rows.Scan(
 			&transferID, &fileName, &sourcePathServer, &destinationPathClient,
 			&transferTime, &statusStr, &associatedSessionID, &initiatingUserID,
-			&targetPCID, &fileSizeMB, &createdAt, &updatedAt,
+			&targetPCID, &fileSizeMB, &conflictPolicy, &createdAt, &updatedAt,
 		)
 
 		if err != nil {
@@ -255,13 +290,14 @@ func (r *FileTransferRepositoryImpl) scanFileTransfers(rows *sql.Rows) ([]*filet
 			fileName,
 			sourcePathServer,
 			destinationPathClient,
-			transferTime,
+			transferTimePtr(transferTime),
 			filetransfer.TransferStatus(statusStr),
 			associatedSessionID,
 			initiatingUserID,
 			targetPCID,
 			fileSizeMB,
 			"", // errorMessage (no está en esta consulta, se agregará si es necesario)
+			filetransfer.ConflictPolicy(conflictPolicy),
 			createdAt,
 			updatedAt,
 		)
@@ -274,4 +310,12 @@ func (r *FileTransferRepositoryImpl) scanFileTransfers(rows *sql.Rows) ([]*filet
 	}
 
 	return transfers, nil
-} 
\ No newline at end of file
+} 
+
+// transferTimePtr convierte transfer_time, NULL mientras la transferencia no se completó
+func transferTimePtr(transferTime sql.NullTime) *time.Time {
+	if !transferTime.Valid {
+		return nil
+	}
+	return &transferTime.Time
+}
@@ -0,0 +1,166 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/macro"
+)
+
+// MacroRepositoryImpl implementa IMacroRepository usando MySQL; los comandos se guardan como JSON
+type MacroRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewMacroRepository crea una nueva instancia del repositorio de macros
+func NewMacroRepository(db *sql.DB) interfaces.IMacroRepository {
+	return &MacroRepositoryImpl{
+		db: db,
+	}
+}
+
+// storedMacroCommand representación JSON de un comando en la columna commands
+type storedMacroCommand struct {
+	EventType string                 `json:"event_type"`
+	Action    string                 `json:"action"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
+	DelayMs   int64                  `json:"delay_ms"`
+}
+
+// Save guarda una nueva macro
+func (r *MacroRepositoryImpl) Save(ctx context.Context, m *macro.Macro) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	commands := make([]storedMacroCommand, 0, m.CommandCount())
+	for _, command := range m.Commands() {
+		commands = append(commands, storedMacroCommand{
+			EventType: command.EventType,
+			Action:    command.Action,
+			Payload:   command.Payload,
+			DelayMs:   command.Delay.Milliseconds(),
+		})
+	}
+
+	commandsJSON, err := json.Marshal(commands)
+	if err != nil {
+		return fmt.Errorf("failed to encode macro commands: %w", err)
+	}
+
+	query := `
+		INSERT INTO input_macros (macro_id, admin_user_id, name, source_session_id, commands, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		m.MacroID(),
+		m.AdminUserID(),
+		m.Name(),
+		nullString(m.SourceSessionID()),
+		commandsJSON,
+		m.CreatedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save macro: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID busca una macro por su ID
+func (r *MacroRepositoryImpl) FindByID(ctx context.Context, macroID string) (*macro.Macro, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT macro_id, admin_user_id, name, source_session_id, commands, created_at
+		FROM input_macros
+		WHERE macro_id = ?
+	`
+
+	m, err := r.scanMacro(r.db.QueryRowContext(ctx, query, macroID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find macro: %w", err)
+	}
+
+	return m, nil
+}
+
+// FindByAdminUserID obtiene las macros de un administrador, más recientes primero
+func (r *MacroRepositoryImpl) FindByAdminUserID(ctx context.Context, adminUserID string) ([]*macro.Macro, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT macro_id, admin_user_id, name, source_session_id, commands, created_at
+		FROM input_macros
+		WHERE admin_user_id = ?
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, adminUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find macros by admin: %w", err)
+	}
+	defer rows.Close()
+
+	macros := make([]*macro.Macro, 0)
+	for rows.Next() {
+		m, err := r.scanMacro(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan macro: %w", err)
+		}
+		macros = append(macros, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating macros: %w", err)
+	}
+
+	return macros, nil
+}
+
+// macroScanner abstrae *sql.Row y *sql.Rows para reutilizar el escaneo
+type macroScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanMacro convierte una fila en una entidad Macro
+func (r *MacroRepositoryImpl) scanMacro(scanner macroScanner) (*macro.Macro, error) {
+	var (
+		macroID         string
+		adminUserID     string
+		name            string
+		sourceSessionID sql.NullString
+		commandsJSON    []byte
+		createdAt       time.Time
+	)
+
+	if err := scanner.Scan(&macroID, &adminUserID, &name, &sourceSessionID, &commandsJSON, &createdAt); err != nil {
+		return nil, err
+	}
+
+	var stored []storedMacroCommand
+	if err := json.Unmarshal(commandsJSON, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode commands of macro %s: %w", macroID, err)
+	}
+
+	commands := make([]macro.Command, len(stored))
+	for i, command := range stored {
+		commands[i] = macro.Command{
+			EventType: command.EventType,
+			Action:    command.Action,
+			Payload:   command.Payload,
+			Delay:     time.Duration(command.DelayMs) * time.Millisecond,
+		}
+	}
+
+	return macro.NewMacroFromDB(macroID, adminUserID, name, sourceSessionID.String, commands, createdAt), nil
+}
@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+)
+
+// NotificationPreferenceRepositoryImpl implementa INotificationPreferenceRepository usando MySQL; las categorías
+// silenciadas se guardan como JSON
+type NotificationPreferenceRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewNotificationPreferenceRepository crea una nueva instancia del repositorio
+func NewNotificationPreferenceRepository(db *sql.DB) interfaces.INotificationPreferenceRepository {
+	return &NotificationPreferenceRepositoryImpl{
+		db: db,
+	}
+}
+
+// FindByAdmin obtiene las preferencias del administrador
+func (r *NotificationPreferenceRepositoryImpl) FindByAdmin(ctx context.Context, adminUserID string) (*interfaces.NotificationPreferenceRecord, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT scope, muted_categories
+		FROM admin_notification_preferences
+		WHERE admin_user_id = ?
+	`
+
+	var (
+		scope     string
+		mutedJSON []byte
+	)
+	if err := r.db.QueryRowContext(ctx, query, adminUserID).Scan(&scope, &mutedJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find notification preferences: %w", err)
+	}
+
+	record := &interfaces.NotificationPreferenceRecord{AdminUserID: adminUserID, Scope: scope}
+	if len(mutedJSON) > 0 {
+		if err := json.Unmarshal(mutedJSON, &record.MutedCategories); err != nil {
+			return nil, fmt.Errorf("failed to decode muted categories: %w", err)
+		}
+	}
+
+	return record, nil
+}
+
+// Save crea o reemplaza las preferencias del administrador
+func (r *NotificationPreferenceRepositoryImpl) Save(ctx context.Context, record *interfaces.NotificationPreferenceRecord) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	muted := record.MutedCategories
+	if muted == nil {
+		muted = []string{}
+	}
+	mutedJSON, err := json.Marshal(muted)
+	if err != nil {
+		return fmt.Errorf("failed to encode muted categories: %w", err)
+	}
+
+	query := `
+		INSERT INTO admin_notification_preferences (admin_user_id, scope, muted_categories)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE scope = VALUES(scope), muted_categories = VALUES(muted_categories)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, record.AdminUserID, record.Scope, mutedJSON); err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+
+	return nil
+}
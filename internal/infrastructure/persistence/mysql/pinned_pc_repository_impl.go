@@ -0,0 +1,77 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+)
+
+// PinnedPCRepositoryImpl implementa IPinnedPCRepository usando MySQL
+type PinnedPCRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewPinnedPCRepository crea una nueva instancia del repositorio
+func NewPinnedPCRepository(db *sql.DB) interfaces.IPinnedPCRepository {
+	return &PinnedPCRepositoryImpl{
+		db: db,
+	}
+}
+
+// Pin fija un PC para el administrador
+func (r *PinnedPCRepositoryImpl) Pin(ctx context.Context, adminUserID, pcID string) error {
+	query := `
+		INSERT IGNORE INTO pinned_pcs (admin_user_id, pc_id)
+		VALUES (?, ?)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, adminUserID, pcID); err != nil {
+		return fmt.Errorf("failed to pin PC: %w", err)
+	}
+
+	return nil
+}
+
+// Unpin quita el PC de los fijados del administrador
+func (r *PinnedPCRepositoryImpl) Unpin(ctx context.Context, adminUserID, pcID string) error {
+	query := `DELETE FROM pinned_pcs WHERE admin_user_id = ? AND pc_id = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, adminUserID, pcID); err != nil {
+		return fmt.Errorf("failed to unpin PC: %w", err)
+	}
+
+	return nil
+}
+
+// FindPCIDsByAdmin obtiene los IDs de los PCs fijados por el administrador, en orden de fijado
+func (r *PinnedPCRepositoryImpl) FindPCIDsByAdmin(ctx context.Context, adminUserID string) ([]string, error) {
+	query := `
+		SELECT pc_id
+		FROM pinned_pcs
+		WHERE admin_user_id = ?
+		ORDER BY pinned_at ASC, pc_id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, adminUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pinned PCs: %w", err)
+	}
+	defer rows.Close()
+
+	var pcIDs []string
+	for rows.Next() {
+		var pcID string
+		if err := rows.Scan(&pcID); err != nil {
+			return nil, fmt.Errorf("failed to scan pinned PC: %w", err)
+		}
+		pcIDs = append(pcIDs, pcID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pinned PCs: %w", err)
+	}
+
+	return pcIDs, nil
+}
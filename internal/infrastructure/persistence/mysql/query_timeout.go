@@ -0,0 +1,19 @@
+package mysql
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultQueryTimeout tiempo máximo de una sentencia en los repositorios de rutas calientes (HTTP y WebSocket).
+// Una BD bloqueada devuelve context.DeadlineExceeded en lugar de retener la petición indefinidamente.
+const DefaultQueryTimeout = 5 * time.Second
+
+// withQueryTimeout acota la sentencia a DefaultQueryTimeout; si el contexto recibido vence antes o ya
+// está cancelado, prevalece su plazo
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, DefaultQueryTimeout)
+}
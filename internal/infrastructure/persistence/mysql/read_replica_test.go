@@ -0,0 +1,53 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+	"github.com/unikyri/escritorio-remoto-backend/internal/infrastructure/database"
+)
+
+// newClosedDB handle ya cerrado: cualquier sentencia que lo use falla con "sql: database is closed"
+func newClosedDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("mysql", unreachableDSN)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	return db
+}
+
+func TestSessionVideoRepository_ReadsUseReplicaWhenConfigured(t *testing.T) {
+	// Arrange - el primario está cerrado; la réplica está abierta y solo falla por el contexto cancelado
+	repo := NewSessionVideoRepositoryWithRouter(database.NewDBRouter(newClosedDB(t), newUnreachableDB(t)))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, findErr := repo.FindAll(ctx, 10, 0)
+	_, countErr := repo.Count(ctx)
+	saveErr := repo.Save(context.Background(), sessionvideo.NewSessionVideo("video.mp4", 10, "session-id", 1))
+
+	// Assert
+	assert.ErrorIs(t, findErr, context.Canceled)
+	assert.ErrorIs(t, countErr, context.Canceled)
+	require.Error(t, saveErr)
+	assert.Contains(t, saveErr.Error(), "database is closed")
+}
+
+func TestActionLogRepository_ReadsFallBackToPrimaryWithoutReplica(t *testing.T) {
+	// Arrange
+	router := database.NewDBRouter(newClosedDB(t), nil)
+	repo := NewActionLogRepositoryWithRouter(router)
+
+	// Act
+	_, err := repo.FindRecent(context.Background(), 10)
+
+	// Assert
+	assert.False(t, router.HasReplica())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database is closed")
+}
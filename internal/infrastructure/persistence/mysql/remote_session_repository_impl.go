@@ -1,36 +1,53 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/infrastructure/database"
 )
 
 // RemoteSessionRepositoryImpl implementa IRemoteSessionRepository usando MySQL
 type RemoteSessionRepositoryImpl struct {
-	db *sql.DB
+	db     *sql.DB // primario: escrituras y lecturas que deben ver el último estado
+	readDB *sql.DB // réplica de lectura para los informes de sesiones por administrador (el primario si no hay réplica)
 }
 
 // NewRemoteSessionRepository crea una nueva instancia del repositorio
 func NewRemoteSessionRepository(db *sql.DB) interfaces.IRemoteSessionRepository {
 	return &RemoteSessionRepositoryImpl{
-		db: db,
+		db:     db,
+		readDB: db,
+	}
+}
+
+// NewRemoteSessionRepositoryWithRouter crea el repositorio enviando los informes de sesiones por administrador a la réplica de lectura del router
+func NewRemoteSessionRepositoryWithRouter(router *database.DBRouter) interfaces.IRemoteSessionRepository {
+	return &RemoteSessionRepositoryImpl{
+		db:     router.Writer(),
+		readDB: router.Reader(),
 	}
 }
 
 // Save guarda una nueva sesión remota
-func (rsr *RemoteSessionRepositoryImpl) Save(session *remotesession.RemoteSession) error {
+func (rsr *RemoteSessionRepositoryImpl) Save(ctx context.Context, session *remotesession.RemoteSession) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		INSERT INTO remote_sessions (
 			session_id, admin_user_id, client_pc_id, start_time, end_time, 
-			status, session_video_id, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			status, session_video_id, reason, ticket_id, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := rsr.db.Exec(
+	_, err := rsr.db.ExecContext(
+		ctx,
 		query,
 		session.SessionID(),
 		session.AdminUserID(),
@@ -39,6 +56,8 @@ func (rsr *RemoteSessionRepositoryImpl) Save(session *remotesession.RemoteSessio
 		session.EndTime(),
 		string(session.Status()),
 		session.SessionVideoID(),
+		nullString(session.Justification().Reason),
+		nullString(session.Justification().TicketID),
 		session.CreatedAt(),
 		session.UpdatedAt(),
 	)
@@ -51,24 +70,27 @@ func (rsr *RemoteSessionRepositoryImpl) Save(session *remotesession.RemoteSessio
 }
 
 // FindById busca una sesión por su ID
-func (rsr *RemoteSessionRepositoryImpl) FindById(id string) (*remotesession.RemoteSession, error) {
+func (rsr *RemoteSessionRepositoryImpl) FindById(ctx context.Context, id string) (*remotesession.RemoteSession, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT session_id, admin_user_id, client_pc_id, start_time, end_time,
-			   status, session_video_id, created_at, updated_at
+			   status, session_video_id, reason, ticket_id, created_at, updated_at
 		FROM remote_sessions
 		WHERE session_id = ?
 	`
 
-	row := rsr.db.QueryRow(query, id)
+	row := rsr.db.QueryRowContext(ctx, query, id)
 
 	var sessionID, adminUserID, clientPCID, status string
-	var sessionVideoID sql.NullString
+	var sessionVideoID, reason, ticketID sql.NullString
 	var startTime, endTime sql.NullTime
 	var createdAt, updatedAt time.Time
 
 	err := row.Scan(
 		&sessionID, &adminUserID, &clientPCID,
-		&startTime, &endTime, &status, &sessionVideoID,
+		&startTime, &endTime, &status, &sessionVideoID, &reason, &ticketID,
 		&createdAt, &updatedAt,
 	)
 
@@ -82,7 +104,7 @@ func (rsr *RemoteSessionRepositoryImpl) FindById(id string) (*remotesession.Remo
 	// Reconstruir la entidad desde la base de datos
 	session := rsr.reconstructSession(
 		sessionID, adminUserID, clientPCID,
-		startTime, endTime, status, sessionVideoID,
+		startTime, endTime, status, sessionVideoID, reason, ticketID,
 		createdAt, updatedAt,
 	)
 
@@ -90,14 +112,17 @@ func (rsr *RemoteSessionRepositoryImpl) FindById(id string) (*remotesession.Remo
 }
 
 // UpdateStatus actualiza el estado de una sesión
-func (rsr *RemoteSessionRepositoryImpl) UpdateStatus(id string, status remotesession.SessionStatus) error {
+func (rsr *RemoteSessionRepositoryImpl) UpdateStatus(ctx context.Context, id string, status remotesession.SessionStatus) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		UPDATE remote_sessions 
 		SET status = ?, updated_at = ?
 		WHERE session_id = ?
 	`
 
-	result, err := rsr.db.Exec(query, string(status), time.Now().UTC(), id)
+	result, err := rsr.db.ExecContext(ctx, query, string(status), time.Now().UTC(), id)
 	if err != nil {
 		return fmt.Errorf("failed to update session status: %w", err)
 	}
@@ -115,15 +140,19 @@ func (rsr *RemoteSessionRepositoryImpl) UpdateStatus(id string, status remoteses
 }
 
 // Update actualiza una sesión completa
-func (rsr *RemoteSessionRepositoryImpl) Update(session *remotesession.RemoteSession) error {
+func (rsr *RemoteSessionRepositoryImpl) Update(ctx context.Context, session *remotesession.RemoteSession) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		UPDATE remote_sessions 
 		SET admin_user_id = ?, client_pc_id = ?, start_time = ?, end_time = ?,
-			status = ?, session_video_id = ?, updated_at = ?
+			status = ?, session_video_id = ?, reason = ?, ticket_id = ?, updated_at = ?
 		WHERE session_id = ?
 	`
 
-	result, err := rsr.db.Exec(
+	result, err := rsr.db.ExecContext(
+		ctx,
 		query,
 		session.AdminUserID(),
 		session.ClientPCID(),
@@ -131,6 +160,8 @@ func (rsr *RemoteSessionRepositoryImpl) Update(session *remotesession.RemoteSess
 		session.EndTime(),
 		string(session.Status()),
 		session.SessionVideoID(),
+		nullString(session.Justification().Reason),
+		nullString(session.Justification().TicketID),
 		session.UpdatedAt(),
 		session.SessionID(),
 	)
@@ -152,85 +183,154 @@ func (rsr *RemoteSessionRepositoryImpl) Update(session *remotesession.RemoteSess
 }
 
 // FindByAdminUserID busca sesiones por ID de usuario administrador
-func (rsr *RemoteSessionRepositoryImpl) FindByAdminUserID(adminUserID string) ([]*remotesession.RemoteSession, error) {
+func (rsr *RemoteSessionRepositoryImpl) FindByAdminUserID(ctx context.Context, adminUserID string) ([]*remotesession.RemoteSession, error) {
 	query := `
 		SELECT session_id, admin_user_id, client_pc_id, start_time, end_time,
-			   status, session_video_id, created_at, updated_at
+			   status, session_video_id, reason, ticket_id, created_at, updated_at
 		FROM remote_sessions
 		WHERE admin_user_id = ?
 		ORDER BY created_at DESC
 	`
 
-	return rsr.findSessions(query, adminUserID)
+	return rsr.findSessionsOn(ctx, rsr.readDB, query, adminUserID)
 }
 
 // FindByClientPCID busca sesiones por ID de PC cliente
-func (rsr *RemoteSessionRepositoryImpl) FindByClientPCID(clientPCID string) ([]*remotesession.RemoteSession, error) {
+func (rsr *RemoteSessionRepositoryImpl) FindByClientPCID(ctx context.Context, clientPCID string) ([]*remotesession.RemoteSession, error) {
 	query := `
 		SELECT session_id, admin_user_id, client_pc_id, start_time, end_time,
-			   status, session_video_id, created_at, updated_at
+			   status, session_video_id, reason, ticket_id, created_at, updated_at
 		FROM remote_sessions
 		WHERE client_pc_id = ?
 		ORDER BY created_at DESC
 	`
 
-	return rsr.findSessions(query, clientPCID)
+	return rsr.findSessions(ctx, query, clientPCID)
 }
 
 // FindActiveSessions busca sesiones activas
-func (rsr *RemoteSessionRepositoryImpl) FindActiveSessions() ([]*remotesession.RemoteSession, error) {
+func (rsr *RemoteSessionRepositoryImpl) FindActiveSessions(ctx context.Context) ([]*remotesession.RemoteSession, error) {
 	query := `
 		SELECT session_id, admin_user_id, client_pc_id, start_time, end_time,
-			   status, session_video_id, created_at, updated_at
+			   status, session_video_id, reason, ticket_id, created_at, updated_at
 		FROM remote_sessions
 		WHERE status = ?
 		ORDER BY created_at DESC
 	`
 
-	return rsr.findSessions(query, string(remotesession.StatusActive))
+	return rsr.findSessions(ctx, query, string(remotesession.StatusActive))
 }
 
 // FindPendingSessions busca sesiones pendientes de aprobación
-func (rsr *RemoteSessionRepositoryImpl) FindPendingSessions() ([]*remotesession.RemoteSession, error) {
+func (rsr *RemoteSessionRepositoryImpl) FindPendingSessions(ctx context.Context) ([]*remotesession.RemoteSession, error) {
 	query := `
 		SELECT session_id, admin_user_id, client_pc_id, start_time, end_time,
-			   status, session_video_id, created_at, updated_at
+			   status, session_video_id, reason, ticket_id, created_at, updated_at
 		FROM remote_sessions
 		WHERE status = ?
 		ORDER BY created_at DESC
 	`
 
-	return rsr.findSessions(query, string(remotesession.StatusPendingApproval))
+	return rsr.findSessions(ctx, query, string(remotesession.StatusPendingApproval))
 }
 
 // FindByStatus busca sesiones por estado
-func (rsr *RemoteSessionRepositoryImpl) FindByStatus(status remotesession.SessionStatus) ([]*remotesession.RemoteSession, error) {
+func (rsr *RemoteSessionRepositoryImpl) FindByStatus(ctx context.Context, status remotesession.SessionStatus) ([]*remotesession.RemoteSession, error) {
 	query := `
 		SELECT session_id, admin_user_id, client_pc_id, start_time, end_time,
-			   status, session_video_id, created_at, updated_at
+			   status, session_video_id, reason, ticket_id, created_at, updated_at
 		FROM remote_sessions
 		WHERE status = ?
 		ORDER BY created_at DESC
 	`
 
-	return rsr.findSessions(query, string(status))
+	return rsr.findSessions(ctx, query, string(status))
 }
 
 // FindSessionsByDateRange busca sesiones en un rango de fechas
-func (rsr *RemoteSessionRepositoryImpl) FindSessionsByDateRange(adminUserID string, startDate, endDate string) ([]*remotesession.RemoteSession, error) {
+func (rsr *RemoteSessionRepositoryImpl) FindSessionsByDateRange(ctx context.Context, adminUserID string, startDate, endDate string) ([]*remotesession.RemoteSession, error) {
 	query := `
 		SELECT session_id, admin_user_id, client_pc_id, start_time, end_time,
-			   status, session_video_id, created_at, updated_at
+			   status, session_video_id, reason, ticket_id, created_at, updated_at
 		FROM remote_sessions
 		WHERE admin_user_id = ? AND created_at BETWEEN ? AND ?
 		ORDER BY created_at DESC
 	`
 
-	return rsr.findSessions(query, adminUserID, startDate, endDate)
+	return rsr.findSessionsOn(ctx, rsr.readDB, query, adminUserID, startDate, endDate)
+}
+
+// FindAccessReport busca una página de las sesiones de un administrador sobre un PC dentro de un rango de fechas
+func (rsr *RemoteSessionRepositoryImpl) FindAccessReport(ctx context.Context, adminUserID, clientPCID string, from, to time.Time, limit, offset int) ([]*remotesession.RemoteSession, error) {
+	query, args := buildAccessReportQuery(adminUserID, clientPCID, from, to, limit, offset)
+	return rsr.findSessionsOn(ctx, rsr.readDB, query, args...)
+}
+
+// CountAccessReport cuenta las sesiones del informe de accesos con los mismos filtros que FindAccessReport
+func (rsr *RemoteSessionRepositoryImpl) CountAccessReport(ctx context.Context, adminUserID, clientPCID string, from, to time.Time) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query, args := buildAccessReportCountQuery(adminUserID, clientPCID, from, to)
+
+	var count int
+	if err := rsr.readDB.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count access report sessions: %w", err)
+	}
+	return count, nil
+}
+
+// buildAccessReportQuery arma la consulta de una página del informe de accesos con los filtros informados
+func buildAccessReportQuery(adminUserID, clientPCID string, from, to time.Time, limit, offset int) (string, []interface{}) {
+	where, args := buildAccessReportFilter(adminUserID, clientPCID, from, to)
+	query := `
+		SELECT session_id, admin_user_id, client_pc_id, start_time, end_time,
+			   status, session_video_id, reason, ticket_id, created_at, updated_at
+		FROM remote_sessions
+		WHERE ` + where + `
+		ORDER BY COALESCE(start_time, created_at) DESC, session_id
+		LIMIT ? OFFSET ?
+	`
+	return query, append(args, limit, offset)
+}
+
+// buildAccessReportCountQuery arma el COUNT(*) del informe de accesos para el total de la paginación
+func buildAccessReportCountQuery(adminUserID, clientPCID string, from, to time.Time) (string, []interface{}) {
+	where, args := buildAccessReportFilter(adminUserID, clientPCID, from, to)
+	return `SELECT COUNT(*) FROM remote_sessions WHERE ` + where, args
+}
+
+// buildAccessReportFilter arma el WHERE del informe de accesos con los filtros informados. Las sesiones que nunca
+// empezaron (rechazadas, expiradas) se ubican en el tiempo por su created_at.
+func buildAccessReportFilter(adminUserID, clientPCID string, from, to time.Time) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+
+	if adminUserID != "" {
+		conditions = append(conditions, "admin_user_id = ?")
+		args = append(args, adminUserID)
+	}
+	if clientPCID != "" {
+		conditions = append(conditions, "client_pc_id = ?")
+		args = append(args, clientPCID)
+	}
+	if !from.IsZero() {
+		conditions = append(conditions, "COALESCE(start_time, created_at) >= ?")
+		args = append(args, from)
+	}
+	if !to.IsZero() {
+		conditions = append(conditions, "COALESCE(start_time, created_at) <= ?")
+		args = append(args, to)
+	}
+
+	return strings.Join(conditions, " AND "), args
 }
 
 // CountSessionsByUser cuenta sesiones por usuario
-func (rsr *RemoteSessionRepositoryImpl) CountSessionsByUser(adminUserID string) (int64, error) {
+func (rsr *RemoteSessionRepositoryImpl) CountSessionsByUser(ctx context.Context, adminUserID string) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT COUNT(*)
 		FROM remote_sessions
@@ -238,7 +338,7 @@ func (rsr *RemoteSessionRepositoryImpl) CountSessionsByUser(adminUserID string)
 	`
 
 	var count int64
-	err := rsr.db.QueryRow(query, adminUserID).Scan(&count)
+	err := rsr.readDB.QueryRowContext(ctx, query, adminUserID).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count sessions: %w", err)
 	}
@@ -246,13 +346,18 @@ func (rsr *RemoteSessionRepositoryImpl) CountSessionsByUser(adminUserID string)
 	return count, nil
 }
 
-// Delete elimina una sesión (soft delete)
-func (rsr *RemoteSessionRepositoryImpl) Delete(id string) error {
+// Delete elimina una sesión terminada. El estado se vuelve a comprobar en el propio DELETE: una sesión que pasó a
+// estar en curso desde que se leyó no se borra y se retorna remotesession.ErrSessionInProgress.
+func (rsr *RemoteSessionRepositoryImpl) Delete(ctx context.Context, id string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	// Implementar soft delete marcando como eliminado
 	// Por ahora implementamos delete físico
-	query := `DELETE FROM remote_sessions WHERE session_id = ?`
+	query := `DELETE FROM remote_sessions WHERE session_id = ? AND status NOT IN (?, ?, ?)`
 
-	result, err := rsr.db.Exec(query, id)
+	result, err := rsr.db.ExecContext(ctx, query, id,
+		remotesession.StatusActive, remotesession.StatusPendingApproval, remotesession.StatusQueued)
 	if err != nil {
 		return fmt.Errorf("failed to delete session: %w", err)
 	}
@@ -263,6 +368,11 @@ func (rsr *RemoteSessionRepositoryImpl) Delete(id string) error {
 	}
 
 	if rowsAffected == 0 {
+		var status string
+		err := rsr.db.QueryRowContext(ctx, `SELECT status FROM remote_sessions WHERE session_id = ?`, id).Scan(&status)
+		if err == nil {
+			return fmt.Errorf("%w: session %s is %s", remotesession.ErrSessionInProgress, id, status)
+		}
 		return fmt.Errorf("session with ID %s not found", id)
 	}
 
@@ -271,9 +381,17 @@ func (rsr *RemoteSessionRepositoryImpl) Delete(id string) error {
 
 // Métodos auxiliares privados
 
-// findSessions ejecuta una query y retorna las sesiones encontradas
-func (rsr *RemoteSessionRepositoryImpl) findSessions(query string, args ...interface{}) ([]*remotesession.RemoteSession, error) {
-	rows, err := rsr.db.Query(query, args...)
+// findSessions ejecuta una query en el primario y retorna las sesiones encontradas
+func (rsr *RemoteSessionRepositoryImpl) findSessions(ctx context.Context, query string, args ...interface{}) ([]*remotesession.RemoteSession, error) {
+	return rsr.findSessionsOn(ctx, rsr.db, query, args...)
+}
+
+// findSessionsOn ejecuta una query en la conexión indicada y retorna las sesiones encontradas
+func (rsr *RemoteSessionRepositoryImpl) findSessionsOn(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]*remotesession.RemoteSession, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -283,13 +401,13 @@ func (rsr *RemoteSessionRepositoryImpl) findSessions(query string, args ...inter
 
 	for rows.Next() {
 		var sessionID, adminUserID, clientPCID, status string
-		var sessionVideoID sql.NullString
+		var sessionVideoID, reason, ticketID sql.NullString
 		var startTime, endTime sql.NullTime
 		var createdAt, updatedAt time.Time
 
 		err := rows.Scan(
 			&sessionID, &adminUserID, &clientPCID,
-			&startTime, &endTime, &status, &sessionVideoID,
+			&startTime, &endTime, &status, &sessionVideoID, &reason, &ticketID,
 			&createdAt, &updatedAt,
 		)
 
@@ -299,7 +417,7 @@ func (rsr *RemoteSessionRepositoryImpl) findSessions(query string, args ...inter
 
 		session := rsr.reconstructSession(
 			sessionID, adminUserID, clientPCID,
-			startTime, endTime, status, sessionVideoID,
+			startTime, endTime, status, sessionVideoID, reason, ticketID,
 			createdAt, updatedAt,
 		)
 
@@ -318,7 +436,7 @@ func (rsr *RemoteSessionRepositoryImpl) reconstructSession(
 	sessionID, adminUserID, clientPCID string,
 	startTime, endTime sql.NullTime,
 	status string,
-	sessionVideoID sql.NullString,
+	sessionVideoID, reason, ticketID sql.NullString,
 	createdAt, updatedAt time.Time,
 ) *remotesession.RemoteSession {
 	// Convertir sql.NullTime a *time.Time
@@ -337,7 +455,7 @@ func (rsr *RemoteSessionRepositoryImpl) reconstructSession(
 	}
 	
 	// Usar el factory method para reconstruir la entidad
-	return remotesession.NewRemoteSessionFromDB(
+	session := remotesession.NewRemoteSessionFromDB(
 		sessionID,
 		adminUserID,
 		clientPCID,
@@ -348,4 +466,6 @@ func (rsr *RemoteSessionRepositoryImpl) reconstructSession(
 		createdAt,
 		updatedAt,
 	)
+	session.SetJustification(remotesession.Justification{Reason: reason.String, TicketID: ticketID.String})
+	return session
 } 
\ No newline at end of file
@@ -0,0 +1,144 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+// unreachableDSN apunta a un puerto sin servidor: si la consulta llegara a conectarse fallaría con un error de red
+const unreachableDSN = "user:password@tcp(127.0.0.1:1)/escritorio_remoto?timeout=30s"
+
+func newUnreachableDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("mysql", unreachableDSN)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestRemoteSessionRepository_CancelledContextReturnsPromptly(t *testing.T) {
+	// Arrange
+	repo := NewRemoteSessionRepository(newUnreachableDB(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	queries := map[string]func() error{
+		"FindById": func() error {
+			_, err := repo.FindById(ctx, "session-id")
+			return err
+		},
+		"FindByClientPCID": func() error {
+			_, err := repo.FindByClientPCID(ctx, "pc-id")
+			return err
+		},
+		"UpdateStatus": func() error {
+			return repo.UpdateStatus(ctx, "session-id", remotesession.StatusActive)
+		},
+		"FindAccessReport": func() error {
+			_, err := repo.FindAccessReport(ctx, "admin-id", "pc-id", time.Time{}, time.Time{}, 50, 0)
+			return err
+		},
+		"CountAccessReport": func() error {
+			_, err := repo.CountAccessReport(ctx, "admin-id", "pc-id", time.Time{}, time.Time{})
+			return err
+		},
+		"CountSessionsByUser": func() error {
+			_, err := repo.CountSessionsByUser(ctx, "admin-id")
+			return err
+		},
+	}
+
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			start := time.Now()
+			err := query()
+
+			// Assert
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.Less(t, time.Since(start), time.Second)
+		})
+	}
+}
+
+func TestBuildAccessReportQuery_AppliesOnlyGivenFilters(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		adminUserID        string
+		clientPCID         string
+		from, to           time.Time
+		expectedConditions []string
+		expectedArgs       []interface{}
+	}{
+		{name: "no filters"},
+		{
+			name:               "admin only",
+			adminUserID:        "admin-id",
+			expectedConditions: []string{"admin_user_id = ?"},
+			expectedArgs:       []interface{}{"admin-id"},
+		},
+		{
+			name:               "pc in range",
+			clientPCID:         "pc-id",
+			from:               from,
+			to:                 to,
+			expectedConditions: []string{"client_pc_id = ?", "COALESCE(start_time, created_at) >= ?", "COALESCE(start_time, created_at) <= ?"},
+			expectedArgs:       []interface{}{"pc-id", from, to},
+		},
+		{
+			name:               "admin and pc since date",
+			adminUserID:        "admin-id",
+			clientPCID:         "pc-id",
+			from:               from,
+			expectedConditions: []string{"admin_user_id = ?", "client_pc_id = ?", "COALESCE(start_time, created_at) >= ?"},
+			expectedArgs:       []interface{}{"admin-id", "pc-id", from},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			query, args := buildAccessReportQuery(tt.adminUserID, tt.clientPCID, tt.from, tt.to, 20, 40)
+			countQuery, countArgs := buildAccessReportCountQuery(tt.adminUserID, tt.clientPCID, tt.from, tt.to)
+
+			// Assert - un placeholder por argumento, solo las condiciones pedidas y la página al final
+			assert.Equal(t, append(tt.expectedArgs, 20, 40), args)
+			assert.Equal(t, len(args), strings.Count(query, "?"))
+			assert.Equal(t, tt.expectedArgs, countArgs)
+			assert.Equal(t, len(countArgs), strings.Count(countQuery, "?"))
+			for _, condition := range tt.expectedConditions {
+				assert.Contains(t, query, condition)
+				assert.Contains(t, countQuery, condition)
+			}
+			assert.Contains(t, query, "ORDER BY COALESCE(start_time, created_at) DESC")
+			assert.Contains(t, query, "LIMIT ? OFFSET ?")
+			assert.Contains(t, countQuery, "SELECT COUNT(*)")
+		})
+	}
+}
+
+func TestSessionVideoRepository_CancelledContextReturnsPromptly(t *testing.T) {
+	// Arrange
+	repo := NewSessionVideoRepository(newUnreachableDB(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	start := time.Now()
+	_, err := repo.FindBySessionID(ctx, "session-id")
+
+	// Assert
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+}
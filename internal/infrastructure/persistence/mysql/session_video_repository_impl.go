@@ -4,25 +4,40 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+	"github.com/unikyri/escritorio-remoto-backend/internal/infrastructure/database"
 )
 
 // sessionVideoRepository implementa ISessionVideoRepository
 type sessionVideoRepository struct {
-	db *sql.DB
+	db     *sql.DB // primario: escrituras y lecturas que deben ver el último estado
+	readDB *sql.DB // réplica de lectura para los listados de grabaciones (el primario si no hay réplica)
 }
 
 // NewSessionVideoRepository crea una nueva instancia del repositorio
 func NewSessionVideoRepository(db *sql.DB) interfaces.ISessionVideoRepository {
 	return &sessionVideoRepository{
-		db: db,
+		db:     db,
+		readDB: db,
+	}
+}
+
+// NewSessionVideoRepositoryWithRouter crea el repositorio enviando los listados de grabaciones a la réplica de lectura del router
+func NewSessionVideoRepositoryWithRouter(router *database.DBRouter) interfaces.ISessionVideoRepository {
+	return &sessionVideoRepository{
+		db:     router.Writer(),
+		readDB: router.Reader(),
 	}
 }
 
 // Save guarda un nuevo video de sesión
 func (r *sessionVideoRepository) Save(ctx context.Context, video *sessionvideo.SessionVideo) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		INSERT INTO session_videos (
 			video_id, file_path, duration_seconds, recorded_at, 
@@ -50,6 +65,9 @@ func (r *sessionVideoRepository) Save(ctx context.Context, video *sessionvideo.S
 
 // FindByID busca un video por su ID
 func (r *sessionVideoRepository) FindByID(ctx context.Context, videoID string) (*sessionvideo.SessionVideo, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT video_id, file_path, duration_seconds, recorded_at, 
 			   associated_session_id, file_size_mb, created_at, updated_at
@@ -86,6 +104,9 @@ func (r *sessionVideoRepository) FindByID(ctx context.Context, videoID string) (
 
 // FindBySessionID busca videos asociados a una sesión específica
 func (r *sessionVideoRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*sessionvideo.SessionVideo, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT video_id, file_path, duration_seconds, recorded_at, 
 			   associated_session_id, file_size_mb, created_at, updated_at
@@ -132,6 +153,9 @@ func (r *sessionVideoRepository) FindBySessionID(ctx context.Context, sessionID
 
 // Update actualiza un video existente
 func (r *sessionVideoRepository) Update(ctx context.Context, video *sessionvideo.SessionVideo) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		UPDATE session_videos 
 		SET file_path = ?, duration_seconds = ?, file_size_mb = ?, updated_at = ?
@@ -155,6 +179,9 @@ func (r *sessionVideoRepository) Update(ctx context.Context, video *sessionvideo
 
 // Delete elimina un video por su ID
 func (r *sessionVideoRepository) Delete(ctx context.Context, videoID string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `DELETE FROM session_videos WHERE video_id = ?`
 
 	_, err := r.db.ExecContext(ctx, query, videoID)
@@ -167,6 +194,9 @@ func (r *sessionVideoRepository) Delete(ctx context.Context, videoID string) err
 
 // FindAll obtiene todos los videos con paginación
 func (r *sessionVideoRepository) FindAll(ctx context.Context, limit, offset int) ([]*sessionvideo.SessionVideo, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT video_id, file_path, duration_seconds, recorded_at, 
 			   associated_session_id, file_size_mb, created_at, updated_at
@@ -175,7 +205,7 @@ func (r *sessionVideoRepository) FindAll(ctx context.Context, limit, offset int)
 		LIMIT ? OFFSET ?
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := r.readDB.QueryContext(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo videos: %w", err)
 	}
@@ -213,10 +243,13 @@ func (r *sessionVideoRepository) FindAll(ctx context.Context, limit, offset int)
 
 // Count obtiene el total de videos
 func (r *sessionVideoRepository) Count(ctx context.Context) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `SELECT COUNT(*) FROM session_videos`
 
 	var count int64
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.readDB.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("error contando videos: %w", err)
 	}
@@ -224,8 +257,32 @@ func (r *sessionVideoRepository) Count(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
+// SumFileSizeByClientPCID suma en una sola consulta el tamaño de las grabaciones de las sesiones de un PC cliente
+func (r *sessionVideoRepository) SumFileSizeByClientPCID(ctx context.Context, clientPCID string) (float64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT COALESCE(SUM(sv.file_size_mb), 0)
+		FROM session_videos sv
+		INNER JOIN remote_sessions rs ON rs.session_id = sv.associated_session_id
+		WHERE rs.client_pc_id = ?
+	`
+
+	var totalMB float64
+	err := r.db.QueryRowContext(ctx, query, clientPCID).Scan(&totalMB)
+	if err != nil {
+		return 0, fmt.Errorf("error sumando videos del cliente: %w", err)
+	}
+
+	return totalMB, nil
+}
+
 // FindByDateRange busca videos en un rango de fechas
 func (r *sessionVideoRepository) FindByDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*sessionvideo.SessionVideo, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT video_id, file_path, duration_seconds, recorded_at, 
 			   associated_session_id, file_size_mb, created_at, updated_at
@@ -235,7 +292,7 @@ func (r *sessionVideoRepository) FindByDateRange(ctx context.Context, startDate,
 		LIMIT ? OFFSET ?
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, startDate, endDate, limit, offset)
+	rows, err := r.readDB.QueryContext(ctx, query, startDate, endDate, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("error buscando videos por rango de fechas: %w", err)
 	}
@@ -270,3 +327,104 @@ func (r *sessionVideoRepository) FindByDateRange(ctx context.Context, startDate,
 
 	return videos, nil
 }
+
+// frameIndexBatchSize filas por sentencia INSERT; 4 parámetros por fila quedan lejos del límite de 65535 placeholders
+const frameIndexBatchSize = 500
+
+// SaveFrameIndex guarda el índice de frames con INSERT multi-fila dentro de una transacción: o se guarda
+// el índice completo o ninguna fila. Repetirlo sobre la misma grabación sobrescribe las entradas existentes.
+func (r *sessionVideoRepository) SaveFrameIndex(ctx context.Context, videoID string, entries []sessionvideo.FrameIndexEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción del índice de frames: %w", err)
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(entries); start += frameIndexBatchSize {
+		end := min(start+frameIndexBatchSize, len(entries))
+		batch := entries[start:end]
+
+		placeholders := strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?),", len(batch)), ",")
+		query := `
+			INSERT INTO session_video_frames (video_id, frame_index, frame_offset, frame_length)
+			VALUES ` + placeholders + `
+			ON DUPLICATE KEY UPDATE frame_offset = VALUES(frame_offset), frame_length = VALUES(frame_length)
+		`
+
+		args := make([]interface{}, 0, len(batch)*4)
+		for _, entry := range batch {
+			args = append(args, videoID, entry.FrameIndex, entry.Offset, entry.Length)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("error guardando índice de frames (%d-%d): %w", start, end-1, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando índice de frames: %w", err)
+	}
+
+	return nil
+}
+
+// FindOrphaned busca grabaciones cuya sesión ya no existe (borrada sin cascada o datos desincronizados). Consulta
+// el primario: con una réplica retrasada, una grabación recién guardada podría verse antes que su sesión.
+func (r *sessionVideoRepository) FindOrphaned(ctx context.Context, limit int) ([]*sessionvideo.SessionVideo, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT sv.video_id, sv.file_path, sv.duration_seconds, sv.recorded_at,
+			   sv.associated_session_id, sv.file_size_mb, sv.created_at, sv.updated_at
+		FROM session_videos sv
+		LEFT JOIN remote_sessions rs ON rs.session_id = sv.associated_session_id
+		WHERE rs.session_id IS NULL
+		ORDER BY sv.recorded_at ASC, sv.video_id ASC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error buscando videos huérfanos: %w", err)
+	}
+	defer rows.Close()
+
+	var videos []*sessionvideo.SessionVideo
+
+	for rows.Next() {
+		var id, filePath, sessionID string
+		var duration int
+		var recordedAt, createdAt, updatedAt sql.NullTime
+		var fileSizeMB float64
+
+		err := rows.Scan(&id, &filePath, &duration, &recordedAt, &sessionID, &fileSizeMB, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando video: %w", err)
+		}
+
+		videos = append(videos, sessionvideo.NewSessionVideoFromDB(
+			id,
+			filePath,
+			duration,
+			recordedAt.Time,
+			sessionID,
+			fileSizeMB,
+			createdAt.Time,
+			updatedAt.Time,
+		))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando videos huérfanos: %w", err)
+	}
+
+	return videos, nil
+}
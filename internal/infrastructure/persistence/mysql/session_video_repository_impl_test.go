@@ -0,0 +1,135 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+	"github.com/unikyri/escritorio-remoto-backend/internal/infrastructure/database"
+)
+
+// testAdminUserID administrador que crea init.sql
+const testAdminUserID = "admin-000-000-000-000000000001"
+
+// newTestDB conecta con la base de datos de desarrollo; sin MySQL disponible el test se omite
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := database.NewConnection(database.Config{
+		Host:               "localhost",
+		Port:               "3306",
+		Database:           "escritorio_remoto_db",
+		Username:           "app_user",
+		Password:           "app_password",
+		MaxConnections:     5,
+		MaxIdleConnections: 2,
+	})
+	if err != nil {
+		t.Skipf("MySQL de pruebas no disponible: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// newTestRecording crea un PC, una sesión y su grabación; se borran (con su índice en cascada) al terminar el test
+func newTestRecording(t *testing.T, db *sql.DB, repo *sessionVideoRepository) *sessionvideo.SessionVideo {
+	t.Helper()
+	ctx := context.Background()
+	pcID, sessionID := uuid.New().String(), uuid.New().String()
+
+	_, err := db.ExecContext(ctx, `INSERT INTO client_pcs (pc_id, identifier, ip, owner_user_id) VALUES (?, ?, ?, ?)`,
+		pcID, "frame-index-"+pcID[:8], "127.0.0.1", testAdminUserID)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO remote_sessions (session_id, admin_user_id, client_pc_id, status) VALUES (?, ?, ?, 'ENDED_BY_ADMIN')`,
+		sessionID, testAdminUserID, pcID)
+	require.NoError(t, err)
+
+	video := sessionvideo.NewSessionVideo("/tmp/frames", 10, sessionID, 1)
+	require.NoError(t, repo.Save(ctx, video))
+
+	t.Cleanup(func() {
+		db.Exec(`DELETE FROM session_videos WHERE video_id = ?`, video.VideoID())
+		db.Exec(`DELETE FROM remote_sessions WHERE session_id = ?`, sessionID)
+		db.Exec(`DELETE FROM client_pcs WHERE pc_id = ?`, pcID)
+	})
+	return video
+}
+
+func TestSessionVideoRepository_SaveFrameIndex_InsertsWholeIndexInBatches(t *testing.T) {
+	// Arrange - más filas que frameIndexBatchSize para cubrir varias sentencias en la misma transacción
+	db := newTestDB(t)
+	repo := NewSessionVideoRepository(db).(*sessionVideoRepository)
+	video := newTestRecording(t, db, repo)
+
+	entries := make([]sessionvideo.FrameIndexEntry, 0, 750)
+	var offset int64
+	for frameIndex := 0; frameIndex < 750; frameIndex++ {
+		length := int64(1000 + frameIndex)
+		entries = append(entries, sessionvideo.FrameIndexEntry{FrameIndex: frameIndex, Offset: offset, Length: length})
+		offset += length
+	}
+
+	// Act
+	err := repo.SaveFrameIndex(context.Background(), video.VideoID(), entries)
+
+	// Assert
+	require.NoError(t, err)
+
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM session_video_frames WHERE video_id = ?`, video.VideoID()).Scan(&count))
+	assert.Equal(t, len(entries), count)
+
+	var stored sessionvideo.FrameIndexEntry
+	require.NoError(t, db.QueryRow(`SELECT frame_index, frame_offset, frame_length FROM session_video_frames WHERE video_id = ? AND frame_index = ?`,
+		video.VideoID(), 612).Scan(&stored.FrameIndex, &stored.Offset, &stored.Length))
+	assert.Equal(t, entries[612], stored)
+}
+
+func TestSessionVideoRepository_SaveFrameIndex_CancelledContextReturnsPromptly(t *testing.T) {
+	// Arrange
+	repo := NewSessionVideoRepository(newUnreachableDB(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	err := repo.SaveFrameIndex(ctx, "video-id", []sessionvideo.FrameIndexEntry{{FrameIndex: 0, Offset: 0, Length: 10}})
+
+	// Assert
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSessionVideoRepository_FindOrphaned_DetectsRecordingOfMissingSession(t *testing.T) {
+	// Arrange - una grabación con sesión y otra cuya sesión no existe (insertada sin comprobar la clave foránea)
+	db := newTestDB(t)
+	repo := NewSessionVideoRepository(db).(*sessionVideoRepository)
+	ctx := context.Background()
+	linked := newTestRecording(t, db, repo)
+
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, `SET FOREIGN_KEY_CHECKS = 0`)
+	require.NoError(t, err)
+	orphanID := uuid.New().String()
+	_, err = conn.ExecContext(ctx, `INSERT INTO session_videos (video_id, file_path, duration_seconds, associated_session_id, file_size_mb) VALUES (?, ?, 10, ?, 1)`,
+		orphanID, "/tmp/orphan-frames", uuid.New().String())
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, `SET FOREIGN_KEY_CHECKS = 1`)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Exec(`DELETE FROM session_videos WHERE video_id = ?`, orphanID) })
+
+	// Act
+	orphans, err := repo.FindOrphaned(ctx, 1000)
+
+	// Assert
+	require.NoError(t, err)
+	ids := make([]string, 0, len(orphans))
+	for _, video := range orphans {
+		ids = append(ids, video.VideoID())
+	}
+	assert.Contains(t, ids, orphanID)
+	assert.NotContains(t, ids, linked.VideoID())
+}
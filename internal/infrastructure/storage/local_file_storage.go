@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+)
+
+// LocalFileSystemStorage implementa IFileStorage sobre un directorio raíz del disco local
+type LocalFileSystemStorage struct {
+	root string
+}
+
+var _ interfaces.IFileStorage = (*LocalFileSystemStorage)(nil)
+
+// NewLocalFileSystemStorage crea el almacenamiento local con raíz en root (p. ej. STORAGE_ROOT)
+func NewLocalFileSystemStorage(root string) *LocalFileSystemStorage {
+	return &LocalFileSystemStorage{root: filepath.Clean(root)}
+}
+
+// SaveFile escribe el contenido en un temporal junto al destino y lo renombra, así nunca queda visible un
+// archivo a medio escribir. Retorna la ruta final.
+func (s *LocalFileSystemStorage) SaveFile(ctx context.Context, destinationPath string, content []byte) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	finalPath := s.GetFilePath(destinationPath)
+	if err := os.MkdirAll(filepath.Dir(finalPath), 0755); err != nil {
+		return "", fmt.Errorf("error creando directorio de destino: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(finalPath), "."+filepath.Base(finalPath)+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("error creando temporal de destino: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("error escribiendo %s: %w", filepath.Base(finalPath), err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("error cerrando %s: %w", filepath.Base(finalPath), err)
+	}
+	if err := os.Rename(tmpPath, finalPath); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("error publicando %s: %w", filepath.Base(finalPath), err)
+	}
+	return finalPath, nil
+}
+
+// ReadFile lee un archivo del almacenamiento
+func (s *LocalFileSystemStorage) ReadFile(ctx context.Context, filePath string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(s.resolve(filePath))
+}
+
+// DeleteFile elimina un archivo del almacenamiento; que ya no exista no es un error
+func (s *LocalFileSystemStorage) DeleteFile(ctx context.Context, filePath string) error {
+	if err := os.Remove(s.resolve(filePath)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// FileExists verifica si un archivo existe
+func (s *LocalFileSystemStorage) FileExists(ctx context.Context, filePath string) bool {
+	_, err := os.Stat(s.resolve(filePath))
+	return err == nil
+}
+
+// GetFileSize obtiene el tamaño de un archivo en bytes
+func (s *LocalFileSystemStorage) GetFileSize(ctx context.Context, filePath string) (int64, error) {
+	info, err := os.Stat(s.resolve(filePath))
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// CreateDirectory crea un directorio si no existe
+func (s *LocalFileSystemStorage) CreateDirectory(ctx context.Context, dirPath string) error {
+	return os.MkdirAll(s.GetFilePath(dirPath), 0755)
+}
+
+// GetFilePath construye la ruta completa para un archivo relativo a la raíz
+func (s *LocalFileSystemStorage) GetFilePath(relativePath string) string {
+	return filepath.Join(s.root, relativePath)
+}
+
+// resolve acepta tanto rutas relativas a la raíz como las rutas completas que retornan SaveFile y MoveFile
+// (guardadas en BD como file_path)
+func (s *LocalFileSystemStorage) resolve(filePath string) string {
+	cleaned := filepath.Clean(filePath)
+	if filepath.IsAbs(cleaned) || cleaned == s.root || strings.HasPrefix(cleaned, s.root+string(filepath.Separator)) {
+		return cleaned
+	}
+	return s.GetFilePath(cleaned)
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalFileSystemStorage_SaveFileAcceptsRelativeAndReturnedPaths(t *testing.T) {
+	// Arrange
+	fileStorage := NewLocalFileSystemStorage(t.TempDir())
+	ctx := context.Background()
+	relativePath := filepath.Join("transfers", "session-1", "report.pdf")
+
+	// Act
+	savedPath, err := fileStorage.SaveFile(ctx, relativePath, []byte("report"))
+
+	// Assert - el resto de operaciones aceptan la ruta relativa y la ruta completa retornada
+	require.NoError(t, err)
+	assert.Equal(t, fileStorage.GetFilePath(relativePath), savedPath)
+	for _, path := range []string{relativePath, savedPath} {
+		assert.True(t, fileStorage.FileExists(ctx, path))
+		content, err := fileStorage.ReadFile(ctx, path)
+		require.NoError(t, err)
+		assert.Equal(t, "report", string(content))
+		size, err := fileStorage.GetFileSize(ctx, path)
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), size)
+	}
+	entries, err := os.ReadDir(filepath.Dir(savedPath))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no deben quedar temporales junto al archivo")
+
+	require.NoError(t, fileStorage.DeleteFile(ctx, savedPath))
+	assert.False(t, fileStorage.FileExists(ctx, relativePath))
+	assert.NoError(t, fileStorage.DeleteFile(ctx, savedPath), "borrar un archivo que ya no existe no es un error")
+}
+
+func TestLocalFileSystemStorage_RelativeRootResolvesReturnedPaths(t *testing.T) {
+	// Arrange - STORAGE_ROOT por defecto es relativo ("./storage")
+	t.Chdir(t.TempDir())
+	fileStorage := NewLocalFileSystemStorage("./storage")
+	ctx := context.Background()
+
+	// Act
+	savedPath, err := fileStorage.SaveFile(ctx, filepath.Join("videos", "v.mp4"), []byte("v"))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("storage", "videos", "v.mp4"), savedPath)
+	assert.True(t, fileStorage.FileExists(ctx, savedPath))
+	assert.NoFileExists(t, filepath.Join("storage", "storage", "videos", "v.mp4"))
+}
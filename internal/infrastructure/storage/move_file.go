@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// renameFile se sustituye en los tests para simular un origen en otro sistema de archivos
+var renameFile = os.Rename
+
+// MoveFile mueve un archivo local al almacenamiento y retorna la ruta final
+func (s *LocalFileSystemStorage) MoveFile(ctx context.Context, sourcePath, destinationPath string) (string, error) {
+	finalPath := s.GetFilePath(destinationPath)
+	if err := MoveLocalFile(ctx, sourcePath, finalPath); err != nil {
+		return "", err
+	}
+	return finalPath, nil
+}
+
+// MoveLocalFile mueve sourcePath a destinationPath creando su directorio. Dentro del mismo sistema de archivos es
+// un rename atómico; entre sistemas distintos copia a un temporal junto al destino, lo renombra y borra el origen,
+// así que el destino solo aparece completo.
+func MoveLocalFile(ctx context.Context, sourcePath, destinationPath string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(destinationPath), 0755); err != nil {
+		return fmt.Errorf("error creando directorio de destino: %w", err)
+	}
+
+	err := renameFile(sourcePath, destinationPath)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return fmt.Errorf("error moviendo %s: %w", filepath.Base(sourcePath), err)
+	}
+
+	if err := copyFileAtomically(ctx, sourcePath, destinationPath); err != nil {
+		return err
+	}
+	if err := os.Remove(sourcePath); err != nil {
+		return fmt.Errorf("archivo copiado pero no se pudo borrar el origen %s: %w", sourcePath, err)
+	}
+	return nil
+}
+
+// copyFileAtomically copia el contenido a un temporal del directorio de destino y lo publica con un rename
+func copyFileAtomically(ctx context.Context, sourcePath, destinationPath string) error {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return fmt.Errorf("error abriendo origen: %w", err)
+	}
+	defer source.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(destinationPath), "."+filepath.Base(destinationPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creando temporal de destino: %w", err)
+	}
+	tmpPath := tmp.Name()
+	published := false
+	defer func() {
+		if !published {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := io.Copy(tmp, source); err != nil {
+		return fmt.Errorf("error copiando %s: %w", filepath.Base(sourcePath), err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("error sincronizando copia: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error cerrando copia: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, destinationPath); err != nil {
+		return fmt.Errorf("error publicando %s: %w", filepath.Base(destinationPath), err)
+	}
+	published = true
+	return nil
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSourceFile crea el archivo ensamblado fuera del almacenamiento
+func writeSourceFile(t *testing.T, content string) string {
+	t.Helper()
+
+	sourcePath := filepath.Join(t.TempDir(), "assembled.mp4.tmp")
+	require.NoError(t, os.WriteFile(sourcePath, []byte(content), 0644))
+	return sourcePath
+}
+
+func TestLocalFileSystemStorage_MoveFile_RenamesWithinSameBackend(t *testing.T) {
+	// Arrange
+	fileStorage := NewLocalFileSystemStorage(t.TempDir())
+	sourcePath := writeSourceFile(t, "video-bytes")
+	renames := 0
+	renameFile = func(oldPath, newPath string) error {
+		renames++
+		return os.Rename(oldPath, newPath)
+	}
+	t.Cleanup(func() { renameFile = os.Rename })
+
+	// Act
+	finalPath, err := fileStorage.MoveFile(context.Background(), sourcePath, filepath.Join("videos", "processed", "s_v.mp4"))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, fileStorage.GetFilePath(filepath.Join("videos", "processed", "s_v.mp4")), finalPath)
+	content, err := os.ReadFile(finalPath)
+	require.NoError(t, err)
+	assert.Equal(t, "video-bytes", string(content))
+	assert.NoFileExists(t, sourcePath)
+	assert.Equal(t, 1, renames)
+}
+
+func TestMoveLocalFile_CrossBackendFallsBackToCopyAndDelete(t *testing.T) {
+	// Arrange - el rename falla como entre dos sistemas de archivos
+	sourcePath := writeSourceFile(t, "video-bytes")
+	destinationDir := filepath.Join(t.TempDir(), "videos")
+	destinationPath := filepath.Join(destinationDir, "s_v.mp4")
+	renameFile = func(oldPath, newPath string) error {
+		return &os.LinkError{Op: "rename", Old: oldPath, New: newPath, Err: syscall.EXDEV}
+	}
+	t.Cleanup(func() { renameFile = os.Rename })
+
+	// Act
+	err := MoveLocalFile(context.Background(), sourcePath, destinationPath)
+
+	// Assert - solo queda el destino completo, sin temporales ni origen
+	require.NoError(t, err)
+	content, err := os.ReadFile(destinationPath)
+	require.NoError(t, err)
+	assert.Equal(t, "video-bytes", string(content))
+	assert.NoFileExists(t, sourcePath)
+	entries, err := os.ReadDir(destinationDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestMoveLocalFile_OtherRenameErrorsAreNotRetriedAsCopy(t *testing.T) {
+	// Arrange
+	sourcePath := filepath.Join(t.TempDir(), "missing.tmp")
+	destinationPath := filepath.Join(t.TempDir(), "s_v.mp4")
+
+	// Act
+	err := MoveLocalFile(context.Background(), sourcePath, destinationPath)
+
+	// Assert
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.NoFileExists(t, destinationPath)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
 	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
 )
 
 // PCController maneja endpoints relacionados con PCs para administradores
@@ -30,7 +31,7 @@ func NewPCController(
 	}
 }
 
-// GetAllClientPCs handles GET /api/admin/pcs - retrieves all client PCs
+// GetAllClientPCs handles GET /api/v1/admin/pcs - retrieves all client PCs
 func (ctrl *PCController) GetAllClientPCs(c *gin.Context) {
 	// Verificar autenticación y autorización de administrador
 	if !ctrl.isAdminAuthenticated(c) {
@@ -38,24 +39,21 @@ func (ctrl *PCController) GetAllClientPCs(c *gin.Context) {
 	}
 
 	// Ejecutar Use Case
+	page := response.ParsePageRequest(c)
 	request := clientpc.GetAllPCsRequest{
-		Limit:  50, // Valor por defecto
-		Offset: 0,  // Valor por defecto
+		Limit:  page.Limit,
+		Offset: page.Offset,
 	}
 
-	response, err := ctrl.getAllPCsUseCase.Execute(c.Request.Context(), request)
+	result, err := ctrl.getAllPCsUseCase.Execute(c.Request.Context(), request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
-			Error:   "RETRIEVAL_FAILED",
-			Message: "Failed to retrieve client PCs",
-			Code:    http.StatusInternalServerError,
-		})
+		response.Error(c, http.StatusInternalServerError, "RETRIEVAL_FAILED", "Failed to retrieve client PCs")
 		return
 	}
 
 	// Convertir entidades a DTOs
-	pcDTOs := make([]dto.ClientPCDTO, len(response.PCs))
-	for i, pc := range response.PCs {
+	pcDTOs := make([]dto.ClientPCDTO, len(result.PCs))
+	for i, pc := range result.PCs {
 		pcDTOs[i] = dto.ClientPCDTO{
 			PCID:             pc.ID().Value(),
 			Identifier:       pc.Identifier(),
@@ -69,15 +67,13 @@ func (ctrl *PCController) GetAllClientPCs(c *gin.Context) {
 	}
 
 	// Responder con la lista de PCs
-	c.JSON(http.StatusOK, dto.ClientPCListResponse{
-		Success: true,
-		Data:    pcDTOs,
-		Count:   len(pcDTOs),
-		Message: "Client PCs retrieved successfully",
-	})
+	response.SuccessPage(c, http.StatusOK, dto.ClientPCListResponse{
+		PCs:   pcDTOs,
+		Count: len(pcDTOs),
+	}, page.Meta(int(result.Total)))
 }
 
-// GetOnlineClientPCs handles GET /api/admin/pcs/online - retrieves only online client PCs
+// GetOnlineClientPCs handles GET /api/v1/admin/pcs/online - retrieves only online client PCs
 func (ctrl *PCController) GetOnlineClientPCs(c *gin.Context) {
 	// Verificar autenticación y autorización de administrador
 	if !ctrl.isAdminAuthenticated(c) {
@@ -87,19 +83,15 @@ func (ctrl *PCController) GetOnlineClientPCs(c *gin.Context) {
 	// Ejecutar Use Case
 	request := clientpc.GetOnlinePCsRequest{}
 
-	response, err := ctrl.getOnlinePCsUseCase.Execute(c.Request.Context(), request)
+	result, err := ctrl.getOnlinePCsUseCase.Execute(c.Request.Context(), request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
-			Error:   "RETRIEVAL_FAILED",
-			Message: "Failed to retrieve online client PCs",
-			Code:    http.StatusInternalServerError,
-		})
+		response.Error(c, http.StatusInternalServerError, "RETRIEVAL_FAILED", "Failed to retrieve online client PCs")
 		return
 	}
 
 	// Convertir entidades a DTOs
-	pcDTOs := make([]dto.ClientPCDTO, len(response.PCs))
-	for i, pc := range response.PCs {
+	pcDTOs := make([]dto.ClientPCDTO, len(result.PCs))
+	for i, pc := range result.PCs {
 		pcDTOs[i] = dto.ClientPCDTO{
 			PCID:             pc.ID().Value(),
 			Identifier:       pc.Identifier(),
@@ -113,11 +105,9 @@ func (ctrl *PCController) GetOnlineClientPCs(c *gin.Context) {
 	}
 
 	// Responder con la lista de PCs online
-	c.JSON(http.StatusOK, dto.OnlineClientPCListResponse{
-		Success: true,
-		Data:    pcDTOs,
-		Count:   len(pcDTOs),
-		Message: "Online client PCs retrieved successfully",
+	response.Success(c, http.StatusOK, dto.ClientPCListResponse{
+		PCs:   pcDTOs,
+		Count: len(pcDTOs),
 	})
 }
 
@@ -125,21 +115,13 @@ func (ctrl *PCController) GetOnlineClientPCs(c *gin.Context) {
 func (ctrl *PCController) isAdminAuthenticated(c *gin.Context) bool {
 	userInfo, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponseDTO{
-			Error:   "AUTHENTICATION_REQUIRED",
-			Message: "Authentication required",
-			Code:    http.StatusUnauthorized,
-		})
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
 		return false
 	}
 
 	userClaims, ok := userInfo.(*userservice.JWTClaims)
-	if !ok || userClaims.Role != string(user.RoleAdministrator) {
-		c.JSON(http.StatusForbidden, dto.ErrorResponseDTO{
-			Error:   "ADMIN_PRIVILEGES_REQUIRED",
-			Message: "Admin privileges required",
-			Code:    http.StatusForbidden,
-		})
+	if !ok || !user.Role(userClaims.Role).IsAdmin() {
+		response.Error(c, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED", "Admin privileges required")
 		return false
 	}
 
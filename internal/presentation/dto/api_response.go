@@ -0,0 +1,23 @@
+package dto
+
+// APIResponse envelope único de todas las respuestas HTTP de la API (/api/v1)
+type APIResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Meta    *PageMeta   `json:"meta,omitempty"` // Solo en endpoints paginados
+	Error   *APIError   `json:"error,omitempty"`
+}
+
+// APIError detalle de un error: código estable para clientes y mensaje legible
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PageMeta metadatos de paginación que acompañan a data en los endpoints de listas
+type PageMeta struct {
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	Total   int  `json:"total"`
+	HasMore bool `json:"has_more"`
+}
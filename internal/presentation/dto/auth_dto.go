@@ -23,10 +23,3 @@ type UserInfoDTO struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
-
-// ErrorResponseDTO representa una respuesta de error
-type ErrorResponseDTO struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-	Code    int    `json:"code"`
-}
@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// CapabilityBinaryFrames capacidad negociada en CLIENT_AUTH_REQUEST para enviar frames y chunks como
+// mensajes WebSocket binarios en lugar de JSON con base64
+const CapabilityBinaryFrames = "binary_frames"
+
+// BinaryFrameVersion versión del formato de mensaje binario
+const BinaryFrameVersion byte = 1
+
+// binaryFrameFixedHeaderSize versión (1 byte) + tipo (1 byte) + longitud de la cabecera JSON (2 bytes big-endian)
+const binaryFrameFixedHeaderSize = 4
+
+// BinaryFrameType identifica el contenido de un mensaje binario
+type BinaryFrameType byte
+
+const (
+	BinaryFrameScreenFrame BinaryFrameType = 1 // cliente → servidor, cabecera ScreenFrame
+	BinaryFrameVideoFrame  BinaryFrameType = 2 // cliente → servidor, cabecera VideoFrameUpload
+	BinaryFrameVideoChunk  BinaryFrameType = 3 // cliente → servidor, cabecera VideoChunk
+	BinaryFrameFileChunk   BinaryFrameType = 4 // servidor → cliente, cabecera FileChunk
+	BinaryFrameAudioChunk  BinaryFrameType = 5 // cliente → servidor, cabecera AudioChunk
+)
+
+// ErrInvalidBinaryFrame indica un mensaje binario truncado o con versión desconocida
+var ErrInvalidBinaryFrame = errors.New("invalid binary frame")
+
+// BinaryFrame mensaje binario decodificado: la cabecera JSON lleva los metadatos del mensaje equivalente
+// en JSON (sin el campo de datos) y Payload los bytes en crudo
+type BinaryFrame struct {
+	Type    BinaryFrameType
+	Header  json.RawMessage
+	Payload []byte
+}
+
+// EncodeBinaryFrame construye un mensaje binario:
+// [versión:1][tipo:1][longitud cabecera:2][cabecera JSON][payload]
+func EncodeBinaryFrame(frameType BinaryFrameType, header interface{}, payload []byte) ([]byte, error) {
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding binary frame header: %w", err)
+	}
+	if len(headerJSON) > 0xFFFF {
+		return nil, fmt.Errorf("binary frame header too large: %d bytes", len(headerJSON))
+	}
+
+	frame := make([]byte, binaryFrameFixedHeaderSize, binaryFrameFixedHeaderSize+len(headerJSON)+len(payload))
+	frame[0] = BinaryFrameVersion
+	frame[1] = byte(frameType)
+	binary.BigEndian.PutUint16(frame[2:4], uint16(len(headerJSON)))
+	frame = append(frame, headerJSON...)
+	frame = append(frame, payload...)
+	return frame, nil
+}
+
+// DecodeBinaryFrame separa un mensaje binario en tipo, cabecera y payload.
+// El payload referencia el slice recibido, sin copiarlo.
+func DecodeBinaryFrame(data []byte) (*BinaryFrame, error) {
+	if len(data) < binaryFrameFixedHeaderSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidBinaryFrame, len(data))
+	}
+	if data[0] != BinaryFrameVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidBinaryFrame, data[0])
+	}
+
+	headerEnd := binaryFrameFixedHeaderSize + int(binary.BigEndian.Uint16(data[2:4]))
+	if len(data) < headerEnd {
+		return nil, fmt.Errorf("%w: header exceeds frame size", ErrInvalidBinaryFrame)
+	}
+
+	return &BinaryFrame{
+		Type:    BinaryFrameType(data[1]),
+		Header:  json.RawMessage(data[binaryFrameFixedHeaderSize:headerEnd]),
+		Payload: data[headerEnd:],
+	}, nil
+}
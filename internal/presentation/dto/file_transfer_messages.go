@@ -12,8 +12,14 @@ type FileTransferRequest struct {
 	FileSizeMB      float64 `json:"file_size_mb"`
 	TotalChunks     int     `json:"total_chunks"` // Total de chunks a enviar
 	DestinationPath string  `json:"destination_path"`
-	InitiatedBy     string  `json:"initiated_by"` // Para logs del servidor
-	Timestamp       int64   `json:"timestamp"`    // Unix timestamp
+	ConflictPolicy  string  `json:"conflict_policy"` // overwrite, rename o skip si destination_path ya existe
+	InitiatedBy     string  `json:"initiated_by"`    // Para logs del servidor
+	Timestamp       int64   `json:"timestamp"`       // Unix timestamp
+
+	// Cifrado opcional de chunks: el cliente responde READY con su client_public_key para activarlo
+	Encryption         string `json:"encryption,omitempty"`        // Algoritmo ofrecido
+	ServerPublicKey    string `json:"server_public_key,omitempty"` // Clave pública efímera X25519 en base64
+	EncryptionRequired bool   `json:"encryption_required,omitempty"`
 }
 
 // FileChunk mensaje con un chunk del archivo
@@ -27,7 +33,8 @@ type FileChunk struct {
 	IsLastChunk   bool   `json:"is_last_chunk"`
 	ChunkSize     int    `json:"chunk_size"`
 	ChunkChecksum string `json:"chunk_checksum,omitempty"`
-	Timestamp     int64  `json:"timestamp"` // Unix timestamp
+	Timestamp     int64  `json:"timestamp"`           // Unix timestamp
+	Encrypted     bool   `json:"encrypted,omitempty"` // ChunkData es base64(nonce || ciphertext AES-GCM)
 }
 
 // FileTransferAcknowledgement respuesta del cliente sobre el estado de la transferencia
@@ -42,6 +49,8 @@ type FileTransferAcknowledgement struct {
 	FileChecksum string `json:"file_checksum,omitempty"`
 	ChunkNumber  int    `json:"chunk_number,omitempty"` // Para confirmación de chunks
 	Timestamp    int64  `json:"timestamp"`              // Unix timestamp
+
+	ClientPublicKey string `json:"client_public_key,omitempty"` // En READY: acepta el cifrado de chunks ofrecido
 }
 
 // FileTransferProgress mensaje de progreso de transferencia
@@ -67,3 +76,22 @@ type FileTransferStatus struct {
 	Duration     string    `json:"duration,omitempty"`
 	Timestamp    time.Time `json:"timestamp"`
 }
+
+// StorageQuery consulta enviada al cliente antes de una transferencia para conocer su espacio libre
+type StorageQuery struct {
+	Type            string `json:"type"` // "storage_query"
+	TransferID      string `json:"transfer_id"`
+	SessionID       string `json:"session_id"`
+	DestinationPath string `json:"destination_path"`
+	RequiredBytes   int64  `json:"required_bytes"`
+	Timestamp       int64  `json:"timestamp"` // Unix timestamp
+}
+
+// StorageQueryResponse respuesta del cliente con el espacio disponible en la ruta de destino
+type StorageQueryResponse struct {
+	Type           string `json:"type"` // "storage_query_response"
+	TransferID     string `json:"transfer_id"`
+	AvailableBytes int64  `json:"available_bytes"`
+	ErrorMessage   string `json:"error_message,omitempty"` // El cliente no pudo determinar el espacio libre
+	Timestamp      int64  `json:"timestamp"`               // Unix timestamp
+}
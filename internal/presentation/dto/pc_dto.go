@@ -4,28 +4,78 @@ import "time"
 
 // ClientPCDTO represents a client PC for API responses
 type ClientPCDTO struct {
-	PCID             string     `json:"pcId"`
-	Identifier       string     `json:"identifier"`
-	ConnectionStatus string     `json:"connectionStatus"`
-	OwnerUsername    string     `json:"ownerUsername"`
-	IP               string     `json:"ip"`
-	RegisteredAt     time.Time  `json:"registeredAt"`
-	LastSeenAt       *time.Time `json:"lastSeenAt"`
-	UpdatedAt        time.Time  `json:"updatedAt"`
+	PCID              string     `json:"pcId"`
+	Identifier        string     `json:"identifier"`
+	ConnectionStatus  string     `json:"connectionStatus"`
+	OwnerUsername     string     `json:"ownerUsername"`
+	IP                string     `json:"ip"`
+	AutoAcceptControl bool       `json:"autoAcceptControl"`
+	OS                string     `json:"os,omitempty"`
+	Hostname          string     `json:"hostname,omitempty"`
+	AgentVersion      string     `json:"agentVersion,omitempty"`
+	RegisteredAt      time.Time  `json:"registeredAt"`
+	LastSeenAt        *time.Time `json:"lastSeenAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
 }
 
-// ClientPCListResponse represents the response for getting all client PCs
+// ClientPCListResponse represents the data of the client PC list endpoints (all and online)
 type ClientPCListResponse struct {
-	Success bool          `json:"success"`
-	Data    []ClientPCDTO `json:"data"`
-	Count   int           `json:"count"`
-	Message string        `json:"message,omitempty"`
-}
-
-// OnlineClientPCListResponse represents the response for getting online client PCs
-type OnlineClientPCListResponse struct {
-	Success bool          `json:"success"`
-	Data    []ClientPCDTO `json:"data"`
-	Count   int           `json:"count"`
-	Message string        `json:"message,omitempty"`
+	PCs   []ClientPCDTO `json:"pcs"`
+	Count int           `json:"count"`
+}
+
+// UpdateAutoAcceptRequest represents the request to change the auto-accept control policy of a PC
+type UpdateAutoAcceptRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// PinnedPCResponse represents the pin state of a PC for the current administrator
+type PinnedPCResponse struct {
+	PCID   string `json:"pcId"`
+	Pinned bool   `json:"pinned"`
+}
+
+// ConnectionSessionDTO represents a single connect/disconnect cycle of a client PC
+type ConnectionSessionDTO struct {
+	ConnectionID     string     `json:"connectionId"`
+	PCID             string     `json:"pcId"`
+	IPAddress        string     `json:"ipAddress"`
+	ConnectedAt      time.Time  `json:"connectedAt"`
+	DisconnectedAt   *time.Time `json:"disconnectedAt"`
+	DisconnectReason string     `json:"disconnectReason,omitempty"`
+	DurationSeconds  int64      `json:"durationSeconds"`
+}
+
+// ConnectionHistoryResponse represents the data of the connection history endpoint of a PC
+type ConnectionHistoryResponse struct {
+	Connections []ConnectionSessionDTO `json:"connections"`
+	Count       int                    `json:"count"`
+}
+
+// ClientHeartbeatRequest represents a heartbeat sent through the REST fallback by a client without WebSocket
+type ClientHeartbeatRequest struct {
+	PCID string `json:"pcId" binding:"required"`
+}
+
+// ClientRegistrationResponse represents the data of the REST client registration endpoint
+type ClientRegistrationResponse struct {
+	PC ClientPCDTO `json:"pc"`
+	// Cadencia recomendada de heartbeat REST; sin heartbeat durante HeartbeatTimeoutSeconds el PC pasa a OFFLINE
+	HeartbeatIntervalSeconds int `json:"heartbeatIntervalSeconds"`
+	HeartbeatTimeoutSeconds  int `json:"heartbeatTimeoutSeconds"`
+}
+
+// ClientHeartbeatResponse represents the data of the REST client heartbeat endpoint
+type ClientHeartbeatResponse struct {
+	Timestamp int64  `json:"timestamp"`
+	Status    string `json:"status"`
+}
+
+// RecordingUploadProgress represents the state of a REST recording upload; the client resumes from ReceivedBytes
+type RecordingUploadProgress struct {
+	VideoID       string `json:"videoId"`
+	SessionID     string `json:"sessionId"`
+	FileSize      int64  `json:"fileSize"`
+	ReceivedBytes int64  `json:"receivedBytes"`
+	IsComplete    bool   `json:"isComplete"`
 }
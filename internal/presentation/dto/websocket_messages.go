@@ -2,28 +2,114 @@ package dto
 
 // WebSocket Message Types
 const (
-	MessageTypeClientAuth         = "CLIENT_AUTH_REQUEST"
-	MessageTypeClientAuthResp     = "CLIENT_AUTH_RESPONSE"
-	MessageTypePCRegistration     = "PC_REGISTRATION_REQUEST"
-	MessageTypePCRegistrationResp = "PC_REGISTRATION_RESPONSE"
-	MessageTypeHeartbeat          = "HEARTBEAT"
-	MessageTypeHeartbeatResp      = "HEARTBEAT_RESPONSE"
+	MessageTypeClientAuth            = "CLIENT_AUTH_REQUEST"
+	MessageTypeClientAuthResp        = "CLIENT_AUTH_RESPONSE"
+	MessageTypePCRegistration        = "PC_REGISTRATION_REQUEST"
+	MessageTypePCRegistrationResp    = "PC_REGISTRATION_RESPONSE"
+	MessageTypeHeartbeat             = "HEARTBEAT"
+	MessageTypeHeartbeatResp         = "HEARTBEAT_RESPONSE"
+	MessageTypeClientShutdown        = "client_shutdown"
+	MessageTypeClientShutdownAck     = "client_shutdown_ack"
+	MessageTypeMalformedPayload      = "malformed_payload"
+	MessageTypeActivityStatus        = "activity_status"
+	MessageTypeClientActivity        = "client_activity"
+	MessageTypeSessionEndAck         = "session_end_ack"
+	MessageTypeProtocolIncompatible  = "protocol_incompatible"
+	MessageTypeQueryPendingTransfers = "query_pending_transfers"
+	MessageTypePendingTransfersList  = "pending_transfers_list"
 
 	// Remote Control Streaming Messages
 	MessageTypeScreenFrame  = "screen_frame"
 	MessageTypeInputCommand = "input_command"
+	MessageTypeFrameAck     = "frame_ack"
+	MessageTypeStreamConfig = "stream_config"
+	MessageTypePauseStream  = "pause_stream"
+	MessageTypeResumeStream = "resume_stream"
+
+	// Remote Control Audio Messages (opcionales, requieren CapabilityAudioStream)
+	MessageTypeAudioChunk  = "audio_chunk"
+	MessageTypeAudioStream = "audio_stream"
+	MessageTypeSetAudio    = "set_audio"
+	MessageTypeAudioStatus = "audio_status"
+
+	// Admin Notification Preferences
+	MessageTypeSubscribeNotifications   = "subscribe_notifications"
+	MessageTypeUnsubscribeNotifications = "unsubscribe_notifications"
+	MessageTypeNotificationPreferences  = "notification_preferences"
+
+	// Admin PC Notifications (agrupadas cuando el servidor tiene ventana de agrupación)
+	MessageTypePCListDelta = "pc_list_delta"
 )
 
+// CapabilityAudioStream capacidad negociada en CLIENT_AUTH_REQUEST para capturar y enviar el audio del PC
+// junto a los frames de pantalla
+const CapabilityAudioStream = "audio_stream"
+
+// CapabilitySessionEndAck capacidad negociada en CLIENT_AUTH_REQUEST por los clientes que responden
+// session_end_ack a control_session_ended; solo a ellos se les exige la confirmación
+const CapabilitySessionEndAck = "session_end_ack"
+
 // Base message structure
 type WebSocketMessage struct {
 	Type string      `json:"type"`
 	Data interface{} `json:"data"`
 }
 
+// MalformedPayload se envía al emisor cuando el campo data de su mensaje no tiene el formato esperado
+type MalformedPayload struct {
+	MessageType string `json:"message_type"`
+	Error       string `json:"error"`
+}
+
+// NotificationSubscription lo envía el administrador en subscribe_notifications (reactiva categorías y, si se indica,
+// cambia el alcance) y en unsubscribe_notifications (silencia categorías)
+type NotificationSubscription struct {
+	Categories []string `json:"categories,omitempty"`
+	Scope      string   `json:"scope,omitempty"` // "all" | "owned"
+}
+
+// NotificationPreferences preferencias vigentes del administrador, en respuesta a subscribe/unsubscribe_notifications
+type NotificationPreferences struct {
+	Scope           string   `json:"scope"`
+	MutedCategories []string `json:"muted_categories"`
+}
+
+// PCListDelta resumen de los cambios de PCs (conexiones, desconexiones y cambios de estado) ocurridos durante la
+// ventana de agrupación, en lugar de un mensaje por evento
+type PCListDelta struct {
+	Changes []PCListDeltaChange `json:"changes"`
+	// ListChanged la lista de PCs cambió y el panel debe refrescarla (equivale a pc_list_update)
+	ListChanged bool `json:"listChanged"`
+	// CoalescedEvents eventos individuales que resume el mensaje
+	CoalescedEvents int    `json:"coalescedEvents"`
+	Timestamp       int64  `json:"timestamp"`
+	Event           string `json:"event"`
+}
+
+// PCListDeltaChange estado final de un PC tras los eventos de la ventana
+type PCListDeltaChange struct {
+	PCID        string `json:"pcId"`
+	Identifier  string `json:"identifier"`
+	OwnerUserID string `json:"ownerUserId"`
+	IP          string `json:"ip,omitempty"`
+	Status      string `json:"status"`
+	// PreviousStatus estado antes del primer cambio de la ventana, si se conoce
+	PreviousStatus string `json:"previousStatus,omitempty"`
+	// Events eventos del PC en orden: "connection", "disconnection" y/o "status_change"
+	Events      []string `json:"events"`
+	CloseCode   int      `json:"closeCode,omitempty"`
+	CloseReason string   `json:"closeReason,omitempty"`
+	Timestamp   int64    `json:"timestamp"`
+}
+
 // Client Authentication Messages
 type ClientAuthRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	// Capabilities capacidades opcionales que soporta el cliente (p. ej. CapabilityBinaryFrames)
+	Capabilities []string `json:"capabilities,omitempty"`
+	// ProtocolVersion versión del protocolo del cliente (MAJOR.MINOR); los clientes antiguos no la envían
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
 }
 
 type ClientAuthResponse struct {
@@ -31,12 +117,35 @@ type ClientAuthResponse struct {
 	Token   string `json:"token,omitempty"`
 	UserID  string `json:"userId,omitempty"`
 	Error   string `json:"error,omitempty"`
+	// Capabilities capacidades solicitadas por el cliente que el servidor aceptó para esta conexión
+	Capabilities []string `json:"capabilities,omitempty"`
+	// ProtocolVersion versión del protocolo negociada para esta conexión
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
+	// HeartbeatIntervalSeconds cadencia de HEARTBEAT recomendada; sin mensajes durante
+	// HeartbeatTimeoutSeconds el servidor cierra la conexión
+	HeartbeatIntervalSeconds int `json:"heartbeatIntervalSeconds,omitempty"`
+	HeartbeatTimeoutSeconds  int `json:"heartbeatTimeoutSeconds,omitempty"`
+}
+
+// ProtocolIncompatible se envía en lugar de CLIENT_AUTH_RESPONSE correcto cuando la versión del cliente no está soportada
+type ProtocolIncompatible struct {
+	ClientVersion     string   `json:"client_version"`
+	ServerVersion     string   `json:"server_version"`
+	SupportedVersions []string `json:"supported_versions"`
+	Message           string   `json:"message"`
 }
 
 // PC Registration Messages
 type PCRegistrationRequest struct {
 	PCIdentifier string `json:"pcIdentifier"`
 	IP           string `json:"ip,omitempty"` // Optional, can be detected from connection
+	// Metadatos opcionales del agente; los clientes antiguos no los envían
+	OS           string `json:"os,omitempty"`
+	Hostname     string `json:"hostname,omitempty"`
+	AgentVersion string `json:"agentVersion,omitempty"`
+	// SupportedInputEvents event_type de input_command que el agente puede ejecutar (p. ej. "keyboard" en un
+	// cliente táctil); sin lista se le envían todos
+	SupportedInputEvents []string `json:"supportedInputEvents,omitempty"`
 }
 
 type PCRegistrationResponse struct {
@@ -55,6 +164,44 @@ type HeartbeatResponse struct {
 	Status    string `json:"status"`
 }
 
+// Client Shutdown Messages
+// ClientShutdownRequest lo envía el cliente antes de cerrarse intencionadamente
+type ClientShutdownRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// ClientShutdownAck confirma que el PC quedó offline y sus sesiones terminaron
+type ClientShutdownAck struct {
+	PCID   string `json:"pc_id"`
+	Status string `json:"status"`
+}
+
+// SessionEndAck lo envía el cliente al procesar control_session_ended, tras detener streaming y grabación
+type SessionEndAck struct {
+	SessionID string `json:"session_id"`
+}
+
+// ActivityStatusReport lo envía el cliente durante una sesión para indicar si el usuario está frente al PC
+type ActivityStatusReport struct {
+	SessionID           string `json:"session_id"`
+	Status              string `json:"status"` // "active" o "idle"
+	LastInputAgeSeconds int64  `json:"last_input_age_seconds"`
+}
+
+// PendingTransferSummary transferencia PENDING del cliente incluida en pending_transfers_list
+type PendingTransferSummary struct {
+	TransferID string  `json:"transfer_id"`
+	FileName   string  `json:"file_name"`
+	FileSizeMB float64 `json:"file_size_mb"`
+}
+
+// PendingTransfersList responde a query_pending_transfers con las transferencias que el servidor tiene pendientes
+// para el PC que pregunta
+type PendingTransfersList struct {
+	PCID      string                   `json:"pc_id"`
+	Transfers []PendingTransferSummary `json:"transfers"`
+}
+
 // Screen Streaming Messages
 // ScreenFrame represents a captured screen frame from client
 type ScreenFrame struct {
@@ -68,6 +215,63 @@ type ScreenFrame struct {
 	SequenceNum int64  `json:"sequence_num"`
 }
 
+// StreamConfig informs the client of the frame limits the server enforces for a session (0 = no limit)
+type StreamConfig struct {
+	SessionID      string `json:"session_id"`
+	MaxWidth       int    `json:"max_width"`
+	MaxHeight      int    `json:"max_height"`
+	OversizePolicy string `json:"oversize_policy,omitempty"` // "downscale" | "reject"
+}
+
+// StreamControl pide al cliente que deje de enviar screen_frame de una sesión (pause_stream) o que lo retome (resume_stream)
+type StreamControl struct {
+	SessionID string `json:"session_id"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// AudioChunk fragmento de audio capturado en el PC cliente (audio_chunk); solo se acepta con el audio activado
+type AudioChunk struct {
+	SessionID   string `json:"session_id"`
+	Timestamp   int64  `json:"timestamp"`
+	Codec       string `json:"codec"` // "opus", "pcm_s16le", etc.
+	SampleRate  int    `json:"sample_rate"`
+	Channels    int    `json:"channels"`
+	AudioData   []byte `json:"audio_data"` // Encoded audio bytes
+	SequenceNum int64  `json:"sequence_num"`
+}
+
+// AudioStream fragmento de audio reenviado al administrador (audio_stream). SequenceNum es la numeración propia
+// del audio de la sesión, asignada por el servidor e independiente de la de los frames.
+type AudioStream struct {
+	SessionID   string `json:"session_id"`
+	Timestamp   int64  `json:"timestamp"`
+	Codec       string `json:"codec"`
+	SampleRate  int    `json:"sample_rate"`
+	Channels    int    `json:"channels"`
+	AudioData   []byte `json:"audio_data"`
+	SequenceNum int64  `json:"sequence_num"`
+}
+
+// SetAudio activa o desactiva el audio de una sesión; lo envía el administrador y el servidor lo reenvía al cliente
+type SetAudio struct {
+	SessionID string `json:"session_id"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// AudioStatus responde al administrador tras set_audio con el estado resultante del audio de la sesión
+type AudioStatus struct {
+	SessionID string `json:"session_id"`
+	Enabled   bool   `json:"enabled"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// FrameAck confirms that the admin finished displaying a screen frame (cumulative up to SequenceNum)
+type FrameAck struct {
+	SessionID   string `json:"session_id"`
+	SequenceNum int64  `json:"sequence_num"`
+}
+
 // InputCommand represents a remote input command (mouse/keyboard) from admin
 type InputCommand struct {
 	SessionID string                 `json:"session_id"`
@@ -94,6 +298,29 @@ type KeyboardEventPayload struct {
 }
 
 // Video Upload Messages
+// VideoFrameUpload represents a single recorded frame sent by the client
+type VideoFrameUpload struct {
+	SessionID  string `json:"session_id"`
+	VideoID    string `json:"video_id"`
+	FrameIndex int    `json:"frame_index"`
+	Timestamp  int64  `json:"timestamp"`
+	FrameData  string `json:"frame_data,omitempty"` // Base64 encoded JPEG (vacío en mensajes binarios)
+}
+
+// VideoFramesBatch agrupa en un solo mensaje video_frames_batch varios frames consecutivos de una grabación
+type VideoFramesBatch struct {
+	SessionID string                  `json:"session_id"`
+	VideoID   string                  `json:"video_id"`
+	Frames    []VideoFramesBatchEntry `json:"frames"`
+}
+
+// VideoFramesBatchEntry frame dentro de un VideoFramesBatch; los frame_index deben ser consecutivos
+type VideoFramesBatchEntry struct {
+	FrameIndex int    `json:"frame_index"`
+	Timestamp  int64  `json:"timestamp"`
+	FrameData  string `json:"frame_data"` // Base64 encoded JPEG
+}
+
 // VideoChunk represents a chunk of video data for upload
 type VideoChunk struct {
 	SessionID   string `json:"session_id"`
@@ -114,6 +341,16 @@ type VideoUploadProgress struct {
 	ProgressPercent float64 `json:"progress_percent"`
 }
 
+// VideoUploadStatus respuesta a video_upload_status: chunks ya recibidos y los que faltan por reenviar
+type VideoUploadStatus struct {
+	VideoID        string `json:"video_id"`
+	SessionID      string `json:"session_id,omitempty"`
+	TotalChunks    int    `json:"total_chunks"`
+	ReceivedChunks []int  `json:"received_chunks"`
+	MissingChunks  []int  `json:"missing_chunks"`
+	IsComplete     bool   `json:"is_complete"`
+}
+
 // VideoUploadComplete represents successful upload completion
 type VideoUploadComplete struct {
 	VideoID   string `json:"video_id"`
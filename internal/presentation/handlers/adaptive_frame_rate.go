@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Valores por defecto del control adaptativo de frames hacia el administrador
+const (
+	// DefaultFrameLatencyHigh latencia de ack a partir de la cual se reduce la tasa de frames
+	DefaultFrameLatencyHigh = 500 * time.Millisecond
+	// DefaultFrameLatencyLow latencia de ack por debajo de la cual se recupera la tasa de frames
+	DefaultFrameLatencyLow = 150 * time.Millisecond
+
+	minThrottledFrameInterval = 50 * time.Millisecond
+	maxThrottledFrameInterval = time.Second
+	frameRateAdjustEvery      = 250 * time.Millisecond
+	maxPendingFrameAcks       = 64
+)
+
+// ErrFrameThrottled indica que el frame se descartó porque el enlace del administrador está congestionado
+var ErrFrameThrottled = errors.New("frame dropped: admin link congested")
+
+// sessionFrameRate estado del control de frames de una sesión
+type sessionFrameRate struct {
+	interval        time.Duration // intervalo mínimo entre frames reenviados; 0 = sin límite
+	lastForwardedAt time.Time
+	lastAdjustedAt  time.Time
+	latency         time.Duration // media móvil de la latencia de ack
+	acksEnabled     bool          // el administrador confirma frames; sin acks no se adapta la tasa
+	sentAt          map[int64]time.Time
+}
+
+// AdaptiveFrameRate ajusta por sesión cuántos frames se reenvían al administrador según la latencia
+// de sus confirmaciones (frame_ack). Cuando la latencia supera el umbral alto se espacian los frames
+// y cuando baja del umbral bajo se recupera la tasa original.
+type AdaptiveFrameRate struct {
+	highLatency time.Duration
+	lowLatency  time.Duration
+
+	sessions map[string]*sessionFrameRate
+	mutex    sync.Mutex
+}
+
+// NewAdaptiveFrameRate crea el control adaptativo con los umbrales de latencia indicados
+func NewAdaptiveFrameRate(highLatency, lowLatency time.Duration) *AdaptiveFrameRate {
+	return &AdaptiveFrameRate{
+		highLatency: highLatency,
+		lowLatency:  lowLatency,
+		sessions:    make(map[string]*sessionFrameRate),
+	}
+}
+
+// AllowFrame indica si el frame debe reenviarse; si se reenvía registra el envío para medir su ack
+func (a *AdaptiveFrameRate) AllowFrame(sessionID string, sequenceNum int64, now time.Time) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	state := a.sessionState(sessionID)
+
+	// Frames sin confirmar más antiguos que el umbral: el administrador no está consumiendo
+	if state.acksEnabled {
+		if oldest, ok := oldestPending(state.sentAt); ok && now.Sub(oldest) > a.highLatency {
+			a.observe(state, now.Sub(oldest), now)
+		}
+	}
+
+	if state.interval > 0 && now.Sub(state.lastForwardedAt) < state.interval {
+		return false
+	}
+
+	state.lastForwardedAt = now
+	if len(state.sentAt) >= maxPendingFrameAcks {
+		dropOldestPending(state.sentAt)
+	}
+	state.sentAt[sequenceNum] = now
+	return true
+}
+
+// RecordAck registra la confirmación de un frame; las confirmaciones son acumulativas
+func (a *AdaptiveFrameRate) RecordAck(sessionID string, sequenceNum int64, now time.Time) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	state := a.sessionState(sessionID)
+	state.acksEnabled = true
+
+	sentAt, exists := state.sentAt[sequenceNum]
+	for seq := range state.sentAt {
+		if seq <= sequenceNum {
+			delete(state.sentAt, seq)
+		}
+	}
+	if exists {
+		a.observe(state, now.Sub(sentAt), now)
+	}
+}
+
+// FrameInterval retorna el intervalo mínimo actual entre frames de la sesión (0 = sin límite)
+func (a *AdaptiveFrameRate) FrameInterval(sessionID string) time.Duration {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if state, exists := a.sessions[sessionID]; exists {
+		return state.interval
+	}
+	return 0
+}
+
+// Forget elimina el estado de una sesión terminada
+func (a *AdaptiveFrameRate) Forget(sessionID string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	delete(a.sessions, sessionID)
+}
+
+// sessionState obtiene o crea el estado de la sesión. Requiere mutex.
+func (a *AdaptiveFrameRate) sessionState(sessionID string) *sessionFrameRate {
+	state, exists := a.sessions[sessionID]
+	if !exists {
+		state = &sessionFrameRate{sentAt: make(map[int64]time.Time)}
+		a.sessions[sessionID] = state
+	}
+	return state
+}
+
+// observe incorpora una muestra de latencia y ajusta el intervalo como mucho cada frameRateAdjustEvery
+func (a *AdaptiveFrameRate) observe(state *sessionFrameRate, sample time.Duration, now time.Time) {
+	if state.latency == 0 {
+		state.latency = sample
+	} else {
+		state.latency = (state.latency + sample) / 2
+	}
+
+	if now.Sub(state.lastAdjustedAt) < frameRateAdjustEvery {
+		return
+	}
+
+	switch {
+	case state.latency > a.highLatency:
+		state.interval = min(max(state.interval*2, minThrottledFrameInterval*2), maxThrottledFrameInterval)
+		state.lastAdjustedAt = now
+	case state.latency < a.lowLatency && state.interval > 0:
+		state.interval /= 2
+		if state.interval < minThrottledFrameInterval {
+			state.interval = 0
+		}
+		state.lastAdjustedAt = now
+	}
+}
+
+// oldestPending retorna el envío sin confirmar más antiguo
+func oldestPending(sentAt map[int64]time.Time) (time.Time, bool) {
+	var oldest time.Time
+	found := false
+	for _, at := range sentAt {
+		if !found || at.Before(oldest) {
+			oldest = at
+			found = true
+		}
+	}
+	return oldest, found
+}
+
+// dropOldestPending descarta el envío sin confirmar con menor número de secuencia
+func dropOldestPending(sentAt map[int64]time.Time) {
+	first := true
+	var oldestSeq int64
+	for seq := range sentAt {
+		if first || seq < oldestSeq {
+			oldestSeq = seq
+			first = false
+		}
+	}
+	delete(sentAt, oldestSeq)
+}
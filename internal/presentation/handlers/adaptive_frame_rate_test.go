@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testFrameSessionID = "session-frames"
+	testFrameEvery     = 33 * time.Millisecond // el cliente captura a ~30 FPS
+)
+
+// simulatedConsumer modela un administrador que procesa los frames en orden con un coste fijo por frame
+type simulatedConsumer struct {
+	costPerFrame time.Duration
+	busyUntil    time.Time
+	pendingAcks  []pendingAck
+}
+
+type pendingAck struct {
+	sequenceNum int64
+	at          time.Time
+}
+
+// receive encola el frame y calcula cuándo llegará su ack
+func (c *simulatedConsumer) receive(sequenceNum int64, now time.Time) {
+	start := now
+	if c.busyUntil.After(start) {
+		start = c.busyUntil
+	}
+	c.busyUntil = start.Add(c.costPerFrame)
+	c.pendingAcks = append(c.pendingAcks, pendingAck{sequenceNum: sequenceNum, at: c.busyUntil})
+}
+
+// deliverAcks entrega al control adaptativo los acks que ya llegaron
+func (c *simulatedConsumer) deliverAcks(frameRate *AdaptiveFrameRate, now time.Time) {
+	for len(c.pendingAcks) > 0 && !c.pendingAcks[0].at.After(now) {
+		ack := c.pendingAcks[0]
+		c.pendingAcks = c.pendingAcks[1:]
+		frameRate.RecordAck(testFrameSessionID, ack.sequenceNum, ack.at)
+	}
+}
+
+// streamFrames simula el envío de frames durante duration y retorna cuántos se reenviaron en la última mitad
+func streamFrames(frameRate *AdaptiveFrameRate, consumer *simulatedConsumer, start time.Time, sequenceNum *int64, duration time.Duration) (time.Time, int) {
+	now := start
+	forwardedInSecondHalf := 0
+	for now.Sub(start) < duration {
+		consumer.deliverAcks(frameRate, now)
+
+		*sequenceNum++
+		if frameRate.AllowFrame(testFrameSessionID, *sequenceNum, now) {
+			consumer.receive(*sequenceNum, now)
+			if now.Sub(start) >= duration/2 {
+				forwardedInSecondHalf++
+			}
+		}
+		now = now.Add(testFrameEvery)
+	}
+	return now, forwardedInSecondHalf
+}
+
+func TestAdaptiveFrameRate_SlowConsumerDropsThenRecovers(t *testing.T) {
+	// Arrange
+	frameRate := NewAdaptiveFrameRate(DefaultFrameLatencyHigh, DefaultFrameLatencyLow)
+	slowConsumer := &simulatedConsumer{costPerFrame: 200 * time.Millisecond} // solo consume 5 FPS
+	now := time.Unix(1700000000, 0)
+	var sequenceNum int64
+
+	// Act - enlace congestionado durante 10s
+	now, congestedForwarded := streamFrames(frameRate, slowConsumer, now, &sequenceNum, 10*time.Second)
+	congestedInterval := frameRate.FrameInterval(testFrameSessionID)
+	congestedLag := slowConsumer.busyUntil.Sub(now)
+
+	// El enlace se libera: el administrador procesa los frames casi al instante
+	fastConsumer := &simulatedConsumer{costPerFrame: 5 * time.Millisecond, pendingAcks: slowConsumer.pendingAcks, busyUntil: slowConsumer.busyUntil}
+	_, recoveredForwarded := streamFrames(frameRate, fastConsumer, now, &sequenceNum, 10*time.Second)
+
+	// Assert - con congestión se reenvía muy por debajo de 30 FPS y el retraso queda acotado
+	assert.Greater(t, congestedInterval, time.Duration(0), "la tasa debe reducirse con el enlace congestionado")
+	assert.Less(t, congestedForwarded, 5*10, "en 5s congestionados no se deben reenviar más de ~10 FPS")
+	assert.Less(t, congestedLag, 2*time.Second, "el retraso del administrador no debe crecer sin límite")
+
+	// Al liberarse el enlace se recupera la tasa completa
+	assert.Equal(t, time.Duration(0), frameRate.FrameInterval(testFrameSessionID))
+	assert.GreaterOrEqual(t, recoveredForwarded, 5*28, "tras recuperarse se deben reenviar ~30 FPS")
+}
+
+func TestAdaptiveFrameRate_WithoutAcksForwardsEveryFrame(t *testing.T) {
+	// Arrange - un administrador que no envía frame_ack no debe ver frames descartados
+	frameRate := NewAdaptiveFrameRate(DefaultFrameLatencyHigh, DefaultFrameLatencyLow)
+	now := time.Unix(1700000000, 0)
+
+	// Act
+	forwarded := 0
+	for seq := int64(1); seq <= 300; seq++ {
+		if frameRate.AllowFrame(testFrameSessionID, seq, now) {
+			forwarded++
+		}
+		now = now.Add(testFrameEvery)
+	}
+
+	// Assert
+	assert.Equal(t, 300, forwarded)
+	assert.Equal(t, time.Duration(0), frameRate.FrameInterval(testFrameSessionID))
+}
+
+func TestAdaptiveFrameRate_ForgetResetsSession(t *testing.T) {
+	// Arrange
+	frameRate := NewAdaptiveFrameRate(DefaultFrameLatencyHigh, DefaultFrameLatencyLow)
+	now := time.Unix(1700000000, 0)
+	frameRate.AllowFrame(testFrameSessionID, 1, now)
+	frameRate.RecordAck(testFrameSessionID, 1, now.Add(2*time.Second))
+	assert.Greater(t, frameRate.FrameInterval(testFrameSessionID), time.Duration(0))
+
+	// Act
+	frameRate.Forget(testFrameSessionID)
+
+	// Assert
+	assert.Equal(t, time.Duration(0), frameRate.FrameInterval(testFrameSessionID))
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// DefaultPCBroadcastBatchWindow ventana con la que main.go agrupa los broadcasts de PCs si no se configura otra.
+// Es 0 (un mensaje por evento) porque los paneles que no entienden pc_list_delta dejarían de recibir los cambios;
+// la agrupación se activa con WS_ADMIN_BROADCAST_BATCH_WINDOW cuando todos los paneles la soportan.
+const DefaultPCBroadcastBatchWindow time.Duration = 0
+
+// Eventos de un PCListDeltaChange y la categoría de notificación que les corresponde
+const (
+	pcDeltaEventConnection    = "connection"
+	pcDeltaEventDisconnection = "disconnection"
+	pcDeltaEventStatusChange  = "status_change"
+)
+
+var pcDeltaEventCategories = map[string]string{
+	pcDeltaEventConnection:    "pc_connected",
+	pcDeltaEventDisconnection: "pc_disconnected",
+	pcDeltaEventStatusChange:  "pc_status_changed",
+}
+
+// pcBroadcastBatch acumula los cambios de PCs durante la ventana. El primer cambio programa el envío, que sale al
+// cumplirse la ventana aunque sigan llegando cambios: una avalancha continua no retrasa las notificaciones más de
+// una ventana.
+type pcBroadcastBatch struct {
+	window      time.Duration
+	changes     map[string]*dto.PCListDeltaChange
+	order       []string
+	listChanged bool
+	events      int
+	timer       *time.Timer
+	mutex       sync.Mutex
+}
+
+func newPCBroadcastBatch(window time.Duration) *pcBroadcastBatch {
+	return &pcBroadcastBatch{window: window, changes: make(map[string]*dto.PCListDeltaChange)}
+}
+
+// add registra un evento de un PC y, si es el primero de la ventana, programa flush
+func (b *pcBroadcastBatch) add(pcID, identifier, ownerUserID, event string, apply func(change *dto.PCListDeltaChange), flush func()) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	change, exists := b.changes[pcID]
+	if !exists {
+		change = &dto.PCListDeltaChange{PCID: pcID}
+		b.changes[pcID] = change
+		b.order = append(b.order, pcID)
+	}
+	change.Identifier = identifier
+	change.OwnerUserID = ownerUserID
+	change.Events = append(change.Events, event)
+	change.Timestamp = time.Now().Unix()
+	apply(change)
+
+	b.events++
+	b.schedule(flush)
+}
+
+// markListChanged registra un pc_list_update
+func (b *pcBroadcastBatch) markListChanged(flush func()) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.listChanged = true
+	b.events++
+	b.schedule(flush)
+}
+
+// schedule programa flush al final de la ventana si no hay un envío pendiente; llamar con el mutex tomado
+func (b *pcBroadcastBatch) schedule(flush func()) {
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.window, flush)
+	}
+}
+
+// take retorna el resumen acumulado y deja el lote vacío para la siguiente ventana
+func (b *pcBroadcastBatch) take() (dto.PCListDelta, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.timer = nil
+	if b.events == 0 {
+		return dto.PCListDelta{}, false
+	}
+
+	delta := dto.PCListDelta{
+		Changes:         make([]dto.PCListDeltaChange, 0, len(b.order)),
+		ListChanged:     b.listChanged,
+		CoalescedEvents: b.events,
+		Timestamp:       time.Now().Unix(),
+		Event:           "list_delta",
+	}
+	for _, pcID := range b.order {
+		delta.Changes = append(delta.Changes, *b.changes[pcID])
+	}
+
+	b.changes = make(map[string]*dto.PCListDeltaChange)
+	b.order = nil
+	b.listChanged = false
+	b.events = 0
+	return delta, true
+}
+
+// SetPCBroadcastBatchWindow agrupa las notificaciones pc_connected, pc_disconnected, pc_status_changed y
+// pc_list_update de cada ventana en un único pc_list_delta (window <= 0 las envía una a una)
+func (h *AdminWebSocketHandler) SetPCBroadcastBatchWindow(window time.Duration) {
+	if window <= 0 {
+		h.pcBatch = nil
+		return
+	}
+	h.pcBatch = newPCBroadcastBatch(window)
+}
+
+// batchPCChange añade el evento al lote en curso; false si la agrupación está desactivada y hay que enviarlo ya
+func (h *AdminWebSocketHandler) batchPCChange(pcID, identifier, ownerUserID, event string, apply func(change *dto.PCListDeltaChange)) bool {
+	batch := h.pcBatch
+	if batch == nil {
+		return false
+	}
+	batch.add(pcID, identifier, ownerUserID, event, apply, func() { h.flushPCBroadcastBatch(batch) })
+	return true
+}
+
+// batchPCListUpdate añade pc_list_update al lote en curso; false si la agrupación está desactivada
+func (h *AdminWebSocketHandler) batchPCListUpdate() bool {
+	batch := h.pcBatch
+	if batch == nil {
+		return false
+	}
+	batch.markListChanged(func() { h.flushPCBroadcastBatch(batch) })
+	return true
+}
+
+// flushPCBroadcastBatch envía a cada administrador la parte del lote que permiten sus preferencias de notificación
+func (h *AdminWebSocketHandler) flushPCBroadcastBatch(batch *pcBroadcastBatch) {
+	delta, ok := batch.take()
+	if !ok {
+		return
+	}
+
+	scopes := newAdminPCScopes(h.adminPCIDs)
+	for _, adminConn := range h.adminConnectionList() {
+		adminDelta, ok := h.filterPCListDelta(adminConn.UserID, delta, scopes)
+		if !ok {
+			continue
+		}
+		message := dto.WebSocketMessage{Type: dto.MessageTypePCListDelta, Data: adminDelta}
+		if err := adminConn.writer().WriteJSON(message); err != nil {
+			log.Printf("Error sending message to admin %s (%s): %v", adminConn.Username, adminConn.ID, err)
+		}
+	}
+	log.Printf("Broadcasted PC list delta: %d PCs changed in %d events", len(delta.Changes), delta.CoalescedEvents)
+}
+
+// filterPCListDelta quita los cambios cuyos eventos tiene silenciados el administrador o que no son de su alcance;
+// false si no queda nada que enviarle
+func (h *AdminWebSocketHandler) filterPCListDelta(adminUserID string, delta dto.PCListDelta, scopes *adminPCScopes) (dto.PCListDelta, bool) {
+	if !h.notificationPrefs.allows(adminUserID, dto.MessageTypePCListDelta, "", scopes) {
+		return dto.PCListDelta{}, false
+	}
+
+	filtered := delta
+	filtered.Changes = make([]dto.PCListDeltaChange, 0, len(delta.Changes))
+	for _, change := range delta.Changes {
+		for _, event := range change.Events {
+			if h.notificationPrefs.allows(adminUserID, pcDeltaEventCategories[event], change.PCID, scopes) {
+				filtered.Changes = append(filtered.Changes, change)
+				break
+			}
+		}
+	}
+	filtered.ListChanged = delta.ListChanged && h.notificationPrefs.allows(adminUserID, "pc_list_update", "", scopes)
+
+	return filtered, len(filtered.Changes) > 0 || filtered.ListChanged
+}
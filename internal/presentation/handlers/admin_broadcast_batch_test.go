@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+const testBatchWindow = 50 * time.Millisecond
+
+// decodePCListDelta convierte el data de un pc_list_delta leído del socket
+func decodePCListDelta(t *testing.T, message dto.WebSocketMessage) dto.PCListDelta {
+	t.Helper()
+
+	require.Equal(t, dto.MessageTypePCListDelta, message.Type)
+	raw, err := json.Marshal(message.Data)
+	require.NoError(t, err)
+	var delta dto.PCListDelta
+	require.NoError(t, json.Unmarshal(raw, &delta))
+	return delta
+}
+
+func TestPCBroadcastBatch_BurstIsSentAsSingleDelta(t *testing.T) {
+	// Arrange
+	h := NewAdminWebSocketHandler(nil, nil)
+	h.SetPCBroadcastBatchWindow(testBatchWindow)
+	adminSide := connectTestAdmin(t, h)
+
+	// Act - cinco PCs se reconectan a la vez tras un corte de red y uno de ellos vuelve a caer
+	for i := 1; i <= 5; i++ {
+		pcID := fmt.Sprintf("pc-%d", i)
+		h.BroadcastPCConnected(pcID, "lab-"+pcID, testAdminUserID, "10.0.0.5")
+		h.BroadcastPCStatusChanged(pcID, "lab-"+pcID, testAdminUserID, "OFFLINE", "ONLINE")
+		h.BroadcastPCListUpdate()
+	}
+	h.BroadcastPCDisconnected("pc-3", "lab-pc-3", testAdminUserID, remotesession.DisconnectReason{CloseCode: remotesession.CloseCodeAbnormalClosure})
+	h.BroadcastPCStatusChanged("pc-3", "lab-pc-3", testAdminUserID, "ONLINE", "OFFLINE")
+	h.BroadcastPCListUpdate()
+
+	// Assert - un único mensaje al final de la ventana con el estado final de cada PC
+	delta := decodePCListDelta(t, readAdminMessage(t, adminSide))
+	assert.Equal(t, 18, delta.CoalescedEvents)
+	assert.True(t, delta.ListChanged)
+	require.Len(t, delta.Changes, 5)
+	assert.Equal(t, "pc-1", delta.Changes[0].PCID)
+	assert.Equal(t, "ONLINE", delta.Changes[0].Status)
+	assert.Equal(t, "OFFLINE", delta.Changes[0].PreviousStatus)
+	assert.Equal(t, []string{"connection", "status_change"}, delta.Changes[0].Events)
+
+	dropped := delta.Changes[2]
+	assert.Equal(t, "pc-3", dropped.PCID)
+	assert.Equal(t, "OFFLINE", dropped.Status)
+	assert.Equal(t, "OFFLINE", dropped.PreviousStatus)
+	assert.Equal(t, remotesession.CloseCodeAbnormalClosure, dropped.CloseCode)
+	assert.Equal(t, []string{"connection", "status_change", "disconnection", "status_change"}, dropped.Events)
+
+	assertNoClientMessage(t, adminSide)
+}
+
+func TestPCBroadcastBatch_NextWindowStartsEmpty(t *testing.T) {
+	// Arrange
+	h := NewAdminWebSocketHandler(nil, nil)
+	h.SetPCBroadcastBatchWindow(testBatchWindow)
+	adminSide := connectTestAdmin(t, h)
+	h.BroadcastPCConnected("pc-1", "lab-pc-1", testAdminUserID, "10.0.0.5")
+	decodePCListDelta(t, readAdminMessage(t, adminSide))
+
+	// Act
+	h.BroadcastPCListUpdate()
+
+	// Assert
+	delta := decodePCListDelta(t, readAdminMessage(t, adminSide))
+	assert.Empty(t, delta.Changes)
+	assert.True(t, delta.ListChanged)
+	assert.Equal(t, 1, delta.CoalescedEvents)
+}
+
+func TestPCBroadcastBatch_AppliesNotificationPreferences(t *testing.T) {
+	// Arrange - el primer administrador solo quiere sus PCs; el segundo silenció conexiones y estados
+	pinnedRepo := new(MockPinnedPCRepository)
+	pinnedRepo.On("FindPCIDsByAdmin", mock.Anything, testAdminUserID).Return([]string{"pc-own"}, nil)
+	h := NewAdminWebSocketHandler(nil, nil)
+	h.SetPCBroadcastBatchWindow(testBatchWindow)
+	h.SetPinnedPCRepository(pinnedRepo)
+	ownedSide := connectTestAdmin(t, h)
+	mutedSide := connectTestAdminAs(t, h, "conn-2", otherAdminUserID)
+	require.NoError(t, h.notificationPrefs.subscribe(testAdminUserID, nil, string(NotificationScopeOwned)))
+	require.NoError(t, h.notificationPrefs.unsubscribe(otherAdminUserID, []string{"pc_connected", "pc_status_changed"}))
+
+	// Act
+	h.BroadcastPCConnected("pc-own", "own-pc", testAdminUserID, "10.0.0.5")
+	h.BroadcastPCConnected("pc-other", "other-pc", otherAdminUserID, "10.0.0.6")
+	h.BroadcastPCStatusChanged("pc-other", "other-pc", otherAdminUserID, "OFFLINE", "ONLINE")
+	h.BroadcastPCListUpdate()
+
+	// Assert
+	owned := decodePCListDelta(t, readAdminMessage(t, ownedSide))
+	require.Len(t, owned.Changes, 1)
+	assert.Equal(t, "pc-own", owned.Changes[0].PCID)
+	assert.True(t, owned.ListChanged)
+
+	muted := decodePCListDelta(t, readAdminMessage(t, mutedSide))
+	assert.Empty(t, muted.Changes)
+	assert.True(t, muted.ListChanged)
+}
+
+func TestPCBroadcastBatch_DisabledSendsEachEvent(t *testing.T) {
+	// Arrange
+	h := NewAdminWebSocketHandler(nil, nil)
+	h.SetPCBroadcastBatchWindow(0)
+	adminSide := connectTestAdmin(t, h)
+
+	// Act
+	h.BroadcastPCConnected("pc-1", "lab-pc-1", testAdminUserID, "10.0.0.5")
+	h.BroadcastPCListUpdate()
+
+	// Assert
+	assert.Equal(t, "pc_connected", readAdminMessage(t, adminSide).Type)
+	assert.Equal(t, "pc_list_update", readAdminMessage(t, adminSide).Type)
+}
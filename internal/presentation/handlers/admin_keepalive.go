@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Valores por defecto del keepalive de los administradores: un ping cada 25 s y la conexión se cierra si
+// pasan 60 s sin pong ni mensajes
+const (
+	DefaultAdminPingInterval    = 25 * time.Second
+	DefaultAdminPresenceTimeout = 60 * time.Second
+)
+
+// AdminKeepaliveConfig cadencia de los pings del servidor a los administradores y tiempo sin respuesta tras el
+// que la conexión se da por perdida. Un dashboard inactivo no envía mensajes: solo su pong mantiene la conexión.
+type AdminKeepaliveConfig struct {
+	PingInterval    time.Duration
+	PresenceTimeout time.Duration
+}
+
+// DefaultAdminKeepaliveConfig ping cada DefaultAdminPingInterval, desconexión tras DefaultAdminPresenceTimeout
+func DefaultAdminKeepaliveConfig() AdminKeepaliveConfig {
+	return AdminKeepaliveConfig{PingInterval: DefaultAdminPingInterval, PresenceTimeout: DefaultAdminPresenceTimeout}
+}
+
+// SetAdminKeepaliveConfig configura los pings y el timeout de presencia de las conexiones de administradores.
+// Valores <= 0 mantienen los de por defecto; un intervalo que no cabe en el timeout se reduce a la mitad de
+// este para que el pong llegue antes de que venza.
+func (h *AdminWebSocketHandler) SetAdminKeepaliveConfig(config AdminKeepaliveConfig) {
+	if config.PresenceTimeout <= 0 {
+		config.PresenceTimeout = DefaultAdminPresenceTimeout
+	}
+	if config.PingInterval <= 0 {
+		config.PingInterval = DefaultAdminPingInterval
+	}
+	if config.PingInterval >= config.PresenceTimeout {
+		config.PingInterval = config.PresenceTimeout / 2
+	}
+	h.keepalive = config
+}
+
+// extendAdminPresence aplaza el cierre por inactividad de la conexión tras un pong o un mensaje
+func (h *AdminWebSocketHandler) extendAdminPresence(adminConn *AdminConnection) {
+	adminConn.Conn.SetReadDeadline(time.Now().Add(h.keepalive.PresenceTimeout))
+}
+
+// adminPingWriteTimeout tiempo máximo para escribir un ping en una conexión sin buffer de salida
+const adminPingWriteTimeout = 5 * time.Second
+
+// runAdminKeepalive envía pings al administrador hasta que se cierra done o falla el envío. Con buffer el ping lo
+// escribe su goroutine de salida, en orden con el resto de mensajes. Sin buffer se envía con WriteControl, el único
+// método de escritura de gorilla/websocket que admite llamadas concurrentes con el resto de escrituras.
+func (h *AdminWebSocketHandler) runAdminKeepalive(adminConn *AdminConnection, done <-chan struct{}) {
+	ticker := time.NewTicker(h.keepalive.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := sendAdminPing(adminConn); err != nil {
+				log.Printf("⚠️ ADMIN KEEPALIVE: Ping to admin %s (%s) failed: %v", adminConn.Username, adminConn.ID, err)
+				return
+			}
+		}
+	}
+}
+
+// sendAdminPing escribe un ping por el buffer de salida o, sin buffer, como control frame
+func sendAdminPing(adminConn *AdminConnection) error {
+	if adminConn.outbound != nil {
+		return adminConn.outbound.WriteMessage(websocket.PingMessage, nil)
+	}
+	return adminConn.Conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(adminPingWriteTimeout))
+}
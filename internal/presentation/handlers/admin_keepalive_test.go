@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dialKeepaliveAdmin conecta un administrador al handler con el keepalive indicado y descarta la bienvenida;
+// configure ajusta el handler antes de conectar
+func dialKeepaliveAdmin(t *testing.T, config AdminKeepaliveConfig, configure ...func(h *AdminWebSocketHandler)) (*AdminWebSocketHandler, *websocket.Conn) {
+	t.Helper()
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByUsername", "admin").Return(user.NewUser(testAdminUserID, "admin", "", string(hashedPassword), user.RoleAdministrator), nil)
+	authService := userservice.NewAuthService(userRepo, "test-secret")
+	token, _, err := authService.AuthenticateAdmin("admin", "password")
+	require.NoError(t, err)
+
+	h := NewAdminWebSocketHandler(authService, nil)
+	h.SetAdminKeepaliveConfig(config)
+	for _, fn := range configure {
+		fn(h)
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ws/admin", h.HandleAdminWebSocket)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	adminSide, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/admin?token="+token, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { adminSide.Close() })
+	require.Equal(t, "admin_connected", readAdminMessage(t, adminSide).Type)
+	return h, adminSide
+}
+
+func TestHandleAdminWebSocket_IdleAdminAnsweringPingsStaysConnected(t *testing.T) {
+	// Arrange
+	h, adminSide := dialKeepaliveAdmin(t, AdminKeepaliveConfig{PingInterval: 20 * time.Millisecond, PresenceTimeout: 100 * time.Millisecond})
+	pings := make(chan struct{}, 64)
+	adminSide.SetPingHandler(func(data string) error {
+		pings <- struct{}{}
+		return adminSide.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+
+	// Act - el dashboard no envía nada; solo lee, lo que responde a los pings
+	readErr := make(chan error, 1)
+	go func() {
+		_, _, err := adminSide.ReadMessage()
+		readErr <- err
+	}()
+	time.Sleep(400 * time.Millisecond)
+
+	// Assert - sigue conectado mucho después del timeout de presencia
+	select {
+	case err := <-readErr:
+		t.Fatalf("idle admin connection was closed: %v", err)
+	default:
+	}
+	assert.Equal(t, 1, h.GetAdminCount())
+	assert.GreaterOrEqual(t, len(pings), 3)
+}
+
+func TestHandleAdminWebSocket_UnbufferedConnectionPingsWithControlFrames(t *testing.T) {
+	// Arrange - sin buffer de salida el ping se escribe directamente en el socket
+	h, adminSide := dialKeepaliveAdmin(t, AdminKeepaliveConfig{PingInterval: 5 * time.Millisecond, PresenceTimeout: time.Second},
+		func(h *AdminWebSocketHandler) { h.SetOutboundBufferConfig(OutboundBufferConfig{}) })
+	pings := make(chan struct{}, 256)
+	adminSide.SetPingHandler(func(data string) error {
+		pings <- struct{}{}
+		return adminSide.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	go func() {
+		for {
+			if _, _, err := adminSide.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Act
+	time.Sleep(100 * time.Millisecond)
+
+	// Assert
+	assert.NotEmpty(t, pings)
+	assert.Equal(t, 1, h.GetAdminCount())
+}
+
+func TestHandleAdminWebSocket_ClosesAdminThatDoesNotAnswerPings(t *testing.T) {
+	// Arrange - el lado del administrador no lee, así que nunca responde a los pings
+	h, _ := dialKeepaliveAdmin(t, AdminKeepaliveConfig{PingInterval: 20 * time.Millisecond, PresenceTimeout: 100 * time.Millisecond})
+
+	// Act & Assert
+	require.Eventually(t, func() bool { return h.GetAdminCount() == 0 }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestSetAdminKeepaliveConfig_KeepsPingIntervalBelowTimeout(t *testing.T) {
+	// Arrange
+	h := NewAdminWebSocketHandler(nil, nil)
+
+	// Act
+	h.SetAdminKeepaliveConfig(AdminKeepaliveConfig{PingInterval: time.Minute, PresenceTimeout: 30 * time.Second})
+
+	// Assert
+	assert.Equal(t, 15*time.Second, h.keepalive.PingInterval)
+	assert.Equal(t, 30*time.Second, h.keepalive.PresenceTimeout)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// adminSessionViews vista de cada administrador (por UserID, no por conexión): las sesiones que está viendo
+// y el último frame de cada una. Se conserva mientras el WebSocket del administrador está caído para que al
+// reconectarse retome las sesiones sin esperar al siguiente frame del cliente.
+type adminSessionViews struct {
+	lastFrames map[string]map[string]dto.ScreenFrame // adminUserID -> sessionID -> último frame
+	mutex      sync.Mutex
+}
+
+func newAdminSessionViews() *adminSessionViews {
+	return &adminSessionViews{lastFrames: make(map[string]map[string]dto.ScreenFrame)}
+}
+
+// remember guarda el frame como el último de la sesión para el administrador
+func (v *adminSessionViews) remember(adminUserID string, frame dto.ScreenFrame) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	sessions, exists := v.lastFrames[adminUserID]
+	if !exists {
+		sessions = make(map[string]dto.ScreenFrame)
+		v.lastFrames[adminUserID] = sessions
+	}
+	sessions[frame.SessionID] = frame
+}
+
+// forget elimina la sesión de la vista del administrador
+func (v *adminSessionViews) forget(adminUserID, sessionID string) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	delete(v.lastFrames[adminUserID], sessionID)
+	if len(v.lastFrames[adminUserID]) == 0 {
+		delete(v.lastFrames, adminUserID)
+	}
+}
+
+// forgetSession elimina la sesión de la vista de cualquier administrador
+func (v *adminSessionViews) forgetSession(sessionID string) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	for adminUserID, sessions := range v.lastFrames {
+		delete(sessions, sessionID)
+		if len(sessions) == 0 {
+			delete(v.lastFrames, adminUserID)
+		}
+	}
+}
+
+// frames retorna los últimos frames de las sesiones del administrador, ordenados por sesión
+func (v *adminSessionViews) frames(adminUserID string) []dto.ScreenFrame {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	frames := make([]dto.ScreenFrame, 0, len(v.lastFrames[adminUserID]))
+	for _, frame := range v.lastFrames[adminUserID] {
+		frames = append(frames, frame)
+	}
+	sort.Slice(frames, func(i, j int) bool { return frames[i].SessionID < frames[j].SessionID })
+	return frames
+}
+
+// resumeSessionView retoma en una conexión recién abierta las sesiones que el administrador estaba viendo:
+// envía session_view_resumed con las sesiones que siguen activas y bajo su control, y a continuación el
+// último frame de cada una. Las que ya no lo están se descartan de la vista.
+func (h *AdminWebSocketHandler) resumeSessionView(adminConn *AdminConnection) {
+	frames := h.sessionViews.frames(adminConn.UserID)
+	if len(frames) == 0 {
+		return
+	}
+
+	resumed := make([]dto.ScreenFrame, 0, len(frames))
+	for _, frame := range frames {
+		if h.sessionService != nil {
+			if err := h.sessionService.ValidateInputCommandPermission(adminConn.Context(), frame.SessionID, adminConn.UserID); err != nil {
+				h.sessionViews.forget(adminConn.UserID, frame.SessionID)
+				continue
+			}
+		}
+		resumed = append(resumed, frame)
+	}
+	if len(resumed) == 0 {
+		return
+	}
+
+	sessionIDs := make([]string, 0, len(resumed))
+	for _, frame := range resumed {
+		sessionIDs = append(sessionIDs, frame.SessionID)
+	}
+
+	writer := adminConn.writer()
+	writer.WriteJSON(dto.WebSocketMessage{
+		Type: "session_view_resumed",
+		Data: map[string]interface{}{
+			"session_ids": sessionIDs,
+			"timestamp":   time.Now().Unix(),
+		},
+	})
+	for _, frame := range resumed {
+		if err := writer.WriteJSON(dto.WebSocketMessage{Type: dto.MessageTypeScreenFrame, Data: frame}); err != nil {
+			log.Printf("Error re-sending last frame of session %s to admin %s: %v", frame.SessionID, adminConn.UserID, err)
+			return
+		}
+	}
+
+	log.Printf("🔁 Admin %s reconnected, resumed %d session(s): %v", adminConn.Username, len(sessionIDs), sessionIDs)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// readAdminMessage lee el siguiente mensaje enviado al administrador
+func readAdminMessage(t *testing.T, adminSide *websocket.Conn) dto.WebSocketMessage {
+	t.Helper()
+
+	var message dto.WebSocketMessage
+	require.NoError(t, adminSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, adminSide.ReadJSON(&message))
+	return message
+}
+
+func TestHandleAdminWebSocket_ReconnectingAdminResumesSessionFrames(t *testing.T) {
+	// Arrange
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByUsername", "admin").Return(user.NewUser(testAdminUserID, "admin", "", string(hashedPassword), user.RoleAdministrator), nil)
+	authService := userservice.NewAuthService(userRepo, "test-secret")
+	token, _, err := authService.AuthenticateAdmin("admin", "password")
+	require.NoError(t, err)
+
+	session, err := remotesession.NewRemoteSession(testAdminUserID, testTargetPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+
+	h := NewAdminWebSocketHandler(authService, remotesessionservice.NewRemoteSessionService(sessionRepo, nil, nil, nil, nil))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ws/admin", h.HandleAdminWebSocket)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	dialAdmin := func() *websocket.Conn {
+		adminSide, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/admin?token="+token, nil)
+		require.NoError(t, err)
+		require.Equal(t, "admin_connected", readAdminMessage(t, adminSide).Type)
+		return adminSide
+	}
+	frame := func(sequenceNum int64) dto.ScreenFrame {
+		return dto.ScreenFrame{SessionID: session.SessionID(), SequenceNum: sequenceNum, Format: "jpeg", FrameData: []byte("jpeg"), Width: 640, Height: 480}
+	}
+
+	firstConn := dialAdmin()
+	require.NoError(t, h.ForwardScreenFrameToAdmin(testAdminUserID, frame(1)))
+	require.Equal(t, dto.MessageTypeScreenFrame, readAdminMessage(t, firstConn).Type)
+
+	// El WebSocket del administrador se cae y el cliente sigue enviando frames
+	firstConn.Close()
+	require.Eventually(t, func() bool { return h.GetAdminCount() == 0 }, 2*time.Second, 10*time.Millisecond)
+	assert.Error(t, h.ForwardScreenFrameToAdmin(testAdminUserID, frame(2)))
+
+	// Act
+	secondConn := dialAdmin()
+	t.Cleanup(func() { secondConn.Close() })
+
+	// Assert - retoma la sesión con el último frame y sigue recibiendo los nuevos
+	resumed := readAdminMessage(t, secondConn)
+	require.Equal(t, "session_view_resumed", resumed.Type)
+	assert.Equal(t, []interface{}{session.SessionID()}, resumed.Data.(map[string]interface{})["session_ids"])
+
+	cached := readAdminMessage(t, secondConn)
+	require.Equal(t, dto.MessageTypeScreenFrame, cached.Type)
+	assert.Equal(t, float64(2), cached.Data.(map[string]interface{})["sequence_num"])
+
+	require.NoError(t, h.ForwardScreenFrameToAdmin(testAdminUserID, frame(3)))
+	live := readAdminMessage(t, secondConn)
+	require.Equal(t, dto.MessageTypeScreenFrame, live.Type)
+	assert.Equal(t, float64(3), live.Data.(map[string]interface{})["sequence_num"])
+}
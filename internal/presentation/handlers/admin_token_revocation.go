@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// CloseCodeTokensRevoked código de cierre (1008 Policy Violation) de las conexiones de un administrador cuyos tokens
+// se revocaron
+const CloseCodeTokensRevoked = websocket.ClosePolicyViolation
+
+// CloseAdminConnections cierra las conexiones WebSocket vivas del administrador para obligarle a autenticarse de
+// nuevo. Retorna cuántas conexiones se cerraron; el bucle de lectura de cada una la elimina del mapa al cerrarse.
+func (h *AdminWebSocketHandler) CloseAdminConnections(adminUserID, reason string) int {
+	h.mutex.RLock()
+	connections := make([]*AdminConnection, 0)
+	for _, adminConn := range h.adminConnections {
+		if adminConn.UserID == adminUserID {
+			connections = append(connections, adminConn)
+		}
+	}
+	h.mutex.RUnlock()
+
+	closeMessage := websocket.FormatCloseMessage(CloseCodeTokensRevoked, reason)
+	for _, adminConn := range connections {
+		adminConn.Conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second))
+		adminConn.Conn.Close()
+		log.Printf("🔒 TOKEN REVOCATION: Closed admin connection %s of user %s (%s)", adminConn.ID, adminUserID, reason)
+	}
+	return len(connections)
+}
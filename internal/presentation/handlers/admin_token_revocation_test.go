@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminWebSocketHandler_CloseAdminConnections_ClosesOnlyThatAdmin(t *testing.T) {
+	// Arrange - dos conexiones del administrador revocado y una de otro administrador
+	h := NewAdminWebSocketHandler(nil, nil)
+	firstSide := connectTestAdmin(t, h)
+	secondSide := connectTestAdminAs(t, h, "conn-2", testAdminUserID)
+	otherSide := connectTestAdminAs(t, h, "conn-3", "other-admin-id")
+
+	// Act
+	closed := h.CloseAdminConnections(testAdminUserID, "tokens revoked")
+
+	// Assert
+	assert.Equal(t, 2, closed)
+	for _, adminSide := range []*websocket.Conn{firstSide, secondSide} {
+		require.NoError(t, adminSide.SetReadDeadline(time.Now().Add(time.Second)))
+		_, _, err := adminSide.ReadMessage()
+		var closeErr *websocket.CloseError
+		require.ErrorAs(t, err, &closeErr)
+		assert.Equal(t, CloseCodeTokensRevoked, closeErr.Code)
+		assert.Equal(t, "tokens revoked", closeErr.Text)
+	}
+	assertNoClientMessage(t, otherSide)
+}
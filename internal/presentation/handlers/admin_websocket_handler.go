@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,8 +13,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/featureflagservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/storagequotaservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/macro"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
 	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
 )
@@ -26,6 +33,28 @@ type AdminConnection struct {
 	IsAuth   bool
 	Conn     *websocket.Conn
 	LastSeen time.Time
+
+	// outbound buffer de salida de la conexión; nil escribe directamente en Conn
+	outbound *OutboundBuffer
+
+	// ctx contexto de la conexión; se cancela al cerrarse el WebSocket y aborta las consultas en curso
+	ctx context.Context
+}
+
+// Context retorna el contexto de la conexión para las consultas a BD que origina
+func (c *AdminConnection) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// writer retorna el destino de los mensajes hacia el administrador: su buffer de salida o, sin buffer, el socket
+func (c *AdminConnection) writer() messageWriter {
+	if c.outbound == nil {
+		return c.Conn
+	}
+	return c.outbound
 }
 
 // AdminWebSocketHandler maneja las conexiones WebSocket de administradores
@@ -38,6 +67,36 @@ type AdminWebSocketHandler struct {
 	// Mapa de conexiones de administradores
 	adminConnections map[string]*AdminConnection
 	mutex            sync.RWMutex
+
+	// Reduce los frames reenviados cuando el administrador los confirma con retraso
+	frameRate *AdaptiveFrameRate
+	// featureFlags permite desactivar frameRate (adaptive_frame_rate) sin redesplegar
+	featureFlags featureflagservice.IFeatureFlagService
+	// inputRecorder captura los comandos reenviados mientras se graba una macro (opcional)
+	inputRecorder InputCommandRecorder
+	// outboundConfig buffer de salida de cada conexión de administrador
+	outboundConfig OutboundBufferConfig
+	// capacity contador compartido que limita las conexiones de administradores (nil = sin límite)
+	capacity *ConnectionCapacity
+	// inputRate limita los input_command reenviados por sesión
+	inputRate *InputRateLimiter
+	// sessionViews sesiones y último frame de cada administrador; sobreviven a la reconexión
+	sessionViews *adminSessionViews
+	// notificationPrefs categorías silenciadas y alcance de los broadcasts de cada administrador
+	notificationPrefs *adminNotificationPreferences
+	// pinnedPCRepository PCs fijados, parte del alcance "owned" de las notificaciones (opcional)
+	pinnedPCRepository interfaces.IPinnedPCRepository
+	// pcBatch agrupa los broadcasts de PCs en pc_list_delta (nil = un mensaje por evento)
+	pcBatch *pcBroadcastBatch
+	// keepalive pings del servidor y timeout de presencia de cada conexión
+	keepalive AdminKeepaliveConfig
+}
+
+// InputCommandRecorder recibe los comandos de input reenviados al cliente para grabar macros
+type InputCommandRecorder interface {
+	CaptureCommand(sessionID string, command macro.Command)
+	// DiscardAdminRecordings descarta las grabaciones sin guardar del administrador que se desconectó
+	DiscardAdminRecordings(adminUserID string) int
 }
 
 // NewAdminWebSocketHandler crea un nuevo handler de WebSocket para administradores
@@ -53,7 +112,13 @@ func NewAdminWebSocketHandler(
 				return true // En producción, verificar origen
 			},
 		},
-		adminConnections: make(map[string]*AdminConnection),
+		adminConnections:  make(map[string]*AdminConnection),
+		frameRate:         NewAdaptiveFrameRate(DefaultFrameLatencyHigh, DefaultFrameLatencyLow),
+		outboundConfig:    DefaultOutboundBufferConfig(),
+		inputRate:         NewInputRateLimiter(DefaultInputRateLimitConfig()),
+		sessionViews:      newAdminSessionViews(),
+		notificationPrefs: newAdminNotificationPreferences(),
+		keepalive:         DefaultAdminKeepaliveConfig(),
 	}
 }
 
@@ -84,7 +149,7 @@ func (h *AdminWebSocketHandler) HandleAdminWebSocket(c *gin.Context) {
 		return
 	}
 
-	if userClaims.Role != string(user.RoleAdministrator) {
+	if !user.Role(userClaims.Role).IsAdmin() {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Admin privileges required"})
 		return
 	}
@@ -97,6 +162,20 @@ func (h *AdminWebSocketHandler) HandleAdminWebSocket(c *gin.Context) {
 	}
 	defer conn.Close()
 
+	// Rechazar la conexión si ya se alcanzó el máximo de administradores
+	if h.capacity != nil {
+		release, ok := h.capacity.Acquire(ConnectionKindAdmin)
+		if !ok {
+			rejectAtCapacity(conn, ConnectionKindAdmin)
+			return
+		}
+		defer release()
+	}
+
+	// Contexto de la conexión: las consultas de sus mensajes se cancelan al desconectarse
+	connCtx, cancelConn := context.WithCancel(c.Request.Context())
+	defer cancelConn()
+
 	// Crear conexión de administrador
 	adminConn := &AdminConnection{
 		ID:       generateConnectionID(),
@@ -106,8 +185,16 @@ func (h *AdminWebSocketHandler) HandleAdminWebSocket(c *gin.Context) {
 		IsAuth:   true,
 		Conn:     conn,
 		LastSeen: time.Now(),
+		ctx:      connCtx,
+	}
+	if h.outboundConfig.Size > 0 {
+		adminConn.outbound = NewOutboundBuffer(conn, h.outboundConfig)
+		defer adminConn.outbound.Close()
 	}
 
+	// Cargar las preferencias de notificación guardadas antes de que empiece a recibir broadcasts
+	h.notificationPrefs.load(connCtx, adminConn.UserID)
+
 	// Registrar conexión
 	h.mutex.Lock()
 	h.adminConnections[adminConn.ID] = adminConn
@@ -123,7 +210,13 @@ func (h *AdminWebSocketHandler) HandleAdminWebSocket(c *gin.Context) {
 			"adminId": adminConn.ID,
 		},
 	}
-	conn.WriteJSON(welcomeMsg)
+	adminConn.writer().WriteJSON(welcomeMsg)
+
+	// Si es una reconexión, retomar las sesiones que estaba viendo y el streaming que se pausó en su ausencia
+	h.resumeSessionView(adminConn)
+	if h.clientWSHandler != nil {
+		h.clientWSHandler.ResumeStreamsForAdmin(adminConn.UserID)
+	}
 
 	// Manejar mensajes
 	defer func() {
@@ -131,19 +224,25 @@ func (h *AdminWebSocketHandler) HandleAdminWebSocket(c *gin.Context) {
 		delete(h.adminConnections, adminConn.ID)
 		h.mutex.Unlock()
 		log.Printf("Admin disconnected: %s (%s)", adminConn.Username, adminConn.ID)
+		// Una grabación de macro sin guardar no sobrevive a la última conexión del administrador
+		if h.inputRecorder != nil && !h.IsAdminConnected(adminConn.UserID) {
+			h.inputRecorder.DiscardAdminRecordings(adminConn.UserID)
+		}
 	}()
 
-	// Configurar timeouts
-	conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+	// Configurar timeouts: los pings del servidor mantienen viva la conexión de un dashboard inactivo
+	h.extendAdminPresence(adminConn)
 	conn.SetPongHandler(func(string) error {
-		conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+		h.extendAdminPresence(adminConn)
 		return nil
 	})
+	keepaliveDone := make(chan struct{})
+	defer close(keepaliveDone)
+	go h.runAdminKeepalive(adminConn, keepaliveDone)
 
 	// Loop de lectura de mensajes
 	for {
-		var message dto.WebSocketMessage
-		err := conn.ReadJSON(&message)
+		messageType, payload, err := conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
@@ -153,9 +252,28 @@ func (h *AdminWebSocketHandler) HandleAdminWebSocket(c *gin.Context) {
 
 		// Actualizar último visto
 		adminConn.LastSeen = time.Now()
+		h.extendAdminPresence(adminConn)
+
+		// El AdminWeb solo envía mensajes de control en JSON: los binarios se descartan sin cerrar la conexión
+		if messageType == websocket.BinaryMessage {
+			log.Printf("⚠️ Admin %s sent an unsupported binary message (%d bytes), ignoring", adminConn.Username, len(payload))
+			continue
+		}
 
-		// Procesar mensaje
-		h.handleAdminMessage(adminConn, message)
+		var message dto.WebSocketMessage
+		if err := json.Unmarshal(payload, &message); err != nil {
+			log.Printf("WebSocket error: invalid JSON message: %v", err)
+			break
+		}
+
+		// Procesar mensaje; un panic en el handler cierra solo esta conexión
+		if err := runRecovered("admin message "+message.Type, fmt.Sprintf("admin %s, connection %s", adminConn.Username, adminConn.ID), func() {
+			h.handleAdminMessage(adminConn, message)
+		}); err != nil {
+			closeMessage := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "internal error")
+			conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second))
+			break
+		}
 	}
 }
 
@@ -170,7 +288,7 @@ func (h *AdminWebSocketHandler) handleAdminMessage(adminConn *AdminConnection, m
 				"timestamp": time.Now().Unix(),
 			},
 		}
-		adminConn.Conn.WriteJSON(pongMsg)
+		adminConn.writer().WriteJSON(pongMsg)
 
 	case "get_pc_list":
 		// Solicitar lista actualizada de PCs (esto se puede implementar más tarde)
@@ -180,6 +298,18 @@ func (h *AdminWebSocketHandler) handleAdminMessage(adminConn *AdminConnection, m
 		// Manejar comando de input del administrador
 		h.handleInputCommand(adminConn, message.Data)
 
+	case dto.MessageTypeFrameAck:
+		// Confirmación de frame mostrado, se usa para medir la latencia del enlace
+		h.handleFrameAck(adminConn, message.Data)
+
+	case dto.MessageTypeSetAudio:
+		// Activar o desactivar el audio de la sesión que controla el administrador
+		h.handleSetAudio(adminConn, message.Data)
+
+	case dto.MessageTypeSubscribeNotifications, dto.MessageTypeUnsubscribeNotifications:
+		// Preferencias de los broadcasts de PCs que recibe el administrador
+		h.handleNotificationSubscription(adminConn, message.Type, message.Data)
+
 	default:
 		log.Printf("Unknown message type from admin %s: %s", adminConn.Username, message.Type)
 	}
@@ -187,6 +317,13 @@ func (h *AdminWebSocketHandler) handleAdminMessage(adminConn *AdminConnection, m
 
 // BroadcastPCConnected notifica a todos los administradores que un PC se conectó
 func (h *AdminWebSocketHandler) BroadcastPCConnected(pcID, identifier, ownerUserID, ip string) {
+	if h.batchPCChange(pcID, identifier, ownerUserID, pcDeltaEventConnection, func(change *dto.PCListDeltaChange) {
+		change.IP = ip
+		change.Status = "ONLINE"
+	}) {
+		return
+	}
+
 	notification := dto.WebSocketMessage{
 		Type: "pc_connected",
 		Data: map[string]interface{}{
@@ -200,12 +337,20 @@ func (h *AdminWebSocketHandler) BroadcastPCConnected(pcID, identifier, ownerUser
 		},
 	}
 
-	h.broadcastToAllAdmins(notification)
+	h.broadcastToAllAdmins(notification, pcID)
 	log.Printf("Broadcasted PC connected: %s (%s)", identifier, pcID)
 }
 
 // BroadcastPCDisconnected notifica a todos los administradores que un PC se desconectó
-func (h *AdminWebSocketHandler) BroadcastPCDisconnected(pcID, identifier, ownerUserID string) {
+func (h *AdminWebSocketHandler) BroadcastPCDisconnected(pcID, identifier, ownerUserID string, reason remotesession.DisconnectReason) {
+	if h.batchPCChange(pcID, identifier, ownerUserID, pcDeltaEventDisconnection, func(change *dto.PCListDeltaChange) {
+		change.Status = "OFFLINE"
+		change.CloseCode = reason.CloseCode
+		change.CloseReason = reason.CloseText
+	}) {
+		return
+	}
+
 	notification := dto.WebSocketMessage{
 		Type: "pc_disconnected",
 		Data: map[string]interface{}{
@@ -213,13 +358,16 @@ func (h *AdminWebSocketHandler) BroadcastPCDisconnected(pcID, identifier, ownerU
 			"identifier":  identifier,
 			"ownerUserId": ownerUserID,
 			"status":      "OFFLINE",
+			"closeCode":   reason.CloseCode,
+			"closeReason": reason.CloseText,
+			"cleanClose":  reason.IsClean(),
 			"timestamp":   time.Now().Unix(),
 			"event":       "disconnection",
 		},
 	}
 
-	h.broadcastToAllAdmins(notification)
-	log.Printf("Broadcasted PC disconnected: %s (%s)", identifier, pcID)
+	h.broadcastToAllAdmins(notification, pcID)
+	log.Printf("Broadcasted PC disconnected: %s (%s), %s", identifier, pcID, reason)
 }
 
 // BroadcastPCRegistered notifica a todos los administradores que un PC se registró
@@ -237,30 +385,62 @@ func (h *AdminWebSocketHandler) BroadcastPCRegistered(pcID, identifier, ownerUse
 		},
 	}
 
-	h.broadcastToAllAdmins(notification)
+	h.broadcastToAllAdmins(notification, pcID)
 	log.Printf("Broadcasted PC registered: %s (%s)", identifier, pcID)
 }
 
 // BroadcastPCStatusChanged notifica cambios de estado de PC
-func (h *AdminWebSocketHandler) BroadcastPCStatusChanged(pcID, identifier, oldStatus, newStatus string) {
+func (h *AdminWebSocketHandler) BroadcastPCStatusChanged(pcID, identifier, ownerUserID, oldStatus, newStatus string) {
+	if h.batchPCChange(pcID, identifier, ownerUserID, pcDeltaEventStatusChange, func(change *dto.PCListDeltaChange) {
+		if change.PreviousStatus == "" {
+			change.PreviousStatus = oldStatus
+		}
+		change.Status = newStatus
+	}) {
+		return
+	}
+
 	notification := dto.WebSocketMessage{
 		Type: "pc_status_changed",
 		Data: map[string]interface{}{
-			"pcId":       pcID,
-			"identifier": identifier,
-			"oldStatus":  oldStatus,
-			"newStatus":  newStatus,
-			"timestamp":  time.Now().Unix(),
-			"event":      "status_change",
+			"pcId":        pcID,
+			"identifier":  identifier,
+			"ownerUserId": ownerUserID,
+			"oldStatus":   oldStatus,
+			"newStatus":   newStatus,
+			"timestamp":   time.Now().Unix(),
+			"event":       "status_change",
 		},
 	}
 
-	h.broadcastToAllAdmins(notification)
+	h.broadcastToAllAdmins(notification, pcID)
 	log.Printf("Broadcasted PC status change: %s (%s) %s -> %s", identifier, pcID, oldStatus, newStatus)
 }
 
+// BroadcastCapacityWarning avisa a todos los administradores de que un tipo de conexión alcanzó el umbral de capacidad
+func (h *AdminWebSocketHandler) BroadcastCapacityWarning(kind ConnectionKind, current, max, threshold int) {
+	notification := dto.WebSocketMessage{
+		Type: "admin_capacity_warning",
+		Data: map[string]interface{}{
+			"connectionType": string(kind),
+			"current":        current,
+			"max":            max,
+			"threshold":      threshold,
+			"timestamp":      time.Now().Unix(),
+			"event":          "capacity_warning",
+		},
+	}
+
+	h.broadcastToAllAdmins(notification, "")
+	log.Printf("Broadcasted capacity warning: %d/%d %s connections", current, max, kind)
+}
+
 // BroadcastPCListUpdate notifica que la lista de PCs debe actualizarse
 func (h *AdminWebSocketHandler) BroadcastPCListUpdate() {
+	if h.batchPCListUpdate() {
+		return
+	}
+
 	notification := dto.WebSocketMessage{
 		Type: "pc_list_update",
 		Data: map[string]interface{}{
@@ -270,24 +450,39 @@ func (h *AdminWebSocketHandler) BroadcastPCListUpdate() {
 		},
 	}
 
-	h.broadcastToAllAdmins(notification)
+	h.broadcastToAllAdmins(notification, "")
 	log.Printf("Broadcasted PC list update notification")
 }
 
-// broadcastToAllAdmins envía un mensaje a todos los administradores conectados
-func (h *AdminWebSocketHandler) broadcastToAllAdmins(message dto.WebSocketMessage) {
-	h.mutex.RLock()
-	defer h.mutex.RUnlock()
-
-	for connID, adminConn := range h.adminConnections {
-		err := adminConn.Conn.WriteJSON(message)
+// broadcastToAllAdmins envía un mensaje a los administradores conectados que no silenciaron su tipo. pcID es el PC
+// al que se refiere (vacío si no es de un PC) para los administradores con alcance "owned".
+func (h *AdminWebSocketHandler) broadcastToAllAdmins(message dto.WebSocketMessage, pcID string) {
+	scopes := newAdminPCScopes(h.adminPCIDs)
+	for _, adminConn := range h.adminConnectionList() {
+		if !h.notificationPrefs.allows(adminConn.UserID, message.Type, pcID, scopes) {
+			continue
+		}
+		err := adminConn.writer().WriteJSON(message)
 		if err != nil {
-			log.Printf("Error sending message to admin %s (%s): %v", adminConn.Username, connID, err)
+			log.Printf("Error sending message to admin %s (%s): %v", adminConn.Username, adminConn.ID, err)
 			// La conexión se limpiará en el defer del handler principal
 		}
 	}
 }
 
+// adminConnectionList copia de las conexiones de administradores, para recorrerlas sin mantener el mutex mientras
+// se resuelven sus preferencias
+func (h *AdminWebSocketHandler) adminConnectionList() []*AdminConnection {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	connections := make([]*AdminConnection, 0, len(h.adminConnections))
+	for _, adminConn := range h.adminConnections {
+		connections = append(connections, adminConn)
+	}
+	return connections
+}
+
 // GetConnectedAdmins retorna la lista de administradores conectados
 func (h *AdminWebSocketHandler) GetConnectedAdmins() map[string]*AdminConnection {
 	h.mutex.RLock()
@@ -314,18 +509,41 @@ func (h *AdminWebSocketHandler) SetClientWSHandler(clientHandler *WebSocketHandl
 	h.clientWSHandler = clientHandler
 }
 
+// SetFeatureFlags configura los feature flags que activan comportamientos experimentales (nil = todos activos)
+func (h *AdminWebSocketHandler) SetFeatureFlags(featureFlags featureflagservice.IFeatureFlagService) {
+	h.featureFlags = featureFlags
+}
+
+// SetOutboundBufferConfig configura el buffer de salida de cada conexión de administrador (Size <= 0 lo desactiva)
+func (h *AdminWebSocketHandler) SetOutboundBufferConfig(config OutboundBufferConfig) {
+	h.outboundConfig = config
+}
+
+// SetConnectionCapacity configura el contador compartido que limita las conexiones de administradores
+func (h *AdminWebSocketHandler) SetConnectionCapacity(capacity *ConnectionCapacity) {
+	h.capacity = capacity
+}
+
+// SetInputRateLimit configura el límite de input_command por sesión
+func (h *AdminWebSocketHandler) SetInputRateLimit(config InputRateLimitConfig) {
+	h.inputRate = NewInputRateLimiter(config)
+}
+
+// SetInputCommandRecorder configura el grabador de macros de input
+func (h *AdminWebSocketHandler) SetInputCommandRecorder(recorder InputCommandRecorder) {
+	h.inputRecorder = recorder
+}
+
+// adaptiveFrameRateEnabled indica si el flag adaptive_frame_rate permite descartar frames
+func (h *AdminWebSocketHandler) adaptiveFrameRateEnabled() bool {
+	return h.featureFlags == nil || h.featureFlags.AdaptiveFrameRate()
+}
+
 // handleInputCommand maneja comandos de input de administradores
 func (h *AdminWebSocketHandler) handleInputCommand(adminConn *AdminConnection, data interface{}) {
 	// Parse input command data
-	commandData, err := json.Marshal(data)
-	if err != nil {
-		log.Printf("❌ INPUT COMMAND: Error marshalling command data: %v", err)
-		return
-	}
-
 	var inputCommand dto.InputCommand
-	if err := json.Unmarshal(commandData, &inputCommand); err != nil {
-		log.Printf("❌ INPUT COMMAND: Error unmarshalling input command: %v", err)
+	if !decodePayload(adminConn.writer(), dto.MessageTypeInputCommand, data, &inputCommand) {
 		return
 	}
 
@@ -333,14 +551,22 @@ func (h *AdminWebSocketHandler) handleInputCommand(adminConn *AdminConnection, d
 		adminConn.Username, inputCommand.EventType, inputCommand.Action, inputCommand.SessionID)
 
 	// Validar permisos del administrador para enviar comandos
-	err = h.sessionService.ValidateInputCommandPermission(inputCommand.SessionID, adminConn.UserID)
+	err := h.sessionService.ValidateInputCommandPermission(adminConn.Context(), inputCommand.SessionID, adminConn.UserID)
 	if err != nil {
 		log.Printf("❌ INPUT COMMAND: Invalid permission for admin %s: %v", adminConn.Username, err)
 		return
 	}
 
+	// Descartar el exceso de comandos para no inundar el PC cliente
+	if allowed, dropped := h.inputRate.Allow(inputCommand.SessionID, time.Now()); !allowed {
+		if dropped > 0 {
+			h.warnInputRateLimited(adminConn, inputCommand.SessionID, dropped)
+		}
+		return
+	}
+
 	// Obtener el PC cliente objetivo
-	clientPCID, err := h.sessionService.GetClientPCIDForActiveSession(inputCommand.SessionID)
+	clientPCID, err := h.sessionService.GetClientPCIDForActiveSession(adminConn.Context(), inputCommand.SessionID)
 	if err != nil {
 		log.Printf("❌ INPUT COMMAND: Error getting client PC for session: %v", err)
 		return
@@ -349,18 +575,50 @@ func (h *AdminWebSocketHandler) handleInputCommand(adminConn *AdminConnection, d
 	// Reenviar comando al cliente a través del ClientWebSocketHandler
 	if h.clientWSHandler != nil {
 		err := h.clientWSHandler.SendInputCommandToClient(clientPCID, inputCommand)
-		if err != nil {
+		if errors.Is(err, ErrInputEventNotSupported) {
+			h.rejectUnsupportedInputCommand(adminConn, clientPCID, inputCommand)
+		} else if err != nil {
 			log.Printf("❌ INPUT COMMAND: Error forwarding command to client %s: %v", clientPCID, err)
 		} else {
 			log.Printf("✅ INPUT COMMAND: Command forwarded to client %s", clientPCID)
+			if h.inputRecorder != nil {
+				h.inputRecorder.CaptureCommand(inputCommand.SessionID, macro.Command{
+					EventType: inputCommand.EventType,
+					Action:    inputCommand.Action,
+					Payload:   inputCommand.Payload,
+				})
+			}
 		}
 	} else {
 		log.Printf("⚠️ INPUT COMMAND: No client WebSocket handler available")
 	}
 }
 
+// warnInputRateLimited avisa al administrador de que se están descartando sus comandos de input
+func (h *AdminWebSocketHandler) warnInputRateLimited(adminConn *AdminConnection, sessionID string, dropped int) {
+	log.Printf("⚠️ INPUT COMMAND: Rate limit exceeded for session %s, dropped %d commands from admin %s",
+		sessionID, dropped, adminConn.Username)
+
+	warning := dto.WebSocketMessage{
+		Type: "input_rate_limited",
+		Data: map[string]interface{}{
+			"session_id":          sessionID,
+			"dropped_commands":    dropped,
+			"commands_per_second": h.inputRate.config.CommandsPerSecond,
+			"burst":               h.inputRate.config.Burst,
+			"timestamp":           time.Now().Unix(),
+		},
+	}
+	if err := adminConn.writer().WriteJSON(warning); err != nil {
+		log.Printf("Error sending input rate limit warning to admin %s: %v", adminConn.UserID, err)
+	}
+}
+
 // ForwardScreenFrameToAdmin reenvía un frame de pantalla a un administrador específico
 func (h *AdminWebSocketHandler) ForwardScreenFrameToAdmin(adminUserID string, screenFrame dto.ScreenFrame) error {
+	// Guardar el frame aunque el administrador esté desconectado: se le reenvía al reconectarse
+	h.sessionViews.remember(adminUserID, screenFrame)
+
 	h.mutex.RLock()
 	defer h.mutex.RUnlock()
 
@@ -374,7 +632,12 @@ func (h *AdminWebSocketHandler) ForwardScreenFrameToAdmin(adminUserID string, sc
 	}
 
 	if targetAdmin == nil {
-		return fmt.Errorf("admin %s not connected", adminUserID)
+		return fmt.Errorf("%w: %s", ErrAdminNotConnected, adminUserID)
+	}
+
+	// Descartar el frame si el enlace del administrador está congestionado
+	if h.adaptiveFrameRateEnabled() && !h.frameRate.AllowFrame(screenFrame.SessionID, screenFrame.SequenceNum, time.Now()) {
+		return ErrFrameThrottled
 	}
 
 	// Crear mensaje de frame de pantalla
@@ -384,7 +647,7 @@ func (h *AdminWebSocketHandler) ForwardScreenFrameToAdmin(adminUserID string, sc
 	}
 
 	// Enviar frame al administrador
-	err := targetAdmin.Conn.WriteJSON(frameMessage)
+	err := targetAdmin.writer().WriteJSON(frameMessage)
 	if err != nil {
 		return fmt.Errorf("error sending frame to admin: %w", err)
 	}
@@ -392,16 +655,30 @@ func (h *AdminWebSocketHandler) ForwardScreenFrameToAdmin(adminUserID string, sc
 	return nil
 }
 
+// handleFrameAck registra la confirmación de un frame enviada por el administrador
+func (h *AdminWebSocketHandler) handleFrameAck(adminConn *AdminConnection, data interface{}) {
+	var ack dto.FrameAck
+	if !decodePayload(adminConn.writer(), dto.MessageTypeFrameAck, data, &ack) {
+		return
+	}
+	if ack.SessionID == "" {
+		log.Printf("❌ FRAME ACK: Invalid ack from admin %s", adminConn.Username)
+		return
+	}
+
+	h.frameRate.RecordAck(ack.SessionID, ack.SequenceNum, time.Now())
+}
+
 // SendInputCommandToClientByAdmin permite que un administrador envíe comandos de input (método alternativo)
-func (h *AdminWebSocketHandler) SendInputCommandToClientByAdmin(adminUserID, sessionID string, inputCommand dto.InputCommand) error {
+func (h *AdminWebSocketHandler) SendInputCommandToClientByAdmin(ctx context.Context, adminUserID, sessionID string, inputCommand dto.InputCommand) error {
 	// Validar permisos del administrador
-	err := h.sessionService.ValidateInputCommandPermission(sessionID, adminUserID)
+	err := h.sessionService.ValidateInputCommandPermission(ctx, sessionID, adminUserID)
 	if err != nil {
 		return fmt.Errorf("invalid permission: %w", err)
 	}
 
 	// Obtener el PC cliente objetivo
-	clientPCID, err := h.sessionService.GetClientPCIDForActiveSession(sessionID)
+	clientPCID, err := h.sessionService.GetClientPCIDForActiveSession(ctx, sessionID)
 	if err != nil {
 		return fmt.Errorf("error getting client PC: %w", err)
 	}
@@ -414,10 +691,21 @@ func (h *AdminWebSocketHandler) SendInputCommandToClientByAdmin(adminUserID, ses
 	return fmt.Errorf("client WebSocket handler not available")
 }
 
+// SendMacroCommand reenvía al cliente un comando de una macro reproducida, validando el permiso de input
+func (h *AdminWebSocketHandler) SendMacroCommand(ctx context.Context, adminUserID, sessionID string, command macro.Command) error {
+	return h.SendInputCommandToClientByAdmin(ctx, adminUserID, sessionID, dto.InputCommand{
+		SessionID: sessionID,
+		Timestamp: time.Now().UnixMilli(),
+		EventType: command.EventType,
+		Action:    command.Action,
+		Payload:   command.Payload,
+	})
+}
+
 // NotifySessionAccepted notifica al administrador que una sesión fue aceptada
-func (h *AdminWebSocketHandler) NotifySessionAccepted(sessionID string) error {
+func (h *AdminWebSocketHandler) NotifySessionAccepted(ctx context.Context, sessionID string) error {
 	// Obtener información de la sesión
-	session, err := h.sessionService.GetSessionById(sessionID)
+	session, err := h.sessionService.GetSessionById(ctx, sessionID)
 	if err != nil {
 		return fmt.Errorf("error getting session: %w", err)
 	}
@@ -441,30 +729,90 @@ func (h *AdminWebSocketHandler) NotifySessionAccepted(sessionID string) error {
 	}
 
 	// Crear mensaje de notificación
+	data := map[string]interface{}{
+		"session_id":   sessionID,
+		"client_pc_id": session.ClientPCID(),
+		"status":       string(session.Status()),
+		"start_time":   session.StartTime(),
+		"message":      "Client accepted remote control session",
+		"timestamp":    time.Now().Unix(),
+	}
+	// Tipos de input que admite el PC, si los declaró, para que la UI no ofrezca los demás
+	if h.clientWSHandler != nil {
+		if supported, declared := h.clientWSHandler.SupportedInputEvents(session.ClientPCID()); declared {
+			data["supported_input_events"] = supported
+		}
+	}
 	notification := dto.WebSocketMessage{
 		Type: "session_accepted",
+		Data: data,
+	}
+
+	// Enviar notificación
+	err = adminConn.writer().WriteJSON(notification)
+	if err != nil {
+		return fmt.Errorf("error sending notification to admin: %w", err)
+	}
+
+	log.Printf("✅ ADMIN NOTIFICATION: Session %s acceptance sent to admin %s", sessionID, session.AdminUserID())
+	return nil
+}
+
+// NotifySessionRejected notifica al administrador que el cliente rechazó una sesión
+func (h *AdminWebSocketHandler) NotifySessionRejected(ctx context.Context, sessionID, reason string) error {
+	// Obtener información de la sesión
+	session, err := h.sessionService.GetSessionById(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("error getting session: %w", err)
+	}
+	if session == nil {
+		return fmt.Errorf("session not found")
+	}
+
+	// Buscar la conexión del administrador por UserID
+	h.mutex.RLock()
+	var adminConn *AdminConnection
+	for _, conn := range h.adminConnections {
+		if conn.UserID == session.AdminUserID() {
+			adminConn = conn
+			break
+		}
+	}
+	h.mutex.RUnlock()
+
+	if adminConn == nil {
+		return fmt.Errorf("admin user %s not connected", session.AdminUserID())
+	}
+
+	// Crear mensaje de notificación
+	notification := dto.WebSocketMessage{
+		Type: "session_rejected",
 		Data: map[string]interface{}{
 			"session_id":   sessionID,
 			"client_pc_id": session.ClientPCID(),
 			"status":       string(session.Status()),
-			"start_time":   session.StartTime(),
-			"message":      "Client accepted remote control session",
+			"reason":       reason,
+			"message":      "Client rejected remote control session",
 			"timestamp":    time.Now().Unix(),
 		},
 	}
 
 	// Enviar notificación
-	err = adminConn.Conn.WriteJSON(notification)
+	err = adminConn.writer().WriteJSON(notification)
 	if err != nil {
 		return fmt.Errorf("error sending notification to admin: %w", err)
 	}
 
-	log.Printf("✅ ADMIN NOTIFICATION: Session %s acceptance sent to admin %s", sessionID, session.AdminUserID())
+	log.Printf("✅ ADMIN NOTIFICATION: Session %s rejection sent to admin %s", sessionID, session.AdminUserID())
 	return nil
 }
 
 // NotifySessionEnded notifica al administrador que una sesión terminó
 func (h *AdminWebSocketHandler) NotifySessionEnded(sessionID, clientPCID, adminUserID string) error {
+	h.frameRate.Forget(sessionID)
+	h.inputRate.Forget(sessionID)
+	h.sessionViews.forgetSession(sessionID)
+
 	// Buscar la conexión del administrador por UserID
 	h.mutex.RLock()
 	var adminConn *AdminConnection
@@ -495,7 +843,7 @@ func (h *AdminWebSocketHandler) NotifySessionEnded(sessionID, clientPCID, adminU
 	}
 
 	// Enviar notificación
-	err := adminConn.Conn.WriteJSON(notification)
+	err := adminConn.writer().WriteJSON(notification)
 	if err != nil {
 		return fmt.Errorf("error sending session ended notification to admin: %w", err)
 	}
@@ -503,3 +851,172 @@ func (h *AdminWebSocketHandler) NotifySessionEnded(sessionID, clientPCID, adminU
 	log.Printf("✅ ADMIN NOTIFICATION: Session %s ended notification sent to admin %s", sessionID, adminUserID)
 	return nil
 }
+
+// IsAdminConnected indica si el administrador tiene al menos un panel conectado
+func (h *AdminWebSocketHandler) IsAdminConnected(adminUserID string) bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for _, conn := range h.adminConnections {
+		if conn.UserID == adminUserID {
+			return true
+		}
+	}
+	return false
+}
+
+// NotifySessionOwnershipTransferred avisa al administrador saliente y al entrante de que la sesión cambió de manos
+func (h *AdminWebSocketHandler) NotifySessionOwnershipTransferred(sessionID, clientPCID, fromAdminID, toAdminID string) {
+	// El control de frame rate se basaba en los ACKs del administrador anterior
+	h.frameRate.Forget(sessionID)
+	h.sessionViews.forget(fromAdminID, sessionID)
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for _, adminConn := range h.adminConnections {
+		if adminConn.UserID != fromAdminID && adminConn.UserID != toAdminID {
+			continue
+		}
+
+		notification := dto.WebSocketMessage{
+			Type: "session_ownership_transferred",
+			Data: map[string]interface{}{
+				"session_id":    sessionID,
+				"client_pc_id":  clientPCID,
+				"from_admin_id": fromAdminID,
+				"to_admin_id":   toAdminID,
+				"is_new_owner":  adminConn.UserID == toAdminID,
+				"timestamp":     time.Now().Unix(),
+			},
+		}
+		if err := adminConn.writer().WriteJSON(notification); err != nil {
+			log.Printf("Error sending session transfer notification to admin %s: %v", adminConn.UserID, err)
+		}
+	}
+
+	log.Printf("🔀 ADMIN NOTIFICATION: Session %s transferred from admin %s to admin %s", sessionID, fromAdminID, toAdminID)
+}
+
+// NotifySessionQueueExpired notifica al administrador que su solicitud en cola caducó sin que el PC se conectara
+func (h *AdminWebSocketHandler) NotifySessionQueueExpired(sessionID, clientPCID, adminUserID string) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	notification := dto.WebSocketMessage{
+		Type: "session_queue_expired",
+		Data: map[string]interface{}{
+			"session_id":   sessionID,
+			"client_pc_id": clientPCID,
+			"status":       string(remotesession.StatusFailed),
+			"message":      "Client PC did not come online before the queued request expired",
+			"timestamp":    time.Now().Unix(),
+		},
+	}
+
+	for _, adminConn := range h.adminConnections {
+		if adminConn.UserID == adminUserID {
+			if err := adminConn.writer().WriteJSON(notification); err != nil {
+				log.Printf("Error sending queue expiration notification to admin %s: %v", adminUserID, err)
+			}
+		}
+	}
+
+	log.Printf("⌛ ADMIN NOTIFICATION: Queued session %s for PC %s expired", sessionID, clientPCID)
+}
+
+// NotifyStreamNotStarting avisa al administrador de que el cliente aceptó la sesión pero no envió ningún frame
+func (h *AdminWebSocketHandler) NotifyStreamNotStarting(adminUserID, sessionID, clientPCID string, waited time.Duration, sessionEnded bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	notification := dto.WebSocketMessage{
+		Type: "stream_not_starting",
+		Data: map[string]interface{}{
+			"session_id":     sessionID,
+			"client_pc_id":   clientPCID,
+			"waited_seconds": int(waited.Seconds()),
+			"session_ended":  sessionEnded,
+			"message":        "Client PC accepted the session but has not sent any screen frames",
+			"timestamp":      time.Now().Unix(),
+		},
+	}
+
+	for _, adminConn := range h.adminConnections {
+		if adminConn.UserID == adminUserID {
+			if err := adminConn.writer().WriteJSON(notification); err != nil {
+				log.Printf("Error sending stream not starting notification to admin %s: %v", adminUserID, err)
+			}
+		}
+	}
+
+	log.Printf("⏱️ ADMIN NOTIFICATION: Session %s on PC %s has not started streaming", sessionID, clientPCID)
+}
+
+// NotifyClientActivity avisa al administrador que controla la sesión de que el usuario del PC pasó a activo o inactivo
+func (h *AdminWebSocketHandler) NotifyClientActivity(activity remotesessionservice.SessionActivity) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	notification := dto.WebSocketMessage{
+		Type: dto.MessageTypeClientActivity,
+		Data: map[string]interface{}{
+			"session_id":             activity.SessionID,
+			"client_pc_id":           activity.ClientPCID,
+			"status":                 strings.ToLower(string(activity.State)),
+			"last_input_age_seconds": int64(activity.LastInputAge.Seconds()),
+			"idle_since":             idleSince(activity),
+			"timestamp":              time.Now().Unix(),
+		},
+	}
+
+	for _, adminConn := range h.adminConnections {
+		if adminConn.UserID == activity.AdminUserID {
+			if err := adminConn.writer().WriteJSON(notification); err != nil {
+				log.Printf("Error sending client activity notification to admin %s: %v", activity.AdminUserID, err)
+			}
+		}
+	}
+}
+
+// idleSince momento (unix) de la última entrada del usuario si está inactivo; nil si está activo
+func idleSince(activity remotesessionservice.SessionActivity) interface{} {
+	if activity.State != remotesessionservice.ClientActivityIdle {
+		return nil
+	}
+	return activity.ChangedAt.Unix()
+}
+
+// NotifyStorageQuotaExceeded notifica al administrador que una grabación fue rechazada por cuota de almacenamiento
+func (h *AdminWebSocketHandler) NotifyStorageQuotaExceeded(adminUserID, sessionID string, usage *storagequotaservice.ClientStorageUsage) {
+	if usage == nil {
+		return
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	notification := dto.WebSocketMessage{
+		Type: "storage_quota_exceeded",
+		Data: map[string]interface{}{
+			"session_id":    sessionID,
+			"client_pc_id":  usage.ClientPCID,
+			"recordings_mb": usage.RecordingsMB,
+			"transfers_mb":  usage.TransfersMB,
+			"total_mb":      usage.TotalMB,
+			"quota_mb":      usage.QuotaMB,
+			"message":       "Client storage quota exceeded, recording rejected",
+			"timestamp":     time.Now().Unix(),
+		},
+	}
+
+	for _, adminConn := range h.adminConnections {
+		if adminConn.UserID == adminUserID {
+			if err := adminConn.writer().WriteJSON(notification); err != nil {
+				log.Printf("Error sending storage quota notification to admin %s: %v", adminUserID, err)
+			}
+		}
+	}
+
+	log.Printf("⚠️ ADMIN NOTIFICATION: Storage quota exceeded for client %s (%.2f/%.2f MB)", usage.ClientPCID, usage.TotalMB, usage.QuotaMB)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+const testAdminUserID = "admin-id"
+
+// connectTestAdmin registra en el handler una conexión real con un administrador simulado y devuelve el lado del administrador
+func connectTestAdmin(t *testing.T, h *AdminWebSocketHandler) *websocket.Conn {
+	t.Helper()
+	return connectTestAdminAs(t, h, "conn-1", testAdminUserID)
+}
+
+// connectTestAdminAs registra una conexión de administrador connID del usuario adminUserID
+func connectTestAdminAs(t *testing.T, h *AdminWebSocketHandler, connID, adminUserID string) *websocket.Conn {
+	t.Helper()
+
+	serverConns := make(chan *websocket.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := h.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		serverConns <- conn
+	}))
+	t.Cleanup(server.Close)
+
+	adminSide, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { adminSide.Close() })
+
+	// Cerrar el extremo del servidor al terminar también lo mantiene vivo: sin referencias, el GC lo cerraría
+	serverConn := <-serverConns
+	t.Cleanup(func() { serverConn.Close() })
+
+	h.adminConnections[connID] = &AdminConnection{
+		ID:     connID,
+		UserID: adminUserID,
+		IsAuth: true,
+		Conn:   serverConn,
+	}
+	return adminSide
+}
+
+// congestAdminLink simula un ack muy tardío y reenvía un frame para que el siguiente quede dentro del intervalo
+func congestAdminLink(h *AdminWebSocketHandler) {
+	now := time.Now()
+	h.frameRate.AllowFrame(testFrameSessionID, 1, now.Add(-2*time.Second))
+	h.frameRate.RecordAck(testFrameSessionID, 1, now)
+	h.frameRate.AllowFrame(testFrameSessionID, 2, now)
+}
+
+func TestForwardScreenFrameToAdmin_CongestedLinkThrottlesFrames(t *testing.T) {
+	// Arrange
+	h := NewAdminWebSocketHandler(nil, nil)
+	h.SetFeatureFlags(featureFlagsFromEnv(nil))
+	connectTestAdmin(t, h)
+	congestAdminLink(h)
+
+	// Act
+	err := h.ForwardScreenFrameToAdmin(testAdminUserID, dto.ScreenFrame{SessionID: testFrameSessionID, SequenceNum: 3})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrFrameThrottled)
+}
+
+func TestForwardScreenFrameToAdmin_AdaptiveFrameRateDisabledForwardsEveryFrame(t *testing.T) {
+	// Arrange
+	h := NewAdminWebSocketHandler(nil, nil)
+	h.SetFeatureFlags(featureFlagsFromEnv(map[string]string{"FEATURE_ADAPTIVE_FRAME_RATE": "false"}))
+	adminSide := connectTestAdmin(t, h)
+	congestAdminLink(h)
+
+	// Act
+	err := h.ForwardScreenFrameToAdmin(testAdminUserID, dto.ScreenFrame{SessionID: testFrameSessionID, SequenceNum: 3})
+
+	// Assert
+	require.NoError(t, err)
+
+	var message dto.WebSocketMessage
+	require.NoError(t, adminSide.ReadJSON(&message))
+	assert.Equal(t, dto.MessageTypeScreenFrame, message.Type)
+}
+
+func TestHandleAdminMessage_MalformedInputCommandReportsError(t *testing.T) {
+	// Arrange - sessionService nil: si el payload se aceptara, el handler fallaría al validar permisos
+	h := NewAdminWebSocketHandler(nil, nil)
+	adminSide := connectTestAdmin(t, h)
+
+	// Act
+	h.handleAdminMessage(h.adminConnections["conn-1"], dto.WebSocketMessage{
+		Type: dto.MessageTypeInputCommand,
+		Data: map[string]interface{}{"session_id": 42, "event_type": "mouse"},
+	})
+
+	// Assert
+	var message dto.WebSocketMessage
+	require.NoError(t, adminSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, adminSide.ReadJSON(&message))
+	assert.Equal(t, dto.MessageTypeMalformedPayload, message.Type)
+	data := message.Data.(map[string]interface{})
+	assert.Equal(t, dto.MessageTypeInputCommand, data["message_type"])
+	assert.NotEmpty(t, data["error"])
+}
@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// ErrAudioUnavailable el PC de la sesión no está conectado o no negoció CapabilityAudioStream
+var ErrAudioUnavailable = errors.New("audio stream not available for this session")
+
+// MaxAudioChunkBytes tamaño máximo de un audio_chunk; los mayores se descartan
+const MaxAudioChunkBytes = 256 * 1024
+
+// audioDropLogInterval como mucho se registra un descarte de audio por PC en este intervalo; el resto se cuenta
+const audioDropLogInterval = 5 * time.Second
+
+// Motivos enviados al administrador en audio_status
+const (
+	AudioStatusReasonNotSupported = "AUDIO_NOT_SUPPORTED"
+	AudioStatusReasonClientError  = "CLIENT_UNREACHABLE"
+)
+
+// audioStream estado del audio de una sesión activada con set_audio
+type audioStream struct {
+	clientPCID string
+	// lastClientSeq último sequence_num del cliente aceptado, para descartar duplicados y desordenados
+	lastClientSeq int64
+	// nextSeq numeración propia del audio reenviado al administrador
+	nextSeq int64
+	// adminUserID administrador que controla la sesión, resuelto con el primer chunk; vacío hasta entonces
+	adminUserID string
+}
+
+// audioDrops descartes de audio de un PC desde el último que se registró
+type audioDrops struct {
+	count    int
+	loggedAt time.Time
+}
+
+// audioStreams sesiones con el audio activado indexadas por sessionID
+type audioStreams struct {
+	sessions map[string]*audioStream
+	drops    map[string]*audioDrops
+	mutex    sync.Mutex
+}
+
+func newAudioStreams() *audioStreams {
+	return &audioStreams{sessions: make(map[string]*audioStream), drops: make(map[string]*audioDrops)}
+}
+
+// enable activa el audio de la sesión; reactivarlo reinicia la numeración
+func (a *audioStreams) enable(sessionID, clientPCID string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.sessions[sessionID] = &audioStream{clientPCID: clientPCID}
+}
+
+// disable desactiva el audio de la sesión; true si estaba activado
+func (a *audioStreams) disable(sessionID string) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	_, exists := a.sessions[sessionID]
+	delete(a.sessions, sessionID)
+	return exists
+}
+
+// isEnabled indica si el audio de la sesión está activado para el PC
+func (a *audioStreams) isEnabled(sessionID, clientPCID string) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	stream, exists := a.sessions[sessionID]
+	return exists && stream.clientPCID == clientPCID
+}
+
+// admin retorna el administrador cacheado de la sesión; vacío si aún no se resolvió o el audio no está activado
+func (a *audioStreams) admin(sessionID, clientPCID string) string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	stream, exists := a.sessions[sessionID]
+	if !exists || stream.clientPCID != clientPCID {
+		return ""
+	}
+	return stream.adminUserID
+}
+
+// setAdmin cachea el administrador de la sesión para no consultarlo en cada chunk
+func (a *audioStreams) setAdmin(sessionID, clientPCID, adminUserID string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if stream, exists := a.sessions[sessionID]; exists && stream.clientPCID == clientPCID {
+		stream.adminUserID = adminUserID
+	}
+}
+
+// forgetAdmin olvida el administrador cacheado (p. ej. al transferir la sesión); el siguiente chunk lo vuelve a
+// resolver y valida de nuevo la sesión
+func (a *audioStreams) forgetAdmin(sessionID string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if stream, exists := a.sessions[sessionID]; exists {
+		stream.adminUserID = ""
+	}
+}
+
+// disablePC desactiva el audio de todas las sesiones del PC al desconectarse; retorna cuántas tenía activadas
+func (a *audioStreams) disablePC(clientPCID string) int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	disabled := 0
+	for sessionID, stream := range a.sessions {
+		if stream.clientPCID == clientPCID {
+			delete(a.sessions, sessionID)
+			disabled++
+		}
+	}
+	delete(a.drops, clientPCID)
+	return disabled
+}
+
+// logDrop registra el descarte de un chunk del PC como mucho una vez cada audioDropLogInterval, con el número de
+// chunks descartados desde el último registro; a 50 chunks por segundo un log por chunk inunda la salida
+func (a *audioStreams) logDrop(clientPCID, format string, args ...interface{}) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	drops, exists := a.drops[clientPCID]
+	if !exists {
+		drops = &audioDrops{}
+		a.drops[clientPCID] = drops
+	}
+	drops.count++
+	now := time.Now()
+	if !drops.loggedAt.IsZero() && now.Sub(drops.loggedAt) < audioDropLogInterval {
+		return
+	}
+	log.Printf("⏭️ AUDIO: "+format+" (%d chunks dropped from PC %s since last report)", append(args, drops.count, clientPCID)...)
+	drops.count = 0
+	drops.loggedAt = now
+}
+
+// next acepta el chunk clientSeq del PC y retorna el número de secuencia con el que se reenvía; false si el audio
+// no está activado o el chunk está duplicado o llegó desordenado
+func (a *audioStreams) next(sessionID, clientPCID string, clientSeq int64) (int64, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	stream, exists := a.sessions[sessionID]
+	if !exists || stream.clientPCID != clientPCID {
+		return 0, false
+	}
+	if stream.nextSeq > 0 && clientSeq <= stream.lastClientSeq {
+		return 0, false
+	}
+	stream.lastClientSeq = clientSeq
+	stream.nextSeq++
+	return stream.nextSeq, true
+}
+
+// handleAudioChunk maneja los fragmentos de audio recibidos de clientes
+func (h *WebSocketHandler) handleAudioChunk(conn messageWriter, clientConn *ClientConnection, data interface{}) {
+	var audioChunk dto.AudioChunk
+	if !decodePayload(conn, dto.MessageTypeAudioChunk, data, &audioChunk) {
+		return
+	}
+
+	h.processAudioChunk(clientConn, audioChunk)
+}
+
+// processAudioChunk valida y reenvía al administrador un fragmento de audio recibido por JSON o en binario. Sigue
+// el camino de los frames de pantalla, pero el audio es opcional: sin la capacidad negociada o sin set_audio
+// activo el chunk se descarta. La sesión y su administrador se validan con el primer chunk y se cachean en el
+// audioStream hasta que el audio se desactiva, la sesión se transfiere o el PC se desconecta.
+func (h *WebSocketHandler) processAudioChunk(clientConn *ClientConnection, audioChunk dto.AudioChunk) {
+	if !clientConn.IsAuth {
+		log.Printf("❌ AUDIO: Unauthorized client attempted to send audio")
+		return
+	}
+
+	if clientConn.PCID == "" {
+		log.Printf("❌ AUDIO: Client not registered, cannot process audio")
+		return
+	}
+
+	if !clientConn.audioStream {
+		log.Printf("❌ AUDIO: Ignoring audio from PC %s, audio stream not negotiated", clientConn.PCID)
+		return
+	}
+
+	if len(audioChunk.AudioData) == 0 || len(audioChunk.AudioData) > MaxAudioChunkBytes {
+		h.audioStreams.logDrop(clientConn.PCID, "Dropping chunk %d with %d bytes", audioChunk.SequenceNum, len(audioChunk.AudioData))
+		return
+	}
+
+	if !h.audioStreams.isEnabled(audioChunk.SessionID, clientConn.PCID) {
+		h.audioStreams.logDrop(clientConn.PCID, "Dropping chunk %d, audio disabled for session %s", audioChunk.SequenceNum, audioChunk.SessionID)
+		return
+	}
+
+	adminUserID := h.audioStreams.admin(audioChunk.SessionID, clientConn.PCID)
+	if adminUserID == "" {
+		// Validar que la sesión está activa y el PC tiene permisos
+		err := h.sessionService.ValidateStreamingPermission(clientConn.Context(), audioChunk.SessionID, clientConn.PCID)
+		if err != nil {
+			log.Printf("❌ AUDIO: Invalid streaming permission: %v", err)
+			return
+		}
+
+		// Obtener el administrador que está controlando esta sesión
+		adminUserID, err = h.sessionService.GetAdminUserIDForActiveSession(clientConn.Context(), audioChunk.SessionID)
+		if err != nil {
+			log.Printf("❌ AUDIO: Error getting admin for session: %v", err)
+			return
+		}
+		h.audioStreams.setAdmin(audioChunk.SessionID, clientConn.PCID, adminUserID)
+	}
+
+	sequenceNum, ok := h.audioStreams.next(audioChunk.SessionID, clientConn.PCID, audioChunk.SequenceNum)
+	if !ok {
+		h.audioStreams.logDrop(clientConn.PCID, "Dropping duplicate or out of order chunk %d", audioChunk.SequenceNum)
+		return
+	}
+
+	if h.adminWSHandler == nil {
+		log.Printf("⚠️ AUDIO: No admin WebSocket handler available")
+		return
+	}
+
+	err := h.adminWSHandler.ForwardAudioToAdmin(adminUserID, dto.AudioStream{
+		SessionID:   audioChunk.SessionID,
+		Timestamp:   audioChunk.Timestamp,
+		Codec:       audioChunk.Codec,
+		SampleRate:  audioChunk.SampleRate,
+		Channels:    audioChunk.Channels,
+		AudioData:   audioChunk.AudioData,
+		SequenceNum: sequenceNum,
+	})
+	if err != nil {
+		// El streaming de pantalla se encarga de pausar la sesión si el administrador no está conectado; el
+		// siguiente chunk vuelve a resolver el administrador
+		h.audioStreams.forgetAdmin(audioChunk.SessionID)
+		h.audioStreams.logDrop(clientConn.PCID, "Error forwarding chunk %d to admin %s: %v", sequenceNum, adminUserID, err)
+	}
+}
+
+// SetSessionAudio activa o desactiva el audio de la sesión y se lo comunica al PC cliente
+func (h *WebSocketHandler) SetSessionAudio(clientPCID, sessionID string, enabled bool) error {
+	h.mutex.RLock()
+	clientConn, exists := h.pcConnections[clientPCID]
+	h.mutex.RUnlock()
+
+	if !exists || !clientConn.audioStream {
+		h.audioStreams.disable(sessionID)
+		return fmt.Errorf("%w: PC %s", ErrAudioUnavailable, clientPCID)
+	}
+
+	if enabled {
+		h.audioStreams.enable(sessionID, clientPCID)
+	} else {
+		h.audioStreams.disable(sessionID)
+	}
+
+	message := dto.WebSocketMessage{
+		Type: dto.MessageTypeSetAudio,
+		Data: dto.SetAudio{SessionID: sessionID, Enabled: enabled},
+	}
+	if err := clientConn.writer().WriteJSON(message); err != nil {
+		h.audioStreams.disable(sessionID)
+		return fmt.Errorf("error sending set_audio to PC %s: %w", clientPCID, err)
+	}
+
+	log.Printf("🔊 AUDIO: Audio enabled=%t for session %s on PC %s", enabled, sessionID, clientPCID)
+	return nil
+}
+
+// handleSetAudio procesa set_audio del administrador que controla la sesión y le responde con audio_status
+func (h *AdminWebSocketHandler) handleSetAudio(adminConn *AdminConnection, data interface{}) {
+	var setAudio dto.SetAudio
+	if !decodePayload(adminConn.writer(), dto.MessageTypeSetAudio, data, &setAudio) {
+		return
+	}
+
+	// Solo el administrador que controla la sesión puede activar su audio
+	err := h.sessionService.ValidateInputCommandPermission(adminConn.Context(), setAudio.SessionID, adminConn.UserID)
+	if err != nil {
+		log.Printf("❌ AUDIO: Invalid permission for admin %s: %v", adminConn.Username, err)
+		return
+	}
+
+	clientPCID, err := h.sessionService.GetClientPCIDForActiveSession(adminConn.Context(), setAudio.SessionID)
+	if err != nil {
+		log.Printf("❌ AUDIO: Error getting client PC for session: %v", err)
+		return
+	}
+
+	status := dto.AudioStatus{SessionID: setAudio.SessionID, Enabled: setAudio.Enabled, Available: true}
+	if h.clientWSHandler == nil {
+		status.Enabled, status.Available, status.Reason = false, false, AudioStatusReasonClientError
+	} else if err := h.clientWSHandler.SetSessionAudio(clientPCID, setAudio.SessionID, setAudio.Enabled); err != nil {
+		log.Printf("❌ AUDIO: Cannot set audio for session %s: %v", setAudio.SessionID, err)
+		status.Enabled, status.Available, status.Reason = false, false, AudioStatusReasonClientError
+		if errors.Is(err, ErrAudioUnavailable) {
+			status.Reason = AudioStatusReasonNotSupported
+		}
+	}
+
+	message := dto.WebSocketMessage{Type: dto.MessageTypeAudioStatus, Data: status}
+	if err := adminConn.writer().WriteJSON(message); err != nil {
+		log.Printf("❌ AUDIO: Error sending audio_status to admin %s: %v", adminConn.Username, err)
+	}
+}
+
+// ForwardAudioToAdmin reenvía un fragmento de audio a un administrador específico. No se guarda para la
+// reconexión como los frames: el audio perdido no tiene sentido reproducirlo después. El envío pasa por el buffer
+// de salida del administrador, que descarta audio_stream como los screen_frame si el consumidor es lento.
+func (h *AdminWebSocketHandler) ForwardAudioToAdmin(adminUserID string, audio dto.AudioStream) error {
+	h.mutex.RLock()
+	var targetAdmin *AdminConnection
+	for _, adminConn := range h.adminConnections {
+		if adminConn.UserID == adminUserID {
+			targetAdmin = adminConn
+			break
+		}
+	}
+	h.mutex.RUnlock()
+
+	if targetAdmin == nil {
+		return fmt.Errorf("%w: %s", ErrAdminNotConnected, adminUserID)
+	}
+
+	message := dto.WebSocketMessage{
+		Type: dto.MessageTypeAudioStream,
+		Data: audio,
+	}
+	if err := targetAdmin.writer().WriteJSON(message); err != nil {
+		return fmt.Errorf("error sending audio to admin: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// newTestAudioHandlers handlers de cliente y administrador enlazados, con un cliente que negoció el audio y un
+// administrador conectado que controla la sesión
+func newTestAudioHandlers(t *testing.T, sessionPCID string) (*WebSocketHandler, *ClientConnection, *websocket.Conn, *websocket.Conn, *remotesession.RemoteSession) {
+	t.Helper()
+
+	h, _, session := newTestRecordingHandler(t, sessionPCID)
+	h.adminWSHandler = NewAdminWebSocketHandler(nil, h.sessionService)
+	h.adminWSHandler.SetClientWSHandler(h)
+	clientSide, clientConn := connectTestClient(t, h)
+	clientConn.audioStream = true
+	adminSide := connectTestAdmin(t, h.adminWSHandler)
+	return h, clientConn, clientSide, adminSide, session
+}
+
+func testAudioChunk(sessionID string, sequenceNum int64) dto.AudioChunk {
+	return dto.AudioChunk{
+		SessionID:   sessionID,
+		Codec:       "opus",
+		SampleRate:  48000,
+		Channels:    2,
+		AudioData:   []byte{0x4f, 0x67, 0x67, 0x53},
+		SequenceNum: sequenceNum,
+	}
+}
+
+func TestHandleSetAudio_EnablesAudioAndForwardsChunksWithOwnSequence(t *testing.T) {
+	// Arrange
+	h, clientConn, clientSide, adminSide, session := newTestAudioHandlers(t, testTargetPCID)
+	adminConn := h.adminWSHandler.adminConnections["conn-1"]
+
+	// Act
+	h.adminWSHandler.handleSetAudio(adminConn, map[string]interface{}{"session_id": session.SessionID(), "enabled": true})
+
+	// Assert - el cliente recibe set_audio y el administrador la confirmación
+	message := readClientStreamControl(t, clientSide)
+	assert.Equal(t, dto.MessageTypeSetAudio, message.Type)
+	assert.Equal(t, map[string]interface{}{"session_id": session.SessionID(), "enabled": true}, message.Data)
+
+	status := readAdminMessage(t, adminSide)
+	require.Equal(t, dto.MessageTypeAudioStatus, status.Type)
+	statusData := status.Data.(map[string]interface{})
+	assert.Equal(t, true, statusData["enabled"])
+	assert.Equal(t, true, statusData["available"])
+
+	// Los chunks se renumeran: el duplicado se descarta y la secuencia del audio empieza en 1
+	h.processAudioChunk(clientConn, testAudioChunk(session.SessionID(), 40))
+	h.processAudioChunk(clientConn, testAudioChunk(session.SessionID(), 40))
+	h.processAudioChunk(clientConn, testAudioChunk(session.SessionID(), 41))
+
+	for _, expectedSeq := range []float64{1, 2} {
+		forwarded := readAdminMessage(t, adminSide)
+		require.Equal(t, dto.MessageTypeAudioStream, forwarded.Type)
+		data := forwarded.Data.(map[string]interface{})
+		assert.Equal(t, expectedSeq, data["sequence_num"])
+		assert.Equal(t, session.SessionID(), data["session_id"])
+		assert.Equal(t, "opus", data["codec"])
+	}
+	assertNoClientMessage(t, adminSide)
+}
+
+func TestProcessAudioChunk_DropsChunksThatAreNotAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		arrange func(h *WebSocketHandler, clientConn *ClientConnection, sessionID string)
+	}{
+		{
+			name:    "audio not enabled",
+			arrange: func(h *WebSocketHandler, clientConn *ClientConnection, sessionID string) {},
+		},
+		{
+			name: "capability not negotiated",
+			arrange: func(h *WebSocketHandler, clientConn *ClientConnection, sessionID string) {
+				h.audioStreams.enable(sessionID, clientConn.PCID)
+				clientConn.audioStream = false
+			},
+		},
+		{
+			name: "audio disabled again",
+			arrange: func(h *WebSocketHandler, clientConn *ClientConnection, sessionID string) {
+				h.audioStreams.enable(sessionID, clientConn.PCID)
+				h.audioStreams.disable(sessionID)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h, clientConn, _, adminSide, session := newTestAudioHandlers(t, testTargetPCID)
+			tt.arrange(h, clientConn, session.SessionID())
+
+			// Act
+			h.processAudioChunk(clientConn, testAudioChunk(session.SessionID(), 1))
+
+			// Assert
+			assertNoClientMessage(t, adminSide)
+		})
+	}
+}
+
+func TestProcessAudioChunk_StreamingPermissionDeniedIsNotForwarded(t *testing.T) {
+	// Arrange - la sesión pertenece a otro PC, aunque el audio se activara para este
+	h, clientConn, _, adminSide, session := newTestAudioHandlers(t, "other-pc")
+	h.audioStreams.enable(session.SessionID(), clientConn.PCID)
+
+	// Act
+	h.processAudioChunk(clientConn, testAudioChunk(session.SessionID(), 1))
+
+	// Assert
+	assertNoClientMessage(t, adminSide)
+}
+
+func TestHandleSetAudio_ClientWithoutCapabilityReportsUnavailable(t *testing.T) {
+	// Arrange
+	h, clientConn, clientSide, adminSide, session := newTestAudioHandlers(t, testTargetPCID)
+	clientConn.audioStream = false
+
+	// Act
+	h.adminWSHandler.handleSetAudio(h.adminWSHandler.adminConnections["conn-1"],
+		map[string]interface{}{"session_id": session.SessionID(), "enabled": true})
+
+	// Assert - no se pide nada al cliente y el audio sigue desactivado
+	status := readAdminMessage(t, adminSide)
+	require.Equal(t, dto.MessageTypeAudioStatus, status.Type)
+	data := status.Data.(map[string]interface{})
+	assert.Equal(t, false, data["enabled"])
+	assert.Equal(t, false, data["available"])
+	assert.Equal(t, AudioStatusReasonNotSupported, data["reason"])
+	assertNoClientMessage(t, clientSide)
+	assert.False(t, h.audioStreams.isEnabled(session.SessionID(), clientConn.PCID))
+}
+
+func TestProcessAudioChunk_CachesSessionAdminUntilOwnershipTransfer(t *testing.T) {
+	// Arrange
+	h, clientConn, _, adminSide, session := newTestAudioHandlers(t, testTargetPCID)
+	h.audioStreams.enable(session.SessionID(), clientConn.PCID)
+
+	// Act
+	h.processAudioChunk(clientConn, testAudioChunk(session.SessionID(), 1))
+
+	// Assert - el administrador se resuelve con el primer chunk y se olvida al transferir la sesión
+	require.Equal(t, dto.MessageTypeAudioStream, readAdminMessage(t, adminSide).Type)
+	assert.Equal(t, "admin-id", h.audioStreams.admin(session.SessionID(), clientConn.PCID))
+
+	require.NoError(t, h.SendSessionOwnershipTransferredToClient(session.SessionID(), clientConn.PCID, "other-admin"))
+	assert.Empty(t, h.audioStreams.admin(session.SessionID(), clientConn.PCID))
+	assert.True(t, h.audioStreams.isEnabled(session.SessionID(), clientConn.PCID))
+}
+
+func TestClientDisconnect_DisablesAudioOfItsSessions(t *testing.T) {
+	// Arrange
+	h, sessionRepo, _ := newTestDisconnectHandler(t, remotesession.StatusFailed)
+	clientSide, served := serveTestClient(t, h)
+	h.audioStreams.enable("session-1", testTargetPCID)
+	h.audioStreams.enable("session-other", "other-pc")
+
+	// Act
+	clientSide.Close()
+	waitServed(t, served)
+
+	// Assert
+	assert.False(t, h.audioStreams.isEnabled("session-1", testTargetPCID))
+	assert.True(t, h.audioStreams.isEnabled("session-other", "other-pc"))
+	sessionRepo.AssertExpectations(t)
+}
@@ -1,13 +1,21 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
 	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
 )
 
+// invalidCredentialsMessage es la única respuesta a cualquier fallo de credenciales (usuario inexistente,
+// contraseña incorrecta, otro rol o cuenta desactivada), para no permitir enumerar usuarios
+const invalidCredentialsMessage = "Invalid credentials"
+
 // AuthHandler maneja las peticiones de autenticación
 type AuthHandler struct {
 	authService *userservice.AuthService
@@ -20,34 +28,78 @@ func NewAuthHandler(authService *userservice.AuthService) *AuthHandler {
 	}
 }
 
-// Login maneja el endpoint POST /api/auth/login
+// Login maneja el endpoint POST /api/v1/auth/login
 func (h *AuthHandler) Login(c *gin.Context) {
 	var request dto.AuthRequestDTO
 
 	// Validar la estructura JSON
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
-			Error:   "invalid_request",
-			Message: "Invalid request format or missing required fields",
-			Code:    400,
-		})
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request format or missing required fields")
 		return
 	}
 
 	// Autenticar al administrador
 	token, user, err := h.authService.AuthenticateAdmin(request.Username, request.Password)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponseDTO{
-			Error:   "authentication_failed",
-			Message: "Invalid credentials or user is not an administrator",
-			Code:    401,
-		})
+		respondAuthenticationError(c, request.Username, err)
+		return
+	}
+
+	// Respuesta exitosa
+	response.Success(c, http.StatusOK, dto.AuthResponseDTO{
+		Token: token,
+		User:  toUserInfoDTO(user),
+	})
+}
+
+// ClientLogin maneja el endpoint POST /api/client/auth, el equivalente REST de CLIENT_AUTH_REQUEST
+// para clientes que no pueden mantener un WebSocket abierto
+func (h *AuthHandler) ClientLogin(c *gin.Context) {
+	var request dto.AuthRequestDTO
+
+	// Validar la estructura JSON
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request format or missing required fields")
+		return
+	}
+
+	// Autenticar al usuario cliente
+	token, user, err := h.authService.AuthenticateClient(request.Username, request.Password)
+	if err != nil {
+		respondAuthenticationError(c, request.Username, err)
 		return
 	}
 
-	// Convertir usuario a DTO
-	userSnapshot := user.ToSnapshot()
-	userDTO := dto.UserInfoDTO{
+	response.Success(c, http.StatusOK, dto.AuthResponseDTO{
+		Token: token,
+		User:  toUserInfoDTO(user),
+	})
+}
+
+// respondAuthenticationError responde con el mensaje genérico a los fallos de credenciales y con 500 al resto;
+// el motivo concreto solo queda en el log del servidor
+func respondAuthenticationError(c *gin.Context, username string, err error) {
+	log.Printf("🔒 AUTH: Login failed for user %q: %v", username, err)
+
+	if isCredentialFailure(err) {
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_FAILED", invalidCredentialsMessage)
+		return
+	}
+	response.Error(c, http.StatusInternalServerError, "AUTHENTICATION_ERROR", "Authentication could not be completed")
+}
+
+// isCredentialFailure indica si el error de autenticación se debe a las credenciales o a la cuenta del usuario
+func isCredentialFailure(err error) bool {
+	return errors.Is(err, userservice.ErrInvalidCredentials) ||
+		errors.Is(err, userservice.ErrNotAdministrator) ||
+		errors.Is(err, userservice.ErrNotClientUser) ||
+		errors.Is(err, userservice.ErrUserInactive)
+}
+
+// toUserInfoDTO convierte el usuario autenticado a DTO
+func toUserInfoDTO(u *user.User) dto.UserInfoDTO {
+	userSnapshot := u.ToSnapshot()
+	return dto.UserInfoDTO{
 		UserID:    userSnapshot.UserID,
 		Username:  userSnapshot.Username,
 		Role:      userSnapshot.Role,
@@ -55,14 +107,6 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		CreatedAt: userSnapshot.CreatedAt,
 		UpdatedAt: userSnapshot.UpdatedAt,
 	}
-
-	// Respuesta exitosa
-	response := dto.AuthResponseDTO{
-		Token: token,
-		User:  userDTO,
-	}
-
-	c.JSON(http.StatusOK, response)
 }
 
 // RegisterRoutes registra las rutas del AuthHandler
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockUserRepository es un mock del repositorio de usuarios
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) FindByUsername(username string) (*user.User, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByID(userID string) (*user.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByIDs(ctx context.Context, userIDs []string) (map[string]*user.User, error) {
+	args := m.Called(ctx, userIDs)
+	return args.Get(0).(map[string]*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Save(user *user.User) error {
+	return m.Called(user).Error(0)
+}
+
+func (m *MockUserRepository) Create(user *user.User) error {
+	return m.Called(user).Error(0)
+}
+
+func (m *MockUserRepository) IncrementTokenVersion(userID string) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+func performLogin(handler *AuthHandler, body string) *httptest.ResponseRecorder {
+	router := newTestRouter("")
+	handler.RegisterRoutes(router.Group("/api/v1"))
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestAuthHandler_Login_ReturnsEnvelopeWithToken(t *testing.T) {
+	// Arrange
+	userRepo := new(MockUserRepository)
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	adminUser := user.NewUser("admin-id", "admin", "127.0.0.1", string(hashedPassword), user.RoleAdministrator)
+	userRepo.On("FindByUsername", "admin").Return(adminUser, nil)
+	handler := NewAuthHandler(userservice.NewAuthService(userRepo, "test-secret"))
+
+	// Act
+	recorder := performLogin(handler, `{"username": "admin", "password": "password"}`)
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.NotEmpty(t, data["token"])
+	assert.Equal(t, "admin", data["user"].(map[string]interface{})["username"])
+}
+
+func TestAuthHandler_Login_InvalidCredentialsReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByUsername", "admin").Return(nil, errors.New("not found"))
+	handler := NewAuthHandler(userservice.NewAuthService(userRepo, "test-secret"))
+
+	// Act
+	recorder := performLogin(handler, `{"username": "admin", "password": "wrong"}`)
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusUnauthorized, "AUTHENTICATION_FAILED")
+}
+
+func TestAuthHandler_Login_MalformedBodyReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	handler := NewAuthHandler(userservice.NewAuthService(new(MockUserRepository), "test-secret"))
+
+	// Act
+	recorder := performLogin(handler, `{"username": "admin"}`)
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusBadRequest, "INVALID_REQUEST")
+}
+
+func TestAuthHandler_ClientLogin_RejectsAdministrator(t *testing.T) {
+	// Arrange
+	userRepo := new(MockUserRepository)
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	adminUser := user.NewUser("admin-id", "admin", "127.0.0.1", string(hashedPassword), user.RoleAdministrator)
+	clientUser := user.NewUser("owner-id", "client", "127.0.0.1", string(hashedPassword), user.RoleClientUser)
+	userRepo.On("FindByUsername", "admin").Return(adminUser, nil)
+	userRepo.On("FindByUsername", "client").Return(clientUser, nil)
+	handler := NewAuthHandler(userservice.NewAuthService(userRepo, "test-secret"))
+
+	router := newTestRouter("")
+	router.POST("/api/client/auth", handler.ClientLogin)
+	login := func(body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/api/client/auth", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Act
+	rejected := login(`{"username": "admin", "password": "password"}`)
+	accepted := login(`{"username": "client", "password": "password"}`)
+
+	// Assert
+	assertErrorEnvelope(t, rejected, http.StatusUnauthorized, "AUTHENTICATION_FAILED")
+	data := assertSuccessEnvelope(t, accepted, http.StatusOK)
+	assert.NotEmpty(t, data["token"])
+	assert.Equal(t, string(user.RoleClientUser), data["user"].(map[string]interface{})["role"])
+}
+
+func TestAuthHandler_Login_CredentialFailuresShareGenericMessage(t *testing.T) {
+	// Arrange - usuario inexistente, contraseña incorrecta, rol cliente y administrador desactivado
+	userRepo := new(MockUserRepository)
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	inactiveAdmin := user.NewUser("inactive-id", "inactive", "127.0.0.1", string(hashedPassword), user.RoleAdministrator)
+	inactiveAdmin.Deactivate()
+	userRepo.On("FindByUsername", "ghost").Return(nil, nil)
+	userRepo.On("FindByUsername", "admin").Return(user.NewUser("admin-id", "admin", "127.0.0.1", string(hashedPassword), user.RoleAdministrator), nil)
+	userRepo.On("FindByUsername", "client").Return(user.NewUser("client-id", "client", "127.0.0.1", string(hashedPassword), user.RoleClientUser), nil)
+	userRepo.On("FindByUsername", "inactive").Return(inactiveAdmin, nil)
+	handler := NewAuthHandler(userservice.NewAuthService(userRepo, "test-secret"))
+
+	for _, body := range []string{
+		`{"username": "ghost", "password": "password"}`,
+		`{"username": "admin", "password": "wrong"}`,
+		`{"username": "client", "password": "password"}`,
+		`{"username": "inactive", "password": "password"}`,
+	} {
+		// Act
+		recorder := performLogin(handler, body)
+
+		// Assert
+		assertErrorEnvelope(t, recorder, http.StatusUnauthorized, "AUTHENTICATION_FAILED")
+		assert.Contains(t, recorder.Body.String(), `"message":"`+invalidCredentialsMessage+`"`, body)
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Valores por defecto del reintento de chunks: hasta 3 reintentos por chunk con espera creciente desde
+// 200 ms (máximo 2 s) y como mucho 20 reintentos en toda la transferencia
+const (
+	DefaultChunkRetriesPerChunk = 3
+	DefaultChunkRetryBackoff    = 200 * time.Millisecond
+	DefaultChunkRetryMaxBackoff = 2 * time.Second
+	DefaultChunkRetriesTotal    = 20
+)
+
+// ErrChunkRetriesExhausted indica que un chunk no se pudo enviar tras agotar los reintentos
+var ErrChunkRetriesExhausted = errors.New("chunk send retries exhausted")
+
+// ChunkRetryConfig reintentos de envío de un chunk ante errores transitorios de escritura. Cada reintento
+// espera Backoff, duplicándolo hasta MaxBackoff. TotalRetries acota los reintentos de toda la transferencia
+// para que un enlace que falla en cada chunk no la alargue indefinidamente. PerChunk <= 0 desactiva los reintentos.
+type ChunkRetryConfig struct {
+	PerChunk     int
+	Backoff      time.Duration
+	MaxBackoff   time.Duration
+	TotalRetries int
+}
+
+// DefaultChunkRetryConfig reintentos por defecto del envío de chunks
+func DefaultChunkRetryConfig() ChunkRetryConfig {
+	return ChunkRetryConfig{
+		PerChunk:     DefaultChunkRetriesPerChunk,
+		Backoff:      DefaultChunkRetryBackoff,
+		MaxBackoff:   DefaultChunkRetryMaxBackoff,
+		TotalRetries: DefaultChunkRetriesTotal,
+	}
+}
+
+// backoffFor espera antes del reintento número retry (empezando en 1)
+func (c ChunkRetryConfig) backoffFor(retry int) time.Duration {
+	backoff := c.Backoff
+	for i := 1; i < retry && backoff < c.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if c.MaxBackoff > 0 && backoff > c.MaxBackoff {
+		backoff = c.MaxBackoff
+	}
+	return backoff
+}
+
+// SetChunkRetryConfig configura los reintentos de envío de chunks de las transferencias
+func (h *WebSocketHandler) SetChunkRetryConfig(config ChunkRetryConfig) {
+	h.chunkRetry = config
+}
+
+// sendChunkWithRetry envía un chunk por clientConn reintentando los errores de escritura, y retorna la conexión
+// por la que se envió. Los errores de escritura de gorilla/websocket no se recuperan en la misma conexión, así que
+// cada reintento vuelve a leer pcConnections y solo reenvía si el PC se reconectó con otra conexión; mientras
+// siga la que falló, el reintento solo espera. Entre intentos comprueba que el cliente sigue conectado y que la
+// transferencia no se canceló. retriesLeft es el presupuesto de reintentos de la transferencia y se descuenta
+// con cada reintento.
+func (h *WebSocketHandler) sendChunkWithRetry(transferID, targetPCID string, chunkIndex int, retriesLeft *int, clientConn *ClientConnection, send func(*ClientConnection) error) (*ClientConnection, error) {
+	err := send(clientConn)
+	for retry := 1; err != nil; retry++ {
+		if retry > h.chunkRetry.PerChunk || *retriesLeft <= 0 {
+			return clientConn, fmt.Errorf("%w: chunk %d after %d retries: %v", ErrChunkRetriesExhausted, chunkIndex, retry-1, err)
+		}
+		*retriesLeft--
+
+		log.Printf("🔁 FILE TRANSFER: Chunk %d of transfer %s failed (%v), retry %d/%d",
+			chunkIndex, transferID, err, retry, h.chunkRetry.PerChunk)
+		time.Sleep(h.chunkRetry.backoffFor(retry))
+
+		if h.taskRegistry.TransferCancelled(transferID) {
+			return clientConn, ErrTransferCancelled
+		}
+		h.mutex.RLock()
+		current, connected := h.pcConnections[targetPCID]
+		h.mutex.RUnlock()
+		if !connected {
+			return clientConn, fmt.Errorf("client disconnected while retrying chunk %d", chunkIndex)
+		}
+		if current == clientConn {
+			// La conexión que falló sigue registrada: reenviar por ella fallaría igual
+			continue
+		}
+
+		log.Printf("🔌 FILE TRANSFER: PC %s reconnected, resending chunk %d of transfer %s", targetPCID, chunkIndex, transferID)
+		clientConn = current
+		err = send(clientConn)
+	}
+	return clientConn, nil
+}
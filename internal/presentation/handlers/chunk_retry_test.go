@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestChunkRetryHandler handler con el PC de prueba conectado y reintentos sin espera apreciable
+func newTestChunkRetryHandler(perChunk int) *WebSocketHandler {
+	h, _ := newTestWebSocketHandler()
+	h.pcConnections[testTargetPCID] = &ClientConnection{PCID: testTargetPCID, IsAuth: true}
+	h.SetChunkRetryConfig(ChunkRetryConfig{PerChunk: perChunk, Backoff: time.Millisecond, MaxBackoff: time.Millisecond, TotalRetries: 10})
+	return h
+}
+
+// reconnectTestPC sustituye la conexión del PC de prueba como si se hubiera reconectado
+func reconnectTestPC(h *WebSocketHandler) *ClientConnection {
+	conn := &ClientConnection{PCID: testTargetPCID, IsAuth: true}
+	h.mutex.Lock()
+	h.pcConnections[testTargetPCID] = conn
+	h.mutex.Unlock()
+	return conn
+}
+
+func TestSendChunkWithRetry_ResendsOverReconnectedConnection(t *testing.T) {
+	// Arrange - el primer intento falla y el PC se reconecta
+	h := newTestChunkRetryHandler(3)
+	original := h.pcConnections[testTargetPCID]
+	var attempts []*ClientConnection
+	retriesLeft := 10
+
+	// Act
+	used, err := h.sendChunkWithRetry("transfer-1", testTargetPCID, 0, &retriesLeft, original, func(conn *ClientConnection) error {
+		attempts = append(attempts, conn)
+		if conn == original {
+			reconnectTestPC(h)
+			return errors.New("write: broken pipe")
+		}
+		return nil
+	})
+
+	// Assert - el reintento usa la nueva conexión y se retorna para los chunks siguientes
+	assert.NoError(t, err)
+	assert.Len(t, attempts, 2)
+	assert.NotSame(t, original, attempts[1])
+	assert.Same(t, attempts[1], used)
+	assert.Equal(t, 9, retriesLeft)
+}
+
+func TestSendChunkWithRetry_DoesNotResendOverFailedConnection(t *testing.T) {
+	// Arrange - el error de escritura es permanente en la conexión y el PC no se reconecta
+	h := newTestChunkRetryHandler(2)
+	attempts := 0
+	retriesLeft := 10
+
+	// Act
+	_, err := h.sendChunkWithRetry("transfer-1", testTargetPCID, 4, &retriesLeft, h.pcConnections[testTargetPCID], func(conn *ClientConnection) error {
+		attempts++
+		return errors.New("write: connection reset")
+	})
+
+	// Assert - los reintentos esperan una reconexión que no llega, sin volver a escribir en la conexión rota
+	assert.ErrorIs(t, err, ErrChunkRetriesExhausted)
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, 8, retriesLeft)
+}
+
+func TestSendChunkWithRetry_FailsWhenRetriesExhausted(t *testing.T) {
+	// Arrange - cada conexión nueva también falla
+	h := newTestChunkRetryHandler(2)
+	attempts := 0
+	retriesLeft := 10
+
+	// Act
+	_, err := h.sendChunkWithRetry("transfer-1", testTargetPCID, 4, &retriesLeft, h.pcConnections[testTargetPCID], func(conn *ClientConnection) error {
+		attempts++
+		reconnectTestPC(h)
+		return errors.New("write: connection reset")
+	})
+
+	// Assert - intento inicial más dos reintentos
+	assert.ErrorIs(t, err, ErrChunkRetriesExhausted)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestSendChunkWithRetry_StopsWhenTransferBudgetIsSpent(t *testing.T) {
+	// Arrange - el chunk admitiría más reintentos pero la transferencia ya no tiene presupuesto
+	h := newTestChunkRetryHandler(5)
+	attempts := 0
+	retriesLeft := 1
+
+	// Act
+	_, err := h.sendChunkWithRetry("transfer-1", testTargetPCID, 7, &retriesLeft, h.pcConnections[testTargetPCID], func(conn *ClientConnection) error {
+		attempts++
+		reconnectTestPC(h)
+		return errors.New("write: timeout")
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrChunkRetriesExhausted)
+	assert.Equal(t, 2, attempts)
+	assert.Zero(t, retriesLeft)
+}
+
+func TestSendChunkWithRetry_StopsWhenClientDisconnects(t *testing.T) {
+	// Arrange
+	h := newTestChunkRetryHandler(3)
+	attempts := 0
+	retriesLeft := 10
+
+	// Act - el cliente se desconecta tras el primer fallo
+	_, err := h.sendChunkWithRetry("transfer-1", testTargetPCID, 0, &retriesLeft, h.pcConnections[testTargetPCID], func(conn *ClientConnection) error {
+		attempts++
+		delete(h.pcConnections, testTargetPCID)
+		return errors.New("write: broken pipe")
+	})
+
+	// Assert
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrChunkRetriesExhausted)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// handleActivityStatus registra si el usuario está frente al PC durante una sesión y, cuando el estado cambia,
+// avisa al administrador que controla la sesión. Los informes repetidos con el mismo estado no se reenvían.
+func (h *WebSocketHandler) handleActivityStatus(conn messageWriter, clientConn *ClientConnection, data interface{}) {
+	var report dto.ActivityStatusReport
+	if !decodePayload(conn, dto.MessageTypeActivityStatus, data, &report) {
+		return
+	}
+
+	if !clientConn.IsAuth || clientConn.PCID == "" {
+		log.Printf("❌ CLIENT ACTIVITY: Unregistered client attempted to report activity")
+		return
+	}
+	if h.sessionService == nil {
+		return
+	}
+
+	state := remotesessionservice.ClientActivityState(strings.ToUpper(report.Status))
+	lastInputAge := time.Duration(report.LastInputAgeSeconds) * time.Second
+	activity, changed, err := h.sessionService.RecordClientActivity(clientConn.Context(), report.SessionID, clientConn.PCID, state, lastInputAge)
+	if err != nil {
+		log.Printf("⚠️ CLIENT ACTIVITY: Ignoring report from PC %s for session %s: %v", clientConn.PCID, report.SessionID, err)
+		return
+	}
+	if !changed {
+		return
+	}
+
+	log.Printf("🧍 CLIENT ACTIVITY: User at PC %s is %s (session %s, last input %v ago)",
+		clientConn.PCID, activity.State, activity.SessionID, activity.LastInputAge)
+	if h.adminWSHandler != nil {
+		h.adminWSHandler.NotifyClientActivity(activity)
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+func TestHandleActivityStatus_ActiveToIdleIsRelayedToAdmin(t *testing.T) {
+	// Arrange
+	h, _, session := newTestRecordingHandler(t, testTargetPCID)
+	h.adminWSHandler = NewAdminWebSocketHandler(nil, nil)
+	adminSide := connectTestAdmin(t, h.adminWSHandler)
+	_, clientConn := connectTestClient(t, h)
+	report := func(status string, lastInputAge int64) {
+		h.handleActivityStatus(clientConn.Conn, clientConn, map[string]interface{}{
+			"session_id":             session.SessionID(),
+			"status":                 status,
+			"last_input_age_seconds": lastInputAge,
+		})
+	}
+	report("active", 1)
+	active := readAdminMessage(t, adminSide)
+	require.Equal(t, dto.MessageTypeClientActivity, active.Type)
+
+	// Act
+	report("active", 2) // sin cambio: no se reenvía
+	report("idle", 300)
+
+	// Assert
+	message := readAdminMessage(t, adminSide)
+	assert.Equal(t, dto.MessageTypeClientActivity, message.Type)
+	data, ok := message.Data.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, session.SessionID(), data["session_id"])
+	assert.Equal(t, testTargetPCID, data["client_pc_id"])
+	assert.Equal(t, "idle", data["status"])
+	assert.Equal(t, float64(300), data["last_input_age_seconds"])
+	assert.InDelta(t, time.Now().Add(-300*time.Second).Unix(), data["idle_since"], 2)
+
+	activity, reported := h.sessionService.GetSessionActivity(session.SessionID())
+	require.True(t, reported)
+	assert.Equal(t, 1, activity.IdleCount)
+	assert.GreaterOrEqual(t, activity.IdleDuration, 300*time.Second)
+}
+
+func TestHandleActivityStatus_IgnoresReportForAnotherPCSession(t *testing.T) {
+	// Arrange - la sesión pertenece a otro PC
+	h, _, session := newTestRecordingHandler(t, "other-pc-id")
+	h.adminWSHandler = NewAdminWebSocketHandler(nil, nil)
+	_, clientConn := connectTestClient(t, h)
+
+	// Act
+	h.handleActivityStatus(clientConn.Conn, clientConn, map[string]interface{}{
+		"session_id": session.SessionID(),
+		"status":     "idle",
+	})
+
+	// Assert
+	_, reported := h.sessionService.GetSessionActivity(session.SessionID())
+	assert.False(t, reported)
+}
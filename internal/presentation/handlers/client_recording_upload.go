@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// UploadRecordingViaREST maneja POST /api/client/recordings/:videoId/upload: subida por partes de una grabación
+// terminada, fuera del WebSocket para no competir con el streaming. Cada parte es un multipart/form-data con
+// pc_id, session_id, file_size (bytes totales), offset, duration y el archivo part. Una parte que no empieza en
+// los bytes ya recibidos responde 409 UPLOAD_OFFSET_MISMATCH y el cliente reanuda desde GET .../upload.
+func (h *WebSocketHandler) UploadRecordingViaREST(c *gin.Context) {
+	claims, ok := restClientClaims(c)
+	if !ok {
+		return
+	}
+	videoService, ok := h.recordingUploadService(c)
+	if !ok {
+		return
+	}
+
+	videoID := c.Param("videoId")
+	part := videoservice.UploadPart{
+		VideoID:   videoID,
+		SessionID: c.PostForm("session_id"),
+		PCID:      c.PostForm("pc_id"),
+		UserID:    claims.UserID,
+	}
+	var err error
+	if part.FileSize, err = strconv.ParseInt(c.PostForm("file_size"), 10, 64); err != nil || part.FileSize <= 0 {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "file_size must be a positive number of bytes")
+		return
+	}
+	if part.Offset, err = strconv.ParseInt(c.DefaultPostForm("offset", "0"), 10, 64); err != nil || part.Offset < 0 {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "offset must be a non-negative number of bytes")
+		return
+	}
+	if part.Duration, err = strconv.Atoi(c.DefaultPostForm("duration", "0")); err != nil {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "duration must be a number of seconds")
+		return
+	}
+	if part.SessionID == "" {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "session_id is required")
+		return
+	}
+	fileHeader, err := c.FormFile("part")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "part file is required")
+		return
+	}
+
+	if !h.ownsRESTClientPC(c, claims, part.PCID) {
+		return
+	}
+	if h.featureFlags != nil && !h.featureFlags.ServerSideRecording() {
+		response.Error(c, http.StatusForbidden, "SERVER_RECORDING_DISABLED", "Server-side recording is disabled")
+		return
+	}
+
+	// La sesión se valida al empezar la subida; las partes siguientes solo deben coincidir con ella, para que una
+	// subida larga pueda reanudarse después del periodo de gracia de la grabación
+	if _, err := videoService.GetPartUploadProgress(videoID); errors.Is(err, videoservice.ErrVideoUploadNotFound) && h.sessionService != nil {
+		if err := h.sessionService.ValidateRecordingPermission(c.Request.Context(), part.SessionID, part.PCID); err != nil {
+			log.Printf("🚫 RECORDING UPLOAD: PC %s cannot upload video %s of session %s: %v", part.PCID, videoID, part.SessionID, err)
+			response.Error(c, http.StatusForbidden, "RECORDING_NOT_PERMITTED", "Recording not permitted for this session")
+			return
+		}
+	}
+
+	partFile, err := fileHeader.Open()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "part file could not be read")
+		return
+	}
+	defer partFile.Close()
+	part.Data = partFile
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
+	defer cancel()
+
+	progress, err := videoService.WriteUploadPart(ctx, part)
+	switch {
+	case errors.Is(err, videoservice.ErrUploadOffsetMismatch):
+		response.Error(c, http.StatusConflict, "UPLOAD_OFFSET_MISMATCH", err.Error())
+		return
+	case errors.Is(err, videoservice.ErrUploadParametersMismatch):
+		response.Error(c, http.StatusConflict, "UPLOAD_CONFLICT", "An upload with different parameters is in progress for this video")
+		return
+	case errors.Is(err, videoservice.ErrUploadSizeExceeded):
+		response.Error(c, http.StatusBadRequest, "UPLOAD_SIZE_EXCEEDED", err.Error())
+		return
+	case err != nil:
+		log.Printf("❌ RECORDING UPLOAD: Error writing part at offset %d of video %s: %v", part.Offset, videoID, err)
+		response.Error(c, http.StatusInternalServerError, "UPLOAD_FAILED", "Failed to store upload part")
+		return
+	}
+
+	if progress.IsComplete {
+		log.Printf("📹 RECORDING UPLOAD: Video %s of session %s uploaded via REST (%d bytes)", videoID, part.SessionID, progress.FileSize)
+	}
+	response.Success(c, http.StatusOK, toRecordingUploadProgress(progress))
+}
+
+// GetRecordingUploadProgress maneja GET /api/client/recordings/:videoId/upload?pc_id=: bytes ya recibidos de la
+// subida, desde los que el cliente reanuda tras un corte o un reinicio del servidor
+func (h *WebSocketHandler) GetRecordingUploadProgress(c *gin.Context) {
+	claims, ok := restClientClaims(c)
+	if !ok {
+		return
+	}
+	videoService, ok := h.recordingUploadService(c)
+	if !ok {
+		return
+	}
+	pcID := c.Query("pc_id")
+	if !h.ownsRESTClientPC(c, claims, pcID) {
+		return
+	}
+
+	progress, err := videoService.GetPartUploadProgress(c.Param("videoId"))
+	if errors.Is(err, videoservice.ErrVideoUploadNotFound) {
+		response.Error(c, http.StatusNotFound, "UPLOAD_NOT_FOUND", "No upload in progress for this video")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "UPLOAD_STATUS_FAILED", "Failed to get upload progress")
+		return
+	}
+	if progress.PCID != "" && progress.PCID != pcID {
+		response.Error(c, http.StatusNotFound, "UPLOAD_NOT_FOUND", "No upload in progress for this video")
+		return
+	}
+
+	response.Success(c, http.StatusOK, toRecordingUploadProgress(progress))
+}
+
+// recordingUploadService obtiene el servicio de video; responde 503 si el servidor no lo tiene
+func (h *WebSocketHandler) recordingUploadService(c *gin.Context) (videoservice.IVideoService, bool) {
+	videoService, ok := h.videoService.(videoservice.IVideoService)
+	if !ok || videoService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "VIDEO_SERVICE_UNAVAILABLE", "Video service not available")
+		return nil, false
+	}
+	return videoService, true
+}
+
+// ownsRESTClientPC verifica que el PC pertenece al usuario cliente; responde 404 si no
+func (h *WebSocketHandler) ownsRESTClientPC(c *gin.Context, claims *userservice.JWTClaims, pcID string) bool {
+	if pcID == "" {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "pc_id is required")
+		return false
+	}
+	pc, err := h.pcService.GetPCByID(c.Request.Context(), pcID)
+	if err != nil || pc == nil || pc.OwnerUserID != claims.UserID {
+		response.Error(c, http.StatusNotFound, "PC_NOT_FOUND", "PC not found")
+		return false
+	}
+	return true
+}
+
+func toRecordingUploadProgress(progress *videoservice.PartUploadProgress) dto.RecordingUploadProgress {
+	return dto.RecordingUploadProgress{
+		VideoID:       progress.VideoID,
+		SessionID:     progress.SessionID,
+		FileSize:      progress.FileSize,
+		ReceivedBytes: progress.ReceivedBytes,
+		IsComplete:    progress.IsComplete,
+	}
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+)
+
+// partUploadVideoService guarda en memoria las partes con el mismo contrato de offsets que el servicio real
+type partUploadVideoService struct {
+	videoservice.IVideoService
+	started  bool
+	part     videoservice.UploadPart
+	received []byte
+	writes   int
+}
+
+func (s *partUploadVideoService) GetPartUploadProgress(videoID string) (*videoservice.PartUploadProgress, error) {
+	if !s.started {
+		return nil, fmt.Errorf("%w: %s", videoservice.ErrVideoUploadNotFound, videoID)
+	}
+	return s.progress(), nil
+}
+
+func (s *partUploadVideoService) WriteUploadPart(ctx context.Context, part videoservice.UploadPart) (*videoservice.PartUploadProgress, error) {
+	data, err := io.ReadAll(part.Data)
+	if err != nil {
+		return nil, err
+	}
+	if !s.started {
+		s.started = true
+		s.part = part
+	}
+	if part.Offset != int64(len(s.received)) {
+		return s.progress(), fmt.Errorf("%w: expected offset %d, got %d", videoservice.ErrUploadOffsetMismatch, len(s.received), part.Offset)
+	}
+	s.received = append(s.received, data...)
+	s.writes++
+	return s.progress(), nil
+}
+
+func (s *partUploadVideoService) progress() *videoservice.PartUploadProgress {
+	return &videoservice.PartUploadProgress{
+		VideoID:       s.part.VideoID,
+		SessionID:     s.part.SessionID,
+		PCID:          s.part.PCID,
+		FileSize:      s.part.FileSize,
+		ReceivedBytes: int64(len(s.received)),
+		IsComplete:    int64(len(s.received)) == s.part.FileSize,
+	}
+}
+
+// newTestRecordingUploadHandler registra un PC del usuario cliente y expone los endpoints de subida
+func newTestRecordingUploadHandler(t *testing.T) (*partUploadVideoService, *gin.Engine, string) {
+	t.Helper()
+
+	h, _, router := newTestRESTClientHandler()
+	videoService := &partUploadVideoService{}
+	h.videoService = videoService
+	router.POST("/api/client/recordings/:videoId/upload", h.UploadRecordingViaREST)
+	router.GET("/api/client/recordings/:videoId/upload", h.GetRecordingUploadProgress)
+
+	registered := postClientAPI(router, "/api/client/register", `{"pcIdentifier": "LAB-PC-01"}`)
+	pcID := assertSuccessEnvelope(t, registered, http.StatusOK)["pc"].(map[string]interface{})["pcId"].(string)
+	return videoService, router, pcID
+}
+
+// postRecordingPart envía una parte de la grabación video-1 de 9 bytes
+func postRecordingPart(t *testing.T, router *gin.Engine, pcID string, offset int, data string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	fields := map[string]string{
+		"pc_id":      pcID,
+		"session_id": "session-1",
+		"file_size":  "9",
+		"offset":     strconv.Itoa(offset),
+		"duration":   "30",
+	}
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	partWriter, err := writer.CreateFormFile("part", "recording.mp4")
+	require.NoError(t, err)
+	_, err = partWriter.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/api/client/recordings/video-1/upload", &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestUploadRecordingViaREST_MultiPartUploadCompletes(t *testing.T) {
+	// Arrange
+	videoService, router, pcID := newTestRecordingUploadHandler(t)
+
+	// Act
+	first := postRecordingPart(t, router, pcID, 0, "0123")
+	last := postRecordingPart(t, router, pcID, 4, "45678")
+
+	// Assert
+	firstData := assertSuccessEnvelope(t, first, http.StatusOK)
+	assert.Equal(t, float64(4), firstData["receivedBytes"])
+	assert.Equal(t, false, firstData["isComplete"])
+
+	lastData := assertSuccessEnvelope(t, last, http.StatusOK)
+	assert.Equal(t, float64(9), lastData["receivedBytes"])
+	assert.Equal(t, true, lastData["isComplete"])
+	assert.Equal(t, "session-1", lastData["sessionId"])
+
+	assert.Equal(t, []byte("012345678"), videoService.received)
+	assert.Equal(t, "video-1", videoService.part.VideoID)
+	assert.Equal(t, pcID, videoService.part.PCID)
+	assert.Equal(t, 30, videoService.part.Duration)
+}
+
+func TestUploadRecordingViaREST_ResumesFromReportedOffset(t *testing.T) {
+	// Arrange - la primera parte llega; el cliente pierde la respuesta de la segunda y cree que no se envió nada
+	videoService, router, pcID := newTestRecordingUploadHandler(t)
+	assertSuccessEnvelope(t, postRecordingPart(t, router, pcID, 0, "012"), http.StatusOK)
+	assertSuccessEnvelope(t, postRecordingPart(t, router, pcID, 3, "345"), http.StatusOK)
+
+	// Act
+	conflict := postRecordingPart(t, router, pcID, 0, "012")
+
+	statusRecorder := httptest.NewRecorder()
+	router.ServeHTTP(statusRecorder, httptest.NewRequest(http.MethodGet, "/api/client/recordings/video-1/upload?pc_id="+pcID, nil))
+	status := assertSuccessEnvelope(t, statusRecorder, http.StatusOK)
+	resumed := postRecordingPart(t, router, pcID, int(status["receivedBytes"].(float64)), "678")
+
+	// Assert
+	assertErrorEnvelope(t, conflict, http.StatusConflict, "UPLOAD_OFFSET_MISMATCH")
+	assert.Equal(t, float64(6), status["receivedBytes"])
+	assert.Equal(t, true, assertSuccessEnvelope(t, resumed, http.StatusOK)["isComplete"])
+	assert.Equal(t, []byte("012345678"), videoService.received)
+	assert.Equal(t, 3, videoService.writes)
+}
+
+func TestUploadRecordingViaREST_RejectsPCOfAnotherOwner(t *testing.T) {
+	// Arrange
+	videoService, router, _ := newTestRecordingUploadHandler(t)
+
+	// Act
+	recorder := postRecordingPart(t, router, "550e8400-e29b-41d4-a716-446655440099", 0, "012")
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusNotFound, "PC_NOT_FOUND")
+	assert.False(t, videoService.started)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// RegisterPCViaREST maneja POST /api/client/register: registro del PC para clientes que no pueden mantener
+// un WebSocket abierto. Comparte con PC_REGISTRATION el paso por CONNECTING y las notificaciones a administradores.
+func (h *WebSocketHandler) RegisterPCViaREST(c *gin.Context) {
+	claims, ok := restClientClaims(c)
+	if !ok {
+		return
+	}
+
+	var regReq dto.PCRegistrationRequest
+	if err := c.ShouldBindJSON(&regReq); err != nil || regReq.PCIdentifier == "" {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "pcIdentifier is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	// Conexión efímera: el cliente REST no tiene socket, solo su identidad e IP observada
+	clientIP := getClientIP(c)
+	clientConn := &ClientConnection{
+		UserID:     claims.UserID,
+		Username:   claims.Username,
+		Role:       claims.Role,
+		IsAuth:     true,
+		RemoteAddr: clientIP,
+	}
+
+	pc, wasConnecting, err := h.registerClientPC(ctx, clientConn, regReq, clientIP)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "REGISTRATION_FAILED", err.Error())
+		return
+	}
+
+	h.touchRESTClient(pc.PCID, time.Now())
+	h.broadcastPCRegistered(pc, claims.Username, wasConnecting)
+	log.Printf("PC registered via REST: %s (%s) for user %s", regReq.PCIdentifier, pc.PCID, claims.Username)
+
+	response.Success(c, http.StatusOK, dto.ClientRegistrationResponse{
+		PC:                       toClientPCDTO(pc),
+		HeartbeatIntervalSeconds: int(h.heartbeat.Interval.Seconds()),
+		HeartbeatTimeoutSeconds:  int(h.heartbeat.StaleTimeout().Seconds()),
+	})
+}
+
+// HeartbeatViaREST maneja POST /api/client/heartbeat: mantiene ONLINE un PC registrado por REST. Las solicitudes
+// de control en cola no se entregan por REST: el cliente no podría aceptarlas ni transmitir sin WebSocket, así que
+// esperan en cola a que el PC se registre por WebSocket.
+func (h *WebSocketHandler) HeartbeatViaREST(c *gin.Context) {
+	claims, ok := restClientClaims(c)
+	if !ok {
+		return
+	}
+
+	var hbReq dto.ClientHeartbeatRequest
+	if err := c.ShouldBindJSON(&hbReq); err != nil {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "pcId is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	// Solo el propietario del PC puede mantenerlo vivo
+	pc, err := h.pcService.GetPCByID(ctx, hbReq.PCID)
+	if err != nil || pc == nil || pc.OwnerUserID != claims.UserID {
+		response.Error(c, http.StatusNotFound, "PC_NOT_FOUND", "PC not found")
+		return
+	}
+
+	h.touchRESTClient(pc.PCID, time.Now())
+	if !h.refreshPCPresence(ctx, pc.PCID, claims.Username) {
+		response.Error(c, http.StatusInternalServerError, "HEARTBEAT_FAILED", "Failed to update PC status")
+		return
+	}
+
+	response.Success(c, http.StatusOK, dto.ClientHeartbeatResponse{
+		Timestamp: time.Now().Unix(),
+		Status:    "OK",
+	})
+}
+
+// restClientClaims obtiene los claims del usuario cliente autenticado; responde el error si no lo hay
+func restClientClaims(c *gin.Context) (*userservice.JWTClaims, bool) {
+	userInfo, exists := c.Get("user")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
+		return nil, false
+	}
+
+	claims, ok := userInfo.(*userservice.JWTClaims)
+	if !ok || claims.Role != string(user.RoleClientUser) {
+		response.Error(c, http.StatusForbidden, "CLIENT_PRIVILEGES_REQUIRED", "Client user privileges required")
+		return nil, false
+	}
+	return claims, true
+}
+
+// touchRESTClient registra el último heartbeat REST del PC
+func (h *WebSocketHandler) touchRESTClient(pcID string, now time.Time) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.restClients[pcID] = now
+}
+
+// ExpireRESTClients marca OFFLINE los PCs registrados por REST que superaron el timeout de heartbeat,
+// salvo que mientras tanto hayan abierto un WebSocket. Retorna cuántos PCs pasaron a OFFLINE.
+func (h *WebSocketHandler) ExpireRESTClients(ctx context.Context, now time.Time) int {
+	h.mutex.Lock()
+	var expired []string
+	for pcID, lastSeen := range h.restClients {
+		if now.Sub(lastSeen) <= h.heartbeat.StaleTimeout() {
+			continue
+		}
+		delete(h.restClients, pcID)
+		if _, hasSocket := h.pcConnections[pcID]; !hasSocket {
+			expired = append(expired, pcID)
+		}
+	}
+	h.mutex.Unlock()
+
+	reason := remotesession.NewDisconnectReason(remotesession.CloseCodeNone, "REST heartbeat timeout")
+	for _, pcID := range expired {
+		if h.sessionService != nil {
+			if err := h.sessionService.HandleClientPCDisconnect(ctx, pcID, reason); err != nil {
+				log.Printf("⚠️ Error calling HandleClientPCDisconnect for PC %s: %v", pcID, err)
+			}
+		}
+
+		pc, err := h.pcService.GetPCByID(ctx, pcID)
+		if err != nil {
+			log.Printf("⚠️ REST CLIENT: Error retrieving expired PC %s: %v", pcID, err)
+			continue
+		}
+		if err := h.pcService.UpdatePCConnectionStatus(ctx, pcID, clientpc.PCConnectionStatusOffline); err != nil {
+			log.Printf("⚠️ REST CLIENT: Error marking PC %s offline: %v", pcID, err)
+			continue
+		}
+
+		log.Printf("⏱️ REST CLIENT: PC %s (%s) marked OFFLINE, no heartbeat in %v", pc.Identifier, pcID, h.heartbeat.StaleTimeout())
+		if h.adminWSHandler != nil {
+			h.adminWSHandler.BroadcastPCDisconnected(pcID, pc.Identifier, pc.OwnerUserID, reason)
+			h.adminWSHandler.BroadcastPCStatusChanged(pcID, pc.Identifier, pc.OwnerUserID, string(pc.ConnectionStatus), string(clientpc.PCConnectionStatusOffline))
+			h.adminWSHandler.BroadcastPCListUpdate()
+		}
+	}
+	return len(expired)
+}
@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/pcservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// testRESTOwnerUserID usuario cliente de las pruebas REST (el PCService real exige UUIDs)
+const testRESTOwnerUserID = "550e8400-e29b-41d4-a716-446655440010"
+
+// memoryClientPCRepository repositorio de PCs en memoria para probar el PCService real
+type memoryClientPCRepository struct {
+	pcs   map[string]clientpc.ClientPC
+	mutex sync.Mutex
+}
+
+func newMemoryClientPCRepository() *memoryClientPCRepository {
+	return &memoryClientPCRepository{pcs: make(map[string]clientpc.ClientPC)}
+}
+
+func (r *memoryClientPCRepository) Save(ctx context.Context, pc *clientpc.ClientPC) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.pcs[pc.PCID] = *pc
+	return nil
+}
+
+func (r *memoryClientPCRepository) FindByID(ctx context.Context, pcID string) (*clientpc.ClientPC, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	pc, exists := r.pcs[pcID]
+	if !exists {
+		return nil, nil
+	}
+	return &pc, nil
+}
+
+func (r *memoryClientPCRepository) FindByIDs(ctx context.Context, pcIDs []string) (map[string]*clientpc.ClientPC, error) {
+	found := make(map[string]*clientpc.ClientPC)
+	for _, pcID := range pcIDs {
+		if pc, _ := r.FindByID(ctx, pcID); pc != nil {
+			found[pcID] = pc
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryClientPCRepository) FindByIdentifierAndOwner(ctx context.Context, identifier string, ownerID string) (*clientpc.ClientPC, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, pc := range r.pcs {
+		if pc.Identifier == identifier && pc.OwnerUserID == ownerID {
+			return &pc, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryClientPCRepository) FindByOwner(ctx context.Context, ownerID string) ([]*clientpc.ClientPC, error) {
+	pcs, _ := r.FindAll(ctx, 0, 0)
+	owned := make([]*clientpc.ClientPC, 0, len(pcs))
+	for _, pc := range pcs {
+		if pc.OwnerUserID == ownerID {
+			owned = append(owned, pc)
+		}
+	}
+	return owned, nil
+}
+
+func (r *memoryClientPCRepository) FindOnlineByOwner(ctx context.Context, ownerID string) ([]*clientpc.ClientPC, error) {
+	owned, _ := r.FindByOwner(ctx, ownerID)
+	online := make([]*clientpc.ClientPC, 0, len(owned))
+	for _, pc := range owned {
+		if pc.IsOnline() {
+			online = append(online, pc)
+		}
+	}
+	return online, nil
+}
+
+func (r *memoryClientPCRepository) UpdateConnectionStatus(ctx context.Context, pcID string, status clientpc.PCConnectionStatus) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	pc := r.pcs[pcID]
+	pc.ConnectionStatus = status
+	r.pcs[pcID] = pc
+	return nil
+}
+
+func (r *memoryClientPCRepository) UpdateLastSeen(ctx context.Context, pcID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	pc := r.pcs[pcID]
+	pc.UpdateLastSeen()
+	r.pcs[pcID] = pc
+	return nil
+}
+
+func (r *memoryClientPCRepository) UpdateAutoAcceptControl(ctx context.Context, pcID string, enabled bool) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	pc := r.pcs[pcID]
+	pc.AutoAcceptControl = enabled
+	r.pcs[pcID] = pc
+	return nil
+}
+
+func (r *memoryClientPCRepository) Delete(ctx context.Context, pcID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.pcs, pcID)
+	return nil
+}
+
+func (r *memoryClientPCRepository) FindAll(ctx context.Context, limit, offset int) ([]*clientpc.ClientPC, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	pcs := make([]*clientpc.ClientPC, 0, len(r.pcs))
+	for _, pc := range r.pcs {
+		pc := pc
+		pcs = append(pcs, &pc)
+	}
+	return pcs, nil
+}
+
+func (r *memoryClientPCRepository) CountByOwner(ctx context.Context, ownerID string) (int, error) {
+	owned, _ := r.FindByOwner(ctx, ownerID)
+	return len(owned), nil
+}
+
+func (r *memoryClientPCRepository) Count(ctx context.Context) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.pcs), nil
+}
+
+// onlyPC retorna el único PC guardado en el repositorio
+func (r *memoryClientPCRepository) onlyPC(t *testing.T) clientpc.ClientPC {
+	t.Helper()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	require.Len(t, r.pcs, 1)
+	for _, pc := range r.pcs {
+		return pc
+	}
+	return clientpc.ClientPC{}
+}
+
+// newTestRESTClientHandler crea un handler con el PCService real sobre un repositorio en memoria
+func newTestRESTClientHandler() (*WebSocketHandler, *memoryClientPCRepository, *gin.Engine) {
+	repo := newMemoryClientPCRepository()
+	h := NewWebSocketHandler(nil, pcservice.NewPCService(repo, clientpc.NewClientPCFactory()), nil, nil, nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &userservice.JWTClaims{UserID: testRESTOwnerUserID, Username: "client", Role: string(user.RoleClientUser)})
+		c.Next()
+	})
+	router.POST("/api/client/register", h.RegisterPCViaREST)
+	router.POST("/api/client/heartbeat", h.HeartbeatViaREST)
+	return h, repo, router
+}
+
+// postClientAPI envía un POST JSON desde la IP 192.168.1.10
+func postClientAPI(router *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Forwarded-For", "192.168.1.10")
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestRegisterPCViaREST_ProducesSameStateAsWebSocketRegistration(t *testing.T) {
+	// Arrange
+	wsRepo := newMemoryClientPCRepository()
+	wsHandler := NewWebSocketHandler(nil, pcservice.NewPCService(wsRepo, clientpc.NewClientPCFactory()), nil, nil, nil, nil)
+	clientSide, clientConn := connectTestClient(t, wsHandler)
+	wsHandler.mutex.Lock()
+	delete(wsHandler.pcConnections, testTargetPCID)
+	wsHandler.mutex.Unlock()
+	clientConn.PCID = ""
+	clientConn.UserID = testRESTOwnerUserID
+
+	restHandler, restRepo, router := newTestRESTClientHandler()
+
+	// Act
+	wsHandler.handlePCRegistration(clientConn.Conn, clientConn, dto.PCRegistrationRequest{PCIdentifier: "LAB-PC-01"}, "192.168.1.10")
+	recorder := postClientAPI(router, "/api/client/register", `{"pcIdentifier": "LAB-PC-01"}`)
+
+	// Assert
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, clientSide.ReadJSON(&message))
+	require.Equal(t, dto.MessageTypePCRegistrationResp, message.Type)
+	assert.Equal(t, true, message.Data.(map[string]interface{})["success"])
+
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	wsPC := wsRepo.onlyPC(t)
+	restPC := restRepo.onlyPC(t)
+	assert.Equal(t, restPC.PCID, data["pc"].(map[string]interface{})["pcId"])
+
+	assert.Equal(t, wsPC.Identifier, restPC.Identifier)
+	assert.Equal(t, wsPC.IP, restPC.IP)
+	assert.Equal(t, wsPC.OwnerUserID, restPC.OwnerUserID)
+	assert.Equal(t, wsPC.ConnectionStatus, restPC.ConnectionStatus)
+	assert.Equal(t, wsPC.AutoAcceptControl, restPC.AutoAcceptControl)
+	assert.Equal(t, clientpc.PCConnectionStatusOnline, restPC.ConnectionStatus)
+
+	// El PC REST cuenta como conectado aunque no tenga socket
+	assert.Contains(t, restHandler.ConnectedPCIDs(), restPC.PCID)
+}
+
+func TestHeartbeatViaREST_BringsOfflinePCBackOnline(t *testing.T) {
+	// Arrange
+	h, repo, router := newTestRESTClientHandler()
+	registered := postClientAPI(router, "/api/client/register", `{"pcIdentifier": "LAB-PC-01"}`)
+	pcID := assertSuccessEnvelope(t, registered, http.StatusOK)["pc"].(map[string]interface{})["pcId"].(string)
+	require.NoError(t, repo.UpdateConnectionStatus(context.Background(), pcID, clientpc.PCConnectionStatusOffline))
+
+	// Act
+	recorder := postClientAPI(router, "/api/client/heartbeat", `{"pcId": "`+pcID+`"}`)
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, "OK", data["status"])
+	pc := repo.onlyPC(t)
+	assert.Equal(t, clientpc.PCConnectionStatusOnline, pc.ConnectionStatus)
+	assert.NotNil(t, pc.LastSeenAt)
+	assert.Contains(t, h.ConnectedPCIDs(), pcID)
+}
+
+func TestHeartbeatViaREST_LeavesQueuedSessionForWebSocket(t *testing.T) {
+	// Arrange - sin expectativas: entregar la solicitud en cola consultaría y actualizaría la sesión
+	h, _, router := newTestRESTClientHandler()
+	sessionRepo := new(MockRemoteSessionRepository)
+	h.sessionService = remotesessionservice.NewRemoteSessionService(sessionRepo, nil, nil, nil, nil)
+	registered := postClientAPI(router, "/api/client/register", `{"pcIdentifier": "LAB-PC-01"}`)
+	data := assertSuccessEnvelope(t, registered, http.StatusOK)
+	pcID := data["pc"].(map[string]interface{})["pcId"].(string)
+
+	// Act
+	recorder := postClientAPI(router, "/api/client/heartbeat", `{"pcId": "`+pcID+`"}`)
+
+	// Assert
+	heartbeat := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.NotContains(t, data, "queuedSession")
+	assert.NotContains(t, heartbeat, "queuedSession")
+	sessionRepo.AssertNotCalled(t, "FindByClientPCID", mock.Anything, mock.Anything)
+}
+
+func TestHeartbeatViaREST_RejectsPCOfAnotherOwner(t *testing.T) {
+	// Arrange
+	_, repo, router := newTestRESTClientHandler()
+	foreign, err := clientpc.NewClientPC("550e8400-e29b-41d4-a716-446655440011", "OTHER-PC", "10.0.0.5", "550e8400-e29b-41d4-a716-446655440012")
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(context.Background(), foreign))
+
+	// Act
+	recorder := postClientAPI(router, "/api/client/heartbeat", `{"pcId": "`+foreign.PCID+`"}`)
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusNotFound, "PC_NOT_FOUND")
+	assert.Equal(t, clientpc.PCConnectionStatusOffline, repo.onlyPC(t).ConnectionStatus)
+}
+
+func TestExpireRESTClients_MarksSilentPCsOffline(t *testing.T) {
+	// Arrange
+	h, repo, router := newTestRESTClientHandler()
+	h.SetHeartbeatConfig(HeartbeatConfig{Interval: 20 * time.Second, MissedLimit: 3})
+	registered := postClientAPI(router, "/api/client/register", `{"pcIdentifier": "LAB-PC-01"}`)
+	pcID := assertSuccessEnvelope(t, registered, http.StatusOK)["pc"].(map[string]interface{})["pcId"].(string)
+
+	// Act
+	notYet := h.ExpireRESTClients(context.Background(), time.Now().Add(30*time.Second))
+	expired := h.ExpireRESTClients(context.Background(), time.Now().Add(2*time.Minute))
+
+	// Assert
+	assert.Equal(t, 0, notYet)
+	assert.Equal(t, 1, expired)
+	assert.Equal(t, clientpc.PCConnectionStatusOffline, repo.onlyPC(t).ConnectionStatus)
+	assert.NotContains(t, h.ConnectedPCIDs(), pcID)
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ConnectionKind tipo de conexión WebSocket contabilizada por ConnectionCapacity
+type ConnectionKind string
+
+const (
+	ConnectionKindClient ConnectionKind = "client"
+	ConnectionKindAdmin  ConnectionKind = "admin"
+)
+
+// DefaultCapacityWarningRatio fracción del máximo a partir de la cual se avisa de que la capacidad se agota
+const DefaultCapacityWarningRatio = 0.8
+
+// CloseCodeServerAtCapacity código de cierre (1013 Try Again Later) con el que se rechazan conexiones por encima del máximo
+const CloseCodeServerAtCapacity = websocket.CloseTryAgainLater
+
+// ConnectionCapacityConfig máximo de conexiones por tipo (0 = sin límite) y umbral de aviso
+type ConnectionCapacityConfig struct {
+	MaxClientConnections int
+	MaxAdminConnections  int
+	WarningRatio         float64
+}
+
+// ConnectionCapacityStats métricas de capacidad de un tipo de conexión
+type ConnectionCapacityStats struct {
+	Current   int `json:"current"`
+	Max       int `json:"max"`
+	Threshold int `json:"warning_threshold"`
+	Peak      int `json:"peak"`
+	Warnings  int `json:"warnings"`
+	Rejected  int `json:"rejected"`
+}
+
+// CapacityWarningNotifier recibe el aviso cuando un tipo de conexión alcanza el umbral
+type CapacityWarningNotifier func(kind ConnectionKind, current, max, threshold int)
+
+// ConnectionCapacity cuenta las conexiones abiertas de clientes y administradores, avisa al cruzar
+// el umbral y rechaza las que superan el máximo
+type ConnectionCapacity struct {
+	config   ConnectionCapacityConfig
+	notifier CapacityWarningNotifier
+
+	mu     sync.Mutex
+	stats  map[ConnectionKind]*ConnectionCapacityStats
+	warned map[ConnectionKind]bool
+}
+
+// NewConnectionCapacity crea el contador; un WarningRatio fuera de (0, 1] usa DefaultCapacityWarningRatio
+func NewConnectionCapacity(config ConnectionCapacityConfig) *ConnectionCapacity {
+	if config.WarningRatio <= 0 || config.WarningRatio > 1 {
+		config.WarningRatio = DefaultCapacityWarningRatio
+	}
+
+	capacity := &ConnectionCapacity{
+		config: config,
+		stats:  make(map[ConnectionKind]*ConnectionCapacityStats),
+		warned: make(map[ConnectionKind]bool),
+	}
+	capacity.stats[ConnectionKindClient] = capacity.newStats(config.MaxClientConnections)
+	capacity.stats[ConnectionKindAdmin] = capacity.newStats(config.MaxAdminConnections)
+	return capacity
+}
+
+// SetWarningNotifier establece el callback que difunde el aviso de capacidad
+func (c *ConnectionCapacity) SetWarningNotifier(notifier CapacityWarningNotifier) {
+	c.notifier = notifier
+}
+
+// Acquire reserva una conexión del tipo indicado. Retorna false si se alcanzó el máximo;
+// si no, la función que la libera al cerrarse.
+func (c *ConnectionCapacity) Acquire(kind ConnectionKind) (func(), bool) {
+	c.mu.Lock()
+	stats := c.stats[kind]
+	if stats.Max > 0 && stats.Current >= stats.Max {
+		stats.Rejected++
+		c.mu.Unlock()
+
+		log.Printf("🚫 CAPACITY: Rejecting %s connection, maximum of %d reached", kind, stats.Max)
+		return nil, false
+	}
+
+	stats.Current++
+	stats.Peak = max(stats.Peak, stats.Current)
+
+	crossed := stats.Threshold > 0 && stats.Current >= stats.Threshold && !c.warned[kind]
+	if crossed {
+		c.warned[kind] = true
+		stats.Warnings++
+	}
+	current, maxConnections, threshold := stats.Current, stats.Max, stats.Threshold
+	c.mu.Unlock()
+
+	if crossed {
+		log.Printf("⚠️ CAPACITY: %d/%d %s connections, warning threshold %d reached", current, maxConnections, kind, threshold)
+		if c.notifier != nil {
+			c.notifier(kind, current, maxConnections, threshold)
+		}
+	}
+
+	var once sync.Once
+	return func() { once.Do(func() { c.release(kind) }) }, true
+}
+
+// Stats retorna una copia de las métricas por tipo de conexión
+func (c *ConnectionCapacity) Stats() map[ConnectionKind]ConnectionCapacityStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := make(map[ConnectionKind]ConnectionCapacityStats, len(c.stats))
+	for kind, stats := range c.stats {
+		snapshot[kind] = *stats
+	}
+	return snapshot
+}
+
+// release libera una conexión; el aviso se rearma al bajar del umbral
+func (c *ConnectionCapacity) release(kind ConnectionKind) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats[kind]
+	if stats.Current > 0 {
+		stats.Current--
+	}
+	if stats.Current < stats.Threshold {
+		c.warned[kind] = false
+	}
+}
+
+// newStats calcula el umbral de aviso para un máximo (sin umbral si no hay máximo)
+func (c *ConnectionCapacity) newStats(maxConnections int) *ConnectionCapacityStats {
+	stats := &ConnectionCapacityStats{Max: maxConnections}
+	if maxConnections > 0 {
+		stats.Threshold = int(math.Ceil(float64(maxConnections) * c.config.WarningRatio))
+	}
+	return stats
+}
+
+// rejectAtCapacity cierra una conexión recién aceptada con CloseCodeServerAtCapacity
+func rejectAtCapacity(conn *websocket.Conn, kind ConnectionKind) {
+	closeMessage := websocket.FormatCloseMessage(CloseCodeServerAtCapacity, "server at capacity: too many "+string(kind)+" connections")
+	conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second))
+}
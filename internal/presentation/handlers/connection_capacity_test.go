@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionCapacity_WarnsOnceWhenCrossingThreshold(t *testing.T) {
+	// Arrange
+	capacity := NewConnectionCapacity(ConnectionCapacityConfig{MaxClientConnections: 10, WarningRatio: 0.8})
+	var warnings []int
+	capacity.SetWarningNotifier(func(kind ConnectionKind, current, max, threshold int) {
+		assert.Equal(t, ConnectionKindClient, kind)
+		assert.Equal(t, 10, max)
+		assert.Equal(t, 8, threshold)
+		warnings = append(warnings, current)
+	})
+
+	// Act
+	releases := make([]func(), 0, 9)
+	for i := 0; i < 9; i++ {
+		release, ok := capacity.Acquire(ConnectionKindClient)
+		require.True(t, ok)
+		releases = append(releases, release)
+	}
+
+	// Assert
+	assert.Equal(t, []int{8}, warnings)
+
+	// Al bajar del umbral el aviso se rearma
+	releases[8]()
+	releases[7]()
+	_, ok := capacity.Acquire(ConnectionKindClient)
+	require.True(t, ok)
+	assert.Equal(t, []int{8, 8}, warnings)
+
+	stats := capacity.Stats()[ConnectionKindClient]
+	assert.Equal(t, 8, stats.Current)
+	assert.Equal(t, 9, stats.Peak)
+	assert.Equal(t, 2, stats.Warnings)
+}
+
+func TestConnectionCapacity_RejectsAboveMaximum(t *testing.T) {
+	// Arrange
+	capacity := NewConnectionCapacity(ConnectionCapacityConfig{MaxAdminConnections: 2})
+	first, ok := capacity.Acquire(ConnectionKindAdmin)
+	require.True(t, ok)
+	_, ok = capacity.Acquire(ConnectionKindAdmin)
+	require.True(t, ok)
+
+	// Act
+	_, rejected := capacity.Acquire(ConnectionKindAdmin)
+
+	// Assert
+	assert.False(t, rejected)
+	assert.Equal(t, 1, capacity.Stats()[ConnectionKindAdmin].Rejected)
+
+	// Los clientes tienen su propio límite (sin límite en este caso)
+	_, ok = capacity.Acquire(ConnectionKindClient)
+	assert.True(t, ok)
+
+	// Liberar dos veces la misma conexión solo cuenta una
+	first()
+	first()
+	assert.Equal(t, 1, capacity.Stats()[ConnectionKindAdmin].Current)
+}
+
+func TestHandleWebSocket_RejectsClientAtCapacityWithCloseCode(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	capacity := NewConnectionCapacity(ConnectionCapacityConfig{MaxClientConnections: 1})
+	_, ok := capacity.Acquire(ConnectionKindClient)
+	require.True(t, ok)
+	h.SetConnectionCapacity(capacity)
+
+	router := newTestRouter("")
+	router.GET("/ws/client", h.HandleWebSocket)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	// Act
+	clientSide, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/client", nil)
+	require.NoError(t, err)
+	defer clientSide.Close()
+
+	require.NoError(t, clientSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, _, err = clientSide.ReadMessage()
+
+	// Assert
+	var closeErr *websocket.CloseError
+	require.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, CloseCodeServerAtCapacity, closeErr.Code)
+	assert.Equal(t, 1, capacity.Stats()[ConnectionKindClient].Rejected)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"sync"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+)
+
+// framePipelineQueueSize frames de una conexión que pueden esperar a ser procesados fuera de la lectura
+const framePipelineQueueSize = 4
+
+// framePipeline procesa en orden, en su propia goroutine, los frames de una conexión. Se usa cuando los frames
+// se reducen de resolución, para que decodificar y recodificar no bloquee la lectura del socket (pings,
+// input acks, fin de sesión). Todos los frames de la conexión pasan por él: así conservan su orden y el estado
+// de grabación de la conexión solo lo toca una goroutine.
+type framePipeline struct {
+	jobs     chan func()
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newFramePipeline arranca la goroutine del pipeline
+func newFramePipeline(queueSize int) *framePipeline {
+	p := &framePipeline{
+		jobs: make(chan func(), queueSize),
+		done: make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *framePipeline) run() {
+	defer close(p.done)
+	for job := range p.jobs {
+		job()
+	}
+}
+
+// tryEnqueue encola sin bloquear; retorna false si la cola está llena
+func (p *framePipeline) tryEnqueue(job func()) bool {
+	select {
+	case p.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// enqueue espera a que haya hueco: la lectura del socket se frena en lugar de perder frames de grabación
+func (p *framePipeline) enqueue(job func()) {
+	p.jobs <- job
+}
+
+// stop deja de aceptar frames y espera a que terminen los encolados
+func (p *framePipeline) stop() {
+	p.stopOnce.Do(func() { close(p.jobs) })
+	<-p.done
+}
+
+// downscalesFrames indica si los frames que superan el límite se reducen, y por tanto se procesan en el pipeline
+func (h *WebSocketHandler) downscalesFrames() bool {
+	return h.frameLimit.Enabled() && h.frameLimit.Mode == videoservice.OversizeFrameDownscale
+}
+
+// framePipelineFor retorna el pipeline de la conexión, creándolo con su primer frame; nil si los frames se
+// procesan en la goroutine de lectura. Solo se llama desde esa goroutine.
+func (h *WebSocketHandler) framePipelineFor(clientConn *ClientConnection) *framePipeline {
+	if !h.downscalesFrames() {
+		return nil
+	}
+	if clientConn.frames == nil {
+		clientConn.frames = newFramePipeline(framePipelineQueueSize)
+	}
+	return clientConn.frames
+}
+
+// stopFramePipeline espera a que se procesen los frames pendientes de la conexión
+func (c *ClientConnection) stopFramePipeline() {
+	if c.frames != nil {
+		c.frames.stop()
+	}
+}
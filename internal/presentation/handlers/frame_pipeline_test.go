@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+)
+
+func TestFramePipeline_RunsJobsInOrderAndDropsWhenFull(t *testing.T) {
+	// Arrange - el primer trabajo bloquea la goroutine hasta que se libera
+	pipeline := newFramePipeline(1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var order []int
+	require.True(t, pipeline.tryEnqueue(func() { close(started); <-release; order = append(order, 1) }))
+	<-started
+	require.True(t, pipeline.tryEnqueue(func() { order = append(order, 2) }))
+
+	// Act
+	dropped := !pipeline.tryEnqueue(func() { order = append(order, 3) })
+	close(release)
+	pipeline.stop()
+
+	// Assert
+	assert.True(t, dropped, "con la cola llena el frame se descarta")
+	assert.Equal(t, []int{1, 2}, order)
+}
+
+func TestFramePipelineFor_OnlyWhenFramesAreDownscaled(t *testing.T) {
+	// Arrange
+	var processed atomic.Int32
+	h, _ := newTestWebSocketHandler()
+	clientConn := &ClientConnection{}
+
+	// Act & Assert - sin límite o en modo reject los frames se procesan en la lectura
+	assert.Nil(t, h.framePipelineFor(clientConn))
+	h.SetFrameResolutionLimit(videoservice.FrameResolutionLimit{MaxWidth: 320, Mode: videoservice.OversizeFrameReject})
+	assert.Nil(t, h.framePipelineFor(clientConn))
+
+	h.SetFrameResolutionLimit(videoservice.FrameResolutionLimit{MaxWidth: 320, Mode: videoservice.OversizeFrameDownscale})
+	pipeline := h.framePipelineFor(clientConn)
+	require.NotNil(t, pipeline)
+	assert.Same(t, pipeline, h.framePipelineFor(clientConn))
+
+	pipeline.enqueue(func() { processed.Add(1) })
+	clientConn.stopFramePipeline()
+	assert.Equal(t, int32(1), processed.Load())
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+)
+
+// RejectedFramesAbuseThreshold frames inválidos de una misma conexión a partir de los que se registra un posible abuso
+const RejectedFramesAbuseThreshold = 10
+
+// SetValidateJPEGFrames exige que los frames de streaming y grabación sean JPEG (activado por defecto)
+func (h *WebSocketHandler) SetValidateJPEGFrames(enabled bool) {
+	h.validateJPEGFrames = enabled
+}
+
+// acceptFrameData comprueba que el frame decodificado es un JPEG y, si no lo es, lo cuenta contra la conexión
+func (h *WebSocketHandler) acceptFrameData(clientConn *ClientConnection, sessionID string, frameData []byte) bool {
+	if !h.validateJPEGFrames {
+		return true
+	}
+
+	if err := videoservice.ValidateJPEGFrame(frameData); err != nil {
+		rejected := clientConn.rejectedFrames.Add(1)
+		log.Printf("❌ FRAME VALIDATION: Dropping frame from PC %s for session %s (%d bytes): %v (rejected so far: %d)",
+			clientConn.PCID, sessionID, len(frameData), err, rejected)
+		if rejected == RejectedFramesAbuseThreshold {
+			log.Printf("🚨 FRAME VALIDATION: PC %s has sent %d invalid frames on this connection, possible abuse",
+				clientConn.PCID, rejected)
+		}
+		return false
+	}
+	return true
+}
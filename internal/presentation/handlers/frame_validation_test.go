@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+func TestNewWebSocketHandler_ValidatesJPEGFramesByDefault(t *testing.T) {
+	// Arrange
+	h := NewWebSocketHandler(nil, nil, nil, nil, nil, nil)
+	clientConn := &ClientConnection{PCID: testTargetPCID, IsAuth: true}
+
+	// Act
+	accepted := h.acceptFrameData(clientConn, "session-1", []byte("random bytes"))
+
+	// Assert
+	assert.False(t, accepted)
+	assert.Equal(t, int64(1), clientConn.rejectedFrames.Load())
+}
+
+func TestProcessScreenFrame_NonJPEGPayloadIsCountedAndDropped(t *testing.T) {
+	// Arrange - sessionService nil: si el frame pasara la validación, el handler fallaría al validar permisos
+	h, _ := newTestWebSocketHandler()
+	h.SetValidateJPEGFrames(true)
+	clientConn := &ClientConnection{PCID: testTargetPCID, IsAuth: true}
+
+	// Act
+	for i := int64(0); i < 3; i++ {
+		h.processScreenFrame(clientConn, dto.ScreenFrame{SessionID: "session-1", SequenceNum: i, FrameData: []byte("random bytes")})
+	}
+
+	// Assert
+	assert.Equal(t, int64(3), clientConn.rejectedFrames.Load())
+}
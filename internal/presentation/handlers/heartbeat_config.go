@@ -0,0 +1,40 @@
+package handlers
+
+import "time"
+
+// Valores por defecto del heartbeat de los clientes: se recomienda uno cada 30 s y una conexión se
+// considera inactiva tras perder 3 seguidos
+const (
+	DefaultHeartbeatInterval    = 30 * time.Second
+	DefaultHeartbeatMissedLimit = 3
+)
+
+// HeartbeatConfig cadencia de heartbeat que el servidor recomienda a los clientes y cuántos heartbeats
+// pueden faltar antes de dar la conexión por inactiva. El timeout siempre es MissedLimit × Interval
+// para que lo que se anuncia al cliente y lo que aplica el servidor no se desalineen.
+type HeartbeatConfig struct {
+	Interval    time.Duration
+	MissedLimit int
+}
+
+// DefaultHeartbeatConfig heartbeat cada DefaultHeartbeatInterval, inactivo tras DefaultHeartbeatMissedLimit perdidos
+func DefaultHeartbeatConfig() HeartbeatConfig {
+	return HeartbeatConfig{Interval: DefaultHeartbeatInterval, MissedLimit: DefaultHeartbeatMissedLimit}
+}
+
+// StaleTimeout tiempo sin mensajes del cliente tras el que su conexión se considera inactiva
+func (c HeartbeatConfig) StaleTimeout() time.Duration {
+	return time.Duration(c.MissedLimit) * c.Interval
+}
+
+// SetHeartbeatConfig configura el heartbeat anunciado a los clientes y el timeout de inactividad derivado.
+// Valores <= 0 mantienen los de por defecto.
+func (h *WebSocketHandler) SetHeartbeatConfig(config HeartbeatConfig) {
+	if config.Interval <= 0 {
+		config.Interval = DefaultHeartbeatInterval
+	}
+	if config.MissedLimit <= 0 {
+		config.MissedLimit = DefaultHeartbeatMissedLimit
+	}
+	h.heartbeat = config
+}
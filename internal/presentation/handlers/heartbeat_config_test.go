@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHandleClientAuth_AnnouncesConfiguredHeartbeatInterval(t *testing.T) {
+	// Arrange
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByUsername", "client").Return(user.NewUser(testRESTOwnerUserID, "client", "", string(hashedPassword), user.RoleClientUser), nil)
+
+	h := NewWebSocketHandler(userservice.NewAuthService(userRepo, "test-secret"), nil, nil, nil, nil, nil)
+	h.SetHeartbeatConfig(HeartbeatConfig{Interval: 15 * time.Second, MissedLimit: 4})
+	clientSide, clientConn := connectTestClient(t, h)
+
+	// Act
+	h.handleClientAuth(clientConn.Conn, clientConn, map[string]interface{}{"username": "client", "password": "password"})
+
+	// Assert
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, clientSide.ReadJSON(&message))
+	require.Equal(t, dto.MessageTypeClientAuthResp, message.Type)
+	data := message.Data.(map[string]interface{})
+	assert.Equal(t, true, data["success"])
+	assert.Equal(t, float64(15), data["heartbeatIntervalSeconds"])
+	assert.Equal(t, float64(60), data["heartbeatTimeoutSeconds"])
+}
+
+func TestExpireRESTClients_UsesTimeoutDerivedFromHeartbeatInterval(t *testing.T) {
+	// Arrange
+	h, _, router := newTestRESTClientHandler()
+	h.SetHeartbeatConfig(HeartbeatConfig{Interval: 10 * time.Second, MissedLimit: 3})
+	registered := postClientAPI(router, "/api/client/register", `{"pcIdentifier": "LAB-PC-01"}`)
+	data := assertSuccessEnvelope(t, registered, http.StatusOK)
+
+	// Act - dos heartbeats perdidos no bastan, el tercero sí
+	registeredAt := time.Now()
+	afterTwoMissed := h.ExpireRESTClients(context.Background(), registeredAt.Add(20*time.Second))
+	afterThreeMissed := h.ExpireRESTClients(context.Background(), registeredAt.Add(31*time.Second))
+
+	// Assert
+	assert.Equal(t, float64(10), data["heartbeatIntervalSeconds"])
+	assert.Equal(t, float64(30), data["heartbeatTimeoutSeconds"])
+	assert.Equal(t, 0, afterTwoMissed)
+	assert.Equal(t, 1, afterThreeMissed)
+}
+
+func TestServeClientConnection_ClosesSilentClientAfterStaleTimeout(t *testing.T) {
+	// Arrange
+	h, sessionRepo, _ := newTestDisconnectHandler(t, remotesession.StatusFailed)
+	h.SetHeartbeatConfig(HeartbeatConfig{Interval: 50 * time.Millisecond, MissedLimit: 2})
+
+	// Act - el cliente no envía ningún mensaje
+	_, served := serveTestClient(t, h)
+
+	// Assert
+	waitServed(t, served)
+	sessionRepo.AssertExpectations(t)
+}
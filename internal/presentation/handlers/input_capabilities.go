@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// ErrInputEventNotSupported el PC declaró en su registro que no admite el event_type del input_command
+var ErrInputEventNotSupported = errors.New("input event type not supported by client")
+
+// InputCommandRejectedReasonNotSupported motivo enviado al administrador en input_command_rejected
+const InputCommandRejectedReasonNotSupported = "INPUT_EVENT_NOT_SUPPORTED"
+
+// normalizeInputEventTypes pasa a minúsculas y quita duplicados y vacíos de los event_type declarados por el
+// cliente. Una lista vacía es nil: el cliente no los declaró y se le envían todos, como a los clientes antiguos.
+func normalizeInputEventTypes(eventTypes []string) []string {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, eventType := range eventTypes {
+		eventType = strings.ToLower(strings.TrimSpace(eventType))
+		if eventType != "" && !seen[eventType] {
+			seen[eventType] = true
+			normalized = append(normalized, eventType)
+		}
+	}
+	return normalized
+}
+
+// supportsInputEvent indica si el cliente admite el event_type; sin declaración los admite todos
+func (c *ClientConnection) supportsInputEvent(eventType string) bool {
+	if c.supportedInputEvents == nil {
+		return true
+	}
+	eventType = strings.ToLower(strings.TrimSpace(eventType))
+	for _, supported := range c.supportedInputEvents {
+		if supported == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportedInputEvents event_type que el PC declaró al registrarse; false si no está conectado o no los declaró
+func (h *WebSocketHandler) SupportedInputEvents(clientPCID string) ([]string, bool) {
+	h.mutex.RLock()
+	clientConn, exists := h.pcConnections[clientPCID]
+	h.mutex.RUnlock()
+
+	if !exists || clientConn.supportedInputEvents == nil {
+		return nil, false
+	}
+	return append([]string(nil), clientConn.supportedInputEvents...), true
+}
+
+// rejectUnsupportedInputCommand avisa al administrador de que el PC no admite el tipo de evento del comando,
+// para que la UI deje de enviarlo en lugar de fallar en silencio en el cliente
+func (h *AdminWebSocketHandler) rejectUnsupportedInputCommand(adminConn *AdminConnection, clientPCID string, inputCommand dto.InputCommand) {
+	log.Printf("🚫 INPUT COMMAND: Client %s does not support event type %q, rejected command from admin %s",
+		clientPCID, inputCommand.EventType, adminConn.Username)
+
+	supported, _ := h.clientWSHandler.SupportedInputEvents(clientPCID)
+	rejection := dto.WebSocketMessage{
+		Type: "input_command_rejected",
+		Data: map[string]interface{}{
+			"session_id":             inputCommand.SessionID,
+			"event_type":             inputCommand.EventType,
+			"action":                 inputCommand.Action,
+			"reason":                 InputCommandRejectedReasonNotSupported,
+			"supported_input_events": supported,
+			"timestamp":              time.Now().Unix(),
+		},
+	}
+	if err := adminConn.writer().WriteJSON(rejection); err != nil {
+		log.Printf("Error sending input command rejection to admin %s: %v", adminConn.UserID, err)
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// newInputCapabilityTest sesión activa con un PC conectado que declaró los event_type indicados al registrarse
+func newInputCapabilityTest(t *testing.T, supportedInputEvents []string) (*AdminWebSocketHandler, string, *websocket.Conn, *websocket.Conn) {
+	t.Helper()
+
+	session, err := remotesession.NewRemoteSession(testAdminUserID, testTargetPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+
+	clientHandler, _ := newTestWebSocketHandler()
+	clientSide, clientConn := connectTestClient(t, clientHandler)
+	clientConn.supportedInputEvents = normalizeInputEventTypes(supportedInputEvents)
+
+	h := NewAdminWebSocketHandler(nil, remotesessionservice.NewRemoteSessionService(sessionRepo, nil, nil, nil, nil))
+	h.SetClientWSHandler(clientHandler)
+	adminSide := connectTestAdmin(t, h)
+	return h, session.SessionID(), clientSide, adminSide
+}
+
+func TestHandleInputCommand_ForwardsSupportedEventType(t *testing.T) {
+	// Arrange
+	h, sessionID, clientSide, adminSide := newInputCapabilityTest(t, []string{" Keyboard ", "mouse", "keyboard"})
+
+	// Act
+	h.handleInputCommand(h.adminConnections["conn-1"], map[string]interface{}{
+		"session_id": sessionID,
+		"event_type": "keyboard",
+		"action":     "keydown",
+	})
+
+	// Assert
+	assert.Equal(t, 1, countInputCommands(t, clientSide))
+
+	var message dto.WebSocketMessage
+	require.NoError(t, adminSide.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	assert.Error(t, adminSide.ReadJSON(&message), "el administrador no debe recibir ningún rechazo")
+
+	supported, declared := h.clientWSHandler.SupportedInputEvents(testTargetPCID)
+	assert.True(t, declared)
+	assert.Equal(t, []string{"keyboard", "mouse"}, supported)
+}
+
+func TestHandleInputCommand_RejectsUnsupportedEventTypeWithFeedback(t *testing.T) {
+	// Arrange - cliente táctil que solo admite touch
+	h, sessionID, clientSide, adminSide := newInputCapabilityTest(t, []string{"touch"})
+
+	// Act
+	h.handleInputCommand(h.adminConnections["conn-1"], map[string]interface{}{
+		"session_id": sessionID,
+		"event_type": "mouse",
+		"action":     "click",
+	})
+
+	// Assert
+	assert.Equal(t, 0, countInputCommands(t, clientSide))
+
+	var rejection dto.WebSocketMessage
+	require.NoError(t, adminSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, adminSide.ReadJSON(&rejection))
+	assert.Equal(t, "input_command_rejected", rejection.Type)
+	data := rejection.Data.(map[string]interface{})
+	assert.Equal(t, sessionID, data["session_id"])
+	assert.Equal(t, "mouse", data["event_type"])
+	assert.Equal(t, InputCommandRejectedReasonNotSupported, data["reason"])
+	assert.Equal(t, []interface{}{"touch"}, data["supported_input_events"])
+}
+
+func TestSendInputCommandToClient_UndeclaredCapabilitiesAcceptAllEventTypes(t *testing.T) {
+	// Arrange - los clientes antiguos no envían supportedInputEvents
+	clientHandler, _ := newTestWebSocketHandler()
+	clientSide, _ := connectTestClient(t, clientHandler)
+
+	// Act
+	err := clientHandler.SendInputCommandToClient(testTargetPCID, dto.InputCommand{SessionID: "session-1", EventType: "mouse", Action: "move"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, countInputCommands(t, clientSide))
+	_, declared := clientHandler.SupportedInputEvents(testTargetPCID)
+	assert.False(t, declared)
+}
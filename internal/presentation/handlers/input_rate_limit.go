@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Valores por defecto del límite de comandos de input por sesión. Un usuario real moviendo el ratón
+// genera del orden de 60 comandos/s, así que el límite solo corta inundaciones.
+const (
+	DefaultInputCommandsPerSecond = 100
+	DefaultInputCommandBurst      = 200
+
+	// inputRateWarnEvery intervalo mínimo entre avisos de throttling al administrador de una sesión
+	inputRateWarnEvery = time.Second
+)
+
+// InputRateLimitConfig tasa sostenida y ráfaga máxima de input_command por sesión (CommandsPerSecond <= 0 = sin límite)
+type InputRateLimitConfig struct {
+	CommandsPerSecond float64
+	Burst             int
+}
+
+// DefaultInputRateLimitConfig límite de DefaultInputCommandsPerSecond con ráfagas de DefaultInputCommandBurst
+func DefaultInputRateLimitConfig() InputRateLimitConfig {
+	return InputRateLimitConfig{CommandsPerSecond: DefaultInputCommandsPerSecond, Burst: DefaultInputCommandBurst}
+}
+
+// sessionInputBucket token bucket de una sesión
+type sessionInputBucket struct {
+	tokens       float64
+	lastRefillAt time.Time
+	dropped      int // descartados desde el último aviso
+	lastWarnedAt time.Time
+}
+
+// InputRateLimiter limita por sesión los comandos de input que se reenvían al cliente, independientemente
+// de quién los envíe, para que un AdminWeb defectuoso o malicioso no inunde el PC. El exceso se descarta.
+type InputRateLimiter struct {
+	config InputRateLimitConfig
+
+	sessions map[string]*sessionInputBucket
+	mutex    sync.Mutex
+}
+
+// NewInputRateLimiter crea el limitador; una ráfaga <= 0 equivale a un segundo de comandos
+func NewInputRateLimiter(config InputRateLimitConfig) *InputRateLimiter {
+	if config.Burst <= 0 {
+		config.Burst = int(math.Max(1, math.Ceil(config.CommandsPerSecond)))
+	}
+
+	return &InputRateLimiter{
+		config:   config,
+		sessions: make(map[string]*sessionInputBucket),
+	}
+}
+
+// Allow consume un comando de la sesión. Si se descarta y toca avisar al administrador, dropped es el
+// número de comandos descartados desde el último aviso (0 = no avisar).
+func (l *InputRateLimiter) Allow(sessionID string, now time.Time) (allowed bool, dropped int) {
+	if l == nil || l.config.CommandsPerSecond <= 0 {
+		return true, 0
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	bucket, exists := l.sessions[sessionID]
+	if !exists {
+		bucket = &sessionInputBucket{tokens: float64(l.config.Burst), lastRefillAt: now}
+		l.sessions[sessionID] = bucket
+	}
+
+	elapsed := now.Sub(bucket.lastRefillAt).Seconds()
+	if elapsed > 0 {
+		bucket.tokens = math.Min(float64(l.config.Burst), bucket.tokens+elapsed*l.config.CommandsPerSecond)
+		bucket.lastRefillAt = now
+	}
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+
+	bucket.dropped++
+	if now.Sub(bucket.lastWarnedAt) < inputRateWarnEvery {
+		return false, 0
+	}
+	dropped = bucket.dropped
+	bucket.dropped = 0
+	bucket.lastWarnedAt = now
+	return false, dropped
+}
+
+// Forget descarta el estado de una sesión terminada
+func (l *InputRateLimiter) Forget(sessionID string) {
+	if l == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.sessions, sessionID)
+}
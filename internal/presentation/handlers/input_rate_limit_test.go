@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+func TestInputRateLimiter_ThrottlesBurstAndRefills(t *testing.T) {
+	// Arrange
+	limiter := NewInputRateLimiter(InputRateLimitConfig{CommandsPerSecond: 10, Burst: 5})
+	now := time.Now()
+
+	// Act
+	allowed, warnings := 0, 0
+	for i := 0; i < 20; i++ {
+		ok, dropped := limiter.Allow("session-1", now)
+		if ok {
+			allowed++
+		}
+		if dropped > 0 {
+			warnings++
+		}
+	}
+
+	// Assert
+	assert.Equal(t, 5, allowed)
+	assert.Equal(t, 1, warnings, "the admin is warned once per interval, not per dropped command")
+
+	// Otra sesión tiene su propio límite
+	ok, _ := limiter.Allow("session-2", now)
+	assert.True(t, ok)
+
+	// Tras medio segundo se recuperan 5 comandos; el siguiente aviso acumula los descartes
+	later := now.Add(500 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		ok, _ := limiter.Allow("session-1", later)
+		require.True(t, ok)
+	}
+	ok, dropped := limiter.Allow("session-1", now.Add(1500*time.Millisecond))
+	assert.True(t, ok)
+	assert.Zero(t, dropped)
+}
+
+func TestInputRateLimiter_ZeroRateDisablesLimit(t *testing.T) {
+	// Arrange
+	limiter := NewInputRateLimiter(InputRateLimitConfig{})
+
+	// Act & Assert
+	for i := 0; i < 1000; i++ {
+		ok, _ := limiter.Allow("session-1", time.Now())
+		require.True(t, ok)
+	}
+}
+
+func TestHandleInputCommand_BurstBeyondLimitIsThrottled(t *testing.T) {
+	// Arrange
+	session, err := remotesession.NewRemoteSession(testAdminUserID, testTargetPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+
+	clientHandler, _ := newTestWebSocketHandler()
+	clientSide, _ := connectTestClient(t, clientHandler)
+
+	h := NewAdminWebSocketHandler(nil, remotesessionservice.NewRemoteSessionService(sessionRepo, nil, nil, nil, nil))
+	h.SetClientWSHandler(clientHandler)
+	h.SetInputRateLimit(InputRateLimitConfig{CommandsPerSecond: 1, Burst: 3})
+	adminSide := connectTestAdmin(t, h)
+
+	// Act
+	for i := 0; i < 10; i++ {
+		h.handleInputCommand(h.adminConnections["conn-1"], map[string]interface{}{
+			"session_id": session.SessionID(),
+			"event_type": "mouse",
+			"action":     "move",
+		})
+	}
+
+	// Assert - solo la ráfaga permitida llega al cliente
+	assert.Equal(t, 3, countInputCommands(t, clientSide))
+
+	var warning dto.WebSocketMessage
+	require.NoError(t, adminSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, adminSide.ReadJSON(&warning))
+	assert.Equal(t, "input_rate_limited", warning.Type)
+	assert.Equal(t, session.SessionID(), warning.Data.(map[string]interface{})["session_id"])
+
+	// Un solo aviso por intervalo
+	require.NoError(t, adminSide.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	assert.Error(t, adminSide.ReadJSON(&warning))
+}
+
+// countInputCommands cuenta los input_command que recibe el cliente simulado hasta que deja de recibir mensajes
+func countInputCommands(t *testing.T, clientSide *websocket.Conn) int {
+	t.Helper()
+
+	count := 0
+	for {
+		var message dto.WebSocketMessage
+		require.NoError(t, clientSide.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+		if err := clientSide.ReadJSON(&message); err != nil {
+			return count
+		}
+		if message.Type == "input_command" {
+			count++
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// NotificationScope PCs de los que un administrador recibe notificaciones
+type NotificationScope string
+
+const (
+	// NotificationScopeAll notificaciones de todos los PCs (por defecto)
+	NotificationScopeAll NotificationScope = "all"
+	// NotificationScopeOwned solo notificaciones de los PCs del administrador: los que fijó y los de las sesiones
+	// activas que controla
+	NotificationScopeOwned NotificationScope = "owned"
+)
+
+// adminPCLookupTimeout tiempo máximo para resolver los PCs de un administrador con alcance "owned"
+const adminPCLookupTimeout = 2 * time.Second
+
+var (
+	// ErrUnknownNotificationCategory la categoría no es uno de los broadcasts a administradores
+	ErrUnknownNotificationCategory = errors.New("unknown notification category")
+	// ErrUnknownNotificationScope el alcance no es "all" ni "owned"
+	ErrUnknownNotificationScope = errors.New("unknown notification scope")
+)
+
+// notificationCategories broadcasts a administradores que se pueden silenciar; la categoría es el tipo del mensaje
+var notificationCategories = map[string]bool{
+	"pc_connected":           true,
+	"pc_disconnected":        true,
+	"pc_registered":          true,
+	"pc_status_changed":      true,
+	"pc_list_update":         true,
+	"admin_capacity_warning": true,
+	"pc_list_delta":          true,
+}
+
+// notificationPreferences preferencias de un administrador
+type notificationPreferences struct {
+	scope NotificationScope
+	muted map[string]bool
+}
+
+// adminNotificationPreferences preferencias de notificación por administrador (UserID, no conexión). Con
+// repositorio se cargan al conectarse el administrador y se guardan en cada cambio, así sobreviven a un reinicio
+// del servidor. Sin preferencias se reciben todas las notificaciones.
+type adminNotificationPreferences struct {
+	preferences map[string]*notificationPreferences
+	loaded      map[string]bool
+	repository  interfaces.INotificationPreferenceRepository
+	mutex       sync.RWMutex
+}
+
+func newAdminNotificationPreferences() *adminNotificationPreferences {
+	return &adminNotificationPreferences{
+		preferences: make(map[string]*notificationPreferences),
+		loaded:      make(map[string]bool),
+	}
+}
+
+// SetNotificationPreferenceRepository persiste las preferencias de notificación de los administradores (nil = solo
+// en memoria)
+func (h *AdminWebSocketHandler) SetNotificationPreferenceRepository(repository interfaces.INotificationPreferenceRepository) {
+	h.notificationPrefs.mutex.Lock()
+	defer h.notificationPrefs.mutex.Unlock()
+	h.notificationPrefs.repository = repository
+}
+
+// SetPinnedPCRepository fuente de los PCs fijados que forman parte del alcance "owned" (nil = solo las sesiones)
+func (h *AdminWebSocketHandler) SetPinnedPCRepository(repository interfaces.IPinnedPCRepository) {
+	h.pinnedPCRepository = repository
+}
+
+// load carga las preferencias guardadas del administrador la primera vez que se conecta. Un error se registra y
+// se reintenta en la siguiente conexión; mientras tanto se aplican las que haya en memoria.
+func (p *adminNotificationPreferences) load(ctx context.Context, adminUserID string) {
+	p.mutex.RLock()
+	repository, loaded := p.repository, p.loaded[adminUserID]
+	p.mutex.RUnlock()
+	if repository == nil || loaded {
+		return
+	}
+
+	record, err := repository.FindByAdmin(ctx, adminUserID)
+	if err != nil {
+		log.Printf("❌ NOTIFICATIONS: Error loading preferences of admin %s: %v", adminUserID, err)
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.loaded[adminUserID] {
+		return
+	}
+	p.loaded[adminUserID] = true
+	if record == nil {
+		return
+	}
+	p.update(adminUserID, func(prefs *notificationPreferences) {
+		if NotificationScope(record.Scope) == NotificationScopeOwned {
+			prefs.scope = NotificationScopeOwned
+		}
+		for _, category := range record.MutedCategories {
+			if notificationCategories[category] {
+				prefs.muted[category] = true
+			}
+		}
+	})
+}
+
+// persist guarda las preferencias vigentes del administrador
+func (p *adminNotificationPreferences) persist(ctx context.Context, adminUserID string) error {
+	p.mutex.RLock()
+	repository := p.repository
+	p.mutex.RUnlock()
+	if repository == nil {
+		return nil
+	}
+
+	preferences := p.snapshot(adminUserID)
+	return repository.Save(ctx, &interfaces.NotificationPreferenceRecord{
+		AdminUserID:     adminUserID,
+		Scope:           preferences.Scope,
+		MutedCategories: preferences.MutedCategories,
+	})
+}
+
+// validateNotificationCategories comprueba que todas las categorías existen
+func validateNotificationCategories(categories []string) error {
+	for _, category := range categories {
+		if !notificationCategories[category] {
+			return fmt.Errorf("%w: %s", ErrUnknownNotificationCategory, category)
+		}
+	}
+	return nil
+}
+
+// update aplica los cambios validados a las preferencias del administrador; llamar con el mutex tomado
+func (p *adminNotificationPreferences) update(adminUserID string, fn func(prefs *notificationPreferences)) {
+	prefs, exists := p.preferences[adminUserID]
+	if !exists {
+		prefs = &notificationPreferences{scope: NotificationScopeAll, muted: make(map[string]bool)}
+		p.preferences[adminUserID] = prefs
+	}
+	fn(prefs)
+}
+
+// subscribe reactiva las categorías y, si scope no está vacío, cambia el alcance
+func (p *adminNotificationPreferences) subscribe(adminUserID string, categories []string, scope string) error {
+	if err := validateNotificationCategories(categories); err != nil {
+		return err
+	}
+	newScope := NotificationScope(scope)
+	if scope != "" && newScope != NotificationScopeAll && newScope != NotificationScopeOwned {
+		return fmt.Errorf("%w: %s", ErrUnknownNotificationScope, scope)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.update(adminUserID, func(prefs *notificationPreferences) {
+		for _, category := range categories {
+			delete(prefs.muted, category)
+		}
+		if scope != "" {
+			prefs.scope = newScope
+		}
+	})
+	return nil
+}
+
+// unsubscribe silencia las categorías
+func (p *adminNotificationPreferences) unsubscribe(adminUserID string, categories []string) error {
+	if err := validateNotificationCategories(categories); err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.update(adminUserID, func(prefs *notificationPreferences) {
+		for _, category := range categories {
+			prefs.muted[category] = true
+		}
+	})
+	return nil
+}
+
+// snapshot preferencias vigentes del administrador
+func (p *adminNotificationPreferences) snapshot(adminUserID string) dto.NotificationPreferences {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	result := dto.NotificationPreferences{Scope: string(NotificationScopeAll), MutedCategories: []string{}}
+	prefs, exists := p.preferences[adminUserID]
+	if !exists {
+		return result
+	}
+	result.Scope = string(prefs.scope)
+	for category := range prefs.muted {
+		result.MutedCategories = append(result.MutedCategories, category)
+	}
+	sort.Strings(result.MutedCategories)
+	return result
+}
+
+// allows indica si el administrador quiere recibir la notificación. pcID es el PC al que se refiere; vacío en las
+// notificaciones que no son de un PC concreto, que el alcance no filtra.
+func (p *adminNotificationPreferences) allows(adminUserID, category, pcID string, scopes *adminPCScopes) bool {
+	p.mutex.RLock()
+	prefs, exists := p.preferences[adminUserID]
+	muted := exists && prefs.muted[category]
+	ownedOnly := exists && prefs.scope == NotificationScopeOwned
+	p.mutex.RUnlock()
+
+	if muted {
+		return false
+	}
+	return !ownedOnly || pcID == "" || scopes.owns(adminUserID, pcID)
+}
+
+// adminPCScopes PCs de cada administrador con alcance "owned", resueltos una sola vez por broadcast
+type adminPCScopes struct {
+	resolve func(adminUserID string) map[string]bool
+	pcIDs   map[string]map[string]bool
+}
+
+func newAdminPCScopes(resolve func(adminUserID string) map[string]bool) *adminPCScopes {
+	return &adminPCScopes{resolve: resolve, pcIDs: make(map[string]map[string]bool)}
+}
+
+// owns indica si el PC es de los del administrador
+func (s *adminPCScopes) owns(adminUserID, pcID string) bool {
+	pcIDs, resolved := s.pcIDs[adminUserID]
+	if !resolved {
+		pcIDs = s.resolve(adminUserID)
+		s.pcIDs[adminUserID] = pcIDs
+	}
+	return pcIDs[pcID]
+}
+
+// adminPCIDs PCs del administrador para el alcance "owned": los que fijó y los de las sesiones activas que
+// controla. El OwnerUserID del PC no sirve: siempre es el usuario cliente que lo registró.
+func (h *AdminWebSocketHandler) adminPCIDs(adminUserID string) map[string]bool {
+	ctx, cancel := context.WithTimeout(context.Background(), adminPCLookupTimeout)
+	defer cancel()
+
+	pcIDs := make(map[string]bool)
+	if h.pinnedPCRepository != nil {
+		pinned, err := h.pinnedPCRepository.FindPCIDsByAdmin(ctx, adminUserID)
+		if err != nil {
+			log.Printf("❌ NOTIFICATIONS: Error loading pinned PCs of admin %s: %v", adminUserID, err)
+		}
+		for _, pcID := range pinned {
+			pcIDs[pcID] = true
+		}
+	}
+	if h.sessionService != nil {
+		sessions, err := h.sessionService.GetActiveSessions(ctx)
+		if err != nil {
+			log.Printf("❌ NOTIFICATIONS: Error loading active sessions of admin %s: %v", adminUserID, err)
+		}
+		for _, session := range sessions {
+			if session.AdminUserID() == adminUserID {
+				pcIDs[session.ClientPCID()] = true
+			}
+		}
+	}
+	return pcIDs
+}
+
+// handleNotificationSubscription procesa subscribe_notifications y unsubscribe_notifications y responde con las
+// preferencias vigentes; un mensaje sin categorías ni alcance solo las consulta
+func (h *AdminWebSocketHandler) handleNotificationSubscription(adminConn *AdminConnection, messageType string, data interface{}) {
+	var subscription dto.NotificationSubscription
+	if !decodePayload(adminConn.writer(), messageType, data, &subscription) {
+		return
+	}
+
+	var err error
+	if messageType == dto.MessageTypeUnsubscribeNotifications {
+		err = h.notificationPrefs.unsubscribe(adminConn.UserID, subscription.Categories)
+	} else {
+		err = h.notificationPrefs.subscribe(adminConn.UserID, subscription.Categories, subscription.Scope)
+	}
+	if err != nil {
+		log.Printf("❌ NOTIFICATIONS: Invalid %s from admin %s: %v", messageType, adminConn.Username, err)
+		sendMalformedPayload(adminConn.writer(), messageType, err)
+		return
+	}
+	if err := h.notificationPrefs.persist(adminConn.Context(), adminConn.UserID); err != nil {
+		// Se aplican igualmente; solo se pierden si el servidor se reinicia antes del siguiente cambio
+		log.Printf("❌ NOTIFICATIONS: Error saving preferences of admin %s: %v", adminConn.Username, err)
+	}
+
+	preferences := h.notificationPrefs.snapshot(adminConn.UserID)
+	log.Printf("🔔 NOTIFICATIONS: Admin %s preferences: scope %s, muted %v", adminConn.Username, preferences.Scope, preferences.MutedCategories)
+
+	message := dto.WebSocketMessage{Type: dto.MessageTypeNotificationPreferences, Data: preferences}
+	if err := adminConn.writer().WriteJSON(message); err != nil {
+		log.Printf("❌ NOTIFICATIONS: Error sending preferences to admin %s: %v", adminConn.Username, err)
+	}
+}
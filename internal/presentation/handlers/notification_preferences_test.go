@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+const otherAdminUserID = "other-admin-id"
+
+// MockPinnedPCRepository mock de IPinnedPCRepository
+type MockPinnedPCRepository struct {
+	mock.Mock
+}
+
+func (m *MockPinnedPCRepository) Pin(ctx context.Context, adminUserID, pcID string) error {
+	return m.Called(ctx, adminUserID, pcID).Error(0)
+}
+
+func (m *MockPinnedPCRepository) Unpin(ctx context.Context, adminUserID, pcID string) error {
+	return m.Called(ctx, adminUserID, pcID).Error(0)
+}
+
+func (m *MockPinnedPCRepository) FindPCIDsByAdmin(ctx context.Context, adminUserID string) ([]string, error) {
+	args := m.Called(ctx, adminUserID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// MockNotificationPreferenceRepository mock de INotificationPreferenceRepository
+type MockNotificationPreferenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferenceRepository) FindByAdmin(ctx context.Context, adminUserID string) (*interfaces.NotificationPreferenceRecord, error) {
+	args := m.Called(ctx, adminUserID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*interfaces.NotificationPreferenceRecord), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepository) Save(ctx context.Context, record *interfaces.NotificationPreferenceRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+// sendNotificationSubscription envía subscribe/unsubscribe_notifications del administrador conn-1 y lee la respuesta
+func sendNotificationSubscription(t *testing.T, h *AdminWebSocketHandler, adminSide *websocket.Conn, messageType string, data map[string]interface{}) dto.WebSocketMessage {
+	t.Helper()
+
+	h.handleAdminMessage(h.adminConnections["conn-1"], dto.WebSocketMessage{Type: messageType, Data: data})
+	return readAdminMessage(t, adminSide)
+}
+
+func TestNotificationPreferences_MutedAdminDoesNotReceiveCategory(t *testing.T) {
+	// Arrange - el primer administrador silencia las desconexiones; el segundo no tiene preferencias
+	h := NewAdminWebSocketHandler(nil, nil)
+	mutedSide := connectTestAdmin(t, h)
+	otherSide := connectTestAdminAs(t, h, "conn-2", otherAdminUserID)
+
+	reply := sendNotificationSubscription(t, h, mutedSide, dto.MessageTypeUnsubscribeNotifications,
+		map[string]interface{}{"categories": []string{"pc_disconnected"}})
+	require.Equal(t, dto.MessageTypeNotificationPreferences, reply.Type)
+	assert.Equal(t, []interface{}{"pc_disconnected"}, reply.Data.(map[string]interface{})["muted_categories"])
+
+	// Act
+	h.BroadcastPCDisconnected("pc-1", "lab-pc-01", testAdminUserID, remotesession.DisconnectReason{})
+	h.BroadcastPCListUpdate()
+
+	// Assert - el silenciado solo recibe la actualización de la lista; el otro recibe ambas
+	assert.Equal(t, "pc_list_update", readAdminMessage(t, mutedSide).Type)
+	assertNoClientMessage(t, mutedSide)
+	assert.Equal(t, "pc_disconnected", readAdminMessage(t, otherSide).Type)
+	assert.Equal(t, "pc_list_update", readAdminMessage(t, otherSide).Type)
+}
+
+func TestNotificationPreferences_SubscribeRestoresMutedCategory(t *testing.T) {
+	// Arrange
+	h := NewAdminWebSocketHandler(nil, nil)
+	adminSide := connectTestAdmin(t, h)
+	sendNotificationSubscription(t, h, adminSide, dto.MessageTypeUnsubscribeNotifications,
+		map[string]interface{}{"categories": []string{"pc_connected", "pc_status_changed"}})
+
+	// Act
+	reply := sendNotificationSubscription(t, h, adminSide, dto.MessageTypeSubscribeNotifications,
+		map[string]interface{}{"categories": []string{"pc_connected"}})
+	h.BroadcastPCConnected("pc-1", "lab-pc-01", testAdminUserID, "10.0.0.5")
+	h.BroadcastPCStatusChanged("pc-1", "lab-pc-01", testAdminUserID, "OFFLINE", "ONLINE")
+
+	// Assert
+	data := reply.Data.(map[string]interface{})
+	assert.Equal(t, "all", data["scope"])
+	assert.Equal(t, []interface{}{"pc_status_changed"}, data["muted_categories"])
+	assert.Equal(t, "pc_connected", readAdminMessage(t, adminSide).Type)
+	assertNoClientMessage(t, adminSide)
+}
+
+func TestNotificationPreferences_OwnedScopeOnlyDeliversOwnPCs(t *testing.T) {
+	// Arrange - el administrador fijó pc-pinned y controla la sesión activa de pc-session
+	session, err := remotesession.NewRemoteSession(testAdminUserID, "pc-session")
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusActive).Return([]*remotesession.RemoteSession{session}, nil)
+	pinnedRepo := new(MockPinnedPCRepository)
+	pinnedRepo.On("FindPCIDsByAdmin", mock.Anything, testAdminUserID).Return([]string{"pc-pinned"}, nil)
+
+	h := NewAdminWebSocketHandler(nil, remotesessionservice.NewRemoteSessionService(sessionRepo, nil, nil, nil, nil))
+	h.SetPinnedPCRepository(pinnedRepo)
+	adminSide := connectTestAdmin(t, h)
+	sendNotificationSubscription(t, h, adminSide, dto.MessageTypeSubscribeNotifications, map[string]interface{}{"scope": "owned"})
+
+	// Act - el propietario de los PCs es siempre el usuario cliente, nunca el administrador
+	h.BroadcastPCConnected("pc-other", "other-pc", "client-user-id", "10.0.0.6")
+	h.BroadcastPCConnected("pc-pinned", "pinned-pc", "client-user-id", "10.0.0.5")
+	h.BroadcastPCConnected("pc-session", "session-pc", "client-user-id", "10.0.0.7")
+	h.BroadcastCapacityWarning(ConnectionKindClient, 9, 10, 8)
+
+	// Assert
+	pinned := readAdminMessage(t, adminSide)
+	require.Equal(t, "pc_connected", pinned.Type)
+	assert.Equal(t, "pc-pinned", pinned.Data.(map[string]interface{})["pcId"])
+	controlled := readAdminMessage(t, adminSide)
+	require.Equal(t, "pc_connected", controlled.Type)
+	assert.Equal(t, "pc-session", controlled.Data.(map[string]interface{})["pcId"])
+	assert.Equal(t, "admin_capacity_warning", readAdminMessage(t, adminSide).Type)
+	assertNoClientMessage(t, adminSide)
+}
+
+func TestNotificationPreferences_ArePersistedAndRestored(t *testing.T) {
+	// Arrange
+	repo := new(MockNotificationPreferenceRepository)
+	repo.On("Save", mock.Anything, &interfaces.NotificationPreferenceRecord{
+		AdminUserID: testAdminUserID, Scope: "all", MutedCategories: []string{"pc_disconnected"},
+	}).Return(nil).Once()
+	repo.On("FindByAdmin", mock.Anything, testAdminUserID).Return(&interfaces.NotificationPreferenceRecord{
+		AdminUserID: testAdminUserID, Scope: "owned", MutedCategories: []string{"pc_connected", "pc_exploded"},
+	}, nil).Once()
+
+	h := NewAdminWebSocketHandler(nil, nil)
+	h.SetNotificationPreferenceRepository(repo)
+	adminSide := connectTestAdmin(t, h)
+
+	// Act - un cambio se guarda; otro servidor con el mismo repositorio carga lo guardado al conectarse
+	sendNotificationSubscription(t, h, adminSide, dto.MessageTypeUnsubscribeNotifications,
+		map[string]interface{}{"categories": []string{"pc_disconnected"}})
+	restarted := NewAdminWebSocketHandler(nil, nil)
+	restarted.SetNotificationPreferenceRepository(repo)
+	restarted.notificationPrefs.load(context.Background(), testAdminUserID)
+	restarted.notificationPrefs.load(context.Background(), testAdminUserID)
+
+	// Assert - las categorías desconocidas guardadas se ignoran y la carga solo consulta una vez
+	repo.AssertExpectations(t)
+	assert.Equal(t, dto.NotificationPreferences{Scope: "owned", MutedCategories: []string{"pc_connected"}},
+		restarted.notificationPrefs.snapshot(testAdminUserID))
+}
+
+func TestNotificationPreferences_InvalidSubscriptionIsRejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		messageType string
+		data        map[string]interface{}
+	}{
+		{name: "unknown category", messageType: dto.MessageTypeUnsubscribeNotifications, data: map[string]interface{}{"categories": []string{"pc_exploded"}}},
+		{name: "unknown scope", messageType: dto.MessageTypeSubscribeNotifications, data: map[string]interface{}{"scope": "favourites"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := NewAdminWebSocketHandler(nil, nil)
+			adminSide := connectTestAdmin(t, h)
+
+			// Act
+			reply := sendNotificationSubscription(t, h, adminSide, tt.messageType, tt.data)
+
+			// Assert - las preferencias no cambian y el administrador sigue recibiendo todo
+			assert.Equal(t, dto.MessageTypeMalformedPayload, reply.Type)
+			assert.Equal(t, dto.NotificationPreferences{Scope: "all", MutedCategories: []string{}}, h.notificationPrefs.snapshot(testAdminUserID))
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// OverflowPolicy indica qué se hace cuando el buffer de salida de una conexión está lleno. Las políticas drop_*
+// solo descartan screen_frame y audio_stream: el resto de mensajes (file_chunk, control_session_ended, ...) espera a que haya
+// sitio como mucho OutboundBufferConfig.BlockTimeout y, si no lo hay, la conexión se cierra.
+type OverflowPolicy string
+
+const (
+	// OverflowDropOldest descarta el screen_frame (o audio_stream) más antiguo pendiente para encolar el nuevo
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+	// OverflowDropNewest descarta el screen_frame (o audio_stream) nuevo y conserva los pendientes
+	OverflowDropNewest OverflowPolicy = "drop_newest"
+	// OverflowDisconnect cierra la conexión del consumidor lento
+	OverflowDisconnect OverflowPolicy = "disconnect"
+)
+
+// DefaultOutboundBufferSize mensajes pendientes por conexión antes de aplicar la política de desbordamiento
+const DefaultOutboundBufferSize = 256
+
+// DefaultOutboundBlockTimeout espera máxima de un mensaje que no se puede descartar con el buffer lleno
+const DefaultOutboundBlockTimeout = 5 * time.Second
+
+// outboundFlushTimeout tiempo máximo que Close espera a que se envíen los mensajes pendientes
+const outboundFlushTimeout = 2 * time.Second
+
+var (
+	// ErrOutboundBufferOverflow el buffer se llenó (con la política disconnect, o con un mensaje que no se puede
+	// descartar y sin hueco antes de BlockTimeout) y la conexión se cerró
+	ErrOutboundBufferOverflow = errors.New("outbound buffer overflow")
+	// ErrOutboundBufferClosed el buffer ya no acepta mensajes
+	ErrOutboundBufferClosed = errors.New("outbound buffer closed")
+)
+
+// OutboundBufferConfig tamaño y política del buffer de salida de cada conexión.
+// Un tamaño <= 0 desactiva el buffer y los mensajes se escriben directamente en el socket.
+type OutboundBufferConfig struct {
+	Size   int
+	Policy OverflowPolicy
+	// BlockTimeout espera máxima de un mensaje que no es screen_frame con el buffer lleno; <= 0 usa el valor por defecto
+	BlockTimeout time.Duration
+}
+
+// DefaultOutboundBufferConfig buffer de DefaultOutboundBufferSize mensajes que descarta los frames más antiguos
+func DefaultOutboundBufferConfig() OutboundBufferConfig {
+	return OutboundBufferConfig{Size: DefaultOutboundBufferSize, Policy: OverflowDropOldest, BlockTimeout: DefaultOutboundBlockTimeout}
+}
+
+// ParseOverflowPolicy interpreta "drop_oldest", "drop_newest" o "disconnect"
+func ParseOverflowPolicy(value string) (OverflowPolicy, error) {
+	switch policy := OverflowPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case OverflowDropOldest, OverflowDropNewest, OverflowDisconnect:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("política de desbordamiento desconocida: %q", value)
+	}
+}
+
+// messageWriter escribe mensajes hacia un WebSocket; lo implementan *websocket.Conn y OutboundBuffer
+type messageWriter interface {
+	WriteJSON(v interface{}) error
+	WriteMessage(messageType int, data []byte) error
+}
+
+// outboundConn socket sobre el que escribe el buffer
+type outboundConn interface {
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+}
+
+type outboundMessage struct {
+	messageType int
+	data        []byte
+	// droppable solo los screen_frame y audio_stream pueden descartarse al desbordarse el buffer
+	droppable bool
+	// closeAfter la goroutine de escritura cierra el socket después de enviar este mensaje
+	closeAfter bool
+}
+
+// OutboundBuffer cola acotada de mensajes salientes de una conexión, vaciada por una goroutine propia.
+// Los productores (reenvío de frames, broadcasts) solo encolan, así un consumidor lento no los bloquea.
+type OutboundBuffer struct {
+	conn   outboundConn
+	config OutboundBufferConfig
+
+	mu    sync.Mutex
+	ready *sync.Cond
+	// space despierta a los productores que esperan hueco para un mensaje que no se puede descartar
+	space   *sync.Cond
+	queue   []outboundMessage
+	closed  bool
+	dropped int
+
+	done chan struct{}
+}
+
+// NewOutboundBuffer crea el buffer y arranca su goroutine de escritura
+func NewOutboundBuffer(conn outboundConn, config OutboundBufferConfig) *OutboundBuffer {
+	b := &OutboundBuffer{
+		conn:   conn,
+		config: config,
+		queue:  make([]outboundMessage, 0, config.Size),
+		done:   make(chan struct{}),
+	}
+	b.ready = sync.NewCond(&b.mu)
+	b.space = sync.NewCond(&b.mu)
+
+	go b.writeLoop()
+	return b
+}
+
+// WriteJSON codifica el mensaje y lo encola como mensaje de texto
+func (b *OutboundBuffer) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error encoding outbound message: %w", err)
+	}
+	return b.enqueue(outboundMessage{messageType: websocket.TextMessage, data: data, droppable: isDroppableMessage(v)})
+}
+
+// isDroppableMessage indica si el mensaje es un screen_frame o un audio_stream: perder un frame solo retrasa la
+// imagen hasta el siguiente y un fragmento de audio perdido no se reproduce después
+func isDroppableMessage(v interface{}) bool {
+	switch message := v.(type) {
+	case dto.WebSocketMessage:
+		return isDroppableMessageType(message.Type)
+	case *dto.WebSocketMessage:
+		return message != nil && isDroppableMessageType(message.Type)
+	default:
+		return false
+	}
+}
+
+func isDroppableMessageType(messageType string) bool {
+	return messageType == dto.MessageTypeScreenFrame || messageType == dto.MessageTypeAudioStream
+}
+
+// WriteMessage encola un mensaje ya codificado; nunca se descarta al desbordarse el buffer
+func (b *OutboundBuffer) WriteMessage(messageType int, data []byte) error {
+	return b.enqueue(outboundMessage{messageType: messageType, data: data})
+}
+
+// CloseWithMessage encola un close frame tras los mensajes pendientes; la goroutine de escritura lo envía, cierra
+// el socket y deja de aceptar mensajes, así el cierre no compite con otras escrituras
+func (b *OutboundBuffer) CloseWithMessage(closeMessage []byte) error {
+	return b.enqueue(outboundMessage{messageType: websocket.CloseMessage, data: closeMessage, closeAfter: true})
+}
+
+// Dropped retorna cuántos mensajes se descartaron por desbordamiento
+func (b *OutboundBuffer) Dropped() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
+
+// Close deja de aceptar mensajes y espera (como máximo outboundFlushTimeout) a que se envíen los pendientes
+func (b *OutboundBuffer) Close() {
+	b.mu.Lock()
+	b.closed = true
+	b.ready.Broadcast()
+	b.space.Broadcast()
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-time.After(outboundFlushTimeout):
+		log.Printf("⚠️ OUTBOUND BUFFER: Timed out flushing pending messages")
+	}
+}
+
+// enqueue añade el mensaje aplicando la política de desbordamiento si la cola está llena. Con drop_* se descarta
+// un screen_frame (el más antiguo pendiente o el nuevo); un mensaje que no se puede descartar espera hueco como
+// mucho BlockTimeout y, si no lo hay, se cierra la conexión y retorna ErrOutboundBufferOverflow.
+func (b *OutboundBuffer) enqueue(message outboundMessage) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrOutboundBufferClosed
+	}
+
+	if len(b.queue) >= b.config.Size {
+		if b.config.Policy == OverflowDisconnect {
+			return b.overflowDisconnect()
+		}
+		if message.droppable && b.config.Policy == OverflowDropNewest {
+			b.recordDrop()
+			b.mu.Unlock()
+			return nil
+		}
+		if !b.dropOldestFrame() {
+			if message.droppable {
+				// Solo hay mensajes que no se pueden descartar: se pierde el frame nuevo
+				b.recordDrop()
+				b.mu.Unlock()
+				return nil
+			}
+			if !b.waitForSpace() {
+				if b.closed {
+					b.mu.Unlock()
+					return ErrOutboundBufferClosed
+				}
+				return b.overflowDisconnect()
+			}
+		}
+	}
+
+	b.queue = append(b.queue, message)
+	b.ready.Signal()
+	b.mu.Unlock()
+	return nil
+}
+
+// dropOldestFrame descarta el screen_frame pendiente más antiguo; false si no hay ninguno. Requiere b.mu.
+func (b *OutboundBuffer) dropOldestFrame() bool {
+	for i, pending := range b.queue {
+		if pending.droppable {
+			copy(b.queue[i:], b.queue[i+1:])
+			b.queue[len(b.queue)-1] = outboundMessage{}
+			b.queue = b.queue[:len(b.queue)-1]
+			b.recordDrop()
+			return true
+		}
+	}
+	return false
+}
+
+// waitForSpace espera, como mucho BlockTimeout, a que la goroutine de escritura libere un hueco; false si se
+// agotó la espera o el buffer se cerró. Requiere b.mu.
+func (b *OutboundBuffer) waitForSpace() bool {
+	timeout := b.config.BlockTimeout
+	if timeout <= 0 {
+		timeout = DefaultOutboundBlockTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	timer := time.AfterFunc(timeout, func() {
+		b.mu.Lock()
+		b.space.Broadcast()
+		b.mu.Unlock()
+	})
+	defer timer.Stop()
+
+	for len(b.queue) >= b.config.Size && !b.closed && time.Now().Before(deadline) {
+		b.space.Wait()
+	}
+	return len(b.queue) < b.config.Size && !b.closed
+}
+
+// overflowDisconnect cierra al consumidor lento descartando lo pendiente. Requiere b.mu y lo libera.
+func (b *OutboundBuffer) overflowDisconnect() error {
+	b.closed = true
+	b.queue = nil
+	b.ready.Broadcast()
+	b.space.Broadcast()
+	b.mu.Unlock()
+
+	log.Printf("🚫 OUTBOUND BUFFER: Buffer full (%d messages), disconnecting slow consumer", b.config.Size)
+	b.conn.Close()
+	return ErrOutboundBufferOverflow
+}
+
+// recordDrop cuenta un mensaje descartado; solo se loguea el primero para no inundar el log. Requiere b.mu.
+func (b *OutboundBuffer) recordDrop() {
+	b.dropped++
+	if b.dropped == 1 {
+		log.Printf("⚠️ OUTBOUND BUFFER: Buffer full (%d messages), dropping messages (%s)", b.config.Size, b.config.Policy)
+	}
+}
+
+// writeLoop envía los mensajes en orden hasta que el buffer se cierra y queda vacío o falla una escritura
+func (b *OutboundBuffer) writeLoop() {
+	defer close(b.done)
+
+	for {
+		b.mu.Lock()
+		for len(b.queue) == 0 && !b.closed {
+			b.ready.Wait()
+		}
+		if len(b.queue) == 0 {
+			b.mu.Unlock()
+			return
+		}
+		message := b.queue[0]
+		b.queue[0] = outboundMessage{}
+		b.queue = b.queue[1:]
+		b.space.Signal()
+		b.mu.Unlock()
+
+		err := b.conn.WriteMessage(message.messageType, message.data)
+		if err != nil || message.closeAfter {
+			b.mu.Lock()
+			b.closed = true
+			b.queue = nil
+			b.space.Broadcast()
+			b.mu.Unlock()
+			if message.closeAfter {
+				b.conn.Close()
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// stalledConn socket cuyo consumidor no lee: cada escritura se bloquea hasta release
+type stalledConn struct {
+	release chan struct{}
+	writing chan struct{}
+
+	mu      sync.Mutex
+	written []string
+	closed  bool
+}
+
+func newStalledConn() *stalledConn {
+	return &stalledConn{release: make(chan struct{}), writing: make(chan struct{}, 1)}
+}
+
+func (c *stalledConn) WriteMessage(messageType int, data []byte) error {
+	select {
+	case c.writing <- struct{}{}:
+	default:
+	}
+	<-c.release
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.written = append(c.written, writtenName(data))
+	return nil
+}
+
+// writtenName nombre del mensaje escrito: el data de un screen_frame de writeFrame o el texto tal cual
+func writtenName(data []byte) string {
+	var frame dto.WebSocketMessage
+	if err := json.Unmarshal(data, &frame); err == nil && frame.Type == dto.MessageTypeScreenFrame {
+		if name, ok := frame.Data.(string); ok {
+			return name
+		}
+	}
+	return string(data)
+}
+
+// writeFrame encola un screen_frame identificado por name, el único tipo de mensaje que se puede descartar
+func writeFrame(buffer *OutboundBuffer, name string) error {
+	return buffer.WriteJSON(dto.WebSocketMessage{Type: dto.MessageTypeScreenFrame, Data: name})
+}
+
+func (c *stalledConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *stalledConn) snapshot() ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.written...), c.closed
+}
+
+// stallBuffer crea un buffer de 2 mensajes cuya goroutine de escritura queda bloqueada enviando "m0"
+func stallBuffer(t *testing.T, policy OverflowPolicy) (*OutboundBuffer, *stalledConn) {
+	t.Helper()
+	return stallBufferWithConfig(t, OutboundBufferConfig{Size: 2, Policy: policy})
+}
+
+// stallBufferWithConfig como stallBuffer con la configuración indicada
+func stallBufferWithConfig(t *testing.T, config OutboundBufferConfig) (*OutboundBuffer, *stalledConn) {
+	t.Helper()
+
+	conn := newStalledConn()
+	buffer := NewOutboundBuffer(conn, config)
+	require.NoError(t, buffer.WriteMessage(websocket.TextMessage, []byte("m0")))
+
+	select {
+	case <-conn.writing:
+	case <-time.After(2 * time.Second):
+		t.Fatal("writer goroutine did not pick up the first message")
+	}
+	return buffer, conn
+}
+
+func TestOutboundBuffer_DropOldestKeepsNewestMessages(t *testing.T) {
+	// Arrange
+	buffer, conn := stallBuffer(t, OverflowDropOldest)
+
+	// Act
+	for _, frame := range []string{"m1", "m2", "m3"} {
+		require.NoError(t, writeFrame(buffer, frame))
+	}
+	close(conn.release)
+	buffer.Close()
+
+	// Assert
+	written, closed := conn.snapshot()
+	assert.Equal(t, []string{"m0", "m2", "m3"}, written)
+	assert.False(t, closed)
+	assert.Equal(t, 1, buffer.Dropped())
+}
+
+func TestOutboundBuffer_DropNewestKeepsPendingMessages(t *testing.T) {
+	// Arrange
+	buffer, conn := stallBuffer(t, OverflowDropNewest)
+
+	// Act
+	for _, frame := range []string{"m1", "m2", "m3"} {
+		require.NoError(t, writeFrame(buffer, frame))
+	}
+	close(conn.release)
+	buffer.Close()
+
+	// Assert
+	written, closed := conn.snapshot()
+	assert.Equal(t, []string{"m0", "m1", "m2"}, written)
+	assert.False(t, closed)
+	assert.Equal(t, 1, buffer.Dropped())
+}
+
+func TestOutboundBuffer_DisconnectClosesSlowConsumer(t *testing.T) {
+	// Arrange
+	buffer, conn := stallBuffer(t, OverflowDisconnect)
+	require.NoError(t, buffer.WriteMessage(websocket.TextMessage, []byte("m1")))
+	require.NoError(t, buffer.WriteMessage(websocket.TextMessage, []byte("m2")))
+
+	// Act
+	err := buffer.WriteMessage(websocket.TextMessage, []byte("m3"))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrOutboundBufferOverflow)
+	_, closed := conn.snapshot()
+	assert.True(t, closed)
+	assert.ErrorIs(t, buffer.WriteJSON(map[string]string{"type": "late"}), ErrOutboundBufferClosed)
+
+	close(conn.release)
+	buffer.Close()
+	written, _ := conn.snapshot()
+	assert.Equal(t, []string{"m0"}, written)
+}
+
+func TestOutboundBuffer_ProducerIsNotBlockedByStalledConsumer(t *testing.T) {
+	// Arrange
+	buffer, conn := stallBuffer(t, OverflowDropOldest)
+	defer func() {
+		close(conn.release)
+		buffer.Close()
+	}()
+
+	// Act
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			writeFrame(buffer, "frame")
+		}
+	}()
+
+	// Assert
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("producer blocked on a stalled consumer")
+	}
+	assert.Equal(t, 98, buffer.Dropped())
+}
+
+func TestOutboundBuffer_DropPoliciesNeverDropControlMessages(t *testing.T) {
+	for _, policy := range []OverflowPolicy{OverflowDropOldest, OverflowDropNewest} {
+		t.Run(string(policy), func(t *testing.T) {
+			// Arrange - buffer lleno con un mensaje de control y un frame
+			buffer, conn := stallBuffer(t, policy)
+			require.NoError(t, buffer.WriteMessage(websocket.TextMessage, []byte("file_chunk")))
+			require.NoError(t, writeFrame(buffer, "frame"))
+
+			// Act
+			err := buffer.WriteJSON(dto.WebSocketMessage{Type: "control_session_ended"})
+			close(conn.release)
+			buffer.Close()
+
+			// Assert - se descarta el frame, no los mensajes de control
+			require.NoError(t, err)
+			written, closed := conn.snapshot()
+			assert.Equal(t, []string{"m0", "file_chunk", `{"type":"control_session_ended","data":null}`}, written)
+			assert.False(t, closed)
+			assert.Equal(t, 1, buffer.Dropped())
+		})
+	}
+}
+
+func TestOutboundBuffer_ControlMessageWaitsForSpace(t *testing.T) {
+	// Arrange
+	buffer, conn := stallBufferWithConfig(t, OutboundBufferConfig{Size: 2, Policy: OverflowDropOldest, BlockTimeout: 2 * time.Second})
+	require.NoError(t, buffer.WriteMessage(websocket.TextMessage, []byte("c1")))
+	require.NoError(t, buffer.WriteMessage(websocket.TextMessage, []byte("c2")))
+
+	// Act - el consumidor vuelve a leer mientras c3 espera hueco
+	time.AfterFunc(50*time.Millisecond, func() { close(conn.release) })
+	err := buffer.WriteMessage(websocket.TextMessage, []byte("c3"))
+	buffer.Close()
+
+	// Assert
+	require.NoError(t, err)
+	written, closed := conn.snapshot()
+	assert.Equal(t, []string{"m0", "c1", "c2", "c3"}, written)
+	assert.False(t, closed)
+	assert.Zero(t, buffer.Dropped())
+}
+
+func TestOutboundBuffer_ControlMessageDisconnectsAfterBlockTimeout(t *testing.T) {
+	// Arrange
+	buffer, conn := stallBufferWithConfig(t, OutboundBufferConfig{Size: 2, Policy: OverflowDropOldest, BlockTimeout: 50 * time.Millisecond})
+	require.NoError(t, buffer.WriteMessage(websocket.TextMessage, []byte("c1")))
+	require.NoError(t, buffer.WriteMessage(websocket.TextMessage, []byte("c2")))
+
+	// Act
+	started := time.Now()
+	err := buffer.WriteMessage(websocket.TextMessage, []byte("c3"))
+
+	// Assert - el productor se entera y el consumidor lento se desconecta
+	assert.ErrorIs(t, err, ErrOutboundBufferOverflow)
+	assert.GreaterOrEqual(t, time.Since(started), 50*time.Millisecond)
+	_, closed := conn.snapshot()
+	assert.True(t, closed)
+
+	close(conn.release)
+	buffer.Close()
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	// Act & Assert
+	for _, value := range []string{"drop_oldest", "DROP_NEWEST", " disconnect "} {
+		_, err := ParseOverflowPolicy(value)
+		assert.NoError(t, err, value)
+	}
+	_, err := ParseOverflowPolicy("block")
+	assert.Error(t, err)
+}
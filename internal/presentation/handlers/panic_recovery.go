@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+)
+
+// ErrHandlerPanic un handler de WebSocket o una goroutine de transferencias entró en pánico; el panic se
+// registró y solo se descarta la conexión o transferencia afectada
+var ErrHandlerPanic = errors.New("handler panicked")
+
+// runRecovered ejecuta fn y convierte un panic en ErrHandlerPanic. scope indica qué se estaba haciendo
+// (p. ej. el tipo de mensaje) y subject a quién afecta (conexión, PC, transferencia) para el log.
+func runRecovered(scope, subject string, fn func()) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("💥 PANIC RECOVERED: %s (%s): %v\n%s", scope, subject, recovered, debug.Stack())
+			err = fmt.Errorf("%w: %s: %v", ErrHandlerPanic, scope, recovered)
+		}
+	}()
+
+	fn()
+	return nil
+}
+
+// goRecovered lanza fn en una goroutine cuyo panic se registra en lugar de tumbar el servidor
+func goRecovered(scope, subject string, fn func()) {
+	go runRecovered(scope, subject, fn)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+func TestServeClientConnection_PanickingHandlerClosesOnlyThatConnection(t *testing.T) {
+	// Arrange - el handler no tiene servicio de transferencias: un COMPLETED_CLIENT desreferencia un nil
+	h, sessionRepo, pcService := newTestDisconnectHandler(t, remotesession.StatusFailed)
+	clientSide, served := serveTestClient(t, h)
+
+	// Act
+	require.NoError(t, clientSide.WriteJSON(dto.WebSocketMessage{
+		Type: "file_transfer_ack",
+		Data: map[string]interface{}{"transfer_id": "transfer-1", "status": "COMPLETED_CLIENT"},
+	}))
+
+	// Assert - el cliente recibe 1011 y la conexión se libera como una desconexión anormal
+	_, _, err := clientSide.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseInternalServerErr), "expected internal error closure, got %v", err)
+
+	waitServed(t, served)
+	sessionRepo.AssertExpectations(t)
+	pcService.AssertExpectations(t)
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	assert.NotContains(t, h.connections, "conn-1")
+	assert.NotContains(t, h.pcConnections, testTargetPCID)
+}
+
+func TestRunRecovered_ConvertsPanicIntoError(t *testing.T) {
+	// Act
+	err := runRecovered("test scope", "subject-1", func() {
+		var transfers map[string]int
+		transfers["transfer-1"]++
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrHandlerPanic)
+	assert.Contains(t, err.Error(), "test scope")
+	assert.NoError(t, runRecovered("test scope", "subject-1", func() {}))
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// decodePayload convierte el campo data de un mensaje WebSocket al DTO destino.
+// Si no encaja responde al emisor con malformed_payload y retorna false.
+func decodePayload(conn messageWriter, messageType string, data interface{}, target interface{}) bool {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("❌ MALFORMED PAYLOAD: Error marshalling %s data: %v", messageType, err)
+		sendMalformedPayload(conn, messageType, err)
+		return false
+	}
+	return decodeRawPayload(conn, messageType, raw, target)
+}
+
+// decodeRawPayload como decodePayload, para cabeceras JSON ya serializadas (p. ej. de mensajes binarios)
+func decodeRawPayload(conn messageWriter, messageType string, raw []byte, target interface{}) bool {
+	if err := json.Unmarshal(raw, target); err != nil {
+		log.Printf("❌ MALFORMED PAYLOAD: Error unmarshalling %s data: %v", messageType, err)
+		sendMalformedPayload(conn, messageType, err)
+		return false
+	}
+	return true
+}
+
+// sendMalformedPayload avisa al emisor de qué mensaje no se pudo interpretar
+func sendMalformedPayload(conn messageWriter, messageType string, err error) {
+	writeErr := conn.WriteJSON(dto.WebSocketMessage{
+		Type: dto.MessageTypeMalformedPayload,
+		Data: dto.MalformedPayload{
+			MessageType: messageType,
+			Error:       err.Error(),
+		},
+	})
+	if writeErr != nil {
+		log.Printf("⚠️ MALFORMED PAYLOAD: Error notifying sender about %s: %v", messageType, writeErr)
+	}
+}
@@ -1,141 +1,365 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/pcservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
 	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
 )
 
+// ConnectedPCsProvider exposes the PCs that currently have a live WebSocket connection
+type ConnectedPCsProvider interface {
+	ConnectedPCIDs() []string
+}
+
 // PCHandler manages PC-related endpoints for administrators
 type PCHandler struct {
-	pcService   pcservice.IPCService
-	authService *userservice.AuthService
+	pcService                pcservice.IPCService
+	connectionHistoryService pcservice.IConnectionHistoryService
+	pinnedPCService          pcservice.IPinnedPCService
+	purgeService             pcservice.IPCPurgeService
+	liveConnections          ConnectedPCsProvider
+	authService              *userservice.AuthService
 }
 
 // NewPCHandler creates a new PC handler
-func NewPCHandler(pcService pcservice.IPCService, authService *userservice.AuthService) *PCHandler {
+func NewPCHandler(
+	pcService pcservice.IPCService,
+	connectionHistoryService pcservice.IConnectionHistoryService,
+	pinnedPCService pcservice.IPinnedPCService,
+	liveConnections ConnectedPCsProvider,
+	authService *userservice.AuthService,
+) *PCHandler {
 	return &PCHandler{
-		pcService:   pcService,
-		authService: authService,
+		pcService:                pcService,
+		connectionHistoryService: connectionHistoryService,
+		pinnedPCService:          pinnedPCService,
+		liveConnections:          liveConnections,
+		authService:              authService,
 	}
 }
 
-// GetAllClientPCs handles GET /api/admin/pcs - retrieves all client PCs
+// SetPurgeService configura el servicio que purga los datos de un PC dado de baja (nil = endpoint desactivado)
+func (h *PCHandler) SetPurgeService(purgeService pcservice.IPCPurgeService) {
+	h.purgeService = purgeService
+}
+
+// GetAllClientPCs handles GET /api/v1/admin/pcs - retrieves all client PCs
 func (h *PCHandler) GetAllClientPCs(c *gin.Context) {
 	// Verificar autenticación y autorización de administrador
 	userInfo, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponseDTO{
-			Error:   "AUTHENTICATION_REQUIRED",
-			Message: "Authentication required",
-			Code:    http.StatusUnauthorized,
-		})
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
 		return
 	}
 
 	userClaims, ok := userInfo.(*userservice.JWTClaims)
-	if !ok || userClaims.Role != string(user.RoleAdministrator) {
-		c.JSON(http.StatusForbidden, dto.ErrorResponseDTO{
-			Error:   "ADMIN_PRIVILEGES_REQUIRED",
-			Message: "Admin privileges required",
-			Code:    http.StatusForbidden,
-		})
+	if !ok || !user.Role(userClaims.Role).IsAdmin() {
+		response.Error(c, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED", "Admin privileges required")
 		return
 	}
 
-	// Obtener todos los PCs cliente
-	pcs, err := h.pcService.GetAllClientPCs(c.Request.Context())
+	// Obtener la página de PCs cliente y el total registrado
+	page := response.ParsePageRequest(c)
+	pcs, total, err := h.pcService.GetClientPCsPage(c.Request.Context(), page.Limit, page.Offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
-			Error:   "RETRIEVAL_FAILED",
-			Message: "Failed to retrieve client PCs",
-			Code:    http.StatusInternalServerError,
-		})
+		response.Error(c, http.StatusInternalServerError, "RETRIEVAL_FAILED", "Failed to retrieve client PCs")
 		return
 	}
 
 	// Convertir a DTOs
 	pcDTOs := make([]dto.ClientPCDTO, len(pcs))
 	for i, pc := range pcs {
-		pcDTOs[i] = dto.ClientPCDTO{
-			PCID:             pc.PCID,
-			Identifier:       pc.Identifier,
-			ConnectionStatus: string(pc.ConnectionStatus),
-			OwnerUsername:    pc.OwnerUserID, // Nota: En esta fase usamos UserID, en fase posterior incluiremos lookup de username
-			IP:               pc.IP,
-			RegisteredAt:     pc.RegisteredAt,
-			LastSeenAt:       pc.LastSeenAt,
-			UpdatedAt:        pc.UpdatedAt,
-		}
+		pcDTOs[i] = toClientPCDTO(pc)
 	}
 
 	// Responder con la lista de PCs
-	c.JSON(http.StatusOK, dto.ClientPCListResponse{
-		Success: true,
-		Data:    pcDTOs,
-		Count:   len(pcDTOs),
-		Message: "Client PCs retrieved successfully",
-	})
+	response.SuccessPage(c, http.StatusOK, dto.ClientPCListResponse{
+		PCs:   pcDTOs,
+		Count: len(pcDTOs),
+	}, page.Meta(total))
 }
 
-// GetOnlineClientPCs handles GET /api/admin/pcs/online - retrieves only online client PCs
+// GetOnlineClientPCs handles GET /api/v1/admin/pcs/online - retrieves only online client PCs
 func (h *PCHandler) GetOnlineClientPCs(c *gin.Context) {
 	// Verificar autenticación y autorización de administrador
 	userInfo, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponseDTO{
-			Error:   "AUTHENTICATION_REQUIRED",
-			Message: "Authentication required",
-			Code:    http.StatusUnauthorized,
-		})
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
 		return
 	}
 
 	userClaims, ok := userInfo.(*userservice.JWTClaims)
-	if !ok || userClaims.Role != string(user.RoleAdministrator) {
-		c.JSON(http.StatusForbidden, dto.ErrorResponseDTO{
-			Error:   "ADMIN_PRIVILEGES_REQUIRED",
-			Message: "Admin privileges required",
-			Code:    http.StatusForbidden,
-		})
+	if !ok || !user.Role(userClaims.Role).IsAdmin() {
+		response.Error(c, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED", "Admin privileges required")
 		return
 	}
 
 	// Obtener solo los PCs cliente online
 	pcs, err := h.pcService.GetOnlineClientPCs(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
-			Error:   "RETRIEVAL_FAILED",
-			Message: "Failed to retrieve online client PCs",
-			Code:    http.StatusInternalServerError,
-		})
+		response.Error(c, http.StatusInternalServerError, "RETRIEVAL_FAILED", "Failed to retrieve online client PCs")
+		return
+	}
+
+	// El filtro de estado se aplica en memoria, la página se toma de la lista filtrada
+	page := response.ParsePageRequest(c)
+	pagePCs := response.Paginate(pcs, page)
+
+	// Convertir a DTOs
+	pcDTOs := make([]dto.ClientPCDTO, len(pagePCs))
+	for i, pc := range pagePCs {
+		pcDTOs[i] = toClientPCDTO(pc)
+	}
+
+	// Responder con la lista de PCs online
+	response.SuccessPage(c, http.StatusOK, dto.ClientPCListResponse{
+		PCs:   pcDTOs,
+		Count: len(pcDTOs),
+	}, page.Meta(len(pcs)))
+}
+
+// GetConnectionHistory handles GET /api/v1/admin/pcs/:pcId/connection-history - retrieves past connections of a PC
+func (h *PCHandler) GetConnectionHistory(c *gin.Context) {
+	// Verificar autenticación y autorización de administrador
+	userInfo, exists := c.Get("user")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
+		return
+	}
+
+	userClaims, ok := userInfo.(*userservice.JWTClaims)
+	if !ok || !user.Role(userClaims.Role).IsAdmin() {
+		response.Error(c, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED", "Admin privileges required")
+		return
+	}
+
+	pcID := c.Param("pcId")
+	if pcID == "" {
+		response.Error(c, http.StatusBadRequest, "INVALID_PC_ID", "PC ID is required")
+		return
+	}
+
+	// Parámetros de paginación opcionales; la página se toma en SQL (LIMIT/OFFSET) con el default del historial
+	page := response.ParsePageRequestWithDefault(c, pcservice.DefaultConnectionHistoryLimit)
+
+	sessions, err := h.connectionHistoryService.GetConnectionHistory(c.Request.Context(), pcID, page.Limit, page.Offset)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "RETRIEVAL_FAILED", "Failed to retrieve connection history")
+		return
+	}
+
+	total, err := h.connectionHistoryService.CountConnectionHistory(c.Request.Context(), pcID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "RETRIEVAL_FAILED", "Failed to count connection history")
+		return
+	}
+
+	// Convertir a DTOs
+	historyDTOs := make([]dto.ConnectionSessionDTO, len(sessions))
+	for i, session := range sessions {
+		historyDTOs[i] = dto.ConnectionSessionDTO{
+			ConnectionID:     session.ConnectionID(),
+			PCID:             session.PCID(),
+			IPAddress:        session.IPAddress(),
+			ConnectedAt:      session.ConnectedAt(),
+			DisconnectedAt:   session.DisconnectedAt(),
+			DisconnectReason: session.DisconnectReason(),
+			DurationSeconds:  int64(session.GetDuration().Seconds()),
+		}
+	}
+
+	response.SuccessPage(c, http.StatusOK, dto.ConnectionHistoryResponse{
+		Connections: historyDTOs,
+		Count:       len(historyDTOs),
+	}, page.Meta(total))
+}
+
+// UpdateAutoAcceptPolicy handles PUT /api/v1/admin/pcs/:pcId/auto-accept - enables or disables auto-accept of control requests
+func (h *PCHandler) UpdateAutoAcceptPolicy(c *gin.Context) {
+	// Verificar autenticación y autorización de administrador
+	userInfo, exists := c.Get("user")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
+		return
+	}
+
+	userClaims, ok := userInfo.(*userservice.JWTClaims)
+	if !ok || !user.Role(userClaims.Role).IsAdmin() {
+		response.Error(c, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED", "Admin privileges required")
+		return
+	}
+
+	pcID := c.Param("pcId")
+	if pcID == "" {
+		response.Error(c, http.StatusBadRequest, "INVALID_PC_ID", "PC ID is required")
+		return
+	}
+
+	var req dto.UpdateAutoAcceptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "Field 'enabled' is required")
+		return
+	}
+
+	pc, err := h.pcService.SetAutoAcceptControl(c.Request.Context(), pcID, *req.Enabled)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "UPDATE_FAILED", "Failed to update auto-accept policy")
+		return
+	}
+
+	response.Success(c, http.StatusOK, toClientPCDTO(pc))
+}
+
+// GetPinnedPCs handles GET /api/v1/admin/pcs/pinned - retrieves the PCs pinned by the current admin with live status
+func (h *PCHandler) GetPinnedPCs(c *gin.Context) {
+	// Verificar autenticación y autorización de administrador
+	userInfo, exists := c.Get("user")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
+		return
+	}
+
+	userClaims, ok := userInfo.(*userservice.JWTClaims)
+	if !ok || !user.Role(userClaims.Role).IsAdmin() {
+		response.Error(c, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED", "Admin privileges required")
+		return
+	}
+
+	// El estado online se toma de las conexiones WebSocket vivas
+	pcs, err := h.pinnedPCService.GetPinnedPCs(c.Request.Context(), userClaims.UserID, h.liveConnections.ConnectedPCIDs())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "RETRIEVAL_FAILED", "Failed to retrieve pinned PCs")
 		return
 	}
 
 	// Convertir a DTOs
 	pcDTOs := make([]dto.ClientPCDTO, len(pcs))
 	for i, pc := range pcs {
-		pcDTOs[i] = dto.ClientPCDTO{
-			PCID:             pc.PCID,
-			Identifier:       pc.Identifier,
-			ConnectionStatus: string(pc.ConnectionStatus),
-			OwnerUsername:    pc.OwnerUserID, // Nota: En esta fase usamos UserID, en fase posterior incluiremos lookup de username
-			IP:               pc.IP,
-			RegisteredAt:     pc.RegisteredAt,
-			LastSeenAt:       pc.LastSeenAt,
-			UpdatedAt:        pc.UpdatedAt,
-		}
+		pcDTOs[i] = toClientPCDTO(pc)
 	}
 
-	// Responder con la lista de PCs online
-	c.JSON(http.StatusOK, dto.OnlineClientPCListResponse{
-		Success: true,
-		Data:    pcDTOs,
-		Count:   len(pcDTOs),
-		Message: "Online client PCs retrieved successfully",
+	response.Success(c, http.StatusOK, dto.ClientPCListResponse{
+		PCs:   pcDTOs,
+		Count: len(pcDTOs),
 	})
 }
+
+// PinPC handles POST /api/v1/admin/pcs/:pcId/pin - pins a PC for the current admin
+func (h *PCHandler) PinPC(c *gin.Context) {
+	h.updatePin(c, true)
+}
+
+// UnpinPC handles DELETE /api/v1/admin/pcs/:pcId/pin - unpins a PC for the current admin
+func (h *PCHandler) UnpinPC(c *gin.Context) {
+	h.updatePin(c, false)
+}
+
+// updatePin fija o quita un PC de los favoritos del administrador autenticado
+func (h *PCHandler) updatePin(c *gin.Context, pinned bool) {
+	// Verificar autenticación y autorización de administrador
+	userInfo, exists := c.Get("user")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
+		return
+	}
+
+	userClaims, ok := userInfo.(*userservice.JWTClaims)
+	if !ok || !user.Role(userClaims.Role).IsAdmin() {
+		response.Error(c, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED", "Admin privileges required")
+		return
+	}
+
+	pcID := c.Param("pcId")
+	if pcID == "" {
+		response.Error(c, http.StatusBadRequest, "INVALID_PC_ID", "PC ID is required")
+		return
+	}
+
+	var err error
+	if pinned {
+		err = h.pinnedPCService.PinPC(c.Request.Context(), userClaims.UserID, pcID)
+	} else {
+		err = h.pinnedPCService.UnpinPC(c.Request.Context(), userClaims.UserID, pcID)
+	}
+	if errors.Is(err, pcservice.ErrPCNotFound) {
+		response.Error(c, http.StatusNotFound, "PC_NOT_FOUND", "PC not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "UPDATE_FAILED", "Failed to update pinned PCs")
+		return
+	}
+
+	response.Success(c, http.StatusOK, dto.PinnedPCResponse{
+		PCID:   pcID,
+		Pinned: pinned,
+	})
+}
+
+// toClientPCDTO convierte la entidad ClientPC al DTO de la API
+func toClientPCDTO(pc *clientpc.ClientPC) dto.ClientPCDTO {
+	return dto.ClientPCDTO{
+		PCID:              pc.PCID,
+		Identifier:        pc.Identifier,
+		ConnectionStatus:  string(pc.ConnectionStatus),
+		OwnerUsername:     pc.OwnerUserID, // Nota: En esta fase usamos UserID, en fase posterior incluiremos lookup de username
+		IP:                pc.IP,
+		AutoAcceptControl: pc.AutoAcceptControl,
+		OS:                pc.OS,
+		Hostname:          pc.Hostname,
+		AgentVersion:      pc.AgentVersion,
+		RegisteredAt:      pc.RegisteredAt,
+		LastSeenAt:        pc.LastSeenAt,
+		UpdatedAt:         pc.UpdatedAt,
+	}
+}
+
+// PurgePC handles DELETE /api/v1/admin/pcs/:pcId/purge - elimina el PC y sus grabaciones, transferencias y sesiones
+func (h *PCHandler) PurgePC(c *gin.Context) {
+	// Verificar autenticación y autorización de administrador
+	userInfo, exists := c.Get("user")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
+		return
+	}
+
+	userClaims, ok := userInfo.(*userservice.JWTClaims)
+	if !ok || !user.Role(userClaims.Role).IsAdmin() {
+		response.Error(c, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED", "Admin privileges required")
+		return
+	}
+
+	if h.purgeService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "PURGE_UNAVAILABLE", "PC purge is not configured")
+		return
+	}
+
+	pcID := c.Param("pcId")
+	if pcID == "" {
+		response.Error(c, http.StatusBadRequest, "INVALID_PC_ID", "PC ID is required")
+		return
+	}
+
+	summary, err := h.purgeService.PurgePC(c.Request.Context(), pcID, userClaims.UserID)
+	if errors.Is(err, pcservice.ErrPCNotFound) {
+		response.Error(c, http.StatusNotFound, "PC_NOT_FOUND", "PC not found")
+		return
+	}
+	if errors.Is(err, pcservice.ErrPCHasActiveSession) {
+		response.Error(c, http.StatusConflict, "PC_HAS_ACTIVE_SESSION", "PC has an active remote session")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "PURGE_FAILED", "Failed to purge PC: "+err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, summary)
+}
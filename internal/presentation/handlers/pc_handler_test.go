@@ -0,0 +1,545 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/pcservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/connectionsession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// MockPCService es un mock del servicio de PCs
+type MockPCService struct {
+	mock.Mock
+}
+
+func (m *MockPCService) RegisterPC(ctx context.Context, ownerUserID, pcIdentifier, ip string, metadata clientpc.PCMetadata) (*clientpc.ClientPC, error) {
+	args := m.Called(ctx, ownerUserID, pcIdentifier, ip, metadata)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) GetPCByID(ctx context.Context, pcID string) (*clientpc.ClientPC, error) {
+	args := m.Called(ctx, pcID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) GetPCsByOwner(ctx context.Context, ownerUserID string) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, ownerUserID)
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) GetOnlinePCsByOwner(ctx context.Context, ownerUserID string) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, ownerUserID)
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) UpdatePCConnectionStatus(ctx context.Context, pcID string, status clientpc.PCConnectionStatus) error {
+	return m.Called(ctx, pcID, status).Error(0)
+}
+
+func (m *MockPCService) UpdatePCLastSeen(ctx context.Context, pcID string) error {
+	return m.Called(ctx, pcID).Error(0)
+}
+
+func (m *MockPCService) GetAllClientPCs(ctx context.Context) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) GetOnlineClientPCs(ctx context.Context) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) GetClientPCsPage(ctx context.Context, limit, offset int) ([]*clientpc.ClientPC, int, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*clientpc.ClientPC), args.Int(1), args.Error(2)
+}
+
+func (m *MockPCService) SetAutoAcceptControl(ctx context.Context, pcID string, enabled bool) (*clientpc.ClientPC, error) {
+	args := m.Called(ctx, pcID, enabled)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockPCService) MarkPCConnecting(ctx context.Context, ownerUserID, pcIdentifier string) (*clientpc.ClientPC, error) {
+	args := m.Called(ctx, ownerUserID, pcIdentifier)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*clientpc.ClientPC), args.Error(1)
+}
+
+// MockConnectionHistoryService es un mock del servicio de historial de conexiones
+type MockConnectionHistoryService struct {
+	mock.Mock
+}
+
+func (m *MockConnectionHistoryService) RecordConnect(ctx context.Context, pcID, ipAddress string) (*connectionsession.ConnectionSession, error) {
+	args := m.Called(ctx, pcID, ipAddress)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*connectionsession.ConnectionSession), args.Error(1)
+}
+
+func (m *MockConnectionHistoryService) RecordDisconnect(ctx context.Context, session *connectionsession.ConnectionSession, reason string) error {
+	return m.Called(ctx, session, reason).Error(0)
+}
+
+func (m *MockConnectionHistoryService) GetConnectionHistory(ctx context.Context, pcID string, limit, offset int) ([]*connectionsession.ConnectionSession, error) {
+	args := m.Called(ctx, pcID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*connectionsession.ConnectionSession), args.Error(1)
+}
+
+func (m *MockConnectionHistoryService) CountConnectionHistory(ctx context.Context, pcID string) (int, error) {
+	args := m.Called(ctx, pcID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockConnectionHistoryService) CloseOpenConnections(ctx context.Context, pcID, reason string) (int, error) {
+	args := m.Called(ctx, pcID, reason)
+	return args.Int(0), args.Error(1)
+}
+
+func newTestPC(pcID string) *clientpc.ClientPC {
+	now := time.Now()
+	return &clientpc.ClientPC{
+		PCID:             pcID,
+		Identifier:       "LAB-PC-01",
+		IP:               "192.168.1.10",
+		ConnectionStatus: clientpc.PCConnectionStatusOnline,
+		OwnerUserID:      "owner-id",
+		RegisteredAt:     now,
+		UpdatedAt:        now,
+	}
+}
+
+// MockPinnedPCService es un mock del servicio de PCs fijados
+type MockPinnedPCService struct {
+	mock.Mock
+}
+
+func (m *MockPinnedPCService) PinPC(ctx context.Context, adminUserID, pcID string) error {
+	return m.Called(ctx, adminUserID, pcID).Error(0)
+}
+
+func (m *MockPinnedPCService) UnpinPC(ctx context.Context, adminUserID, pcID string) error {
+	return m.Called(ctx, adminUserID, pcID).Error(0)
+}
+
+func (m *MockPinnedPCService) GetPinnedPCs(ctx context.Context, adminUserID string, connectedPCIDs []string) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, adminUserID, connectedPCIDs)
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+// stubConnectedPCs simula las conexiones WebSocket vivas
+type stubConnectedPCs []string
+
+func (s stubConnectedPCs) ConnectedPCIDs() []string {
+	return s
+}
+
+func newTestPCHandler() (*PCHandler, *MockPCService, *MockConnectionHistoryService) {
+	pcService := new(MockPCService)
+	historyService := new(MockConnectionHistoryService)
+	return NewPCHandler(pcService, historyService, nil, stubConnectedPCs{}, nil), pcService, historyService
+}
+
+func newTestPinnedPCHandler(connectedPCIDs ...string) (*PCHandler, *MockPinnedPCService) {
+	pinnedPCService := new(MockPinnedPCService)
+	return NewPCHandler(new(MockPCService), new(MockConnectionHistoryService), pinnedPCService, stubConnectedPCs(connectedPCIDs), nil), pinnedPCService
+}
+
+func TestPCHandler_GetAllClientPCs_ReturnsEnvelopeWithPCs(t *testing.T) {
+	// Arrange
+	handler, pcService, _ := newTestPCHandler()
+	pcService.On("GetClientPCsPage", mock.Anything, 50, 0).Return([]*clientpc.ClientPC{newTestPC("pc-1"), newTestPC("pc-2")}, 2, nil)
+
+	router := newTestRouter(user.RoleAdministrator)
+	router.GET("/api/v1/admin/pcs", handler.GetAllClientPCs)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/pcs", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, float64(2), data["count"])
+	assert.Len(t, data["pcs"], 2)
+	assertPageMeta(t, recorder, dto.PageMeta{Limit: 50, Offset: 0, Total: 2, HasMore: false})
+	pcService.AssertExpectations(t)
+}
+
+func TestPCHandler_GetAllClientPCs_PageMeta(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		offset   int
+		pageSize int
+		expected dto.PageMeta
+	}{
+		{"first page", "?limit=2", 0, 2, dto.PageMeta{Limit: 2, Offset: 0, Total: 5, HasMore: true}},
+		{"middle page", "?limit=2&offset=2", 2, 2, dto.PageMeta{Limit: 2, Offset: 2, Total: 5, HasMore: true}},
+		{"last page", "?limit=2&offset=4", 4, 1, dto.PageMeta{Limit: 2, Offset: 4, Total: 5, HasMore: false}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler, pcService, _ := newTestPCHandler()
+			pagePCs := make([]*clientpc.ClientPC, tc.pageSize)
+			for i := range pagePCs {
+				pagePCs[i] = newTestPC("pc")
+			}
+			pcService.On("GetClientPCsPage", mock.Anything, 2, tc.offset).Return(pagePCs, 5, nil)
+
+			router := newTestRouter(user.RoleAdministrator)
+			router.GET("/api/v1/admin/pcs", handler.GetAllClientPCs)
+			recorder := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/pcs"+tc.query, nil))
+
+			// Assert
+			data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+			assert.Len(t, data["pcs"], tc.pageSize)
+			assertPageMeta(t, recorder, tc.expected)
+		})
+	}
+}
+
+func TestPCHandler_GetOnlineClientPCs_PaginatesFilteredList(t *testing.T) {
+	// Arrange
+	handler, pcService, _ := newTestPCHandler()
+	pcService.On("GetOnlineClientPCs", mock.Anything).
+		Return([]*clientpc.ClientPC{newTestPC("pc-1"), newTestPC("pc-2"), newTestPC("pc-3")}, nil)
+
+	router := newTestRouter(user.RoleAdministrator)
+	router.GET("/api/v1/admin/pcs/online", handler.GetOnlineClientPCs)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/pcs/online?limit=2&offset=2", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, float64(1), data["count"])
+	assertPageMeta(t, recorder, dto.PageMeta{Limit: 2, Offset: 2, Total: 3, HasMore: false})
+}
+
+func TestPCHandler_GetAllClientPCs_ServiceErrorReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	handler, pcService, _ := newTestPCHandler()
+	pcService.On("GetClientPCsPage", mock.Anything, 50, 0).Return([]*clientpc.ClientPC(nil), 0, errors.New("db down"))
+
+	router := newTestRouter(user.RoleAdministrator)
+	router.GET("/api/v1/admin/pcs", handler.GetAllClientPCs)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/pcs", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+}
+
+func TestPCHandler_GetOnlineClientPCs_NonAdminReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	handler, pcService, _ := newTestPCHandler()
+
+	router := newTestRouter(user.RoleClientUser)
+	router.GET("/api/v1/admin/pcs/online", handler.GetOnlineClientPCs)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/pcs/online", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED")
+	pcService.AssertNotCalled(t, "GetOnlineClientPCs", mock.Anything)
+}
+
+func TestPCHandler_GetConnectionHistory_ReturnsEnvelopeWithEmptyList(t *testing.T) {
+	// Arrange
+	handler, _, historyService := newTestPCHandler()
+	historyService.On("GetConnectionHistory", mock.Anything, "pc-1", pcservice.DefaultConnectionHistoryLimit, 0).
+		Return([]*connectionsession.ConnectionSession{}, nil)
+	historyService.On("CountConnectionHistory", mock.Anything, "pc-1").Return(0, nil)
+
+	router := newTestRouter(user.RoleAdministrator)
+	router.GET("/api/v1/admin/pcs/:pcId/connection-history", handler.GetConnectionHistory)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/pcs/pc-1/connection-history", nil))
+
+	// Assert - la lista vacía se serializa como [] y no como null
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, []interface{}{}, data["connections"])
+	assert.Equal(t, float64(0), data["count"])
+	assertPageMeta(t, recorder, dto.PageMeta{Limit: pcservice.DefaultConnectionHistoryLimit, Offset: 0, Total: 0, HasMore: false})
+}
+
+func TestPCHandler_GetConnectionHistory_PageMeta(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		offset   int
+		pageSize int
+		expected dto.PageMeta
+	}{
+		{"first page", "?limit=10", 0, 10, dto.PageMeta{Limit: 10, Offset: 0, Total: 25, HasMore: true}},
+		{"middle page", "?limit=10&offset=10", 10, 10, dto.PageMeta{Limit: 10, Offset: 10, Total: 25, HasMore: true}},
+		{"last page", "?limit=10&offset=20", 20, 5, dto.PageMeta{Limit: 10, Offset: 20, Total: 25, HasMore: false}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler, _, historyService := newTestPCHandler()
+			sessions := make([]*connectionsession.ConnectionSession, tc.pageSize)
+			for i := range sessions {
+				session, err := connectionsession.NewConnectionSession("pc-1", "192.168.1.10")
+				require.NoError(t, err)
+				sessions[i] = session
+			}
+			historyService.On("GetConnectionHistory", mock.Anything, "pc-1", 10, tc.offset).Return(sessions, nil)
+			historyService.On("CountConnectionHistory", mock.Anything, "pc-1").Return(25, nil)
+
+			router := newTestRouter(user.RoleAdministrator)
+			router.GET("/api/v1/admin/pcs/:pcId/connection-history", handler.GetConnectionHistory)
+			recorder := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/pcs/pc-1/connection-history"+tc.query, nil))
+
+			// Assert
+			data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+			assert.Len(t, data["connections"], tc.pageSize)
+			assertPageMeta(t, recorder, tc.expected)
+		})
+	}
+}
+
+func TestPCHandler_UpdateAutoAcceptPolicy_ReturnsEnvelopeWithPC(t *testing.T) {
+	// Arrange
+	handler, pcService, _ := newTestPCHandler()
+	pc := newTestPC("pc-1")
+	pc.SetAutoAcceptControl(true)
+	pcService.On("SetAutoAcceptControl", mock.Anything, "pc-1", true).Return(pc, nil)
+
+	router := newTestRouter(user.RoleAdministrator)
+	router.PUT("/api/v1/admin/pcs/:pcId/auto-accept", handler.UpdateAutoAcceptPolicy)
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPut, "/api/v1/admin/pcs/pc-1/auto-accept", strings.NewReader(`{"enabled": true}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	// Act
+	router.ServeHTTP(recorder, request)
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, "pc-1", data["pcId"])
+	assert.Equal(t, true, data["autoAcceptControl"])
+}
+
+func TestPCHandler_UpdateAutoAcceptPolicy_MissingFieldReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	handler, _, _ := newTestPCHandler()
+
+	router := newTestRouter(user.RoleAdministrator)
+	router.PUT("/api/v1/admin/pcs/:pcId/auto-accept", handler.UpdateAutoAcceptPolicy)
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPut, "/api/v1/admin/pcs/pc-1/auto-accept", strings.NewReader(`{}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	// Act
+	router.ServeHTTP(recorder, request)
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusBadRequest, "INVALID_REQUEST")
+}
+
+func TestPCHandler_PinPC_ReturnsPinnedState(t *testing.T) {
+	// Arrange
+	handler, pinnedPCService := newTestPinnedPCHandler()
+	pinnedPCService.On("PinPC", mock.Anything, "admin-id", "pc-1").Return(nil)
+
+	router := newTestRouter(user.RoleAdministrator)
+	router.POST("/api/v1/admin/pcs/:pcId/pin", handler.PinPC)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/pcs/pc-1/pin", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, "pc-1", data["pcId"])
+	assert.Equal(t, true, data["pinned"])
+	pinnedPCService.AssertExpectations(t)
+}
+
+func TestPCHandler_PinPC_UnknownPCReturnsNotFound(t *testing.T) {
+	// Arrange
+	handler, pinnedPCService := newTestPinnedPCHandler()
+	pinnedPCService.On("PinPC", mock.Anything, "admin-id", "missing-pc").
+		Return(fmt.Errorf("%w: missing-pc", pcservice.ErrPCNotFound))
+
+	router := newTestRouter(user.RoleAdministrator)
+	router.POST("/api/v1/admin/pcs/:pcId/pin", handler.PinPC)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/pcs/missing-pc/pin", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusNotFound, "PC_NOT_FOUND")
+}
+
+func TestPCHandler_PinPC_NonAdminReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	handler, pinnedPCService := newTestPinnedPCHandler()
+
+	router := newTestRouter(user.RoleClientUser)
+	router.POST("/api/v1/admin/pcs/:pcId/pin", handler.PinPC)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/pcs/pc-1/pin", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED")
+	pinnedPCService.AssertNotCalled(t, "PinPC", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPCHandler_UnpinPC_ReturnsUnpinnedState(t *testing.T) {
+	// Arrange
+	handler, pinnedPCService := newTestPinnedPCHandler()
+	pinnedPCService.On("UnpinPC", mock.Anything, "admin-id", "pc-1").Return(nil)
+
+	router := newTestRouter(user.RoleAdministrator)
+	router.DELETE("/api/v1/admin/pcs/:pcId/pin", handler.UnpinPC)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/pcs/pc-1/pin", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, "pc-1", data["pcId"])
+	assert.Equal(t, false, data["pinned"])
+	pinnedPCService.AssertExpectations(t)
+}
+
+func TestPCHandler_GetPinnedPCs_ReflectsLiveStatus(t *testing.T) {
+	// Arrange
+	handler, pinnedPCService := newTestPinnedPCHandler("pc-1")
+	onlinePC := newTestPC("pc-1")
+	offlinePC := newTestPC("pc-2")
+	offlinePC.ConnectionStatus = clientpc.PCConnectionStatusOffline
+	pinnedPCService.On("GetPinnedPCs", mock.Anything, "admin-id", []string{"pc-1"}).
+		Return([]*clientpc.ClientPC{onlinePC, offlinePC}, nil)
+
+	router := newTestRouter(user.RoleAdministrator)
+	router.GET("/api/v1/admin/pcs/pinned", handler.GetPinnedPCs)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/pcs/pinned", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, float64(2), data["count"])
+	pcs := data["pcs"].([]interface{})
+	require.Len(t, pcs, 2)
+	assert.Equal(t, "ONLINE", pcs[0].(map[string]interface{})["connectionStatus"])
+	assert.Equal(t, "OFFLINE", pcs[1].(map[string]interface{})["connectionStatus"])
+	pinnedPCService.AssertExpectations(t)
+}
+
+// MockPCPurgeService es un mock del servicio de purga de PCs
+type MockPCPurgeService struct {
+	mock.Mock
+}
+
+func (m *MockPCPurgeService) PurgePC(ctx context.Context, pcID, adminUserID string) (*pcservice.PCPurgeSummary, error) {
+	args := m.Called(ctx, pcID, adminUserID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pcservice.PCPurgeSummary), args.Error(1)
+}
+
+func TestPCHandler_PurgePC_ReturnsSummary(t *testing.T) {
+	// Arrange
+	handler, _, _ := newTestPCHandler()
+	purgeService := new(MockPCPurgeService)
+	purgeService.On("PurgePC", mock.Anything, "pc-1", "admin-id").Return(&pcservice.PCPurgeSummary{
+		PCID: "pc-1", Identifier: "retired-pc", RecordingsDeleted: 2, TransfersDeleted: 1, SessionsArchived: 3,
+	}, nil)
+	handler.SetPurgeService(purgeService)
+
+	router := newTestRouter(user.RoleAdministrator)
+	router.DELETE("/api/v1/admin/pcs/:pcId/purge", handler.PurgePC)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/pcs/pc-1/purge", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, "pc-1", data["pc_id"])
+	assert.Equal(t, float64(2), data["recordings_deleted"])
+	assert.Equal(t, float64(1), data["transfers_deleted"])
+	assert.Equal(t, float64(3), data["sessions_archived"])
+	purgeService.AssertExpectations(t)
+}
+
+func TestPCHandler_PurgePC_ActiveSessionReturnsConflict(t *testing.T) {
+	// Arrange
+	handler, _, _ := newTestPCHandler()
+	purgeService := new(MockPCPurgeService)
+	purgeService.On("PurgePC", mock.Anything, "pc-1", "admin-id").
+		Return(nil, fmt.Errorf("%w: session-1", pcservice.ErrPCHasActiveSession))
+	handler.SetPurgeService(purgeService)
+
+	router := newTestRouter(user.RoleAdministrator)
+	router.DELETE("/api/v1/admin/pcs/:pcId/purge", handler.PurgePC)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/pcs/pc-1/purge", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusConflict, "PC_HAS_ACTIVE_SESSION")
+}
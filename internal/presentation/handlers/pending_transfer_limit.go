@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+)
+
+// DefaultMaxPendingTransfersPerClient transferencias pendientes que se envían a la vez a un cliente que se
+// reconecta; el resto espera a los siguientes heartbeats
+const DefaultMaxPendingTransfersPerClient = 5
+
+// SetMaxPendingTransfersPerClient configura cuántas transferencias pendientes puede tener un cliente en curso a la
+// vez; <= 0 envía todas las pendientes en el primer heartbeat
+func (h *WebSocketHandler) SetMaxPendingTransfersPerClient(max int) {
+	h.maxPendingTransfersPerClient = max
+}
+
+// pendingTransferQueue transferencias pendientes que ya se están procesando, para que los heartbeats siguientes
+// no las vuelvan a lanzar y solo completen el cupo de cada cliente
+type pendingTransferQueue struct {
+	inFlight map[string]string // map[transferID]pcID
+	mutex    sync.Mutex
+}
+
+func newPendingTransferQueue() *pendingTransferQueue {
+	return &pendingTransferQueue{inFlight: make(map[string]string)}
+}
+
+// claim reserva, de la más antigua a la más reciente, las transferencias pendientes que caben en el cupo del
+// cliente descontando las que ya tiene en curso; limit <= 0 no limita
+func (q *pendingTransferQueue) claim(clientPCID string, pending []*filetransfer.FileTransfer, limit int) []*filetransfer.FileTransfer {
+	ordered := make([]*filetransfer.FileTransfer, len(pending))
+	copy(ordered, pending)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].CreatedAt().Before(ordered[j].CreatedAt())
+	})
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	running := 0
+	for _, pcID := range q.inFlight {
+		if pcID == clientPCID {
+			running++
+		}
+	}
+
+	claimed := make([]*filetransfer.FileTransfer, 0, len(ordered))
+	for _, transfer := range ordered {
+		if limit > 0 && running+len(claimed) >= limit {
+			break
+		}
+		if _, exists := q.inFlight[transfer.TransferID()]; exists {
+			continue
+		}
+		q.inFlight[transfer.TransferID()] = clientPCID
+		claimed = append(claimed, transfer)
+	}
+	return claimed
+}
+
+// release libera el hueco de una transferencia que terminó de procesarse
+func (q *pendingTransferQueue) release(transferID string) {
+	q.mutex.Lock()
+	delete(q.inFlight, transferID)
+	q.mutex.Unlock()
+}
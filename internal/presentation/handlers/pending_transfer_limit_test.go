@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/backgroundtaskservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+)
+
+// discardWriter descarta la respuesta del heartbeat
+type discardWriter struct{}
+
+func (discardWriter) WriteJSON(v interface{}) error                   { return nil }
+func (discardWriter) WriteMessage(messageType int, data []byte) error { return nil }
+
+// newPendingTransfers crea transferencias PENDING creadas con un minuto de diferencia y las retorna como el
+// repositorio, de la más reciente a la más antigua
+func newPendingTransfers(count int) []*filetransfer.FileTransfer {
+	base := time.Now().Add(-time.Hour)
+	transfers := make([]*filetransfer.FileTransfer, count)
+	for i := 0; i < count; i++ {
+		createdAt := base.Add(time.Duration(i) * time.Minute)
+		transfers[count-1-i] = filetransfer.NewFileTransferFromDB(
+			fmt.Sprintf("transfer-%02d", i), fmt.Sprintf("file-%02d.txt", i), "/srv/files/file.txt", fmt.Sprintf("C:/Downloads/file-%02d.txt", i),
+			nil, filetransfer.TransferStatusPending, "session-1", "admin-1", testTargetPCID, 1, "",
+			filetransfer.DefaultConflictPolicy, createdAt, createdAt)
+	}
+	return transfers
+}
+
+// receiveAttempted espera la transferencia que el handler intentó enviar (y marcó FAILED al no haber socket)
+func receiveAttempted(t *testing.T, attempted <-chan string) string {
+	t.Helper()
+
+	select {
+	case transferID := <-attempted:
+		return transferID
+	case <-time.After(2 * time.Second):
+		t.Fatal("pending transfer was not processed")
+		return ""
+	}
+}
+
+func TestHandleHeartbeat_ProcessesOnlyOldestPendingTransfersUpToLimit(t *testing.T) {
+	// Arrange - 8 pendientes y cupo de 3; el cliente no tiene socket registrado, así que cada envío falla al momento
+	h, transferRepo := newTestWebSocketHandler()
+	pcService := new(MockPCService)
+	pcService.On("GetPCByID", mock.Anything, testTargetPCID).Return(nil, nil)
+	pcService.On("UpdatePCLastSeen", mock.Anything, testTargetPCID).Return(nil)
+	pcService.On("UpdatePCConnectionStatus", mock.Anything, testTargetPCID, mock.Anything).Return(nil)
+	h.pcService = pcService
+	h.SetTaskRegistry(backgroundtaskservice.NewTaskRegistry())
+	h.SetMaxPendingTransfersPerClient(3)
+
+	transfers := newPendingTransfers(8)
+	transferRepo.On("FindByTargetPCID", mock.Anything, testTargetPCID).Return(transfers, nil)
+	// El primer lote queda retenido hasta que el test lo libera
+	gate := make(chan struct{})
+	for _, transfer := range transfers {
+		transferRepo.On("FindByID", mock.Anything, transfer.TransferID()).
+			Run(func(mock.Arguments) { <-gate }).Return(transfer, nil)
+	}
+	attempted := make(chan string, len(transfers))
+	transferRepo.On("UpdateStatus", mock.Anything, mock.Anything, filetransfer.TransferStatusFailed, mock.Anything).
+		Run(func(args mock.Arguments) { attempted <- args.String(1) }).Return(nil)
+
+	clientConn := &ClientConnection{PCID: testTargetPCID, IsAuth: true}
+	heartbeat := func() {
+		h.handleHeartbeat(discardWriter{}, clientConn, map[string]interface{}{"pc_id": testTargetPCID})
+	}
+
+	// Act - primer heartbeat y un segundo mientras el lote sigue en curso
+	heartbeat()
+	heartbeat()
+	firstBatch := make([]string, 0, 3)
+	for _, task := range h.taskRegistry.List() {
+		firstBatch = append(firstBatch, task.TransferID)
+	}
+	close(gate)
+	processed := []string{receiveAttempted(t, attempted), receiveAttempted(t, attempted), receiveAttempted(t, attempted)}
+
+	// Assert - solo las 3 más antiguas, en orden, y el segundo heartbeat no lanzó nada más
+	assert.ElementsMatch(t, []string{"transfer-00", "transfer-01", "transfer-02"}, firstBatch)
+	assert.Equal(t, []string{"transfer-00", "transfer-01", "transfer-02"}, processed)
+	require.Eventually(t, func() bool {
+		h.pendingTransfers.mutex.Lock()
+		defer h.pendingTransfers.mutex.Unlock()
+		return len(h.pendingTransfers.inFlight) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Empty(t, h.taskRegistry.List())
+	assert.Empty(t, attempted)
+
+	// Act - el siguiente heartbeat continúa con las siguientes
+	heartbeat()
+	next := []string{receiveAttempted(t, attempted), receiveAttempted(t, attempted), receiveAttempted(t, attempted)}
+
+	// Assert
+	assert.Equal(t, []string{"transfer-03", "transfer-04", "transfer-05"}, next)
+}
+
+func TestHandleHeartbeat_HoldsPendingTransferBehindSameDestination(t *testing.T) {
+	// Arrange - dos pendientes al mismo archivo del cliente y una a otro archivo
+	h, transferRepo := newTestWebSocketHandler()
+	pcService := new(MockPCService)
+	pcService.On("GetPCByID", mock.Anything, testTargetPCID).Return(nil, nil)
+	pcService.On("UpdatePCLastSeen", mock.Anything, testTargetPCID).Return(nil)
+	pcService.On("UpdatePCConnectionStatus", mock.Anything, testTargetPCID, mock.Anything).Return(nil)
+	h.pcService = pcService
+	h.SetMaxPendingTransfersPerClient(0)
+
+	base := time.Now().Add(-time.Hour)
+	pending := func(transferID, destination string, minutes int) *filetransfer.FileTransfer {
+		createdAt := base.Add(time.Duration(minutes) * time.Minute)
+		return filetransfer.NewFileTransferFromDB(transferID, "report.pdf", "/srv/files/report.pdf", destination,
+			nil, filetransfer.TransferStatusPending, "session-1", "admin-1", testTargetPCID, 1, "",
+			filetransfer.DefaultConflictPolicy, createdAt, createdAt)
+	}
+	transfers := []*filetransfer.FileTransfer{
+		pending("transfer-second", "C:/Downloads/report.pdf", 2),
+		pending("transfer-other", "C:/Downloads/notes.txt", 1),
+		pending("transfer-first", "C:/Downloads/report.pdf", 0),
+	}
+	transferRepo.On("FindByTargetPCID", mock.Anything, testTargetPCID).Return(transfers, nil)
+	for _, transfer := range transfers {
+		transferRepo.On("FindByID", mock.Anything, transfer.TransferID()).Return(transfer, nil)
+	}
+	attempted := make(chan string, len(transfers))
+	transferRepo.On("UpdateStatus", mock.Anything, mock.Anything, filetransfer.TransferStatusFailed, mock.Anything).
+		Run(func(args mock.Arguments) { attempted <- args.String(1) }).Return(nil)
+
+	clientConn := &ClientConnection{PCID: testTargetPCID, IsAuth: true}
+
+	// Act
+	h.handleHeartbeat(discardWriter{}, clientConn, map[string]interface{}{"pc_id": testTargetPCID})
+	processed := []string{receiveAttempted(t, attempted), receiveAttempted(t, attempted)}
+
+	// Assert - la segunda al mismo destino espera a que la primera termine
+	assert.Equal(t, []string{"transfer-first", "transfer-other"}, processed)
+	select {
+	case transferID := <-attempted:
+		t.Fatalf("transfer %s was sent while another transfer to the same destination was pending", transferID)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// handleQueryPendingTransfers responde a query_pending_transfers con las transferencias PENDING del PC, para que un
+// cliente que se reconecta pueda conciliar su estado sin depender solo de los envíos del servidor
+func (h *WebSocketHandler) handleQueryPendingTransfers(conn messageWriter, clientConn *ClientConnection) {
+	if !clientConn.IsAuth || clientConn.PCID == "" {
+		log.Printf("❌ PENDING TRANSFERS: Unregistered client attempted to query pending transfers")
+		return
+	}
+	if h.fileTransferService == nil {
+		return
+	}
+
+	transfers, err := h.fileTransferService.GetTransfersByTargetPC(clientConn.Context(), clientConn.PCID)
+	if err != nil {
+		log.Printf("❌ PENDING TRANSFERS: Error getting transfers for client %s: %v", clientConn.PCID, err)
+		return
+	}
+
+	list := dto.PendingTransfersList{PCID: clientConn.PCID, Transfers: make([]dto.PendingTransferSummary, 0)}
+	for _, transfer := range transfers {
+		if transfer.Status() != filetransfer.TransferStatusPending {
+			continue
+		}
+		list.Transfers = append(list.Transfers, dto.PendingTransferSummary{
+			TransferID: transfer.TransferID(),
+			FileName:   transfer.FileName(),
+			FileSizeMB: transfer.FileSizeMB(),
+		})
+	}
+
+	message := dto.WebSocketMessage{Type: dto.MessageTypePendingTransfersList, Data: list}
+	if err := conn.WriteJSON(message); err != nil {
+		log.Printf("❌ PENDING TRANSFERS: Error sending pending transfers list to PC %s: %v", clientConn.PCID, err)
+		return
+	}
+	log.Printf("📋 PENDING TRANSFERS: Sent %d pending transfers to PC %s", len(list.Transfers), clientConn.PCID)
+}
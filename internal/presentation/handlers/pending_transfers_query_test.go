@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+func TestQueryPendingTransfers_ConnectedClientReceivesItsPendingList(t *testing.T) {
+	// Arrange - dos pendientes y una ya completada que no debe aparecer
+	h, transferRepo := newTestWebSocketHandler()
+	clientSide, clientConn := connectTestClient(t, h)
+	transfers := newPendingTransfers(2)
+	completedAt := time.Now().Add(-time.Minute)
+	completed := filetransfer.NewFileTransferFromDB("transfer-done", "done.txt", "/srv/files/done.txt", "C:/Downloads/done.txt",
+		&completedAt, filetransfer.TransferStatusCompleted, "session-1", "admin-1", testTargetPCID, 2, "",
+		filetransfer.DefaultConflictPolicy, completedAt, completedAt)
+	transferRepo.On("FindByTargetPCID", mock.Anything, testTargetPCID).Return(append(transfers, completed), nil)
+	var reason remotesession.DisconnectReason
+
+	// Act
+	h.dispatchClientMessage(clientConn.writer(), clientConn, dto.WebSocketMessage{Type: dto.MessageTypeQueryPendingTransfers}, "", &reason)
+
+	// Assert
+	messageType, data := readClientMessage(t, clientSide)
+	require.Equal(t, dto.MessageTypePendingTransfersList, messageType)
+	assert.Equal(t, testTargetPCID, data["pc_id"])
+	listed, ok := data["transfers"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, listed, 2)
+	for i, entry := range listed {
+		summary := entry.(map[string]interface{})
+		assert.Equal(t, transfers[i].TransferID(), summary["transfer_id"])
+		assert.Equal(t, transfers[i].FileName(), summary["file_name"])
+		assert.Equal(t, float64(1), summary["file_size_mb"])
+	}
+}
+
+func TestQueryPendingTransfers_UnregisteredClientGetsNoList(t *testing.T) {
+	// Arrange
+	h, transferRepo := newTestWebSocketHandler()
+	clientSide, clientConn := connectTestClient(t, h)
+	clientConn.IsAuth = false
+
+	// Act
+	h.handleQueryPendingTransfers(clientConn.writer(), clientConn)
+
+	// Assert
+	assertNoClientMessage(t, clientSide)
+	transferRepo.AssertNotCalled(t, "FindByTargetPCID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+var (
+	// ErrInvalidProtocolVersion la versión informada por el cliente no tiene el formato MAJOR.MINOR
+	ErrInvalidProtocolVersion = errors.New("invalid protocol version")
+	// ErrProtocolIncompatible la versión mayor del cliente no está soportada por el servidor
+	ErrProtocolIncompatible = errors.New("protocol version incompatible")
+)
+
+// ProtocolVersion versión del protocolo de mensajes WebSocket; las versiones con la misma mayor son compatibles
+type ProtocolVersion struct {
+	Major int
+	Minor int
+}
+
+var (
+	// CurrentProtocolVersion versión que habla el servidor
+	CurrentProtocolVersion = ProtocolVersion{Major: 2, Minor: 0}
+	// LegacyProtocolVersion versión que se asume para los clientes anteriores al versionado, que no la informan
+	LegacyProtocolVersion = ProtocolVersion{Major: 1, Minor: 0}
+)
+
+// shimmedProtocolMajors versiones mayores antiguas que se siguen aceptando con adaptaciones de compatibilidad
+var shimmedProtocolMajors = map[int]bool{
+	LegacyProtocolVersion.Major: true,
+}
+
+// String formato MAJOR.MINOR
+func (v ProtocolVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// ParseProtocolVersion interpreta "MAJOR" o "MAJOR.MINOR"; vacío equivale a LegacyProtocolVersion
+func ParseProtocolVersion(raw string) (ProtocolVersion, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return LegacyProtocolVersion, nil
+	}
+
+	majorPart, minorPart, hasMinor := strings.Cut(raw, ".")
+	major, err := strconv.Atoi(majorPart)
+	if err != nil || major < 0 {
+		return ProtocolVersion{}, fmt.Errorf("%w: %q", ErrInvalidProtocolVersion, raw)
+	}
+	minor := 0
+	if hasMinor {
+		minor, err = strconv.Atoi(minorPart)
+		if err != nil || minor < 0 {
+			return ProtocolVersion{}, fmt.Errorf("%w: %q", ErrInvalidProtocolVersion, raw)
+		}
+	}
+	return ProtocolVersion{Major: major, Minor: minor}, nil
+}
+
+// negotiateProtocolVersion decide cómo hablar con el cliente: la versión actual, una mayor antigua con
+// adaptaciones (shimmed = true) o ErrProtocolIncompatible
+func negotiateProtocolVersion(requested string) (version ProtocolVersion, shimmed bool, err error) {
+	version, err = ParseProtocolVersion(requested)
+	if err != nil {
+		return ProtocolVersion{}, false, fmt.Errorf("%w: %v", ErrProtocolIncompatible, err)
+	}
+
+	switch {
+	case version.Major == CurrentProtocolVersion.Major:
+		// Con la misma mayor se habla la menor más baja de las dos
+		if version.Minor > CurrentProtocolVersion.Minor {
+			version.Minor = CurrentProtocolVersion.Minor
+		}
+		return version, false, nil
+	case shimmedProtocolMajors[version.Major]:
+		return version, true, nil
+	default:
+		return ProtocolVersion{}, false, fmt.Errorf("%w: client %s, server %s", ErrProtocolIncompatible, version, CurrentProtocolVersion)
+	}
+}
+
+// supportedProtocolVersions versiones mayores aceptadas, de la más reciente a la más antigua ("2.x", "1.x")
+func supportedProtocolVersions() []string {
+	versions := []string{fmt.Sprintf("%d.x", CurrentProtocolVersion.Major)}
+	for major := CurrentProtocolVersion.Major - 1; major >= 0; major-- {
+		if shimmedProtocolMajors[major] {
+			versions = append(versions, fmt.Sprintf("%d.x", major))
+		}
+	}
+	return versions
+}
+
+// legacyProtocol indica si la conexión negoció una versión mayor antigua y necesita adaptaciones de
+// compatibilidad. Las conexiones sin versión negociada usan el protocolo actual.
+func (c *ClientConnection) legacyProtocol() bool {
+	return c.protocolVersion.Major != 0 && c.protocolVersion.Major != CurrentProtocolVersion.Major
+}
+
+// sendProtocolIncompatible indica al cliente que debe actualizarse para hablar con este servidor
+func sendProtocolIncompatible(conn messageWriter, clientVersion string, err error) {
+	log.Printf("⛔ PROTOCOL: Rejecting client with protocol version %q: %v", clientVersion, err)
+	conn.WriteJSON(dto.WebSocketMessage{
+		Type: dto.MessageTypeProtocolIncompatible,
+		Data: dto.ProtocolIncompatible{
+			ClientVersion:     clientVersion,
+			ServerVersion:     CurrentProtocolVersion.String(),
+			SupportedVersions: supportedProtocolVersions(),
+			Message:           "Client protocol version is not supported, please update the client",
+		},
+	})
+}
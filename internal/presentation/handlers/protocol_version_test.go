@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/filetransferservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newTestAuthHandler handler con un usuario cliente "client" / "password" y un cliente conectado sin autenticar
+func newTestAuthHandler(t *testing.T) (*WebSocketHandler, *MockUserRepository, *websocket.Conn, *ClientConnection) {
+	t.Helper()
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByUsername", "client").Return(user.NewUser(testRESTOwnerUserID, "client", "", string(hashedPassword), user.RoleClientUser), nil)
+
+	transferService := filetransferservice.NewFileTransferService(new(MockFileTransferRepository), nil, nil, nil)
+	h := NewWebSocketHandler(userservice.NewAuthService(userRepo, "test-secret"), nil, nil, nil, transferService, nil)
+	clientSide, clientConn := connectTestClient(t, h)
+	clientConn.IsAuth = false // todavía no envió CLIENT_AUTH_REQUEST
+	return h, userRepo, clientSide, clientConn
+}
+
+// authenticateWithVersion envía CLIENT_AUTH_REQUEST con la versión indicada (vacía = sin informar)
+func authenticateWithVersion(h *WebSocketHandler, clientConn *ClientConnection, protocolVersion string) {
+	request := map[string]interface{}{"username": "client", "password": "password"}
+	if protocolVersion != "" {
+		request["protocolVersion"] = protocolVersion
+	}
+	h.handleClientAuth(clientConn.Conn, clientConn, request)
+}
+
+// readClientMessage lee el siguiente mensaje JSON que recibe el cliente
+func readClientMessage(t *testing.T, clientSide *websocket.Conn) (string, map[string]interface{}) {
+	t.Helper()
+
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, clientSide.ReadJSON(&message))
+	data, _ := message.Data.(map[string]interface{})
+	return message.Type, data
+}
+
+func TestHandleClientAuth_CompatibleProtocolVersionProceeds(t *testing.T) {
+	// Arrange - misma versión mayor que el servidor y una menor más reciente
+	h, _, clientSide, clientConn := newTestAuthHandler(t)
+
+	// Act
+	authenticateWithVersion(h, clientConn, "2.3")
+
+	// Assert
+	messageType, data := readClientMessage(t, clientSide)
+	require.Equal(t, dto.MessageTypeClientAuthResp, messageType)
+	assert.Equal(t, true, data["success"])
+	assert.Equal(t, "2.0", data["protocolVersion"])
+	assert.True(t, clientConn.IsAuth)
+	assert.Equal(t, CurrentProtocolVersion, clientConn.protocolVersion)
+	assert.False(t, clientConn.legacyProtocol())
+}
+
+func TestHandleClientAuth_LegacyProtocolVersionEnablesShims(t *testing.T) {
+	// Arrange - cliente anterior al versionado: no informa versión ni conoce session_end_ack
+	h, _, clientSide, clientConn := newTestAuthHandler(t)
+	h.SetSessionEndAckTimeout(30 * time.Millisecond)
+
+	// Act
+	authenticateWithVersion(h, clientConn, "")
+	messageType, data := readClientMessage(t, clientSide)
+	require.NoError(t, h.SendSessionEndedToClient(testEndedSessionID, testTargetPCID))
+	readSessionEnded(t, clientSide)
+
+	// Assert - se autentica con el protocolo 1 y no se espera su confirmación de fin de sesión
+	require.Equal(t, dto.MessageTypeClientAuthResp, messageType)
+	assert.Equal(t, true, data["success"])
+	assert.Equal(t, "1.0", data["protocolVersion"])
+	assert.Equal(t, LegacyProtocolVersion, clientConn.protocolVersion)
+	assert.True(t, clientConn.legacyProtocol())
+	h.sessionEndAcks.mutex.Lock()
+	assert.Empty(t, h.sessionEndAcks.timers)
+	h.sessionEndAcks.mutex.Unlock()
+}
+
+func TestHandleClientAuth_IncompatibleProtocolVersionIsRejected(t *testing.T) {
+	// Arrange - versión mayor más reciente que la del servidor
+	h, userRepo, clientSide, clientConn := newTestAuthHandler(t)
+
+	// Act
+	authenticateWithVersion(h, clientConn, "3.0")
+
+	// Assert - primero protocol_incompatible y después la autenticación fallida, sin comprobar credenciales
+	messageType, data := readClientMessage(t, clientSide)
+	require.Equal(t, dto.MessageTypeProtocolIncompatible, messageType)
+	assert.Equal(t, "3.0", data["client_version"])
+	assert.Equal(t, CurrentProtocolVersion.String(), data["server_version"])
+	assert.Equal(t, []interface{}{"2.x", "1.x"}, data["supported_versions"])
+	assert.NotEmpty(t, data["message"])
+
+	messageType, data = readClientMessage(t, clientSide)
+	require.Equal(t, dto.MessageTypeClientAuthResp, messageType)
+	assert.Equal(t, false, data["success"])
+	assert.Equal(t, dto.MessageTypeProtocolIncompatible, data["error"])
+	assert.False(t, clientConn.IsAuth)
+	userRepo.AssertNotCalled(t, "FindByUsername", mock.Anything)
+}
+
+func TestParseProtocolVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected ProtocolVersion
+		wantErr  bool
+	}{
+		{name: "major and minor", raw: "2.1", expected: ProtocolVersion{Major: 2, Minor: 1}},
+		{name: "major only", raw: " 2 ", expected: ProtocolVersion{Major: 2}},
+		{name: "empty is legacy", raw: "", expected: LegacyProtocolVersion},
+		{name: "not a number", raw: "v2", wantErr: true},
+		{name: "negative minor", raw: "2.-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			version, err := ParseProtocolVersion(tt.raw)
+
+			// Assert
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidProtocolVersion)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, version)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// releaseClientRecordings libera en videoService las grabaciones de las sesiones de las que la conexión envió frames
+func (h *WebSocketHandler) releaseClientRecordings(clientConn *ClientConnection) {
+	videoService, ok := h.videoService.(videoservice.IVideoService)
+	if !ok || videoService == nil {
+		return
+	}
+	for sessionID := range clientConn.recordingSessions {
+		if released := videoService.ReleaseSessionRecordings(sessionID); released > 0 {
+			log.Printf("🎞️ VIDEO RECORDING: PC %s disconnected, released %d unfinished recording(s) of session %s", clientConn.PCID, released, sessionID)
+		}
+	}
+}
+
+// refuseRecordingOverCapacity rechaza (una vez por grabación) un video que no cabe en el máximo de grabaciones
+// simultáneas. El cliente recibe recording_capacity_reached y deja de enviar frames; la sesión sigue sin grabar
+// y el administrador de la sesión recibe el aviso.
+func (h *WebSocketHandler) refuseRecordingOverCapacity(conn messageWriter, clientConn *ClientConnection, sessionID, videoID string, err error) {
+	if !clientConn.recordingState.markOnce(sessionID, recordingCapacityKey+videoID) {
+		return
+	}
+
+	log.Printf("⛔ VIDEO RECORDING: Recording %s of session %s from PC %s refused, %v", videoID, sessionID, clientConn.PCID, err)
+
+	conn.WriteJSON(dto.WebSocketMessage{
+		Type: "recording_capacity_reached",
+		Data: map[string]interface{}{
+			"session_id": sessionID,
+			"video_id":   videoID,
+			"error_code": "RECORDING_CAPACITY_REACHED",
+			"error":      "Server is at its maximum of concurrent recordings, the session continues without recording",
+		},
+	})
+
+	if h.adminWSHandler == nil || h.sessionService == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if session, sessErr := h.sessionService.GetSessionById(ctx, sessionID); sessErr == nil && session != nil {
+		h.adminWSHandler.NotifyRecordingCapacityReached(session.AdminUserID(), sessionID, clientConn.PCID, videoID)
+	}
+}
+
+// NotifyRecordingCapacityReached avisa al administrador de la sesión que su grabación no se inició por haber
+// alcanzado el máximo de grabaciones simultáneas
+func (h *AdminWebSocketHandler) NotifyRecordingCapacityReached(adminUserID, sessionID, clientPCID, videoID string) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	notification := dto.WebSocketMessage{
+		Type: "recording_capacity_reached",
+		Data: map[string]interface{}{
+			"session_id":   sessionID,
+			"client_pc_id": clientPCID,
+			"video_id":     videoID,
+			"message":      "Maximum concurrent recordings reached, session continues without recording",
+			"timestamp":    time.Now().Unix(),
+		},
+	}
+
+	for _, adminConn := range h.adminConnections {
+		if adminConn.UserID == adminUserID {
+			if err := adminConn.writer().WriteJSON(notification); err != nil {
+				log.Printf("Error sending recording capacity notification to admin %s: %v", adminUserID, err)
+			}
+		}
+	}
+
+	log.Printf("⚠️ ADMIN NOTIFICATION: Recording %s of session %s not started, concurrent recording limit reached", videoID, sessionID)
+}
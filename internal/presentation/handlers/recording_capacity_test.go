@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// fullVideoService rechaza cualquier grabación nueva como si el servidor estuviera al máximo de grabaciones
+type fullVideoService struct {
+	spyVideoService
+	attempts int
+}
+
+func (s *fullVideoService) SaveVideoFrame(frameInfo videoservice.VideoFrameInfo) error {
+	s.attempts++
+	return fmt.Errorf("%w: video %s", videoservice.ErrRecordingCapacityReached, frameInfo.VideoID)
+}
+
+func TestHandleVideoFrameUpload_RecordingOverCapacityIsRefusedOnce(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	videoService := &fullVideoService{}
+	h.videoService = videoService
+	clientSide, clientConn := connectTestClient(t, h)
+
+	// Act
+	h.handleVideoFrameUpload(clientConn.Conn, clientConn, testVideoFrameData(1))
+	h.handleVideoFrameUpload(clientConn.Conn, clientConn, testVideoFrameData(2))
+
+	// Assert - el cliente recibe un único aviso y los frames siguientes ni se intentan guardar
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, "recording_capacity_reached", message.Type)
+	data := message.Data.(map[string]interface{})
+	assert.Equal(t, "RECORDING_CAPACITY_REACHED", data["error_code"])
+	assert.Equal(t, "video-1", data["video_id"])
+	assert.Equal(t, 1, videoService.attempts)
+
+	clientSide.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	assert.Error(t, clientSide.ReadJSON(&message))
+}
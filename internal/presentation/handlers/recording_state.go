@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+)
+
+// Claves de recordingState; las que dependen de la grabación llevan el videoID detrás
+const (
+	recordingQuotaKey      = "quota:"
+	recordingLimitKey      = "limit:"
+	recordingDisabledKey   = "disabled:"
+	recordingCapacityKey   = "capacity:"
+	recordingPermissionKey = "permission"
+)
+
+// recordingState decisiones de grabación de una conexión que no se repiten en cada frame (permiso, cuota, capacidad) y
+// avisos ya enviados al cliente, agrupados por sesión. Lo escribe la goroutine que procesa los frames y lo limpia
+// quien termina la sesión, por eso lleva su propio mutex. Las entradas de una sesión se olvidan al terminar la
+// sesión; las de la conexión se descartan con ella al desconectarse.
+type recordingState struct {
+	mu       sync.Mutex
+	sessions map[string]map[string]bool
+	// permissions hasta cuándo vale el permiso validado de cada grabación (videoID), por sesión
+	permissions map[string]map[string]time.Time
+}
+
+// lookup retorna el valor guardado para la clave de la sesión y si existía
+func (s *recordingState) lookup(sessionID, key string) (value, found bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, found = s.sessions[sessionID][key]
+	return value, found
+}
+
+// store guarda el valor de la clave de la sesión
+func (s *recordingState) store(sessionID, key string, value bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(sessionID, key, value)
+}
+
+// markOnce marca la clave de la sesión; retorna true solo la primera vez, para enviar cada aviso una vez
+func (s *recordingState) markOnce(sessionID, key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessions[sessionID][key] {
+		return false
+	}
+	s.set(sessionID, key, true)
+	return true
+}
+
+// set guarda el valor creando los mapas que falten; requiere mu
+func (s *recordingState) set(sessionID, key string, value bool) {
+	if s.sessions == nil {
+		s.sessions = make(map[string]map[string]bool)
+	}
+	if s.sessions[sessionID] == nil {
+		s.sessions[sessionID] = make(map[string]bool)
+	}
+	s.sessions[sessionID][key] = value
+}
+
+// permitted indica si el permiso de la grabación de la sesión se validó y sigue vigente en now
+func (s *recordingState) permitted(sessionID, videoID string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, found := s.permissions[sessionID][videoID]
+	return found && now.Before(until)
+}
+
+// permit recuerda que la grabación de la sesión puede escribirse sin volver a validarla hasta until
+func (s *recordingState) permit(sessionID, videoID string, until time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.permissions == nil {
+		s.permissions = make(map[string]map[string]time.Time)
+	}
+	if s.permissions[sessionID] == nil {
+		s.permissions[sessionID] = make(map[string]time.Time)
+	}
+	s.permissions[sessionID][videoID] = until
+}
+
+// forgetSession olvida todo lo guardado de la sesión
+func (s *recordingState) forgetSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, sessionID)
+	delete(s.permissions, sessionID)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+)
+
+func TestRecordingState_MarkOnceIsPerSession(t *testing.T) {
+	// Arrange
+	var state recordingState
+
+	// Act
+	first := state.markOnce("session-a", recordingLimitKey+"video-1")
+	repeated := state.markOnce("session-a", recordingLimitKey+"video-1")
+	otherSession := state.markOnce("session-b", recordingLimitKey+"video-1")
+
+	// Assert
+	assert.True(t, first)
+	assert.False(t, repeated)
+	assert.True(t, otherSession)
+}
+
+func TestRecordingState_PermissionExpires(t *testing.T) {
+	// Arrange
+	var state recordingState
+	now := time.Now()
+
+	// Act
+	state.permit("session-a", "video-1", now.Add(time.Minute))
+
+	// Assert - vale para esa grabación hasta que vence
+	assert.True(t, state.permitted("session-a", "video-1", now))
+	assert.False(t, state.permitted("session-a", "video-2", now))
+	assert.False(t, state.permitted("session-a", "video-1", now.Add(time.Minute)))
+}
+
+func TestSendSessionEndedToClient_ForgetsSessionRecordingState(t *testing.T) {
+	// Arrange - la conexión recuerda la cuota y el aviso de límite de la sesión que termina y de otra
+	h, _ := newTestWebSocketHandler()
+	clientSide, clientConn := connectTestClient(t, h)
+	clientConn.recordingState.store(testEndedSessionID, recordingQuotaKey+"video-1", false)
+	clientConn.recordingState.markOnce(testEndedSessionID, recordingLimitKey+"video-1")
+	clientConn.recordingState.markOnce("other-session", recordingLimitKey+"video-2")
+	clientConn.recordingState.permit(testEndedSessionID, "video-1", time.Now().Add(time.Minute))
+
+	// Act
+	require.NoError(t, h.SendSessionEndedToClient(testEndedSessionID, testTargetPCID))
+
+	// Assert
+	readSessionEnded(t, clientSide)
+	_, quotaChecked := clientConn.recordingState.lookup(testEndedSessionID, recordingQuotaKey+"video-1")
+	assert.False(t, quotaChecked)
+	assert.False(t, clientConn.recordingState.permitted(testEndedSessionID, "video-1", time.Now()))
+	_, otherKept := clientConn.recordingState.lookup("other-session", recordingLimitKey+"video-2")
+	assert.True(t, otherKept)
+}
+
+func TestHandleVideoFrameUpload_ValidatesRecordingPermissionUntilSessionEnds(t *testing.T) {
+	// Arrange
+	session, err := remotesession.NewRemoteSession("admin-id", testTargetPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+	h := NewWebSocketHandler(nil, nil, remotesessionservice.NewRemoteSessionService(sessionRepo, nil, nil, nil, nil), nil, nil, nil)
+	h.SetValidateJPEGFrames(false)
+	videoService := &spyVideoService{}
+	h.videoService = videoService
+	clientSide, clientConn := connectTestClient(t, h)
+
+	sendFrame := func(frameIndex int) {
+		data := testVideoFrameData(frameIndex)
+		data["session_id"] = session.SessionID()
+		h.handleVideoFrameUpload(clientConn.Conn, clientConn, data)
+	}
+
+	// Act
+	for frameIndex := 1; frameIndex <= 3; frameIndex++ {
+		sendFrame(frameIndex)
+	}
+	sessionRepo.AssertNumberOfCalls(t, "FindById", 1)
+
+	require.NoError(t, h.SendSessionEndedToClient(session.SessionID(), testTargetPCID))
+	readSessionEnded(t, clientSide)
+	sendFrame(4)
+
+	// Assert - la sesión se consulta una vez mientras dura y de nuevo tras terminar
+	assert.Len(t, videoService.savedFrames, 4)
+	sessionRepo.AssertNumberOfCalls(t, "FindById", 2)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// testEnvelope refleja dto.APIResponse con data sin tipar para inspeccionar el JSON recibido
+type testEnvelope struct {
+	Success bool                   `json:"success"`
+	Data    map[string]interface{} `json:"data"`
+	Meta    *dto.PageMeta          `json:"meta"`
+	Error   *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// newTestRouter crea un router gin que inyecta los claims del usuario como lo hace AuthMiddleware
+func newTestRouter(role user.Role) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if role != "" {
+		router.Use(func(c *gin.Context) {
+			c.Set("user", &userservice.JWTClaims{UserID: "admin-id", Username: "admin", Role: string(role)})
+			c.Next()
+		})
+	}
+	return router
+}
+
+// assertSuccessEnvelope verifica el envelope de éxito y retorna sus datos
+func assertSuccessEnvelope(t *testing.T, recorder *httptest.ResponseRecorder, expectedStatus int) map[string]interface{} {
+	t.Helper()
+	assert.Equal(t, expectedStatus, recorder.Code)
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	var envelope testEnvelope
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+	assert.True(t, envelope.Success)
+	assert.Nil(t, envelope.Error)
+	require.NotNil(t, envelope.Data)
+	return envelope.Data
+}
+
+// assertPageMeta verifica los metadatos de paginación del envelope
+func assertPageMeta(t *testing.T, recorder *httptest.ResponseRecorder, expected dto.PageMeta) {
+	t.Helper()
+
+	var envelope testEnvelope
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+	require.NotNil(t, envelope.Meta)
+	assert.Equal(t, expected, *envelope.Meta)
+}
+
+// assertErrorEnvelope verifica el envelope de error con el código esperado
+func assertErrorEnvelope(t *testing.T, recorder *httptest.ResponseRecorder, expectedStatus int, expectedCode string) {
+	t.Helper()
+	assert.Equal(t, expectedStatus, recorder.Code)
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	var envelope testEnvelope
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+	assert.False(t, envelope.Success)
+	assert.Nil(t, envelope.Data)
+	require.NotNil(t, envelope.Error)
+	assert.Equal(t, expectedCode, envelope.Error.Code)
+	assert.NotEmpty(t, envelope.Error.Message)
+}
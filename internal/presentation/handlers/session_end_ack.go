@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// DefaultSessionEndAckTimeout tiempo que se espera el session_end_ack del cliente tras enviarle control_session_ended
+const DefaultSessionEndAckTimeout = 10 * time.Second
+
+// CloseCodeSessionEndAckTimeout código de cierre (1008 Policy Violation) con el que se desconecta a un cliente que no
+// confirmó el fin de sesión: cerrar el socket garantiza que deja de transmitir y grabar
+const CloseCodeSessionEndAckTimeout = websocket.ClosePolicyViolation
+
+// SetSessionEndAckTimeout configura la espera del session_end_ack; <= 0 no espera la confirmación
+func (h *WebSocketHandler) SetSessionEndAckTimeout(timeout time.Duration) {
+	h.sessionEndAckTimeout = timeout
+}
+
+// sessionEndAckKey identifica la confirmación pendiente de una sesión en un PC concreto
+func sessionEndAckKey(clientPCID, sessionID string) string {
+	return clientPCID + "/" + sessionID
+}
+
+// awaitSessionEndAck espera la confirmación de fin de sesión; si no llega a tiempo desconecta al cliente
+func (h *WebSocketHandler) awaitSessionEndAck(sessionID string, clientConn *ClientConnection) {
+	timeout := h.sessionEndAckTimeout
+	if timeout <= 0 {
+		return
+	}
+	// Solo se espera a los clientes que negociaron la capacidad: a los demás (entre ellos los del protocolo 1)
+	// esperarla los desconectaría en cada fin de sesión
+	if !clientConn.sessionEndAck {
+		return
+	}
+
+	h.sessionEndAcks.start(sessionEndAckKey(clientConn.PCID, sessionID), timeout, func() {
+		h.handleSessionEndAckTimeout(sessionID, clientConn, timeout)
+	})
+}
+
+// handleSessionEndAck confirma que el cliente detuvo el streaming y la grabación de la sesión
+func (h *WebSocketHandler) handleSessionEndAck(conn messageWriter, clientConn *ClientConnection, data interface{}) {
+	var ack dto.SessionEndAck
+	if !decodePayload(conn, dto.MessageTypeSessionEndAck, data, &ack) {
+		return
+	}
+
+	if !h.sessionEndAcks.stop(sessionEndAckKey(clientConn.PCID, ack.SessionID)) {
+		log.Printf("⚠️ SESSION END ACK: Unexpected ack from PC %s for session %s", clientConn.PCID, ack.SessionID)
+		return
+	}
+	log.Printf("✅ SESSION END ACK: PC %s confirmed it stopped session %s", clientConn.PCID, ack.SessionID)
+}
+
+// handleSessionEndAckTimeout cierra la conexión del cliente que no confirmó el fin de sesión, salvo que ya se
+// haya reconectado con otra conexión
+func (h *WebSocketHandler) handleSessionEndAckTimeout(sessionID string, clientConn *ClientConnection, waited time.Duration) {
+	h.mutex.RLock()
+	current := h.pcConnections[clientConn.PCID] == clientConn
+	h.mutex.RUnlock()
+	if !current {
+		return
+	}
+
+	log.Printf("⏱️ SESSION END ACK: PC %s did not confirm end of session %s after %v, disconnecting", clientConn.PCID, sessionID, waited)
+
+	closeMessage := websocket.FormatCloseMessage(CloseCodeSessionEndAckTimeout, "session_end_ack timeout")
+	clientConn.closeWithMessage(closeMessage)
+}
+
+// closeWithMessage cierra la conexión desde fuera de su goroutine de lectura. Con buffer de salida el close frame
+// y el cierre los hace su goroutine de escritura, en orden con el resto de mensajes. Sin buffer se envía con
+// WriteControl, el único método de escritura de gorilla/websocket que admite llamadas concurrentes, y el cierre del
+// socket hace que el bucle de lectura salga y libere la conexión.
+func (c *ClientConnection) closeWithMessage(closeMessage []byte) {
+	if c.outbound != nil {
+		if err := c.outbound.CloseWithMessage(closeMessage); err == nil {
+			return
+		}
+	}
+	c.Conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second))
+	c.Conn.Close()
+}
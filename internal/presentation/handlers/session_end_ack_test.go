@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+const testEndedSessionID = "session-end-ack-test"
+
+// readSessionEnded lee el control_session_ended que recibe el cliente
+func readSessionEnded(t *testing.T, clientSide *websocket.Conn) {
+	t.Helper()
+
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, clientSide.ReadJSON(&message))
+	require.Equal(t, "control_session_ended", message.Type)
+}
+
+func TestSendSessionEndedToClient_AckKeepsClientConnected(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	h.SetSessionEndAckTimeout(30 * time.Millisecond)
+	clientSide, clientConn := connectTestClient(t, h)
+	clientConn.sessionEndAck = true
+	require.NoError(t, h.SendSessionEndedToClient(testEndedSessionID, testTargetPCID))
+	readSessionEnded(t, clientSide)
+
+	// Act
+	h.handleSessionEndAck(clientConn.Conn, clientConn, map[string]interface{}{"session_id": testEndedSessionID})
+
+	// Assert - pasado el timeout la conexión sigue abierta
+	time.Sleep(60 * time.Millisecond)
+	h.sessionEndAcks.mutex.Lock()
+	assert.Empty(t, h.sessionEndAcks.timers)
+	h.sessionEndAcks.mutex.Unlock()
+	require.NoError(t, clientConn.Conn.WriteJSON(dto.WebSocketMessage{Type: "ping"}))
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, "ping", message.Type)
+}
+
+func TestSendSessionEndedToClient_MissingAckDisconnectsClient(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	h.SetSessionEndAckTimeout(30 * time.Millisecond)
+	clientSide, clientConn := connectTestClient(t, h)
+	clientConn.sessionEndAck = true
+
+	// Act
+	require.NoError(t, h.SendSessionEndedToClient(testEndedSessionID, testTargetPCID))
+
+	// Assert
+	readSessionEnded(t, clientSide)
+	_, _, err := clientSide.ReadMessage()
+	closeErr, ok := err.(*websocket.CloseError)
+	require.True(t, ok, "expected close error, got %v", err)
+	assert.Equal(t, CloseCodeSessionEndAckTimeout, closeErr.Code)
+}
+
+func TestHandleSessionEndAck_IgnoresAckFromAnotherPC(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	h.SetSessionEndAckTimeout(time.Minute)
+	clientSide, clientConn := connectTestClient(t, h)
+	clientConn.sessionEndAck = true
+	require.NoError(t, h.SendSessionEndedToClient(testEndedSessionID, testTargetPCID))
+	readSessionEnded(t, clientSide)
+	otherPC := &ClientConnection{PCID: "other-pc-id", IsAuth: true}
+
+	// Act
+	h.handleSessionEndAck(nil, otherPC, map[string]interface{}{"session_id": testEndedSessionID})
+
+	// Assert
+	assert.True(t, h.sessionEndAcks.stop(sessionEndAckKey(testTargetPCID, testEndedSessionID)))
+}
+
+func TestSendSessionEndedToClient_WithoutCapabilityDoesNotAwaitAck(t *testing.T) {
+	// Arrange - cliente del protocolo actual que no negoció session_end_ack
+	h, _ := newTestWebSocketHandler()
+	h.SetSessionEndAckTimeout(30 * time.Millisecond)
+	clientSide, _ := connectTestClient(t, h)
+
+	// Act
+	require.NoError(t, h.SendSessionEndedToClient(testEndedSessionID, testTargetPCID))
+
+	// Assert
+	readSessionEnded(t, clientSide)
+	h.sessionEndAcks.mutex.Lock()
+	assert.Empty(t, h.sessionEndAcks.timers)
+	h.sessionEndAcks.mutex.Unlock()
+}
+
+func TestSendSessionEndedToClient_MissingAckClosesThroughOutboundBuffer(t *testing.T) {
+	// Arrange - el close frame sale por la goroutine de escritura, después de control_session_ended
+	h, _ := newTestWebSocketHandler()
+	h.SetSessionEndAckTimeout(30 * time.Millisecond)
+	clientSide, clientConn := connectTestClient(t, h)
+	clientConn.sessionEndAck = true
+	clientConn.outbound = NewOutboundBuffer(clientConn.Conn, DefaultOutboundBufferConfig())
+
+	// Act
+	require.NoError(t, h.SendSessionEndedToClient(testEndedSessionID, testTargetPCID))
+
+	// Assert
+	readSessionEnded(t, clientSide)
+	_, _, err := clientSide.ReadMessage()
+	closeErr, ok := err.(*websocket.CloseError)
+	require.True(t, ok, "expected close error, got %v", err)
+	assert.Equal(t, CloseCodeSessionEndAckTimeout, closeErr.Code)
+	assert.ErrorIs(t, clientConn.outbound.WriteJSON(dto.WebSocketMessage{Type: "ping"}), ErrOutboundBufferClosed)
+}
+
+func TestHandleClientAuth_NegotiatesSessionEndAckCapability(t *testing.T) {
+	// Arrange
+	h, _, clientSide, clientConn := newTestAuthHandler(t)
+
+	// Act
+	h.handleClientAuth(clientConn.Conn, clientConn, map[string]interface{}{
+		"username":        "client",
+		"password":        "password",
+		"protocolVersion": "2.0",
+		"capabilities":    []string{dto.CapabilitySessionEndAck},
+	})
+
+	// Assert
+	messageType, data := readClientMessage(t, clientSide)
+	require.Equal(t, dto.MessageTypeClientAuthResp, messageType)
+	assert.Equal(t, []interface{}{dto.CapabilitySessionEndAck}, data["capabilities"])
+	assert.True(t, clientConn.sessionEndAck)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// ErrAdminNotConnected el administrador que controla la sesión no tiene ningún panel conectado
+var ErrAdminNotConnected = errors.New("admin not connected")
+
+// Motivos enviados al cliente en pause_stream / resume_stream
+const (
+	StreamPauseReasonAdminDisconnected   = "ADMIN_DISCONNECTED"
+	StreamResumeReasonAdminReconnected   = "ADMIN_RECONNECTED"
+	StreamResumeReasonSessionTransferred = "SESSION_TRANSFERRED"
+)
+
+// pausedStream sesión cuyo cliente dejó de transmitir a la espera de su administrador
+type pausedStream struct {
+	clientPCID  string
+	adminUserID string
+}
+
+// pausedStreams sesiones pausadas indexadas por sessionID
+type pausedStreams struct {
+	sessions map[string]pausedStream
+	mutex    sync.Mutex
+}
+
+func newPausedStreams() *pausedStreams {
+	return &pausedStreams{sessions: make(map[string]pausedStream)}
+}
+
+// add marca la sesión como pausada; false si ya lo estaba
+func (p *pausedStreams) add(sessionID string, stream pausedStream) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if _, exists := p.sessions[sessionID]; exists {
+		return false
+	}
+	p.sessions[sessionID] = stream
+	return true
+}
+
+// remove olvida la pausa de la sesión; true si estaba pausada
+func (p *pausedStreams) remove(sessionID string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	_, exists := p.sessions[sessionID]
+	delete(p.sessions, sessionID)
+	return exists
+}
+
+// removeByAdmin olvida y retorna las sesiones pausadas a la espera del administrador
+func (p *pausedStreams) removeByAdmin(adminUserID string) map[string]pausedStream {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	resumed := make(map[string]pausedStream)
+	for sessionID, stream := range p.sessions {
+		if stream.adminUserID == adminUserID {
+			resumed[sessionID] = stream
+			delete(p.sessions, sessionID)
+		}
+	}
+	return resumed
+}
+
+// pauseStreamForAbsentAdmin pide al cliente que deje de transmitir una sesión cuyo administrador no está conectado.
+// Solo el primer frame perdido envía pause_stream; los que lleguen antes de que el cliente lo procese se descartan
+// sin registrar nada.
+func (h *WebSocketHandler) pauseStreamForAbsentAdmin(clientConn *ClientConnection, sessionID, adminUserID string) {
+	if !h.pausedStreams.add(sessionID, pausedStream{clientPCID: clientConn.PCID, adminUserID: adminUserID}) {
+		return
+	}
+
+	log.Printf("⏸️ SCREEN FRAME: Admin %s not connected, pausing stream of session %s on PC %s", adminUserID, sessionID, clientConn.PCID)
+	h.sendStreamControl(clientConn, dto.MessageTypePauseStream, sessionID, StreamPauseReasonAdminDisconnected)
+}
+
+// ResumeStreamsForAdmin pide a los clientes que retomen el streaming pausado mientras el administrador estaba desconectado
+func (h *WebSocketHandler) ResumeStreamsForAdmin(adminUserID string) {
+	for sessionID, stream := range h.pausedStreams.removeByAdmin(adminUserID) {
+		h.mutex.RLock()
+		clientConn, exists := h.pcConnections[stream.clientPCID]
+		h.mutex.RUnlock()
+		if !exists {
+			continue
+		}
+
+		log.Printf("▶️ SCREEN FRAME: Admin %s reconnected, resuming stream of session %s on PC %s", adminUserID, sessionID, stream.clientPCID)
+		h.sendStreamControl(clientConn, dto.MessageTypeResumeStream, sessionID, StreamResumeReasonAdminReconnected)
+	}
+}
+
+// sendStreamControl envía pause_stream o resume_stream al cliente
+func (h *WebSocketHandler) sendStreamControl(clientConn *ClientConnection, messageType, sessionID, reason string) {
+	message := dto.WebSocketMessage{
+		Type: messageType,
+		Data: dto.StreamControl{SessionID: sessionID, Reason: reason},
+	}
+	if err := clientConn.writer().WriteJSON(message); err != nil {
+		log.Printf("❌ SCREEN FRAME: Error sending %s to PC %s: %v", messageType, clientConn.PCID, err)
+	}
+}
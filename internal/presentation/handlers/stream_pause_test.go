@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// readClientStreamControl lee el siguiente pause_stream / resume_stream enviado al cliente
+func readClientStreamControl(t *testing.T, clientSide *websocket.Conn) dto.WebSocketMessage {
+	t.Helper()
+
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, clientSide.ReadJSON(&message))
+	return message
+}
+
+// assertNoClientMessage comprueba que el cliente no recibe nada más
+func assertNoClientMessage(t *testing.T, clientSide *websocket.Conn) {
+	t.Helper()
+
+	require.NoError(t, clientSide.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	_, _, err := clientSide.ReadMessage()
+	var netErr net.Error
+	require.ErrorAs(t, err, &netErr)
+	assert.True(t, netErr.Timeout(), "unexpected message sent to client")
+}
+
+func TestProcessScreenFrame_AbsentAdminPausesStreamOnce(t *testing.T) {
+	// Arrange - el administrador de la sesión no tiene ningún panel conectado
+	h, _, session := newTestRecordingHandler(t, testTargetPCID)
+	h.adminWSHandler = NewAdminWebSocketHandler(nil, nil)
+	clientSide, clientConn := connectTestClient(t, h)
+
+	// Act - el cliente sigue transmitiendo a 30 fps
+	for i := 1; i <= 5; i++ {
+		h.processScreenFrame(clientConn, dto.ScreenFrame{SessionID: session.SessionID(), SequenceNum: int64(i)})
+	}
+
+	// Assert
+	message := readClientStreamControl(t, clientSide)
+	assert.Equal(t, dto.MessageTypePauseStream, message.Type)
+	data, ok := message.Data.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, session.SessionID(), data["session_id"])
+	assert.Equal(t, StreamPauseReasonAdminDisconnected, data["reason"])
+	assertNoClientMessage(t, clientSide)
+}
+
+func TestResumeStreamsForAdmin_ResumesPausedStreamWhenAdminReconnects(t *testing.T) {
+	// Arrange
+	h, _, session := newTestRecordingHandler(t, testTargetPCID)
+	h.adminWSHandler = NewAdminWebSocketHandler(nil, nil)
+	clientSide, clientConn := connectTestClient(t, h)
+	h.processScreenFrame(clientConn, dto.ScreenFrame{SessionID: session.SessionID(), SequenceNum: 1})
+	require.Equal(t, dto.MessageTypePauseStream, readClientStreamControl(t, clientSide).Type)
+	adminSide := connectTestAdmin(t, h.adminWSHandler)
+
+	// Act
+	h.ResumeStreamsForAdmin(testAdminUserID)
+
+	// Assert - el cliente retoma y sus frames vuelven a llegar al administrador
+	message := readClientStreamControl(t, clientSide)
+	assert.Equal(t, dto.MessageTypeResumeStream, message.Type)
+	data, ok := message.Data.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, StreamResumeReasonAdminReconnected, data["reason"])
+
+	h.processScreenFrame(clientConn, dto.ScreenFrame{SessionID: session.SessionID(), SequenceNum: 2})
+	assert.Equal(t, dto.MessageTypeScreenFrame, readAdminMessage(t, adminSide).Type)
+
+	// Una segunda reconexión no vuelve a enviar resume_stream
+	h.ResumeStreamsForAdmin(testAdminUserID)
+	assertNoClientMessage(t, clientSide)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultFirstFrameTimeout tiempo que se espera el primer frame tras activar una sesión antes de avisar al administrador
+const DefaultFirstFrameTimeout = 15 * time.Second
+
+// StreamWatchdogConfig vigilancia del primer frame de cada sesión: un cliente que acepta pero nunca transmite
+// deja al administrador mirando una pantalla negra. FirstFrameTimeout <= 0 desactiva la vigilancia.
+type StreamWatchdogConfig struct {
+	FirstFrameTimeout   time.Duration
+	EndSessionOnTimeout bool // además de avisar, finaliza la sesión como FAILED
+}
+
+// DefaultStreamWatchdogConfig avisa tras DefaultFirstFrameTimeout sin finalizar la sesión
+func DefaultStreamWatchdogConfig() StreamWatchdogConfig {
+	return StreamWatchdogConfig{FirstFrameTimeout: DefaultFirstFrameTimeout}
+}
+
+// streamWatchdog temporizadores del primer frame pendientes, indexados por sessionID
+type streamWatchdog struct {
+	timers map[string]*time.Timer
+	mutex  sync.Mutex
+}
+
+func newStreamWatchdog() *streamWatchdog {
+	return &streamWatchdog{timers: make(map[string]*time.Timer)}
+}
+
+// start programa onTimeout si no se llama a stop antes de timeout; reemplaza un temporizador previo de la sesión
+func (w *streamWatchdog) start(sessionID string, timeout time.Duration, onTimeout func()) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if previous, exists := w.timers[sessionID]; exists {
+		previous.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(timeout, func() {
+		w.mutex.Lock()
+		current := w.timers[sessionID] == timer
+		if current {
+			delete(w.timers, sessionID)
+		}
+		w.mutex.Unlock()
+
+		if current {
+			onTimeout()
+		}
+	})
+	w.timers[sessionID] = timer
+}
+
+// stop cancela la vigilancia de la sesión (llegó el primer frame o la sesión terminó); retorna false si no
+// había un temporizador pendiente
+func (w *streamWatchdog) stop(sessionID string) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	timer, exists := w.timers[sessionID]
+	if exists {
+		timer.Stop()
+		delete(w.timers, sessionID)
+	}
+	return exists
+}
+
+// SetStreamWatchdogConfig configura la espera del primer frame tras activar una sesión
+func (h *WebSocketHandler) SetStreamWatchdogConfig(config StreamWatchdogConfig) {
+	h.streamWatchdogConfig = config
+}
+
+// watchFirstFrame empieza a esperar el primer frame de una sesión recién activada
+func (h *WebSocketHandler) watchFirstFrame(sessionID, clientPCID string) {
+	config := h.streamWatchdogConfig
+	if config.FirstFrameTimeout <= 0 {
+		return
+	}
+
+	h.streamWatchdog.start(sessionID, config.FirstFrameTimeout, func() {
+		h.handleStreamNotStarting(sessionID, clientPCID, config)
+	})
+}
+
+// handleStreamNotStarting avisa al administrador de que el cliente no transmite y, si está configurado, finaliza la sesión
+func (h *WebSocketHandler) handleStreamNotStarting(sessionID, clientPCID string, config StreamWatchdogConfig) {
+	if h.sessionService == nil {
+		return
+	}
+
+	ctx := context.Background()
+	adminUserID, err := h.sessionService.GetAdminUserIDForActiveSession(ctx, sessionID)
+	if err != nil {
+		// La sesión ya terminó por otra vía: no hay nada que vigilar
+		return
+	}
+
+	log.Printf("⏱️ STREAM WATCHDOG: No frames from PC %s for session %s after %v", clientPCID, sessionID, config.FirstFrameTimeout)
+
+	sessionEnded := false
+	if config.EndSessionOnTimeout {
+		if err := h.sessionService.FailSessionWithoutStream(ctx, sessionID); err != nil {
+			log.Printf("⚠️ STREAM WATCHDOG: Could not end session %s: %v", sessionID, err)
+		} else {
+			sessionEnded = true
+		}
+	}
+
+	if h.adminWSHandler != nil {
+		h.adminWSHandler.NotifyStreamNotStarting(adminUserID, sessionID, clientPCID, config.FirstFrameTimeout, sessionEnded)
+	}
+}
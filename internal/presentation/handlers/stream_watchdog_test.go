@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+func TestWatchFirstFrame_NoFramesWarnsAdmin(t *testing.T) {
+	// Arrange - la sesión está ACTIVE pero el cliente nunca envía frames
+	h, _, session := newTestRecordingHandler(t, testTargetPCID)
+	h.adminWSHandler = NewAdminWebSocketHandler(nil, nil)
+	adminSide := connectTestAdmin(t, h.adminWSHandler)
+	h.SetStreamWatchdogConfig(StreamWatchdogConfig{FirstFrameTimeout: 20 * time.Millisecond})
+
+	// Act
+	h.watchFirstFrame(session.SessionID(), testTargetPCID)
+
+	// Assert
+	message := readAdminMessage(t, adminSide)
+	assert.Equal(t, "stream_not_starting", message.Type)
+	data, ok := message.Data.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, session.SessionID(), data["session_id"])
+	assert.Equal(t, testTargetPCID, data["client_pc_id"])
+	assert.Equal(t, false, data["session_ended"])
+	assert.Equal(t, remotesession.StatusActive, session.Status())
+}
+
+func TestWatchFirstFrame_EndSessionOnTimeoutFailsSession(t *testing.T) {
+	// Arrange
+	h, _, session := newTestRecordingHandler(t, testTargetPCID)
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+	sessionRepo.On("UpdateStatus", mock.Anything, session.SessionID(), remotesession.StatusFailed).Return(nil)
+	h.sessionService = remotesessionservice.NewRemoteSessionService(sessionRepo, nil, nil, nil, nil)
+	h.adminWSHandler = NewAdminWebSocketHandler(nil, nil)
+	adminSide := connectTestAdmin(t, h.adminWSHandler)
+	h.SetStreamWatchdogConfig(StreamWatchdogConfig{FirstFrameTimeout: 20 * time.Millisecond, EndSessionOnTimeout: true})
+
+	// Act
+	h.watchFirstFrame(session.SessionID(), testTargetPCID)
+
+	// Assert
+	message := readAdminMessage(t, adminSide)
+	assert.Equal(t, "stream_not_starting", message.Type)
+	data, ok := message.Data.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, true, data["session_ended"])
+	assert.Equal(t, remotesession.StatusFailed, session.Status())
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestWatchFirstFrame_FirstFrameCancelsWarning(t *testing.T) {
+	// Arrange
+	h, _, session := newTestRecordingHandler(t, testTargetPCID)
+	h.SetStreamWatchdogConfig(StreamWatchdogConfig{FirstFrameTimeout: 20 * time.Millisecond})
+	h.watchFirstFrame(session.SessionID(), testTargetPCID)
+
+	// Act
+	h.processScreenFrame(&ClientConnection{PCID: testTargetPCID, IsAuth: true}, dto.ScreenFrame{SessionID: session.SessionID(), SequenceNum: 1})
+
+	// Assert
+	h.streamWatchdog.mutex.Lock()
+	defer h.streamWatchdog.mutex.Unlock()
+	assert.Empty(t, h.streamWatchdog.timers)
+}
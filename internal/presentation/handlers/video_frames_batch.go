@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// MaxVideoFramesPerBatch máximo de frames en un video_frames_batch (4 s de grabación a 30 FPS)
+const MaxVideoFramesPerBatch = 120
+
+var (
+	// ErrEmptyFramesBatch el lote no contiene frames
+	ErrEmptyFramesBatch = errors.New("frames batch is empty")
+	// ErrFramesBatchTooLarge el lote supera MaxVideoFramesPerBatch
+	ErrFramesBatchTooLarge = errors.New("frames batch is too large")
+	// ErrNonContiguousFramesBatch los frame_index del lote no son consecutivos
+	ErrNonContiguousFramesBatch = errors.New("frame indices in batch are not contiguous")
+)
+
+// handleVideoFramesBatch guarda varios frames consecutivos recibidos en un solo mensaje. Las validaciones de
+// permiso, feature flag y cuota se hacen una vez por lote; los frames se guardan en orden y se deja de
+// guardar si la grabación alcanza su límite.
+func (h *WebSocketHandler) handleVideoFramesBatch(conn messageWriter, clientConn *ClientConnection, data interface{}) {
+	var batch dto.VideoFramesBatch
+	if !decodePayload(conn, "video_frames_batch", data, &batch) {
+		return
+	}
+
+	if err := validateFramesBatch(batch); err != nil {
+		log.Printf("❌ VIDEO FRAMES BATCH: Invalid batch for video %s from PC %s: %v", batch.VideoID, clientConn.PCID, err)
+		sendMalformedPayload(conn, "video_frames_batch", err)
+		return
+	}
+
+	// Decodificar todo el lote antes de guardar para no dejar huecos por un frame corrupto
+	framesBytes := make([][]byte, len(batch.Frames))
+	for i, frame := range batch.Frames {
+		frameBytes, err := base64.StdEncoding.DecodeString(frame.FrameData)
+		if err != nil {
+			log.Printf("❌ VIDEO FRAMES BATCH: Error decoding frame %d: %v", frame.FrameIndex, err)
+			sendMalformedPayload(conn, "video_frames_batch", fmt.Errorf("frame %d: %w", frame.FrameIndex, err))
+			return
+		}
+		if !h.acceptFrameData(clientConn, batch.SessionID, frameBytes) {
+			sendMalformedPayload(conn, "video_frames_batch", fmt.Errorf("frame %d: %w", frame.FrameIndex, videoservice.ErrInvalidJPEGFrame))
+			return
+		}
+		framesBytes[i] = frameBytes
+	}
+
+	log.Printf("📸 VIDEO FRAMES BATCH: Received frames %d-%d from PC %s (session: %s, video: %s)",
+		batch.Frames[0].FrameIndex, batch.Frames[len(batch.Frames)-1].FrameIndex, clientConn.PCID, batch.SessionID, batch.VideoID)
+
+	h.runVideoFrameJob(clientConn, func() {
+		if !h.canAcceptVideoFrames(conn, clientConn, batch.SessionID, batch.VideoID) {
+			return
+		}
+
+		for i, frame := range batch.Frames {
+			videoFrame := dto.VideoFrameUpload{
+				SessionID:  batch.SessionID,
+				VideoID:    batch.VideoID,
+				FrameIndex: frame.FrameIndex,
+				Timestamp:  frame.Timestamp,
+			}
+			if !h.storeVideoFrame(conn, clientConn, videoFrame, framesBytes[i]) {
+				return
+			}
+		}
+	})
+}
+
+// validateFramesBatch comprueba el tamaño del lote y que sus frame_index sean consecutivos y crecientes
+func validateFramesBatch(batch dto.VideoFramesBatch) error {
+	if len(batch.Frames) == 0 {
+		return ErrEmptyFramesBatch
+	}
+	if len(batch.Frames) > MaxVideoFramesPerBatch {
+		return fmt.Errorf("%w: %d frames (max %d)", ErrFramesBatchTooLarge, len(batch.Frames), MaxVideoFramesPerBatch)
+	}
+
+	first := batch.Frames[0].FrameIndex
+	if first < 0 {
+		return fmt.Errorf("%w: negative frame index %d", ErrNonContiguousFramesBatch, first)
+	}
+	for i, frame := range batch.Frames {
+		if frame.FrameIndex != first+i {
+			return fmt.Errorf("%w: expected %d, got %d", ErrNonContiguousFramesBatch, first+i, frame.FrameIndex)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// testVideoFramesBatchData simula un video_frames_batch del cliente con los frame_index indicados
+func testVideoFramesBatchData(frameIndices ...int) map[string]interface{} {
+	frames := make([]interface{}, 0, len(frameIndices))
+	for _, frameIndex := range frameIndices {
+		frames = append(frames, map[string]interface{}{
+			"frame_index": frameIndex,
+			"timestamp":   time.Now().UnixMilli(),
+			"frame_data":  base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("jpeg-%d", frameIndex))),
+		})
+	}
+	return map[string]interface{}{
+		"session_id": "session-1",
+		"video_id":   "video-1",
+		"frames":     frames,
+	}
+}
+
+func TestHandleVideoFramesBatch_WritesEveryFrameToDisk(t *testing.T) {
+	// Arrange - el servicio real guarda los frames bajo storage/session_videos del directorio actual
+	t.Chdir(t.TempDir())
+	h, _ := newTestWebSocketHandler()
+	h.videoService = videoservice.NewVideoService(nil, nil, nil, nil, videoservice.FrameStorageIndividual, 0, videoservice.DefaultPartialRecordingPolicy)
+	_, clientConn := connectTestClient(t, h)
+
+	// Act
+	h.handleVideoFramesBatch(clientConn.Conn, clientConn, testVideoFramesBatchData(5, 6, 7))
+
+	// Assert
+	framesDir := filepath.Join("storage", "session_videos", "video-1", "frames")
+	for _, frameIndex := range []int{5, 6, 7} {
+		content, err := os.ReadFile(filepath.Join(framesDir, fmt.Sprintf("frame_%06d.jpg", frameIndex)))
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("jpeg-%d", frameIndex), string(content))
+	}
+}
+
+func TestHandleVideoFramesBatch_NonContiguousIndicesAreRejected(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	videoService := &spyVideoService{}
+	h.videoService = videoService
+	clientSide, clientConn := connectTestClient(t, h)
+
+	// Act
+	h.handleVideoFramesBatch(clientConn.Conn, clientConn, testVideoFramesBatchData(1, 2, 4))
+
+	// Assert - no se guarda ningún frame del lote
+	assert.Empty(t, videoService.savedFrames)
+
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, dto.MessageTypeMalformedPayload, message.Type)
+	assert.Equal(t, "video_frames_batch", message.Data.(map[string]interface{})["message_type"])
+}
@@ -4,22 +4,30 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"net"
 	"net/http"
+	"net/netip"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/backgroundtaskservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/featureflagservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/filetransferservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/pcservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/storagequotaservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/connectionsession"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
 	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
 )
 
@@ -32,6 +40,21 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// DefaultStorageQueryTimeout tiempo máximo de espera de la respuesta a un storage_query
+const DefaultStorageQueryTimeout = 10 * time.Second
+
+// DefaultTransferReadyTimeout tiempo máximo de espera del READY del cliente tras un file_transfer_request
+const DefaultTransferReadyTimeout = 5 * time.Second
+
+// ErrInsufficientClientSpace indica que el cliente reportó menos espacio libre del que requiere la transferencia
+var ErrInsufficientClientSpace = errors.New("insufficient client space")
+
+// ErrChunkEncryptionNotNegotiated indica que el cifrado de chunks es obligatorio y el cliente no lo aceptó
+var ErrChunkEncryptionNotNegotiated = errors.New("chunk encryption not negotiated")
+
+// ErrTransferCancelled indica que un administrador canceló la tarea que enviaba la transferencia
+var ErrTransferCancelled = errors.New("transfer cancelled by administrator")
+
 // ClientConnection represents an active WebSocket connection
 type ClientConnection struct {
 	Conn       *websocket.Conn
@@ -42,6 +65,55 @@ type ClientConnection struct {
 	IsAuth     bool
 	LastSeen   time.Time
 	RemoteAddr string
+	// ConnectionSession registro persistido de esta conexión (nil si no hay historial configurado)
+	ConnectionSession *connectionsession.ConnectionSession
+
+	// recordingState por sesión: si cada grabación fue admitida por la cuota, las rechazadas por el máximo de
+	// grabaciones simultáneas (no se reintentan) y los avisos ya enviados (límite de frames, grabación desactivada,
+	// permiso); se limpia al terminar la sesión
+	recordingState recordingState
+	// recordingSessions sesiones de las que esta conexión guardó frames; se liberan en videoService al desconectarse
+	recordingSessions map[string]bool
+
+	// shutdownRequested el cliente anunció con client_shutdown que se cierra intencionadamente
+	shutdownRequested bool
+	// binaryFrames el cliente negoció CapabilityBinaryFrames: frames y chunks viajan como mensajes binarios
+	binaryFrames bool
+	// audioStream el cliente negoció CapabilityAudioStream: puede enviar audio_chunk si el administrador lo activa
+	audioStream bool
+	// sessionEndAck el cliente negoció CapabilitySessionEndAck: confirma cada control_session_ended
+	sessionEndAck bool
+	// supportedInputEvents event_type de input_command declarados en PC_REGISTRATION; nil = todos
+	supportedInputEvents []string
+	// protocolVersion versión del protocolo negociada en la autenticación; cero = versión actual
+	protocolVersion ProtocolVersion
+	// rejectedFrames frames descartados por no ser JPEG válidos, para detectar clientes abusivos
+	rejectedFrames atomic.Int64
+
+	// frames procesa los frames cuando se reducen de resolución; nil hasta el primer frame o sin reducción
+	frames *framePipeline
+
+	// outbound buffer de salida de la conexión; nil escribe directamente en Conn
+	outbound *OutboundBuffer
+
+	// ctx contexto de la conexión; se cancela al cerrarse el WebSocket y aborta las consultas en curso
+	ctx context.Context
+}
+
+// Context retorna el contexto de la conexión para las consultas a BD que origina
+func (c *ClientConnection) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// writer retorna el destino de los mensajes hacia el cliente: su buffer de salida o, sin buffer, el socket
+func (c *ClientConnection) writer() messageWriter {
+	if c.outbound == nil {
+		return c.Conn
+	}
+	return c.outbound
 }
 
 // WebSocketHandler manages WebSocket connections for client PCs
@@ -52,9 +124,50 @@ type WebSocketHandler struct {
 	videoService        interface{} // VideoService interface
 	fileTransferService *filetransferservice.FileTransferService
 	adminWSHandler      *AdminWebSocketHandler
+	connectionHistory   pcservice.IConnectionHistoryService
+	storageQuota        storagequotaservice.IStorageQuotaService
+	featureFlags        featureflagservice.IFeatureFlagService
+	frameLimit          videoservice.FrameResolutionLimit
+	validateJPEGFrames  bool
+	outboundConfig      OutboundBufferConfig
+	capacity            *ConnectionCapacity
 	connections         map[string]*ClientConnection // map[connectionID]*ClientConnection
 	pcConnections       map[string]*ClientConnection // map[pcID]*ClientConnection
+	restClients         map[string]time.Time         // map[pcID]último heartbeat de los clientes que usan la API REST
+	heartbeat           HeartbeatConfig              // intervalo anunciado al cliente y timeout de inactividad (WebSocket y REST)
 	mutex               sync.RWMutex
+
+	// Espera del primer frame de cada sesión activada
+	streamWatchdogConfig StreamWatchdogConfig
+	streamWatchdog       *streamWatchdog
+
+	// Confirmaciones session_end_ack pendientes, con la misma mecánica de temporizadores por clave
+	sessionEndAckTimeout time.Duration
+	sessionEndAcks       *streamWatchdog
+
+	// Sesiones con el streaming pausado porque su administrador no está conectado
+	pausedStreams *pausedStreams
+
+	// Sesiones con el audio activado por su administrador y numeración de sus chunks
+	audioStreams *audioStreams
+
+	// Handshake previo a cada transferencia, indexado por transferID
+	storageQueries         map[string]chan dto.StorageQueryResponse        // respuestas de espacio libre pendientes
+	transferReady          map[string]chan dto.FileTransferAcknowledgement // READY pendientes
+	transferCiphers        map[string]*filetransferservice.ChunkCipher     // cifradores negociados, consumidos al enviar chunks
+	storageQueryTimeout    time.Duration
+	transferReadyTimeout   time.Duration
+	requireChunkEncryption bool
+	transferHandshakeMutex sync.Mutex
+	// chunkRetry reintentos de escritura de cada chunk antes de dar la transferencia por fallida
+	chunkRetry ChunkRetryConfig
+
+	// Transferencias pendientes en curso por cliente y cupo de cada uno (<= 0 = sin límite)
+	pendingTransfers             *pendingTransferQueue
+	maxPendingTransfersPerClient int
+
+	// Goroutines de transferencias en curso, visibles y cancelables desde la API (nil = sin seguimiento)
+	taskRegistry *backgroundtaskservice.TaskRegistry
 }
 
 // NewWebSocketHandler creates a new WebSocket handler
@@ -67,18 +180,77 @@ func NewWebSocketHandler(
 	adminWSHandler *AdminWebSocketHandler,
 ) *WebSocketHandler {
 	return &WebSocketHandler{
-		authService:         authService,
-		pcService:           pcService,
-		sessionService:      sessionService,
-		videoService:        videoService,
-		fileTransferService: fileTransferService,
-		adminWSHandler:      adminWSHandler,
-		connections:         make(map[string]*ClientConnection),
-		pcConnections:       make(map[string]*ClientConnection),
-		mutex:               sync.RWMutex{},
+		authService:          authService,
+		pcService:            pcService,
+		sessionService:       sessionService,
+		videoService:         videoService,
+		fileTransferService:  fileTransferService,
+		adminWSHandler:       adminWSHandler,
+		connections:          make(map[string]*ClientConnection),
+		pcConnections:        make(map[string]*ClientConnection),
+		restClients:          make(map[string]time.Time),
+		heartbeat:            DefaultHeartbeatConfig(),
+		mutex:                sync.RWMutex{},
+		storageQueries:       make(map[string]chan dto.StorageQueryResponse),
+		transferReady:        make(map[string]chan dto.FileTransferAcknowledgement),
+		transferCiphers:      make(map[string]*filetransferservice.ChunkCipher),
+		storageQueryTimeout:  DefaultStorageQueryTimeout,
+		transferReadyTimeout: DefaultTransferReadyTimeout,
+		chunkRetry:           DefaultChunkRetryConfig(),
+		outboundConfig:       DefaultOutboundBufferConfig(),
+		validateJPEGFrames:   true,
+		streamWatchdogConfig: DefaultStreamWatchdogConfig(),
+		streamWatchdog:       newStreamWatchdog(),
+		sessionEndAckTimeout: DefaultSessionEndAckTimeout,
+		sessionEndAcks:       newStreamWatchdog(),
+		pausedStreams:        newPausedStreams(),
+		audioStreams:         newAudioStreams(),
+		pendingTransfers:     newPendingTransferQueue(),
+
+		maxPendingTransfersPerClient: DefaultMaxPendingTransfersPerClient,
 	}
 }
 
+// SetConnectionHistoryService configura el servicio que persiste el historial de conexiones
+func (h *WebSocketHandler) SetConnectionHistoryService(connectionHistory pcservice.IConnectionHistoryService) {
+	h.connectionHistory = connectionHistory
+}
+
+// SetFeatureFlags configura los feature flags que activan comportamientos experimentales (nil = todos activos)
+func (h *WebSocketHandler) SetFeatureFlags(featureFlags featureflagservice.IFeatureFlagService) {
+	h.featureFlags = featureFlags
+}
+
+// SetStorageQuotaService configura el servicio de cuotas usado para admitir nuevas grabaciones
+func (h *WebSocketHandler) SetStorageQuotaService(storageQuota storagequotaservice.IStorageQuotaService) {
+	h.storageQuota = storageQuota
+}
+
+// SetRequireChunkEncryption exige cifrado de chunks a nivel de aplicación; los clientes que no lo acepten no reciben archivos
+func (h *WebSocketHandler) SetRequireChunkEncryption(required bool) {
+	h.requireChunkEncryption = required
+}
+
+// SetTaskRegistry configura el registro de las goroutines que envían transferencias
+func (h *WebSocketHandler) SetTaskRegistry(registry *backgroundtaskservice.TaskRegistry) {
+	h.taskRegistry = registry
+}
+
+// SetFrameResolutionLimit configura la resolución máxima de los frames de streaming y grabación (sin límite por defecto)
+func (h *WebSocketHandler) SetFrameResolutionLimit(limit videoservice.FrameResolutionLimit) {
+	h.frameLimit = limit
+}
+
+// SetOutboundBufferConfig configura el buffer de salida de cada conexión de cliente (Size <= 0 lo desactiva)
+func (h *WebSocketHandler) SetOutboundBufferConfig(config OutboundBufferConfig) {
+	h.outboundConfig = config
+}
+
+// SetConnectionCapacity configura el contador compartido que limita las conexiones de clientes (nil = sin límite)
+func (h *WebSocketHandler) SetConnectionCapacity(capacity *ConnectionCapacity) {
+	h.capacity = capacity
+}
+
 // HandleWebSocket handles WebSocket connections
 func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	// Upgrade HTTP connection to WebSocket
@@ -89,8 +261,22 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	}
 	defer conn.Close()
 
+	// Rechazar la conexión si ya se alcanzó el máximo de clientes
+	if h.capacity != nil {
+		release, ok := h.capacity.Acquire(ConnectionKindClient)
+		if !ok {
+			rejectAtCapacity(conn, ConnectionKindClient)
+			return
+		}
+		defer release()
+	}
+
 	// Get client IP
-	clientIP := getClientIP(c.Request)
+	clientIP := getClientIP(c)
+
+	// Contexto de la conexión: las consultas de sus mensajes se cancelan al desconectarse
+	connCtx, cancelConn := context.WithCancel(c.Request.Context())
+	defer cancelConn()
 
 	// Create connection object
 	connectionID := generateConnectionID()
@@ -99,23 +285,62 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 		IsAuth:     false,
 		LastSeen:   time.Now(),
 		RemoteAddr: clientIP,
+		ctx:        connCtx,
 	}
+	if h.outboundConfig.Size > 0 {
+		clientConn.outbound = NewOutboundBuffer(conn, h.outboundConfig)
+		defer clientConn.outbound.Close()
+	}
+
+	h.serveClientConnection(connectionID, clientConn)
+}
+
+// serveClientConnection registra la conexión, procesa sus mensajes hasta que se cierra y libera sus recursos.
+// Un client_shutdown previo al cierre hace que las sesiones activas terminen como ENDED_BY_CLIENT aunque
+// el socket se corte después; sin él, un corte sin frame de cierre las marca FAILED.
+func (h *WebSocketHandler) serveClientConnection(connectionID string, clientConn *ClientConnection) {
+	conn := clientConn.Conn
+	writer := clientConn.writer()
+	clientIP := clientConn.RemoteAddr
 
 	// Add to connections map
 	h.mutex.Lock()
 	h.connections[connectionID] = clientConn
 	h.mutex.Unlock()
 
+	// Motivo de la desconexión, se actualiza al salir del bucle de lectura
+	disconnectReason := remotesession.NewDisconnectReason(remotesession.CloseCodeNone, "connection closed")
+
 	// Clean up on exit
 	defer func() {
+		// Los frames pendientes terminan antes de liberar las grabaciones de la conexión
+		clientConn.stopFramePipeline()
+
+		// Bajo el mutex solo se quita la conexión de los mapas; la persistencia y los avisos van después para no
+		// bloquear al resto de conexiones mientras esperan a la BD
 		h.mutex.Lock()
 		delete(h.connections, connectionID)
 		if clientConn.PCID != "" {
 			delete(h.pcConnections, clientConn.PCID)
+		}
+		h.mutex.Unlock()
+
+		if clientConn.PCID != "" {
+			// Persistir cierre de la sesión de conexión para diagnóstico
+			h.recordDisconnect(clientConn, disconnectReason.String())
+
+			// Las grabaciones que el cliente dejó a medias no siguen ocupando hueco hasta que caduquen
+			h.releaseClientRecordings(clientConn)
 
-			// 🔄 Intentar finalizar/rechazar sesiones activas/pendientes para este PC
-			log.Printf("⚡ Calling HandleClientPCDisconnect for PCID: %s", clientConn.PCID)
-			if err := h.sessionService.HandleClientPCDisconnect(clientConn.PCID); err != nil {
+			// El audio de sus sesiones deja de reenviarse y no se queda en memoria
+			h.audioStreams.disablePC(clientConn.PCID)
+
+			// 🔄 Intentar finalizar/rechazar sesiones activas/pendientes para este PC.
+			// La conexión ya se cerró: se usa un contexto propio acotado
+			log.Printf("⚡ Calling HandleClientPCDisconnect for PCID: %s (%s)", clientConn.PCID, disconnectReason)
+			disconnectCtx, cancelDisconnect := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancelDisconnect()
+			if err := h.sessionService.HandleClientPCDisconnect(disconnectCtx, clientConn.PCID, disconnectReason); err != nil {
 				// Loguear el error, pero no hacer que la desconexión falle por esto.
 				// El servicio HandleClientPCDisconnect ya loguea sus propios errores críticos.
 				log.Printf("⚠️ Error calling HandleClientPCDisconnect for PC %s: %v", clientConn.PCID, err)
@@ -144,84 +369,287 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 
 					// Notificar a administradores sobre la desconexión del PC
 					if h.adminWSHandler != nil {
-						h.adminWSHandler.BroadcastPCDisconnected(clientConn.PCID, pcIdentifier, clientConn.UserID)
+						h.adminWSHandler.BroadcastPCDisconnected(clientConn.PCID, pcIdentifier, clientConn.UserID, disconnectReason)
 						// Notificar cambio de estado específico
-						h.adminWSHandler.BroadcastPCStatusChanged(clientConn.PCID, pcIdentifier, oldStatus, "OFFLINE")
+						h.adminWSHandler.BroadcastPCStatusChanged(clientConn.PCID, pcIdentifier, clientConn.UserID, oldStatus, "OFFLINE")
 						// Notificar actualización general de la lista
 						h.adminWSHandler.BroadcastPCListUpdate()
 					}
 				}
 			}
 		}
-		h.mutex.Unlock()
 		log.Printf("Client disconnected: %s (Username: %s, PCID: %s)", connectionID, clientConn.Username, clientConn.PCID)
+
+		// Confirmar el cierre ordenado una vez liberados PC y sesiones
+		if clientConn.shutdownRequested {
+			h.sendClientShutdownAck(writer, clientConn)
+		}
 	}()
 
 	log.Printf("New WebSocket connection: %s from %s", connectionID, clientIP)
 
+	// Un cliente que deja de enviar mensajes (heartbeats incluidos) durante el timeout se da por desconectado
+	staleTimeout := h.heartbeat.StaleTimeout()
+	conn.SetReadDeadline(time.Now().Add(staleTimeout))
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(staleTimeout))
+		return nil
+	})
+
 	// Handle messages
 	for {
-		var message dto.WebSocketMessage
-		err := conn.ReadJSON(&message)
+		messageType, payload, err := conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
 			}
+			// Tras un client_shutdown el corte del socket es esperado y no cambia el motivo
+			if !clientConn.shutdownRequested {
+				disconnectReason = disconnectReasonFromError(err)
+			}
 			break
 		}
 
 		// Update last seen
 		clientConn.LastSeen = time.Now()
+		conn.SetReadDeadline(clientConn.LastSeen.Add(staleTimeout))
+
+		// Un panic en un handler solo cierra esta conexión (con la limpieza habitual), no el servidor
+		subject := fmt.Sprintf("connection %s, PC %s", connectionID, clientConn.PCID)
+
+		// Frames y chunks en binario (si se negoció); los mensajes de control siguen siendo JSON
+		if messageType == websocket.BinaryMessage {
+			if err := runRecovered("client binary message", subject, func() {
+				h.handleBinaryMessage(writer, clientConn, payload)
+			}); err != nil {
+				disconnectReason = h.closeAfterPanic(clientConn)
+				break
+			}
+			continue
+		}
+
+		// Un mensaje de texto que no es JSON válido cierra la conexión, como hacía ReadJSON
+		var message dto.WebSocketMessage
+		if err := json.Unmarshal(payload, &message); err != nil {
+			log.Printf("WebSocket error: invalid JSON message: %v", err)
+			disconnectReason = disconnectReasonFromError(err)
+			break
+		}
 
 		// Handle message based on type
-		switch message.Type {
-		case dto.MessageTypeClientAuth:
-			h.handleClientAuth(conn, clientConn, message.Data)
-		case dto.MessageTypePCRegistration:
-			h.handlePCRegistration(conn, clientConn, message.Data, clientIP)
-		case dto.MessageTypeHeartbeat:
-			h.handleHeartbeat(conn, clientConn, message.Data)
-		case dto.MessageTypeScreenFrame:
-			h.handleScreenFrame(conn, clientConn, message.Data)
-		case "session_accepted":
-			h.handleSessionAccepted(conn, clientConn, message.Data)
-		case "session_rejected":
-			h.handleSessionRejected(conn, clientConn, message.Data)
-		case "video_chunk_upload":
-			h.handleVideoChunkUpload(conn, clientConn, message.Data)
-		case "video_upload_complete":
-			h.handleVideoUploadComplete(conn, clientConn, message.Data)
-		case "video_frame_upload":
-			h.handleVideoFrameUpload(conn, clientConn, message.Data)
-		case "video_recording_complete":
-			h.handleVideoRecordingComplete(conn, clientConn, message.Data)
-		case "file_transfer_ack":
-			h.handleFileTransferAcknowledgement(conn, clientConn, message.Data)
-		default:
-			log.Printf("Unknown message type: %s", message.Type)
+		err = runRecovered("client message "+message.Type, subject, func() {
+			h.dispatchClientMessage(writer, clientConn, message, clientIP, &disconnectReason)
+		})
+		if err != nil {
+			disconnectReason = h.closeAfterPanic(clientConn)
+			break
+		}
+
+		// El cliente se está cerrando: no se procesan más mensajes y la limpieza se hace como cierre limpio
+		if clientConn.shutdownRequested {
+			return
+		}
+	}
+}
+
+// dispatchClientMessage envía el mensaje de texto del cliente a su handler; client_shutdown actualiza disconnectReason
+func (h *WebSocketHandler) dispatchClientMessage(writer messageWriter, clientConn *ClientConnection, message dto.WebSocketMessage, clientIP string, disconnectReason *remotesession.DisconnectReason) {
+	switch message.Type {
+	case dto.MessageTypeClientAuth:
+		h.handleClientAuth(writer, clientConn, message.Data)
+	case dto.MessageTypePCRegistration:
+		h.handlePCRegistration(writer, clientConn, message.Data, clientIP)
+	case dto.MessageTypeHeartbeat:
+		h.handleHeartbeat(writer, clientConn, message.Data)
+	case dto.MessageTypeScreenFrame:
+		h.handleScreenFrame(writer, clientConn, message.Data)
+	case dto.MessageTypeAudioChunk:
+		h.handleAudioChunk(writer, clientConn, message.Data)
+	case "session_accepted":
+		h.handleSessionAccepted(writer, clientConn, message.Data)
+	case "session_rejected":
+		h.handleSessionRejected(writer, clientConn, message.Data)
+	case "video_chunk_upload":
+		h.handleVideoChunkUpload(writer, clientConn, message.Data)
+	case "video_upload_complete":
+		h.handleVideoUploadComplete(writer, clientConn, message.Data)
+	case "video_upload_status":
+		h.handleVideoUploadStatus(writer, clientConn, message.Data)
+	case "video_frame_upload":
+		h.handleVideoFrameUpload(writer, clientConn, message.Data)
+	case "video_frames_batch":
+		h.handleVideoFramesBatch(writer, clientConn, message.Data)
+	case "video_recording_complete":
+		h.handleVideoRecordingComplete(writer, clientConn, message.Data)
+	case "file_transfer_ack":
+		h.handleFileTransferAcknowledgement(writer, clientConn, message.Data)
+	case "storage_query_response":
+		h.handleStorageQueryResponse(writer, clientConn, message.Data)
+	case dto.MessageTypeSessionEndAck:
+		h.handleSessionEndAck(writer, clientConn, message.Data)
+	case dto.MessageTypeActivityStatus:
+		h.handleActivityStatus(writer, clientConn, message.Data)
+	case dto.MessageTypeQueryPendingTransfers:
+		h.handleQueryPendingTransfers(writer, clientConn)
+	case dto.MessageTypeClientShutdown:
+		*disconnectReason = h.handleClientShutdown(clientConn, message.Data)
+	default:
+		log.Printf("Unknown message type: %s", message.Type)
+	}
+}
+
+// closeAfterPanic cierra con 1011 la conexión cuyo handler entró en pánico; el defer de HandleWebSocket
+// hace después la limpieza de una desconexión anormal (PC offline, sesiones, pings)
+func (h *WebSocketHandler) closeAfterPanic(clientConn *ClientConnection) remotesession.DisconnectReason {
+	closeMessage := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "internal error")
+	clientConn.Conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second))
+	return remotesession.NewDisconnectReason(remotesession.CloseCodeInternalError, "handler panic")
+}
+
+// handleClientShutdown registra el aviso de cierre intencionado del cliente y devuelve el motivo de desconexión limpio
+func (h *WebSocketHandler) handleClientShutdown(clientConn *ClientConnection, data interface{}) remotesession.DisconnectReason {
+	var shutdown dto.ClientShutdownRequest
+	if shutdownData, err := json.Marshal(data); err == nil {
+		json.Unmarshal(shutdownData, &shutdown)
+	}
+
+	clientConn.shutdownRequested = true
+	log.Printf("👋 CLIENT SHUTDOWN: PC %s (%s) is closing intentionally (reason: %q)", clientConn.PCID, clientConn.Username, shutdown.Reason)
+
+	return remotesession.NewClientShutdownReason(shutdown.Reason)
+}
+
+// sendClientShutdownAck confirma al cliente que su PC quedó offline y cierra el WebSocket de forma ordenada
+func (h *WebSocketHandler) sendClientShutdownAck(conn messageWriter, clientConn *ClientConnection) {
+	err := conn.WriteJSON(dto.WebSocketMessage{
+		Type: dto.MessageTypeClientShutdownAck,
+		Data: dto.ClientShutdownAck{
+			PCID:   clientConn.PCID,
+			Status: string(clientpc.PCConnectionStatusOffline),
+		},
+	})
+	if err != nil {
+		log.Printf("⚠️ CLIENT SHUTDOWN: Error sending ack to PC %s: %v", clientConn.PCID, err)
+		return
+	}
+
+	// El ack debe salir antes que el frame de cierre
+	if clientConn.outbound != nil {
+		clientConn.outbound.Close()
+	}
+
+	closeMessage := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "client_shutdown")
+	clientConn.Conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second))
+}
+
+// disconnectReasonFromError extrae el código y texto de cierre del error devuelto por ReadJSON.
+// Errores sin frame de cierre (caídas de red, timeouts) se tratan como cierre anormal.
+func disconnectReasonFromError(err error) remotesession.DisconnectReason {
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) {
+		return remotesession.NewDisconnectReason(closeErr.Code, closeErr.Text)
+	}
+	return remotesession.NewDisconnectReason(remotesession.CloseCodeAbnormalClosure, err.Error())
+}
+
+// supportedCapabilities capacidades opcionales que el servidor puede negociar con los clientes
+var supportedCapabilities = map[string]bool{
+	dto.CapabilityBinaryFrames:  true,
+	dto.CapabilityAudioStream:   true,
+	dto.CapabilitySessionEndAck: true,
+}
+
+// negotiateCapabilities devuelve, sin duplicados, las capacidades solicitadas que el servidor soporta
+func negotiateCapabilities(requested []string) []string {
+	accepted := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, capability := range requested {
+		if supportedCapabilities[capability] && !seen[capability] {
+			seen[capability] = true
+			accepted = append(accepted, capability)
 		}
 	}
+	return accepted
+}
+
+// handleBinaryMessage procesa un mensaje binario (cabecera JSON + bytes en crudo) con el mismo flujo que su
+// equivalente JSON. Solo se aceptan de clientes que negociaron CapabilityBinaryFrames al autenticarse.
+func (h *WebSocketHandler) handleBinaryMessage(conn messageWriter, clientConn *ClientConnection, data []byte) {
+	if !clientConn.binaryFrames {
+		log.Printf("❌ BINARY FRAME: Ignoring binary message from PC %s, binary frames not negotiated", clientConn.PCID)
+		return
+	}
+
+	frame, err := dto.DecodeBinaryFrame(data)
+	if err != nil {
+		log.Printf("❌ BINARY FRAME: Error decoding binary message from PC %s: %v", clientConn.PCID, err)
+		return
+	}
+
+	switch frame.Type {
+	case dto.BinaryFrameScreenFrame:
+		var screenFrame dto.ScreenFrame
+		if !decodeRawPayload(conn, dto.MessageTypeScreenFrame, frame.Header, &screenFrame) {
+			return
+		}
+		screenFrame.FrameData = frame.Payload
+		h.processScreenFrame(clientConn, screenFrame)
+	case dto.BinaryFrameVideoFrame:
+		var videoFrame dto.VideoFrameUpload
+		if !decodeRawPayload(conn, "video_frame_upload", frame.Header, &videoFrame) {
+			return
+		}
+		h.saveVideoFrame(conn, clientConn, videoFrame, frame.Payload)
+	case dto.BinaryFrameVideoChunk:
+		var videoChunk dto.VideoChunk
+		if !decodeRawPayload(conn, "video_chunk_upload", frame.Header, &videoChunk) {
+			return
+		}
+		h.processVideoChunk(conn, clientConn, videoChunk, frame.Payload)
+	case dto.BinaryFrameAudioChunk:
+		var audioChunk dto.AudioChunk
+		if !decodeRawPayload(conn, dto.MessageTypeAudioChunk, frame.Header, &audioChunk) {
+			return
+		}
+		audioChunk.AudioData = frame.Payload
+		h.processAudioChunk(clientConn, audioChunk)
+	default:
+		log.Printf("❌ BINARY FRAME: Unknown binary frame type %d from PC %s", frame.Type, clientConn.PCID)
+	}
 }
 
 // handleClientAuth handles client authentication
-func (h *WebSocketHandler) handleClientAuth(conn *websocket.Conn, clientConn *ClientConnection, data interface{}) {
+func (h *WebSocketHandler) handleClientAuth(conn messageWriter, clientConn *ClientConnection, data interface{}) {
 	// Parse authentication request
 	authData, err := json.Marshal(data)
 	if err != nil {
-		h.sendAuthResponse(conn, false, "", "", "Invalid request format")
+		h.sendAuthResponse(conn, false, "", "", "Invalid request format", nil, "")
 		return
 	}
 
 	var authReq dto.ClientAuthRequest
 	if err := json.Unmarshal(authData, &authReq); err != nil {
-		h.sendAuthResponse(conn, false, "", "", "Invalid request format")
+		h.sendAuthResponse(conn, false, "", "", "Invalid request format", nil, "")
+		return
+	}
+
+	// La versión se comprueba antes que las credenciales: un cliente incompatible debe actualizarse
+	protocolVersion, shimmed, err := negotiateProtocolVersion(authReq.ProtocolVersion)
+	if err != nil {
+		sendProtocolIncompatible(conn, authReq.ProtocolVersion, err)
+		h.sendAuthResponse(conn, false, "", "", dto.MessageTypeProtocolIncompatible, nil, "")
 		return
 	}
 
 	// Authenticate user
 	token, user, err := h.authService.AuthenticateClient(authReq.Username, authReq.Password)
 	if err != nil {
-		h.sendAuthResponse(conn, false, "", "", "Authentication failed")
+		log.Printf("🔒 AUTH: Client authentication failed for user %q: %v", authReq.Username, err)
+		errorMsg := "Authentication failed"
+		if isCredentialFailure(err) {
+			errorMsg = invalidCredentialsMessage
+		}
+		h.sendAuthResponse(conn, false, "", "", errorMsg, nil, "")
 		return
 	}
 
@@ -230,14 +658,32 @@ func (h *WebSocketHandler) handleClientAuth(conn *websocket.Conn, clientConn *Cl
 	clientConn.Username = user.Username()
 	clientConn.Role = string(user.Role())
 	clientConn.IsAuth = true
+	clientConn.protocolVersion = protocolVersion
+	if shimmed {
+		log.Printf("🧩 PROTOCOL: Client %s uses protocol %s, enabling compatibility with server %s",
+			user.Username(), protocolVersion, CurrentProtocolVersion)
+	}
+
+	// Negociar capacidades opcionales: solo se aceptan las que el servidor soporta
+	capabilities := negotiateCapabilities(authReq.Capabilities)
+	for _, capability := range capabilities {
+		switch capability {
+		case dto.CapabilityBinaryFrames:
+			clientConn.binaryFrames = true
+		case dto.CapabilityAudioStream:
+			clientConn.audioStream = true
+		case dto.CapabilitySessionEndAck:
+			clientConn.sessionEndAck = true
+		}
+	}
 
 	// Send success response
-	h.sendAuthResponse(conn, true, token, user.UserID(), "")
-	log.Printf("Client authenticated: %s (%s)", user.Username(), user.UserID())
+	h.sendAuthResponse(conn, true, token, user.UserID(), "", capabilities, protocolVersion.String())
+	log.Printf("Client authenticated: %s (%s), protocol %s, capabilities: %v", user.Username(), user.UserID(), protocolVersion, capabilities)
 }
 
 // handlePCRegistration handles PC registration
-func (h *WebSocketHandler) handlePCRegistration(conn *websocket.Conn, clientConn *ClientConnection, data interface{}, clientIP string) {
+func (h *WebSocketHandler) handlePCRegistration(conn messageWriter, clientConn *ClientConnection, data interface{}, clientIP string) {
 	// Check if client is authenticated
 	if !clientConn.IsAuth {
 		h.sendPCRegistrationResponse(conn, false, "", "Authentication required")
@@ -257,24 +703,23 @@ func (h *WebSocketHandler) handlePCRegistration(conn *websocket.Conn, clientConn
 		return
 	}
 
-	// Use provided IP or detect from connection
-	ip := regReq.IP
-	if ip == "" {
-		ip = clientIP
-	}
-
 	// Register PC
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	pc, err := h.pcService.RegisterPC(ctx, clientConn.UserID, regReq.PCIdentifier, ip)
+	pc, wasConnecting, err := h.registerClientPC(ctx, clientConn, regReq, clientIP)
 	if err != nil {
 		h.sendPCRegistrationResponse(conn, false, "", err.Error())
 		return
 	}
 
-	// Update connection with PC info
-	clientConn.PCID = pc.PCID
+	// Registrar inicio de la sesión de conexión (solo una vez por conexión y PC)
+	if clientConn.ConnectionSession == nil || clientConn.ConnectionSession.PCID() != pc.PCID {
+		h.recordConnect(ctx, clientConn, pc.PCID)
+	}
+
+	// Tipos de input que el agente puede ejecutar; los demás se rechazan antes de enviarlos
+	clientConn.supportedInputEvents = normalizeInputEventTypes(regReq.SupportedInputEvents)
 
 	// Add to PC connections map
 	h.mutex.Lock()
@@ -282,33 +727,179 @@ func (h *WebSocketHandler) handlePCRegistration(conn *websocket.Conn, clientConn
 	h.mutex.Unlock()
 
 	// Notificar a administradores sobre el registro del PC
+	h.broadcastPCRegistered(pc, clientConn.Username, wasConnecting)
+
+	// Send success response
+	h.sendPCRegistrationResponse(conn, true, pc.PCID, "")
+	log.Printf("PC registered: %s (%s) for user %s, input events: %v", regReq.PCIdentifier, pc.PCID, clientConn.Username, clientConn.supportedInputEvents)
+
+	// Entregar la solicitud de control que esperaba en cola a que el PC se conectara
+	h.deliverQueuedSession(ctx, pc.PCID)
+}
+
+// registerClientPC registra el PC del cliente autenticado pasando por CONNECTING si ya existía.
+// Es la parte del registro común al WebSocket y a la API REST; retorna el PC y si estuvo en CONNECTING.
+func (h *WebSocketHandler) registerClientPC(ctx context.Context, clientConn *ClientConnection, regReq dto.PCRegistrationRequest, clientIP string) (*clientpc.ClientPC, bool, error) {
+	// Validar la IP declarada contra la observada en el socket
+	registrationIP := resolveRegistrationIP(regReq.IP, clientIP)
+	if registrationIP.Discrepancy != "" {
+		log.Printf("🚩 PC REGISTRATION: %s for PC %s of user %s (reported %q, observed %q, using %q)",
+			registrationIP.Discrepancy, regReq.PCIdentifier, clientConn.Username, regReq.IP, clientIP, registrationIP.IP)
+	}
+
+	// Mientras se completa el registro el PC queda en CONNECTING
+	connecting := h.markPCConnecting(ctx, clientConn, regReq.PCIdentifier)
+
+	metadata := clientpc.PCMetadata{OS: regReq.OS, Hostname: regReq.Hostname, AgentVersion: regReq.AgentVersion}
+	pc, err := h.pcService.RegisterPC(ctx, clientConn.UserID, regReq.PCIdentifier, registrationIP.IP, metadata)
+	if err != nil {
+		if connecting != nil {
+			h.failPCConnecting(ctx, clientConn, connecting)
+		}
+		return nil, false, err
+	}
+
+	// Update connection with PC info
+	clientConn.PCID = pc.PCID
+	return pc, connecting != nil, nil
+}
+
+// broadcastPCRegistered notifica a los administradores el registro de un PC que queda ONLINE
+func (h *WebSocketHandler) broadcastPCRegistered(pc *clientpc.ClientPC, username string, wasConnecting bool) {
+	if h.adminWSHandler == nil {
+		return
+	}
+
+	h.adminWSHandler.BroadcastPCRegistered(pc.PCID, pc.Identifier, pc.OwnerUserID, pc.IP)
+
+	// También notificar que el PC está ahora ONLINE ya que se acaba de conectar
+	log.Printf("PC registered and online: %s (%s) for user %s", pc.Identifier, pc.PCID, username)
+	h.adminWSHandler.BroadcastPCConnected(pc.PCID, pc.Identifier, pc.OwnerUserID, pc.IP)
+	previousStatus := string(clientpc.PCConnectionStatusOffline)
+	if wasConnecting {
+		previousStatus = string(clientpc.PCConnectionStatusConnecting)
+	}
+	h.adminWSHandler.BroadcastPCStatusChanged(pc.PCID, pc.Identifier, pc.OwnerUserID, previousStatus, string(clientpc.PCConnectionStatusOnline))
+
+	// Notificar actualización general de la lista
+	h.adminWSHandler.BroadcastPCListUpdate()
+}
+
+// markPCConnecting persiste y difunde el estado CONNECTING de un PC ya registrado antes de su registro.
+// Retorna nil si el PC es nuevo, si el socket ya lo tenía registrado o si no se pudo marcar.
+func (h *WebSocketHandler) markPCConnecting(ctx context.Context, clientConn *ClientConnection, pcIdentifier string) *clientpc.ClientPC {
+	pc, err := h.pcService.MarkPCConnecting(ctx, clientConn.UserID, pcIdentifier)
+	if err != nil {
+		log.Printf("⚠️ Error marking PC %s as connecting: %v", pcIdentifier, err)
+		return nil
+	}
+	if pc == nil {
+		return nil
+	}
+
+	// Un re-registro sobre el mismo socket no pasa por CONNECTING
+	if clientConn.PCID == pc.PCID {
+		if err := h.pcService.UpdatePCConnectionStatus(ctx, pc.PCID, clientpc.PCConnectionStatusOnline); err != nil {
+			log.Printf("⚠️ Error restoring ONLINE status for PC %s: %v", pc.PCID, err)
+		}
+		return nil
+	}
+
+	log.Printf("🔄 PC CONNECTING: %s (%s) for user %s", pc.Identifier, pc.PCID, clientConn.Username)
 	if h.adminWSHandler != nil {
-		h.adminWSHandler.BroadcastPCRegistered(pc.PCID, pc.Identifier, pc.OwnerUserID, pc.IP)
+		h.adminWSHandler.BroadcastPCStatusChanged(pc.PCID, pc.Identifier, pc.OwnerUserID,
+			string(clientpc.PCConnectionStatusOffline), string(clientpc.PCConnectionStatusConnecting))
+	}
+	return pc
+}
 
-		// También notificar que el PC está ahora ONLINE ya que se acaba de conectar
-		log.Printf("PC registered and online: %s (%s) for user %s", pc.Identifier, pc.PCID, clientConn.Username)
-		h.adminWSHandler.BroadcastPCConnected(pc.PCID, pc.Identifier, pc.OwnerUserID, pc.IP)
-		h.adminWSHandler.BroadcastPCStatusChanged(pc.PCID, pc.Identifier, "OFFLINE", "ONLINE")
+// failPCConnecting devuelve a OFFLINE un PC cuyo registro falló tras marcarlo CONNECTING,
+// salvo que otro socket vivo lo mantenga registrado
+func (h *WebSocketHandler) failPCConnecting(ctx context.Context, clientConn *ClientConnection, pc *clientpc.ClientPC) {
+	h.mutex.RLock()
+	existing, registered := h.pcConnections[pc.PCID]
+	h.mutex.RUnlock()
 
-		// Notificar actualización general de la lista
-		h.adminWSHandler.BroadcastPCListUpdate()
+	status := clientpc.PCConnectionStatusOffline
+	if registered && existing != clientConn {
+		status = clientpc.PCConnectionStatusOnline
 	}
 
-	// Send success response
-	h.sendPCRegistrationResponse(conn, true, pc.PCID, "")
-	log.Printf("PC registered: %s (%s) for user %s", regReq.PCIdentifier, pc.PCID, clientConn.Username)
+	if err := h.pcService.UpdatePCConnectionStatus(ctx, pc.PCID, status); err != nil {
+		log.Printf("⚠️ Error updating status of PC %s after failed registration: %v", pc.PCID, err)
+	}
+
+	log.Printf("❌ PC CONNECTING: Registration failed for %s (%s), status %s", pc.Identifier, pc.PCID, status)
+	if h.adminWSHandler != nil {
+		h.adminWSHandler.BroadcastPCStatusChanged(pc.PCID, pc.Identifier, pc.OwnerUserID,
+			string(clientpc.PCConnectionStatusConnecting), string(status))
+	}
 }
 
-// handleHeartbeat handles heartbeat messages
-func (h *WebSocketHandler) handleHeartbeat(conn *websocket.Conn, clientConn *ClientConnection, data interface{}) {
-	// Parse heartbeat request
-	hbData, err := json.Marshal(data)
+// deliverQueuedSession envía al cliente recién registrado la solicitud de control que tenía en cola
+func (h *WebSocketHandler) deliverQueuedSession(ctx context.Context, pcID string) {
+	if h.sessionService == nil {
+		return
+	}
+
+	session, err := h.sessionService.DeliverQueuedSession(ctx, pcID)
 	if err != nil {
+		log.Printf("⚠️ Error delivering queued session for PC %s: %v", pcID, err)
+		return
+	}
+	if session == nil {
 		return
 	}
 
+	if session.IsActive() {
+		err = h.SendAutoAcceptedSessionToClient(session.SessionID(), pcID)
+	} else {
+		err = h.SendRemoteControlRequestToClient(session.SessionID(), pcID, session.AdminUserID(), "")
+	}
+	if err != nil {
+		log.Printf("⚠️ Error sending queued session %s to PC %s: %v", session.SessionID(), pcID, err)
+	}
+}
+
+// recordConnect persiste una nueva sesión de conexión para el PC
+func (h *WebSocketHandler) recordConnect(ctx context.Context, clientConn *ClientConnection, pcID string) {
+	if h.connectionHistory == nil {
+		return
+	}
+
+	// Cerrar una sesión previa si el mismo socket re-registra otro PC
+	if clientConn.ConnectionSession != nil {
+		h.recordDisconnect(clientConn, "re-registered as another PC")
+	}
+
+	session, err := h.connectionHistory.RecordConnect(ctx, pcID, clientConn.RemoteAddr)
+	if err != nil {
+		log.Printf("⚠️ Error recording connection session for PC %s: %v", pcID, err)
+		return
+	}
+	clientConn.ConnectionSession = session
+}
+
+// recordDisconnect persiste el cierre de la sesión de conexión activa
+func (h *WebSocketHandler) recordDisconnect(clientConn *ClientConnection, reason string) {
+	if h.connectionHistory == nil || clientConn.ConnectionSession == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.connectionHistory.RecordDisconnect(ctx, clientConn.ConnectionSession, reason); err != nil {
+		log.Printf("⚠️ Error recording disconnect for PC %s: %v", clientConn.ConnectionSession.PCID(), err)
+	}
+	clientConn.ConnectionSession = nil
+}
+
+// handleHeartbeat handles heartbeat messages
+func (h *WebSocketHandler) handleHeartbeat(conn messageWriter, clientConn *ClientConnection, data interface{}) {
+	// Parse heartbeat request
 	var hbReq dto.HeartbeatRequest
-	if err := json.Unmarshal(hbData, &hbReq); err != nil {
+	if !decodePayload(conn, dto.MessageTypeHeartbeat, data, &hbReq) {
 		return
 	}
 
@@ -317,38 +908,7 @@ func (h *WebSocketHandler) handleHeartbeat(conn *websocket.Conn, clientConn *Cli
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		// Primero verificar el estado actual del PC para detectar cambios
-		currentPC, err := h.pcService.GetPCByID(ctx, clientConn.PCID)
-		var wasOffline bool
-		if err == nil && currentPC != nil {
-			wasOffline = currentPC.ConnectionStatus == clientpc.PCConnectionStatusOffline
-		}
-
-		// Actualizar último visto
-		if err := h.pcService.UpdatePCLastSeen(ctx, clientConn.PCID); err != nil {
-			log.Printf("Error updating PC last seen: %v", err)
-		}
-
-		// Asegurar que el PC esté marcado como online
-		if err := h.pcService.UpdatePCConnectionStatus(ctx, clientConn.PCID, clientpc.PCConnectionStatusOnline); err != nil {
-			log.Printf("Error updating PC status to online: %v", err)
-		} else {
-			// Si el PC estaba offline y ahora está online, notificar el cambio
-			if wasOffline && h.adminWSHandler != nil {
-				log.Printf("PC reconnected: %s (%s)", clientConn.PCID, clientConn.Username)
-
-				// Obtener información actualizada del PC para las notificaciones
-				updatedPC, err := h.pcService.GetPCByID(ctx, clientConn.PCID)
-				if err == nil && updatedPC != nil {
-					// Notificar reconexión del PC con información completa
-					h.adminWSHandler.BroadcastPCConnected(updatedPC.PCID, updatedPC.Identifier, updatedPC.OwnerUserID, updatedPC.IP)
-					// Notificar cambio de estado específico
-					h.adminWSHandler.BroadcastPCStatusChanged(updatedPC.PCID, updatedPC.Identifier, "OFFLINE", "ONLINE")
-					// Notificar actualización general de la lista
-					h.adminWSHandler.BroadcastPCListUpdate()
-				}
-			}
-
+		if h.refreshPCPresence(ctx, clientConn.PCID, clientConn.Username) {
 			// Procesar transferencias pendientes para este cliente
 			log.Printf("🔍 Verificando transferencias pendientes para cliente: %s", clientConn.PCID)
 			h.processPendingTransfers(clientConn.PCID)
@@ -367,8 +927,58 @@ func (h *WebSocketHandler) handleHeartbeat(conn *websocket.Conn, clientConn *Cli
 	conn.WriteJSON(response)
 }
 
+// refreshPCPresence actualiza el último visto del PC y lo mantiene ONLINE, notificando a los administradores
+// si estaba OFFLINE. Es la parte del heartbeat común al WebSocket y a la API REST; retorna false si no pudo marcarse.
+func (h *WebSocketHandler) refreshPCPresence(ctx context.Context, pcID, username string) bool {
+	// Primero verificar el estado actual del PC para detectar cambios
+	currentPC, err := h.pcService.GetPCByID(ctx, pcID)
+	var wasOffline bool
+	if err == nil && currentPC != nil {
+		wasOffline = currentPC.ConnectionStatus == clientpc.PCConnectionStatusOffline
+	}
+
+	// Actualizar último visto
+	if err := h.pcService.UpdatePCLastSeen(ctx, pcID); err != nil {
+		log.Printf("Error updating PC last seen: %v", err)
+	}
+
+	// Asegurar que el PC esté marcado como online
+	if err := h.pcService.UpdatePCConnectionStatus(ctx, pcID, clientpc.PCConnectionStatusOnline); err != nil {
+		log.Printf("Error updating PC status to online: %v", err)
+		return false
+	}
+
+	// Si el PC estaba offline y ahora está online, notificar el cambio
+	if wasOffline && h.adminWSHandler != nil {
+		log.Printf("PC reconnected: %s (%s)", pcID, username)
+
+		// Obtener información actualizada del PC para las notificaciones
+		updatedPC, err := h.pcService.GetPCByID(ctx, pcID)
+		if err == nil && updatedPC != nil {
+			// Notificar reconexión del PC con información completa
+			h.adminWSHandler.BroadcastPCConnected(updatedPC.PCID, updatedPC.Identifier, updatedPC.OwnerUserID, updatedPC.IP)
+			// Notificar cambio de estado específico
+			h.adminWSHandler.BroadcastPCStatusChanged(updatedPC.PCID, updatedPC.Identifier, updatedPC.OwnerUserID, "OFFLINE", "ONLINE")
+			// Notificar actualización general de la lista
+			h.adminWSHandler.BroadcastPCListUpdate()
+		}
+	}
+	return true
+}
+
 // handleScreenFrame maneja frames de pantalla recibidos de clientes
-func (h *WebSocketHandler) handleScreenFrame(conn *websocket.Conn, clientConn *ClientConnection, data interface{}) {
+func (h *WebSocketHandler) handleScreenFrame(conn messageWriter, clientConn *ClientConnection, data interface{}) {
+	// Parse screen frame data
+	var screenFrame dto.ScreenFrame
+	if !decodePayload(conn, dto.MessageTypeScreenFrame, data, &screenFrame) {
+		return
+	}
+
+	h.processScreenFrame(clientConn, screenFrame)
+}
+
+// processScreenFrame valida y reenvía al administrador un frame recibido por JSON o en binario
+func (h *WebSocketHandler) processScreenFrame(clientConn *ClientConnection, screenFrame dto.ScreenFrame) {
 	if !clientConn.IsAuth {
 		log.Printf("❌ SCREEN FRAME: Unauthorized client attempted to send frame")
 		return
@@ -379,40 +989,60 @@ func (h *WebSocketHandler) handleScreenFrame(conn *websocket.Conn, clientConn *C
 		return
 	}
 
-	// Parse screen frame data
-	frameData, err := json.Marshal(data)
-	if err != nil {
-		log.Printf("❌ SCREEN FRAME: Error marshalling frame data: %v", err)
+	log.Printf("📹 SCREEN FRAME: Received frame %d from PC %s (session: %s, size: %dx%d)",
+		screenFrame.SequenceNum, clientConn.PCID, screenFrame.SessionID, screenFrame.Width, screenFrame.Height)
+
+	if !h.acceptFrameData(clientConn, screenFrame.SessionID, screenFrame.FrameData) {
 		return
 	}
 
-	var screenFrame dto.ScreenFrame
-	if err := json.Unmarshal(frameData, &screenFrame); err != nil {
-		log.Printf("❌ SCREEN FRAME: Error unmarshalling screen frame: %v", err)
+	// Con reducción de resolución el frame se procesa fuera de la lectura; si la cola está llena se descarta,
+	// como un frame congestionado, porque el siguiente lo sustituye
+	if pipeline := h.framePipelineFor(clientConn); pipeline != nil {
+		if !pipeline.tryEnqueue(func() { h.deliverScreenFrame(clientConn, screenFrame) }) {
+			log.Printf("⏭️ SCREEN FRAME: Frame %d from PC %s dropped, downscaling queue full", screenFrame.SequenceNum, clientConn.PCID)
+		}
 		return
 	}
+	h.deliverScreenFrame(clientConn, screenFrame)
+}
 
-	log.Printf("📹 SCREEN FRAME: Received frame %d from PC %s (session: %s, size: %dx%d)",
-		screenFrame.SequenceNum, clientConn.PCID, screenFrame.SessionID, screenFrame.Width, screenFrame.Height)
-
+// deliverScreenFrame comprueba el permiso de streaming, aplica la resolución máxima y reenvía el frame al administrador
+func (h *WebSocketHandler) deliverScreenFrame(clientConn *ClientConnection, screenFrame dto.ScreenFrame) {
 	// Validar que la sesión está activa y el PC tiene permisos
-	err = h.sessionService.ValidateStreamingPermission(screenFrame.SessionID, clientConn.PCID)
+	err := h.sessionService.ValidateStreamingPermission(clientConn.Context(), screenFrame.SessionID, clientConn.PCID)
 	if err != nil {
 		log.Printf("❌ SCREEN FRAME: Invalid streaming permission: %v", err)
 		return
 	}
+	h.streamWatchdog.stop(screenFrame.SessionID)
 
 	// Obtener el administrador que está controlando esta sesión
-	adminUserID, err := h.sessionService.GetAdminUserIDForActiveSession(screenFrame.SessionID)
+	adminUserID, err := h.sessionService.GetAdminUserIDForActiveSession(clientConn.Context(), screenFrame.SessionID)
 	if err != nil {
 		log.Printf("❌ SCREEN FRAME: Error getting admin for session: %v", err)
 		return
 	}
 
+	// Aplicar la resolución máxima antes de reenviar al administrador
+	var ok bool
+	screenFrame.FrameData, screenFrame.Width, screenFrame.Height, ok = h.enforceFrameResolution(
+		screenFrame.FrameData, screenFrame.Width, screenFrame.Height, screenFrame.SessionID)
+	if !ok {
+		return
+	}
+
 	// Reenviar frame al administrador a través del AdminWebSocketHandler
 	if h.adminWSHandler != nil {
 		err := h.adminWSHandler.ForwardScreenFrameToAdmin(adminUserID, screenFrame)
-		if err != nil {
+		if errors.Is(err, ErrAdminNotConnected) {
+			h.pauseStreamForAbsentAdmin(clientConn, screenFrame.SessionID, adminUserID)
+			return
+		}
+		h.pausedStreams.remove(screenFrame.SessionID)
+		if errors.Is(err, ErrFrameThrottled) {
+			log.Printf("⏭️ SCREEN FRAME: Frame %d dropped, admin %s link congested", screenFrame.SequenceNum, adminUserID)
+		} else if err != nil {
 			log.Printf("❌ SCREEN FRAME: Error forwarding frame to admin %s: %v", adminUserID, err)
 		} else {
 			log.Printf("✅ SCREEN FRAME: Frame %d forwarded to admin %s", screenFrame.SequenceNum, adminUserID)
@@ -423,28 +1053,29 @@ func (h *WebSocketHandler) handleScreenFrame(conn *websocket.Conn, clientConn *C
 }
 
 // handleSessionAccepted maneja cuando el cliente acepta una sesión de control remoto
-func (h *WebSocketHandler) handleSessionAccepted(conn *websocket.Conn, clientConn *ClientConnection, data interface{}) {
+func (h *WebSocketHandler) handleSessionAccepted(conn messageWriter, clientConn *ClientConnection, data interface{}) {
 	// Parse session accepted message
-	sessionData, err := json.Marshal(data)
-	if err != nil {
-		log.Printf("Error marshalling session accepted data: %v", err)
-		return
-	}
-
 	var acceptedMsg struct {
 		SessionID string `json:"session_id"`
 	}
-	if err := json.Unmarshal(sessionData, &acceptedMsg); err != nil {
-		log.Printf("Error unmarshalling session accepted message: %v", err)
+	if !decodePayload(conn, "session_accepted", data, &acceptedMsg) {
 		return
 	}
 
 	log.Printf("🎉 Client %s accepted remote control session: %s", clientConn.PCID, acceptedMsg.SessionID)
 
 	// Actualizar estado de sesión en base de datos a ACTIVE
-	err = h.sessionService.AcceptSession(acceptedMsg.SessionID)
+	err := h.sessionService.AcceptSession(clientConn.Context(), acceptedMsg.SessionID)
 	if err != nil {
-		log.Printf("❌ Error accepting session in service: %v", err)
+		if errors.Is(err, remotesessionservice.ErrSessionAlreadyDecided) {
+			// Un reintento o un rechazo cruzado llegó primero: se conserva esa decisión
+			log.Printf("⚠️ Ignoring late acceptance from client %s: %v", clientConn.PCID, err)
+		} else if errors.Is(err, remotesessionservice.ErrSessionApprovalExpired) {
+			// El plazo anunciado en remote_control_request ya pasó: la solicitud se rechaza por caducada
+			log.Printf("⌛ Ignoring expired acceptance from client %s: %v", clientConn.PCID, err)
+		} else {
+			log.Printf("❌ Error accepting session in service: %v", err)
+		}
 
 		// Enviar error al cliente
 		errorMsg := dto.WebSocketMessage{
@@ -463,7 +1094,7 @@ func (h *WebSocketHandler) handleSessionAccepted(conn *websocket.Conn, clientCon
 
 	// Notificar al administrador que la sesión fue aceptada
 	if h.adminWSHandler != nil {
-		err = h.adminWSHandler.NotifySessionAccepted(acceptedMsg.SessionID)
+		err = h.adminWSHandler.NotifySessionAccepted(clientConn.Context(), acceptedMsg.SessionID)
 		if err != nil {
 			log.Printf("⚠️ Warning: Failed to notify admin of session acceptance: %v", err)
 		} else {
@@ -487,36 +1118,120 @@ func (h *WebSocketHandler) handleSessionAccepted(conn *websocket.Conn, clientCon
 		log.Printf("Error sending session started message to client: %v", err)
 	} else {
 		log.Printf("✅ Session started confirmation sent to client %s", clientConn.PCID)
+		h.sendStreamConfig(conn, acceptedMsg.SessionID)
+		h.watchFirstFrame(acceptedMsg.SessionID, clientConn.PCID)
 	}
 }
 
-// handleSessionRejected maneja cuando el cliente rechaza una sesión de control remoto
-func (h *WebSocketHandler) handleSessionRejected(conn *websocket.Conn, clientConn *ClientConnection, data interface{}) {
-	// Parse session rejected message
-	sessionData, err := json.Marshal(data)
+// sendStreamConfig informa al cliente de la resolución máxima que el servidor aplica a los frames de la sesión
+func (h *WebSocketHandler) sendStreamConfig(conn messageWriter, sessionID string) {
+	err := conn.WriteJSON(dto.WebSocketMessage{
+		Type: dto.MessageTypeStreamConfig,
+		Data: dto.StreamConfig{
+			SessionID:      sessionID,
+			MaxWidth:       h.frameLimit.MaxWidth,
+			MaxHeight:      h.frameLimit.MaxHeight,
+			OversizePolicy: string(h.frameLimit.Mode),
+		},
+	})
 	if err != nil {
-		log.Printf("Error marshalling session rejected data: %v", err)
-		return
+		log.Printf("⚠️ STREAM CONFIG: Error sending stream config for session %s: %v", sessionID, err)
+	}
+}
+
+// enforceFrameResolution aplica el límite de resolución a un frame y retorna los bytes y la resolución resultantes
+// (la declarada si no hay límite); retorna false si el frame debe descartarse
+func (h *WebSocketHandler) enforceFrameResolution(frameData []byte, width, height int, sessionID string) ([]byte, int, int, bool) {
+	if !h.frameLimit.Enabled() {
+		return frameData, width, height, true
 	}
 
+	data, width, height, err := h.frameLimit.Enforce(frameData)
+	if err != nil {
+		log.Printf("❌ FRAME RESOLUTION: Dropping frame for session %s: %v", sessionID, err)
+		return nil, 0, 0, false
+	}
+	return data, width, height, true
+}
+
+// handleSessionRejected maneja cuando el cliente rechaza una sesión de control remoto
+func (h *WebSocketHandler) handleSessionRejected(conn messageWriter, clientConn *ClientConnection, data interface{}) {
+	// Parse session rejected message
 	var rejectedMsg struct {
 		SessionID string `json:"session_id"`
 		Reason    string `json:"reason"`
 	}
-	if err := json.Unmarshal(sessionData, &rejectedMsg); err != nil {
-		log.Printf("Error unmarshalling session rejected message: %v", err)
+	if !decodePayload(conn, "session_rejected", data, &rejectedMsg) {
 		return
 	}
 
 	log.Printf("❌ Client %s rejected remote control session: %s (reason: %s)",
 		clientConn.PCID, rejectedMsg.SessionID, rejectedMsg.Reason)
 
-	// TODO: Actualizar estado de sesión en base de datos a ENDED_BY_CLIENT
-	// TODO: Notificar al administrador que la sesión fue rechazada
+	// Actualizar estado de sesión en base de datos a REJECTED
+	err := h.sessionService.RejectSession(clientConn.Context(), rejectedMsg.SessionID, rejectedMsg.Reason)
+	if err != nil {
+		if errors.Is(err, remotesessionservice.ErrSessionAlreadyDecided) {
+			// La sesión ya fue aceptada o rechazada: el rechazo tardío no la modifica
+			log.Printf("⚠️ Ignoring late rejection from client %s: %v", clientConn.PCID, err)
+		} else if errors.Is(err, remotesessionservice.ErrSessionApprovalExpired) {
+			log.Printf("⌛ Ignoring expired rejection from client %s: %v", clientConn.PCID, err)
+		} else {
+			log.Printf("❌ Error rejecting session in service: %v", err)
+		}
+
+		errorMsg := dto.WebSocketMessage{
+			Type: "session_failed",
+			Data: map[string]interface{}{
+				"session_id": rejectedMsg.SessionID,
+				"error":      "Failed to reject session",
+				"message":    err.Error(),
+			},
+		}
+		conn.WriteJSON(errorMsg)
+		return
+	}
+
+	log.Printf("✅ Session %s marked as rejected in database", rejectedMsg.SessionID)
+
+	// Notificar al administrador que la sesión fue rechazada
+	if h.adminWSHandler != nil {
+		err = h.adminWSHandler.NotifySessionRejected(clientConn.Context(), rejectedMsg.SessionID, rejectedMsg.Reason)
+		if err != nil {
+			log.Printf("⚠️ Warning: Failed to notify admin of session rejection: %v", err)
+		}
+	}
 }
 
 // handleVideoChunkUpload maneja la subida de chunks de video
-func (h *WebSocketHandler) handleVideoChunkUpload(conn *websocket.Conn, clientConn *ClientConnection, data interface{}) {
+func (h *WebSocketHandler) handleVideoChunkUpload(conn messageWriter, clientConn *ClientConnection, data interface{}) {
+	// Parsear datos del chunk de video
+	var videoChunk dto.VideoChunk
+	if !decodePayload(conn, "video_chunk_upload", data, &videoChunk) {
+		return
+	}
+
+	// Decodificar chunk data de base64 a bytes
+	chunkBytes, err := base64.StdEncoding.DecodeString(videoChunk.ChunkData)
+	if err != nil {
+		log.Printf("❌ VIDEO CHUNK UPLOAD: Error decoding chunk data: %v", err)
+
+		errorResponse := dto.WebSocketMessage{
+			Type: "video_upload_error",
+			Data: map[string]interface{}{
+				"video_id": videoChunk.VideoID,
+				"error":    "Error decoding chunk data",
+			},
+		}
+		conn.WriteJSON(errorResponse)
+		return
+	}
+
+	h.processVideoChunk(conn, clientConn, videoChunk, chunkBytes)
+}
+
+// processVideoChunk procesa un chunk de video ya decodificado, recibido por JSON o en binario
+func (h *WebSocketHandler) processVideoChunk(conn messageWriter, clientConn *ClientConnection, videoChunk dto.VideoChunk, chunkBytes []byte) {
 	// Verificar autenticación
 	if !clientConn.IsAuth {
 		log.Printf("❌ VIDEO CHUNK UPLOAD: Unauthorized client attempted video upload")
@@ -536,52 +1251,28 @@ func (h *WebSocketHandler) handleVideoChunkUpload(conn *websocket.Conn, clientCo
 		return
 	}
 
-	// Parsear datos del chunk de video
-	chunkData, err := json.Marshal(data)
-	if err != nil {
-		log.Printf("❌ VIDEO CHUNK UPLOAD: Error marshalling chunk data: %v", err)
-		return
-	}
+	log.Printf("📹 VIDEO CHUNK UPLOAD: Received video chunk %d from PC %s (video: %s)",
+		videoChunk.ChunkIndex, clientConn.PCID, videoChunk.VideoID)
 
-	var videoChunk dto.VideoChunk
-	if err := json.Unmarshal(chunkData, &videoChunk); err != nil {
-		log.Printf("❌ VIDEO CHUNK UPLOAD: Error unmarshalling video chunk: %v", err)
+	if !h.isServerRecordingEnabled(conn, clientConn, videoChunk.SessionID, videoChunk.VideoID) {
 		return
 	}
 
-	log.Printf("📹 VIDEO CHUNK UPLOAD: Received video chunk %d from PC %s (video: %s)",
-		videoChunk.ChunkIndex, clientConn.PCID, videoChunk.VideoID)
-
 	// 🚀 PROCESAR CHUNK REAL USANDO VIDEOSERVICE
 	if h.videoService != nil {
-		// Decodificar chunk data de base64 a bytes
-		chunkData, err := base64.StdEncoding.DecodeString(videoChunk.ChunkData)
-		if err != nil {
-			log.Printf("❌ VIDEO CHUNK UPLOAD: Error decoding chunk data: %v", err)
-
-			errorResponse := dto.WebSocketMessage{
-				Type: "video_upload_error",
-				Data: map[string]interface{}{
-					"video_id": videoChunk.VideoID,
-					"error":    "Error decoding chunk data",
-				},
-			}
-			conn.WriteJSON(errorResponse)
-			return
-		}
-
 		// Convertir a formato del VideoService
 		serviceChunk := videoservice.VideoChunk{
 			VideoID:     videoChunk.VideoID,
 			SessionID:   videoChunk.SessionID,
 			ChunkIndex:  videoChunk.ChunkIndex,
-			ChunkData:   chunkData, // Ahora es []byte
+			ChunkData:   chunkBytes,
 			IsLastChunk: videoChunk.IsLastChunk,
 			FileSize:    videoChunk.FileSize,
 			Duration:    videoChunk.Duration,
 			FileName:    videoChunk.FileName,
+			PCID:        clientConn.PCID,
+			UserID:      clientConn.UserID,
 		}
-
 		// Procesar chunk usando VideoService (sin type cast necesario)
 		result, err := h.videoService.(videoservice.IVideoService).HandleUploadedVideoChunk(serviceChunk)
 		if err != nil {
@@ -671,32 +1362,60 @@ func (h *WebSocketHandler) handleVideoChunkUpload(conn *websocket.Conn, clientCo
 	}
 }
 
-// handleVideoUploadComplete handles video upload completion
-func (h *WebSocketHandler) handleVideoUploadComplete(conn *websocket.Conn, clientConn *ClientConnection, data interface{}) {
+// handleVideoUploadComplete finaliza explícitamente una subida por chunks. El video se persiste una única vez
+// en videoService: si el último chunk ya lo completó, solo se confirma; si no, se completa aquí.
+func (h *WebSocketHandler) handleVideoUploadComplete(conn messageWriter, clientConn *ClientConnection, data interface{}) {
 	// Parse video upload completion message
-	completionData, err := json.Marshal(data)
-	if err != nil {
-		log.Printf("Error marshalling video upload completion data: %v", err)
+	var completionMsg struct {
+		VideoID string `json:"video_id"`
+	}
+	if !decodePayload(conn, "video_upload_complete", data, &completionMsg) {
 		return
 	}
 
-	var completionMsg struct {
-		VideoID string `json:"video_id"`
+	if !clientConn.IsAuth {
+		log.Printf("❌ VIDEO UPLOAD COMPLETE: Unauthorized client attempted to complete video %s", completionMsg.VideoID)
+		return
+	}
+
+	videoService, ok := h.videoService.(videoservice.IVideoService)
+	if !ok || videoService == nil {
+		log.Printf("❌ VIDEO UPLOAD COMPLETE: VideoService not available")
+		conn.WriteJSON(dto.WebSocketMessage{
+			Type: "video_upload_error",
+			Data: map[string]interface{}{
+				"video_id": completionMsg.VideoID,
+				"error":    "Video service not available",
+			},
+		})
+		return
 	}
-	if err := json.Unmarshal(completionData, &completionMsg); err != nil {
-		log.Printf("Error unmarshalling video upload completion message: %v", err)
+
+	// Solo el PC registrado en esta conexión y su usuario pueden completar sus propias subidas
+	result, err := videoService.CompleteVideoUpload(completionMsg.VideoID, clientConn.PCID, clientConn.UserID)
+	if err != nil {
+		log.Printf("❌ VIDEO UPLOAD COMPLETE: Error completing video %s: %v", completionMsg.VideoID, err)
+		conn.WriteJSON(dto.WebSocketMessage{
+			Type: "video_upload_error",
+			Data: map[string]interface{}{
+				"video_id": completionMsg.VideoID,
+				"error":    err.Error(),
+			},
+		})
 		return
 	}
 
-	log.Printf("🎉 Video %s upload completed", completionMsg.VideoID)
+	log.Printf("🎉 Video %s upload completed (already persisted: %t)", completionMsg.VideoID, result.AlreadyCompleted)
 
 	// Send confirmation to client
 	completionConfirmedMsg := dto.WebSocketMessage{
 		Type: "video_upload_completed_confirmed",
 		Data: map[string]interface{}{
-			"video_id":  completionMsg.VideoID,
-			"message":   "Video upload completed and confirmed",
-			"timestamp": time.Now().Unix(),
+			"video_id":          completionMsg.VideoID,
+			"file_path":         result.FilePath,
+			"already_completed": result.AlreadyCompleted,
+			"message":           "Video upload completed and confirmed",
+			"timestamp":         time.Now().Unix(),
 		},
 	}
 
@@ -708,74 +1427,321 @@ func (h *WebSocketHandler) handleVideoUploadComplete(conn *websocket.Conn, clien
 	}
 }
 
+// handleVideoUploadStatus responde qué chunks de una subida ya tiene el servidor, para que tras reconectar
+// el cliente reenvíe solo los que faltan
+func (h *WebSocketHandler) handleVideoUploadStatus(conn messageWriter, clientConn *ClientConnection, data interface{}) {
+	var statusReq struct {
+		VideoID string `json:"video_id"`
+	}
+	if !decodePayload(conn, "video_upload_status", data, &statusReq) {
+		return
+	}
+
+	if !clientConn.IsAuth {
+		log.Printf("❌ VIDEO UPLOAD STATUS: Unauthorized client queried video %s", statusReq.VideoID)
+		return
+	}
+
+	videoService, ok := h.videoService.(videoservice.IVideoService)
+	if !ok || videoService == nil {
+		conn.WriteJSON(dto.WebSocketMessage{
+			Type: "video_upload_error",
+			Data: map[string]interface{}{
+				"video_id": statusReq.VideoID,
+				"error":    "Video service not available",
+			},
+		})
+		return
+	}
+
+	status, err := videoService.GetVideoUploadStatus(statusReq.VideoID, clientConn.PCID)
+	if err != nil {
+		log.Printf("⚠️ VIDEO UPLOAD STATUS: No upload for video %s from PC %s: %v", statusReq.VideoID, clientConn.PCID, err)
+		conn.WriteJSON(dto.WebSocketMessage{
+			Type: "video_upload_error",
+			Data: map[string]interface{}{
+				"video_id": statusReq.VideoID,
+				"error":    err.Error(),
+			},
+		})
+		return
+	}
+
+	log.Printf("📹 VIDEO UPLOAD STATUS: Video %s has %d/%d chunks (PC %s)",
+		statusReq.VideoID, len(status.ReceivedChunks), status.TotalChunks, clientConn.PCID)
+
+	conn.WriteJSON(dto.WebSocketMessage{
+		Type: "video_upload_status_response",
+		Data: dto.VideoUploadStatus{
+			VideoID:        status.VideoID,
+			SessionID:      status.SessionID,
+			TotalChunks:    status.TotalChunks,
+			ReceivedChunks: status.ReceivedChunks,
+			MissingChunks:  status.MissingChunks,
+			IsComplete:     status.IsComplete,
+		},
+	})
+}
+
 // handleVideoFrameUpload handles individual JPEG frame uploads for the new frame-based recording system
-func (h *WebSocketHandler) handleVideoFrameUpload(conn *websocket.Conn, clientConn *ClientConnection, data interface{}) {
+func (h *WebSocketHandler) handleVideoFrameUpload(conn messageWriter, clientConn *ClientConnection, data interface{}) {
+	// Parsear datos del frame de video
+	var videoFrame dto.VideoFrameUpload
+	if !decodePayload(conn, "video_frame_upload", data, &videoFrame) {
+		return
+	}
+
+	// Decodificar frame data de base64 a bytes
+	frameBytes, err := base64.StdEncoding.DecodeString(videoFrame.FrameData)
+	if err != nil {
+		log.Printf("❌ VIDEO FRAME UPLOAD: Error decoding frame data: %v", err)
+		return
+	}
+
+	h.saveVideoFrame(conn, clientConn, videoFrame, frameBytes)
+}
+
+// saveVideoFrame guarda un frame de grabación ya decodificado, recibido por JSON o en binario
+func (h *WebSocketHandler) saveVideoFrame(conn messageWriter, clientConn *ClientConnection, videoFrame dto.VideoFrameUpload, frameBytes []byte) {
+	log.Printf("📸 VIDEO FRAME UPLOAD: Received frame %d from PC %s (session: %s, video: %s)",
+		videoFrame.FrameIndex, clientConn.PCID, videoFrame.SessionID, videoFrame.VideoID)
+
+	if !h.acceptFrameData(clientConn, videoFrame.SessionID, frameBytes) {
+		return
+	}
+
+	h.runVideoFrameJob(clientConn, func() {
+		if !h.canAcceptVideoFrames(conn, clientConn, videoFrame.SessionID, videoFrame.VideoID) {
+			return
+		}
+		h.storeVideoFrame(conn, clientConn, videoFrame, frameBytes)
+	})
+}
+
+// runVideoFrameJob guarda frames de grabación en el pipeline de la conexión si los frames se reducen de
+// resolución, o directamente si no
+func (h *WebSocketHandler) runVideoFrameJob(clientConn *ClientConnection, job func()) {
+	if pipeline := h.framePipelineFor(clientConn); pipeline != nil {
+		pipeline.enqueue(job)
+		return
+	}
+	job()
+}
+
+// canAcceptVideoFrames comprueba autenticación, permiso, feature flag y cuota antes de guardar frames de una grabación
+func (h *WebSocketHandler) canAcceptVideoFrames(conn messageWriter, clientConn *ClientConnection, sessionID, videoID string) bool {
 	// Verificar autenticación
 	if !clientConn.IsAuth {
 		log.Printf("❌ VIDEO FRAME UPLOAD: Unauthorized client attempted frame upload")
-		return
+		return false
+	}
+
+	// Verificar que tenemos videoService
+	if h.videoService == nil {
+		log.Printf("❌ VIDEO FRAME UPLOAD: VideoService not available")
+		return false
 	}
 
-	// Parsear datos del frame de video
-	frameData, err := json.Marshal(data)
-	if err != nil {
-		log.Printf("❌ VIDEO FRAME UPLOAD: Error marshalling frame data: %v", err)
+	if !h.hasRecordingPermission(conn, clientConn, sessionID, videoID) {
+		return false
+	}
+
+	if !h.isServerRecordingEnabled(conn, clientConn, sessionID, videoID) {
+		return false
+	}
+
+	// Una grabación rechazada por capacidad no se empieza a mitad aunque después quede hueco
+	if refused, _ := clientConn.recordingState.lookup(sessionID, recordingCapacityKey+videoID); refused {
+		return false
+	}
+
+	// Verificar cuota de almacenamiento antes de aceptar una nueva grabación
+	return h.isRecordingWithinQuota(conn, clientConn, sessionID, videoID)
+}
+
+// storeVideoFrame guarda un frame ya validado por canAcceptVideoFrames. Retorna false si la grabación
+// no admite más frames (límite alcanzado o error de almacenamiento).
+func (h *WebSocketHandler) storeVideoFrame(conn messageWriter, clientConn *ClientConnection, videoFrame dto.VideoFrameUpload, frameBytes []byte) bool {
+	// Aplicar la resolución máxima antes de guardar el frame
+	frameBytes, _, _, ok := h.enforceFrameResolution(frameBytes, 0, 0, videoFrame.SessionID)
+	if !ok {
+		return true
+	}
+
+	// Procesar frame usando VideoService
+	frameInfo := videoservice.VideoFrameInfo{
+		VideoID:    videoFrame.VideoID,
+		SessionID:  videoFrame.SessionID,
+		FrameIndex: videoFrame.FrameIndex,
+		Timestamp:  videoFrame.Timestamp,
+		FrameData:  frameBytes,
+	}
+
+	err := h.videoService.(videoservice.IVideoService).SaveVideoFrame(frameInfo)
+	if errors.Is(err, videoservice.ErrFrameLimitReached) {
+		h.notifyRecordingLimitReached(conn, clientConn, videoFrame.SessionID, videoFrame.VideoID)
+		return false
+	}
+	if errors.Is(err, videoservice.ErrRecordingCapacityReached) {
+		h.refuseRecordingOverCapacity(conn, clientConn, videoFrame.SessionID, videoFrame.VideoID, err)
+		return false
+	}
+	if errors.Is(err, videoservice.ErrVideoSessionMismatch) {
+		log.Printf("🚫 VIDEO FRAME UPLOAD: Frame %d from PC %s rejected: %v", videoFrame.FrameIndex, clientConn.PCID, err)
+		return false
+	}
+	if err != nil {
+		log.Printf("❌ VIDEO FRAME UPLOAD: Error saving frame %d: %v", videoFrame.FrameIndex, err)
+		return false
+	}
+
+	if clientConn.recordingSessions == nil {
+		clientConn.recordingSessions = make(map[string]bool)
+	}
+	clientConn.recordingSessions[videoFrame.SessionID] = true
+
+	// Solo loguear cada 30 frames para no saturar
+	if videoFrame.FrameIndex%30 == 0 || videoFrame.FrameIndex == 0 {
+		log.Printf("✅ VIDEO FRAME UPLOAD: Frame %d saved successfully for video %s",
+			videoFrame.FrameIndex, videoFrame.VideoID)
+	}
+	return true
+}
+
+// isServerRecordingEnabled consulta el flag server_side_recording. Si está desactivado la grabación no se
+// guarda y se informa al cliente (una vez por grabación) para que deje de enviar frames.
+func (h *WebSocketHandler) isServerRecordingEnabled(conn messageWriter, clientConn *ClientConnection, sessionID, videoID string) bool {
+	if h.featureFlags == nil || h.featureFlags.ServerSideRecording() {
+		return true
+	}
+
+	if !clientConn.recordingState.markOnce(sessionID, recordingDisabledKey+videoID) {
+		return false
+	}
+
+	log.Printf("🚩 VIDEO RECORDING: Server-side recording disabled by feature flag, ignoring video %s from PC %s", videoID, clientConn.PCID)
+
+	conn.WriteJSON(dto.WebSocketMessage{
+		Type: "video_recording_rejected",
+		Data: map[string]interface{}{
+			"session_id": sessionID,
+			"video_id":   videoID,
+			"error_code": "SERVER_RECORDING_DISABLED",
+			"error":      "Server-side recording is disabled",
+		},
+	})
+	return false
+}
+
+// hasRecordingPermission verifica que la sesión de la grabación pertenece al PC de esta conexión y sigue
+// activa (o terminó hace poco), para que un cliente no pueda escribir en la grabación de otra sesión.
+// Un permiso concedido se recuerda por grabación durante el periodo de gracia, así que no se consulta la sesión
+// en cada frame: se vuelve a validar cuando vence o cuando la sesión termina (SendSessionEndedToClient).
+// Si no, descarta el mensaje e informa al cliente (una vez por sesión).
+func (h *WebSocketHandler) hasRecordingPermission(conn messageWriter, clientConn *ClientConnection, sessionID, videoID string) bool {
+	if h.sessionService == nil {
+		return true
+	}
+
+	now := time.Now()
+	if clientConn.recordingState.permitted(sessionID, videoID, now) {
+		return true
+	}
+
+	err := h.sessionService.ValidateRecordingPermission(clientConn.Context(), sessionID, clientConn.PCID)
+	if err == nil {
+		clientConn.recordingState.permit(sessionID, videoID, now.Add(h.sessionService.RecordingGracePeriod()))
+		return true
+	}
+
+	if !clientConn.recordingState.markOnce(sessionID, recordingPermissionKey) {
+		return false
+	}
+
+	log.Printf("🚫 VIDEO RECORDING: PC %s cannot write video %s of session %s: %v", clientConn.PCID, videoID, sessionID, err)
+
+	conn.WriteJSON(dto.WebSocketMessage{
+		Type: "video_recording_rejected",
+		Data: map[string]interface{}{
+			"session_id": sessionID,
+			"video_id":   videoID,
+			"error_code": "RECORDING_NOT_PERMITTED",
+			"error":      "Recording not permitted for this session",
+		},
+	})
+	return false
+}
+
+// notifyRecordingLimitReached informa al cliente (una vez por grabación) que debe dejar de enviar frames
+func (h *WebSocketHandler) notifyRecordingLimitReached(conn messageWriter, clientConn *ClientConnection, sessionID, videoID string) {
+	if !clientConn.recordingState.markOnce(sessionID, recordingLimitKey+videoID) {
 		return
 	}
 
-	var videoFrame struct {
-		SessionID  string `json:"session_id"`
-		VideoID    string `json:"video_id"`
-		FrameIndex int    `json:"frame_index"`
-		Timestamp  int64  `json:"timestamp"`
-		FrameData  string `json:"frame_data"` // Base64 encoded JPEG
+	log.Printf("⏹️ VIDEO FRAME UPLOAD: Recording %s reached the frame limit, recording finalized", videoID)
+
+	conn.WriteJSON(dto.WebSocketMessage{
+		Type: "recording_limit_reached",
+		Data: map[string]interface{}{
+			"session_id": sessionID,
+			"video_id":   videoID,
+			"error_code": "RECORDING_FRAME_LIMIT_REACHED",
+		},
+	})
+}
+
+// isRecordingWithinQuota verifica (una vez por grabación) que el cliente no haya excedido su cuota.
+// Si la excede, rechaza la grabación, informa al cliente y notifica al administrador de la sesión.
+func (h *WebSocketHandler) isRecordingWithinQuota(conn messageWriter, clientConn *ClientConnection, sessionID, videoID string) bool {
+	if h.storageQuota == nil || clientConn.PCID == "" {
+		return true
 	}
 
-	if err := json.Unmarshal(frameData, &videoFrame); err != nil {
-		log.Printf("❌ VIDEO FRAME UPLOAD: Error unmarshalling frame data: %v", err)
-		return
+	quotaKey := recordingQuotaKey + videoID
+	if allowed, checked := clientConn.recordingState.lookup(sessionID, quotaKey); checked {
+		return allowed
 	}
 
-	log.Printf("📸 VIDEO FRAME UPLOAD: Received frame %d from PC %s (session: %s, video: %s)",
-		videoFrame.FrameIndex, clientConn.PCID, videoFrame.SessionID, videoFrame.VideoID)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	// Verificar que tenemos videoService
-	if h.videoService == nil {
-		log.Printf("❌ VIDEO FRAME UPLOAD: VideoService not available")
-		return
+	usage, err := h.storageQuota.CheckQuota(ctx, clientConn.PCID, 0)
+	if err == nil {
+		clientConn.recordingState.store(sessionID, quotaKey, true)
+		return true
 	}
 
-	// Decodificar frame data de base64 a bytes
-	frameBytes, err := base64.StdEncoding.DecodeString(videoFrame.FrameData)
-	if err != nil {
-		log.Printf("❌ VIDEO FRAME UPLOAD: Error decoding frame data: %v", err)
-		return
+	if !errors.Is(err, storagequotaservice.ErrStorageQuotaExceeded) {
+		// No bloquear la grabación por errores al calcular el uso
+		log.Printf("⚠️ VIDEO FRAME UPLOAD: Error checking storage quota for PC %s: %v", clientConn.PCID, err)
+		clientConn.recordingState.store(sessionID, quotaKey, true)
+		return true
 	}
 
-	// Procesar frame usando VideoService
-	frameInfo := videoservice.VideoFrameInfo{
-		VideoID:    videoFrame.VideoID,
-		SessionID:  videoFrame.SessionID,
-		FrameIndex: videoFrame.FrameIndex,
-		Timestamp:  videoFrame.Timestamp,
-		FrameData:  frameBytes,
-	}
+	clientConn.recordingState.store(sessionID, quotaKey, false)
+	log.Printf("❌ VIDEO FRAME UPLOAD: Recording %s rejected, %v", videoID, err)
 
-	err = h.videoService.(videoservice.IVideoService).SaveVideoFrame(frameInfo)
-	if err != nil {
-		log.Printf("❌ VIDEO FRAME UPLOAD: Error saving frame %d: %v", videoFrame.FrameIndex, err)
-		return
-	}
+	conn.WriteJSON(dto.WebSocketMessage{
+		Type: "video_recording_rejected",
+		Data: map[string]interface{}{
+			"session_id": sessionID,
+			"video_id":   videoID,
+			"error_code": "STORAGE_QUOTA_EXCEEDED",
+			"error":      err.Error(),
+		},
+	})
 
-	// Solo loguear cada 30 frames para no saturar
-	if videoFrame.FrameIndex%30 == 0 || videoFrame.FrameIndex == 0 {
-		log.Printf("✅ VIDEO FRAME UPLOAD: Frame %d saved successfully for video %s",
-			videoFrame.FrameIndex, videoFrame.VideoID)
+	if h.adminWSHandler != nil {
+		if session, sessErr := h.sessionService.GetSessionById(ctx, sessionID); sessErr == nil && session != nil {
+			h.adminWSHandler.NotifyStorageQuotaExceeded(session.AdminUserID(), sessionID, usage)
+		}
 	}
+
+	return false
 }
 
 // handleVideoRecordingComplete handles the completion metadata for frame-based recordings
-func (h *WebSocketHandler) handleVideoRecordingComplete(conn *websocket.Conn, clientConn *ClientConnection, data interface{}) {
+func (h *WebSocketHandler) handleVideoRecordingComplete(conn messageWriter, clientConn *ClientConnection, data interface{}) {
 	// Verificar autenticación
 	if !clientConn.IsAuth {
 		log.Printf("❌ VIDEO RECORDING COMPLETE: Unauthorized client attempted to complete recording")
@@ -783,12 +1749,6 @@ func (h *WebSocketHandler) handleVideoRecordingComplete(conn *websocket.Conn, cl
 	}
 
 	// Parsear datos de finalización
-	completionData, err := json.Marshal(data)
-	if err != nil {
-		log.Printf("❌ VIDEO RECORDING COMPLETE: Error marshalling completion data: %v", err)
-		return
-	}
-
 	var recordingComplete struct {
 		VideoID         string  `json:"video_id"`
 		SessionID       string  `json:"session_id"`
@@ -797,9 +1757,7 @@ func (h *WebSocketHandler) handleVideoRecordingComplete(conn *websocket.Conn, cl
 		DurationSeconds float64 `json:"duration_seconds"`
 		Timestamp       int64   `json:"timestamp"`
 	}
-
-	if err := json.Unmarshal(completionData, &recordingComplete); err != nil {
-		log.Printf("❌ VIDEO RECORDING COMPLETE: Error unmarshalling completion data: %v", err)
+	if !decodePayload(conn, "video_recording_complete", data, &recordingComplete) {
 		return
 	}
 
@@ -812,6 +1770,14 @@ func (h *WebSocketHandler) handleVideoRecordingComplete(conn *websocket.Conn, cl
 		return
 	}
 
+	if !h.hasRecordingPermission(conn, clientConn, recordingComplete.SessionID, recordingComplete.VideoID) {
+		return
+	}
+
+	if !h.isServerRecordingEnabled(conn, clientConn, recordingComplete.SessionID, recordingComplete.VideoID) {
+		return
+	}
+
 	// Procesar finalización usando VideoService
 	recordingInfo := videoservice.VideoRecordingMetadata{
 		VideoID:         recordingComplete.VideoID,
@@ -820,9 +1786,14 @@ func (h *WebSocketHandler) handleVideoRecordingComplete(conn *websocket.Conn, cl
 		FPS:             recordingComplete.FPS,
 		DurationSeconds: recordingComplete.DurationSeconds,
 		CompletedAt:     time.Now(),
+		ClientTimestamp: recordingComplete.Timestamp,
 	}
 
-	err = h.videoService.(videoservice.IVideoService).FinalizeVideoRecording(recordingInfo)
+	err := h.videoService.(videoservice.IVideoService).FinalizeVideoRecording(recordingInfo)
+	if errors.Is(err, videoservice.ErrRecordingEmpty) {
+		log.Printf("⏭️ VIDEO RECORDING COMPLETE: Recording %s has no valid frames, not saved", recordingComplete.VideoID)
+		return
+	}
 	if err != nil {
 		log.Printf("❌ VIDEO RECORDING COMPLETE: Error finalizing recording: %v", err)
 		return
@@ -853,17 +1824,10 @@ func (h *WebSocketHandler) handleVideoRecordingComplete(conn *websocket.Conn, cl
 }
 
 // handleFileTransferAcknowledgement handles file transfer acknowledgement messages
-func (h *WebSocketHandler) handleFileTransferAcknowledgement(conn *websocket.Conn, clientConn *ClientConnection, data interface{}) {
+func (h *WebSocketHandler) handleFileTransferAcknowledgement(conn messageWriter, clientConn *ClientConnection, data interface{}) {
 	// Parse file transfer acknowledgement message
-	ackData, err := json.Marshal(data)
-	if err != nil {
-		log.Printf("Error marshalling file transfer acknowledgement data: %v", err)
-		return
-	}
-
 	var ackMsg dto.FileTransferAcknowledgement
-	if err := json.Unmarshal(ackData, &ackMsg); err != nil {
-		log.Printf("Error unmarshalling file transfer acknowledgement message: %v", err)
+	if !decodePayload(conn, "file_transfer_ack", data, &ackMsg) {
 		return
 	}
 
@@ -879,6 +1843,18 @@ func (h *WebSocketHandler) handleFileTransferAcknowledgement(conn *websocket.Con
 		// Cliente está listo para recibir el archivo
 		log.Printf("📡 Client ready for transfer %s", ackMsg.TransferID)
 
+		h.transferHandshakeMutex.Lock()
+		readyChan, exists := h.transferReady[ackMsg.TransferID]
+		if exists {
+			delete(h.transferReady, ackMsg.TransferID)
+		}
+		h.transferHandshakeMutex.Unlock()
+
+		if exists {
+			// El canal tiene buffer de 1, el envío nunca bloquea el loop de lectura
+			readyChan <- ackMsg
+		}
+
 	case "CHUNK_RECEIVED":
 		// Cliente confirmó recepción de un chunk
 		log.Printf("📦 Chunk %d received by client for transfer %s", ackMsg.ChunkNumber, ackMsg.TransferID)
@@ -924,7 +1900,9 @@ func (h *WebSocketHandler) handleFileTransferAcknowledgement(conn *websocket.Con
 			filetransfer.TransferStatusFailed,
 			errorMsg,
 		)
-		if err != nil {
+		if errors.Is(err, filetransferservice.ErrFailedAfterCompletion) {
+			log.Printf("⚠️ Transfer %s was already completed, client failure audited: %s", ackMsg.TransferID, errorMsg)
+		} else if err != nil {
 			log.Printf("Error updating transfer status to FAILED: %v", err)
 		} else {
 			log.Printf("❌ Transfer %s failed: %s", ackMsg.TransferID, errorMsg)
@@ -941,22 +1919,124 @@ func (h *WebSocketHandler) handleFileTransferAcknowledgement(conn *websocket.Con
 	}
 }
 
+// handleStorageQueryResponse entrega la respuesta de espacio libre a la transferencia que la está esperando
+func (h *WebSocketHandler) handleStorageQueryResponse(conn messageWriter, clientConn *ClientConnection, data interface{}) {
+	var queryResponse dto.StorageQueryResponse
+	if !decodePayload(conn, "storage_query_response", data, &queryResponse) {
+		return
+	}
+
+	h.transferHandshakeMutex.Lock()
+	responseChan, exists := h.storageQueries[queryResponse.TransferID]
+	if exists {
+		delete(h.storageQueries, queryResponse.TransferID)
+	}
+	h.transferHandshakeMutex.Unlock()
+
+	if !exists {
+		log.Printf("⚠️ STORAGE QUERY: Unexpected response for transfer %s from client %s", queryResponse.TransferID, clientConn.PCID)
+		return
+	}
+
+	// El canal tiene buffer de 1, el envío nunca bloquea el loop de lectura
+	responseChan <- queryResponse
+}
+
+// ensureClientHasSpace consulta al cliente su espacio libre en la ruta de destino y aborta la transferencia si no alcanza.
+// Si el cliente no responde a tiempo o no puede determinar su espacio, la transferencia continúa como antes.
+func (h *WebSocketHandler) ensureClientHasSpace(clientConn *ClientConnection, transfer *filetransfer.FileTransfer, requiredBytes int64) error {
+	responseChan := make(chan dto.StorageQueryResponse, 1)
+
+	h.transferHandshakeMutex.Lock()
+	h.storageQueries[transfer.TransferID()] = responseChan
+	h.transferHandshakeMutex.Unlock()
+
+	defer func() {
+		h.transferHandshakeMutex.Lock()
+		delete(h.storageQueries, transfer.TransferID())
+		h.transferHandshakeMutex.Unlock()
+	}()
+
+	message := dto.WebSocketMessage{
+		Type: "storage_query",
+		Data: dto.StorageQuery{
+			Type:            "storage_query",
+			TransferID:      transfer.TransferID(),
+			SessionID:       transfer.AssociatedSessionID(),
+			DestinationPath: transfer.DestinationPathClient(),
+			RequiredBytes:   requiredBytes,
+			Timestamp:       time.Now().Unix(),
+		},
+	}
+
+	if err := clientConn.writer().WriteJSON(message); err != nil {
+		return fmt.Errorf("error sending storage query: %w", err)
+	}
+
+	var queryResponse dto.StorageQueryResponse
+	select {
+	case queryResponse = <-responseChan:
+	case <-time.After(h.storageQueryTimeout):
+		log.Printf("⚠️ STORAGE QUERY: No response from client %s for transfer %s, continuing without space check",
+			transfer.TargetPCID(), transfer.TransferID())
+		return nil
+	}
+
+	if queryResponse.ErrorMessage != "" {
+		log.Printf("⚠️ STORAGE QUERY: Client %s could not determine free space for transfer %s: %s",
+			transfer.TargetPCID(), transfer.TransferID(), queryResponse.ErrorMessage)
+		return nil
+	}
+
+	if queryResponse.AvailableBytes >= requiredBytes {
+		log.Printf("💾 STORAGE QUERY: Client %s has %d bytes available, %d required for transfer %s",
+			transfer.TargetPCID(), queryResponse.AvailableBytes, requiredBytes, transfer.TransferID())
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errorMsg := fmt.Sprintf("Espacio insuficiente en el cliente: %d bytes disponibles, %d requeridos",
+		queryResponse.AvailableBytes, requiredBytes)
+	if err := h.fileTransferService.UpdateTransferStatus(
+		ctx,
+		transfer.TransferID(),
+		filetransfer.TransferStatusInsufficientClientSpace,
+		errorMsg,
+	); err != nil {
+		log.Printf("Error updating transfer status to INSUFFICIENT_CLIENT_SPACE: %v", err)
+	}
+
+	log.Printf("❌ STORAGE QUERY: Transfer %s aborted: %s", transfer.TransferID(), errorMsg)
+	return fmt.Errorf("%w: %d bytes available, %d required", ErrInsufficientClientSpace, queryResponse.AvailableBytes, requiredBytes)
+}
+
 // Helper methods for sending responses
 
-func (h *WebSocketHandler) sendAuthResponse(conn *websocket.Conn, success bool, token, userID, errorMsg string) {
+func (h *WebSocketHandler) sendAuthResponse(conn messageWriter, success bool, token, userID, errorMsg string, capabilities []string, protocolVersion string) {
+	authResp := dto.ClientAuthResponse{
+		Success:         success,
+		Token:           token,
+		UserID:          userID,
+		Error:           errorMsg,
+		Capabilities:    capabilities,
+		ProtocolVersion: protocolVersion,
+	}
+	// Cadencia de heartbeat que el cliente debe seguir para no superar el timeout del servidor
+	if success {
+		authResp.HeartbeatIntervalSeconds = int(h.heartbeat.Interval.Seconds())
+		authResp.HeartbeatTimeoutSeconds = int(h.heartbeat.StaleTimeout().Seconds())
+	}
+
 	response := dto.WebSocketMessage{
 		Type: dto.MessageTypeClientAuthResp,
-		Data: dto.ClientAuthResponse{
-			Success: success,
-			Token:   token,
-			UserID:  userID,
-			Error:   errorMsg,
-		},
+		Data: authResp,
 	}
 	conn.WriteJSON(response)
 }
 
-func (h *WebSocketHandler) sendPCRegistrationResponse(conn *websocket.Conn, success bool, pcID, errorMsg string) {
+func (h *WebSocketHandler) sendPCRegistrationResponse(conn messageWriter, success bool, pcID, errorMsg string) {
 	response := dto.WebSocketMessage{
 		Type: dto.MessageTypePCRegistrationResp,
 		Data: dto.PCRegistrationResponse{
@@ -980,25 +2060,75 @@ func (h *WebSocketHandler) GetConnectedPCs() map[string]*ClientConnection {
 	return result
 }
 
+// ConnectedPCIDs returns the IDs of the PCs that currently have a live WebSocket connection
+// or that sent a REST heartbeat within the heartbeat stale timeout
+func (h *WebSocketHandler) ConnectedPCIDs() []string {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	now := time.Now()
+	pcIDs := make([]string, 0, len(h.pcConnections)+len(h.restClients))
+	for pcID := range h.pcConnections {
+		pcIDs = append(pcIDs, pcID)
+	}
+	for pcID, lastSeen := range h.restClients {
+		if _, hasSocket := h.pcConnections[pcID]; !hasSocket && now.Sub(lastSeen) <= h.heartbeat.StaleTimeout() {
+			pcIDs = append(pcIDs, pcID)
+		}
+	}
+	return pcIDs
+}
+
 // Utility functions
 
-func getClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		return xff
+// getClientIP IP del cliente según gin: X-Forwarded-For y X-Real-IP solo se aceptan si la petición llega de un
+// proxy de confianza (TRUSTED_PROXIES, ver middleware.ParseTrustedProxies); si no, la dirección del socket
+func getClientIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// registrationIP IP elegida para registrar un PC y, si la hay, la discrepancia con la IP declarada
+type registrationIP struct {
+	IP          string
+	Discrepancy string
+}
+
+// resolveRegistrationIP normaliza la IP declarada por el cliente y la contrasta con la observada en el socket.
+// La declarada solo se usa si es válida y coincide con la observada, o si la observada no es pública
+// (servidor en la misma LAN o detrás de un proxy): una IP pública observada distinta prevalece siempre.
+func resolveRegistrationIP(reported, observed string) registrationIP {
+	observedIP, observedErr := clientpc.NormalizeIP(observed)
+	if observedErr != nil {
+		observedIP = observed
+	}
+
+	if strings.TrimSpace(reported) == "" {
+		return registrationIP{IP: observedIP}
+	}
+
+	reportedIP, err := clientpc.NormalizeIP(reported)
+	if err != nil {
+		return registrationIP{IP: observedIP, Discrepancy: "invalid reported IP"}
+	}
+
+	if observedErr != nil || reportedIP == observedIP {
+		return registrationIP{IP: reportedIP}
 	}
 
-	// Check X-Real-IP header
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return xri
+	if isPublicIP(observedIP) {
+		return registrationIP{IP: observedIP, Discrepancy: "reported IP does not match connection address"}
 	}
 
-	// Fall back to RemoteAddr
-	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	return registrationIP{IP: reportedIP}
+}
+
+// isPublicIP indica si la IP es enrutable en Internet (no privada, loopback, link-local ni sin especificar)
+func isPublicIP(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
 	if err != nil {
-		return r.RemoteAddr
+		return false
 	}
-	return host
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
 }
 
 func generateConnectionID() string {
@@ -1042,13 +2172,15 @@ func (h *WebSocketHandler) SendRemoteControlRequestToClient(sessionID, clientPCI
 			"admin_username": adminUsername,
 			"client_pc_id":   clientPCID,
 			"timestamp":      time.Now().Unix(),
+			// Segundos que el cliente tiene para responder; pasado ese tiempo el servidor rechaza la solicitud
+			"approval_timeout_seconds": int(h.approvalTimeout().Seconds()),
 		},
 	}
 
 	log.Printf("📡 REMOTE CONTROL: Sending message to client %s: %+v", clientPCID, remoteControlMsg)
 
 	// Enviar mensaje al cliente
-	err := clientConn.Conn.WriteJSON(remoteControlMsg)
+	err := clientConn.writer().WriteJSON(remoteControlMsg)
 	if err != nil {
 		log.Printf("❌ REMOTE CONTROL: Error sending to client %s: %v", clientPCID, err)
 		return err
@@ -1058,6 +2190,57 @@ func (h *WebSocketHandler) SendRemoteControlRequestToClient(sessionID, clientPCI
 	return nil
 }
 
+// approvalTimeout tiempo de aprobación configurado en el servicio de sesiones
+func (h *WebSocketHandler) approvalTimeout() time.Duration {
+	if h.sessionService == nil {
+		return remotesessionservice.DefaultSessionApprovalTimeout
+	}
+	return h.sessionService.ApprovalTimeout()
+}
+
+// SendAutoAcceptedSessionToClient indica al cliente que inicie el streaming de una sesión
+// aceptada automáticamente por la política del PC, sin mostrar la solicitud de control
+func (h *WebSocketHandler) SendAutoAcceptedSessionToClient(sessionID, clientPCID string) error {
+	h.mutex.RLock()
+	clientConn, exists := h.pcConnections[clientPCID]
+	h.mutex.RUnlock()
+
+	if !exists {
+		log.Printf("❌ AUTO-ACCEPT: Client PC %s not found in connections map", clientPCID)
+		return fmt.Errorf("client PC %s not connected", clientPCID)
+	}
+
+	sessionStartedMsg := dto.WebSocketMessage{
+		Type: "session_started",
+		Data: map[string]interface{}{
+			"session_id":    sessionID,
+			"status":        "ACTIVE",
+			"auto_accepted": true,
+			"message":       "Remote control session auto-accepted by PC policy",
+			"timestamp":     time.Now().Unix(),
+		},
+	}
+
+	err := clientConn.writer().WriteJSON(sessionStartedMsg)
+	if err != nil {
+		log.Printf("❌ AUTO-ACCEPT: Error sending session started to client %s: %v", clientPCID, err)
+		return err
+	}
+
+	log.Printf("✅ AUTO-ACCEPT: Session %s started on client %s without prompt", sessionID, clientPCID)
+	h.sendStreamConfig(clientConn.writer(), sessionID)
+	h.watchFirstFrame(sessionID, clientPCID)
+
+	// Notificar al administrador como si el cliente hubiera aceptado
+	if h.adminWSHandler != nil {
+		if err := h.adminWSHandler.NotifySessionAccepted(clientConn.Context(), sessionID); err != nil {
+			log.Printf("⚠️ Warning: Failed to notify admin of auto-accepted session: %v", err)
+		}
+	}
+
+	return nil
+}
+
 // SendInputCommandToClient envía un comando de input a un cliente específico
 func (h *WebSocketHandler) SendInputCommandToClient(clientPCID string, inputCommand dto.InputCommand) error {
 	log.Printf("🖱️ REMOTE CONTROL: Attempting to send input command to client PC: %s", clientPCID)
@@ -1073,6 +2256,10 @@ func (h *WebSocketHandler) SendInputCommandToClient(clientPCID string, inputComm
 
 	log.Printf("✅ INPUT COMMAND: Found client connection for PC: %s", clientPCID)
 
+	if !clientConn.supportsInputEvent(inputCommand.EventType) {
+		return fmt.Errorf("%w: %s", ErrInputEventNotSupported, inputCommand.EventType)
+	}
+
 	// Crear mensaje de comando de input
 	inputMsg := dto.WebSocketMessage{
 		Type: "input_command",
@@ -1082,7 +2269,7 @@ func (h *WebSocketHandler) SendInputCommandToClient(clientPCID string, inputComm
 	log.Printf("📡 INPUT COMMAND: Sending to client %s: %+v", clientPCID, inputMsg)
 
 	// Enviar mensaje al cliente
-	err := clientConn.Conn.WriteJSON(inputMsg)
+	err := clientConn.writer().WriteJSON(inputMsg)
 	if err != nil {
 		log.Printf("❌ INPUT COMMAND: Error sending to client %s: %v", clientPCID, err)
 		return err
@@ -1123,6 +2310,11 @@ func (h *WebSocketHandler) SendFileTransferRequestToClient(transfer *filetransfe
 	fileSize := int64(transfer.FileSizeMB() * 1024 * 1024)                   // Convertir MB a bytes
 	totalChunks := int((fileSize + int64(chunkSize) - 1) / int64(chunkSize)) // Redondear hacia arriba
 
+	// Verificar espacio libre en el cliente antes de enviar cualquier dato
+	if err := h.ensureClientHasSpace(clientConn, transfer, fileSize); err != nil {
+		return err
+	}
+
 	// Crear mensaje de solicitud de transferencia con estructura actualizada
 	request := dto.FileTransferRequest{
 		Type:            "file_transfer_request",
@@ -1133,16 +2325,43 @@ func (h *WebSocketHandler) SendFileTransferRequestToClient(transfer *filetransfe
 		FileSizeMB:      transfer.FileSizeMB(),
 		TotalChunks:     totalChunks,
 		DestinationPath: transfer.DestinationPathClient(),
+		ConflictPolicy:  string(transfer.ConflictPolicy()),
 		InitiatedBy:     transfer.InitiatingUserID(),
 		Timestamp:       time.Now().Unix(), // Unix timestamp
 	}
 
+	// Ofrecer cifrado de chunks con una clave efímera propia de esta transferencia
+	keyExchange, err := filetransferservice.NewTransferKeyExchange()
+	if err != nil {
+		log.Printf("⚠️ Could not generate key exchange for transfer %s: %v", transfer.TransferID(), err)
+		if h.requireChunkEncryption {
+			h.failTransfer(transfer, "No se pudo generar la clave de cifrado")
+			return fmt.Errorf("%w: %v", ErrChunkEncryptionNotNegotiated, err)
+		}
+	} else {
+		request.Encryption = filetransferservice.ChunkEncryptionAlgorithm
+		request.ServerPublicKey = keyExchange.PublicKey()
+		request.EncryptionRequired = h.requireChunkEncryption
+	}
+
+	// Registrar la espera del READY antes de enviar para no perder una respuesta rápida
+	readyChan := make(chan dto.FileTransferAcknowledgement, 1)
+	h.transferHandshakeMutex.Lock()
+	h.transferReady[transfer.TransferID()] = readyChan
+	h.transferHandshakeMutex.Unlock()
+
+	defer func() {
+		h.transferHandshakeMutex.Lock()
+		delete(h.transferReady, transfer.TransferID())
+		h.transferHandshakeMutex.Unlock()
+	}()
+
 	message := dto.WebSocketMessage{
 		Type: "file_transfer_request",
 		Data: request,
 	}
 
-	if err := clientConn.Conn.WriteJSON(message); err != nil {
+	if err := clientConn.writer().WriteJSON(message); err != nil {
 		log.Printf("Error sending file transfer request to client PC %s: %v", transfer.TargetPCID(), err)
 
 		// Marcar transferencia como fallida
@@ -1161,11 +2380,79 @@ func (h *WebSocketHandler) SendFileTransferRequestToClient(transfer *filetransfe
 
 	log.Printf("✅ File transfer request sent to client PC: %s (Transfer: %s, File: %s, %d chunks)",
 		transfer.TargetPCID(), transfer.TransferID(), transfer.FileName(), totalChunks)
+
+	return h.awaitTransferReady(transfer, readyChan, keyExchange)
+}
+
+// awaitTransferReady espera el READY del cliente y, si aceptó el cifrado, deriva el cifrador de la transferencia.
+// Clientes que no responden o no envían clave reciben los chunks en claro, salvo que el cifrado sea obligatorio.
+func (h *WebSocketHandler) awaitTransferReady(
+	transfer *filetransfer.FileTransfer,
+	readyChan chan dto.FileTransferAcknowledgement,
+	keyExchange *filetransferservice.TransferKeyExchange,
+) error {
+	var ackMsg dto.FileTransferAcknowledgement
+	select {
+	case ackMsg = <-readyChan:
+	case <-time.After(h.transferReadyTimeout):
+		log.Printf("⚠️ No READY from client %s for transfer %s", transfer.TargetPCID(), transfer.TransferID())
+	}
+
+	if ackMsg.ClientPublicKey != "" && keyExchange != nil {
+		chunkCipher, err := keyExchange.DeriveCipher(ackMsg.ClientPublicKey, transfer.TransferID())
+		if err == nil {
+			h.transferHandshakeMutex.Lock()
+			h.transferCiphers[transfer.TransferID()] = chunkCipher
+			h.transferHandshakeMutex.Unlock()
+
+			log.Printf("🔐 Chunk encryption negotiated for transfer %s", transfer.TransferID())
+			return nil
+		}
+		log.Printf("⚠️ Invalid client key for transfer %s: %v", transfer.TransferID(), err)
+	}
+
+	if h.requireChunkEncryption {
+		h.failTransfer(transfer, "El cliente no aceptó el cifrado de chunks requerido")
+		return fmt.Errorf("%w: transfer %s", ErrChunkEncryptionNotNegotiated, transfer.TransferID())
+	}
+
 	return nil
 }
 
+// takeTransferCipher devuelve y descarta el cifrador negociado para una transferencia (nil si se envía en claro)
+func (h *WebSocketHandler) takeTransferCipher(transferID string) *filetransferservice.ChunkCipher {
+	h.transferHandshakeMutex.Lock()
+	defer h.transferHandshakeMutex.Unlock()
+
+	chunkCipher := h.transferCiphers[transferID]
+	delete(h.transferCiphers, transferID)
+	return chunkCipher
+}
+
+// failTransfer marca una transferencia como fallida con el mensaje indicado
+func (h *WebSocketHandler) failTransfer(transfer *filetransfer.FileTransfer, errorMsg string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.fileTransferService.UpdateTransferStatus(
+		ctx,
+		transfer.TransferID(),
+		filetransfer.TransferStatusFailed,
+		errorMsg,
+	); err != nil {
+		log.Printf("Error updating transfer status to FAILED: %v", err)
+	}
+}
+
 // SendFileChunksToClient sends file chunks to the client PC
 func (h *WebSocketHandler) SendFileChunksToClient(transfer *filetransfer.FileTransfer) error {
+	// Cifrador negociado durante el handshake (nil = chunks en claro)
+	chunkCipher := h.takeTransferCipher(transfer.TransferID())
+	if chunkCipher == nil && h.requireChunkEncryption {
+		h.failTransfer(transfer, "El cliente no aceptó el cifrado de chunks requerido")
+		return fmt.Errorf("%w: transfer %s", ErrChunkEncryptionNotNegotiated, transfer.TransferID())
+	}
+
 	// Actualizar estado a IN_PROGRESS
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -1207,14 +2494,21 @@ func (h *WebSocketHandler) SendFileChunksToClient(transfer *filetransfer.FileTra
 	fileSize := int64(transfer.FileSizeMB() * 1024 * 1024)
 	totalChunks := int((fileSize + int64(chunkSize) - 1) / int64(chunkSize))
 	chunkIndex := 0
+	retriesLeft := h.chunkRetry.TotalRetries
 
 	// Leer archivo en chunks y enviar
 	err = h.fileTransferService.ReadFileInChunks(
 		transfer.SourcePathServer(),
 		chunkSize,
 		func(chunkData []byte, isLastChunk bool) error {
-			// Codificar chunk en base64
-			encodedData := base64.StdEncoding.EncodeToString(chunkData)
+			payload := chunkData
+			if chunkCipher != nil {
+				encrypted, err := chunkCipher.EncryptChunk(chunkIndex, chunkData)
+				if err != nil {
+					return fmt.Errorf("error encrypting chunk %d: %w", chunkIndex, err)
+				}
+				payload = encrypted
+			}
 
 			// Usar estructura actualizada
 			chunk := dto.FileChunk{
@@ -1223,28 +2517,32 @@ func (h *WebSocketHandler) SendFileChunksToClient(transfer *filetransfer.FileTra
 				SessionID:     transfer.AssociatedSessionID(),
 				ChunkIndex:    chunkIndex, // 0-based index
 				TotalChunks:   totalChunks,
-				ChunkData:     encodedData, // Base64 string directamente
 				IsLastChunk:   isLastChunk,
 				ChunkSize:     len(chunkData),
 				ChunkChecksum: "",                // TODO: implementar checksum MD5 si es necesario
 				Timestamp:     time.Now().Unix(), // Unix timestamp
+				Encrypted:     chunkCipher != nil,
 			}
 
-			message := dto.WebSocketMessage{
-				Type: "file_chunk",
-				Data: chunk,
+			if h.taskRegistry.TransferCancelled(transfer.TransferID()) {
+				return ErrTransferCancelled
 			}
 
-			// Verificar que el cliente sigue conectado antes de cada chunk
+			// Verificar que el cliente sigue conectado antes de cada chunk (si se reconectó, seguir por la nueva conexión)
 			h.mutex.RLock()
-			_, exists := h.pcConnections[transfer.TargetPCID()]
+			current, exists := h.pcConnections[transfer.TargetPCID()]
 			h.mutex.RUnlock()
 
 			if !exists {
 				return fmt.Errorf("client disconnected during chunk transfer")
 			}
+			clientConn = current
 
-			if err := clientConn.Conn.WriteJSON(message); err != nil {
+			var err error
+			clientConn, err = h.sendChunkWithRetry(transfer.TransferID(), transfer.TargetPCID(), chunkIndex, &retriesLeft, clientConn, func(conn *ClientConnection) error {
+				return sendFileChunk(conn, chunk, payload)
+			})
+			if err != nil {
 				return fmt.Errorf("error sending chunk %d: %w", chunkIndex, err)
 			}
 
@@ -1284,8 +2582,38 @@ func (h *WebSocketHandler) SendFileChunksToClient(transfer *filetransfer.FileTra
 	return nil
 }
 
-// ProcessFileTransfer processes a complete file transfer from start to finish
+// sendFileChunk envía un chunk como mensaje binario si el cliente lo negoció; si no, como JSON con base64
+func sendFileChunk(clientConn *ClientConnection, chunk dto.FileChunk, payload []byte) error {
+	if clientConn.binaryFrames {
+		frame, err := dto.EncodeBinaryFrame(dto.BinaryFrameFileChunk, chunk, payload)
+		if err != nil {
+			return err
+		}
+		return clientConn.writer().WriteMessage(websocket.BinaryMessage, frame)
+	}
+
+	chunk.ChunkData = base64.StdEncoding.EncodeToString(payload)
+	return clientConn.writer().WriteJSON(dto.WebSocketMessage{
+		Type: "file_chunk",
+		Data: chunk,
+	})
+}
+
+// ProcessFileTransfer processes a complete file transfer from start to finish. Se ejecuta en una goroutine:
+// un panic marca la transferencia como FAILED y se devuelve como ErrHandlerPanic en lugar de tumbar el servidor.
 func (h *WebSocketHandler) ProcessFileTransfer(transfer *filetransfer.FileTransfer) error {
+	var processErr error
+	subject := fmt.Sprintf("transfer %s, PC %s, session %s", transfer.TransferID(), transfer.TargetPCID(), transfer.AssociatedSessionID())
+	if err := runRecovered("file transfer", subject, func() {
+		processErr = h.processFileTransfer(transfer)
+	}); err != nil {
+		h.failTransfer(transfer, "Error interno procesando la transferencia")
+		return err
+	}
+	return processErr
+}
+
+func (h *WebSocketHandler) processFileTransfer(transfer *filetransfer.FileTransfer) error {
 	log.Printf("🚀 Starting file transfer process: %s (File: %s, Target: %s)",
 		transfer.TransferID(), transfer.FileName(), transfer.TargetPCID())
 
@@ -1334,6 +2662,9 @@ func (h *WebSocketHandler) ProcessFileTransfer(transfer *filetransfer.FileTransf
 // SendSessionEndedToClient notifica al cliente que una sesión ha terminado
 func (h *WebSocketHandler) SendSessionEndedToClient(sessionID, clientPCID string) error {
 	log.Printf("🔚 SESSION END: Attempting to send session ended notification to client PC: %s", clientPCID)
+	h.streamWatchdog.stop(sessionID)
+	h.pausedStreams.remove(sessionID)
+	h.audioStreams.disable(sessionID)
 
 	h.mutex.RLock()
 	clientConn, exists := h.pcConnections[clientPCID]
@@ -1346,6 +2677,9 @@ func (h *WebSocketHandler) SendSessionEndedToClient(sessionID, clientPCID string
 
 	log.Printf("✅ SESSION END: Found client connection for PC: %s", clientPCID)
 
+	// Lo que la conexión recordaba de las grabaciones de la sesión ya no se necesita
+	clientConn.recordingState.forgetSession(sessionID)
+
 	// Crear mensaje de sesión terminada
 	sessionEndedMsg := dto.WebSocketMessage{
 		Type: "control_session_ended",
@@ -1360,16 +2694,76 @@ func (h *WebSocketHandler) SendSessionEndedToClient(sessionID, clientPCID string
 	log.Printf("📡 SESSION END: Sending to client %s: %+v", clientPCID, sessionEndedMsg)
 
 	// Enviar mensaje al cliente
-	err := clientConn.Conn.WriteJSON(sessionEndedMsg)
+	err := clientConn.writer().WriteJSON(sessionEndedMsg)
 	if err != nil {
 		log.Printf("❌ SESSION END: Error sending to client %s: %v", clientPCID, err)
 		return err
 	}
 
 	log.Printf("✅ SESSION END: Notification sent successfully to client %s", clientPCID)
+	h.awaitSessionEndAck(sessionID, clientConn)
+	return nil
+}
+
+// SendSessionOwnershipTransferredToClient avisa al cliente de que otro administrador controla ahora su sesión
+func (h *WebSocketHandler) SendSessionOwnershipTransferredToClient(sessionID, clientPCID, toAdminID string) error {
+	// El audio se reenvía al nuevo administrador a partir del siguiente chunk
+	h.audioStreams.forgetAdmin(sessionID)
+
+	h.mutex.RLock()
+	clientConn, exists := h.pcConnections[clientPCID]
+	h.mutex.RUnlock()
+
+	if !exists {
+		log.Printf("⚠️ SESSION TRANSFER: Client PC %s not found in connections map", clientPCID)
+		return nil // No es un error crítico si el cliente no está conectado
+	}
+
+	transferredMsg := dto.WebSocketMessage{
+		Type: "control_session_transferred",
+		Data: map[string]interface{}{
+			"session_id":    sessionID,
+			"admin_user_id": toAdminID,
+			"message":       "Remote control session handed off to another administrator",
+			"timestamp":     time.Now().Unix(),
+		},
+	}
+
+	if err := clientConn.writer().WriteJSON(transferredMsg); err != nil {
+		log.Printf("❌ SESSION TRANSFER: Error sending to client %s: %v", clientPCID, err)
+		return err
+	}
+
+	log.Printf("✅ SESSION TRANSFER: Notification sent to client %s", clientPCID)
+
+	// El nuevo administrador está conectado: si el streaming estaba pausado, se retoma para él
+	if h.pausedStreams.remove(sessionID) {
+		h.sendStreamControl(clientConn, dto.MessageTypeResumeStream, sessionID, StreamResumeReasonSessionTransferred)
+	}
 	return nil
 }
 
+// sendPendingTransfer envía la solicitud y los chunks de una transferencia pendiente
+func (h *WebSocketHandler) sendPendingTransfer(transfer *filetransfer.FileTransfer) {
+	// Enviar solicitud de transferencia
+	err := h.SendFileTransferRequestToClient(transfer)
+	if err != nil {
+		log.Printf("❌ PENDING TRANSFERS: Error sending transfer %s: %v", transfer.TransferID(), err)
+		return
+	}
+
+	// Esperar un poco entre transferencias
+	time.Sleep(2 * time.Second)
+
+	// Enviar chunks del archivo
+	err = h.SendFileChunksToClient(transfer)
+	if err != nil {
+		log.Printf("❌ PENDING TRANSFERS: Error sending file chunks for transfer %s: %v", transfer.TransferID(), err)
+	} else {
+		log.Printf("✅ PENDING TRANSFERS: Transferencia %s procesada exitosamente", transfer.TransferID())
+	}
+}
+
 // processPendingTransfers procesa transferencias pendientes para un cliente recién conectado
 func (h *WebSocketHandler) processPendingTransfers(clientPCID string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -1389,40 +2783,59 @@ func (h *WebSocketHandler) processPendingTransfers(clientPCID string) {
 	pendingTransfers := make([]*filetransfer.FileTransfer, 0)
 	for i, transfer := range transfers {
 		log.Printf("  [%d] Transfer ID: %s, Status: %s, File: %s", i+1, transfer.TransferID(), transfer.Status(), transfer.FileName())
-		if transfer.Status() == filetransfer.TransferStatusPending {
-			pendingTransfers = append(pendingTransfers, transfer)
+		if transfer.Status() != filetransfer.TransferStatusPending {
+			continue
+		}
+		// En cola detrás de otra transferencia al mismo destino: se envía cuando aquella termine
+		if conflict := filetransferservice.FindDestinationConflict(transfer, transfers); conflict != nil {
+			log.Printf("  ⏳ Transfer %s espera a %s (mismo destino %s)", transfer.TransferID(), conflict.TransferID(), transfer.DestinationPathClient())
+			continue
 		}
+		pendingTransfers = append(pendingTransfers, transfer)
 	}
 
 	if len(pendingTransfers) > 0 {
-		log.Printf("📋 PENDING TRANSFERS: Cliente %s se conectó con %d transferencias pendientes", clientPCID, len(pendingTransfers))
+		// Solo se lanzan las más antiguas hasta completar el cupo del cliente; el resto espera a los siguientes
+		// heartbeats para no saturar a un cliente que se acaba de reconectar
+		claimed := h.pendingTransfers.claim(clientPCID, pendingTransfers, h.maxPendingTransfersPerClient)
+		log.Printf("📋 PENDING TRANSFERS: Cliente %s tiene %d transferencias pendientes, se procesan %d",
+			clientPCID, len(pendingTransfers), len(claimed))
+		if len(claimed) == 0 {
+			return
+		}
 
-		// Procesar transferencias pendientes en una goroutine
-		go func() {
-			for i, transfer := range pendingTransfers {
-				log.Printf("🔄 PENDING TRANSFERS: [%d/%d] Procesando transferencia pendiente: %s -> %s",
-					i+1, len(pendingTransfers), transfer.FileName(), clientPCID)
+		// Cada transferencia queda registrada en cola hasta que la goroutine la procesa
+		tasks := make([]*backgroundtaskservice.Task, len(claimed))
+		for i, transfer := range claimed {
+			tasks[i] = h.taskRegistry.Register(backgroundtaskservice.TaskKindPendingTransfer, transfer.TransferID())
+		}
 
-				// Enviar solicitud de transferencia
-				err := h.SendFileTransferRequestToClient(transfer)
-				if err != nil {
-					log.Printf("❌ PENDING TRANSFERS: Error sending transfer %s: %v", transfer.TransferID(), err)
+		// Procesar transferencias pendientes en una goroutine; un panic en una transferencia la marca FAILED
+		// y se continúa con la siguiente
+		goRecovered("pending transfers", "PC "+clientPCID, func() {
+			for i, transfer := range claimed {
+				task := tasks[i]
+				if task.Context().Err() != nil {
+					log.Printf("🛑 PENDING TRANSFERS: Transferencia %s cancelada antes de empezar", transfer.TransferID())
+					task.Finish()
+					h.pendingTransfers.release(transfer.TransferID())
 					continue
 				}
+				task.MarkRunning()
 
-				// Esperar un poco entre transferencias
-				time.Sleep(2 * time.Second)
+				log.Printf("🔄 PENDING TRANSFERS: [%d/%d] Procesando transferencia pendiente: %s -> %s",
+					i+1, len(claimed), transfer.FileName(), clientPCID)
 
-				// Enviar chunks del archivo
-				err = h.SendFileChunksToClient(transfer)
-				if err != nil {
-					log.Printf("❌ PENDING TRANSFERS: Error sending file chunks for transfer %s: %v", transfer.TransferID(), err)
-				} else {
-					log.Printf("✅ PENDING TRANSFERS: Transferencia %s procesada exitosamente", transfer.TransferID())
+				if err := runRecovered("pending transfer", "transfer "+transfer.TransferID()+", PC "+clientPCID, func() {
+					h.sendPendingTransfer(transfer)
+				}); err != nil {
+					h.failTransfer(transfer, "Error interno procesando la transferencia")
 				}
+				task.Finish()
+				h.pendingTransfers.release(transfer.TransferID())
 			}
 			log.Printf("🎉 PENDING TRANSFERS: Completado procesamiento de transferencias pendientes para cliente %s", clientPCID)
-		}()
+		})
 	} else {
 		log.Printf("✅ PENDING TRANSFERS: No hay transferencias pendientes para cliente %s", clientPCID)
 	}
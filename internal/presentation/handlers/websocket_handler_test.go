@@ -0,0 +1,1155 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/featureflagservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/filetransferservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/connectionsession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// MockFileTransferRepository es un mock del repositorio de transferencias
+type MockFileTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockFileTransferRepository) Save(ctx context.Context, transfer *filetransfer.FileTransfer) error {
+	return m.Called(ctx, transfer).Error(0)
+}
+
+func (m *MockFileTransferRepository) UpdateStatus(ctx context.Context, transferID string, status filetransfer.TransferStatus, errorMessage string) error {
+	return m.Called(ctx, transferID, status, errorMessage).Error(0)
+}
+
+func (m *MockFileTransferRepository) FindByID(ctx context.Context, transferID string) (*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindByTargetPCID(ctx context.Context, targetPCID string) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, targetPCID)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindByInitiatingUserID(ctx context.Context, userID string) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindPendingTransfers(ctx context.Context) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) SumFileSizeByTargetPCID(ctx context.Context, targetPCID string) (float64, error) {
+	args := m.Called(ctx, targetPCID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindInProgressTransfers(ctx context.Context) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) Delete(ctx context.Context, transferID string) error {
+	return m.Called(ctx, transferID).Error(0)
+}
+
+const testTargetPCID = "pc-123"
+
+// connectTestClient registra en el handler una conexión real con un cliente simulado y devuelve el lado del cliente
+func connectTestClient(t *testing.T, h *WebSocketHandler) (*websocket.Conn, *ClientConnection) {
+	t.Helper()
+
+	serverConns := make(chan *websocket.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		serverConns <- conn
+	}))
+	t.Cleanup(server.Close)
+
+	clientSide, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { clientSide.Close() })
+
+	// Cerrar el extremo del servidor al terminar también lo mantiene vivo: sin referencias, el GC lo cerraría
+	serverConn := <-serverConns
+	t.Cleanup(func() { serverConn.Close() })
+
+	clientConn := &ClientConnection{Conn: serverConn, PCID: testTargetPCID, IsAuth: true}
+	h.mutex.Lock()
+	h.pcConnections[testTargetPCID] = clientConn
+	h.mutex.Unlock()
+
+	return clientSide, clientConn
+}
+
+func newTestWebSocketHandler() (*WebSocketHandler, *MockFileTransferRepository) {
+	transferRepo := new(MockFileTransferRepository)
+	transferService := filetransferservice.NewFileTransferService(transferRepo, nil, nil, nil)
+	h := NewWebSocketHandler(nil, nil, nil, nil, transferService, nil)
+	// Los tests envían frames de relleno que no son JPEG; los de validación la vuelven a activar
+	h.SetValidateJPEGFrames(false)
+	return h, transferRepo
+}
+
+// sendReady simula el READY del cliente, opcionalmente aceptando el cifrado con su clave pública
+func sendReady(h *WebSocketHandler, clientConn *ClientConnection, transferID, clientPublicKey string) {
+	h.handleFileTransferAcknowledgement(nil, clientConn, map[string]interface{}{
+		"transfer_id":       transferID,
+		"status":            "READY",
+		"client_public_key": clientPublicKey,
+	})
+}
+
+// answerStorageQuery lee el storage_query y responde con espacio de sobra
+func answerStorageQuery(t *testing.T, h *WebSocketHandler, clientSide *websocket.Conn, clientConn *ClientConnection) {
+	t.Helper()
+
+	query := readStorageQuery(t, clientSide)
+	h.handleStorageQueryResponse(nil, clientConn, map[string]interface{}{
+		"transfer_id":     query["transfer_id"],
+		"available_bytes": 1 << 40,
+	})
+}
+
+// readStorageQuery lee el siguiente mensaje del cliente simulado y verifica que sea un storage_query
+func readStorageQuery(t *testing.T, clientSide *websocket.Conn) map[string]interface{} {
+	t.Helper()
+
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.ReadJSON(&message))
+	require.Equal(t, "storage_query", message.Type)
+	return message.Data.(map[string]interface{})
+}
+
+func TestSendFileTransferRequestToClient_SufficientSpaceSendsRequest(t *testing.T) {
+	// Arrange
+	h, transferRepo := newTestWebSocketHandler()
+	clientSide, clientConn := connectTestClient(t, h)
+	transfer := filetransfer.NewFileTransfer("report.pdf", "/srv/report.pdf", "C:/Downloads/report.pdf",
+		"session-1", "admin-1", testTargetPCID, 1)
+
+	result := make(chan error, 1)
+
+	// Act
+	go func() { result <- h.SendFileTransferRequestToClient(transfer) }()
+
+	query := readStorageQuery(t, clientSide)
+	h.handleStorageQueryResponse(nil, clientConn, map[string]interface{}{
+		"transfer_id":     transfer.TransferID(),
+		"available_bytes": 10 * 1024 * 1024,
+	})
+
+	var request dto.WebSocketMessage
+	require.NoError(t, clientSide.ReadJSON(&request))
+	sendReady(h, clientConn, transfer.TransferID(), "")
+
+	// Assert
+	assert.Equal(t, transfer.TransferID(), query["transfer_id"])
+	assert.Equal(t, "C:/Downloads/report.pdf", query["destination_path"])
+	assert.Equal(t, float64(1024*1024), query["required_bytes"])
+	assert.Equal(t, "file_transfer_request", request.Type)
+	assert.NoError(t, <-result)
+	transferRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSendFileTransferRequestToClient_ConveysConflictPolicy(t *testing.T) {
+	for _, policy := range []filetransfer.ConflictPolicy{filetransfer.ConflictPolicyOverwrite, filetransfer.ConflictPolicyRename, filetransfer.ConflictPolicySkip} {
+		t.Run(string(policy), func(t *testing.T) {
+			// Arrange
+			h, _ := newTestWebSocketHandler()
+			clientSide, clientConn := connectTestClient(t, h)
+			transfer := filetransfer.NewFileTransfer("report.pdf", "/srv/report.pdf", "C:/Downloads/report.pdf",
+				"session-1", "admin-1", testTargetPCID, 1)
+			require.NoError(t, transfer.SetConflictPolicy(policy))
+
+			result := make(chan error, 1)
+
+			// Act
+			go func() { result <- h.SendFileTransferRequestToClient(transfer) }()
+
+			answerStorageQuery(t, h, clientSide, clientConn)
+			var request dto.WebSocketMessage
+			require.NoError(t, clientSide.ReadJSON(&request))
+			sendReady(h, clientConn, transfer.TransferID(), "")
+
+			// Assert
+			require.NoError(t, <-result)
+			assert.Equal(t, "file_transfer_request", request.Type)
+			assert.Equal(t, string(policy), request.Data.(map[string]interface{})["conflict_policy"])
+		})
+	}
+}
+
+func TestSendFileTransferRequestToClient_InsufficientSpaceAbortsTransfer(t *testing.T) {
+	// Arrange
+	h, transferRepo := newTestWebSocketHandler()
+	clientSide, clientConn := connectTestClient(t, h)
+	transfer := filetransfer.NewFileTransfer("backup.zip", "/srv/backup.zip", "C:/Downloads/backup.zip",
+		"session-1", "admin-1", testTargetPCID, 100)
+
+	transferRepo.On("UpdateStatus", mock.Anything, transfer.TransferID(),
+		filetransfer.TransferStatusInsufficientClientSpace, mock.AnythingOfType("string")).Return(nil)
+	transferRepo.On("FindByID", mock.Anything, transfer.TransferID()).Return(transfer, nil)
+
+	result := make(chan error, 1)
+
+	// Act
+	go func() { result <- h.SendFileTransferRequestToClient(transfer) }()
+
+	readStorageQuery(t, clientSide)
+	h.handleStorageQueryResponse(nil, clientConn, map[string]interface{}{
+		"transfer_id":     transfer.TransferID(),
+		"available_bytes": 1024,
+	})
+
+	err := <-result
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInsufficientClientSpace)
+	transferRepo.AssertExpectations(t)
+
+	// No se debe enviar la solicitud de transferencia tras abortar
+	clientSide.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	var unexpected dto.WebSocketMessage
+	assert.Error(t, clientSide.ReadJSON(&unexpected))
+}
+
+func TestSendFileTransferRequestToClient_NoStorageResponseContinuesTransfer(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	h.storageQueryTimeout = 50 * time.Millisecond
+	h.transferReadyTimeout = 50 * time.Millisecond
+	clientSide, _ := connectTestClient(t, h)
+	transfer := filetransfer.NewFileTransfer("report.pdf", "/srv/report.pdf", "C:/Downloads/report.pdf",
+		"session-1", "admin-1", testTargetPCID, 1)
+
+	result := make(chan error, 1)
+
+	// Act - el cliente no implementa storage_query y nunca responde
+	go func() { result <- h.SendFileTransferRequestToClient(transfer) }()
+
+	readStorageQuery(t, clientSide)
+	var request dto.WebSocketMessage
+	require.NoError(t, clientSide.ReadJSON(&request))
+
+	// Assert
+	assert.Equal(t, "file_transfer_request", request.Type)
+	assert.NoError(t, <-result)
+}
+
+func TestFileTransfer_EncryptedChunksRoundTripWithNegotiatedKey(t *testing.T) {
+	// Arrange
+	h, transferRepo := newTestWebSocketHandler()
+	h.SetRequireChunkEncryption(true)
+	clientSide, clientConn := connectTestClient(t, h)
+
+	content := []byte("contenido confidencial que no debe viajar en claro")
+	sourcePath := filepath.Join(t.TempDir(), "secret.txt")
+	require.NoError(t, os.WriteFile(sourcePath, content, 0644))
+	transfer := filetransfer.NewFileTransfer("secret.txt", sourcePath, "C:/Downloads/secret.txt",
+		"session-1", "admin-1", testTargetPCID, float64(len(content))/(1024*1024))
+
+	transferRepo.On("UpdateStatus", mock.Anything, transfer.TransferID(),
+		filetransfer.TransferStatusInProgress, "").Return(nil)
+	transferRepo.On("FindByID", mock.Anything, transfer.TransferID()).Return(transfer, nil)
+
+	clientKeys, err := filetransferservice.NewTransferKeyExchange()
+	require.NoError(t, err)
+
+	result := make(chan error, 1)
+
+	// Act
+	go func() {
+		if err := h.SendFileTransferRequestToClient(transfer); err != nil {
+			result <- err
+			return
+		}
+		result <- h.SendFileChunksToClient(transfer)
+	}()
+
+	answerStorageQuery(t, h, clientSide, clientConn)
+
+	var requestMsg struct {
+		Type string                  `json:"type"`
+		Data dto.FileTransferRequest `json:"data"`
+	}
+	require.NoError(t, clientSide.ReadJSON(&requestMsg))
+	sendReady(h, clientConn, transfer.TransferID(), clientKeys.PublicKey())
+
+	var chunkMsg struct {
+		Type string        `json:"type"`
+		Data dto.FileChunk `json:"data"`
+	}
+	require.NoError(t, clientSide.ReadJSON(&chunkMsg))
+	require.NoError(t, <-result)
+
+	// Assert - el cliente descifra con la clave derivada del handshake
+	assert.Equal(t, filetransferservice.ChunkEncryptionAlgorithm, requestMsg.Data.Encryption)
+	assert.True(t, requestMsg.Data.EncryptionRequired)
+	assert.True(t, chunkMsg.Data.Encrypted)
+
+	encrypted, err := base64.StdEncoding.DecodeString(chunkMsg.Data.ChunkData)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encrypted), string(content))
+
+	clientCipher, err := clientKeys.DeriveCipher(requestMsg.Data.ServerPublicKey, transfer.TransferID())
+	require.NoError(t, err)
+	decrypted, err := clientCipher.DecryptChunk(chunkMsg.Data.ChunkIndex, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, content, decrypted)
+}
+
+func TestFileTransfer_RequiredEncryptionFailsWhenClientSendsNoKey(t *testing.T) {
+	// Arrange
+	h, transferRepo := newTestWebSocketHandler()
+	h.SetRequireChunkEncryption(true)
+	clientSide, clientConn := connectTestClient(t, h)
+	transfer := filetransfer.NewFileTransfer("report.pdf", "/srv/report.pdf", "C:/Downloads/report.pdf",
+		"session-1", "admin-1", testTargetPCID, 1)
+
+	transferRepo.On("UpdateStatus", mock.Anything, transfer.TransferID(),
+		filetransfer.TransferStatusFailed, mock.AnythingOfType("string")).Return(nil)
+	transferRepo.On("FindByID", mock.Anything, transfer.TransferID()).Return(transfer, nil)
+
+	result := make(chan error, 1)
+
+	// Act - cliente antiguo que responde READY sin clave pública
+	go func() { result <- h.SendFileTransferRequestToClient(transfer) }()
+
+	answerStorageQuery(t, h, clientSide, clientConn)
+	var request dto.WebSocketMessage
+	require.NoError(t, clientSide.ReadJSON(&request))
+	sendReady(h, clientConn, transfer.TransferID(), "")
+
+	// Assert
+	assert.ErrorIs(t, <-result, ErrChunkEncryptionNotNegotiated)
+	assert.ErrorIs(t, h.SendFileChunksToClient(transfer), ErrChunkEncryptionNotNegotiated)
+	transferRepo.AssertExpectations(t)
+	transferRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, transfer.TransferID(),
+		filetransfer.TransferStatusInProgress, "")
+}
+
+// spyVideoService registra los frames guardados; el resto de IVideoService no se usa en estos tests
+type spyVideoService struct {
+	videoservice.IVideoService
+	savedFrames     []videoservice.VideoFrameInfo
+	completedVideos []string
+	completedOwners [][2]string
+	completeResult  *videoservice.VideoUploadResult
+	completeErr     error
+}
+
+func (s *spyVideoService) CompleteVideoUpload(videoID, pcID, userID string) (*videoservice.VideoUploadResult, error) {
+	s.completedVideos = append(s.completedVideos, videoID)
+	s.completedOwners = append(s.completedOwners, [2]string{pcID, userID})
+	return s.completeResult, s.completeErr
+}
+
+func (s *spyVideoService) SaveVideoFrame(frameInfo videoservice.VideoFrameInfo) error {
+	s.savedFrames = append(s.savedFrames, frameInfo)
+	return nil
+}
+
+// featureFlagsFromEnv crea los feature flags a partir de variables de entorno simuladas
+func featureFlagsFromEnv(values map[string]string) featureflagservice.IFeatureFlagService {
+	return featureflagservice.NewFeatureFlagService(func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	})
+}
+
+// testVideoFrameData simula el mensaje video_frame_upload del cliente
+func testVideoFrameData(frameIndex int) map[string]interface{} {
+	return map[string]interface{}{
+		"session_id":  "session-1",
+		"video_id":    "video-1",
+		"frame_index": frameIndex,
+		"timestamp":   time.Now().UnixMilli(),
+		"frame_data":  base64.StdEncoding.EncodeToString([]byte("jpeg")),
+	}
+}
+
+func TestHandleVideoFrameUpload_ServerRecordingDisabledSkipsSaveAndNotifiesOnce(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	videoService := &spyVideoService{}
+	h.videoService = videoService
+	h.SetFeatureFlags(featureFlagsFromEnv(map[string]string{"FEATURE_SERVER_SIDE_RECORDING": "false"}))
+	clientSide, clientConn := connectTestClient(t, h)
+
+	// Act
+	h.handleVideoFrameUpload(clientConn.Conn, clientConn, testVideoFrameData(1))
+	h.handleVideoFrameUpload(clientConn.Conn, clientConn, testVideoFrameData(2))
+
+	// Assert
+	assert.Empty(t, videoService.savedFrames)
+
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, "video_recording_rejected", message.Type)
+	assert.Equal(t, "SERVER_RECORDING_DISABLED", message.Data.(map[string]interface{})["error_code"])
+
+	// El segundo frame no genera otra notificación
+	clientSide.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	assert.Error(t, clientSide.ReadJSON(&message))
+}
+
+func TestHandleVideoFrameUpload_DownscalesFrameAboveResolutionLimit(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	videoService := &spyVideoService{}
+	h.videoService = videoService
+	h.SetFrameResolutionLimit(videoservice.FrameResolutionLimit{MaxWidth: 320, Mode: videoservice.OversizeFrameDownscale})
+	_, clientConn := connectTestClient(t, h)
+
+	var frame bytes.Buffer
+	require.NoError(t, jpeg.Encode(&frame, image.NewGray(image.Rect(0, 0, 640, 480)), nil))
+	data := testVideoFrameData(1)
+	data["frame_data"] = base64.StdEncoding.EncodeToString(frame.Bytes())
+
+	// Act
+	h.handleVideoFrameUpload(clientConn.Conn, clientConn, data)
+	clientConn.stopFramePipeline()
+
+	// Assert - se guarda el frame reducido, fuera de la goroutine de lectura
+	require.NotNil(t, clientConn.frames)
+	require.Len(t, videoService.savedFrames, 1)
+	config, _, err := image.DecodeConfig(bytes.NewReader(videoService.savedFrames[0].FrameData))
+	require.NoError(t, err)
+	assert.Equal(t, 320, config.Width)
+	assert.Equal(t, 240, config.Height)
+}
+
+func TestHandleVideoFrameUpload_ServerRecordingEnabledSavesFrame(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	videoService := &spyVideoService{}
+	h.videoService = videoService
+	h.SetFeatureFlags(featureFlagsFromEnv(nil))
+	_, clientConn := connectTestClient(t, h)
+
+	// Act
+	h.handleVideoFrameUpload(clientConn.Conn, clientConn, testVideoFrameData(1))
+
+	// Assert
+	require.Len(t, videoService.savedFrames, 1)
+	assert.Equal(t, "video-1", videoService.savedFrames[0].VideoID)
+}
+
+// newTestRecordingHandler crea un handler con una sesión ACTIVE del PC sessionPCID y un spy de grabación
+func newTestRecordingHandler(t *testing.T, sessionPCID string) (*WebSocketHandler, *spyVideoService, *remotesession.RemoteSession) {
+	t.Helper()
+
+	session, err := remotesession.NewRemoteSession("admin-id", sessionPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+
+	h := NewWebSocketHandler(nil, nil, remotesessionservice.NewRemoteSessionService(sessionRepo, nil, nil, nil, nil), nil, nil, nil)
+	h.SetValidateJPEGFrames(false)
+	videoService := &spyVideoService{}
+	h.videoService = videoService
+	return h, videoService, session
+}
+
+func TestHandleVideoFrameUpload_OwnActiveSessionSavesFrame(t *testing.T) {
+	// Arrange
+	h, videoService, session := newTestRecordingHandler(t, testTargetPCID)
+	_, clientConn := connectTestClient(t, h)
+	data := testVideoFrameData(1)
+	data["session_id"] = session.SessionID()
+
+	// Act
+	h.handleVideoFrameUpload(clientConn.Conn, clientConn, data)
+
+	// Assert
+	require.Len(t, videoService.savedFrames, 1)
+	assert.Equal(t, session.SessionID(), videoService.savedFrames[0].SessionID)
+}
+
+func TestHandleVideoFrameUpload_SpoofedSessionIsRejected(t *testing.T) {
+	// Arrange - la sesión pertenece a otro PC
+	h, videoService, session := newTestRecordingHandler(t, "pc-other")
+	clientSide, clientConn := connectTestClient(t, h)
+	data := testVideoFrameData(1)
+	data["session_id"] = session.SessionID()
+
+	// Act
+	h.handleVideoFrameUpload(clientConn.Conn, clientConn, data)
+	h.handleVideoFrameUpload(clientConn.Conn, clientConn, data)
+	h.handleVideoRecordingComplete(clientConn.Conn, clientConn, map[string]interface{}{
+		"session_id":   session.SessionID(),
+		"video_id":     "video-1",
+		"total_frames": 2,
+	})
+
+	// Assert - no se guarda ni se finaliza nada y el cliente recibe un único rechazo
+	assert.Empty(t, videoService.savedFrames)
+
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, "video_recording_rejected", message.Type)
+	assert.Equal(t, "RECORDING_NOT_PERMITTED", message.Data.(map[string]interface{})["error_code"])
+
+	clientSide.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	assert.Error(t, clientSide.ReadJSON(&message))
+}
+
+func TestHandleVideoUploadComplete_CompletesUploadThroughVideoService(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	videoService := &spyVideoService{completeResult: &videoservice.VideoUploadResult{
+		IsComplete:       true,
+		FilePath:         "videos/processed/session-1_video-1.mp4",
+		AlreadyCompleted: true,
+	}}
+	h.videoService = videoService
+	clientSide, clientConn := connectTestClient(t, h)
+	clientConn.UserID = "client-user-1"
+
+	// Act
+	h.handleVideoUploadComplete(clientConn.Conn, clientConn, map[string]interface{}{"video_id": "video-1"})
+
+	// Assert - se completa en nombre del PC y el usuario de la conexión, que deben ser los de la subida
+	assert.Equal(t, []string{"video-1"}, videoService.completedVideos)
+	assert.Equal(t, [][2]string{{testTargetPCID, "client-user-1"}}, videoService.completedOwners)
+
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, "video_upload_completed_confirmed", message.Type)
+	data := message.Data.(map[string]interface{})
+	assert.Equal(t, "videos/processed/session-1_video-1.mp4", data["file_path"])
+	assert.Equal(t, true, data["already_completed"])
+}
+
+func TestHandleVideoUploadComplete_ReportsIncompleteUpload(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	videoService := &spyVideoService{completeErr: fmt.Errorf("%w: faltan 1 de 2 chunks", videoservice.ErrVideoUploadIncomplete)}
+	h.videoService = videoService
+	clientSide, clientConn := connectTestClient(t, h)
+
+	// Act
+	h.handleVideoUploadComplete(clientConn.Conn, clientConn, map[string]interface{}{"video_id": "video-1"})
+
+	// Assert - no se confirma una subida que no se persistió
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, "video_upload_error", message.Type)
+	assert.Contains(t, message.Data.(map[string]interface{})["error"], "faltan 1 de 2 chunks")
+}
+
+// MockRemoteSessionRepository es un mock del repositorio de sesiones remotas
+type MockRemoteSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRemoteSessionRepository) Save(ctx context.Context, session *remotesession.RemoteSession) error {
+	return m.Called(ctx, session).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) FindById(ctx context.Context, id string) (*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) UpdateStatus(ctx context.Context, id string, status remotesession.SessionStatus) error {
+	return m.Called(ctx, id, status).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) FindByAdminUserID(ctx context.Context, adminUserID string) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, adminUserID)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindByClientPCID(ctx context.Context, clientPCID string) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, clientPCID)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindActiveSessions(ctx context.Context) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindPendingSessions(ctx context.Context) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) Update(ctx context.Context, session *remotesession.RemoteSession) error {
+	return m.Called(ctx, session).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) Delete(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) FindByStatus(ctx context.Context, status remotesession.SessionStatus) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindSessionsByDateRange(ctx context.Context, adminUserID string, startDate, endDate string) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, adminUserID, startDate, endDate)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindAccessReport(ctx context.Context, adminUserID, clientPCID string, from, to time.Time, limit, offset int) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, adminUserID, clientPCID, from, to, limit, offset)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) CountAccessReport(ctx context.Context, adminUserID, clientPCID string, from, to time.Time) (int, error) {
+	args := m.Called(ctx, adminUserID, clientPCID, from, to)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) CountSessionsByUser(ctx context.Context, adminUserID string) (int64, error) {
+	args := m.Called(ctx, adminUserID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// serveTestClient atiende con serveClientConnection una conexión ya autenticada del PC de prueba;
+// el canal devuelto se cierra cuando el handler terminó de liberar la conexión
+func serveTestClient(t *testing.T, h *WebSocketHandler) (*websocket.Conn, <-chan struct{}) {
+	t.Helper()
+	return serveTestConnection(t, h, &ClientConnection{PCID: testTargetPCID, IsAuth: true, RemoteAddr: "127.0.0.1"})
+}
+
+// serveTestConnection atiende con serveClientConnection la conexión indicada, asignándole el socket del servidor
+func serveTestConnection(t *testing.T, h *WebSocketHandler, clientConn *ClientConnection) (*websocket.Conn, <-chan struct{}) {
+	t.Helper()
+
+	served := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		defer close(served)
+
+		clientConn.Conn = conn
+		h.serveClientConnection("conn-1", clientConn)
+	}))
+	t.Cleanup(server.Close)
+
+	clientSide, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { clientSide.Close() })
+
+	return clientSide, served
+}
+
+// newTestDisconnectHandler crea un handler con una sesión ACTIVE para el PC de prueba; espera que termine con expectedStatus
+func newTestDisconnectHandler(t *testing.T, expectedStatus remotesession.SessionStatus) (*WebSocketHandler, *MockRemoteSessionRepository, *MockPCService) {
+	t.Helper()
+
+	session, err := remotesession.NewRemoteSession("admin-id", testTargetPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindByClientPCID", mock.Anything, testTargetPCID).Return([]*remotesession.RemoteSession{session}, nil)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+	sessionRepo.On("UpdateStatus", mock.Anything, session.SessionID(), expectedStatus).Return(nil)
+
+	pcService := new(MockPCService)
+	pcService.On("GetPCByID", mock.Anything, testTargetPCID).Return(newTestPC(testTargetPCID), nil)
+	pcService.On("UpdatePCConnectionStatus", mock.Anything, testTargetPCID, clientpc.PCConnectionStatusOffline).Return(nil)
+
+	sessionService := remotesessionservice.NewRemoteSessionService(sessionRepo, nil, nil, nil, nil)
+	return NewWebSocketHandler(nil, pcService, sessionService, nil, nil, nil), sessionRepo, pcService
+}
+
+// waitServed espera a que el handler libere la conexión
+func waitServed(t *testing.T, served <-chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-served:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the handler did not release the connection")
+	}
+}
+
+func TestServeClientConnection_PersistsDisconnectOutsideHandlerMutex(t *testing.T) {
+	// Arrange - el historial de conexiones comprueba si el mutex del handler sigue tomado mientras persiste
+	h, sessionRepo, _ := newTestDisconnectHandler(t, remotesession.StatusFailed)
+	connectionSession, err := connectionsession.NewConnectionSession(testTargetPCID, "127.0.0.1")
+	require.NoError(t, err)
+	var mutexFree bool
+	history := new(MockConnectionHistoryService)
+	history.On("RecordDisconnect", mock.Anything, connectionSession, mock.Anything).Run(func(mock.Arguments) {
+		if mutexFree = h.mutex.TryLock(); mutexFree {
+			h.mutex.Unlock()
+		}
+	}).Return(nil)
+	h.SetConnectionHistoryService(history)
+	clientSide, served := serveTestConnection(t, h, &ClientConnection{
+		PCID: testTargetPCID, IsAuth: true, RemoteAddr: "127.0.0.1", ConnectionSession: connectionSession,
+	})
+
+	// Act - el cliente corta el socket sin frame de cierre
+	require.NoError(t, clientSide.Close())
+
+	// Assert
+	waitServed(t, served)
+	history.AssertExpectations(t)
+	sessionRepo.AssertExpectations(t)
+	assert.True(t, mutexFree, "the disconnect was persisted while holding the handler mutex")
+}
+
+func TestClientShutdown_EndsActiveSessionCleanlyAndAcknowledges(t *testing.T) {
+	// Arrange
+	h, sessionRepo, pcService := newTestDisconnectHandler(t, remotesession.StatusEndedByClient)
+	clientSide, served := serveTestClient(t, h)
+
+	// Act
+	require.NoError(t, clientSide.WriteJSON(dto.WebSocketMessage{
+		Type: dto.MessageTypeClientShutdown,
+		Data: dto.ClientShutdownRequest{Reason: "user quit"},
+	}))
+
+	// Assert - el cliente recibe el ack y después un cierre normal
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, dto.MessageTypeClientShutdownAck, message.Type)
+	ack := message.Data.(map[string]interface{})
+	assert.Equal(t, testTargetPCID, ack["pc_id"])
+	assert.Equal(t, "OFFLINE", ack["status"])
+
+	_, _, err := clientSide.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), "expected normal closure, got %v", err)
+
+	waitServed(t, served)
+	sessionRepo.AssertExpectations(t)
+	pcService.AssertExpectations(t)
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	assert.NotContains(t, h.pcConnections, testTargetPCID)
+}
+
+func TestClientShutdown_AbruptDropFailsActiveSession(t *testing.T) {
+	// Arrange
+	h, sessionRepo, pcService := newTestDisconnectHandler(t, remotesession.StatusFailed)
+	clientSide, served := serveTestClient(t, h)
+
+	// Act - el socket se corta sin client_shutdown ni frame de cierre
+	require.NoError(t, clientSide.UnderlyingConn().Close())
+
+	// Assert
+	waitServed(t, served)
+	sessionRepo.AssertExpectations(t)
+	sessionRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, remotesession.StatusEndedByClient)
+	pcService.AssertExpectations(t)
+}
+
+func TestResolveRegistrationIP(t *testing.T) {
+	tests := []struct {
+		name              string
+		reported          string
+		observed          string
+		expectedIP        string
+		expectDiscrepancy bool
+	}{
+		{name: "empty reported uses observed", reported: "", observed: "203.0.113.10", expectedIP: "203.0.113.10"},
+		{name: "valid matching IPv4", reported: " 203.0.113.10 ", observed: "203.0.113.10", expectedIP: "203.0.113.10"},
+		{name: "IPv6 is normalized", reported: "2001:DB8:0:0::1", observed: "2001:db8::1", expectedIP: "2001:db8::1"},
+		{name: "IPv4-mapped IPv6 is unmapped", reported: "::ffff:192.168.1.20", observed: "192.168.1.1", expectedIP: "192.168.1.20"},
+		{name: "invalid reported falls back to observed", reported: "999.1.1.1", observed: "203.0.113.10", expectedIP: "203.0.113.10", expectDiscrepancy: true},
+		{name: "mismatch with public observed prefers observed", reported: "198.51.100.7", observed: "203.0.113.10", expectedIP: "203.0.113.10", expectDiscrepancy: true},
+		{name: "LAN address behind private observed is trusted", reported: "192.168.1.20", observed: "10.0.0.1", expectedIP: "192.168.1.20"},
+		{name: "LAN address behind loopback is trusted", reported: "192.168.1.20", observed: "127.0.0.1", expectedIP: "192.168.1.20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := resolveRegistrationIP(tt.reported, tt.observed)
+
+			// Assert
+			assert.Equal(t, tt.expectedIP, result.IP)
+			assert.Equal(t, tt.expectDiscrepancy, result.Discrepancy != "")
+		})
+	}
+}
+
+func TestGetClientIP_TrustsForwardedAddressOnlyFromTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		expectedIP     string
+	}{
+		{name: "no trusted proxies uses socket address", trustedProxies: nil, expectedIP: "10.0.0.1"},
+		{name: "trusted proxy forwards client address", trustedProxies: []string{"10.0.0.0/8"}, expectedIP: "203.0.113.10"},
+		{name: "untrusted proxy is ignored", trustedProxies: []string{"192.168.0.1"}, expectedIP: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			router := gin.New()
+			require.NoError(t, router.SetTrustedProxies(tt.trustedProxies))
+			var ip string
+			router.GET("/ws/client", func(c *gin.Context) { ip = getClientIP(c) })
+
+			request := httptest.NewRequest(http.MethodGet, "/ws/client", nil)
+			request.RemoteAddr = "10.0.0.1:51234"
+			request.Header.Set("X-Forwarded-For", "203.0.113.10")
+
+			// Act
+			router.ServeHTTP(httptest.NewRecorder(), request)
+
+			// Assert
+			assert.Equal(t, tt.expectedIP, ip)
+		})
+	}
+}
+
+// binaryVideoFrame codifica un video_frame_upload como mensaje binario con los bytes del JPEG en crudo
+func binaryVideoFrame(t *testing.T, frameIndex int, jpeg []byte) []byte {
+	t.Helper()
+
+	frame, err := dto.EncodeBinaryFrame(dto.BinaryFrameVideoFrame, dto.VideoFrameUpload{
+		SessionID:  "session-1",
+		VideoID:    "video-1",
+		FrameIndex: frameIndex,
+		Timestamp:  time.Now().UnixMilli(),
+	}, jpeg)
+	require.NoError(t, err)
+	return frame
+}
+
+func TestBinaryVideoFrame_RoundTripsThroughReadLoop(t *testing.T) {
+	// Arrange - sin PCID la desconexión no toca sesiones ni el estado del PC
+	h, _ := newTestWebSocketHandler()
+	videoService := &spyVideoService{}
+	h.videoService = videoService
+	jpeg := []byte{0xFF, 0xD8, 0x00, 0x0A, 0xFF, 0xD9}
+	clientSide, served := serveTestConnection(t, h, &ClientConnection{IsAuth: true, binaryFrames: true})
+
+	// Act
+	require.NoError(t, clientSide.WriteMessage(websocket.BinaryMessage, binaryVideoFrame(t, 7, jpeg)))
+	require.NoError(t, clientSide.WriteJSON(dto.WebSocketMessage{Type: "video_frame_upload", Data: testVideoFrameData(8)}))
+	clientSide.Close()
+	waitServed(t, served)
+
+	// Assert - el frame binario llega intacto y el JSON sigue funcionando en la misma conexión
+	require.Len(t, videoService.savedFrames, 2)
+	assert.Equal(t, "session-1", videoService.savedFrames[0].SessionID)
+	assert.Equal(t, "video-1", videoService.savedFrames[0].VideoID)
+	assert.Equal(t, 7, videoService.savedFrames[0].FrameIndex)
+	assert.Equal(t, jpeg, videoService.savedFrames[0].FrameData)
+	assert.Equal(t, []byte("jpeg"), videoService.savedFrames[1].FrameData)
+}
+
+func TestBinaryMessage_IgnoredWhenNotNegotiated(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	videoService := &spyVideoService{}
+	h.videoService = videoService
+	clientSide, served := serveTestConnection(t, h, &ClientConnection{IsAuth: true})
+
+	// Act
+	require.NoError(t, clientSide.WriteMessage(websocket.BinaryMessage, binaryVideoFrame(t, 1, []byte("jpeg"))))
+	require.NoError(t, clientSide.WriteJSON(dto.WebSocketMessage{Type: "video_frame_upload", Data: testVideoFrameData(2)}))
+	clientSide.Close()
+	waitServed(t, served)
+
+	// Assert - el binario se descarta sin cerrar la conexión
+	require.Len(t, videoService.savedFrames, 1)
+	assert.Equal(t, 2, videoService.savedFrames[0].FrameIndex)
+}
+
+func TestMalformedHeartbeat_ReportsErrorAndKeepsConnection(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	clientSide, served := serveTestConnection(t, h, &ClientConnection{IsAuth: true})
+
+	// Act
+	require.NoError(t, clientSide.WriteJSON(dto.WebSocketMessage{
+		Type: dto.MessageTypeHeartbeat,
+		Data: map[string]interface{}{"timestamp": "yesterday"},
+	}))
+
+	// Assert
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, dto.MessageTypeMalformedPayload, message.Type)
+	data := message.Data.(map[string]interface{})
+	assert.Equal(t, dto.MessageTypeHeartbeat, data["message_type"])
+	assert.Contains(t, data["error"], "timestamp")
+
+	// La conexión sigue abierta: un heartbeat válido recibe su respuesta
+	require.NoError(t, clientSide.WriteJSON(dto.WebSocketMessage{
+		Type: dto.MessageTypeHeartbeat,
+		Data: dto.HeartbeatRequest{Timestamp: time.Now().Unix()},
+	}))
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, dto.MessageTypeHeartbeatResp, message.Type)
+
+	clientSide.Close()
+	waitServed(t, served)
+}
+
+func TestSendFileChunk_UsesBinaryFrameWhenNegotiated(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	clientSide, clientConn := connectTestClient(t, h)
+	clientConn.binaryFrames = true
+	payload := []byte{0x00, 0x01, 0xFE, 0xFF}
+
+	// Act
+	err := sendFileChunk(clientConn, dto.FileChunk{Type: "file_chunk", TransferID: "transfer-1", ChunkIndex: 3, ChunkSize: len(payload)}, payload)
+
+	// Assert
+	require.NoError(t, err)
+	messageType, data, err := clientSide.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.BinaryMessage, messageType)
+
+	frame, err := dto.DecodeBinaryFrame(data)
+	require.NoError(t, err)
+	assert.Equal(t, dto.BinaryFrameFileChunk, frame.Type)
+	assert.Equal(t, payload, frame.Payload)
+
+	var chunk dto.FileChunk
+	require.NoError(t, json.Unmarshal(frame.Header, &chunk))
+	assert.Equal(t, "transfer-1", chunk.TransferID)
+	assert.Equal(t, 3, chunk.ChunkIndex)
+	assert.Empty(t, chunk.ChunkData)
+}
+
+func TestSendFileChunk_UsesBase64JSONForLegacyClients(t *testing.T) {
+	// Arrange
+	h, _ := newTestWebSocketHandler()
+	clientSide, clientConn := connectTestClient(t, h)
+	payload := []byte("chunk")
+
+	// Act
+	err := sendFileChunk(clientConn, dto.FileChunk{Type: "file_chunk", TransferID: "transfer-1"}, payload)
+
+	// Assert
+	require.NoError(t, err)
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, "file_chunk", message.Type)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(payload), message.Data.(map[string]interface{})["chunk_data"])
+}
+
+func TestNegotiateCapabilities_AcceptsOnlySupportedOnce(t *testing.T) {
+	// Act
+	accepted := negotiateCapabilities([]string{"compression", dto.CapabilityBinaryFrames, dto.CapabilityBinaryFrames})
+
+	// Assert
+	assert.Equal(t, []string{dto.CapabilityBinaryFrames}, accepted)
+	assert.Equal(t, []string{dto.CapabilityAudioStream, dto.CapabilityBinaryFrames},
+		negotiateCapabilities([]string{dto.CapabilityAudioStream, dto.CapabilityBinaryFrames}))
+	assert.Empty(t, negotiateCapabilities(nil))
+}
+
+func TestDecodeBinaryFrame_RejectsMalformedFrames(t *testing.T) {
+	// Arrange
+	valid, err := dto.EncodeBinaryFrame(dto.BinaryFrameScreenFrame, dto.ScreenFrame{SessionID: "session-1"}, []byte("png"))
+	require.NoError(t, err)
+	wrongVersion := append([]byte{}, valid...)
+	wrongVersion[0] = 9
+
+	// Act & Assert
+	for _, data := range [][]byte{nil, valid[:3], valid[:10], wrongVersion} {
+		_, err := dto.DecodeBinaryFrame(data)
+		assert.ErrorIs(t, err, dto.ErrInvalidBinaryFrame)
+	}
+}
+
+// newTestRegistrationHandler prepara un socket autenticado aún sin PC registrado y un administrador que observa los cambios de estado
+func newTestRegistrationHandler(t *testing.T) (*WebSocketHandler, *MockPCService, *ClientConnection, *websocket.Conn, *websocket.Conn) {
+	t.Helper()
+
+	pcService := new(MockPCService)
+	adminHandler := NewAdminWebSocketHandler(nil, nil)
+	adminSide := connectTestAdmin(t, adminHandler)
+	h := NewWebSocketHandler(nil, pcService, nil, nil, nil, adminHandler)
+
+	clientSide, clientConn := connectTestClient(t, h)
+	h.mutex.Lock()
+	delete(h.pcConnections, testTargetPCID)
+	h.mutex.Unlock()
+	clientConn.PCID = ""
+	clientConn.UserID = "owner-id"
+
+	return h, pcService, clientConn, clientSide, adminSide
+}
+
+// readStatusChanges lee del administrador los siguientes count pc_status_changed como pares "old→new"
+func readStatusChanges(t *testing.T, adminSide *websocket.Conn, count int) []string {
+	t.Helper()
+
+	require.NoError(t, adminSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var changes []string
+	for len(changes) < count {
+		var message dto.WebSocketMessage
+		require.NoError(t, adminSide.ReadJSON(&message))
+		if message.Type != "pc_status_changed" {
+			continue
+		}
+		data := message.Data.(map[string]interface{})
+		changes = append(changes, fmt.Sprintf("%s→%s", data["oldStatus"], data["newStatus"]))
+	}
+	return changes
+}
+
+func TestHandlePCRegistration_TransitionsFromConnectingToOnline(t *testing.T) {
+	// Arrange
+	h, pcService, clientConn, clientSide, adminSide := newTestRegistrationHandler(t)
+	connecting := newTestPC(testTargetPCID)
+	connecting.SetConnecting()
+	pcService.On("MarkPCConnecting", mock.Anything, "owner-id", "LAB-PC-01").Return(connecting, nil)
+	pcService.On("RegisterPC", mock.Anything, "owner-id", "LAB-PC-01", "192.168.1.10", clientpc.PCMetadata{}).Return(newTestPC(testTargetPCID), nil)
+
+	// Act
+	h.handlePCRegistration(clientConn.Conn, clientConn, dto.PCRegistrationRequest{PCIdentifier: "LAB-PC-01"}, "192.168.1.10")
+
+	// Assert
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, dto.MessageTypePCRegistrationResp, message.Type)
+	assert.Equal(t, true, message.Data.(map[string]interface{})["success"])
+
+	assert.Equal(t, []string{"OFFLINE→CONNECTING", "CONNECTING→ONLINE"}, readStatusChanges(t, adminSide, 2))
+	assert.Equal(t, testTargetPCID, clientConn.PCID)
+	pcService.AssertNotCalled(t, "UpdatePCConnectionStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandlePCRegistration_PassesReportedMetadataToService(t *testing.T) {
+	// Arrange
+	h, pcService, clientConn, clientSide, _ := newTestRegistrationHandler(t)
+	connecting := newTestPC(testTargetPCID)
+	connecting.SetConnecting()
+	metadata := clientpc.PCMetadata{OS: "Windows 11 Pro", Hostname: "LAB-PC-01", AgentVersion: "1.4.2"}
+	pcService.On("MarkPCConnecting", mock.Anything, "owner-id", "LAB-PC-01").Return(connecting, nil)
+	pcService.On("RegisterPC", mock.Anything, "owner-id", "LAB-PC-01", "192.168.1.10", metadata).Return(newTestPC(testTargetPCID), nil)
+
+	// Act
+	h.handlePCRegistration(clientConn.Conn, clientConn, dto.PCRegistrationRequest{
+		PCIdentifier: "LAB-PC-01",
+		OS:           "Windows 11 Pro",
+		Hostname:     "LAB-PC-01",
+		AgentVersion: "1.4.2",
+	}, "192.168.1.10")
+
+	// Assert
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, true, message.Data.(map[string]interface{})["success"])
+	pcService.AssertCalled(t, "RegisterPC", mock.Anything, "owner-id", "LAB-PC-01", "192.168.1.10", metadata)
+}
+
+func TestHandlePCRegistration_FailedRegistrationReturnsConnectingPCToOffline(t *testing.T) {
+	// Arrange
+	h, pcService, clientConn, clientSide, adminSide := newTestRegistrationHandler(t)
+	connecting := newTestPC(testTargetPCID)
+	connecting.SetConnecting()
+	pcService.On("MarkPCConnecting", mock.Anything, "owner-id", "LAB-PC-01").Return(connecting, nil)
+	pcService.On("RegisterPC", mock.Anything, "owner-id", "LAB-PC-01", "192.168.1.10", clientpc.PCMetadata{}).Return(nil, errors.New("database unavailable"))
+	pcService.On("UpdatePCConnectionStatus", mock.Anything, testTargetPCID, clientpc.PCConnectionStatusOffline).Return(nil)
+
+	// Act
+	h.handlePCRegistration(clientConn.Conn, clientConn, dto.PCRegistrationRequest{PCIdentifier: "LAB-PC-01"}, "192.168.1.10")
+
+	// Assert
+	var message dto.WebSocketMessage
+	require.NoError(t, clientSide.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, clientSide.ReadJSON(&message))
+	assert.Equal(t, false, message.Data.(map[string]interface{})["success"])
+
+	assert.Equal(t, []string{"OFFLINE→CONNECTING", "CONNECTING→OFFLINE"}, readStatusChanges(t, adminSide, 2))
+	assert.Empty(t, clientConn.PCID)
+	pcService.AssertCalled(t, "UpdatePCConnectionStatus", mock.Anything, testTargetPCID, clientpc.PCConnectionStatusOffline)
+}
+
+func TestSendRemoteControlRequestToClient_AdvertisesCleanupApprovalTimeout(t *testing.T) {
+	// Arrange
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionService := remotesessionservice.NewRemoteSessionService(sessionRepo, nil, nil, nil, nil)
+	sessionService.SetApprovalTimeout(45 * time.Second)
+	h := NewWebSocketHandler(nil, nil, sessionService, nil, nil, nil)
+	clientSide, _ := connectTestClient(t, h)
+
+	// Act
+	require.NoError(t, h.SendRemoteControlRequestToClient("session-1", testTargetPCID, "admin-id", "admin"))
+
+	// Assert - el plazo anunciado es exactamente el umbral con el que la limpieza rechaza la solicitud
+	messageType, data := readClientMessage(t, clientSide)
+	require.Equal(t, "remote_control_request", messageType)
+	advertised := time.Duration(data["approval_timeout_seconds"].(float64)) * time.Second
+	assert.Equal(t, sessionService.ApprovalTimeout(), advertised)
+
+	sentAt := time.Now().UTC()
+	justInTime := sentAt.Add(-advertised + time.Second)
+	tooLate := sentAt.Add(-advertised - time.Second)
+	answeredInTime := remotesession.NewRemoteSessionFromDB("answered-in-time", "admin-id", testTargetPCID, nil, nil,
+		remotesession.StatusPendingApproval, nil, justInTime, justInTime)
+	unanswered := remotesession.NewRemoteSessionFromDB("unanswered", "admin-id", testTargetPCID, nil, nil,
+		remotesession.StatusPendingApproval, nil, tooLate, tooLate)
+	sessionRepo.On("FindByClientPCID", mock.Anything, testTargetPCID).Return([]*remotesession.RemoteSession{
+		answeredInTime, unanswered,
+	}, nil)
+	sessionRepo.On("FindById", mock.Anything, "unanswered").Return(unanswered, nil)
+	sessionRepo.On("UpdateStatus", mock.Anything, "unanswered", remotesession.StatusRejected).Return(nil)
+
+	require.NoError(t, sessionService.CleanupStuckSessions(context.Background(), testTargetPCID))
+	sessionRepo.AssertExpectations(t)
+	sessionRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, "answered-in-time", mock.Anything)
+}
@@ -0,0 +1,14 @@
+package dto
+
+// ActionTypeFilterRequest nuevo filtro de tipos de acción del audit log
+type ActionTypeFilterRequest struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// ActionTypeFilterResponse filtro vigente y tipos de acción que se registran siempre
+type ActionTypeFilterResponse struct {
+	Allow        []string `json:"allow"`
+	Deny         []string `json:"deny"`
+	AlwaysLogged []string `json:"always_logged"`
+}
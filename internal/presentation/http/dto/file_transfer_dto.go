@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// FileTransferDTO representa una transferencia de archivo en las respuestas de la API
+type FileTransferDTO struct {
+	TransferID      string  `json:"transfer_id"`
+	FileName        string  `json:"file_name"`
+	TargetPCID      string  `json:"target_pc_id"`
+	SessionID       string  `json:"session_id"`
+	Status          string  `json:"status"`
+	FileSizeMB      float64 `json:"file_size_mb"`
+	DestinationPath string  `json:"destination_path"`
+	ConflictPolicy  string  `json:"conflict_policy"`
+	// TransferTime momento en que se completó la transferencia; null mientras no se haya completado
+	TransferTime *time.Time `json:"transfer_time"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	// QueuedBehindTransferID transferencia al mismo destino que debe terminar antes de enviar esta
+	QueuedBehindTransferID string `json:"queued_behind_transfer_id,omitempty"`
+}
+
+// FileTransferListResponse representa los datos de los endpoints que listan transferencias
+type FileTransferListResponse struct {
+	Transfers []FileTransferDTO `json:"transfers"`
+	Count     int               `json:"count"`
+}
@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/macro"
+)
+
+// SaveMacroRequest datos para guardar la grabación en curso como macro
+type SaveMacroRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// MacroCommandDTO comando de una macro; delay_ms es la espera desde el comando anterior
+type MacroCommandDTO struct {
+	EventType string                 `json:"event_type"`
+	Action    string                 `json:"action"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
+	DelayMs   int64                  `json:"delay_ms"`
+}
+
+// MacroDTO representa una macro de input en las respuestas de la API
+type MacroDTO struct {
+	MacroID         string            `json:"macro_id"`
+	Name            string            `json:"name"`
+	SourceSessionID string            `json:"source_session_id,omitempty"`
+	CommandCount    int               `json:"command_count"`
+	DurationMs      int64             `json:"duration_ms"`
+	CreatedAt       time.Time         `json:"created_at"`
+	Commands        []MacroCommandDTO `json:"commands"`
+}
+
+// MacroListResponse representa los datos del endpoint de listado de macros
+type MacroListResponse struct {
+	Macros []MacroDTO `json:"macros"`
+	Count  int        `json:"count"`
+}
+
+// MacroRecordingResponse confirma el inicio de la grabación de una macro
+type MacroRecordingResponse struct {
+	SessionID string `json:"session_id"`
+	Recording bool   `json:"recording"`
+}
+
+// MacroReplayResponse confirma que la reproducción de la macro empezó en la sesión
+type MacroReplayResponse struct {
+	MacroID      string `json:"macro_id"`
+	SessionID    string `json:"session_id"`
+	CommandCount int    `json:"command_count"`
+	DurationMs   int64  `json:"duration_ms"`
+}
+
+// NewMacroDTO convierte una macro del dominio a su DTO
+func NewMacroDTO(m *macro.Macro) MacroDTO {
+	commands := make([]MacroCommandDTO, 0, m.CommandCount())
+	for _, command := range m.Commands() {
+		commands = append(commands, MacroCommandDTO{
+			EventType: command.EventType,
+			Action:    command.Action,
+			Payload:   command.Payload,
+			DelayMs:   command.Delay.Milliseconds(),
+		})
+	}
+
+	return MacroDTO{
+		MacroID:         m.MacroID(),
+		Name:            m.Name(),
+		SourceSessionID: m.SourceSessionID(),
+		CommandCount:    m.CommandCount(),
+		DurationMs:      m.Duration().Milliseconds(),
+		CreatedAt:       m.CreatedAt(),
+		Commands:        commands,
+	}
+}
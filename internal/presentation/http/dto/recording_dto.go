@@ -0,0 +1,76 @@
+package dto
+
+import "time"
+
+// RecordingDTO representa una grabación de frames en las respuestas de la API. FPSSource es "frame_timestamps" si
+// los FPS se midieron con los timestamps de los frames o "duration" si son frames entre duración.
+type RecordingDTO struct {
+	VideoID           string    `json:"video_id"`
+	SessionID         string    `json:"session_id"`
+	RecordedAt        time.Time `json:"recorded_at"`
+	DurationSeconds   int       `json:"duration_seconds"`
+	TotalFrames       int       `json:"total_frames"`
+	FPS               float64   `json:"fps"`
+	FPSSource         string    `json:"fps_source"`
+	VariableFrameRate bool      `json:"variable_frame_rate"`
+	FileSizeMB        float64   `json:"file_size_mb"`
+	SessionStatus     string    `json:"session_status,omitempty"`
+	// FrameCountUnavailable no se pudieron contar los frames: total_frames (y los fps por duración) valen 0
+	FrameCountUnavailable bool `json:"frame_count_unavailable,omitempty"`
+}
+
+// ClientRecordingsDTO agrupa las grabaciones de un PC cliente
+type ClientRecordingsDTO struct {
+	ClientPCID string         `json:"client_pc_id"`
+	ClientName string         `json:"client_name,omitempty"`
+	Recordings []RecordingDTO `json:"recordings"`
+	Count      int            `json:"count"`
+}
+
+// AllRecordingsResponse representa los datos del endpoint de grabaciones agrupadas por cliente
+type AllRecordingsResponse struct {
+	Clients []ClientRecordingsDTO `json:"clients"`
+	Count   int                   `json:"count"`
+}
+
+// RecordingStorageDTO representa el espacio que ocupa una grabación en disco
+type RecordingStorageDTO struct {
+	VideoID       string  `json:"video_id"`
+	SessionID     string  `json:"session_id"`
+	FrameCount    int     `json:"frame_count"`
+	TotalBytes    int64   `json:"total_bytes"`
+	TotalSizeMB   float64 `json:"total_size_mb"`
+	StorageFormat string  `json:"storage_format"`
+	MP4Exported   bool    `json:"mp4_exported"`
+	MP4Path       string  `json:"mp4_path,omitempty"`
+}
+
+// ActiveRecordingDTO representa una grabación que todavía recibe frames
+type ActiveRecordingDTO struct {
+	VideoID   string    `json:"video_id"`
+	SessionID string    `json:"session_id"`
+	StartedAt time.Time `json:"started_at"`
+	Frames    int       `json:"frames"`
+}
+
+// ActiveRecordingsResponse representa los datos del endpoint de grabaciones en curso
+type ActiveRecordingsResponse struct {
+	Recordings []ActiveRecordingDTO `json:"recordings"`
+	Count      int                  `json:"count"`
+}
+
+// RecordingThumbnailDTO miniatura de un frame; Data es el JPEG en base64
+type RecordingThumbnailDTO struct {
+	FrameIndex int    `json:"frame_index"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Data       string `json:"data"`
+}
+
+// RecordingThumbnailsResponse representa los datos del endpoint de miniaturas de una grabación
+type RecordingThumbnailsResponse struct {
+	VideoID     string                  `json:"video_id"`
+	TotalFrames int                     `json:"total_frames"`
+	Thumbnails  []RecordingThumbnailDTO `json:"thumbnails"`
+	Count       int                     `json:"count"`
+}
@@ -7,7 +7,11 @@ import (
 
 // InitiateSessionRequest representa la solicitud para iniciar una sesión remota
 type InitiateSessionRequest struct {
-	ClientPCID string `json:"client_pc_id" binding:"required"`
+	ClientPCID     string `json:"client_pc_id" binding:"required"`
+	QueueIfOffline bool   `json:"queue_if_offline"` // si el PC está offline, deja la solicitud en cola hasta que se conecte
+	// Reason y TicketID justifican la conexión; obligatorio al menos uno si SESSION_REQUIRE_JUSTIFICATION=true
+	Reason   string `json:"reason,omitempty"`
+	TicketID string `json:"ticket_id,omitempty"`
 }
 
 // Validate valida la solicitud de iniciación de sesión
@@ -18,13 +22,11 @@ func (req *InitiateSessionRequest) Validate() error {
 	return nil
 }
 
-// InitiateSessionResponse representa la respuesta de iniciación de sesión
+// InitiateSessionResponse representa los datos de la respuesta de iniciación de sesión
 type InitiateSessionResponse struct {
-	Success   bool   `json:"success"`
-	SessionID string `json:"session_id,omitempty"`
-	Status    string `json:"status,omitempty"`
+	SessionID string `json:"session_id"`
+	Status    string `json:"status"`
 	Message   string `json:"message,omitempty"`
-	Error     string `json:"error,omitempty"`
 }
 
 // SessionStatusResponse representa la respuesta de estado de sesión
@@ -38,17 +40,39 @@ type SessionStatusResponse struct {
 	Duration     *time.Duration `json:"duration,omitempty"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
+	// Reason y TicketID motivo de la conexión indicado al iniciar la sesión
+	Reason       string         `json:"reason,omitempty"`
+	TicketID     string         `json:"ticket_id,omitempty"`
+	// Recording indica si la sesión tiene una grabación en curso
+	Recording    bool           `json:"recording"`
+	// ClientActivity presencia del usuario en el PC según los informes del cliente (omitido si no informó)
+	ClientActivity *ClientActivityDTO `json:"client_activity,omitempty"`
+	// SupportedInputEvents event_type de input que el PC declaró al registrarse (omitido si los admite todos)
+	SupportedInputEvents []string `json:"supported_input_events,omitempty"`
+}
+
+// ClientActivityDTO presencia del usuario en el PC durante la sesión
+type ClientActivityDTO struct {
+	Status              string    `json:"status"`
+	LastInputAgeSeconds int64     `json:"last_input_age_seconds"`
+	ReportedAt          time.Time `json:"reported_at"`
+	IdleCount           int       `json:"idle_count"`
+	IdleSeconds         int64     `json:"idle_seconds"`
 }
 
 // SessionSummaryDTO representa un resumen de sesión
 type SessionSummaryDTO struct {
-	SessionID   string     `json:"session_id"`
-	AdminUserID string     `json:"admin_user_id"`
-	ClientPCID  string     `json:"client_pc_id"`
-	Status      string     `json:"status"`
-	StartTime   *time.Time `json:"start_time,omitempty"`
-	EndTime     *time.Time `json:"end_time,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
+	SessionID     string     `json:"session_id"`
+	AdminUserID   string     `json:"admin_user_id"`
+	AdminUsername string     `json:"admin_username,omitempty"`
+	ClientPCID    string     `json:"client_pc_id"`
+	ClientPCName  string     `json:"client_pc_name,omitempty"`
+	Status        string     `json:"status"`
+	StartTime     *time.Time `json:"start_time,omitempty"`
+	EndTime       *time.Time `json:"end_time,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+	TicketID      string     `json:"ticket_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
 }
 
 // ActiveSessionsResponse representa la respuesta de sesiones activas
@@ -63,9 +87,27 @@ type UserSessionsResponse struct {
 	Count    int                 `json:"count"`
 }
 
-// ErrorResponse representa una respuesta de error
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
-} 
\ No newline at end of file
+// AccessReportResponse representa las sesiones del informe de accesos (qué administrador controló qué PC y cuándo)
+type AccessReportResponse struct {
+	Sessions []SessionSummaryDTO `json:"sessions"`
+	Count    int                 `json:"count"`
+}
+
+// EndSessionResponse representa los datos de la respuesta de finalización de sesión
+type EndSessionResponse struct {
+	SessionID string `json:"session_id"`
+	Status    string `json:"status"`
+}
+
+// TransferSessionRequest representa la solicitud para traspasar una sesión activa a otro administrador
+type TransferSessionRequest struct {
+	ToAdminID string `json:"to_admin_id" binding:"required"`
+}
+
+// TransferSessionResponse representa los datos de la respuesta del traspaso de sesión
+type TransferSessionResponse struct {
+	SessionID   string `json:"session_id"`
+	Status      string `json:"status"`
+	FromAdminID string `json:"from_admin_id"`
+	ToAdminID   string `json:"to_admin_id"`
+}
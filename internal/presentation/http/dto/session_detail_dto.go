@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+// SessionDetailResponse todo lo que muestra la página de detalle de una sesión en una sola respuesta
+type SessionDetailResponse struct {
+	Session   SessionStatusResponse     `json:"session"`
+	Transfers []FileTransferDTO         `json:"transfers"`
+	Recording *RecordingDTO             `json:"recording"` // null si la sesión no tiene grabación
+	Timeline  []SessionTimelineEntryDTO `json:"timeline"`
+}
+
+// SessionTimelineEntryDTO entrada del audit log de la sesión, en orden cronológico
+type SessionTimelineEntryDTO struct {
+	Timestamp         time.Time              `json:"timestamp"`
+	ActionType        string                 `json:"action_type"`
+	Description       string                 `json:"description"`
+	PerformedByUserID string                 `json:"performed_by_user_id"`
+	Details           map[string]interface{} `json:"details,omitempty"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// BackgroundTaskDTO representa una goroutine de transferencia en curso
+type BackgroundTaskDTO struct {
+	ID         string    `json:"id"`
+	Kind       string    `json:"kind"`
+	TransferID string    `json:"transfer_id,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	Status     string    `json:"status"`
+}
+
+// BackgroundTasksResponse representa los datos del endpoint de tareas en segundo plano
+type BackgroundTasksResponse struct {
+	Tasks []BackgroundTaskDTO `json:"tasks"`
+	Count int                 `json:"count"`
+}
+
+// BackgroundJobDTO representa un trabajo periódico del servidor en las respuestas de la API
+type BackgroundJobDTO struct {
+	Name                   string     `json:"name"`
+	IntervalSeconds        float64    `json:"interval_seconds"`
+	Running                bool       `json:"running"`
+	Runs                   int        `json:"runs"`
+	LastRunAt              *time.Time `json:"last_run_at,omitempty"`
+	LastRunDurationSeconds float64    `json:"last_run_duration_seconds"`
+	LastError              string     `json:"last_error,omitempty"`
+	NextRunAt              time.Time  `json:"next_run_at"`
+}
+
+// BackgroundJobsResponse representa los datos del endpoint de trabajos periódicos
+type BackgroundJobsResponse struct {
+	Jobs  []BackgroundJobDTO `json:"jobs"`
+	Count int                `json:"count"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// UserTokensRevokedResponse representa los datos de la respuesta de revocación de tokens de un usuario
+type UserTokensRevokedResponse struct {
+	UserID            string    `json:"user_id"`
+	Username          string    `json:"username"`
+	ClosedConnections int       `json:"closed_connections"`
+	RevokedAt         time.Time `json:"revoked_at"`
+}
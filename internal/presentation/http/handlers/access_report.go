@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// accessReportDateLayout formato de fecha sin hora aceptado por from y to
+const accessReportDateLayout = "2006-01-02"
+
+// GetAccessReport maneja GET /api/v1/admin/access-report?admin_user_id=&pc_id=&from=&to=
+func (rch *RemoteControlHandler) GetAccessReport(c *gin.Context) {
+	from, err := parseAccessReportTime(c.Query("from"), false)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "INVALID_DATE", "from must be RFC3339 or YYYY-MM-DD")
+		return
+	}
+	to, err := parseAccessReportTime(c.Query("to"), true)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "INVALID_DATE", "to must be RFC3339 or YYYY-MM-DD")
+		return
+	}
+
+	// La página se toma en SQL (LIMIT/OFFSET) y el total con un COUNT(*) sobre los mismos filtros
+	page := response.ParsePageRequest(c)
+	sessions, total, err := rch.sessionService.GetAccessReport(c.Request.Context(), c.Query("admin_user_id"), c.Query("pc_id"), from, to, page.Limit, page.Offset)
+	if err != nil {
+		if errors.Is(err, remotesessionservice.ErrInvalidAccessReportRange) {
+			response.Error(c, http.StatusBadRequest, "INVALID_DATE_RANGE", "from must not be after to")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "ACCESS_REPORT_FAILED", err.Error())
+		return
+	}
+
+	pcNames, adminUsernames := rch.resolveSessionLabels(c, sessions)
+
+	sessionDTOs := make([]dto.SessionSummaryDTO, 0, len(sessions))
+	for _, session := range sessions {
+		sessionDTOs = append(sessionDTOs, dto.SessionSummaryDTO{
+			SessionID:     session.SessionID(),
+			AdminUserID:   session.AdminUserID(),
+			AdminUsername: adminUsernames[session.AdminUserID()],
+			ClientPCID:    session.ClientPCID(),
+			ClientPCName:  pcNames[session.ClientPCID()],
+			Status:        string(session.Status()),
+			StartTime:     session.StartTime(),
+			EndTime:       session.EndTime(),
+			Reason:        session.Justification().Reason,
+			TicketID:      session.Justification().TicketID,
+			CreatedAt:     session.CreatedAt(),
+		})
+	}
+
+	response.SuccessPage(c, http.StatusOK, dto.AccessReportResponse{
+		Sessions: sessionDTOs,
+		Count:    len(sessionDTOs),
+	}, page.Meta(total))
+}
+
+// parseAccessReportTime interpreta RFC3339 o una fecha YYYY-MM-DD (UTC); como límite superior, una fecha sin hora
+// incluye el día completo. Vacío retorna la fecha cero (sin filtro).
+func parseAccessReportTime(raw string, endOfDay bool) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	day, err := time.Parse(accessReportDateLayout, raw)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		return day.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return day, nil
+}
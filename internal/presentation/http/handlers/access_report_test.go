@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// newAccessReportHandler handler cuyo servicio resuelve los nombres del PC y el administrador de prueba
+func newAccessReportHandler() (*RemoteControlHandler, *MockRemoteSessionRepository) {
+	sessionRepo := new(MockRemoteSessionRepository)
+	userRepo := new(MockUserRepository)
+	pcRepo := new(MockClientPCRepository)
+
+	pc, _ := clientpc.NewClientPC(testClientPCID, "lab-pc-01", "192.168.1.50", testAdminUserID)
+	pcRepo.On("FindByIDs", mock.Anything, mock.Anything).Return(map[string]*clientpc.ClientPC{testClientPCID: pc}, nil)
+	userRepo.On("FindByIDs", mock.Anything, mock.Anything).Return(map[string]*user.User{
+		testAdminUserID: user.NewUser(testAdminUserID, "admin", "", "hashed", user.RoleAdministrator),
+	}, nil)
+
+	sessionService := remotesessionservice.NewRemoteSessionService(sessionRepo, userRepo, pcRepo, nil, nil)
+	return NewRemoteControlHandler(sessionService, nil), sessionRepo
+}
+
+// serveAccessReport envía GET /access-report con la query indicada
+func serveAccessReport(handler *RemoteControlHandler, query string) *httptest.ResponseRecorder {
+	router := newTestAdminRouter()
+	router.GET("/api/v1/admin/access-report", handler.GetAccessReport)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/access-report"+query, nil))
+	return recorder
+}
+
+func TestRemoteControlHandler_GetAccessReport_PassesFilterCombinations(t *testing.T) {
+	from := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		query       string
+		adminUserID string
+		clientPCID  string
+		from        time.Time
+		to          time.Time
+	}{
+		{name: "no filters", query: ""},
+		{name: "admin only", query: "?admin_user_id=" + testAdminUserID, adminUserID: testAdminUserID},
+		{name: "pc only", query: "?pc_id=" + testClientPCID, clientPCID: testClientPCID},
+		{
+			name:        "admin and pc in range",
+			query:       "?admin_user_id=" + testAdminUserID + "&pc_id=" + testClientPCID + "&from=2025-03-01T08:00:00Z&to=2025-03-31T18:00:00Z",
+			adminUserID: testAdminUserID, clientPCID: testClientPCID, from: from, to: to,
+		},
+		{
+			name:  "date only range covers the whole last day",
+			query: "?from=2025-03-01&to=2025-03-31",
+			from:  time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler, sessionRepo := newAccessReportHandler()
+			sessionRepo.On("FindAccessReport", mock.Anything, tt.adminUserID, tt.clientPCID, tt.from, tt.to, response.DefaultPageLimit, 0).
+				Return([]*remotesession.RemoteSession{}, nil)
+			sessionRepo.On("CountAccessReport", mock.Anything, tt.adminUserID, tt.clientPCID, tt.from, tt.to).Return(0, nil)
+
+			// Act
+			recorder := serveAccessReport(handler, tt.query)
+
+			// Assert
+			data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+			assert.Equal(t, []interface{}{}, data["sessions"])
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRemoteControlHandler_GetAccessReport_ReturnsStartEndAndStatus(t *testing.T) {
+	// Arrange
+	handler, sessionRepo := newAccessReportHandler()
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	end := start.Add(45 * time.Minute)
+	session := remotesession.NewRemoteSessionFromDB("session-1", testAdminUserID, testClientPCID,
+		&start, &end, remotesession.StatusEnded, nil, start, end)
+	sessionRepo.On("FindAccessReport", mock.Anything, testAdminUserID, testClientPCID, time.Time{}, time.Time{}, response.DefaultPageLimit, 0).
+		Return([]*remotesession.RemoteSession{session}, nil)
+	sessionRepo.On("CountAccessReport", mock.Anything, testAdminUserID, testClientPCID, time.Time{}, time.Time{}).Return(1, nil)
+
+	// Act
+	recorder := serveAccessReport(handler, "?admin_user_id="+testAdminUserID+"&pc_id="+testClientPCID)
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	sessions, ok := data["sessions"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, sessions, 1)
+	entry := sessions[0].(map[string]interface{})
+	assert.Equal(t, "session-1", entry["session_id"])
+	assert.Equal(t, "admin", entry["admin_username"])
+	assert.Equal(t, "lab-pc-01", entry["client_pc_name"])
+	assert.Equal(t, string(remotesession.StatusEnded), entry["status"])
+	assert.Equal(t, "2025-03-10T09:00:00Z", entry["start_time"])
+	assert.Equal(t, "2025-03-10T09:45:00Z", entry["end_time"])
+}
+
+func TestRemoteControlHandler_GetAccessReport_InvalidDatesReturnBadRequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		expectedCode string
+	}{
+		{name: "malformed from", query: "?from=yesterday", expectedCode: "INVALID_DATE"},
+		{name: "malformed to", query: "?to=31/03/2025", expectedCode: "INVALID_DATE"},
+		{name: "from after to", query: "?from=2025-04-01&to=2025-03-01", expectedCode: "INVALID_DATE_RANGE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler, sessionRepo := newAccessReportHandler()
+
+			// Act
+			recorder := serveAccessReport(handler, tt.query)
+
+			// Assert
+			assertErrorEnvelope(t, recorder, http.StatusBadRequest, tt.expectedCode)
+			sessionRepo.AssertNotCalled(t, "FindAccessReport", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRemoteControlHandler_GetAccessReport_PagesInRepository(t *testing.T) {
+	// Arrange - el repositorio ya devuelve solo la página; el total viene del COUNT
+	handler, sessionRepo := newAccessReportHandler()
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	session := remotesession.NewRemoteSessionFromDB("session-21", testAdminUserID, testClientPCID,
+		&start, nil, remotesession.StatusActive, nil, start, start)
+	sessionRepo.On("FindAccessReport", mock.Anything, testAdminUserID, "", time.Time{}, time.Time{}, 10, 20).
+		Return([]*remotesession.RemoteSession{session}, nil)
+	sessionRepo.On("CountAccessReport", mock.Anything, testAdminUserID, "", time.Time{}, time.Time{}).Return(21, nil)
+
+	// Act
+	recorder := serveAccessReport(handler, "?admin_user_id="+testAdminUserID+"&limit=10&offset=20")
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Len(t, data["sessions"], 1)
+	assertPageMeta(t, recorder, dto.PageMeta{Limit: 10, Offset: 20, Total: 21, HasMore: false})
+}
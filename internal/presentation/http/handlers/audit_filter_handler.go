@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// AuditFilterHandler consulta y cambia en caliente qué tipos de acción se guardan en el audit log
+type AuditFilterHandler struct {
+	filterService actionlogservice.IActionTypeFilterService
+}
+
+// NewAuditFilterHandler crea una nueva instancia del handler del filtro del audit log
+func NewAuditFilterHandler(filterService actionlogservice.IActionTypeFilterService) *AuditFilterHandler {
+	return &AuditFilterHandler{
+		filterService: filterService,
+	}
+}
+
+// GetActionFilter maneja GET /api/v1/admin/audit/action-filter
+func (h *AuditFilterHandler) GetActionFilter(c *gin.Context) {
+	if _, ok := requireAdministrator(c); !ok {
+		return
+	}
+
+	response.Success(c, http.StatusOK, toActionTypeFilterResponse(h.filterService.ActionTypeFilter()))
+}
+
+// UpdateActionFilter maneja PUT /api/v1/admin/audit/action-filter. Los tipos obligatorios pueden aparecer
+// en deny, pero se siguen registrando.
+func (h *AuditFilterHandler) UpdateActionFilter(c *gin.Context) {
+	claims, ok := requireAdministrator(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ActionTypeFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body: "+err.Error())
+		return
+	}
+
+	filter := actionlogservice.ActionTypeFilter{
+		Allow: toActionTypes(req.Allow),
+		Deny:  toActionTypes(req.Deny),
+	}
+	h.filterService.SetActionTypeFilter(filter)
+	log.Printf("🗂️ AUDIT FILTER: Admin %s set allow=%v deny=%v", claims.UserID, filter.Allow, filter.Deny)
+
+	response.Success(c, http.StatusOK, toActionTypeFilterResponse(filter))
+}
+
+// toActionTypes normaliza los nombres de tipos de acción recibidos
+func toActionTypes(names []string) []actionlog.ActionType {
+	return actionlogservice.ParseActionTypeList(strings.Join(names, ","))
+}
+
+// toActionTypeFilterResponse convierte el filtro a DTO
+func toActionTypeFilterResponse(filter actionlogservice.ActionTypeFilter) dto.ActionTypeFilterResponse {
+	return dto.ActionTypeFilterResponse{
+		Allow:        actionTypeNames(filter.Allow),
+		Deny:         actionTypeNames(filter.Deny),
+		AlwaysLogged: actionTypeNames(actionlogservice.MandatoryActionTypes()),
+	}
+}
+
+// actionTypeNames convierte tipos de acción a texto; nunca retorna nil para serializar [] en vez de null
+func actionTypeNames(types []actionlog.ActionType) []string {
+	names := make([]string, 0, len(types))
+	for _, actionType := range types {
+		names = append(names, string(actionType))
+	}
+	return names
+}
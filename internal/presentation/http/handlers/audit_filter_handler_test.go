@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+)
+
+func TestAuditFilterHandler_UpdateActionFilter_AppliesFilterAtRuntime(t *testing.T) {
+	// Arrange
+	filterService := actionlogservice.NewActionLogService(nil).(*actionlogservice.ActionLogService)
+	handler := NewAuditFilterHandler(filterService)
+
+	router := newTestRouter()
+	router.PUT("/api/v1/admin/audit/action-filter", withRole(user.RoleAdministrator), handler.UpdateActionFilter)
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPut, "/api/v1/admin/audit/action-filter",
+		strings.NewReader(`{"deny": ["pc_status_changed", "USER_LOGIN"]}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	// Act
+	router.ServeHTTP(recorder, request)
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, []interface{}{"PC_STATUS_CHANGED", "USER_LOGIN"}, data["deny"])
+	assert.Contains(t, data["always_logged"], "USER_LOGIN")
+
+	filter := filterService.ActionTypeFilter()
+	assert.False(t, filter.Allows(actionlog.ActionPCStatusChanged))
+	assert.True(t, filter.Allows(actionlog.ActionUserLogin))
+}
+
+func TestAuditFilterHandler_UpdateActionFilter_RejectsNonAdministrators(t *testing.T) {
+	// Arrange
+	handler := NewAuditFilterHandler(actionlogservice.NewActionLogService(nil).(*actionlogservice.ActionLogService))
+
+	router := newTestRouter()
+	router.PUT("/api/v1/admin/audit/action-filter", withRole(user.RoleClientUser), handler.UpdateActionFilter)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/v1/admin/audit/action-filter", strings.NewReader(`{}`)))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED")
+}
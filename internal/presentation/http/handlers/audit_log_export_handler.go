@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// auditLogCSVFlushRows filas entre envíos al cliente: el export se transmite mientras se lee de la BD
+const auditLogCSVFlushRows = 500
+
+// auditLogCSVHeader columnas del export; details es el JSON de detalles en una sola columna
+var auditLogCSVHeader = []string{
+	"log_id", "timestamp", "action_type", "description", "performed_by_user_id",
+	"subject_entity_id", "subject_entity_type", "details",
+}
+
+// AuditLogExportHandler exporta el audit log filtrado como CSV para hojas de cálculo y SIEM
+type AuditLogExportHandler struct {
+	exportService actionlogservice.IAuditLogExportService
+}
+
+// NewAuditLogExportHandler crea una nueva instancia del handler de exportación del audit log
+func NewAuditLogExportHandler(exportService actionlogservice.IAuditLogExportService) *AuditLogExportHandler {
+	return &AuditLogExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportCSV maneja GET /api/v1/admin/audit-logs/export.csv?user_id=&action_type=&entity_id=&entity_type=.
+// Los filtros se combinan; sin filtros se exporta todo el audit log, del más antiguo al más reciente.
+func (h *AuditLogExportHandler) ExportCSV(c *gin.Context) {
+	claims, ok := requireAdministrator(c)
+	if !ok {
+		return
+	}
+
+	filter := interfaces.ActionLogFilter{
+		PerformedByUserID: strings.TrimSpace(c.Query("user_id")),
+		ActionType:        actionlog.ActionType(strings.ToUpper(strings.TrimSpace(c.Query("action_type")))),
+		SubjectEntityID:   strings.TrimSpace(c.Query("entity_id")),
+		SubjectEntityType: strings.TrimSpace(c.Query("entity_type")),
+	}
+
+	// La respuesta empieza con la primera fila: si la consulta falla antes, aún se puede responder con un error
+	var writer *csv.Writer
+	rows := 0
+	start := func() error {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="audit-logs-`+time.Now().UTC().Format("20060102-150405")+`.csv"`)
+		c.Status(http.StatusOK)
+		writer = csv.NewWriter(c.Writer)
+		return writer.Write(auditLogCSVHeader)
+	}
+
+	err := h.exportService.ExportLogs(c.Request.Context(), filter, func(entry *actionlog.ActionLog) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(auditLogCSVRecord(entry)); err != nil {
+			return err
+		}
+		rows++
+		if rows%auditLogCSVFlushRows == 0 {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+
+	switch {
+	case err != nil && writer == nil:
+		log.Printf("❌ AUDIT EXPORT: Error exporting audit logs for admin %s: %v", claims.UserID, err)
+		response.Error(c, http.StatusInternalServerError, "AUDIT_EXPORT_FAILED", "Failed to export audit logs")
+		return
+	case err != nil && isClientAbort(err):
+		log.Printf("⏹️ AUDIT EXPORT: Admin %s closed the export after %d rows: %v", claims.UserID, rows, err)
+		c.Abort()
+		return
+	case err != nil:
+		// Las cabeceras ya se enviaron: solo queda cortar el CSV
+		log.Printf("❌ AUDIT EXPORT: Export for admin %s interrupted after %d rows: %v", claims.UserID, rows, err)
+		c.Abort()
+		return
+	}
+
+	if writer == nil {
+		if err := start(); err != nil {
+			log.Printf("❌ AUDIT EXPORT: Error writing CSV header: %v", err)
+			return
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("❌ AUDIT EXPORT: Error sending export to admin %s: %v", claims.UserID, err)
+		return
+	}
+	log.Printf("🗂️ AUDIT EXPORT: Admin %s exported %d audit logs (user=%q action_type=%q entity=%q/%q)",
+		claims.UserID, rows, filter.PerformedByUserID, filter.ActionType, filter.SubjectEntityID, filter.SubjectEntityType)
+}
+
+// auditLogCSVRecord convierte un log en una fila del CSV
+func auditLogCSVRecord(entry *actionlog.ActionLog) []string {
+	details := ""
+	if entry.Details() != nil {
+		if raw, err := json.Marshal(entry.Details()); err == nil {
+			details = string(raw)
+		}
+	}
+
+	return []string{
+		strconv.FormatInt(entry.LogID(), 10),
+		entry.Timestamp().UTC().Format(time.RFC3339),
+		csvSafeField(string(entry.ActionType())),
+		csvSafeField(entry.Description()),
+		csvSafeField(entry.PerformedByUserID()),
+		csvSafeField(optionalString(entry.SubjectEntityID())),
+		csvSafeField(optionalString(entry.SubjectEntityType())),
+		details,
+	}
+}
+
+// csvSafeField evita que una hoja de cálculo interprete como fórmula un texto que viene del cliente
+// (p. ej. el identificador de un PC que empieza por "=")
+func csvSafeField(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// optionalString retorna el valor o vacío si es nil
+func optionalString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
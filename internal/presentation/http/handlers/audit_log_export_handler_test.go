@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+)
+
+// memoryActionLogRepository guarda los logs en memoria y los recorre aplicando el filtro como la consulta SQL
+type memoryActionLogRepository struct {
+	interfaces.IActionLogRepository
+	logs      []*actionlog.ActionLog
+	streamErr error
+}
+
+func (r *memoryActionLogRepository) Save(ctx context.Context, log *actionlog.ActionLog) error {
+	log.SetLogID(int64(len(r.logs) + 1))
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+func (r *memoryActionLogRepository) StreamByFilter(ctx context.Context, filter interfaces.ActionLogFilter, fn func(*actionlog.ActionLog) error) error {
+	if r.streamErr != nil {
+		return r.streamErr
+	}
+	for _, log := range r.logs {
+		if filter.PerformedByUserID != "" && log.PerformedByUserID() != filter.PerformedByUserID {
+			continue
+		}
+		if filter.ActionType != "" && log.ActionType() != filter.ActionType {
+			continue
+		}
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newTestAuditExportRouter expone el export con un administrador autenticado
+func newTestAuditExportRouter(repo *memoryActionLogRepository) (actionlogservice.IActionLogService, *httptest.ResponseRecorder, func(query string)) {
+	service := actionlogservice.NewActionLogService(repo)
+	handler := NewAuditLogExportHandler(service.(actionlogservice.IAuditLogExportService))
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/audit-logs/export.csv", withRole(user.RoleAdministrator), handler.ExportCSV)
+	recorder := httptest.NewRecorder()
+	export := func(query string) {
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit-logs/export.csv"+query, nil))
+	}
+	return service, recorder, export
+}
+
+func TestAuditLogExportHandler_ExportCSV_StreamsFilteredLogsWithHeader(t *testing.T) {
+	// Arrange
+	repo := &memoryActionLogRepository{}
+	service, recorder, export := newTestAuditExportRouter(repo)
+	ctx := context.Background()
+	pcID, pcType := "pc-001", "PC"
+	require.NoError(t, service.LogAction(ctx, actionlog.ActionUserLogin, "Admin login", "admin-1", nil, nil, nil))
+	require.NoError(t, service.LogAction(ctx, actionlog.ActionPCRegistered, "=HYPERLINK(\"evil\") registrado", "admin-1",
+		&pcID, &pcType, map[string]interface{}{"ip": "10.0.0.5", "identifier": "LAB-01"}))
+	require.NoError(t, service.LogAction(ctx, actionlog.ActionPCRegistered, "Otro PC", "admin-2", nil, nil, nil))
+
+	// Act
+	export("?user_id=admin-1&action_type=pc_registered")
+
+	// Assert
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Header().Get("Content-Disposition"), "attachment")
+
+	records, err := csv.NewReader(strings.NewReader(recorder.Body.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, []string{"log_id", "timestamp", "action_type", "description", "performed_by_user_id",
+		"subject_entity_id", "subject_entity_type", "details"}, records[0])
+
+	row := records[1]
+	assert.Equal(t, "2", row[0])
+	assert.Equal(t, "PC_REGISTERED", row[2])
+	assert.Equal(t, "'=HYPERLINK(\"evil\") registrado", row[3])
+	assert.Equal(t, "admin-1", row[4])
+	assert.Equal(t, "pc-001", row[5])
+	assert.Equal(t, "PC", row[6])
+	assert.JSONEq(t, `{"ip": "10.0.0.5", "identifier": "LAB-01"}`, row[7])
+}
+
+func TestAuditLogExportHandler_ExportCSV_EmptyResultHasHeaderOnly(t *testing.T) {
+	// Arrange
+	_, recorder, export := newTestAuditExportRouter(&memoryActionLogRepository{})
+
+	// Act
+	export("")
+
+	// Assert
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "log_id,timestamp,action_type,description,performed_by_user_id,subject_entity_id,subject_entity_type,details\n",
+		recorder.Body.String())
+}
+
+func TestAuditLogExportHandler_ExportCSV_QueryFailureReturnsError(t *testing.T) {
+	// Arrange
+	_, recorder, export := newTestAuditExportRouter(&memoryActionLogRepository{streamErr: errors.New("connection refused")})
+
+	// Act
+	export("")
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusInternalServerError, "AUDIT_EXPORT_FAILED")
+}
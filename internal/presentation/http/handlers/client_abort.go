@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// isClientAbort indica si el error se debe a que el cliente cerró la conexión (pestaña cerrada, descarga
+// cancelada) y no a un fallo del servidor
+func isClientAbort(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// abortAwareWriter recuerda el primer error de escritura, que http.ServeContent descarta
+type abortAwareWriter struct {
+	http.ResponseWriter
+	err error
+}
+
+func (w *abortAwareWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// contextReadSeeker deja de leer en cuanto se cancela el contexto de la petición, para que la copia no siga
+// leyendo del disco cuando el cliente ya se fue
+type contextReadSeeker struct {
+	ctx     context.Context
+	content io.ReadSeeker
+}
+
+func (r *contextReadSeeker) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.content.Read(p)
+}
+
+func (r *contextReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	return r.content.Seek(offset, whence)
+}
+
+// serveContent sirve content con http.ServeContent (Range, If-Range, Content-Type) y retorna el error que cortó
+// el envío, o nil si se envió completo. El contenido lo cierra quien lo abrió.
+func serveContent(c *gin.Context, name string, modTime time.Time, content io.ReadSeeker) error {
+	ctx := c.Request.Context()
+	writer := &abortAwareWriter{ResponseWriter: c.Writer}
+	http.ServeContent(writer, c.Request, name, modTime, &contextReadSeeker{ctx: ctx, content: content})
+
+	if writer.err != nil {
+		return writer.err
+	}
+	return ctx.Err()
+}
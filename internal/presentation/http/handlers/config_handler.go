@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// secretConfigMarkers fragmentos de nombre que identifican valores secretos; nunca se exponen
+var secretConfigMarkers = []string{"SECRET", "PASSWORD", "TOKEN", "PRIVATE_KEY", "API_KEY"}
+
+// ConfigHandler expone la configuración efectiva del servidor (valores de entorno ya resueltos, con sus
+// valores por defecto) para diagnosticar despliegues, omitiendo los secretos
+type ConfigHandler struct {
+	effectiveConfig map[string]interface{}
+}
+
+// NewConfigHandler crea el handler. El mapa se lee en cada petición, así que puede seguir completándose
+// mientras arranca el servidor.
+func NewConfigHandler(effectiveConfig map[string]interface{}) *ConfigHandler {
+	return &ConfigHandler{
+		effectiveConfig: effectiveConfig,
+	}
+}
+
+// GetConfig maneja GET /api/v1/admin/config; solo para super-administradores, porque la configuración incluye la
+// raíz del almacenamiento, los proxies y los límites
+func (h *ConfigHandler) GetConfig(c *gin.Context) {
+	// Verificar autenticación y autorización de super-administrador
+	userInfo, exists := c.Get("user")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
+		return
+	}
+
+	userClaims, ok := userInfo.(*userservice.JWTClaims)
+	if !ok || !user.Role(userClaims.Role).IsAnyOf(user.SuperAdminRoles()...) {
+		response.Error(c, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED", "Admin privileges required")
+		return
+	}
+
+	config := make(map[string]interface{}, len(h.effectiveConfig))
+	omitted := make([]string, 0)
+	for key, value := range h.effectiveConfig {
+		if isSecretConfigKey(key) {
+			omitted = append(omitted, key)
+			continue
+		}
+		config[key] = value
+	}
+	sort.Strings(omitted)
+
+	response.Success(c, http.StatusOK, gin.H{
+		"config":  config,
+		"omitted": omitted,
+	})
+}
+
+// isSecretConfigKey indica si la clave de configuración corresponde a un secreto
+func isSecretConfigKey(key string) bool {
+	upperKey := strings.ToUpper(key)
+	for _, marker := range secretConfigMarkers {
+		if strings.Contains(upperKey, marker) {
+			return true
+		}
+	}
+	return false
+}
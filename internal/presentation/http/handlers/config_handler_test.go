@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+)
+
+func TestConfigHandler_GetConfig_OmitsSecrets(t *testing.T) {
+	// Arrange
+	handler := NewConfigHandler(map[string]interface{}{
+		"JWT_SECRET":          "super-secret-jwt",
+		"DB_PASSWORD":         "db-password",
+		"DB_HOST":             "localhost",
+		"STORAGE_ROOT":        "./storage",
+		"HEARTBEAT_INTERVAL":  "30s",
+		"REQUEST_MAX_BODY_MB": 1.0,
+	})
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/config", withRole(user.RoleAdministrator), handler.GetConfig)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/config", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	config, ok := data["config"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "localhost", config["DB_HOST"])
+	assert.Equal(t, "./storage", config["STORAGE_ROOT"])
+	assert.Equal(t, "30s", config["HEARTBEAT_INTERVAL"])
+	assert.Equal(t, 1.0, config["REQUEST_MAX_BODY_MB"])
+	assert.NotContains(t, config, "JWT_SECRET")
+	assert.NotContains(t, config, "DB_PASSWORD")
+	assert.ElementsMatch(t, []interface{}{"DB_PASSWORD", "JWT_SECRET"}, data["omitted"])
+
+	// Los valores secretos no aparecen en ninguna parte de la respuesta
+	assert.NotContains(t, recorder.Body.String(), "super-secret-jwt")
+	assert.NotContains(t, recorder.Body.String(), "db-password")
+}
+
+func TestConfigHandler_GetConfig_RejectsNonSuperAdministrators(t *testing.T) {
+	for _, role := range []user.Role{user.RoleClientUser, user.RoleOperator, user.RoleViewer} {
+		t.Run(string(role), func(t *testing.T) {
+			// Arrange
+			handler := NewConfigHandler(map[string]interface{}{"DB_HOST": "localhost"})
+
+			router := newTestRouter()
+			router.GET("/api/v1/admin/config", withRole(role), handler.GetConfig)
+			recorder := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/config", nil))
+
+			// Assert
+			assertErrorEnvelope(t, recorder, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED")
+			assert.NotContains(t, recorder.Body.String(), "localhost")
+		})
+	}
+}
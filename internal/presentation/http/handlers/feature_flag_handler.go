@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/featureflagservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// FeatureFlagHandler expone el estado de los feature flags para visibilidad operativa (solo lectura)
+type FeatureFlagHandler struct {
+	featureFlags featureflagservice.IFeatureFlagService
+}
+
+// NewFeatureFlagHandler crea una nueva instancia del handler de feature flags
+func NewFeatureFlagHandler(featureFlags featureflagservice.IFeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		featureFlags: featureFlags,
+	}
+}
+
+// GetFlags maneja GET /api/v1/admin/flags
+func (h *FeatureFlagHandler) GetFlags(c *gin.Context) {
+	// Verificar autenticación y autorización de administrador
+	userInfo, exists := c.Get("user")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
+		return
+	}
+
+	userClaims, ok := userInfo.(*userservice.JWTClaims)
+	if !ok || !user.Role(userClaims.Role).IsAdmin() {
+		response.Error(c, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED", "Admin privileges required")
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"flags": h.featureFlags.All(),
+	})
+}
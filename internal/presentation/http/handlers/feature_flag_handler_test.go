@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/featureflagservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+)
+
+// newTestFeatureFlagHandler crea el handler con variables de entorno simuladas
+func newTestFeatureFlagHandler(env map[string]string) *FeatureFlagHandler {
+	return NewFeatureFlagHandler(featureflagservice.NewFeatureFlagService(func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}))
+}
+
+func TestFeatureFlagHandler_GetFlags_ReturnsEffectiveState(t *testing.T) {
+	// Arrange
+	handler := newTestFeatureFlagHandler(map[string]string{"FEATURE_SERVER_SIDE_RECORDING": "false"})
+
+	router := newTestRouter()
+	router.GET("/api/admin/flags", withRole(user.RoleAdministrator), handler.GetFlags)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/admin/flags", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	flags, ok := data["flags"].([]interface{})
+	require.True(t, ok)
+
+	byName := make(map[string]map[string]interface{})
+	for _, flag := range flags {
+		state := flag.(map[string]interface{})
+		byName[state["name"].(string)] = state
+	}
+	require.Contains(t, byName, string(featureflagservice.FlagServerSideRecording))
+	assert.Equal(t, false, byName[string(featureflagservice.FlagServerSideRecording)]["enabled"])
+	assert.Equal(t, featureflagservice.SourceEnv, byName[string(featureflagservice.FlagServerSideRecording)]["source"])
+	assert.Equal(t, true, byName[string(featureflagservice.FlagAdaptiveFrameRate)]["enabled"])
+}
+
+func TestFeatureFlagHandler_GetFlags_RejectsNonAdministrators(t *testing.T) {
+	// Arrange
+	handler := newTestFeatureFlagHandler(nil)
+
+	router := newTestRouter()
+	router.GET("/api/admin/flags", withRole(user.RoleClientUser), handler.GetFlags)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/admin/flags", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED")
+}
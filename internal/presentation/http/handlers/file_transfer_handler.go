@@ -1,19 +1,29 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
 
 	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/backgroundtaskservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/filetransferservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/storagequotaservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/middleware"
 )
 
 // WebSocketHandlerInterface define los métodos que necesitamos del WebSocketHandler
@@ -27,6 +37,10 @@ type FileTransferHandler struct {
 	authService         *userservice.AuthService
 	fileStorage         interfaces.IFileStorage
 	webSocketHandler    WebSocketHandlerInterface
+	// accessAudit registra quién consulta cada transferencia (nil = sin auditoría de accesos)
+	accessAudit *actionlogservice.AccessAuditor
+	// taskRegistry registra la goroutine que procesa cada envío (nil = sin seguimiento)
+	taskRegistry *backgroundtaskservice.TaskRegistry
 }
 
 // NewFileTransferHandler crea una nueva instancia del handler
@@ -44,31 +58,41 @@ func NewFileTransferHandler(
 	}
 }
 
+// SetAccessAuditor configura la auditoría de consultas de transferencias
+func (h *FileTransferHandler) SetAccessAuditor(auditor *actionlogservice.AccessAuditor) {
+	h.accessAudit = auditor
+}
+
+// SetTaskRegistry configura el registro de las goroutines que procesan los envíos
+func (h *FileTransferHandler) SetTaskRegistry(registry *backgroundtaskservice.TaskRegistry) {
+	h.taskRegistry = registry
+}
+
 // SendFileRequest estructura de la solicitud de envío de archivo
 type SendFileRequest struct {
 	TargetPCID     string `json:"target_pc_id" binding:"required"`
 	ClientFileName string `json:"client_file_name" binding:"required"`
 	ServerFilePath string `json:"server_file_path,omitempty"` // Opcional si se sube archivo
+	// Carpeta relativa de destino en el cliente; si se omite se usa Descargas/RemoteDesk
+	ClientDestinationDir string `json:"client_destination_dir,omitempty"`
+	// Plantilla de destino solo para este envío ({session}, {date}, {pc}, {file}); tiene prioridad sobre la carpeta
+	ClientDestinationTemplate string `json:"client_destination_template,omitempty"`
+	// Qué hace el cliente si ya existe un archivo con ese nombre: overwrite, rename (por defecto) o skip
+	ConflictPolicy string `json:"conflict_policy,omitempty"`
 }
 
-// SendFile maneja el endpoint POST /api/admin/sessions/{sessionID}/files/send
+// SendFile maneja el endpoint POST /api/v1/admin/sessions/{sessionID}/files/send
 func (h *FileTransferHandler) SendFile(c *gin.Context) {
 	sessionID := c.Param("sessionId")
 	if sessionID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Session ID requerido",
-		})
+		response.Error(c, http.StatusBadRequest, "INVALID_SESSION_ID", "Session ID requerido")
 		return
 	}
 
 	// Obtener usuario autenticado
 	userClaims, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   "Usuario no autenticado",
-		})
+		response.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", "Usuario no autenticado")
 		return
 	}
 
@@ -86,22 +110,19 @@ func (h *FileTransferHandler) SendFile(c *gin.Context) {
 		// Obtener otros campos del form
 		request.TargetPCID = c.PostForm("target_pc_id")
 		request.ClientFileName = c.PostForm("client_file_name")
+		request.ClientDestinationDir = c.PostForm("client_destination_dir")
+		request.ClientDestinationTemplate = c.PostForm("client_destination_template")
+		request.ConflictPolicy = c.PostForm("conflict_policy")
 
 		if request.TargetPCID == "" || request.ClientFileName == "" {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   "target_pc_id y client_file_name son requeridos",
-			})
+			response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "target_pc_id y client_file_name son requeridos")
 			return
 		}
 
 		// Guardar archivo temporalmente en el servidor
 		tempPath, err := h.saveUploadedFile(c, file, header, sessionID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"error":   fmt.Sprintf("Error guardando archivo: %v", err),
-			})
+			response.Error(c, http.StatusInternalServerError, "FILE_UPLOAD_FAILED", fmt.Sprintf("Error guardando archivo: %v", err))
 			return
 		}
 		serverFilePath = tempPath
@@ -109,45 +130,105 @@ func (h *FileTransferHandler) SendFile(c *gin.Context) {
 	} else {
 		// No hay archivo subido, usar JSON con ruta de archivo existente
 		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   fmt.Sprintf("Datos de solicitud inválidos: %v", err),
-			})
+			response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("Datos de solicitud inválidos: %v", err))
 			return
 		}
 
 		if request.ServerFilePath == "" {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   "Se requiere server_file_path o subir un archivo",
-			})
+			response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "Se requiere server_file_path o subir un archivo")
+			return
+		}
+
+		// Solo se permiten rutas dentro de los directorios de origen configurados
+		resolvedPath, err := h.fileTransferService.ResolveServerSourcePath(request.ServerFilePath)
+		if err != nil {
+			if errors.Is(err, filetransferservice.ErrServerPathNotAllowed) {
+				response.Error(c, http.StatusForbidden, "SERVER_PATH_NOT_ALLOWED", fmt.Sprintf("Ruta de servidor no permitida: %v", err))
+				return
+			}
+			if errors.Is(err, filetransferservice.ErrServerFileNotFound) {
+				response.Error(c, http.StatusNotFound, "SERVER_FILE_NOT_FOUND", fmt.Sprintf("Archivo del servidor no encontrado: %v", err))
+				return
+			}
+			response.Error(c, http.StatusBadRequest, "INVALID_SERVER_PATH", fmt.Sprintf("Ruta de servidor inválida: %v", err))
 			return
 		}
-		serverFilePath = request.ServerFilePath
+		serverFilePath = resolvedPath
 	}
 
 	// Iniciar transferencia
 	transferRequest := filetransferservice.InitiateServerToClientTransferRequest{
-		AdminUserID:    adminUserID,
-		SessionID:      sessionID,
-		TargetPCID:     request.TargetPCID,
-		ServerFilePath: serverFilePath,
-		ClientFileName: request.ClientFileName,
+		AdminUserID:               adminUserID,
+		SessionID:                 sessionID,
+		TargetPCID:                request.TargetPCID,
+		ServerFilePath:            serverFilePath,
+		ClientFileName:            request.ClientFileName,
+		ClientDestinationDir:      request.ClientDestinationDir,
+		ClientDestinationTemplate: request.ClientDestinationTemplate,
+		ConflictPolicy:            filetransfer.ConflictPolicy(request.ConflictPolicy),
 	}
 
 	transfer, err := h.fileTransferService.InitiateServerToClientTransfer(c.Request.Context(), transferRequest)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   fmt.Sprintf("Error iniciando transferencia: %v", err),
-		})
+		if errors.Is(err, storagequotaservice.ErrStorageQuotaExceeded) {
+			// Descartar el archivo subido temporalmente, no se transferirá
+			if file != nil {
+				os.Remove(serverFilePath)
+			}
+			response.Error(c, http.StatusInsufficientStorage, "STORAGE_QUOTA_EXCEEDED", fmt.Sprintf("Cuota de almacenamiento del cliente excedida: %v", err))
+			return
+		}
+		if errors.Is(err, filetransferservice.ErrInvalidDestinationDir) {
+			if file != nil {
+				os.Remove(serverFilePath)
+			}
+			response.Error(c, http.StatusBadRequest, "INVALID_DESTINATION_DIR", fmt.Sprintf("Carpeta de destino inválida: %v", err))
+			return
+		}
+		if errors.Is(err, filetransferservice.ErrInvalidDestinationTemplate) {
+			if file != nil {
+				os.Remove(serverFilePath)
+			}
+			response.Error(c, http.StatusBadRequest, "INVALID_DESTINATION_TEMPLATE", fmt.Sprintf("Plantilla de destino inválida: %v", err))
+			return
+		}
+		if errors.Is(err, filetransfer.ErrInvalidConflictPolicy) {
+			if file != nil {
+				os.Remove(serverFilePath)
+			}
+			response.Error(c, http.StatusBadRequest, "INVALID_CONFLICT_POLICY", fmt.Sprintf("Política de conflicto inválida: %v", err))
+			return
+		}
+		if errors.Is(err, filetransferservice.ErrDestinationBusy) {
+			if file != nil {
+				os.Remove(serverFilePath)
+			}
+			response.Error(c, http.StatusConflict, "DESTINATION_BUSY", fmt.Sprintf("El destino ya está recibiendo otra transferencia: %v", err))
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "TRANSFER_INITIATION_FAILED", fmt.Sprintf("Error iniciando transferencia: %v", err))
+		return
+	}
+
+	// Otra transferencia al mismo archivo del PC sigue pendiente o en curso: esta queda PENDING y se envía con los
+	// heartbeats del cliente cuando aquella termine, para no entrelazar dos escrituras en el mismo destino
+	conflict, err := h.fileTransferService.FindConflictingTransfer(c.Request.Context(), transfer)
+	if err != nil {
+		log.Printf("⚠️ AUTO-PROCESSING: Error comprobando el destino de la transferencia %s: %v", transfer.TransferID(), err)
+	}
+	if conflict != nil {
+		log.Printf("⏳ AUTO-PROCESSING: Transferencia %s en cola detrás de %s (destino %s)",
+			transfer.TransferID(), conflict.TransferID(), transfer.DestinationPathClient())
+		transferDTO := toFileTransferDTO(transfer)
+		transferDTO.QueuedBehindTransferID = conflict.TransferID()
+		response.Success(c, http.StatusOK, transferDTO)
 		return
 	}
 
 	// 🚀 PROCESAR TRANSFERENCIA INMEDIATAMENTE
 	// Procesar la transferencia en una goroutine para no bloquear la respuesta HTTP
 	if h.webSocketHandler != nil {
-		go func() {
+		h.taskRegistry.Go(backgroundtaskservice.TaskKindFileTransfer, transfer.TransferID(), func(ctx context.Context) error {
 			log.Printf("🔄 AUTO-PROCESSING: Iniciando procesamiento automático de transferencia %s", transfer.TransferID())
 			err := h.webSocketHandler.ProcessFileTransfer(transfer)
 			if err != nil {
@@ -155,106 +236,146 @@ func (h *FileTransferHandler) SendFile(c *gin.Context) {
 			} else {
 				log.Printf("✅ AUTO-PROCESSING: Transferencia %s procesada exitosamente", transfer.TransferID())
 			}
-		}()
+			return nil
+		})
 	} else {
 		log.Printf("⚠️ AUTO-PROCESSING: WebSocketHandler no disponible, transferencia %s quedará pendiente", transfer.TransferID())
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Transferencia iniciada exitosamente",
-		"data": gin.H{
-			"transfer_id":      transfer.TransferID(),
-			"file_name":        transfer.FileName(),
-			"target_pc_id":     transfer.TargetPCID(),
-			"session_id":       transfer.AssociatedSessionID(),
-			"status":           transfer.Status(),
-			"file_size_mb":     transfer.FileSizeMB(),
-			"destination_path": transfer.DestinationPathClient(),
-		},
-	})
+	response.Success(c, http.StatusOK, toFileTransferDTO(transfer))
 }
 
 // GetTransfersBySession obtiene todas las transferencias de una sesión
 func (h *FileTransferHandler) GetTransfersBySession(c *gin.Context) {
 	sessionID := c.Param("sessionId")
 	if sessionID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Session ID requerido",
-		})
+		response.Error(c, http.StatusBadRequest, "INVALID_SESSION_ID", "Session ID requerido")
 		return
 	}
 
 	transfers, err := h.fileTransferService.GetTransfersBySessionID(c.Request.Context(), sessionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   fmt.Sprintf("Error obteniendo transferencias: %v", err),
-		})
+		response.Error(c, http.StatusInternalServerError, "TRANSFERS_RETRIEVAL_FAILED", fmt.Sprintf("Error obteniendo transferencias: %v", err))
 		return
 	}
 
-	var transferData []gin.H
-	for _, transfer := range transfers {
-		transferData = append(transferData, gin.H{
-			"transfer_id":      transfer.TransferID(),
-			"file_name":        transfer.FileName(),
-			"target_pc_id":     transfer.TargetPCID(),
-			"session_id":       transfer.AssociatedSessionID(),
-			"status":           transfer.Status(),
-			"file_size_mb":     transfer.FileSizeMB(),
-			"destination_path": transfer.DestinationPathClient(),
-			"transfer_time":    transfer.TransferTime(),
-			"error_message":    transfer.ErrorMessage(),
-			"created_at":       transfer.CreatedAt(),
-			"updated_at":       transfer.UpdatedAt(),
-		})
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    transferData,
-		"count":   len(transferData),
-	})
+	respondTransferPage(c, transfers)
 }
 
 // GetTransferStatus obtiene el estado de una transferencia específica
 func (h *FileTransferHandler) GetTransferStatus(c *gin.Context) {
 	transferID := c.Param("transferId")
 	if transferID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Transfer ID requerido",
-		})
+		response.Error(c, http.StatusBadRequest, "INVALID_TRANSFER_ID", "Transfer ID requerido")
 		return
 	}
 
 	transfer, err := h.fileTransferService.GetTransferByID(c.Request.Context(), transferID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"error":   fmt.Sprintf("Transferencia no encontrada: %v", err),
-		})
+		response.Error(c, http.StatusNotFound, "TRANSFER_NOT_FOUND", fmt.Sprintf("Transferencia no encontrada: %v", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"transfer_id":      transfer.TransferID(),
-			"file_name":        transfer.FileName(),
-			"target_pc_id":     transfer.TargetPCID(),
-			"session_id":       transfer.AssociatedSessionID(),
-			"status":           transfer.Status(),
-			"file_size_mb":     transfer.FileSizeMB(),
-			"destination_path": transfer.DestinationPathClient(),
-			"transfer_time":    transfer.TransferTime(),
-			"error_message":    transfer.ErrorMessage(),
-			"created_at":       transfer.CreatedAt(),
-			"updated_at":       transfer.UpdatedAt(),
-		},
-	})
+	h.accessAudit.LogFileTransferViewed(c.Request.Context(), requestAdminUserID(c), transfer.TransferID(), transfer.FileName())
+	response.Success(c, http.StatusOK, toFileTransferDTO(transfer))
+}
+
+// DownloadTransferFile maneja GET /api/v1/admin/transfers/:transferId/download: sirve la copia del archivo que
+// guarda el servidor, con soporte de Range. Solo el administrador que inició la transferencia o un ADMINISTRATOR
+func (h *FileTransferHandler) DownloadTransferFile(c *gin.Context) {
+	transferID := c.Param("transferId")
+	if transferID == "" {
+		response.Error(c, http.StatusBadRequest, "INVALID_TRANSFER_ID", "Transfer ID requerido")
+		return
+	}
+
+	transfer, err := h.fileTransferService.GetTransferByID(c.Request.Context(), transferID)
+	if err != nil || transfer == nil {
+		response.Error(c, http.StatusNotFound, "TRANSFER_NOT_FOUND", fmt.Sprintf("Transferencia no encontrada: %s", transferID))
+		return
+	}
+
+	adminUserID := requestAdminUserID(c)
+	if transfer.InitiatingUserID() != adminUserID && !requestHasRole(c, user.SuperAdminRoles()...) {
+		response.Error(c, http.StatusForbidden, "INSUFFICIENT_PERMISSIONS", "Solo puedes descargar tus propias transferencias")
+		return
+	}
+
+	file, err := os.Open(transfer.SourcePathServer())
+	if err != nil {
+		if os.IsNotExist(err) {
+			response.Error(c, http.StatusNotFound, "FILE_NOT_FOUND", "El archivo de la transferencia ya no existe en el servidor")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "FILE_READ_FAILED", fmt.Sprintf("Error abriendo archivo: %v", err))
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		response.Error(c, http.StatusNotFound, "FILE_NOT_FOUND", "El archivo de la transferencia ya no existe en el servidor")
+		return
+	}
+
+	h.accessAudit.LogFileTransferDownloaded(c.Request.Context(), adminUserID, transfer.TransferID(), transfer.FileName(), info.Size())
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": transfer.FileName()}))
+	// ServeContent responde Range/If-Range y deduce el Content-Type de la extensión del nombre
+	err = serveContent(c, transfer.FileName(), info.ModTime(), file)
+	switch {
+	case err == nil:
+	case isClientAbort(err):
+		// El administrador cerró la pestaña o canceló la descarga: no es un fallo del servidor
+		log.Printf("⏹️ DOWNLOAD: Cliente cerró la descarga de la transferencia %s: %v", transfer.TransferID(), err)
+		c.Abort()
+	default:
+		log.Printf("❌ DOWNLOAD: Error enviando la transferencia %s: %v", transfer.TransferID(), err)
+		c.Abort()
+	}
+}
+
+// requestHasRole indica si el rol del JWT de la petición está entre roles
+func requestHasRole(c *gin.Context, roles ...user.Role) bool {
+	userInfo, _ := c.Get(middleware.UserKey)
+	claims, ok := userInfo.(*userservice.JWTClaims)
+	return ok && user.Role(claims.Role).IsAnyOf(roles...)
+}
+
+// toFileTransferDTO convierte la entidad FileTransfer al DTO de la API
+func toFileTransferDTO(transfer *filetransfer.FileTransfer) dto.FileTransferDTO {
+	return dto.FileTransferDTO{
+		TransferID:      transfer.TransferID(),
+		FileName:        transfer.FileName(),
+		TargetPCID:      transfer.TargetPCID(),
+		SessionID:       transfer.AssociatedSessionID(),
+		Status:          string(transfer.Status()),
+		FileSizeMB:      transfer.FileSizeMB(),
+		DestinationPath: transfer.DestinationPathClient(),
+		ConflictPolicy:  string(transfer.ConflictPolicy()),
+		TransferTime:    transfer.TransferTime(),
+		ErrorMessage:    transfer.ErrorMessage(),
+		CreatedAt:       transfer.CreatedAt(),
+		UpdatedAt:       transfer.UpdatedAt(),
+	}
+}
+
+// respondTransferPage responde con la página de transferencias solicitada y sus metadatos
+func respondTransferPage(c *gin.Context, transfers []*filetransfer.FileTransfer) {
+	page := response.ParsePageRequest(c)
+	response.SuccessPage(c, http.StatusOK, toFileTransferListResponse(response.Paginate(transfers, page)), page.Meta(len(transfers)))
+}
+
+// toFileTransferListResponse convierte una lista de transferencias a los datos de respuesta
+func toFileTransferListResponse(transfers []*filetransfer.FileTransfer) dto.FileTransferListResponse {
+	transferDTOs := make([]dto.FileTransferDTO, 0, len(transfers))
+	for _, transfer := range transfers {
+		transferDTOs = append(transferDTOs, toFileTransferDTO(transfer))
+	}
+	return dto.FileTransferListResponse{
+		Transfers: transferDTOs,
+		Count:     len(transferDTOs),
+	}
 }
 
 // saveUploadedFile guarda un archivo subido temporalmente en el servidor
@@ -312,75 +433,26 @@ func (h *FileTransferHandler) saveUploadedFile(c *gin.Context, file interface{},
 func (h *FileTransferHandler) GetPendingTransfers(c *gin.Context) {
 	transfers, err := h.fileTransferService.GetPendingTransfers(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   fmt.Sprintf("Error obteniendo transferencias pendientes: %v", err),
-		})
+		response.Error(c, http.StatusInternalServerError, "TRANSFERS_RETRIEVAL_FAILED", fmt.Sprintf("Error obteniendo transferencias pendientes: %v", err))
 		return
 	}
 
-	var transferData []gin.H
-	for _, transfer := range transfers {
-		transferData = append(transferData, gin.H{
-			"transfer_id":      transfer.TransferID(),
-			"file_name":        transfer.FileName(),
-			"target_pc_id":     transfer.TargetPCID(),
-			"session_id":       transfer.AssociatedSessionID(),
-			"status":           transfer.Status(),
-			"file_size_mb":     transfer.FileSizeMB(),
-			"destination_path": transfer.DestinationPathClient(),
-			"transfer_time":    transfer.TransferTime(),
-			"created_at":       transfer.CreatedAt(),
-		})
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    transferData,
-		"count":   len(transferData),
-	})
+	respondTransferPage(c, transfers)
 }
 
 // GetTransfersByClient obtiene todas las transferencias de un cliente específico
 func (h *FileTransferHandler) GetTransfersByClient(c *gin.Context) {
 	clientPCID := c.Param("clientId")
 	if clientPCID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Client PC ID requerido",
-		})
+		response.Error(c, http.StatusBadRequest, "INVALID_CLIENT_ID", "Client PC ID requerido")
 		return
 	}
 
 	transfers, err := h.fileTransferService.GetTransfersByTargetPC(c.Request.Context(), clientPCID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   fmt.Sprintf("Error obteniendo transferencias del cliente: %v", err),
-		})
+		response.Error(c, http.StatusInternalServerError, "TRANSFERS_RETRIEVAL_FAILED", fmt.Sprintf("Error obteniendo transferencias del cliente: %v", err))
 		return
 	}
 
-	var transferData []gin.H
-	for _, transfer := range transfers {
-		transferData = append(transferData, gin.H{
-			"transfer_id":      transfer.TransferID(),
-			"file_name":        transfer.FileName(),
-			"target_pc_id":     transfer.TargetPCID(),
-			"session_id":       transfer.AssociatedSessionID(),
-			"status":           transfer.Status(),
-			"file_size_mb":     transfer.FileSizeMB(),
-			"destination_path": transfer.DestinationPathClient(),
-			"transfer_time":    transfer.TransferTime(),
-			"error_message":    transfer.ErrorMessage(),
-			"created_at":       transfer.CreatedAt(),
-			"updated_at":       transfer.UpdatedAt(),
-		})
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    transferData,
-		"count":   len(transferData),
-	})
+	respondTransferPage(c, transfers)
 }
@@ -0,0 +1,409 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/filetransferservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// MockFileTransferRepository es un mock del repositorio de transferencias
+type MockFileTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockFileTransferRepository) Save(ctx context.Context, transfer *filetransfer.FileTransfer) error {
+	return m.Called(ctx, transfer).Error(0)
+}
+
+func (m *MockFileTransferRepository) UpdateStatus(ctx context.Context, transferID string, status filetransfer.TransferStatus, errorMessage string) error {
+	return m.Called(ctx, transferID, status, errorMessage).Error(0)
+}
+
+func (m *MockFileTransferRepository) FindByID(ctx context.Context, transferID string) (*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindByTargetPCID(ctx context.Context, targetPCID string) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, targetPCID)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindByInitiatingUserID(ctx context.Context, userID string) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindPendingTransfers(ctx context.Context) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) SumFileSizeByTargetPCID(ctx context.Context, targetPCID string) (float64, error) {
+	args := m.Called(ctx, targetPCID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) FindInProgressTransfers(ctx context.Context) ([]*filetransfer.FileTransfer, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*filetransfer.FileTransfer), args.Error(1)
+}
+
+func (m *MockFileTransferRepository) Delete(ctx context.Context, transferID string) error {
+	return m.Called(ctx, transferID).Error(0)
+}
+
+func newTestFileTransferHandler() (*FileTransferHandler, *MockFileTransferRepository) {
+	transferRepo := new(MockFileTransferRepository)
+	transferService := filetransferservice.NewFileTransferService(transferRepo, nil, nil, nil)
+	return NewFileTransferHandler(transferService, nil, nil, nil), transferRepo
+}
+
+func TestFileTransferHandler_GetTransferStatus_ReturnsEnvelopeWithTransfer(t *testing.T) {
+	// Arrange
+	handler, transferRepo := newTestFileTransferHandler()
+	transfer := filetransfer.NewFileTransfer("report.pdf", "/srv/report.pdf", "C:/Downloads/report.pdf",
+		"session-1", testAdminUserID, testClientPCID, 1.5)
+	transferRepo.On("FindByID", mock.Anything, transfer.TransferID()).Return(transfer, nil)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/transfers/:transferId/status", handler.GetTransferStatus)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/transfers/"+transfer.TransferID()+"/status", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, transfer.TransferID(), data["transfer_id"])
+	assert.Equal(t, "report.pdf", data["file_name"])
+	assert.Equal(t, string(filetransfer.TransferStatusPending), data["status"])
+}
+
+func TestFileTransferHandler_GetTransferStatus_LogsTransferViewed(t *testing.T) {
+	// Arrange
+	handler, transferRepo := newTestFileTransferHandler()
+	transfer := filetransfer.NewFileTransfer("report.pdf", "/srv/report.pdf", "C:/Downloads/report.pdf",
+		"session-1", testAdminUserID, testClientPCID, 1.5)
+	transferRepo.On("FindByID", mock.Anything, transfer.TransferID()).Return(transfer, nil)
+
+	actionLogService := new(MockActionLogService)
+	actionLogService.On("LogAction", mock.Anything, actionlog.ActionFileTransferViewed, mock.Anything, testAdminUserID,
+		mock.MatchedBy(func(entityID *string) bool { return *entityID == transfer.TransferID() }), mock.Anything,
+		mock.MatchedBy(func(details map[string]interface{}) bool { return details["file_name"] == "report.pdf" })).Return(nil).Once()
+	handler.SetAccessAuditor(actionlogservice.NewAccessAuditor(actionLogService, time.Minute))
+
+	router := newTestAdminRouter()
+	router.GET("/api/v1/admin/transfers/:transferId/status", handler.GetTransferStatus)
+
+	// Act - el panel consulta el estado varias veces mientras progresa
+	for i := 0; i < 3; i++ {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/transfers/"+transfer.TransferID()+"/status", nil))
+		assertSuccessEnvelope(t, recorder, http.StatusOK)
+	}
+
+	// Assert
+	actionLogService.AssertExpectations(t)
+	actionLogService.AssertNumberOfCalls(t, "LogAction", 1)
+}
+
+func TestFileTransferHandler_GetTransferStatus_NotFoundReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	handler, transferRepo := newTestFileTransferHandler()
+	transferRepo.On("FindByID", mock.Anything, "missing").Return(nil, errors.New("sql: no rows in result set"))
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/transfers/:transferId/status", handler.GetTransferStatus)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/transfers/missing/status", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusNotFound, "TRANSFER_NOT_FOUND")
+}
+
+func TestFileTransferHandler_GetPendingTransfers_ReturnsEnvelopeWithEmptyList(t *testing.T) {
+	// Arrange
+	handler, transferRepo := newTestFileTransferHandler()
+	transferRepo.On("FindPendingTransfers", mock.Anything).Return([]*filetransfer.FileTransfer{}, nil)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/transfers/pending", handler.GetPendingTransfers)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/transfers/pending", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, []interface{}{}, data["transfers"])
+	assert.Equal(t, float64(0), data["count"])
+}
+
+func TestFileTransferHandler_GetPendingTransfers_PageMeta(t *testing.T) {
+	// Arrange
+	handler, transferRepo := newTestFileTransferHandler()
+	transfers := make([]*filetransfer.FileTransfer, 0, 5)
+	for i := 0; i < 5; i++ {
+		transfers = append(transfers, filetransfer.NewFileTransfer(fmt.Sprintf("file-%d.txt", i), "/srv/file.txt",
+			"C:/Downloads/file.txt", "session-1", testAdminUserID, testClientPCID, 0.1))
+	}
+	transferRepo.On("FindPendingTransfers", mock.Anything).Return(transfers, nil)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/transfers/pending", handler.GetPendingTransfers)
+
+	testCases := []struct {
+		name          string
+		query         string
+		expectedFirst string
+		expectedCount int
+		expectedMeta  dto.PageMeta
+	}{
+		{"primera página", "?limit=2&offset=0", "file-0.txt", 2, dto.PageMeta{Limit: 2, Offset: 0, Total: 5, HasMore: true}},
+		{"página intermedia", "?limit=2&offset=2", "file-2.txt", 2, dto.PageMeta{Limit: 2, Offset: 2, Total: 5, HasMore: true}},
+		{"última página", "?limit=2&offset=4", "file-4.txt", 1, dto.PageMeta{Limit: 2, Offset: 4, Total: 5, HasMore: false}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/transfers/pending"+tc.query, nil))
+
+			// Assert
+			data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+			assert.Equal(t, float64(tc.expectedCount), data["count"])
+			items := data["transfers"].([]interface{})
+			assert.Len(t, items, tc.expectedCount)
+			assert.Equal(t, tc.expectedFirst, items[0].(map[string]interface{})["file_name"])
+			assertPageMeta(t, recorder, tc.expectedMeta)
+		})
+	}
+}
+
+func TestFileTransferHandler_SendFile_UnauthenticatedReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	handler, _ := newTestFileTransferHandler()
+
+	router := newTestRouter()
+	router.POST("/api/v1/admin/sessions/:sessionId/files/send", handler.SendFile)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/sessions/session-1/files/send", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusUnauthorized, "UNAUTHORIZED")
+}
+
+func TestFileTransferHandler_SendFile_RejectsServerPathOutsideAllowedDirs(t *testing.T) {
+	// Arrange
+	handler, transferRepo := newTestFileTransferHandler()
+	require.NoError(t, handler.fileTransferService.SetAllowedSourceDirs([]string{t.TempDir()}))
+
+	router := newTestRouter()
+	router.POST("/api/v1/admin/sessions/:sessionId/files/send", func(c *gin.Context) {
+		c.Set("user", &userservice.JWTClaims{UserID: "admin-1"})
+		handler.SendFile(c)
+	})
+	body := `{"target_pc_id": "pc-1", "client_file_name": "shadow", "server_file_path": "/etc/shadow"}`
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/admin/sessions/session-1/files/send", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, request)
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusForbidden, "SERVER_PATH_NOT_ALLOWED")
+	transferRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestFileTransferHandler_DownloadTransferFile_ServesServerCopyWithRange(t *testing.T) {
+	// Arrange
+	handler, transferRepo := newTestFileTransferHandler()
+	serverPath := filepath.Join(t.TempDir(), "report.txt")
+	require.NoError(t, os.WriteFile(serverPath, []byte("0123456789"), 0644))
+	transfer := filetransfer.NewFileTransfer("report.txt", serverPath, "C:/Downloads/report.txt",
+		"session-1", testAdminUserID, testClientPCID, 0.1)
+	transferRepo.On("FindByID", mock.Anything, transfer.TransferID()).Return(transfer, nil)
+
+	actionLogService := new(MockActionLogService)
+	actionLogService.On("LogAction", mock.Anything, actionlog.ActionFileTransferDownloaded, mock.Anything, testAdminUserID,
+		mock.MatchedBy(func(entityID *string) bool { return *entityID == transfer.TransferID() }), mock.Anything,
+		mock.MatchedBy(func(details map[string]interface{}) bool { return details["size_bytes"] == int64(10) })).Return(nil).Once()
+	handler.SetAccessAuditor(actionlogservice.NewAccessAuditor(actionLogService, time.Minute))
+
+	router := newTestAdminRouter()
+	router.GET("/api/v1/admin/transfers/:transferId/download", handler.DownloadTransferFile)
+	url := "/api/v1/admin/transfers/" + transfer.TransferID() + "/download"
+
+	// Act - descarga completa y reanudación desde el byte 6
+	full := httptest.NewRecorder()
+	router.ServeHTTP(full, httptest.NewRequest(http.MethodGet, url, nil))
+	rangeRequest := httptest.NewRequest(http.MethodGet, url, nil)
+	rangeRequest.Header.Set("Range", "bytes=6-")
+	partial := httptest.NewRecorder()
+	router.ServeHTTP(partial, rangeRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, full.Code)
+	assert.Equal(t, "0123456789", full.Body.String())
+	assert.Equal(t, `attachment; filename=report.txt`, full.Header().Get("Content-Disposition"))
+	assert.Equal(t, http.StatusPartialContent, partial.Code)
+	assert.Equal(t, "6789", partial.Body.String())
+	assert.Equal(t, "bytes 6-9/10", partial.Header().Get("Content-Range"))
+	actionLogService.AssertExpectations(t)
+	actionLogService.AssertNumberOfCalls(t, "LogAction", 1)
+}
+
+func TestFileTransferHandler_DownloadTransferFile_MissingFileReturnsNotFound(t *testing.T) {
+	// Arrange - el archivo del servidor se borró después de la transferencia
+	handler, transferRepo := newTestFileTransferHandler()
+	transfer := filetransfer.NewFileTransfer("report.txt", filepath.Join(t.TempDir(), "deleted.txt"), "C:/Downloads/report.txt",
+		"session-1", testAdminUserID, testClientPCID, 0.1)
+	transferRepo.On("FindByID", mock.Anything, transfer.TransferID()).Return(transfer, nil)
+
+	router := newTestAdminRouter()
+	router.GET("/api/v1/admin/transfers/:transferId/download", handler.DownloadTransferFile)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/transfers/"+transfer.TransferID()+"/download", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusNotFound, "FILE_NOT_FOUND")
+}
+
+func TestFileTransferHandler_DownloadTransferFile_RejectsOtherAdmins(t *testing.T) {
+	// Arrange - transferencia de otro administrador; el solicitante es OPERATOR
+	handler, transferRepo := newTestFileTransferHandler()
+	serverPath := filepath.Join(t.TempDir(), "report.txt")
+	require.NoError(t, os.WriteFile(serverPath, []byte("secret"), 0644))
+	transfer := filetransfer.NewFileTransfer("report.txt", serverPath, "C:/Downloads/report.txt",
+		"session-1", "another-admin", testClientPCID, 0.1)
+	transferRepo.On("FindByID", mock.Anything, transfer.TransferID()).Return(transfer, nil)
+
+	router := newTestAdminRouter()
+	router.GET("/api/v1/admin/transfers/:transferId/download", func(c *gin.Context) {
+		c.Set("user", &userservice.JWTClaims{UserID: testAdminUserID, Role: "OPERATOR"})
+		handler.DownloadTransferFile(c)
+	})
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/transfers/"+transfer.TransferID()+"/download", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusForbidden, "INSUFFICIENT_PERMISSIONS")
+}
+
+// disconnectingWriter ResponseWriter de un cliente que se desconecta tras recibir el primer bloque: cancela el
+// contexto de la petición o, con writeErr, falla la escritura como un socket cerrado
+type disconnectingWriter struct {
+	header   http.Header
+	status   int
+	written  int
+	writeErr error
+	cancel   context.CancelFunc
+}
+
+func (w *disconnectingWriter) Header() http.Header { return w.header }
+
+func (w *disconnectingWriter) WriteHeader(status int) { w.status = status }
+
+func (w *disconnectingWriter) Write(p []byte) (int, error) {
+	if w.writeErr != nil {
+		return 0, w.writeErr
+	}
+	w.written += len(p)
+	w.cancel()
+	return len(p), nil
+}
+
+// openFileHandles descriptores del proceso abiertos sobre path
+func openFileHandles(t *testing.T, path string) int {
+	t.Helper()
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("/proc/self/fd not available")
+	}
+	count := 0
+	for _, entry := range entries {
+		target, err := os.Readlink(filepath.Join("/proc/self/fd", entry.Name()))
+		if err == nil && target == path {
+			count++
+		}
+	}
+	return count
+}
+
+func TestFileTransferHandler_DownloadTransferFile_ClientDisconnectStopsCleanly(t *testing.T) {
+	tests := []struct {
+		name     string
+		writeErr error
+	}{
+		{name: "request context canceled"},
+		{name: "broken pipe", writeErr: &os.SyscallError{Syscall: "write", Err: syscall.EPIPE}},
+		{name: "connection reset", writeErr: &os.SyscallError{Syscall: "write", Err: syscall.ECONNRESET}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange - archivo de varios bloques de copia
+			handler, transferRepo := newTestFileTransferHandler()
+			serverPath := filepath.Join(t.TempDir(), "capture.bin")
+			content := []byte(strings.Repeat("x", 512*1024))
+			require.NoError(t, os.WriteFile(serverPath, content, 0644))
+			transfer := filetransfer.NewFileTransfer("capture.bin", serverPath, "C:/Downloads/capture.bin",
+				"session-1", testAdminUserID, testClientPCID, 0.5)
+			transferRepo.On("FindByID", mock.Anything, transfer.TransferID()).Return(transfer, nil)
+
+			router := newTestAdminRouter()
+			router.GET("/api/v1/admin/transfers/:transferId/download", handler.DownloadTransferFile)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			request := httptest.NewRequest(http.MethodGet, "/api/v1/admin/transfers/"+transfer.TransferID()+"/download", nil).WithContext(ctx)
+			writer := &disconnectingWriter{header: http.Header{}, writeErr: tt.writeErr, cancel: cancel}
+
+			// Act
+			router.ServeHTTP(writer, request)
+
+			// Assert - el envío se corta tras el primer bloque, sin error del servidor y con el archivo cerrado
+			assert.Less(t, writer.written, len(content))
+			assert.Less(t, writer.status, http.StatusInternalServerError)
+			assert.Zero(t, openFileHandles(t, serverPath))
+		})
+	}
+}
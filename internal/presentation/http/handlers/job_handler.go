@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/backgroundtaskservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// JobHandler muestra los trabajos periódicos del servidor y cuándo se ejecutaron por última vez
+type JobHandler struct {
+	scheduler *backgroundtaskservice.JobScheduler
+}
+
+// NewJobHandler crea una nueva instancia del handler de trabajos periódicos
+func NewJobHandler(scheduler *backgroundtaskservice.JobScheduler) *JobHandler {
+	return &JobHandler{
+		scheduler: scheduler,
+	}
+}
+
+// ListJobs maneja GET /api/v1/admin/jobs
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	if _, ok := requireAdministrator(c); !ok {
+		return
+	}
+
+	jobs := h.scheduler.Jobs()
+	jobDTOs := make([]dto.BackgroundJobDTO, 0, len(jobs))
+	for _, job := range jobs {
+		jobDTOs = append(jobDTOs, toBackgroundJobDTO(job))
+	}
+
+	response.Success(c, http.StatusOK, dto.BackgroundJobsResponse{
+		Jobs:  jobDTOs,
+		Count: len(jobDTOs),
+	})
+}
+
+// toBackgroundJobDTO convierte la información de un trabajo periódico a DTO
+func toBackgroundJobDTO(job backgroundtaskservice.JobInfo) dto.BackgroundJobDTO {
+	jobDTO := dto.BackgroundJobDTO{
+		Name:                   job.Name,
+		IntervalSeconds:        job.Interval.Seconds(),
+		Running:                job.Running,
+		Runs:                   job.Runs,
+		LastRunDurationSeconds: job.LastDuration.Seconds(),
+		LastError:              job.LastError,
+		NextRunAt:              job.NextRunAt,
+	}
+	if !job.LastRunAt.IsZero() {
+		lastRunAt := job.LastRunAt
+		jobDTO.LastRunAt = &lastRunAt
+	}
+	return jobDTO
+}
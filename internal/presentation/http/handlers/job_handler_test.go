@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/backgroundtaskservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+)
+
+func TestJobHandler_ListJobs_ReturnsLastAndNextRun(t *testing.T) {
+	// Arrange - un trabajo que ya se ejecutó y otro que aún no ha vencido
+	scheduler := backgroundtaskservice.NewJobScheduler(1)
+	ran := make(chan struct{}, 1)
+	require.NoError(t, scheduler.Register("rest_client_expiry", 5*time.Millisecond, func(ctx context.Context) error {
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+		return nil
+	}))
+	require.NoError(t, scheduler.Register("session_queue_expiry", time.Hour, func(ctx context.Context) error { return nil }))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler.Start(ctx)
+	<-ran
+	require.Eventually(t, func() bool { return scheduler.Jobs()[0].Runs > 0 }, time.Second, 5*time.Millisecond)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/jobs", withRole(user.RoleAdministrator), NewJobHandler(scheduler).ListJobs)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/jobs", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, float64(2), data["count"])
+	jobs := data["jobs"].([]interface{})
+	expiry := jobs[0].(map[string]interface{})
+	assert.Equal(t, "rest_client_expiry", expiry["name"])
+	assert.NotEmpty(t, expiry["last_run_at"])
+	assert.NotEmpty(t, expiry["next_run_at"])
+	queue := jobs[1].(map[string]interface{})
+	assert.Equal(t, "session_queue_expiry", queue["name"])
+	assert.Equal(t, float64(3600), queue["interval_seconds"])
+	assert.NotContains(t, queue, "last_run_at")
+	assert.NotEmpty(t, queue["next_run_at"])
+}
+
+func TestJobHandler_ListJobs_RequiresAdministrator(t *testing.T) {
+	// Arrange
+	router := newTestRouter()
+	router.GET("/api/v1/admin/jobs", withRole(user.RoleClientUser), NewJobHandler(backgroundtaskservice.NewJobScheduler(1)).ListJobs)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/jobs", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED")
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/macroservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/macro"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// MacroHandler maneja la grabación, listado y reproducción de macros de input
+type MacroHandler struct {
+	macroService macroservice.IMacroService
+}
+
+// NewMacroHandler crea una nueva instancia del handler de macros
+func NewMacroHandler(macroService macroservice.IMacroService) *MacroHandler {
+	return &MacroHandler{
+		macroService: macroService,
+	}
+}
+
+// StartRecording maneja POST /api/v1/admin/sessions/:sessionId/macros/recording
+func (h *MacroHandler) StartRecording(c *gin.Context) {
+	claims, ok := requireAdministrator(c)
+	if !ok {
+		return
+	}
+
+	sessionID := c.Param("sessionId")
+	if err := h.macroService.StartRecording(c.Request.Context(), sessionID, claims.UserID); err != nil {
+		respondMacroError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, dto.MacroRecordingResponse{
+		SessionID: sessionID,
+		Recording: true,
+	})
+}
+
+// SaveMacro maneja POST /api/v1/admin/sessions/:sessionId/macros
+func (h *MacroHandler) SaveMacro(c *gin.Context) {
+	claims, ok := requireAdministrator(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SaveMacroRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body: "+err.Error())
+		return
+	}
+
+	saved, err := h.macroService.SaveRecording(c.Request.Context(), c.Param("sessionId"), claims.UserID, req.Name)
+	if err != nil {
+		respondMacroError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, dto.NewMacroDTO(saved))
+}
+
+// ListMacros maneja GET /api/v1/admin/macros
+func (h *MacroHandler) ListMacros(c *gin.Context) {
+	claims, ok := requireAdministrator(c)
+	if !ok {
+		return
+	}
+
+	macros, err := h.macroService.ListMacros(c.Request.Context(), claims.UserID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "RETRIEVAL_FAILED", "Failed to retrieve macros")
+		return
+	}
+
+	page := response.ParsePageRequest(c)
+	macroDTOs := make([]dto.MacroDTO, 0, len(macros))
+	for _, m := range response.Paginate(macros, page) {
+		macroDTOs = append(macroDTOs, dto.NewMacroDTO(m))
+	}
+
+	response.SuccessPage(c, http.StatusOK, dto.MacroListResponse{
+		Macros: macroDTOs,
+		Count:  len(macroDTOs),
+	}, page.Meta(len(macros)))
+}
+
+// ReplayMacro maneja POST /api/v1/admin/sessions/:sessionId/macros/:macroId/replay
+func (h *MacroHandler) ReplayMacro(c *gin.Context) {
+	claims, ok := requireAdministrator(c)
+	if !ok {
+		return
+	}
+
+	sessionID := c.Param("sessionId")
+	replayed, err := h.macroService.ReplayMacro(c.Request.Context(), c.Param("macroId"), sessionID, claims.UserID)
+	if err != nil {
+		respondMacroError(c, err)
+		return
+	}
+
+	// La reproducción continúa en segundo plano respetando las esperas originales
+	response.Success(c, http.StatusAccepted, dto.MacroReplayResponse{
+		MacroID:      replayed.MacroID(),
+		SessionID:    sessionID,
+		CommandCount: replayed.CommandCount(),
+		DurationMs:   replayed.Duration().Milliseconds(),
+	})
+}
+
+// requireAdministrator verifica autenticación y rol de administrador y retorna sus claims
+func requireAdministrator(c *gin.Context) (*userservice.JWTClaims, bool) {
+	userInfo, exists := c.Get("user")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
+		return nil, false
+	}
+
+	userClaims, ok := userInfo.(*userservice.JWTClaims)
+	if !ok || !user.Role(userClaims.Role).IsAdmin() {
+		response.Error(c, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED", "Admin privileges required")
+		return nil, false
+	}
+
+	return userClaims, true
+}
+
+// respondMacroError traduce los errores del servicio de macros a respuestas HTTP
+func respondMacroError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, macroservice.ErrMacroNotFound):
+		response.Error(c, http.StatusNotFound, "MACRO_NOT_FOUND", err.Error())
+	case errors.Is(err, macroservice.ErrNoActiveRecording):
+		response.Error(c, http.StatusConflict, "NO_ACTIVE_RECORDING", err.Error())
+	case errors.Is(err, macroservice.ErrInputNotPermitted):
+		response.Error(c, http.StatusForbidden, "INPUT_NOT_PERMITTED", err.Error())
+	case errors.Is(err, macro.ErrEmptyName), errors.Is(err, macro.ErrNameTooLong),
+		errors.Is(err, macro.ErrNoCommands), errors.Is(err, macro.ErrTooManyCommands):
+		response.Error(c, http.StatusBadRequest, "INVALID_MACRO", err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, "MACRO_OPERATION_FAILED", err.Error())
+	}
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/macroservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/macro"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+)
+
+// MockMacroService es un mock del servicio de macros
+type MockMacroService struct {
+	mock.Mock
+}
+
+func (m *MockMacroService) StartRecording(ctx context.Context, sessionID, adminUserID string) error {
+	return m.Called(ctx, sessionID, adminUserID).Error(0)
+}
+
+func (m *MockMacroService) CaptureCommand(sessionID string, command macro.Command) {
+	m.Called(sessionID, command)
+}
+
+func (m *MockMacroService) SaveRecording(ctx context.Context, sessionID, adminUserID, name string) (*macro.Macro, error) {
+	args := m.Called(ctx, sessionID, adminUserID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*macro.Macro), args.Error(1)
+}
+
+func (m *MockMacroService) ListMacros(ctx context.Context, adminUserID string) ([]*macro.Macro, error) {
+	args := m.Called(ctx, adminUserID)
+	return args.Get(0).([]*macro.Macro), args.Error(1)
+}
+
+func (m *MockMacroService) ReplayMacro(ctx context.Context, macroID, sessionID, adminUserID string) (*macro.Macro, error) {
+	args := m.Called(ctx, macroID, sessionID, adminUserID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*macro.Macro), args.Error(1)
+}
+
+// newTestMacro crea una macro de dos comandos separados por 200ms
+func newTestMacro(t *testing.T) *macro.Macro {
+	t.Helper()
+
+	m, err := macro.NewMacro(testAdminUserID, "Flush DNS", "session-1", []macro.Command{
+		{EventType: "keyboard", Action: "type", Payload: map[string]interface{}{"text": "ipconfig /flushdns"}},
+		{EventType: "keyboard", Action: "keydown", Payload: map[string]interface{}{"key": "Enter"}, Delay: 200 * time.Millisecond},
+	})
+	require.NoError(t, err)
+	return m
+}
+
+func TestMacroHandler_SaveMacro_ReturnsCreatedMacro(t *testing.T) {
+	// Arrange
+	macroService := new(MockMacroService)
+	handler := NewMacroHandler(macroService)
+	saved := newTestMacro(t)
+	macroService.On("SaveRecording", mock.Anything, "session-1", testAdminUserID, "Flush DNS").Return(saved, nil)
+
+	router := newTestRouter()
+	router.POST("/api/v1/admin/sessions/:sessionId/macros", withRole(user.RoleAdministrator), handler.SaveMacro)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/sessions/session-1/macros",
+		strings.NewReader(`{"name":"Flush DNS"}`)))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusCreated)
+	assert.Equal(t, saved.MacroID(), data["macro_id"])
+	assert.Equal(t, float64(2), data["command_count"])
+	assert.Equal(t, float64(200), data["duration_ms"])
+	require.Len(t, data["commands"], 2)
+	assert.Equal(t, "type", data["commands"].([]interface{})[0].(map[string]interface{})["action"])
+}
+
+func TestMacroHandler_SaveMacro_WithoutRecordingReturnsConflict(t *testing.T) {
+	// Arrange
+	macroService := new(MockMacroService)
+	handler := NewMacroHandler(macroService)
+	macroService.On("SaveRecording", mock.Anything, "session-1", testAdminUserID, "Flush DNS").Return(nil, macroservice.ErrNoActiveRecording)
+
+	router := newTestRouter()
+	router.POST("/api/v1/admin/sessions/:sessionId/macros", withRole(user.RoleAdministrator), handler.SaveMacro)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/sessions/session-1/macros",
+		strings.NewReader(`{"name":"Flush DNS"}`)))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusConflict, "NO_ACTIVE_RECORDING")
+}
+
+func TestMacroHandler_ReplayMacro_ReturnsAccepted(t *testing.T) {
+	// Arrange
+	macroService := new(MockMacroService)
+	handler := NewMacroHandler(macroService)
+	saved := newTestMacro(t)
+	macroService.On("ReplayMacro", mock.Anything, saved.MacroID(), "session-2", testAdminUserID).Return(saved, nil)
+
+	router := newTestRouter()
+	router.POST("/api/v1/admin/sessions/:sessionId/macros/:macroId/replay", withRole(user.RoleAdministrator), handler.ReplayMacro)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost,
+		fmt.Sprintf("/api/v1/admin/sessions/session-2/macros/%s/replay", saved.MacroID()), nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusAccepted)
+	assert.Equal(t, "session-2", data["session_id"])
+	assert.Equal(t, float64(2), data["command_count"])
+	macroService.AssertExpectations(t)
+}
+
+func TestMacroHandler_ReplayMacro_WithoutInputPermissionReturnsForbidden(t *testing.T) {
+	// Arrange
+	macroService := new(MockMacroService)
+	handler := NewMacroHandler(macroService)
+	macroService.On("ReplayMacro", mock.Anything, "macro-1", "session-ended", testAdminUserID).
+		Return(nil, fmt.Errorf("%w: session is not active", macroservice.ErrInputNotPermitted))
+
+	router := newTestRouter()
+	router.POST("/api/v1/admin/sessions/:sessionId/macros/:macroId/replay", withRole(user.RoleAdministrator), handler.ReplayMacro)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/sessions/session-ended/macros/macro-1/replay", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusForbidden, "INPUT_NOT_PERMITTED")
+}
+
+func TestMacroHandler_ListMacros_RejectsNonAdministrators(t *testing.T) {
+	// Arrange
+	macroService := new(MockMacroService)
+	handler := NewMacroHandler(macroService)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/macros", withRole(user.RoleClientUser), handler.ListMacros)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/macros", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED")
+	macroService.AssertNotCalled(t, "ListMacros", mock.Anything, mock.Anything)
+}
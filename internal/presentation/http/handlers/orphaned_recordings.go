@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// SetOrphanedRecordingService configura la detección de grabaciones huérfanas (nil = endpoints no disponibles)
+func (vh *VideoHandler) SetOrphanedRecordingService(orphanedRecordings videoservice.IOrphanedRecordingService) {
+	vh.orphanedRecordings = orphanedRecordings
+}
+
+// GetOrphanedRecordings lista las grabaciones cuya sesión ya no existe, que GetAllRecordings no muestra
+// GET /api/v1/admin/recordings/orphaned
+func (vh *VideoHandler) GetOrphanedRecordings(c *gin.Context) {
+	if vh.orphanedRecordings == nil {
+		response.Error(c, http.StatusServiceUnavailable, "ORPHANED_RECORDINGS_UNAVAILABLE", "Detección de grabaciones huérfanas no disponible")
+		return
+	}
+
+	report, err := vh.orphanedRecordings.FindOrphanedRecordings(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "ORPHANED_RECORDINGS_CHECK_FAILED", "Error buscando grabaciones huérfanas")
+		return
+	}
+
+	response.Success(c, http.StatusOK, report)
+}
+
+// CleanOrphanedRecordings elimina los archivos y las filas de las grabaciones cuya sesión ya no existe
+// DELETE /api/v1/admin/recordings/orphaned
+func (vh *VideoHandler) CleanOrphanedRecordings(c *gin.Context) {
+	if vh.orphanedRecordings == nil {
+		response.Error(c, http.StatusServiceUnavailable, "ORPHANED_RECORDINGS_UNAVAILABLE", "Detección de grabaciones huérfanas no disponible")
+		return
+	}
+
+	adminUserID := requestAdminUserID(c)
+	report, err := vh.orphanedRecordings.CleanOrphanedRecordings(c.Request.Context(), adminUserID)
+	if err != nil {
+		log.Printf("❌ ORPHANED RECORDINGS: Cleanup by admin %s failed: %v", adminUserID, err)
+		response.Error(c, http.StatusInternalServerError, "ORPHANED_RECORDINGS_CLEANUP_FAILED", "Error eliminando grabaciones huérfanas")
+		return
+	}
+
+	response.Success(c, http.StatusOK, report)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/reconciliationservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// LiveConnectionsProvider expone los PCs con conexión WebSocket viva
+type LiveConnectionsProvider interface {
+	ConnectedPCIDs() []string
+}
+
+// ReconciliationHandler maneja la reconciliación manual de estados tras caídas del servidor
+type ReconciliationHandler struct {
+	reconciliationService reconciliationservice.IReconciliationService
+	liveConnections       LiveConnectionsProvider
+}
+
+// NewReconciliationHandler crea una nueva instancia del handler de reconciliación
+func NewReconciliationHandler(
+	reconciliationService reconciliationservice.IReconciliationService,
+	liveConnections LiveConnectionsProvider,
+) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationService: reconciliationService,
+		liveConnections:       liveConnections,
+	}
+}
+
+// Reconcile maneja POST /api/v1/admin/reconcile
+func (h *ReconciliationHandler) Reconcile(c *gin.Context) {
+	// Verificar autenticación y autorización de administrador
+	userInfo, exists := c.Get("user")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
+		return
+	}
+
+	userClaims, ok := userInfo.(*userservice.JWTClaims)
+	if !ok || !user.Role(userClaims.Role).IsAdmin() {
+		response.Error(c, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED", "Admin privileges required")
+		return
+	}
+
+	report, err := h.reconciliationService.Reconcile(c.Request.Context(), h.liveConnections.ConnectedPCIDs())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "RECONCILIATION_FAILED", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, report)
+}
+
+// GetStatusDrift maneja GET /api/v1/admin/diagnostics/status-drift.
+// Es de solo lectura: lista las discrepancias entre la base de datos y las conexiones vivas sin corregirlas.
+func (h *ReconciliationHandler) GetStatusDrift(c *gin.Context) {
+	// Verificar autenticación y autorización de administrador
+	userInfo, exists := c.Get("user")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
+		return
+	}
+
+	userClaims, ok := userInfo.(*userservice.JWTClaims)
+	if !ok || !user.Role(userClaims.Role).IsAdmin() {
+		response.Error(c, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED", "Admin privileges required")
+		return
+	}
+
+	report, err := h.reconciliationService.DetectStatusDrift(c.Request.Context(), h.liveConnections.ConnectedPCIDs())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "STATUS_DRIFT_CHECK_FAILED", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, report)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/reconciliationservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+)
+
+// MockReconciliationService es un mock del servicio de reconciliación
+type MockReconciliationService struct {
+	mock.Mock
+}
+
+func (m *MockReconciliationService) Reconcile(ctx context.Context, connectedPCIDs []string) (*reconciliationservice.ReconciliationReport, error) {
+	args := m.Called(ctx, connectedPCIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*reconciliationservice.ReconciliationReport), args.Error(1)
+}
+
+func (m *MockReconciliationService) DetectStatusDrift(ctx context.Context, connectedPCIDs []string) (*reconciliationservice.StatusDriftReport, error) {
+	args := m.Called(ctx, connectedPCIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*reconciliationservice.StatusDriftReport), args.Error(1)
+}
+
+// stubLiveConnections devuelve una lista fija de PCs conectados
+type stubLiveConnections []string
+
+func (s stubLiveConnections) ConnectedPCIDs() []string { return s }
+
+// withRole inyecta los claims JWT que dejaría el middleware de autenticación
+func withRole(role user.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user", &userservice.JWTClaims{UserID: testAdminUserID, Role: string(role)})
+		c.Next()
+	}
+}
+
+func TestReconciliationHandler_Reconcile_ReturnsEnvelopeWithReport(t *testing.T) {
+	// Arrange
+	reconciliation := new(MockReconciliationService)
+	handler := NewReconciliationHandler(reconciliation, stubLiveConnections{"pc-live"})
+	reconciliation.On("Reconcile", mock.Anything, []string{"pc-live"}).Return(&reconciliationservice.ReconciliationReport{
+		PCsMarkedOffline: []reconciliationservice.PCStatusChange{{PCID: "pc-ghost", PreviousStatus: "ONLINE", NewStatus: "OFFLINE"}},
+		SessionsEnded:    []reconciliationservice.SessionStatusChange{},
+		ConnectedPCs:     1,
+		ReconciledAt:     time.Now(),
+	}, nil)
+
+	router := newTestRouter()
+	router.POST("/api/v1/admin/reconcile", withRole(user.RoleAdministrator), handler.Reconcile)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/reconcile", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Len(t, data["pcs_marked_offline"], 1)
+	assert.Equal(t, []interface{}{}, data["sessions_ended"])
+	assert.Equal(t, float64(1), data["connected_pcs"])
+	reconciliation.AssertExpectations(t)
+}
+
+func TestReconciliationHandler_Reconcile_RejectsNonAdministrators(t *testing.T) {
+	// Arrange
+	reconciliation := new(MockReconciliationService)
+	handler := NewReconciliationHandler(reconciliation, stubLiveConnections{})
+
+	router := newTestRouter()
+	router.POST("/api/v1/admin/reconcile", withRole(user.RoleClientUser), handler.Reconcile)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/reconcile", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED")
+	reconciliation.AssertNotCalled(t, "Reconcile", mock.Anything, mock.Anything)
+}
+
+func TestReconciliationHandler_Reconcile_ServiceErrorReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	reconciliation := new(MockReconciliationService)
+	handler := NewReconciliationHandler(reconciliation, stubLiveConnections{})
+	reconciliation.On("Reconcile", mock.Anything, []string{}).Return(nil, errors.New("database unavailable"))
+
+	router := newTestRouter()
+	router.POST("/api/v1/admin/reconcile", withRole(user.RoleAdministrator), handler.Reconcile)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/reconcile", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusInternalServerError, "RECONCILIATION_FAILED")
+}
+
+func TestReconciliationHandler_GetStatusDrift_ReportsSeededDrift(t *testing.T) {
+	// Arrange - pc-ghost figura ONLINE sin socket y pc-live tiene socket pero figura OFFLINE
+	reconciliation := new(MockReconciliationService)
+	handler := NewReconciliationHandler(reconciliation, stubLiveConnections{"pc-live"})
+	reconciliation.On("DetectStatusDrift", mock.Anything, []string{"pc-live"}).Return(&reconciliationservice.StatusDriftReport{
+		Drifts: []reconciliationservice.PCStatusDrift{
+			{PCID: "pc-ghost", Kind: reconciliationservice.DriftPersistedOnlineWithoutSocket, PersistedStatus: "ONLINE"},
+			{PCID: "pc-live", Kind: reconciliationservice.DriftSocketWithPersistedOffline, PersistedStatus: "OFFLINE", LiveConnection: true},
+		},
+		PersistedPCs: 2,
+		ConnectedPCs: 1,
+		CheckedAt:    time.Now(),
+	}, nil)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/diagnostics/status-drift", withRole(user.RoleAdministrator), handler.GetStatusDrift)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/diagnostics/status-drift", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	drifts, ok := data["drifts"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, drifts, 2)
+	assert.Equal(t, "DB_ONLINE_WITHOUT_SOCKET", drifts[0].(map[string]interface{})["kind"])
+	assert.Equal(t, "SOCKET_WITH_DB_OFFLINE", drifts[1].(map[string]interface{})["kind"])
+	assert.Equal(t, true, drifts[1].(map[string]interface{})["live_connection"])
+	reconciliation.AssertNotCalled(t, "Reconcile", mock.Anything, mock.Anything)
+	reconciliation.AssertExpectations(t)
+}
+
+func TestReconciliationHandler_GetStatusDrift_RejectsNonAdministrators(t *testing.T) {
+	// Arrange
+	reconciliation := new(MockReconciliationService)
+	handler := NewReconciliationHandler(reconciliation, stubLiveConnections{})
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/diagnostics/status-drift", withRole(user.RoleClientUser), handler.GetStatusDrift)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/diagnostics/status-drift", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusForbidden, "ADMIN_PRIVILEGES_REQUIRED")
+	reconciliation.AssertNotCalled(t, "DetectStatusDrift", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+// Origen de los FPS de una grabación en RecordingDTO.FPSSource
+const (
+	fpsSourceFrameTimestamps = "frame_timestamps"
+	fpsSourceDuration        = "duration"
+)
+
+// recordingFPS FPS de una grabación medidos con los intervalos entre los timestamps de sus frames. Las grabaciones
+// guardadas sin timestamps, o con menos de dos frames medibles, dividen los frames entre la duración.
+func recordingFPS(videoService videoservice.IVideoService, video *sessionvideo.SessionVideo, totalFrames int) (fps float64, source string, variableRate bool) {
+	cadence, err := videoService.GetFrameCadence(video.FilePath())
+	if err != nil && !errors.Is(err, videoservice.ErrFrameTimestampsNotFound) {
+		log.Printf("⚠️ RECORDING FPS: Could not measure frame cadence of video %s: %v", video.VideoID(), err)
+	}
+	if err == nil && cadence.FPS > 0 {
+		return cadence.FPS, fpsSourceFrameTimestamps, cadence.VariableRate
+	}
+
+	if video.DurationSeconds() > 0 {
+		fps = float64(totalFrames) / float64(video.DurationSeconds())
+	}
+	return fps, fpsSourceDuration, false
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// Formatos de respuesta de GET /recordings/{videoId}/thumbnails
+const (
+	thumbnailFormatJSON    = "json"
+	thumbnailFormatMontage = "montage"
+)
+
+// SetThumbnailService configura la generación de miniaturas de grabaciones (nil = endpoint no disponible)
+func (vh *VideoHandler) SetThumbnailService(thumbnailService videoservice.IRecordingThumbnailService) {
+	vh.thumbnailService = thumbnailService
+}
+
+// GetRecordingThumbnails retorna count frames equiespaciados de la grabación reducidos a miniaturas, para
+// revisarla sin cargar la reproducción completa. Con format=montage responde un único JPEG con la cuadrícula;
+// por defecto, un JSON con cada miniatura en base64.
+// GET /api/v1/admin/recordings/{videoId}/thumbnails?count=N&format=json|montage
+func (vh *VideoHandler) GetRecordingThumbnails(c *gin.Context) {
+	if vh.thumbnailService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "THUMBNAILS_UNAVAILABLE", "Miniaturas de grabaciones no disponibles")
+		return
+	}
+
+	videoID := c.Param("videoId")
+	if videoID == "" {
+		response.Error(c, http.StatusBadRequest, "INVALID_VIDEO_ID", "Video ID requerido")
+		return
+	}
+	count, err := strconv.Atoi(c.DefaultQuery("count", strconv.Itoa(videoservice.DefaultThumbnailCount)))
+	if err != nil || count < 1 || count > videoservice.MaxThumbnailCount {
+		response.Error(c, http.StatusBadRequest, "INVALID_THUMBNAIL_COUNT", "count debe estar entre 1 y "+strconv.Itoa(videoservice.MaxThumbnailCount))
+		return
+	}
+	format := c.DefaultQuery("format", thumbnailFormatJSON)
+	if format != thumbnailFormatJSON && format != thumbnailFormatMontage {
+		response.Error(c, http.StatusBadRequest, "INVALID_THUMBNAIL_FORMAT", "format debe ser json o montage")
+		return
+	}
+
+	// El repositorio no distingue "no encontrado" de otros errores de consulta
+	video, err := vh.videoService.GetVideoByID(c.Request.Context(), videoID)
+	if err != nil || video == nil {
+		response.Error(c, http.StatusNotFound, "RECORDING_NOT_FOUND", "Grabación no encontrada")
+		return
+	}
+
+	thumbnails, err := vh.thumbnailService.GetRecordingThumbnails(c.Request.Context(), video, count)
+	if errors.Is(err, videoservice.ErrRecordingHasNoFrames) {
+		response.Error(c, http.StatusNotFound, "RECORDING_HAS_NO_FRAMES", "La grabación no tiene frames")
+		return
+	}
+	if err != nil {
+		log.Printf("❌ THUMBNAILS: Error generating thumbnails of video %s: %v", videoID, err)
+		response.Error(c, http.StatusInternalServerError, "THUMBNAILS_FAILED", "Error generando miniaturas de la grabación")
+		return
+	}
+
+	vh.accessAudit.LogRecordingViewed(c.Request.Context(), requestAdminUserID(c), video.AssociatedSessionID(), video.VideoID(), "thumbnails")
+
+	if format == thumbnailFormatMontage {
+		c.Header("Cache-Control", "private, max-age=300")
+		c.Header("X-Thumbnail-Columns", strconv.Itoa(thumbnails.Columns))
+		c.Data(http.StatusOK, "image/jpeg", thumbnails.Montage)
+		return
+	}
+
+	items := make([]dto.RecordingThumbnailDTO, 0, len(thumbnails.Thumbnails))
+	for _, thumbnail := range thumbnails.Thumbnails {
+		items = append(items, dto.RecordingThumbnailDTO{
+			FrameIndex: thumbnail.FrameIndex,
+			Width:      thumbnail.Width,
+			Height:     thumbnail.Height,
+			Data:       base64.StdEncoding.EncodeToString(thumbnail.Data),
+		})
+	}
+	response.Success(c, http.StatusOK, dto.RecordingThumbnailsResponse{
+		VideoID:     thumbnails.VideoID,
+		TotalFrames: thumbnails.TotalFrames,
+		Thumbnails:  items,
+		Count:       len(items),
+	})
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+// newThumbnailTestRouter expone las miniaturas de video-1, una grabación de frames JPEG 320x240 en disco
+func newThumbnailTestRouter(t *testing.T, frames int) http.Handler {
+	t.Helper()
+
+	framesDir := t.TempDir()
+	for i := 0; i < frames; i++ {
+		var frame bytes.Buffer
+		require.NoError(t, jpeg.Encode(&frame, image.NewRGBA(image.Rect(0, 0, 320, 240)), nil))
+		require.NoError(t, os.WriteFile(filepath.Join(framesDir, fmt.Sprintf("frame_%06d.jpg", i)), frame.Bytes(), 0644))
+	}
+
+	service := videoservice.NewVideoService(nil, nil, nil, nil, videoservice.FrameStorageIndividual, 0, videoservice.DefaultPartialRecordingPolicy)
+	handler := NewVideoHandler(nil, recordingLookupVideoService{
+		IVideoService: service,
+		videos: map[string]*sessionvideo.SessionVideo{
+			"video-1": sessionvideo.NewSessionVideoFromDB("video-1", framesDir, frames, time.Now(), "session-1", 0, time.Now(), time.Now()),
+		},
+	}, nil)
+	handler.SetThumbnailService(service.(videoservice.IRecordingThumbnailService))
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/recordings/:videoId/thumbnails", handler.GetRecordingThumbnails)
+	return router
+}
+
+func TestVideoHandler_GetRecordingThumbnails_ReturnsRequestedNumberOfBase64Thumbnails(t *testing.T) {
+	// Arrange
+	router := newThumbnailTestRouter(t, 12)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/recordings/video-1/thumbnails?count=4", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, float64(12), data["total_frames"])
+	assert.Equal(t, float64(4), data["count"])
+	thumbnails := data["thumbnails"].([]interface{})
+	require.Len(t, thumbnails, 4)
+	for _, item := range thumbnails {
+		thumbnail := item.(map[string]interface{})
+		raw, err := base64.StdEncoding.DecodeString(thumbnail["data"].(string))
+		require.NoError(t, err)
+		img, err := jpeg.Decode(bytes.NewReader(raw))
+		require.NoError(t, err)
+		assert.Equal(t, 160, img.Bounds().Dx())
+		assert.Equal(t, float64(120), thumbnail["height"])
+	}
+	assert.Equal(t, float64(11), thumbnails[3].(map[string]interface{})["frame_index"])
+}
+
+func TestVideoHandler_GetRecordingThumbnails_ServesMontageImage(t *testing.T) {
+	// Arrange
+	router := newThumbnailTestRouter(t, 12)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/recordings/video-1/thumbnails?count=4&format=montage", nil))
+
+	// Assert - cuadrícula de 2×2 miniaturas de 160x120
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "image/jpeg", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "2", recorder.Header().Get("X-Thumbnail-Columns"))
+	montage, err := jpeg.Decode(recorder.Body)
+	require.NoError(t, err)
+	assert.Equal(t, image.Pt(320, 240), montage.Bounds().Size())
+}
+
+func TestVideoHandler_GetRecordingThumbnails_RejectsCountOutOfRange(t *testing.T) {
+	// Arrange
+	router := newThumbnailTestRouter(t, 1)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/recordings/video-1/thumbnails?count=0", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusBadRequest, "INVALID_THUMBNAIL_COUNT")
+}
@@ -1,26 +1,47 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
-	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/handlers"
 	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
 	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/middleware"
 )
 
+// RemoteControlNotifier define los métodos que necesitamos del WebSocketHandler para avisar al cliente
+type RemoteControlNotifier interface {
+	SendRemoteControlRequestToClient(sessionID, clientPCID, adminUserID, adminUsername string) error
+	SendAutoAcceptedSessionToClient(sessionID, clientPCID string) error
+}
+
+// SessionRecordingProvider indica si una sesión tiene una grabación en curso
+type SessionRecordingProvider interface {
+	IsSessionRecording(sessionID string) bool
+}
+
+// ClientInputCapabilityProvider event_type de input que declaró el PC conectado (false si no los declaró)
+type ClientInputCapabilityProvider interface {
+	SupportedInputEvents(clientPCID string) ([]string, bool)
+}
+
 // RemoteControlHandler maneja las operaciones de control remoto
 type RemoteControlHandler struct {
-	sessionService   *remotesessionservice.RemoteSessionService
-	webSocketHandler *handlers.WebSocketHandler
+	sessionService    *remotesessionservice.RemoteSessionService
+	webSocketHandler  RemoteControlNotifier
+	recordings        SessionRecordingProvider
+	inputCapabilities ClientInputCapabilityProvider
 }
 
 // NewRemoteControlHandler crea una nueva instancia del handler
 func NewRemoteControlHandler(
 	sessionService *remotesessionservice.RemoteSessionService,
-	webSocketHandler *handlers.WebSocketHandler,
+	webSocketHandler RemoteControlNotifier,
 ) *RemoteControlHandler {
 	return &RemoteControlHandler{
 		sessionService:   sessionService,
@@ -28,47 +49,97 @@ func NewRemoteControlHandler(
 	}
 }
 
-// InitiateSession maneja POST /api/admin/sessions/initiate
+// SetRecordingProvider configura de dónde se obtiene si una sesión se está grabando
+func (rch *RemoteControlHandler) SetRecordingProvider(recordings SessionRecordingProvider) {
+	rch.recordings = recordings
+}
+
+// SetInputCapabilityProvider configura de dónde se obtienen los tipos de input que admite el PC de la sesión
+func (rch *RemoteControlHandler) SetInputCapabilityProvider(provider ClientInputCapabilityProvider) {
+	rch.inputCapabilities = provider
+}
+
+// initiateSessionErrorCode traduce los errores de InitiateSession a un código estable para que la UI
+// distinga un PC desconectado de uno inexistente o ya en sesión
+func initiateSessionErrorCode(err error) (int, string) {
+	switch {
+	case errors.Is(err, remotesessionservice.ErrClientPCOffline):
+		return http.StatusConflict, "PC_OFFLINE"
+	case errors.Is(err, remotesessionservice.ErrClientPCNotFound):
+		return http.StatusNotFound, "PC_NOT_FOUND"
+	case errors.Is(err, remotesessionservice.ErrSessionAlreadyActive):
+		return http.StatusConflict, "SESSION_ALREADY_ACTIVE"
+	case errors.Is(err, remotesessionservice.ErrSessionAlreadyQueued):
+		return http.StatusConflict, "SESSION_ALREADY_QUEUED"
+	case errors.Is(err, remotesessionservice.ErrAdminUserNotFound):
+		return http.StatusUnauthorized, "UNAUTHORIZED"
+	case errors.Is(err, remotesessionservice.ErrJustificationRequired):
+		return http.StatusBadRequest, "JUSTIFICATION_REQUIRED"
+	case errors.Is(err, remotesessionservice.ErrInvalidSessionRequest):
+		return http.StatusBadRequest, "VALIDATION_ERROR"
+	default:
+		// Fallos del repositorio u otros no previstos: no son culpa de la solicitud
+		return http.StatusInternalServerError, "SESSION_INITIATION_FAILED"
+	}
+}
+
+// InitiateSession maneja POST /api/v1/admin/sessions/initiate
 func (rch *RemoteControlHandler) InitiateSession(c *gin.Context) {
 	// Obtener ID del usuario desde JWT (middleware de autenticación)
 	adminUserID, exists := c.Get(middleware.UserIDKey)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Error:   "unauthorized",
-			Message: "User not authenticated",
-		})
+		response.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", "User not authenticated")
 		return
 	}
 
 	// Parsear request body
 	var req dto.InitiateSessionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "invalid_request",
-			Message: "Invalid request body",
-			Details: err.Error(),
-		})
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body: "+err.Error())
 		return
 	}
 
 	// Validar request
 	if err := req.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "validation_error",
-			Message: err.Error(),
-		})
+		response.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	justification, err := remotesession.NewJustification(req.Reason, req.TicketID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
 		return
 	}
 
 	// Iniciar sesión usando el servicio
 	session, err := rch.sessionService.InitiateSession(
+		c.Request.Context(),
 		adminUserID.(string),
 		req.ClientPCID,
+		justification,
 	)
+	if errors.Is(err, remotesessionservice.ErrClientPCOffline) && req.QueueIfOffline {
+		rch.queueSession(c, adminUserID.(string), req.ClientPCID, justification)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "session_initiation_failed",
-			Message: err.Error(),
+		status, code := initiateSessionErrorCode(err)
+		response.Error(c, status, code, err.Error())
+		return
+	}
+
+	// PC con auto-aceptación: la sesión ya está ACTIVE, el cliente inicia el streaming directamente
+	if session.Status() == remotesession.StatusActive {
+		err = rch.webSocketHandler.SendAutoAcceptedSessionToClient(session.SessionID(), session.ClientPCID())
+		if err != nil {
+			// Log error pero no fallar la request - la sesión ya se creó
+			log.Printf("⚠️ Failed to start auto-accepted session %s on client: %v", session.SessionID(), err)
+		}
+
+		response.Success(c, http.StatusOK, dto.InitiateSessionResponse{
+			SessionID: session.SessionID(),
+			Status:    string(session.Status()),
+			Message:   "Remote control session auto-accepted by PC policy",
 		})
 		return
 	}
@@ -82,47 +153,46 @@ func (rch *RemoteControlHandler) InitiateSession(c *gin.Context) {
 	}
 
 	// Responder con la sesión creada
-	response := dto.InitiateSessionResponse{
-		Success:   true,
+	response.Success(c, http.StatusOK, dto.InitiateSessionResponse{
 		SessionID: session.SessionID(),
 		Status:    string(session.Status()),
 		Message:   "Remote control request sent to client",
-	}
-
-	c.JSON(http.StatusOK, response)
+	})
 }
 
-// GetSessionStatus maneja GET /api/admin/sessions/:sessionId/status
+// GetSessionStatus maneja GET /api/v1/admin/sessions/:sessionId/status
 func (rch *RemoteControlHandler) GetSessionStatus(c *gin.Context) {
 	sessionID := c.Param("sessionId")
 	if sessionID == "" {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "invalid_session_id",
-			Message: "Session ID is required",
-		})
+		response.Error(c, http.StatusBadRequest, "INVALID_SESSION_ID", "Session ID is required")
 		return
 	}
 
 	// Obtener sesión
-	session, err := rch.sessionService.GetSessionById(sessionID)
+	session, err := rch.sessionService.GetSessionById(c.Request.Context(), sessionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "session_retrieval_failed",
-			Message: err.Error(),
-		})
+		response.Error(c, http.StatusInternalServerError, "SESSION_RETRIEVAL_FAILED", err.Error())
 		return
 	}
 
 	if session == nil {
-		c.JSON(http.StatusNotFound, dto.ErrorResponse{
-			Error:   "session_not_found",
-			Message: "Session not found",
-		})
+		response.Error(c, http.StatusNotFound, "SESSION_NOT_FOUND", "Session not found")
 		return
 	}
 
-	// Crear response DTO
-	response := dto.SessionStatusResponse{
+	status := toSessionStatusResponse(rch.sessionService, rch.recordings, session)
+	if rch.inputCapabilities != nil {
+		if supported, declared := rch.inputCapabilities.SupportedInputEvents(session.ClientPCID()); declared {
+			status.SupportedInputEvents = supported
+		}
+	}
+
+	response.Success(c, http.StatusOK, status)
+}
+
+// toSessionStatusResponse estado de la sesión con su grabación en curso y la presencia informada por el cliente
+func toSessionStatusResponse(sessionService *remotesessionservice.RemoteSessionService, recordings SessionRecordingProvider, session *remotesession.RemoteSession) dto.SessionStatusResponse {
+	status := dto.SessionStatusResponse{
 		SessionID:   session.SessionID(),
 		AdminUserID: session.AdminUserID(),
 		ClientPCID:  session.ClientPCID(),
@@ -131,163 +201,250 @@ func (rch *RemoteControlHandler) GetSessionStatus(c *gin.Context) {
 		EndTime:     session.EndTime(),
 		CreatedAt:   session.CreatedAt(),
 		UpdatedAt:   session.UpdatedAt(),
+		Reason:      session.Justification().Reason,
+		TicketID:    session.Justification().TicketID,
+	}
+
+	if recordings != nil {
+		status.Recording = recordings.IsSessionRecording(session.SessionID())
 	}
 
-	if session.GetDuration() > 0 {
-		duration := session.GetDuration()
-		response.Duration = &duration
+	if activity, reported := sessionService.GetSessionActivity(session.SessionID()); reported {
+		status.ClientActivity = &dto.ClientActivityDTO{
+			Status:              strings.ToLower(string(activity.State)),
+			LastInputAgeSeconds: int64(activity.LastInputAge.Seconds()),
+			ReportedAt:          activity.ReportedAt,
+			IdleCount:           activity.IdleCount,
+			IdleSeconds:         int64(activity.IdleDuration.Seconds()),
+		}
 	}
 
-	c.JSON(http.StatusOK, response)
+	// Sin inicio registrado la duración es 0 y no se incluye; una sesión activa cuenta hasta ahora
+	if duration := session.GetDuration(); duration > 0 {
+		status.Duration = &duration
+	}
+
+	return status
 }
 
-// GetActiveSessions maneja GET /api/admin/sessions/active
+// GetActiveSessions maneja GET /api/v1/admin/sessions/active
 func (rch *RemoteControlHandler) GetActiveSessions(c *gin.Context) {
 	// Obtener sesiones activas
-	sessions, err := rch.sessionService.GetActiveSessions()
+	sessions, err := rch.sessionService.GetActiveSessions(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "sessions_retrieval_failed",
-			Message: err.Error(),
-		})
+		response.Error(c, http.StatusInternalServerError, "SESSIONS_RETRIEVAL_FAILED", err.Error())
 		return
 	}
 
+	// Paginar antes de resolver nombres para consultar solo los de la página
+	page := response.ParsePageRequest(c)
+	pageSessions := response.Paginate(sessions, page)
+
+	// Resolver nombres de PC y administradores en bloque
+	pcNames, adminUsernames := rch.resolveSessionLabels(c, pageSessions)
+
 	// Convertir a DTOs
-	var sessionDTOs []dto.SessionSummaryDTO
-	for _, session := range sessions {
+	sessionDTOs := make([]dto.SessionSummaryDTO, 0, len(pageSessions))
+	for _, session := range pageSessions {
 		sessionDTOs = append(sessionDTOs, dto.SessionSummaryDTO{
-			SessionID:   session.SessionID(),
-			AdminUserID: session.AdminUserID(),
-			ClientPCID:  session.ClientPCID(),
-			Status:      string(session.Status()),
-			StartTime:   session.StartTime(),
-			CreatedAt:   session.CreatedAt(),
+			SessionID:     session.SessionID(),
+			AdminUserID:   session.AdminUserID(),
+			AdminUsername: adminUsernames[session.AdminUserID()],
+			ClientPCID:    session.ClientPCID(),
+			ClientPCName:  pcNames[session.ClientPCID()],
+			Status:        string(session.Status()),
+			StartTime:     session.StartTime(),
+			Reason:        session.Justification().Reason,
+			TicketID:      session.Justification().TicketID,
+			CreatedAt:     session.CreatedAt(),
 		})
 	}
 
-	response := dto.ActiveSessionsResponse{
+	response.SuccessPage(c, http.StatusOK, dto.ActiveSessionsResponse{
 		Sessions: sessionDTOs,
 		Count:    len(sessionDTOs),
-	}
-
-	c.JSON(http.StatusOK, response)
+	}, page.Meta(len(sessions)))
 }
 
-// GetUserSessions maneja GET /api/admin/sessions/my
+// GetUserSessions maneja GET /api/v1/admin/sessions/my
 func (rch *RemoteControlHandler) GetUserSessions(c *gin.Context) {
 	// Obtener ID del usuario desde JWT
 	adminUserID, exists := c.Get(middleware.UserIDKey)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Error:   "unauthorized",
-			Message: "User not authenticated",
-		})
+		response.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", "User not authenticated")
 		return
 	}
 
 	// Obtener sesiones del usuario
-	sessions, err := rch.sessionService.GetSessionsByUser(adminUserID.(string))
+	sessions, err := rch.sessionService.GetSessionsByUser(c.Request.Context(), adminUserID.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "sessions_retrieval_failed",
-			Message: err.Error(),
-		})
+		response.Error(c, http.StatusInternalServerError, "SESSIONS_RETRIEVAL_FAILED", err.Error())
 		return
 	}
 
+	// Paginar antes de resolver nombres para consultar solo los de la página
+	page := response.ParsePageRequest(c)
+	pageSessions := response.Paginate(sessions, page)
+
+	// Resolver nombres de PC y administradores en bloque
+	pcNames, adminUsernames := rch.resolveSessionLabels(c, pageSessions)
+
 	// Convertir a DTOs
-	var sessionDTOs []dto.SessionSummaryDTO
-	for _, session := range sessions {
+	sessionDTOs := make([]dto.SessionSummaryDTO, 0, len(pageSessions))
+	for _, session := range pageSessions {
 		sessionDTOs = append(sessionDTOs, dto.SessionSummaryDTO{
-			SessionID:   session.SessionID(),
-			AdminUserID: session.AdminUserID(),
-			ClientPCID:  session.ClientPCID(),
-			Status:      string(session.Status()),
-			StartTime:   session.StartTime(),
-			EndTime:     session.EndTime(),
-			CreatedAt:   session.CreatedAt(),
+			SessionID:     session.SessionID(),
+			AdminUserID:   session.AdminUserID(),
+			AdminUsername: adminUsernames[session.AdminUserID()],
+			ClientPCID:    session.ClientPCID(),
+			ClientPCName:  pcNames[session.ClientPCID()],
+			Status:        string(session.Status()),
+			StartTime:     session.StartTime(),
+			EndTime:       session.EndTime(),
+			Reason:        session.Justification().Reason,
+			TicketID:      session.Justification().TicketID,
+			CreatedAt:     session.CreatedAt(),
 		})
 	}
 
-	response := dto.UserSessionsResponse{
+	response.SuccessPage(c, http.StatusOK, dto.UserSessionsResponse{
 		Sessions: sessionDTOs,
 		Count:    len(sessionDTOs),
-	}
-
-	c.JSON(http.StatusOK, response)
+	}, page.Meta(len(sessions)))
 }
 
-// EndSession maneja POST /api/admin/sessions/:sessionId/end
+// EndSession maneja POST /api/v1/admin/sessions/:sessionId/end
 func (rch *RemoteControlHandler) EndSession(c *gin.Context) {
 	sessionID := c.Param("sessionId")
 	if sessionID == "" {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "invalid_session_id",
-			Message: "Session ID is required",
-		})
+		response.Error(c, http.StatusBadRequest, "INVALID_SESSION_ID", "Session ID is required")
 		return
 	}
 
 	// Obtener ID del usuario desde JWT
 	adminUserID, exists := c.Get(middleware.UserIDKey)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Error:   "unauthorized",
-			Message: "User not authenticated",
-		})
+		response.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", "User not authenticated")
 		return
 	}
 
 	// Verificar que la sesión existe y pertenece al administrador
-	session, err := rch.sessionService.GetSessionById(sessionID)
+	session, err := rch.sessionService.GetSessionById(c.Request.Context(), sessionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "session_retrieval_failed",
-			Message: err.Error(),
-		})
+		response.Error(c, http.StatusInternalServerError, "SESSION_RETRIEVAL_FAILED", err.Error())
 		return
 	}
 
 	if session == nil {
-		c.JSON(http.StatusNotFound, dto.ErrorResponse{
-			Error:   "session_not_found",
-			Message: "Session not found",
-		})
+		response.Error(c, http.StatusNotFound, "SESSION_NOT_FOUND", "Session not found")
 		return
 	}
 
 	// Verificar permisos - solo el administrador que inició la sesión puede terminarla
 	if session.AdminUserID() != adminUserID.(string) {
-		c.JSON(http.StatusForbidden, dto.ErrorResponse{
-			Error:   "insufficient_permissions",
-			Message: "You can only end your own sessions",
-		})
+		response.Error(c, http.StatusForbidden, "INSUFFICIENT_PERMISSIONS", "You can only end your own sessions")
 		return
 	}
 
 	// Verificar que la sesión está activa
 	if session.Status() != remotesession.StatusActive {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "invalid_session_state",
-			Message: "Session is not active",
-		})
+		response.Error(c, http.StatusBadRequest, "INVALID_SESSION_STATE", "Session is not active")
 		return
 	}
 
 	// Finalizar la sesión
-	err = rch.sessionService.EndSessionByAdmin(sessionID)
+	err = rch.sessionService.EndSessionByAdmin(c.Request.Context(), sessionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "session_end_failed",
-			Message: err.Error(),
-		})
+		response.Error(c, http.StatusInternalServerError, "SESSION_END_FAILED", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, dto.EndSessionResponse{
+		SessionID: sessionID,
+		Status:    string(remotesession.StatusEndedByAdmin),
+	})
+}
+
+// transferSessionErrorCode traduce los errores de TransferSessionOwnership a un código estable
+func transferSessionErrorCode(err error) (int, string) {
+	switch {
+	case errors.Is(err, remotesessionservice.ErrSessionNotFound):
+		return http.StatusNotFound, "SESSION_NOT_FOUND"
+	case errors.Is(err, remotesessionservice.ErrNotSessionOwner):
+		return http.StatusForbidden, "INSUFFICIENT_PERMISSIONS"
+	case errors.Is(err, remotesessionservice.ErrSessionNotActive):
+		return http.StatusBadRequest, "INVALID_SESSION_STATE"
+	case errors.Is(err, remotesessionservice.ErrAdminUserNotFound):
+		return http.StatusNotFound, "ADMIN_NOT_FOUND"
+	case errors.Is(err, remotesessionservice.ErrInvalidTransferTarget):
+		return http.StatusBadRequest, "INVALID_TRANSFER_TARGET"
+	case errors.Is(err, remotesessionservice.ErrTargetAdminNotConnected):
+		return http.StatusConflict, "TARGET_ADMIN_NOT_CONNECTED"
+	default:
+		return http.StatusInternalServerError, "SESSION_TRANSFER_FAILED"
+	}
+}
+
+// TransferSession maneja POST /api/v1/admin/sessions/:sessionId/transfer
+func (rch *RemoteControlHandler) TransferSession(c *gin.Context) {
+	sessionID := c.Param("sessionId")
+	if sessionID == "" {
+		response.Error(c, http.StatusBadRequest, "INVALID_SESSION_ID", "Session ID is required")
+		return
+	}
+
+	// Obtener ID del usuario desde JWT
+	adminUserID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	var req dto.TransferSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body: "+err.Error())
+		return
+	}
+
+	// Solo el administrador que controla la sesión puede traspasarla
+	session, err := rch.sessionService.TransferSessionOwnership(c.Request.Context(), sessionID, adminUserID.(string), req.ToAdminID)
+	if err != nil {
+		status, code := transferSessionErrorCode(err)
+		response.Error(c, status, code, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, dto.TransferSessionResponse{
+		SessionID:   session.SessionID(),
+		Status:      string(session.Status()),
+		FromAdminID: adminUserID.(string),
+		ToAdminID:   session.AdminUserID(),
+	})
+}
+
+// resolveSessionLabels obtiene nombres de PC y administradores; si falla, la lista se devuelve sin ellos
+func (rch *RemoteControlHandler) resolveSessionLabels(c *gin.Context, sessions []*remotesession.RemoteSession) (map[string]string, map[string]string) {
+	pcNames, adminUsernames, err := rch.sessionService.ResolveSessionLabels(c.Request.Context(), sessions)
+	if err != nil {
+		log.Printf("⚠️ Failed to resolve session labels: %v", err)
+		return map[string]string{}, map[string]string{}
+	}
+	return pcNames, adminUsernames
+}
+
+// queueSession deja en cola la solicitud para un PC offline; se entrega cuando el PC vuelva a registrarse
+func (rch *RemoteControlHandler) queueSession(c *gin.Context, adminUserID, clientPCID string, justification remotesession.Justification) {
+	session, err := rch.sessionService.QueueSession(c.Request.Context(), adminUserID, clientPCID, justification)
+	if err != nil {
+		status, code := initiateSessionErrorCode(err)
+		response.Error(c, status, code, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Session ended successfully",
+	response.Success(c, http.StatusAccepted, dto.InitiateSessionResponse{
+		SessionID: session.SessionID(),
+		Status:    string(session.Status()),
+		Message:   "Client PC is offline, request queued until it reconnects",
 	})
 }
 
@@ -0,0 +1,587 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/clientpc"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/events"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	sharedevents "github.com/unikyri/escritorio-remoto-backend/internal/domain/shared/events"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/middleware"
+)
+
+const (
+	testAdminUserID = "550e8400-e29b-41d4-a716-446655440000"
+	testClientPCID  = "550e8400-e29b-41d4-a716-446655440001"
+)
+
+// MockRemoteControlNotifier es un mock del WebSocketHandler que registra los mensajes enviados al cliente
+type MockRemoteControlNotifier struct {
+	mock.Mock
+}
+
+func (m *MockRemoteControlNotifier) SendRemoteControlRequestToClient(sessionID, clientPCID, adminUserID, adminUsername string) error {
+	return m.Called(sessionID, clientPCID, adminUserID, adminUsername).Error(0)
+}
+
+func (m *MockRemoteControlNotifier) SendAutoAcceptedSessionToClient(sessionID, clientPCID string) error {
+	return m.Called(sessionID, clientPCID).Error(0)
+}
+
+// MockUserRepository es un mock del repositorio de usuarios
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) FindByUsername(username string) (*user.User, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByID(userID string) (*user.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByIDs(ctx context.Context, userIDs []string) (map[string]*user.User, error) {
+	args := m.Called(ctx, userIDs)
+	return args.Get(0).(map[string]*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Save(u *user.User) error {
+	return m.Called(u).Error(0)
+}
+
+func (m *MockUserRepository) Create(u *user.User) error {
+	return m.Called(u).Error(0)
+}
+
+func (m *MockUserRepository) IncrementTokenVersion(userID string) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+// MockClientPCRepository es un mock del repositorio de PCs cliente
+type MockClientPCRepository struct {
+	mock.Mock
+}
+
+func (m *MockClientPCRepository) Save(ctx context.Context, pc *clientpc.ClientPC) error {
+	return m.Called(ctx, pc).Error(0)
+}
+
+func (m *MockClientPCRepository) FindByID(ctx context.Context, pcID string) (*clientpc.ClientPC, error) {
+	args := m.Called(ctx, pcID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockClientPCRepository) FindByIDs(ctx context.Context, pcIDs []string) (map[string]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, pcIDs)
+	return args.Get(0).(map[string]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockClientPCRepository) FindByIdentifierAndOwner(ctx context.Context, identifier string, ownerID string) (*clientpc.ClientPC, error) {
+	args := m.Called(ctx, identifier, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockClientPCRepository) FindByOwner(ctx context.Context, ownerID string) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, ownerID)
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockClientPCRepository) FindOnlineByOwner(ctx context.Context, ownerID string) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, ownerID)
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockClientPCRepository) UpdateConnectionStatus(ctx context.Context, pcID string, status clientpc.PCConnectionStatus) error {
+	return m.Called(ctx, pcID, status).Error(0)
+}
+
+func (m *MockClientPCRepository) UpdateLastSeen(ctx context.Context, pcID string) error {
+	return m.Called(ctx, pcID).Error(0)
+}
+
+func (m *MockClientPCRepository) UpdateAutoAcceptControl(ctx context.Context, pcID string, enabled bool) error {
+	return m.Called(ctx, pcID, enabled).Error(0)
+}
+
+func (m *MockClientPCRepository) Delete(ctx context.Context, pcID string) error {
+	return m.Called(ctx, pcID).Error(0)
+}
+
+func (m *MockClientPCRepository) FindAll(ctx context.Context, limit, offset int) ([]*clientpc.ClientPC, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*clientpc.ClientPC), args.Error(1)
+}
+
+func (m *MockClientPCRepository) CountByOwner(ctx context.Context, ownerID string) (int, error) {
+	args := m.Called(ctx, ownerID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockClientPCRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+// MockActionLogService es un mock del servicio de auditoría
+type MockActionLogService struct {
+	mock.Mock
+}
+
+func (m *MockActionLogService) LogAction(ctx context.Context, actionType actionlog.ActionType, description string,
+	performedByUserID string, subjectEntityID *string, subjectEntityType *string,
+	details map[string]interface{}) error {
+	return m.Called(ctx, actionType, description, performedByUserID, subjectEntityID, subjectEntityType, details).Error(0)
+}
+
+func (m *MockActionLogService) LogSessionEnded(ctx context.Context, sessionID, adminUserID, reason string) error {
+	return m.Called(ctx, sessionID, adminUserID, reason).Error(0)
+}
+
+func (m *MockActionLogService) GetRecentLogs(ctx context.Context, limit int) ([]*actionlog.ActionLog, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*actionlog.ActionLog), args.Error(1)
+}
+
+func (m *MockActionLogService) GetLogsByUser(ctx context.Context, userID string, limit, offset int) ([]*actionlog.ActionLog, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	return args.Get(0).([]*actionlog.ActionLog), args.Error(1)
+}
+
+func (m *MockActionLogService) GetLogsByActionType(ctx context.Context, actionType actionlog.ActionType, limit, offset int) ([]*actionlog.ActionLog, error) {
+	args := m.Called(ctx, actionType, limit, offset)
+	return args.Get(0).([]*actionlog.ActionLog), args.Error(1)
+}
+
+func (m *MockActionLogService) GetLogsByEntity(ctx context.Context, entityID, entityType string, limit, offset int) ([]*actionlog.ActionLog, error) {
+	args := m.Called(ctx, entityID, entityType, limit, offset)
+	return args.Get(0).([]*actionlog.ActionLog), args.Error(1)
+}
+
+func (m *MockActionLogService) GetLogsCount(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+// MockEventBus es un mock del bus de eventos de dominio
+type MockEventBus struct {
+	mock.Mock
+}
+
+func (m *MockEventBus) Publish(event sharedevents.DomainEvent) {
+	m.Called(event)
+}
+
+func (m *MockEventBus) Subscribe(eventType string, handler events.EventHandler) {
+	m.Called(eventType, handler)
+}
+
+// newInitiateSessionHandler prepara el handler con un servicio real sobre repositorios mock y un PC con el estado indicado
+func newInitiateSessionHandler(online, autoAccept bool) (*RemoteControlHandler, *MockRemoteControlNotifier) {
+	sessionRepo := new(MockRemoteSessionRepository)
+	userRepo := new(MockUserRepository)
+	pcRepo := new(MockClientPCRepository)
+	actionLogService := new(MockActionLogService)
+	eventBus := new(MockEventBus)
+	notifier := new(MockRemoteControlNotifier)
+
+	admin := user.NewUser(testAdminUserID, "admin", "", "hashed", user.RoleAdministrator)
+	pc, _ := clientpc.NewClientPC(testClientPCID, "lab-pc-01", "192.168.1.50", testAdminUserID)
+	if online {
+		pc.SetOnline()
+	}
+	pc.SetAutoAcceptControl(autoAccept)
+
+	sessionRepo.On("FindByClientPCID", mock.Anything, testClientPCID).Return([]*remotesession.RemoteSession{}, nil)
+	sessionRepo.On("Save", mock.Anything, mock.AnythingOfType("*remotesession.RemoteSession")).Return(nil)
+	userRepo.On("FindByID", testAdminUserID).Return(admin, nil)
+	pcRepo.On("FindByID", mock.Anything, testClientPCID).Return(pc, nil)
+	actionLogService.On("LogAction", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	eventBus.On("Publish", mock.Anything).Return()
+
+	sessionService := remotesessionservice.NewRemoteSessionService(sessionRepo, userRepo, pcRepo, actionLogService, eventBus)
+	return NewRemoteControlHandler(sessionService, notifier), notifier
+}
+
+// serveInitiateSession envía POST /sessions/initiate autenticado como testAdminUserID
+func serveInitiateSession(handler *RemoteControlHandler) *httptest.ResponseRecorder {
+	return serveInitiateSessionBody(handler, `{"client_pc_id": "`+testClientPCID+`"}`)
+}
+
+// serveInitiateSessionBody envía POST /sessions/initiate con el body indicado
+func serveInitiateSessionBody(handler *RemoteControlHandler, body string) *httptest.ResponseRecorder {
+	router := newTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDKey, testAdminUserID)
+		c.Next()
+	})
+	router.POST("/api/v1/admin/sessions/initiate", handler.InitiateSession)
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/admin/sessions/initiate", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func newTestRemoteControlHandler() (*RemoteControlHandler, *MockRemoteSessionRepository) {
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionService := remotesessionservice.NewRemoteSessionService(sessionRepo, nil, nil, nil, nil)
+	return NewRemoteControlHandler(sessionService, nil), sessionRepo
+}
+
+func TestRemoteControlHandler_GetSessionStatus_ReturnsEnvelopeWithSession(t *testing.T) {
+	// Arrange
+	handler, sessionRepo := newTestRemoteControlHandler()
+	session, err := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	require.NoError(t, err)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/sessions/:sessionId/status", handler.GetSessionStatus)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sessions/"+session.SessionID()+"/status", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, session.SessionID(), data["session_id"])
+	assert.Equal(t, string(remotesession.StatusPendingApproval), data["status"])
+}
+
+// stubRecordingProvider sesiones con una grabación en curso
+type stubRecordingProvider map[string]bool
+
+func (s stubRecordingProvider) IsSessionRecording(sessionID string) bool {
+	return s[sessionID]
+}
+
+func TestRemoteControlHandler_GetSessionStatus_ReportsRecordingInProgress(t *testing.T) {
+	// Arrange
+	handler, sessionRepo := newTestRemoteControlHandler()
+	recording, err := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	require.NoError(t, err)
+	notRecording, err := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	require.NoError(t, err)
+	sessionRepo.On("FindById", mock.Anything, recording.SessionID()).Return(recording, nil)
+	sessionRepo.On("FindById", mock.Anything, notRecording.SessionID()).Return(notRecording, nil)
+	handler.SetRecordingProvider(stubRecordingProvider{recording.SessionID(): true})
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/sessions/:sessionId/status", handler.GetSessionStatus)
+	status := func(sessionID string) map[string]interface{} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sessions/"+sessionID+"/status", nil))
+		return assertSuccessEnvelope(t, recorder, http.StatusOK)
+	}
+
+	// Act
+	recordingStatus := status(recording.SessionID())
+	notRecordingStatus := status(notRecording.SessionID())
+
+	// Assert
+	assert.Equal(t, true, recordingStatus["recording"])
+	assert.Equal(t, false, notRecordingStatus["recording"])
+}
+
+func TestRemoteControlHandler_GetSessionStatus_NotFoundReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	handler, sessionRepo := newTestRemoteControlHandler()
+	sessionRepo.On("FindById", mock.Anything, "missing").Return(nil, nil)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/sessions/:sessionId/status", handler.GetSessionStatus)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sessions/missing/status", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusNotFound, "SESSION_NOT_FOUND")
+}
+
+func TestRemoteControlHandler_GetActiveSessions_ReturnsEnvelopeWithEmptyList(t *testing.T) {
+	// Arrange
+	handler, sessionRepo := newTestRemoteControlHandler()
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusActive).Return([]*remotesession.RemoteSession{}, nil)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/sessions/active", handler.GetActiveSessions)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sessions/active", nil))
+
+	// Assert - la lista vacía se serializa como [] y no como null
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, []interface{}{}, data["sessions"])
+	assert.Equal(t, float64(0), data["count"])
+}
+
+func TestRemoteControlHandler_GetActiveSessions_RepositoryErrorReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	handler, sessionRepo := newTestRemoteControlHandler()
+	sessionRepo.On("FindByStatus", mock.Anything, remotesession.StatusActive).Return([]*remotesession.RemoteSession(nil), errors.New("db down"))
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/sessions/active", handler.GetActiveSessions)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sessions/active", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusInternalServerError, "SESSIONS_RETRIEVAL_FAILED")
+}
+
+func TestRemoteControlHandler_EndSession_OtherAdminReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	handler, sessionRepo := newTestRemoteControlHandler()
+	session, err := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	require.NoError(t, err)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+
+	router := newTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDKey, "another-admin")
+		c.Next()
+	})
+	router.POST("/api/v1/admin/sessions/:sessionId/end", handler.EndSession)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/sessions/"+session.SessionID()+"/end", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusForbidden, "INSUFFICIENT_PERMISSIONS")
+	sessionRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestRemoteControlHandler_InitiateSession_SendsRemoteControlRequestToClient(t *testing.T) {
+	// Arrange
+	handler, notifier := newInitiateSessionHandler(true, false)
+	notifier.On("SendRemoteControlRequestToClient", mock.Anything, testClientPCID, testAdminUserID, "").Return(nil)
+
+	// Act
+	recorder := serveInitiateSession(handler)
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, string(remotesession.StatusPendingApproval), data["status"])
+
+	notifier.AssertNumberOfCalls(t, "SendRemoteControlRequestToClient", 1)
+	notifier.AssertNotCalled(t, "SendAutoAcceptedSessionToClient", mock.Anything, mock.Anything)
+	sentSessionID := notifier.Calls[0].Arguments.String(0)
+	assert.Equal(t, data["session_id"], sentSessionID, "la solicitud enviada debe corresponder a la sesión creada")
+}
+
+func TestRemoteControlHandler_InitiateSession_AutoAcceptedStartsSessionOnClient(t *testing.T) {
+	// Arrange
+	handler, notifier := newInitiateSessionHandler(true, true)
+	notifier.On("SendAutoAcceptedSessionToClient", mock.Anything, testClientPCID).Return(nil)
+
+	// Act
+	recorder := serveInitiateSession(handler)
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, string(remotesession.StatusActive), data["status"])
+	notifier.AssertCalled(t, "SendAutoAcceptedSessionToClient", data["session_id"], testClientPCID)
+	notifier.AssertNotCalled(t, "SendRemoteControlRequestToClient", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRemoteControlHandler_InitiateSession_NotificationFailureStillReturnsSession(t *testing.T) {
+	// Arrange
+	handler, notifier := newInitiateSessionHandler(true, false)
+	notifier.On("SendRemoteControlRequestToClient", mock.Anything, testClientPCID, testAdminUserID, "").
+		Return(errors.New("client PC not connected"))
+
+	// Act
+	recorder := serveInitiateSession(handler)
+
+	// Assert - la sesión ya se creó, el fallo de WebSocket no cambia la respuesta
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.NotEmpty(t, data["session_id"])
+	notifier.AssertExpectations(t)
+}
+
+func TestRemoteControlHandler_InitiateSession_OfflinePCSendsNothing(t *testing.T) {
+	// Arrange
+	handler, notifier := newInitiateSessionHandler(false, false)
+
+	// Act
+	recorder := serveInitiateSession(handler)
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusConflict, "PC_OFFLINE")
+	assert.Empty(t, notifier.Calls)
+}
+
+func TestRemoteControlHandler_InitiateSession_QueuesRequestForOfflinePC(t *testing.T) {
+	// Arrange
+	handler, notifier := newInitiateSessionHandler(false, false)
+
+	// Act
+	recorder := serveInitiateSessionBody(handler, `{"client_pc_id": "`+testClientPCID+`", "queue_if_offline": true}`)
+
+	// Assert - la solicitud queda en cola y no se envía nada al cliente hasta que se registre
+	data := assertSuccessEnvelope(t, recorder, http.StatusAccepted)
+	assert.Equal(t, string(remotesession.StatusQueued), data["status"])
+	assert.NotEmpty(t, data["session_id"])
+	assert.Empty(t, notifier.Calls)
+}
+
+func TestRemoteControlHandler_InitiateSession_FailureCodes(t *testing.T) {
+	activeSession, _ := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	_ = activeSession.Accept()
+	admin := user.NewUser(testAdminUserID, "admin", "", "hashed", user.RoleAdministrator)
+	offlinePC, _ := clientpc.NewClientPC(testClientPCID, "lab-pc-01", "192.168.1.50", testAdminUserID)
+	onlinePC, _ := clientpc.NewClientPC(testClientPCID, "lab-pc-01", "192.168.1.50", testAdminUserID)
+	onlinePC.SetOnline()
+
+	tests := []struct {
+		name           string
+		sessions       []*remotesession.RemoteSession
+		admin          *user.User
+		pc             *clientpc.ClientPC
+		pcErr          error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"PC offline", nil, admin, offlinePC, nil, http.StatusConflict, "PC_OFFLINE"},
+		{"PC no registrado", nil, admin, nil, nil, http.StatusNotFound, "PC_NOT_FOUND"},
+		{"sesión ya activa", []*remotesession.RemoteSession{activeSession}, admin, onlinePC, nil, http.StatusConflict, "SESSION_ALREADY_ACTIVE"},
+		{"administrador inexistente", nil, nil, onlinePC, nil, http.StatusUnauthorized, "UNAUTHORIZED"},
+		{"error de repositorio", nil, admin, nil, errors.New("db down"), http.StatusInternalServerError, "SESSION_INITIATION_FAILED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			sessionRepo := new(MockRemoteSessionRepository)
+			userRepo := new(MockUserRepository)
+			pcRepo := new(MockClientPCRepository)
+			notifier := new(MockRemoteControlNotifier)
+
+			sessions := tt.sessions
+			if sessions == nil {
+				sessions = []*remotesession.RemoteSession{}
+			}
+			sessionRepo.On("FindByClientPCID", mock.Anything, testClientPCID).Return(sessions, nil)
+			if tt.admin != nil {
+				userRepo.On("FindByID", testAdminUserID).Return(tt.admin, nil)
+			} else {
+				userRepo.On("FindByID", testAdminUserID).Return(nil, nil)
+			}
+			if tt.pc != nil {
+				pcRepo.On("FindByID", mock.Anything, testClientPCID).Return(tt.pc, tt.pcErr)
+			} else {
+				pcRepo.On("FindByID", mock.Anything, testClientPCID).Return(nil, tt.pcErr)
+			}
+
+			sessionService := remotesessionservice.NewRemoteSessionService(sessionRepo, userRepo, pcRepo, new(MockActionLogService), new(MockEventBus))
+			handler := NewRemoteControlHandler(sessionService, notifier)
+
+			// Act
+			recorder := serveInitiateSession(handler)
+
+			// Assert
+			assertErrorEnvelope(t, recorder, tt.expectedStatus, tt.expectedCode)
+			sessionRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+			assert.Empty(t, notifier.Calls)
+		})
+	}
+}
+
+func TestInitiateSessionErrorCode_ValidationErrorsStayBadRequest(t *testing.T) {
+	// Arrange
+	validationErr := fmt.Errorf("%w: admin user ID cannot be empty", remotesessionservice.ErrInvalidSessionRequest)
+	repositoryErr := fmt.Errorf("error saving session: %w", errors.New("db down"))
+
+	// Act
+	validationStatus, validationCode := initiateSessionErrorCode(validationErr)
+	repositoryStatus, repositoryCode := initiateSessionErrorCode(repositoryErr)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, validationStatus)
+	assert.Equal(t, "VALIDATION_ERROR", validationCode)
+	assert.Equal(t, http.StatusInternalServerError, repositoryStatus)
+	assert.Equal(t, "SESSION_INITIATION_FAILED", repositoryCode)
+}
+
+func TestRemoteControlHandler_InitiateSession_RequiredJustificationMissingReturnsBadRequest(t *testing.T) {
+	// Arrange
+	handler, notifier := newInitiateSessionHandler(true, false)
+	handler.sessionService.SetRequireJustification(true)
+
+	// Act
+	recorder := serveInitiateSessionBody(handler, `{"client_pc_id": "`+testClientPCID+`", "reason": "   "}`)
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusBadRequest, "JUSTIFICATION_REQUIRED")
+	notifier.AssertNotCalled(t, "SendRemoteControlRequestToClient", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRemoteControlHandler_InitiateSession_TooLongReasonReturnsBadRequest(t *testing.T) {
+	// Arrange
+	handler, notifier := newInitiateSessionHandler(true, false)
+	reason := strings.Repeat("a", remotesession.MaxJustificationReasonLength+1)
+
+	// Act
+	recorder := serveInitiateSessionBody(handler, `{"client_pc_id": "`+testClientPCID+`", "reason": "`+reason+`"}`)
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusBadRequest, "VALIDATION_ERROR")
+	notifier.AssertNotCalled(t, "SendRemoteControlRequestToClient", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRemoteControlHandler_GetSessionStatus_ReturnsJustification(t *testing.T) {
+	// Arrange
+	handler, sessionRepo := newTestRemoteControlHandler()
+	session, err := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	require.NoError(t, err)
+	session.SetJustification(remotesession.Justification{Reason: "Printer driver crash", TicketID: "HD-4821"})
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/sessions/:sessionId/status", handler.GetSessionStatus)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sessions/"+session.SessionID()+"/status", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, "Printer driver crash", data["reason"])
+	assert.Equal(t, "HD-4821", data["ticket_id"])
+}
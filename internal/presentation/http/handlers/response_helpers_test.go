@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/middleware"
+)
+
+// testEnvelope refleja dto.APIResponse con data sin tipar para inspeccionar el JSON recibido
+type testEnvelope struct {
+	Success bool                   `json:"success"`
+	Data    map[string]interface{} `json:"data"`
+	Meta    *dto.PageMeta          `json:"meta"`
+	Error   *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// newTestRouter crea un router gin en modo test
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+// newTestAdminRouter crea un router gin en modo test con el administrador de prueba autenticado
+func newTestAdminRouter() *gin.Engine {
+	router := newTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDKey, testAdminUserID)
+		c.Next()
+	})
+	return router
+}
+
+// assertSuccessEnvelope verifica el envelope de éxito y retorna sus datos
+func assertSuccessEnvelope(t *testing.T, recorder *httptest.ResponseRecorder, expectedStatus int) map[string]interface{} {
+	t.Helper()
+	assert.Equal(t, expectedStatus, recorder.Code)
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	var envelope testEnvelope
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+	assert.True(t, envelope.Success)
+	assert.Nil(t, envelope.Error)
+	require.NotNil(t, envelope.Data)
+	return envelope.Data
+}
+
+// assertPageMeta verifica los metadatos de paginación del envelope
+func assertPageMeta(t *testing.T, recorder *httptest.ResponseRecorder, expected dto.PageMeta) {
+	t.Helper()
+
+	var envelope testEnvelope
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+	require.NotNil(t, envelope.Meta)
+	assert.Equal(t, expected, *envelope.Meta)
+}
+
+// assertErrorEnvelope verifica el envelope de error con el código esperado
+func assertErrorEnvelope(t *testing.T, recorder *httptest.ResponseRecorder, expectedStatus int, expectedCode string) {
+	t.Helper()
+	assert.Equal(t, expectedStatus, recorder.Code)
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	var envelope testEnvelope
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+	assert.False(t, envelope.Success)
+	assert.Nil(t, envelope.Data)
+	require.NotNil(t, envelope.Error)
+	assert.Equal(t, expectedCode, envelope.Error.Code)
+	assert.NotEmpty(t, envelope.Error.Message)
+}
+
+// MockRemoteSessionRepository es un mock del repositorio de sesiones remotas
+type MockRemoteSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRemoteSessionRepository) Save(ctx context.Context, session *remotesession.RemoteSession) error {
+	return m.Called(ctx, session).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) FindById(ctx context.Context, id string) (*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) UpdateStatus(ctx context.Context, id string, status remotesession.SessionStatus) error {
+	return m.Called(ctx, id, status).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) FindByAdminUserID(ctx context.Context, adminUserID string) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, adminUserID)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindByClientPCID(ctx context.Context, clientPCID string) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, clientPCID)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindActiveSessions(ctx context.Context) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindPendingSessions(ctx context.Context) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) Update(ctx context.Context, session *remotesession.RemoteSession) error {
+	return m.Called(ctx, session).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) Delete(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockRemoteSessionRepository) FindByStatus(ctx context.Context, status remotesession.SessionStatus) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindSessionsByDateRange(ctx context.Context, adminUserID string, startDate, endDate string) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, adminUserID, startDate, endDate)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) FindAccessReport(ctx context.Context, adminUserID, clientPCID string, from, to time.Time, limit, offset int) ([]*remotesession.RemoteSession, error) {
+	args := m.Called(ctx, adminUserID, clientPCID, from, to, limit, offset)
+	return args.Get(0).([]*remotesession.RemoteSession), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) CountAccessReport(ctx context.Context, adminUserID, clientPCID string, from, to time.Time) (int, error) {
+	args := m.Called(ctx, adminUserID, clientPCID, from, to)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRemoteSessionRepository) CountSessionsByUser(ctx context.Context, adminUserID string) (int64, error) {
+	args := m.Called(ctx, adminUserID)
+	return args.Get(0).(int64), args.Error(1)
+}
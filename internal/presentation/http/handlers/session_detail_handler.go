@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/filetransferservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// sessionTimelineLimit entradas del audit log incluidas en el detalle de una sesión
+const sessionTimelineLimit = 200
+
+// SessionDetailHandler reúne en una respuesta el estado, las transferencias, la grabación y el audit log de una sesión
+type SessionDetailHandler struct {
+	sessionService      *remotesessionservice.RemoteSessionService
+	fileTransferService *filetransferservice.FileTransferService
+	videoService        videoservice.IVideoService
+	actionLogService    actionlogservice.IActionLogService
+	// accessAudit registra quién ve cada grabación (nil = sin auditoría de accesos)
+	accessAudit *actionlogservice.AccessAuditor
+}
+
+// NewSessionDetailHandler crea una nueva instancia del handler de detalle de sesión
+func NewSessionDetailHandler(
+	sessionService *remotesessionservice.RemoteSessionService,
+	fileTransferService *filetransferservice.FileTransferService,
+	videoService videoservice.IVideoService,
+	actionLogService actionlogservice.IActionLogService,
+) *SessionDetailHandler {
+	return &SessionDetailHandler{
+		sessionService:      sessionService,
+		fileTransferService: fileTransferService,
+		videoService:        videoService,
+		actionLogService:    actionLogService,
+	}
+}
+
+// SetAccessAuditor configura la auditoría de visualización de grabaciones
+func (h *SessionDetailHandler) SetAccessAuditor(auditor *actionlogservice.AccessAuditor) {
+	h.accessAudit = auditor
+}
+
+// GetSessionDetail maneja GET /api/v1/admin/sessions/:sessionId/detail
+func (h *SessionDetailHandler) GetSessionDetail(c *gin.Context) {
+	sessionID := c.Param("sessionId")
+	if sessionID == "" {
+		response.Error(c, http.StatusBadRequest, "INVALID_SESSION_ID", "Session ID is required")
+		return
+	}
+
+	ctx := c.Request.Context()
+	session, err := h.sessionService.GetSessionById(ctx, sessionID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "SESSION_RETRIEVAL_FAILED", err.Error())
+		return
+	}
+	if session == nil {
+		response.Error(c, http.StatusNotFound, "SESSION_NOT_FOUND", "Session not found")
+		return
+	}
+
+	detail := dto.SessionDetailResponse{
+		Session:   toSessionStatusResponse(h.sessionService, h.videoService, session),
+		Transfers: []dto.FileTransferDTO{},
+		Timeline:  []dto.SessionTimelineEntryDTO{},
+	}
+
+	transfers, err := h.fileTransferService.GetTransfersBySessionID(ctx, sessionID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "TRANSFERS_RETRIEVAL_FAILED", err.Error())
+		return
+	}
+	for _, transfer := range transfers {
+		detail.Transfers = append(detail.Transfers, toFileTransferDTO(transfer))
+	}
+
+	// Como /recording/metadata, se muestra la primera grabación de la sesión
+	videos, err := h.videoService.GetVideosBySessionID(ctx, sessionID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "VIDEOS_RETRIEVAL_FAILED", err.Error())
+		return
+	}
+	if len(videos) > 0 {
+		// Sin el recuento de frames el resto del detalle sigue siendo útil: solo ese campo se degrada
+		totalFrames, err := h.videoService.CountVideoFrames(videos[0].FilePath())
+		if err != nil {
+			log.Printf("⚠️ SESSION DETAIL: Could not count frames of video %s: %v", videos[0].VideoID(), err)
+			totalFrames = 0
+		}
+		recording := toRecordingMetadataDTO(h.videoService, videos[0], totalFrames)
+		recording.FrameCountUnavailable = err != nil
+		detail.Recording = &recording
+		h.accessAudit.LogRecordingViewed(ctx, requestAdminUserID(c), sessionID, videos[0].VideoID(), "detail")
+	}
+
+	if h.actionLogService != nil {
+		logs, err := h.sessionTimelineLogs(ctx, sessionID, videos, transfers)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "TIMELINE_RETRIEVAL_FAILED", err.Error())
+			return
+		}
+		detail.Timeline = toSessionTimeline(logs)
+	}
+
+	response.Success(c, http.StatusOK, detail)
+}
+
+// sessionTimelineLogs entradas del audit log de la sesión y de sus grabaciones y transferencias
+// (subidas, descartes, visualizaciones, descargas)
+func (h *SessionDetailHandler) sessionTimelineLogs(ctx context.Context, sessionID string, videos []*sessionvideo.SessionVideo, transfers []*filetransfer.FileTransfer) ([]*actionlog.ActionLog, error) {
+	logs, err := h.actionLogService.GetLogsByEntity(ctx, sessionID, "REMOTE_SESSION", sessionTimelineLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, video := range videos {
+		videoLogs, err := h.actionLogService.GetLogsByEntity(ctx, video.VideoID(), "SESSION_VIDEO", sessionTimelineLimit, 0)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, videoLogs...)
+	}
+	for _, transfer := range transfers {
+		transferLogs, err := h.actionLogService.GetLogsByEntity(ctx, transfer.TransferID(), "FILE_TRANSFER", sessionTimelineLimit, 0)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, transferLogs...)
+	}
+	return logs, nil
+}
+
+// toSessionTimeline ordena las entradas del audit log de la más antigua a la más reciente y conserva las
+// sessionTimelineLimit más recientes
+func toSessionTimeline(logs []*actionlog.ActionLog) []dto.SessionTimelineEntryDTO {
+	timeline := make([]dto.SessionTimelineEntryDTO, 0, len(logs))
+	for _, entry := range logs {
+		timeline = append(timeline, dto.SessionTimelineEntryDTO{
+			Timestamp:         entry.Timestamp(),
+			ActionType:        string(entry.ActionType()),
+			Description:       entry.Description(),
+			PerformedByUserID: entry.PerformedByUserID(),
+			Details:           entry.Details(),
+		})
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Timestamp.Before(timeline[j].Timestamp)
+	})
+	if len(timeline) > sessionTimelineLimit {
+		timeline = timeline[len(timeline)-sessionTimelineLimit:]
+	}
+	return timeline
+}
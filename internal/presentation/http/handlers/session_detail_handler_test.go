@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/filetransferservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/filetransfer"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+// sessionDetailFixture handler de detalle con una sesión activa y sus dependencias simuladas
+type sessionDetailFixture struct {
+	handler          *SessionDetailHandler
+	session          *remotesession.RemoteSession
+	transferRepo     *MockFileTransferRepository
+	videoService     *MockVideoService
+	actionLogService *MockActionLogService
+}
+
+func newSessionDetailFixture(t *testing.T) *sessionDetailFixture {
+	t.Helper()
+
+	session, err := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	require.NoError(t, err)
+	require.NoError(t, session.Accept())
+
+	sessionRepo := new(MockRemoteSessionRepository)
+	sessionRepo.On("FindById", mock.Anything, session.SessionID()).Return(session, nil)
+	transferRepo := new(MockFileTransferRepository)
+	videoService := new(MockVideoService)
+	actionLogService := new(MockActionLogService)
+
+	handler := NewSessionDetailHandler(
+		remotesessionservice.NewRemoteSessionService(sessionRepo, nil, nil, nil, nil),
+		filetransferservice.NewFileTransferService(transferRepo, nil, nil, nil),
+		videoService,
+		actionLogService,
+	)
+	return &sessionDetailFixture{
+		handler:          handler,
+		session:          session,
+		transferRepo:     transferRepo,
+		videoService:     videoService,
+		actionLogService: actionLogService,
+	}
+}
+
+func (f *sessionDetailFixture) serve() *httptest.ResponseRecorder {
+	router := newTestRouter()
+	router.GET("/api/v1/admin/sessions/:sessionId/detail", f.handler.GetSessionDetail)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sessions/"+f.session.SessionID()+"/detail", nil))
+	return recorder
+}
+
+func TestSessionDetailHandler_GetSessionDetail_CombinesAllSections(t *testing.T) {
+	// Arrange
+	fixture := newSessionDetailFixture(t)
+	sessionID := fixture.session.SessionID()
+
+	transfer := filetransfer.NewFileTransfer("report.pdf", "/srv/report.pdf", "C:/Downloads/report.pdf",
+		sessionID, testAdminUserID, testClientPCID, 1.5)
+	fixture.transferRepo.On("FindBySessionID", mock.Anything, sessionID).Return([]*filetransfer.FileTransfer{transfer}, nil)
+
+	video := sessionvideo.NewSessionVideo("storage/session_videos/video-1/frames", 10, sessionID, 2.5)
+	fixture.videoService.On("GetVideosBySessionID", mock.Anything, sessionID).Return([]*sessionvideo.SessionVideo{video}, nil)
+	fixture.videoService.On("CountVideoFrames", video.FilePath()).Return(100, nil)
+	fixture.videoService.On("GetFrameCadence", video.FilePath()).Return(nil, videoservice.ErrFrameTimestampsNotFound)
+	fixture.videoService.On("IsSessionRecording", sessionID).Return(true)
+
+	// El repositorio devuelve el audit log del más reciente al más antiguo
+	entityType := "REMOTE_SESSION"
+	startedAt := time.Now().Add(-time.Minute)
+	logs := []*actionlog.ActionLog{
+		actionlog.NewActionLogFromDB(2, startedAt.Add(30*time.Second), actionlog.ActionRemoteSessionActivity, "Client activity",
+			testAdminUserID, &sessionID, &entityType, map[string]interface{}{"idle_count": 1}, startedAt),
+		actionlog.NewActionLogFromDB(1, startedAt, actionlog.ActionRemoteSessionStarted, "Session started",
+			testAdminUserID, &sessionID, &entityType, nil, startedAt),
+	}
+	fixture.actionLogService.On("GetLogsByEntity", mock.Anything, sessionID, "REMOTE_SESSION", sessionTimelineLimit, 0).Return(logs, nil)
+
+	// Las entradas de la grabación y de la transferencia también forman parte del timeline
+	videoEntity, transferEntity, videoID, transferID := "SESSION_VIDEO", "FILE_TRANSFER", video.VideoID(), transfer.TransferID()
+	fixture.actionLogService.On("GetLogsByEntity", mock.Anything, videoID, "SESSION_VIDEO", sessionTimelineLimit, 0).Return([]*actionlog.ActionLog{
+		actionlog.NewActionLogFromDB(4, startedAt.Add(50*time.Second), actionlog.ActionRecordingViewed, "Recording viewed",
+			testAdminUserID, &videoID, &videoEntity, nil, startedAt),
+	}, nil)
+	fixture.actionLogService.On("GetLogsByEntity", mock.Anything, transferID, "FILE_TRANSFER", sessionTimelineLimit, 0).Return([]*actionlog.ActionLog{
+		actionlog.NewActionLogFromDB(3, startedAt.Add(40*time.Second), actionlog.ActionFileTransferDownloaded, "Transfer downloaded",
+			testAdminUserID, &transferID, &transferEntity, nil, startedAt),
+	}, nil)
+
+	// Act
+	recorder := fixture.serve()
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+
+	session := data["session"].(map[string]interface{})
+	assert.Equal(t, sessionID, session["session_id"])
+	assert.Equal(t, string(remotesession.StatusActive), session["status"])
+	assert.Equal(t, true, session["recording"])
+
+	transfers := data["transfers"].([]interface{})
+	require.Len(t, transfers, 1)
+	assert.Equal(t, transfer.TransferID(), transfers[0].(map[string]interface{})["transfer_id"])
+
+	recording := data["recording"].(map[string]interface{})
+	assert.Equal(t, video.VideoID(), recording["video_id"])
+	assert.Equal(t, float64(100), recording["total_frames"])
+	assert.NotContains(t, recording, "frame_count_unavailable")
+
+	timeline := data["timeline"].([]interface{})
+	require.Len(t, timeline, 4)
+	assert.Equal(t, string(actionlog.ActionRemoteSessionStarted), timeline[0].(map[string]interface{})["action_type"])
+	assert.Equal(t, string(actionlog.ActionRemoteSessionActivity), timeline[1].(map[string]interface{})["action_type"])
+	assert.Equal(t, string(actionlog.ActionFileTransferDownloaded), timeline[2].(map[string]interface{})["action_type"])
+	assert.Equal(t, string(actionlog.ActionRecordingViewed), timeline[3].(map[string]interface{})["action_type"])
+}
+
+func TestSessionDetailHandler_GetSessionDetail_FrameCountFailureDegradesOnlyThatField(t *testing.T) {
+	// Arrange - el directorio de frames no se puede leer
+	fixture := newSessionDetailFixture(t)
+	sessionID := fixture.session.SessionID()
+	fixture.transferRepo.On("FindBySessionID", mock.Anything, sessionID).Return([]*filetransfer.FileTransfer{}, nil)
+	video := sessionvideo.NewSessionVideo("storage/session_videos/video-1/frames", 10, sessionID, 2.5)
+	fixture.videoService.On("GetVideosBySessionID", mock.Anything, sessionID).Return([]*sessionvideo.SessionVideo{video}, nil)
+	fixture.videoService.On("CountVideoFrames", video.FilePath()).Return(0, errors.New("permission denied"))
+	fixture.videoService.On("GetFrameCadence", video.FilePath()).Return(nil, videoservice.ErrFrameTimestampsNotFound)
+	fixture.videoService.On("IsSessionRecording", sessionID).Return(false)
+	fixture.actionLogService.On("GetLogsByEntity", mock.Anything, mock.Anything, mock.Anything, sessionTimelineLimit, 0).Return([]*actionlog.ActionLog{}, nil)
+
+	// Act
+	recorder := fixture.serve()
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	recording := data["recording"].(map[string]interface{})
+	assert.Equal(t, video.VideoID(), recording["video_id"])
+	assert.Equal(t, float64(0), recording["total_frames"])
+	assert.Equal(t, true, recording["frame_count_unavailable"])
+	assert.Equal(t, sessionID, data["session"].(map[string]interface{})["session_id"])
+}
+
+func TestSessionDetailHandler_GetSessionDetail_WithoutRecordingOrTransfers(t *testing.T) {
+	// Arrange
+	fixture := newSessionDetailFixture(t)
+	sessionID := fixture.session.SessionID()
+	fixture.transferRepo.On("FindBySessionID", mock.Anything, sessionID).Return([]*filetransfer.FileTransfer{}, nil)
+	fixture.videoService.On("GetVideosBySessionID", mock.Anything, sessionID).Return([]*sessionvideo.SessionVideo{}, nil)
+	fixture.videoService.On("IsSessionRecording", sessionID).Return(false)
+	fixture.actionLogService.On("GetLogsByEntity", mock.Anything, sessionID, "REMOTE_SESSION", sessionTimelineLimit, 0).Return([]*actionlog.ActionLog{}, nil)
+
+	// Act
+	recorder := fixture.serve()
+
+	// Assert - las secciones vacías son listas vacías y la grabación es null, no un error
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, sessionID, data["session"].(map[string]interface{})["session_id"])
+	assert.Equal(t, []interface{}{}, data["transfers"])
+	assert.Nil(t, data["recording"])
+	assert.Equal(t, []interface{}{}, data["timeline"])
+	fixture.videoService.AssertNotCalled(t, "CountVideoFrames", mock.Anything)
+}
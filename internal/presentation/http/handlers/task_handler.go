@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/backgroundtaskservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// TaskHandler lista y cancela las goroutines de transferencias lanzadas por el servidor
+type TaskHandler struct {
+	registry *backgroundtaskservice.TaskRegistry
+}
+
+// NewTaskHandler crea una nueva instancia del handler de tareas en segundo plano
+func NewTaskHandler(registry *backgroundtaskservice.TaskRegistry) *TaskHandler {
+	return &TaskHandler{
+		registry: registry,
+	}
+}
+
+// ListTasks maneja GET /api/v1/admin/tasks
+func (h *TaskHandler) ListTasks(c *gin.Context) {
+	if _, ok := requireAdministrator(c); !ok {
+		return
+	}
+
+	tasks := h.registry.List()
+	taskDTOs := make([]dto.BackgroundTaskDTO, 0, len(tasks))
+	for _, task := range tasks {
+		taskDTOs = append(taskDTOs, toBackgroundTaskDTO(task))
+	}
+
+	response.Success(c, http.StatusOK, dto.BackgroundTasksResponse{
+		Tasks: taskDTOs,
+		Count: len(taskDTOs),
+	})
+}
+
+// CancelTask maneja DELETE /api/v1/admin/tasks/:taskId. La transferencia se detiene antes del siguiente
+// chunk y queda como FAILED; si aún estaba en cola no llega a enviarse.
+func (h *TaskHandler) CancelTask(c *gin.Context) {
+	claims, ok := requireAdministrator(c)
+	if !ok {
+		return
+	}
+
+	task, err := h.registry.Cancel(c.Param("taskId"))
+	if errors.Is(err, backgroundtaskservice.ErrTaskNotFound) {
+		response.Error(c, http.StatusNotFound, "TASK_NOT_FOUND", "Task not found or already finished")
+		return
+	}
+
+	log.Printf("🛑 BACKGROUND TASK: Admin %s cancelled %s task %s (transfer %s)", claims.UserID, task.Kind, task.ID, task.TransferID)
+	response.Success(c, http.StatusOK, toBackgroundTaskDTO(task))
+}
+
+// toBackgroundTaskDTO convierte la información de una tarea a DTO
+func toBackgroundTaskDTO(task backgroundtaskservice.TaskInfo) dto.BackgroundTaskDTO {
+	return dto.BackgroundTaskDTO{
+		ID:         task.ID,
+		Kind:       task.Kind,
+		TransferID: task.TransferID,
+		StartedAt:  task.StartedAt,
+		Status:     string(task.Status),
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/backgroundtaskservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+)
+
+func TestTaskHandler_ListTasks_ReturnsRegisteredTransfers(t *testing.T) {
+	// Arrange
+	registry := backgroundtaskservice.NewTaskRegistry()
+	task := registry.Register(backgroundtaskservice.TaskKindPendingTransfer, "transfer-queued")
+	defer task.Finish()
+	handler := NewTaskHandler(registry)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/tasks", withRole(user.RoleAdministrator), handler.ListTasks)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/tasks", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, float64(1), data["count"])
+	tasks := data["tasks"].([]interface{})
+	listed := tasks[0].(map[string]interface{})
+	assert.Equal(t, task.ID(), listed["id"])
+	assert.Equal(t, "transfer-queued", listed["transfer_id"])
+	assert.Equal(t, "QUEUED", listed["status"])
+}
+
+func TestTaskHandler_CancelTask_CancelsQueuedTransfer(t *testing.T) {
+	// Arrange
+	registry := backgroundtaskservice.NewTaskRegistry()
+	task := registry.Register(backgroundtaskservice.TaskKindPendingTransfer, "transfer-queued")
+	defer task.Finish()
+	handler := NewTaskHandler(registry)
+
+	router := newTestRouter()
+	router.DELETE("/api/v1/admin/tasks/:taskId", withRole(user.RoleAdministrator), handler.CancelTask)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/tasks/"+task.ID(), nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, "CANCELLING", data["status"])
+	assert.Error(t, task.Context().Err())
+	assert.True(t, registry.TransferCancelled("transfer-queued"))
+}
+
+func TestTaskHandler_CancelTask_UnknownTaskReturnsNotFound(t *testing.T) {
+	// Arrange
+	handler := NewTaskHandler(backgroundtaskservice.NewTaskRegistry())
+
+	router := newTestRouter()
+	router.DELETE("/api/v1/admin/tasks/:taskId", withRole(user.RoleAdministrator), handler.CancelTask)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/tasks/missing", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusNotFound, "TASK_NOT_FOUND")
+}
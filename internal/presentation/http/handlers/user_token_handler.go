@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// TokenRevoker invalida los tokens emitidos a un usuario
+type TokenRevoker interface {
+	RevokeUserTokens(userID string) (*user.User, error)
+}
+
+// AdminConnectionCloser cierra las conexiones WebSocket vivas de un administrador
+type AdminConnectionCloser interface {
+	CloseAdminConnections(adminUserID, reason string) int
+}
+
+// UserTokenHandler revoca los tokens de un usuario cuyo token se cree comprometido
+type UserTokenHandler struct {
+	tokenRevoker     TokenRevoker
+	adminConnections AdminConnectionCloser
+	actionLogService actionlogservice.IActionLogService
+}
+
+// NewUserTokenHandler crea una nueva instancia del handler de revocación de tokens; actionLogService es opcional
+func NewUserTokenHandler(
+	tokenRevoker TokenRevoker,
+	adminConnections AdminConnectionCloser,
+	actionLogService actionlogservice.IActionLogService,
+) *UserTokenHandler {
+	return &UserTokenHandler{
+		tokenRevoker:     tokenRevoker,
+		adminConnections: adminConnections,
+		actionLogService: actionLogService,
+	}
+}
+
+// RevokeTokens maneja POST /api/v1/admin/users/:userId/revoke-tokens. Invalida los tokens del usuario y cierra
+// sus WebSockets de administrador: tiene que volver a iniciar sesión.
+func (h *UserTokenHandler) RevokeTokens(c *gin.Context) {
+	claims, ok := requireAdministrator(c)
+	if !ok {
+		return
+	}
+
+	userID := c.Param("userId")
+	target, err := h.tokenRevoker.RevokeUserTokens(userID)
+	if err != nil {
+		if errors.Is(err, userservice.ErrUserNotFound) {
+			response.Error(c, http.StatusNotFound, "USER_NOT_FOUND", "User not found")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "TOKEN_REVOCATION_FAILED", err.Error())
+		return
+	}
+
+	closed := h.adminConnections.CloseAdminConnections(userID, "tokens revoked")
+	log.Printf("🔒 TOKEN REVOCATION: Admin %s revoked tokens of user %s (%s), %d connections closed",
+		claims.UserID, userID, target.Username(), closed)
+	h.logRevocation(c, claims.UserID, target, closed)
+
+	response.Success(c, http.StatusOK, dto.UserTokensRevokedResponse{
+		UserID:            userID,
+		Username:          target.Username(),
+		ClosedConnections: closed,
+		RevokedAt:         time.Now(),
+	})
+}
+
+// logRevocation registra la revocación en el audit log; un fallo del audit log no deshace la revocación
+func (h *UserTokenHandler) logRevocation(c *gin.Context, adminUserID string, target *user.User, closedConnections int) {
+	if h.actionLogService == nil {
+		return
+	}
+
+	userID := target.UserID()
+	entityType := "USER"
+	details := map[string]interface{}{
+		"username":           target.Username(),
+		"closed_connections": closedConnections,
+	}
+	description := fmt.Sprintf("Tokens of user %s revoked", target.Username())
+	if err := h.actionLogService.LogAction(c.Request.Context(), actionlog.ActionUserTokensRevoked, description, adminUserID, &userID, &entityType, details); err != nil {
+		log.Printf("⚠️ Warning: Failed to log token revocation audit entry: %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// recordingConnectionCloser registra los administradores cuyas conexiones se cerraron
+type recordingConnectionCloser struct {
+	closedUserIDs []string
+	open          int
+}
+
+func (r *recordingConnectionCloser) CloseAdminConnections(adminUserID, reason string) int {
+	r.closedUserIDs = append(r.closedUserIDs, adminUserID)
+	return r.open
+}
+
+func TestUserTokenHandler_RevokeTokens_RejectsExistingTokenAndClosesSockets(t *testing.T) {
+	// Arrange - el administrador comprometido tiene un token válido y dos WebSockets abiertos
+	userRepo := new(MockUserRepository)
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	target := user.NewUser("compromised-id", "operator1", "", string(hashedPassword), user.RoleOperator)
+	userRepo.On("FindByUsername", "operator1").Return(target, nil)
+	userRepo.On("FindByID", "compromised-id").Return(target, nil)
+	userRepo.On("IncrementTokenVersion", "compromised-id").Return(1, nil)
+
+	authService := userservice.NewAuthService(userRepo, "test-secret")
+	token, _, err := authService.AuthenticateAdmin("operator1", "password")
+	require.NoError(t, err)
+	_, err = authService.ValidateToken(token)
+	require.NoError(t, err)
+
+	closer := &recordingConnectionCloser{open: 2}
+	actionLog := new(MockActionLogService)
+	actionLog.On("LogAction", mock.Anything, actionlog.ActionUserTokensRevoked, mock.Anything, testAdminUserID,
+		mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	handler := NewUserTokenHandler(authService, closer, actionLog)
+
+	router := newTestRouter()
+	router.POST("/api/v1/admin/users/:userId/revoke-tokens", withRole(user.RoleAdministrator), handler.RevokeTokens)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/compromised-id/revoke-tokens", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, "compromised-id", data["user_id"])
+	assert.Equal(t, float64(2), data["closed_connections"])
+	assert.Equal(t, []string{"compromised-id"}, closer.closedUserIDs)
+
+	_, err = authService.ValidateToken(token)
+	assert.ErrorIs(t, err, userservice.ErrInvalidToken)
+	assert.ErrorIs(t, err, userservice.ErrTokenRevoked)
+
+	// Un nuevo inicio de sesión emite un token válido
+	newToken, _, err := authService.AuthenticateAdmin("operator1", "password")
+	require.NoError(t, err)
+	_, err = authService.ValidateToken(newToken)
+	assert.NoError(t, err)
+	actionLog.AssertExpectations(t)
+}
+
+func TestUserTokenHandler_RevokeTokens_UnknownUserReturnsNotFound(t *testing.T) {
+	// Arrange
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByID", "missing-id").Return(nil, nil)
+	closer := &recordingConnectionCloser{}
+	handler := NewUserTokenHandler(userservice.NewAuthService(userRepo, "test-secret"), closer, nil)
+
+	router := newTestRouter()
+	router.POST("/api/v1/admin/users/:userId/revoke-tokens", withRole(user.RoleAdministrator), handler.RevokeTokens)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/missing-id/revoke-tokens", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusNotFound, "USER_NOT_FOUND")
+	assert.Empty(t, closer.closedUserIDs)
+}
@@ -1,16 +1,20 @@
 package handlers
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/middleware"
 )
 
 // VideoHandler maneja las solicitudes HTTP relacionadas con videos y frames
@@ -18,6 +22,12 @@ type VideoHandler struct {
 	sessionService *remotesessionservice.RemoteSessionService
 	videoService   videoservice.IVideoService
 	authService    *userservice.AuthService
+	// accessAudit registra quién ve cada grabación (nil = sin auditoría de accesos)
+	accessAudit *actionlogservice.AccessAuditor
+	// thumbnailService genera las miniaturas de GET /recordings/{videoId}/thumbnails (nil = no disponible)
+	thumbnailService videoservice.IRecordingThumbnailService
+	// orphanedRecordings detecta y limpia grabaciones de sesiones que ya no existen (nil = no disponible)
+	orphanedRecordings videoservice.IOrphanedRecordingService
 }
 
 // NewVideoHandler crea una nueva instancia del handler de video
@@ -33,33 +43,29 @@ func NewVideoHandler(
 	}
 }
 
+// SetAccessAuditor configura la auditoría de visualización de grabaciones
+func (vh *VideoHandler) SetAccessAuditor(auditor *actionlogservice.AccessAuditor) {
+	vh.accessAudit = auditor
+}
+
 // GetRecordingMetadata obtiene los metadatos de una grabación por sessionId
-// GET /api/admin/sessions/{sessionId}/recording/metadata
+// GET /api/v1/admin/sessions/{sessionId}/recording/metadata
 func (vh *VideoHandler) GetRecordingMetadata(c *gin.Context) {
 	sessionID := c.Param("sessionId")
 	if sessionID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Session ID requerido",
-		})
+		response.Error(c, http.StatusBadRequest, "INVALID_SESSION_ID", "Session ID requerido")
 		return
 	}
 
 	// Obtener videos por sessionID
 	videos, err := vh.videoService.GetVideosBySessionID(c.Request.Context(), sessionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Error obteniendo videos de la sesión",
-		})
+		response.Error(c, http.StatusInternalServerError, "VIDEOS_RETRIEVAL_FAILED", "Error obteniendo videos de la sesión")
 		return
 	}
 
 	if len(videos) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"error":   "No se encontraron grabaciones para esta sesión",
-		})
+		response.Error(c, http.StatusNotFound, "RECORDING_NOT_FOUND", "No se encontraron grabaciones para esta sesión")
 		return
 	}
 
@@ -70,129 +76,174 @@ func (vh *VideoHandler) GetRecordingMetadata(c *gin.Context) {
 	framesDir := video.FilePath() // Ahora contiene la ruta del directorio de frames
 	totalFrames, err := vh.countFramesInDirectory(framesDir)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Error contando frames de la grabación",
-		})
+		response.Error(c, http.StatusInternalServerError, "FRAME_COUNT_FAILED", "Error contando frames de la grabación")
 		return
 	}
 
-	// Calcular FPS aproximado
-	var fps float64
-	if video.DurationSeconds() > 0 {
-		fps = float64(totalFrames) / float64(video.DurationSeconds())
-	}
+	vh.accessAudit.LogRecordingViewed(c.Request.Context(), requestAdminUserID(c), sessionID, video.VideoID(), "metadata")
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"video_id":         video.VideoID(),
-			"session_id":       sessionID,
-			"total_frames":     totalFrames,
-			"duration_seconds": video.DurationSeconds(),
-			"fps":              fps,
-			"recorded_at":      video.RecordedAt(),
-			"file_size_mb":     video.FileSizeMB(),
-		},
-	})
+	response.Success(c, http.StatusOK, toRecordingMetadataDTO(vh.videoService, video, totalFrames))
+}
+
+// toRecordingMetadataDTO metadatos de una grabación con sus FPS
+func toRecordingMetadataDTO(videoService videoservice.IVideoService, video *sessionvideo.SessionVideo, totalFrames int) dto.RecordingDTO {
+	fps, fpsSource, variableRate := recordingFPS(videoService, video, totalFrames)
+
+	return dto.RecordingDTO{
+		VideoID:           video.VideoID(),
+		SessionID:         video.AssociatedSessionID(),
+		RecordedAt:        video.RecordedAt(),
+		DurationSeconds:   video.DurationSeconds(),
+		TotalFrames:       totalFrames,
+		FPS:               fps,
+		FPSSource:         fpsSource,
+		VariableFrameRate: variableRate,
+		FileSizeMB:        video.FileSizeMB(),
+	}
 }
 
 // GetVideoFrame sirve un frame individual de video
-// GET /api/admin/sessions/{sessionId}/frames/{frameNumber}
+// GET /api/v1/admin/sessions/{sessionId}/frames/{frameNumber}
 func (vh *VideoHandler) GetVideoFrame(c *gin.Context) {
 	sessionID := c.Param("sessionId")
 	frameNumberStr := c.Param("frameNumber")
 
 	if sessionID == "" || frameNumberStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Session ID y número de frame requeridos",
-		})
+		response.Error(c, http.StatusBadRequest, "INVALID_REQUEST", "Session ID y número de frame requeridos")
 		return
 	}
 
 	frameNumber, err := strconv.Atoi(frameNumberStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Número de frame inválido",
-		})
+		response.Error(c, http.StatusBadRequest, "INVALID_FRAME_NUMBER", "Número de frame inválido")
 		return
 	}
 
 	// Obtener videos por sessionID
 	videos, err := vh.videoService.GetVideosBySessionID(c.Request.Context(), sessionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Error obteniendo videos de la sesión",
-		})
+		response.Error(c, http.StatusInternalServerError, "VIDEOS_RETRIEVAL_FAILED", "Error obteniendo videos de la sesión")
 		return
 	}
 
 	if len(videos) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"error":   "No se encontraron grabaciones para esta sesión",
-		})
+		response.Error(c, http.StatusNotFound, "RECORDING_NOT_FOUND", "No se encontraron grabaciones para esta sesión")
 		return
 	}
 
 	// Tomar el primer video
 	video := videos[0]
 
-	// Construir ruta del frame
-	frameFileName := fmt.Sprintf("frame_%06d.jpg", frameNumber)
-	frameFilePath := filepath.Join(video.FilePath(), frameFileName)
-
-	// Verificar que el archivo existe
-	if _, err := os.Stat(frameFilePath); os.IsNotExist(err) {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"error":   "Frame no encontrado",
-		})
+	// Leer el frame (archivo individual o contenedor empaquetado)
+	frameData, err := vh.videoService.GetVideoFrame(video.FilePath(), frameNumber)
+	if err != nil {
+		if errors.Is(err, videoservice.ErrFrameNotFound) {
+			response.Error(c, http.StatusNotFound, "FRAME_NOT_FOUND", "Frame no encontrado")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "FRAME_READ_FAILED", "Error leyendo frame de la grabación")
 		return
 	}
 
-	// Servir el archivo JPEG con headers apropiados
-	c.Header("Content-Type", "image/jpeg")
+	// Los frames de una misma visualización se agrupan en una sola entrada de auditoría
+	vh.accessAudit.LogRecordingViewed(c.Request.Context(), requestAdminUserID(c), sessionID, video.VideoID(), "frame")
+
+	// Servir el JPEG con headers apropiados
 	c.Header("Cache-Control", "public, max-age=3600") // Cache por 1 hora
-	c.File(frameFilePath)
+	c.Data(http.StatusOK, "image/jpeg", frameData)
 }
 
-// countFramesInDirectory cuenta los archivos de frame en un directorio
-func (vh *VideoHandler) countFramesInDirectory(dirPath string) (int, error) {
-	entries, err := os.ReadDir(dirPath)
+// GetRecordingStorage retorna el número de frames y el espacio en disco de una grabación
+// GET /api/v1/admin/recordings/{videoId}/storage
+func (vh *VideoHandler) GetRecordingStorage(c *gin.Context) {
+	videoID := c.Param("videoId")
+	if videoID == "" {
+		response.Error(c, http.StatusBadRequest, "INVALID_VIDEO_ID", "Video ID requerido")
+		return
+	}
+
+	// El repositorio no distingue "no encontrado" de otros errores de consulta
+	video, err := vh.videoService.GetVideoByID(c.Request.Context(), videoID)
+	if err != nil || video == nil {
+		response.Error(c, http.StatusNotFound, "RECORDING_NOT_FOUND", "Grabación no encontrada")
+		return
+	}
+
+	frameCount, err := vh.countFramesInDirectory(video.FilePath())
 	if err != nil {
-		return 0, err
+		response.Error(c, http.StatusInternalServerError, "FRAME_COUNT_FAILED", "Error contando frames de la grabación")
+		return
 	}
 
-	count := 0
-	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".jpg" {
-			count++
-		}
+	usage, err := vh.videoService.GetRecordingStorageUsage(c.Request.Context(), video)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "STORAGE_USAGE_FAILED", "Error calculando el espacio de la grabación")
+		return
+	}
+
+	response.Success(c, http.StatusOK, dto.RecordingStorageDTO{
+		VideoID:       video.VideoID(),
+		SessionID:     video.AssociatedSessionID(),
+		FrameCount:    frameCount,
+		TotalBytes:    usage.TotalBytes,
+		TotalSizeMB:   float64(usage.TotalBytes) / (1024 * 1024),
+		StorageFormat: string(usage.Format),
+		MP4Exported:   usage.MP4Exported,
+		MP4Path:       usage.MP4Path,
+	})
+}
+
+// GetActiveRecordings maneja GET /api/v1/admin/recordings/active - grabaciones que todavía reciben frames
+func (vh *VideoHandler) GetActiveRecordings(c *gin.Context) {
+	active := vh.videoService.ListActiveRecordings()
+
+	recordings := make([]dto.ActiveRecordingDTO, 0, len(active))
+	for _, recording := range active {
+		recordings = append(recordings, dto.ActiveRecordingDTO{
+			VideoID:   recording.VideoID,
+			SessionID: recording.SessionID,
+			StartedAt: recording.StartedAt,
+			Frames:    recording.Frames,
+		})
 	}
 
-	return count, nil
+	response.Success(c, http.StatusOK, dto.ActiveRecordingsResponse{
+		Recordings: recordings,
+		Count:      len(recordings),
+	})
+}
+
+// requestAdminUserID obtiene el ID del administrador autenticado (vacío si no lo hay)
+func requestAdminUserID(c *gin.Context) string {
+	adminUserID, _ := c.Get(middleware.UserIDKey)
+	id, _ := adminUserID.(string)
+	return id
+}
+
+// countFramesInDirectory cuenta los frames de una grabación en cualquiera de los formatos de almacenamiento
+func (vh *VideoHandler) countFramesInDirectory(dirPath string) (int, error) {
+	return vh.videoService.CountVideoFrames(dirPath)
 }
 
 // GetAllRecordings obtiene todas las grabaciones agrupadas por cliente
-// GET /api/admin/recordings
+// GET /api/v1/admin/recordings
 func (vh *VideoHandler) GetAllRecordings(c *gin.Context) {
 	// Obtener todas las sesiones que tienen videos
 	sessions, err := vh.sessionService.GetAllSessionsWithVideos(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Error obteniendo sesiones con videos",
-		})
+		response.Error(c, http.StatusInternalServerError, "SESSIONS_RETRIEVAL_FAILED", "Error obteniendo sesiones con videos")
 		return
 	}
 
-	// Agrupar por cliente con información adicional
-	clientRecordings := make(map[string]interface{})
-	processedClients := make(map[string]bool) // Para evitar consultas duplicadas
+	// Resolver los identificadores de todos los PCs en una sola consulta
+	pcNames, _, err := vh.sessionService.ResolveSessionLabels(c.Request.Context(), sessions)
+	if err != nil {
+		pcNames = map[string]string{} // Se usa el ID corto como nombre
+	}
+
+	// Agrupar por cliente, conservando el orden en que aparecen las sesiones
+	clientIndex := make(map[string]int)
+	clients := make([]dto.ClientRecordingsDTO, 0)
 
 	for _, session := range sessions {
 		clientPCID := session.ClientPCID()
@@ -208,94 +259,65 @@ func (vh *VideoHandler) GetAllRecordings(c *gin.Context) {
 		}
 
 		// Crear estructura del cliente si no existe
-		if clientRecordings[clientPCID] == nil {
-			// Solo buscar info del cliente una vez
-			var clientName string
-			if !processedClients[clientPCID] {
-				// Tomar solo los primeros 8 caracteres del UUID para mostrar
-				shortID := clientPCID
-				if len(clientPCID) > 8 {
-					shortID = clientPCID[:8] + "..."
-				}
-				clientName = "Cliente " + shortID
-				processedClients[clientPCID] = true
+		index, exists := clientIndex[clientPCID]
+		if !exists {
+			// Tomar solo los primeros 8 caracteres del UUID para mostrar
+			shortID := clientPCID
+			if len(clientPCID) > 8 {
+				shortID = clientPCID[:8] + "..."
 			}
-
-			clientRecordings[clientPCID] = gin.H{
-				"client_pc_id": clientPCID,
-				"client_name":  clientName,
-				"recordings":   []gin.H{},
+			clientName := "Cliente " + shortID
+			if identifier, ok := pcNames[clientPCID]; ok {
+				clientName = identifier
 			}
+
+			clients = append(clients, dto.ClientRecordingsDTO{
+				ClientPCID: clientPCID,
+				ClientName: clientName,
+				Recordings: []dto.RecordingDTO{},
+			})
+			index = len(clients) - 1
+			clientIndex[clientPCID] = index
 		}
 
 		// Procesar cada video de la sesión
 		for _, video := range videos {
-			// Calcular total de frames
-			framesDir := video.FilePath()
-			totalFrames, frameErr := vh.countFramesInDirectory(framesDir)
-			if frameErr != nil {
-				totalFrames = 0
-			}
-
-			// Calcular FPS
-			var fps float64
-			if video.DurationSeconds() > 0 {
-				fps = float64(totalFrames) / float64(video.DurationSeconds())
-			}
-
-			recording := gin.H{
-				"video_id":         video.VideoID(),
-				"session_id":       session.SessionID(),
-				"recorded_at":      video.RecordedAt(),
-				"duration_seconds": video.DurationSeconds(),
-				"total_frames":     totalFrames,
-				"fps":              fps,
-				"file_size_mb":     video.FileSizeMB(),
-				"session_status":   session.Status(),
-			}
-
-			// Agregar a la lista del cliente
-			clientData := clientRecordings[clientPCID].(gin.H)
-			recordings := clientData["recordings"].([]gin.H)
-			clientData["recordings"] = append(recordings, recording)
+			clients[index].Recordings = append(clients[index].Recordings, vh.toRecordingDTO(video, session))
 		}
+		clients[index].Count = len(clients[index].Recordings)
 	}
 
-	// Convertir mapa a slice para response JSON ordenado
-	var result []interface{}
-	for _, clientData := range clientRecordings {
-		result = append(result, clientData)
-	}
+	// Paginar por cliente para no partir las grabaciones de un mismo PC
+	page := response.ParsePageRequest(c)
+	pageClients := response.Paginate(clients, page)
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    result,
-	})
+	response.SuccessPage(c, http.StatusOK, dto.AllRecordingsResponse{
+		Clients: pageClients,
+		Count:   len(pageClients),
+	}, page.Meta(len(clients)))
 }
 
 // GetClientRecordings obtiene las grabaciones de un cliente específico
-// GET /api/admin/clients/{clientId}/recordings
+// GET /api/v1/admin/clients/{clientId}/recordings
 func (vh *VideoHandler) GetClientRecordings(c *gin.Context) {
 	clientPCID := c.Param("clientId")
 	if clientPCID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Client ID requerido",
-		})
+		response.Error(c, http.StatusBadRequest, "INVALID_CLIENT_ID", "Client ID requerido")
 		return
 	}
 
 	// Obtener sesiones de este cliente que tienen videos
 	sessions, err := vh.sessionService.GetSessionsByClientPCID(c.Request.Context(), clientPCID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Error obteniendo sesiones del cliente",
-		})
+		response.Error(c, http.StatusInternalServerError, "SESSIONS_RETRIEVAL_FAILED", "Error obteniendo sesiones del cliente")
 		return
 	}
 
-	var recordings []gin.H
+	type sessionRecording struct {
+		video   *sessionvideo.SessionVideo
+		session *remotesession.RemoteSession
+	}
+	entries := make([]sessionRecording, 0)
 
 	for _, session := range sessions {
 		// Obtener videos de esta sesión
@@ -305,38 +327,46 @@ func (vh *VideoHandler) GetClientRecordings(c *gin.Context) {
 		}
 
 		for _, video := range videos {
-			// Calcular total de frames
-			framesDir := video.FilePath()
-			totalFrames, frameErr := vh.countFramesInDirectory(framesDir)
-			if frameErr != nil {
-				totalFrames = 0
-			}
+			entries = append(entries, sessionRecording{video: video, session: session})
+		}
+	}
 
-			// Calcular FPS
-			var fps float64
-			if video.DurationSeconds() > 0 {
-				fps = float64(totalFrames) / float64(video.DurationSeconds())
-			}
+	// Contar frames solo de las grabaciones de la página solicitada
+	page := response.ParsePageRequest(c)
+	pageEntries := response.Paginate(entries, page)
 
-			recording := gin.H{
-				"video_id":         video.VideoID(),
-				"session_id":       session.SessionID(),
-				"recorded_at":      video.RecordedAt(),
-				"duration_seconds": video.DurationSeconds(),
-				"total_frames":     totalFrames,
-				"fps":              fps,
-				"file_size_mb":     video.FileSizeMB(),
-				"session_status":   session.Status(),
-			}
+	recordings := make([]dto.RecordingDTO, 0, len(pageEntries))
+	for _, entry := range pageEntries {
+		recordings = append(recordings, vh.toRecordingDTO(entry.video, entry.session))
+	}
 
-			recordings = append(recordings, recording)
-		}
+	response.SuccessPage(c, http.StatusOK, dto.ClientRecordingsDTO{
+		ClientPCID: clientPCID,
+		Recordings: recordings,
+		Count:      len(recordings),
+	}, page.Meta(len(entries)))
+}
+
+// toRecordingDTO convierte un video de sesión al DTO de grabación calculando frames y FPS
+func (vh *VideoHandler) toRecordingDTO(video *sessionvideo.SessionVideo, session *remotesession.RemoteSession) dto.RecordingDTO {
+	// Calcular total de frames
+	totalFrames, err := vh.countFramesInDirectory(video.FilePath())
+	if err != nil {
+		totalFrames = 0
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success":      true,
-		"client_pc_id": clientPCID,
-		"recordings":   recordings,
-		"total_count":  len(recordings),
-	})
+	fps, fpsSource, variableRate := recordingFPS(vh.videoService, video, totalFrames)
+
+	return dto.RecordingDTO{
+		VideoID:           video.VideoID(),
+		SessionID:         session.SessionID(),
+		RecordedAt:        video.RecordedAt(),
+		DurationSeconds:   video.DurationSeconds(),
+		TotalFrames:       totalFrames,
+		FPS:               fps,
+		FPSSource:         fpsSource,
+		VariableFrameRate: variableRate,
+		FileSizeMB:        video.FileSizeMB(),
+		SessionStatus:     string(session.Status()),
+	}
 }
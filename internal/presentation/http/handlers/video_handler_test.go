@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/actionlogservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/interfaces"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/remotesessionservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/videoservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/actionlog"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/remotesession"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/sessionvideo"
+)
+
+// MockVideoService es un mock del servicio de video
+type MockVideoService struct {
+	mock.Mock
+}
+
+func (m *MockVideoService) HandleUploadedVideoChunk(chunk videoservice.VideoChunk) (*videoservice.VideoUploadResult, error) {
+	args := m.Called(chunk)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*videoservice.VideoUploadResult), args.Error(1)
+}
+
+func (m *MockVideoService) CompleteVideoUpload(videoID, pcID, userID string) (*videoservice.VideoUploadResult, error) {
+	args := m.Called(videoID, pcID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*videoservice.VideoUploadResult), args.Error(1)
+}
+
+func (m *MockVideoService) GetVideoUploadStatus(videoID, pcID string) (*videoservice.VideoUploadStatus, error) {
+	args := m.Called(videoID, pcID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*videoservice.VideoUploadStatus), args.Error(1)
+}
+
+func (m *MockVideoService) WriteUploadPart(ctx context.Context, part videoservice.UploadPart) (*videoservice.PartUploadProgress, error) {
+	args := m.Called(ctx, part)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*videoservice.PartUploadProgress), args.Error(1)
+}
+
+func (m *MockVideoService) GetPartUploadProgress(videoID string) (*videoservice.PartUploadProgress, error) {
+	args := m.Called(videoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*videoservice.PartUploadProgress), args.Error(1)
+}
+
+func (m *MockVideoService) FinalizeVideoUpload(ctx context.Context, sessionID, videoID, tempFilePath string, fileSizeMB float64, duration int) (*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, sessionID, videoID, tempFilePath, fileSizeMB, duration)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sessionvideo.SessionVideo), args.Error(1)
+}
+
+func (m *MockVideoService) GetVideosBySessionID(ctx context.Context, sessionID string) ([]*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*sessionvideo.SessionVideo), args.Error(1)
+}
+
+func (m *MockVideoService) GetVideoByID(ctx context.Context, videoID string) (*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, videoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sessionvideo.SessionVideo), args.Error(1)
+}
+
+func (m *MockVideoService) DeleteVideo(ctx context.Context, videoID string) error {
+	return m.Called(ctx, videoID).Error(0)
+}
+
+func (m *MockVideoService) GetAllVideos(ctx context.Context, limit, offset int) ([]*sessionvideo.SessionVideo, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*sessionvideo.SessionVideo), args.Error(1)
+}
+
+func (m *MockVideoService) SaveVideoFrame(frameInfo videoservice.VideoFrameInfo) error {
+	return m.Called(frameInfo).Error(0)
+}
+
+func (m *MockVideoService) FinalizeVideoRecording(recordingInfo videoservice.VideoRecordingMetadata) error {
+	return m.Called(recordingInfo).Error(0)
+}
+
+func (m *MockVideoService) GetVideoFrame(framesDir string, frameNumber int) ([]byte, error) {
+	args := m.Called(framesDir, frameNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockVideoService) CountVideoFrames(framesDir string) (int, error) {
+	args := m.Called(framesDir)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockVideoService) GetFrameCadence(framesDir string) (*videoservice.FrameCadence, error) {
+	args := m.Called(framesDir)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*videoservice.FrameCadence), args.Error(1)
+}
+
+func (m *MockVideoService) GetRecordingStorageUsage(ctx context.Context, video *sessionvideo.SessionVideo) (*videoservice.RecordingStorageUsage, error) {
+	args := m.Called(ctx, video)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*videoservice.RecordingStorageUsage), args.Error(1)
+}
+
+func (m *MockVideoService) ReleaseSessionRecordings(sessionID string) int {
+	return m.Called(sessionID).Int(0)
+}
+
+func (m *MockVideoService) ApplyPartialRecordingPolicy(ctx context.Context, sessionID, adminUserID string, endStatus remotesession.SessionStatus) ([]videoservice.PartialRecordingDisposition, error) {
+	args := m.Called(ctx, sessionID, adminUserID, endStatus)
+	return args.Get(0).([]videoservice.PartialRecordingDisposition), args.Error(1)
+}
+
+func (m *MockVideoService) IsSessionRecording(sessionID string) bool {
+	return m.Called(sessionID).Bool(0)
+}
+
+func (m *MockVideoService) ListActiveRecordings() []videoservice.ActiveRecording {
+	return m.Called().Get(0).([]videoservice.ActiveRecording)
+}
+
+func newTestVideoHandler() (*VideoHandler, *MockVideoService, *MockRemoteSessionRepository) {
+	sessionRepo := new(MockRemoteSessionRepository)
+	videoService := new(MockVideoService)
+	sessionService := remotesessionservice.NewRemoteSessionService(sessionRepo, nil, nil, nil, nil)
+	return NewVideoHandler(sessionService, videoService, nil), videoService, sessionRepo
+}
+
+func TestVideoHandler_GetRecordingMetadata_ReturnsEnvelopeWithRecording(t *testing.T) {
+	// Arrange
+	handler, videoService, _ := newTestVideoHandler()
+	video := sessionvideo.NewSessionVideo("storage/session_videos/video-1/frames", 10, "session-1", 2.5)
+	videoService.On("GetVideosBySessionID", mock.Anything, "session-1").Return([]*sessionvideo.SessionVideo{video}, nil)
+	videoService.On("CountVideoFrames", video.FilePath()).Return(100, nil)
+	videoService.On("GetFrameCadence", video.FilePath()).Return(nil, videoservice.ErrFrameTimestampsNotFound)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/sessions/:sessionId/recording/metadata", handler.GetRecordingMetadata)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sessions/session-1/recording/metadata", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, video.VideoID(), data["video_id"])
+	assert.Equal(t, float64(100), data["total_frames"])
+	assert.Equal(t, float64(10), data["fps"])
+	assert.Equal(t, "duration", data["fps_source"])
+}
+
+func TestVideoHandler_GetRecordingMetadata_UsesMeasuredFrameCadence(t *testing.T) {
+	// Arrange - 100 frames en 10 s, pero con una pausa: la cadencia real era de 25 FPS
+	handler, videoService, _ := newTestVideoHandler()
+	video := sessionvideo.NewSessionVideo("storage/session_videos/video-1/frames", 10, "session-1", 2.5)
+	videoService.On("GetVideosBySessionID", mock.Anything, "session-1").Return([]*sessionvideo.SessionVideo{video}, nil)
+	videoService.On("CountVideoFrames", video.FilePath()).Return(100, nil)
+	videoService.On("GetFrameCadence", video.FilePath()).Return(&videoservice.FrameCadence{FPS: 25, VariableRate: true}, nil)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/sessions/:sessionId/recording/metadata", handler.GetRecordingMetadata)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sessions/session-1/recording/metadata", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, float64(25), data["fps"])
+	assert.Equal(t, "frame_timestamps", data["fps_source"])
+	assert.Equal(t, true, data["variable_frame_rate"])
+}
+
+func TestVideoHandler_GetRecordingMetadata_NoRecordingReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	handler, videoService, _ := newTestVideoHandler()
+	videoService.On("GetVideosBySessionID", mock.Anything, "session-1").Return([]*sessionvideo.SessionVideo{}, nil)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/sessions/:sessionId/recording/metadata", handler.GetRecordingMetadata)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sessions/session-1/recording/metadata", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusNotFound, "RECORDING_NOT_FOUND")
+}
+
+func TestVideoHandler_GetVideoFrame_MissingFrameReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	handler, videoService, _ := newTestVideoHandler()
+	video := sessionvideo.NewSessionVideo("storage/session_videos/video-1/frames", 10, "session-1", 2.5)
+	videoService.On("GetVideosBySessionID", mock.Anything, "session-1").Return([]*sessionvideo.SessionVideo{video}, nil)
+	videoService.On("GetVideoFrame", video.FilePath(), 999).Return(nil, videoservice.ErrFrameNotFound)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/sessions/:sessionId/frames/:frameNumber", handler.GetVideoFrame)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sessions/session-1/frames/999", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusNotFound, "FRAME_NOT_FOUND")
+}
+
+func TestVideoHandler_GetVideoFrame_ServesJPEG(t *testing.T) {
+	// Arrange
+	handler, videoService, _ := newTestVideoHandler()
+	video := sessionvideo.NewSessionVideo("storage/session_videos/video-1/frames", 10, "session-1", 2.5)
+	jpeg := []byte{0xFF, 0xD8, 0xFF, 0xD9}
+	videoService.On("GetVideosBySessionID", mock.Anything, "session-1").Return([]*sessionvideo.SessionVideo{video}, nil)
+	videoService.On("GetVideoFrame", video.FilePath(), 1).Return(jpeg, nil)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/sessions/:sessionId/frames/:frameNumber", handler.GetVideoFrame)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sessions/session-1/frames/1", nil))
+
+	// Assert - el frame es binario, solo los errores usan el envelope JSON
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "image/jpeg", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, jpeg, recorder.Body.Bytes())
+}
+
+func TestVideoHandler_GetRecordingMetadata_LogsRecordingViewed(t *testing.T) {
+	// Arrange
+	handler, videoService, _ := newTestVideoHandler()
+	video := sessionvideo.NewSessionVideo("storage/session_videos/video-1/frames", 10, "session-1", 2.5)
+	videoService.On("GetVideosBySessionID", mock.Anything, "session-1").Return([]*sessionvideo.SessionVideo{video}, nil)
+	videoService.On("CountVideoFrames", video.FilePath()).Return(100, nil)
+	videoService.On("GetFrameCadence", video.FilePath()).Return(nil, videoservice.ErrFrameTimestampsNotFound)
+
+	actionLogService := new(MockActionLogService)
+	actionLogService.On("LogAction", mock.Anything, actionlog.ActionRecordingViewed, mock.Anything, testAdminUserID,
+		mock.MatchedBy(func(entityID *string) bool { return *entityID == video.VideoID() }), mock.Anything,
+		mock.MatchedBy(func(details map[string]interface{}) bool {
+			return details["session_id"] == "session-1" && details["accessed_via"] == "metadata" && details["accessed_at"] != nil
+		})).Return(nil).Once()
+	handler.SetAccessAuditor(actionlogservice.NewAccessAuditor(actionLogService, time.Minute))
+
+	router := newTestAdminRouter()
+	router.GET("/api/v1/admin/sessions/:sessionId/recording/metadata", handler.GetRecordingMetadata)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sessions/session-1/recording/metadata", nil))
+
+	// Assert
+	assertSuccessEnvelope(t, recorder, http.StatusOK)
+	actionLogService.AssertExpectations(t)
+}
+
+func TestVideoHandler_GetVideoFrame_AggregatesFrameViewsIntoOneAuditEntry(t *testing.T) {
+	// Arrange
+	handler, videoService, _ := newTestVideoHandler()
+	video := sessionvideo.NewSessionVideo("storage/session_videos/video-1/frames", 10, "session-1", 2.5)
+	videoService.On("GetVideosBySessionID", mock.Anything, "session-1").Return([]*sessionvideo.SessionVideo{video}, nil)
+	videoService.On("GetVideoFrame", video.FilePath(), mock.Anything).Return([]byte{0xFF, 0xD8, 0xFF, 0xD9}, nil)
+
+	actionLogService := new(MockActionLogService)
+	actionLogService.On("LogAction", mock.Anything, actionlog.ActionRecordingViewed, mock.Anything, testAdminUserID,
+		mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	handler.SetAccessAuditor(actionlogservice.NewAccessAuditor(actionLogService, time.Minute))
+
+	router := newTestAdminRouter()
+	router.GET("/api/v1/admin/sessions/:sessionId/frames/:frameNumber", handler.GetVideoFrame)
+
+	// Act - el reproductor pide los frames uno a uno
+	for frame := 1; frame <= 30; frame++ {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/admin/sessions/session-1/frames/%d", frame), nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+	}
+
+	// Assert
+	actionLogService.AssertNumberOfCalls(t, "LogAction", 1)
+}
+
+func TestVideoHandler_GetClientRecordings_ReturnsEnvelopeWithRecordings(t *testing.T) {
+	// Arrange
+	handler, videoService, sessionRepo := newTestVideoHandler()
+	session, _ := remotesession.NewRemoteSession(testAdminUserID, testClientPCID)
+	video := sessionvideo.NewSessionVideo("storage/session_videos/video-1/frames", 0, session.SessionID(), 2.5)
+	sessionRepo.On("FindByClientPCID", mock.Anything, testClientPCID).Return([]*remotesession.RemoteSession{session}, nil)
+	videoService.On("GetVideosBySessionID", mock.Anything, session.SessionID()).Return([]*sessionvideo.SessionVideo{video}, nil)
+	videoService.On("CountVideoFrames", video.FilePath()).Return(0, errors.New("directory missing"))
+	videoService.On("GetFrameCadence", video.FilePath()).Return(nil, videoservice.ErrFrameTimestampsNotFound)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/clients/:clientId/recordings", handler.GetClientRecordings)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/clients/"+testClientPCID+"/recordings", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, testClientPCID, data["client_pc_id"])
+	assert.Equal(t, float64(1), data["count"])
+	assert.Len(t, data["recordings"], 1)
+}
+
+// recordingLookupVideoService usa el servicio de video real sobre el disco y resuelve GetVideoByID en memoria
+type recordingLookupVideoService struct {
+	videoservice.IVideoService
+	videos map[string]*sessionvideo.SessionVideo
+}
+
+func (s recordingLookupVideoService) GetVideoByID(ctx context.Context, videoID string) (*sessionvideo.SessionVideo, error) {
+	video, ok := s.videos[videoID]
+	if !ok {
+		return nil, errors.New("video no encontrado: " + videoID)
+	}
+	return video, nil
+}
+
+// processedVideoStorage simula el almacenamiento con los MP4 procesados indicados
+type processedVideoStorage struct {
+	interfaces.IFileStorage
+	existing map[string]bool
+}
+
+func (s processedVideoStorage) FileExists(ctx context.Context, filePath string) bool {
+	return s.existing[filePath]
+}
+
+func TestVideoHandler_GetRecordingStorage_ReportsFramesDirectoryUsage(t *testing.T) {
+	// Arrange
+	framesDir := filepath.Join(t.TempDir(), "video-1", "frames")
+	require.NoError(t, os.MkdirAll(framesDir, 0755))
+	for i, size := range []int{100, 200, 300} {
+		frameFile := filepath.Join(framesDir, fmt.Sprintf("frame_%06d.jpg", i))
+		require.NoError(t, os.WriteFile(frameFile, make([]byte, size), 0644))
+	}
+
+	video := sessionvideo.NewSessionVideoFromDB("video-1", framesDir, 3, time.Now(), "session-1", 0, time.Now(), time.Now())
+	fileStorage := processedVideoStorage{existing: map[string]bool{
+		filepath.Join("videos", "processed", "session-1_video-1.mp4"): true,
+	}}
+	videoService := recordingLookupVideoService{
+		IVideoService: videoservice.NewVideoService(nil, nil, fileStorage, nil, videoservice.FrameStorageIndividual, 0, videoservice.DefaultPartialRecordingPolicy),
+		videos:        map[string]*sessionvideo.SessionVideo{"video-1": video},
+	}
+	handler := NewVideoHandler(nil, videoService, nil)
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/recordings/:videoId/storage", handler.GetRecordingStorage)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/recordings/video-1/storage", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, "video-1", data["video_id"])
+	assert.Equal(t, "session-1", data["session_id"])
+	assert.Equal(t, float64(3), data["frame_count"])
+	assert.Equal(t, float64(600), data["total_bytes"])
+	assert.Equal(t, string(videoservice.FrameStorageIndividual), data["storage_format"])
+	assert.Equal(t, true, data["mp4_exported"])
+}
+
+func TestVideoHandler_GetRecordingStorage_UnknownVideoReturnsErrorEnvelope(t *testing.T) {
+	// Arrange
+	handler, videoService, _ := newTestVideoHandler()
+	videoService.On("GetVideoByID", mock.Anything, "missing").Return(nil, errors.New("video no encontrado: missing"))
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/recordings/:videoId/storage", handler.GetRecordingStorage)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/recordings/missing/storage", nil))
+
+	// Assert
+	assertErrorEnvelope(t, recorder, http.StatusNotFound, "RECORDING_NOT_FOUND")
+}
+
+func TestVideoHandler_GetActiveRecordings_ListsRecordingsInProgress(t *testing.T) {
+	// Arrange
+	handler, videoService, _ := newTestVideoHandler()
+	startedAt := time.Now().Add(-time.Minute)
+	videoService.On("ListActiveRecordings").Return([]videoservice.ActiveRecording{
+		{VideoID: "video-1", SessionID: "session-1", StartedAt: startedAt, Frames: 42},
+	})
+
+	router := newTestRouter()
+	router.GET("/api/v1/admin/recordings/active", handler.GetActiveRecordings)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/recordings/active", nil))
+
+	// Assert
+	data := assertSuccessEnvelope(t, recorder, http.StatusOK)
+	assert.Equal(t, float64(1), data["count"])
+	recording := data["recordings"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "video-1", recording["video_id"])
+	assert.Equal(t, "session-1", recording["session_id"])
+	assert.Equal(t, float64(42), recording["frames"])
+}
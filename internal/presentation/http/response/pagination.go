@@ -0,0 +1,68 @@
+package response
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+const (
+	// DefaultPageLimit tamaño de página cuando no se indica ?limit
+	DefaultPageLimit = 50
+	// MaxPageLimit tamaño máximo de página aceptado
+	MaxPageLimit = 200
+)
+
+// PageRequest parámetros de paginación de una petición (?limit=&offset=)
+type PageRequest struct {
+	Limit  int
+	Offset int
+}
+
+// ParsePageRequest lee limit y offset de la query; valores ausentes o inválidos usan los defaults
+// y limit se acota a MaxPageLimit
+func ParsePageRequest(c *gin.Context) PageRequest {
+	return ParsePageRequestWithDefault(c, DefaultPageLimit)
+}
+
+// ParsePageRequestWithDefault igual que ParsePageRequest pero con el tamaño de página por defecto del endpoint
+func ParsePageRequestWithDefault(c *gin.Context, defaultLimit int) PageRequest {
+	page := PageRequest{Limit: defaultLimit}
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+		page.Limit = limit
+	}
+	if page.Limit > MaxPageLimit {
+		page.Limit = MaxPageLimit
+	}
+
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil && offset > 0 {
+		page.Offset = offset
+	}
+
+	return page
+}
+
+// Meta construye los metadatos de la página a partir del total de elementos
+func (p PageRequest) Meta(total int) dto.PageMeta {
+	return dto.PageMeta{
+		Limit:   p.Limit,
+		Offset:  p.Offset,
+		Total:   total,
+		HasMore: p.Offset+p.Limit < total,
+	}
+}
+
+// Paginate devuelve la porción de items que corresponde a la página, para listas ya cargadas en memoria
+func Paginate[T any](items []T, page PageRequest) []T {
+	if page.Offset >= len(items) {
+		return items[:0]
+	}
+
+	end := page.Offset + page.Limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[page.Offset:end]
+}
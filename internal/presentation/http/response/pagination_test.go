@@ -0,0 +1,83 @@
+package response
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+func TestParsePageRequest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		expected PageRequest
+	}{
+		{"defaults", "", PageRequest{Limit: DefaultPageLimit, Offset: 0}},
+		{"explicit values", "?limit=10&offset=20", PageRequest{Limit: 10, Offset: 20}},
+		{"limit capped", "?limit=5000", PageRequest{Limit: MaxPageLimit, Offset: 0}},
+		{"invalid values fall back", "?limit=abc&offset=-3", PageRequest{Limit: DefaultPageLimit, Offset: 0}},
+		{"zero limit falls back", "?limit=0", PageRequest{Limit: DefaultPageLimit, Offset: 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			c, _ := newTestContext()
+			c.Request = httptest.NewRequest("GET", "/items"+tc.query, nil)
+
+			// Act
+			page := ParsePageRequest(c)
+
+			// Assert
+			assert.Equal(t, tc.expected, page)
+		})
+	}
+}
+
+func TestParsePageRequestWithDefault(t *testing.T) {
+	// Arrange
+	c, _ := newTestContext()
+	c.Request = httptest.NewRequest("GET", "/items", nil)
+	capped, _ := newTestContext()
+	capped.Request = httptest.NewRequest("GET", "/items?limit=5000&offset=5", nil)
+
+	// Act
+	page := ParsePageRequestWithDefault(c, 20)
+	cappedPage := ParsePageRequestWithDefault(capped, 20)
+
+	// Assert
+	assert.Equal(t, PageRequest{Limit: 20, Offset: 0}, page)
+	assert.Equal(t, PageRequest{Limit: MaxPageLimit, Offset: 5}, cappedPage)
+}
+
+func TestPageRequest_Meta(t *testing.T) {
+	testCases := []struct {
+		name     string
+		page     PageRequest
+		total    int
+		expected dto.PageMeta
+	}{
+		{"first page", PageRequest{Limit: 10, Offset: 0}, 25, dto.PageMeta{Limit: 10, Offset: 0, Total: 25, HasMore: true}},
+		{"middle page", PageRequest{Limit: 10, Offset: 10}, 25, dto.PageMeta{Limit: 10, Offset: 10, Total: 25, HasMore: true}},
+		{"last page", PageRequest{Limit: 10, Offset: 20}, 25, dto.PageMeta{Limit: 10, Offset: 20, Total: 25, HasMore: false}},
+		{"exact last page", PageRequest{Limit: 10, Offset: 10}, 20, dto.PageMeta{Limit: 10, Offset: 10, Total: 20, HasMore: false}},
+		{"empty", PageRequest{Limit: 10, Offset: 0}, 0, dto.PageMeta{Limit: 10, Offset: 0, Total: 0, HasMore: false}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.page.Meta(tc.total))
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	assert.Equal(t, []int{1, 2}, Paginate(items, PageRequest{Limit: 2, Offset: 0}))
+	assert.Equal(t, []int{3, 4}, Paginate(items, PageRequest{Limit: 2, Offset: 2}))
+	assert.Equal(t, []int{5}, Paginate(items, PageRequest{Limit: 2, Offset: 4}))
+	assert.Empty(t, Paginate(items, PageRequest{Limit: 2, Offset: 10}))
+}
@@ -0,0 +1,40 @@
+package response
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// Success responde con el envelope de éxito y los datos indicados
+func Success(c *gin.Context, status int, data interface{}) {
+	c.JSON(status, dto.APIResponse{
+		Success: true,
+		Data:    data,
+	})
+}
+
+// SuccessPage responde con el envelope de éxito, los datos de la página y sus metadatos de paginación
+func SuccessPage(c *gin.Context, status int, data interface{}, meta dto.PageMeta) {
+	c.JSON(status, dto.APIResponse{
+		Success: true,
+		Data:    data,
+		Meta:    &meta,
+	})
+}
+
+// Error responde con el envelope de error
+func Error(c *gin.Context, status int, code, message string) {
+	c.JSON(status, dto.APIResponse{
+		Success: false,
+		Error: &dto.APIError{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
+
+// AbortWithError responde con el envelope de error y detiene la cadena de handlers (middlewares)
+func AbortWithError(c *gin.Context, status int, code, message string) {
+	Error(c, status, code, message)
+	c.Abort()
+}
@@ -0,0 +1,67 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestContext() (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	return c, recorder
+}
+
+func TestSuccess_WritesEnvelopeWithData(t *testing.T) {
+	// Arrange
+	c, recorder := newTestContext()
+
+	// Act
+	Success(c, http.StatusCreated, map[string]string{"id": "123"})
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, true, body["success"])
+	assert.Equal(t, map[string]interface{}{"id": "123"}, body["data"])
+	assert.NotContains(t, body, "error")
+}
+
+func TestError_WritesEnvelopeWithCodeAndMessage(t *testing.T) {
+	// Arrange
+	c, recorder := newTestContext()
+
+	// Act
+	Error(c, http.StatusNotFound, "SESSION_NOT_FOUND", "Session not found")
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, false, body["success"])
+	assert.Equal(t, map[string]interface{}{"code": "SESSION_NOT_FOUND", "message": "Session not found"}, body["error"])
+	assert.NotContains(t, body, "data")
+}
+
+func TestAbortWithError_StopsHandlerChain(t *testing.T) {
+	// Arrange
+	c, recorder := newTestContext()
+
+	// Act
+	AbortWithError(c, http.StatusUnauthorized, "MISSING_TOKEN", "Authorization token required")
+
+	// Assert
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
@@ -6,7 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
-	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
 )
 
 // Constantes para claves del contexto
@@ -21,24 +21,14 @@ func AuthMiddleware(authService *userservice.AuthService) gin.HandlerFunc {
 		// Obtener el token del header Authorization
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, dto.ErrorResponseDTO{
-				Error:   "MISSING_TOKEN",
-				Message: "Authorization token required",
-				Code:    http.StatusUnauthorized,
-			})
-			c.Abort()
+			response.AbortWithError(c, http.StatusUnauthorized, "MISSING_TOKEN", "Authorization token required")
 			return
 		}
 
 		// Verificar formato Bearer token
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, dto.ErrorResponseDTO{
-				Error:   "INVALID_TOKEN_FORMAT",
-				Message: "Invalid authorization token format",
-				Code:    http.StatusUnauthorized,
-			})
-			c.Abort()
+			response.AbortWithError(c, http.StatusUnauthorized, "INVALID_TOKEN_FORMAT", "Invalid authorization token format")
 			return
 		}
 
@@ -47,12 +37,7 @@ func AuthMiddleware(authService *userservice.AuthService) gin.HandlerFunc {
 		// Validar el token
 		claims, err := authService.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, dto.ErrorResponseDTO{
-				Error:   "INVALID_TOKEN",
-				Message: "Invalid or expired token",
-				Code:    http.StatusUnauthorized,
-			})
-			c.Abort()
+			response.AbortWithError(c, http.StatusUnauthorized, "INVALID_TOKEN", "Invalid or expired token")
 			return
 		}
 
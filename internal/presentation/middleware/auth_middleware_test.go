@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+func performAuthenticatedRequest(authorization string) (*httptest.ResponseRecorder, bool) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AuthMiddleware(userservice.NewAuthService(nil, "test-secret")))
+
+	handlerCalled := false
+	router.GET("/api/v1/admin/pcs", func(c *gin.Context) {
+		handlerCalled = true
+	})
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/admin/pcs", nil)
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+	router.ServeHTTP(recorder, request)
+	return recorder, handlerCalled
+}
+
+func TestAuthMiddleware_RejectsRequestsWithErrorEnvelope(t *testing.T) {
+	cases := []struct {
+		name          string
+		authorization string
+		expectedCode  string
+	}{
+		{"missing token", "", "MISSING_TOKEN"},
+		{"invalid format", "Token abc", "INVALID_TOKEN_FORMAT"},
+		{"invalid token", "Bearer not-a-jwt", "INVALID_TOKEN"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			recorder, handlerCalled := performAuthenticatedRequest(tc.authorization)
+
+			// Assert
+			assert.False(t, handlerCalled)
+			assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+			var envelope dto.APIResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+			assert.False(t, envelope.Success)
+			assert.Nil(t, envelope.Data)
+			require.NotNil(t, envelope.Error)
+			assert.Equal(t, tc.expectedCode, envelope.Error.Code)
+		})
+	}
+}
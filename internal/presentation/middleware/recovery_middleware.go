@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// Recovery recupera el panic de un handler HTTP, lo registra con el método, la ruta y el stack y responde
+// 500 INTERNAL_ERROR con el sobre de error habitual en lugar del 500 vacío de gin.Recovery
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// http.ErrAbortHandler es la forma estándar de abortar una respuesta: se respeta
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			log.Printf("💥 PANIC RECOVERED: HTTP %s %s: %v\n%s", c.Request.Method, c.Request.URL.Path, recovered, debug.Stack())
+			// Una conexión ya secuestrada (WebSocket) o con la respuesta empezada no admite otra respuesta
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			response.AbortWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+		}()
+
+		c.Next()
+	}
+}
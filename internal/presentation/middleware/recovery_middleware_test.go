@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+func TestRecovery_PanicRespondsWithErrorEnvelopeAndKeepsServing(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Recovery())
+	router.GET("/panic", func(c *gin.Context) { panic("boom") })
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	// Act
+	panicked := httptest.NewRecorder()
+	router.ServeHTTP(panicked, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	next := httptest.NewRecorder()
+	router.ServeHTTP(next, httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, panicked.Code)
+	var envelope dto.APIResponse
+	require.NoError(t, json.Unmarshal(panicked.Body.Bytes(), &envelope))
+	assert.False(t, envelope.Success)
+	require.NotNil(t, envelope.Error)
+	assert.Equal(t, "INTERNAL_ERROR", envelope.Error.Code)
+	assert.Equal(t, http.StatusNoContent, next.Code)
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// Límites por defecto de las peticiones HTTP
+const (
+	// DefaultMaxRequestBodyBytes tamaño máximo del body para endpoints sin límite propio
+	DefaultMaxRequestBodyBytes int64 = 1 << 20 // 1 MB
+	// DefaultRequestTimeout tiempo máximo de ejecución de un handler antes de responder 503
+	DefaultRequestTimeout = 30 * time.Second
+)
+
+// MaxBodySize limita el body de cada petición con http.MaxBytesReader. routeLimits permite fijar un límite
+// distinto por ruta de gin (p. ej. "/api/v1/admin/sessions/:sessionId/files/send"); un límite <= 0 no limita.
+// Las peticiones que declaran un Content-Length mayor se rechazan con 413 sin leer el body.
+func MaxBodySize(defaultLimit int64, routeLimits map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := defaultLimit
+		if routeLimit, exists := routeLimits[c.FullPath()]; exists {
+			limit = routeLimit
+		}
+
+		if limit <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			response.AbortWithError(c, http.StatusRequestEntityTooLarge, "REQUEST_TOO_LARGE",
+				fmt.Sprintf("Request body exceeds the %d bytes limit", limit))
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// RequestTimeout cancela el contexto de la petición y responde 503 con el envelope de error cuando el
+// handler supera timeout. Se aplica sobre el http.Handler completo porque la respuesta del handler se
+// bufferiza hasta que termina: las rutas que coinciden con exemptPatterns (sintaxis de path.Match, p. ej.
+// "/ws/*") no se limitan, ya que WebSockets y descargas necesitan Hijack/Flush o más tiempo.
+func RequestTimeout(next http.Handler, timeout time.Duration, exemptPatterns ...string) http.Handler {
+	if timeout <= 0 {
+		return next
+	}
+
+	body, _ := json.Marshal(dto.APIResponse{
+		Success: false,
+		Error: &dto.APIError{
+			Code:    "REQUEST_TIMEOUT",
+			Message: fmt.Sprintf("Request exceeded the %s time limit", timeout),
+		},
+	})
+	limited := http.TimeoutHandler(next, timeout, string(body))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, pattern := range exemptPatterns {
+			if matched, _ := path.Match(pattern, r.URL.Path); matched {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		// Si el handler termina a tiempo sus cabeceras reemplazan a esta; si no, la respuesta 503 es JSON
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		limited.ServeHTTP(w, r)
+	})
+}
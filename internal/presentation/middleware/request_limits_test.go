@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+const testUploadRoute = "/api/v1/admin/sessions/:sessionId/files/send"
+
+// newBodyLimitRouter crea un router con límite de 16 bytes y 64 en la ruta de subida; los handlers leen el body completo
+func newBodyLimitRouter(readErr *error) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(MaxBodySize(16, map[string]int64{testUploadRoute: 64}))
+
+	readBody := func(c *gin.Context) {
+		_, *readErr = io.ReadAll(c.Request.Body)
+		c.Status(http.StatusOK)
+	}
+	router.POST("/api/v1/admin/reconcile", readBody)
+	router.POST(testUploadRoute, readBody)
+	return router
+}
+
+// assertErrorCode verifica el envelope de error con el código esperado
+func assertErrorCode(t *testing.T, recorder *httptest.ResponseRecorder, expectedStatus int, expectedCode string) {
+	t.Helper()
+	assert.Equal(t, expectedStatus, recorder.Code)
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	var envelope dto.APIResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+	assert.False(t, envelope.Success)
+	require.NotNil(t, envelope.Error)
+	assert.Equal(t, expectedCode, envelope.Error.Code)
+}
+
+func TestMaxBodySize_RejectsDeclaredOverLimitBody(t *testing.T) {
+	// Arrange
+	var readErr error
+	router := newBodyLimitRouter(&readErr)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/reconcile", strings.NewReader(strings.Repeat("x", 17))))
+
+	// Assert
+	assertErrorCode(t, recorder, http.StatusRequestEntityTooLarge, "REQUEST_TOO_LARGE")
+}
+
+func TestMaxBodySize_StopsReadingUndeclaredOverLimitBody(t *testing.T) {
+	// Arrange - sin Content-Length (chunked) el límite se aplica al leer
+	var readErr error
+	router := newBodyLimitRouter(&readErr)
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/admin/reconcile", strings.NewReader(strings.Repeat("x", 17)))
+	request.ContentLength = -1
+
+	// Act
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	// Assert
+	var maxBytesErr *http.MaxBytesError
+	assert.True(t, errors.As(readErr, &maxBytesErr), "expected MaxBytesError, got %v", readErr)
+}
+
+func TestMaxBodySize_RouteOverrideAllowsLargerBody(t *testing.T) {
+	// Arrange
+	var readErr error
+	router := newBodyLimitRouter(&readErr)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/sessions/s-1/files/send", strings.NewReader(strings.Repeat("x", 40))))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NoError(t, readErr)
+}
+
+// slowHandler espera a que se cancele el contexto de la petición (o a un máximo) y lo reporta
+func slowHandler(cancelled chan<- bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			cancelled <- true
+		case <-time.After(2 * time.Second):
+			cancelled <- false
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestRequestTimeout_SlowHandlerReturns503AndCancelsContext(t *testing.T) {
+	// Arrange
+	cancelled := make(chan bool, 1)
+	handler := RequestTimeout(slowHandler(cancelled), 20*time.Millisecond, "/ws/*")
+	recorder := httptest.NewRecorder()
+
+	// Act
+	start := time.Now()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/pcs", nil))
+
+	// Assert
+	assert.Less(t, time.Since(start), time.Second)
+	assertErrorCode(t, recorder, http.StatusServiceUnavailable, "REQUEST_TIMEOUT")
+	assert.True(t, <-cancelled, "the handler context must be cancelled")
+}
+
+func TestRequestTimeout_ExemptRouteIsNotLimited(t *testing.T) {
+	// Arrange
+	handler := RequestTimeout(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusSwitchingProtocols)
+	}), 10*time.Millisecond, "/ws/*")
+	recorder := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ws/client", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusSwitchingProtocols, recorder.Code)
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/http/response"
+)
+
+// RequireRole permite la petición solo si el usuario autenticado tiene alguno de los roles indicados.
+// Debe ir después de AuthMiddleware, que deja los claims del token en el contexto.
+func RequireRole(roles ...user.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userInfo, exists := c.Get(UserKey)
+		if !exists {
+			response.AbortWithError(c, http.StatusUnauthorized, "AUTHENTICATION_REQUIRED", "Authentication required")
+			return
+		}
+
+		claims, ok := userInfo.(*userservice.JWTClaims)
+		if !ok || !user.Role(claims.Role).IsAnyOf(roles...) {
+			response.AbortWithError(c, http.StatusForbidden, "INSUFFICIENT_ROLE", "Your role is not allowed to perform this action")
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unikyri/escritorio-remoto-backend/internal/application/userservice"
+	"github.com/unikyri/escritorio-remoto-backend/internal/domain/user"
+	"github.com/unikyri/escritorio-remoto-backend/internal/presentation/dto"
+)
+
+// newRoleTestRouter replica la autorización de main.go sobre un endpoint representativo de cada nivel;
+// los claims se inyectan en lugar de validar un JWT
+func newRoleTestRouter(role user.Role) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	admin := router.Group("/api/v1/admin")
+	admin.Use(func(c *gin.Context) {
+		c.Set(UserKey, &userservice.JWTClaims{UserID: "user-id", Role: string(role)})
+		c.Next()
+	}, RequireRole(user.AdminRoles()...))
+
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	admin.GET("/recordings", ok)
+	admin.POST("/sessions/initiate", RequireRole(user.SessionControlRoles()...), ok)
+	admin.DELETE("/pcs/:pcId/purge", RequireRole(user.SuperAdminRoles()...), ok)
+	return router
+}
+
+func TestRequireRole_AuthorizesEachRoleByEndpointGroup(t *testing.T) {
+	endpoints := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/api/v1/admin/recordings"},
+		{http.MethodPost, "/api/v1/admin/sessions/initiate"},
+		{http.MethodDelete, "/api/v1/admin/pcs/pc-1/purge"},
+	}
+	// Códigos esperados para observar, controlar y purgar
+	cases := []struct {
+		role     user.Role
+		expected [3]int
+	}{
+		{user.RoleAdministrator, [3]int{http.StatusNoContent, http.StatusNoContent, http.StatusNoContent}},
+		{user.RoleOperator, [3]int{http.StatusNoContent, http.StatusNoContent, http.StatusForbidden}},
+		{user.RoleViewer, [3]int{http.StatusNoContent, http.StatusForbidden, http.StatusForbidden}},
+		{user.RoleClientUser, [3]int{http.StatusForbidden, http.StatusForbidden, http.StatusForbidden}},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.role), func(t *testing.T) {
+			// Arrange
+			router := newRoleTestRouter(tc.role)
+
+			for i, endpoint := range endpoints {
+				// Act
+				recorder := httptest.NewRecorder()
+				router.ServeHTTP(recorder, httptest.NewRequest(endpoint.method, endpoint.path, nil))
+
+				// Assert
+				assert.Equal(t, tc.expected[i], recorder.Code, "%s %s", endpoint.method, endpoint.path)
+			}
+		})
+	}
+}
+
+func TestRequireRole_RejectsWithErrorEnvelope(t *testing.T) {
+	// Arrange
+	router := newRoleTestRouter(user.RoleViewer)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/sessions/initiate", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	var envelope dto.APIResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+	assert.False(t, envelope.Success)
+	require.NotNil(t, envelope.Error)
+	assert.Equal(t, "INSUFFICIENT_ROLE", envelope.Error.Code)
+}
+
+func TestRequireRole_RequiresAuthentication(t *testing.T) {
+	// Arrange - sin AuthMiddleware no hay claims en el contexto
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/admin/recordings", RequireRole(user.AdminRoles()...), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/recordings", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import "strings"
+
+// ParseTrustedProxies separa la lista de proxies de confianza (TRUSTED_PROXIES: IPs o CIDR separados por comas)
+// para gin.Engine.SetTrustedProxies. Vacía retorna nil: no se confía en ningún proxy y c.ClientIP() usa siempre la
+// dirección del socket, ignorando X-Forwarded-For y X-Real-IP.
+func ParseTrustedProxies(raw string) []string {
+	var proxies []string
+	for _, proxy := range strings.Split(raw, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
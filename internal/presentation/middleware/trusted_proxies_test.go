@@ -0,0 +1,13 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	assert.Nil(t, ParseTrustedProxies(""))
+	assert.Nil(t, ParseTrustedProxies(" , "))
+	assert.Equal(t, []string{"10.0.0.5", "172.16.0.0/12"}, ParseTrustedProxies(" 10.0.0.5, ,172.16.0.0/12 "))
+}
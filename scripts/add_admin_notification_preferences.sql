@@ -0,0 +1,18 @@
+-- Script de migración para persistir las preferencias de notificación de los administradores
+-- Ejecutar este script en la base de datos existente
+
+USE escritorio_remoto_db;
+
+-- Una fila por administrador que cambió sus preferencias; sin fila recibe todas las notificaciones
+CREATE TABLE IF NOT EXISTS admin_notification_preferences (
+    admin_user_id VARCHAR(36) PRIMARY KEY,
+    scope ENUM('all', 'owned') NOT NULL DEFAULT 'all',
+    muted_categories JSON NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (admin_user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+-- Verificar el cambio
+DESCRIBE admin_notification_preferences;
+
+SELECT 'Tabla admin_notification_preferences creada' as mensaje;
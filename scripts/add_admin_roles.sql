@@ -0,0 +1,13 @@
+-- Script de migración para agregar los roles de administración OPERATOR y VIEWER
+-- Ejecutar este script en la base de datos existente
+
+USE escritorio_remoto_db;
+
+-- Los usuarios ADMINISTRATOR existentes conservan el acceso completo (super-administrador)
+ALTER TABLE users
+MODIFY COLUMN role ENUM('ADMINISTRATOR', 'OPERATOR', 'VIEWER', 'CLIENT_USER') NOT NULL;
+
+-- Verificar el cambio
+DESCRIBE users;
+
+SELECT 'Roles OPERATOR y VIEWER agregados a users' as mensaje;
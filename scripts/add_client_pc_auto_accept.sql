@@ -0,0 +1,13 @@
+-- Script de migración para agregar la política de auto-aceptación de control a client_pcs
+-- Ejecutar este script en la base de datos existente, antes de add_client_pc_metadata.sql
+
+USE escritorio_remoto_db;
+
+-- PCs de laboratorio/kiosco que aceptan las solicitudes de control sin confirmación; por defecto desactivada
+ALTER TABLE client_pcs
+ADD COLUMN auto_accept_control BOOLEAN NOT NULL DEFAULT FALSE AFTER last_seen_at;
+
+-- Verificar el cambio
+DESCRIBE client_pcs;
+
+SELECT 'Columna auto_accept_control agregada a client_pcs' as mensaje;
@@ -0,0 +1,15 @@
+-- Script de migración para agregar los metadatos del agente (SO, hostname y versión) a client_pcs
+-- Ejecutar este script en la base de datos existente, después de add_client_pc_auto_accept.sql
+
+USE escritorio_remoto_db;
+
+-- Columnas opcionales: los PCs registrados por clientes antiguos quedan en NULL
+ALTER TABLE client_pcs
+ADD COLUMN os VARCHAR(64) NULL AFTER auto_accept_control,
+ADD COLUMN hostname VARCHAR(255) NULL AFTER os,
+ADD COLUMN agent_version VARCHAR(32) NULL AFTER hostname;
+
+-- Verificar el cambio
+DESCRIBE client_pcs;
+
+SELECT 'Metadatos del agente agregados a client_pcs' as mensaje;
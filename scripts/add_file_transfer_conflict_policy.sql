@@ -0,0 +1,13 @@
+-- Script de migración para agregar la política de conflicto de nombres a file_transfers
+-- Ejecutar este script en la base de datos existente
+
+USE escritorio_remoto_db;
+
+-- Las transferencias anteriores quedan con 'rename', la política por defecto
+ALTER TABLE file_transfers
+ADD COLUMN conflict_policy ENUM('overwrite', 'rename', 'skip') NOT NULL DEFAULT 'rename' AFTER file_size_mb;
+
+-- Verificar el cambio
+DESCRIBE file_transfers;
+
+SELECT 'Política de conflicto agregada a file_transfers' as mensaje;
@@ -0,0 +1,13 @@
+-- Script de migración para abortar transferencias cuando el cliente no tiene espacio libre suficiente
+-- Ejecutar este script en la base de datos existente
+
+USE escritorio_remoto_db;
+
+-- Agrega INSUFFICIENT_CLIENT_SPACE al ENUM de file_transfers
+ALTER TABLE file_transfers
+MODIFY COLUMN status ENUM('PENDING', 'IN_PROGRESS', 'COMPLETED', 'FAILED', 'INSUFFICIENT_CLIENT_SPACE') NOT NULL;
+
+-- Verificar el cambio
+DESCRIBE file_transfers;
+
+SELECT 'Estado INSUFFICIENT_CLIENT_SPACE agregado a file_transfers' as mensaje;
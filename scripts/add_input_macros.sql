@@ -0,0 +1,21 @@
+-- Script de migración para guardar las macros de input grabadas por los administradores
+-- Ejecutar este script en la base de datos existente
+
+USE escritorio_remoto_db;
+
+-- Secuencias de comandos de input con sus esperas, para reproducirlas en otras sesiones
+CREATE TABLE IF NOT EXISTS input_macros (
+    macro_id VARCHAR(36) PRIMARY KEY,
+    admin_user_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    source_session_id VARCHAR(36) NULL,
+    commands JSON NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (admin_user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    INDEX idx_admin_created_at (admin_user_id, created_at)
+);
+
+-- Verificar el cambio
+DESCRIBE input_macros;
+
+SELECT 'Tabla input_macros creada' as mensaje;
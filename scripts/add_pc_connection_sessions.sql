@@ -0,0 +1,21 @@
+-- Script de migración para guardar el historial de conexiones WebSocket de los PCs cliente
+-- Ejecutar este script en la base de datos existente
+
+USE escritorio_remoto_db;
+
+-- Un registro por conexión: se abre al registrarse el PC y se cierra con la hora y el motivo de la desconexión
+CREATE TABLE IF NOT EXISTS pc_connection_sessions (
+    connection_id VARCHAR(36) PRIMARY KEY,
+    pc_id VARCHAR(36) NOT NULL,
+    ip_address VARCHAR(255) NOT NULL,
+    connected_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    disconnected_at TIMESTAMP NULL,
+    disconnect_reason VARCHAR(255) NULL,
+    FOREIGN KEY (pc_id) REFERENCES client_pcs(pc_id) ON DELETE CASCADE,
+    INDEX idx_pc_connected_at (pc_id, connected_at)
+);
+
+-- Verificar el cambio
+DESCRIBE pc_connection_sessions;
+
+SELECT 'Tabla pc_connection_sessions creada' as mensaje;
@@ -0,0 +1,20 @@
+-- Script de migración para que cada administrador pueda fijar PCs como favoritos
+-- Ejecutar este script en la base de datos existente
+
+USE escritorio_remoto_db;
+
+-- Un registro por PC fijado; se borra en cascada con el administrador o con el PC
+CREATE TABLE IF NOT EXISTS pinned_pcs (
+    admin_user_id VARCHAR(36) NOT NULL,
+    pc_id VARCHAR(36) NOT NULL,
+    pinned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (admin_user_id, pc_id),
+    FOREIGN KEY (admin_user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (pc_id) REFERENCES client_pcs(pc_id) ON DELETE CASCADE,
+    INDEX idx_admin_pinned_at (admin_user_id, pinned_at)
+);
+
+-- Verificar el cambio
+DESCRIBE pinned_pcs;
+
+SELECT 'Tabla pinned_pcs creada' as mensaje;
@@ -0,0 +1,14 @@
+-- Script de migración para agregar el motivo y el ticket de las sesiones remotas
+-- Ejecutar este script en la base de datos existente
+
+USE escritorio_remoto_db;
+
+-- Las sesiones anteriores quedan sin motivo ni ticket
+ALTER TABLE remote_sessions
+ADD COLUMN reason VARCHAR(500) NULL AFTER session_video_id,
+ADD COLUMN ticket_id VARCHAR(100) NULL AFTER reason;
+
+-- Verificar el cambio
+DESCRIBE remote_sessions;
+
+SELECT 'Motivo y ticket agregados a remote_sessions' as mensaje;
@@ -0,0 +1,13 @@
+-- Script de migración para poner en cola las solicitudes de control a PCs offline
+-- Ejecutar este script en la base de datos existente
+
+USE escritorio_remoto_db;
+
+-- Agrega QUEUED al ENUM de remote_sessions: la solicitud espera a que el PC se registre
+ALTER TABLE remote_sessions
+MODIFY COLUMN status ENUM('QUEUED', 'PENDING_APPROVAL', 'ACTIVE', 'ENDED_SUCCESSFULLY', 'ENDED_BY_ADMIN', 'ENDED_BY_CLIENT', 'FAILED') NOT NULL;
+
+-- Verificar el cambio
+DESCRIBE remote_sessions;
+
+SELECT 'Estado QUEUED agregado a remote_sessions' as mensaje;
@@ -0,0 +1,13 @@
+-- Script de migración para auditar por separado la solicitud de una sesión remota y su inicio
+-- Ejecutar este script en la base de datos existente
+
+USE escritorio_remoto_db;
+
+-- Agrega REMOTE_SESSION_REQUESTED al ENUM de action_logs
+ALTER TABLE action_logs
+MODIFY COLUMN action_type ENUM('USER_LOGIN', 'USER_LOGOUT', 'USER_CREATED', 'PC_REGISTERED', 'PC_STATUS_CHANGED', 'REMOTE_SESSION_STARTED', 'REMOTE_SESSION_ENDED', 'FILE_TRANSFER_INITIATED', 'FILE_TRANSFER_COMPLETED', 'FILE_TRANSFER_FAILED', 'VIDEO_RECORDING_STARTED', 'VIDEO_RECORDING_ENDED', 'VIDEO_UPLOADED', 'VIDEO_RECORDING_DISPOSED', 'REMOTE_SESSION_TRANSFERRED', 'RECORDING_VIEWED', 'FILE_TRANSFER_VIEWED', 'PC_PURGED', 'REMOTE_SESSION_AUTO_ACCEPTED', 'REMOTE_SESSION_ACTIVITY', 'FILE_TRANSFER_DOWNLOADED', 'USER_TOKENS_REVOKED', 'VIDEO_RECORDING_EMPTY', 'REMOTE_SESSION_REQUESTED') NOT NULL;
+
+-- Verificar el cambio
+DESCRIBE action_logs;
+
+SELECT 'Tipo de acción REMOTE_SESSION_REQUESTED agregado' as mensaje;
@@ -0,0 +1,19 @@
+-- Script de migración para agregar el índice de frames de las grabaciones empaquetadas
+-- Ejecutar este script en la base de datos existente
+
+USE escritorio_remoto_db;
+
+-- Una fila por frame con su posición en frames.pack; se elimina en cascada con la grabación
+CREATE TABLE IF NOT EXISTS session_video_frames (
+    video_id VARCHAR(36) NOT NULL,
+    frame_index INT NOT NULL,
+    frame_offset BIGINT NOT NULL,
+    frame_length BIGINT NOT NULL,
+    PRIMARY KEY (video_id, frame_index),
+    FOREIGN KEY (video_id) REFERENCES session_videos(video_id) ON DELETE CASCADE
+);
+
+-- Verificar el cambio
+DESCRIBE session_video_frames;
+
+SELECT 'Índice de frames agregado a session_video_frames' as mensaje;
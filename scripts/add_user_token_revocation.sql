@@ -0,0 +1,18 @@
+-- Script de migración para persistir la revocación de tokens de los usuarios
+-- Ejecutar este script en la base de datos existente
+
+USE escritorio_remoto_db;
+
+-- Versión vigente de los tokens del usuario; revocar la incrementa y los JWT con una versión menor dejan de valer
+ALTER TABLE users
+ADD COLUMN token_version INT NOT NULL DEFAULT 0 AFTER is_active;
+
+-- Agrega USER_TOKENS_REVOKED al ENUM de action_logs
+ALTER TABLE action_logs
+MODIFY COLUMN action_type ENUM('USER_LOGIN', 'USER_LOGOUT', 'USER_CREATED', 'PC_REGISTERED', 'PC_STATUS_CHANGED', 'REMOTE_SESSION_STARTED', 'REMOTE_SESSION_ENDED', 'FILE_TRANSFER_INITIATED', 'FILE_TRANSFER_COMPLETED', 'FILE_TRANSFER_FAILED', 'VIDEO_RECORDING_STARTED', 'VIDEO_RECORDING_ENDED', 'VIDEO_UPLOADED', 'VIDEO_RECORDING_DISPOSED', 'REMOTE_SESSION_TRANSFERRED', 'RECORDING_VIEWED', 'FILE_TRANSFER_VIEWED', 'PC_PURGED', 'REMOTE_SESSION_AUTO_ACCEPTED', 'REMOTE_SESSION_ACTIVITY', 'FILE_TRANSFER_DOWNLOADED', 'USER_TOKENS_REVOKED') NOT NULL;
+
+-- Verificar el cambio
+DESCRIBE users;
+DESCRIBE action_logs;
+
+SELECT 'Columna token_version y tipo de acción USER_TOKENS_REVOKED agregados' as mensaje;
@@ -0,0 +1,18 @@
+-- Script de migración: transfer_time pasa a ser el momento en que la transferencia se completó
+-- Ejecutar este script en la base de datos existente
+
+USE escritorio_remoto_db;
+
+-- Hasta ahora se rellenaba al crear la transferencia; NULL mientras no se complete
+ALTER TABLE file_transfers
+MODIFY COLUMN transfer_time TIMESTAMP NULL DEFAULT NULL;
+
+-- En las completadas, updated_at es la mejor aproximación disponible del momento de la transferencia.
+-- updated_at = updated_at evita que ON UPDATE CURRENT_TIMESTAMP lo cambie.
+UPDATE file_transfers SET transfer_time = updated_at, updated_at = updated_at WHERE status = 'COMPLETED';
+UPDATE file_transfers SET transfer_time = NULL, updated_at = updated_at WHERE status <> 'COMPLETED';
+
+-- Verificar el cambio
+DESCRIBE file_transfers;
+
+SELECT 'transfer_time de file_transfers corregido' as mensaje;
@@ -9,8 +9,9 @@ CREATE TABLE users (
     username VARCHAR(255) UNIQUE NOT NULL,
     ip VARCHAR(255) NOT NULL,
     hashed_password VARCHAR(255) NOT NULL,
-    role ENUM('ADMINISTRATOR', 'CLIENT_USER') NOT NULL,
+    role ENUM('ADMINISTRATOR', 'OPERATOR', 'VIEWER', 'CLIENT_USER') NOT NULL,
     is_active BOOLEAN DEFAULT TRUE,
+    token_version INT NOT NULL DEFAULT 0,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
 );
@@ -24,12 +25,48 @@ CREATE TABLE client_pcs (
     registered_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     owner_user_id VARCHAR(36) NOT NULL,
     last_seen_at TIMESTAMP NULL,
+    auto_accept_control BOOLEAN NOT NULL DEFAULT FALSE,
+    os VARCHAR(64) NULL,
+    hostname VARCHAR(255) NULL,
+    agent_version VARCHAR(32) NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     FOREIGN KEY (owner_user_id) REFERENCES users(user_id) ON DELETE CASCADE,
     UNIQUE KEY unique_identifier_per_owner (identifier, owner_user_id)
 );
 
+-- pc_connection_sessions Table (historial de conexiones WebSocket de PCs cliente)
+CREATE TABLE pc_connection_sessions (
+    connection_id VARCHAR(36) PRIMARY KEY,
+    pc_id VARCHAR(36) NOT NULL,
+    ip_address VARCHAR(255) NOT NULL,
+    connected_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    disconnected_at TIMESTAMP NULL,
+    disconnect_reason VARCHAR(255) NULL,
+    FOREIGN KEY (pc_id) REFERENCES client_pcs(pc_id) ON DELETE CASCADE,
+    INDEX idx_pc_connected_at (pc_id, connected_at)
+);
+
+-- pinned_pcs Table (PCs fijados como favoritos por cada administrador)
+CREATE TABLE pinned_pcs (
+    admin_user_id VARCHAR(36) NOT NULL,
+    pc_id VARCHAR(36) NOT NULL,
+    pinned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (admin_user_id, pc_id),
+    FOREIGN KEY (admin_user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (pc_id) REFERENCES client_pcs(pc_id) ON DELETE CASCADE,
+    INDEX idx_admin_pinned_at (admin_user_id, pinned_at)
+);
+
+-- admin_notification_preferences Table (alcance y categorías silenciadas de los broadcasts de cada administrador)
+CREATE TABLE admin_notification_preferences (
+    admin_user_id VARCHAR(36) PRIMARY KEY,
+    scope ENUM('all', 'owned') NOT NULL DEFAULT 'all',
+    muted_categories JSON NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (admin_user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
 -- remote_sessions Table  
 CREATE TABLE remote_sessions (
     session_id VARCHAR(36) PRIMARY KEY,
@@ -37,14 +74,28 @@ CREATE TABLE remote_sessions (
     client_pc_id VARCHAR(36) NOT NULL,
     start_time TIMESTAMP NULL,
     end_time TIMESTAMP NULL,
-    status ENUM('PENDING_APPROVAL', 'ACTIVE', 'ENDED_SUCCESSFULLY', 'ENDED_BY_ADMIN', 'ENDED_BY_CLIENT', 'FAILED') NOT NULL,
+    status ENUM('QUEUED', 'PENDING_APPROVAL', 'ACTIVE', 'ENDED_SUCCESSFULLY', 'ENDED_BY_ADMIN', 'ENDED_BY_CLIENT', 'FAILED') NOT NULL,
     session_video_id VARCHAR(36) NULL,
+    reason VARCHAR(500) NULL,
+    ticket_id VARCHAR(100) NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     FOREIGN KEY (admin_user_id) REFERENCES users(user_id),
     FOREIGN KEY (client_pc_id) REFERENCES client_pcs(pc_id)
 );
 
+-- input_macros Table (secuencias de comandos de input grabadas para reproducirlas en otras sesiones)
+CREATE TABLE input_macros (
+    macro_id VARCHAR(36) PRIMARY KEY,
+    admin_user_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    source_session_id VARCHAR(36) NULL,
+    commands JSON NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (admin_user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    INDEX idx_admin_created_at (admin_user_id, created_at)
+);
+
 -- session_videos Table
 CREATE TABLE session_videos (
     video_id VARCHAR(36) PRIMARY KEY,
@@ -58,6 +109,16 @@ CREATE TABLE session_videos (
     FOREIGN KEY (associated_session_id) REFERENCES remote_sessions(session_id) ON DELETE CASCADE
 );
 
+-- session_video_frames Table (índice de frames de las grabaciones empaquetadas)
+CREATE TABLE session_video_frames (
+    video_id VARCHAR(36) NOT NULL,
+    frame_index INT NOT NULL,
+    frame_offset BIGINT NOT NULL,
+    frame_length BIGINT NOT NULL,
+    PRIMARY KEY (video_id, frame_index),
+    FOREIGN KEY (video_id) REFERENCES session_videos(video_id) ON DELETE CASCADE
+);
+
 -- Add FK constraint for session_video_id
 ALTER TABLE remote_sessions
 ADD CONSTRAINT fk_session_video
@@ -69,12 +130,13 @@ CREATE TABLE file_transfers (
     file_name VARCHAR(255) NOT NULL,
     source_path_server VARCHAR(1024),
     destination_path_client VARCHAR(1024),
-    transfer_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    status ENUM('PENDING', 'IN_PROGRESS', 'COMPLETED', 'FAILED') NOT NULL,
+    transfer_time TIMESTAMP NULL DEFAULT NULL, -- Momento en que se completó (NULL hasta entonces)
+    status ENUM('PENDING', 'IN_PROGRESS', 'COMPLETED', 'FAILED', 'INSUFFICIENT_CLIENT_SPACE') NOT NULL,
     associated_session_id VARCHAR(36) NOT NULL,
     initiating_user_id VARCHAR(36) NOT NULL,
     target_pc_id VARCHAR(36) NOT NULL,
     file_size_mb FLOAT,
+    conflict_policy ENUM('overwrite', 'rename', 'skip') NOT NULL DEFAULT 'rename',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     FOREIGN KEY (associated_session_id) REFERENCES remote_sessions(session_id),
@@ -86,7 +148,7 @@ CREATE TABLE file_transfers (
 CREATE TABLE action_logs (
     log_id BIGINT PRIMARY KEY AUTO_INCREMENT,
     timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    action_type ENUM('USER_LOGIN', 'USER_LOGOUT', 'USER_CREATED', 'PC_REGISTERED', 'PC_STATUS_CHANGED', 'REMOTE_SESSION_STARTED', 'REMOTE_SESSION_ENDED', 'FILE_TRANSFER_INITIATED', 'FILE_TRANSFER_COMPLETED', 'FILE_TRANSFER_FAILED', 'VIDEO_RECORDING_STARTED', 'VIDEO_RECORDING_ENDED', 'VIDEO_UPLOADED') NOT NULL,
+    action_type ENUM('USER_LOGIN', 'USER_LOGOUT', 'USER_CREATED', 'PC_REGISTERED', 'PC_STATUS_CHANGED', 'REMOTE_SESSION_STARTED', 'REMOTE_SESSION_ENDED', 'FILE_TRANSFER_INITIATED', 'FILE_TRANSFER_COMPLETED', 'FILE_TRANSFER_FAILED', 'VIDEO_RECORDING_STARTED', 'VIDEO_RECORDING_ENDED', 'VIDEO_UPLOADED', 'VIDEO_RECORDING_DISPOSED', 'REMOTE_SESSION_TRANSFERRED', 'RECORDING_VIEWED', 'FILE_TRANSFER_VIEWED', 'PC_PURGED', 'REMOTE_SESSION_AUTO_ACCEPTED', 'REMOTE_SESSION_ACTIVITY', 'FILE_TRANSFER_DOWNLOADED', 'USER_TOKENS_REVOKED', 'VIDEO_RECORDING_EMPTY', 'REMOTE_SESSION_REQUESTED') NOT NULL,
     description TEXT,
     performed_by_user_id VARCHAR(36) NOT NULL,
     subject_entity_id VARCHAR(255) NULL,
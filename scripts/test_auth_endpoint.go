@@ -16,24 +16,29 @@ type AuthRequest struct {
 	Password string `json:"password"`
 }
 
-// AuthResponse estructura para la respuesta de login
+// AuthResponse estructura para la respuesta de login (envelope API v1)
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  struct {
-		UserID    string    `json:"user_id"`
-		Username  string    `json:"username"`
-		Role      string    `json:"role"`
-		IsActive  bool      `json:"is_active"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
-	} `json:"user"`
+	Success bool `json:"success"`
+	Data    struct {
+		Token string `json:"token"`
+		User  struct {
+			UserID    string    `json:"user_id"`
+			Username  string    `json:"username"`
+			Role      string    `json:"role"`
+			IsActive  bool      `json:"is_active"`
+			CreatedAt time.Time `json:"created_at"`
+			UpdatedAt time.Time `json:"updated_at"`
+		} `json:"user"`
+	} `json:"data"`
 }
 
-// ErrorResponse estructura para respuestas de error
+// ErrorResponse estructura para respuestas de error (envelope API v1)
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-	Code    int    `json:"code"`
+	Success bool `json:"success"`
+	Error   struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
 }
 
 func main() {
@@ -85,8 +90,8 @@ func testInvalidCredentials(baseURL string) {
 
 		var errorResp ErrorResponse
 		if err := json.Unmarshal(response, &errorResp); err == nil {
-			fmt.Printf("   Error: %s\n", errorResp.Error)
-			fmt.Printf("   Mensaje: %s\n", errorResp.Message)
+			fmt.Printf("   Error: %s\n", errorResp.Error.Code)
+			fmt.Printf("   Mensaje: %s\n", errorResp.Error.Message)
 		}
 	} else {
 		fmt.Printf("❌ Respuesta inesperada para credenciales incorrectas (Status: %d)\n", statusCode)
@@ -108,10 +113,10 @@ func testValidAdminCredentials(baseURL string) {
 
 		var authResp AuthResponse
 		if err := json.Unmarshal(response, &authResp); err == nil {
-			fmt.Printf("   Token JWT recibido: %s...\n", authResp.Token[:20])
-			fmt.Printf("   Usuario: %s (ID: %s)\n", authResp.User.Username, authResp.User.UserID)
-			fmt.Printf("   Rol: %s\n", authResp.User.Role)
-			fmt.Printf("   Activo: %v\n", authResp.User.IsActive)
+			fmt.Printf("   Token JWT recibido: %s...\n", authResp.Data.Token[:20])
+			fmt.Printf("   Usuario: %s (ID: %s)\n", authResp.Data.User.Username, authResp.Data.User.UserID)
+			fmt.Printf("   Rol: %s\n", authResp.Data.User.Role)
+			fmt.Printf("   Activo: %v\n", authResp.Data.User.IsActive)
 		} else {
 			fmt.Printf("❌ Error al parsear respuesta exitosa: %v\n", err)
 		}
@@ -128,7 +133,7 @@ func makeAuthRequest(baseURL string, request AuthRequest) ([]byte, int) {
 		return nil, 0
 	}
 
-	resp, err := http.Post(baseURL+"/api/auth/login", "application/json", bytes.NewBuffer(jsonData))
+	resp, err := http.Post(baseURL+"/api/v1/auth/login", "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
 		log.Printf("❌ Error en petición HTTP: %v", err)
 		return nil, 0